@@ -68,7 +68,13 @@ func NewFetcher(client kibana.Client, cacheExpiration time.Duration) *Fetcher {
 }
 
 // Fetch retrieves agent configuration, fetched from Kibana or a local temporary cache.
+//
+// If ctx carries a tenant, the configuration is looked up for the service name
+// qualified as "<tenant>/<service name>", and cached separately per tenant.
 func (f *Fetcher) Fetch(ctx context.Context, query Query) (Result, error) {
+	if tenant := utility.Tenant(ctx); tenant != "" {
+		query.Service.Name = tenant + "/" + query.Service.Name
+	}
 	req := func() (Result, error) {
 		return newResult(f.request(ctx, convert.ToReader(query)))
 	}
@@ -31,6 +31,7 @@ import (
 
 	"github.com/elastic/apm-server/kibana"
 	"github.com/elastic/apm-server/tests"
+	"github.com/elastic/apm-server/utility"
 )
 
 type m map[string]interface{}
@@ -98,6 +99,20 @@ func TestFetcher_Fetch(t *testing.T) {
 		fetch(fetcher, 0.7, 0.7)
 
 	})
+
+	t.Run("Tenant", func(t *testing.T) {
+		fetcher := NewFetcher(tests.MockKibana(http.StatusOK, mockDoc(0.5), mockVersion, true), time.Minute)
+		ctx := utility.ContextWithTenant(context.Background(), "acme")
+		_, err := fetcher.Fetch(ctx, query(t.Name()))
+		require.NoError(t, err)
+
+		tenantQuery := query(t.Name())
+		tenantQuery.Service.Name = "acme/" + tenantQuery.Service.Name
+		_, ok := fetcher.cache.gocache.Get(tenantQuery.id())
+		assert.True(t, ok)
+		_, ok = fetcher.cache.gocache.Get(query(t.Name()).id())
+		assert.False(t, ok)
+	})
 }
 
 func TestSanitize(t *testing.T) {
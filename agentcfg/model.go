@@ -31,11 +31,14 @@ const (
 	Etag = "ifnonematch"
 	// EtagSentinel is a value to return back to agents when Kibana doesn't have any configuration
 	EtagSentinel = "-"
+	// TraceContinuationStrategy is the setting controlling whether agents continue traces
+	// from incoming traceparent headers: "continue", "restart", or "restart_external".
+	TraceContinuationStrategy = "trace_continuation_strategy"
 )
 
 var (
 	// WhitelistedSettings are settings considered safe to be returned to all requesters, including unauthenticated ones such as RUM.
-	WhitelistedSettings = []string{"transaction_sample_rate"}
+	WhitelistedSettings = []string{"transaction_sample_rate", TraceContinuationStrategy}
 )
 
 // Result models a Kibana response
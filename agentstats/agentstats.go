@@ -0,0 +1,140 @@
+// Licensed to Elasticsearch B.V. under one or more contributor
+// license agreements. See the NOTICE file distributed with
+// this work for additional information regarding copyright
+// ownership. Elasticsearch B.V. licenses this file to you under
+// the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+// Package agentstats tracks the number of events accepted and rejected
+// for each agent name and version, to quantify which agent versions
+// send events failing validation.
+package agentstats
+
+import (
+	"sort"
+	"sync"
+
+	"github.com/elastic/beats/v7/libbeat/monitoring"
+)
+
+// maxAgents is the number of distinct agent names and versions tracked.
+// Agent names and versions are sent by clients, so the number tracked
+// must be bounded.
+const maxAgents = 1000
+
+// Default is the Tracker recording statistics for events received by
+// the server. Its statistics are reported under apm-server.agents.
+var Default = NewTracker(maxAgents)
+
+func init() {
+	monitoring.NewFunc(monitoring.Default, "apm-server.agents", Default.CollectMonitoring, monitoring.Report)
+}
+
+// Stats holds the statistics for an agent name and version.
+type Stats struct {
+	Name    string `json:"name"`
+	Version string `json:"version"`
+
+	// Accepted holds the number of events accepted.
+	Accepted int64 `json:"accepted"`
+
+	// Rejected holds the number of events rejected for being
+	// invalid or too large.
+	Rejected int64 `json:"rejected"`
+}
+
+type key struct {
+	name, version string
+}
+
+// Tracker records statistics for a bounded number of agent names and versions.
+type Tracker struct {
+	mu         sync.Mutex
+	max        int
+	stats      map[key]*Stats
+	overflowed int64
+}
+
+// NewTracker returns a new Tracker, recording statistics for up to max
+// agent names and versions.
+func NewTracker(max int) *Tracker {
+	return &Tracker{max: max, stats: make(map[key]*Stats)}
+}
+
+// Add adds to the number of events accepted and rejected for the given agent.
+// Once the maximum number of agents are tracked, events for other agents are
+// counted as overflowed.
+func (t *Tracker) Add(name, version string, accepted, rejected int) {
+	if accepted == 0 && rejected == 0 {
+		return
+	}
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	k := key{name: name, version: version}
+	s, ok := t.stats[k]
+	if !ok {
+		if len(t.stats) >= t.max {
+			t.overflowed += int64(accepted + rejected)
+			return
+		}
+		s = &Stats{Name: name, Version: version}
+		t.stats[k] = s
+	}
+	s.Accepted += int64(accepted)
+	s.Rejected += int64(rejected)
+}
+
+// Stats returns the statistics for each agent, sorted by name and version,
+// and the number of events for agents which could not be tracked.
+func (t *Tracker) Stats() (stats []Stats, overflowed int64) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	stats = make([]Stats, 0, len(t.stats))
+	for _, s := range t.stats {
+		stats = append(stats, *s)
+	}
+	sort.Slice(stats, func(i, j int) bool {
+		if stats[i].Name != stats[j].Name {
+			return stats[i].Name < stats[j].Name
+		}
+		return stats[i].Version < stats[j].Version
+	})
+	return stats, t.overflowed
+}
+
+// CollectMonitoring may be called to collect monitoring metrics from the
+// tracker. It is intended to be used with libbeat/monitoring.NewFunc.
+//
+// The metrics are nested by agent name and version, e.g.
+// apm-server.agents.versions.<name>.<version>.accepted.
+func (t *Tracker) CollectMonitoring(_ monitoring.Mode, V monitoring.Visitor) {
+	V.OnRegistryStart()
+	defer V.OnRegistryFinished()
+
+	stats, overflowed := t.Stats()
+	monitoring.ReportInt(V, "overflowed", overflowed)
+	monitoring.ReportNamespace(V, "versions", func() {
+		for i := 0; i < len(stats); {
+			name := stats[i].Name
+			monitoring.ReportNamespace(V, name, func() {
+				for ; i < len(stats) && stats[i].Name == name; i++ {
+					s := stats[i]
+					monitoring.ReportNamespace(V, s.Version, func() {
+						monitoring.ReportInt(V, "accepted", s.Accepted)
+						monitoring.ReportInt(V, "rejected", s.Rejected)
+					})
+				}
+			})
+		}
+	})
+}
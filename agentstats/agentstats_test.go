@@ -0,0 +1,67 @@
+// Licensed to Elasticsearch B.V. under one or more contributor
+// license agreements. See the NOTICE file distributed with
+// this work for additional information regarding copyright
+// ownership. Elasticsearch B.V. licenses this file to you under
+// the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package agentstats
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/elastic/beats/v7/libbeat/monitoring"
+)
+
+func TestTracker(t *testing.T) {
+	tracker := NewTracker(2)
+	tracker.Add("python", "5.0.0", 3, 1)
+	tracker.Add("go", "1.8.0", 2, 0)
+	tracker.Add("python", "5.0.0", 1, 2)
+	tracker.Add("java", "1.0.0", 0, 0) // nothing to record
+	tracker.Add("java", "1.0.0", 4, 1) // overflow
+
+	stats, overflowed := tracker.Stats()
+	assert.Equal(t, []Stats{
+		{Name: "go", Version: "1.8.0", Accepted: 2},
+		{Name: "python", Version: "5.0.0", Accepted: 4, Rejected: 3},
+	}, stats)
+	assert.Equal(t, int64(5), overflowed)
+}
+
+func TestTrackerCollectMonitoring(t *testing.T) {
+	tracker := NewTracker(10)
+	tracker.Add("go", "1.8.0", 2, 0)
+	tracker.Add("go", "1.8.1", 3, 1)
+	tracker.Add("rum-js", "5.5.0", 1, 1)
+
+	registry := monitoring.NewRegistry()
+	monitoring.NewFunc(registry, "agents", tracker.CollectMonitoring)
+	snapshot := monitoring.CollectStructSnapshot(registry, monitoring.Full, false)
+	assert.Equal(t, map[string]interface{}{
+		"agents": map[string]interface{}{
+			"overflowed": int64(0),
+			"versions": map[string]interface{}{
+				"go": map[string]interface{}{
+					"1.8.0": map[string]interface{}{"accepted": int64(2), "rejected": int64(0)},
+					"1.8.1": map[string]interface{}{"accepted": int64(3), "rejected": int64(1)},
+				},
+				"rum-js": map[string]interface{}{
+					"5.5.0": map[string]interface{}{"accepted": int64(1), "rejected": int64(1)},
+				},
+			},
+		},
+	}, snapshot)
+}
@@ -0,0 +1,50 @@
+// Licensed to Elasticsearch B.V. under one or more contributor
+// license agreements. See the NOTICE file distributed with
+// this work for additional information regarding copyright
+// ownership. Elasticsearch B.V. licenses this file to you under
+// the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package api
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/elastic/apm-server/agentstats"
+)
+
+func TestAgentStatsHandler(t *testing.T) {
+	tracker := agentstats.NewTracker(10)
+	tracker.Add("go", "1.8.0", 2, 1)
+	h := agentStatsHandler(tracker)
+
+	r := httptest.NewRequest(http.MethodGet, "/debug/agents", nil)
+	r.RemoteAddr = "127.0.0.1:12345"
+	rec := httptest.NewRecorder()
+	h(rec, r)
+	require.Equal(t, http.StatusOK, rec.Code)
+	assert.JSONEq(t, `{
+		"agents": [{"name": "go", "version": "1.8.0", "accepted": 2, "rejected": 1}],
+		"overflowed": 0
+	}`, rec.Body.String())
+
+	r.RemoteAddr = "192.0.2.1:12345"
+	rec = httptest.NewRecorder()
+	h(rec, r)
+	assert.Equal(t, http.StatusForbidden, rec.Code)
+}
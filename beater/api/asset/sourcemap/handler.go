@@ -18,16 +18,23 @@
 package sourcemap
 
 import (
+	"compress/gzip"
 	"fmt"
+	"io"
 	"io/ioutil"
+	"mime/multipart"
 	"net/http"
 	"strings"
 
+	"github.com/pkg/errors"
 	"go.elastic.co/apm"
 
 	"github.com/elastic/beats/v7/libbeat/monitoring"
 
+	"github.com/elastic/apm-server/beater/headers"
 	"github.com/elastic/apm-server/beater/request"
+	"github.com/elastic/apm-server/decoder"
+	"github.com/elastic/apm-server/model"
 	"github.com/elastic/apm-server/processor/asset"
 	"github.com/elastic/apm-server/publish"
 	"github.com/elastic/apm-server/transform"
@@ -54,7 +61,8 @@ func Handler(dec RequestDecoder, processor asset.Processor, cfg transform.Config
 
 		data, err := dec(c.Request)
 		if err != nil {
-			if strings.Contains(err.Error(), request.MapResultIDToStatus[request.IDResponseErrorsRequestTooLarge].Keyword) {
+			if errors.Cause(err) == decoder.ErrDecompressedTooLarge ||
+				strings.Contains(err.Error(), request.MapResultIDToStatus[request.IDResponseErrorsRequestTooLarge].Keyword) {
 				c.Result.SetWithError(request.IDResponseErrorsRequestTooLarge, err)
 			} else {
 				c.Result.SetWithError(request.IDResponseErrorsDecode, err)
@@ -76,6 +84,16 @@ func Handler(dec RequestDecoder, processor asset.Processor, cfg transform.Config
 			return
 		}
 
+		// In multi-tenant mode, sourcemaps are uploaded for the
+		// tenant identified by the request's credentials.
+		if tenant := utility.Tenant(c.Request.Context()); tenant != "" {
+			for _, t := range transformables {
+				if s, ok := t.(*model.Sourcemap); ok {
+					s.TenantID = tenant
+				}
+			}
+		}
+
 		tctx := &transform.Context{Config: cfg}
 		req := publish.PendingReq{Transformables: transformables, Tcontext: tctx}
 		span, ctx := apm.StartSpan(c.Request.Context(), "Send", "Reporter")
@@ -96,19 +114,37 @@ func Handler(dec RequestDecoder, processor asset.Processor, cfg transform.Config
 	}
 }
 
-func DecodeSourcemapFormData(req *http.Request) (map[string]interface{}, error) {
+// FormDataDecoder returns a RequestDecoder decoding sourcemaps uploaded as
+// multipart form data. Gzip-compressed sourcemaps are limited to
+// maxDecompressedSize bytes after decompression, if positive.
+func FormDataDecoder(maxDecompressedSize int64) RequestDecoder {
+	return func(req *http.Request) (map[string]interface{}, error) {
+		return decodeSourcemapFormData(req, maxDecompressedSize)
+	}
+}
+
+func decodeSourcemapFormData(req *http.Request, maxDecompressedSize int64) (map[string]interface{}, error) {
 	contentType := req.Header.Get("Content-Type")
 	if !strings.Contains(contentType, "multipart/form-data") {
 		return nil, fmt.Errorf("invalid content type: %s", req.Header.Get("Content-Type"))
 	}
 
-	file, _, err := req.FormFile("sourcemap")
+	file, header, err := req.FormFile("sourcemap")
 	if err != nil {
 		return nil, err
 	}
 	defer file.Close()
 
-	sourcemapBytes, err := ioutil.ReadAll(file)
+	var reader io.Reader = file
+	if isGzipped(header) {
+		zr, err := gzip.NewReader(file)
+		if err != nil {
+			return nil, errors.Wrap(err, "decompressing sourcemap")
+		}
+		defer zr.Close()
+		reader = decoder.LimitDecompressedSize(zr, maxDecompressedSize)
+	}
+	sourcemapBytes, err := ioutil.ReadAll(reader)
 	if err != nil {
 		return nil, err
 	}
@@ -121,3 +157,12 @@ func DecodeSourcemapFormData(req *http.Request) (map[string]interface{}, error)
 
 	return payload, nil
 }
+
+// isGzipped reports whether the uploaded sourcemap file part is gzip-compressed,
+// as indicated by its Content-Encoding header or a ".gz" file name extension.
+func isGzipped(header *multipart.FileHeader) bool {
+	if strings.EqualFold(header.Header.Get(headers.ContentEncoding), "gzip") {
+		return true
+	}
+	return strings.HasSuffix(strings.ToLower(header.Filename), ".gz")
+}
@@ -19,23 +19,29 @@ package sourcemap
 
 import (
 	"bytes"
+	"compress/gzip"
 	"context"
 	"fmt"
 	"io"
 	"mime/multipart"
 	"net/http"
 	"net/http/httptest"
+	"net/textproto"
 	"testing"
 
 	"github.com/pkg/errors"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 
 	"github.com/elastic/apm-server/beater/beatertest"
 	"github.com/elastic/apm-server/beater/request"
+	"github.com/elastic/apm-server/decoder"
+	"github.com/elastic/apm-server/model"
 	"github.com/elastic/apm-server/processor/asset"
 	"github.com/elastic/apm-server/publish"
 	"github.com/elastic/apm-server/tests/loader"
 	"github.com/elastic/apm-server/transform"
+	"github.com/elastic/apm-server/utility"
 )
 
 func TestAssetHandler(t *testing.T) {
@@ -106,6 +112,22 @@ func TestAssetHandler(t *testing.T) {
 	}
 }
 
+func TestAssetHandlerTenant(t *testing.T) {
+	var reported []transform.Transformable
+	tc := testcaseT{
+		r:         httptest.NewRequest(http.MethodPost, "/", nil),
+		processor: &sourcemapProcessor{},
+		reporter: func(ctx context.Context, p publish.PendingReq) error {
+			reported = p.Transformables
+			return nil
+		},
+	}
+	tc.r = tc.r.WithContext(utility.ContextWithTenant(tc.r.Context(), "team-a"))
+	tc.setup()
+	assert.Equal(t, http.StatusAccepted, tc.w.Code)
+	assert.Equal(t, []transform.Transformable{&model.Sourcemap{ServiceName: "foo", TenantID: "team-a"}}, reported)
+}
+
 type testcaseT struct {
 	w         *httptest.ResponseRecorder
 	r         *http.Request
@@ -183,7 +205,7 @@ func TestDecodeSourcemapFormData(t *testing.T) {
 	assert.NoError(t, err)
 
 	assert.NoError(t, err)
-	data, err := DecodeSourcemapFormData(req)
+	data, err := FormDataDecoder(0)(req)
 	assert.NoError(t, err)
 
 	assert.Len(t, data, 4)
@@ -193,3 +215,70 @@ func TestDecodeSourcemapFormData(t *testing.T) {
 	assert.NotNil(t, data["sourcemap"].(string))
 	assert.Equal(t, len(fileBytes), len(data["sourcemap"].(string)))
 }
+
+func TestDecodeSourcemapFormDataGzipped(t *testing.T) {
+	fileBytes, err := loader.LoadDataAsBytes("../testdata/sourcemap/bundle.js.map")
+	require.NoError(t, err)
+	var compressed bytes.Buffer
+	zw := gzip.NewWriter(&compressed)
+	_, err = zw.Write(fileBytes)
+	require.NoError(t, err)
+	require.NoError(t, zw.Close())
+
+	newRequest := func(filename, contentEncoding string, content []byte) *http.Request {
+		body := &bytes.Buffer{}
+		writer := multipart.NewWriter(body)
+		h := make(textproto.MIMEHeader)
+		h.Set("Content-Disposition", fmt.Sprintf(`form-data; name="sourcemap"; filename="%s"`, filename))
+		h.Set("Content-Type", "application/octet-stream")
+		if contentEncoding != "" {
+			h.Set("Content-Encoding", contentEncoding)
+		}
+		part, err := writer.CreatePart(h)
+		require.NoError(t, err)
+		_, err = part.Write(content)
+		require.NoError(t, err)
+		writer.WriteField("service_name", "My service")
+		require.NoError(t, writer.Close())
+
+		req := httptest.NewRequest(http.MethodPost, "/", body)
+		req.Header.Set("Content-Type", writer.FormDataContentType())
+		return req
+	}
+
+	for name, req := range map[string]*http.Request{
+		"ContentEncoding": newRequest("bundle.js.map", "gzip", compressed.Bytes()),
+		"Extension":       newRequest("bundle.js.map.gz", "", compressed.Bytes()),
+	} {
+		t.Run(name, func(t *testing.T) {
+			data, err := FormDataDecoder(0)(req)
+			require.NoError(t, err)
+			assert.Equal(t, string(fileBytes), data["sourcemap"])
+		})
+	}
+
+	t.Run("Invalid", func(t *testing.T) {
+		_, err := FormDataDecoder(0)(newRequest("bundle.js.map.gz", "", fileBytes))
+		assert.Error(t, err)
+	})
+
+	t.Run("TooLarge", func(t *testing.T) {
+		req := newRequest("bundle.js.map.gz", "", compressed.Bytes())
+		_, err := FormDataDecoder(100)(req)
+		assert.Equal(t, decoder.ErrDecompressedTooLarge, err)
+
+		tc := testcaseT{
+			r:   newRequest("bundle.js.map.gz", "", compressed.Bytes()),
+			dec: FormDataDecoder(100),
+		}
+		tc.setup()
+		assert.Equal(t, http.StatusRequestEntityTooLarge, tc.w.Code)
+	})
+}
+
+// sourcemapProcessor decodes any payload as a sourcemap.
+type sourcemapProcessor struct{ mockProcessor }
+
+func (*sourcemapProcessor) Decode(map[string]interface{}) ([]transform.Transformable, error) {
+	return []transform.Transformable{&model.Sourcemap{ServiceName: "foo"}}, nil
+}
@@ -0,0 +1,91 @@
+// Licensed to Elasticsearch B.V. under one or more contributor
+// license agreements. See the NOTICE file distributed with
+// this work for additional information regarding copyright
+// ownership. Elasticsearch B.V. licenses this file to you under
+// the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package sourcemap
+
+import (
+	"context"
+	"errors"
+	"net/http"
+
+	"github.com/elastic/apm-server/beater/request"
+	"github.com/elastic/apm-server/sourcemap"
+)
+
+const (
+	serviceNameParam    = "service_name"
+	serviceVersionParam = "service_version"
+	bundleFilepathParam = "bundle_filepath"
+)
+
+// Store lists and deletes uploaded sourcemaps.
+type Store interface {
+	List(ctx context.Context, name, version string) ([]sourcemap.Metadata, error)
+	Delete(ctx context.Context, name, version, path string) (int, error)
+}
+
+// ManagementHandler returns a request.Handler listing the sourcemaps uploaded
+// to store for GET requests, and deleting them for DELETE requests. Any other
+// requests are passed on to h.
+func ManagementHandler(store Store, h request.Handler) request.Handler {
+	return func(c *request.Context) {
+		switch c.Request.Method {
+		case http.MethodGet:
+			listSourcemaps(c, store)
+		case http.MethodDelete:
+			deleteSourcemaps(c, store)
+		default:
+			h(c)
+		}
+	}
+}
+
+func listSourcemaps(c *request.Context, store Store) {
+	query := c.Request.URL.Query()
+	sourcemaps, err := store.List(c.Request.Context(), query.Get(serviceNameParam), query.Get(serviceVersionParam))
+	if err != nil {
+		c.Result.SetWithError(request.IDResponseErrorsServiceUnavailable, err)
+		c.Write()
+		return
+	}
+	if sourcemaps == nil {
+		sourcemaps = []sourcemap.Metadata{}
+	}
+	c.Result.SetWithBody(request.IDResponseValidOK, map[string]interface{}{"sourcemaps": sourcemaps})
+	c.Write()
+}
+
+func deleteSourcemaps(c *request.Context, store Store) {
+	query := c.Request.URL.Query()
+	name, version := query.Get(serviceNameParam), query.Get(serviceVersionParam)
+	if name == "" || version == "" {
+		// Require the service version, so a typo cannot
+		// delete the sourcemaps of all versions at once.
+		c.Result.SetWithError(request.IDResponseErrorsInvalidQuery,
+			errors.New(serviceNameParam+" and "+serviceVersionParam+" are required"))
+		c.Write()
+		return
+	}
+	deleted, err := store.Delete(c.Request.Context(), name, version, query.Get(bundleFilepathParam))
+	if err != nil {
+		c.Result.SetWithError(request.IDResponseErrorsServiceUnavailable, err)
+		c.Write()
+		return
+	}
+	c.Result.SetWithBody(request.IDResponseValidOK, map[string]interface{}{"deleted": deleted})
+	c.Write()
+}
@@ -0,0 +1,122 @@
+// Licensed to Elasticsearch B.V. under one or more contributor
+// license agreements. See the NOTICE file distributed with
+// this work for additional information regarding copyright
+// ownership. Elasticsearch B.V. licenses this file to you under
+// the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package sourcemap
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/elastic/apm-server/beater/request"
+	"github.com/elastic/apm-server/sourcemap"
+)
+
+type mockStore struct {
+	sourcemaps []sourcemap.Metadata
+	deleted    int
+	err        error
+
+	name, version, path string
+}
+
+func (s *mockStore) List(ctx context.Context, name, version string) ([]sourcemap.Metadata, error) {
+	s.name, s.version = name, version
+	return s.sourcemaps, s.err
+}
+
+func (s *mockStore) Delete(ctx context.Context, name, version, path string) (int, error) {
+	s.name, s.version, s.path = name, version, path
+	return s.deleted, s.err
+}
+
+func TestManagementHandler(t *testing.T) {
+	uploaded := time.Date(2020, 6, 1, 10, 0, 0, 0, time.UTC)
+	handle := func(store Store, method, target string) *httptest.ResponseRecorder {
+		var uploadCalled bool
+		upload := func(c *request.Context) {
+			uploadCalled = true
+			c.Result.SetDefault(request.IDResponseValidAccepted)
+			c.Write()
+		}
+		rec := httptest.NewRecorder()
+		c := request.NewContext()
+		c.Reset(rec, httptest.NewRequest(method, target, nil))
+		ManagementHandler(store, upload)(c)
+		assert.Equal(t, method == http.MethodPost, uploadCalled)
+		return rec
+	}
+
+	t.Run("Upload", func(t *testing.T) {
+		rec := handle(&mockStore{}, http.MethodPost, "/")
+		assert.Equal(t, http.StatusAccepted, rec.Code)
+	})
+
+	t.Run("List", func(t *testing.T) {
+		store := &mockStore{sourcemaps: []sourcemap.Metadata{{
+			ID: "abc", ServiceName: "foo", ServiceVersion: "1.0", BundleFilepath: "/bundle.js", Uploaded: uploaded,
+		}}}
+		rec := handle(store, http.MethodGet, "/?service_name=foo")
+		require.Equal(t, http.StatusOK, rec.Code)
+		assert.Equal(t, "foo", store.name)
+		assert.Equal(t, "", store.version)
+		assert.JSONEq(t, `{"sourcemaps":[{
+			"id":"abc","service_name":"foo","service_version":"1.0",
+			"bundle_filepath":"/bundle.js","uploaded":"2020-06-01T10:00:00Z"
+		}]}`, rec.Body.String())
+	})
+
+	t.Run("ListEmpty", func(t *testing.T) {
+		rec := handle(&mockStore{}, http.MethodGet, "/")
+		require.Equal(t, http.StatusOK, rec.Code)
+		assert.JSONEq(t, `{"sourcemaps":[]}`, rec.Body.String())
+	})
+
+	t.Run("ListError", func(t *testing.T) {
+		rec := handle(&mockStore{err: errors.New("boom")}, http.MethodGet, "/")
+		assert.Equal(t, http.StatusServiceUnavailable, rec.Code)
+	})
+
+	t.Run("Delete", func(t *testing.T) {
+		store := &mockStore{deleted: 3}
+		rec := handle(store, http.MethodDelete, "/?service_name=foo&service_version=1.0&bundle_filepath=/bundle.js")
+		require.Equal(t, http.StatusOK, rec.Code)
+		assert.Equal(t, []string{"foo", "1.0", "/bundle.js"}, []string{store.name, store.version, store.path})
+		var body map[string]interface{}
+		require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &body))
+		assert.Equal(t, map[string]interface{}{"deleted": 3.0}, body)
+	})
+
+	t.Run("DeleteMissingVersion", func(t *testing.T) {
+		store := &mockStore{}
+		rec := handle(store, http.MethodDelete, "/?service_name=foo")
+		assert.Equal(t, http.StatusBadRequest, rec.Code)
+		assert.Empty(t, store.name)
+	})
+
+	t.Run("DeleteError", func(t *testing.T) {
+		rec := handle(&mockStore{err: errors.New("boom")}, http.MethodDelete, "/?service_name=foo&service_version=1.0")
+		assert.Equal(t, http.StatusServiceUnavailable, rec.Code)
+	})
+}
@@ -0,0 +1,75 @@
+// Licensed to Elasticsearch B.V. under one or more contributor
+// license agreements. See the NOTICE file distributed with
+// this work for additional information regarding copyright
+// ownership. Elasticsearch B.V. licenses this file to you under
+// the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package api
+
+import (
+	"net/http"
+
+	"github.com/elastic/beats/v7/libbeat/logp"
+	"github.com/elastic/beats/v7/libbeat/monitoring"
+
+	"github.com/elastic/apm-server/beater/api/spool"
+	"github.com/elastic/apm-server/beater/authorization"
+	"github.com/elastic/apm-server/beater/config"
+	"github.com/elastic/apm-server/beater/middleware"
+	"github.com/elastic/apm-server/beater/request"
+	logs "github.com/elastic/apm-server/log"
+)
+
+var backfillMonitoringMap = request.DefaultMonitoringMapForRegistry(
+	monitoring.Default.NewRegistry("apm-server.backfill"),
+)
+
+// backfillHandler reports the progress of the most recent backfill of
+// spooled events, and on POST starts a new backfill in the background,
+// responding with 202. Only requests from localhost are served.
+func backfillHandler(spooler *spool.Spooler) request.Handler {
+	logger := logp.NewLogger(logs.Handler)
+	return func(c *request.Context) {
+		if !isLoopback(c.Request.RemoteAddr) {
+			c.Result.SetDefault(request.IDResponseErrorsForbidden)
+			c.Write()
+			return
+		}
+		id := request.IDResponseValidOK
+		switch c.Request.Method {
+		case http.MethodGet:
+		case http.MethodPost:
+			if spooler.Backfill() {
+				logger.Info("backfill of spooled events started")
+				id = request.IDResponseValidAccepted
+			}
+		default:
+			c.Header().Set("Allow", "GET, POST")
+			c.Result.SetDefault(request.IDResponseErrorsMethodNotAllowed)
+			c.Write()
+			return
+		}
+		c.Result.SetDefault(id)
+		c.Result.Body = spooler.Progress()
+		c.Write()
+	}
+}
+
+// backfillMiddleware returns the middleware for the backfill endpoint:
+// as backfilled events are published as if sent by their agents,
+// requests must be authorized to write events.
+func backfillMiddleware(cfg *config.Config, auth *authorization.Handler) []middleware.Middleware {
+	return append(apmMiddleware(cfg, backfillMonitoringMap),
+		middleware.AuthorizationMiddleware(auth, true))
+}
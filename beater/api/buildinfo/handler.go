@@ -0,0 +1,65 @@
+// Licensed to Elasticsearch B.V. under one or more contributor
+// license agreements. See the NOTICE file distributed with
+// this work for additional information regarding copyright
+// ownership. Elasticsearch B.V. licenses this file to you under
+// the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package buildinfo
+
+import (
+	"github.com/elastic/beats/v7/libbeat/monitoring"
+
+	"github.com/elastic/apm-server/beater/request"
+)
+
+var (
+	// MonitoringMap holds a mapping for request.IDs to monitoring counters
+	MonitoringMap = request.DefaultMonitoringMapForRegistry(registry)
+	registry      = monitoring.Default.NewRegistry("apm-server.buildinfo")
+)
+
+// Info describes the build of the server, and the schemas it validates
+// events against, so that automation can verify all servers in a cluster
+// behave consistently, e.g. before requiring newer agents.
+type Info struct {
+	Version   string `json:"version"`
+	BuildSHA  string `json:"build_sha"`
+	BuildDate string `json:"build_date"`
+
+	// Features holds the state of the optional features of the server.
+	Features map[string]bool `json:"features"`
+
+	// FeatureFlags holds the state of the experimental feature flags.
+	FeatureFlags map[string]bool `json:"feature_flags"`
+
+	Schemas Schemas `json:"schemas"`
+}
+
+// Schemas describes the JSON schemas events are validated against.
+type Schemas struct {
+	// Versions holds the schema versions which may be selected.
+	Versions []string `json:"versions"`
+
+	// Digests holds digests of the latest schemas, keyed by API.
+	Digests map[string]string `json:"digests"`
+}
+
+// Handler returns a request.Handler reporting info.
+func Handler(info Info) request.Handler {
+	return func(c *request.Context) {
+		c.Result.SetDefault(request.IDResponseValidOK)
+		c.Result.Body = info
+		c.Write()
+	}
+}
@@ -0,0 +1,60 @@
+// Licensed to Elasticsearch B.V. under one or more contributor
+// license agreements. See the NOTICE file distributed with
+// this work for additional information regarding copyright
+// ownership. Elasticsearch B.V. licenses this file to you under
+// the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package buildinfo
+
+import (
+	"encoding/json"
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/elastic/apm-server/beater/beatertest"
+)
+
+func TestHandler(t *testing.T) {
+	info := Info{
+		Version:      "8.0.0",
+		BuildSHA:     "abc123",
+		BuildDate:    "2020-01-01T00:00:00Z",
+		Features:     map[string]bool{"rum": true},
+		FeatureFlags: map[string]bool{"profiles": false},
+		Schemas: Schemas{
+			Versions: []string{"latest"},
+			Digests:  map[string]string{"intake.v2": "sha256:0123"},
+		},
+	}
+	c, w := beatertest.ContextWithResponseRecorder(http.MethodGet, "/v1/build")
+	Handler(info)(c)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	var body map[string]interface{}
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &body))
+	assert.Equal(t, map[string]interface{}{
+		"version":       "8.0.0",
+		"build_sha":     "abc123",
+		"build_date":    "2020-01-01T00:00:00Z",
+		"features":      map[string]interface{}{"rum": true},
+		"feature_flags": map[string]interface{}{"profiles": false},
+		"schemas": map[string]interface{}{
+			"versions": []interface{}{"latest"},
+			"digests":  map[string]interface{}{"intake.v2": "sha256:0123"},
+		},
+	}, body)
+}
@@ -0,0 +1,68 @@
+// Licensed to Elasticsearch B.V. under one or more contributor
+// license agreements. See the NOTICE file distributed with
+// this work for additional information regarding copyright
+// ownership. Elasticsearch B.V. licenses this file to you under
+// the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package api
+
+import (
+	"sort"
+
+	"github.com/elastic/apm-server/beater/api/root"
+	"github.com/elastic/apm-server/beater/config"
+	"github.com/elastic/apm-server/decoder"
+)
+
+// capabilities returns the capabilities of the server with the given config,
+// reported by the root endpoint.
+func capabilities(cfg *config.Config) root.Capabilities {
+	var endpoints []string
+	for _, route := range routes(cfg, nil, nil, nil, nil, nil, nil, nil) {
+		if routeEnabled(cfg, route.path) {
+			endpoints = append(endpoints, route.path)
+		}
+	}
+	sort.Strings(endpoints)
+
+	versions := map[string][]string{"backend": {"v2"}}
+	if cfg.RumConfig.IsEnabled() {
+		versions["rum"] = []string{"v2", "v3"}
+	}
+	return root.Capabilities{
+		Endpoints:     endpoints,
+		MaxHeaderSize: cfg.MaxHeaderSize,
+		Intake: root.IntakeCapabilities{
+			Versions:         versions,
+			MaxEventSize:     cfg.MaxEventSize,
+			ContentEncodings: decoder.ContentEncodings,
+		},
+	}
+}
+
+// routeEnabled reports whether the route with the given path serves
+// requests, rather than rejecting them with the kill switch middleware.
+func routeEnabled(cfg *config.Config, path string) bool {
+	switch path {
+	case IntakeRUMPath, IntakeRUMV3Path:
+		return cfg.RumConfig.IsEnabled()
+	case AgentConfigPath:
+		return cfg.Kibana.Enabled
+	case AgentConfigRUMPath:
+		return cfg.Kibana.Enabled && cfg.RumConfig.IsEnabled()
+	case AssetSourcemapPath:
+		return cfg.RumConfig.IsEnabled() && cfg.RumConfig.SourceMapping.IsEnabled()
+	}
+	return true
+}
@@ -0,0 +1,48 @@
+// Licensed to Elasticsearch B.V. under one or more contributor
+// license agreements. See the NOTICE file distributed with
+// this work for additional information regarding copyright
+// ownership. Elasticsearch B.V. licenses this file to you under
+// the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package api
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/elastic/apm-server/beater/beatertest"
+	"github.com/elastic/apm-server/beater/config"
+)
+
+func TestCapabilities(t *testing.T) {
+	cfg := config.DefaultConfig(beatertest.MockBeatVersion())
+	c := capabilities(cfg)
+	assert.Equal(t, []string{RootPath, IntakePath, BuildInfoPath, StatusPath}, c.Endpoints)
+	assert.Equal(t, map[string][]string{"backend": {"v2"}}, c.Intake.Versions)
+	assert.Equal(t, cfg.MaxEventSize, c.Intake.MaxEventSize)
+	assert.Equal(t, cfg.MaxHeaderSize, c.MaxHeaderSize)
+	assert.Equal(t, []string{"deflate", "gzip", "zstd"}, c.Intake.ContentEncodings)
+
+	enabled := true
+	cfg.RumConfig.Enabled = &enabled
+	cfg.Kibana.Enabled = true
+	cfg.FeatureFlags.Profiles = true
+	c = capabilities(cfg)
+	assert.Equal(t, []string{
+		RootPath, AssetSourcemapPath, AgentConfigPath, AgentConfigRUMPath,
+		IntakePath, ProfilePath, IntakeRUMPath, IntakeRUMV3Path, BuildInfoPath, StatusPath,
+	}, c.Endpoints)
+	assert.Equal(t, map[string][]string{"backend": {"v2"}, "rum": {"v2", "v3"}}, c.Intake.Versions)
+}
@@ -0,0 +1,301 @@
+// Licensed to Elasticsearch B.V. under one or more contributor
+// license agreements. See the NOTICE file distributed with
+// this work for additional information regarding copyright
+// ownership. Elasticsearch B.V. licenses this file to you under
+// the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+// Package capture writes a sample of raw intake request bodies to disk,
+// for investigating agents sending malformed or unexpected events.
+package capture
+
+import (
+	"bufio"
+	"bytes"
+	"compress/gzip"
+	"compress/zlib"
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/klauspost/compress/zstd"
+	"golang.org/x/time/rate"
+
+	"github.com/elastic/beats/v7/libbeat/logp"
+	"github.com/elastic/beats/v7/libbeat/paths"
+
+	"github.com/elastic/apm-server/beater/config"
+	"github.com/elastic/apm-server/beater/headers"
+	logs "github.com/elastic/apm-server/log"
+)
+
+const fileExtension = ".ndjson"
+
+// Capturer records intake request bodies, and writes those matching its
+// filters to disk, up to a configured number per minute. Only the most
+// recently written request bodies are kept.
+type Capturer struct {
+	dir         string
+	maxFiles    int
+	maxBodySize int
+	services    map[string]bool
+	statusCodes map[int]bool
+	limiter     *rate.Limiter
+	logger      *logp.Logger
+
+	mu sync.Mutex // serializes writing and removing files
+}
+
+// New returns a new Capturer for cfg, creating its directory if needed.
+func New(cfg config.CaptureConfig) (*Capturer, error) {
+	dir := cfg.Path
+	if !filepath.IsAbs(dir) {
+		dir = paths.Resolve(paths.Data, dir)
+	}
+	if err := os.MkdirAll(dir, 0700); err != nil {
+		return nil, err
+	}
+	c := &Capturer{
+		dir:         dir,
+		maxFiles:    cfg.MaxFiles,
+		maxBodySize: cfg.MaxBodySize,
+		limiter:     rate.NewLimiter(rate.Every(time.Minute/time.Duration(cfg.RequestsPerMinute)), cfg.RequestsPerMinute),
+		logger:      logp.NewLogger(logs.Handler),
+	}
+	if len(cfg.ServiceNames) > 0 {
+		c.services = make(map[string]bool)
+		for _, name := range cfg.ServiceNames {
+			c.services[name] = true
+		}
+	}
+	if len(cfg.StatusCodes) > 0 {
+		c.statusCodes = make(map[int]bool)
+		for _, code := range cfg.StatusCodes {
+			c.statusCodes[code] = true
+		}
+	}
+	return c, nil
+}
+
+// Recording holds the start of a request body, as read by its handler.
+type Recording struct {
+	io.ReadCloser
+	buf      bytes.Buffer
+	max      int
+	encoding string
+
+	// services holds the service names to record request bodies for,
+	// or nil if bodies are recorded for all services. Once the service
+	// name has been read, matched records whether it is one of them.
+	services map[string]bool
+	decided  bool
+	matched  bool
+}
+
+// Read reads from the request body, recording up to the maximum body size.
+// Recording stops once the request body is known to be for a service which
+// is not captured.
+//
+// The service name is parsed once: when the first newline is read, or, for
+// compressed request bodies, once the body has been read or the maximum
+// body size recorded.
+func (r *Recording) Read(p []byte) (int, error) {
+	n, err := r.ReadCloser.Read(p)
+	if remaining := r.max - r.buf.Len(); remaining > 0 {
+		if remaining > n {
+			remaining = n
+		}
+		r.buf.Write(p[:remaining])
+		if r.services != nil && !r.decided {
+			newline := !compressed(r.encoding) && bytes.IndexByte(p[:remaining], '\n') >= 0
+			if newline || err == io.EOF || r.buf.Len() == r.max {
+				r.decide(serviceName(r.buf.Bytes(), r.encoding))
+			}
+		}
+	}
+	return n, err
+}
+
+// decide records whether the service name matches the filter,
+// discarding the recorded body if it does not.
+func (r *Recording) decide(name string) {
+	r.decided = true
+	r.matched = r.services[name]
+	if !r.matched {
+		r.buf = bytes.Buffer{}
+		r.max = 0
+	}
+}
+
+// Record replaces the body of req with one recording what is read from it.
+// Request bodies are not recorded while the per-minute limit is reached, as
+// they would not be captured.
+func (c *Capturer) Record(req *http.Request) *Recording {
+	if req.Body == nil || !c.allowed() {
+		return nil
+	}
+	r := &Recording{
+		ReadCloser: req.Body,
+		max:        c.maxBodySize,
+		encoding:   req.Header.Get(headers.ContentEncoding),
+		services:   c.services,
+	}
+	req.Body = r
+	return r
+}
+
+// allowed reports whether the per-minute limit currently allows capturing
+// a request body, without counting towards the limit.
+func (c *Capturer) allowed() bool {
+	now := time.Now()
+	r := c.limiter.ReserveN(now, 1)
+	defer r.CancelAt(now)
+	return r.OK() && r.DelayFrom(now) == 0
+}
+
+// Capture writes the request body recorded by r to disk, if the request
+// matches the configured filters and the per-minute limit has not been
+// reached. Errors writing the request body are logged.
+func (c *Capturer) Capture(req *http.Request, r *Recording, statusCode int, requestID string) {
+	if r == nil {
+		return
+	}
+	if r.services != nil && !r.decided {
+		r.decide(serviceName(r.buf.Bytes(), r.encoding))
+	}
+	if r.buf.Len() == 0 {
+		return
+	}
+	if c.statusCodes != nil && !c.statusCodes[statusCode] {
+		return
+	}
+	if !c.limiter.Allow() {
+		return
+	}
+	name := time.Now().UTC().Format("20060102T150405.000000000Z")
+	name += fmt.Sprintf("-%d", statusCode)
+	if requestID != "" {
+		// Request IDs may be sent by clients, so must not be used as paths.
+		name += "-" + strings.Map(func(r rune) rune {
+			if r >= 'a' && r <= 'z' || r >= 'A' && r <= 'Z' || r >= '0' && r <= '9' || r == '-' {
+				return r
+			}
+			return '_'
+		}, requestID)
+	}
+	name += fileExtension
+	switch r.encoding {
+	case "gzip":
+		name += ".gz"
+	case "deflate":
+		name += ".zz"
+	case "zstd":
+		name += ".zst"
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if err := ioutil.WriteFile(filepath.Join(c.dir, name), r.buf.Bytes(), 0600); err != nil {
+		c.logger.Errorw("failed to capture request body", "error", err)
+		return
+	}
+	if err := c.removeOldest(); err != nil {
+		c.logger.Errorw("failed to remove captured request bodies", "error", err)
+	}
+}
+
+// removeOldest removes the oldest captured request bodies, keeping maxFiles.
+// File names start with the capture time, so they sort from oldest to newest.
+func (c *Capturer) removeOldest() error {
+	infos, err := ioutil.ReadDir(c.dir)
+	if err != nil {
+		return err
+	}
+	var names []string
+	for _, info := range infos {
+		if info.Mode().IsRegular() && strings.Contains(info.Name(), fileExtension) {
+			names = append(names, info.Name())
+		}
+	}
+	sort.Strings(names)
+	for len(names) > c.maxFiles {
+		if err := os.Remove(filepath.Join(c.dir, names[0])); err != nil {
+			return err
+		}
+		names = names[1:]
+	}
+	return nil
+}
+
+// compressed reports whether request bodies with the given content encoding
+// are decompressed by serviceName.
+func compressed(encoding string) bool {
+	switch encoding {
+	case "gzip", "deflate", "zstd":
+		return true
+	}
+	return false
+}
+
+// serviceName returns the service name from the metadata at the start of
+// an intake request body, or the empty string if it cannot be determined.
+func serviceName(body []byte, encoding string) string {
+	var reader io.Reader = bytes.NewReader(body)
+	var err error
+	switch encoding {
+	case "gzip":
+		reader, err = gzip.NewReader(reader)
+	case "deflate":
+		reader, err = zlib.NewReader(reader)
+	case "zstd":
+		var zr *zstd.Decoder
+		if zr, err = zstd.NewReader(reader, zstd.WithDecoderConcurrency(1)); err == nil {
+			defer zr.Close()
+			reader = zr
+		}
+	}
+	if err != nil {
+		return ""
+	}
+	line, err := bufio.NewReader(reader).ReadBytes('\n')
+	if err != nil && err != io.EOF {
+		return ""
+	}
+	var metadata struct {
+		V2 struct {
+			Service struct {
+				Name string `json:"name"`
+			} `json:"service"`
+		} `json:"metadata"`
+		V3 struct {
+			Service struct {
+				Name string `json:"n"`
+			} `json:"se"`
+		} `json:"m"`
+	}
+	if err := json.Unmarshal(line, &metadata); err != nil {
+		return ""
+	}
+	if metadata.V2.Service.Name != "" {
+		return metadata.V2.Service.Name
+	}
+	return metadata.V3.Service.Name
+}
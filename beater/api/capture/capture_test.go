@@ -0,0 +1,187 @@
+// Licensed to Elasticsearch B.V. under one or more contributor
+// license agreements. See the NOTICE file distributed with
+// this work for additional information regarding copyright
+// ownership. Elasticsearch B.V. licenses this file to you under
+// the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package capture
+
+import (
+	"bytes"
+	"compress/gzip"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"testing/iotest"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/elastic/apm-server/beater/config"
+)
+
+const testBody = `{"metadata":{"service":{"name":"opbeans-go"}}}
+{"transaction":{}}
+`
+
+func TestCapture(t *testing.T) {
+	c, dir := newTestCapturer(t, func(cfg *config.CaptureConfig) {})
+	defer os.RemoveAll(dir)
+	capture(t, c, testBody, "", http.StatusAccepted, "abc/../123")
+
+	infos, err := ioutil.ReadDir(dir)
+	require.NoError(t, err)
+	require.Len(t, infos, 1)
+	assert.Regexp(t, `^\d{8}T\d{6}\.\d{9}Z-202-abc____123\.ndjson$`, infos[0].Name())
+	captured, err := ioutil.ReadFile(filepath.Join(dir, infos[0].Name()))
+	require.NoError(t, err)
+	assert.Equal(t, testBody, string(captured))
+}
+
+func TestCaptureMaxBodySize(t *testing.T) {
+	c, dir := newTestCapturer(t, func(cfg *config.CaptureConfig) { cfg.MaxBodySize = 10 })
+	defer os.RemoveAll(dir)
+	capture(t, c, testBody, "", http.StatusAccepted, "")
+
+	captured := readCaptured(t, dir)
+	require.Len(t, captured, 1)
+	assert.Equal(t, testBody[:10], captured[0])
+}
+
+func TestCaptureMaxFiles(t *testing.T) {
+	c, dir := newTestCapturer(t, func(cfg *config.CaptureConfig) { cfg.MaxFiles = 2 })
+	defer os.RemoveAll(dir)
+	for _, body := range []string{"1\n", "2\n", "3\n"} {
+		capture(t, c, body, "", http.StatusAccepted, "")
+	}
+	assert.Equal(t, []string{"2\n", "3\n"}, readCaptured(t, dir))
+}
+
+func TestCaptureRequestsPerMinute(t *testing.T) {
+	c, dir := newTestCapturer(t, func(cfg *config.CaptureConfig) { cfg.RequestsPerMinute = 2 })
+	defer os.RemoveAll(dir)
+	for _, body := range []string{"1\n", "2\n", "3\n"} {
+		capture(t, c, body, "", http.StatusAccepted, "")
+	}
+	assert.Equal(t, []string{"1\n", "2\n"}, readCaptured(t, dir))
+
+	// Request bodies are not recorded while the limit is reached.
+	req := httptest.NewRequest(http.MethodPost, "/", bytes.NewReader([]byte("4\n")))
+	assert.Nil(t, c.Record(req))
+}
+
+func TestCaptureStatusCodes(t *testing.T) {
+	c, dir := newTestCapturer(t, func(cfg *config.CaptureConfig) {
+		cfg.StatusCodes = []int{http.StatusBadRequest}
+	})
+	defer os.RemoveAll(dir)
+	capture(t, c, "1\n", "", http.StatusAccepted, "")
+	capture(t, c, "2\n", "", http.StatusBadRequest, "")
+	assert.Equal(t, []string{"2\n"}, readCaptured(t, dir))
+}
+
+func TestCaptureServiceNames(t *testing.T) {
+	c, dir := newTestCapturer(t, func(cfg *config.CaptureConfig) {
+		cfg.ServiceNames = []string{"opbeans-go", "opbeans-rum"}
+	})
+	defer os.RemoveAll(dir)
+	v3Body := `{"m":{"se":{"n":"opbeans-rum"}}}` + "\n"
+	var gzipBody bytes.Buffer
+	zw := gzip.NewWriter(&gzipBody)
+	zw.Write([]byte(testBody))
+	zw.Close()
+
+	capture(t, c, testBody, "", http.StatusAccepted, "")
+	capture(t, c, v3Body, "", http.StatusAccepted, "")
+	capture(t, c, gzipBody.String(), "gzip", http.StatusAccepted, "")
+	capture(t, c, `{"metadata":{"service":{"name":"opbeans-java"}}}`, "", http.StatusAccepted, "")
+	capture(t, c, "invalid\n", "", http.StatusAccepted, "")
+	assert.Equal(t, []string{testBody, v3Body, gzipBody.String()}, readCaptured(t, dir))
+
+	// Recording stops once the service name is known not to match,
+	// and such requests do not count towards the per-minute limit.
+	body := `{"metadata":{"service":{"name":"opbeans-java"}}}` + "\n" + strings.Repeat(`{"transaction":{}}`+"\n", 100)
+	req := httptest.NewRequest(http.MethodPost, "/", iotest.OneByteReader(strings.NewReader(body)))
+	r := c.Record(req)
+	require.NotNil(t, r)
+	read, err := ioutil.ReadAll(req.Body)
+	require.NoError(t, err)
+	assert.Equal(t, body, string(read))
+	assert.Zero(t, r.buf.Len())
+	assert.Zero(t, r.buf.Cap())
+
+	// The service name of compressed request bodies
+	// is parsed once the whole body has been read.
+	gzipBody.Reset()
+	zw = gzip.NewWriter(&gzipBody)
+	zw.Write([]byte(body))
+	zw.Close()
+	req = httptest.NewRequest(http.MethodPost, "/", iotest.OneByteReader(bytes.NewReader(gzipBody.Bytes())))
+	req.Header.Set("Content-Encoding", "gzip")
+	r = c.Record(req)
+	require.NotNil(t, r)
+	_, err = ioutil.ReadAll(io.LimitReader(req.Body, int64(gzipBody.Len()-1)))
+	require.NoError(t, err)
+	assert.False(t, r.decided)
+	_, err = ioutil.ReadAll(req.Body)
+	require.NoError(t, err)
+	assert.True(t, r.decided)
+	assert.False(t, r.matched)
+	assert.Zero(t, r.buf.Len())
+}
+
+// newTestCapturer returns a Capturer writing to a new temporary directory,
+// which the caller must remove.
+func newTestCapturer(t *testing.T, configure func(*config.CaptureConfig)) (*Capturer, string) {
+	dir, err := ioutil.TempDir("", "apm-server-capture")
+	require.NoError(t, err)
+
+	cfg := config.DefaultConfig("8.0.0").Capture
+	cfg.Enabled = true
+	cfg.Path = dir
+	configure(&cfg)
+	c, err := New(cfg)
+	require.NoError(t, err)
+	return c, dir
+}
+
+func capture(t *testing.T, c *Capturer, body, encoding string, statusCode int, requestID string) {
+	req := httptest.NewRequest(http.MethodPost, "/", bytes.NewReader([]byte(body)))
+	if encoding != "" {
+		req.Header.Set("Content-Encoding", encoding)
+	}
+	r := c.Record(req)
+	read, err := ioutil.ReadAll(req.Body)
+	require.NoError(t, err)
+	assert.Equal(t, body, string(read))
+	c.Capture(req, r, statusCode, requestID)
+}
+
+// readCaptured returns the captured request bodies, from oldest to newest.
+func readCaptured(t *testing.T, dir string) []string {
+	infos, err := ioutil.ReadDir(dir)
+	require.NoError(t, err)
+	var bodies []string
+	for _, info := range infos {
+		body, err := ioutil.ReadFile(filepath.Join(dir, info.Name()))
+		require.NoError(t, err)
+		bodies = append(bodies, string(body))
+	}
+	return bodies
+}
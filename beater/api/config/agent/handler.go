@@ -19,14 +19,19 @@ package agent
 
 import (
 	"fmt"
+	"hash/fnv"
 	"net/http"
+	"sort"
 	"strings"
+	"sync"
 	"time"
 
+	"github.com/hashicorp/golang-lru/simplelru"
 	"go.elastic.co/apm"
 
 	"github.com/pkg/errors"
 
+	"github.com/elastic/beats/v7/libbeat/logp"
 	"github.com/elastic/beats/v7/libbeat/monitoring"
 
 	"github.com/elastic/apm-server/agentcfg"
@@ -35,11 +40,16 @@ import (
 	"github.com/elastic/apm-server/beater/request"
 	"github.com/elastic/apm-server/convert"
 	"github.com/elastic/apm-server/kibana"
+	logs "github.com/elastic/apm-server/log"
 )
 
 const (
 	errMaxAgeDuration = 5 * time.Minute
 
+	// maxBlockedSettingsLogged bounds the number of blocked settings
+	// remembered for deduplicating audit log entries.
+	maxBlockedSettingsLogged = 1000
+
 	msgInvalidQuery               = "invalid query"
 	msgKibanaDisabled             = "disabled Kibana configuration"
 	msgKibanaVersionNotCompatible = "not a compatible Kibana version"
@@ -52,6 +62,7 @@ var (
 	// MonitoringMap holds a mapping for request.IDs to monitoring counters
 	MonitoringMap = request.DefaultMonitoringMapForRegistry(registry)
 	registry      = monitoring.Default.NewRegistry("apm-server.acm")
+	blocked       = monitoring.NewInt(registry, "settings.blocked")
 
 	errMsgKibanaDisabled     = errors.New(msgKibanaDisabled)
 	errMsgNoKibanaConnection = errors.New(msgNoKibanaConnection)
@@ -62,9 +73,21 @@ var (
 )
 
 // Handler returns a request.Handler for managing agent central configuration requests.
-func Handler(client kibana.Client, config *config.AgentConfig) request.Handler {
+//
+// Settings in defaults are returned for those not configured in Kibana. The
+// returned Etags incorporate the defaults, so that agents pick up changes to
+// them.
+//
+// Settings configured in Kibana which are blocked by the configured denylist
+// are not returned. An audit log entry is written when a setting is first
+// blocked for a service, and whenever its blocked value changes, rather than
+// on every poll. Like the defaults, the denylist is incorporated into the
+// returned Etags.
+func Handler(client kibana.Client, config *config.AgentConfig, defaults agentcfg.Settings) request.Handler {
 	cacheControl := fmt.Sprintf("max-age=%v, must-revalidate", config.Cache.Expiration.Seconds())
 	fetcher := agentcfg.NewFetcher(client, config.Cache.Expiration)
+	defaultsEtag := settingsEtag(defaults) + denylistEtag(config.Denylist)
+	audit := newBlockedSettingsLog(maxBlockedSettingsLogged)
 
 	return func(c *request.Context) {
 		// error handling
@@ -82,7 +105,7 @@ func Handler(client kibana.Client, config *config.AgentConfig) request.Handler {
 			return
 		}
 
-		query, queryErr := buildQuery(c)
+		query, queryErr := buildQuery(c, defaultsEtag)
 		if queryErr != nil {
 			extractQueryError(c, queryErr, c.Authorization.IsAuthorizationConfigured())
 			c.Write()
@@ -98,14 +121,16 @@ func Handler(client kibana.Client, config *config.AgentConfig) request.Handler {
 		}
 
 		// configuration successfully fetched
+		etag := result.Source.Etag + defaultsEtag
 		c.Header().Set(headers.CacheControl, cacheControl)
-		c.Header().Set(headers.Etag, fmt.Sprintf("\"%s\"", result.Source.Etag))
-		c.Header().Set(headers.AccessControlExposeHeaders, headers.Etag)
+		c.Header().Set(headers.Etag, fmt.Sprintf("\"%s\"", etag))
+		c.Header().Add(headers.AccessControlExposeHeaders, headers.Etag)
 
-		if result.Source.Etag == ifNoneMatch(c) {
+		if etag == ifNoneMatch(c) {
 			c.Result.SetDefault(request.IDResponseValidNotModified)
 		} else {
-			c.Result.SetWithBody(request.IDResponseValidOK, result.Source.Settings)
+			settings := withoutDenied(c, query.Service, result.Source.Settings, config.Denylist, audit)
+			c.Result.SetWithBody(request.IDResponseValidOK, withDefaults(settings, defaults))
 		}
 		c.Write()
 	}
@@ -146,7 +171,139 @@ func validateClient(c *request.Context, client kibana.Client, withAuth bool) boo
 	return true
 }
 
-func buildQuery(c *request.Context) (query agentcfg.Query, err error) {
+// withDefaults returns settings with defaults added for settings not
+// present. The given settings may be cached, and are not modified.
+func withDefaults(settings, defaults agentcfg.Settings) agentcfg.Settings {
+	if len(defaults) == 0 {
+		return settings
+	}
+	merged := make(agentcfg.Settings, len(settings)+len(defaults))
+	for k, v := range defaults {
+		merged[k] = v
+	}
+	for k, v := range settings {
+		merged[k] = v
+	}
+	return merged
+}
+
+// withoutDenied returns settings without those blocked by denylist, logging
+// an audit entry for each blocked setting not yet logged by audit. The given
+// settings may be cached, and are not modified.
+func withoutDenied(
+	c *request.Context,
+	service agentcfg.Service,
+	settings agentcfg.Settings,
+	denylist []config.AgentConfigDenyRule,
+	audit *blockedSettingsLog,
+) agentcfg.Settings {
+	var allowed agentcfg.Settings
+	for k, v := range settings {
+		if !denied(service, k, v, denylist) {
+			continue
+		}
+		if allowed == nil {
+			allowed = make(agentcfg.Settings, len(settings))
+			for k, v := range settings {
+				allowed[k] = v
+			}
+		}
+		delete(allowed, k)
+		blocked.Inc()
+		if !audit.changed(service, k, v) {
+			continue
+		}
+
+		logger := c.Logger
+		if logger == nil {
+			logger = logp.NewLogger(logs.Handler)
+		}
+		logger.Infow("blocked agent config setting",
+			"service.name", service.Name,
+			"service.environment", service.Environment,
+			"setting", k,
+			"value", v,
+		)
+	}
+	if allowed == nil {
+		return settings
+	}
+	return allowed
+}
+
+// blockedSettingsLog records the values of blocked settings which have been
+// logged, so an audit entry is only written when a setting is first blocked
+// for a service, or its value changes. Agents poll for their configuration
+// every 30 seconds by default, so logging on every poll would flood the logs.
+type blockedSettingsLog struct {
+	mu     sync.Mutex
+	logged *simplelru.LRU
+}
+
+type blockedSettingKey struct {
+	service, environment, setting string
+}
+
+func newBlockedSettingsLog(size int) *blockedSettingsLog {
+	// NewLRU only fails for non-positive sizes.
+	logged, _ := simplelru.NewLRU(size, nil)
+	return &blockedSettingsLog{logged: logged}
+}
+
+// changed records value as logged for the given service and setting,
+// reporting whether it differs from the previously logged value.
+func (l *blockedSettingsLog) changed(service agentcfg.Service, setting, value string) bool {
+	key := blockedSettingKey{service: service.Name, environment: service.Environment, setting: setting}
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if logged, ok := l.logged.Get(key); ok && logged.(string) == value {
+		return false
+	}
+	l.logged.Add(key, value)
+	return true
+}
+
+func denied(service agentcfg.Service, setting, value string, denylist []config.AgentConfigDenyRule) bool {
+	for i := range denylist {
+		if denylist[i].Blocks(service.Name, service.Environment, setting, value) {
+			return true
+		}
+	}
+	return false
+}
+
+// denylistEtag returns a suffix identifying denylist, to be appended to
+// the Etags of results, or an empty string if denylist is empty.
+func denylistEtag(denylist []config.AgentConfigDenyRule) string {
+	if len(denylist) == 0 {
+		return ""
+	}
+	h := fnv.New32a()
+	for _, rule := range denylist {
+		fmt.Fprintf(h, "%s=%s;%s;%s;", rule.Setting, strings.Join(rule.Values, ","), rule.Service, rule.Environment)
+	}
+	return fmt.Sprintf("-%08x", h.Sum32())
+}
+
+// settingsEtag returns a suffix identifying settings, to be appended to
+// the Etags of results, or an empty string if settings is empty.
+func settingsEtag(settings agentcfg.Settings) string {
+	if len(settings) == 0 {
+		return ""
+	}
+	keys := make([]string, 0, len(settings))
+	for k := range settings {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	h := fnv.New32a()
+	for _, k := range keys {
+		fmt.Fprintf(h, "%s=%s;", k, settings[k])
+	}
+	return fmt.Sprintf("-%08x", h.Sum32())
+}
+
+func buildQuery(c *request.Context, defaultsEtag string) (query agentcfg.Query, err error) {
 	r := c.Request
 
 	switch r.Method {
@@ -170,7 +327,8 @@ func buildQuery(c *request.Context) (query agentcfg.Query, err error) {
 	if c.IsRum {
 		query.InsecureAgents = rumAgents
 	}
-	query.Etag = ifNoneMatch(c)
+	// The Etag suffix identifying the server's defaults is unknown to Kibana.
+	query.Etag = strings.TrimSuffix(ifNoneMatch(c), defaultsEtag)
 	return
 }
 
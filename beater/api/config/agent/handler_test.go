@@ -25,6 +25,7 @@ import (
 	"net/http"
 	"net/http/httptest"
 	"net/url"
+	"strings"
 	"testing"
 	"time"
 
@@ -35,8 +36,10 @@ import (
 
 	"github.com/elastic/beats/v7/libbeat/common"
 	libkibana "github.com/elastic/beats/v7/libbeat/kibana"
+	"github.com/elastic/beats/v7/libbeat/logp"
 
 	"github.com/elastic/apm-server/agentcfg"
+	"github.com/elastic/apm-server/beater/api/ratelimit"
 	"github.com/elastic/apm-server/beater/authorization"
 	"github.com/elastic/apm-server/beater/config"
 	"github.com/elastic/apm-server/beater/headers"
@@ -181,7 +184,7 @@ func TestAgentConfigHandler(t *testing.T) {
 	for name, tc := range testcases {
 
 		runTest := func(t *testing.T, expectedBody map[string]string, auth authorization.Authorization) {
-			h := Handler(tc.kbClient, &cfg)
+			h := Handler(tc.kbClient, &cfg, nil)
 			w := httptest.NewRecorder()
 			r := httptest.NewRequest(tc.method, target(tc.queryParams), nil)
 			for k, v := range tc.requestHeader {
@@ -214,7 +217,7 @@ func TestAgentConfigHandler(t *testing.T) {
 
 func TestAgentConfigHandler_NoKibanaClient(t *testing.T) {
 	cfg := config.AgentConfig{Cache: &config.Cache{Expiration: time.Nanosecond}}
-	h := Handler(nil, &cfg)
+	h := Handler(nil, &cfg, nil)
 
 	w := httptest.NewRecorder()
 	ctx := request.NewContext()
@@ -236,7 +239,7 @@ func TestAgentConfigHandler_PostOk(t *testing.T) {
 	}, mockVersion, true)
 
 	var cfg = config.AgentConfig{Cache: &config.Cache{Expiration: time.Nanosecond}}
-	h := Handler(kb, &cfg)
+	h := Handler(kb, &cfg, nil)
 
 	w := httptest.NewRecorder()
 	r := httptest.NewRequest(http.MethodPost, "/config", convert.ToReader(m{
@@ -264,6 +267,42 @@ func TestAgentConfigRum(t *testing.T) {
 	assert.Equal(t, map[string]string{"transaction_sample_rate": "0.5"}, actual)
 }
 
+func TestAgentConfigRumDefaults(t *testing.T) {
+	h := getHandlerWithDefaults("rum-js", agentcfg.Settings{
+		agentcfg.TraceContinuationStrategy: "restart_external",
+		"transaction_sample_rate":          "1",
+	})
+	get := func(etag string) *httptest.ResponseRecorder {
+		w := httptest.NewRecorder()
+		r := httptest.NewRequest(http.MethodGet, "/rum?service.name=opbeans", nil)
+		if etag != "" {
+			r.Header.Set(headers.IfNoneMatch, etag)
+		}
+		ctx := request.NewContext()
+		ctx.Reset(w, r)
+		ctx.IsRum = true
+		h(ctx)
+		return w
+	}
+
+	w := get("")
+	var actual map[string]string
+	json.Unmarshal(w.Body.Bytes(), &actual)
+	assert.Equal(t, http.StatusOK, w.Code, w.Body.String())
+	// Settings configured in Kibana take precedence over the defaults.
+	assert.Equal(t, map[string]string{
+		"transaction_sample_rate":     "0.5",
+		"trace_continuation_strategy": "restart_external",
+	}, actual)
+	etag := w.Header().Get(headers.Etag)
+	assert.True(t, strings.HasPrefix(etag, `"123-`), etag)
+
+	// The Etag returned by Kibana alone does not match, as the
+	// agent has not yet received the defaults.
+	assert.Equal(t, http.StatusOK, get(`"123"`).Code)
+	assert.Equal(t, http.StatusNotModified, get(etag).Code)
+}
+
 func TestAgentConfigRumEtag(t *testing.T) {
 	h := getHandler("rum-js")
 	w := httptest.NewRecorder()
@@ -312,7 +351,7 @@ func TestAgentConfigRateLimit(t *testing.T) {
 	ctx := request.NewContext()
 	ctx.Reset(w, r)
 	ctx.IsRum = true
-	ctx.RateLimiter = rate.NewLimiter(rate.Limit(0), 0)
+	ctx.RateLimiter = ratelimit.NewLimiter(rate.Limit(0), 0)
 	h(ctx)
 	var actual map[string]string
 	json.Unmarshal(w.Body.Bytes(), &actual)
@@ -320,7 +359,74 @@ func TestAgentConfigRateLimit(t *testing.T) {
 	assert.Equal(t, map[string]string{"error": "too many requests"}, actual)
 }
 
+func TestAgentConfigDenylist(t *testing.T) {
+	require.NoError(t, logp.DevelopmentSetup(logp.ToObserverOutput()))
+	kb := tests.MockKibana(http.StatusOK, m{
+		"_id": "1",
+		"_source": m{
+			"settings": m{
+				"transaction_sample_rate": 0.5,
+				"capture_body":            "all",
+			},
+			"etag":       "123",
+			"agent_name": "go",
+		},
+	}, mockVersion, true)
+	cfg := config.AgentConfig{
+		Cache: &config.Cache{Expiration: time.Nanosecond},
+		Denylist: []config.AgentConfigDenyRule{
+			{Setting: "capture_body", Values: []string{"ALL"}, Environment: "prod*"},
+			{Setting: "transaction_sample_rate", Service: "other"},
+		},
+	}
+	h := Handler(kb, &cfg, nil)
+	get := func(query string) (*httptest.ResponseRecorder, map[string]string) {
+		w := httptest.NewRecorder()
+		ctx := request.NewContext()
+		ctx.Reset(w, httptest.NewRequest(http.MethodGet, "/config?"+query, nil))
+		h(ctx)
+		var actual map[string]string
+		json.Unmarshal(w.Body.Bytes(), &actual)
+		return w, actual
+	}
+
+	w, settings := get("service.name=opbeans&service.environment=production")
+	assert.Equal(t, http.StatusOK, w.Code, w.Body.String())
+	assert.Equal(t, map[string]string{"transaction_sample_rate": "0.5"}, settings)
+	// The Etag incorporates the denylist, so agents pick up changes to it.
+	etag := w.Header().Get(headers.Etag)
+	assert.True(t, strings.HasPrefix(etag, `"123-`), etag)
+
+	w, settings = get("service.name=opbeans&service.environment=staging")
+	assert.Equal(t, http.StatusOK, w.Code, w.Body.String())
+	assert.Equal(t, map[string]string{"transaction_sample_rate": "0.5", "capture_body": "all"}, settings)
+
+	// Blocked settings are only logged once, not on every poll.
+	_, settings = get("service.name=opbeans&service.environment=production")
+	assert.Equal(t, map[string]string{"transaction_sample_rate": "0.5"}, settings)
+	logs := logp.ObserverLogs().FilterMessage("blocked agent config setting").All()
+	require.Len(t, logs, 1)
+	assert.Equal(t, "capture_body", logs[0].ContextMap()["setting"])
+}
+
+func TestBlockedSettingsLog(t *testing.T) {
+	audit := newBlockedSettingsLog(2)
+	opbeans := agentcfg.Service{Name: "opbeans", Environment: "production"}
+	assert.True(t, audit.changed(opbeans, "capture_body", "all"))
+	assert.False(t, audit.changed(opbeans, "capture_body", "all"))
+	assert.True(t, audit.changed(opbeans, "capture_body", "errors"))
+	assert.True(t, audit.changed(agentcfg.Service{Name: "opbeans"}, "capture_body", "errors"))
+	assert.True(t, audit.changed(opbeans, "log_level", "debug"))
+
+	// The least recently logged setting is evicted.
+	assert.True(t, audit.changed(opbeans, "capture_body", "errors"))
+}
+
 func getHandler(agent string) request.Handler {
+	return getHandlerWithDefaults(agent, nil)
+}
+
+func getHandlerWithDefaults(agent string, defaults agentcfg.Settings) request.Handler {
 	kb := tests.MockKibana(http.StatusOK, m{
 		"_id": "1",
 		"_source": m{
@@ -334,7 +440,7 @@ func getHandler(agent string) request.Handler {
 	}, mockVersion, true)
 
 	var cfg = config.AgentConfig{Cache: &config.Cache{Expiration: time.Nanosecond}}
-	return Handler(kb, &cfg)
+	return Handler(kb, &cfg, defaults)
 }
 
 func TestIfNoneMatch(t *testing.T) {
@@ -358,7 +464,7 @@ func TestAgentConfigTraceContext(t *testing.T) {
 	kibanaCfg := libkibana.DefaultClientConfig()
 	kibanaCfg.Host = "testKibana:12345"
 	client := kibana.NewConnectingClient(&kibanaCfg)
-	handler := Handler(client, &config.AgentConfig{Cache: &config.Cache{Expiration: 5 * time.Minute}})
+	handler := Handler(client, &config.AgentConfig{Cache: &config.Cache{Expiration: 5 * time.Minute}}, nil)
 	_, spans, _ := apmtest.WithTransaction(func(ctx context.Context) {
 		// When the handler is called with a context containing
 		// a transaction, the underlying Kibana query should create a span
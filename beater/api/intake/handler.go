@@ -21,7 +21,9 @@ import (
 	"fmt"
 	"io"
 	"net/http"
+	"strconv"
 	"strings"
+	"time"
 
 	"github.com/pkg/errors"
 
@@ -57,13 +59,18 @@ func Handler(processor *stream.Processor, report publish.Reporter) request.Handl
 			return
 		}
 
-		reader, serr := bodyReader(c.Request)
+		reader, serr := bodyReader(c.Request, processor.MaxDecompressedSize)
 		if serr != nil {
 			sendError(c, serr)
 			return
 		}
+		defer reader.Close()
 
-		res := processor.HandleStream(c.Request.Context(), c.RateLimiter, c.RequestMetadata, reader, report)
+		var rateLimiter stream.RateLimiter
+		if c.RateLimiter != nil {
+			rateLimiter = c.RateLimiter
+		}
+		res := processor.HandleStream(c.Request.Context(), rateLimiter, c.RequestMetadata, reader, report)
 		sendResponse(c, res)
 	}
 }
@@ -97,6 +104,18 @@ L:
 		case stream.ShuttingDownErrType:
 			set(request.MapResultIDToStatus[request.IDResponseErrorsShuttingDown].Code, request.IDResponseErrorsShuttingDown)
 			break L
+		case stream.TimeoutErrType:
+			set(request.MapResultIDToStatus[request.IDResponseErrorsTimeout].Code, request.IDResponseErrorsTimeout)
+			break L
+		case stream.QuotaExceededErrType:
+			set(request.MapResultIDToStatus[request.IDResponseErrorsQuotaExceeded].Code, request.IDResponseErrorsQuotaExceeded)
+			break L
+		case stream.ServiceNotAllowedErrType:
+			set(request.MapResultIDToStatus[request.IDResponseErrorsForbidden].Code, request.IDResponseErrorsForbidden)
+			break L
+		case stream.EventTypeNotAllowedErrType:
+			// Events of other types in the stream are still accepted.
+			set(request.MapResultIDToStatus[request.IDResponseErrorsForbidden].Code, request.IDResponseErrorsForbidden)
 		default:
 			set(request.MapResultIDToStatus[request.IDResponseErrorsInternal].Code, request.IDResponseErrorsInternal)
 		}
@@ -108,6 +127,7 @@ L:
 		// but also signals to http.Server that it should close it:
 		// https://golang.org/src/net/http/server.go#L1254
 		c.Header().Add(headers.Connection, "Close")
+		sr.RequestID = c.RequestID
 		body = sr
 	} else if _, ok := c.Request.URL.Query()["verbose"]; ok {
 		body = sr
@@ -117,6 +137,17 @@ L:
 		err = errors.New(errMsg)
 	}
 	c.Result.Set(id, code, request.MapResultIDToStatus[id].Keyword, body, err)
+	switch id {
+	case request.IDResponseErrorsValidate, request.IDResponseErrorsRequestTooLarge:
+		// Rejected events are logged in a periodic summary instead.
+		c.Result.Summarized = sr.Summarized()
+	}
+	if retryAfter := sr.RetryAfter(); retryAfter > 0 {
+		c.Header().Set(headers.RetryAfter, strconv.Itoa(int((retryAfter+time.Second-1)/time.Second)))
+	}
+	if rate, ok := sr.SamplingRate(); ok {
+		c.Header().Set(headers.ElasticAPMSamplingRate, strconv.FormatFloat(rate, 'f', -1, 64))
+	}
 	c.Write()
 }
 func sendError(c *request.Context, err *stream.Error) {
@@ -142,8 +173,8 @@ func validateRequest(r *http.Request) *stream.Error {
 	return nil
 }
 
-func bodyReader(r *http.Request) (io.ReadCloser, *stream.Error) {
-	reader, err := decoder.CompressedRequestReader(r)
+func bodyReader(r *http.Request, maxDecompressedSize int64) (io.ReadCloser, *stream.Error) {
+	reader, err := decoder.CompressedRequestReader(r, maxDecompressedSize)
 	if err != nil {
 		return nil, &stream.Error{
 			Type:    stream.InvalidInputErrType,
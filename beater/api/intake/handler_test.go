@@ -22,10 +22,12 @@ import (
 	"compress/gzip"
 	"compress/zlib"
 	"context"
+	"encoding/json"
 	"net/http"
 	"net/http/httptest"
 	"path/filepath"
 	"testing"
+	"time"
 
 	"github.com/elastic/apm-server/beater/api/ratelimit"
 
@@ -38,8 +40,10 @@ import (
 	"github.com/elastic/apm-server/beater/request"
 	"github.com/elastic/apm-server/processor/stream"
 	"github.com/elastic/apm-server/publish"
+	"github.com/elastic/apm-server/quota"
 	"github.com/elastic/apm-server/tests/approvals"
 	"github.com/elastic/apm-server/tests/loader"
+	"github.com/elastic/apm-server/transform"
 )
 
 func TestIntakeHandler(t *testing.T) {
@@ -94,6 +98,26 @@ func TestIntakeHandler(t *testing.T) {
 			r:    compressedRequest(t, "gzip", true),
 			code: http.StatusAccepted, id: request.IDResponseValidAccepted,
 		},
+		"CompressedBodyReaderGzipTooLarge": {
+			path: "errors.ndjson",
+			r:    compressedRequest(t, "gzip", true),
+			processor: func() *stream.Processor {
+				cfg := config.DefaultConfig("7.0.0")
+				cfg.MaxDecompressedBodySize = 100
+				return stream.BackendProcessor(cfg)
+			}(),
+			code: http.StatusBadRequest, id: request.IDResponseErrorsRequestTooLarge,
+		},
+		"CompressedBodyReaderGzipTooLargeRUMV3": {
+			path: "errors.ndjson",
+			r:    compressedRequest(t, "gzip", true),
+			processor: func() *stream.Processor {
+				cfg := config.DefaultConfig("7.0.0")
+				cfg.MaxDecompressedBodySize = 100
+				return stream.RUMV3Processor(cfg, &transform.Config{})
+			}(),
+			code: http.StatusBadRequest, id: request.IDResponseErrorsRequestTooLarge,
+		},
 		"TooLarge": {
 			path: "errors.ndjson", processor: &stream.Processor{},
 			code: http.StatusBadRequest, id: request.IDResponseErrorsRequestTooLarge},
@@ -103,6 +127,26 @@ func TestIntakeHandler(t *testing.T) {
 		"FullQueue": {
 			path: "errors.ndjson", reporter: beatertest.ErrorReporterFn(publish.ErrFull),
 			code: http.StatusServiceUnavailable, id: request.IDResponseErrorsFullQueue},
+		"Timeout": {
+			path: "errors.ndjson", reporter: beatertest.ErrorReporterFn(context.DeadlineExceeded),
+			code: http.StatusServiceUnavailable, id: request.IDResponseErrorsTimeout},
+		"QuotaExceeded": {
+			path: "errors.ndjson", reporter: beatertest.ErrorReporterFn(quota.ErrExceeded),
+			code: http.StatusTooManyRequests, id: request.IDResponseErrorsQuotaExceeded},
+		"ServiceNotAllowed": {
+			path: "errors.ndjson",
+			processor: stream.RUMProcessor(&config.Config{
+				MaxEventSize: 100 * 1024,
+				RumConfig:    &config.RumConfig{AllowServiceNames: []string{"frontend"}},
+			}, &transform.Config{}),
+			code: http.StatusForbidden, id: request.IDResponseErrorsForbidden},
+		"EventTypeNotAllowed": {
+			path: "errors.ndjson",
+			processor: stream.RUMProcessor(&config.Config{
+				MaxEventSize: 100 * 1024,
+				RumConfig:    &config.RumConfig{AllowEventTypes: []string{"transaction"}},
+			}, &transform.Config{}),
+			code: http.StatusForbidden, id: request.IDResponseErrorsForbidden},
 		"InvalidEvent": {
 			path: "invalid-event.ndjson",
 			code: http.StatusBadRequest, id: request.IDResponseErrorsValidate},
@@ -153,6 +197,54 @@ func TestIntakeHandler(t *testing.T) {
 	}
 }
 
+func TestIntakeHandlerQuotaExceeded(t *testing.T) {
+	reset := time.Now().Add(time.Hour)
+	tc := testcaseIntakeHandler{path: "errors.ndjson",
+		reporter: beatertest.ErrorReporterFn(&quota.ExceededError{Accepted: 2, Reset: reset})}
+	tc.setup(t)
+	Handler(tc.processor, tc.reporter)(tc.c)
+	assert.Equal(t, http.StatusTooManyRequests, tc.w.Code)
+	assert.Equal(t, string(request.IDResponseErrorsQuotaExceeded), string(tc.c.Result.ID))
+	assert.Equal(t, "3600", tc.w.Header().Get(headers.RetryAfter))
+
+	var body struct {
+		Accepted int
+		Errors   []struct{ Message string }
+	}
+	require.NoError(t, json.Unmarshal(tc.w.Body.Bytes(), &body))
+	assert.Equal(t, 2, body.Accepted)
+	require.Len(t, body.Errors, 1)
+	assert.Equal(t, "service ingestion quota exceeded until "+reset.Format(time.RFC3339), body.Errors[0].Message)
+
+	// Other rejections carry no Retry-After header.
+	tc = testcaseIntakeHandler{path: "errors.ndjson", reporter: beatertest.ErrorReporterFn(publish.ErrFull)}
+	tc.setup(t)
+	Handler(tc.processor, tc.reporter)(tc.c)
+	assert.Empty(t, tc.w.Header().Get(headers.RetryAfter))
+}
+
+func TestIntakeHandlerSamplingRate(t *testing.T) {
+	cfg := config.DefaultConfig("7.0.0")
+	cfg.Sampling.Rules = []config.SamplingRuleConfig{
+		{Service: "other"},
+		{Service: "1234_service-*", Environment: "staging", Rate: 0.25},
+	}
+	tc := testcaseIntakeHandler{path: "errors.ndjson", processor: stream.BackendProcessor(cfg)}
+	tc.setup(t)
+	Handler(tc.processor, tc.reporter)(tc.c)
+	assert.Equal(t, http.StatusAccepted, tc.w.Code)
+	assert.Equal(t, "0.25", tc.w.Header().Get(headers.ElasticAPMSamplingRate))
+
+	// No header is set if no rule matches the service.
+	cfg.Sampling.Rules = cfg.Sampling.Rules[:1]
+	tc = testcaseIntakeHandler{path: "errors.ndjson", processor: stream.BackendProcessor(cfg)}
+	tc.setup(t)
+	Handler(tc.processor, tc.reporter)(tc.c)
+	assert.Equal(t, http.StatusAccepted, tc.w.Code)
+	_, ok := tc.w.Header()[headers.ElasticAPMSamplingRate]
+	assert.False(t, ok)
+}
+
 type testcaseIntakeHandler struct {
 	c         *request.Context
 	w         *httptest.ResponseRecorder
@@ -0,0 +1,100 @@
+// Licensed to Elasticsearch B.V. under one or more contributor
+// license agreements. See the NOTICE file distributed with
+// this work for additional information regarding copyright
+// ownership. Elasticsearch B.V. licenses this file to you under
+// the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package api
+
+import (
+	"encoding/json"
+	"mime"
+	"net/http"
+
+	"github.com/pkg/errors"
+
+	"github.com/elastic/beats/v7/libbeat/logp"
+	"github.com/elastic/beats/v7/libbeat/monitoring"
+
+	"github.com/elastic/apm-server/beater/authorization"
+	"github.com/elastic/apm-server/beater/config"
+	"github.com/elastic/apm-server/beater/headers"
+	"github.com/elastic/apm-server/beater/middleware"
+	"github.com/elastic/apm-server/beater/request"
+	logs "github.com/elastic/apm-server/log"
+)
+
+var intakeControlMonitoringMap = request.DefaultMonitoringMapForRegistry(
+	monitoring.Default.NewRegistry("apm-server.intake_control"),
+)
+
+type intakeControlState struct {
+	Paused *bool `json:"paused"`
+}
+
+// intakeControlHandler reports, and on POST sets, whether event intake is paused.
+// While paused, intake requests are rejected with 503 and a Retry-After header so
+// agents buffer events locally; other endpoints are unaffected. Only requests from
+// localhost are served, and POST requests must have a JSON body.
+func intakeControlHandler(pause *middleware.Pause) request.Handler {
+	logger := logp.NewLogger(logs.Handler)
+	return func(c *request.Context) {
+		if !isLoopback(c.Request.RemoteAddr) {
+			c.Result.SetDefault(request.IDResponseErrorsForbidden)
+			c.Write()
+			return
+		}
+		switch c.Request.Method {
+		case http.MethodGet:
+		case http.MethodPost:
+			mediaType, _, _ := mime.ParseMediaType(c.Request.Header.Get(headers.ContentType))
+			if mediaType != "application/json" {
+				c.Result.SetWithError(request.IDResponseErrorsValidate,
+					errors.Errorf("invalid content type: %q, expected application/json", mediaType))
+				c.Write()
+				return
+			}
+			var state intakeControlState
+			if err := json.NewDecoder(c.Request.Body).Decode(&state); err != nil || state.Paused == nil {
+				c.Result.SetWithError(request.IDResponseErrorsValidate,
+					errors.New(`expected a JSON body of the form {"paused": true|false}`))
+				c.Write()
+				return
+			}
+			pause.Set(*state.Paused)
+			if *state.Paused {
+				logger.Warn("event intake paused")
+			} else {
+				logger.Info("event intake resumed")
+			}
+		default:
+			c.Header().Set("Allow", "GET, POST")
+			c.Result.SetDefault(request.IDResponseErrorsMethodNotAllowed)
+			c.Write()
+			return
+		}
+		paused := pause.Paused()
+		c.Result.SetDefault(request.IDResponseValidOK)
+		c.Result.Body = intakeControlState{Paused: &paused}
+		c.Write()
+	}
+}
+
+// intakeControlMiddleware returns the middleware for the intake control
+// endpoint: as pausing intake affects all agents, requests must be
+// authorized to write events.
+func intakeControlMiddleware(cfg *config.Config, auth *authorization.Handler) []middleware.Middleware {
+	return append(apmMiddleware(cfg, intakeControlMonitoringMap),
+		middleware.AuthorizationMiddleware(auth, true))
+}
@@ -20,22 +20,36 @@ package api
 import (
 	"net/http"
 	"regexp"
+	"strings"
+	"time"
 
 	"github.com/elastic/beats/v7/libbeat/monitoring"
 
 	"github.com/elastic/beats/v7/libbeat/logp"
+	"github.com/elastic/beats/v7/libbeat/version"
 
+	"github.com/elastic/apm-server/agentcfg"
+	"github.com/elastic/apm-server/agentstats"
 	"github.com/elastic/apm-server/beater/api/asset/sourcemap"
+	"github.com/elastic/apm-server/beater/api/buildinfo"
+	"github.com/elastic/apm-server/beater/api/capture"
 	"github.com/elastic/apm-server/beater/api/config/agent"
 	"github.com/elastic/apm-server/beater/api/intake"
+	"github.com/elastic/apm-server/beater/api/openapi"
 	"github.com/elastic/apm-server/beater/api/profile"
 	"github.com/elastic/apm-server/beater/api/root"
+	"github.com/elastic/apm-server/beater/api/spool"
+	"github.com/elastic/apm-server/beater/api/status"
+	"github.com/elastic/apm-server/beater/api/streamlimit"
 	"github.com/elastic/apm-server/beater/authorization"
 	"github.com/elastic/apm-server/beater/config"
 	"github.com/elastic/apm-server/beater/middleware"
 	"github.com/elastic/apm-server/beater/request"
+	"github.com/elastic/apm-server/dedup"
 	"github.com/elastic/apm-server/kibana"
+	"github.com/elastic/apm-server/labels"
 	logs "github.com/elastic/apm-server/log"
+	"github.com/elastic/apm-server/model/modeldecoder"
 	psourcemap "github.com/elastic/apm-server/processor/asset/sourcemap"
 	"github.com/elastic/apm-server/processor/stream"
 	"github.com/elastic/apm-server/publish"
@@ -56,6 +70,10 @@ const (
 	IntakePath = "/intake/v2/events"
 	// ProfilePath defines the path to ingest profiles
 	ProfilePath = "/intake/v2/profile"
+	// StatusPath defines the path to query when events were first received
+	StatusPath = "/v1/status"
+	// BuildInfoPath defines the path to query the server's build and schema versions
+	BuildInfoPath = "/v1/build"
 
 	// RUM routes
 
@@ -70,10 +88,23 @@ const (
 type route struct {
 	path      string
 	handlerFn func(*config.Config, *authorization.Builder, publish.Reporter) (request.Handler, error)
+	spec      func(*config.Config) openapi.PathItem
 }
 
 // NewMux registers apm handlers to paths building up the APM Server API.
-func NewMux(beaterConfig *config.Config, report publish.Reporter) (*http.ServeMux, error) {
+//
+// The status API reports on the events tracked by tracker, which is expected
+// to wrap report. If tracker is nil, the events reported by the handlers
+// are tracked.
+//
+// The intake APIs report the sampling rates returned by samplingRate, which
+// may change at runtime. If samplingRate is nil, the rates of the sampling
+// rules in beaterConfig are reported.
+//
+// If sanitizer is non-nil, the intake APIs sanitize the metadata labels of
+// each stream with it, once for all events in the stream. It is expected to
+// be the sanitizer wrapping report.
+func NewMux(beaterConfig *config.Config, report publish.Reporter, tracker *status.Tracker, samplingRate func(serviceName, environment string) (float64, bool), sanitizer *labels.Sanitizer) (*http.ServeMux, error) {
 	pool := request.NewContextPool()
 	mux := http.NewServeMux()
 	logger := logp.NewLogger(logs.Handler)
@@ -83,26 +114,55 @@ func NewMux(beaterConfig *config.Config, report publish.Reporter) (*http.ServeMu
 		return nil, err
 	}
 
-	routeMap := []route{
-		{RootPath, rootHandler},
-		{AssetSourcemapPath, sourcemapHandler},
-		{AgentConfigPath, backendAgentConfigHandler},
-		{AgentConfigRUMPath, rumAgentConfigHandler},
-		{IntakeRUMPath, rumIntakeHandler},
-		{IntakeRUMV3Path, rumV3IntakeHandler},
-		{IntakePath, backendIntakeHandler},
+	// Events rejected while the output is unavailable may be spooled
+	// to disk, and backfilled through the backfill endpoint.
+	var spooler *spool.Spooler
+	if beaterConfig.Spool.Enabled {
+		if spooler, err = spool.New(beaterConfig.Spool, report); err != nil {
+			return nil, err
+		}
+		report = spooler.Report
 	}
 
-	// Profiling is currently experimental, and intended for profiling the
-	// server itself, so we only add the route if self-profiling is enabled.
-	if beaterConfig.SelfInstrumentation.IsEnabled() {
-		if beaterConfig.SelfInstrumentation.Profiling.CPU.IsEnabled() ||
-			beaterConfig.SelfInstrumentation.Profiling.Heap.IsEnabled() {
-			routeMap = append(routeMap, route{ProfilePath, profileHandler})
+	if tracker == nil {
+		tracker = status.NewTracker(status.DefaultMaxServices)
+		report = tracker.Wrap(report)
+	}
+
+	if cfg := beaterConfig.Dedup; cfg.Enabled {
+		// Drop events resent by retrying agents before they are
+		// reported, so they do not affect aggregations either.
+		report = dedup.NewDeduplicator(dedup.Config{
+			TTL:      cfg.TTL,
+			MaxBytes: cfg.MaxBytes,
+		}).Wrap(report)
+	}
+
+	// Intake may be paused through the intake control endpoint, e.g.
+	// during Elasticsearch maintenance, so agents buffer events locally.
+	pause := &middleware.Pause{}
+
+	// A sample of intake request bodies may be captured to disk,
+	// for investigating misbehaving agents.
+	var capturer *capture.Capturer
+	if beaterConfig.Capture.Enabled {
+		if capturer, err = capture.New(beaterConfig.Capture); err != nil {
+			return nil, err
 		}
 	}
 
-	for _, route := range routeMap {
+	// Events rejected by validation may be logged as a periodic summary,
+	// shared by all intake handlers, rather than per request.
+	var summary *stream.ValidationSummary
+	if beaterConfig.ValidationSummary.Enabled {
+		summary = stream.NewValidationSummary(beaterConfig.ValidationSummary.Interval)
+	}
+
+	// Concurrent streams and the throughput of request bodies may be
+	// limited per client, shared by all intake handlers.
+	limits := streamlimit.New(beaterConfig.StreamLimits)
+
+	for _, route := range routes(beaterConfig, tracker, pause, capturer, summary, limits, samplingRate, sanitizer) {
 		h, err := route.handlerFn(beaterConfig, auth, report)
 		if err != nil {
 			return nil, err
@@ -116,37 +176,179 @@ func NewMux(beaterConfig *config.Config, report publish.Reporter) (*http.ServeMu
 		logger.Infof("Path %s added to request handler", path)
 		mux.Handle(path, http.HandlerFunc(debugVarsHandler))
 	}
+	if beaterConfig.ConfigEndpoint.IsEnabled() {
+		h, err := debugConfigHandler(beaterConfig)
+		if err != nil {
+			return nil, err
+		}
+		path := beaterConfig.ConfigEndpoint.URL
+		logger.Infof("Path %s added to request handler", path)
+		mux.Handle(path, h)
+	}
+	if beaterConfig.OpenAPI.IsEnabled() {
+		h, err := openAPIHandler(beaterConfig)
+		if err != nil {
+			return nil, err
+		}
+		path := beaterConfig.OpenAPI.URL
+		logger.Infof("Path %s added to request handler", path)
+		mux.Handle(path, h)
+	}
+	if beaterConfig.IntakeControl.IsEnabled() {
+		path := beaterConfig.IntakeControl.URL
+		logger.Infof("Path %s added to request handler", path)
+		h, err := middleware.Wrap(intakeControlHandler(pause), intakeControlMiddleware(
+			beaterConfig, auth.ForPrivilege(authorization.PrivilegeEventWrite.Action))...)
+		if err != nil {
+			return nil, err
+		}
+		mux.Handle(path, pool.HTTPHandler(h))
+	}
+	if spooler != nil {
+		path := beaterConfig.Spool.URL
+		logger.Infof("Path %s added to request handler", path)
+		h, err := middleware.Wrap(backfillHandler(spooler), backfillMiddleware(
+			beaterConfig, auth.ForPrivilege(authorization.PrivilegeEventWrite.Action))...)
+		if err != nil {
+			return nil, err
+		}
+		mux.Handle(path, pool.HTTPHandler(h))
+	}
+	if beaterConfig.RumConfig.IsEnabled() && beaterConfig.RumConfig.AgentBundle.IsEnabled() {
+		path := strings.TrimSuffix(beaterConfig.RumConfig.AgentBundle.URL, "/") + "/"
+		logger.Infof("Path %s added to request handler", path)
+		mux.Handle(path, rumAgentBundleHandler(beaterConfig.RumConfig.AgentBundle))
+	}
+	if beaterConfig.AgentStats.IsEnabled() {
+		path := beaterConfig.AgentStats.URL
+		logger.Infof("Path %s added to request handler", path)
+		mux.Handle(path, agentStatsHandler(agentstats.Default))
+	}
 	return mux, nil
 }
 
-func profileHandler(cfg *config.Config, builder *authorization.Builder, reporter publish.Reporter) (request.Handler, error) {
-	h := profile.Handler(transform.Config{}, reporter)
-	authHandler := builder.ForPrivilege(authorization.PrivilegeEventWrite.Action)
-	return middleware.Wrap(h, backendMiddleware(cfg, authHandler, profile.MonitoringMap)...)
+// routes returns the APM Server API routes registered for the given config.
+// The status tracker and pause may be nil if the route handlers will not be used,
+// the capturer is nil unless request capturing is enabled, the validation
+// summary is nil unless summarizing validation errors is enabled, the
+// stream limits are nil unless any are configured, samplingRate is nil if the
+// sampling rules in the config apply, and the sanitizer is nil if metadata
+// labels are not sanitized per stream.
+func routes(beaterConfig *config.Config, tracker *status.Tracker, pause *middleware.Pause, capturer *capture.Capturer, summary *stream.ValidationSummary, limits *streamlimit.Limits, samplingRate func(serviceName, environment string) (float64, bool), sanitizer *labels.Sanitizer) []route {
+	routeMap := []route{
+		{RootPath, rootHandler, rootSpec},
+		{StatusPath, statusHandler(tracker), statusSpec},
+		{BuildInfoPath, buildInfoHandler, buildInfoSpec},
+		{AssetSourcemapPath, sourcemapHandler, sourcemapSpec},
+		{AgentConfigPath, backendAgentConfigHandler, backendAgentConfigSpec},
+		{AgentConfigRUMPath, rumAgentConfigHandler, rumAgentConfigSpec},
+		{IntakeRUMPath, rumIntakeHandler(pause, capturer, summary, limits, samplingRate, sanitizer), rumIntakeSpec},
+		{IntakeRUMV3Path, rumV3IntakeHandler(pause, capturer, summary, limits, samplingRate, sanitizer), rumV3IntakeSpec},
+		{IntakePath, backendIntakeHandler(pause, capturer, summary, limits, samplingRate, sanitizer), backendIntakeSpec},
+	}
+
+	// Profiling is currently experimental, and intended for profiling the
+	// server itself, so we only add the route if self-profiling is enabled,
+	// or if the profiles feature flag is enabled.
+	if beaterConfig.FeatureFlags.Profiles || selfProfilingEnabled(beaterConfig) {
+		routeMap = append(routeMap, route{ProfilePath, profileHandler(pause), profileSpec})
+	}
+	return routeMap
+}
+
+func selfProfilingEnabled(cfg *config.Config) bool {
+	return cfg.SelfInstrumentation.IsEnabled() &&
+		(cfg.SelfInstrumentation.Profiling.CPU.IsEnabled() || cfg.SelfInstrumentation.Profiling.Heap.IsEnabled())
 }
 
-func backendIntakeHandler(cfg *config.Config, builder *authorization.Builder, reporter publish.Reporter) (request.Handler, error) {
-	h := intake.Handler(stream.BackendProcessor(cfg), reporter)
-	authHandler := builder.ForPrivilege(authorization.PrivilegeEventWrite.Action)
-	return middleware.Wrap(h, backendMiddleware(cfg, authHandler, intake.MonitoringMap)...)
+func statusHandler(tracker *status.Tracker) func(*config.Config, *authorization.Builder, publish.Reporter) (request.Handler, error) {
+	return func(cfg *config.Config, builder *authorization.Builder, _ publish.Reporter) (request.Handler, error) {
+		authHandler := builder.ForAnyOfPrivileges(authorization.ActionAny)
+		return middleware.Wrap(status.Handler(tracker), statusMiddleware(cfg, authHandler)...)
+	}
 }
 
-func rumIntakeHandler(cfg *config.Config, _ *authorization.Builder, reporter publish.Reporter) (request.Handler, error) {
-	tcfg, err := rumTransformConfig(cfg)
-	if err != nil {
-		return nil, err
+func buildInfoHandler(cfg *config.Config, builder *authorization.Builder, _ publish.Reporter) (request.Handler, error) {
+	h := buildinfo.Handler(buildinfo.Info{
+		Version:      version.GetDefaultVersion(),
+		BuildSHA:     version.Commit(),
+		BuildDate:    version.BuildTime().Format(time.RFC3339),
+		Features:     cfg.Features(),
+		FeatureFlags: cfg.FeatureFlags.Flags(),
+		Schemas: buildinfo.Schemas{
+			Versions: modeldecoder.SchemaVersions(),
+			Digests:  modeldecoder.SchemaDigests(),
+		},
+	})
+	authHandler := builder.ForAnyOfPrivileges(authorization.ActionAny)
+	return middleware.Wrap(h, append(apmMiddleware(cfg, buildinfo.MonitoringMap),
+		middleware.AuthorizationMiddleware(authHandler, true))...)
+}
+
+func profileHandler(pause *middleware.Pause) func(*config.Config, *authorization.Builder, publish.Reporter) (request.Handler, error) {
+	return func(cfg *config.Config, builder *authorization.Builder, reporter publish.Reporter) (request.Handler, error) {
+		h := profile.Handler(transform.Config{}, reporter)
+		authHandler := builder.ForPrivilege(authorization.PrivilegeEventWrite.Action)
+		return middleware.Wrap(h, append(backendMiddleware(cfg, authHandler, profile.MonitoringMap),
+			middleware.ReadOnlyMiddleware(cfg.ReadOnly), pauseMiddleware(cfg, pause))...)
 	}
-	h := intake.Handler(stream.RUMProcessor(cfg, tcfg), reporter)
-	return middleware.Wrap(h, rumMiddleware(cfg, nil, intake.MonitoringMap)...)
 }
 
-func rumV3IntakeHandler(cfg *config.Config, _ *authorization.Builder, reporter publish.Reporter) (request.Handler, error) {
-	tcfg, err := rumTransformConfig(cfg)
-	if err != nil {
-		return nil, err
+func backendIntakeHandler(pause *middleware.Pause, capturer *capture.Capturer, summary *stream.ValidationSummary, limits *streamlimit.Limits, samplingRate func(serviceName, environment string) (float64, bool), sanitizer *labels.Sanitizer) func(*config.Config, *authorization.Builder, publish.Reporter) (request.Handler, error) {
+	return func(cfg *config.Config, builder *authorization.Builder, reporter publish.Reporter) (request.Handler, error) {
+		p := stream.BackendProcessor(cfg)
+		p.ValidationSummary = summary
+		if samplingRate != nil {
+			p.SamplingRate = samplingRate
+		}
+		if sanitizer != nil {
+			p.SanitizeMetadata = sanitizer.SanitizeMetadata
+		}
+		h := intake.Handler(p, reporter)
+		authHandler := builder.ForPrivilege(authorization.PrivilegeEventWrite.Action)
+		return middleware.Wrap(h, append(backendMiddleware(cfg, authHandler, intake.MonitoringMap),
+			intakeMiddleware(cfg, pause, capturer, limits)...)...)
+	}
+}
+
+func rumIntakeHandler(pause *middleware.Pause, capturer *capture.Capturer, summary *stream.ValidationSummary, limits *streamlimit.Limits, samplingRate func(serviceName, environment string) (float64, bool), sanitizer *labels.Sanitizer) func(*config.Config, *authorization.Builder, publish.Reporter) (request.Handler, error) {
+	return func(cfg *config.Config, _ *authorization.Builder, reporter publish.Reporter) (request.Handler, error) {
+		tcfg, err := rumTransformConfig(cfg)
+		if err != nil {
+			return nil, err
+		}
+		p := stream.RUMProcessor(cfg, tcfg)
+		p.ValidationSummary = summary
+		if samplingRate != nil {
+			p.SamplingRate = samplingRate
+		}
+		if sanitizer != nil {
+			p.SanitizeMetadata = sanitizer.SanitizeMetadata
+		}
+		h := intake.Handler(p, reporter)
+		return middleware.Wrap(h, append(rumMiddleware(cfg, nil, intake.MonitoringMap),
+			intakeMiddleware(cfg, pause, capturer, limits)...)...)
+	}
+}
+
+func rumV3IntakeHandler(pause *middleware.Pause, capturer *capture.Capturer, summary *stream.ValidationSummary, limits *streamlimit.Limits, samplingRate func(serviceName, environment string) (float64, bool), sanitizer *labels.Sanitizer) func(*config.Config, *authorization.Builder, publish.Reporter) (request.Handler, error) {
+	return func(cfg *config.Config, _ *authorization.Builder, reporter publish.Reporter) (request.Handler, error) {
+		tcfg, err := rumTransformConfig(cfg)
+		if err != nil {
+			return nil, err
+		}
+		p := stream.RUMV3Processor(cfg, tcfg)
+		p.ValidationSummary = summary
+		if samplingRate != nil {
+			p.SamplingRate = samplingRate
+		}
+		if sanitizer != nil {
+			p.SanitizeMetadata = sanitizer.SanitizeMetadata
+		}
+		h := intake.Handler(p, reporter)
+		return middleware.Wrap(h, append(rumMiddleware(cfg, nil, intake.MonitoringMap),
+			intakeMiddleware(cfg, pause, capturer, limits)...)...)
 	}
-	h := intake.Handler(stream.RUMV3Processor(cfg, tcfg), reporter)
-	return middleware.Wrap(h, rumMiddleware(cfg, nil, intake.MonitoringMap)...)
 }
 
 func sourcemapHandler(cfg *config.Config, builder *authorization.Builder, reporter publish.Reporter) (request.Handler, error) {
@@ -154,28 +356,35 @@ func sourcemapHandler(cfg *config.Config, builder *authorization.Builder, report
 	if err != nil {
 		return nil, err
 	}
-	h := sourcemap.Handler(sourcemap.DecodeSourcemapFormData, psourcemap.Processor, *tcfg, reporter)
+	h := sourcemap.Handler(sourcemap.FormDataDecoder(cfg.MaxDecompressedBodySize), psourcemap.Processor, *tcfg, reporter)
+	if tcfg.SourcemapStore != nil {
+		h = sourcemap.ManagementHandler(tcfg.SourcemapStore, h)
+	}
 	authHandler := builder.ForPrivilege(authorization.PrivilegeSourcemapWrite.Action)
 	return middleware.Wrap(h, sourcemapMiddleware(cfg, authHandler)...)
 }
 
 func backendAgentConfigHandler(cfg *config.Config, builder *authorization.Builder, _ publish.Reporter) (request.Handler, error) {
 	authHandler := builder.ForPrivilege(authorization.PrivilegeAgentConfigRead.Action)
-	return agentConfigHandler(cfg, authHandler, backendMiddleware)
+	return agentConfigHandler(cfg, authHandler, backendMiddleware, nil)
 }
 
 func rumAgentConfigHandler(cfg *config.Config, _ *authorization.Builder, _ publish.Reporter) (request.Handler, error) {
-	return agentConfigHandler(cfg, nil, rumMiddleware)
+	var defaults agentcfg.Settings
+	if strategy := cfg.RumConfig.TraceContinuationStrategy; strategy != "" {
+		defaults = agentcfg.Settings{agentcfg.TraceContinuationStrategy: strategy}
+	}
+	return agentConfigHandler(cfg, nil, rumMiddleware, defaults)
 }
 
 type middlewareFunc func(*config.Config, *authorization.Handler, map[request.ResultID]*monitoring.Int) []middleware.Middleware
 
-func agentConfigHandler(cfg *config.Config, authHandler *authorization.Handler, middlewareFunc middlewareFunc) (request.Handler, error) {
+func agentConfigHandler(cfg *config.Config, authHandler *authorization.Handler, middlewareFunc middlewareFunc, defaults agentcfg.Settings) (request.Handler, error) {
 	var client kibana.Client
 	if cfg.Kibana.Enabled {
 		client = kibana.NewConnectingClient(&cfg.Kibana.ClientConfig)
 	}
-	h := agent.Handler(client, cfg.AgentConfig)
+	h := agent.Handler(client, cfg.AgentConfig, defaults)
 	msg := "Agent remote configuration is disabled. " +
 		"Configure the `apm-server.kibana` section in apm-server.yml to enable it. " +
 		"If you are using a RUM agent, you also need to configure the `apm-server.rum` section. " +
@@ -185,13 +394,17 @@ func agentConfigHandler(cfg *config.Config, authHandler *authorization.Handler,
 }
 
 func rootHandler(cfg *config.Config, builder *authorization.Builder, _ publish.Reporter) (request.Handler, error) {
-	return middleware.Wrap(root.Handler(),
+	h := root.Handler(root.HandlerConfig{
+		Features:     cfg.FeatureFlags.Flags(),
+		Capabilities: capabilities(cfg),
+	})
+	return middleware.Wrap(h,
 		rootMiddleware(cfg, builder.ForAnyOfPrivileges(authorization.ActionAny))...)
 }
 
-func apmMiddleware(m map[request.ResultID]*monitoring.Int) []middleware.Middleware {
+func apmMiddleware(cfg *config.Config, m map[request.ResultID]*monitoring.Int) []middleware.Middleware {
 	return []middleware.Middleware{
-		middleware.LogMiddleware(),
+		middleware.LogMiddleware(cfg.RepeatedErrors.SuppressFor()),
 		middleware.RecoverPanicMiddleware(),
 		middleware.MonitoringMiddleware(m),
 		middleware.RequestTimeMiddleware(),
@@ -199,7 +412,10 @@ func apmMiddleware(m map[request.ResultID]*monitoring.Int) []middleware.Middlewa
 }
 
 func backendMiddleware(cfg *config.Config, auth *authorization.Handler, m map[request.ResultID]*monitoring.Int) []middleware.Middleware {
-	backendMiddleware := append(apmMiddleware(m),
+	backendMiddleware := append(apmMiddleware(cfg, m),
+		// once the write timeout has passed the response can no longer be sent,
+		// so there is no point in continuing to process the request
+		middleware.TimeoutMiddleware(cfg.WriteTimeout),
 		middleware.AuthorizationMiddleware(auth, true),
 	)
 	if cfg.AugmentEnabled {
@@ -212,7 +428,8 @@ func rumMiddleware(cfg *config.Config, _ *authorization.Handler, m map[request.R
 	msg := "RUM endpoint is disabled. " +
 		"Configure the `apm-server.rum` section in apm-server.yml to enable ingestion of RUM events. " +
 		"If you are not using the RUM agent, you can safely ignore this error."
-	rumMiddleware := append(apmMiddleware(m),
+	rumMiddleware := append(apmMiddleware(cfg, m),
+		middleware.TimeoutMiddleware(cfg.WriteTimeout),
 		middleware.ResponseHeadersMiddleware(cfg.RumConfig.ResponseHeaders),
 		middleware.SetRumFlagMiddleware(),
 		middleware.SetIPRateLimitMiddleware(cfg.RumConfig.EventRate),
@@ -231,14 +448,43 @@ func sourcemapMiddleware(cfg *config.Config, auth *authorization.Handler) []midd
 		"If you are not using the RUM agent, you can safely ignore this error."
 	enabled := cfg.RumConfig.IsEnabled() && cfg.RumConfig.SourceMapping.IsEnabled()
 	return append(backendMiddleware(cfg, auth, sourcemap.MonitoringMap),
-		middleware.KillSwitchMiddleware(enabled, msg))
+		middleware.KillSwitchMiddleware(enabled, msg),
+		middleware.ReadOnlyMiddleware(cfg.ReadOnly))
 }
 
-func rootMiddleware(_ *config.Config, auth *authorization.Handler) []middleware.Middleware {
-	return append(apmMiddleware(root.MonitoringMap),
+func pauseMiddleware(cfg *config.Config, pause *middleware.Pause) middleware.Middleware {
+	var retryAfter time.Duration
+	if cfg.IntakeControl != nil {
+		retryAfter = cfg.IntakeControl.RetryAfter
+	}
+	return middleware.PauseMiddleware(pause, retryAfter)
+}
+
+// intakeMiddleware returns the middleware specific to event intake routes.
+func intakeMiddleware(cfg *config.Config, pause *middleware.Pause, capturer *capture.Capturer, limits *streamlimit.Limits) []middleware.Middleware {
+	intakeMiddleware := []middleware.Middleware{
+		middleware.ReadOnlyMiddleware(cfg.ReadOnly),
+		pauseMiddleware(cfg, pause),
+	}
+	if limits != nil {
+		intakeMiddleware = append(intakeMiddleware, middleware.StreamLimitMiddleware(limits))
+	}
+	if capturer != nil {
+		intakeMiddleware = append(intakeMiddleware, middleware.CaptureMiddleware(capturer))
+	}
+	return intakeMiddleware
+}
+
+func rootMiddleware(cfg *config.Config, auth *authorization.Handler) []middleware.Middleware {
+	return append(apmMiddleware(cfg, root.MonitoringMap),
 		middleware.AuthorizationMiddleware(auth, false))
 }
 
+func statusMiddleware(cfg *config.Config, auth *authorization.Handler) []middleware.Middleware {
+	return append(apmMiddleware(cfg, status.MonitoringMap),
+		middleware.AuthorizationMiddleware(auth, true))
+}
+
 func rumTransformConfig(beaterConfig *config.Config) (*transform.Config, error) {
 	store, err := beaterConfig.RumConfig.MemoizedSourcemapStore()
 	if err != nil {
@@ -248,5 +494,6 @@ func rumTransformConfig(beaterConfig *config.Config) (*transform.Config, error)
 		SourcemapStore:      store,
 		LibraryPattern:      regexp.MustCompile(beaterConfig.RumConfig.LibraryPattern),
 		ExcludeFromGrouping: regexp.MustCompile(beaterConfig.RumConfig.ExcludeFromGrouping),
+		GroupingKeyTemplate: beaterConfig.ErrorGrouping.Template(),
 	}, nil
 }
@@ -0,0 +1,73 @@
+// Licensed to Elasticsearch B.V. under one or more contributor
+// license agreements. See the NOTICE file distributed with
+// this work for additional information regarding copyright
+// ownership. Elasticsearch B.V. licenses this file to you under
+// the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package api
+
+import (
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/elastic/apm-server/beater/beatertest"
+	"github.com/elastic/apm-server/beater/config"
+)
+
+func TestBackfillEndpoint(t *testing.T) {
+	dir, err := ioutil.TempDir("", "apm-server-spool")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+
+	cfg := config.DefaultConfig(beatertest.MockBeatVersion())
+	cfg.Spool.Enabled = true
+	cfg.Spool.Path = dir
+	mux, err := NewMux(cfg, beatertest.NilReporter, nil, nil, nil)
+	require.NoError(t, err)
+
+	do := func(method, remoteAddr string) *httptest.ResponseRecorder {
+		r := httptest.NewRequest(method, "/admin/backfill", nil)
+		r.RemoteAddr = remoteAddr
+		rec := httptest.NewRecorder()
+		mux.ServeHTTP(rec, r)
+		return rec
+	}
+	const localhost = "127.0.0.1:12345"
+
+	rec := do(http.MethodGet, localhost)
+	require.Equal(t, http.StatusOK, rec.Code)
+	assert.JSONEq(t, `{"running":false,"files":0,"files_done":0,"events":0,"spooled_bytes":0}`, rec.Body.String())
+
+	rec = do(http.MethodPost, localhost)
+	assert.Equal(t, http.StatusAccepted, rec.Code)
+	assert.Contains(t, rec.Body.String(), `"started"`)
+
+	assert.Equal(t, http.StatusMethodNotAllowed, do(http.MethodDelete, localhost).Code)
+	assert.Equal(t, http.StatusForbidden, do(http.MethodPost, "192.0.2.1:12345").Code)
+
+	t.Run("Disabled", func(t *testing.T) {
+		cfg := config.DefaultConfig(beatertest.MockBeatVersion())
+		r := httptest.NewRequest(http.MethodGet, "/admin/backfill", nil)
+		r.RemoteAddr = localhost
+		rec, err := requestToMuxer(cfg, r)
+		require.NoError(t, err)
+		assert.NotEqual(t, http.StatusOK, rec.Code)
+	})
+}
@@ -0,0 +1,74 @@
+// Licensed to Elasticsearch B.V. under one or more contributor
+// license agreements. See the NOTICE file distributed with
+// this work for additional information regarding copyright
+// ownership. Elasticsearch B.V. licenses this file to you under
+// the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package api
+
+import (
+	"encoding/json"
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/elastic/apm-server/beater/api/buildinfo"
+	"github.com/elastic/apm-server/beater/beatertest"
+	"github.com/elastic/apm-server/beater/config"
+	"github.com/elastic/apm-server/beater/headers"
+	"github.com/elastic/apm-server/beater/request"
+	"github.com/elastic/apm-server/model/modeldecoder"
+)
+
+func TestBuildInfoHandler_AuthorizationMiddleware(t *testing.T) {
+	cfg := config.DefaultConfig(beatertest.MockBeatVersion())
+	cfg.SecretToken = "1234"
+
+	t.Run("Unauthorized", func(t *testing.T) {
+		rec, err := requestToMuxerWithHeader(cfg, BuildInfoPath, http.MethodGet, nil)
+		require.NoError(t, err)
+		assert.Equal(t, http.StatusUnauthorized, rec.Code)
+	})
+
+	t.Run("Authorized", func(t *testing.T) {
+		h := map[string]string{headers.Authorization: "Bearer 1234"}
+		rec, err := requestToMuxerWithHeader(cfg, BuildInfoPath, http.MethodGet, h)
+		require.NoError(t, err)
+		assert.Equal(t, http.StatusOK, rec.Code)
+
+		var info buildinfo.Info
+		require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &info))
+		assert.NotEmpty(t, info.Version)
+		assert.Equal(t, cfg.Features(), info.Features)
+		assert.Equal(t, cfg.FeatureFlags.Flags(), info.FeatureFlags)
+		assert.Equal(t, modeldecoder.SchemaVersions(), info.Schemas.Versions)
+		assert.Equal(t, modeldecoder.SchemaDigests(), info.Schemas.Digests)
+	})
+}
+
+func TestBuildInfoHandler_MonitoringMiddleware(t *testing.T) {
+	h := testHandler(t, buildInfoHandler)
+	c, _ := beatertest.ContextWithResponseRecorder(http.MethodGet, BuildInfoPath)
+
+	expected := map[request.ResultID]int{
+		request.IDRequestCount:       1,
+		request.IDResponseCount:      1,
+		request.IDResponseValidCount: 1,
+		request.IDResponseValidOK:    1}
+
+	equal, result := beatertest.CompareMonitoringInt(h, c, expected, buildinfo.MonitoringMap)
+	assert.True(t, equal, result)
+}
@@ -19,7 +19,6 @@ package api
 
 import (
 	"net/http"
-	"net/http/httptest"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
@@ -75,7 +74,7 @@ func TestConfigAgentHandler_PanicMiddleware(t *testing.T) {
 	h := testHandler(t, backendAgentConfigHandler)
 	rec := &beatertest.WriterPanicOnce{}
 	c := request.NewContext()
-	c.Reset(rec, httptest.NewRequest(http.MethodGet, "/", nil))
+	c.Reset(rec, newTestRequest(http.MethodGet, "/"))
 	h(c)
 	require.Equal(t, http.StatusInternalServerError, rec.StatusCode)
 	approvals.AssertApproveResult(t, approvalPathConfigAgent(t.Name()), rec.Body.Bytes())
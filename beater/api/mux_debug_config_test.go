@@ -0,0 +1,75 @@
+// Licensed to Elasticsearch B.V. under one or more contributor
+// license agreements. See the NOTICE file distributed with
+// this work for additional information regarding copyright
+// ownership. Elasticsearch B.V. licenses this file to you under
+// the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package api
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/elastic/apm-server/beater/beatertest"
+	"github.com/elastic/apm-server/beater/config"
+)
+
+func TestConfigEndpoint(t *testing.T) {
+	cfg := config.DefaultConfig(beatertest.MockBeatVersion())
+	cfg.SecretToken = "abc123"
+	enabled := true
+	cfg.ConfigEndpoint.Enabled = &enabled
+
+	t.Run("Localhost", func(t *testing.T) {
+		r := httptest.NewRequest(http.MethodGet, "/debug/config", nil)
+		r.RemoteAddr = "127.0.0.1:12345"
+		rec, err := requestToMuxer(cfg, r)
+		require.NoError(t, err)
+		require.Equal(t, http.StatusOK, rec.Code)
+		assert.Equal(t, "application/json; charset=utf-8", rec.Header().Get("Content-Type"))
+
+		var body struct {
+			Config   map[string]interface{} `json:"config"`
+			Features map[string]bool        `json:"features"`
+		}
+		require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &body))
+		assert.Equal(t, "xxxxx", body.Config["secret_token"])
+		assert.Equal(t, "production", body.Config["mode"])
+		assert.Equal(t, false, body.Features["rum"])
+		assert.Equal(t, true, body.Features["capture_personal_data"])
+	})
+
+	t.Run("Remote", func(t *testing.T) {
+		r := httptest.NewRequest(http.MethodGet, "/debug/config", nil)
+		r.RemoteAddr = "192.0.2.1:12345"
+		rec, err := requestToMuxer(cfg, r)
+		require.NoError(t, err)
+		assert.Equal(t, http.StatusForbidden, rec.Code)
+		assert.NotContains(t, rec.Body.String(), "abc123")
+	})
+
+	t.Run("Disabled", func(t *testing.T) {
+		cfg := config.DefaultConfig(beatertest.MockBeatVersion())
+		r := httptest.NewRequest(http.MethodGet, "/debug/config", nil)
+		r.RemoteAddr = "127.0.0.1:12345"
+		rec, err := requestToMuxer(cfg, r)
+		require.NoError(t, err)
+		assert.NotEqual(t, http.StatusOK, rec.Code)
+	})
+}
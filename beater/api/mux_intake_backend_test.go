@@ -20,6 +20,7 @@ package api
 import (
 	"net/http"
 	"net/http/httptest"
+	"os"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
@@ -29,6 +30,7 @@ import (
 	"github.com/elastic/apm-server/beater/beatertest"
 	"github.com/elastic/apm-server/beater/config"
 	"github.com/elastic/apm-server/beater/headers"
+	"github.com/elastic/apm-server/beater/middleware"
 	"github.com/elastic/apm-server/beater/request"
 	"github.com/elastic/apm-server/tests/approvals"
 )
@@ -57,17 +59,17 @@ func TestIntakeBackendHandler_AuthorizationMiddleware(t *testing.T) {
 }
 
 func TestIntakeBackendHandler_PanicMiddleware(t *testing.T) {
-	h := testHandler(t, backendIntakeHandler)
+	h := testHandler(t, backendIntakeHandler(&middleware.Pause{}, nil, nil, nil, nil, nil))
 	rec := &beatertest.WriterPanicOnce{}
 	c := request.NewContext()
-	c.Reset(rec, httptest.NewRequest(http.MethodGet, "/", nil))
+	c.Reset(rec, newTestRequest(http.MethodGet, "/"))
 	h(c)
 	assert.Equal(t, http.StatusInternalServerError, rec.StatusCode)
 	approvals.AssertApproveResult(t, approvalPathIntakeBackend(t.Name()), rec.Body.Bytes())
 }
 
 func TestIntakeBackendHandler_MonitoringMiddleware(t *testing.T) {
-	h := testHandler(t, backendIntakeHandler)
+	h := testHandler(t, backendIntakeHandler(&middleware.Pause{}, nil, nil, nil, nil, nil))
 	c, _ := beatertest.ContextWithResponseRecorder(http.MethodGet, "/")
 	// send GET request resulting in 405 MethodNotAllowed error
 	expected := map[request.ResultID]int{
@@ -80,6 +82,29 @@ func TestIntakeBackendHandler_MonitoringMiddleware(t *testing.T) {
 	assert.True(t, equal, result)
 }
 
+func TestIntakeBackendHandler_SamplingRate(t *testing.T) {
+	// The sampling rate may change between requests,
+	// e.g. when the sampling policies file is reloaded.
+	rate := 0.5
+	h := testHandler(t, backendIntakeHandler(&middleware.Pause{}, nil, nil, nil, func(serviceName, environment string) (float64, bool) {
+		return rate, true
+	}, nil))
+	for _, expected := range []string{"0.5", "0.25"} {
+		f, err := os.Open("../../testdata/intake-v2/only-metadata.ndjson")
+		require.NoError(t, err)
+		r := httptest.NewRequest(http.MethodPost, "/", f)
+		r.Header.Set(headers.ContentType, "application/x-ndjson")
+		w := httptest.NewRecorder()
+		c := request.NewContext()
+		c.Reset(w, r)
+		h(c)
+		f.Close()
+		assert.Equal(t, http.StatusAccepted, w.Code)
+		assert.Equal(t, expected, w.Header().Get(headers.ElasticAPMSamplingRate))
+		rate = 0.25
+	}
+}
+
 func approvalPathIntakeBackend(f string) string {
 	return "intake/test_approved/integration/backend/" + f
 }
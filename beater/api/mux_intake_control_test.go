@@ -0,0 +1,151 @@
+// Licensed to Elasticsearch B.V. under one or more contributor
+// license agreements. See the NOTICE file distributed with
+// this work for additional information regarding copyright
+// ownership. Elasticsearch B.V. licenses this file to you under
+// the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package api
+
+import (
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/elastic/apm-server/beater/beatertest"
+	"github.com/elastic/apm-server/beater/config"
+	"github.com/elastic/apm-server/beater/headers"
+)
+
+func TestIntakeControlEndpoint(t *testing.T) {
+	cfg := config.DefaultConfig(beatertest.MockBeatVersion())
+	enabled := true
+	cfg.IntakeControl.Enabled = &enabled
+	mux, err := NewMux(cfg, beatertest.NilReporter, nil, nil, nil)
+	require.NoError(t, err)
+
+	do := func(method, path, body, remoteAddr string) *httptest.ResponseRecorder {
+		r := httptest.NewRequest(method, path, strings.NewReader(body))
+		r.RemoteAddr = remoteAddr
+		if body != "" {
+			r.Header.Set(headers.ContentType, "application/json")
+		}
+		rec := httptest.NewRecorder()
+		mux.ServeHTTP(rec, r)
+		return rec
+	}
+	const localhost = "127.0.0.1:12345"
+
+	rec := do(http.MethodGet, "/admin/intake", "", localhost)
+	require.Equal(t, http.StatusOK, rec.Code)
+	assert.JSONEq(t, `{"paused":false}`, rec.Body.String())
+
+	rec = do(http.MethodPost, "/admin/intake", `{"paused":true}`, localhost)
+	require.Equal(t, http.StatusOK, rec.Code)
+	assert.JSONEq(t, `{"paused":true}`, rec.Body.String())
+
+	rec = do(http.MethodPost, IntakePath, "", localhost)
+	assert.Equal(t, http.StatusServiceUnavailable, rec.Code)
+	assert.Equal(t, "30", rec.Header().Get("Retry-After"))
+
+	// Health checks are unaffected by pausing intake.
+	rec = do(http.MethodGet, RootPath, "", localhost)
+	assert.Equal(t, http.StatusOK, rec.Code)
+
+	rec = do(http.MethodPost, "/admin/intake", `{"paused":false}`, localhost)
+	require.Equal(t, http.StatusOK, rec.Code)
+	rec = do(http.MethodPost, IntakePath, "", localhost)
+	assert.NotEqual(t, http.StatusServiceUnavailable, rec.Code)
+
+	t.Run("Invalid", func(t *testing.T) {
+		rec := do(http.MethodPost, "/admin/intake", `{}`, localhost)
+		assert.Equal(t, http.StatusBadRequest, rec.Code)
+		rec = do(http.MethodDelete, "/admin/intake", "", localhost)
+		assert.Equal(t, http.StatusMethodNotAllowed, rec.Code)
+
+		r := httptest.NewRequest(http.MethodPost, "/admin/intake", strings.NewReader(`{"paused":true}`))
+		r.RemoteAddr = localhost
+		r.Header.Set(headers.ContentType, "text/plain")
+		rec = httptest.NewRecorder()
+		mux.ServeHTTP(rec, r)
+		assert.Equal(t, http.StatusBadRequest, rec.Code)
+		assert.Contains(t, rec.Body.String(), "application/json")
+	})
+
+	t.Run("Remote", func(t *testing.T) {
+		rec := do(http.MethodPost, "/admin/intake", `{"paused":true}`, "192.0.2.1:12345")
+		assert.Equal(t, http.StatusForbidden, rec.Code)
+		rec = do(http.MethodGet, "/admin/intake", "", localhost)
+		assert.JSONEq(t, `{"paused":false}`, rec.Body.String())
+	})
+
+	t.Run("Unauthorized", func(t *testing.T) {
+		cfg := config.DefaultConfig(beatertest.MockBeatVersion())
+		cfg.IntakeControl.Enabled = &enabled
+		cfg.SecretToken = "abc123"
+		mux, err := NewMux(cfg, beatertest.NilReporter, nil, nil, nil)
+		require.NoError(t, err)
+
+		r := httptest.NewRequest(http.MethodPost, "/admin/intake", strings.NewReader(`{"paused":true}`))
+		r.RemoteAddr = localhost
+		r.Header.Set(headers.ContentType, "application/json")
+		rec := httptest.NewRecorder()
+		mux.ServeHTTP(rec, r)
+		assert.Equal(t, http.StatusUnauthorized, rec.Code)
+
+		r.Header.Set(headers.Authorization, "Bearer abc123")
+		r.Body = ioutil.NopCloser(strings.NewReader(`{"paused":true}`))
+		rec = httptest.NewRecorder()
+		mux.ServeHTTP(rec, r)
+		assert.Equal(t, http.StatusOK, rec.Code)
+	})
+
+	t.Run("Disabled", func(t *testing.T) {
+		cfg := config.DefaultConfig(beatertest.MockBeatVersion())
+		r := httptest.NewRequest(http.MethodGet, "/admin/intake", nil)
+		r.RemoteAddr = localhost
+		rec, err := requestToMuxer(cfg, r)
+		require.NoError(t, err)
+		assert.NotEqual(t, http.StatusOK, rec.Code)
+	})
+}
+
+func TestReadOnlyMode(t *testing.T) {
+	cfg := config.DefaultConfig(beatertest.MockBeatVersion())
+	cfg.ReadOnly = true
+	rumEnabled := true
+	cfg.RumConfig.Enabled = &rumEnabled
+	mux, err := NewMux(cfg, beatertest.NilReporter, nil, nil, nil)
+	require.NoError(t, err)
+
+	do := func(method, path string) *httptest.ResponseRecorder {
+		rec := httptest.NewRecorder()
+		mux.ServeHTTP(rec, httptest.NewRequest(method, path, nil))
+		return rec
+	}
+
+	for _, path := range []string{IntakePath, IntakeRUMPath, IntakeRUMV3Path, AssetSourcemapPath} {
+		rec := do(http.MethodPost, path)
+		assert.Equal(t, http.StatusServiceUnavailable, rec.Code, path)
+		assert.Contains(t, rec.Body.String(), "server is in read-only mode", path)
+	}
+
+	// Health checks and agent configuration are still served.
+	assert.Equal(t, http.StatusOK, do(http.MethodGet, RootPath).Code)
+	assert.NotContains(t, do(http.MethodGet, AgentConfigPath+"?service.name=opbeans").Body.String(), "read-only")
+}
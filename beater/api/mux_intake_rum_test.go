@@ -94,7 +94,7 @@ func TestRUMHandler_KillSwitchMiddleware(t *testing.T) {
 func TestRUMHandler_CORSMiddleware(t *testing.T) {
 	cfg := cfgEnabledRUM()
 	cfg.RumConfig.AllowOrigins = []string{"foo"}
-	h, err := rumIntakeHandler(cfg, nil, beatertest.NilReporter)
+	h, err := rumIntakeHandler(&middleware.Pause{}, nil, nil, nil, nil, nil)(cfg, nil, beatertest.NilReporter)
 	require.NoError(t, err)
 	c, w := beatertest.ContextWithResponseRecorder(http.MethodPost, "/")
 	c.Request.Header.Set(headers.Origin, "bar")
@@ -104,18 +104,18 @@ func TestRUMHandler_CORSMiddleware(t *testing.T) {
 }
 
 func TestIntakeRUMHandler_PanicMiddleware(t *testing.T) {
-	h, err := rumIntakeHandler(config.DefaultConfig(beatertest.MockBeatVersion()), nil, beatertest.NilReporter)
+	h, err := rumIntakeHandler(&middleware.Pause{}, nil, nil, nil, nil, nil)(config.DefaultConfig(beatertest.MockBeatVersion()), nil, beatertest.NilReporter)
 	require.NoError(t, err)
 	rec := &beatertest.WriterPanicOnce{}
 	c := request.NewContext()
-	c.Reset(rec, httptest.NewRequest(http.MethodGet, "/", nil))
+	c.Reset(rec, newTestRequest(http.MethodGet, "/"))
 	h(c)
 	assert.Equal(t, http.StatusInternalServerError, rec.StatusCode)
 	approvals.AssertApproveResult(t, approvalPathIntakeRUM(t.Name()), rec.Body.Bytes())
 }
 
 func TestRumHandler_MonitoringMiddleware(t *testing.T) {
-	h, err := rumIntakeHandler(config.DefaultConfig(beatertest.MockBeatVersion()), nil, beatertest.NilReporter)
+	h, err := rumIntakeHandler(&middleware.Pause{}, nil, nil, nil, nil, nil)(config.DefaultConfig(beatertest.MockBeatVersion()), nil, beatertest.NilReporter)
 	require.NoError(t, err)
 	c, _ := beatertest.ContextWithResponseRecorder(http.MethodPost, "/")
 	// send GET request resulting in 403 Forbidden error
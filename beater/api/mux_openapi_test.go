@@ -0,0 +1,114 @@
+// Licensed to Elasticsearch B.V. under one or more contributor
+// license agreements. See the NOTICE file distributed with
+// this work for additional information regarding copyright
+// ownership. Elasticsearch B.V. licenses this file to you under
+// the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package api
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/elastic/apm-server/beater/api/openapi"
+	"github.com/elastic/apm-server/beater/beatertest"
+	"github.com/elastic/apm-server/beater/config"
+)
+
+func TestOpenAPIEndpoint(t *testing.T) {
+	cfg := config.DefaultConfig(beatertest.MockBeatVersion())
+	enabled := true
+	cfg.OpenAPI.Enabled = &enabled
+
+	r := httptest.NewRequest(http.MethodGet, "/docs", nil)
+	rec, err := requestToMuxer(cfg, r)
+	require.NoError(t, err)
+	require.Equal(t, http.StatusOK, rec.Code)
+	assert.Equal(t, "application/json; charset=utf-8", rec.Header().Get("Content-Type"))
+
+	var doc openapi.Document
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &doc))
+	assert.Equal(t, openapi.Version, doc.OpenAPI)
+	for _, path := range []string{
+		RootPath, StatusPath, AssetSourcemapPath, AgentConfigPath, AgentConfigRUMPath,
+		IntakePath, IntakeRUMPath, IntakeRUMV3Path,
+	} {
+		assert.Contains(t, doc.Paths, path)
+	}
+	assert.NotContains(t, doc.Paths, ProfilePath)
+
+	t.Run("Disabled", func(t *testing.T) {
+		cfg := config.DefaultConfig(beatertest.MockBeatVersion())
+		rec, err := requestToMuxer(cfg, httptest.NewRequest(http.MethodGet, "/docs", nil))
+		require.NoError(t, err)
+		assert.NotEqual(t, http.StatusOK, rec.Code)
+	})
+}
+
+func TestOpenAPISecurity(t *testing.T) {
+	t.Run("NoAuth", func(t *testing.T) {
+		doc := OpenAPI(config.DefaultConfig(beatertest.MockBeatVersion()))
+		assert.Empty(t, doc.Components.SecuritySchemes)
+		assert.Nil(t, doc.Paths[IntakePath].Post.Security)
+		assert.NotContains(t, doc.Paths[IntakePath].Post.Responses, "401")
+	})
+
+	t.Run("SecretTokenAndAPIKey", func(t *testing.T) {
+		cfg := config.DefaultConfig(beatertest.MockBeatVersion())
+		cfg.SecretToken = "abc123"
+		cfg.APIKeyConfig.Enabled = true
+		doc := OpenAPI(cfg)
+		assert.Equal(t, "bearer", doc.Components.SecuritySchemes[secretTokenScheme].Scheme)
+		assert.Equal(t, "Authorization", doc.Components.SecuritySchemes[apiKeyScheme].Name)
+
+		backend := []openapi.SecurityRequirement{{secretTokenScheme: {}}, {apiKeyScheme: {}}}
+		assert.Equal(t, backend, doc.Paths[IntakePath].Post.Security)
+		assert.Equal(t, backend, doc.Paths[AssetSourcemapPath].Post.Security)
+		assert.Equal(t, backend, doc.Paths[AgentConfigPath].Get.Security)
+		assert.Contains(t, doc.Paths[IntakePath].Post.Responses, "401")
+
+		// authorization is optional for the root path, and not used for RUM routes
+		assert.Equal(t, append(backend, openapi.SecurityRequirement{}), doc.Paths[RootPath].Get.Security)
+		assert.Nil(t, doc.Paths[IntakeRUMPath].Post.Security)
+		assert.Nil(t, doc.Paths[AgentConfigRUMPath].Post.Security)
+	})
+}
+
+func TestOpenAPIExamples(t *testing.T) {
+	doc := OpenAPI(config.DefaultConfig(beatertest.MockBeatVersion()))
+	for _, path := range []string{IntakePath, IntakeRUMPath, IntakeRUMV3Path} {
+		body := doc.Paths[path].Post.RequestBody
+		require.NotNil(t, body, path)
+		assert.NotEmpty(t, body.Content[ndjsonContentType].Example, path)
+	}
+}
+
+func TestOpenAPIProfile(t *testing.T) {
+	cfg := config.DefaultConfig(beatertest.MockBeatVersion())
+	enabled := true
+	cfg.SelfInstrumentation = &config.InstrumentationConfig{Enabled: &enabled}
+	cfg.SelfInstrumentation.Profiling.CPU = &config.CPUProfiling{Enabled: true}
+	assert.Contains(t, OpenAPI(cfg).Paths, ProfilePath)
+}
+
+func TestOpenAPIProfileFeatureFlag(t *testing.T) {
+	cfg := config.DefaultConfig(beatertest.MockBeatVersion())
+	cfg.FeatureFlags.Profiles = true
+	assert.Contains(t, OpenAPI(cfg).Paths, ProfilePath)
+}
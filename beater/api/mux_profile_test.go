@@ -15,34 +15,27 @@
 // specific language governing permissions and limitations
 // under the License.
 
-package beater
+package api
 
 import (
+	"net/http"
 	"testing"
-	"time"
 
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 
-	"github.com/elastic/beats/v7/libbeat/beat"
-	"github.com/elastic/beats/v7/libbeat/common"
+	"github.com/elastic/apm-server/beater/beatertest"
+	"github.com/elastic/apm-server/beater/config"
 )
 
-func TestOnboarding(t *testing.T) {
-	events := make(chan beat.Event, 1)
-	beater, err := setupServer(t, nil, nil, events)
+func TestProfileHandler_FeatureFlag(t *testing.T) {
+	cfg := config.DefaultConfig(beatertest.MockBeatVersion())
+	rec, err := requestToMuxerWithPattern(cfg, ProfilePath)
 	require.NoError(t, err)
-	defer beater.Stop()
+	assert.Equal(t, http.StatusNotFound, rec.Code)
 
-	select {
-	case event := <-events:
-		listening := event.Fields["observer"].(common.MapStr)["listening"]
-		assert.Equal(t, "localhost:0", beater.config.Host)
-		assert.NotEqual(t, "localhost:0", listening)
-		processor := event.Fields["processor"].(common.MapStr)
-		assert.Equal(t, "onboarding", processor["name"])
-		assert.Equal(t, "onboarding", processor["event"])
-	case <-time.After(10 * time.Second):
-		t.Fatal("timed out waiting for onboarding event")
-	}
+	cfg.FeatureFlags.Profiles = true
+	rec, err = requestToMuxerWithPattern(cfg, ProfilePath)
+	require.NoError(t, err)
+	assert.NotEqual(t, http.StatusNotFound, rec.Code)
 }
@@ -19,7 +19,6 @@ package api
 
 import (
 	"net/http"
-	"net/http/httptest"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
@@ -57,7 +56,7 @@ func TestRootHandler_PanicMiddleware(t *testing.T) {
 	h := testHandler(t, rootHandler)
 	rec := &beatertest.WriterPanicOnce{}
 	c := request.NewContext()
-	c.Reset(rec, httptest.NewRequest(http.MethodGet, "/", nil))
+	c.Reset(rec, newTestRequest(http.MethodGet, "/"))
 	h(c)
 
 	assert.Equal(t, http.StatusInternalServerError, rec.StatusCode)
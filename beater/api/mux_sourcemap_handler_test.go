@@ -19,7 +19,6 @@ package api
 
 import (
 	"net/http"
-	"net/http/httptest"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
@@ -85,7 +84,7 @@ func TestSourcemapHandler_PanicMiddleware(t *testing.T) {
 	h := testHandler(t, sourcemapHandler)
 	rec := &beatertest.WriterPanicOnce{}
 	c := request.NewContext()
-	c.Reset(rec, httptest.NewRequest(http.MethodGet, "/", nil))
+	c.Reset(rec, newTestRequest(http.MethodGet, "/"))
 	h(c)
 	require.Equal(t, http.StatusInternalServerError, rec.StatusCode)
 	approvals.AssertApproveResult(t, approvalPathAsset(t.Name()), rec.Body.Bytes())
@@ -27,10 +27,21 @@ import (
 	"github.com/elastic/apm-server/beater/authorization"
 	"github.com/elastic/apm-server/beater/beatertest"
 	"github.com/elastic/apm-server/beater/config"
+	"github.com/elastic/apm-server/beater/headers"
 	"github.com/elastic/apm-server/beater/request"
 	"github.com/elastic/apm-server/publish"
 )
 
+// testRequestID is the fixed request ID of test requests,
+// so it can be compared in approved responses.
+const testRequestID = "test-request-id"
+
+func newTestRequest(method, target string) *http.Request {
+	r := httptest.NewRequest(method, target, nil)
+	r.Header.Set(headers.XRequestID, testRequestID)
+	return r
+}
+
 func requestToMuxerWithPattern(cfg *config.Config, pattern string) (*httptest.ResponseRecorder, error) {
 	r := httptest.NewRequest(http.MethodPost, pattern, nil)
 	return requestToMuxer(cfg, r)
@@ -44,7 +55,10 @@ func requestToMuxerWithHeader(cfg *config.Config, pattern string, method string,
 }
 
 func requestToMuxer(cfg *config.Config, r *http.Request) (*httptest.ResponseRecorder, error) {
-	mux, err := NewMux(cfg, beatertest.NilReporter)
+	if r.Header.Get(headers.XRequestID) == "" {
+		r.Header.Set(headers.XRequestID, testRequestID)
+	}
+	mux, err := NewMux(cfg, beatertest.NilReporter, nil, nil, nil)
 	if err != nil {
 		return nil, err
 	}
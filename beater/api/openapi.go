@@ -0,0 +1,513 @@
+// Licensed to Elasticsearch B.V. under one or more contributor
+// license agreements. See the NOTICE file distributed with
+// this work for additional information regarding copyright
+// ownership. Elasticsearch B.V. licenses this file to you under
+// the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package api
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"github.com/elastic/beats/v7/libbeat/version"
+
+	"github.com/elastic/apm-server/agentcfg"
+	"github.com/elastic/apm-server/beater/api/openapi"
+	"github.com/elastic/apm-server/beater/config"
+	"github.com/elastic/apm-server/beater/headers"
+	"github.com/elastic/apm-server/processor/stream/generated/examples"
+)
+
+const (
+	secretTokenScheme = "secretToken"
+	apiKeyScheme      = "apiKey"
+
+	ndjsonContentType = "application/x-ndjson"
+	jsonContentType   = "application/json"
+)
+
+// OpenAPI returns an OpenAPI document describing the routes NewMux registers
+// for the given configuration, including the enabled authorization schemes.
+func OpenAPI(cfg *config.Config) *openapi.Document {
+	doc := &openapi.Document{
+		OpenAPI: openapi.Version,
+		Info: openapi.Info{
+			Title:       "APM Server",
+			Description: "Intake, RUM, sourcemap upload and agent configuration API of the Elastic APM Server.",
+			Version:     version.GetDefaultVersion(),
+		},
+		Paths: make(map[string]openapi.PathItem),
+		Components: openapi.Components{
+			SecuritySchemes: securitySchemes(cfg),
+		},
+	}
+	for _, route := range routes(cfg, nil, nil, nil, nil, nil, nil, nil) {
+		doc.Paths[route.path] = route.spec(cfg)
+	}
+	return doc
+}
+
+// openAPIHandler serves the OpenAPI document for the given configuration.
+func openAPIHandler(cfg *config.Config) (http.HandlerFunc, error) {
+	body, err := json.MarshalIndent(OpenAPI(cfg), "", "  ")
+	if err != nil {
+		return nil, err
+	}
+	return func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set(headers.ContentType, "application/json; charset=utf-8")
+		w.Write(body)
+	}, nil
+}
+
+func securitySchemes(cfg *config.Config) map[string]openapi.SecurityScheme {
+	schemes := make(map[string]openapi.SecurityScheme)
+	if cfg.SecretToken != "" {
+		schemes[secretTokenScheme] = openapi.SecurityScheme{
+			Type:        "http",
+			Scheme:      "bearer",
+			Description: "The secret token configured in `apm-server.secret_token`.",
+		}
+	}
+	if cfg.APIKeyConfig.IsEnabled() {
+		schemes[apiKeyScheme] = openapi.SecurityScheme{
+			Type:        "apiKey",
+			In:          "header",
+			Name:        headers.Authorization,
+			Description: "An Elasticsearch API Key, sent as `ApiKey <base64(id:api_key)>`.",
+		}
+	}
+	return schemes
+}
+
+// backendSecurity returns the alternative security requirements for backend
+// routes, or nil if no authorization is configured.
+func backendSecurity(cfg *config.Config) []openapi.SecurityRequirement {
+	var security []openapi.SecurityRequirement
+	if cfg.SecretToken != "" {
+		security = append(security, openapi.SecurityRequirement{secretTokenScheme: {}})
+	}
+	if cfg.APIKeyConfig.IsEnabled() {
+		security = append(security, openapi.SecurityRequirement{apiKeyScheme: {}})
+	}
+	return security
+}
+
+func rootSpec(cfg *config.Config) openapi.PathItem {
+	security := backendSecurity(cfg)
+	if security != nil {
+		// unauthorized requests are served, but without server information
+		security = append(security, openapi.SecurityRequirement{})
+	}
+	return openapi.PathItem{
+		Get: &openapi.Operation{
+			Summary:     "Server information",
+			Description: "Healthcheck, reporting build information for authorized requests.",
+			OperationID: "getServerInfo",
+			Tags:        []string{"server"},
+			Responses: map[string]openapi.Response{
+				"200": jsonResponse("Server is up and running.", &openapi.Schema{
+					Type: "object",
+					Properties: map[string]openapi.Schema{
+						"build_date": {Type: "string", Format: "date-time"},
+						"build_sha":  {Type: "string"},
+						"version":    {Type: "string"},
+						"features": {
+							Type:                 "object",
+							Description:          "Experimental feature flags, and whether they are enabled.",
+							AdditionalProperties: &openapi.Schema{Type: "boolean"},
+						},
+						"capabilities": {
+							Type:        "object",
+							Description: "Capabilities of the server, for agents to negotiate features with.",
+							Properties: map[string]openapi.Schema{
+								"endpoints":       {Type: "array", Items: &openapi.Schema{Type: "string"}},
+								"max_header_size": {Type: "integer"},
+								"intake": {
+									Type: "object",
+									Properties: map[string]openapi.Schema{
+										"versions": {
+											Type:                 "object",
+											Description:          "Supported intake API versions, by agent type.",
+											AdditionalProperties: &openapi.Schema{Type: "array", Items: &openapi.Schema{Type: "string"}},
+										},
+										"max_event_size":    {Type: "integer"},
+										"content_encodings": {Type: "array", Items: &openapi.Schema{Type: "string"}},
+									},
+								},
+							},
+						},
+					},
+				}),
+			},
+			Security: security,
+		},
+	}
+}
+
+func statusSpec(cfg *config.Config) openapi.PathItem {
+	security := backendSecurity(cfg)
+	firstEventReceived := openapi.Schema{Type: "string", Format: "date-time"}
+	return openapi.PathItem{
+		Get: &openapi.Operation{
+			Summary:     "Server status",
+			Description: "Reports when events were first received by the server, per service and agent.",
+			OperationID: "getStatus",
+			Tags:        []string{"server"},
+			Responses: errorResponses(map[string]openapi.Response{
+				"200": jsonResponse("Server status.", &openapi.Schema{
+					Type: "object",
+					Properties: map[string]openapi.Schema{
+						"first_event_received": firstEventReceived,
+						"services": {
+							Type: "array",
+							Items: &openapi.Schema{
+								Type: "object",
+								Properties: map[string]openapi.Schema{
+									"name":                 {Type: "string"},
+									"first_event_received": firstEventReceived,
+									"agents": {
+										Type: "array",
+										Items: &openapi.Schema{
+											Type: "object",
+											Properties: map[string]openapi.Schema{
+												"name":                 {Type: "string"},
+												"first_event_received": firstEventReceived,
+											},
+										},
+									},
+								},
+							},
+						},
+					},
+				}),
+			}, security),
+			Security: security,
+		},
+	}
+}
+
+func buildInfoSpec(cfg *config.Config) openapi.PathItem {
+	security := backendSecurity(cfg)
+	flags := openapi.Schema{Type: "object", AdditionalProperties: &openapi.Schema{Type: "boolean"}}
+	return openapi.PathItem{
+		Get: &openapi.Operation{
+			Summary: "Server build information",
+			Description: "Reports the version and build of the server, the state of its optional features, " +
+				"and the versions and digests of the JSON schemas events are validated against.",
+			OperationID: "getBuildInfo",
+			Tags:        []string{"server"},
+			Responses: errorResponses(map[string]openapi.Response{
+				"200": jsonResponse("Server build information.", &openapi.Schema{
+					Type: "object",
+					Properties: map[string]openapi.Schema{
+						"version":       {Type: "string"},
+						"build_sha":     {Type: "string"},
+						"build_date":    {Type: "string", Format: "date-time"},
+						"features":      flags,
+						"feature_flags": flags,
+						"schemas": {
+							Type: "object",
+							Properties: map[string]openapi.Schema{
+								"versions": {Type: "array", Items: &openapi.Schema{Type: "string"}},
+								"digests":  {Type: "object", AdditionalProperties: &openapi.Schema{Type: "string"}},
+							},
+						},
+					},
+				}),
+			}, security),
+			Security: security,
+		},
+	}
+}
+
+func backendIntakeSpec(cfg *config.Config) openapi.PathItem {
+	return intakeSpec("postEvents", "Ingest events", examples.Events, backendSecurity(cfg))
+}
+
+func rumIntakeSpec(cfg *config.Config) openapi.PathItem {
+	return intakeSpec("postRUMEvents", "Ingest RUM events", examples.Events, nil)
+}
+
+func rumV3IntakeSpec(cfg *config.Config) openapi.PathItem {
+	return intakeSpec("postRUMV3Events", "Ingest RUM events in the v3 format", examples.RUMV3Events, nil)
+}
+
+func intakeSpec(operationID, summary, example string, security []openapi.SecurityRequirement) openapi.PathItem {
+	return openapi.PathItem{
+		Post: &openapi.Operation{
+			Summary: summary,
+			Description: "Events are sent as newline delimited JSON, starting with a metadata object. " +
+				"Run `apm-server export schema` for the JSON schemas events are validated against.",
+			OperationID: operationID,
+			Tags:        []string{"intake"},
+			Parameters: []openapi.Parameter{{
+				Name:        "verbose",
+				In:          "query",
+				Description: "Report the number of accepted events for successful requests.",
+				Schema:      &openapi.Schema{Type: "boolean"},
+			}},
+			RequestBody: &openapi.RequestBody{
+				Required: true,
+				Content: map[string]openapi.MediaType{
+					ndjsonContentType: {Schema: &openapi.Schema{Type: "string"}, Example: example},
+				},
+			},
+			Responses: errorResponses(map[string]openapi.Response{
+				"202": jsonResponse("Events accepted.", intakeResultSchema),
+				"400": jsonResponse("Invalid events, or request body too large.", intakeResultSchema),
+				"429": jsonResponse("Rate limit exceeded.", intakeResultSchema),
+				"503": jsonResponse("Queue is full, or server is shutting down.", intakeResultSchema),
+			}, security),
+			Security: security,
+		},
+	}
+}
+
+func profileSpec(cfg *config.Config) openapi.PathItem {
+	return openapi.PathItem{
+		Post: &openapi.Operation{
+			Summary:     "Ingest profiles",
+			Description: "Experimental: ingest pprof profiles of the server itself.",
+			OperationID: "postProfile",
+			Tags:        []string{"intake"},
+			RequestBody: &openapi.RequestBody{
+				Required: true,
+				Content: map[string]openapi.MediaType{
+					"multipart/form-data": {Schema: &openapi.Schema{
+						Type: "object",
+						Properties: map[string]openapi.Schema{
+							"metadata": {Type: "string", Description: "Metadata JSON object."},
+							"profile":  {Type: "string", Format: "binary", Description: "Gzipped pprof profile."},
+						},
+						Required: []string{"profile"},
+					}},
+				},
+			},
+			Responses: errorResponses(map[string]openapi.Response{
+				"202": {Description: "Profiles accepted."},
+			}, backendSecurity(cfg)),
+			Security: backendSecurity(cfg),
+		},
+	}
+}
+
+func sourcemapSpec(cfg *config.Config) openapi.PathItem {
+	return openapi.PathItem{
+		Post: &openapi.Operation{
+			Summary:     "Upload a sourcemap",
+			Description: "Requires `apm-server.rum.enabled` and `apm-server.rum.source_mapping.enabled`.",
+			OperationID: "postSourcemap",
+			Tags:        []string{"sourcemap"},
+			RequestBody: &openapi.RequestBody{
+				Required: true,
+				Content: map[string]openapi.MediaType{
+					"multipart/form-data": {Schema: &openapi.Schema{
+						Type: "object",
+						Properties: map[string]openapi.Schema{
+							"service_name":    {Type: "string"},
+							"service_version": {Type: "string"},
+							"bundle_filepath": {Type: "string"},
+							"sourcemap":       {Type: "string", Format: "binary"},
+						},
+						Required: []string{"service_name", "service_version", "bundle_filepath", "sourcemap"},
+					}},
+				},
+			},
+			Responses: errorResponses(map[string]openapi.Response{
+				"202": {Description: "Sourcemap accepted."},
+				"400": jsonResponse("Invalid sourcemap or form data.", errorSchema),
+			}, backendSecurity(cfg)),
+			Security: backendSecurity(cfg),
+		},
+		Get: &openapi.Operation{
+			Summary:     "List uploaded sourcemaps",
+			Description: "Requires `apm-server.rum.enabled` and `apm-server.rum.source_mapping.enabled`.",
+			OperationID: "listSourcemaps",
+			Tags:        []string{"sourcemap"},
+			Parameters: []openapi.Parameter{
+				{Name: "service_name", In: "query", Schema: &openapi.Schema{Type: "string"}},
+				{Name: "service_version", In: "query", Schema: &openapi.Schema{Type: "string"}},
+			},
+			Responses: errorResponses(map[string]openapi.Response{
+				"200": jsonResponse("Uploaded sourcemaps, most recent first.", &openapi.Schema{
+					Type: "object",
+					Properties: map[string]openapi.Schema{
+						"sourcemaps": {Type: "array", Items: &openapi.Schema{
+							Type: "object",
+							Properties: map[string]openapi.Schema{
+								"id":              {Type: "string"},
+								"service_name":    {Type: "string"},
+								"service_version": {Type: "string"},
+								"bundle_filepath": {Type: "string"},
+								"uploaded":        {Type: "string", Format: "date-time"},
+							},
+						}},
+					},
+				}),
+				"503": jsonResponse("Elasticsearch is unavailable.", errorSchema),
+			}, backendSecurity(cfg)),
+			Security: backendSecurity(cfg),
+		},
+		Delete: &openapi.Operation{
+			Summary:     "Delete uploaded sourcemaps",
+			Description: "Requires `apm-server.rum.enabled` and `apm-server.rum.source_mapping.enabled`.",
+			OperationID: "deleteSourcemaps",
+			Tags:        []string{"sourcemap"},
+			Parameters: []openapi.Parameter{
+				{Name: "service_name", In: "query", Required: true, Schema: &openapi.Schema{Type: "string"}},
+				{Name: "service_version", In: "query", Required: true, Schema: &openapi.Schema{Type: "string"}},
+				{Name: "bundle_filepath", In: "query", Schema: &openapi.Schema{Type: "string"},
+					Description: "Only delete the sourcemap for the given bundle, rather than all bundles of the service version."},
+			},
+			Responses: errorResponses(map[string]openapi.Response{
+				"200": jsonResponse("Number of sourcemaps deleted.", &openapi.Schema{
+					Type:       "object",
+					Properties: map[string]openapi.Schema{"deleted": {Type: "integer"}},
+				}),
+				"400": jsonResponse("Invalid query.", errorSchema),
+				"503": jsonResponse("Elasticsearch is unavailable.", errorSchema),
+			}, backendSecurity(cfg)),
+			Security: backendSecurity(cfg),
+		},
+	}
+}
+
+func backendAgentConfigSpec(cfg *config.Config) openapi.PathItem {
+	return agentConfigSpec("AgentConfig", backendSecurity(cfg))
+}
+
+func rumAgentConfigSpec(cfg *config.Config) openapi.PathItem {
+	return agentConfigSpec("RUMAgentConfig", nil)
+}
+
+func agentConfigSpec(name string, security []openapi.SecurityRequirement) openapi.PathItem {
+	responses := errorResponses(map[string]openapi.Response{
+		"200": {
+			Description: "Configuration for the requested service.",
+			Headers: map[string]openapi.Header{
+				headers.Etag: {Schema: &openapi.Schema{Type: "string"}},
+			},
+			Content: map[string]openapi.MediaType{
+				jsonContentType: {Schema: &openapi.Schema{
+					Type:                 "object",
+					AdditionalProperties: &openapi.Schema{Type: "string"},
+				}},
+			},
+		},
+		"304": {Description: "Configuration has not changed since the given etag."},
+		"400": jsonResponse("Invalid query.", errorSchema),
+		"403": jsonResponse("Remote configuration is disabled.", errorSchema),
+		"503": jsonResponse("Kibana is unavailable.", errorSchema),
+	}, security)
+	description := "Requires `apm-server.kibana` to be configured."
+	return openapi.PathItem{
+		Get: &openapi.Operation{
+			Summary:     "Query agent configuration",
+			Description: description,
+			OperationID: "get" + name,
+			Tags:        []string{"agent configuration"},
+			Parameters: []openapi.Parameter{
+				{Name: agentcfg.ServiceName, In: "query", Required: true, Schema: &openapi.Schema{Type: "string"}},
+				{Name: agentcfg.ServiceEnv, In: "query", Schema: &openapi.Schema{Type: "string"}},
+				{Name: agentcfg.Etag, In: "query", Schema: &openapi.Schema{Type: "string"},
+					Description: "Etag of the configuration last applied, alternative to the If-None-Match header."},
+				{Name: headers.IfNoneMatch, In: "header", Schema: &openapi.Schema{Type: "string"}},
+			},
+			Responses: responses,
+			Security:  security,
+		},
+		Post: &openapi.Operation{
+			Summary:     "Query agent configuration",
+			Description: description,
+			OperationID: "post" + name,
+			Tags:        []string{"agent configuration"},
+			Parameters: []openapi.Parameter{
+				{Name: headers.IfNoneMatch, In: "header", Schema: &openapi.Schema{Type: "string"}},
+			},
+			RequestBody: &openapi.RequestBody{
+				Required: true,
+				Content: map[string]openapi.MediaType{
+					jsonContentType: {
+						Schema: &openapi.Schema{
+							Type: "object",
+							Properties: map[string]openapi.Schema{
+								"service": {
+									Type: "object",
+									Properties: map[string]openapi.Schema{
+										"name":        {Type: "string"},
+										"environment": {Type: "string"},
+									},
+									Required: []string{"name"},
+								},
+							},
+							Required: []string{"service"},
+						},
+						Example: map[string]interface{}{
+							"service": map[string]string{"name": "opbeans-go", "environment": "production"},
+						},
+					},
+				},
+			},
+			Responses: responses,
+			Security:  security,
+		},
+	}
+}
+
+var (
+	errorSchema = &openapi.Schema{
+		Type:       "object",
+		Properties: map[string]openapi.Schema{"error": {Type: "string"}},
+	}
+	intakeResultSchema = &openapi.Schema{
+		Type: "object",
+		Properties: map[string]openapi.Schema{
+			"accepted": {Type: "integer"},
+			"errors": {
+				Type: "array",
+				Items: &openapi.Schema{
+					Type: "object",
+					Properties: map[string]openapi.Schema{
+						"message":  {Type: "string"},
+						"document": {Type: "string"},
+					},
+				},
+			},
+		},
+	}
+)
+
+func jsonResponse(description string, schema *openapi.Schema) openapi.Response {
+	return openapi.Response{
+		Description: description,
+		Content:     map[string]openapi.MediaType{jsonContentType: {Schema: schema}},
+	}
+}
+
+// errorResponses adds the responses common to all routes to the given responses,
+// and authorization failures for routes requiring authorization.
+func errorResponses(responses map[string]openapi.Response, security []openapi.SecurityRequirement) map[string]openapi.Response {
+	if _, ok := responses["503"]; !ok {
+		responses["503"] = jsonResponse("Request timed out.", errorSchema)
+	}
+	if _, ok := responses["403"]; !ok {
+		responses["403"] = jsonResponse("Endpoint is disabled, or request is forbidden.", errorSchema)
+	}
+	responses["500"] = jsonResponse("Internal error.", errorSchema)
+	if security != nil {
+		responses["401"] = jsonResponse("Missing or invalid credentials.", errorSchema)
+	}
+	return responses
+}
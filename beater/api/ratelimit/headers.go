@@ -0,0 +1,44 @@
+// Licensed to Elasticsearch B.V. under one or more contributor
+// license agreements. See the NOTICE file distributed with
+// this work for additional information regarding copyright
+// ownership. Elasticsearch B.V. licenses this file to you under
+// the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package ratelimit
+
+import (
+	"math"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/elastic/apm-server/beater/headers"
+)
+
+// Allowance describes how much a client may send immediately, out of its
+// limit, and the time until its full limit is available again.
+type Allowance struct {
+	Limit     int
+	Remaining int
+	Reset     time.Duration
+}
+
+// SetHeaders sets the RateLimit-Limit, RateLimit-Remaining, and
+// RateLimit-Reset response headers describing the allowance,
+// so clients can adapt their send rate.
+func (a Allowance) SetHeaders(h http.Header) {
+	h.Set(headers.RateLimitLimit, strconv.Itoa(a.Limit))
+	h.Set(headers.RateLimitRemaining, strconv.Itoa(a.Remaining))
+	h.Set(headers.RateLimitReset, strconv.Itoa(int(math.Ceil(a.Reset.Seconds()))))
+}
@@ -0,0 +1,34 @@
+// Licensed to Elasticsearch B.V. under one or more contributor
+// license agreements. See the NOTICE file distributed with
+// this work for additional information regarding copyright
+// ownership. Elasticsearch B.V. licenses this file to you under
+// the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package ratelimit
+
+import (
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAllowanceSetHeaders(t *testing.T) {
+	h := make(http.Header)
+	Allowance{Limit: 6, Remaining: 1, Reset: 2500 * time.Millisecond}.SetHeaders(h)
+	assert.Equal(t, "6", h.Get("RateLimit-Limit"))
+	assert.Equal(t, "1", h.Get("RateLimit-Remaining"))
+	assert.Equal(t, "3", h.Get("RateLimit-Reset"))
+}
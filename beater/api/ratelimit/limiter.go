@@ -0,0 +1,166 @@
+// Licensed to Elasticsearch B.V. under one or more contributor
+// license agreements. See the NOTICE file distributed with
+// this work for additional information regarding copyright
+// ownership. Elasticsearch B.V. licenses this file to you under
+// the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package ratelimit
+
+import (
+	"context"
+	"errors"
+	"math"
+	"sync"
+	"time"
+
+	"golang.org/x/time/rate"
+)
+
+var errWaitExceedsDeadline = errors.New("rate: Wait(n) would exceed context deadline")
+
+// Limiter is a token bucket rate limiter, like rate.Limiter, which also
+// reports the tokens it holds. This allows describing a client's allowance
+// in response headers, without reserving tokens to find out.
+type Limiter struct {
+	limit rate.Limit
+	burst int
+
+	mu     sync.Mutex
+	tokens float64   // tokens held at last
+	last   time.Time // time tokens were last updated
+}
+
+// NewLimiter returns a new Limiter allowing events up to rate r,
+// and bursts of at most b events. The limiter is initially full.
+func NewLimiter(r rate.Limit, b int) *Limiter {
+	return &Limiter{limit: r, burst: b, tokens: float64(b)}
+}
+
+// Limit returns the maximum overall event rate.
+func (l *Limiter) Limit() rate.Limit {
+	return l.limit
+}
+
+// Burst returns the maximum burst size.
+func (l *Limiter) Burst() int {
+	return l.burst
+}
+
+// Allow reports whether an event may happen now.
+func (l *Limiter) Allow() bool {
+	return l.AllowN(time.Now(), 1)
+}
+
+// AllowN reports whether n events may happen at time now,
+// consuming tokens if so.
+func (l *Limiter) AllowN(now time.Time, n int) bool {
+	_, ok := l.reserveN(now, n, 0)
+	return ok
+}
+
+// WaitN blocks until n events may happen, consuming tokens.
+// It returns an error without consuming tokens if n exceeds the
+// burst size, or if ctx would be done before the tokens are available.
+// Tokens are returned if ctx is done while waiting.
+func (l *Limiter) WaitN(ctx context.Context, n int) error {
+	now := time.Now()
+	maxWait := time.Duration(math.MaxInt64)
+	if deadline, ok := ctx.Deadline(); ok {
+		maxWait = deadline.Sub(now)
+	}
+	wait, ok := l.reserveN(now, n, maxWait)
+	if !ok {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+		return errWaitExceedsDeadline
+	}
+	if wait <= 0 {
+		return nil
+	}
+	t := time.NewTimer(wait)
+	defer t.Stop()
+	select {
+	case <-t.C:
+		return nil
+	case <-ctx.Done():
+		l.mu.Lock()
+		l.tokens += float64(n)
+		l.mu.Unlock()
+		return ctx.Err()
+	}
+}
+
+// Allowance returns the limiter's allowance at time now,
+// without consuming any tokens.
+func (l *Limiter) Allowance(now time.Time) Allowance {
+	l.mu.Lock()
+	tokens := l.advance(now)
+	l.mu.Unlock()
+	a := Allowance{Limit: l.burst}
+	if tokens > 0 {
+		a.Remaining = int(tokens)
+	}
+	if missing := float64(l.burst) - tokens; missing > 0 && l.limit > 0 {
+		a.Reset = durationFromTokens(l.limit, missing)
+	}
+	return a
+}
+
+// reserveN consumes n tokens at time now, returning how long to wait
+// for them to be available. No tokens are consumed if n exceeds the
+// burst size, or if the wait would exceed maxWait.
+func (l *Limiter) reserveN(now time.Time, n int, maxWait time.Duration) (time.Duration, bool) {
+	if l.limit == rate.Inf {
+		return 0, true
+	}
+	if n > l.burst {
+		return 0, false
+	}
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	tokens := l.advance(now) - float64(n)
+	var wait time.Duration
+	if tokens < 0 {
+		if l.limit <= 0 {
+			return 0, false
+		}
+		wait = durationFromTokens(l.limit, -tokens)
+	}
+	if wait > maxWait {
+		return 0, false
+	}
+	l.tokens = tokens
+	if now.After(l.last) {
+		l.last = now
+	}
+	return wait, true
+}
+
+// advance returns the tokens held at time now. l.mu must be held.
+func (l *Limiter) advance(now time.Time) float64 {
+	elapsed := now.Sub(l.last)
+	if elapsed <= 0 || l.limit <= 0 {
+		return l.tokens
+	}
+	tokens := l.tokens + elapsed.Seconds()*float64(l.limit)
+	if burst := float64(l.burst); tokens > burst {
+		tokens = burst
+	}
+	return tokens
+}
+
+func durationFromTokens(limit rate.Limit, tokens float64) time.Duration {
+	return time.Duration(tokens / float64(limit) * float64(time.Second))
+}
@@ -0,0 +1,76 @@
+// Licensed to Elasticsearch B.V. under one or more contributor
+// license agreements. See the NOTICE file distributed with
+// this work for additional information regarding copyright
+// ownership. Elasticsearch B.V. licenses this file to you under
+// the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package ratelimit
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLimiterAllowance(t *testing.T) {
+	now := time.Now()
+	l := NewLimiter(2, 6)
+	assert.Equal(t, Allowance{Limit: 6, Remaining: 6}, l.Allowance(now))
+
+	// Querying the allowance does not consume it.
+	assert.True(t, l.AllowN(now, 5))
+	assert.Equal(t, Allowance{Limit: 6, Remaining: 1, Reset: 2500 * time.Millisecond}, l.Allowance(now))
+	assert.Equal(t, Allowance{Limit: 6, Remaining: 1, Reset: 2500 * time.Millisecond}, l.Allowance(now))
+
+	assert.True(t, l.AllowN(now, 1))
+	assert.False(t, l.AllowN(now, 1))
+	assert.Equal(t, Allowance{Limit: 6, Remaining: 0, Reset: 3 * time.Second}, l.Allowance(now))
+	assert.Equal(t, Allowance{Limit: 6, Remaining: 2, Reset: 2 * time.Second}, l.Allowance(now.Add(time.Second)))
+	assert.Equal(t, Allowance{Limit: 6, Remaining: 6}, l.Allowance(now.Add(time.Minute)))
+}
+
+func TestLimiterAllowN(t *testing.T) {
+	now := time.Now()
+	l := NewLimiter(1, 2)
+	assert.False(t, l.AllowN(now, 3), "n exceeds burst")
+	assert.True(t, l.AllowN(now, 2))
+	assert.False(t, l.AllowN(now, 1))
+	assert.True(t, l.AllowN(now.Add(time.Second), 1))
+
+	// A limiter without rate or burst allows nothing.
+	l = NewLimiter(0, 0)
+	assert.False(t, l.Allow())
+}
+
+func TestLimiterWaitN(t *testing.T) {
+	l := NewLimiter(100, 10)
+	require.NoError(t, l.WaitN(context.Background(), 10))
+
+	// Waiting for a token takes about 10ms.
+	start := time.Now()
+	require.NoError(t, l.WaitN(context.Background(), 1))
+	assert.True(t, time.Since(start) >= 5*time.Millisecond)
+
+	// Waits exceeding the context deadline fail immediately,
+	// without consuming tokens.
+	ctx, cancel := context.WithTimeout(context.Background(), time.Millisecond)
+	defer cancel()
+	before := l.Allowance(time.Now()).Remaining
+	assert.Error(t, l.WaitN(ctx, 10))
+	assert.True(t, l.Allowance(time.Now()).Remaining >= before)
+	assert.Error(t, l.WaitN(context.Background(), 11), "n exceeds burst")
+}
@@ -40,7 +40,7 @@ type Store struct {
 	limit          int
 	burstFactor    int
 	mu             sync.Mutex //guards limiter in cache
-	evictedLimiter *rate.Limiter
+	evictedLimiter *Limiter
 }
 
 // NewStore returns a new instance of the Store
@@ -52,7 +52,7 @@ func NewStore(size, rateLimit, burstFactor int) (*Store, error) {
 	store := Store{limit: rateLimit, burstFactor: burstFactor}
 
 	var onEvicted = func(_ interface{}, value interface{}) {
-		store.evictedLimiter = *value.(**rate.Limiter)
+		store.evictedLimiter = *value.(**Limiter)
 	}
 
 	c, err := simplelru.NewLRU(size, simplelru.EvictCallback(onEvicted))
@@ -63,8 +63,8 @@ func NewStore(size, rateLimit, burstFactor int) (*Store, error) {
 	return &store, nil
 }
 
-// acquire returns a rate.Limiter instance for the given key
-func (s *Store) acquire(key string) *rate.Limiter {
+// acquire returns a Limiter instance for the given key
+func (s *Store) acquire(key string) *Limiter {
 
 	// lock get and add action for cache to allow proper eviction handling without
 	// race conditions.
@@ -72,20 +72,20 @@ func (s *Store) acquire(key string) *rate.Limiter {
 	defer s.mu.Unlock()
 
 	if l, ok := s.cache.Get(key); ok {
-		return *l.(**rate.Limiter)
+		return *l.(**Limiter)
 	}
 
-	var limiter *rate.Limiter
+	var limiter *Limiter
 	if evicted := s.cache.Add(key, &limiter); evicted {
 		limiter = s.evictedLimiter
 	} else {
-		limiter = rate.NewLimiter(rate.Limit(s.limit), s.limit*s.burstFactor)
+		limiter = NewLimiter(rate.Limit(s.limit), s.limit*s.burstFactor)
 	}
 	return limiter
 }
 
 // ForIP returns a rate limiter for the given request IP
-func (s *Store) ForIP(r *http.Request) *rate.Limiter {
+func (s *Store) ForIP(r *http.Request) *Limiter {
 	if s == nil {
 		return nil
 	}
@@ -35,14 +35,51 @@ var (
 	registry      = monitoring.Default.NewRegistry("apm-server.root")
 )
 
+// HandlerConfig holds the server information reported by Handler,
+// in addition to build information.
+type HandlerConfig struct {
+	// Features holds the state of the experimental feature flags.
+	Features map[string]bool
+
+	// Capabilities describes what the server supports.
+	Capabilities Capabilities
+}
+
+// Capabilities describes what the server supports, so agents
+// can negotiate features rather than assume them by version.
+type Capabilities struct {
+	// Endpoints holds the paths of the enabled API endpoints.
+	Endpoints []string `json:"endpoints"`
+
+	// MaxHeaderSize is the maximum size of request headers, in bytes.
+	MaxHeaderSize int `json:"max_header_size"`
+
+	Intake IntakeCapabilities `json:"intake"`
+}
+
+// IntakeCapabilities describes what the intake endpoints support.
+type IntakeCapabilities struct {
+	// Versions holds the supported intake API versions,
+	// keyed by the type of agent: "backend" or "rum".
+	Versions map[string][]string `json:"versions"`
+
+	// MaxEventSize is the maximum size of an event, in bytes.
+	MaxEventSize int `json:"max_event_size"`
+
+	// ContentEncodings holds the supported request Content-Encodings.
+	ContentEncodings []string `json:"content_encodings"`
+}
+
 // Handler returns error if route does not exist,
 // otherwise returns information about the server. The detail level differs for authorized and non-authorized requests.
 //TODO: only allow GET, HEAD requests (breaking change)
-func Handler() request.Handler {
+func Handler(cfg HandlerConfig) request.Handler {
 	serverInfo := common.MapStr{
-		"build_date": version.BuildTime().Format(time.RFC3339),
-		"build_sha":  version.Commit(),
-		"version":    version.GetDefaultVersion(),
+		"build_date":   version.BuildTime().Format(time.RFC3339),
+		"build_sha":    version.Commit(),
+		"version":      version.GetDefaultVersion(),
+		"features":     cfg.Features,
+		"capabilities": cfg.Capabilities,
 	}
 
 	return func(c *request.Context) {
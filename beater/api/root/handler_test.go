@@ -36,7 +36,7 @@ import (
 func TestRootHandler(t *testing.T) {
 	t.Run("404", func(t *testing.T) {
 		c, w := beatertest.ContextWithResponseRecorder(http.MethodGet, "/abc/xyz")
-		Handler()(c)
+		Handler(HandlerConfig{})(c)
 
 		assert.Equal(t, http.StatusNotFound, w.Code)
 		assert.Equal(t, `{"error":"404 page not found"}`+"\n", w.Body.String())
@@ -45,7 +45,7 @@ func TestRootHandler(t *testing.T) {
 	t.Run("ok", func(t *testing.T) {
 		c, w := beatertest.ContextWithResponseRecorder(http.MethodGet, "/")
 		c.Authorization = &authorization.DenyAuth{}
-		Handler()(c)
+		Handler(HandlerConfig{})(c)
 
 		assert.Equal(t, http.StatusOK, w.Code)
 		assert.Equal(t, "", w.Body.String())
@@ -54,7 +54,7 @@ func TestRootHandler(t *testing.T) {
 	t.Run("unauthorized", func(t *testing.T) {
 		c, w := beatertest.ContextWithResponseRecorder(http.MethodGet, "/")
 		c.Authorization = authorization.DenyAuth{}
-		Handler()(c)
+		Handler(HandlerConfig{})(c)
 
 		assert.Equal(t, http.StatusOK, w.Code)
 		assert.Equal(t, "", w.Body.String())
@@ -65,10 +65,24 @@ func TestRootHandler(t *testing.T) {
 		builder, err := authorization.NewBuilder(&config.Config{SecretToken: "abc"})
 		require.NoError(t, err)
 		c.Authorization = builder.ForPrivilege("").AuthorizationFor("Bearer", "abc")
-		Handler()(c)
+		Handler(HandlerConfig{
+			Features: map[string]bool{"profiles": true},
+			Capabilities: Capabilities{
+				Endpoints:     []string{"/"},
+				MaxHeaderSize: 1024,
+				Intake: IntakeCapabilities{
+					Versions:         map[string][]string{"backend": {"v2"}},
+					MaxEventSize:     2048,
+					ContentEncodings: []string{"gzip"},
+				},
+			},
+		})(c)
 
 		assert.Equal(t, http.StatusOK, w.Code)
-		body := fmt.Sprintf("{\"build_date\":\"0001-01-01T00:00:00Z\",\"build_sha\":\"%s\",\"version\":\"%s\"}\n",
+		body := fmt.Sprintf("{\"build_date\":\"0001-01-01T00:00:00Z\",\"build_sha\":\"%s\","+
+			"\"capabilities\":{\"endpoints\":[\"/\"],\"max_header_size\":1024,\"intake\":"+
+			"{\"versions\":{\"backend\":[\"v2\"]},\"max_event_size\":2048,\"content_encodings\":[\"gzip\"]}},"+
+			"\"features\":{\"profiles\":true},\"version\":\"%s\"}\n",
 			version.Commit(), version.GetDefaultVersion())
 		assert.Equal(t, body, w.Body.String())
 	})
@@ -0,0 +1,84 @@
+// Licensed to Elasticsearch B.V. under one or more contributor
+// license agreements. See the NOTICE file distributed with
+// this work for additional information regarding copyright
+// ownership. Elasticsearch B.V. licenses this file to you under
+// the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package api
+
+import (
+	"net/http"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+
+	"github.com/elastic/beats/v7/libbeat/paths"
+
+	"github.com/elastic/apm-server/beater/config"
+)
+
+// rumAgentBundleCacheControl allows browsers and proxies to cache RUM agent
+// bundles indefinitely: bundles are served at paths including their version,
+// so the content served at a path never changes.
+const rumAgentBundleCacheControl = "public, max-age=31536000, immutable"
+
+// rumAgentBundleHandler serves the RUM agent bundles and their sourcemaps,
+// found at <path>/<version>/<file> on disk, at <url>/<version>/<file>.
+func rumAgentBundleHandler(cfg *config.RumAgentBundle) http.HandlerFunc {
+	dir := paths.Resolve(paths.Home, cfg.Path)
+	prefix := strings.TrimSuffix(cfg.URL, "/") + "/"
+	return func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodGet && r.Method != http.MethodHead {
+			w.Header().Set("Allow", "GET, HEAD")
+			http.Error(w, http.StatusText(http.StatusMethodNotAllowed), http.StatusMethodNotAllowed)
+			return
+		}
+
+		// Only serve files directly within a version directory,
+		// and only JavaScript bundles and their sourcemaps.
+		name := strings.TrimPrefix(r.URL.Path, prefix)
+		parts := strings.Split(name, "/")
+		if len(parts) != 2 || !validBundlePathSegment(parts[0]) || !validBundlePathSegment(parts[1]) ||
+			(path.Ext(name) != ".js" && path.Ext(name) != ".map") {
+			http.NotFound(w, r)
+			return
+		}
+
+		f, err := os.Open(filepath.Join(dir, parts[0], parts[1]))
+		if err != nil {
+			http.NotFound(w, r)
+			return
+		}
+		defer f.Close()
+		info, err := f.Stat()
+		if err != nil || info.IsDir() {
+			http.NotFound(w, r)
+			return
+		}
+
+		if path.Ext(name) == ".map" {
+			w.Header().Set("Content-Type", "application/json")
+		} else {
+			w.Header().Set("Content-Type", "application/javascript")
+		}
+		w.Header().Set("Cache-Control", rumAgentBundleCacheControl)
+		w.Header().Set("X-Content-Type-Options", "nosniff")
+		http.ServeContent(w, r, name, info.ModTime(), f)
+	}
+}
+
+func validBundlePathSegment(s string) bool {
+	return s != "" && s != "." && s != ".." && !strings.ContainsAny(s, `\`)
+}
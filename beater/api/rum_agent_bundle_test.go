@@ -0,0 +1,102 @@
+// Licensed to Elasticsearch B.V. under one or more contributor
+// license agreements. See the NOTICE file distributed with
+// this work for additional information regarding copyright
+// ownership. Elasticsearch B.V. licenses this file to you under
+// the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package api
+
+import (
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRUMAgentBundleHandler(t *testing.T) {
+	dir, err := ioutil.TempDir("", "rum-agent")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+	require.NoError(t, os.Mkdir(filepath.Join(dir, "5.0.0"), 0755))
+	for name, content := range map[string]string{
+		"elastic-apm-rum.umd.min.js":     "var elasticApm;",
+		"elastic-apm-rum.umd.min.js.map": `{"version":3}`,
+		"README.md":                      "readme",
+	} {
+		require.NoError(t, ioutil.WriteFile(filepath.Join(dir, "5.0.0", name), []byte(content), 0644))
+	}
+
+	cfg := cfgEnabledRUM()
+	enabled := true
+	cfg.RumConfig.AgentBundle.Enabled = &enabled
+	cfg.RumConfig.AgentBundle.Path = dir
+
+	request := func(method, path string) *httptest.ResponseRecorder {
+		rec, err := requestToMuxer(cfg, httptest.NewRequest(method, path, nil))
+		require.NoError(t, err)
+		return rec
+	}
+
+	t.Run("Bundle", func(t *testing.T) {
+		rec := request(http.MethodGet, "/rum/agent/5.0.0/elastic-apm-rum.umd.min.js")
+		require.Equal(t, http.StatusOK, rec.Code)
+		assert.Equal(t, "var elasticApm;", rec.Body.String())
+		assert.Equal(t, "application/javascript", rec.Header().Get("Content-Type"))
+		assert.Equal(t, rumAgentBundleCacheControl, rec.Header().Get("Cache-Control"))
+	})
+
+	t.Run("Sourcemap", func(t *testing.T) {
+		rec := request(http.MethodGet, "/rum/agent/5.0.0/elastic-apm-rum.umd.min.js.map")
+		require.Equal(t, http.StatusOK, rec.Code)
+		assert.Equal(t, "application/json", rec.Header().Get("Content-Type"))
+	})
+
+	t.Run("NotModified", func(t *testing.T) {
+		r := httptest.NewRequest(http.MethodGet, "/rum/agent/5.0.0/elastic-apm-rum.umd.min.js", nil)
+		r.Header.Set("If-Modified-Since", "Fri, 31 Dec 9999 23:59:59 GMT")
+		rec, err := requestToMuxer(cfg, r)
+		require.NoError(t, err)
+		assert.Equal(t, http.StatusNotModified, rec.Code)
+	})
+
+	for name, path := range map[string]string{
+		"UnknownVersion":  "/rum/agent/4.0.0/elastic-apm-rum.umd.min.js",
+		"OtherFile":       "/rum/agent/5.0.0/README.md",
+		"NoVersion":       "/rum/agent/elastic-apm-rum.umd.min.js",
+		"ParentDirectory": "/rum/agent/5.0.0/../5.0.0/elastic-apm-rum.umd.min.js",
+	} {
+		t.Run(name, func(t *testing.T) {
+			rec := request(http.MethodGet, path)
+			assert.NotEqual(t, http.StatusOK, rec.Code)
+		})
+	}
+
+	t.Run("MethodNotAllowed", func(t *testing.T) {
+		rec := request(http.MethodPost, "/rum/agent/5.0.0/elastic-apm-rum.umd.min.js")
+		assert.Equal(t, http.StatusMethodNotAllowed, rec.Code)
+	})
+
+	t.Run("Disabled", func(t *testing.T) {
+		cfg := cfgEnabledRUM()
+		cfg.RumConfig.AgentBundle.Path = dir
+		rec, err := requestToMuxer(cfg, httptest.NewRequest(http.MethodGet, "/rum/agent/5.0.0/elastic-apm-rum.umd.min.js", nil))
+		require.NoError(t, err)
+		assert.Equal(t, http.StatusNotFound, rec.Code)
+	})
+}
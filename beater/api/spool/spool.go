@@ -0,0 +1,433 @@
+// Licensed to Elasticsearch B.V. under one or more contributor
+// license agreements. See the NOTICE file distributed with
+// this work for additional information regarding copyright
+// ownership. Elasticsearch B.V. licenses this file to you under
+// the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+// Package spool writes events rejected while the output is unavailable
+// to local disk, and backfills them once it has recovered.
+package spool
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/pkg/errors"
+	"golang.org/x/time/rate"
+
+	"github.com/elastic/beats/v7/libbeat/beat"
+	"github.com/elastic/beats/v7/libbeat/common"
+	"github.com/elastic/beats/v7/libbeat/logp"
+	"github.com/elastic/beats/v7/libbeat/monitoring"
+	"github.com/elastic/beats/v7/libbeat/paths"
+
+	"github.com/elastic/apm-server/beater/config"
+	logs "github.com/elastic/apm-server/log"
+	"github.com/elastic/apm-server/publish"
+	"github.com/elastic/apm-server/transform"
+)
+
+const (
+	fileExtension   = ".ndjson"
+	replayingSuffix = ".replaying"
+	tempSuffix      = ".tmp"
+
+	// maxBatchSize is the maximum number of spooled events
+	// reported together when backfilling.
+	maxBatchSize = 100
+)
+
+var (
+	registry         = monitoring.Default.NewRegistry("apm-server.spool")
+	eventsSpooled    = monitoring.NewInt(registry, "events.spooled")
+	eventsBackfilled = monitoring.NewInt(registry, "events.backfilled")
+	spoolFull        = monitoring.NewInt(registry, "full")
+
+	// fileSeq disambiguates files spooled at the same time,
+	// e.g. by the self-instrumentation server.
+	fileSeq uint64
+)
+
+var errSpoolFull = errors.New("spool is full")
+
+// Spooler writes events rejected by its reporter with publish.ErrFull or
+// publish.ErrMemoryFull to disk, one file per request, up to a configured
+// size. Spooled events are reported again by Backfill.
+//
+// Events are spooled after being transformed, so backfilled events are
+// published exactly as they would have been originally.
+type Spooler struct {
+	dir      string
+	maxBytes int64
+	rate     int
+	report   publish.Reporter
+	logger   *logp.Logger
+
+	mu   sync.Mutex // serializes writing and removing files
+	size int64
+
+	progressMu sync.Mutex
+	progress   Progress
+}
+
+// Progress describes the most recent backfill.
+type Progress struct {
+	Running      bool       `json:"running"`
+	Started      *time.Time `json:"started,omitempty"`
+	Finished     *time.Time `json:"finished,omitempty"`
+	Files        int        `json:"files"`
+	FilesDone    int        `json:"files_done"`
+	Events       int64      `json:"events"`
+	Error        string     `json:"error,omitempty"`
+	SpooledBytes int64      `json:"spooled_bytes"`
+}
+
+// New returns a new Spooler for cfg, spooling events rejected by report,
+// and creating its directory if needed. Files left being backfilled when
+// the server was last stopped are spooled again.
+func New(cfg config.SpoolConfig, report publish.Reporter) (*Spooler, error) {
+	dir := cfg.Path
+	if !filepath.IsAbs(dir) {
+		dir = paths.Resolve(paths.Data, dir)
+	}
+	if err := os.MkdirAll(dir, 0700); err != nil {
+		return nil, err
+	}
+	s := &Spooler{
+		dir:      dir,
+		maxBytes: cfg.MaxBytes,
+		rate:     cfg.Rate,
+		report:   report,
+		logger:   logp.NewLogger(logs.Spool),
+	}
+	infos, err := ioutil.ReadDir(dir)
+	if err != nil {
+		return nil, err
+	}
+	for _, info := range infos {
+		path := filepath.Join(dir, info.Name())
+		switch {
+		case strings.HasSuffix(info.Name(), tempSuffix):
+			os.Remove(path)
+			continue
+		case strings.HasSuffix(info.Name(), replayingSuffix):
+			if err := os.Rename(path, strings.TrimSuffix(path, replayingSuffix)); err != nil {
+				return nil, err
+			}
+		case !strings.HasSuffix(info.Name(), fileExtension):
+			continue
+		}
+		s.size += info.Size()
+	}
+	return s, nil
+}
+
+// Report reports req, spooling its events if they are rejected because
+// the output is unavailable. Once the spool is full, the original error
+// is returned.
+func (s *Spooler) Report(ctx context.Context, req publish.PendingReq) error {
+	err := s.report(ctx, req)
+	if err != publish.ErrFull && err != publish.ErrMemoryFull {
+		return err
+	}
+	var buf bytes.Buffer
+	enc := json.NewEncoder(&buf)
+	var n int64
+	for _, t := range req.Transformables {
+		for _, event := range t.Transform(ctx, req.Tcontext) {
+			if encErr := enc.Encode(spooledEvent{
+				Timestamp: event.Timestamp,
+				Meta:      event.Meta,
+				Fields:    event.Fields,
+			}); encErr != nil {
+				s.logger.Errorw("failed to spool event", "error", encErr)
+				return err
+			}
+			n++
+		}
+	}
+	if n == 0 {
+		return nil
+	}
+	if spoolErr := s.write(buf.Bytes(), false); spoolErr != nil {
+		if spoolErr == errSpoolFull {
+			spoolFull.Inc()
+		} else {
+			s.logger.Errorw("failed to spool events", "error", spoolErr)
+		}
+		return err
+	}
+	eventsSpooled.Add(n)
+	return nil
+}
+
+// write writes data to a new spool file. Unless force is true,
+// errSpoolFull is returned if this would exceed the maximum size.
+func (s *Spooler) write(data []byte, force bool) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	size := int64(len(data))
+	if !force && s.size+size > s.maxBytes {
+		return errSpoolFull
+	}
+	name := fmt.Sprintf("%020d-%d%s", time.Now().UnixNano(), atomic.AddUint64(&fileSeq, 1), fileExtension)
+	path := filepath.Join(s.dir, name)
+	// Write to a temporary file first, so that files
+	// are never backfilled while partially written.
+	if err := ioutil.WriteFile(path+tempSuffix, data, 0600); err != nil {
+		os.Remove(path + tempSuffix)
+		return err
+	}
+	if err := os.Rename(path+tempSuffix, path); err != nil {
+		os.Remove(path + tempSuffix)
+		return err
+	}
+	s.size += size
+	return nil
+}
+
+func (s *Spooler) remove(path string, size int64) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if err := os.Remove(path); err != nil {
+		return err
+	}
+	s.size -= size
+	return nil
+}
+
+// Progress returns the progress of the most recent backfill.
+func (s *Spooler) Progress() Progress {
+	s.mu.Lock()
+	size := s.size
+	s.mu.Unlock()
+	s.progressMu.Lock()
+	defer s.progressMu.Unlock()
+	p := s.progress
+	p.SpooledBytes = size
+	return p
+}
+
+func (s *Spooler) updateProgress(f func(*Progress)) {
+	s.progressMu.Lock()
+	defer s.progressMu.Unlock()
+	f(&s.progress)
+}
+
+// Backfill starts reporting spooled events in the background, oldest
+// first, at up to the configured rate. It returns false if a backfill
+// is already running.
+//
+// Backfilling stops at the first error, with the unreported events
+// remaining spooled; it may be started again once the output recovers.
+func (s *Spooler) Backfill() bool {
+	s.progressMu.Lock()
+	defer s.progressMu.Unlock()
+	if s.progress.Running {
+		return false
+	}
+	now := time.Now()
+	s.progress = Progress{Running: true, Started: &now}
+	go func() {
+		err := s.backfill(context.Background())
+		if err != nil {
+			s.logger.Errorw("backfill stopped", "error", err)
+		}
+		s.updateProgress(func(p *Progress) {
+			now := time.Now()
+			p.Running = false
+			p.Finished = &now
+			if err != nil {
+				p.Error = err.Error()
+			}
+		})
+	}()
+	return true
+}
+
+func (s *Spooler) backfill(ctx context.Context) error {
+	infos, err := ioutil.ReadDir(s.dir)
+	if err != nil {
+		return err
+	}
+	var names []string
+	for _, info := range infos {
+		if strings.HasSuffix(info.Name(), fileExtension) {
+			names = append(names, info.Name())
+		}
+	}
+	sort.Strings(names)
+	s.updateProgress(func(p *Progress) { p.Files = len(names) })
+
+	batchSize := maxBatchSize
+	limiter := rate.NewLimiter(rate.Inf, 0)
+	if s.rate > 0 {
+		if s.rate < batchSize {
+			batchSize = s.rate
+		}
+		limiter = rate.NewLimiter(rate.Limit(s.rate), batchSize)
+	}
+	for _, name := range names {
+		// Files are claimed by renaming them, so that each is
+		// backfilled once, even by concurrent Spoolers.
+		path := filepath.Join(s.dir, name)
+		claimed := path + replayingSuffix
+		if err := os.Rename(path, claimed); err != nil {
+			if os.IsNotExist(err) {
+				continue
+			}
+			return err
+		}
+		if err := s.replay(ctx, claimed, limiter, batchSize); err != nil {
+			return err
+		}
+		s.updateProgress(func(p *Progress) { p.FilesDone++ })
+	}
+	return nil
+}
+
+// replay reports the events spooled in path, in batches of up to
+// batchSize events, and removes the file. If reporting fails, the
+// unreported events are spooled again.
+func (s *Spooler) replay(ctx context.Context, path string, limiter *rate.Limiter, batchSize int) error {
+	data, err := ioutil.ReadFile(path)
+	if err != nil {
+		return err
+	}
+	var lines [][]byte
+	var events []beat.Event
+	for _, line := range bytes.Split(data, []byte("\n")) {
+		if len(line) == 0 {
+			continue
+		}
+		event, err := decodeEvent(line)
+		if err != nil {
+			return errors.Wrapf(err, "failed to decode %s", path)
+		}
+		lines = append(lines, line)
+		events = append(events, event)
+	}
+
+	for i := 0; i < len(events); i += batchSize {
+		end := i + batchSize
+		if end > len(events) {
+			end = len(events)
+		}
+		if err := limiter.WaitN(ctx, end-i); err != nil {
+			return s.respool(path, int64(len(data)), lines[i:], err)
+		}
+		req := publish.PendingReq{
+			Transformables: make([]transform.Transformable, end-i),
+			Tcontext:       &transform.Context{},
+		}
+		for j, event := range events[i:end] {
+			req.Transformables[j] = backfilledEvent(event)
+			req.Size += int64(len(lines[i+j]))
+		}
+		if err := s.report(ctx, req); err != nil {
+			return s.respool(path, int64(len(data)), lines[i:], err)
+		}
+		eventsBackfilled.Add(int64(end - i))
+		s.updateProgress(func(p *Progress) { p.Events += int64(end - i) })
+	}
+	return s.remove(path, int64(len(data)))
+}
+
+// respool replaces the claimed file at path with one holding the
+// remaining lines, and returns err.
+func (s *Spooler) respool(path string, size int64, lines [][]byte, err error) error {
+	if removeErr := s.remove(path, size); removeErr != nil {
+		return removeErr
+	}
+	var buf bytes.Buffer
+	for _, line := range lines {
+		buf.Write(line)
+		buf.WriteByte('\n')
+	}
+	// The remaining events were within the limit when first spooled.
+	if writeErr := s.write(buf.Bytes(), true); writeErr != nil {
+		return writeErr
+	}
+	return err
+}
+
+type spooledEvent struct {
+	Timestamp time.Time     `json:"timestamp"`
+	Meta      common.MapStr `json:"meta,omitempty"`
+	Fields    common.MapStr `json:"fields"`
+}
+
+func decodeEvent(data []byte) (beat.Event, error) {
+	var e struct {
+		Timestamp time.Time              `json:"timestamp"`
+		Meta      map[string]interface{} `json:"meta"`
+		Fields    map[string]interface{} `json:"fields"`
+	}
+	dec := json.NewDecoder(bytes.NewReader(data))
+	dec.UseNumber()
+	if err := dec.Decode(&e); err != nil {
+		return beat.Event{}, err
+	}
+	event := beat.Event{Timestamp: e.Timestamp, Fields: common.MapStr{}}
+	if e.Meta != nil {
+		event.Meta = decodeValue(e.Meta).(common.MapStr)
+	}
+	if e.Fields != nil {
+		event.Fields = decodeValue(e.Fields).(common.MapStr)
+	}
+	return event, nil
+}
+
+// decodeValue converts the numbers and objects of a decoded
+// event to the types they had when the event was spooled.
+func decodeValue(v interface{}) interface{} {
+	switch v := v.(type) {
+	case map[string]interface{}:
+		m := make(common.MapStr, len(v))
+		for k, v := range v {
+			m[k] = decodeValue(v)
+		}
+		return m
+	case []interface{}:
+		for i := range v {
+			v[i] = decodeValue(v[i])
+		}
+		return v
+	case json.Number:
+		if i, err := v.Int64(); err == nil {
+			return i
+		}
+		f, _ := v.Float64()
+		return f
+	}
+	return v
+}
+
+// backfilledEvent is a transform.Transformable for an event
+// which has already been transformed.
+type backfilledEvent beat.Event
+
+func (e backfilledEvent) Transform(context.Context, *transform.Context) []beat.Event {
+	return []beat.Event{beat.Event(e)}
+}
@@ -0,0 +1,199 @@
+// Licensed to Elasticsearch B.V. under one or more contributor
+// license agreements. See the NOTICE file distributed with
+// this work for additional information regarding copyright
+// ownership. Elasticsearch B.V. licenses this file to you under
+// the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package spool
+
+import (
+	"context"
+	"errors"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/elastic/beats/v7/libbeat/beat"
+	"github.com/elastic/beats/v7/libbeat/common"
+
+	"github.com/elastic/apm-server/beater/config"
+	"github.com/elastic/apm-server/publish"
+	"github.com/elastic/apm-server/transform"
+)
+
+type testEvent beat.Event
+
+func (e testEvent) Transform(context.Context, *transform.Context) []beat.Event {
+	return []beat.Event{beat.Event(e)}
+}
+
+func newTestEvent(i int) transform.Transformable {
+	return testEvent{
+		Timestamp: time.Unix(int64(i), 0).UTC(),
+		Meta:      common.MapStr{"pipeline": "apm"},
+		Fields: common.MapStr{
+			"processor":   common.MapStr{"event": "transaction"},
+			"transaction": common.MapStr{"duration": common.MapStr{"us": int64(i)}, "sampled": true},
+			"ratio":       0.5,
+		},
+	}
+}
+
+// testReporter records the events it is sent, or returns err if set.
+type testReporter struct {
+	mu     sync.Mutex
+	err    error
+	events []beat.Event
+}
+
+func (r *testReporter) report(ctx context.Context, req publish.PendingReq) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if r.err != nil {
+		return r.err
+	}
+	for _, t := range req.Transformables {
+		r.events = append(r.events, t.Transform(ctx, req.Tcontext)...)
+	}
+	return nil
+}
+
+func (r *testReporter) setErr(err error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.err = err
+}
+
+func newTestSpooler(t *testing.T, f func(*config.SpoolConfig)) (*Spooler, *testReporter, string) {
+	dir, err := ioutil.TempDir("", "apm-server-spool")
+	require.NoError(t, err)
+	cfg := config.DefaultConfig("").Spool
+	cfg.Path = dir
+	cfg.Rate = 0
+	f(&cfg)
+	reporter := &testReporter{}
+	s, err := New(cfg, reporter.report)
+	require.NoError(t, err)
+	return s, reporter, dir
+}
+
+func waitBackfilled(t *testing.T, s *Spooler) Progress {
+	assert.Eventually(t, func() bool { return !s.Progress().Running }, 10*time.Second, time.Millisecond)
+	return s.Progress()
+}
+
+func TestSpoolBackfill(t *testing.T) {
+	s, reporter, dir := newTestSpooler(t, func(cfg *config.SpoolConfig) {})
+	defer os.RemoveAll(dir)
+
+	reporter.setErr(publish.ErrFull)
+	var expected []beat.Event
+	for i := 0; i < 3; i++ {
+		req := publish.PendingReq{Transformables: []transform.Transformable{newTestEvent(2 * i), newTestEvent(2*i + 1)}}
+		require.NoError(t, s.Report(context.Background(), req))
+		for _, t := range req.Transformables {
+			expected = append(expected, beat.Event(t.(testEvent)))
+		}
+	}
+	assert.Empty(t, reporter.events)
+	assert.NotZero(t, s.Progress().SpooledBytes)
+
+	// Other errors are returned, and events are not spooled.
+	reporter.setErr(publish.ErrChannelClosed)
+	err := s.Report(context.Background(), publish.PendingReq{Transformables: []transform.Transformable{newTestEvent(6)}})
+	assert.Equal(t, publish.ErrChannelClosed, err)
+
+	reporter.setErr(nil)
+	require.True(t, s.Backfill())
+	progress := waitBackfilled(t, s)
+	assert.Equal(t, 3, progress.Files)
+	assert.Equal(t, 3, progress.FilesDone)
+	assert.Equal(t, int64(6), progress.Events)
+	assert.Zero(t, progress.SpooledBytes)
+	assert.Empty(t, progress.Error)
+	assert.Equal(t, expected, reporter.events)
+
+	infos, err := ioutil.ReadDir(dir)
+	require.NoError(t, err)
+	assert.Empty(t, infos)
+}
+
+func TestSpoolMaxBytes(t *testing.T) {
+	s, reporter, dir := newTestSpooler(t, func(cfg *config.SpoolConfig) { cfg.MaxBytes = 1 })
+	defer os.RemoveAll(dir)
+
+	reporter.setErr(publish.ErrMemoryFull)
+	err := s.Report(context.Background(), publish.PendingReq{Transformables: []transform.Transformable{newTestEvent(0)}})
+	assert.Equal(t, publish.ErrMemoryFull, err)
+	assert.Zero(t, s.Progress().SpooledBytes)
+}
+
+func TestSpoolBackfillError(t *testing.T) {
+	s, reporter, dir := newTestSpooler(t, func(cfg *config.SpoolConfig) { cfg.Rate = 1 })
+	defer os.RemoveAll(dir)
+
+	reporter.setErr(publish.ErrFull)
+	req := publish.PendingReq{Transformables: []transform.Transformable{newTestEvent(0), newTestEvent(1)}}
+	require.NoError(t, s.Report(context.Background(), req))
+	size := s.Progress().SpooledBytes
+
+	// The output is still unavailable: the backfill stops, and the events remain spooled.
+	reporter.setErr(errors.New("boom"))
+	require.True(t, s.Backfill())
+	progress := waitBackfilled(t, s)
+	assert.Equal(t, "boom", progress.Error)
+	assert.Equal(t, 0, progress.FilesDone)
+	assert.Equal(t, size, progress.SpooledBytes)
+
+	reporter.setErr(nil)
+	require.True(t, s.Backfill())
+	progress = waitBackfilled(t, s)
+	assert.Empty(t, progress.Error)
+	assert.Equal(t, int64(2), progress.Events)
+	assert.Len(t, reporter.events, 2)
+}
+
+func TestSpoolRecoversClaimedFiles(t *testing.T) {
+	s, reporter, dir := newTestSpooler(t, func(cfg *config.SpoolConfig) {})
+	defer os.RemoveAll(dir)
+
+	reporter.setErr(publish.ErrFull)
+	req := publish.PendingReq{Transformables: []transform.Transformable{newTestEvent(0)}}
+	require.NoError(t, s.Report(context.Background(), req))
+
+	// Simulate the server stopping while backfilling.
+	infos, err := ioutil.ReadDir(dir)
+	require.NoError(t, err)
+	require.Len(t, infos, 1)
+	path := filepath.Join(dir, infos[0].Name())
+	require.NoError(t, os.Rename(path, path+replayingSuffix))
+	require.NoError(t, ioutil.WriteFile(path+"x"+tempSuffix, []byte("partial"), 0600))
+
+	cfg := config.DefaultConfig("").Spool
+	cfg.Path = dir
+	reporter.setErr(nil)
+	s, err = New(cfg, reporter.report)
+	require.NoError(t, err)
+	require.True(t, s.Backfill())
+	progress := waitBackfilled(t, s)
+	assert.Empty(t, progress.Error)
+	assert.Equal(t, int64(1), progress.Events)
+	assert.Equal(t, []beat.Event{beat.Event(req.Transformables[0].(testEvent))}, reporter.events)
+}
@@ -0,0 +1,75 @@
+// Licensed to Elasticsearch B.V. under one or more contributor
+// license agreements. See the NOTICE file distributed with
+// this work for additional information regarding copyright
+// ownership. Elasticsearch B.V. licenses this file to you under
+// the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package status
+
+import (
+	"context"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/elastic/apm-server/beater/beatertest"
+	"github.com/elastic/apm-server/model"
+	"github.com/elastic/apm-server/publish"
+	"github.com/elastic/apm-server/transform"
+	"github.com/elastic/apm-server/utility"
+)
+
+func TestHandler(t *testing.T) {
+	tracker := NewTracker(DefaultMaxServices)
+
+	t.Run("empty", func(t *testing.T) {
+		c, w := beatertest.ContextWithResponseRecorder(http.MethodGet, "/v1/status")
+		Handler(tracker)(c)
+
+		assert.Equal(t, http.StatusOK, w.Code)
+		assert.Equal(t, `{"first_event_received":null,"services":[]}`+"\n", w.Body.String())
+	})
+
+	t.Run("events", func(t *testing.T) {
+		tracker.now = func() time.Time { return time.Unix(123, 0).UTC() }
+		report := tracker.Wrap(beatertest.NilReporter)
+		require.NoError(t, report(context.Background(), publish.PendingReq{
+			Transformables: []transform.Transformable{
+				&model.Transaction{Metadata: eventMetadata("service", "go")},
+			},
+		}))
+
+		c, w := beatertest.ContextWithResponseRecorder(http.MethodGet, "/v1/status")
+		Handler(tracker)(c)
+
+		assert.Equal(t, http.StatusOK, w.Code)
+		assert.Equal(t, `{"first_event_received":"1970-01-01T00:02:03Z","services":[`+
+			`{"name":"service","first_event_received":"1970-01-01T00:02:03Z","agents":[`+
+			`{"name":"go","first_event_received":"1970-01-01T00:02:03Z"}]}]}`+"\n", w.Body.String())
+	})
+
+	t.Run("tenant", func(t *testing.T) {
+		// Events recorded above have no tenant, and are not
+		// reported to requests authorized for a tenant.
+		c, w := beatertest.ContextWithResponseRecorder(http.MethodGet, "/v1/status")
+		c.Request = c.Request.WithContext(utility.ContextWithTenant(c.Request.Context(), "team-a"))
+		Handler(tracker)(c)
+
+		assert.Equal(t, http.StatusOK, w.Code)
+		assert.Equal(t, `{"first_event_received":null,"services":[]}`+"\n", w.Body.String())
+	})
+}
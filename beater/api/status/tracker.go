@@ -0,0 +1,199 @@
+// Licensed to Elasticsearch B.V. under one or more contributor
+// license agreements. See the NOTICE file distributed with
+// this work for additional information regarding copyright
+// ownership. Elasticsearch B.V. licenses this file to you under
+// the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package status
+
+import (
+	"context"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/hashicorp/golang-lru/simplelru"
+
+	"github.com/elastic/apm-server/model"
+	"github.com/elastic/apm-server/publish"
+	"github.com/elastic/apm-server/transform"
+)
+
+const (
+	// DefaultMaxServices is the default maximum number of services tracked.
+	DefaultMaxServices = 1000
+
+	// maxAgentsPerService is the maximum number of agents tracked per service.
+	maxAgentsPerService = 100
+)
+
+// Tracker tracks when events were first received, per tenant, service and agent.
+// The status of each tenant is reported separately, so tenants cannot learn
+// of each other's services.
+//
+// As service and agent names are controlled by agents, the number of services
+// tracked is limited, evicting the least recently seen service when the limit
+// is reached, and the number of agents tracked per service is limited.
+type Tracker struct {
+	now func() time.Time
+
+	mu       sync.Mutex
+	first    map[string]time.Time // tenant ID -> first event received
+	services *simplelru.LRU       // serviceKey -> *serviceStatus
+}
+
+type serviceKey struct {
+	tenant  string
+	service string
+}
+
+type serviceStatus struct {
+	first  time.Time
+	agents map[string]time.Time
+}
+
+// Status holds the status reported by the status API.
+type Status struct {
+	// FirstEventReceived holds the time at which the first event
+	// was received by the server, or nil if none have been received.
+	FirstEventReceived *time.Time `json:"first_event_received"`
+
+	// Services holds the status of each service from which events
+	// have been received, ordered by name.
+	Services []ServiceStatus `json:"services"`
+}
+
+// ServiceStatus holds the status of a service.
+type ServiceStatus struct {
+	Name               string        `json:"name"`
+	FirstEventReceived time.Time     `json:"first_event_received"`
+	Agents             []AgentStatus `json:"agents"`
+}
+
+// AgentStatus holds the status of an agent of a service.
+type AgentStatus struct {
+	Name               string    `json:"name"`
+	FirstEventReceived time.Time `json:"first_event_received"`
+}
+
+// NewTracker returns a new Tracker, tracking at most maxServices services.
+func NewTracker(maxServices int) *Tracker {
+	services, err := simplelru.NewLRU(maxServices, nil)
+	if err != nil {
+		// maxServices is not positive
+		panic(err)
+	}
+	return &Tracker{now: time.Now, first: make(map[string]time.Time), services: services}
+}
+
+// Wrap returns a Reporter which records the events reported to reporter.
+// Events are recorded once they have been reported successfully.
+func (t *Tracker) Wrap(reporter publish.Reporter) publish.Reporter {
+	return func(ctx context.Context, req publish.PendingReq) error {
+		if err := reporter(ctx, req); err != nil {
+			return err
+		}
+		t.record(req.Transformables)
+		return nil
+	}
+}
+
+func (t *Tracker) record(events []transform.Transformable) {
+	if len(events) == 0 {
+		return
+	}
+	now := t.now()
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	for _, event := range events {
+		if tenant, service, agent, ok := eventService(event); ok {
+			t.add(tenant, service, agent, now)
+		}
+	}
+}
+
+// add records the first event received from the given tenant's service and
+// agent, if none has been recorded before, and marks the service as recently
+// seen. The caller must hold t.mu.
+func (t *Tracker) add(tenant, service, agent string, now time.Time) {
+	if _, ok := t.first[tenant]; !ok {
+		t.first[tenant] = now
+	}
+	key := serviceKey{tenant: tenant, service: service}
+	var s *serviceStatus
+	if value, ok := t.services.Get(key); ok {
+		s = value.(*serviceStatus)
+	} else {
+		s = &serviceStatus{first: now, agents: make(map[string]time.Time)}
+		t.services.Add(key, s)
+	}
+	if _, ok := s.agents[agent]; !ok && len(s.agents) < maxAgentsPerService {
+		s.agents[agent] = now
+	}
+}
+
+// Status returns the current status of the given tenant's services. The
+// tenant is empty unless the server is configured with tenants.
+func (t *Tracker) Status(tenant string) Status {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	status := Status{Services: []ServiceStatus{}}
+	if first, ok := t.first[tenant]; ok {
+		status.FirstEventReceived = &first
+	}
+	for _, key := range t.services.Keys() {
+		key := key.(serviceKey)
+		if key.tenant != tenant {
+			continue
+		}
+		value, _ := t.services.Peek(key)
+		s := value.(*serviceStatus)
+		service := ServiceStatus{
+			Name:               key.service,
+			FirstEventReceived: s.first,
+			Agents:             make([]AgentStatus, 0, len(s.agents)),
+		}
+		for name, first := range s.agents {
+			service.Agents = append(service.Agents, AgentStatus{Name: name, FirstEventReceived: first})
+		}
+		sort.Slice(service.Agents, func(i, j int) bool {
+			return service.Agents[i].Name < service.Agents[j].Name
+		})
+		status.Services = append(status.Services, service)
+	}
+	sort.Slice(status.Services, func(i, j int) bool {
+		return status.Services[i].Name < status.Services[j].Name
+	})
+	return status
+}
+
+// eventService returns the tenant ID, and the service and agent names of event.
+func eventService(event transform.Transformable) (tenant, service, agent string, ok bool) {
+	var metadata *model.Metadata
+	switch event := event.(type) {
+	case *model.Transaction:
+		metadata = &event.Metadata
+	case *model.Span:
+		metadata = &event.Metadata
+	case *model.Error:
+		metadata = &event.Metadata
+	case *model.Metricset:
+		metadata = &event.Metadata
+	case *model.PprofProfile:
+		metadata = &event.Metadata
+	default:
+		return "", "", "", false
+	}
+	return metadata.TenantID, metadata.Service.Name, metadata.Service.Agent.Name, true
+}
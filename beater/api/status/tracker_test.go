@@ -0,0 +1,159 @@
+// Licensed to Elasticsearch B.V. under one or more contributor
+// license agreements. See the NOTICE file distributed with
+// this work for additional information regarding copyright
+// ownership. Elasticsearch B.V. licenses this file to you under
+// the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package status
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/elastic/apm-server/model"
+	"github.com/elastic/apm-server/publish"
+	"github.com/elastic/apm-server/transform"
+)
+
+func TestTrackerEmpty(t *testing.T) {
+	status := NewTracker(DefaultMaxServices).Status("")
+	assert.Nil(t, status.FirstEventReceived)
+	assert.NotNil(t, status.Services)
+	assert.Empty(t, status.Services)
+}
+
+func TestTrackerWrap(t *testing.T) {
+	t0 := time.Unix(123, 0).UTC()
+	t1 := t0.Add(time.Second)
+	tracker := NewTracker(DefaultMaxServices)
+	tracker.now = func() time.Time { return t0 }
+
+	var reportErr error
+	report := tracker.Wrap(func(context.Context, publish.PendingReq) error { return reportErr })
+	send := func(events ...transform.Transformable) error {
+		return report(context.Background(), publish.PendingReq{Transformables: events})
+	}
+
+	require.NoError(t, send(
+		&model.Transaction{Metadata: eventMetadata("service_b", "go")},
+		&model.Span{Metadata: eventMetadata("service_a", "java")},
+	))
+	tracker.now = func() time.Time { return t1 }
+	require.NoError(t, send(
+		&model.Error{Metadata: eventMetadata("service_b", "go")},
+		&model.Metricset{Metadata: eventMetadata("service_b", "python")},
+	))
+
+	// Events are only recorded once they have been reported successfully.
+	reportErr = errors.New("boom")
+	assert.Equal(t, reportErr, send(&model.Transaction{Metadata: eventMetadata("service_c", "go")}))
+
+	status := tracker.Status("")
+	require.NotNil(t, status.FirstEventReceived)
+	assert.Equal(t, t0, *status.FirstEventReceived)
+	assert.Equal(t, []ServiceStatus{{
+		Name:               "service_a",
+		FirstEventReceived: t0,
+		Agents:             []AgentStatus{{Name: "java", FirstEventReceived: t0}},
+	}, {
+		Name:               "service_b",
+		FirstEventReceived: t0,
+		Agents: []AgentStatus{
+			{Name: "go", FirstEventReceived: t0},
+			{Name: "python", FirstEventReceived: t1},
+		},
+	}}, status.Services)
+}
+
+func eventMetadata(service, agent string) model.Metadata {
+	return model.Metadata{Service: model.Service{
+		Name:  service,
+		Agent: model.Agent{Name: agent},
+	}}
+}
+
+func TestTrackerMaxServices(t *testing.T) {
+	tracker := NewTracker(2)
+	report := tracker.Wrap(func(context.Context, publish.PendingReq) error { return nil })
+	send := func(service string) {
+		require.NoError(t, report(context.Background(), publish.PendingReq{
+			Transformables: []transform.Transformable{&model.Transaction{Metadata: eventMetadata(service, "go")}},
+		}))
+	}
+	send("service_a")
+	send("service_b")
+	send("service_a")
+	send("service_c")
+
+	// service_b was the least recently seen service.
+	var names []string
+	for _, service := range tracker.Status("").Services {
+		names = append(names, service.Name)
+	}
+	assert.Equal(t, []string{"service_a", "service_c"}, names)
+}
+
+func TestTrackerMaxAgentsPerService(t *testing.T) {
+	tracker := NewTracker(DefaultMaxServices)
+	report := tracker.Wrap(func(context.Context, publish.PendingReq) error { return nil })
+	for i := 0; i < maxAgentsPerService+10; i++ {
+		require.NoError(t, report(context.Background(), publish.PendingReq{
+			Transformables: []transform.Transformable{
+				&model.Transaction{Metadata: eventMetadata("service", fmt.Sprintf("agent_%d", i))},
+			},
+		}))
+	}
+	status := tracker.Status("")
+	require.Len(t, status.Services, 1)
+	assert.Len(t, status.Services[0].Agents, maxAgentsPerService)
+}
+
+func TestTrackerTenants(t *testing.T) {
+	t0 := time.Unix(123, 0).UTC()
+	tracker := NewTracker(DefaultMaxServices)
+	tracker.now = func() time.Time { return t0 }
+	report := tracker.Wrap(func(context.Context, publish.PendingReq) error { return nil })
+	send := func(tenant, service string) {
+		metadata := eventMetadata(service, "go")
+		metadata.TenantID = tenant
+		require.NoError(t, report(context.Background(), publish.PendingReq{
+			Transformables: []transform.Transformable{&model.Transaction{Metadata: metadata}},
+		}))
+	}
+	send("team-a", "frontend")
+	send("team-a", "backend")
+	send("team-b", "frontend")
+
+	names := func(tenant string) []string {
+		var names []string
+		for _, service := range tracker.Status(tenant).Services {
+			names = append(names, service.Name)
+		}
+		return names
+	}
+	assert.Equal(t, []string{"backend", "frontend"}, names("team-a"))
+	assert.Equal(t, []string{"frontend"}, names("team-b"))
+
+	// Tenants receive no status of other tenants' services.
+	status := tracker.Status("team-c")
+	assert.Nil(t, status.FirstEventReceived)
+	assert.Empty(t, status.Services)
+	assert.Empty(t, tracker.Status("").Services)
+}
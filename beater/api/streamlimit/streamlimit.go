@@ -0,0 +1,232 @@
+// Licensed to Elasticsearch B.V. under one or more contributor
+// license agreements. See the NOTICE file distributed with
+// this work for additional information regarding copyright
+// ownership. Elasticsearch B.V. licenses this file to you under
+// the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+// Package streamlimit defends the server against clients holding intake
+// streams open while sending little or no data, such as stuck agents or
+// slow-loris attacks, which could otherwise exhaust its connection slots.
+package streamlimit
+
+import (
+	"context"
+	"io"
+	"net"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/elastic/beats/v7/libbeat/logp"
+	"github.com/elastic/beats/v7/libbeat/monitoring"
+
+	"github.com/elastic/apm-server/beater/authorization"
+	"github.com/elastic/apm-server/beater/config"
+	logs "github.com/elastic/apm-server/log"
+	"github.com/elastic/apm-server/utility"
+)
+
+var (
+	monitoringRegistry = monitoring.Default.NewRegistry("apm-server.stream_limits")
+	rejectedCounter    = monitoring.NewInt(monitoringRegistry, "rejected")
+	terminatedCounter  = monitoring.NewInt(monitoringRegistry, "terminated")
+)
+
+// Limits caps the number of concurrent streams per client, and closes the
+// connections of clients sending request bodies below a minimum throughput.
+type Limits struct {
+	maxPerClient  int
+	minThroughput int
+	window        time.Duration
+	logger        *logp.Logger
+
+	mu     sync.Mutex
+	active map[string]int
+}
+
+// New returns a new Limits for cfg, or nil if cfg enables no limits.
+func New(cfg config.StreamLimitsConfig) *Limits {
+	if cfg.MaxPerClient <= 0 && cfg.MinThroughput <= 0 {
+		return nil
+	}
+	return &Limits{
+		maxPerClient:  cfg.MaxPerClient,
+		minThroughput: cfg.MinThroughput,
+		window:        cfg.Window,
+		logger:        logp.NewLogger(logs.Handler),
+		active:        make(map[string]int),
+	}
+}
+
+// Acquire reserves a stream for the client of r, authorized by auth,
+// returning a function releasing it once the stream has ended. Acquire
+// returns false if the client already has the maximum number of streams
+// open.
+func (l *Limits) Acquire(r *http.Request, auth authorization.Authorization) (func(), bool) {
+	if l.maxPerClient <= 0 {
+		return func() {}, true
+	}
+	key := ClientKey(r, auth)
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if l.active[key] >= l.maxPerClient {
+		rejectedCounter.Inc()
+		return nil, false
+	}
+	l.active[key]++
+	return func() { l.release(key) }, true
+}
+
+func (l *Limits) release(key string) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if l.active[key] <= 1 {
+		// Forget clients without open streams, so the
+		// number of tracked clients remains bounded.
+		delete(l.active, key)
+		return
+	}
+	l.active[key]--
+}
+
+// Watch replaces the body of r with one measuring the rate at which the
+// client sends it, and closes the client's connection if that falls below
+// the minimum throughput. The returned function must be called once the
+// request has been handled.
+//
+// Only time spent waiting for the client counts towards its throughput,
+// so a handler which stops reading, e.g. due to backpressure, does not
+// cause the connection to be closed.
+func (l *Limits) Watch(r *http.Request) func() {
+	conn, ok := r.Context().Value(connContextKey{}).(net.Conn)
+	if !ok || l.minThroughput <= 0 || r.Body == nil || r.Body == http.NoBody {
+		return func() {}
+	}
+	body := &meteredBody{ReadCloser: r.Body}
+	r.Body = body
+
+	done := make(chan struct{})
+	stopped := make(chan struct{})
+	go func() {
+		defer close(stopped)
+		ticker := time.NewTicker(l.window)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-done:
+				return
+			case now := <-ticker.C:
+				if body.sufficient(now, l.minThroughput, l.window) {
+					continue
+				}
+				terminatedCounter.Inc()
+				l.logger.Warnw("closing connection of client sending request body too slowly",
+					"remote_address", utility.RemoteAddr(r),
+					"min_throughput", l.minThroughput,
+				)
+				conn.Close()
+				return
+			}
+		}
+	}()
+	return func() {
+		close(done)
+		<-stopped
+	}
+}
+
+// ClientKey returns a key identifying the client of r by the ID of the
+// API Key it was authorized with, if any, and otherwise by its IP address.
+//
+// auth must be the authorization of r, after r has been authorized;
+// unverified credentials are never used, as any client could otherwise
+// evade the limits by sending arbitrary Authorization headers.
+func ClientKey(r *http.Request, auth authorization.Authorization) string {
+	if auth, ok := auth.(apiKeyAuthorization); ok {
+		if id := auth.APIKeyID(); id != "" {
+			return "api_key:" + id
+		}
+	}
+	return "ip:" + utility.RemoteAddr(r)
+}
+
+// apiKeyAuthorization is implemented by the authorization
+// of requests authorized with an API Key.
+type apiKeyAuthorization interface {
+	APIKeyID() string
+}
+
+type connContextKey struct{}
+
+// ConnContext returns a copy of ctx carrying conn, so it can be closed if
+// its client sends request bodies too slowly. ConnContext has the signature
+// of http.Server.ConnContext.
+func ConnContext(ctx context.Context, conn net.Conn) context.Context {
+	return context.WithValue(ctx, connContextKey{}, conn)
+}
+
+// meteredBody records the number of bytes read from a request body,
+// and the time spent waiting for them.
+type meteredBody struct {
+	io.ReadCloser
+
+	mu        sync.Mutex
+	n         int64
+	waited    time.Duration
+	readStart time.Time // zero unless a read is in progress
+	done      bool
+}
+
+func (b *meteredBody) Read(p []byte) (int, error) {
+	b.mu.Lock()
+	b.readStart = time.Now()
+	b.mu.Unlock()
+
+	n, err := b.ReadCloser.Read(p)
+
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.waited += time.Since(b.readStart)
+	b.readStart = time.Time{}
+	b.n += int64(n)
+	if err != nil {
+		b.done = true
+	}
+	return n, err
+}
+
+// sufficient reports whether the body has been sent with at least
+// minThroughput bytes per second since the last measurement. A new
+// measurement starts once at least window has been spent waiting.
+func (b *meteredBody) sufficient(now time.Time, minThroughput int, window time.Duration) bool {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if b.done {
+		return true
+	}
+	waited := b.waited
+	if !b.readStart.IsZero() {
+		waited += now.Sub(b.readStart)
+		b.readStart = now
+	}
+	if waited < window {
+		// Too little time has been spent waiting for the
+		// client to judge its throughput, so carry it over.
+		b.waited = waited
+		return true
+	}
+	ok := float64(b.n) >= float64(minThroughput)*waited.Seconds()
+	b.n, b.waited = 0, 0
+	return ok
+}
@@ -0,0 +1,199 @@
+// Licensed to Elasticsearch B.V. under one or more contributor
+// license agreements. See the NOTICE file distributed with
+// this work for additional information regarding copyright
+// ownership. Elasticsearch B.V. licenses this file to you under
+// the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package streamlimit
+
+import (
+	"context"
+	"errors"
+	"io"
+	"io/ioutil"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/elastic/apm-server/beater/authorization"
+	"github.com/elastic/apm-server/beater/config"
+	"github.com/elastic/apm-server/beater/headers"
+	"github.com/elastic/apm-server/elasticsearch"
+)
+
+func TestNew(t *testing.T) {
+	cfg := config.DefaultConfig("7.0.0").StreamLimits
+	assert.Nil(t, New(cfg))
+
+	cfg.MaxPerClient = 1
+	assert.NotNil(t, New(cfg))
+}
+
+func TestLimitsAcquire(t *testing.T) {
+	limits := New(config.StreamLimitsConfig{MaxPerClient: 2, Window: time.Second})
+	newRequest := func(remoteAddr string) *http.Request {
+		r := httptest.NewRequest(http.MethodPost, "/", nil)
+		r.RemoteAddr = remoteAddr
+		return r
+	}
+	allow := &authorization.AllowAuth{}
+
+	release1, ok := limits.Acquire(newRequest("10.0.0.1:1234"), allow)
+	require.True(t, ok)
+	release2, ok := limits.Acquire(newRequest("10.0.0.1:5678"), allow)
+	require.True(t, ok)
+	_, ok = limits.Acquire(newRequest("10.0.0.1:1234"), allow)
+	assert.False(t, ok)
+
+	// Other clients are limited independently,
+	// whether by their address or API Key.
+	release3, ok := limits.Acquire(newRequest("10.0.0.2:1234"), allow)
+	require.True(t, ok)
+	release4, ok := limits.Acquire(newRequest("10.0.0.1:1234"), apiKeyAuth("key1"))
+	require.True(t, ok)
+
+	release1()
+	release5, ok := limits.Acquire(newRequest("10.0.0.1:1234"), allow)
+	require.True(t, ok)
+
+	for _, release := range []func(){release2, release3, release4, release5} {
+		release()
+	}
+	assert.Empty(t, limits.active)
+}
+
+func TestClientKey(t *testing.T) {
+	r := httptest.NewRequest(http.MethodPost, "/", nil)
+	r.RemoteAddr = "10.0.0.1:1234"
+	assert.Equal(t, "ip:10.0.0.1", ClientKey(r, &authorization.AllowAuth{}))
+	assert.Equal(t, "api_key:key1", ClientKey(r, apiKeyAuth("key1")))
+
+	// Unverified credentials are never used to identify the client.
+	r.Header.Set(headers.Authorization, "Bearer abc123")
+	assert.Equal(t, "ip:10.0.0.1", ClientKey(r, &authorization.AllowAuth{}))
+}
+
+// apiKeyAuth is an authorization.Authorization of a request authorized
+// with the API Key of the given ID.
+type apiKeyAuth string
+
+func (a apiKeyAuth) AuthorizedFor(context.Context, elasticsearch.Resource) (bool, error) {
+	return true, nil
+}
+
+func (a apiKeyAuth) IsAuthorizationConfigured() bool { return true }
+
+func (a apiKeyAuth) APIKeyID() string { return string(a) }
+
+func TestLimitsWatchSlowClient(t *testing.T) {
+	limits := New(config.StreamLimitsConfig{MinThroughput: 1024, Window: 20 * time.Millisecond})
+	pr, pw := io.Pipe()
+	defer pw.Close()
+	conn := &closeConn{closed: make(chan struct{}), onClose: func() {
+		pw.CloseWithError(errors.New("connection closed"))
+	}}
+	r := watchedRequest(pr, conn)
+
+	stop := limits.Watch(r)
+	defer stop()
+
+	// The client trickles a byte at a time,
+	// well below the minimum throughput.
+	go func() {
+		for {
+			if _, err := pw.Write([]byte("x")); err != nil {
+				return
+			}
+			time.Sleep(5 * time.Millisecond)
+		}
+	}()
+	_, err := ioutil.ReadAll(r.Body)
+	assert.EqualError(t, err, "connection closed")
+	select {
+	case <-conn.closed:
+	case <-time.After(time.Second):
+		t.Fatal("connection not closed")
+	}
+}
+
+func TestLimitsWatchHandlerNotReading(t *testing.T) {
+	limits := New(config.StreamLimitsConfig{MinThroughput: 1024, Window: 10 * time.Millisecond})
+	pr, pw := io.Pipe()
+	defer pw.Close()
+	conn := &closeConn{closed: make(chan struct{})}
+	r := watchedRequest(pr, conn)
+
+	// Time spent not reading the body, e.g. due to backpressure,
+	// does not count against the client's throughput.
+	stop := limits.Watch(r)
+	time.Sleep(100 * time.Millisecond)
+	stop()
+	select {
+	case <-conn.closed:
+		t.Fatal("connection closed unexpectedly")
+	default:
+	}
+}
+
+func TestLimitsWatchFastClient(t *testing.T) {
+	limits := New(config.StreamLimitsConfig{MinThroughput: 1024, Window: 10 * time.Millisecond})
+	pr, pw := io.Pipe()
+	conn := &closeConn{closed: make(chan struct{})}
+	r := watchedRequest(pr, conn)
+
+	stop := limits.Watch(r)
+	go func() {
+		defer pw.Close()
+		for i := 0; i < 50; i++ {
+			pw.Write(make([]byte, 1024))
+			time.Sleep(time.Millisecond)
+		}
+	}()
+	_, err := ioutil.ReadAll(r.Body)
+	assert.NoError(t, err)
+	stop()
+	select {
+	case <-conn.closed:
+		t.Fatal("connection closed unexpectedly")
+	default:
+	}
+}
+
+func watchedRequest(body io.Reader, conn net.Conn) *http.Request {
+	r := httptest.NewRequest(http.MethodPost, "/", body)
+	return r.WithContext(ConnContext(r.Context(), conn))
+}
+
+type closeConn struct {
+	net.Conn
+	once    sync.Once
+	closed  chan struct{}
+	onClose func()
+}
+
+func (c *closeConn) Close() error {
+	c.once.Do(func() {
+		close(c.closed)
+		if c.onClose != nil {
+			c.onClose()
+		}
+	})
+	return nil
+}
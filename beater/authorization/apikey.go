@@ -23,6 +23,7 @@ import (
 	"net/http"
 	"time"
 
+	"github.com/elastic/apm-server/beater/api/ratelimit"
 	es "github.com/elastic/apm-server/elasticsearch"
 )
 
@@ -59,6 +60,17 @@ func (a *apikeyBuilder) forKey(key string) *apikeyAuth {
 }
 
 // IsAuthorizationConfigured will return true if a non-empty token is required.
+// APIKeyID returns the ID of the API Key.
+func (a *apikeyAuth) APIKeyID() string {
+	return apiKeyID(a.key)
+}
+
+// Allowance describes the limit on API keys whose privileges are queried
+// from Elasticsearch per minute, configured by apm-server.api_key.limit.
+func (a *apikeyAuth) Allowance(now time.Time) ratelimit.Allowance {
+	return a.cache.allowance(now)
+}
+
 func (a *apikeyAuth) IsAuthorizationConfigured() bool {
 	return true
 }
@@ -26,6 +26,9 @@ import (
 
 type bearerBuilder struct {
 	required string
+	// tenantTokens holds the secret tokens of tenants,
+	// accepted in addition to required.
+	tenantTokens []string
 }
 
 type bearerAuth struct {
@@ -34,12 +37,16 @@ type bearerAuth struct {
 }
 
 func (b bearerBuilder) forToken(token string) *bearerAuth {
-	if b.required == "" {
+	if b.required == "" && len(b.tenantTokens) == 0 {
 		return &bearerAuth{authorized: true, configured: false}
 	}
-	return &bearerAuth{
-		authorized: subtle.ConstantTimeCompare([]byte(b.required), []byte(token)) == 1,
-		configured: true}
+	authorized := b.required != "" && subtle.ConstantTimeCompare([]byte(b.required), []byte(token)) == 1
+	for _, tenantToken := range b.tenantTokens {
+		if subtle.ConstantTimeCompare([]byte(tenantToken), []byte(token)) == 1 {
+			authorized = true
+		}
+	}
+	return &bearerAuth{authorized: authorized, configured: true}
 }
 
 func (b *bearerAuth) AuthorizedFor(context.Context, elasticsearch.Resource) (bool, error) {
@@ -32,9 +32,15 @@ func TestBearerAuth(t *testing.T) {
 	}{
 		"empty":           {builder: bearerBuilder{}, authorized: true, configured: false},
 		"empty for token": {builder: bearerBuilder{}, authorized: true, configured: false, token: "1"},
-		"no token":        {builder: bearerBuilder{"123"}, authorized: false, configured: true},
-		"invalid token":   {builder: bearerBuilder{"123"}, authorized: false, configured: true, token: "1"},
-		"valid token":     {builder: bearerBuilder{"123"}, authorized: true, configured: true, token: "123"},
+		"no token":        {builder: bearerBuilder{required: "123"}, authorized: false, configured: true},
+		"invalid token":   {builder: bearerBuilder{required: "123"}, authorized: false, configured: true, token: "1"},
+		"valid token":     {builder: bearerBuilder{required: "123"}, authorized: true, configured: true, token: "123"},
+		"tenant token": {builder: bearerBuilder{required: "123", tenantTokens: []string{"456"}},
+			authorized: true, configured: true, token: "456"},
+		"tenant token only": {builder: bearerBuilder{tenantTokens: []string{"456"}},
+			authorized: true, configured: true, token: "456"},
+		"invalid tenant token": {builder: bearerBuilder{tenantTokens: []string{"456"}},
+			authorized: false, configured: true, token: "123"},
 	} {
 		t.Run(name, func(t *testing.T) {
 			bearer := tc.builder.forToken(tc.token)
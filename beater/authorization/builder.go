@@ -30,6 +30,7 @@ import (
 type Builder struct {
 	apikey   *apikeyBuilder
 	bearer   *bearerBuilder
+	tenants  *tenants
 	fallback Authorization
 }
 
@@ -66,8 +67,13 @@ func NewBuilder(cfg *config.Config) (*Builder, error) {
 		b.apikey = newApikeyBuilder(client, cache, []elasticsearch.PrivilegeAction{})
 		b.fallback = DenyAuth{}
 	}
-	if cfg.SecretToken != "" {
-		b.bearer = &bearerBuilder{cfg.SecretToken}
+	var tenantTokens []string
+	if cfg.Tenancy.Enabled {
+		b.tenants = newTenants(cfg.Tenancy)
+		tenantTokens = b.tenants.secretTokens()
+	}
+	if cfg.SecretToken != "" || len(tenantTokens) > 0 {
+		b.bearer = &bearerBuilder{required: cfg.SecretToken, tenantTokens: tenantTokens}
 		b.fallback = DenyAuth{}
 	}
 	return &b, nil
@@ -80,7 +86,7 @@ func (b *Builder) ForPrivilege(privilege elasticsearch.PrivilegeAction) *Handler
 
 // ForAnyOfPrivileges creates an authorization Handler checking for any of the provided privileges
 func (b *Builder) ForAnyOfPrivileges(privileges ...elasticsearch.PrivilegeAction) *Handler {
-	handler := Handler{bearer: b.bearer, fallback: b.fallback}
+	handler := Handler{bearer: b.bearer, tenants: b.tenants, fallback: b.fallback}
 	if b.apikey != nil {
 		handler.apikey = newApikeyBuilder(b.apikey.esClient, b.apikey.cache, privileges)
 	}
@@ -104,3 +110,13 @@ func (h *Handler) AuthorizationFor(kind string, token string) Authorization {
 		return h.fallback
 	}
 }
+
+// TenantFor returns the ID of the tenant the given credentials belong to,
+// or an empty string if multi-tenant mode is disabled or the credentials
+// do not belong to a tenant. The credentials must have been authorized.
+func (h *Handler) TenantFor(kind string, token string) string {
+	if h.tenants == nil {
+		return ""
+	}
+	return h.tenants.forCredentials(kind, token)
+}
@@ -35,9 +35,9 @@ func TestBuilder(t *testing.T) {
 		fallback               Authorization
 	}{
 		"no auth": {fallback: AllowAuth{}},
-		"bearer":  {withBearer: true, fallback: DenyAuth{}, bearer: &bearerBuilder{"xvz"}},
+		"bearer":  {withBearer: true, fallback: DenyAuth{}, bearer: &bearerBuilder{required: "xvz"}},
 		"apikey":  {withApikey: true, fallback: DenyAuth{}},
-		"all":     {withApikey: true, withBearer: true, fallback: DenyAuth{}, bearer: &bearerBuilder{"xvz"}},
+		"all":     {withApikey: true, withBearer: true, fallback: DenyAuth{}, bearer: &bearerBuilder{required: "xvz"}},
 	} {
 
 		setup := func() *Builder {
@@ -20,6 +20,7 @@ package authorization
 import (
 	"time"
 
+	"github.com/elastic/apm-server/beater/api/ratelimit"
 	es "github.com/elastic/apm-server/elasticsearch"
 
 	"github.com/patrickmn/go-cache"
@@ -54,6 +55,21 @@ func (c *privilegesCache) isFull() bool {
 	return c.cache.ItemCount() >= c.size
 }
 
+// allowance returns the number of further privileges which may be cached
+// at time now, and the time until all cached privileges have expired.
+func (c *privilegesCache) allowance(now time.Time) ratelimit.Allowance {
+	a := ratelimit.Allowance{Limit: c.size}
+	if remaining := c.size - c.cache.ItemCount(); remaining > 0 {
+		a.Remaining = remaining
+	}
+	for _, item := range c.cache.Items() {
+		if reset := time.Unix(0, item.Expiration).Sub(now); reset > a.Reset {
+			a.Reset = reset
+		}
+	}
+	return a
+}
+
 func (c *privilegesCache) get(id string) es.Permissions {
 	if val, exists := c.cache.Get(id); exists {
 		return val.(es.Permissions)
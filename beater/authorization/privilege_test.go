@@ -21,6 +21,7 @@ import (
 	"testing"
 	"time"
 
+	"github.com/elastic/apm-server/beater/api/ratelimit"
 	"github.com/elastic/apm-server/elasticsearch"
 
 	"github.com/stretchr/testify/assert"
@@ -45,3 +46,19 @@ func TestPrivilegesCache(t *testing.T) {
 	assert.Equal(t, p, cache.get("id1"))
 	assert.Nil(t, cache.get("oneMore"))
 }
+
+func TestPrivilegesCacheAllowance(t *testing.T) {
+	cache := newPrivilegesCache(time.Minute, 2)
+	now := time.Now()
+	assert.Equal(t, ratelimit.Allowance{Limit: 2, Remaining: 2}, cache.allowance(now))
+
+	cache.add("id1", elasticsearch.Permissions{})
+	a := cache.allowance(now)
+	assert.Equal(t, 2, a.Limit)
+	assert.Equal(t, 1, a.Remaining)
+	assert.InDelta(t, time.Minute, a.Reset, float64(time.Second))
+
+	cache.add("id2", elasticsearch.Permissions{})
+	assert.Equal(t, 0, cache.allowance(now).Remaining)
+	assert.Equal(t, time.Duration(0), cache.allowance(now.Add(2*time.Minute)).Reset)
+}
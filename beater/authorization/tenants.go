@@ -0,0 +1,88 @@
+// Licensed to Elasticsearch B.V. under one or more contributor
+// license agreements. See the NOTICE file distributed with
+// this work for additional information regarding copyright
+// ownership. Elasticsearch B.V. licenses this file to you under
+// the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package authorization
+
+import (
+	"crypto/subtle"
+	"encoding/base64"
+	"strings"
+
+	"github.com/elastic/apm-server/beater/config"
+	"github.com/elastic/apm-server/beater/headers"
+)
+
+// tenants maps credentials to the tenants they belong to.
+type tenants struct {
+	tokens    []tenantToken
+	apiKeyIDs map[string]string
+}
+
+type tenantToken struct {
+	token  string
+	tenant string
+}
+
+func newTenants(cfg config.TenancyConfig) *tenants {
+	t := tenants{apiKeyIDs: make(map[string]string)}
+	for _, tenant := range cfg.Tenants {
+		for _, token := range tenant.SecretTokens {
+			t.tokens = append(t.tokens, tenantToken{token: token, tenant: tenant.ID})
+		}
+		for _, id := range tenant.APIKeyIDs {
+			t.apiKeyIDs[id] = tenant.ID
+		}
+	}
+	return &t
+}
+
+func (t *tenants) secretTokens() []string {
+	tokens := make([]string, len(t.tokens))
+	for i, token := range t.tokens {
+		tokens[i] = token.token
+	}
+	return tokens
+}
+
+func (t *tenants) forCredentials(kind, token string) string {
+	switch kind {
+	case headers.Bearer:
+		var tenant string
+		for _, tt := range t.tokens {
+			if subtle.ConstantTimeCompare([]byte(tt.token), []byte(token)) == 1 {
+				tenant = tt.tenant
+			}
+		}
+		return tenant
+	case headers.APIKey:
+		return t.apiKeyIDs[apiKeyID(token)]
+	}
+	return ""
+}
+
+// apiKeyID returns the ID of the API Key given as base64(id:api_key).
+func apiKeyID(token string) string {
+	decoded, err := base64.StdEncoding.DecodeString(token)
+	if err != nil {
+		return ""
+	}
+	id := string(decoded)
+	if i := strings.IndexRune(id, ':'); i >= 0 {
+		return id[:i]
+	}
+	return ""
+}
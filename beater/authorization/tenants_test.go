@@ -0,0 +1,84 @@
+// Licensed to Elasticsearch B.V. under one or more contributor
+// license agreements. See the NOTICE file distributed with
+// this work for additional information regarding copyright
+// ownership. Elasticsearch B.V. licenses this file to you under
+// the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package authorization
+
+import (
+	"context"
+	"encoding/base64"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/elastic/apm-server/beater/config"
+	"github.com/elastic/apm-server/beater/headers"
+)
+
+func TestTenants(t *testing.T) {
+	tenants := newTenants(config.TenancyConfig{
+		Enabled: true,
+		Tenants: []config.TenantConfig{
+			{ID: "team-a", SecretTokens: []string{"abc"}, APIKeyIDs: []string{"key-a"}},
+			{ID: "team-b", SecretTokens: []string{"def", "ghi"}},
+		},
+	})
+	assert.Equal(t, []string{"abc", "def", "ghi"}, tenants.secretTokens())
+
+	apiKey := func(id string) string {
+		return base64.StdEncoding.EncodeToString([]byte(id + ":secret"))
+	}
+	for name, tc := range map[string]struct {
+		kind, token, tenant string
+	}{
+		"bearer":             {kind: headers.Bearer, token: "abc", tenant: "team-a"},
+		"bearer second":      {kind: headers.Bearer, token: "ghi", tenant: "team-b"},
+		"bearer unknown":     {kind: headers.Bearer, token: "xyz"},
+		"api key":            {kind: headers.APIKey, token: apiKey("key-a"), tenant: "team-a"},
+		"api key unknown":    {kind: headers.APIKey, token: apiKey("key-b")},
+		"api key malformed":  {kind: headers.APIKey, token: "not base64"},
+		"api key without id": {kind: headers.APIKey, token: base64.StdEncoding.EncodeToString([]byte("key-a"))},
+		"unknown kind":       {kind: "Basic", token: "abc"},
+	} {
+		t.Run(name, func(t *testing.T) {
+			assert.Equal(t, tc.tenant, tenants.forCredentials(tc.kind, tc.token))
+		})
+	}
+}
+
+func TestHandlerTenantFor(t *testing.T) {
+	cfg := &config.Config{}
+	builder, err := NewBuilder(cfg)
+	assert.NoError(t, err)
+	assert.Equal(t, "", builder.ForPrivilege(ActionAny).TenantFor(headers.Bearer, "abc"))
+
+	cfg.Tenancy = config.TenancyConfig{
+		Enabled: true,
+		Tenants: []config.TenantConfig{{ID: "team-a", SecretTokens: []string{"abc"}}},
+	}
+	builder, err = NewBuilder(cfg)
+	assert.NoError(t, err)
+	handler := builder.ForPrivilege(ActionAny)
+	assert.Equal(t, "team-a", handler.TenantFor(headers.Bearer, "abc"))
+
+	// tenant tokens are accepted even without apm-server.secret_token
+	authorized, err := handler.AuthorizationFor(headers.Bearer, "abc").AuthorizedFor(context.Background(), ResourceInternal)
+	assert.NoError(t, err)
+	assert.True(t, authorized)
+	authorized, err = handler.AuthorizationFor(headers.Bearer, "xyz").AuthorizedFor(context.Background(), ResourceInternal)
+	assert.NoError(t, err)
+	assert.False(t, authorized)
+}
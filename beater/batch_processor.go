@@ -0,0 +1,69 @@
+// Licensed to Elasticsearch B.V. under one or more contributor
+// license agreements. See the NOTICE file distributed with
+// this work for additional information regarding copyright
+// ownership. Elasticsearch B.V. licenses this file to you under
+// the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package beater
+
+import (
+	"context"
+
+	"github.com/elastic/apm-server/model"
+	"github.com/elastic/apm-server/publish"
+	"github.com/elastic/apm-server/transform"
+)
+
+// runServerWithBatchProcessor wraps runServer such that all transactions,
+// spans, metricsets and errors reported by the server are passed through
+// processor before being reported. Other events are reported unchanged.
+func runServerWithBatchProcessor(runServer RunServerFunc, processor model.BatchProcessor) RunServerFunc {
+	return func(ctx context.Context, args ServerParams) error {
+		args.Reporter = newBatchProcessorReporter(processor, args.Reporter)
+		return runServer(ctx, args)
+	}
+}
+
+// newBatchProcessorReporter returns a publish.Reporter which passes events
+// through processor before deferring to reporter.
+//
+// The returned publish.Reporter does not guarantee order preservation of
+// reported events.
+func newBatchProcessorReporter(processor model.BatchProcessor, reporter publish.Reporter) publish.Reporter {
+	return func(ctx context.Context, req publish.PendingReq) error {
+		var batch model.Batch
+		var other []transform.Transformable
+		for _, tf := range req.Transformables {
+			switch event := tf.(type) {
+			case *model.Transaction:
+				batch.Transactions = append(batch.Transactions, event)
+			case *model.Span:
+				batch.Spans = append(batch.Spans, event)
+			case *model.Metricset:
+				batch.Metricsets = append(batch.Metricsets, event)
+			case *model.Error:
+				batch.Errors = append(batch.Errors, event)
+			default:
+				other = append(other, tf)
+			}
+		}
+		if batch.Len() > 0 {
+			if err := processor.Process(ctx, &batch); err != nil {
+				return err
+			}
+		}
+		req.Transformables = append(batch.Transformables(), other...)
+		return reporter(ctx, req)
+	}
+}
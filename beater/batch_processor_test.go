@@ -0,0 +1,71 @@
+// Licensed to Elasticsearch B.V. under one or more contributor
+// license agreements. See the NOTICE file distributed with
+// this work for additional information regarding copyright
+// ownership. Elasticsearch B.V. licenses this file to you under
+// the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package beater
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/elastic/apm-server/model"
+	"github.com/elastic/apm-server/publish"
+	"github.com/elastic/apm-server/transform"
+)
+
+func TestBatchProcessorReporter(t *testing.T) {
+	// Drop spans, and enrich transactions.
+	processor := model.ProcessBatchFunc(func(ctx context.Context, b *model.Batch) error {
+		b.Spans = nil
+		for _, tx := range b.Transactions {
+			tx.Type = "enriched"
+		}
+		return nil
+	})
+
+	var reported []transform.Transformable
+	reporter := newBatchProcessorReporter(processor, func(ctx context.Context, req publish.PendingReq) error {
+		reported = req.Transformables
+		return nil
+	})
+
+	tx := &model.Transaction{Type: "request"}
+	profile := &model.PprofProfile{}
+	err := reporter(context.Background(), publish.PendingReq{
+		Transformables: []transform.Transformable{&model.Span{}, profile, tx, &model.Span{}},
+	})
+	require.NoError(t, err)
+	assert.Equal(t, []transform.Transformable{tx, profile}, reported)
+	assert.Equal(t, "enriched", tx.Type)
+}
+
+func TestBatchProcessorReporterError(t *testing.T) {
+	processorErr := errors.New("processor failed")
+	processor := model.ProcessBatchFunc(func(ctx context.Context, b *model.Batch) error {
+		return processorErr
+	})
+	reporter := newBatchProcessorReporter(processor, func(ctx context.Context, req publish.PendingReq) error {
+		panic("unexpected call")
+	})
+	err := reporter(context.Background(), publish.PendingReq{
+		Transformables: []transform.Transformable{&model.Error{}},
+	})
+	assert.Equal(t, processorErr, err)
+}
@@ -34,9 +34,13 @@ import (
 
 	"github.com/elastic/apm-server/beater/config"
 	"github.com/elastic/apm-server/ingest/pipeline"
+	"github.com/elastic/apm-server/labels"
 	logs "github.com/elastic/apm-server/log"
+	"github.com/elastic/apm-server/model"
 	"github.com/elastic/apm-server/publish"
+	"github.com/elastic/apm-server/quota"
 	"github.com/elastic/apm-server/sampling"
+	"github.com/elastic/apm-server/stacktrace"
 )
 
 var (
@@ -50,6 +54,15 @@ type CreatorParams struct {
 	// WrapRunServer is optional. If provided, it must return a function that calls
 	// its input, possibly modifying the parameters on the way in.
 	WrapRunServer func(RunServerFunc) RunServerFunc
+
+	// BatchProcessors holds processors for enriching or filtering events,
+	// enabling downstream builds to extend the event pipeline without
+	// modifying the server.
+	//
+	// BatchProcessors is optional. If provided, the processors are called
+	// in order for each batch of transactions, spans, metricsets and errors
+	// reported, before the batch is aggregated or published.
+	BatchProcessors []model.BatchProcessor
 }
 
 // NewCreator returns a new beat.Creator which creates beaters
@@ -71,10 +84,11 @@ func NewCreator(args CreatorParams) beat.Creator {
 		}
 
 		bt := &beater{
-			config:        beaterConfig,
-			stopped:       false,
-			logger:        logger,
-			wrapRunServer: args.WrapRunServer,
+			config:          beaterConfig,
+			stopped:         false,
+			logger:          logger,
+			wrapRunServer:   args.WrapRunServer,
+			batchProcessors: args.BatchProcessors,
 		}
 
 		// setup pipelines if explicitly directed to or setup --pipelines and config is not set at all
@@ -89,6 +103,17 @@ func NewCreator(args CreatorParams) beat.Creator {
 		} else {
 			logger.Info("No pipeline callback registered")
 		}
+
+		bt.readiness = newReadiness(logger, isElasticsearchOutput(b))
+		if isElasticsearchOutput(b) && !b.InSetupCmd {
+			// readiness is delayed until the first successful connection to Elasticsearch.
+			if _, err := elasticsearch.RegisterConnectCallback(func(*eslegclient.Connection) error {
+				bt.readiness.setConnected()
+				return nil
+			}); err != nil {
+				return nil, err
+			}
+		}
 		return bt, nil
 	}
 }
@@ -118,9 +143,11 @@ func checkConfig(logger *logp.Logger) error {
 }
 
 type beater struct {
-	config        *config.Config
-	logger        *logp.Logger
-	wrapRunServer func(RunServerFunc) RunServerFunc
+	config          *config.Config
+	logger          *logp.Logger
+	wrapRunServer   func(RunServerFunc) RunServerFunc
+	batchProcessors []model.BatchProcessor
+	readiness       *readiness
 
 	mutex      sync.Mutex // guards stopServer and stopped
 	stopServer func()
@@ -140,6 +167,11 @@ func (bt *beater) Run(b *beat.Beat) error {
 	if tracerServer != nil {
 		runServer = runServerWithTracerServer(runServer, tracerServer, tracer)
 	}
+	if len(bt.batchProcessors) > 0 {
+		// Wrap runServer before any wrapRunServer, so events pass
+		// through the processors before being aggregated.
+		runServer = runServerWithBatchProcessor(runServer, model.BatchProcessors(bt.batchProcessors))
+	}
 	if bt.wrapRunServer != nil {
 		// Wrap runServer function, enabling injection of
 		// behaviour into the processing/reporting pipeline.
@@ -147,22 +179,80 @@ func (bt *beater) Run(b *beat.Beat) error {
 	}
 
 	publisher, err := publish.NewPublisher(b.Publisher, tracer, &publish.PublisherConfig{
-		Info:            b.Info,
-		ShutdownTimeout: bt.config.ShutdownTimeout,
-		Pipeline:        bt.config.Pipeline,
+		Info:             b.Info,
+		ShutdownTimeout:  bt.config.ShutdownTimeout,
+		Pipeline:         bt.config.Pipeline,
+		MaxInflightBytes: bt.config.MaxUnpublishedBytes,
 	})
 	if err != nil {
 		return err
 	}
 	defer publisher.Stop()
 
-	reporter := publisher.Send
+	sanitizer := labels.NewSanitizer(labels.Config{
+		MaxKeysPerService: bt.config.Labels.MaxKeysPerService,
+	})
+	reporter := sanitizer.Wrap(publisher.Send)
+	if cfg := bt.config.SpanStacktrace; cfg.MinDuration > 0 || cfg.MaxFrames > 0 {
+		reporter = stacktrace.NewSpanReporter(stacktrace.Config{
+			MinSpanDuration: cfg.MinDuration,
+			MaxFrames:       cfg.MaxFrames,
+		}, reporter)
+	}
+	if cfg := bt.config.PublishLimit; cfg.Enabled {
+		limiter := publish.NewAdaptiveLimiter(publish.AdaptiveLimiterConfig{
+			Min:              cfg.Min,
+			Max:              cfg.Max,
+			Initial:          cfg.Initial,
+			LatencyThreshold: cfg.LatencyThreshold,
+			BackoffRatio:     cfg.BackoffRatio,
+			MaxWait:          cfg.MaxWait,
+		})
+		reporter = limiter.Wrap(reporter)
+	}
+	var backpressure *publish.IntakeBackpressure
+	if cfg := bt.config.OutputHealth; cfg.Enabled {
+		// Reports wait for the cluster to recover before
+		// acquiring a slot from the publish limiter.
+		if backpressure, err = newIntakeBackpressure(cfg); err != nil {
+			return err
+		}
+		reporter = backpressure.Wrap(reporter)
+	}
+	if cfg := bt.config.Quota; cfg.Enabled {
+		reporter = quota.NewEnforcer(quotaConfig(cfg)).Wrap(reporter)
+	}
+	samplingPolicies := newSamplingPolicies(bt.config.Sampling)
+	var tailSampler *sampling.TailSampler
+	if cfg := bt.config.Sampling.Tail; cfg.Enabled {
+		// Traces are tail-sampled after any head-based sampling
+		// decisions have been made, and unsampled transactions
+		// discarded.
+		tailSampler = sampling.NewTailSampler(tailSamplerConfig(cfg, samplingPolicies.tail))
+		samplingPolicies.tailSampler = tailSampler
+		reporter = tailSampler.Wrap(reporter)
+	}
 	if !bt.config.Sampling.KeepUnsampled {
 		// The server has been configured to discard unsampled
 		// transactions. Make sure this is done just before calling
 		// the publisher to avoid affecting aggregations.
 		reporter = sampling.NewDiscardUnsampledReporter(reporter)
 	}
+	if samplingPolicies.hasTransactionRules() {
+		// Sample transactions matching rules on transaction type or
+		// name before unsampled transactions are discarded.
+		reporter = sampling.NewTransactionSamplingReporter(samplingPolicies.transactionRate, reporter)
+	}
+
+	if tailSampler != nil {
+		runServer = runServerWithTailSampler(runServer, tailSampler)
+	}
+	if samplingPolicies.file != nil {
+		runServer = runServerWithSamplingPolicies(runServer, samplingPolicies)
+	}
+	if backpressure != nil {
+		runServer = runServerWithIntakeBackpressure(runServer, backpressure)
+	}
 
 	stopped := make(chan struct{})
 	defer close(stopped)
@@ -185,13 +275,58 @@ func (bt *beater) Run(b *beat.Beat) error {
 	bt.mutex.Unlock()
 
 	return runServer(ctx, ServerParams{
-		Config:   bt.config,
-		Logger:   bt.logger,
-		Tracer:   tracer,
-		Reporter: reporter,
+		Config:         bt.config,
+		Logger:         bt.logger,
+		Tracer:         tracer,
+		Reporter:       reporter,
+		Listening:      bt.readiness.setListening,
+		SamplingRate:   samplingPolicies.rate,
+		LabelSanitizer: sanitizer,
 	})
 }
 
+// quotaConfig converts the quota configuration into a quota.Config.
+func quotaConfig(cfg config.QuotaConfig) quota.Config {
+	services := make(map[string]quota.Limits, len(cfg.Services))
+	for _, service := range cfg.Services {
+		services[service.Name] = quota.Limits(service.QuotaLimits)
+	}
+	return quota.Config{
+		Default:    quota.Limits(cfg.Default),
+		Services:   services,
+		Action:     quota.Action(cfg.Action),
+		SampleRate: cfg.SampleRate,
+	}
+}
+
+// tailSamplerConfig converts the tail-based sampling configuration into a
+// sampling.TailSamplerConfig, with the given initial policies.
+func tailSamplerConfig(cfg config.TailSamplingConfig, policies []sampling.TailSamplingPolicy) sampling.TailSamplerConfig {
+	return sampling.TailSamplerConfig{
+		Policies:     policies,
+		DecisionWait: cfg.DecisionWait,
+		MaxTraces:    cfg.MaxTraces,
+		Annotate:     cfg.Annotate,
+	}
+}
+
+func tailSamplingPolicies(cfg []config.TailSamplingPolicyConfig) []sampling.TailSamplingPolicy {
+	policies := make([]sampling.TailSamplingPolicy, len(cfg))
+	for i, p := range cfg {
+		policies[i] = sampling.TailSamplingPolicy{
+			Name:               p.Name,
+			ServiceName:        p.Service,
+			ServiceEnvironment: p.Environment,
+			TransactionType:    p.TransactionType,
+			TransactionName:    p.TransactionName,
+			MinDuration:        p.MinDuration,
+			HasError:           p.HasError,
+			SampleRate:         p.Rate,
+		}
+	}
+	return policies
+}
+
 func isElasticsearchOutput(b *beat.Beat) bool {
 	return b.Config != nil && b.Config.Output.Name() == "elasticsearch"
 }
@@ -199,6 +334,7 @@ func isElasticsearchOutput(b *beat.Beat) bool {
 func (bt *beater) registerPipelineCallback(b *beat.Beat) error {
 	overwrite := bt.config.Register.Ingest.Pipeline.ShouldOverwrite()
 	path := bt.config.Register.Ingest.Pipeline.Path
+	routingField := bt.config.Register.Ingest.Pipeline.RoutingField
 
 	// ensure setup cmd is working properly
 	b.OverwritePipelinesCallback = func(esConfig *common.Config) error {
@@ -206,11 +342,11 @@ func (bt *beater) registerPipelineCallback(b *beat.Beat) error {
 		if err != nil {
 			return err
 		}
-		return pipeline.RegisterPipelines(conn, overwrite, path)
+		return pipeline.RegisterPipelines(conn, overwrite, path, routingField)
 	}
 	// ensure pipelines are registered when new ES connection is established.
 	_, err := elasticsearch.RegisterConnectCallback(func(conn *eslegclient.Connection) error {
-		return pipeline.RegisterPipelines(conn, overwrite, path)
+		return pipeline.RegisterPipelines(conn, overwrite, path, routingField)
 	})
 	return err
 }
@@ -224,6 +360,7 @@ func (bt *beater) Stop() {
 	}
 	bt.logger.Infof("stopping apm-server... waiting maximum of %v seconds for queues to drain",
 		bt.config.ShutdownTimeout.Seconds())
+	bt.readiness.stopping()
 	bt.stopServer()
 	bt.stopped = true
 }
@@ -243,9 +380,28 @@ func runServerWithTracerServer(runServer RunServerFunc, tracerServer *tracerServ
 			return nil
 		})
 		g.Go(func() error {
-			return tracerServer.serve(args.Reporter)
+			return tracerServer.serve(args.Reporter, args.LabelSanitizer)
+		})
+		g.Go(func() error {
+			return runServer(ctx, args)
+		})
+		return g.Wait()
+	}
+}
+
+// runServerWithTailSampler wraps runServer such that it also runs
+// sampler, deciding pending traces once the server has shut down.
+func runServerWithTailSampler(runServer RunServerFunc, sampler *sampling.TailSampler) RunServerFunc {
+	return func(ctx context.Context, args ServerParams) error {
+		// The sampler is stopped only after the server, so events
+		// received during graceful shutdown are still decided.
+		samplerCtx, stopSampler := context.WithCancel(context.Background())
+		g, ctx := errgroup.WithContext(ctx)
+		g.Go(func() error {
+			return sampler.Run(samplerCtx)
 		})
 		g.Go(func() error {
+			defer stopSampler()
 			return runServer(ctx, args)
 		})
 		return g.Wait()
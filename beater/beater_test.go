@@ -50,26 +50,20 @@ func setupBeater(
 	beatConfig *beat.BeatConfig,
 ) (*testBeater, error) {
 
-	onboardingDocs := make(chan onboardingDoc, 1)
+	listenAddrs := make(chan string, 1)
 	createBeater := NewCreator(CreatorParams{
 		WrapRunServer: func(runServer RunServerFunc) RunServerFunc {
 			return func(ctx context.Context, args ServerParams) error {
-				// Wrap the reporter so we can intercept the
-				// onboarding doc, to extract the listen address.
+				// Record the listen address once the server is listening.
+				args.Listening = func(addr net.Addr) {
+					listenAddrs <- addr.String()
+				}
+				// Wrap the reporter so we can check that
+				// everything goes through the wrapped reporter.
 				origReporter := args.Reporter
 				args.Reporter = func(ctx context.Context, req publish.PendingReq) error {
 					for _, tf := range req.Transformables {
-						switch tf := tf.(type) {
-						case onboardingDoc:
-							select {
-							case <-ctx.Done():
-								return ctx.Err()
-							case onboardingDocs <- tf:
-							}
-
-						case *model.Transaction:
-							// Add a label to test that everything
-							// goes through the wrapped reporter.
+						if tf, ok := tf.(*model.Transaction); ok {
 							if tf.Labels == nil {
 								labels := make(model.Labels)
 								tf.Labels = &labels
@@ -103,8 +97,8 @@ func setupBeater(
 	select {
 	case err := <-errCh:
 		return nil, err
-	case o := <-onboardingDocs:
-		tb.initClient(tb.config, o.listenAddr)
+	case listenAddr := <-listenAddrs:
+		tb.initClient(tb.config, listenAddr)
 	case <-time.After(time.Second * 10):
 		return nil, errors.New("timeout waiting for server to start listening")
 	}
@@ -0,0 +1,63 @@
+// Licensed to Elasticsearch B.V. under one or more contributor
+// license agreements. See the NOTICE file distributed with
+// this work for additional information regarding copyright
+// ownership. Elasticsearch B.V. licenses this file to you under
+// the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package config
+
+import (
+	"strings"
+
+	"github.com/ryanuber/go-glob"
+)
+
+// AgentConfigDenyRule blocks a setting managed in Kibana from being
+// distributed to agents by the agent config endpoint.
+type AgentConfigDenyRule struct {
+	// Setting holds the name of the blocked setting, e.g. capture_body.
+	Setting string `config:"setting" validate:"required"`
+
+	// Values holds the blocked values of the setting, compared
+	// case-insensitively. If empty, any value is blocked.
+	Values []string `config:"values"`
+
+	// Service and Environment hold glob patterns restricting the rule
+	// to matching services. Empty patterns match any service or environment.
+	Service     string `config:"service"`
+	Environment string `config:"environment"`
+}
+
+// Blocks reports whether the rule blocks the setting with the given
+// value from being distributed to the given service.
+func (r *AgentConfigDenyRule) Blocks(service, environment, setting, value string) bool {
+	if r.Setting != setting {
+		return false
+	}
+	if r.Service != "" && !glob.Glob(r.Service, service) {
+		return false
+	}
+	if r.Environment != "" && !glob.Glob(r.Environment, environment) {
+		return false
+	}
+	if len(r.Values) == 0 {
+		return true
+	}
+	for _, v := range r.Values {
+		if strings.EqualFold(v, value) {
+			return true
+		}
+	}
+	return false
+}
@@ -18,6 +18,7 @@
 package config
 
 import (
+	"fmt"
 	"time"
 )
 
@@ -26,6 +27,17 @@ const (
 	defaultAggregationMaxTransactionGroups           = 1000
 	defaultAggregationHDRHistogramSignificantFigures = 2
 	defaultAggregationRUMUserAgentLRUSize            = 5000
+	defaultServiceDestinationsMaxGroups              = 10000
+	defaultTraceSummariesMaxTraces                   = 10000
+	defaultTraceSummariesIdleTimeout                 = 30 * time.Second
+	defaultMetricsetsInterval                        = 1 * time.Minute
+	defaultMetricsetsMaxGroups                       = 10000
+
+	// MetricTypeCounter identifies metrics aggregated by summing their values.
+	MetricTypeCounter = "counter"
+
+	// MetricTypeGauge identifies metrics aggregated by keeping their latest value.
+	MetricTypeGauge = "gauge"
 )
 
 // AggregationConfig holds configuration related to metrics aggregation.
@@ -35,6 +47,59 @@ type AggregationConfig struct {
 	MaxTransactionGroups           int           `config:"max_transaction_groups" validate:"min=1"`
 	HDRHistogramSignificantFigures int           `config:"hdrhistogram_significant_figures" validate:"min=1, max=5"`
 	RUMUserAgentLRUSize            int           `config:"rum.user_agent.lru_size" validate:"min=1"`
+
+	ServiceDestinations ServiceDestinationAggregationConfig `config:"service_destinations"`
+	TraceSummaries      TraceSummaryAggregationConfig       `config:"trace_summaries"`
+	Metricsets          MetricsetAggregationConfig          `config:"metricsets"`
+}
+
+// ServiceDestinationAggregationConfig holds configuration related to
+// aggregating exit spans into service destination metrics.
+type ServiceDestinationAggregationConfig struct {
+	Enabled   bool `config:"enabled"`
+	MaxGroups int  `config:"max_groups" validate:"min=1"`
+}
+
+// TraceSummaryAggregationConfig holds configuration related to
+// publishing a summary document for each completed trace.
+type TraceSummaryAggregationConfig struct {
+	Enabled     bool          `config:"enabled"`
+	MaxTraces   int           `config:"max_traces" validate:"min=1"`
+	IdleTimeout time.Duration `config:"idle_timeout" validate:"min=1"`
+}
+
+// MetricsetAggregationConfig holds configuration related to aggregating
+// metricsets reported by agents over a longer interval.
+type MetricsetAggregationConfig struct {
+	Enabled   bool                       `config:"enabled"`
+	Interval  time.Duration              `config:"interval" validate:"min=1"`
+	MaxGroups int                        `config:"max_groups" validate:"min=1"`
+	Rules     []MetricsetAggregationRule `config:"rules"`
+}
+
+// MetricsetAggregationRule identifies metrics to aggregate by name,
+// and how they are aggregated.
+type MetricsetAggregationRule struct {
+	// Name is a glob pattern matching metric names.
+	Name string `config:"name" validate:"required"`
+
+	// Type is either "counter" or "gauge".
+	Type string `config:"type" validate:"required"`
+}
+
+func (c *MetricsetAggregationConfig) Validate() error {
+	if c.Enabled && len(c.Rules) == 0 {
+		return fmt.Errorf("aggregation.metricsets.rules must be specified when enabled")
+	}
+	return nil
+}
+
+func (r *MetricsetAggregationRule) Validate() error {
+	switch r.Type {
+	case MetricTypeCounter, MetricTypeGauge:
+		return nil
+	}
+	return fmt.Errorf("invalid metric type %q for %q, expected %q or %q", r.Type, r.Name, MetricTypeCounter, MetricTypeGauge)
 }
 
 func defaultAggregationConfig() AggregationConfig {
@@ -43,5 +108,16 @@ func defaultAggregationConfig() AggregationConfig {
 		MaxTransactionGroups:           defaultAggregationMaxTransactionGroups,
 		HDRHistogramSignificantFigures: defaultAggregationHDRHistogramSignificantFigures,
 		RUMUserAgentLRUSize:            defaultAggregationRUMUserAgentLRUSize,
+		ServiceDestinations: ServiceDestinationAggregationConfig{
+			MaxGroups: defaultServiceDestinationsMaxGroups,
+		},
+		TraceSummaries: TraceSummaryAggregationConfig{
+			MaxTraces:   defaultTraceSummariesMaxTraces,
+			IdleTimeout: defaultTraceSummariesIdleTimeout,
+		},
+		Metricsets: MetricsetAggregationConfig{
+			Interval:  defaultMetricsetsInterval,
+			MaxGroups: defaultMetricsetsMaxGroups,
+		},
 	}
 }
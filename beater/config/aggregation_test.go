@@ -57,6 +57,16 @@ func TestAggregationConfigInvalid(t *testing.T) {
 		key:    "aggregation.hdrhistogram_significant_figures",
 		value:  float64(6),
 		expect: "Error processing configuration: requires value > 5 accessing 'aggregation.hdrhistogram_significant_figures'",
+	}, {
+		name:   "metricsets without rules",
+		key:    "aggregation.metricsets.enabled",
+		value:  true,
+		expect: "Error processing configuration: aggregation.metricsets.rules must be specified when enabled accessing 'aggregation.metricsets'",
+	}, {
+		name:   "metricsets rule with invalid type",
+		key:    "aggregation.metricsets.rules",
+		value:  []map[string]interface{}{{"name": "*", "type": "histogram"}},
+		expect: `Error processing configuration: invalid metric type "histogram" for "*", expected "counter" or "gauge" accessing 'aggregation.metricsets.rules.0'`,
 	}} {
 		t.Run(test.name, func(t *testing.T) {
 			_, err := NewConfig("9.9.9", common.MustNewConfigFrom(map[string]interface{}{
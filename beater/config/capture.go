@@ -0,0 +1,64 @@
+// Licensed to Elasticsearch B.V. under one or more contributor
+// license agreements. See the NOTICE file distributed with
+// this work for additional information regarding copyright
+// ownership. Elasticsearch B.V. licenses this file to you under
+// the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package config
+
+const (
+	defaultCapturePath              = "capture"
+	defaultCaptureRequestsPerMinute = 10
+	defaultCaptureMaxFiles          = 100
+	defaultCaptureMaxBodySize       = 1024 * 1024
+)
+
+// CaptureConfig holds configuration related to capturing raw intake
+// request bodies to local disk, for investigating misbehaving agents.
+type CaptureConfig struct {
+	Enabled bool `config:"enabled"`
+
+	// Path is the directory captured request bodies are written to.
+	// Relative paths are resolved against the data path.
+	Path string `config:"path"`
+
+	// RequestsPerMinute is the maximum number of request bodies
+	// captured per minute.
+	RequestsPerMinute int `config:"requests_per_minute" validate:"min=1"`
+
+	// MaxFiles is the number of captured request bodies kept on disk.
+	// Once exceeded, the oldest are removed.
+	MaxFiles int `config:"max_files" validate:"min=1"`
+
+	// MaxBodySize is the maximum number of bytes captured for each
+	// request body; larger bodies are truncated.
+	MaxBodySize int `config:"max_body_size" validate:"min=1"`
+
+	// ServiceNames, if non-empty, restricts capturing to requests
+	// with metadata for one of the given services.
+	ServiceNames []string `config:"service_names"`
+
+	// StatusCodes, if non-empty, restricts capturing to requests
+	// answered with one of the given response status codes.
+	StatusCodes []int `config:"status_codes"`
+}
+
+func defaultCaptureConfig() CaptureConfig {
+	return CaptureConfig{
+		Path:              defaultCapturePath,
+		RequestsPerMinute: defaultCaptureRequestsPerMinute,
+		MaxFiles:          defaultCaptureMaxFiles,
+		MaxBodySize:       defaultCaptureMaxBodySize,
+	}
+}
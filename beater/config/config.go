@@ -30,6 +30,7 @@ import (
 	"github.com/elastic/beats/v7/libbeat/kibana"
 	"github.com/elastic/beats/v7/libbeat/logp"
 
+	"github.com/elastic/apm-server/elasticsearch"
 	logs "github.com/elastic/apm-server/log"
 )
 
@@ -38,6 +39,10 @@ const (
 	DefaultPort = "8200"
 
 	msgInvalidConfigAgentCfg = "invalid value for `apm-server.agent.config.cache.expiration`, only accepting full seconds"
+
+	// maxMemoryUnpublishedShare is the inverse share of max_memory
+	// defaulting max_unpublished_bytes.
+	maxMemoryUnpublishedShare = 4
 )
 
 var (
@@ -68,28 +73,59 @@ func defaultKibanaConfig() KibanaConfig {
 
 // Config holds configuration information nested under the key `apm-server`
 type Config struct {
-	Host                string                  `config:"host"`
-	MaxHeaderSize       int                     `config:"max_header_size"`
-	IdleTimeout         time.Duration           `config:"idle_timeout"`
-	ReadTimeout         time.Duration           `config:"read_timeout"`
-	WriteTimeout        time.Duration           `config:"write_timeout"`
-	MaxEventSize        int                     `config:"max_event_size"`
-	ShutdownTimeout     time.Duration           `config:"shutdown_timeout"`
-	TLS                 *tlscommon.ServerConfig `config:"ssl"`
-	MaxConnections      int                     `config:"max_connections"`
-	Expvar              *ExpvarConfig           `config:"expvar"`
-	AugmentEnabled      bool                    `config:"capture_personal_data"`
-	SelfInstrumentation *InstrumentationConfig  `config:"instrumentation"`
-	RumConfig           *RumConfig              `config:"rum"`
-	Register            *RegisterConfig         `config:"register"`
-	Mode                Mode                    `config:"mode"`
-	Kibana              KibanaConfig            `config:"kibana"`
-	AgentConfig         *AgentConfig            `config:"agent.config"`
-	SecretToken         string                  `config:"secret_token"`
-	APIKeyConfig        *APIKeyConfig           `config:"api_key"`
-	JaegerConfig        JaegerConfig            `config:"jaeger"`
-	Aggregation         AggregationConfig       `config:"aggregation"`
-	Sampling            SamplingConfig          `config:"sampling"`
+	Host                    string                  `config:"host"`
+	MaxHeaderSize           int                     `config:"max_header_size"`
+	IdleTimeout             time.Duration           `config:"idle_timeout"`
+	ReadTimeout             time.Duration           `config:"read_timeout"`
+	WriteTimeout            time.Duration           `config:"write_timeout"`
+	MaxEventSize            int                     `config:"max_event_size"`
+	MaxDecompressedBodySize int64                   `config:"max_decompressed_body_size" validate:"min=0"`
+	DecodeConcurrency       int                     `config:"decode_concurrency" validate:"min=1"`
+	MaxUnpublishedBytes     int64                   `config:"max_unpublished_bytes" validate:"min=0"`
+	MaxMemory               int64                   `config:"max_memory" validate:"min=0"`
+	ContextLimits           ContextLimitsConfig     `config:"context_limits"`
+	FastJSON                bool                    `config:"fast_json"`
+	ShutdownTimeout         time.Duration           `config:"shutdown_timeout"`
+	TLS                     *tlscommon.ServerConfig `config:"ssl"`
+	MaxConnections          int                     `config:"max_connections"`
+	Expvar                  *ExpvarConfig           `config:"expvar"`
+	ConfigEndpoint          *ConfigEndpointConfig   `config:"config_endpoint"`
+	OpenAPI                 *OpenAPIConfig          `config:"openapi"`
+	IntakeControl           *IntakeControlConfig    `config:"intake_control"`
+	AgentStats              *AgentStatsConfig       `config:"agent_stats"`
+	AugmentEnabled          bool                    `config:"capture_personal_data"`
+	SelfInstrumentation     *InstrumentationConfig  `config:"instrumentation"`
+	RumConfig               *RumConfig              `config:"rum"`
+	Register                *RegisterConfig         `config:"register"`
+	Mode                    Mode                    `config:"mode"`
+	Kibana                  KibanaConfig            `config:"kibana"`
+	AgentConfig             *AgentConfig            `config:"agent.config"`
+	SecretToken             string                  `config:"secret_token"`
+	APIKeyConfig            *APIKeyConfig           `config:"api_key"`
+	JaegerConfig            JaegerConfig            `config:"jaeger"`
+	Aggregation             AggregationConfig       `config:"aggregation"`
+	Sampling                SamplingConfig          `config:"sampling"`
+	PublishLimit            PublishLimitConfig      `config:"publish_limit"`
+	OutputHealth            OutputHealthConfig      `config:"output_health"`
+	Quota                   QuotaConfig             `config:"quota"`
+	Tenancy                 TenancyConfig           `config:"tenancy"`
+	Labels                  LabelsConfig            `config:"labels"`
+	ProcessArgs             ProcessArgsConfig       `config:"process_args"`
+	ContainerInference      ContainerInferConfig    `config:"container_inference"`
+	Dedup                   DedupConfig             `config:"dedup"`
+	FeatureFlags            FeatureFlagsConfig      `config:"features"`
+	SpanStacktrace          SpanStacktraceConfig    `config:"span_stacktrace"`
+	LibraryFrames           LibraryFramesConfig     `config:"library_frames"`
+	ErrorGrouping           ErrorGroupingConfig     `config:"error_grouping"`
+	Capture                 CaptureConfig           `config:"capture"`
+	ValidationSummary       ValidationSummaryConfig `config:"validation_summary"`
+	StreamLimits            StreamLimitsConfig      `config:"stream_limits"`
+	RepeatedErrors          RepeatedErrorsConfig    `config:"repeated_errors"`
+	Spool                   SpoolConfig             `config:"spool"`
+
+	// ReadOnly makes the server reject event intake and sourcemap uploads,
+	// while still serving agent configuration, e.g. during maintenance.
+	ReadOnly bool `config:"read_only"`
 
 	Pipeline string
 }
@@ -100,9 +136,37 @@ type ExpvarConfig struct {
 	URL     string `config:"url"`
 }
 
+// ConfigEndpointConfig holds config information about exposing the effective configuration
+type ConfigEndpointConfig struct {
+	Enabled *bool  `config:"enabled"`
+	URL     string `config:"url"`
+}
+
+// OpenAPIConfig holds config information about exposing the OpenAPI document
+type OpenAPIConfig struct {
+	Enabled *bool  `config:"enabled"`
+	URL     string `config:"url"`
+}
+
+// IntakeControlConfig holds config information about exposing the endpoint
+// for pausing and resuming event intake
+type IntakeControlConfig struct {
+	Enabled    *bool         `config:"enabled"`
+	URL        string        `config:"url"`
+	RetryAfter time.Duration `config:"retry_after" validate:"min=1"`
+}
+
+// AgentStatsConfig holds config information about exposing the
+// per-agent-version event statistics
+type AgentStatsConfig struct {
+	Enabled *bool  `config:"enabled"`
+	URL     string `config:"url"`
+}
+
 // AgentConfig holds remote agent config information
 type AgentConfig struct {
-	Cache *Cache `config:"cache"`
+	Cache    *Cache                `config:"cache"`
+	Denylist []AgentConfigDenyRule `config:"denylist"`
 }
 
 // Cache holds config information about cache expiration
@@ -122,18 +186,44 @@ func NewConfig(version string, ucfg *common.Config, outputESCfg *common.Config)
 		return nil, errors.New(msgInvalidConfigAgentCfg)
 	}
 
+	if c.MaxMemory > 0 && !ucfg.HasField("max_unpublished_bytes") {
+		// Leave memory for events being decoded, queued and published.
+		c.MaxUnpublishedBytes = c.MaxMemory / maxMemoryUnpublishedShare
+	}
+
 	if outputESCfg != nil && (outputESCfg.HasField("pipeline") || outputESCfg.HasField("pipelines")) {
 		c.Pipeline = ""
 	}
 
+	if outputESCfg != nil && outputESCfg.HasField("service_token") {
+		// The service token is not known to libbeat, so check
+		// it is not combined with other output credentials.
+		var esConfig elasticsearch.Config
+		if err := outputESCfg.Unpack(&esConfig); err != nil {
+			return nil, errors.Wrap(err, "Error processing output.elasticsearch configuration")
+		}
+	}
+
 	if err := c.RumConfig.setup(logger, outputESCfg); err != nil {
 		return nil, err
 	}
 
+	if c.Pipeline == "" && c.Register.Ingest.Pipeline.RoutingField != "" {
+		// Routing is set by the apm ingest pipeline.
+		logger.Warn("" +
+			"apm-server.register.ingest.pipeline.routing_field is ignored, " +
+			"as the apm pipeline is not used by the Elasticsearch output",
+		)
+	}
+
 	if err := c.APIKeyConfig.setup(logger, outputESCfg); err != nil {
 		return nil, err
 	}
 
+	if err := c.OutputHealth.setup(logger, outputESCfg); err != nil {
+		return nil, err
+	}
+
 	if err := c.SelfInstrumentation.setup(logger); err != nil {
 		return nil, err
 	}
@@ -154,6 +244,13 @@ func NewConfig(version string, ucfg *common.Config, outputESCfg *common.Config)
 			"which will lead to incorrect metrics being reported in the APM UI",
 		)
 	}
+	if c.Sampling.Tail.Enabled && !c.Aggregation.Enabled {
+		logger.Warn("" +
+			"apm-server.sampling.tail.enabled is true and " +
+			"apm-server.aggregation.enabled is false, " +
+			"which will lead to incorrect metrics being reported in the APM UI",
+		)
+	}
 	return c, nil
 }
 
@@ -162,22 +259,61 @@ func (c *ExpvarConfig) IsEnabled() bool {
 	return c != nil && (c.Enabled == nil || *c.Enabled)
 }
 
+// IsEnabled indicates whether the config endpoint is enabled or not
+func (c *ConfigEndpointConfig) IsEnabled() bool {
+	return c != nil && (c.Enabled == nil || *c.Enabled)
+}
+
+// IsEnabled indicates whether the OpenAPI document is served or not
+func (c *OpenAPIConfig) IsEnabled() bool {
+	return c != nil && (c.Enabled == nil || *c.Enabled)
+}
+
+// IsEnabled indicates whether the intake control endpoint is enabled or not
+func (c *IntakeControlConfig) IsEnabled() bool {
+	return c != nil && (c.Enabled == nil || *c.Enabled)
+}
+
+// IsEnabled indicates whether the agent statistics endpoint is enabled or not
+func (c *AgentStatsConfig) IsEnabled() bool {
+	return c != nil && (c.Enabled == nil || *c.Enabled)
+}
+
 // DefaultConfig returns a config with default settings for `apm-server` config options.
 func DefaultConfig(beatVersion string) *Config {
 	return &Config{
-		Host:            net.JoinHostPort("localhost", DefaultPort),
-		MaxHeaderSize:   1 * 1024 * 1024, // 1mb
-		MaxConnections:  0,               // unlimited
-		IdleTimeout:     45 * time.Second,
-		ReadTimeout:     30 * time.Second,
-		WriteTimeout:    30 * time.Second,
-		MaxEventSize:    300 * 1024, // 300 kb
-		ShutdownTimeout: 5 * time.Second,
-		AugmentEnabled:  true,
+		Host:                    net.JoinHostPort("localhost", DefaultPort),
+		MaxHeaderSize:           1 * 1024 * 1024, // 1mb
+		MaxConnections:          0,               // unlimited
+		IdleTimeout:             45 * time.Second,
+		ReadTimeout:             30 * time.Second,
+		WriteTimeout:            30 * time.Second,
+		MaxEventSize:            300 * 1024,        // 300 kb
+		MaxDecompressedBodySize: 100 * 1024 * 1024, // 100 mb
+		DecodeConcurrency:       1,
+		ShutdownTimeout:         5 * time.Second,
+		AugmentEnabled:          true,
 		Expvar: &ExpvarConfig{
 			Enabled: new(bool),
 			URL:     "/debug/vars",
 		},
+		ConfigEndpoint: &ConfigEndpointConfig{
+			Enabled: new(bool),
+			URL:     "/debug/config",
+		},
+		OpenAPI: &OpenAPIConfig{
+			Enabled: new(bool),
+			URL:     "/docs",
+		},
+		IntakeControl: &IntakeControlConfig{
+			Enabled:    new(bool),
+			URL:        "/admin/intake",
+			RetryAfter: 30 * time.Second,
+		},
+		AgentStats: &AgentStatsConfig{
+			Enabled: new(bool),
+			URL:     "/debug/agents",
+		},
 		RumConfig:    defaultRum(beatVersion),
 		Register:     defaultRegisterConfig(true),
 		Mode:         ModeProduction,
@@ -188,5 +324,17 @@ func DefaultConfig(beatVersion string) *Config {
 		JaegerConfig: defaultJaeger(),
 		Aggregation:  defaultAggregationConfig(),
 		Sampling:     defaultSamplingConfig(),
+		PublishLimit: defaultPublishLimitConfig(),
+		OutputHealth: defaultOutputHealthConfig(),
+		Quota:        defaultQuotaConfig(),
+		Labels:       defaultLabelsConfig(),
+		ProcessArgs:  defaultProcessArgsConfig(),
+		Dedup:        defaultDedupConfig(),
+		Capture:      defaultCaptureConfig(),
+
+		ValidationSummary: defaultValidationSummaryConfig(),
+		StreamLimits:      defaultStreamLimitsConfig(),
+		RepeatedErrors:    defaultRepeatedErrorsConfig(),
+		Spool:             defaultSpoolConfig(),
 	}
 }
@@ -52,9 +52,17 @@ func Test_UnpackConfig(t *testing.T) {
 		},
 		"overwrite default": {
 			inpCfg: map[string]interface{}{
-				"host":                  "localhost:3000",
-				"max_header_size":       8,
-				"max_event_size":        100,
+				"host":               "localhost:3000",
+				"max_header_size":    8,
+				"max_event_size":     100,
+				"decode_concurrency": 4,
+				"max_memory":         1024 * 1024 * 1024,
+				"context_limits": map[string]interface{}{
+					"max_depth":        3,
+					"max_keys":         100,
+					"max_value_length": 1024,
+				},
+				"fast_json":             true,
 				"idle_timeout":          5 * time.Second,
 				"read_timeout":          3 * time.Second,
 				"write_timeout":         4 * time.Second,
@@ -86,29 +94,128 @@ func Test_UnpackConfig(t *testing.T) {
 						},
 						"index_pattern":       "apm-test*",
 						"elasticsearch.hosts": []string{"localhost:9201", "localhost:9202"},
+						"max_wait":            2 * time.Second,
 					},
 					"library_pattern":       "^custom",
 					"exclude_from_grouping": "^grouping",
+					"allow_service_names":   []string{"frontend"},
+					"allow_service_names_by_origin": []map[string]interface{}{
+						{"origin": "https://*.example.com", "service_names": []string{"shop"}},
+					},
+					"allow_event_types": []string{"transaction", "error"},
+					"allow_event_types_by_service": []map[string]interface{}{
+						{"service_name": "shop", "event_types": []string{"error"}},
+					},
+					"agent_bundle": map[string]interface{}{
+						"enabled": true,
+						"url":     "/rum/elastic-apm-rum",
+						"path":    "/var/lib/rum-agent",
+					},
+					"trace_continuation_strategy": "restart_external",
 				},
 				"register": map[string]interface{}{
 					"ingest": map[string]interface{}{
 						"pipeline": map[string]interface{}{
-							"overwrite": false,
-							"path":      filepath.Join("tmp", "definition.json"),
+							"overwrite":     false,
+							"path":          filepath.Join("tmp", "definition.json"),
+							"routing_field": "trace.id",
 						},
 					},
 				},
 				"kibana":                        map[string]interface{}{"enabled": "true"},
 				"agent.config.cache.expiration": "2m",
-				"jaeger.grpc.enabled":           true,
-				"jaeger.grpc.host":              "localhost:12345",
-				"jaeger.http.enabled":           true,
-				"jaeger.http.host":              "localhost:6789",
+				"read_only":                     true,
+				"agent.config.denylist": []map[string]interface{}{
+					{"setting": "capture_body", "values": []string{"all"}, "environment": "production"},
+				},
+				"jaeger.grpc.enabled": true,
+				"jaeger.grpc.host":    "localhost:12345",
+				"jaeger.http.enabled": true,
+				"jaeger.http.host":    "localhost:6789",
 				"api_key": map[string]interface{}{
 					"enabled":             true,
 					"limit":               200,
 					"elasticsearch.hosts": []string{"localhost:9201", "localhost:9202"},
 				},
+				"output_health": map[string]interface{}{
+					"enabled":             true,
+					"interval":            "30s",
+					"max_wait":            "5s",
+					"breaker_threshold":   0.8,
+					"elasticsearch.hosts": []string{"localhost:9201"},
+				},
+				"labels": map[string]interface{}{
+					"max_keys_per_service": 50,
+				},
+				"process_args": map[string]interface{}{
+					"scrub":                  false,
+					"sensitive_flag_pattern": "secret",
+				},
+				"container_inference": map[string]interface{}{
+					"enabled":    true,
+					"cni_ranges": []string{"10.244.0.0/16"},
+					"proc_path":  "/host/proc",
+				},
+				"dedup": map[string]interface{}{
+					"enabled":   true,
+					"ttl":       "30s",
+					"max_bytes": 1024,
+				},
+				"features": map[string]interface{}{
+					"profiles":        true,
+					"trace_summaries": true,
+				},
+				"span_stacktrace": map[string]interface{}{
+					"min_duration": "5ms",
+					"max_frames":   50,
+				},
+				"library_frames.patterns":     []string{"/vendor/"},
+				"error_grouping.key_template": "{{.ServiceName}}",
+				"capture": map[string]interface{}{
+					"enabled":             true,
+					"path":                "/tmp/capture",
+					"requests_per_minute": 5,
+					"max_files":           10,
+					"max_body_size":       1024,
+					"service_names":       []string{"opbeans-go"},
+					"status_codes":        []int{400},
+				},
+				"validation_summary": map[string]interface{}{
+					"enabled":  true,
+					"interval": "5m",
+				},
+				"stream_limits": map[string]interface{}{
+					"max_per_client": 4,
+					"min_throughput": 128,
+					"window":         "20s",
+				},
+				"repeated_errors": map[string]interface{}{
+					"enabled":  true,
+					"interval": "5m",
+				},
+				"spool": map[string]interface{}{
+					"enabled":   true,
+					"path":      "/tmp/spool",
+					"max_bytes": 1024,
+					"rate":      10,
+					"url":       "/backfill",
+				},
+				"sampling.rules": []map[string]interface{}{
+					{"service": "opbeans-*", "environment": "production", "rate": 0.1},
+					{"transaction.type": "messaging", "transaction.name": "consume *", "rate": 0.01},
+					{"rate": 1},
+				},
+				"sampling.tail": map[string]interface{}{
+					"enabled":       true,
+					"decision_wait": "5s",
+					"max_traces":    500,
+					"annotate":      true,
+					"policies": []map[string]interface{}{
+						{"name": "slow", "trace.min_duration": "2s", "rate": 1},
+						{"trace.has_error": true, "rate": 1},
+						{"service": "opbeans-*", "transaction.type": "request", "rate": 0.1},
+					},
+				},
 				"aggregation": map[string]interface{}{
 					"enabled":                          true,
 					"interval":                         "1s",
@@ -119,12 +226,40 @@ func Test_UnpackConfig(t *testing.T) {
 							"lru_size": 123,
 						},
 					},
+					"service_destinations": map[string]interface{}{
+						"enabled":    true,
+						"max_groups": 456,
+					},
+					"trace_summaries": map[string]interface{}{
+						"enabled":      true,
+						"max_traces":   789,
+						"idle_timeout": "10s",
+					},
+					"metricsets": map[string]interface{}{
+						"enabled":    true,
+						"interval":   "5m",
+						"max_groups": 12,
+						"rules": []map[string]interface{}{
+							{"name": "system.cpu.*", "type": "gauge"},
+							{"name": "*.count", "type": "counter"},
+						},
+					},
 				},
 			},
 			outCfg: &Config{
-				Host:            "localhost:3000",
-				MaxHeaderSize:   8,
-				MaxEventSize:    100,
+				Host:                    "localhost:3000",
+				MaxHeaderSize:           8,
+				MaxEventSize:            100,
+				MaxDecompressedBodySize: 104857600,
+				DecodeConcurrency:       4,
+				MaxMemory:               1024 * 1024 * 1024,
+				MaxUnpublishedBytes:     256 * 1024 * 1024,
+				ContextLimits: ContextLimitsConfig{
+					MaxDepth:       3,
+					MaxKeys:        100,
+					MaxValueLength: 1024,
+				},
+				FastJSON:        true,
 				IdleTimeout:     5000000000,
 				ReadTimeout:     3000000000,
 				WriteTimeout:    4000000000,
@@ -143,6 +278,23 @@ func Test_UnpackConfig(t *testing.T) {
 					Enabled: &truthy,
 					URL:     "/debug/vars",
 				},
+				ConfigEndpoint: &ConfigEndpointConfig{
+					Enabled: new(bool),
+					URL:     "/debug/config",
+				},
+				OpenAPI: &OpenAPIConfig{
+					Enabled: new(bool),
+					URL:     "/docs",
+				},
+				IntakeControl: &IntakeControlConfig{
+					Enabled:    new(bool),
+					URL:        "/admin/intake",
+					RetryAfter: 30 * time.Second,
+				},
+				AgentStats: &AgentStatsConfig{
+					Enabled: new(bool),
+					URL:     "/debug/agents",
+				},
 				RumConfig: &RumConfig{
 					Enabled: &truthy,
 					EventRate: &EventRate{
@@ -158,18 +310,34 @@ func Test_UnpackConfig(t *testing.T) {
 							Hosts:    elasticsearch.Hosts{"localhost:9201", "localhost:9202"},
 							Protocol: "http",
 							Timeout:  5 * time.Second},
+						MaxWait:      2 * time.Second,
 						esConfigured: true,
 					},
 					LibraryPattern:      "^custom",
 					ExcludeFromGrouping: "^grouping",
-					BeatVersion:         version,
+					AllowServiceNames:   []string{"frontend"},
+					OriginServiceNames: []OriginServiceNames{
+						{Origin: "https://*.example.com", ServiceNames: []string{"shop"}},
+					},
+					AllowEventTypes: []string{"transaction", "error"},
+					ServiceEventTypes: []ServiceEventTypes{
+						{ServiceName: "shop", EventTypes: []string{"error"}},
+					},
+					AgentBundle: &RumAgentBundle{
+						Enabled: &truthy,
+						URL:     "/rum/elastic-apm-rum",
+						Path:    "/var/lib/rum-agent",
+					},
+					TraceContinuationStrategy: "restart_external",
+					BeatVersion:               version,
 				},
 				Register: &RegisterConfig{
 					Ingest: &IngestConfig{
 						Pipeline: &PipelineConfig{
-							Enabled:   &truthy,
-							Overwrite: &falsy,
-							Path:      filepath.Join("tmp", "definition.json"),
+							Enabled:      &truthy,
+							Overwrite:    &falsy,
+							Path:         filepath.Join("tmp", "definition.json"),
+							RoutingField: "trace.id",
 						},
 					},
 				},
@@ -177,8 +345,14 @@ func Test_UnpackConfig(t *testing.T) {
 					Enabled:      true,
 					ClientConfig: defaultKibanaConfig().ClientConfig,
 				},
-				AgentConfig: &AgentConfig{Cache: &Cache{Expiration: 2 * time.Minute}},
-				Pipeline:    defaultAPMPipeline,
+				AgentConfig: &AgentConfig{
+					Cache: &Cache{Expiration: 2 * time.Minute},
+					Denylist: []AgentConfigDenyRule{
+						{Setting: "capture_body", Values: []string{"all"}, Environment: "production"},
+					},
+				},
+				ReadOnly: true,
+				Pipeline: defaultAPMPipeline,
 				JaegerConfig: JaegerConfig{
 					GRPC: JaegerGRPCConfig{
 						Enabled: true,
@@ -215,9 +389,122 @@ func Test_UnpackConfig(t *testing.T) {
 					MaxTransactionGroups:           123,
 					HDRHistogramSignificantFigures: 1,
 					RUMUserAgentLRUSize:            123,
+					ServiceDestinations: ServiceDestinationAggregationConfig{
+						Enabled:   true,
+						MaxGroups: 456,
+					},
+					TraceSummaries: TraceSummaryAggregationConfig{
+						Enabled:     true,
+						MaxTraces:   789,
+						IdleTimeout: 10 * time.Second,
+					},
+					Metricsets: MetricsetAggregationConfig{
+						Enabled:   true,
+						Interval:  5 * time.Minute,
+						MaxGroups: 12,
+						Rules: []MetricsetAggregationRule{
+							{Name: "system.cpu.*", Type: "gauge"},
+							{Name: "*.count", Type: "counter"},
+						},
+					},
 				},
 				Sampling: SamplingConfig{
 					KeepUnsampled: true,
+					Rules: []SamplingRuleConfig{
+						{Service: "opbeans-*", Environment: "production", Rate: 0.1},
+						{TransactionType: "messaging", TransactionName: "consume *", Rate: 0.01},
+						{Rate: 1},
+					},
+					Tail: TailSamplingConfig{
+						Enabled:      true,
+						DecisionWait: 5 * time.Second,
+						MaxTraces:    500,
+						Annotate:     true,
+						Policies: []TailSamplingPolicyConfig{
+							{Name: "slow", MinDuration: 2 * time.Second, Rate: 1},
+							{HasError: true, Rate: 1},
+							{Service: "opbeans-*", TransactionType: "request", Rate: 0.1},
+						},
+					},
+					PoliciesFile: PolicyFileConfig{ReloadPeriod: 10 * time.Second},
+				},
+				PublishLimit: PublishLimitConfig{
+					Min:              1,
+					Max:              100,
+					Initial:          10,
+					LatencyThreshold: 100 * time.Millisecond,
+					BackoffRatio:     0.9,
+					MaxWait:          time.Second,
+				},
+				OutputHealth: OutputHealthConfig{
+					Enabled:          true,
+					Interval:         30 * time.Second,
+					MaxWait:          5 * time.Second,
+					BreakerThreshold: 0.8,
+					ESConfig: &elasticsearch.Config{
+						Hosts:    elasticsearch.Hosts{"localhost:9201"},
+						Protocol: "http",
+						Timeout:  5 * time.Second},
+					esConfigured: true,
+				},
+				Quota: QuotaConfig{
+					Action:     "reject",
+					SampleRate: 0.1,
+				},
+				Labels: LabelsConfig{MaxKeysPerService: 50},
+				ProcessArgs: ProcessArgsConfig{
+					Scrub:                false,
+					SensitiveFlagPattern: "secret",
+					RulesFile:            PolicyFileConfig{ReloadPeriod: 10 * time.Second},
+				},
+				ContainerInference: ContainerInferConfig{
+					Enabled:   true,
+					CNIRanges: []string{"10.244.0.0/16"},
+					ProcPath:  "/host/proc",
+				},
+				Dedup: DedupConfig{
+					Enabled:  true,
+					TTL:      30 * time.Second,
+					MaxBytes: 1024,
+				},
+				FeatureFlags: FeatureFlagsConfig{
+					Profiles:       true,
+					TraceSummaries: true,
+				},
+				SpanStacktrace: SpanStacktraceConfig{
+					MinDuration: 5 * time.Millisecond,
+					MaxFrames:   50,
+				},
+				LibraryFrames: LibraryFramesConfig{Patterns: []string{"/vendor/"}},
+				ErrorGrouping: ErrorGroupingConfig{KeyTemplate: "{{.ServiceName}}"},
+				Capture: CaptureConfig{
+					Enabled:           true,
+					Path:              "/tmp/capture",
+					RequestsPerMinute: 5,
+					MaxFiles:          10,
+					MaxBodySize:       1024,
+					ServiceNames:      []string{"opbeans-go"},
+					StatusCodes:       []int{400},
+				},
+				ValidationSummary: ValidationSummaryConfig{
+					Enabled:  true,
+					Interval: 5 * time.Minute,
+				},
+				StreamLimits: StreamLimitsConfig{
+					MaxPerClient:  4,
+					MinThroughput: 128,
+					Window:        20 * time.Second,
+				},
+				RepeatedErrors: RepeatedErrorsConfig{
+					Enabled:  true,
+					Interval: 5 * time.Minute,
+				},
+				Spool: SpoolConfig{
+					Enabled:  true,
+					Path:     "/tmp/spool",
+					MaxBytes: 1024,
+					Rate:     10,
+					URL:      "/backfill",
 				},
 			},
 		},
@@ -253,16 +540,24 @@ func Test_UnpackConfig(t *testing.T) {
 				"aggregation.enabled":                 true,
 				"aggregation.rum.user_agent.lru_size": 123,
 				"sampling.keep_unsampled":             false,
+				"publish_limit.enabled":               true,
+				"quota.enabled":                       true,
+				"quota.action":                        "sample",
+				"quota.services": []map[string]interface{}{
+					{"name": "opbeans-go", "events_per_day": 1000},
+				},
 			},
 			outCfg: &Config{
-				Host:            "localhost:3000",
-				MaxHeaderSize:   1048576,
-				MaxEventSize:    307200,
-				IdleTimeout:     45000000000,
-				ReadTimeout:     30000000000,
-				WriteTimeout:    30000000000,
-				ShutdownTimeout: 5000000000,
-				SecretToken:     "1234random",
+				Host:                    "localhost:3000",
+				MaxHeaderSize:           1048576,
+				MaxEventSize:            307200,
+				MaxDecompressedBodySize: 104857600,
+				DecodeConcurrency:       1,
+				IdleTimeout:             45000000000,
+				ReadTimeout:             30000000000,
+				WriteTimeout:            30000000000,
+				ShutdownTimeout:         5000000000,
+				SecretToken:             "1234random",
 				TLS: &tlscommon.ServerConfig{
 					Enabled:     &truthy,
 					Certificate: tlscommon.CertificateConfig{Certificate: "", Key: ""},
@@ -272,6 +567,23 @@ func Test_UnpackConfig(t *testing.T) {
 					Enabled: &truthy,
 					URL:     "/debug/vars",
 				},
+				ConfigEndpoint: &ConfigEndpointConfig{
+					Enabled: new(bool),
+					URL:     "/debug/config",
+				},
+				OpenAPI: &OpenAPIConfig{
+					Enabled: new(bool),
+					URL:     "/docs",
+				},
+				IntakeControl: &IntakeControlConfig{
+					Enabled:    new(bool),
+					URL:        "/admin/intake",
+					RetryAfter: 30 * time.Second,
+				},
+				AgentStats: &AgentStatsConfig{
+					Enabled: new(bool),
+					URL:     "/debug/agents",
+				},
 				RumConfig: &RumConfig{
 					Enabled: &truthy,
 					EventRate: &EventRate{
@@ -289,7 +601,12 @@ func Test_UnpackConfig(t *testing.T) {
 					},
 					LibraryPattern:      "rum",
 					ExcludeFromGrouping: "^/webpack",
-					BeatVersion:         "8.0.0",
+					AgentBundle: &RumAgentBundle{
+						Enabled: new(bool),
+						URL:     "/rum/agent",
+						Path:    "rum-agent",
+					},
+					BeatVersion: "8.0.0",
 				},
 				Register: &RegisterConfig{
 					Ingest: &IngestConfig{
@@ -327,9 +644,73 @@ func Test_UnpackConfig(t *testing.T) {
 					MaxTransactionGroups:           1000,
 					HDRHistogramSignificantFigures: 2,
 					RUMUserAgentLRUSize:            123,
+					ServiceDestinations: ServiceDestinationAggregationConfig{
+						MaxGroups: 10000,
+					},
+					TraceSummaries: TraceSummaryAggregationConfig{
+						MaxTraces:   10000,
+						IdleTimeout: 30 * time.Second,
+					},
+					Metricsets: MetricsetAggregationConfig{
+						Interval:  time.Minute,
+						MaxGroups: 10000,
+					},
 				},
 				Sampling: SamplingConfig{
 					KeepUnsampled: false,
+					Tail: TailSamplingConfig{
+						DecisionWait: 10 * time.Second,
+						MaxTraces:    10000,
+					},
+					PoliciesFile: PolicyFileConfig{ReloadPeriod: 10 * time.Second},
+				},
+				PublishLimit: PublishLimitConfig{
+					Enabled:          true,
+					Min:              1,
+					Max:              100,
+					Initial:          10,
+					LatencyThreshold: 100 * time.Millisecond,
+					BackoffRatio:     0.9,
+					MaxWait:          time.Second,
+				},
+				OutputHealth: OutputHealthConfig{
+					Interval:         10 * time.Second,
+					MaxWait:          time.Second,
+					BreakerThreshold: 0.9,
+					ESConfig:         elasticsearch.DefaultConfig(),
+				},
+				Quota: QuotaConfig{
+					Enabled: true,
+					Services: []ServiceQuotaConfig{
+						{Name: "opbeans-go", QuotaLimits: QuotaLimits{EventsPerDay: 1000}},
+					},
+					Action:     "sample",
+					SampleRate: 0.1,
+				},
+				Labels: LabelsConfig{MaxKeysPerService: 1000},
+				ProcessArgs: ProcessArgsConfig{
+					Scrub:                true,
+					SensitiveFlagPattern: "(?i)passw(or)?d|pwd|secret|token|api[-_]?key|credential|auth",
+					RulesFile:            PolicyFileConfig{ReloadPeriod: 10 * time.Second},
+				},
+				Dedup: DedupConfig{
+					TTL:      2 * time.Minute,
+					MaxBytes: 10 * 1024 * 1024,
+				},
+				Capture: CaptureConfig{
+					Path:              "capture",
+					RequestsPerMinute: 10,
+					MaxFiles:          100,
+					MaxBodySize:       1024 * 1024,
+				},
+				ValidationSummary: ValidationSummaryConfig{Interval: time.Minute},
+				StreamLimits:      StreamLimitsConfig{Window: 10 * time.Second},
+				RepeatedErrors:    RepeatedErrorsConfig{Interval: time.Minute},
+				Spool: SpoolConfig{
+					Path:     "spool",
+					MaxBytes: 1024 * 1024 * 1024,
+					Rate:     1000,
+					URL:      "/admin/backfill",
 				},
 			},
 		},
@@ -504,3 +885,59 @@ func TestNewConfig_ESConfig(t *testing.T) {
 	assert.NotNil(t, cfg.APIKeyConfig.ESConfig)
 	assert.Equal(t, []string{"192.0.0.168:9200"}, []string(cfg.APIKeyConfig.ESConfig.Hosts))
 }
+
+func TestNewConfig_OutputHealth(t *testing.T) {
+	version := "8.0.0"
+	ucfg, err := common.NewConfigFrom(`{"output_health.enabled":true}`)
+	require.NoError(t, err)
+
+	// no es config given
+	_, err = NewConfig(version, ucfg, nil)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "output_health requires")
+
+	// with es config
+	outputESCfg := common.MustNewConfigFrom(`{"hosts":["192.0.0.168:9200"]}`)
+	cfg, err := NewConfig(version, ucfg, outputESCfg)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"192.0.0.168:9200"}, []string(cfg.OutputHealth.ESConfig.Hosts))
+
+	ucfg, err = common.NewConfigFrom(`{"output_health.breaker_threshold":1.5}`)
+	require.NoError(t, err)
+	_, err = NewConfig(version, ucfg, nil)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "output_health.breaker_threshold")
+}
+
+func TestNewConfig_MaxMemory(t *testing.T) {
+	version := "8.0.0"
+	ucfg, err := common.NewConfigFrom(`{"max_memory":1000}`)
+	require.NoError(t, err)
+	cfg, err := NewConfig(version, ucfg, nil)
+	require.NoError(t, err)
+	assert.Equal(t, int64(250), cfg.MaxUnpublishedBytes)
+
+	// an explicit limit, including unlimited, is kept
+	ucfg, err = common.NewConfigFrom(`{"max_memory":1000,"max_unpublished_bytes":0}`)
+	require.NoError(t, err)
+	cfg, err = NewConfig(version, ucfg, nil)
+	require.NoError(t, err)
+	assert.Equal(t, int64(0), cfg.MaxUnpublishedBytes)
+}
+
+func TestNewConfig_ESServiceToken(t *testing.T) {
+	version := "8.0.0"
+	ucfg, err := common.NewConfigFrom(`{"rum.enabled":true,"api_key.enabled":true}`)
+	require.NoError(t, err)
+
+	outputESCfg := common.MustNewConfigFrom(`{"hosts":["192.0.0.168:9200"],"service_token":"s3cr3t"}`)
+	cfg, err := NewConfig(version, ucfg, outputESCfg)
+	require.NoError(t, err)
+	assert.Equal(t, "s3cr3t", cfg.RumConfig.SourceMapping.ESConfig.ServiceToken)
+	assert.Equal(t, "s3cr3t", cfg.APIKeyConfig.ESConfig.ServiceToken)
+
+	outputESCfg = common.MustNewConfigFrom(`{"hosts":["192.0.0.168:9200"],"service_token":"s3cr3t","username":"elastic"}`)
+	_, err = NewConfig(version, ucfg, outputESCfg)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "only one of username/password, api_key, and service_token may be set")
+}
@@ -0,0 +1,58 @@
+// Licensed to Elasticsearch B.V. under one or more contributor
+// license agreements. See the NOTICE file distributed with
+// this work for additional information regarding copyright
+// ownership. Elasticsearch B.V. licenses this file to you under
+// the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package config
+
+import (
+	"net"
+
+	"github.com/pkg/errors"
+)
+
+// ContainerInferConfig holds configuration related to inferring the
+// container of agents which do not send container metadata.
+type ContainerInferConfig struct {
+	Enabled bool `config:"enabled"`
+
+	// CNIRanges holds the CIDR network ranges of container networks.
+	// The containers of agents connecting over TCP from these ranges,
+	// or from loopback addresses, are inferred.
+	CNIRanges []string `config:"cni_ranges"`
+
+	// ProcPath holds the path of the host's procfs. If empty, "/proc" is used.
+	ProcPath string `config:"proc_path"`
+}
+
+func (c *ContainerInferConfig) Validate() error {
+	if _, err := c.CNINetworks(); err != nil {
+		return err
+	}
+	return nil
+}
+
+// CNINetworks returns the parsed CNIRanges.
+func (c *ContainerInferConfig) CNINetworks() ([]*net.IPNet, error) {
+	networks := make([]*net.IPNet, len(c.CNIRanges))
+	for i, cidr := range c.CNIRanges {
+		_, network, err := net.ParseCIDR(cidr)
+		if err != nil {
+			return nil, errors.Wrapf(err, "invalid container_inference.cni_ranges entry %q", cidr)
+		}
+		networks[i] = network
+	}
+	return networks, nil
+}
@@ -0,0 +1,35 @@
+// Licensed to Elasticsearch B.V. under one or more contributor
+// license agreements. See the NOTICE file distributed with
+// this work for additional information regarding copyright
+// ownership. Elasticsearch B.V. licenses this file to you under
+// the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package config
+
+// ContextLimitsConfig holds limits applied to free-form context sent by
+// agents, i.e. context.custom and context.tags, while decoding events.
+// A value of zero means unlimited.
+type ContextLimitsConfig struct {
+	// MaxDepth is the maximum nesting depth of objects. Objects nested
+	// deeper than this are removed.
+	MaxDepth int `config:"max_depth" validate:"min=0"`
+
+	// MaxKeys is the maximum total number of keys, across all nesting
+	// levels. Keys beyond this are removed, in lexicographical order.
+	MaxKeys int `config:"max_keys" validate:"min=0"`
+
+	// MaxValueLength is the maximum length of string values, in
+	// characters. Longer values are truncated.
+	MaxValueLength int `config:"max_value_length" validate:"min=0"`
+}
@@ -0,0 +1,55 @@
+// Licensed to Elasticsearch B.V. under one or more contributor
+// license agreements. See the NOTICE file distributed with
+// this work for additional information regarding copyright
+// ownership. Elasticsearch B.V. licenses this file to you under
+// the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package config
+
+import (
+	"errors"
+	"time"
+)
+
+const (
+	defaultDedupTTL      = 2 * time.Minute
+	defaultDedupMaxBytes = 10 * 1024 * 1024
+)
+
+// DedupConfig holds configuration related to dropping events which have
+// already been received recently, e.g. when resent by retrying agents.
+type DedupConfig struct {
+	Enabled bool `config:"enabled"`
+
+	// TTL defines how long event IDs are remembered.
+	TTL time.Duration `config:"ttl"`
+
+	// MaxBytes is the approximate memory budget for remembered
+	// event IDs. Once exceeded, the oldest IDs are forgotten.
+	MaxBytes int64 `config:"max_bytes" validate:"min=1"`
+}
+
+func (c *DedupConfig) Validate() error {
+	if c.TTL <= 0 {
+		return errors.New("dedup.ttl must be greater than zero")
+	}
+	return nil
+}
+
+func defaultDedupConfig() DedupConfig {
+	return DedupConfig{
+		TTL:      defaultDedupTTL,
+		MaxBytes: defaultDedupMaxBytes,
+	}
+}
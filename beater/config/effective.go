@@ -0,0 +1,103 @@
+// Licensed to Elasticsearch B.V. under one or more contributor
+// license agreements. See the NOTICE file distributed with
+// this work for additional information regarding copyright
+// ownership. Elasticsearch B.V. licenses this file to you under
+// the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package config
+
+import (
+	"github.com/elastic/beats/v7/libbeat/common"
+)
+
+// redactedSettings holds the names of settings whose values are not exposed.
+var redactedSettings = common.MakeStringSet(
+	"secret_token",
+	"secret_tokens",
+	"api_key",
+	"password",
+	"passphrase",
+	"key_passphrase",
+	"service_token",
+)
+
+// Effective returns the settings of the resolved configuration,
+// with the values of secret settings redacted.
+func (c *Config) Effective() (map[string]interface{}, error) {
+	resolved, err := common.NewConfigFrom(c)
+	if err != nil {
+		return nil, err
+	}
+	var effective map[string]interface{}
+	if err := resolved.Unpack(&effective); err != nil {
+		return nil, err
+	}
+	effective["mode"] = c.Mode.String()
+	Redact(effective)
+	return effective, nil
+}
+
+// Features returns whether the optional features of the server are enabled.
+func (c *Config) Features() map[string]bool {
+	return map[string]bool{
+		"aggregation":                      c.Aggregation.Enabled,
+		"aggregation.service_destinations": c.Aggregation.ServiceDestinations.Enabled,
+		"aggregation.trace_summaries":      c.Aggregation.TraceSummaries.Enabled && c.FeatureFlags.TraceSummaries,
+		"api_key":                          c.APIKeyConfig.IsEnabled(),
+		"capture_personal_data":            c.AugmentEnabled,
+		"expvar":                           c.Expvar.IsEnabled(),
+		"instrumentation":                  c.SelfInstrumentation.IsEnabled(),
+		"jaeger.grpc":                      c.JaegerConfig.GRPC.Enabled,
+		"jaeger.http":                      c.JaegerConfig.HTTP.Enabled,
+		"kibana":                           c.Kibana.Enabled,
+		"output_health":                    c.OutputHealth.Enabled,
+		"publish_limit":                    c.PublishLimit.Enabled,
+		"read_only":                        c.ReadOnly,
+		"register.ingest.pipeline":         c.Register != nil && c.Register.Ingest != nil && c.Register.Ingest.Pipeline.IsEnabled(),
+		"rum":                              c.RumConfig.IsEnabled(),
+		"rum.source_mapping":               c.RumConfig.IsEnabled() && c.RumConfig.SourceMapping.IsEnabled(),
+		"sampling.keep_unsampled":          c.Sampling.KeepUnsampled,
+		"ssl":                              c.TLS.IsEnabled(),
+		"tenancy":                          c.Tenancy.Enabled,
+	}
+}
+
+// Redact replaces the values of secret settings in the given config values,
+// and removes fields excluded from unpacking.
+func Redact(value interface{}) {
+	switch value := value.(type) {
+	case map[string]interface{}:
+		for k, v := range value {
+			if s, ok := v.(string); ok && s != "" && redactedSettings.Has(k) {
+				value[k] = "xxxxx"
+				continue
+			}
+			if l, ok := v.([]interface{}); ok && redactedSettings.Has(k) {
+				for i := range l {
+					l[i] = "xxxxx"
+				}
+				continue
+			}
+			if k == "-" {
+				delete(value, k)
+				continue
+			}
+			Redact(v)
+		}
+	case []interface{}:
+		for _, v := range value {
+			Redact(v)
+		}
+	}
+}
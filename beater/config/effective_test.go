@@ -0,0 +1,120 @@
+// Licensed to Elasticsearch B.V. under one or more contributor
+// license agreements. See the NOTICE file distributed with
+// this work for additional information regarding copyright
+// ownership. Elasticsearch B.V. licenses this file to you under
+// the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package config
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/elastic/beats/v7/libbeat/common"
+)
+
+func TestEffective(t *testing.T) {
+	cfg := DefaultConfig("7.x")
+	cfg.SecretToken = "abc123"
+
+	effective, err := cfg.Effective()
+	require.NoError(t, err)
+	assert.Equal(t, "xxxxx", effective["secret_token"])
+	assert.Equal(t, "production", effective["mode"])
+	assert.NotContains(t, effective, "-")
+}
+
+func TestEffectiveCredentials(t *testing.T) {
+	ucfg := common.MustNewConfigFrom(map[string]interface{}{
+		"secret_token":                 "secret-token",
+		"tenancy.tenants":              []map[string]interface{}{{"id": "a", "secret_tokens": []string{"tenant-token"}}},
+		"instrumentation.api_key":      "instrumentation-api-key",
+		"instrumentation.secret_token": "instrumentation-secret-token",
+		"ssl.key_passphrase":           "ssl-passphrase",
+		"rum.enabled":                  true,
+		"rum.source_mapping.elasticsearch": map[string]interface{}{
+			"hosts":    []string{"localhost:9200"},
+			"username": "elastic",
+			"password": "sourcemap-password",
+		},
+		"api_key.enabled":                           true,
+		"api_key.elasticsearch.hosts":               []string{"localhost:9200"},
+		"api_key.elasticsearch.api_key":             "es-api-key",
+		"output_health.elasticsearch.hosts":         []string{"localhost:9200"},
+		"output_health.elasticsearch.service_token": "service-token",
+	})
+	cfg, err := NewConfig("7.x", ucfg, nil)
+	require.NoError(t, err)
+
+	effective, err := cfg.Effective()
+	require.NoError(t, err)
+	encoded, err := json.Marshal(effective)
+	require.NoError(t, err)
+	for _, secret := range []string{
+		"secret-token",
+		"tenant-token",
+		"instrumentation-api-key",
+		"instrumentation-secret-token",
+		"ssl-passphrase",
+		"sourcemap-password",
+		"es-api-key",
+		"service-token",
+	} {
+		assert.NotContains(t, string(encoded), secret)
+	}
+}
+
+func TestRedact(t *testing.T) {
+	value := map[string]interface{}{
+		"secret_token": "abc",
+		"api_key":      "",
+		"nested": []interface{}{
+			map[string]interface{}{"password": "secret", "username": "elastic"},
+			map[string]interface{}{"id": "team-a", "secret_tokens": []interface{}{"def", "ghi"}},
+		},
+		"-": "excluded",
+	}
+	Redact(value)
+	assert.Equal(t, map[string]interface{}{
+		"secret_token": "xxxxx",
+		"api_key":      "",
+		"nested": []interface{}{
+			map[string]interface{}{"password": "xxxxx", "username": "elastic"},
+			map[string]interface{}{"id": "team-a", "secret_tokens": []interface{}{"xxxxx", "xxxxx"}},
+		},
+	}, value)
+}
+
+func TestFeatures(t *testing.T) {
+	cfg := DefaultConfig("7.x")
+	features := cfg.Features()
+	assert.False(t, features["rum"])
+	assert.False(t, features["rum.source_mapping"])
+	assert.True(t, features["register.ingest.pipeline"])
+
+	enabled := true
+	cfg.RumConfig.Enabled = &enabled
+	features = cfg.Features()
+	assert.True(t, features["rum"])
+	assert.True(t, features["rum.source_mapping"])
+
+	// Trace summaries are only enabled with their feature flag.
+	cfg.Aggregation.TraceSummaries.Enabled = true
+	assert.False(t, cfg.Features()["aggregation.trace_summaries"])
+	cfg.FeatureFlags.TraceSummaries = true
+	assert.True(t, cfg.Features()["aggregation.trace_summaries"])
+}
@@ -0,0 +1,56 @@
+// Licensed to Elasticsearch B.V. under one or more contributor
+// license agreements. See the NOTICE file distributed with
+// this work for additional information regarding copyright
+// ownership. Elasticsearch B.V. licenses this file to you under
+// the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package config
+
+import (
+	"text/template"
+
+	"github.com/pkg/errors"
+
+	"github.com/elastic/apm-server/model"
+)
+
+// ErrorGroupingConfig holds configuration related to computing
+// error grouping keys.
+type ErrorGroupingConfig struct {
+	// KeyTemplate, if non-empty, is a text/template executed with
+	// model.GroupingKeyData to compute error grouping keys, in place
+	// of the default grouping key.
+	KeyTemplate string `config:"key_template"`
+}
+
+func (c *ErrorGroupingConfig) Validate() error {
+	if c.KeyTemplate == "" {
+		return nil
+	}
+	if _, err := model.ParseGroupingKeyTemplate(c.KeyTemplate); err != nil {
+		return errors.Wrap(err, "Invalid template for `error_grouping.key_template`")
+	}
+	return nil
+}
+
+// Template returns the parsed KeyTemplate, or nil if no template is
+// configured and the default grouping key should be used.
+func (c *ErrorGroupingConfig) Template() *template.Template {
+	if c.KeyTemplate == "" {
+		return nil
+	}
+	// The template has been validated when loading the config.
+	tmpl, _ := model.ParseGroupingKeyTemplate(c.KeyTemplate)
+	return tmpl
+}
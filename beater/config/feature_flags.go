@@ -0,0 +1,39 @@
+// Licensed to Elasticsearch B.V. under one or more contributor
+// license agreements. See the NOTICE file distributed with
+// this work for additional information regarding copyright
+// ownership. Elasticsearch B.V. licenses this file to you under
+// the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package config
+
+// FeatureFlagsConfig holds flags enabling experimental features.
+// Experimental features may change or be removed in any release.
+type FeatureFlagsConfig struct {
+	// Profiles enables the profile intake endpoint for agents.
+	// Without it, the endpoint is only available when the server
+	// profiles itself.
+	Profiles bool `config:"profiles"`
+
+	// TraceSummaries enables publishing trace summary documents,
+	// configured in aggregation.trace_summaries.
+	TraceSummaries bool `config:"trace_summaries"`
+}
+
+// Flags returns the state of each feature flag, keyed by name.
+func (c FeatureFlagsConfig) Flags() map[string]bool {
+	return map[string]bool{
+		"profiles":        c.Profiles,
+		"trace_summaries": c.TraceSummaries,
+	}
+}
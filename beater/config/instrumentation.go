@@ -28,16 +28,19 @@ const (
 	defaultCPUProfilingInterval  = 1 * time.Minute
 	defaultCPUProfilingDuration  = 10 * time.Second
 	defaultHeapProfilingInterval = 1 * time.Minute
+	defaultMetricsInterval       = 30 * time.Second
 )
 
 // InstrumentationConfig holds config information about self instrumenting the APM Server
 type InstrumentationConfig struct {
-	Enabled     *bool           `config:"enabled"`
-	Environment *string         `config:"environment"`
-	Hosts       urls            `config:"hosts"` //TODO(simi): add `validate:"nonzero"` again once https://github.com/elastic/go-ucfg/issues/147 is fixed
-	Profiling   ProfilingConfig `config:"profiling"`
-	APIKey      string          `config:"api_key"`
-	SecretToken string          `config:"secret_token"`
+	Enabled     *bool             `config:"enabled"`
+	Environment *string           `config:"environment"`
+	ServiceName string            `config:"service_name"`
+	Hosts       urls              `config:"hosts"` //TODO(simi): add `validate:"nonzero"` again once https://github.com/elastic/go-ucfg/issues/147 is fixed
+	Profiling   ProfilingConfig   `config:"profiling"`
+	Metrics     *MetricsReporting `config:"metrics"`
+	APIKey      string            `config:"api_key"`
+	SecretToken string            `config:"secret_token"`
 }
 
 func (c *InstrumentationConfig) Validate() error {
@@ -65,6 +68,9 @@ func (c *InstrumentationConfig) setup(log *logp.Logger) error {
 	if err := c.Profiling.Heap.setup(log); err != nil {
 		return err
 	}
+	if err := c.Metrics.setup(log); err != nil {
+		return err
+	}
 	return nil
 }
 
@@ -119,3 +125,26 @@ func (p *HeapProfiling) setup(log *logp.Logger) error {
 	}
 	return nil
 }
+
+// MetricsReporting holds config information about reporting the APM Server's
+// own process and runtime metrics
+type MetricsReporting struct {
+	Enabled  *bool         `config:"enabled"`
+	Interval time.Duration `config:"interval" validate:"positive"`
+}
+
+// IsEnabled indicates whether metrics reporting is enabled or not.
+// Metrics are reported by default when self instrumentation is enabled.
+func (m *MetricsReporting) IsEnabled() bool {
+	return m == nil || m.Enabled == nil || *m.Enabled
+}
+
+func (m *MetricsReporting) setup(log *logp.Logger) error {
+	if m == nil || !m.IsEnabled() {
+		return nil
+	}
+	if m.Interval <= 0 {
+		m.Interval = defaultMetricsInterval
+	}
+	return nil
+}
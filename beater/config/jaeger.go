@@ -19,6 +19,9 @@ package config
 
 import (
 	"crypto/tls"
+	"strings"
+
+	"github.com/pkg/errors"
 
 	"github.com/elastic/beats/v7/libbeat/common/transport/tlscommon"
 )
@@ -32,6 +35,8 @@ const (
 type JaegerConfig struct {
 	GRPC JaegerGRPCConfig `config:"grpc"`
 	HTTP JaegerHTTPConfig `config:"http"`
+
+	AttributeMappings []JaegerAttributeMapping `config:"attribute_mappings"`
 }
 
 // JaegerGRPCConfig holds configuration for the Jaeger gRPC server.
@@ -48,6 +53,44 @@ type JaegerHTTPConfig struct {
 	Host    string `config:"host"`
 }
 
+// JaegerAttributeMapping holds configuration for mapping a Jaeger process
+// tag or span tag to an Elastic APM field.
+type JaegerAttributeMapping struct {
+	Source string `config:"source" validate:"required"`
+	Target string `config:"target" validate:"required"`
+}
+
+// jaegerAttributeMappingTargets holds the fields which Jaeger tags may be
+// mapped to, in addition to "labels.<name>".
+var jaegerAttributeMappingTargets = []string{
+	"service.name",
+	"service.version",
+	"service.environment",
+	"service.node.name",
+	"host.hostname",
+	"host.name",
+	"container.id",
+	"kubernetes.namespace",
+	"kubernetes.pod.name",
+	"kubernetes.pod.uid",
+}
+
+// Validate validates the mapping target.
+func (m *JaegerAttributeMapping) Validate() error {
+	if strings.HasPrefix(m.Target, "labels.") && len(m.Target) > len("labels.") {
+		return nil
+	}
+	for _, target := range jaegerAttributeMappingTargets {
+		if m.Target == target {
+			return nil
+		}
+	}
+	return errors.Errorf(
+		"invalid target %q for attribute %q, must be one of %s or labels.<name>",
+		m.Target, m.Source, strings.Join(jaegerAttributeMappingTargets, ", "),
+	)
+}
+
 func (c *JaegerConfig) setup(cfg *Config) error {
 	if cfg.TLS == nil || !cfg.TLS.IsEnabled() {
 		return nil
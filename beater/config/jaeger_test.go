@@ -21,6 +21,11 @@ import (
 	"testing"
 
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/elastic/beats/v7/libbeat/common"
+
+	"github.com/elastic/apm-server/processor/otel"
 )
 
 func TestJaeger_default(t *testing.T) {
@@ -36,3 +41,37 @@ func TestJaeger_default(t *testing.T) {
 	}
 	assert.Equal(t, expected, defaultJaeger())
 }
+
+func TestJaegerAttributeMappings(t *testing.T) {
+	cfg, err := NewConfig("9.9.9", common.MustNewConfigFrom(map[string]interface{}{
+		"jaeger.attribute_mappings": []map[string]interface{}{
+			{"source": "deployment.environment", "target": "service.environment"},
+			{"source": "team", "target": "labels.owner"},
+		},
+	}), nil)
+	require.NoError(t, err)
+	assert.Equal(t, []JaegerAttributeMapping{
+		{Source: "deployment.environment", Target: "service.environment"},
+		{Source: "team", Target: "labels.owner"},
+	}, cfg.JaegerConfig.AttributeMappings)
+}
+
+func TestJaegerAttributeMappingsInvalid(t *testing.T) {
+	for name, mapping := range map[string]map[string]interface{}{
+		"unknown target": {"source": "team", "target": "service.owner"},
+		"empty label":    {"source": "team", "target": "labels."},
+		"missing source": {"target": "labels.team"},
+	} {
+		t.Run(name, func(t *testing.T) {
+			_, err := NewConfig("9.9.9", common.MustNewConfigFrom(map[string]interface{}{
+				"jaeger.attribute_mappings": []map[string]interface{}{mapping},
+			}), nil)
+			require.Error(t, err)
+		})
+	}
+}
+
+func TestJaegerAttributeMappingTargets(t *testing.T) {
+	// The targets accepted by the config must be supported by the OTel processor.
+	assert.ElementsMatch(t, otel.AttributeMappingTargets(), jaegerAttributeMappingTargets)
+}
@@ -0,0 +1,33 @@
+// Licensed to Elasticsearch B.V. under one or more contributor
+// license agreements. See the NOTICE file distributed with
+// this work for additional information regarding copyright
+// ownership. Elasticsearch B.V. licenses this file to you under
+// the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package config
+
+const defaultLabelsMaxKeysPerService = 1000
+
+// LabelsConfig holds configuration related to sanitizing event labels
+// before they are indexed.
+type LabelsConfig struct {
+	// MaxKeysPerService is the maximum number of distinct label keys
+	// indexed for a service per day. Labels with new keys beyond this
+	// are dropped. Zero means unlimited.
+	MaxKeysPerService int `config:"max_keys_per_service" validate:"min=0"`
+}
+
+func defaultLabelsConfig() LabelsConfig {
+	return LabelsConfig{MaxKeysPerService: defaultLabelsMaxKeysPerService}
+}
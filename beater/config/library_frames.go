@@ -0,0 +1,52 @@
+// Licensed to Elasticsearch B.V. under one or more contributor
+// license agreements. See the NOTICE file distributed with
+// this work for additional information regarding copyright
+// ownership. Elasticsearch B.V. licenses this file to you under
+// the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package config
+
+import (
+	"regexp"
+	"strings"
+
+	"github.com/pkg/errors"
+)
+
+// LibraryFramesConfig holds configuration related to classifying the
+// stack frames sent by backend agents as library frames.
+type LibraryFramesConfig struct {
+	// Patterns holds regular expressions matched against a frame's
+	// module, classname, filename and abs_path. Frames matching any
+	// pattern are marked as library frames.
+	Patterns []string `config:"patterns"`
+}
+
+func (c *LibraryFramesConfig) Validate() error {
+	for _, pattern := range c.Patterns {
+		if _, err := regexp.Compile(pattern); err != nil {
+			return errors.Wrapf(err, "Invalid regex for `library_frames.patterns`: ")
+		}
+	}
+	return nil
+}
+
+// Pattern returns a regular expression matching any of the configured
+// patterns, or an empty string if there are none.
+func (c *LibraryFramesConfig) Pattern() string {
+	if len(c.Patterns) == 0 {
+		return ""
+	}
+	return "(?:" + strings.Join(c.Patterns, ")|(?:") + ")"
+}
@@ -0,0 +1,42 @@
+// Licensed to Elasticsearch B.V. under one or more contributor
+// license agreements. See the NOTICE file distributed with
+// this work for additional information regarding copyright
+// ownership. Elasticsearch B.V. licenses this file to you under
+// the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package config
+
+import (
+	"regexp"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestLibraryFramesPattern(t *testing.T) {
+	assert.Equal(t, "", (&LibraryFramesConfig{}).Pattern())
+
+	c := &LibraryFramesConfig{Patterns: []string{"/vendor/", "site-packages/", `^jdk\.`}}
+	assert.NoError(t, c.Validate())
+	pattern := regexp.MustCompile(c.Pattern())
+	for _, s := range []string{"github.com/x/vendor/y.go", "/usr/lib/python3/site-packages/flask/app.py", "jdk.internal.reflect"} {
+		assert.True(t, pattern.MatchString(s), s)
+	}
+	for _, s := range []string{"main.go", "com.example.jdk.Handler"} {
+		assert.False(t, pattern.MatchString(s), s)
+	}
+
+	c = &LibraryFramesConfig{Patterns: []string{"("}}
+	assert.Error(t, c.Validate())
+}
@@ -19,7 +19,7 @@ package config
 
 import "strings"
 
-//Mode enumerates the APM Server env
+// Mode enumerates the APM Server env
 type Mode uint8
 
 const (
@@ -40,3 +40,11 @@ func (m *Mode) Unpack(s string) error {
 	*m = ModeProduction
 	return nil
 }
+
+// String returns the configuration value of the Mode
+func (m Mode) String() string {
+	if m == ModeExperimental {
+		return "experimental"
+	}
+	return "production"
+}
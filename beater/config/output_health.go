@@ -0,0 +1,109 @@
+// Licensed to Elasticsearch B.V. under one or more contributor
+// license agreements. See the NOTICE file distributed with
+// this work for additional information regarding copyright
+// ownership. Elasticsearch B.V. licenses this file to you under
+// the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package config
+
+import (
+	"time"
+
+	"github.com/pkg/errors"
+
+	"github.com/elastic/beats/v7/libbeat/common"
+	"github.com/elastic/beats/v7/libbeat/logp"
+
+	"github.com/elastic/apm-server/elasticsearch"
+)
+
+const (
+	defaultOutputHealthInterval         = 10 * time.Second
+	defaultOutputHealthMaxWait          = time.Second
+	defaultOutputHealthBreakerThreshold = 0.9
+)
+
+// OutputHealthConfig holds configuration related to throttling the
+// publishing of events while the Elasticsearch cluster is unhealthy.
+type OutputHealthConfig struct {
+	Enabled bool `config:"enabled"`
+
+	// Interval defines how often the cluster health and
+	// circuit breaker statistics are polled.
+	Interval time.Duration `config:"interval"`
+
+	// MaxWait defines how long a request may wait for the
+	// cluster to recover, before being rejected.
+	MaxWait time.Duration `config:"max_wait"`
+
+	// BreakerThreshold defines the fraction of a circuit breaker's
+	// limit above which a node is considered overloaded.
+	BreakerThreshold float64 `config:"breaker_threshold"`
+
+	ESConfig *elasticsearch.Config `config:"elasticsearch"`
+
+	esConfigured bool
+}
+
+func (c *OutputHealthConfig) Validate() error {
+	if c.Interval <= 0 {
+		return errors.New("output_health.interval must be positive")
+	}
+	if c.MaxWait < 0 {
+		return errors.New("output_health.max_wait must not be negative")
+	}
+	if c.BreakerThreshold <= 0 || c.BreakerThreshold > 1 {
+		return errors.New("output_health.breaker_threshold must be greater than 0 and at most 1")
+	}
+	return nil
+}
+
+func (c *OutputHealthConfig) setup(log *logp.Logger, outputESCfg *common.Config) error {
+	if !c.Enabled || c.esConfigured {
+		return nil
+	}
+	if outputESCfg == nil {
+		return errors.New("output_health requires output.elasticsearch or output_health.elasticsearch to be configured")
+	}
+	log.Info("Falling back to elasticsearch output for output health checks")
+	if err := outputESCfg.Unpack(c.ESConfig); err != nil {
+		return errors.Wrap(err, "unpacking Elasticsearch config into output health config")
+	}
+	return nil
+}
+
+func (c *OutputHealthConfig) Unpack(inp *common.Config) error {
+	// this type is needed to avoid a custom Unpack method
+	type tmpOutputHealthConfig OutputHealthConfig
+
+	cfg := tmpOutputHealthConfig(defaultOutputHealthConfig())
+	if err := inp.Unpack(&cfg); err != nil {
+		return errors.Wrap(err, "error unpacking output_health config")
+	}
+	*c = OutputHealthConfig(cfg)
+	if inp.HasField("elasticsearch") {
+		c.esConfigured = true
+	}
+	// Validate is not called for types with a custom Unpack method.
+	return c.Validate()
+}
+
+func defaultOutputHealthConfig() OutputHealthConfig {
+	return OutputHealthConfig{
+		Interval:         defaultOutputHealthInterval,
+		MaxWait:          defaultOutputHealthMaxWait,
+		BreakerThreshold: defaultOutputHealthBreakerThreshold,
+		ESConfig:         elasticsearch.DefaultConfig(),
+	}
+}
@@ -0,0 +1,47 @@
+// Licensed to Elasticsearch B.V. under one or more contributor
+// license agreements. See the NOTICE file distributed with
+// this work for additional information regarding copyright
+// ownership. Elasticsearch B.V. licenses this file to you under
+// the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package config
+
+import (
+	"errors"
+	"time"
+)
+
+const defaultPolicyFileReloadPeriod = 10 * time.Second
+
+// PolicyFileConfig holds configuration related to loading policies from a
+// YAML file, which is reloaded at runtime when it changes.
+type PolicyFileConfig struct {
+	// Path holds the path of the policy file. If empty, no policy
+	// file is loaded.
+	Path string `config:"path"`
+
+	// ReloadPeriod defines how often the file is checked for changes.
+	ReloadPeriod time.Duration `config:"reload_period"`
+}
+
+func (c *PolicyFileConfig) Validate() error {
+	if c.ReloadPeriod <= 0 {
+		return errors.New("policy file reload_period must be greater than zero")
+	}
+	return nil
+}
+
+func defaultPolicyFileConfig() PolicyFileConfig {
+	return PolicyFileConfig{ReloadPeriod: defaultPolicyFileReloadPeriod}
+}
@@ -0,0 +1,86 @@
+// Licensed to Elasticsearch B.V. under one or more contributor
+// license agreements. See the NOTICE file distributed with
+// this work for additional information regarding copyright
+// ownership. Elasticsearch B.V. licenses this file to you under
+// the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package config
+
+import (
+	"regexp"
+
+	"github.com/pkg/errors"
+
+	"github.com/elastic/beats/v7/libbeat/common"
+
+	"github.com/elastic/apm-server/policyfile"
+)
+
+const defaultProcessArgsSensitiveFlagPattern = "(?i)passw(or)?d|pwd|secret|token|api[-_]?key|credential|auth"
+
+// ProcessArgsConfig holds configuration related to the process
+// command line arguments (process.args) sent by agents.
+type ProcessArgsConfig struct {
+	// Scrub controls whether the values of sensitive command line
+	// flags are redacted, e.g. "--password=abc" or "--password abc".
+	Scrub bool `config:"scrub"`
+
+	// SensitiveFlagPattern is a regular expression matching the
+	// names of command line flags whose values are redacted.
+	SensitiveFlagPattern string `config:"sensitive_flag_pattern"`
+
+	// RulesFile, if its path is set, defines a YAML file holding
+	// scrub and sensitive_flag_pattern, overriding the settings above.
+	// The file is reloaded when it changes.
+	RulesFile PolicyFileConfig `config:"rules_file"`
+}
+
+func (c *ProcessArgsConfig) Validate() error {
+	if _, err := regexp.Compile(c.SensitiveFlagPattern); err != nil {
+		return errors.Wrapf(err, "Invalid regex for `process_args.sensitive_flag_pattern`: ")
+	}
+	if c.RulesFile.Path != "" {
+		if _, err := policyfile.Unpack(c.RulesFile.Path, c.UnpackRules); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// UnpackRules unpacks the scrub and sensitive_flag_pattern settings held
+// in a rules file over those of c, returning a ProcessArgsConfig.
+func (c ProcessArgsConfig) UnpackRules(cfg *common.Config) (interface{}, error) {
+	rules := struct {
+		Scrub                bool   `config:"scrub"`
+		SensitiveFlagPattern string `config:"sensitive_flag_pattern"`
+	}{c.Scrub, c.SensitiveFlagPattern}
+	if err := cfg.Unpack(&rules); err != nil {
+		return nil, err
+	}
+	if _, err := regexp.Compile(rules.SensitiveFlagPattern); err != nil {
+		return nil, errors.Wrapf(err, "Invalid regex for `sensitive_flag_pattern`: ")
+	}
+	return ProcessArgsConfig{
+		Scrub:                rules.Scrub,
+		SensitiveFlagPattern: rules.SensitiveFlagPattern,
+	}, nil
+}
+
+func defaultProcessArgsConfig() ProcessArgsConfig {
+	return ProcessArgsConfig{
+		Scrub:                true,
+		SensitiveFlagPattern: defaultProcessArgsSensitiveFlagPattern,
+		RulesFile:            defaultPolicyFileConfig(),
+	}
+}
@@ -0,0 +1,61 @@
+// Licensed to Elasticsearch B.V. under one or more contributor
+// license agreements. See the NOTICE file distributed with
+// this work for additional information regarding copyright
+// ownership. Elasticsearch B.V. licenses this file to you under
+// the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package config
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/elastic/beats/v7/libbeat/common"
+)
+
+func TestProcessArgsConfigInvalid(t *testing.T) {
+	_, err := NewConfig("9.9.9", common.MustNewConfigFrom(map[string]interface{}{
+		"process_args.sensitive_flag_pattern": "(",
+	}), nil)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "Invalid regex for `process_args.sensitive_flag_pattern`")
+}
+
+func TestProcessArgsConfigRulesFile(t *testing.T) {
+	dir, err := ioutil.TempDir("", "process_args")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+	path := filepath.Join(dir, "rules.yml")
+	require.NoError(t, ioutil.WriteFile(path, []byte(`sensitive_flag_pattern: "(?i)ssn"`), 0644))
+
+	cfg, err := NewConfig("9.9.9", common.MustNewConfigFrom(map[string]interface{}{
+		"process_args.rules_file.path": path,
+	}), nil)
+	require.NoError(t, err)
+	rules, err := cfg.ProcessArgs.UnpackRules(common.MustNewConfigFrom(`sensitive_flag_pattern: "(?i)ssn"`))
+	require.NoError(t, err)
+	assert.Equal(t, ProcessArgsConfig{Scrub: true, SensitiveFlagPattern: "(?i)ssn"}, rules)
+
+	require.NoError(t, ioutil.WriteFile(path, []byte(`sensitive_flag_pattern: "("`), 0644))
+	_, err = NewConfig("9.9.9", common.MustNewConfigFrom(map[string]interface{}{
+		"process_args.rules_file.path": path,
+	}), nil)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "Invalid regex for `sensitive_flag_pattern`")
+}
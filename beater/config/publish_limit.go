@@ -0,0 +1,78 @@
+// Licensed to Elasticsearch B.V. under one or more contributor
+// license agreements. See the NOTICE file distributed with
+// this work for additional information regarding copyright
+// ownership. Elasticsearch B.V. licenses this file to you under
+// the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package config
+
+import (
+	"errors"
+	"time"
+)
+
+const (
+	defaultPublishLimitMin              = 1
+	defaultPublishLimitMax              = 100
+	defaultPublishLimitInitial          = 10
+	defaultPublishLimitLatencyThreshold = 100 * time.Millisecond
+	defaultPublishLimitBackoffRatio     = 0.9
+	defaultPublishLimitMaxWait          = time.Second
+)
+
+// PublishLimitConfig holds configuration related to adaptively limiting the
+// number of concurrent requests publishing events.
+type PublishLimitConfig struct {
+	Enabled bool `config:"enabled"`
+
+	// Min, Max and Initial bound the concurrency limit, and define its
+	// value on startup.
+	Min     int `config:"min" validate:"min=1"`
+	Max     int `config:"max" validate:"min=1"`
+	Initial int `config:"initial" validate:"min=1"`
+
+	// LatencyThreshold defines the publish latency above which
+	// the limit is multiplied by BackoffRatio. Below the threshold,
+	// the limit is increased additively.
+	LatencyThreshold time.Duration `config:"latency_threshold"`
+	BackoffRatio     float64       `config:"backoff_ratio" validate:"min=0.1, max=0.99"`
+
+	// MaxWait defines how long a request may wait to publish,
+	// once the limit has been reached, before being rejected.
+	MaxWait time.Duration `config:"max_wait"`
+}
+
+func (c *PublishLimitConfig) Validate() error {
+	if c.Min > c.Max {
+		return errors.New("publish_limit.min must not be greater than publish_limit.max")
+	}
+	if c.LatencyThreshold <= 0 {
+		return errors.New("publish_limit.latency_threshold must be positive")
+	}
+	if c.Initial < c.Min || c.Initial > c.Max {
+		return errors.New("publish_limit.initial must be between publish_limit.min and publish_limit.max")
+	}
+	return nil
+}
+
+func defaultPublishLimitConfig() PublishLimitConfig {
+	return PublishLimitConfig{
+		Min:              defaultPublishLimitMin,
+		Max:              defaultPublishLimitMax,
+		Initial:          defaultPublishLimitInitial,
+		LatencyThreshold: defaultPublishLimitLatencyThreshold,
+		BackoffRatio:     defaultPublishLimitBackoffRatio,
+		MaxWait:          defaultPublishLimitMaxWait,
+	}
+}
@@ -0,0 +1,64 @@
+// Licensed to Elasticsearch B.V. under one or more contributor
+// license agreements. See the NOTICE file distributed with
+// this work for additional information regarding copyright
+// ownership. Elasticsearch B.V. licenses this file to you under
+// the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package config
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/elastic/beats/v7/libbeat/common"
+)
+
+func TestPublishLimitConfigInvalid(t *testing.T) {
+	for name, test := range map[string]struct {
+		cfg    map[string]interface{}
+		expect string
+	}{
+		"min greater than max": {
+			cfg:    map[string]interface{}{"publish_limit.min": 10, "publish_limit.max": 5, "publish_limit.initial": 5},
+			expect: "publish_limit.min must not be greater than publish_limit.max",
+		},
+		"initial out of range": {
+			cfg:    map[string]interface{}{"publish_limit.initial": 1000},
+			expect: "publish_limit.initial must be between publish_limit.min and publish_limit.max",
+		},
+		"non-positive latency threshold": {
+			cfg:    map[string]interface{}{"publish_limit.latency_threshold": "0s"},
+			expect: "publish_limit.latency_threshold must be positive",
+		},
+		"backoff ratio too high": {
+			cfg:    map[string]interface{}{"publish_limit.backoff_ratio": 1.5},
+			expect: "requires value > 0.99",
+		},
+	} {
+		t.Run(name, func(t *testing.T) {
+			_, err := NewConfig("9.9.9", common.MustNewConfigFrom(test.cfg), nil)
+			require.Error(t, err)
+			assert.Contains(t, err.Error(), test.expect)
+		})
+	}
+}
+
+func TestPublishLimitConfigDefault(t *testing.T) {
+	cfg, err := NewConfig("9.9.9", common.MustNewConfigFrom(map[string]interface{}{}), nil)
+	require.NoError(t, err)
+	assert.Equal(t, defaultPublishLimitConfig(), cfg.PublishLimit)
+	assert.False(t, cfg.PublishLimit.Enabled)
+}
@@ -0,0 +1,80 @@
+// Licensed to Elasticsearch B.V. under one or more contributor
+// license agreements. See the NOTICE file distributed with
+// this work for additional information regarding copyright
+// ownership. Elasticsearch B.V. licenses this file to you under
+// the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package config
+
+import (
+	"fmt"
+)
+
+const (
+	quotaActionReject = "reject"
+	quotaActionSample = "sample"
+
+	defaultQuotaSampleRate = 0.1
+)
+
+// QuotaConfig holds configuration related to per-service ingestion quotas.
+type QuotaConfig struct {
+	Enabled bool `config:"enabled"`
+
+	// Default holds the quota for services not listed in Services.
+	Default QuotaLimits `config:"default"`
+
+	// Services holds quotas for individual services.
+	Services []ServiceQuotaConfig `config:"services"`
+
+	// Action defines what is done with events of services exceeding
+	// their quota: they are either rejected, or sampled at SampleRate.
+	Action     string  `config:"action"`
+	SampleRate float64 `config:"sample_rate" validate:"min=0, max=1"`
+}
+
+// QuotaLimits holds daily ingestion limits. Zero values mean unlimited.
+type QuotaLimits struct {
+	EventsPerDay int64 `config:"events_per_day" validate:"min=0"`
+	BytesPerDay  int64 `config:"bytes_per_day" validate:"min=0"`
+}
+
+// ServiceQuotaConfig holds the quota for the service with the given name.
+type ServiceQuotaConfig struct {
+	Name        string `config:"name" validate:"required"`
+	QuotaLimits `config:",inline"`
+}
+
+func (c *QuotaConfig) Validate() error {
+	switch c.Action {
+	case quotaActionReject, quotaActionSample:
+	default:
+		return fmt.Errorf("quota.action must be one of %q or %q, got %q", quotaActionReject, quotaActionSample, c.Action)
+	}
+	seen := make(map[string]bool)
+	for _, service := range c.Services {
+		if seen[service.Name] {
+			return fmt.Errorf("duplicate quota for service %q", service.Name)
+		}
+		seen[service.Name] = true
+	}
+	return nil
+}
+
+func defaultQuotaConfig() QuotaConfig {
+	return QuotaConfig{
+		Action:     quotaActionReject,
+		SampleRate: defaultQuotaSampleRate,
+	}
+}
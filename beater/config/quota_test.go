@@ -0,0 +1,71 @@
+// Licensed to Elasticsearch B.V. under one or more contributor
+// license agreements. See the NOTICE file distributed with
+// this work for additional information regarding copyright
+// ownership. Elasticsearch B.V. licenses this file to you under
+// the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package config
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/elastic/beats/v7/libbeat/common"
+)
+
+func TestQuotaConfigInvalid(t *testing.T) {
+	for name, test := range map[string]struct {
+		cfg    map[string]interface{}
+		expect string
+	}{
+		"unknown action": {
+			cfg:    map[string]interface{}{"quota.action": "drop"},
+			expect: `quota.action must be one of "reject" or "sample", got "drop"`,
+		},
+		"sample rate too high": {
+			cfg:    map[string]interface{}{"quota.sample_rate": 1.5},
+			expect: "requires value > 1",
+		},
+		"negative limit": {
+			cfg:    map[string]interface{}{"quota.default.events_per_day": -1},
+			expect: "requires value < 0",
+		},
+		"duplicate service": {
+			cfg: map[string]interface{}{"quota.services": []map[string]interface{}{
+				{"name": "opbeans-go", "events_per_day": 1},
+				{"name": "opbeans-go", "bytes_per_day": 1},
+			}},
+			expect: `duplicate quota for service "opbeans-go"`,
+		},
+		"service without name": {
+			cfg:    map[string]interface{}{"quota.services": []map[string]interface{}{{"events_per_day": 1}}},
+			expect: "string value is not set",
+		},
+	} {
+		t.Run(name, func(t *testing.T) {
+			_, err := NewConfig("9.9.9", common.MustNewConfigFrom(test.cfg), nil)
+			require.Error(t, err)
+			assert.Contains(t, err.Error(), test.expect)
+		})
+	}
+}
+
+func TestQuotaConfigDefault(t *testing.T) {
+	cfg, err := NewConfig("9.9.9", common.MustNewConfigFrom(map[string]interface{}{}), nil)
+	require.NoError(t, err)
+	assert.Equal(t, defaultQuotaConfig(), cfg.Quota)
+	assert.False(t, cfg.Quota.Enabled)
+}
@@ -19,6 +19,9 @@ package config
 
 import (
 	"path/filepath"
+	"regexp"
+
+	"github.com/pkg/errors"
 
 	"github.com/elastic/beats/v7/libbeat/paths"
 )
@@ -27,6 +30,8 @@ const (
 	defaultAPMPipeline = "apm"
 )
 
+var routingFieldPattern = regexp.MustCompile(`^[a-zA-Z0-9_]+(\.[a-zA-Z0-9_]+)*$`)
+
 // RegisterConfig holds ingest config information
 type RegisterConfig struct {
 	Ingest *IngestConfig `config:"ingest"`
@@ -42,6 +47,19 @@ type PipelineConfig struct {
 	Enabled   *bool `config:"enabled"`
 	Overwrite *bool `config:"overwrite"`
 	Path      string
+
+	// RoutingField holds the name of the event field used as the _routing
+	// value of indexed documents, e.g. trace.id to co-locate the documents
+	// of a trace on the same shard.
+	RoutingField string `config:"routing_field"`
+}
+
+// Validate validates the configured routing field.
+func (c *PipelineConfig) Validate() error {
+	if c.RoutingField != "" && !routingFieldPattern.MatchString(c.RoutingField) {
+		return errors.Errorf("invalid routing_field %q", c.RoutingField)
+	}
+	return nil
 }
 
 // IsEnabled indicates whether pipeline registration is enabled or not
@@ -0,0 +1,56 @@
+// Licensed to Elasticsearch B.V. under one or more contributor
+// license agreements. See the NOTICE file distributed with
+// this work for additional information regarding copyright
+// ownership. Elasticsearch B.V. licenses this file to you under
+// the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package config
+
+import (
+	"errors"
+	"time"
+)
+
+const defaultRepeatedErrorsInterval = time.Minute
+
+// RepeatedErrorsConfig holds configuration related to suppressing request
+// errors logged repeatedly, such as the same validation error for each
+// request of a broken agent, in favour of logging how often they repeated.
+type RepeatedErrorsConfig struct {
+	Enabled bool `config:"enabled"`
+
+	// Interval defines how long repetitions of an error are suppressed
+	// after it is logged, before the number of repetitions is logged.
+	Interval time.Duration `config:"interval"`
+}
+
+func (c *RepeatedErrorsConfig) Validate() error {
+	if c.Interval <= 0 {
+		return errors.New("repeated_errors.interval must be greater than zero")
+	}
+	return nil
+}
+
+// SuppressFor returns how long repetitions of an error are suppressed,
+// or zero if they are not.
+func (c *RepeatedErrorsConfig) SuppressFor() time.Duration {
+	if !c.Enabled {
+		return 0
+	}
+	return c.Interval
+}
+
+func defaultRepeatedErrorsConfig() RepeatedErrorsConfig {
+	return RepeatedErrorsConfig{Interval: defaultRepeatedErrorsInterval}
+}
@@ -23,6 +23,7 @@ import (
 	"time"
 
 	"github.com/pkg/errors"
+	"github.com/ryanuber/go-glob"
 
 	"github.com/elastic/beats/v7/libbeat/common"
 	"github.com/elastic/beats/v7/libbeat/logp"
@@ -43,18 +44,58 @@ const (
 
 // RumConfig holds config information related to the RUM endpoint
 type RumConfig struct {
-	Enabled             *bool               `config:"enabled"`
-	EventRate           *EventRate          `config:"event_rate"`
-	AllowOrigins        []string            `config:"allow_origins"`
-	AllowHeaders        []string            `config:"allow_headers"`
-	ResponseHeaders     map[string][]string `config:"response_headers"`
-	LibraryPattern      string              `config:"library_pattern"`
-	ExcludeFromGrouping string              `config:"exclude_from_grouping"`
-	SourceMapping       *SourceMapping      `config:"source_mapping"`
+	Enabled             *bool                `config:"enabled"`
+	EventRate           *EventRate           `config:"event_rate"`
+	AllowOrigins        []string             `config:"allow_origins"`
+	AllowHeaders        []string             `config:"allow_headers"`
+	ResponseHeaders     map[string][]string  `config:"response_headers"`
+	LibraryPattern      string               `config:"library_pattern"`
+	ExcludeFromGrouping string               `config:"exclude_from_grouping"`
+	SourceMapping       *SourceMapping       `config:"source_mapping"`
+	AllowServiceNames   []string             `config:"allow_service_names"`
+	OriginServiceNames  []OriginServiceNames `config:"allow_service_names_by_origin"`
+	AllowEventTypes     []string             `config:"allow_event_types"`
+	OriginEventTypes    []OriginEventTypes   `config:"allow_event_types_by_origin"`
+	ServiceEventTypes   []ServiceEventTypes  `config:"allow_event_types_by_service"`
+	AgentBundle         *RumAgentBundle      `config:"agent_bundle"`
+
+	// TraceContinuationStrategy, if non-empty, is returned to RUM agents
+	// in central configuration responses for services not configured
+	// with a strategy in Kibana.
+	TraceContinuationStrategy string `config:"trace_continuation_strategy"`
 
 	BeatVersion string
 }
 
+// RumAgentBundle holds config information about serving the RUM agent
+// bundles found in Path, at URL/<version>/<file>.
+type RumAgentBundle struct {
+	Enabled *bool  `config:"enabled"`
+	URL     string `config:"url"`
+	Path    string `config:"path"`
+}
+
+// OriginServiceNames restricts the service names accepted from
+// RUM agents running on pages served from a matching origin.
+type OriginServiceNames struct {
+	Origin       string   `config:"origin" validate:"required"`
+	ServiceNames []string `config:"service_names"`
+}
+
+// OriginEventTypes restricts the event types accepted from RUM
+// agents running on pages served from a matching origin.
+type OriginEventTypes struct {
+	Origin     string   `config:"origin" validate:"required"`
+	EventTypes []string `config:"event_types"`
+}
+
+// ServiceEventTypes restricts the event types accepted from RUM
+// agents claiming the given service name.
+type ServiceEventTypes struct {
+	ServiceName string   `config:"service_name" validate:"required"`
+	EventTypes  []string `config:"event_types"`
+}
+
 // EventRate holds config information about event rate limiting
 type EventRate struct {
 	Limit   int `config:"limit"`
@@ -67,6 +108,7 @@ type SourceMapping struct {
 	Enabled      *bool                 `config:"enabled"`
 	IndexPattern string                `config:"index_pattern"`
 	ESConfig     *elasticsearch.Config `config:"elasticsearch"`
+	MaxWait      time.Duration         `config:"max_wait" validate:"min=0"`
 	esConfigured bool
 
 	initStoreOnce sync.Once
@@ -79,11 +121,56 @@ func (c *RumConfig) IsEnabled() bool {
 	return c != nil && (c.Enabled != nil && *c.Enabled)
 }
 
+// IsEnabled indicates whether serving RUM agent bundles is enabled or not
+func (b *RumAgentBundle) IsEnabled() bool {
+	return b != nil && (b.Enabled == nil || *b.Enabled)
+}
+
 // IsEnabled indicates whether sourcemap handling is enabled or not
 func (s *SourceMapping) IsEnabled() bool {
 	return s == nil || s.Enabled == nil || *s.Enabled
 }
 
+// AllowedServiceNames returns the service names that RUM events sent from
+// origin may claim, and whether service names are restricted at all.
+//
+// If allow_service_names_by_origin is configured, the first entry whose
+// pattern matches origin is used; if no entry matches, including when the
+// request has no origin, no service name is allowed. An entry with an empty
+// list of service names allows none. Otherwise allow_service_names applies,
+// with an empty list allowing any service name.
+func (c *RumConfig) AllowedServiceNames(origin string) (names []string, restricted bool) {
+	if len(c.OriginServiceNames) > 0 {
+		for _, o := range c.OriginServiceNames {
+			if glob.Glob(o.Origin, origin) {
+				return o.ServiceNames, true
+			}
+		}
+		return nil, true
+	}
+	return c.AllowServiceNames, len(c.AllowServiceNames) > 0
+}
+
+// AllowedEventTypes returns the event types that RUM agents claiming the
+// given service name, sent from origin, may send, and whether event types
+// are restricted at all. Service-specific lists take precedence over
+// origin-specific lists, which take precedence over allow_event_types; the
+// first matching entry wins. A matching entry with an empty list of event
+// types allows none, while an empty allow_event_types allows any.
+func (c *RumConfig) AllowedEventTypes(origin, serviceName string) (types []string, restricted bool) {
+	for _, s := range c.ServiceEventTypes {
+		if s.ServiceName == serviceName {
+			return s.EventTypes, true
+		}
+	}
+	for _, o := range c.OriginEventTypes {
+		if glob.Glob(o.Origin, origin) {
+			return o.EventTypes, true
+		}
+	}
+	return c.AllowEventTypes, len(c.AllowEventTypes) > 0
+}
+
 // MemoizedSourcemapStore creates the sourcemap store once and then caches it
 //
 // TODO(axw) move this logic out of beater/config. This is a consumer of config,
@@ -106,6 +193,7 @@ func (c *RumConfig) MemoizedSourcemapStore() (*sourcemap.Store, error) {
 			c.SourceMapping.storeError = err
 			return
 		}
+		store.WaitTimeout = c.SourceMapping.MaxWait
 		c.SourceMapping.store = store
 	})
 	return c.SourceMapping.store, c.SourceMapping.storeError
@@ -122,6 +210,28 @@ func (c *RumConfig) setup(log *logp.Logger, outputESCfg *common.Config) error {
 	if _, err := regexp.Compile(c.ExcludeFromGrouping); err != nil {
 		return errors.Wrapf(err, "Invalid regex for `exclude_from_grouping`: ")
 	}
+	eventTypes := [][]string{c.AllowEventTypes}
+	for _, o := range c.OriginEventTypes {
+		eventTypes = append(eventTypes, o.EventTypes)
+	}
+	for _, s := range c.ServiceEventTypes {
+		eventTypes = append(eventTypes, s.EventTypes)
+	}
+	for _, types := range eventTypes {
+		for _, t := range types {
+			switch t {
+			case "transaction", "span", "error", "metricset":
+			default:
+				return errors.Errorf("Invalid event type %q in `allow_event_types`", t)
+			}
+		}
+	}
+
+	switch c.TraceContinuationStrategy {
+	case "", "continue", "restart", "restart_external":
+	default:
+		return errors.Errorf("Invalid `trace_continuation_strategy` %q", c.TraceContinuationStrategy)
+	}
 
 	if c.SourceMapping == nil || c.SourceMapping.esConfigured {
 		return nil
@@ -165,6 +275,7 @@ func (s *SourceMapping) Unpack(inp *common.Config) error {
 	s.Enabled = cfg.Enabled
 	s.IndexPattern = cfg.IndexPattern
 	s.ESConfig = cfg.ESConfig
+	s.MaxWait = cfg.MaxWait
 
 	if inp.HasField("elasticsearch") {
 		s.esConfigured = true
@@ -191,6 +302,11 @@ func defaultRum(beatVersion string) *RumConfig {
 		SourceMapping:       defaultSourcemapping(),
 		LibraryPattern:      defaultLibraryPattern,
 		ExcludeFromGrouping: defaultExcludeFromGrouping,
-		BeatVersion:         beatVersion,
+		AgentBundle: &RumAgentBundle{
+			Enabled: new(bool),
+			URL:     "/rum/agent",
+			Path:    "rum-agent",
+		},
+		BeatVersion: beatVersion,
 	}
 }
@@ -23,6 +23,9 @@ import (
 	"time"
 
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/elastic/beats/v7/libbeat/logp"
 
 	"github.com/elastic/apm-server/elasticsearch"
 )
@@ -46,6 +49,76 @@ func TestDefaultRum(t *testing.T) {
 	assert.Equal(t, defaultRum("7.0.0"), c.RumConfig)
 }
 
+func TestRumAllowedServiceNames(t *testing.T) {
+	c := &RumConfig{
+		AllowServiceNames: []string{"frontend"},
+		OriginServiceNames: []OriginServiceNames{
+			{Origin: "https://shop.example.com", ServiceNames: []string{"shop"}},
+			{Origin: "https://*.example.com", ServiceNames: []string{"site", "blog"}},
+		},
+	}
+	assertAllowed := func(c *RumConfig, origin string, names []string, restricted bool) {
+		t.Helper()
+		allowed, ok := c.AllowedServiceNames(origin)
+		assert.Equal(t, names, allowed)
+		assert.Equal(t, restricted, ok)
+	}
+	// Requests with no origin, or an origin matching no entry, are
+	// not allowed any service name.
+	assertAllowed(c, "", nil, true)
+	assertAllowed(c, "https://example.org", nil, true)
+	assertAllowed(c, "https://shop.example.com", []string{"shop"}, true)
+	assertAllowed(c, "https://blog.example.com", []string{"site", "blog"}, true)
+
+	assertAllowed(&RumConfig{AllowServiceNames: []string{"frontend"}}, "", []string{"frontend"}, true)
+	assertAllowed(&RumConfig{}, "https://example.org", nil, false)
+}
+
+func TestRumAllowedEventTypes(t *testing.T) {
+	c := &RumConfig{
+		AllowEventTypes: []string{"transaction", "span", "error"},
+		OriginEventTypes: []OriginEventTypes{
+			{Origin: "https://*.example.com", EventTypes: []string{"error"}},
+		},
+		ServiceEventTypes: []ServiceEventTypes{
+			{ServiceName: "shop", EventTypes: []string{"transaction"}},
+		},
+	}
+	assertAllowed := func(c *RumConfig, origin, service string, types []string, restricted bool) {
+		t.Helper()
+		allowed, ok := c.AllowedEventTypes(origin, service)
+		assert.Equal(t, types, allowed)
+		assert.Equal(t, restricted, ok)
+	}
+	assertAllowed(c, "", "frontend", []string{"transaction", "span", "error"}, true)
+	assertAllowed(c, "https://blog.example.com", "frontend", []string{"error"}, true)
+	assertAllowed(c, "https://blog.example.com", "shop", []string{"transaction"}, true)
+	assertAllowed(&RumConfig{}, "https://example.org", "frontend", nil, false)
+
+	// An explicitly empty list of event types allows none.
+	c.ServiceEventTypes = append(c.ServiceEventTypes, ServiceEventTypes{ServiceName: "blocked", EventTypes: []string{}})
+	assertAllowed(c, "", "blocked", []string{}, true)
+}
+
+func TestRumSetupInvalidEventType(t *testing.T) {
+	truthy := true
+	c := &RumConfig{
+		Enabled:          &truthy,
+		OriginEventTypes: []OriginEventTypes{{Origin: "*", EventTypes: []string{"profile"}}},
+	}
+	err := c.setup(logp.NewLogger("test"), nil)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), `"profile"`)
+}
+
+func TestRumSetupInvalidTraceContinuationStrategy(t *testing.T) {
+	truthy := true
+	c := &RumConfig{Enabled: &truthy, TraceContinuationStrategy: "restart_all"}
+	err := c.setup(logp.NewLogger("test"), nil)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), `"restart_all"`)
+}
+
 func TestMemoizedSourcemapMapper(t *testing.T) {
 	truthy := true
 	esConfig := elasticsearch.Config{Hosts: []string{"localhost:0"}}
@@ -17,11 +17,218 @@
 
 package config
 
+import (
+	"errors"
+	"time"
+
+	"github.com/ryanuber/go-glob"
+
+	"github.com/elastic/beats/v7/libbeat/common"
+
+	"github.com/elastic/apm-server/policyfile"
+)
+
+const (
+	defaultTailSamplingDecisionWait = 10 * time.Second
+	defaultTailSamplingMaxTraces    = 10000
+)
+
 // SamplingConfig holds configuration related to sampling.
 type SamplingConfig struct {
 	// KeepUnsampled controls whether unsampled
 	// transactions should be recorded.
 	KeepUnsampled bool `config:"keep_unsampled"`
+
+	// Rules holds the head-based sampling rates desired for services,
+	// reported to agents in intake responses. The first matching rule
+	// applies. The reported rates are independent of the sampling rate
+	// agents are given by central agent configuration.
+	//
+	// Rules matching on transaction type or name are instead evaluated
+	// by the server for each sampled transaction it receives.
+	Rules SamplingRules `config:"rules"`
+
+	// Tail holds configuration for tail-based sampling, deciding
+	// which traces to keep once their root transaction is received.
+	Tail TailSamplingConfig `config:"tail"`
+
+	// PoliciesFile, if its path is set, defines a YAML file holding
+	// the sampling rules under "rules" and the tail-based sampling
+	// policies under "tail.policies", in place of Rules and
+	// Tail.Policies. The file is watched, and both are replaced
+	// together when it changes.
+	PoliciesFile PolicyFileConfig `config:"policies_file"`
+}
+
+// SamplingPolicies holds the contents of a sampling policies file.
+type SamplingPolicies struct {
+	Rules        SamplingRules              `config:"rules"`
+	TailPolicies []TailSamplingPolicyConfig `config:"tail.policies"`
+}
+
+// TailSamplingConfig holds configuration related to tail-based sampling.
+type TailSamplingConfig struct {
+	Enabled bool `config:"enabled"`
+
+	// DecisionWait defines how long the events of a trace are held
+	// waiting for its root transaction.
+	DecisionWait time.Duration `config:"decision_wait"`
+
+	// MaxTraces defines the maximum number of traces held waiting for
+	// their root transaction. Events of further traces are kept.
+	MaxTraces int `config:"max_traces"`
+
+	// Policies holds the policies deciding which traces to keep. The
+	// first matching policy applies; traces matching none are kept.
+	Policies []TailSamplingPolicyConfig `config:"policies"`
+
+	// Annotate controls whether the root transactions of traces kept
+	// by a policy are labeled with the name of the policy.
+	Annotate bool `config:"annotate"`
+}
+
+// TailSamplingPolicyConfig holds the fraction of traces to keep among
+// those matching the policy. Empty criteria match any trace.
+type TailSamplingPolicyConfig struct {
+	// Name identifies the policy in metrics and annotations. It
+	// defaults to the policy's index.
+	Name string `config:"name"`
+
+	// Service, Environment, TransactionType, and TransactionName hold
+	// glob patterns matched against the root transaction of traces.
+	Service         string `config:"service"`
+	Environment     string `config:"environment"`
+	TransactionType string `config:"transaction.type"`
+	TransactionName string `config:"transaction.name"`
+
+	// MinDuration matches traces whose root transaction took at
+	// least as long.
+	MinDuration time.Duration `config:"trace.min_duration"`
+
+	// HasError matches traces containing an error.
+	HasError bool `config:"trace.has_error"`
+
+	Rate float64 `config:"rate" validate:"min=0, max=1"`
+}
+
+func (c *TailSamplingConfig) Validate() error {
+	if c.DecisionWait <= 0 {
+		return errors.New("sampling.tail.decision_wait must be greater than zero")
+	}
+	if c.MaxTraces <= 0 {
+		return errors.New("sampling.tail.max_traces must be greater than zero")
+	}
+	return nil
+}
+
+func (c *SamplingConfig) Validate() error {
+	tailPolicies := c.Tail.Policies
+	if c.PoliciesFile.Path != "" {
+		v, err := policyfile.Unpack(c.PoliciesFile.Path, UnpackSamplingPolicies)
+		if err != nil {
+			return err
+		}
+		tailPolicies = v.(SamplingPolicies).TailPolicies
+	}
+	if c.Tail.Enabled && len(tailPolicies) == 0 {
+		if c.PoliciesFile.Path != "" {
+			return errors.New("sampling.policies_file must hold tail.policies when tail-based sampling is enabled")
+		}
+		return errors.New("sampling.tail.policies must be set when tail-based sampling is enabled")
+	}
+	return nil
+}
+
+// UnpackSamplingPolicies unpacks the sampling rules and tail-based sampling
+// policies held in a sampling policies file, returning a SamplingPolicies.
+func UnpackSamplingPolicies(cfg *common.Config) (interface{}, error) {
+	var policies SamplingPolicies
+	if err := cfg.Unpack(&policies); err != nil {
+		return nil, err
+	}
+	return policies, nil
+}
+
+// SamplingRuleConfig holds the head-based sampling rate desired for
+// services matching the rule.
+type SamplingRuleConfig struct {
+	// Service and Environment hold glob patterns matching the service
+	// name and environment. Empty patterns match any service name or
+	// environment.
+	Service     string `config:"service"`
+	Environment string `config:"environment"`
+
+	// TransactionType and TransactionName hold glob patterns matching
+	// the type and name of transactions. Rules with either set apply to
+	// individual transactions, and are evaluated server-side.
+	TransactionType string `config:"transaction.type"`
+	TransactionName string `config:"transaction.name"`
+
+	Rate float64 `config:"rate" validate:"min=0, max=1"`
+}
+
+// SamplingRules holds sampling rules, of which the first matching applies.
+type SamplingRules []SamplingRuleConfig
+
+// Rate returns the head-based sampling rate desired for the service with
+// the given name and environment, and whether any rule matched. Rules
+// matching on transaction type or name are ignored.
+func (rules SamplingRules) Rate(serviceName, environment string) (float64, bool) {
+	for _, rule := range rules {
+		if !rule.isTransactionRule() && rule.matchesService(serviceName, environment) {
+			return rule.Rate, true
+		}
+	}
+	return 0, false
+}
+
+// HasTransactionRules reports whether any rule matches on transaction
+// type or name, requiring transactions to be sampled server-side.
+func (rules SamplingRules) HasTransactionRules() bool {
+	for _, rule := range rules {
+		if rule.isTransactionRule() {
+			return true
+		}
+	}
+	return false
+}
+
+// TransactionRate returns the sampling rate the server should apply to a
+// transaction with the given type and name, sent by the service with the
+// given name and environment. The boolean result is false if the first
+// matching rule does not match on transaction type or name, in which case
+// the agent's sampling decision stands.
+func (rules SamplingRules) TransactionRate(serviceName, environment, transactionType, transactionName string) (float64, bool) {
+	for _, rule := range rules {
+		if !rule.matchesService(serviceName, environment) {
+			continue
+		}
+		if !rule.isTransactionRule() {
+			return 0, false
+		}
+		if rule.TransactionType != "" && !glob.Glob(rule.TransactionType, transactionType) {
+			continue
+		}
+		if rule.TransactionName != "" && !glob.Glob(rule.TransactionName, transactionName) {
+			continue
+		}
+		return rule.Rate, true
+	}
+	return 0, false
+}
+
+func (r *SamplingRuleConfig) isTransactionRule() bool {
+	return r.TransactionType != "" || r.TransactionName != ""
+}
+
+func (r *SamplingRuleConfig) matchesService(serviceName, environment string) bool {
+	if r.Service != "" && !glob.Glob(r.Service, serviceName) {
+		return false
+	}
+	if r.Environment != "" && !glob.Glob(r.Environment, environment) {
+		return false
+	}
+	return true
 }
 
 func defaultSamplingConfig() SamplingConfig {
@@ -29,5 +236,10 @@ func defaultSamplingConfig() SamplingConfig {
 		// In a future major release we will set this to
 		// false, and then later remove the option.
 		KeepUnsampled: true,
+		Tail: TailSamplingConfig{
+			DecisionWait: defaultTailSamplingDecisionWait,
+			MaxTraces:    defaultTailSamplingMaxTraces,
+		},
+		PoliciesFile: defaultPolicyFileConfig(),
 	}
 }
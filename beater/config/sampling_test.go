@@ -0,0 +1,153 @@
+// Licensed to Elasticsearch B.V. under one or more contributor
+// license agreements. See the NOTICE file distributed with
+// this work for additional information regarding copyright
+// ownership. Elasticsearch B.V. licenses this file to you under
+// the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package config
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/elastic/beats/v7/libbeat/common"
+)
+
+func TestSamplingRate(t *testing.T) {
+	rules := SamplingRules{
+		{Service: "opbeans-*", Environment: "production", Rate: 0.1},
+		{Service: "opbeans-*", Rate: 0.5},
+		{Environment: "staging", Rate: 0},
+	}
+	for _, test := range []struct {
+		service, environment string
+		rate                 float64
+		ok                   bool
+	}{
+		{"opbeans-go", "production", 0.1, true},
+		{"opbeans-go", "dev", 0.5, true},
+		{"shop", "staging", 0, true},
+		{"shop", "production", 0, false},
+	} {
+		rate, ok := rules.Rate(test.service, test.environment)
+		assert.Equal(t, test.ok, ok, test.service+"/"+test.environment)
+		assert.Equal(t, test.rate, rate, test.service+"/"+test.environment)
+	}
+
+	_, ok := SamplingRules(nil).Rate("opbeans-go", "production")
+	assert.False(t, ok)
+}
+
+func TestSamplingTransactionRate(t *testing.T) {
+	rules := SamplingRules{
+		{TransactionType: "request", Rate: 1},
+		{Service: "opbeans-*", TransactionType: "messaging", Rate: 0.01},
+		{Service: "opbeans-*", Rate: 0.5},
+		{TransactionName: "GET /healthcheck", Rate: 0},
+	}
+	assert.True(t, rules.HasTransactionRules())
+	assert.False(t, SamplingRules{{Rate: 0.5}}.HasTransactionRules())
+
+	for _, test := range []struct {
+		service, txType, txName string
+		rate                    float64
+		ok                      bool
+	}{
+		{"opbeans-go", "request", "GET /", 1, true},
+		{"opbeans-go", "messaging", "consume", 0.01, true},
+		// The service-wide rule matches first; the agent's decision stands.
+		{"opbeans-go", "scheduled", "GET /healthcheck", 0, false},
+		{"shop", "scheduled", "GET /healthcheck", 0, true},
+		{"shop", "scheduled", "GET /", 0, false},
+	} {
+		rate, ok := rules.TransactionRate(test.service, "production", test.txType, test.txName)
+		assert.Equal(t, test.ok, ok, test.service+"/"+test.txType)
+		assert.Equal(t, test.rate, rate, test.service+"/"+test.txType)
+	}
+
+	// Rules matching on transactions are not reported to agents.
+	rate, ok := rules.Rate("opbeans-go", "production")
+	assert.True(t, ok)
+	assert.Equal(t, 0.5, rate)
+	_, ok = rules.Rate("shop", "production")
+	assert.False(t, ok)
+}
+
+func TestTailSamplingConfigValidate(t *testing.T) {
+	c := defaultSamplingConfig()
+	assert.NoError(t, c.Validate())
+	assert.NoError(t, c.Tail.Validate())
+
+	c.Tail.Enabled = true
+	assert.EqualError(t, c.Validate(), "sampling.tail.policies must be set when tail-based sampling is enabled")
+
+	c.Tail.Policies = []TailSamplingPolicyConfig{{Rate: 1}}
+	assert.NoError(t, c.Validate())
+	c.Tail.DecisionWait = 0
+	assert.EqualError(t, c.Tail.Validate(), "sampling.tail.decision_wait must be greater than zero")
+}
+
+func TestSamplingConfigPoliciesFile(t *testing.T) {
+	dir, err := ioutil.TempDir("", "sampling")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+	path := filepath.Join(dir, "policies.yml")
+	require.NoError(t, ioutil.WriteFile(path, []byte(`
+rules:
+  - service: "opbeans-*"
+    rate: 0.5
+  - transaction.type: messaging
+    rate: 0.01
+tail:
+  policies:
+    - name: errors
+      trace.has_error: true
+      rate: 1
+    - rate: 0.1
+`), 0644))
+
+	c := defaultSamplingConfig()
+	c.Tail.Enabled = true
+	c.PoliciesFile.Path = path
+	assert.NoError(t, c.Validate())
+
+	cfg, err := common.LoadFile(path)
+	require.NoError(t, err)
+	policies, err := UnpackSamplingPolicies(cfg)
+	require.NoError(t, err)
+	assert.Equal(t, SamplingPolicies{
+		Rules: SamplingRules{
+			{Service: "opbeans-*", Rate: 0.5},
+			{TransactionType: "messaging", Rate: 0.01},
+		},
+		TailPolicies: []TailSamplingPolicyConfig{
+			{Name: "errors", HasError: true, Rate: 1},
+			{Rate: 0.1},
+		},
+	}, policies)
+
+	require.NoError(t, ioutil.WriteFile(path, []byte("tail.policies: [{rate: 2}]"), 0644))
+	assert.Error(t, c.Validate())
+
+	// Tail-based sampling requires the file to hold its policies.
+	require.NoError(t, ioutil.WriteFile(path, []byte("rules: [{rate: 0.5}]"), 0644))
+	assert.EqualError(t, c.Validate(), "sampling.policies_file must hold tail.policies when tail-based sampling is enabled")
+	c.Tail.Enabled = false
+	assert.NoError(t, c.Validate())
+}
@@ -0,0 +1,32 @@
+// Licensed to Elasticsearch B.V. under one or more contributor
+// license agreements. See the NOTICE file distributed with
+// this work for additional information regarding copyright
+// ownership. Elasticsearch B.V. licenses this file to you under
+// the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package config
+
+import "time"
+
+// SpanStacktraceConfig holds configuration related to limiting the cost
+// of indexing span stacktraces.
+type SpanStacktraceConfig struct {
+	// MinDuration is the minimum duration of spans whose stacktraces
+	// are indexed. Zero means stacktraces are indexed for all spans.
+	MinDuration time.Duration `config:"min_duration" validate:"min=0"`
+
+	// MaxFrames is the maximum number of frames indexed per span
+	// stacktrace. Zero means unlimited.
+	MaxFrames int `config:"max_frames" validate:"min=0"`
+}
@@ -0,0 +1,56 @@
+// Licensed to Elasticsearch B.V. under one or more contributor
+// license agreements. See the NOTICE file distributed with
+// this work for additional information regarding copyright
+// ownership. Elasticsearch B.V. licenses this file to you under
+// the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package config
+
+const (
+	defaultSpoolPath     = "spool"
+	defaultSpoolMaxBytes = 1024 * 1024 * 1024
+	defaultSpoolRate     = 1000
+	defaultSpoolURL      = "/admin/backfill"
+)
+
+// SpoolConfig holds configuration related to spooling events to local disk
+// while the output is unavailable, and backfilling them once it recovers.
+type SpoolConfig struct {
+	Enabled bool `config:"enabled"`
+
+	// Path is the directory spooled events are written to.
+	// Relative paths are resolved against the data path.
+	Path string `config:"path"`
+
+	// MaxBytes is the maximum size of the spooled events on disk.
+	// Once reached, requests are rejected as if no spool was configured.
+	MaxBytes int64 `config:"max_bytes" validate:"min=1"`
+
+	// Rate is the maximum number of events per second reported when
+	// backfilling spooled events. Zero means there is no limit.
+	Rate int `config:"rate" validate:"min=0"`
+
+	// URL is the path of the endpoint for triggering a backfill, and
+	// reporting its progress.
+	URL string `config:"url"`
+}
+
+func defaultSpoolConfig() SpoolConfig {
+	return SpoolConfig{
+		Path:     defaultSpoolPath,
+		MaxBytes: defaultSpoolMaxBytes,
+		Rate:     defaultSpoolRate,
+		URL:      defaultSpoolURL,
+	}
+}
@@ -0,0 +1,55 @@
+// Licensed to Elasticsearch B.V. under one or more contributor
+// license agreements. See the NOTICE file distributed with
+// this work for additional information regarding copyright
+// ownership. Elasticsearch B.V. licenses this file to you under
+// the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package config
+
+import (
+	"errors"
+	"time"
+)
+
+const defaultStreamLimitsWindow = 10 * time.Second
+
+// StreamLimitsConfig holds configuration related to defending the server
+// against clients holding intake streams open while sending little or no
+// data, such as stuck agents or slow-loris attacks.
+type StreamLimitsConfig struct {
+	// MaxPerClient defines the maximum number of concurrent intake streams
+	// per client, identified by its credentials or, failing that, its IP
+	// address. Zero means there is no limit.
+	MaxPerClient int `config:"max_per_client" validate:"min=0"`
+
+	// MinThroughput defines the minimum rate in bytes per second at which
+	// clients must send request bodies. Connections of clients sending more
+	// slowly are closed. Zero disables the check.
+	MinThroughput int `config:"min_throughput" validate:"min=0"`
+
+	// Window defines the time spent waiting for a client to send data,
+	// over which its throughput is measured.
+	Window time.Duration `config:"window"`
+}
+
+func (c *StreamLimitsConfig) Validate() error {
+	if c.Window <= 0 {
+		return errors.New("stream_limits.window must be greater than zero")
+	}
+	return nil
+}
+
+func defaultStreamLimitsConfig() StreamLimitsConfig {
+	return StreamLimitsConfig{Window: defaultStreamLimitsWindow}
+}
@@ -0,0 +1,72 @@
+// Licensed to Elasticsearch B.V. under one or more contributor
+// license agreements. See the NOTICE file distributed with
+// this work for additional information regarding copyright
+// ownership. Elasticsearch B.V. licenses this file to you under
+// the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package config
+
+import (
+	"fmt"
+	"regexp"
+)
+
+var tenantIDPattern = regexp.MustCompile("^[a-z0-9][a-z0-9_-]*$")
+
+// TenancyConfig holds configuration related to multi-tenant mode, where
+// credentials are mapped to tenants whose events are stamped with the
+// tenant ID and written to tenant-specific indices.
+type TenancyConfig struct {
+	Enabled bool           `config:"enabled"`
+	Tenants []TenantConfig `config:"tenants"`
+}
+
+// TenantConfig holds the credentials identifying a tenant.
+type TenantConfig struct {
+	ID string `config:"id" validate:"required"`
+
+	// SecretTokens holds secret tokens accepted for the tenant,
+	// in addition to `apm-server.secret_token`.
+	SecretTokens []string `config:"secret_tokens"`
+
+	// APIKeyIDs holds the IDs of API Keys belonging to the tenant.
+	APIKeyIDs []string `config:"api_key_ids"`
+}
+
+func (c *TenancyConfig) Validate() error {
+	ids := make(map[string]bool)
+	credentials := make(map[string]bool)
+	for _, tenant := range c.Tenants {
+		if !tenantIDPattern.MatchString(tenant.ID) {
+			return fmt.Errorf("invalid tenant id %q, must consist of lowercase letters, digits, '_' and '-'", tenant.ID)
+		}
+		if ids[tenant.ID] {
+			return fmt.Errorf("duplicate tenant id %q", tenant.ID)
+		}
+		ids[tenant.ID] = true
+		for _, token := range tenant.SecretTokens {
+			if token == "" || credentials["token:"+token] {
+				return fmt.Errorf("secret tokens of tenant %q must be non-empty and unique", tenant.ID)
+			}
+			credentials["token:"+token] = true
+		}
+		for _, id := range tenant.APIKeyIDs {
+			if id == "" || credentials["api_key:"+id] {
+				return fmt.Errorf("API Key IDs of tenant %q must be non-empty and unique", tenant.ID)
+			}
+			credentials["api_key:"+id] = true
+		}
+	}
+	return nil
+}
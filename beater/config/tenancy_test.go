@@ -0,0 +1,83 @@
+// Licensed to Elasticsearch B.V. under one or more contributor
+// license agreements. See the NOTICE file distributed with
+// this work for additional information regarding copyright
+// ownership. Elasticsearch B.V. licenses this file to you under
+// the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package config
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/elastic/beats/v7/libbeat/common"
+)
+
+func TestTenancyConfig(t *testing.T) {
+	cfg, err := NewConfig("9.9.9", common.MustNewConfigFrom(map[string]interface{}{
+		"tenancy.enabled": true,
+		"tenancy.tenants": []map[string]interface{}{
+			{"id": "team-a", "secret_tokens": []string{"abc"}},
+			{"id": "team-b", "api_key_ids": []string{"key-b"}},
+		},
+	}), nil)
+	require.NoError(t, err)
+	assert.Equal(t, TenancyConfig{
+		Enabled: true,
+		Tenants: []TenantConfig{
+			{ID: "team-a", SecretTokens: []string{"abc"}},
+			{ID: "team-b", APIKeyIDs: []string{"key-b"}},
+		},
+	}, cfg.Tenancy)
+}
+
+func TestTenancyConfigInvalid(t *testing.T) {
+	for name, test := range map[string]struct {
+		tenants []map[string]interface{}
+		expect  string
+	}{
+		"invalid id": {
+			tenants: []map[string]interface{}{{"id": "Team A"}},
+			expect:  `invalid tenant id "Team A"`,
+		},
+		"duplicate id": {
+			tenants: []map[string]interface{}{{"id": "team-a"}, {"id": "team-a"}},
+			expect:  `duplicate tenant id "team-a"`,
+		},
+		"shared secret token": {
+			tenants: []map[string]interface{}{
+				{"id": "team-a", "secret_tokens": []string{"abc"}},
+				{"id": "team-b", "secret_tokens": []string{"abc"}},
+			},
+			expect: `secret tokens of tenant "team-b" must be non-empty and unique`,
+		},
+		"shared api key id": {
+			tenants: []map[string]interface{}{
+				{"id": "team-a", "api_key_ids": []string{"key"}},
+				{"id": "team-b", "api_key_ids": []string{"key"}},
+			},
+			expect: `API Key IDs of tenant "team-b" must be non-empty and unique`,
+		},
+	} {
+		t.Run(name, func(t *testing.T) {
+			_, err := NewConfig("9.9.9", common.MustNewConfigFrom(map[string]interface{}{
+				"tenancy.tenants": test.tenants,
+			}), nil)
+			require.Error(t, err)
+			assert.Contains(t, err.Error(), test.expect)
+		})
+	}
+}
@@ -0,0 +1,183 @@
+// Licensed to Elasticsearch B.V. under one or more contributor
+// license agreements. See the NOTICE file distributed with
+// this work for additional information regarding copyright
+// ownership. Elasticsearch B.V. licenses this file to you under
+// the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package config
+
+import (
+	"fmt"
+	"reflect"
+	"sort"
+	"strings"
+
+	"github.com/elastic/beats/v7/libbeat/common"
+
+	"github.com/elastic/apm-server/idxmgmt/ilm"
+)
+
+// removedSettings maps settings which are no longer supported to the settings replacing them.
+var removedSettings = map[string]string{
+	"frontend": "rum",
+}
+
+// knownSettings describes all settings nested under the key `apm-server`.
+type knownSettings struct {
+	Config `config:",inline"`
+	ILM    ilm.Config `config:"ilm"`
+}
+
+var commonConfigType = reflect.TypeOf(common.Config{})
+
+// UnknownSettings returns a description of each setting in the `apm-server` configuration cfg
+// which is not supported, suggesting the intended setting where possible.
+func UnknownSettings(cfg *common.Config) ([]string, error) {
+	var settings map[string]interface{}
+	if err := cfg.Unpack(&settings); err != nil {
+		return nil, err
+	}
+	var problems []string
+	checkSettings(reflect.TypeOf(knownSettings{}), settings, "apm-server", &problems)
+	sort.Strings(problems)
+	return problems, nil
+}
+
+func checkSettings(t reflect.Type, value interface{}, path string, problems *[]string) {
+	for t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+	switch t.Kind() {
+	case reflect.Struct:
+		settings, ok := value.(map[string]interface{})
+		if !ok || t == commonConfigType {
+			// type mismatches are reported when unpacking the config,
+			// and nested configs may hold arbitrary settings
+			return
+		}
+		fields := structSettings(t)
+		for key, v := range settings {
+			settingPath := path + "." + key
+			fieldType, ok := fields[key]
+			if !ok {
+				*problems = append(*problems, unknownSetting(path, key, fields))
+				continue
+			}
+			checkSettings(fieldType, v, settingPath, problems)
+		}
+	case reflect.Slice, reflect.Array:
+		if values, ok := value.([]interface{}); ok {
+			for i, v := range values {
+				checkSettings(t.Elem(), v, fmt.Sprintf("%s.%d", path, i), problems)
+			}
+		}
+	}
+}
+
+// structSettings returns the types of the settings of struct type t, keyed by name,
+// following the naming rules applied when unpacking configs.
+func structSettings(t reflect.Type) map[string]reflect.Type {
+	settings := make(map[string]reflect.Type)
+	nested := make(map[string][]reflect.StructField)
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		if field.PkgPath != "" {
+			continue
+		}
+		tag := strings.Split(field.Tag.Get("config"), ",")
+		name := tag[0]
+		if len(tag) > 1 && (tag[1] == "inline" || tag[1] == "squash") {
+			fieldType := field.Type
+			for fieldType.Kind() == reflect.Ptr {
+				fieldType = fieldType.Elem()
+			}
+			for name, settingType := range structSettings(fieldType) {
+				settings[name] = settingType
+			}
+			continue
+		}
+		if name == "-" {
+			continue
+		}
+		if name == "" {
+			name = strings.ToLower(field.Name)
+		}
+		if i := strings.IndexRune(name, '.'); i >= 0 {
+			// dotted names are nested settings, e.g. `agent.config`
+			field.Tag = reflect.StructTag(fmt.Sprintf("config:%q", name[i+1:]))
+			nested[name[:i]] = append(nested[name[:i]], field)
+			continue
+		}
+		settings[name] = field.Type
+	}
+	for name, fields := range nested {
+		settings[name] = reflect.StructOf(fields)
+	}
+	return settings
+}
+
+func unknownSetting(path, key string, known map[string]reflect.Type) string {
+	msg := fmt.Sprintf("%s.%s: unknown setting", path, key)
+	if replacement, ok := removedSettings[key]; ok && path == "apm-server" {
+		return fmt.Sprintf("%s.%s: setting is no longer supported, use %s.%s instead", path, key, path, replacement)
+	}
+	if suggestion := closestSetting(key, known); suggestion != "" {
+		msg += fmt.Sprintf(", did you mean %s.%s?", path, suggestion)
+	}
+	return msg
+}
+
+// closestSetting returns the known setting with the smallest edit distance to key,
+// if it is close enough to likely be a typo.
+func closestSetting(key string, known map[string]reflect.Type) string {
+	var closest string
+	minDistance := len(key)/3 + 1
+	for name := range known {
+		if d := editDistance(key, name); d < minDistance || d == minDistance && name < closest {
+			closest, minDistance = name, d
+		}
+	}
+	return closest
+}
+
+// editDistance returns the Levenshtein distance between a and b.
+func editDistance(a, b string) int {
+	prev := make([]int, len(b)+1)
+	curr := make([]int, len(b)+1)
+	for j := range prev {
+		prev[j] = j
+	}
+	for i := 1; i <= len(a); i++ {
+		curr[0] = i
+		for j := 1; j <= len(b); j++ {
+			cost := 1
+			if a[i-1] == b[j-1] {
+				cost = 0
+			}
+			curr[j] = min(prev[j]+1, curr[j-1]+1, prev[j-1]+cost)
+		}
+		prev, curr = curr, prev
+	}
+	return prev[len(b)]
+}
+
+func min(values ...int) int {
+	m := values[0]
+	for _, v := range values[1:] {
+		if v < m {
+			m = v
+		}
+	}
+	return m
+}
@@ -0,0 +1,87 @@
+// Licensed to Elasticsearch B.V. under one or more contributor
+// license agreements. See the NOTICE file distributed with
+// this work for additional information regarding copyright
+// ownership. Elasticsearch B.V. licenses this file to you under
+// the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package config
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/elastic/beats/v7/libbeat/common"
+)
+
+func TestUnknownSettings(t *testing.T) {
+	for name, tc := range map[string]struct {
+		cfg      common.MapStr
+		problems []string
+	}{
+		"Empty": {},
+		"Known": {
+			cfg: common.MapStr{
+				"host":                          "localhost:8200",
+				"rum.enabled":                   true,
+				"rum.allow_origins":             []string{"*"},
+				"rum.response_headers":          map[string][]string{"X-Foo": {"bar"}},
+				"rum.source_mapping.max_wait":   "2s",
+				"agent.config.cache.expiration": "1m",
+				"ssl.enabled":                   true,
+				"ssl.certificate":               "cert.pem",
+				"kibana.host":                   "localhost:5601",
+				"ilm.enabled":                   "auto",
+				"ilm.setup.mapping":             []common.MapStr{{"event_type": "error", "policy_name": "p"}},
+				"register.ingest.pipeline.path": "pipeline.json",
+			},
+		},
+		"Typo": {
+			cfg: common.MapStr{"rum.allowed_origins": []string{"*"}},
+			problems: []string{
+				"apm-server.rum.allowed_origins: unknown setting, did you mean apm-server.rum.allow_origins?",
+			},
+		},
+		"Unknown": {
+			cfg: common.MapStr{"foo": "bar", "agent.config.cache.size": 1},
+			problems: []string{
+				"apm-server.agent.config.cache.size: unknown setting",
+				"apm-server.foo: unknown setting",
+			},
+		},
+		"NestedInList": {
+			cfg: common.MapStr{"rum.source_mapping.elasticsearch.hosts": []string{"localhost"}},
+		},
+		"Removed": {
+			cfg: common.MapStr{"frontend.enabled": true},
+			problems: []string{
+				"apm-server.frontend: setting is no longer supported, use apm-server.rum instead",
+			},
+		},
+	} {
+		t.Run(name, func(t *testing.T) {
+			problems, err := UnknownSettings(common.MustNewConfigFrom(tc.cfg))
+			require.NoError(t, err)
+			assert.Equal(t, tc.problems, problems)
+		})
+	}
+}
+
+func TestEditDistance(t *testing.T) {
+	assert.Equal(t, 0, editDistance("rum", "rum"))
+	assert.Equal(t, 3, editDistance("", "rum"))
+	assert.Equal(t, 2, editDistance("allowed_origins", "allow_origins"))
+	assert.Equal(t, 1, editDistance("hst", "host"))
+}
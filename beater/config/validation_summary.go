@@ -0,0 +1,46 @@
+// Licensed to Elasticsearch B.V. under one or more contributor
+// license agreements. See the NOTICE file distributed with
+// this work for additional information regarding copyright
+// ownership. Elasticsearch B.V. licenses this file to you under
+// the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package config
+
+import (
+	"errors"
+	"time"
+)
+
+const defaultValidationSummaryInterval = time.Minute
+
+// ValidationSummaryConfig holds configuration related to logging a periodic
+// summary of events rejected for being invalid or too large, in place of
+// logging each request with rejected events.
+type ValidationSummaryConfig struct {
+	Enabled bool `config:"enabled"`
+
+	// Interval defines how often the summary is logged.
+	Interval time.Duration `config:"interval"`
+}
+
+func (c *ValidationSummaryConfig) Validate() error {
+	if c.Interval <= 0 {
+		return errors.New("validation_summary.interval must be greater than zero")
+	}
+	return nil
+}
+
+func defaultValidationSummaryConfig() ValidationSummaryConfig {
+	return ValidationSummaryConfig{Interval: defaultValidationSummaryInterval}
+}
@@ -33,10 +33,16 @@ const (
 	ContentEncoding            = "Content-Encoding"
 	ContentLength              = "Content-Length"
 	ContentType                = "Content-Type"
+	ElasticAPMSamplingRate     = "Elastic-Apm-Sampling-Rate"
 	Etag                       = "Etag"
 	IfNoneMatch                = "If-None-Match"
 	Origin                     = "Origin"
+	RateLimitLimit             = "RateLimit-Limit"
+	RateLimitRemaining         = "RateLimit-Remaining"
+	RateLimitReset             = "RateLimit-Reset"
+	RetryAfter                 = "Retry-After"
 	UserAgent                  = "User-Agent"
 	Vary                       = "Vary"
 	XContentTypeOptions        = "X-Content-Type-Options"
+	XRequestID                 = "X-Request-Id"
 )
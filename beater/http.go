@@ -31,7 +31,11 @@ import (
 	"github.com/elastic/beats/v7/libbeat/logp"
 
 	"github.com/elastic/apm-server/beater/api"
+	"github.com/elastic/apm-server/beater/api/status"
+	"github.com/elastic/apm-server/beater/api/streamlimit"
 	"github.com/elastic/apm-server/beater/config"
+	"github.com/elastic/apm-server/containerinfer"
+	"github.com/elastic/apm-server/labels"
 	"github.com/elastic/apm-server/publish"
 )
 
@@ -40,10 +44,14 @@ type httpServer struct {
 	cfg      *config.Config
 	logger   *logp.Logger
 	reporter publish.Reporter
+
+	// listening, if non-nil, is called with the listener's
+	// address once the server has started listening.
+	listening func(net.Addr)
 }
 
-func newHTTPServer(logger *logp.Logger, cfg *config.Config, tracer *apm.Tracer, reporter publish.Reporter) (*httpServer, error) {
-	mux, err := api.NewMux(cfg, reporter)
+func newHTTPServer(logger *logp.Logger, cfg *config.Config, tracer *apm.Tracer, reporter publish.Reporter, tracker *status.Tracker, samplingRate func(serviceName, environment string) (float64, bool), sanitizer *labels.Sanitizer) (*httpServer, error) {
+	mux, err := api.NewMux(cfg, reporter, tracker, samplingRate, sanitizer)
 	if err != nil {
 		return nil, err
 	}
@@ -60,6 +68,30 @@ func newHTTPServer(logger *logp.Logger, cfg *config.Config, tracer *apm.Tracer,
 		MaxHeaderBytes: cfg.MaxHeaderSize,
 	}
 
+	if cfg.ContainerInference.Enabled {
+		cniRanges, err := cfg.ContainerInference.CNINetworks()
+		if err != nil {
+			return nil, err
+		}
+		inferrer := containerinfer.New(containerinfer.Config{
+			CNIRanges: cniRanges,
+			ProcPath:  cfg.ContainerInference.ProcPath,
+		})
+		server.ConnContext = inferrer.ConnContext
+	}
+
+	if cfg.StreamLimits.MinThroughput > 0 {
+		// Connections of clients sending request bodies too
+		// slowly are closed, so they must be reachable from requests.
+		connContext := server.ConnContext
+		server.ConnContext = func(ctx context.Context, conn net.Conn) context.Context {
+			if connContext != nil {
+				ctx = connContext(ctx, conn)
+			}
+			return streamlimit.ConnContext(ctx, conn)
+		}
+	}
+
 	if cfg.TLS.IsEnabled() {
 		tlsServerConfig, err := tlscommon.LoadTLSServerConfig(cfg.TLS)
 		if err != nil {
@@ -67,7 +99,7 @@ func newHTTPServer(logger *logp.Logger, cfg *config.Config, tracer *apm.Tracer,
 		}
 		server.TLSConfig = tlsServerConfig.BuildModuleConfig("")
 	}
-	return &httpServer{server, cfg, logger, reporter}, nil
+	return &httpServer{Server: server, cfg: cfg, logger: logger, reporter: reporter}, nil
 }
 
 func (h *httpServer) start() error {
@@ -100,8 +132,9 @@ func (h *httpServer) start() error {
 		h.logger.Infof("Connection limit set to: %d", h.cfg.MaxConnections)
 	}
 
-	// Create the "onboarding" document, which contains the server's listening address.
-	notifyListening(context.Background(), addr, h.reporter)
+	if h.listening != nil {
+		h.listening(addr)
+	}
 
 	if h.TLSConfig != nil {
 		h.logger.Info("SSL enabled.")
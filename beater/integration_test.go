@@ -38,6 +38,7 @@ import (
 	"github.com/elastic/beats/v7/libbeat/version"
 
 	"github.com/elastic/apm-server/beater/api"
+	"github.com/elastic/apm-server/beater/api/status"
 	"github.com/elastic/apm-server/tests/approvals"
 	"github.com/elastic/apm-server/tests/loader"
 )
@@ -111,14 +112,9 @@ func testPublish(t *testing.T, client *http.Client, req *http.Request, events <-
 	got := body(t, rsp)
 	assert.Equal(t, http.StatusAccepted, rsp.StatusCode, got)
 
-	onboarded := false
 	var docs []map[string]interface{}
 	enc := jsonoutput.New(version.GetDefaultVersion(), jsonoutput.Config{Pretty: true})
 	for _, e := range collectEvents(events, time.Second) {
-		if e.Fields["processor"].(common.MapStr)["name"] == "onboarding" {
-			onboarded = true
-			continue
-		}
 		adjustMissingTimestamp(&e)
 		doc, err := enc.Encode("apm-test", &e)
 		require.NoError(t, err)
@@ -129,7 +125,6 @@ func testPublish(t *testing.T, client *http.Client, req *http.Request, events <-
 	}
 	ret, err := json.Marshal(map[string]interface{}{"events": docs})
 	require.NoError(t, err)
-	assert.True(t, onboarded)
 	return ret
 }
 
@@ -167,7 +162,7 @@ func TestPublishIntegration(t *testing.T) {
 	}
 }
 
-func TestPublishIntegrationOnboarding(t *testing.T) {
+func TestPublishIntegrationStatus(t *testing.T) {
 	if testing.Short() {
 		t.Skip("skipping slow tc")
 	}
@@ -178,15 +173,34 @@ func TestPublishIntegrationOnboarding(t *testing.T) {
 	require.NoError(t, err)
 	defer apm.Stop()
 
-	allEvents := collectEvents(events, time.Second)
-	require.Equal(t, 1, len(allEvents))
-	event := allEvents[0]
-	otype, err := event.Fields.GetValue("observer.type")
-	require.NoError(t, err)
-	assert.Equal(t, "test-apm-server", otype.(string))
-	hasListen, err := event.Fields.HasKey("observer.listening")
+	getStatus := func() status.Status {
+		rsp, err := apm.client.Get(apm.baseURL + api.StatusPath)
+		require.NoError(t, err)
+		defer rsp.Body.Close()
+		require.Equal(t, http.StatusOK, rsp.StatusCode)
+		var s status.Status
+		require.NoError(t, json.NewDecoder(rsp.Body).Decode(&s))
+		return s
+	}
+	assert.Nil(t, getStatus().FirstEventReceived)
+	assert.Empty(t, getStatus().Services)
+
+	b, err := loader.LoadDataAsBytes("../testdata/intake-v2/transactions.ndjson")
 	require.NoError(t, err)
-	assert.True(t, hasListen, "missing field: observer.listening")
+	testPublishIntake(t, apm, events, bytes.NewReader(b))
+
+	s := getStatus()
+	require.NotNil(t, s.FirstEventReceived)
+	first := *s.FirstEventReceived
+	assert.Equal(t, []status.ServiceStatus{{
+		Name:               "1234_service-12a3",
+		FirstEventReceived: first,
+		Agents:             []status.AgentStatus{{Name: "elastic-node", FirstEventReceived: first}},
+	}, {
+		Name:               "service1",
+		FirstEventReceived: first,
+		Agents:             []status.AgentStatus{{Name: "elastic-ruby", FirstEventReceived: first}},
+	}}, s.Services)
 }
 
 func TestPublishIntegrationProfile(t *testing.T) {
@@ -42,6 +42,7 @@ var (
 	}
 
 	errNotAuthorized = errors.New("not authorized")
+	errReadOnly      = errors.New("server is in read-only mode")
 )
 
 type monitoringMap map[request.ResultID]*monitoring.Int
@@ -23,6 +23,7 @@ import (
 	"io/ioutil"
 	"mime"
 	"net/http"
+	"time"
 
 	"github.com/apache/thrift/lib/go/thrift"
 	"github.com/jaegertracing/jaeger/model"
@@ -46,10 +47,11 @@ var (
 )
 
 // newHTTPMux returns a new http.ServeMux which accepts Thrift-encoded spans.
-func newHTTPMux(consumer consumer.TraceConsumer) (*http.ServeMux, error) {
+// If suppressRepeated is positive, repetitions of logged errors are suppressed for that long.
+func newHTTPMux(consumer consumer.TraceConsumer, suppressRepeated time.Duration) (*http.ServeMux, error) {
 	handler, err := middleware.Wrap(
 		newHTTPHandler(consumer),
-		middleware.LogMiddleware(),
+		middleware.LogMiddleware(suppressRepeated),
 		middleware.RecoverPanicMiddleware(),
 		middleware.MonitoringMiddleware(httpMonitoringMap),
 		middleware.RequestTimeMiddleware(),
@@ -92,7 +92,7 @@ func testHTTPMux(t *testing.T, test httpMuxTest) {
 	mux, err := newHTTPMux(traceConsumerFunc(func(ctx context.Context, td consumerdata.TraceData) error {
 		consumed = true
 		return test.consumerError
-	}))
+	}), 0)
 	require.NoError(t, err)
 
 	body := encodeThriftSpans(test.spans...)
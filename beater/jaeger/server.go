@@ -59,9 +59,17 @@ func NewServer(logger *logp.Logger, cfg *config.Config, tracer *apm.Tracer, repo
 	if !cfg.JaegerConfig.GRPC.Enabled && !cfg.JaegerConfig.HTTP.Enabled {
 		return nil, nil
 	}
+	attributeMappings := make([]processor.AttributeMapping, len(cfg.JaegerConfig.AttributeMappings))
+	for i, m := range cfg.JaegerConfig.AttributeMappings {
+		attributeMappings[i] = processor.AttributeMapping{Source: m.Source, Target: m.Target}
+	}
+	if cfg.ReadOnly {
+		reporter = func(context.Context, publish.PendingReq) error { return errReadOnly }
+	}
 	traceConsumer := &processor.Consumer{
-		Reporter:        reporter,
-		TransformConfig: transform.Config{},
+		Reporter:          reporter,
+		TransformConfig:   transform.Config{},
+		AttributeMappings: attributeMappings,
 	}
 
 	srv := &Server{logger: logger}
@@ -114,7 +122,7 @@ func NewServer(logger *logp.Logger, cfg *config.Config, tracer *apm.Tracer, repo
 		if err != nil {
 			return nil, err
 		}
-		httpMux, err := newHTTPMux(traceConsumer)
+		httpMux, err := newHTTPMux(traceConsumer, cfg.RepeatedErrors.SuppressFor())
 		if err != nil {
 			return nil, err
 		}
@@ -21,6 +21,7 @@ import (
 	"github.com/elastic/apm-server/beater/authorization"
 	"github.com/elastic/apm-server/beater/headers"
 	"github.com/elastic/apm-server/beater/request"
+	"github.com/elastic/apm-server/utility"
 )
 
 // AuthorizationMiddleware returns a Middleware to only let authorized requests pass through
@@ -28,7 +29,8 @@ func AuthorizationMiddleware(auth *authorization.Handler, apply bool) Middleware
 	return func(h request.Handler) (request.Handler, error) {
 		return func(c *request.Context) {
 			header := c.Request.Header.Get(headers.Authorization)
-			c.Authorization = auth.AuthorizationFor(authorization.ParseAuthorizationHeader(header))
+			kind, token := authorization.ParseAuthorizationHeader(header)
+			c.Authorization = auth.AuthorizationFor(kind, token)
 
 			if apply {
 				authorized, err := c.Authorization.AuthorizedFor(c.Request.Context(), authorization.ResourceInternal)
@@ -37,6 +39,9 @@ func AuthorizationMiddleware(auth *authorization.Handler, apply bool) Middleware
 					c.Write()
 					return
 				}
+				if tenant := auth.TenantFor(kind, token); tenant != "" {
+					c.Request = c.Request.WithContext(utility.ContextWithTenant(c.Request.Context(), tenant))
+				}
 			}
 
 			h(c)
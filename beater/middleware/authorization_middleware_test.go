@@ -31,6 +31,7 @@ import (
 	"github.com/elastic/apm-server/beater/config"
 	"github.com/elastic/apm-server/beater/headers"
 	"github.com/elastic/apm-server/beater/request"
+	"github.com/elastic/apm-server/utility"
 )
 
 func TestAuthorizationMiddleware(t *testing.T) {
@@ -90,3 +91,30 @@ func TestAuthorizationMiddleware(t *testing.T) {
 		})
 	}
 }
+
+func TestAuthorizationMiddlewareTenant(t *testing.T) {
+	builder, err := authorization.NewBuilder(&config.Config{
+		SecretToken: "foo",
+		Tenancy: config.TenancyConfig{
+			Enabled: true,
+			Tenants: []config.TenantConfig{{ID: "team-a", SecretTokens: []string{"bar"}}},
+		},
+	})
+	require.NoError(t, err)
+	handler := builder.ForAnyOfPrivileges(authorization.ActionAny)
+
+	for header, expected := range map[string]string{
+		"Bearer foo": "",
+		"Bearer bar": "team-a",
+	} {
+		c, rec := beatertest.DefaultContextWithResponseRecorder()
+		c.Request.Header.Set(headers.Authorization, header)
+		var tenant string
+		Apply(AuthorizationMiddleware(handler, true), func(c *request.Context) {
+			tenant = utility.Tenant(c.Request.Context())
+			beatertest.Handler202(c)
+		})(c)
+		require.Equal(t, http.StatusAccepted, rec.Code)
+		assert.Equal(t, expected, tenant, header)
+	}
+}
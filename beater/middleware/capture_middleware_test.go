@@ -0,0 +1,68 @@
+// Licensed to Elasticsearch B.V. under one or more contributor
+// license agreements. See the NOTICE file distributed with
+// this work for additional information regarding copyright
+// ownership. Elasticsearch B.V. licenses this file to you under
+// the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package middleware
+
+import (
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/elastic/apm-server/beater/api/capture"
+	"github.com/elastic/apm-server/beater/beatertest"
+	"github.com/elastic/apm-server/beater/config"
+	"github.com/elastic/apm-server/beater/request"
+)
+
+func TestCaptureMiddleware(t *testing.T) {
+	dir, err := ioutil.TempDir("", "apm-server-capture")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+
+	cfg := config.DefaultConfig(beatertest.MockBeatVersion()).Capture
+	cfg.Path = dir
+	cfg.StatusCodes = []int{http.StatusBadRequest}
+	capturer, err := capture.New(cfg)
+	require.NoError(t, err)
+
+	h := Apply(CaptureMiddleware(capturer), func(c *request.Context) {
+		body, err := ioutil.ReadAll(c.Request.Body)
+		require.NoError(t, err)
+		if string(body) == "invalid" {
+			c.Result.SetDefault(request.IDResponseErrorsValidate)
+		} else {
+			c.Result.SetDefault(request.IDResponseValidAccepted)
+		}
+		c.Write()
+	})
+	for _, body := range []string{"valid", "invalid"} {
+		c, _ := beatertest.DefaultContextWithResponseRecorder()
+		c.Request = httptest.NewRequest(http.MethodPost, "/", strings.NewReader(body))
+		h(c)
+	}
+
+	infos, err := ioutil.ReadDir(dir)
+	require.NoError(t, err)
+	require.Len(t, infos, 1)
+	assert.Contains(t, infos[0].Name(), "-400")
+}
@@ -26,11 +26,16 @@ import (
 
 	"github.com/elastic/apm-server/beater/headers"
 	"github.com/elastic/apm-server/beater/request"
+	"github.com/elastic/apm-server/utility"
 )
 
 var (
-	supportedHeaders = []string{headers.ContentType, headers.ContentEncoding, headers.Accept}
+	supportedHeaders = []string{headers.ContentType, headers.ContentEncoding, headers.Accept, headers.XRequestID}
 	supportedMethods = strings.Join([]string{http.MethodPost, http.MethodOptions}, ", ")
+	rateLimitHeaders = []string{headers.RateLimitLimit, headers.RateLimitRemaining, headers.RateLimitReset}
+
+	exposedHeaders        = strings.Join(append([]string{headers.Etag, headers.ElasticAPMSamplingRate, headers.XRequestID}, rateLimitHeaders...), ", ")
+	exposedRequestHeaders = strings.Join(append([]string{headers.ElasticAPMSamplingRate, headers.XRequestID}, rateLimitHeaders...), ", ")
 )
 
 // CORSMiddleware returns a middleware serving preflight OPTION requests and terminating requests if they do not
@@ -68,7 +73,7 @@ func CORSMiddleware(allowedOrigins, allowedHeaders []string) Middleware {
 				h := append(allowedHeaders, supportedHeaders...)
 				c.Header().Set(headers.AccessControlAllowHeaders, strings.Join(h, ", "))
 
-				c.Header().Set(headers.AccessControlExposeHeaders, headers.Etag)
+				c.Header().Set(headers.AccessControlExposeHeaders, exposedHeaders)
 
 				c.Header().Set(headers.ContentLength, "0")
 
@@ -78,6 +83,8 @@ func CORSMiddleware(allowedOrigins, allowedHeaders []string) Middleware {
 			} else if validOrigin {
 				// we need to check the origin and set the ACAO header in both the OPTIONS preflight and the actual request
 				c.Header().Set(headers.AccessControlAllowOrigin, origin)
+				c.Header().Add(headers.AccessControlExposeHeaders, exposedRequestHeaders)
+				c.Request = c.Request.WithContext(utility.ContextWithOrigin(c.Request.Context(), origin))
 				h(c)
 
 			} else {
@@ -28,6 +28,7 @@ import (
 	"github.com/elastic/apm-server/beater/beatertest"
 	"github.com/elastic/apm-server/beater/headers"
 	"github.com/elastic/apm-server/beater/request"
+	"github.com/elastic/apm-server/utility"
 )
 
 func TestCORSMiddleware(t *testing.T) {
@@ -43,7 +44,8 @@ func TestCORSMiddleware(t *testing.T) {
 		assert.Equal(t, "3600", rec.Header().Get(headers.AccessControlMaxAge))
 		assert.Equal(t, "Origin", rec.Header().Get(headers.Vary))
 		assert.Equal(t, "POST, OPTIONS", rec.Header().Get(headers.AccessControlAllowMethods))
-		assert.Equal(t, "Content-Type, Content-Encoding, Accept", rec.Header().Get(headers.AccessControlAllowHeaders))
+		assert.Equal(t, "Content-Type, Content-Encoding, Accept, X-Request-Id", rec.Header().Get(headers.AccessControlAllowHeaders))
+		assert.Equal(t, "Etag, Elastic-Apm-Sampling-Rate, X-Request-Id, RateLimit-Limit, RateLimit-Remaining, RateLimit-Reset", rec.Header().Get(headers.AccessControlExposeHeaders))
 		assert.Equal(t, "0", rec.Header().Get(headers.ContentLength))
 		assert.Equal(t, http.StatusOK, rec.Code)
 		assert.Empty(t, rec.Body.String())
@@ -62,10 +64,12 @@ func TestCORSMiddleware(t *testing.T) {
 
 	t.Run("GETAllowedOrigins", func(t *testing.T) {
 		for _, origin := range []string{"", "wxyz", "testingx"} {
-			_, rec := cors(origin, []string{"*", "testing.*"}, nil, http.MethodPost)
+			c, rec := cors(origin, []string{"*", "testing.*"}, nil, http.MethodPost)
 
 			assert.Equal(t, http.StatusAccepted, rec.Code)
 			assert.Equal(t, origin, rec.Header().Get(headers.AccessControlAllowOrigin))
+			assert.Equal(t, "Elastic-Apm-Sampling-Rate, X-Request-Id, RateLimit-Limit, RateLimit-Remaining, RateLimit-Reset", rec.Header().Get(headers.AccessControlExposeHeaders))
+			assert.Equal(t, origin, utility.Origin(c.Request.Context()))
 		}
 	})
 
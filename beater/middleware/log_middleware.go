@@ -18,6 +18,8 @@
 package middleware
 
 import (
+	"time"
+
 	"github.com/gofrs/uuid"
 
 	"go.elastic.co/apm"
@@ -30,21 +32,37 @@ import (
 	"github.com/elastic/apm-server/utility"
 )
 
-// LogMiddleware returns a middleware taking care of logging processing a request in the middleware and the request handler
-func LogMiddleware() Middleware {
-	logger := logp.NewLogger(logs.Request)
+// maxRequestIDLength is the maximum length of request IDs propagated
+// from the X-Request-Id header of requests.
+const maxRequestIDLength = 128
+
+// LogMiddleware returns a middleware taking care of logging processing a request in the middleware and the request handler.
+// If suppressRepeated is positive, repetitions of an error are suppressed for that long after it is logged.
+func LogMiddleware(suppressRepeated time.Duration) Middleware {
+	var logger *logp.Logger
+	if suppressRepeated > 0 {
+		logger = logp.NewLogger(logs.Request, logs.SuppressRepeated(suppressRepeated))
+	} else {
+		logger = logp.NewLogger(logs.Request)
+	}
 	return func(h request.Handler) (request.Handler, error) {
 
 		return func(c *request.Context) {
-			var reqID, transactionID, traceID string
+			var transactionID, traceID string
+			reqID := c.Request.Header.Get(headers.XRequestID)
+			if !validRequestID(reqID) {
+				reqID = ""
+			}
 			tx := apm.TransactionFromContext(c.Request.Context())
 			if tx != nil {
 				// This request is being traced, grab its IDs to add to logs.
 				traceContext := tx.TraceContext()
 				transactionID = traceContext.Span.String()
 				traceID = traceContext.Trace.String()
-				reqID = transactionID
-			} else {
+				if reqID == "" {
+					reqID = transactionID
+				}
+			} else if reqID == "" {
 				uuid, err := uuid.NewV4()
 				if err != nil {
 					id := request.IDResponseErrorsInternal
@@ -55,6 +73,7 @@ func LogMiddleware() Middleware {
 				}
 				reqID = uuid.String()
 			}
+			c.RequestID = reqID
 
 			reqLogger := logger.With(
 				"request_id", reqID,
@@ -91,7 +110,9 @@ func LogMiddleware() Middleware {
 				keysAndValues = append(keysAndValues, "stacktrace", c.Result.Stacktrace)
 			}
 
-			if c.Result.Failure() {
+			if c.Result.Failure() && c.Result.Summarized {
+				reqLogger.Debugw(keyword, keysAndValues...)
+			} else if c.Result.Failure() {
 				reqLogger.Errorw(keyword, keysAndValues...)
 			} else {
 				reqLogger.Infow(keyword, keysAndValues...)
@@ -100,3 +121,18 @@ func LogMiddleware() Middleware {
 		}, nil
 	}
 }
+
+// validRequestID reports whether id, received from a client,
+// may be used as request ID: it must be non-empty, and consist
+// of at most maxRequestIDLength printable ASCII characters.
+func validRequestID(id string) bool {
+	if id == "" || len(id) > maxRequestIDLength {
+		return false
+	}
+	for i := 0; i < len(id); i++ {
+		if id[i] < '!' || id[i] > '~' {
+			return false
+		}
+	}
+	return true
+}
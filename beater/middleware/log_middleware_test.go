@@ -19,7 +19,9 @@ package middleware
 
 import (
 	"net/http"
+	"strings"
 	"testing"
+	"time"
 
 	"github.com/pkg/errors"
 	"github.com/stretchr/testify/assert"
@@ -92,6 +94,17 @@ func TestLogMiddleware(t *testing.T) {
 			},
 			code: http.StatusForbidden,
 		},
+		{
+			name:    "Summarized error",
+			message: "data validation error",
+			level:   zapcore.DebugLevel,
+			handler: func(c *request.Context) {
+				c.Result.SetDefault(request.IDResponseErrorsValidate)
+				c.Result.Summarized = true
+				c.Write()
+			},
+			code: http.StatusBadRequest,
+		},
 	}
 
 	for _, tc := range testCases {
@@ -103,7 +116,7 @@ func TestLogMiddleware(t *testing.T) {
 				c.Request = c.Request.WithContext(apm.ContextWithTransaction(c.Request.Context(), tx))
 				defer tx.End()
 			}
-			Apply(LogMiddleware(), tc.handler)(c)
+			Apply(LogMiddleware(0), tc.handler)(c)
 
 			assert.Equal(t, tc.code, rec.Code)
 			for i, entry := range logp.ObserverLogs().TakeAll() {
@@ -115,6 +128,7 @@ func TestLogMiddleware(t *testing.T) {
 
 				ec := entry.ContextMap()
 				assert.NotEmpty(t, ec["request_id"])
+				assert.Equal(t, ec["request_id"], rec.Header().Get(headers.XRequestID))
 				assert.NotEmpty(t, ec["method"])
 				assert.Equal(t, c.Request.URL.String(), ec["URL"])
 				assert.NotEmpty(t, ec["remote_address"])
@@ -139,3 +153,60 @@ func TestLogMiddleware(t *testing.T) {
 		})
 	}
 }
+
+func TestLogMiddlewareRequestID(t *testing.T) {
+	err := logp.DevelopmentSetup(logp.ToObserverOutput())
+	require.NoError(t, err)
+
+	for name, tc := range map[string]struct {
+		header  string
+		traced  bool
+		expect  string
+		unknown bool
+	}{
+		"Propagated":       {header: "abc-123", expect: "abc-123"},
+		"PropagatedTraced": {header: "abc-123", traced: true, expect: "abc-123"},
+		"Invalid":          {header: "abc 123", unknown: true},
+		"TooLong":          {header: strings.Repeat("a", maxRequestIDLength+1), unknown: true},
+		"Missing":          {unknown: true},
+	} {
+		t.Run(name, func(t *testing.T) {
+			c, rec := beatertest.DefaultContextWithResponseRecorder()
+			c.Request.Header.Set(headers.XRequestID, tc.header)
+			if tc.traced {
+				tx := apmtest.DiscardTracer.StartTransaction("name", "type")
+				c.Request = c.Request.WithContext(apm.ContextWithTransaction(c.Request.Context(), tx))
+				defer tx.End()
+			}
+			Apply(LogMiddleware(0), beatertest.Handler403)(c)
+
+			requestID := rec.Header().Get(headers.XRequestID)
+			if tc.unknown {
+				assert.NotEmpty(t, requestID)
+				assert.NotEqual(t, tc.header, requestID)
+			} else {
+				assert.Equal(t, tc.expect, requestID)
+			}
+			assert.Equal(t, requestID, c.RequestID)
+			assert.Contains(t, rec.Body.String(), `"request_id":"`+requestID+`"`)
+
+			entries := logp.ObserverLogs().TakeAll()
+			require.Len(t, entries, 1)
+			assert.Equal(t, requestID, entries[0].ContextMap()["request_id"])
+		})
+	}
+}
+
+func TestLogMiddlewareSuppressRepeated(t *testing.T) {
+	err := logp.DevelopmentSetup(logp.ToObserverOutput())
+	require.NoError(t, err)
+
+	h := Apply(LogMiddleware(time.Minute), beatertest.Handler403)
+	for i := 0; i < 3; i++ {
+		c, _ := beatertest.DefaultContextWithResponseRecorder()
+		h(c)
+	}
+	entries := logp.ObserverLogs().TakeAll()
+	require.Len(t, entries, 1)
+	assert.Equal(t, "forbidden request", entries[0].Message)
+}
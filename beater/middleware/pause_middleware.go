@@ -0,0 +1,64 @@
+// Licensed to Elasticsearch B.V. under one or more contributor
+// license agreements. See the NOTICE file distributed with
+// this work for additional information regarding copyright
+// ownership. Elasticsearch B.V. licenses this file to you under
+// the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package middleware
+
+import (
+	"strconv"
+	"sync/atomic"
+	"time"
+
+	"github.com/elastic/apm-server/beater/headers"
+	"github.com/elastic/apm-server/beater/request"
+)
+
+// Pause records whether request handling is paused. The zero value is
+// not paused, and a Pause is safe for concurrent use.
+type Pause struct {
+	paused int32
+}
+
+// Set pauses or resumes request handling.
+func (p *Pause) Set(paused bool) {
+	var v int32
+	if paused {
+		v = 1
+	}
+	atomic.StoreInt32(&p.paused, v)
+}
+
+// Paused reports whether request handling is paused.
+func (p *Pause) Paused() bool {
+	return atomic.LoadInt32(&p.paused) == 1
+}
+
+// PauseMiddleware returns a Middleware rejecting requests while p is paused,
+// asking clients to retry after the given duration.
+func PauseMiddleware(p *Pause, retryAfter time.Duration) Middleware {
+	retryAfterSeconds := strconv.Itoa(int((retryAfter + time.Second - 1) / time.Second))
+	return func(h request.Handler) (request.Handler, error) {
+		return func(c *request.Context) {
+			if !p.Paused() {
+				h(c)
+				return
+			}
+			c.Header().Set(headers.RetryAfter, retryAfterSeconds)
+			c.Result.SetDefault(request.IDResponseErrorsPaused)
+			c.Write()
+		}, nil
+	}
+}
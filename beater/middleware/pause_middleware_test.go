@@ -0,0 +1,49 @@
+// Licensed to Elasticsearch B.V. under one or more contributor
+// license agreements. See the NOTICE file distributed with
+// this work for additional information regarding copyright
+// ownership. Elasticsearch B.V. licenses this file to you under
+// the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package middleware
+
+import (
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/elastic/apm-server/beater/beatertest"
+)
+
+func TestPauseMiddleware(t *testing.T) {
+	var pause Pause
+	m := PauseMiddleware(&pause, 1500*time.Millisecond)
+
+	t.Run("Resumed", func(t *testing.T) {
+		c, rec := beatertest.DefaultContextWithResponseRecorder()
+		Apply(m, beatertest.Handler202)(c)
+		assert.Equal(t, http.StatusAccepted, rec.Code)
+		assert.Empty(t, rec.Header().Get("Retry-After"))
+	})
+	t.Run("Paused", func(t *testing.T) {
+		pause.Set(true)
+		defer pause.Set(false)
+		c, rec := beatertest.DefaultContextWithResponseRecorder()
+		Apply(m, beatertest.Handler202)(c)
+		assert.Equal(t, http.StatusServiceUnavailable, rec.Code)
+		assert.Equal(t, "2", rec.Header().Get("Retry-After"))
+		assert.Contains(t, rec.Body.String(), "intake is paused")
+	})
+}
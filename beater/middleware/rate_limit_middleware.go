@@ -25,7 +25,8 @@ import (
 
 const burstMultiplier = 3
 
-// SetIPRateLimitMiddleware sets a rate limiter
+// SetIPRateLimitMiddleware sets a rate limiter. The rate limiter's allowance
+// is described in RateLimit-* response headers when the response is written.
 func SetIPRateLimitMiddleware(cfg *config.EventRate) Middleware {
 	store, err := ratelimit.NewStore(cfg.LruSize, cfg.Limit, burstMultiplier)
 
@@ -0,0 +1,47 @@
+// Licensed to Elasticsearch B.V. under one or more contributor
+// license agreements. See the NOTICE file distributed with
+// this work for additional information regarding copyright
+// ownership. Elasticsearch B.V. licenses this file to you under
+// the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package middleware
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/elastic/apm-server/beater/beatertest"
+	"github.com/elastic/apm-server/beater/config"
+	"github.com/elastic/apm-server/beater/headers"
+	"github.com/elastic/apm-server/beater/request"
+)
+
+func TestSetIPRateLimitMiddleware(t *testing.T) {
+	h := Apply(SetIPRateLimitMiddleware(&config.EventRate{Limit: 2, LruSize: 10}), func(c *request.Context) {
+		c.RateLimiter.Allow()
+		beatertest.Handler202(c)
+	})
+
+	// Headers describe the allowance after handling each request.
+	for _, remaining := range []string{"5", "4"} {
+		c, rec := beatertest.DefaultContextWithResponseRecorder()
+		h(c)
+		assert.Equal(t, http.StatusAccepted, rec.Code)
+		assert.Equal(t, "6", rec.Header().Get(headers.RateLimitLimit))
+		assert.Equal(t, remaining, rec.Header().Get(headers.RateLimitRemaining))
+		assert.NotEmpty(t, rec.Header().Get(headers.RateLimitReset))
+	}
+}
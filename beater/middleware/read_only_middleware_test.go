@@ -0,0 +1,41 @@
+// Licensed to Elasticsearch B.V. under one or more contributor
+// license agreements. See the NOTICE file distributed with
+// this work for additional information regarding copyright
+// ownership. Elasticsearch B.V. licenses this file to you under
+// the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package middleware
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/elastic/apm-server/beater/beatertest"
+)
+
+func TestReadOnlyMiddleware(t *testing.T) {
+	t.Run("Off", func(t *testing.T) {
+		c, rec := beatertest.DefaultContextWithResponseRecorder()
+		Apply(ReadOnlyMiddleware(false), beatertest.Handler202)(c)
+		assert.Equal(t, http.StatusAccepted, rec.Code)
+	})
+	t.Run("On", func(t *testing.T) {
+		c, rec := beatertest.DefaultContextWithResponseRecorder()
+		Apply(ReadOnlyMiddleware(true), beatertest.Handler202)(c)
+		assert.Equal(t, http.StatusServiceUnavailable, rec.Code)
+		assert.Contains(t, rec.Body.String(), "server is in read-only mode")
+	})
+}
@@ -0,0 +1,62 @@
+// Licensed to Elasticsearch B.V. under one or more contributor
+// license agreements. See the NOTICE file distributed with
+// this work for additional information regarding copyright
+// ownership. Elasticsearch B.V. licenses this file to you under
+// the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package middleware
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/elastic/apm-server/beater/api/streamlimit"
+	"github.com/elastic/apm-server/beater/beatertest"
+	"github.com/elastic/apm-server/beater/config"
+	"github.com/elastic/apm-server/beater/request"
+)
+
+func TestStreamLimitMiddleware(t *testing.T) {
+	limits := streamlimit.New(config.StreamLimitsConfig{MaxPerClient: 1, Window: time.Second})
+
+	// A second stream opened by the same client while
+	// the first is still being handled is rejected.
+	var inner *request.Context
+	var h request.Handler
+	h, err := StreamLimitMiddleware(limits)(func(c *request.Context) {
+		if inner == nil {
+			var rec *httptest.ResponseRecorder
+			inner, rec = beatertest.DefaultContextWithResponseRecorder()
+			h(inner)
+			assert.Equal(t, http.StatusTooManyRequests, rec.Code)
+		}
+		beatertest.Handler202(c)
+	})
+	require.NoError(t, err)
+
+	c, rec := beatertest.DefaultContextWithResponseRecorder()
+	h(c)
+	assert.Equal(t, http.StatusAccepted, rec.Code)
+	assert.Equal(t, request.IDResponseErrorsTooManyStreams, inner.Result.ID)
+
+	// Once the first stream has ended, the client may open another.
+	c, rec = beatertest.DefaultContextWithResponseRecorder()
+	h(c)
+	assert.Equal(t, http.StatusAccepted, rec.Code)
+}
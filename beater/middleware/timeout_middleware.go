@@ -0,0 +1,42 @@
+// Licensed to Elasticsearch B.V. under one or more contributor
+// license agreements. See the NOTICE file distributed with
+// this work for additional information regarding copyright
+// ownership. Elasticsearch B.V. licenses this file to you under
+// the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package middleware
+
+import (
+	"context"
+	"time"
+
+	"github.com/elastic/apm-server/beater/request"
+)
+
+// TimeoutMiddleware returns a Middleware setting a deadline on the request's context,
+// after which downstream processing of the request is cancelled.
+// A non-positive timeout leaves the request's context unchanged.
+func TimeoutMiddleware(timeout time.Duration) Middleware {
+	return func(h request.Handler) (request.Handler, error) {
+		if timeout <= 0 {
+			return h, nil
+		}
+		return func(c *request.Context) {
+			ctx, cancel := context.WithTimeout(c.Request.Context(), timeout)
+			defer cancel()
+			c.Request = c.Request.WithContext(ctx)
+			h(c)
+		}, nil
+	}
+}
@@ -0,0 +1,54 @@
+// Licensed to Elasticsearch B.V. under one or more contributor
+// license agreements. See the NOTICE file distributed with
+// this work for additional information regarding copyright
+// ownership. Elasticsearch B.V. licenses this file to you under
+// the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package middleware
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/elastic/apm-server/beater/beatertest"
+	"github.com/elastic/apm-server/beater/request"
+)
+
+func TestTimeoutMiddleware(t *testing.T) {
+	t.Run("Deadline", func(t *testing.T) {
+		c, _ := beatertest.DefaultContextWithResponseRecorder()
+		var deadline time.Time
+		var ok bool
+		Apply(TimeoutMiddleware(time.Minute), func(c *request.Context) {
+			deadline, ok = c.Request.Context().Deadline()
+		})(c)
+		assert.True(t, ok)
+		assert.WithinDuration(t, time.Now().Add(time.Minute), deadline, 10*time.Second)
+	})
+
+	t.Run("CancelledAfterHandler", func(t *testing.T) {
+		c, _ := beatertest.DefaultContextWithResponseRecorder()
+		Apply(TimeoutMiddleware(time.Minute), beatertest.HandlerIdle)(c)
+		assert.Error(t, c.Request.Context().Err())
+	})
+
+	t.Run("Disabled", func(t *testing.T) {
+		c, _ := beatertest.DefaultContextWithResponseRecorder()
+		Apply(TimeoutMiddleware(0), beatertest.HandlerIdle)(c)
+		_, ok := c.Request.Context().Deadline()
+		assert.False(t, ok)
+	})
+}
@@ -0,0 +1,107 @@
+// Licensed to Elasticsearch B.V. under one or more contributor
+// license agreements. See the NOTICE file distributed with
+// this work for additional information regarding copyright
+// ownership. Elasticsearch B.V. licenses this file to you under
+// the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package beater
+
+import (
+	"context"
+	"fmt"
+	"sort"
+
+	"golang.org/x/sync/errgroup"
+
+	"github.com/elastic/apm-server/beater/config"
+	"github.com/elastic/apm-server/elasticsearch"
+	"github.com/elastic/apm-server/publish"
+)
+
+// newIntakeBackpressure returns a publish.IntakeBackpressure which
+// polls the health of the configured Elasticsearch cluster.
+func newIntakeBackpressure(cfg config.OutputHealthConfig) (*publish.IntakeBackpressure, error) {
+	client, err := elasticsearch.NewClient(cfg.ESConfig)
+	if err != nil {
+		return nil, err
+	}
+	return publish.NewIntakeBackpressure(publish.IntakeBackpressureConfig{
+		Interval: cfg.Interval,
+		MaxWait:  cfg.MaxWait,
+		Check:    clusterHealthCheck(client, cfg.BreakerThreshold),
+	}), nil
+}
+
+// clusterHealthCheck returns a function reporting the Elasticsearch cluster
+// as unhealthy while its status is red, or while the estimated usage of any
+// node's circuit breaker is at or above breakerThreshold of its limit.
+func clusterHealthCheck(client elasticsearch.Client, breakerThreshold float64) func(context.Context) (string, error) {
+	return func(ctx context.Context) (string, error) {
+		health, err := elasticsearch.ClusterHealth(ctx, client)
+		if err != nil {
+			return "", err
+		}
+		if health.Status == "red" {
+			return "cluster health is red", nil
+		}
+
+		stats, err := elasticsearch.NodesBreakers(ctx, client)
+		if err != nil {
+			return "", err
+		}
+		nodeIDs := make([]string, 0, len(stats.Nodes))
+		for id := range stats.Nodes {
+			nodeIDs = append(nodeIDs, id)
+		}
+		sort.Strings(nodeIDs)
+		for _, id := range nodeIDs {
+			node := stats.Nodes[id]
+			names := make([]string, 0, len(node.Breakers))
+			for name := range node.Breakers {
+				names = append(names, name)
+			}
+			sort.Strings(names)
+			for _, name := range names {
+				breaker := node.Breakers[name]
+				if breaker.LimitSizeInBytes <= 0 {
+					continue
+				}
+				usage := float64(breaker.EstimatedSizeInBytes) / float64(breaker.LimitSizeInBytes)
+				if usage >= breakerThreshold {
+					return fmt.Sprintf("%s circuit breaker of node %s at %.0f%% of its limit",
+						name, node.Name, usage*100), nil
+				}
+			}
+		}
+		return "", nil
+	}
+}
+
+func runServerWithIntakeBackpressure(runServer RunServerFunc, backpressure *publish.IntakeBackpressure) RunServerFunc {
+	return func(ctx context.Context, args ServerParams) error {
+		// Backpressure is stopped only after the server, so requests
+		// handled during graceful shutdown are still held back, for at
+		// most max_wait, while the output is unhealthy.
+		backpressureCtx, stopBackpressure := context.WithCancel(context.Background())
+		g, ctx := errgroup.WithContext(ctx)
+		g.Go(func() error {
+			return backpressure.Run(backpressureCtx)
+		})
+		g.Go(func() error {
+			defer stopBackpressure()
+			return runServer(ctx, args)
+		})
+		return g.Wait()
+	}
+}
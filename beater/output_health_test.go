@@ -0,0 +1,83 @@
+// Licensed to Elasticsearch B.V. under one or more contributor
+// license agreements. See the NOTICE file distributed with
+// this work for additional information regarding copyright
+// ownership. Elasticsearch B.V. licenses this file to you under
+// the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package beater
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/elastic/apm-server/elasticsearch"
+)
+
+func TestClusterHealthCheck(t *testing.T) {
+	for name, tc := range map[string]struct {
+		status   string
+		breakers map[string]elasticsearch.Breaker
+		reason   string
+	}{
+		"healthy": {
+			status: "yellow",
+			breakers: map[string]elasticsearch.Breaker{
+				"parent": {LimitSizeInBytes: 100, EstimatedSizeInBytes: 50},
+			},
+		},
+		"red": {
+			status: "red",
+			reason: "cluster health is red",
+		},
+		"breaker": {
+			status: "green",
+			breakers: map[string]elasticsearch.Breaker{
+				"parent":  {LimitSizeInBytes: 100, EstimatedSizeInBytes: 95},
+				"request": {LimitSizeInBytes: 0, EstimatedSizeInBytes: 10},
+			},
+			reason: "parent circuit breaker of node instance-0 at 95% of its limit",
+		},
+	} {
+		t.Run(name, func(t *testing.T) {
+			srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				w.Header().Set("Content-Type", "application/json")
+				switch r.URL.Path {
+				case "/_cluster/health":
+					json.NewEncoder(w).Encode(elasticsearch.ClusterHealthResponse{Status: tc.status})
+				case "/_nodes/stats/breaker":
+					json.NewEncoder(w).Encode(elasticsearch.NodesBreakersResponse{
+						Nodes: map[string]elasticsearch.NodeBreakers{
+							"abc": {Name: "instance-0", Breakers: tc.breakers},
+						},
+					})
+				default:
+					w.WriteHeader(http.StatusNotFound)
+				}
+			}))
+			defer srv.Close()
+
+			client, err := elasticsearch.NewVersionedClient("", "", "", []string{srv.URL}, nil)
+			require.NoError(t, err)
+			reason, err := clusterHealthCheck(client, 0.9)(context.Background())
+			require.NoError(t, err)
+			assert.Equal(t, tc.reason, reason)
+		})
+	}
+}
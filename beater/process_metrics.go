@@ -0,0 +1,53 @@
+// Licensed to Elasticsearch B.V. under one or more contributor
+// license agreements. See the NOTICE file distributed with
+// this work for additional information regarding copyright
+// ownership. Elasticsearch B.V. licenses this file to you under
+// the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package beater
+
+import (
+	"context"
+	"os"
+
+	"go.elastic.co/apm"
+)
+
+// procSelfFD is the directory listing the open file descriptors of the
+// current process. It is only available on Linux.
+var procSelfFD = "/proc/self/fd"
+
+// gatherProcessMetrics gathers process metrics for self-instrumentation
+// which are not reported by the tracer's builtin metrics gatherers.
+func gatherProcessMetrics(ctx context.Context, m *apm.Metrics) error {
+	if n, err := countOpenFiles(); err == nil {
+		m.Add("system.process.fd.open", nil, float64(n))
+	}
+	return nil
+}
+
+// countOpenFiles returns the number of open file descriptors held by the
+// current process, or an error if this cannot be determined on this platform.
+func countOpenFiles() (int, error) {
+	f, err := os.Open(procSelfFD)
+	if err != nil {
+		return 0, err
+	}
+	defer f.Close()
+	names, err := f.Readdirnames(-1)
+	if err != nil {
+		return 0, err
+	}
+	return len(names), nil
+}
@@ -21,11 +21,11 @@ import (
 	"encoding/json"
 	"net/http"
 	"strings"
-
-	"golang.org/x/time/rate"
+	"time"
 
 	"github.com/elastic/beats/v7/libbeat/logp"
 
+	"github.com/elastic/apm-server/beater/api/ratelimit"
 	"github.com/elastic/apm-server/beater/authorization"
 	"github.com/elastic/apm-server/beater/headers"
 	logs "github.com/elastic/apm-server/log"
@@ -40,15 +40,25 @@ var (
 	mimeTypesJSON = []string{mimeTypeAny, mimeTypeApplicationJSON}
 )
 
+// rateLimitedAuthorization is implemented by authorizations whose use
+// is rate limited, such as API keys.
+type rateLimitedAuthorization interface {
+	Allowance(now time.Time) ratelimit.Allowance
+}
+
 // Context abstracts request and response information for http requests
 type Context struct {
 	Request       *http.Request
 	Logger        *logp.Logger
-	RateLimiter   *rate.Limiter
+	RateLimiter   *ratelimit.Limiter
 	Authorization authorization.Authorization
 	IsRum         bool
 	Result        Result
 
+	// RequestID identifies the request in logs and responses. It is
+	// propagated from the request's X-Request-Id header, if valid.
+	RequestID string
+
 	// RequestMetadata contains metadata extracted from the request
 	// by middleware, and should be merged into event metadata.
 	RequestMetadata map[string]interface{}
@@ -68,6 +78,7 @@ func (c *Context) Reset(w http.ResponseWriter, r *http.Request) {
 	c.RateLimiter = nil
 	c.Authorization = &authorization.AllowAuth{}
 	c.IsRum = false
+	c.RequestID = ""
 	c.Result.Reset()
 	for k := range c.RequestMetadata {
 		delete(c.RequestMetadata, k)
@@ -89,6 +100,9 @@ func (c *Context) MultipleWriteAttempts() bool {
 
 // Write sets response headers, and writes the body to the response writer.
 // In case body is nil only the headers will be set.
+//
+// If the request is rate limited, RateLimit-* headers describe the client's
+// allowance after handling the request.
 // In case statusCode indicates an error response, the body is also set as error in the context.
 // Only first call with write to http response.
 func (c *Context) Write() {
@@ -98,6 +112,14 @@ func (c *Context) Write() {
 	c.writeAttempts++
 
 	c.w.Header().Set(headers.XContentTypeOptions, "nosniff")
+	if c.RequestID != "" {
+		c.w.Header().Set(headers.XRequestID, c.RequestID)
+	}
+	if c.RateLimiter != nil {
+		c.RateLimiter.Allowance(time.Now()).SetHeaders(c.w.Header())
+	} else if auth, ok := c.Authorization.(rateLimitedAuthorization); ok {
+		auth.Allowance(time.Now()).SetHeaders(c.w.Header())
+	}
 
 	body := c.Result.Body
 	if body == nil {
@@ -108,7 +130,11 @@ func (c *Context) Write() {
 	// wrap body in map: necessary to keep current logic
 	if c.Result.Failure() {
 		if b, ok := body.(string); ok {
-			body = map[string]string{"error": b}
+			m := map[string]string{"error": b}
+			if c.RequestID != "" {
+				m["request_id"] = c.RequestID
+			}
+			body = m
 		}
 	}
 
@@ -18,18 +18,22 @@
 package request
 
 import (
+	"context"
 	"net/http"
 	"net/http/httptest"
 	"reflect"
 	"testing"
+	"time"
 
 	"github.com/pkg/errors"
 	"github.com/stretchr/testify/assert"
 
 	"github.com/elastic/beats/v7/libbeat/logp"
 
+	"github.com/elastic/apm-server/beater/api/ratelimit"
 	"github.com/elastic/apm-server/beater/authorization"
 	"github.com/elastic/apm-server/beater/headers"
+	"github.com/elastic/apm-server/elasticsearch"
 )
 
 func TestContext_Reset(t *testing.T) {
@@ -83,6 +87,37 @@ func TestContext_Header(t *testing.T) {
 
 func TestContext_Write(t *testing.T) {
 
+	t.Run("RateLimitHeaders", func(t *testing.T) {
+		c, w := mockContextAccept("*/*")
+		c.RateLimiter = ratelimit.NewLimiter(1, 3)
+		c.RateLimiter.Allow()
+		c.Result = Result{Body: nil, StatusCode: http.StatusAccepted}
+		c.Write()
+
+		assert.Equal(t, "3", w.Header().Get(headers.RateLimitLimit))
+		assert.Equal(t, "2", w.Header().Get(headers.RateLimitRemaining))
+		assert.Equal(t, "1", w.Header().Get(headers.RateLimitReset))
+	})
+
+	t.Run("RateLimitedAuthorizationHeaders", func(t *testing.T) {
+		c, w := mockContextAccept("*/*")
+		c.Authorization = rateLimitedAuth{ratelimit.Allowance{Limit: 100, Remaining: 99, Reset: time.Minute}}
+		c.Result = Result{Body: nil, StatusCode: http.StatusAccepted}
+		c.Write()
+
+		assert.Equal(t, "100", w.Header().Get(headers.RateLimitLimit))
+		assert.Equal(t, "99", w.Header().Get(headers.RateLimitRemaining))
+		assert.Equal(t, "60", w.Header().Get(headers.RateLimitReset))
+	})
+
+	t.Run("NoRateLimitHeaders", func(t *testing.T) {
+		c, w := mockContextAccept("*/*")
+		c.Result = Result{Body: nil, StatusCode: http.StatusAccepted}
+		c.Write()
+
+		assert.Empty(t, w.Header().Get(headers.RateLimitLimit))
+	})
+
 	t.Run("SecondWrite", func(t *testing.T) {
 		c, w := mockContextAccept("*/*")
 		c.Result = Result{Body: nil, StatusCode: http.StatusAccepted}
@@ -212,3 +247,19 @@ func mockContextAccept(accept string) (*Context, *httptest.ResponseRecorder) {
 	return c, w
 
 }
+
+type rateLimitedAuth struct {
+	allowance ratelimit.Allowance
+}
+
+func (a rateLimitedAuth) AuthorizedFor(context.Context, elasticsearch.Resource) (bool, error) {
+	return true, nil
+}
+
+func (a rateLimitedAuth) IsAuthorizationConfigured() bool {
+	return true
+}
+
+func (a rateLimitedAuth) Allowance(time.Time) ratelimit.Allowance {
+	return a.allowance
+}
@@ -76,6 +76,16 @@ const (
 	IDResponseErrorsServiceUnavailable ResultID = "response.errors.unavailable"
 	// IDResponseErrorsInternal identifies responses where internal errors occured
 	IDResponseErrorsInternal ResultID = "response.errors.internal"
+	// IDResponseErrorsTimeout identifies responses for requests whose deadline was exceeded or were cancelled
+	IDResponseErrorsTimeout ResultID = "response.errors.timeout"
+	// IDResponseErrorsQuotaExceeded identifies responses for requests whose service exceeded its ingestion quota
+	IDResponseErrorsQuotaExceeded ResultID = "response.errors.quota"
+	// IDResponseErrorsPaused identifies responses for requests received while intake is paused
+	IDResponseErrorsPaused ResultID = "response.errors.paused"
+	// IDResponseErrorsReadOnly identifies responses for write requests received while the server is in read-only mode
+	IDResponseErrorsReadOnly ResultID = "response.errors.readonly"
+	// IDResponseErrorsTooManyStreams identifies responses for requests exceeding the number of concurrent streams permitted per client
+	IDResponseErrorsTooManyStreams ResultID = "response.errors.streams"
 	// IDResponseErrorsServiceUnavailable identifies responses where resource is unavailable
 )
 
@@ -98,6 +108,11 @@ var (
 		IDResponseErrorsShuttingDown:       {Code: http.StatusServiceUnavailable, Keyword: "server is shutting down"},
 		IDResponseErrorsServiceUnavailable: {Code: http.StatusServiceUnavailable, Keyword: "service unavailable"},
 		IDResponseErrorsInternal:           {Code: http.StatusInternalServerError, Keyword: "internal error"},
+		IDResponseErrorsTimeout:            {Code: http.StatusServiceUnavailable, Keyword: "request timed out"},
+		IDResponseErrorsQuotaExceeded:      {Code: http.StatusTooManyRequests, Keyword: "quota exceeded"},
+		IDResponseErrorsPaused:             {Code: http.StatusServiceUnavailable, Keyword: "intake is paused"},
+		IDResponseErrorsReadOnly:           {Code: http.StatusServiceUnavailable, Keyword: "server is in read-only mode"},
+		IDResponseErrorsTooManyStreams:     {Code: http.StatusTooManyRequests, Keyword: "too many concurrent streams"},
 	}
 )
 
@@ -118,6 +133,10 @@ type Result struct {
 	Body       interface{}
 	Err        error
 	Stacktrace string
+
+	// Summarized indicates that the failure is reported in aggregate,
+	// so it need not be logged with each request.
+	Summarized bool
 }
 
 // DefaultMonitoringMapForRegistry returns map matching resultIDs to monitoring counters for given registry.
@@ -149,6 +168,7 @@ func (r *Result) Reset() {
 	r.Body = nil
 	r.Err = nil
 	r.Stacktrace = ""
+	r.Summarized = false
 }
 
 // Failure returns a bool indicating whether it is describing a successful result or not
@@ -53,6 +53,7 @@ func TestResult_Reset(t *testing.T) {
 		Body:       []interface{}{1, "foo"},
 		Err:        errors.New("foo"),
 		Stacktrace: "bar",
+		Summarized: true,
 	}
 	r.Reset()
 	assertResultIsEmpty(t, r)
@@ -192,7 +193,7 @@ func TestResult_Failure(t *testing.T) {
 func TestDefaultMonitoringMapForRegistry(t *testing.T) {
 	mockRegistry := monitoring.Default.NewRegistry("mock-default")
 	m := DefaultMonitoringMapForRegistry(mockRegistry)
-	assert.Equal(t, 21, len(m))
+	assert.Equal(t, 26, len(m))
 	for id := range m {
 		assert.Equal(t, int64(0), m[id].Get())
 	}
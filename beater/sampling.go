@@ -0,0 +1,118 @@
+// Licensed to Elasticsearch B.V. under one or more contributor
+// license agreements. See the NOTICE file distributed with
+// this work for additional information regarding copyright
+// ownership. Elasticsearch B.V. licenses this file to you under
+// the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package beater
+
+import (
+	"context"
+	"sync/atomic"
+
+	"golang.org/x/sync/errgroup"
+
+	"github.com/elastic/beats/v7/libbeat/logp"
+
+	"github.com/elastic/apm-server/beater/config"
+	logs "github.com/elastic/apm-server/log"
+	"github.com/elastic/apm-server/model"
+	"github.com/elastic/apm-server/policyfile"
+	"github.com/elastic/apm-server/sampling"
+)
+
+// samplingPolicies holds the sampling rules and tail-based sampling policies
+// in effect. If a sampling policies file is configured, both are read from
+// the file, and replaced together when watch observes a change to the file.
+type samplingPolicies struct {
+	file   *policyfile.File
+	logger *logp.Logger
+
+	rules atomic.Value // config.SamplingRules
+	tail  []sampling.TailSamplingPolicy
+
+	// tailSampler, if non-nil, has its policies replaced
+	// when the file changes.
+	tailSampler *sampling.TailSampler
+}
+
+func newSamplingPolicies(cfg config.SamplingConfig) *samplingPolicies {
+	p := &samplingPolicies{logger: logp.NewLogger(logs.Sampling)}
+	rules, tail := cfg.Rules, cfg.Tail.Policies
+	if path := cfg.PoliciesFile.Path; path != "" {
+		p.file = policyfile.New(path, cfg.PoliciesFile.ReloadPeriod, config.UnpackSamplingPolicies)
+		// The file has been validated when loading the config.
+		if v, _ := p.file.Load(); v != nil {
+			policies := v.(config.SamplingPolicies)
+			rules, tail = policies.Rules, policies.TailPolicies
+		}
+	}
+	p.rules.Store(rules)
+	p.tail = tailSamplingPolicies(tail)
+	return p
+}
+
+// hasTransactionRules reports whether transactions may need to be sampled
+// server-side, now or once the policies file changes.
+func (p *samplingPolicies) hasTransactionRules() bool {
+	return p.file != nil || p.rules.Load().(config.SamplingRules).HasTransactionRules()
+}
+
+// rate returns the head-based sampling rate desired for the service with the
+// given name and environment, according to the sampling rules in effect.
+func (p *samplingPolicies) rate(serviceName, environment string) (float64, bool) {
+	return p.rules.Load().(config.SamplingRules).Rate(serviceName, environment)
+}
+
+// transactionRate returns the sampling rate the server should apply to tx,
+// according to the sampling rules currently in effect.
+func (p *samplingPolicies) transactionRate(tx *model.Transaction) (float64, bool) {
+	service := tx.Metadata.Service
+	rules := p.rules.Load().(config.SamplingRules)
+	return rules.TransactionRate(service.Name, service.Environment, tx.Type, tx.Name)
+}
+
+// watch replaces the sampling rules and tail-based sampling policies
+// whenever the policies file changes, until ctx is cancelled.
+func (p *samplingPolicies) watch(ctx context.Context) error {
+	return p.file.Watch(ctx, func(v interface{}) {
+		policies := v.(config.SamplingPolicies)
+		if p.tailSampler != nil && len(policies.TailPolicies) == 0 {
+			p.logger.Error("sampling policies file holds no tail.policies, keeping previous policies")
+			return
+		}
+		p.rules.Store(policies.Rules)
+		if p.tailSampler != nil {
+			p.tailSampler.SetPolicies(tailSamplingPolicies(policies.TailPolicies))
+		}
+		p.logger.Info("replaced sampling rules and tail-based sampling policies")
+	})
+}
+
+// runServerWithSamplingPolicies wraps runServer such that the sampling
+// policies file is watched while the server runs.
+func runServerWithSamplingPolicies(runServer RunServerFunc, policies *samplingPolicies) RunServerFunc {
+	return func(ctx context.Context, args ServerParams) error {
+		g, ctx := errgroup.WithContext(ctx)
+		watchCtx, stopWatching := context.WithCancel(ctx)
+		g.Go(func() error {
+			return policies.watch(watchCtx)
+		})
+		g.Go(func() error {
+			defer stopWatching()
+			return runServer(ctx, args)
+		})
+		return g.Wait()
+	}
+}
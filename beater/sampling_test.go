@@ -0,0 +1,79 @@
+// Licensed to Elasticsearch B.V. under one or more contributor
+// license agreements. See the NOTICE file distributed with
+// this work for additional information regarding copyright
+// ownership. Elasticsearch B.V. licenses this file to you under
+// the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package beater
+
+import (
+	"context"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/elastic/apm-server/beater/config"
+	"github.com/elastic/apm-server/model"
+	"github.com/elastic/apm-server/sampling"
+)
+
+func TestSamplingPoliciesWatch(t *testing.T) {
+	dir, err := ioutil.TempDir("", "sampling")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+	path := filepath.Join(dir, "policies.yml")
+	require.NoError(t, ioutil.WriteFile(path, []byte(`
+rules: [{transaction.type: messaging, rate: 0}]
+tail.policies: [{rate: 1}]
+`), 0644))
+
+	cfg := config.DefaultConfig("").Sampling
+	cfg.Tail.Enabled = true
+	cfg.PoliciesFile = config.PolicyFileConfig{Path: path, ReloadPeriod: time.Millisecond}
+	policies := newSamplingPolicies(cfg)
+	assert.True(t, policies.hasTransactionRules())
+	assert.Equal(t, []sampling.TailSamplingPolicy{{SampleRate: 1}}, policies.tail)
+
+	var tx model.Transaction
+	tx.Type = "messaging"
+	rate, ok := policies.transactionRate(&tx)
+	assert.True(t, ok)
+	assert.Equal(t, 0.0, rate)
+
+	policies.tailSampler = sampling.NewTailSampler(tailSamplerConfig(cfg.Tail, policies.tail))
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan error)
+	go func() { done <- policies.watch(ctx) }()
+
+	// Rules and tail-based sampling policies are replaced together.
+	later := time.Now().Add(time.Second)
+	require.NoError(t, ioutil.WriteFile(path, []byte(`
+rules: [{transaction.type: messaging, rate: 0.5}]
+tail.policies: [{rate: 0.1}]
+`), 0644))
+	require.NoError(t, os.Chtimes(path, later, later))
+	for deadline := time.Now().Add(10 * time.Second); ; time.Sleep(time.Millisecond) {
+		if rate, _ := policies.transactionRate(&tx); rate == 0.5 {
+			break
+		}
+		require.True(t, time.Now().Before(deadline), "timed out waiting for the rules to be reloaded")
+	}
+	cancel()
+	assert.NoError(t, <-done)
+}
@@ -19,6 +19,7 @@ package beater
 
 import (
 	"context"
+	"net"
 	"net/http"
 
 	"go.elastic.co/apm"
@@ -27,8 +28,10 @@ import (
 	"github.com/elastic/beats/v7/libbeat/logp"
 	"github.com/elastic/beats/v7/libbeat/version"
 
+	"github.com/elastic/apm-server/beater/api/status"
 	"github.com/elastic/apm-server/beater/config"
 	"github.com/elastic/apm-server/beater/jaeger"
+	"github.com/elastic/apm-server/labels"
 	"github.com/elastic/apm-server/publish"
 )
 
@@ -51,14 +54,31 @@ type ServerParams struct {
 	// Reporter is the publish.Reporter that the APM Server
 	// should use for reporting events.
 	Reporter publish.Reporter
+
+	// Listening, if non-nil, is called with the HTTP server's
+	// listening address once it has started listening.
+	Listening func(net.Addr)
+
+	// SamplingRate, if non-nil, returns the head-based sampling rate
+	// desired for a service, reported to agents in intake responses.
+	// If nil, the rates of the sampling rules in Config are reported.
+	SamplingRate func(serviceName, environment string) (float64, bool)
+
+	// LabelSanitizer, if non-nil, is the labels.Sanitizer wrapped by
+	// Reporter. It is used to sanitize the metadata labels of each
+	// intake stream once, rather than for each of its events.
+	LabelSanitizer *labels.Sanitizer
 }
 
 // runServer runs the APM Server until a fatal error occurs, or ctx is cancelled.
 func runServer(ctx context.Context, args ServerParams) error {
-	srv, err := newServer(args.Logger, args.Config, args.Tracer, args.Reporter)
+	srv, err := newServer(args.Logger, args.Config, args.Tracer, args.Reporter, args.SamplingRate, args.LabelSanitizer)
 	if err != nil {
 		return err
 	}
+	if srv.httpServer != nil {
+		srv.httpServer.listening = args.Listening
+	}
 	done := make(chan struct{})
 	defer close(done)
 	go func() {
@@ -80,8 +100,13 @@ type server struct {
 	reporter     publish.Reporter
 }
 
-func newServer(logger *logp.Logger, cfg *config.Config, tracer *apm.Tracer, reporter publish.Reporter) (server, error) {
-	httpServer, err := newHTTPServer(logger, cfg, tracer, reporter)
+func newServer(logger *logp.Logger, cfg *config.Config, tracer *apm.Tracer, reporter publish.Reporter, samplingRate func(serviceName, environment string) (float64, bool), sanitizer *labels.Sanitizer) (server, error) {
+	// The status API reports on the events received by all servers,
+	// so they all report events through the status tracker.
+	tracker := status.NewTracker(status.DefaultMaxServices)
+	reporter = tracker.Wrap(reporter)
+
+	httpServer, err := newHTTPServer(logger, cfg, tracer, reporter, tracker, samplingRate, sanitizer)
 	if err != nil {
 		return server{}, err
 	}
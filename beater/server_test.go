@@ -24,6 +24,7 @@ import (
 	"io/ioutil"
 	"net"
 	"net/http"
+	"net/http/httptest"
 	"os"
 	"runtime"
 	"testing"
@@ -32,6 +33,7 @@ import (
 	"github.com/gofrs/uuid"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
+	"go.elastic.co/apm/apmtest"
 
 	"github.com/elastic/beats/v7/libbeat/beat"
 	"github.com/elastic/beats/v7/libbeat/common"
@@ -47,7 +49,10 @@ import (
 	"github.com/elastic/apm-server/beater/api"
 	"github.com/elastic/apm-server/beater/config"
 	"github.com/elastic/apm-server/elasticsearch"
+	"github.com/elastic/apm-server/model"
+	"github.com/elastic/apm-server/publish"
 	"github.com/elastic/apm-server/tests/loader"
+	"github.com/elastic/apm-server/transform"
 )
 
 type m map[string]interface{}
@@ -65,6 +70,25 @@ func TestServerOk(t *testing.T) {
 	assert.Equal(t, http.StatusAccepted, res.StatusCode, body(t, res))
 }
 
+func TestServerStatusTracksAllReporters(t *testing.T) {
+	cfg := config.DefaultConfig(version.GetDefaultVersion())
+	srv, err := newServer(logp.NewLogger("test"), cfg, apmtest.DiscardTracer, func(context.Context, publish.PendingReq) error { return nil }, nil, nil)
+	require.NoError(t, err)
+
+	// Events reported by other servers, such as the Jaeger server,
+	// share the reporter given to the HTTP server's status API.
+	require.NoError(t, srv.reporter(context.Background(), publish.PendingReq{
+		Transformables: []transform.Transformable{&model.Transaction{Metadata: model.Metadata{
+			Service: model.Service{Name: "jaeger-service", Agent: model.Agent{Name: "Jaeger"}},
+		}}},
+	}))
+
+	w := httptest.NewRecorder()
+	srv.httpServer.Handler.ServeHTTP(w, httptest.NewRequest(http.MethodGet, api.StatusPath, nil))
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Contains(t, w.Body.String(), `"name":"jaeger-service"`)
+}
+
 func TestServerRoot(t *testing.T) {
 	apm, err := setupServer(t, nil, nil, nil)
 	require.NoError(t, err)
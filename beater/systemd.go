@@ -0,0 +1,107 @@
+// Licensed to Elasticsearch B.V. under one or more contributor
+// license agreements. See the NOTICE file distributed with
+// this work for additional information regarding copyright
+// ownership. Elasticsearch B.V. licenses this file to you under
+// the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package beater
+
+import (
+	"net"
+	"os"
+	"sync"
+
+	"github.com/elastic/beats/v7/libbeat/logp"
+)
+
+const (
+	sdNotifyReady    = "READY=1"
+	sdNotifyStopping = "STOPPING=1"
+)
+
+// sdNotify sends state to the systemd service manager, if the process has
+// been started by a service unit with Type=notify. Otherwise sdNotify does
+// nothing and returns false.
+func sdNotify(state string) (bool, error) {
+	socket := os.Getenv("NOTIFY_SOCKET")
+	if socket == "" {
+		return false, nil
+	}
+	addr := &net.UnixAddr{Name: socket, Net: "unixgram"}
+	if socket[0] == '@' {
+		// abstract namespace socket
+		addr.Name = "\x00" + socket[1:]
+	}
+	conn, err := net.DialUnix(addr.Net, nil, addr)
+	if err != nil {
+		return false, err
+	}
+	defer conn.Close()
+	if _, err := conn.Write([]byte(state)); err != nil {
+		return false, err
+	}
+	return true, nil
+}
+
+// readiness reports the server as ready to the service manager once it is
+// listening for requests and, when publishing to Elasticsearch, the output
+// has successfully connected to Elasticsearch for the first time.
+type readiness struct {
+	logger *logp.Logger
+	notify func(state string) (bool, error)
+
+	mu                sync.Mutex
+	listening, waitES bool
+	ready             bool
+}
+
+func newReadiness(logger *logp.Logger, waitES bool) *readiness {
+	return &readiness{logger: logger, notify: sdNotify, waitES: waitES}
+}
+
+// setListening records that the server is listening for requests.
+func (r *readiness) setListening(net.Addr) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.listening = true
+	r.update()
+}
+
+// setConnected records that the Elasticsearch output has connected.
+func (r *readiness) setConnected() {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.waitES = false
+	r.update()
+}
+
+// stopping reports to the service manager that the server is stopping.
+func (r *readiness) stopping() {
+	if _, err := r.notify(sdNotifyStopping); err != nil {
+		r.logger.Warnf("failed to notify service manager of stopping: %s", err)
+	}
+}
+
+func (r *readiness) update() {
+	if r.ready || !r.listening || r.waitES {
+		return
+	}
+	r.ready = true
+	notified, err := r.notify(sdNotifyReady)
+	if err != nil {
+		r.logger.Warnf("failed to notify service manager of readiness: %s", err)
+	} else if notified {
+		r.logger.Info("notified service manager of readiness")
+	}
+}
@@ -0,0 +1,96 @@
+// Licensed to Elasticsearch B.V. under one or more contributor
+// license agreements. See the NOTICE file distributed with
+// this work for additional information regarding copyright
+// ownership. Elasticsearch B.V. licenses this file to you under
+// the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package beater
+
+import (
+	"io/ioutil"
+	"net"
+	"os"
+	"path/filepath"
+	"runtime"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/elastic/beats/v7/libbeat/logp"
+)
+
+func TestSDNotify(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("systemd is not available on Windows")
+	}
+	defer os.Setenv("NOTIFY_SOCKET", os.Getenv("NOTIFY_SOCKET"))
+
+	os.Unsetenv("NOTIFY_SOCKET")
+	notified, err := sdNotify(sdNotifyReady)
+	assert.NoError(t, err)
+	assert.False(t, notified)
+
+	dir, err := ioutil.TempDir("", "apm-server-sdnotify")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+	socket := filepath.Join(dir, "notify.sock")
+	conn, err := net.ListenUnixgram("unixgram", &net.UnixAddr{Name: socket, Net: "unixgram"})
+	require.NoError(t, err)
+	defer conn.Close()
+
+	os.Setenv("NOTIFY_SOCKET", socket)
+	notified, err = sdNotify(sdNotifyReady)
+	require.NoError(t, err)
+	assert.True(t, notified)
+
+	buf := make([]byte, 64)
+	n, err := conn.Read(buf)
+	require.NoError(t, err)
+	assert.Equal(t, sdNotifyReady, string(buf[:n]))
+}
+
+func TestReadiness(t *testing.T) {
+	var states []string
+	newTestReadiness := func(waitES bool) *readiness {
+		states = nil
+		r := newReadiness(logp.NewLogger("test"), waitES)
+		r.notify = func(state string) (bool, error) {
+			states = append(states, state)
+			return true, nil
+		}
+		return r
+	}
+
+	r := newTestReadiness(false)
+	r.setListening(nil)
+	r.setListening(nil)
+	assert.Equal(t, []string{sdNotifyReady}, states)
+	r.stopping()
+	assert.Equal(t, []string{sdNotifyReady, sdNotifyStopping}, states)
+
+	// readiness is delayed until connected to Elasticsearch
+	r = newTestReadiness(true)
+	r.setListening(nil)
+	assert.Empty(t, states)
+	r.setConnected()
+	r.setConnected()
+	assert.Equal(t, []string{sdNotifyReady}, states)
+
+	r = newTestReadiness(true)
+	r.setConnected()
+	assert.Empty(t, states)
+	r.setListening(nil)
+	assert.Equal(t, []string{sdNotifyReady}, states)
+}
@@ -34,6 +34,7 @@ import (
 
 	"github.com/elastic/apm-server/beater/api"
 	"github.com/elastic/apm-server/beater/config"
+	"github.com/elastic/apm-server/labels"
 	logs "github.com/elastic/apm-server/log"
 	"github.com/elastic/apm-server/pipelistener"
 	"github.com/elastic/apm-server/publish"
@@ -102,8 +103,12 @@ func initTracer(info beat.Info, cfg *config.Config, logger *logp.Logger) (*apm.T
 	if cfg.SelfInstrumentation.Environment != nil {
 		environment = *cfg.SelfInstrumentation.Environment
 	}
+	serviceName := info.Beat
+	if cfg.SelfInstrumentation.ServiceName != "" {
+		serviceName = cfg.SelfInstrumentation.ServiceName
+	}
 	tracer, err := apm.NewTracerOptions(apm.TracerOptions{
-		ServiceName:        info.Beat,
+		ServiceName:        serviceName,
 		ServiceVersion:     info.Version,
 		ServiceEnvironment: environment,
 		Transport:          tracerTransport,
@@ -113,6 +118,19 @@ func initTracer(info beat.Info, cfg *config.Config, logger *logp.Logger) (*apm.T
 	}
 	tracer.SetLogger(logp.NewLogger(logs.Tracing))
 
+	// The tracer reports Go runtime and system metrics (CPU, memory, GC,
+	// goroutines) by default; we add process metrics not covered by those.
+	if metrics := cfg.SelfInstrumentation.Metrics; !metrics.IsEnabled() {
+		logger.Infof("self instrumentation metrics are disabled")
+		tracer.SetMetricsInterval(0)
+	} else {
+		if metrics != nil {
+			logger.Infof("self instrumentation metrics: every %s", metrics.Interval)
+			tracer.SetMetricsInterval(metrics.Interval)
+		}
+		tracer.RegisterMetricsGatherer(apm.GatherMetricsFunc(gatherProcessMetrics))
+	}
+
 	return tracer, tracerServer, nil
 }
 
@@ -163,8 +181,8 @@ func newTracerServer(cfg *config.Config) (*tracerServer, error) {
 	}, nil
 }
 
-func (s *tracerServer) serve(report publish.Reporter) error {
-	mux, err := api.NewMux(s.cfg, report)
+func (s *tracerServer) serve(report publish.Reporter, sanitizer *labels.Sanitizer) error {
+	mux, err := api.NewMux(s.cfg, report, nil, nil, sanitizer)
 	if err != nil {
 		return err
 	}
@@ -156,6 +156,39 @@ func TestServerTracingDisabled(t *testing.T) {
 	}
 }
 
+func TestServerTracingMetrics(t *testing.T) {
+	events := make(chan beat.Event, 10)
+	cfg := common.MustNewConfigFrom(m{
+		"instrumentation": m{
+			"enabled":      true,
+			"service_name": "apm-server-self",
+			"metrics":      m{"interval": "100ms"},
+		},
+	})
+	beater, err := setupServer(t, cfg, nil, events)
+	require.NoError(t, err)
+	defer beater.Stop()
+
+	timeout := time.After(5 * time.Second)
+	for {
+		select {
+		case e := <-events:
+			processor := e.Fields["processor"].(common.MapStr)
+			if processor["event"] != "metric" {
+				continue
+			}
+			serviceName, _ := e.GetValue("service.name")
+			assert.Equal(t, "apm-server-self", serviceName)
+			if goroutines, err := e.GetValue("golang.goroutines"); err == nil {
+				assert.NotZero(t, goroutines)
+				return
+			}
+		case <-timeout:
+			t.Fatal("timed out waiting for self-instrumentation metrics")
+		}
+	}
+}
+
 func eventTransactionId(event beat.Event) string {
 	transaction := event.Fields["transaction"].(common.MapStr)
 	return transaction["id"].(string)
@@ -182,8 +215,7 @@ func transactionEvents(events <-chan beat.Event) <-chan beat.Event {
 
 // setupTestServerInstrumentation sets up a beater with or without instrumentation enabled,
 // and returns a channel to which events are published, and a function to be
-// called to teardown the beater. The initial onboarding event is consumed
-// and a transactions request is made before returning.
+// called to teardown the beater. A transactions request is made before returning.
 func setupTestServerInstrumentation(t *testing.T, enabled bool) (chan beat.Event, func()) {
 	if testing.Short() {
 		t.Skip("skipping server test")
@@ -202,10 +234,6 @@ func setupTestServerInstrumentation(t *testing.T, enabled bool) (chan beat.Event
 	beater, err := setupServer(t, cfg, nil, events)
 	require.NoError(t, err)
 
-	// onboarding event
-	e := <-events
-	assert.Equal(t, "onboarding", e.Fields["processor"].(common.MapStr)["name"])
-
 	// Send a transaction request so we have something to trace.
 	req := makeTransactionRequest(t, beater.baseURL)
 	req.Header.Add("Content-Type", "application/x-ndjson")
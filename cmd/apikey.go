@@ -26,6 +26,7 @@ import (
 	"io"
 	"io/ioutil"
 	"os"
+	"sort"
 	"strings"
 	"time"
 
@@ -43,6 +44,10 @@ import (
 	es "github.com/elastic/apm-server/elasticsearch"
 )
 
+// outputRoleName is the name of the role descriptor for API Keys
+// created for APM Server to connect to Elasticsearch.
+const outputRoleName = "apm_server_output"
+
 func genApikeyCmd(settings instance.Settings) *cobra.Command {
 
 	short := "Manage API Keys for communication between APM agents and server"
@@ -58,6 +63,7 @@ Check the Elastic Security API documentation for details.`,
 
 	apikeyCmd.AddCommand(
 		createApikeyCmd(settings),
+		createOutputApikeyCmd(settings),
 		invalidateApikeyCmd(settings),
 		getApikeysCmd(settings),
 		verifyApikeyCmd(settings),
@@ -101,6 +107,30 @@ If no privilege(s) are specified, the API Key will be valid for all.`,
 	return create
 }
 
+func createOutputApikeyCmd(settings instance.Settings) *cobra.Command {
+	var keyName, expiration string
+	var json bool
+	short := "Create an API Key for APM Server to connect to Elasticsearch"
+	create := &cobra.Command{
+		Use:   "create-output",
+		Short: short,
+		Long: short + `.
+The API Key is granted the minimal privileges needed for publishing events and reading sourcemaps,
+and can be used for "output.elasticsearch.api_key". It does not allow setting up index templates,
+ILM policies, or ingest pipelines, which require an administrative user.`,
+		Run: makeAPIKeyRun(settings, &json, func(client es.Client, config *config.Config, args []string) error {
+			return createOutputAPIKey(client, keyName, expiration, outputRoleDescriptor(config), json)
+		}),
+	}
+	create.Flags().StringVar(&keyName, "name", "apm-server-output", "API Key name")
+	create.Flags().StringVar(&expiration, "expiration", "",
+		`expiration for the key, eg. "1d" (default never)`)
+	create.Flags().BoolVar(&json, "json", false,
+		"prints the output of this command as JSON")
+	create.Flags().SortFlags = false
+	return create
+}
+
 func invalidateApikeyCmd(settings instance.Settings) *cobra.Command {
 	var id, name string
 	var json bool
@@ -351,6 +381,84 @@ PUT /_security/role/my_role {
 	return nil
 }
 
+// outputRoleDescriptor returns the minimal privileges required by APM Server
+// for publishing events to Elasticsearch, and fetching sourcemaps.
+func outputRoleDescriptor(config *config.Config) es.Applications {
+	return es.Applications{
+		// monitor is required for checking the Elasticsearch version and license.
+		Cluster: []string{"monitor"},
+		Index: []es.IndexPrivileges{{
+			Names:      []string{"apm-*"},
+			Privileges: []string{"create_doc", "create_index"},
+		}, {
+			Names:      []string{config.RumConfig.SourceMapping.IndexPattern},
+			Privileges: []string{"read"},
+		}},
+	}
+}
+
+func createOutputAPIKey(client es.Client, keyName, expiry string, role es.Applications, asJSON bool) error {
+	// API keys are limited to the privileges of the user that creates them,
+	// so check first whether the user has all of the requested privileges.
+	hasPrivileges, err := es.HasPrivileges(context.Background(), client, es.HasPrivilegesRequest{
+		Cluster: role.Cluster,
+		Index:   role.Index,
+	}, "")
+	if err != nil {
+		return err
+	}
+	if !hasPrivileges.HasAll {
+		var missingPrivileges []string
+		for privilege, hasPrivilege := range hasPrivileges.Cluster {
+			if !hasPrivilege {
+				missingPrivileges = append(missingPrivileges, privilege)
+			}
+		}
+		for index, privileges := range hasPrivileges.Index {
+			for privilege, hasPrivilege := range privileges {
+				if !hasPrivilege {
+					missingPrivileges = append(missingPrivileges, fmt.Sprintf("%s (%s)", privilege, index))
+				}
+			}
+		}
+		sort.Strings(missingPrivileges)
+		return fmt.Errorf("%s is missing the following requested privilege(s): %s",
+			hasPrivileges.Username, strings.Join(missingPrivileges, ", "))
+	}
+
+	apikeyRequest := es.CreateAPIKeyRequest{
+		Name:            keyName,
+		RoleDescriptors: es.RoleDescriptor{outputRoleName: role},
+	}
+	if expiry != "" {
+		apikeyRequest.Expiration = &expiry
+	}
+	response, err := es.CreateAPIKey(context.Background(), client, apikeyRequest)
+	if err != nil {
+		return err
+	}
+
+	type APIKey struct {
+		es.CreateAPIKeyResponse
+		Credentials string `json:"credentials"`
+	}
+	apikey := APIKey{
+		CreateAPIKeyResponse: response,
+		Credentials:          response.ID + ":" + response.Key,
+	}
+
+	printText, printJSON := printers(asJSON)
+	printText("API Key created:")
+	printText("")
+	printText("Name ........... %s", apikey.Name)
+	printText("Expiration ..... %s", humanTime(apikey.ExpirationMs))
+	printText("Id ............. %s", apikey.ID)
+	printText("API Key ........ %s (won't be shown again)", apikey.Key)
+	printText(`Credentials .... %s (use it as "output.elasticsearch.api_key", won't be shown again)`, apikey.Credentials)
+	printJSON(apikey)
+	return nil
+}
+
 func getAPIKey(client es.Client, id, name *string, validOnly, asJSON bool) error {
 	if isSet(id) {
 		name = nil
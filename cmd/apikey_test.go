@@ -0,0 +1,92 @@
+// Licensed to Elasticsearch B.V. under one or more contributor
+// license agreements. See the NOTICE file distributed with
+// this work for additional information regarding copyright
+// ownership. Elasticsearch B.V. licenses this file to you under
+// the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package cmd
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/elastic/apm-server/beater/config"
+	es "github.com/elastic/apm-server/elasticsearch"
+)
+
+func TestCreateOutputAPIKey(t *testing.T) {
+	var hasPrivilegesRequest, createRequest map[string]interface{}
+	hasAll := true
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		switch r.URL.Path {
+		case "/_security/user/_has_privileges":
+			require.NoError(t, json.NewDecoder(r.Body).Decode(&hasPrivilegesRequest))
+			json.NewEncoder(w).Encode(map[string]interface{}{
+				"username":          "admin",
+				"has_all_requested": hasAll,
+				"cluster":           map[string]bool{"monitor": true},
+				"index": map[string]interface{}{
+					"apm-*":              map[string]bool{"create_doc": true, "create_index": hasAll},
+					"apm-*-sourcemap*": map[string]bool{"read": true},
+				},
+			})
+		case "/_security/api_key":
+			require.NoError(t, json.NewDecoder(r.Body).Decode(&createRequest))
+			json.NewEncoder(w).Encode(map[string]interface{}{
+				"id": "key_id", "name": "apm-server-output", "api_key": "key_secret",
+			})
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer srv.Close()
+
+	client, err := es.NewClient(&es.Config{Hosts: []string{srv.URL}})
+	require.NoError(t, err)
+	role := outputRoleDescriptor(config.DefaultConfig("8.0.0"))
+
+	err = createOutputAPIKey(client, "apm-server-output", "", role, true)
+	require.NoError(t, err)
+
+	indexPrivileges := []interface{}{
+		map[string]interface{}{"names": []interface{}{"apm-*"}, "privileges": []interface{}{"create_doc", "create_index"}},
+		map[string]interface{}{"names": []interface{}{"apm-*-sourcemap*"}, "privileges": []interface{}{"read"}},
+	}
+	assert.Equal(t, map[string]interface{}{
+		"cluster": []interface{}{"monitor"},
+		"index":   indexPrivileges,
+	}, hasPrivilegesRequest)
+	assert.Equal(t, map[string]interface{}{
+		"name": "apm-server-output",
+		"role_descriptors": map[string]interface{}{
+			"apm_server_output": map[string]interface{}{
+				"cluster": []interface{}{"monitor"},
+				"index":   indexPrivileges,
+			},
+		},
+	}, createRequest)
+
+	// The API Key is not created if the user lacks any of the privileges.
+	createRequest = nil
+	hasAll = false
+	err = createOutputAPIKey(client, "apm-server-output", "", role, true)
+	assert.EqualError(t, err, "admin is missing the following requested privilege(s): create_index (apm-*)")
+	assert.Nil(t, createRequest)
+}
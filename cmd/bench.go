@@ -0,0 +1,332 @@
+// Licensed to Elasticsearch B.V. under one or more contributor
+// license agreements. See the NOTICE file distributed with
+// this work for additional information regarding copyright
+// ownership. Elasticsearch B.V. licenses this file to you under
+// the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+// +build bench
+
+package cmd
+
+import (
+	"bytes"
+	"context"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"math"
+	"math/rand"
+	"os"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/pkg/errors"
+	"github.com/spf13/cobra"
+
+	"github.com/elastic/apm-server/tests/generator"
+)
+
+func init() {
+	optionalCommands = append(optionalCommands, genBenchCmd)
+}
+
+func genBenchCmd() *cobra.Command {
+	var clientFlags intakeClientFlags
+	b := benchmark{
+		generator: payloadGenerator{
+			services:               1,
+			transactionsPerRequest: 10,
+			spansPerTransaction:    5,
+			errorRate:              0.1,
+		},
+		concurrency: 1,
+		duration:    30 * time.Second,
+	}
+	cmd := &cobra.Command{
+		Use:   "bench",
+		Short: "Generate load against an APM Server",
+		Long: `Generate load against an APM Server.
+Synthetic transactions, spans and errors are sent to the server's intake endpoint
+for the given duration, after which the achieved events per second and request
+latency percentiles are reported.`,
+		Args: cobra.NoArgs,
+		Run: func(cmd *cobra.Command, args []string) {
+			if err := b.validate(); err != nil {
+				fmt.Fprintln(os.Stderr, err)
+				os.Exit(1)
+			}
+			b.intakeClient = clientFlags.intakeClient()
+			result, err := b.run(context.Background())
+			if err != nil {
+				fmt.Fprintln(os.Stderr, err)
+				os.Exit(1)
+			}
+			result.print(os.Stdout)
+			if result.requests == result.failed {
+				os.Exit(1)
+			}
+		},
+	}
+	clientFlags.register(cmd.Flags())
+	cmd.Flags().DurationVar(&b.duration, "duration", b.duration, "Duration to generate load for")
+	cmd.Flags().IntVar(&b.concurrency, "concurrency", b.concurrency, "Number of concurrent requests")
+	cmd.Flags().IntVar(&b.generator.services, "services", b.generator.services, "Number of distinct services to generate events for")
+	cmd.Flags().IntVar(&b.generator.transactionsPerRequest, "transactions", b.generator.transactionsPerRequest,
+		"Number of transactions per request, controlling the payload size")
+	cmd.Flags().IntVar(&b.generator.spansPerTransaction, "spans", b.generator.spansPerTransaction, "Number of spans per transaction")
+	cmd.Flags().Float64Var(&b.generator.errorRate, "error-rate", b.generator.errorRate,
+		"Fraction of transactions for which an error is generated")
+	cmd.Flags().BoolVar(&b.generator.randomFields, "random-fields", b.generator.randomFields,
+		"Add random fields generated from the intake JSON schemas to all events")
+	return cmd
+}
+
+// benchmark drives an APM Server with synthetic intake payloads.
+type benchmark struct {
+	intakeClient
+	generator   payloadGenerator
+	concurrency int
+	duration    time.Duration
+}
+
+func (b *benchmark) validate() error {
+	switch {
+	case b.concurrency < 1:
+		return errors.New("concurrency must be at least 1")
+	case b.duration <= 0:
+		return errors.New("duration must be positive")
+	case b.generator.services < 1:
+		return errors.New("services must be at least 1")
+	case b.generator.transactionsPerRequest < 1:
+		return errors.New("transactions must be at least 1")
+	case b.generator.spansPerTransaction < 0:
+		return errors.New("spans must not be negative")
+	case b.generator.errorRate < 0 || b.generator.errorRate > 1:
+		return errors.New("error-rate must be between 0 and 1")
+	}
+	return nil
+}
+
+// run sends payloads from concurrent workers until the configured duration has passed.
+// An error is returned if payloads could not be generated.
+func (b *benchmark) run(ctx context.Context) (benchmarkResult, error) {
+	ctx, cancel := context.WithTimeout(ctx, b.duration)
+	defer cancel()
+
+	var mu sync.Mutex
+	var result benchmarkResult
+	var generateErr error
+	var wg sync.WaitGroup
+	start := time.Now()
+	for i := 0; i < b.concurrency; i++ {
+		wg.Add(1)
+		go func(seed int64) {
+			defer wg.Done()
+			gen := b.generator
+			gen.rng = rand.New(rand.NewSource(seed))
+			for ctx.Err() == nil {
+				payload, events, err := gen.payload(time.Now())
+				if err != nil {
+					mu.Lock()
+					if generateErr == nil {
+						generateErr = err
+					}
+					mu.Unlock()
+					cancel()
+					return
+				}
+				requestStart := time.Now()
+				err = b.send(ctx, bytes.NewReader(payload))
+				latency := time.Since(requestStart)
+				if ctx.Err() != nil {
+					// requests interrupted by the end of the benchmark are not counted
+					return
+				}
+
+				mu.Lock()
+				result.requests++
+				if err != nil {
+					result.failed++
+					result.lastErr = err
+				} else {
+					result.events += events
+					result.latencies = append(result.latencies, latency)
+				}
+				mu.Unlock()
+			}
+		}(time.Now().UnixNano() + int64(i))
+	}
+	wg.Wait()
+	if generateErr != nil {
+		return benchmarkResult{}, generateErr
+	}
+	result.elapsed = time.Since(start)
+	return result, nil
+}
+
+type benchmarkResult struct {
+	requests, failed, events int
+	elapsed                  time.Duration
+	latencies                []time.Duration
+	lastErr                  error
+}
+
+// percentile returns the latency below which the fraction p of successful requests fall.
+func (r *benchmarkResult) percentile(p float64) time.Duration {
+	if len(r.latencies) == 0 {
+		return 0
+	}
+	sort.Slice(r.latencies, func(i, j int) bool { return r.latencies[i] < r.latencies[j] })
+	i := int(math.Ceil(p*float64(len(r.latencies)))) - 1
+	if i < 0 {
+		i = 0
+	}
+	return r.latencies[i]
+}
+
+func (r *benchmarkResult) print(w io.Writer) {
+	fmt.Fprintf(w, "requests:   %d (%d failed)\n", r.requests, r.failed)
+	fmt.Fprintf(w, "events:     %d\n", r.events)
+	fmt.Fprintf(w, "elapsed:    %s\n", r.elapsed.Round(time.Millisecond))
+	fmt.Fprintf(w, "events/sec: %.1f\n", float64(r.events)/r.elapsed.Seconds())
+	fmt.Fprintf(w, "latency:    p50=%s p90=%s p99=%s max=%s\n",
+		r.percentile(0.5).Round(time.Microsecond), r.percentile(0.9).Round(time.Microsecond),
+		r.percentile(0.99).Round(time.Microsecond), r.percentile(1).Round(time.Microsecond))
+	if r.lastErr != nil {
+		fmt.Fprintf(w, "last error: %v\n", r.lastErr)
+	}
+}
+
+// payloadGenerator synthesizes intake payloads, each holding the
+// transactions of a single service along with their spans and errors.
+type payloadGenerator struct {
+	rng                    *rand.Rand
+	services               int
+	transactionsPerRequest int
+	spansPerTransaction    int
+	errorRate              float64
+	randomFields           bool
+
+	// schemaGenerators are created on first use, sharing rng.
+	schemaGenerators map[string]*generator.Generator
+}
+
+// payload returns an NDJSON intake payload with events timestamped
+// around now, and the number of events it holds.
+func (g *payloadGenerator) payload(now time.Time) ([]byte, int, error) {
+	var buf bytes.Buffer
+	var events int
+	var err error
+	enc := json.NewEncoder(&buf)
+	write := func(eventType string, fields map[string]interface{}) {
+		if err != nil {
+			return
+		}
+		if g.randomFields {
+			if fields, err = g.withRandomFields(eventType, fields); err != nil {
+				return
+			}
+		}
+		err = enc.Encode(map[string]interface{}{eventType: fields})
+	}
+
+	write("metadata", map[string]interface{}{
+		"service": map[string]interface{}{
+			"name":     fmt.Sprintf("bench-service-%d", g.rng.Intn(g.services)),
+			"agent":    map[string]interface{}{"name": "bench", "version": "1.0.0"},
+			"language": map[string]interface{}{"name": "go"},
+		},
+	})
+	for i := 0; i < g.transactionsPerRequest; i++ {
+		traceID, transactionID := g.id(16), g.id(8)
+		timestamp := now.Add(-time.Duration(g.rng.Intn(int(time.Second))))
+		duration := time.Duration(1+g.rng.Intn(500)) * time.Millisecond
+
+		for j := 0; j < g.spansPerTransaction; j++ {
+			write("span", map[string]interface{}{
+				"id":             g.id(8),
+				"trace_id":       traceID,
+				"transaction_id": transactionID,
+				"parent_id":      transactionID,
+				"name":           "SELECT FROM bench",
+				"type":           "db",
+				"subtype":        "postgresql",
+				"timestamp":      micros(timestamp.Add(duration * time.Duration(j) / time.Duration(g.spansPerTransaction+1))),
+				"duration":       millis(duration / time.Duration(g.spansPerTransaction+1)),
+			})
+			events++
+		}
+		if g.rng.Float64() < g.errorRate {
+			write("error", map[string]interface{}{
+				"id":             g.id(16),
+				"trace_id":       traceID,
+				"transaction_id": transactionID,
+				"parent_id":      transactionID,
+				"timestamp":      micros(timestamp.Add(duration)),
+				"exception":      map[string]interface{}{"message": "bench error", "type": "BenchError"},
+			})
+			events++
+		}
+		write("transaction", map[string]interface{}{
+			"id":         transactionID,
+			"trace_id":   traceID,
+			"name":       fmt.Sprintf("GET /bench/%d", g.rng.Intn(10)),
+			"type":       "request",
+			"result":     "HTTP 2xx",
+			"sampled":    true,
+			"timestamp":  micros(timestamp),
+			"duration":   millis(duration),
+			"span_count": map[string]interface{}{"started": g.spansPerTransaction},
+		})
+		events++
+	}
+	if err != nil {
+		return nil, 0, err
+	}
+	return buf.Bytes(), events, nil
+}
+
+// withRandomFields returns fields merged into an object generated from the
+// schema of the given event type, keeping the synthetic top-level fields.
+func (g *payloadGenerator) withRandomFields(eventType string, fields map[string]interface{}) (map[string]interface{}, error) {
+	gen, ok := g.schemaGenerators[eventType]
+	if !ok {
+		var err error
+		if gen, err = generator.NewIntake(eventType, g.rng); err != nil {
+			return nil, errors.Wrapf(err, "failed to create %s generator", eventType)
+		}
+		if g.schemaGenerators == nil {
+			g.schemaGenerators = make(map[string]*generator.Generator)
+		}
+		g.schemaGenerators[eventType] = gen
+	}
+	out := gen.GenerateObject()
+	for k, v := range fields {
+		out[k] = v
+	}
+	return out, nil
+}
+
+// id returns a random hex encoded ID of n bytes.
+func (g *payloadGenerator) id(n int) string {
+	b := make([]byte, n)
+	g.rng.Read(b)
+	return hex.EncodeToString(b)
+}
+
+func millis(d time.Duration) float64 {
+	return float64(d) / float64(time.Millisecond)
+}
@@ -0,0 +1,117 @@
+// Licensed to Elasticsearch B.V. under one or more contributor
+// license agreements. See the NOTICE file distributed with
+// this work for additional information regarding copyright
+// ownership. Elasticsearch B.V. licenses this file to you under
+// the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+// +build bench
+
+package cmd
+
+import (
+	"bytes"
+	"context"
+	"io/ioutil"
+	"math/rand"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/elastic/apm-server/beater/config"
+	"github.com/elastic/apm-server/processor/stream"
+)
+
+func TestPayloadGeneratorValid(t *testing.T) {
+	gen := payloadGenerator{
+		rng:                    rand.New(rand.NewSource(1)),
+		services:               3,
+		transactionsPerRequest: 4,
+		spansPerTransaction:    2,
+		errorRate:              0.5,
+	}
+	payload, events, err := gen.payload(time.Now())
+	require.NoError(t, err)
+
+	var out strings.Builder
+	valid, err := testIntake(stream.BackendProcessor(config.DefaultConfig("8.0.0")), bytes.NewReader(payload), &out)
+	require.NoError(t, err)
+	assert.True(t, valid, out.String())
+	assert.Equal(t, events+1, bytes.Count(payload, []byte("\n")))
+	assert.Equal(t, 4, bytes.Count(payload, []byte(`{"transaction":`)))
+	assert.Equal(t, 8, bytes.Count(payload, []byte(`{"span":`)))
+}
+
+func TestPayloadGeneratorRandomFieldsValid(t *testing.T) {
+	gen := payloadGenerator{
+		rng:                    rand.New(rand.NewSource(1)),
+		services:               1,
+		transactionsPerRequest: 20,
+		spansPerTransaction:    2,
+		errorRate:              0.5,
+		randomFields:           true,
+	}
+	for i := 0; i < 5; i++ {
+		payload, _, err := gen.payload(time.Now())
+		require.NoError(t, err)
+		var out strings.Builder
+		valid, err := testIntake(stream.BackendProcessor(config.DefaultConfig("8.0.0")), bytes.NewReader(payload), &out)
+		require.NoError(t, err)
+		assert.True(t, valid, out.String())
+	}
+}
+
+func TestPayloadGeneratorRandomFieldsError(t *testing.T) {
+	gen := payloadGenerator{rng: rand.New(rand.NewSource(1)), randomFields: true}
+	_, err := gen.withRandomFields("unknown", nil)
+	assert.EqualError(t, err, `failed to create unknown generator: unknown event type "unknown"`)
+}
+
+func TestBenchmarkRun(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		ioutil.ReadAll(r.Body)
+		w.WriteHeader(http.StatusAccepted)
+	}))
+	defer srv.Close()
+
+	b := benchmark{
+		intakeClient: intakeClient{client: srv.Client(), url: srv.URL},
+		generator:    payloadGenerator{services: 1, transactionsPerRequest: 2, spansPerTransaction: 1},
+		concurrency:  1,
+		duration:     100 * time.Millisecond,
+	}
+	require.NoError(t, b.validate())
+	result, err := b.run(context.Background())
+	require.NoError(t, err)
+	assert.NotZero(t, result.requests)
+	assert.Zero(t, result.failed)
+	assert.Equal(t, 4*result.requests, result.events)
+	assert.Len(t, result.latencies, result.requests)
+	assert.True(t, result.elapsed >= b.duration)
+}
+
+func TestBenchmarkResultPercentile(t *testing.T) {
+	var r benchmarkResult
+	assert.Zero(t, r.percentile(0.5))
+	for i := 100; i > 0; i-- {
+		r.latencies = append(r.latencies, time.Duration(i)*time.Millisecond)
+	}
+	assert.Equal(t, 50*time.Millisecond, r.percentile(0.5))
+	assert.Equal(t, 99*time.Millisecond, r.percentile(0.99))
+	assert.Equal(t, 100*time.Millisecond, r.percentile(1))
+}
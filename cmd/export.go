@@ -0,0 +1,120 @@
+// Licensed to Elasticsearch B.V. under one or more contributor
+// license agreements. See the NOTICE file distributed with
+// this work for additional information regarding copyright
+// ownership. Elasticsearch B.V. licenses this file to you under
+// the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package cmd
+
+import (
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+
+	"github.com/pkg/errors"
+	"github.com/spf13/cobra"
+
+	"github.com/elastic/beats/v7/libbeat/cmd/instance"
+	"github.com/elastic/beats/v7/libbeat/common"
+	libidxmgmt "github.com/elastic/beats/v7/libbeat/idxmgmt"
+	"github.com/elastic/beats/v7/libbeat/version"
+)
+
+// eventTemplateSetup is implemented by the APM Server index manager,
+// loading the templates relevant to a single event type.
+type eventTemplateSetup interface {
+	SetupEventTemplate(eventType string) error
+}
+
+// addEventTemplateFlag extends the `export template` command with an `--event` flag,
+// restricting the export to the templates installed for a single event type.
+func addEventTemplateFlag(templateCmd *cobra.Command, settings instance.Settings) {
+	templateCmd.Flags().String("event", "",
+		"Only export the templates installed for the given event type (transaction, span, error, metric, profile).")
+	exportAll := templateCmd.Run
+	templateCmd.Run = func(c *cobra.Command, args []string) {
+		eventType, _ := c.Flags().GetString("event")
+		if eventType == "" {
+			exportAll(c, args)
+			return
+		}
+		esVersion, _ := c.Flags().GetString("es.version")
+		dir, _ := c.Flags().GetString("dir")
+		if err := exportEventTemplate(settings, eventType, esVersion, dir); err != nil {
+			fmt.Fprintf(os.Stderr, "Error exporting template: %v.\n", err)
+			os.Exit(1)
+		}
+	}
+}
+
+func exportEventTemplate(settings instance.Settings, eventType, esVersion, dir string) error {
+	if eventType == "metricset" {
+		// metricsets are indexed with processor.event=metric
+		eventType = "metric"
+	}
+	client, err := newExportClient(esVersion, dir)
+	if err != nil {
+		return err
+	}
+	b, err := instance.NewInitializedBeat(settings)
+	if err != nil {
+		return errors.Wrap(err, "failed to initialize 'export' command")
+	}
+	clientHandler := libidxmgmt.NewFileClientHandler(client)
+	m, ok := b.IdxSupporter.Manager(clientHandler, libidxmgmt.BeatsAssets(b.Fields)).(eventTemplateSetup)
+	if !ok {
+		return errors.New("index management does not support exporting event type templates")
+	}
+	return m.SetupEventTemplate(eventType)
+}
+
+// exportClient writes exported index management components
+// to stdout, or to a file per component if dir is set.
+type exportClient struct {
+	version common.Version
+	dir     string
+}
+
+func newExportClient(esVersion, dir string) (*exportClient, error) {
+	if esVersion == "" {
+		esVersion = version.GetDefaultVersion()
+	}
+	v, err := common.NewVersion(esVersion)
+	if err != nil {
+		return nil, err
+	}
+	if dir != "" {
+		if dir, err = filepath.Abs(dir); err != nil {
+			return nil, err
+		}
+	}
+	return &exportClient{version: *v, dir: dir}, nil
+}
+
+func (c *exportClient) GetVersion() common.Version {
+	return c.version
+}
+
+func (c *exportClient) Write(component string, name string, body string) error {
+	if c.dir == "" {
+		_, err := os.Stdout.WriteString(body)
+		return err
+	}
+	path := filepath.Join(c.dir, component)
+	if err := os.MkdirAll(path, os.ModePerm); err != nil {
+		return err
+	}
+	return ioutil.WriteFile(filepath.Join(path, name+".json"), []byte(body), 0644)
+}
@@ -0,0 +1,73 @@
+// Licensed to Elasticsearch B.V. under one or more contributor
+// license agreements. See the NOTICE file distributed with
+// this work for additional information regarding copyright
+// ownership. Elasticsearch B.V. licenses this file to you under
+// the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/spf13/cobra"
+
+	"github.com/elastic/beats/v7/libbeat/cmd/instance"
+
+	"github.com/elastic/apm-server/beater/api"
+	"github.com/elastic/apm-server/beater/config"
+)
+
+func genExportOpenAPICmd(settings instance.Settings) *cobra.Command {
+	return &cobra.Command{
+		Use:   "openapi",
+		Short: "Export the OpenAPI document describing the HTTP endpoints",
+		Long: `Export an OpenAPI 3 document describing the HTTP endpoints and authorization schemes
+enabled by the loaded configuration, including example intake payloads.`,
+		Args: cobra.NoArgs,
+		Run: func(cmd *cobra.Command, args []string) {
+			beaterConfig, err := loadBeaterConfig(settings)
+			if err == nil {
+				err = writeOpenAPI(os.Stdout, beaterConfig)
+			}
+			if err != nil {
+				fmt.Fprintf(os.Stderr, "Error exporting OpenAPI document: %v.\n", err)
+				os.Exit(1)
+			}
+		},
+	}
+}
+
+func loadBeaterConfig(settings instance.Settings) (*config.Config, error) {
+	beat, err := instance.NewInitializedBeat(settings)
+	if err != nil {
+		return nil, err
+	}
+	cfg, err := beat.BeatConfig()
+	if err != nil {
+		return nil, err
+	}
+	return config.NewConfig(beat.Info.Version, cfg, nil)
+}
+
+func writeOpenAPI(w io.Writer, beaterConfig *config.Config) error {
+	out, err := json.MarshalIndent(api.OpenAPI(beaterConfig), "", "  ")
+	if err != nil {
+		return err
+	}
+	_, err = w.Write(append(out, '\n'))
+	return err
+}
@@ -0,0 +1,154 @@
+// Licensed to Elasticsearch B.V. under one or more contributor
+// license agreements. See the NOTICE file distributed with
+// this work for additional information regarding copyright
+// ownership. Elasticsearch B.V. licenses this file to you under
+// the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/pkg/errors"
+	"github.com/spf13/cobra"
+
+	errorschema "github.com/elastic/apm-server/model/error/generated/schema"
+	metadataschema "github.com/elastic/apm-server/model/metadata/generated/schema"
+	metricsetschema "github.com/elastic/apm-server/model/metricset/generated/schema"
+	sourcemapschema "github.com/elastic/apm-server/model/sourcemap/generated/schema"
+	spanschema "github.com/elastic/apm-server/model/span/generated/schema"
+	transactionschema "github.com/elastic/apm-server/model/transaction/generated/schema"
+	"github.com/elastic/apm-server/processor/stream/generated/examples"
+)
+
+// intakeSchemas holds the JSON schemas the server validates intake payloads against, by name.
+var intakeSchemas = map[string]string{
+	"metadata":           metadataschema.ModelSchema,
+	"error":              errorschema.ModelSchema,
+	"transaction":        transactionschema.ModelSchema,
+	"span":               spanschema.ModelSchema,
+	"metricset":          metricsetschema.ModelSchema,
+	"rum_v3_metadata":    metadataschema.RUMV3Schema,
+	"rum_v3_error":       errorschema.RUMV3Schema,
+	"rum_v3_transaction": transactionschema.RUMV3Schema,
+	"rum_v3_span":        spanschema.RUMV3Schema,
+	"rum_v3_metricset":   metricsetschema.RUMV3Schema,
+	"sourcemap":          sourcemapschema.PayloadSchema,
+}
+
+// intakeExamples holds valid example intake payloads, by name.
+var intakeExamples = map[string]string{
+	"events":        examples.Events,
+	"rum_v3_events": examples.RUMV3Events,
+}
+
+func genExportSchemaCmd() *cobra.Command {
+	var dir, name string
+	cmd := &cobra.Command{
+		Use:   "schema",
+		Short: "Export intake JSON schemas and example payloads",
+		Long: `Export the JSON schemas intake payloads are validated against, and valid example payloads.
+By default all schemas are printed to stdout as a single JSON object keyed by schema name.
+Use --name to print a single schema or example payload, or --dir to write all of them to files.`,
+		Args: cobra.NoArgs,
+		Run: func(cmd *cobra.Command, args []string) {
+			var err error
+			switch {
+			case dir != "":
+				err = writeSchemas(dir)
+			case name != "":
+				err = printSchema(name)
+			default:
+				err = printSchemas()
+			}
+			if err != nil {
+				fmt.Fprintf(os.Stderr, "Error exporting schema: %v.\n", err)
+				os.Exit(1)
+			}
+		},
+	}
+	cmd.Flags().StringVar(&dir, "dir", "",
+		"Specify directory for writing schema and example files. By default schemas are printed to stdout.")
+	cmd.Flags().StringVar(&name, "name", "",
+		fmt.Sprintf("Print only the named schema or example (%s)", strings.Join(schemaNames(), ", ")))
+	return cmd
+}
+
+// schemaNames returns the sorted names of all schemas, followed by the sorted names of all examples.
+func schemaNames() []string {
+	var schemas, examples []string
+	for name := range intakeSchemas {
+		schemas = append(schemas, name)
+	}
+	for name := range intakeExamples {
+		examples = append(examples, name)
+	}
+	sort.Strings(schemas)
+	sort.Strings(examples)
+	return append(schemas, examples...)
+}
+
+func printSchema(name string) error {
+	if schema, ok := intakeSchemas[name]; ok {
+		_, err := os.Stdout.WriteString(schema + "\n")
+		return err
+	}
+	if example, ok := intakeExamples[name]; ok {
+		_, err := os.Stdout.WriteString(example)
+		return err
+	}
+	return errors.Errorf("unknown schema '%s', must be one of: %s", name, strings.Join(schemaNames(), ", "))
+}
+
+func printSchemas() error {
+	schemas := make(map[string]json.RawMessage, len(intakeSchemas))
+	for name, schema := range intakeSchemas {
+		schemas[name] = json.RawMessage(schema)
+	}
+	out, err := json.MarshalIndent(schemas, "", "  ")
+	if err != nil {
+		return err
+	}
+	_, err = os.Stdout.Write(append(out, '\n'))
+	return err
+}
+
+// writeSchemas writes each schema to dir/schema/<name>.json,
+// and each example to dir/examples/<name>.ndjson.
+func writeSchemas(dir string) error {
+	write := func(subdir, file, content string) error {
+		path := filepath.Join(dir, subdir)
+		if err := os.MkdirAll(path, os.ModePerm); err != nil {
+			return err
+		}
+		return ioutil.WriteFile(filepath.Join(path, file), []byte(content), 0644)
+	}
+	for name, schema := range intakeSchemas {
+		if err := write("schema", name+".json", schema+"\n"); err != nil {
+			return err
+		}
+	}
+	for name, example := range intakeExamples {
+		if err := write("examples", name+".ndjson", example); err != nil {
+			return err
+		}
+	}
+	return nil
+}
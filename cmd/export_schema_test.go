@@ -0,0 +1,58 @@
+// Licensed to Elasticsearch B.V. under one or more contributor
+// license agreements. See the NOTICE file distributed with
+// this work for additional information regarding copyright
+// ownership. Elasticsearch B.V. licenses this file to you under
+// the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package cmd
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestWriteSchemas(t *testing.T) {
+	dir, err := ioutil.TempDir("", "apm-server-schema")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+
+	require.NoError(t, writeSchemas(dir))
+	schemaFiles, err := filepath.Glob(filepath.Join(dir, "schema", "*.json"))
+	require.NoError(t, err)
+	assert.Len(t, schemaFiles, len(intakeSchemas))
+	for _, f := range schemaFiles {
+		data, err := ioutil.ReadFile(f)
+		require.NoError(t, err)
+		assert.True(t, json.Valid(data), f)
+	}
+
+	for name, example := range intakeExamples {
+		data, err := ioutil.ReadFile(filepath.Join(dir, "examples", name+".ndjson"))
+		require.NoError(t, err)
+		assert.Equal(t, example, string(data))
+	}
+}
+
+func TestSchemaNames(t *testing.T) {
+	names := schemaNames()
+	assert.Len(t, names, len(intakeSchemas)+len(intakeExamples))
+	assert.Equal(t, "error", names[0])
+	assert.Equal(t, []string{"events", "rum_v3_events"}, names[len(names)-2:])
+}
@@ -0,0 +1,175 @@
+// Licensed to Elasticsearch B.V. under one or more contributor
+// license agreements. See the NOTICE file distributed with
+// this work for additional information regarding copyright
+// ownership. Elasticsearch B.V. licenses this file to you under
+// the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package cmd
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/pkg/errors"
+	"github.com/spf13/cobra"
+
+	"github.com/elastic/beats/v7/libbeat/cmd/instance"
+	"github.com/elastic/beats/v7/libbeat/common"
+
+	"github.com/elastic/apm-server/beater/config"
+	"github.com/elastic/apm-server/processor/stream"
+	"github.com/elastic/apm-server/publish"
+	"github.com/elastic/apm-server/transform"
+)
+
+func genTestIntakeCmd(settings instance.Settings) *cobra.Command {
+	var rum, rumV3 bool
+	cmd := &cobra.Command{
+		Use:   "intake <file>...",
+		Short: "Test intake payload files",
+		Long: `Test intake payload files.
+Each file is read as an NDJSON intake payload, with the metadata on the first line,
+and is decoded and validated the same way the intake endpoints do.
+Errors are reported per line, and the command fails if any line is invalid.
+Legacy (v1) JSON payloads are no longer accepted by the intake endpoints,
+and are reported as invalid.`,
+		Args: cobra.MinimumNArgs(1),
+		Run: func(cmd *cobra.Command, args []string) {
+			if rum && rumV3 {
+				fmt.Fprintln(os.Stderr, "--rum and --rum-v3 are mutually exclusive")
+				os.Exit(1)
+			}
+			_, cfg, err := bootstrapConfig(settings)
+			if err != nil {
+				fmt.Fprintln(os.Stderr, err)
+				os.Exit(1)
+			}
+			var p *stream.Processor
+			switch {
+			case rum:
+				p = stream.RUMProcessor(cfg, &transform.Config{})
+			case rumV3:
+				p = stream.RUMV3Processor(cfg, &transform.Config{})
+			default:
+				p = stream.BackendProcessor(cfg)
+			}
+
+			valid := true
+			for _, path := range args {
+				ok, err := testIntakeFile(p, path, os.Stdout)
+				if err != nil {
+					fmt.Fprintln(os.Stderr, err)
+					os.Exit(1)
+				}
+				valid = valid && ok
+			}
+			if !valid {
+				os.Exit(1)
+			}
+		},
+	}
+	cmd.Flags().BoolVar(&rum, "rum", false, "Validate the files as RUM v2 payloads")
+	cmd.Flags().BoolVar(&rumV3, "rum-v3", false, "Validate the files as RUM v3 payloads")
+	return cmd
+}
+
+// bootstrapConfig returns the raw `apm-server` configuration, and the
+// APM Server configuration resolved from it as loaded by the server.
+func bootstrapConfig(settings instance.Settings) (*common.Config, *config.Config, error) {
+	beat, err := instance.NewInitializedBeat(settings)
+	if err != nil {
+		return nil, nil, err
+	}
+	rawCfg, err := beat.BeatConfig()
+	if err != nil {
+		return nil, nil, err
+	}
+	var esOutputCfg *common.Config
+	if beat.Config.Output.Name() == "elasticsearch" {
+		esOutputCfg = beat.Config.Output.Config()
+	}
+	cfg, err := config.NewConfig(beat.Info.Version, rawCfg, esOutputCfg)
+	if err != nil {
+		return nil, nil, err
+	}
+	return rawCfg, cfg, nil
+}
+
+func testIntakeFile(p *stream.Processor, path string, w io.Writer) (bool, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return false, err
+	}
+	defer f.Close()
+	fmt.Fprintf(w, "%s:\n", path)
+	return testIntake(p, f, w)
+}
+
+// testIntake validates the NDJSON intake payload read from r, writing an
+// error for each invalid line to w. Every event is processed on its own,
+// together with the payload's metadata, so that all invalid lines are
+// reported rather than only the first few per request.
+//
+// testIntake reports whether all lines of the payload are valid.
+func testIntake(p *stream.Processor, r io.Reader, w io.Writer) (bool, error) {
+	discard := func(context.Context, publish.PendingReq) error { return nil }
+	handle := func(body []byte) *stream.Result {
+		return p.HandleStream(context.Background(), nil, map[string]interface{}{}, bytes.NewReader(body), discard)
+	}
+	report := func(lineNumber int, res *stream.Result) bool {
+		for _, err := range res.Errors {
+			fmt.Fprintf(w, "  line %d: %s\n", lineNumber, err.Message)
+		}
+		return len(res.Errors) == 0
+	}
+
+	reader := bufio.NewReader(r)
+	var metadata []byte
+	var lineNumber, events, invalid int
+	for {
+		line, err := reader.ReadBytes('\n')
+		if err != nil && err != io.EOF {
+			return false, errors.Wrap(err, "failed to read payload")
+		}
+		lineNumber++
+		if line = bytes.TrimSpace(line); len(line) > 0 {
+			if metadata == nil {
+				metadata = append(line, '\n')
+				if !report(lineNumber, handle(metadata)) {
+					fmt.Fprintln(w, "  invalid metadata, skipping events")
+					return false, nil
+				}
+			} else {
+				events++
+				body := append(metadata[:len(metadata):len(metadata)], line...)
+				if !report(lineNumber, handle(body)) {
+					invalid++
+				}
+			}
+		}
+		if err == io.EOF {
+			break
+		}
+	}
+	if metadata == nil {
+		fmt.Fprintln(w, "  empty payload, expected metadata")
+		return false, nil
+	}
+	fmt.Fprintf(w, "  %d events, %d invalid\n", events, invalid)
+	return invalid == 0, nil
+}
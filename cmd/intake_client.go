@@ -0,0 +1,90 @@
+// Licensed to Elasticsearch B.V. under one or more contributor
+// license agreements. See the NOTICE file distributed with
+// this work for additional information regarding copyright
+// ownership. Elasticsearch B.V. licenses this file to you under
+// the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package cmd
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"strings"
+
+	"github.com/pkg/errors"
+	"github.com/spf13/pflag"
+
+	"github.com/elastic/apm-server/beater/api"
+	"github.com/elastic/apm-server/beater/headers"
+)
+
+// intakeClient sends NDJSON payloads to an APM Server's intake endpoint.
+type intakeClient struct {
+	client        *http.Client
+	url           string
+	authorization string
+}
+
+// send sends the payload read from body in a single request,
+// returning an error if the server did not accept all events.
+func (c *intakeClient) send(ctx context.Context, body io.Reader) error {
+	req, err := http.NewRequest(http.MethodPost, c.url, body)
+	if err != nil {
+		return err
+	}
+	req = req.WithContext(ctx)
+	req.Header.Set(headers.ContentType, "application/x-ndjson")
+	if c.authorization != "" {
+		req.Header.Set(headers.Authorization, c.authorization)
+	}
+	resp, err := c.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusAccepted {
+		body, _ := ioutil.ReadAll(resp.Body)
+		return errors.Errorf("unexpected response %s: %s", resp.Status, bytes.TrimSpace(body))
+	}
+	return nil
+}
+
+// intakeClientFlags holds the command line flags for configuring an intakeClient.
+type intakeClientFlags struct {
+	serverURL   string
+	secretToken string
+	apiKey      string
+}
+
+func (f *intakeClientFlags) register(flags *pflag.FlagSet) {
+	flags.StringVar(&f.serverURL, "url", "http://localhost:8200", "URL of the target APM Server")
+	flags.StringVar(&f.secretToken, "secret-token", "", "Secret token for authorizing requests")
+	flags.StringVar(&f.apiKey, "api-key", "", "Base64 encoded API Key for authorizing requests")
+}
+
+func (f *intakeClientFlags) intakeClient() intakeClient {
+	c := intakeClient{
+		client: http.DefaultClient,
+		url:    strings.TrimRight(f.serverURL, "/") + api.IntakePath,
+	}
+	if f.secretToken != "" {
+		c.authorization = headers.Bearer + " " + f.secretToken
+	} else if f.apiKey != "" {
+		c.authorization = headers.APIKey + " " + f.apiKey
+	}
+	return c
+}
@@ -0,0 +1,99 @@
+// Licensed to Elasticsearch B.V. under one or more contributor
+// license agreements. See the NOTICE file distributed with
+// this work for additional information regarding copyright
+// ownership. Elasticsearch B.V. licenses this file to you under
+// the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package cmd
+
+import (
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/elastic/apm-server/beater/config"
+	"github.com/elastic/apm-server/processor/stream"
+)
+
+func TestTestIntake(t *testing.T) {
+	p := stream.BackendProcessor(config.DefaultConfig("8.0.0"))
+	for name, tc := range map[string]struct {
+		file   string
+		valid  bool
+		output []string
+	}{
+		"Valid": {
+			file:   "transactions.ndjson",
+			valid:  true,
+			output: []string{"4 events, 0 invalid"},
+		},
+		"InvalidEvent": {
+			file:   "invalid-event.ndjson",
+			output: []string{"line 2: failed to validate transaction", "2 events, 1 invalid"},
+		},
+		"InvalidJSONEvent": {
+			file:   "invalid-json-event.ndjson",
+			output: []string{"line 2: data read error", "2 events, 1 invalid"},
+		},
+		"UnrecognizedEvent": {
+			file:   "unrecognized-event.ndjson",
+			output: []string{"line 2: did not recognize object type", "1 events, 1 invalid"},
+		},
+		"InvalidMetadata": {
+			file:   "invalid-metadata.ndjson",
+			output: []string{"line 1: failed to validate metadata", "invalid metadata, skipping events"},
+		},
+	} {
+		t.Run(name, func(t *testing.T) {
+			f, err := os.Open("../testdata/intake-v2/" + tc.file)
+			require.NoError(t, err)
+			defer f.Close()
+
+			var out strings.Builder
+			valid, err := testIntake(p, f, &out)
+			require.NoError(t, err)
+			assert.Equal(t, tc.valid, valid)
+			for _, s := range tc.output {
+				assert.Contains(t, out.String(), s)
+			}
+		})
+	}
+}
+
+func TestTestIntakeAllInvalidLinesReported(t *testing.T) {
+	metadata := `{"metadata": {"service": {"name": "svc", "agent": {"name": "go", "version": "1.0"}}}}`
+	var payload strings.Builder
+	payload.WriteString(metadata + "\n")
+	for i := 0; i < 10; i++ {
+		payload.WriteString(`{"unknown": {}}` + "\n")
+	}
+
+	var out strings.Builder
+	valid, err := testIntake(stream.BackendProcessor(config.DefaultConfig("8.0.0")), strings.NewReader(payload.String()), &out)
+	require.NoError(t, err)
+	assert.False(t, valid)
+	assert.Equal(t, 10, strings.Count(out.String(), "did not recognize object type"))
+	assert.Contains(t, out.String(), "line 11:")
+}
+
+func TestTestIntakeEmpty(t *testing.T) {
+	var out strings.Builder
+	valid, err := testIntake(stream.BackendProcessor(config.DefaultConfig("8.0.0")), strings.NewReader("\n\n"), &out)
+	require.NoError(t, err)
+	assert.False(t, valid)
+	assert.Contains(t, out.String(), "empty payload")
+}
@@ -0,0 +1,237 @@
+// Licensed to Elasticsearch B.V. under one or more contributor
+// license agreements. See the NOTICE file distributed with
+// this work for additional information regarding copyright
+// ownership. Elasticsearch B.V. licenses this file to you under
+// the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+
+	"github.com/pkg/errors"
+	"github.com/spf13/cobra"
+
+	"github.com/elastic/beats/v7/libbeat/cmd/instance"
+	"github.com/elastic/beats/v7/libbeat/common"
+	libilm "github.com/elastic/beats/v7/libbeat/idxmgmt/ilm"
+
+	"github.com/elastic/apm-server/idxmgmt/ilm"
+
+	es "github.com/elastic/apm-server/elasticsearch"
+)
+
+const (
+	migrationActionAlias   = "alias"
+	migrationActionReindex = "reindex"
+)
+
+func genMigrateCmd(settings instance.Settings) *cobra.Command {
+	var dryRun, reindex, json bool
+	short := "Migrate indices created by previous APM Server versions to the current index strategy"
+	cmd := &cobra.Command{
+		Use:   "migrate",
+		Short: short,
+		Long: short + `.
+Indices matching "apm-*" which are not managed through the current rollover aliases are added to the
+rollover alias of their event type as read-only indices, making them available through the alias.
+With --reindex, their documents are instead reindexed into the rollover alias, running as Elasticsearch
+tasks in the background. Run "apm-server setup --index-management" before migrating, to create the
+rollover aliases. With --dry-run, the migration plan is printed without making any changes.`,
+		Run: func(cmd *cobra.Command, args []string) {
+			client, mappings, err := bootstrapMigration(settings)
+			if err != nil {
+				printErr(err, json)
+				os.Exit(1)
+			}
+			if err := migrate(context.Background(), client, mappings, reindex, dryRun, json); err != nil {
+				printErr(err, json)
+				os.Exit(1)
+			}
+		},
+	}
+	cmd.Flags().BoolVar(&dryRun, "dry-run", false,
+		"print the migration plan without making any changes")
+	cmd.Flags().BoolVar(&reindex, "reindex", false,
+		"reindex documents into the rollover aliases, instead of adding the indices to them")
+	cmd.Flags().BoolVar(&json, "json", false,
+		"prints the output of this command as JSON")
+	cmd.Flags().SortFlags = false
+	return cmd
+}
+
+// bootstrapMigration returns a client for the configured Elasticsearch output,
+// and the ILM mappings of event types to rollover aliases.
+func bootstrapMigration(settings instance.Settings) (es.Client, ilm.Mappings, error) {
+	beat, err := instance.NewInitializedBeat(settings)
+	if err != nil {
+		return nil, nil, err
+	}
+	if beat.Config.Output.Name() != "elasticsearch" {
+		return nil, nil, errors.New("migrating indices requires the Elasticsearch output to be configured")
+	}
+	esConfig := es.DefaultConfig()
+	if err := beat.Config.Output.Config().Unpack(esConfig); err != nil {
+		return nil, nil, err
+	}
+	client, err := es.NewClient(esConfig)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	cfg := struct {
+		ILM *common.Config `config:"apm-server.ilm"`
+	}{}
+	if err := beat.RawConfig.Unpack(&cfg); err != nil {
+		return nil, nil, err
+	}
+	ilmConfig, err := ilm.NewConfig(beat.Info, cfg.ILM)
+	if err != nil {
+		return nil, nil, err
+	}
+	if ilmConfig.Mode == libilm.ModeDisabled {
+		return nil, nil, errors.New("migrating indices requires ILM to be enabled")
+	}
+	return client, ilmConfig.Setup.Mappings, nil
+}
+
+// migrationStep describes the migration of a single index to a rollover alias.
+type migrationStep struct {
+	Action string `json:"action"`
+	Index  string `json:"index"`
+	Alias  string `json:"alias"`
+	Task   string `json:"task,omitempty"`
+}
+
+func migrate(ctx context.Context, client es.Client, mappings ilm.Mappings, reindex, dryRun, asJSON bool) error {
+	indices, err := es.GetAliases(ctx, client, "apm-*")
+	if err != nil {
+		return err
+	}
+	steps, err := planMigration(indices, mappings, reindex)
+	if err != nil {
+		return err
+	}
+
+	printText, printJSON := printers(asJSON)
+	if len(steps) == 0 {
+		printText("No indices to migrate.")
+		printJSON([]migrationStep{})
+		return nil
+	}
+	if dryRun {
+		for _, step := range steps {
+			printText("%s", describeMigrationStep(step, true))
+		}
+		printJSON(steps)
+		return nil
+	}
+
+	if !reindex {
+		var req es.UpdateAliasesRequest
+		for _, step := range steps {
+			req.Actions = append(req.Actions, es.AliasAction{
+				Add: &es.AddAlias{Index: step.Index, Alias: step.Alias, IsWriteIndex: false},
+			})
+		}
+		if err := es.UpdateAliases(ctx, client, req); err != nil {
+			return err
+		}
+	} else {
+		for i, step := range steps {
+			task, err := es.StartReindex(ctx, client, es.ReindexRequest{
+				Source: es.ReindexIndex{Index: step.Index},
+				Dest:   es.ReindexDest{Index: step.Alias, OpType: "create"},
+			})
+			if err != nil {
+				return errors.Wrapf(err, "error reindexing %s", step.Index)
+			}
+			steps[i].Task = task.Task
+		}
+	}
+	for _, step := range steps {
+		printText("%s", describeMigrationStep(step, false))
+	}
+	printJSON(steps)
+	return nil
+}
+
+// planMigration returns the steps for migrating the given indices to the rollover
+// aliases of their event types. Indices which are already part of the rollover alias
+// or which cannot be assigned to an event type are not migrated. An error is returned
+// if the rollover alias for an index to migrate does not exist.
+func planMigration(indices map[string]es.IndexAliases, mappings ilm.Mappings, reindex bool) ([]migrationStep, error) {
+	action := migrationActionAlias
+	if reindex {
+		action = migrationActionReindex
+	}
+	existingAliases := make(map[string]bool)
+	for _, index := range indices {
+		for alias := range index.Aliases {
+			existingAliases[alias] = true
+		}
+	}
+
+	names := make([]string, 0, len(indices))
+	for name := range indices {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	var steps []migrationStep
+	for _, name := range names {
+		alias := migrationAlias(name, mappings)
+		if alias == "" || strings.HasPrefix(name, alias+"-") {
+			continue
+		}
+		if _, ok := indices[name].Aliases[alias]; ok {
+			continue
+		}
+		if !existingAliases[alias] {
+			return nil, errors.Errorf(
+				"rollover alias %s does not exist, run 'apm-server setup --index-management' first", alias)
+		}
+		steps = append(steps, migrationStep{Action: action, Index: name, Alias: alias})
+	}
+	return steps, nil
+}
+
+// migrationAlias returns the rollover alias of the event type the index
+// was created for, derived from its name, e.g. apm-7.7.0-span-2020.05.01.
+func migrationAlias(index string, mappings ilm.Mappings) string {
+	for _, mapping := range mappings {
+		eventType := mapping.EventType
+		if strings.HasSuffix(index, "-"+eventType) || strings.Contains(index, "-"+eventType+"-") {
+			return mapping.RolloverAlias
+		}
+	}
+	return ""
+}
+
+func describeMigrationStep(step migrationStep, dryRun bool) string {
+	switch {
+	case step.Action == migrationActionReindex && dryRun:
+		return fmt.Sprintf("Would reindex %s into %s", step.Index, step.Alias)
+	case step.Action == migrationActionReindex:
+		return fmt.Sprintf("Reindexing %s into %s (task %s)", step.Index, step.Alias, step.Task)
+	case dryRun:
+		return fmt.Sprintf("Would add %s to %s", step.Index, step.Alias)
+	default:
+		return fmt.Sprintf("Added %s to %s", step.Index, step.Alias)
+	}
+}
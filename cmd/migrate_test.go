@@ -0,0 +1,127 @@
+// Licensed to Elasticsearch B.V. under one or more contributor
+// license agreements. See the NOTICE file distributed with
+// this work for additional information regarding copyright
+// ownership. Elasticsearch B.V. licenses this file to you under
+// the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package cmd
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/elastic/beats/v7/libbeat/beat"
+
+	es "github.com/elastic/apm-server/elasticsearch"
+	"github.com/elastic/apm-server/idxmgmt/ilm"
+)
+
+func migrationIndices() map[string]es.IndexAliases {
+	isWriteIndex := true
+	return map[string]es.IndexAliases{
+		"apm-8.0.0-span-000001":        {Aliases: map[string]es.Alias{"apm-8.0.0-span": {IsWriteIndex: &isWriteIndex}}},
+		"apm-8.0.0-error-000001":       {Aliases: map[string]es.Alias{"apm-8.0.0-error": {IsWriteIndex: &isWriteIndex}}},
+		"apm-7.7.0-span-000003":        {Aliases: map[string]es.Alias{"apm-7.7.0-span": {IsWriteIndex: &isWriteIndex}}},
+		"apm-7.7.0-span-000002":        {Aliases: map[string]es.Alias{"apm-7.7.0-span": {}, "apm-8.0.0-span": {}}},
+		"apm-7.6.0-error-2020.03.01":   {Aliases: map[string]es.Alias{}},
+		"apm-7.6.0-onboarding-2020.03": {Aliases: map[string]es.Alias{}},
+	}
+}
+
+func migrationMappings(t *testing.T) ilm.Mappings {
+	cfg, err := ilm.NewConfig(beat.Info{Version: "8.0.0"}, nil)
+	require.NoError(t, err)
+	return cfg.Setup.Mappings
+}
+
+func TestPlanMigration(t *testing.T) {
+	mappings := migrationMappings(t)
+
+	steps, err := planMigration(migrationIndices(), mappings, false)
+	require.NoError(t, err)
+	assert.Equal(t, []migrationStep{
+		{Action: "alias", Index: "apm-7.6.0-error-2020.03.01", Alias: "apm-8.0.0-error"},
+		{Action: "alias", Index: "apm-7.7.0-span-000003", Alias: "apm-8.0.0-span"},
+	}, steps)
+
+	steps, err = planMigration(migrationIndices(), mappings, true)
+	require.NoError(t, err)
+	require.Len(t, steps, 2)
+	assert.Equal(t, "reindex", steps[0].Action)
+
+	indices := migrationIndices()
+	delete(indices, "apm-8.0.0-error-000001")
+	_, err = planMigration(indices, mappings, false)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "rollover alias apm-8.0.0-error does not exist")
+}
+
+func TestMigrate(t *testing.T) {
+	var aliasRequests, reindexRequests []map[string]interface{}
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		var req map[string]interface{}
+		switch r.URL.Path {
+		case "/apm-*/_alias":
+			json.NewEncoder(w).Encode(migrationIndices())
+		case "/_aliases":
+			require.NoError(t, json.NewDecoder(r.Body).Decode(&req))
+			aliasRequests = append(aliasRequests, req)
+			json.NewEncoder(w).Encode(map[string]interface{}{"acknowledged": true})
+		case "/_reindex":
+			assert.Equal(t, "false", r.URL.Query().Get("wait_for_completion"))
+			require.NoError(t, json.NewDecoder(r.Body).Decode(&req))
+			reindexRequests = append(reindexRequests, req)
+			json.NewEncoder(w).Encode(map[string]interface{}{"task": "node:1"})
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer srv.Close()
+
+	client, err := es.NewClient(&es.Config{Hosts: []string{srv.URL}})
+	require.NoError(t, err)
+	mappings := migrationMappings(t)
+
+	// dry run does not change anything
+	require.NoError(t, migrate(context.Background(), client, mappings, false, true, true))
+	require.NoError(t, migrate(context.Background(), client, mappings, true, true, true))
+	assert.Empty(t, aliasRequests)
+	assert.Empty(t, reindexRequests)
+
+	require.NoError(t, migrate(context.Background(), client, mappings, false, false, true))
+	assert.Equal(t, []map[string]interface{}{{
+		"actions": []interface{}{
+			map[string]interface{}{"add": map[string]interface{}{
+				"index": "apm-7.6.0-error-2020.03.01", "alias": "apm-8.0.0-error", "is_write_index": false}},
+			map[string]interface{}{"add": map[string]interface{}{
+				"index": "apm-7.7.0-span-000003", "alias": "apm-8.0.0-span", "is_write_index": false}},
+		},
+	}}, aliasRequests)
+
+	require.NoError(t, migrate(context.Background(), client, mappings, true, false, true))
+	assert.Equal(t, []map[string]interface{}{{
+		"source": map[string]interface{}{"index": "apm-7.6.0-error-2020.03.01"},
+		"dest":   map[string]interface{}{"index": "apm-8.0.0-error", "op_type": "create"},
+	}, {
+		"source": map[string]interface{}{"index": "apm-7.7.0-span-000003"},
+		"dest":   map[string]interface{}{"index": "apm-8.0.0-span", "op_type": "create"},
+	}}, reindexRequests)
+}
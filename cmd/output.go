@@ -0,0 +1,130 @@
+// Licensed to Elasticsearch B.V. under one or more contributor
+// license agreements. See the NOTICE file distributed with
+// this work for additional information regarding copyright
+// ownership. Elasticsearch B.V. licenses this file to you under
+// the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package cmd
+
+import (
+	"github.com/elastic/beats/v7/libbeat/cfgfile"
+	"github.com/elastic/beats/v7/libbeat/common"
+)
+
+// esWorkersOverride returns a ConditionalOverride which defaults the
+// number of bulk workers per Elasticsearch host when the Elasticsearch
+// output is configured without an explicit `worker` setting.
+//
+// Each worker holds its own connection to one host and sends one bulk
+// request at a time, pulling batches from the shared publisher queue.
+// By default libbeat uses a single worker per host, serializing all
+// indexing through one bulk request per host; instead we run about one
+// worker per CPU, spread across the configured hosts.
+//
+// Batches are not routed by node health: there is no client-side health
+// scoring, and every host has the same number of workers. A host is only
+// avoided while its workers are backing off after failed requests.
+func esWorkersOverride(numCPU int) cfgfile.ConditionalOverride {
+	override := common.NewConfig()
+	return cfgfile.ConditionalOverride{
+		Check: func(cfg *common.Config) bool {
+			workers, ok := defaultESWorkers(cfg, numCPU)
+			if !ok {
+				return false
+			}
+			// The override is only merged into the defaults after
+			// Check returns, so it can be updated to suit cfg.
+			return override.SetInt("output.elasticsearch.worker", -1, int64(workers)) == nil
+		},
+		Config: override,
+	}
+}
+
+// defaultESWorkers returns the number of bulk workers per host to use for
+// the Elasticsearch output in cfg, and false if the output is not configured
+// or already specifies the number of workers.
+func defaultESWorkers(cfg *common.Config, numCPU int) (int, bool) {
+	es, ok := esOutputConfig(cfg)
+	if !ok || es.HasField("worker") {
+		return 0, false
+	}
+	var config struct {
+		Hosts []string `config:"hosts"`
+	}
+	if err := es.Unpack(&config); err != nil {
+		return 0, false
+	}
+	hosts := len(config.Hosts)
+	if hosts == 0 {
+		hosts = 1
+	}
+	workers := numCPU / hosts
+	if workers < 1 {
+		workers = 1
+	}
+	return workers, true
+}
+
+// esServiceTokenOverride returns a ConditionalOverride which authenticates
+// the Elasticsearch output with a service account token, when configured
+// with `output.elasticsearch.service_token`. The libbeat output does not
+// support service tokens, so the token is sent as a bearer token through
+// the output's custom headers.
+//
+// Configuring service_token along with other credentials is reported as
+// an error when the server's configuration is loaded.
+func esServiceTokenOverride() cfgfile.ConditionalOverride {
+	override := common.NewConfig()
+	return cfgfile.ConditionalOverride{
+		Check: func(cfg *common.Config) bool {
+			es, ok := esOutputConfig(cfg)
+			if !ok {
+				return false
+			}
+			var config struct {
+				ServiceToken string `config:"service_token"`
+				Username     string `config:"username"`
+				APIKey       string `config:"api_key"`
+			}
+			if err := es.Unpack(&config); err != nil {
+				return false
+			}
+			if config.ServiceToken == "" || config.Username != "" || config.APIKey != "" {
+				return false
+			}
+			return override.SetString(
+				"output.elasticsearch.headers.Authorization", -1,
+				"Bearer "+config.ServiceToken,
+			) == nil
+		},
+		Config: override,
+	}
+}
+
+// esOutputConfig returns the Elasticsearch output config in cfg,
+// and false if the output is not configured.
+func esOutputConfig(cfg *common.Config) (*common.Config, bool) {
+	if !cfg.HasField("output") {
+		return nil, false
+	}
+	output, err := cfg.Child("output", -1)
+	if err != nil || !output.HasField("elasticsearch") {
+		return nil, false
+	}
+	es, err := output.Child("elasticsearch", -1)
+	if err != nil {
+		return nil, false
+	}
+	return es, true
+}
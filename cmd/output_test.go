@@ -0,0 +1,110 @@
+// Licensed to Elasticsearch B.V. under one or more contributor
+// license agreements. See the NOTICE file distributed with
+// this work for additional information regarding copyright
+// ownership. Elasticsearch B.V. licenses this file to you under
+// the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package cmd
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/elastic/beats/v7/libbeat/common"
+)
+
+func TestESWorkersOverride(t *testing.T) {
+	for name, test := range map[string]struct {
+		config  map[string]interface{}
+		workers int
+		apply   bool
+	}{
+		"single host": {
+			config:  map[string]interface{}{"output.elasticsearch.hosts": []string{"localhost:9200"}},
+			workers: 8, apply: true,
+		},
+		"multiple hosts": {
+			config:  map[string]interface{}{"output.elasticsearch.hosts": []string{"es1:9200", "es2:9200", "es3:9200"}},
+			workers: 2, apply: true,
+		},
+		"more hosts than CPUs": {
+			config: map[string]interface{}{"output.elasticsearch.hosts": []string{
+				"es1", "es2", "es3", "es4", "es5", "es6", "es7", "es8", "es9",
+			}},
+			workers: 1, apply: true,
+		},
+		"explicit workers": {
+			config: map[string]interface{}{"output.elasticsearch": map[string]interface{}{
+				"hosts": []string{"localhost:9200"}, "worker": 1,
+			}},
+		},
+		"other output": {
+			config: map[string]interface{}{"output.logstash.hosts": []string{"localhost:5044"}},
+		},
+		"no output": {
+			config: map[string]interface{}{},
+		},
+	} {
+		t.Run(name, func(t *testing.T) {
+			cfg := common.MustNewConfigFrom(test.config)
+			override := esWorkersOverride(8)
+			require.Equal(t, test.apply, override.Check(cfg))
+			if !test.apply {
+				return
+			}
+			workers, err := override.Config.Int("output.elasticsearch.worker", -1)
+			require.NoError(t, err)
+			assert.Equal(t, int64(test.workers), workers)
+		})
+	}
+}
+
+func TestESServiceTokenOverride(t *testing.T) {
+	for name, test := range map[string]struct {
+		config map[string]interface{}
+		apply  bool
+	}{
+		"service token": {
+			config: map[string]interface{}{"output.elasticsearch": map[string]interface{}{
+				"hosts": []string{"localhost:9200"}, "service_token": "s3cr3t",
+			}},
+			apply: true,
+		},
+		"conflicting credentials": {
+			config: map[string]interface{}{"output.elasticsearch": map[string]interface{}{
+				"hosts": []string{"localhost:9200"}, "service_token": "s3cr3t", "api_key": "id:key",
+			}},
+		},
+		"no service token": {
+			config: map[string]interface{}{"output.elasticsearch.hosts": []string{"localhost:9200"}},
+		},
+		"no output": {
+			config: map[string]interface{}{},
+		},
+	} {
+		t.Run(name, func(t *testing.T) {
+			cfg := common.MustNewConfigFrom(test.config)
+			override := esServiceTokenOverride()
+			require.Equal(t, test.apply, override.Check(cfg))
+			if !test.apply {
+				return
+			}
+			authorization, err := override.Config.String("output.elasticsearch.headers.Authorization", -1)
+			require.NoError(t, err)
+			assert.Equal(t, "Bearer s3cr3t", authorization)
+		})
+	}
+}
@@ -0,0 +1,241 @@
+// Licensed to Elasticsearch B.V. under one or more contributor
+// license agreements. See the NOTICE file distributed with
+// this work for additional information regarding copyright
+// ownership. Elasticsearch B.V. licenses this file to you under
+// the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package cmd
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"time"
+
+	"github.com/pkg/errors"
+	"github.com/spf13/cobra"
+)
+
+func genReplayCmd() *cobra.Command {
+	var r replayer
+	var clientFlags intakeClientFlags
+	cmd := &cobra.Command{
+		Use:   "replay <file>...",
+		Short: "Replay captured intake payloads against an APM Server",
+		Long: `Replay captured intake payloads against an APM Server.
+Each file is read as an NDJSON intake payload and sent to the server's intake endpoint
+in a single request. Event timestamps can be rewritten relative to the current time,
+and events can be paced according to their original timestamps, scaled by --speed.
+Paced events are sent in a separate request per --window, each carrying the payload's metadata,
+so that long replays do not trip the server's read timeout or minimum throughput.`,
+		Args: cobra.MinimumNArgs(1),
+		Run: func(cmd *cobra.Command, args []string) {
+			r.intakeClient = clientFlags.intakeClient()
+			for _, path := range args {
+				if err := r.replayFile(context.Background(), path); err != nil {
+					fmt.Fprintf(os.Stderr, "Error replaying %s: %v\n", path, err)
+					os.Exit(1)
+				}
+				fmt.Fprintf(os.Stdout, "Replayed %s\n", path)
+			}
+		},
+	}
+	clientFlags.register(cmd.Flags())
+	cmd.Flags().BoolVar(&r.rewriteTimestamps, "now", false,
+		"Shift event timestamps so that the first event of each payload occurs at the time of sending")
+	cmd.Flags().Float64Var(&r.speed, "speed", 0,
+		"Pace events by their original timestamps, sped up by the given factor. By default events are sent as fast as possible.")
+	cmd.Flags().DurationVar(&r.window, "window", time.Second,
+		"When pacing events, send the events due within each window of the given duration in a separate request")
+	return cmd
+}
+
+// replayer sends captured NDJSON intake payloads to an APM Server.
+type replayer struct {
+	intakeClient
+
+	// rewriteTimestamps shifts event timestamps, preserving their relative
+	// offsets, such that the first event in a payload occurs at the time
+	// it is sent.
+	rewriteTimestamps bool
+
+	// speed, if positive, paces the events in a payload according to the
+	// offsets between their timestamps, divided by speed.
+	speed float64
+
+	// window is the duration of the pacing windows when speed is positive.
+	// The events due within a window are sent in a single request.
+	window time.Duration
+}
+
+func (r *replayer) replayFile(ctx context.Context, path string) error {
+	f, err := os.Open(path)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+	return r.replay(ctx, f)
+}
+
+// replay sends the payload read from in to the server, returning an error if
+// the server did not accept all events. Unless events are paced, the payload
+// is streamed in a single request.
+func (r *replayer) replay(ctx context.Context, in io.Reader) error {
+	if r.speed > 0 {
+		return r.replayPaced(ctx, in)
+	}
+
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	pr, pw := io.Pipe()
+	go func() {
+		pw.CloseWithError(r.writePayload(ctx, in, pw, nil))
+	}()
+
+	return r.send(ctx, pr)
+}
+
+// replayPaced sends the paced events of the payload read from in in a
+// separate request per pacing window, each preceded by the payload's metadata.
+func (r *replayer) replayPaced(ctx context.Context, in io.Reader) error {
+	var batch bytes.Buffer
+	var windowEnd time.Time
+	metadataLen := func() int {
+		return bytes.IndexByte(batch.Bytes(), '\n') + 1
+	}
+	flush := func(due time.Time) error {
+		if n := metadataLen(); batch.Len() > n {
+			if due.Before(windowEnd) {
+				return nil
+			}
+			if err := r.send(ctx, bytes.NewReader(batch.Bytes())); err != nil {
+				return err
+			}
+			batch.Truncate(n)
+		}
+		windowEnd = due.Add(r.window)
+		return nil
+	}
+	if err := r.writePayload(ctx, in, &batch, flush); err != nil {
+		return err
+	}
+	if batch.Len() > metadataLen() {
+		return r.send(ctx, bytes.NewReader(batch.Bytes()))
+	}
+	return nil
+}
+
+// writePayload copies the payload read from in to out, rewriting and pacing
+// events as configured. The first line holds the metadata, and is copied
+// unchanged. If flush is non-nil, it is called with the time a paced event
+// is due before waiting for it.
+func (r *replayer) writePayload(ctx context.Context, in io.Reader, out io.Writer, flush func(due time.Time) error) error {
+	var (
+		metadataWritten bool
+		start           time.Time
+		firstTimestamp  int64
+		haveTimestamp   bool
+	)
+	reader := bufio.NewReader(in)
+	for {
+		line, err := reader.ReadBytes('\n')
+		if err != nil && err != io.EOF {
+			return err
+		}
+		if line = bytes.TrimSpace(line); len(line) > 0 {
+			if metadataWritten && (r.rewriteTimestamps || r.speed > 0) {
+				event, fields, timestamp, ok, decodeErr := decodeEvent(line)
+				if decodeErr != nil {
+					return decodeErr
+				}
+				if ok {
+					if !haveTimestamp {
+						start, firstTimestamp, haveTimestamp = time.Now(), timestamp, true
+					}
+					offset := time.Duration(timestamp-firstTimestamp) * time.Microsecond
+					if r.speed > 0 {
+						due := start.Add(time.Duration(float64(offset) / r.speed))
+						if flush != nil {
+							if err := flush(due); err != nil {
+								return err
+							}
+						}
+						if err := sleepUntil(ctx, due); err != nil {
+							return err
+						}
+					}
+					if r.rewriteTimestamps {
+						fields["timestamp"] = micros(start.Add(offset))
+						if line, err = json.Marshal(event); err != nil {
+							return err
+						}
+					}
+				}
+			}
+			if _, err := out.Write(append(line, '\n')); err != nil {
+				return err
+			}
+			metadataWritten = true
+		}
+		if err == io.EOF {
+			return nil
+		}
+	}
+}
+
+func micros(t time.Time) int64 {
+	return t.UnixNano() / int64(time.Microsecond)
+}
+
+// decodeEvent decodes an event line, returning the event keyed by its type,
+// the event's fields, and its timestamp in microseconds since the epoch.
+// ok is false if the event has no timestamp.
+func decodeEvent(line []byte) (event map[string]map[string]interface{}, fields map[string]interface{}, timestamp int64, ok bool, err error) {
+	dec := json.NewDecoder(bytes.NewReader(line))
+	dec.UseNumber()
+	if err := dec.Decode(&event); err != nil {
+		return nil, nil, 0, false, errors.Wrap(err, "failed to decode event")
+	}
+	for _, fields = range event {
+		break
+	}
+	n, ok := fields["timestamp"].(json.Number)
+	if !ok {
+		return event, fields, 0, false, nil
+	}
+	if timestamp, err = n.Int64(); err != nil {
+		return nil, nil, 0, false, errors.Wrap(err, "invalid event timestamp")
+	}
+	return event, fields, timestamp, true, nil
+}
+
+func sleepUntil(ctx context.Context, t time.Time) error {
+	d := time.Until(t)
+	if d <= 0 {
+		return nil
+	}
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-timer.C:
+		return nil
+	}
+}
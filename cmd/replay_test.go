@@ -0,0 +1,141 @@
+// Licensed to Elasticsearch B.V. under one or more contributor
+// license agreements. See the NOTICE file distributed with
+// this work for additional information regarding copyright
+// ownership. Elasticsearch B.V. licenses this file to you under
+// the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package cmd
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/elastic/apm-server/beater/headers"
+)
+
+const replayPayload = `{"metadata": {"service": {"name": "svc", "agent": {"name": "go", "version": "1.0"}}}}
+{"transaction": {"id": "a", "timestamp": 1000000}}
+{"span": {"id": "b", "timestamp": 1100000}}
+{"error": {"id": "c"}}
+`
+
+func TestReplay(t *testing.T) {
+	var body []byte
+	var header http.Header
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ = ioutil.ReadAll(r.Body)
+		header = r.Header
+		w.WriteHeader(http.StatusAccepted)
+	}))
+	defer srv.Close()
+
+	r := replayer{intakeClient: intakeClient{client: srv.Client(), url: srv.URL, authorization: "Bearer abc"}}
+	require.NoError(t, r.replay(context.Background(), strings.NewReader(replayPayload)))
+	assert.Equal(t, replayPayload, string(body))
+	assert.Equal(t, "Bearer abc", header.Get(headers.Authorization))
+	assert.Equal(t, "application/x-ndjson", header.Get(headers.ContentType))
+}
+
+func TestReplayRewriteTimestamps(t *testing.T) {
+	var body []byte
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ = ioutil.ReadAll(r.Body)
+		w.WriteHeader(http.StatusAccepted)
+	}))
+	defer srv.Close()
+
+	before := time.Now()
+	r := replayer{intakeClient: intakeClient{client: srv.Client(), url: srv.URL}, rewriteTimestamps: true}
+	require.NoError(t, r.replay(context.Background(), strings.NewReader(replayPayload)))
+
+	lines := bytes.Split(bytes.TrimSpace(body), []byte("\n"))
+	require.Len(t, lines, 4)
+	timestamp := func(line []byte, eventType string) int64 {
+		var event map[string]map[string]interface{}
+		require.NoError(t, json.Unmarshal(line, &event))
+		return int64(event[eventType]["timestamp"].(float64))
+	}
+	first := timestamp(lines[1], "transaction")
+	assert.InDelta(t, before.UnixNano()/1000, first, float64(time.Minute/time.Microsecond))
+	assert.Equal(t, first+100000, timestamp(lines[2], "span"))
+	assert.JSONEq(t, `{"error": {"id": "c"}}`, string(lines[3]))
+}
+
+func TestReplaySpeed(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		ioutil.ReadAll(r.Body)
+		w.WriteHeader(http.StatusAccepted)
+	}))
+	defer srv.Close()
+
+	// the events are 100ms apart, replaying at 2x speed takes at least 50ms
+	start := time.Now()
+	r := replayer{intakeClient: intakeClient{client: srv.Client(), url: srv.URL}, speed: 2}
+	require.NoError(t, r.replay(context.Background(), strings.NewReader(replayPayload)))
+	assert.True(t, time.Since(start) >= 50*time.Millisecond)
+}
+
+func TestReplaySpeedWindows(t *testing.T) {
+	var bodies []string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := ioutil.ReadAll(r.Body)
+		bodies = append(bodies, string(body))
+		w.WriteHeader(http.StatusAccepted)
+	}))
+	defer srv.Close()
+
+	metadata := strings.SplitAfter(replayPayload, "\n")[0]
+	for name, test := range map[string]struct {
+		window time.Duration
+		bodies []string
+	}{
+		"SingleWindow": {window: time.Minute, bodies: []string{replayPayload}},
+		"WindowPerEvent": {window: 10 * time.Millisecond, bodies: []string{
+			metadata + `{"transaction": {"id": "a", "timestamp": 1000000}}` + "\n",
+			metadata + `{"span": {"id": "b", "timestamp": 1100000}}` + "\n" + `{"error": {"id": "c"}}` + "\n",
+		}},
+	} {
+		t.Run(name, func(t *testing.T) {
+			bodies = nil
+			r := replayer{intakeClient: intakeClient{client: srv.Client(), url: srv.URL}, speed: 2, window: test.window}
+			require.NoError(t, r.replay(context.Background(), strings.NewReader(replayPayload)))
+			assert.Equal(t, test.bodies, bodies)
+		})
+	}
+}
+
+func TestReplayErrorResponse(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		ioutil.ReadAll(r.Body)
+		w.WriteHeader(http.StatusBadRequest)
+		w.Write([]byte(`{"accepted": 0, "errors": [{"message": "invalid"}]}`))
+	}))
+	defer srv.Close()
+
+	r := replayer{intakeClient: intakeClient{client: srv.Client(), url: srv.URL}}
+	err := r.replay(context.Background(), strings.NewReader(replayPayload))
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "400 Bad Request")
+	assert.Contains(t, err.Error(), `"message": "invalid"`)
+}
@@ -0,0 +1,113 @@
+// Licensed to Elasticsearch B.V. under one or more contributor
+// license agreements. See the NOTICE file distributed with
+// this work for additional information regarding copyright
+// ownership. Elasticsearch B.V. licenses this file to you under
+// the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package cmd
+
+import (
+	"github.com/elastic/beats/v7/libbeat/cfgfile"
+	"github.com/elastic/beats/v7/libbeat/common"
+)
+
+const (
+	// queuedEventSize is the estimated memory held by an event
+	// in the publisher queue, for sizing the queue.
+	queuedEventSize = 8 * 1024
+
+	// queueMemoryShare is the inverse share of the memory limit
+	// to be held by events in the publisher queue.
+	queueMemoryShare = 8
+
+	minQueueEvents     = 512
+	defaultQueueEvents = 4 * 1024
+)
+
+// maxProcsOverride returns a ConditionalOverride which defaults `max_procs`
+// to the number of CPUs available to the process, when limited to fewer
+// CPUs than the host provides, e.g. by a container's cgroup CPU quota.
+func maxProcsOverride(cpus, numCPU int) cfgfile.ConditionalOverride {
+	override := common.NewConfig()
+	return cfgfile.ConditionalOverride{
+		Check: func(cfg *common.Config) bool {
+			if cpus >= numCPU || cfg.HasField("max_procs") {
+				return false
+			}
+			return override.SetInt("max_procs", -1, int64(cpus)) == nil
+		},
+		Config: override,
+	}
+}
+
+// maxMemoryOverride returns a ConditionalOverride which defaults
+// `apm-server.max_memory` to the memory limit of the process,
+// e.g. a container's cgroup memory limit.
+func maxMemoryOverride(memory int64) cfgfile.ConditionalOverride {
+	override := common.NewConfig()
+	return cfgfile.ConditionalOverride{
+		Check: func(cfg *common.Config) bool {
+			if memory <= 0 || configuredMaxMemory(cfg) > 0 {
+				return false
+			}
+			return override.SetInt("apm-server.max_memory", -1, memory) == nil
+		},
+		Config: override,
+	}
+}
+
+// queueOverride returns a ConditionalOverride which defaults the size of
+// the publisher's memory queue when no queue is configured, such that the
+// queued events are estimated to hold at most 1/8 of `apm-server.max_memory`,
+// or else of the memory limit of the process. The queue is never sized above
+// libbeat's default of 4096 events.
+func queueOverride(memory int64) cfgfile.ConditionalOverride {
+	override := common.NewConfig()
+	return cfgfile.ConditionalOverride{
+		Check: func(cfg *common.Config) bool {
+			if cfg.HasField("queue") {
+				return false
+			}
+			limit := memory
+			if maxMemory := configuredMaxMemory(cfg); maxMemory > 0 {
+				limit = maxMemory
+			}
+			if limit <= 0 {
+				return false
+			}
+			events := limit / queueMemoryShare / queuedEventSize
+			if events >= defaultQueueEvents {
+				return false
+			}
+			if events < minQueueEvents {
+				events = minQueueEvents
+			}
+			return override.SetInt("queue.mem.events", -1, events) == nil &&
+				override.SetInt("queue.mem.flush.min_events", -1, events/2) == nil
+		},
+		Config: override,
+	}
+}
+
+// configuredMaxMemory returns `apm-server.max_memory` in cfg,
+// or zero if it is not configured.
+func configuredMaxMemory(cfg *common.Config) int64 {
+	var config struct {
+		MaxMemory int64 `config:"apm-server.max_memory"`
+	}
+	if err := cfg.Unpack(&config); err != nil {
+		return 0
+	}
+	return config.MaxMemory
+}
@@ -0,0 +1,100 @@
+// Licensed to Elasticsearch B.V. under one or more contributor
+// license agreements. See the NOTICE file distributed with
+// this work for additional information regarding copyright
+// ownership. Elasticsearch B.V. licenses this file to you under
+// the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package cmd
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/elastic/beats/v7/libbeat/common"
+)
+
+func TestMaxProcsOverride(t *testing.T) {
+	override := maxProcsOverride(2, 8)
+	require.True(t, override.Check(common.MustNewConfigFrom(map[string]interface{}{})))
+	maxProcs, err := override.Config.Int("max_procs", -1)
+	require.NoError(t, err)
+	assert.Equal(t, int64(2), maxProcs)
+
+	assert.False(t, maxProcsOverride(2, 8).Check(common.MustNewConfigFrom(map[string]interface{}{"max_procs": 4})))
+	assert.False(t, maxProcsOverride(8, 8).Check(common.MustNewConfigFrom(map[string]interface{}{})))
+}
+
+func TestMaxMemoryOverride(t *testing.T) {
+	override := maxMemoryOverride(1 << 30)
+	require.True(t, override.Check(common.MustNewConfigFrom(map[string]interface{}{})))
+	maxMemory, err := override.Config.Int("apm-server.max_memory", -1)
+	require.NoError(t, err)
+	assert.Equal(t, int64(1<<30), maxMemory)
+
+	assert.False(t, maxMemoryOverride(1<<30).Check(common.MustNewConfigFrom(map[string]interface{}{
+		"apm-server.max_memory": 1 << 20,
+	})))
+	assert.False(t, maxMemoryOverride(0).Check(common.MustNewConfigFrom(map[string]interface{}{})))
+}
+
+func TestQueueOverride(t *testing.T) {
+	for name, test := range map[string]struct {
+		memory int64
+		config map[string]interface{}
+		events int
+		apply  bool
+	}{
+		"memory limit": {
+			memory: 128 << 20,
+			events: 2048, apply: true,
+		},
+		"small memory limit": {
+			memory: 16 << 20,
+			events: 512, apply: true,
+		},
+		"max_memory": {
+			memory: 1 << 30,
+			config: map[string]interface{}{"apm-server.max_memory": 64 << 20},
+			events: 1024, apply: true,
+		},
+		"large memory limit": {
+			memory: 1 << 30,
+		},
+		"no memory limit": {},
+		"explicit queue": {
+			memory: 128 << 20,
+			config: map[string]interface{}{"queue.mem.events": 8192},
+		},
+	} {
+		t.Run(name, func(t *testing.T) {
+			cfg := common.MustNewConfigFrom(map[string]interface{}{})
+			if test.config != nil {
+				cfg = common.MustNewConfigFrom(test.config)
+			}
+			override := queueOverride(test.memory)
+			require.Equal(t, test.apply, override.Check(cfg))
+			if !test.apply {
+				return
+			}
+			events, err := override.Config.Int("queue.mem.events", -1)
+			require.NoError(t, err)
+			assert.Equal(t, int64(test.events), events)
+			minEvents, err := override.Config.Int("queue.mem.flush.min_events", -1)
+			require.NoError(t, err)
+			assert.Equal(t, int64(test.events/2), minEvents)
+		})
+	}
+}
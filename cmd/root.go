@@ -19,7 +19,9 @@ package cmd
 
 import (
 	"fmt"
+	"runtime"
 
+	"github.com/spf13/cobra"
 	"github.com/spf13/pflag"
 
 	"github.com/elastic/beats/v7/libbeat/beat"
@@ -30,8 +32,13 @@ import (
 	"github.com/elastic/beats/v7/libbeat/monitoring/report"
 	"github.com/elastic/beats/v7/libbeat/publisher/processing"
 
+	// Import processors. The script processor's interpreter has no memory
+	// limit, and there are no WebAssembly or CEL runtimes; see apm-server.yml.
+	_ "github.com/elastic/beats/v7/libbeat/processors/script"
+
 	"github.com/elastic/apm-server/idxmgmt"
 	_ "github.com/elastic/apm-server/include" // include assets
+	"github.com/elastic/apm-server/resources"
 )
 
 const (
@@ -39,6 +46,11 @@ const (
 	apmIndexPattern = "apm"
 )
 
+// optionalCommands holds the constructors of commands which are only built
+// with a build tag, such as "bench", keeping test dependencies out of the
+// production binary.
+var optionalCommands []func() *cobra.Command
+
 var libbeatConfigOverrides = common.MustNewConfigFrom(map[string]interface{}{
 	"logging": map[string]interface{}{
 		"metrics": map[string]interface{}{
@@ -68,6 +80,27 @@ var libbeatConfigOverrides = common.MustNewConfigFrom(map[string]interface{}{
 	},
 })
 
+// defaultConfigOverrides returns the conditional overrides of libbeat's
+// default settings, which are applied beneath the user's configuration.
+// Settings depending on the host's resources are derived from the given
+// limits, which take into account the cgroup limits of containers.
+func defaultConfigOverrides(limits resources.Limits) []cfgfile.ConditionalOverride {
+	libbeatOverride := cfgfile.ConditionalOverride{
+		Check: func(_ *common.Config) bool {
+			return true
+		},
+		Config: libbeatConfigOverrides,
+	}
+	return []cfgfile.ConditionalOverride{
+		libbeatOverride,
+		esWorkersOverride(limits.CPUs),
+		esServiceTokenOverride(),
+		maxProcsOverride(limits.CPUs, runtime.NumCPU()),
+		maxMemoryOverride(limits.Memory),
+		queueOverride(limits.Memory),
+	}
+}
+
 // NewRootCommand returns the "apm-server" root command.
 func NewRootCommand(newBeat beat.Creator) *cmd.BeatsRootCmd {
 	var runFlags = pflag.NewFlagSet(beatName, pflag.ExitOnError)
@@ -81,16 +114,19 @@ func NewRootCommand(newBeat beat.Creator) *cmd.BeatsRootCmd {
 		},
 		IndexManagement: idxmgmt.MakeDefaultSupporter,
 		Processing:      processing.MakeDefaultObserverSupport(false),
-		ConfigOverrides: []cfgfile.ConditionalOverride{{
-			Check: func(_ *common.Config) bool {
-				return true
-			},
-			Config: libbeatConfigOverrides,
-		}},
+		ConfigOverrides: defaultConfigOverrides(resources.Detect()),
 	}
 
 	rootCmd := cmd.GenRootCmdWithSettings(newBeat, settings)
 	rootCmd.AddCommand(genApikeyCmd(settings))
+	rootCmd.AddCommand(genReplayCmd())
+	rootCmd.AddCommand(genMigrateCmd(settings))
+	for _, genCmd := range optionalCommands {
+		rootCmd.AddCommand(genCmd())
+	}
+	rootCmd.ExportCmd.AddCommand(genExportSchemaCmd())
+	rootCmd.ExportCmd.AddCommand(genExportOpenAPICmd(settings))
+	rootCmd.TestCmd.AddCommand(genTestIntakeCmd(settings))
 	modifyBuiltinCommands(rootCmd, settings)
 	return rootCmd
 }
@@ -106,6 +142,13 @@ func modifyBuiltinCommands(rootCmd *cmd.BeatsRootCmd, settings instance.Settings
 			cmd.ResetFlags()
 			cmd.Flags().String("es.version", settings.Version, "Elasticsearch version")
 			cmd.Flags().String("dir", "", "Specify directory for printing template files. By default templates are printed to stdout.")
+			addEventTemplateFlag(cmd, settings)
+		}
+	}
+
+	for _, cmd := range rootCmd.TestCmd.Commands() {
+		if cmd.Name() == "config" {
+			addStrictConfigFlag(cmd, settings)
 		}
 	}
 
@@ -116,6 +159,9 @@ func modifyBuiltinCommands(rootCmd *cmd.BeatsRootCmd, settings instance.Settings
 
  * Index management including loading Elasticsearch templates, ILM policies and write aliases.
  * Ingest pipelines
+
+Use --pipelines or --ilm-policy to only set up the respective components.
+Existing ingest pipelines and ILM policies are compared with their definitions and the differences are logged.
 `
 	setup.ResetFlags()
 
@@ -124,5 +170,7 @@ func modifyBuiltinCommands(rootCmd *cmd.BeatsRootCmd, settings instance.Settings
 	setup.Flags().Bool(tmplKey, false, "Setup index template")
 	setup.Flags().MarkDeprecated(tmplKey, fmt.Sprintf("please use --%s instead", cmd.IndexManagementKey))
 	setup.Flags().Bool(cmd.IndexManagementKey, false, "Setup Elasticsearch index management")
-	setup.Flags().Bool(cmd.PipelineKey, false, "Setup ingest pipelines")
+	setup.Flags().Bool(cmd.PipelineKey, false, "Setup ingest pipelines, updating those differing from their definition")
+	//lint:ignore SA1019 Setting up ILM policies on their own is supported by APM Server.
+	setup.Flags().Bool(cmd.ILMPolicyKey, false, "Setup ILM policies")
 }
@@ -0,0 +1,62 @@
+// Licensed to Elasticsearch B.V. under one or more contributor
+// license agreements. See the NOTICE file distributed with
+// this work for additional information regarding copyright
+// ownership. Elasticsearch B.V. licenses this file to you under
+// the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package cmd
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/elastic/beats/v7/libbeat/beat"
+	"github.com/elastic/beats/v7/libbeat/common"
+	"github.com/elastic/beats/v7/libbeat/processors"
+)
+
+func TestScriptProcessor(t *testing.T) {
+	cfg := common.MustNewConfigFrom(map[string]interface{}{
+		"script": map[string]interface{}{
+			"lang":    "javascript",
+			"timeout": "1s",
+			"source": `function process(event) {
+				event.Delete("http.request.headers.Cookie");
+				event.Put("labels.team", "checkout");
+			}`,
+		},
+	})
+	procs, err := processors.New(processors.PluginConfig{cfg})
+	require.NoError(t, err)
+
+	event, err := procs.Run(&beat.Event{
+		Timestamp: time.Now(),
+		Fields: common.MapStr{
+			"http": common.MapStr{"request": common.MapStr{"headers": common.MapStr{
+				"Cookie":     []string{"secret"},
+				"User-Agent": []string{"curl"},
+			}}},
+		},
+	})
+	require.NoError(t, err)
+	assert.Equal(t, common.MapStr{
+		"http": common.MapStr{"request": common.MapStr{"headers": common.MapStr{
+			"User-Agent": []string{"curl"},
+		}}},
+		"labels": common.MapStr{"team": "checkout"},
+	}, event.Fields)
+}
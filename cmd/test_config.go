@@ -0,0 +1,105 @@
+// Licensed to Elasticsearch B.V. under one or more contributor
+// license agreements. See the NOTICE file distributed with
+// this work for additional information regarding copyright
+// ownership. Elasticsearch B.V. licenses this file to you under
+// the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package cmd
+
+import (
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/pkg/errors"
+	"github.com/spf13/cobra"
+	"gopkg.in/yaml.v2"
+
+	"github.com/elastic/beats/v7/libbeat/cmd/instance"
+	"github.com/elastic/beats/v7/libbeat/common"
+
+	"github.com/elastic/apm-server/beater/config"
+)
+
+// addStrictConfigFlag extends the `test config` command with a `--strict` flag,
+// failing on unsupported `apm-server` settings and printing the effective configuration.
+func addStrictConfigFlag(configCmd *cobra.Command, settings instance.Settings) {
+	configCmd.Flags().Bool("strict", false,
+		"Fail on unknown or unsupported apm-server settings, and print the effective apm-server configuration")
+	testConfig := configCmd.Run
+	configCmd.Run = func(c *cobra.Command, args []string) {
+		if strict, _ := c.Flags().GetBool("strict"); !strict {
+			testConfig(c, args)
+			return
+		}
+		if err := testConfigStrict(settings, os.Stdout); err != nil {
+			fmt.Fprintln(os.Stderr, err)
+			os.Exit(1)
+		}
+	}
+}
+
+func testConfigStrict(settings instance.Settings, w io.Writer) error {
+	rawCfg, cfg, err := bootstrapConfig(settings)
+	if err != nil {
+		return err
+	}
+	problems, err := config.UnknownSettings(rawCfg)
+	if err != nil {
+		return err
+	}
+	if len(problems) > 0 {
+		for _, problem := range problems {
+			fmt.Fprintln(w, problem)
+		}
+		return errors.Errorf("Config contains %d unsupported settings", len(problems))
+	}
+
+	effective, err := effectiveConfig(rawCfg, cfg)
+	if err != nil {
+		return err
+	}
+	out, err := yaml.Marshal(map[string]interface{}{"apm-server": effective})
+	if err != nil {
+		return err
+	}
+	if _, err := w.Write(out); err != nil {
+		return err
+	}
+	fmt.Fprintln(w, "Config OK")
+	return nil
+}
+
+// effectiveConfig returns the resolved configuration cfg with secrets redacted,
+// along with the ILM settings from the raw configuration, which are resolved
+// by index management.
+func effectiveConfig(rawCfg *common.Config, cfg *config.Config) (map[string]interface{}, error) {
+	effective, err := cfg.Effective()
+	if err != nil {
+		return nil, err
+	}
+	if rawCfg.HasField("ilm") {
+		ilmCfg, err := rawCfg.Child("ilm", -1)
+		if err != nil {
+			return nil, err
+		}
+		var ilm map[string]interface{}
+		if err := ilmCfg.Unpack(&ilm); err != nil {
+			return nil, err
+		}
+		config.Redact(ilm)
+		effective["ilm"] = ilm
+	}
+	return effective, nil
+}
@@ -0,0 +1,63 @@
+// Licensed to Elasticsearch B.V. under one or more contributor
+// license agreements. See the NOTICE file distributed with
+// this work for additional information regarding copyright
+// ownership. Elasticsearch B.V. licenses this file to you under
+// the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package cmd
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/elastic/beats/v7/libbeat/common"
+
+	"github.com/elastic/apm-server/beater/config"
+)
+
+func TestEffectiveConfig(t *testing.T) {
+	rawCfg := common.MustNewConfigFrom(map[string]interface{}{
+		"secret_token":       "abc",
+		"ilm.enabled":        false,
+		"api_key.enabled":    true,
+		"kibana.password":    "secret",
+		"rum.allow_origins":  []string{"*"},
+		"rum.source_mapping": map[string]interface{}{"elasticsearch.password": ""},
+	})
+	cfg, err := config.NewConfig("8.0.0", rawCfg, nil)
+	require.NoError(t, err)
+
+	effective, err := effectiveConfig(rawCfg, cfg)
+	require.NoError(t, err)
+	m := common.MapStr(effective)
+
+	for key, expected := range map[string]interface{}{
+		"secret_token":    "xxxxx",
+		"kibana.password": "xxxxx",
+		"api_key.enabled": true,
+		"ilm.enabled":     false,
+		"mode":            "production",
+		"max_event_size":  uint64(300 * 1024),
+		"rum.source_mapping.elasticsearch.password": "",
+	} {
+		v, err := m.GetValue(key)
+		require.NoError(t, err, key)
+		assert.Equal(t, expected, v, key)
+	}
+	jaeger, err := m.GetValue("jaeger.grpc")
+	require.NoError(t, err)
+	assert.NotContains(t, jaeger, "-")
+}
@@ -0,0 +1,61 @@
+// Licensed to Elasticsearch B.V. under one or more contributor
+// license agreements. See the NOTICE file distributed with
+// this work for additional information regarding copyright
+// ownership. Elasticsearch B.V. licenses this file to you under
+// the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package containerinfer
+
+import (
+	"bufio"
+	"io"
+	"regexp"
+	"strings"
+)
+
+var (
+	// containerIDPattern matches container IDs in cgroup paths, e.g.
+	// "/docker/<id>" or "/kubepods.slice/.../cri-containerd-<id>.scope".
+	containerIDPattern = regexp.MustCompile(`(?:^|[/-])([0-9a-f]{64})(?:\.scope)?$`)
+
+	// podUIDPattern matches Kubernetes pod UIDs in cgroup paths, e.g.
+	// "/kubepods/burstable/pod<uid>/" or "kubepods-burstable-pod<uid>.slice",
+	// where the systemd cgroup driver replaces dashes with underscores.
+	podUIDPattern = regexp.MustCompile(`pod([0-9a-f]{8}[-_][0-9a-f]{4}[-_][0-9a-f]{4}[-_][0-9a-f]{4}[-_][0-9a-f]{12})`)
+)
+
+// parseCgroups parses the contents of /proc/<pid>/cgroup, returning the
+// container identified by the cgroup paths, if any.
+//
+// Each line has the format "hierarchy-ID:controller-list:cgroup-path".
+func parseCgroups(r io.Reader) (Container, bool) {
+	scanner := bufio.NewScanner(r)
+	for scanner.Scan() {
+		fields := strings.SplitN(scanner.Text(), ":", 3)
+		if len(fields) != 3 {
+			continue
+		}
+		path := fields[2]
+		match := containerIDPattern.FindStringSubmatch(path)
+		if match == nil {
+			continue
+		}
+		container := Container{ID: match[1]}
+		if match := podUIDPattern.FindStringSubmatch(path); match != nil {
+			container.PodUID = strings.Replace(match[1], "_", "-", -1)
+		}
+		return container, true
+	}
+	return Container{}, false
+}
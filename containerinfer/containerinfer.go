@@ -0,0 +1,173 @@
+// Licensed to Elasticsearch B.V. under one or more contributor
+// license agreements. See the NOTICE file distributed with
+// this work for additional information regarding copyright
+// ownership. Elasticsearch B.V. licenses this file to you under
+// the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+// Package containerinfer infers the container of agents which do not send
+// container metadata, from the connection over which events are received.
+//
+// The container is inferred by finding the process on the other end of the
+// connection, and reading its control groups. The peer process is found
+// using peer credentials for Unix domain sockets, and by searching the
+// sockets of all processes for TCP connections originating on the local
+// host or within a configured container network (CNI) range. The server
+// must be able to read the host's procfs for the latter.
+package containerinfer
+
+import (
+	"context"
+	"net"
+	"os"
+	"path/filepath"
+	"strconv"
+	"sync"
+	"syscall"
+
+	"github.com/elastic/beats/v7/libbeat/logp"
+
+	logs "github.com/elastic/apm-server/log"
+)
+
+const defaultProcPath = "/proc"
+
+// Container holds inferred container metadata.
+type Container struct {
+	// ID holds the container ID.
+	ID string
+
+	// PodUID holds the Kubernetes pod UID, if the container
+	// is running in a Kubernetes pod.
+	PodUID string
+}
+
+// Config holds configuration for New.
+type Config struct {
+	// CNIRanges holds the network ranges of container networks.
+	// TCP connections from these ranges, or from loopback
+	// addresses, are considered for inference.
+	CNIRanges []*net.IPNet
+
+	// ProcPath holds the path of the host's procfs.
+	// If empty, "/proc" is used.
+	ProcPath string
+}
+
+// Inferrer infers the containers of connection peers.
+type Inferrer struct {
+	cniRanges []*net.IPNet
+	procPath  string
+	logger    *logp.Logger
+}
+
+// New returns a new Inferrer with the given config.
+func New(cfg Config) *Inferrer {
+	procPath := cfg.ProcPath
+	if procPath == "" {
+		procPath = defaultProcPath
+	}
+	return &Inferrer{
+		cniRanges: cfg.CNIRanges,
+		procPath:  procPath,
+		logger:    logp.NewLogger(logs.ContainerInference),
+	}
+}
+
+type connContextKey struct{}
+
+// connInference holds the lazily inferred container of a connection's peer.
+type connInference struct {
+	inferrer  *Inferrer
+	conn      net.Conn
+	once      sync.Once
+	container Container
+	ok        bool
+}
+
+// ConnContext returns a copy of ctx carrying conn, so the container of
+// its peer can later be inferred with FromContext. ConnContext has the
+// signature of http.Server.ConnContext.
+func (i *Inferrer) ConnContext(ctx context.Context, conn net.Conn) context.Context {
+	return context.WithValue(ctx, connContextKey{}, &connInference{inferrer: i, conn: conn})
+}
+
+// FromContext returns the inferred container of the peer of the connection
+// carried by ctx, if any. Inference is performed at most once per connection.
+func FromContext(ctx context.Context) (Container, bool) {
+	ci, _ := ctx.Value(connContextKey{}).(*connInference)
+	if ci == nil {
+		return Container{}, false
+	}
+	ci.once.Do(func() {
+		ci.container, ci.ok = ci.inferrer.Infer(ci.conn)
+	})
+	return ci.container, ci.ok
+}
+
+// Infer infers the container of the peer of conn.
+func (i *Inferrer) Infer(conn net.Conn) (Container, bool) {
+	pid, ok := i.peerPID(conn)
+	if !ok {
+		return Container{}, false
+	}
+	f, err := os.Open(filepath.Join(i.procPath, strconv.Itoa(pid), "cgroup"))
+	if err != nil {
+		i.logger.Debugf("failed to read cgroups of process %d: %s", pid, err)
+		return Container{}, false
+	}
+	defer f.Close()
+	return parseCgroups(f)
+}
+
+func (i *Inferrer) peerPID(conn net.Conn) (int, bool) {
+	switch remote := conn.RemoteAddr().(type) {
+	case *net.UnixAddr:
+		// Peer credentials require access to the socket,
+		// which is not possible if the connection is wrapped.
+		sc, ok := conn.(syscall.Conn)
+		if !ok {
+			return 0, false
+		}
+		pid, err := unixPeerPID(sc)
+		if err != nil {
+			i.logger.Debugf("failed to get peer credentials: %s", err)
+			return 0, false
+		}
+		return pid, true
+	case *net.TCPAddr:
+		local, _ := conn.LocalAddr().(*net.TCPAddr)
+		if local == nil || !i.considerTCPPeer(remote.IP) {
+			return 0, false
+		}
+		pid, err := findSocketPID(i.procPath, remote, local)
+		if err != nil {
+			i.logger.Debugf("failed to find process for %s: %s", remote, err)
+			return 0, false
+		}
+		return pid, true
+	}
+	return 0, false
+}
+
+func (i *Inferrer) considerTCPPeer(ip net.IP) bool {
+	if ip.IsLoopback() {
+		return true
+	}
+	for _, cidr := range i.cniRanges {
+		if cidr.Contains(ip) {
+			return true
+		}
+	}
+	return false
+}
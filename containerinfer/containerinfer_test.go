@@ -0,0 +1,186 @@
+// Licensed to Elasticsearch B.V. under one or more contributor
+// license agreements. See the NOTICE file distributed with
+// this work for additional information regarding copyright
+// ownership. Elasticsearch B.V. licenses this file to you under
+// the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package containerinfer
+
+import (
+	"context"
+	"io/ioutil"
+	"net"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const testContainerID = "2e1dfaac3c0b5d9fd5bd9ebbea6a0e43fdf32c2f7e3b5bda50d8e1e16bcd3e54"
+
+func TestParseCgroups(t *testing.T) {
+	for name, test := range map[string]struct {
+		input  string
+		expect Container
+		ok     bool
+	}{
+		"docker": {
+			input:  "12:devices:/docker/" + testContainerID + "\n",
+			expect: Container{ID: testContainerID},
+			ok:     true,
+		},
+		"kubepods cgroupfs": {
+			input:  "11:cpu,cpuacct:/kubepods/burstable/pod6b2b0b7c-6a3d-4c5e-9f37-3e1f0a2c8d41/" + testContainerID,
+			expect: Container{ID: testContainerID, PodUID: "6b2b0b7c-6a3d-4c5e-9f37-3e1f0a2c8d41"},
+			ok:     true,
+		},
+		"kubepods systemd": {
+			input: "0::/kubepods.slice/kubepods-burstable.slice/" +
+				"kubepods-burstable-pod6b2b0b7c_6a3d_4c5e_9f37_3e1f0a2c8d41.slice/" +
+				"cri-containerd-" + testContainerID + ".scope",
+			expect: Container{ID: testContainerID, PodUID: "6b2b0b7c-6a3d-4c5e-9f37-3e1f0a2c8d41"},
+			ok:     true,
+		},
+		"not a container": {
+			input: "12:devices:/user.slice\n0::/user.slice/user-1000.slice/session-2.scope\n",
+		},
+	} {
+		t.Run(name, func(t *testing.T) {
+			container, ok := parseCgroups(strings.NewReader(test.input))
+			assert.Equal(t, test.ok, ok)
+			assert.Equal(t, test.expect, container)
+		})
+	}
+}
+
+func TestProcNetAddrs(t *testing.T) {
+	assert.Equal(t, []string{
+		"0100007F:1F90",
+		"0000000000000000FFFF00000100007F:1F90",
+	}, procNetAddrs(&net.TCPAddr{IP: net.ParseIP("127.0.0.1"), Port: 8080}))
+	assert.Equal(t, []string{
+		"00000000000000000000000001000000:0050",
+	}, procNetAddrs(&net.TCPAddr{IP: net.ParseIP("::1"), Port: 80}))
+}
+
+func TestFindSocketPID(t *testing.T) {
+	procPath, err := ioutil.TempDir("", "procfs")
+	require.NoError(t, err)
+	defer os.RemoveAll(procPath)
+
+	// Process 10 is the server, in the host network namespace.
+	// Processes 20 and 21 are in a container's network namespace;
+	// 21 owns the client socket.
+	writeProcess(t, procPath, 10, "net:[1]", "socket:[100]", "")
+	writeProcess(t, procPath, 20, "net:[2]", "pipe:[5]", strings.Join([]string{
+		"  sl  local_address rem_address   st tx_queue rx_queue tr tm->when retrnsmt   uid  timeout inode",
+		"   0: 0100007F:0035 00000000:0000 0A 00000000:00000000 00:00000000 00000000     0        0 300",
+		"   1: 0500F40A:D431 0100F40A:1F90 01 00000000:00000000 00:00000000 00000000     0        0 200",
+	}, "\n"))
+	writeProcess(t, procPath, 21, "net:[2]", "socket:[200]", "")
+
+	local := &net.TCPAddr{IP: net.ParseIP("10.244.0.5"), Port: 54321}
+	remote := &net.TCPAddr{IP: net.ParseIP("10.244.0.1"), Port: 8080}
+	pid, err := findSocketPID(procPath, local, remote)
+	require.NoError(t, err)
+	assert.Equal(t, 21, pid)
+
+	_, err = findSocketPID(procPath, &net.TCPAddr{IP: net.ParseIP("10.244.0.6"), Port: 1}, remote)
+	assert.EqualError(t, err, "socket not found")
+}
+
+func TestInferTCPLoopback(t *testing.T) {
+	if runtime.GOOS != "linux" {
+		t.Skip("procfs is only available on Linux")
+	}
+	lis, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	defer lis.Close()
+
+	client, err := net.Dial("tcp", lis.Addr().String())
+	require.NoError(t, err)
+	defer client.Close()
+	server, err := lis.Accept()
+	require.NoError(t, err)
+	defer server.Close()
+
+	inferrer := New(Config{})
+	pid, ok := inferrer.peerPID(server)
+	require.True(t, ok)
+	assert.Equal(t, os.Getpid(), pid)
+}
+
+func TestInferUnixSocket(t *testing.T) {
+	if runtime.GOOS != "linux" {
+		t.Skip("peer credentials are only supported on Linux")
+	}
+	dir, err := ioutil.TempDir("", "containerinfer")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+
+	lis, err := net.Listen("unix", filepath.Join(dir, "apm.sock"))
+	require.NoError(t, err)
+	defer lis.Close()
+
+	client, err := net.Dial("unix", lis.Addr().String())
+	require.NoError(t, err)
+	defer client.Close()
+	server, err := lis.Accept()
+	require.NoError(t, err)
+	defer server.Close()
+
+	inferrer := New(Config{})
+	pid, ok := inferrer.peerPID(server)
+	require.True(t, ok)
+	assert.Equal(t, os.Getpid(), pid)
+}
+
+func TestFromContext(t *testing.T) {
+	_, ok := FromContext(context.Background())
+	assert.False(t, ok)
+
+	// Connections from outside of the CNI ranges are not considered.
+	inferrer := New(Config{CNIRanges: []*net.IPNet{{IP: net.IPv4(10, 244, 0, 0), Mask: net.CIDRMask(16, 32)}}})
+	ctx := inferrer.ConnContext(context.Background(), fakeConn{
+		local:  &net.TCPAddr{IP: net.ParseIP("10.0.0.1"), Port: 8200},
+		remote: &net.TCPAddr{IP: net.ParseIP("192.168.0.1"), Port: 1234},
+	})
+	_, ok = FromContext(ctx)
+	assert.False(t, ok)
+}
+
+type fakeConn struct {
+	net.Conn
+	local, remote net.Addr
+}
+
+func (c fakeConn) LocalAddr() net.Addr  { return c.local }
+func (c fakeConn) RemoteAddr() net.Addr { return c.remote }
+
+func writeProcess(t *testing.T, procPath string, pid int, netns, fd, tcp string) {
+	dir := filepath.Join(procPath, strconv.Itoa(pid))
+	require.NoError(t, os.MkdirAll(filepath.Join(dir, "ns"), 0755))
+	require.NoError(t, os.MkdirAll(filepath.Join(dir, "fd"), 0755))
+	require.NoError(t, os.MkdirAll(filepath.Join(dir, "net"), 0755))
+	require.NoError(t, os.Symlink(netns, filepath.Join(dir, "ns", "net")))
+	require.NoError(t, os.Symlink(fd, filepath.Join(dir, "fd", "3")))
+	if tcp != "" {
+		require.NoError(t, ioutil.WriteFile(filepath.Join(dir, "net", "tcp"), []byte(tcp), 0644))
+	}
+}
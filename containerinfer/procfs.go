@@ -0,0 +1,167 @@
+// Licensed to Elasticsearch B.V. under one or more contributor
+// license agreements. See the NOTICE file distributed with
+// this work for additional information regarding copyright
+// ownership. Elasticsearch B.V. licenses this file to you under
+// the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package containerinfer
+
+import (
+	"bufio"
+	"encoding/binary"
+	"encoding/hex"
+	"fmt"
+	"io/ioutil"
+	"net"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"github.com/pkg/errors"
+
+	"github.com/elastic/apm-server/utility"
+)
+
+// findSocketPID returns the ID of a process owning the TCP socket with the
+// given local and remote addresses, searching the network namespaces of all
+// processes in procPath.
+func findSocketPID(procPath string, local, remote *net.TCPAddr) (int, error) {
+	entries, err := ioutil.ReadDir(procPath)
+	if err != nil {
+		return 0, err
+	}
+
+	// Group processes by network namespace, so the sockets
+	// of each namespace are only read once.
+	var namespaces []string
+	namespacePIDs := make(map[string][]int)
+	for _, entry := range entries {
+		pid, err := strconv.Atoi(entry.Name())
+		if err != nil {
+			continue
+		}
+		ns, err := os.Readlink(filepath.Join(procPath, entry.Name(), "ns", "net"))
+		if err != nil {
+			continue
+		}
+		if _, ok := namespacePIDs[ns]; !ok {
+			namespaces = append(namespaces, ns)
+		}
+		namespacePIDs[ns] = append(namespacePIDs[ns], pid)
+	}
+
+	localAddrs := procNetAddrs(local)
+	remoteAddrs := procNetAddrs(remote)
+	for _, ns := range namespaces {
+		pids := namespacePIDs[ns]
+		pidDir := filepath.Join(procPath, strconv.Itoa(pids[0]))
+		inode, err := findSocketInode(pidDir, localAddrs, remoteAddrs)
+		if err != nil {
+			return 0, err
+		}
+		if inode == "" {
+			continue
+		}
+		link := "socket:[" + inode + "]"
+		for _, pid := range pids {
+			if processHasFD(filepath.Join(procPath, strconv.Itoa(pid)), link) {
+				return pid, nil
+			}
+		}
+		return 0, fmt.Errorf("no process found owning socket inode %s", inode)
+	}
+	return 0, errors.New("socket not found")
+}
+
+// findSocketInode returns the inode of the socket with any of the given
+// local and remote addresses, in the network namespace of the process
+// with the given procfs directory. If no socket is found, inode is empty.
+func findSocketInode(pidDir string, localAddrs, remoteAddrs []string) (inode string, _ error) {
+	for _, name := range []string{"tcp", "tcp6"} {
+		f, err := os.Open(filepath.Join(pidDir, "net", name))
+		if err != nil {
+			if os.IsNotExist(err) {
+				continue
+			}
+			return "", err
+		}
+		inode := scanProcNetTCP(bufio.NewScanner(f), localAddrs, remoteAddrs)
+		f.Close()
+		if inode != "" {
+			return inode, nil
+		}
+	}
+	return "", nil
+}
+
+// scanProcNetTCP scans the contents of /proc/<pid>/net/tcp{,6}, returning
+// the inode of the socket with any of the given local and remote addresses.
+//
+// Each line after the header holds the slot number, the local and remote
+// addresses, and further socket state; the inode is the tenth field.
+func scanProcNetTCP(scanner *bufio.Scanner, localAddrs, remoteAddrs []string) string {
+	scanner.Scan() // skip header
+	for scanner.Scan() {
+		fields := strings.Fields(scanner.Text())
+		if len(fields) < 10 {
+			continue
+		}
+		if utility.Contains(fields[1], localAddrs) && utility.Contains(fields[2], remoteAddrs) {
+			return fields[9]
+		}
+	}
+	return ""
+}
+
+// processHasFD reports whether the process with the given procfs directory
+// has a file descriptor with the given link target.
+func processHasFD(pidDir, target string) bool {
+	fdDir := filepath.Join(pidDir, "fd")
+	fds, err := ioutil.ReadDir(fdDir)
+	if err != nil {
+		return false
+	}
+	for _, fd := range fds {
+		if link, err := os.Readlink(filepath.Join(fdDir, fd.Name())); err == nil && link == target {
+			return true
+		}
+	}
+	return false
+}
+
+// procNetAddrs returns the possible encodings of addr in /proc/net/tcp{,6}.
+// IPv4 addresses may also appear as IPv4-mapped IPv6 addresses.
+func procNetAddrs(addr *net.TCPAddr) []string {
+	port := fmt.Sprintf("%04X", addr.Port)
+	var addrs []string
+	if ip4 := addr.IP.To4(); ip4 != nil {
+		addrs = append(addrs, procNetIP(ip4)+":"+port)
+	}
+	if ip16 := addr.IP.To16(); ip16 != nil {
+		addrs = append(addrs, procNetIP(ip16)+":"+port)
+	}
+	return addrs
+}
+
+// procNetIP encodes ip as in /proc/net/tcp{,6}: as hex-encoded 32-bit
+// words, each in host byte order, which is little-endian on all platforms
+// supported by the inference.
+func procNetIP(ip net.IP) string {
+	buf := make([]byte, len(ip))
+	for i := 0; i+4 <= len(ip); i += 4 {
+		binary.LittleEndian.PutUint32(buf[i:], binary.BigEndian.Uint32(ip[i:]))
+	}
+	return strings.ToUpper(hex.EncodeToString(buf))
+}
@@ -0,0 +1,58 @@
+// Licensed to Elasticsearch B.V. under one or more contributor
+// license agreements. See the NOTICE file distributed with
+// this work for additional information regarding copyright
+// ownership. Elasticsearch B.V. licenses this file to you under
+// the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package decoder
+
+import (
+	"bytes"
+	"unicode/utf8"
+
+	jsoniter "github.com/json-iterator/go"
+)
+
+var fastJSON = jsoniter.Config{
+	EscapeHTML:             true,
+	SortMapKeys:            true,
+	ValidateJsonRawMessage: true,
+	UseNumber:              true,
+}.Froze()
+
+// DecodeJSONBytes decodes the first JSON value in data into a map,
+// like DecodeJSONData. Any data following the value is ignored.
+func DecodeJSONBytes(data []byte) (map[string]interface{}, error) {
+	return DecodeJSONData(bytes.NewReader(data))
+}
+
+// DecodeJSONBytesFast is equivalent to DecodeJSONBytes, but decodes
+// using jsoniter rather than encoding/json.
+//
+// Input which jsoniter might treat differently to encoding/json, i.e.
+// invalid JSON or invalid UTF-8, is decoded with DecodeJSONBytes so
+// that results and error messages are identical.
+func DecodeJSONBytesFast(data []byte) (map[string]interface{}, error) {
+	if !jsoniter.Valid(data) || !utf8.Valid(data) {
+		return DecodeJSONBytes(data)
+	}
+	iter := fastJSON.BorrowIterator(data)
+	defer fastJSON.ReturnIterator(iter)
+	v := make(map[string]interface{})
+	iter.ReadVal(&v)
+	if iter.Error != nil {
+		return DecodeJSONBytes(data)
+	}
+	return v, nil
+}
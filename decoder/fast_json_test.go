@@ -0,0 +1,125 @@
+// Licensed to Elasticsearch B.V. under one or more contributor
+// license agreements. See the NOTICE file distributed with
+// this work for additional information regarding copyright
+// ownership. Elasticsearch B.V. licenses this file to you under
+// the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package decoder
+
+import (
+	"bufio"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// TestDecodeJSONBytesFastConformance checks that DecodeJSONBytesFast produces
+// the same maps and errors as DecodeJSONBytes. Schema validation operates on
+// the decoded maps, so identical maps imply identical validation results.
+func TestDecodeJSONBytesFastConformance(t *testing.T) {
+	inputs := []string{
+		``,
+		` `,
+		`{}`,
+		`null`,
+		`[]`,
+		`"string"`,
+		`123`,
+		`{"a": 1}{"b": 2}`,
+		`{"a": 1} trailing`,
+		`{"a": 1`,
+		`{"a": }`,
+		`{'a': 1}`,
+		`{"a": 1,}`,
+		`{"a": 01}`,
+		`{"a": 1.}`,
+		`{"a": -}`,
+		`{"a": 1e}`,
+		`{"a": 1.2.3}`,
+		`{"a": --1}`,
+		`{"a": NaN}`,
+		`{"a": 1, "a": 2}`,
+		`{"a": 1.0, "b": -0, "c": 1E+2, "d": 123456789012345678901234567890}`,
+		`{"a": true, "b": false, "c": null}`,
+		`{"a": {"b": [1, "2", {"c": null}]}}`,
+		`{"a": "é😀"}`,
+		`{"a": "\ud800"}`,
+		`{"a": "\udc00\ud800"}`,
+		`{"a": "\/\b\f\n\r\t\"\\"}`,
+		`{"a": "\x"}`,
+		"{\"a\": \"\xff\"}",
+		"{\"a\": \"\x01\"}",
+		"{\"a\": \"<>& \"}",
+		"\t{\"a\": 1}\r\n",
+	}
+	for _, input := range inputs {
+		assertDecodeJSONBytesFastConformance(t, []byte(input))
+	}
+
+	// Every line of the intake test data must also decode identically.
+	files, err := filepath.Glob(filepath.Join("..", "testdata", "intake-v*", "*.ndjson"))
+	require.NoError(t, err)
+	require.NotEmpty(t, files)
+	for _, file := range files {
+		f, err := os.Open(file)
+		require.NoError(t, err)
+		scanner := bufio.NewScanner(f)
+		scanner.Buffer(nil, 10*1024*1024)
+		for scanner.Scan() {
+			assertDecodeJSONBytesFastConformance(t, scanner.Bytes())
+		}
+		require.NoError(t, scanner.Err())
+		f.Close()
+	}
+}
+
+func assertDecodeJSONBytesFastConformance(t *testing.T, input []byte) {
+	expected, expectedErr := DecodeJSONBytes(input)
+	actual, actualErr := DecodeJSONBytesFast(input)
+	assert.Equal(t, expected, actual, "%q", input)
+	assert.Equal(t, expectedErr, actualErr, "%q", input)
+}
+
+func BenchmarkDecodeJSONBytes(b *testing.B) {
+	benchmarkDecodeJSONBytes(b, DecodeJSONBytes)
+}
+
+func BenchmarkDecodeJSONBytesFast(b *testing.B) {
+	benchmarkDecodeJSONBytes(b, DecodeJSONBytesFast)
+}
+
+func benchmarkDecodeJSONBytes(b *testing.B, decode func([]byte) (map[string]interface{}, error)) {
+	f, err := os.Open(filepath.Join("..", "testdata", "intake-v2", "events.ndjson"))
+	require.NoError(b, err)
+	defer f.Close()
+	var lines [][]byte
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		lines = append(lines, append([]byte(nil), scanner.Bytes()...))
+	}
+	require.NoError(b, scanner.Err())
+
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		for _, line := range lines {
+			if _, err := decode(line); err != nil {
+				b.Fatal(err)
+			}
+		}
+	}
+}
@@ -23,7 +23,9 @@ import (
 	"encoding/json"
 	"io"
 	"net/http"
+	"sync"
 
+	"github.com/klauspost/compress/zstd"
 	"github.com/pkg/errors"
 
 	"github.com/elastic/beats/v7/libbeat/monitoring"
@@ -38,12 +40,39 @@ var (
 	gzipCounter                   = monitoring.NewInt(decoderMetrics, "gzip.count")
 	uncompressedLengthAccumulator = monitoring.NewInt(decoderMetrics, "uncompressed.content-length")
 	uncompressedCounter           = monitoring.NewInt(decoderMetrics, "uncompressed.count")
+	zstdLengthAccumulator         = monitoring.NewInt(decoderMetrics, "zstd.content-length")
+	zstdCounter                   = monitoring.NewInt(decoderMetrics, "zstd.count")
 	readerCounter                 = monitoring.NewInt(decoderMetrics, "reader.count")
+	tooLargeCounter               = monitoring.NewInt(decoderMetrics, "decompressed-too-large.count")
+)
+
+// zstdMaxWindowSize is the maximum window size of zstd frames, limiting the
+// memory allocated for decompressing a request body. zstd compression levels
+// up to 19 use windows of at most 8 MiB.
+const zstdMaxWindowSize = 8 * 1024 * 1024
+
+// ErrDecompressedTooLarge is returned when reading more than the permitted
+// number of bytes from a reader returned by CompressedRequestReader.
+var ErrDecompressedTooLarge = errors.New("decompressed request body exceeded the permitted size")
+
+// ContentEncodings holds the Content-Encodings supported by CompressedRequestReader,
+// in addition to uncompressed request bodies.
+var ContentEncodings = []string{"deflate", "gzip", "zstd"}
+
+var (
+	gzipReaderPool sync.Pool
+	zlibReaderPool sync.Pool
 )
 
 // CompressedRequestReader returns a reader that will decompress
-// the body according to the supplied Content-Encoding header in the request
-func CompressedRequestReader(req *http.Request) (io.ReadCloser, error) {
+// the body according to the supplied Content-Encoding header in the request.
+// If maxSize is positive, the returned reader returns ErrDecompressedTooLarge
+// once more than maxSize bytes of the decompressed body have been read,
+// without decompressing the remainder of the body.
+//
+// Decompressing readers are pooled across requests; the returned reader
+// must be closed once the body has been read, and must not be used after.
+func CompressedRequestReader(req *http.Request, maxSize int64) (io.ReadCloser, error) {
 	reader := req.Body
 	if reader == nil {
 		return nil, errors.New("no content")
@@ -61,7 +90,7 @@ func CompressedRequestReader(req *http.Request) (io.ReadCloser, error) {
 			deflateCounter.Inc()
 		}
 		var err error
-		reader, err = zlib.NewReader(reader)
+		reader, err = newZlibReader(reader)
 		if err != nil {
 			return nil, err
 		}
@@ -72,7 +101,18 @@ func CompressedRequestReader(req *http.Request) (io.ReadCloser, error) {
 			gzipCounter.Inc()
 		}
 		var err error
-		reader, err = gzip.NewReader(reader)
+		reader, err = newGzipReader(reader)
+		if err != nil {
+			return nil, err
+		}
+
+	case "zstd":
+		if knownCLen {
+			zstdLengthAccumulator.Add(cLen)
+			zstdCounter.Inc()
+		}
+		var err error
+		reader, err = newZstdReader(reader)
 		if err != nil {
 			return nil, err
 		}
@@ -83,7 +123,114 @@ func CompressedRequestReader(req *http.Request) (io.ReadCloser, error) {
 		}
 	}
 	readerCounter.Inc()
-	return reader, nil
+	return LimitDecompressedSize(reader, maxSize), nil
+}
+
+// LimitDecompressedSize returns a reader reading from the decompressing
+// reader r, which returns ErrDecompressedTooLarge once more than maxSize
+// bytes have been read. If maxSize is not positive, r is returned.
+func LimitDecompressedSize(r io.ReadCloser, maxSize int64) io.ReadCloser {
+	if maxSize <= 0 {
+		return r
+	}
+	return &sizeLimitedReader{ReadCloser: r, remaining: maxSize}
+}
+
+func newGzipReader(r io.Reader) (io.ReadCloser, error) {
+	if zr, ok := gzipReaderPool.Get().(*gzip.Reader); ok {
+		if err := zr.Reset(r); err != nil {
+			gzipReaderPool.Put(zr)
+			return nil, err
+		}
+		return &pooledReader{ReadCloser: zr, pool: &gzipReaderPool}, nil
+	}
+	zr, err := gzip.NewReader(r)
+	if err != nil {
+		return nil, err
+	}
+	return &pooledReader{ReadCloser: zr, pool: &gzipReaderPool}, nil
+}
+
+func newZlibReader(r io.Reader) (io.ReadCloser, error) {
+	if zr, ok := zlibReaderPool.Get().(io.ReadCloser); ok {
+		if err := zr.(zlib.Resetter).Reset(r, nil); err != nil {
+			zlibReaderPool.Put(zr)
+			return nil, err
+		}
+		return &pooledReader{ReadCloser: zr, pool: &zlibReaderPool}, nil
+	}
+	zr, err := zlib.NewReader(r)
+	if err != nil {
+		return nil, err
+	}
+	return &pooledReader{ReadCloser: zr, pool: &zlibReaderPool}, nil
+}
+
+// newZstdReader returns a zstd decompressing reader. zstd decoders cannot be
+// reset without a new input, so they are not pooled, but closed with the reader.
+func newZstdReader(r io.Reader) (io.ReadCloser, error) {
+	zr, err := zstd.NewReader(r,
+		zstd.WithDecoderConcurrency(1),
+		zstd.WithDecoderLowmem(true),
+		zstd.WithDecoderMaxMemory(zstdMaxWindowSize),
+	)
+	if err != nil {
+		return nil, err
+	}
+	return zstdReader{zr}, nil
+}
+
+type zstdReader struct {
+	*zstd.Decoder
+}
+
+func (r zstdReader) Close() error {
+	r.Decoder.Close()
+	return nil
+}
+
+// sizeLimitedReader wraps a decompressing reader, accounting for the number
+// of decompressed bytes read, and failing once more than permitted are read.
+type sizeLimitedReader struct {
+	io.ReadCloser
+	remaining int64
+	err       error
+}
+
+func (r *sizeLimitedReader) Read(p []byte) (int, error) {
+	if r.err != nil {
+		return 0, r.err
+	}
+	if int64(len(p)) > r.remaining+1 {
+		p = p[:r.remaining+1]
+	}
+	n, err := r.ReadCloser.Read(p)
+	if int64(n) <= r.remaining {
+		r.remaining -= int64(n)
+		return n, err
+	}
+	n = int(r.remaining)
+	r.remaining = 0
+	r.err = ErrDecompressedTooLarge
+	tooLargeCounter.Inc()
+	return n, r.err
+}
+
+// pooledReader wraps a decompressing reader, returning it to its
+// pool when closed.
+type pooledReader struct {
+	io.ReadCloser
+	pool *sync.Pool
+}
+
+func (r *pooledReader) Close() error {
+	if r.ReadCloser == nil {
+		return nil
+	}
+	err := r.ReadCloser.Close()
+	r.pool.Put(r.ReadCloser)
+	r.ReadCloser = nil
+	return err
 }
 
 func DecodeJSONData(reader io.Reader) (map[string]interface{}, error) {
@@ -18,11 +18,20 @@
 package decoder_test
 
 import (
+	"bytes"
+	"compress/gzip"
+	"compress/zlib"
 	"encoding/json"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
 	"strings"
 	"testing"
 
+	"github.com/klauspost/compress/zstd"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 
 	"github.com/elastic/apm-server/decoder"
 )
@@ -38,3 +47,89 @@ func TestDecodeJSONData(t *testing.T) {
 		"number": json.Number("123"),
 	}, decoded)
 }
+
+func TestCompressedRequestReaderPooled(t *testing.T) {
+	compress := map[string]func(w io.Writer) io.WriteCloser{
+		"gzip":    func(w io.Writer) io.WriteCloser { return gzip.NewWriter(w) },
+		"deflate": func(w io.Writer) io.WriteCloser { return zlib.NewWriter(w) },
+		"zstd":    newZstdWriter,
+	}
+	for encoding, newWriter := range compress {
+		t.Run(encoding, func(t *testing.T) {
+			// Readers are returned to the pool on Close, so the
+			// second request may reuse the first request's reader.
+			for _, body := range []string{"first request", "second request"} {
+				var buf bytes.Buffer
+				w := newWriter(&buf)
+				_, err := w.Write([]byte(body))
+				require.NoError(t, err)
+				require.NoError(t, w.Close())
+
+				req := httptest.NewRequest(http.MethodPost, "/", &buf)
+				req.Header.Set("Content-Encoding", encoding)
+				reader, err := decoder.CompressedRequestReader(req, 0)
+				require.NoError(t, err)
+				out, err := ioutil.ReadAll(reader)
+				require.NoError(t, err)
+				assert.Equal(t, body, string(out))
+				assert.NoError(t, reader.Close())
+				assert.NoError(t, reader.Close())
+			}
+		})
+	}
+}
+
+func TestCompressedRequestReaderInvalid(t *testing.T) {
+	req := httptest.NewRequest(http.MethodPost, "/", strings.NewReader("not gzip"))
+	req.Header.Set("Content-Encoding", "gzip")
+	_, err := decoder.CompressedRequestReader(req, 0)
+	assert.Error(t, err)
+}
+
+func TestCompressedRequestReaderMaxSize(t *testing.T) {
+	const maxSize = 1024 * 1024
+	compress := map[string]func(w io.Writer) io.WriteCloser{
+		"gzip":    func(w io.Writer) io.WriteCloser { return gzip.NewWriter(w) },
+		"deflate": func(w io.Writer) io.WriteCloser { return zlib.NewWriter(w) },
+		"zstd":    newZstdWriter,
+		"":        func(w io.Writer) io.WriteCloser { return nopWriteCloser{w} },
+	}
+	for encoding, newWriter := range compress {
+		t.Run(encoding, func(t *testing.T) {
+			for name, size := range map[string]int{"within": maxSize, "exceeding": 16 * maxSize} {
+				var buf bytes.Buffer
+				w := newWriter(&buf)
+				_, err := w.Write(make([]byte, size))
+				require.NoError(t, err)
+				require.NoError(t, w.Close())
+
+				req := httptest.NewRequest(http.MethodPost, "/", &buf)
+				req.Header.Set("Content-Encoding", encoding)
+				reader, err := decoder.CompressedRequestReader(req, maxSize)
+				require.NoError(t, err)
+				out, err := ioutil.ReadAll(reader)
+				if name == "within" {
+					require.NoError(t, err)
+				} else {
+					assert.Equal(t, decoder.ErrDecompressedTooLarge, err)
+				}
+				assert.Len(t, out, maxSize)
+				assert.NoError(t, reader.Close())
+			}
+		})
+	}
+}
+
+func newZstdWriter(w io.Writer) io.WriteCloser {
+	zw, err := zstd.NewWriter(w)
+	if err != nil {
+		panic(err)
+	}
+	return zw
+}
+
+type nopWriteCloser struct {
+	io.Writer
+}
+
+func (nopWriteCloser) Close() error { return nil }
@@ -59,19 +59,33 @@ func (sr *NDJSONStreamReader) resetDecoder() {
 }
 
 func (sr *NDJSONStreamReader) Read() (map[string]interface{}, error) {
-	buf, readErr := sr.readLine()
+	buf, readErr := sr.ReadLine()
 	if len(buf) == 0 || (readErr != nil && !sr.isEOF) {
 		return nil, readErr
 	}
+	decoded, err := sr.DecodeLatestLine()
+	if err != nil {
+		return nil, err
+	}
+	return decoded, readErr // this might be io.EOF
+}
+
+// DecodeLatestLine decodes the line most recently read by ReadLine.
+func (sr *NDJSONStreamReader) DecodeLatestLine() (map[string]interface{}, error) {
 	decoded := make(map[string]interface{})
 	if err := sr.decoder.Decode(&decoded); err != nil {
 		sr.resetDecoder() // clear out decoding state
 		return nil, JSONDecodeError("data read error: " + err.Error())
 	}
-	return decoded, readErr // this might be io.EOF
+	return decoded, nil
 }
 
-func (sr *NDJSONStreamReader) readLine() ([]byte, error) {
+// ReadLine reads the next line without decoding it, for callers which
+// decode lines themselves. The returned slice is only valid until the
+// next call to ReadLine or Read.
+//
+// ReadLine can return a line along with io.EOF.
+func (sr *NDJSONStreamReader) ReadLine() ([]byte, error) {
 	// readLine can return valid data in `buf` _and_ also an io.EOF
 	line, readErr := sr.lineReader.ReadLine()
 	sr.latestLine = line
@@ -19,6 +19,7 @@ package decoder
 
 import (
 	"bytes"
+	"io"
 	"strings"
 	"testing"
 
@@ -80,3 +81,21 @@ func TestNDStreamReader(t *testing.T) {
 		}
 	}
 }
+
+func TestNDStreamReaderReadLine(t *testing.T) {
+	buf := bytes.NewBufferString("{\"key\": \"value1\"}\n{\"key\": \"value2\"}")
+	n := NewNDJSONStreamReader(buf, 100)
+
+	line, err := n.ReadLine()
+	require.NoError(t, err)
+	assert.Equal(t, `{"key": "value1"}`, string(line))
+
+	// Lines read by ReadLine may be decoded later with DecodeLatestLine.
+	line, err = n.ReadLine()
+	assert.Equal(t, io.EOF, err)
+	assert.True(t, n.IsEOF())
+	assert.Equal(t, `{"key": "value2"}`, string(line))
+	out, err := n.DecodeLatestLine()
+	require.NoError(t, err)
+	assert.Equal(t, map[string]interface{}{"key": "value2"}, out)
+}
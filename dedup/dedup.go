@@ -0,0 +1,211 @@
+// Licensed to Elasticsearch B.V. under one or more contributor
+// license agreements. See the NOTICE file distributed with
+// this work for additional information regarding copyright
+// ownership. Elasticsearch B.V. licenses this file to you under
+// the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+// Package dedup provides a reporter which drops events that have
+// already been reported recently, such as those resent by agents
+// retrying requests whose response was lost.
+package dedup
+
+import (
+	"container/list"
+	"context"
+	"sync"
+	"time"
+
+	"github.com/elastic/beats/v7/libbeat/monitoring"
+
+	"github.com/elastic/apm-server/model"
+	"github.com/elastic/apm-server/publish"
+	"github.com/elastic/apm-server/transform"
+)
+
+// entryOverhead is the approximate number of bytes used for each entry,
+// in addition to its key: the map entry, list element and expiry time.
+const entryOverhead = 128
+
+var (
+	monitoringRegistry = monitoring.Default.NewRegistry("apm-server.dedup")
+	droppedCounter     = monitoring.NewInt(monitoringRegistry, "events_dropped")
+	evictedCounter     = monitoring.NewInt(monitoringRegistry, "evicted")
+	entriesGauge       = monitoring.NewInt(monitoringRegistry, "entries")
+)
+
+// Config holds configuration for NewDeduplicator.
+type Config struct {
+	// TTL is how long event IDs are remembered after being reported.
+	TTL time.Duration
+
+	// MaxBytes is the approximate memory budget for remembered event IDs.
+	// When it is exceeded, the oldest IDs are forgotten first.
+	MaxBytes int64
+}
+
+// Deduplicator remembers the IDs of reported transactions, spans and errors
+// for a limited time, dropping events with IDs that have already been seen.
+//
+// Metricsets and profiles have no IDs, and are never dropped.
+type Deduplicator struct {
+	cfg Config
+	now func() time.Time
+
+	mu      sync.Mutex
+	bytes   int64
+	entries map[string]*list.Element
+	order   *list.List // *entry, oldest first
+}
+
+type entry struct {
+	key     string
+	expires time.Time
+}
+
+// NewDeduplicator returns a new Deduplicator with the given config.
+func NewDeduplicator(cfg Config) *Deduplicator {
+	return &Deduplicator{
+		cfg:     cfg,
+		now:     time.Now,
+		entries: make(map[string]*list.Element),
+		order:   list.New(),
+	}
+}
+
+// Wrap returns a Reporter which drops events already reported within the
+// configured TTL, and reports the remaining events to reporter. Event IDs
+// are remembered only once reporter returns successfully, so that events
+// which failed to be reported may be retried.
+//
+// The returned publish.Reporter does not guarantee order preservation of
+// reported events.
+func (d *Deduplicator) Wrap(reporter publish.Reporter) publish.Reporter {
+	return func(ctx context.Context, req publish.PendingReq) error {
+		events := req.Transformables
+		if len(events) == 0 {
+			return reporter(ctx, req)
+		}
+		eventSize := req.Size / int64(len(events))
+
+		var dropped int64
+		d.mu.Lock()
+		d.expire()
+		for i := 0; i < len(events); {
+			if key := eventKey(events[i]); key == "" || !d.seen(key) {
+				i++
+				continue
+			}
+			dropped++
+			n := len(events)
+			events[i], events[n-1] = events[n-1], events[i]
+			events = events[:n-1]
+		}
+		d.mu.Unlock()
+
+		droppedCounter.Add(dropped)
+		if len(events) == 0 {
+			return nil
+		}
+		req.Transformables = events
+		req.Size = eventSize * int64(len(events))
+		if err := reporter(ctx, req); err != nil {
+			return err
+		}
+
+		d.mu.Lock()
+		defer d.mu.Unlock()
+		expires := d.now().Add(d.cfg.TTL)
+		for _, event := range events {
+			if key := eventKey(event); key != "" {
+				d.add(key, expires)
+			}
+		}
+		d.evict()
+		entriesGauge.Set(int64(len(d.entries)))
+		return nil
+	}
+}
+
+// seen reports whether an unexpired entry exists for key.
+// The caller must hold d.mu.
+func (d *Deduplicator) seen(key string) bool {
+	_, ok := d.entries[key]
+	return ok
+}
+
+// add remembers key until expires. The caller must hold d.mu.
+func (d *Deduplicator) add(key string, expires time.Time) {
+	if elem, ok := d.entries[key]; ok {
+		// The same event was reported concurrently; keep the
+		// list ordered by expiry by moving it to the back.
+		elem.Value.(*entry).expires = expires
+		d.order.MoveToBack(elem)
+		return
+	}
+	d.entries[key] = d.order.PushBack(&entry{key: key, expires: expires})
+	d.bytes += entrySize(key)
+}
+
+// expire removes expired entries. The caller must hold d.mu.
+func (d *Deduplicator) expire() {
+	now := d.now()
+	for elem := d.order.Front(); elem != nil; elem = d.order.Front() {
+		if elem.Value.(*entry).expires.After(now) {
+			break
+		}
+		d.remove(elem)
+	}
+}
+
+// evict removes the oldest entries until the memory budget
+// is no longer exceeded. The caller must hold d.mu.
+func (d *Deduplicator) evict() {
+	for d.bytes > d.cfg.MaxBytes && d.order.Len() > 0 {
+		d.remove(d.order.Front())
+		evictedCounter.Inc()
+	}
+}
+
+// remove removes the entry for elem. The caller must hold d.mu.
+func (d *Deduplicator) remove(elem *list.Element) {
+	e := d.order.Remove(elem).(*entry)
+	delete(d.entries, e.key)
+	d.bytes -= entrySize(e.key)
+}
+
+func entrySize(key string) int64 {
+	return int64(len(key)) + entryOverhead
+}
+
+// eventKey returns the key identifying event, or the empty string if
+// event cannot be identified. IDs are only unique within a trace, and
+// within an event type. In multi-tenant mode, IDs are scoped by tenant,
+// so that tenants cannot drop each other's events by reusing their IDs.
+func eventKey(event transform.Transformable) string {
+	switch event := event.(type) {
+	case *model.Transaction:
+		if event.ID != "" {
+			return event.Metadata.TenantID + "/transaction:" + event.TraceID + ":" + event.ID
+		}
+	case *model.Span:
+		if event.ID != "" {
+			return event.Metadata.TenantID + "/span:" + event.TraceID + ":" + event.ID
+		}
+	case *model.Error:
+		if event.ID != nil && *event.ID != "" {
+			return event.Metadata.TenantID + "/error:" + event.TraceID + ":" + *event.ID
+		}
+	}
+	return ""
+}
@@ -0,0 +1,132 @@
+// Licensed to Elasticsearch B.V. under one or more contributor
+// license agreements. See the NOTICE file distributed with
+// this work for additional information regarding copyright
+// ownership. Elasticsearch B.V. licenses this file to you under
+// the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package dedup
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/elastic/beats/v7/libbeat/monitoring"
+
+	"github.com/elastic/apm-server/model"
+	"github.com/elastic/apm-server/publish"
+	"github.com/elastic/apm-server/transform"
+)
+
+func TestDeduplicator(t *testing.T) {
+	d := NewDeduplicator(Config{TTL: time.Minute, MaxBytes: 1024 * 1024})
+	now := time.Now()
+	d.now = func() time.Time { return now }
+
+	var reported []transform.Transformable
+	reporter := d.Wrap(func(ctx context.Context, req publish.PendingReq) error {
+		reported = append(reported, req.Transformables...)
+		return nil
+	})
+	report := func(events ...transform.Transformable) error {
+		return reporter(context.Background(), publish.PendingReq{Transformables: events})
+	}
+	before := snapshot()
+
+	errorID := "1"
+	events := []transform.Transformable{
+		&model.Transaction{TraceID: "trace", ID: "1"},
+		&model.Span{TraceID: "trace", ID: "1"},
+		&model.Error{TraceID: "trace", ID: &errorID},
+		&model.Metricset{},
+	}
+	assert.NoError(t, report(events...))
+	assert.Len(t, reported, 4)
+
+	// A retry of the same events only reports metricsets, which have no IDs.
+	reported = nil
+	assert.NoError(t, report(events...))
+	assert.Equal(t, []transform.Transformable{&model.Metricset{}}, reported)
+
+	// The same ID in another trace is not a duplicate.
+	reported = nil
+	assert.NoError(t, report(&model.Transaction{TraceID: "other_trace", ID: "1"}))
+	assert.Len(t, reported, 1)
+
+	// The same ID sent by another tenant is not a duplicate.
+	reported = nil
+	assert.NoError(t, report(&model.Transaction{Metadata: model.Metadata{TenantID: "team-a"}, TraceID: "trace", ID: "1"}))
+	assert.Len(t, reported, 1)
+
+	// Once expired, IDs are forgotten.
+	now = now.Add(time.Minute)
+	reported = nil
+	assert.NoError(t, report(events...))
+	assert.Len(t, reported, 4)
+
+	after := snapshot()
+	assert.Equal(t, int64(3), after["events_dropped"]-before["events_dropped"])
+}
+
+func TestDeduplicatorReportError(t *testing.T) {
+	d := NewDeduplicator(Config{TTL: time.Minute, MaxBytes: 1024 * 1024})
+	reportErr := errors.New("boom")
+	var reported int
+	reporter := d.Wrap(func(ctx context.Context, req publish.PendingReq) error {
+		reported += len(req.Transformables)
+		return reportErr
+	})
+
+	// Events are only remembered once reported successfully,
+	// so that they may be retried.
+	req := publish.PendingReq{Transformables: []transform.Transformable{
+		&model.Transaction{TraceID: "trace", ID: "1"},
+	}}
+	assert.Equal(t, reportErr, reporter(context.Background(), req))
+	reportErr = nil
+	assert.NoError(t, reporter(context.Background(), req))
+	assert.NoError(t, reporter(context.Background(), req))
+	assert.Equal(t, 2, reported)
+}
+
+func TestDeduplicatorMaxBytes(t *testing.T) {
+	key := eventKey(&model.Transaction{TraceID: "trace", ID: "1"})
+	d := NewDeduplicator(Config{TTL: time.Minute, MaxBytes: 2 * entrySize(key)})
+	var reported int
+	reporter := d.Wrap(func(ctx context.Context, req publish.PendingReq) error {
+		reported += len(req.Transformables)
+		return nil
+	})
+	report := func(id string) {
+		err := reporter(context.Background(), publish.PendingReq{Transformables: []transform.Transformable{
+			&model.Transaction{TraceID: "trace", ID: id},
+		}})
+		assert.NoError(t, err)
+	}
+
+	report("1")
+	report("2")
+	report("3") // evicts "1"
+	assert.Len(t, d.entries, 2)
+	report("1") // evicts "2"
+	report("3")
+	assert.Equal(t, 4, reported)
+}
+
+func snapshot() map[string]int64 {
+	return monitoring.CollectFlatSnapshot(monitoringRegistry, monitoring.Full, false).Ints
+}
@@ -0,0 +1,57 @@
+// Licensed to Elasticsearch B.V. under one or more contributor
+// license agreements. See the NOTICE file distributed with
+// this work for additional information regarding copyright
+// ownership. Elasticsearch B.V. licenses this file to you under
+// the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package elasticsearch
+
+import (
+	"context"
+
+	"github.com/elastic/go-elasticsearch/v7/esapi"
+)
+
+// ClusterHealth requires monitor cluster privilege
+func ClusterHealth(ctx context.Context, client Client) (ClusterHealthResponse, error) {
+	var health ClusterHealthResponse
+	err := doRequest(ctx, client, esapi.ClusterHealthRequest{}, &health)
+	return health, err
+}
+
+// NodesBreakers requires monitor cluster privilege
+func NodesBreakers(ctx context.Context, client Client) (NodesBreakersResponse, error) {
+	var stats NodesBreakersResponse
+	err := doRequest(ctx, client, esapi.NodesStatsRequest{Metric: []string{"breaker"}}, &stats)
+	return stats, err
+}
+
+type ClusterHealthResponse struct {
+	Status string `json:"status"`
+}
+
+type NodesBreakersResponse struct {
+	Nodes map[string]NodeBreakers `json:"nodes"`
+}
+
+type NodeBreakers struct {
+	Name     string             `json:"name"`
+	Breakers map[string]Breaker `json:"breakers"`
+}
+
+type Breaker struct {
+	LimitSizeInBytes     int64 `json:"limit_size_in_bytes"`
+	EstimatedSizeInBytes int64 `json:"estimated_size_in_bytes"`
+	Tripped              int64 `json:"tripped"`
+}
@@ -37,9 +37,10 @@ const (
 )
 
 var (
-	errInvalidHosts     = errors.New("`Hosts` must at least contain one hostname")
-	errConfigMissing    = errors.New("config missing")
-	esConnectionTimeout = 5 * time.Second
+	errInvalidHosts           = errors.New("`Hosts` must at least contain one hostname")
+	errConfigMissing          = errors.New("config missing")
+	errConflictingCredentials = errors.New("only one of username/password, api_key, and service_token may be set")
+	esConnectionTimeout       = 5 * time.Second
 )
 
 // Config holds all configurable fields that are used to create a Client
@@ -54,6 +55,21 @@ type Config struct {
 	Username     string            `config:"username"`
 	Password     string            `config:"password"`
 	APIKey       string            `config:"api_key"`
+	ServiceToken string            `config:"service_token"`
+}
+
+// Validate ensures at most one means of authentication is configured.
+func (c *Config) Validate() error {
+	var n int
+	for _, set := range []bool{c.Username != "" || c.Password != "", c.APIKey != "", c.ServiceToken != ""} {
+		if set {
+			n++
+		}
+	}
+	if n > 1 {
+		return errConflictingCredentials
+	}
+	return nil
 }
 
 // DefaultConfig returns a default config.
@@ -81,6 +97,9 @@ func connectionConfig(config *Config) (http.RoundTripper, []string, error) {
 	if err != nil {
 		return nil, nil, err
 	}
+	if config.ServiceToken != "" {
+		return &bearerTokenRoundTripper{token: config.ServiceToken, next: transp}, addrs, nil
+	}
 	return transp, addrs, nil
 }
 
@@ -140,3 +159,17 @@ func httpTransport(cfg *Config) (*http.Transport, error) {
 		TLSClientConfig: tlsConfig.ToConfig(),
 	}, nil
 }
+
+// bearerTokenRoundTripper authenticates requests with a bearer token,
+// such as an Elasticsearch service account token.
+type bearerTokenRoundTripper struct {
+	token string
+	next  http.RoundTripper
+}
+
+func (rt *bearerTokenRoundTripper) RoundTrip(req *http.Request) (*http.Response, error) {
+	// RoundTrippers must not modify the given request.
+	req = req.Clone(req.Context())
+	req.Header.Set("Authorization", "Bearer "+rt.token)
+	return rt.next.RoundTrip(req)
+}
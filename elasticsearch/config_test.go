@@ -97,6 +97,43 @@ func TestAddresses(t *testing.T) {
 	})
 }
 
+func TestConfigValidate(t *testing.T) {
+	for _, cfg := range []Config{
+		{},
+		{Username: "elastic", Password: "changeme"},
+		{APIKey: "id:key"},
+		{ServiceToken: "token"},
+	} {
+		assert.NoError(t, cfg.Validate())
+	}
+	for _, cfg := range []Config{
+		{Username: "elastic", APIKey: "id:key"},
+		{Password: "changeme", ServiceToken: "token"},
+		{APIKey: "id:key", ServiceToken: "token"},
+	} {
+		assert.EqualError(t, cfg.Validate(), "only one of username/password, api_key, and service_token may be set")
+	}
+}
+
+func TestServiceToken(t *testing.T) {
+	var authorization string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		authorization = r.Header.Get("Authorization")
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte("{}"))
+	}))
+	defer srv.Close()
+
+	client, err := NewClient(&Config{Hosts: []string{srv.URL}, ServiceToken: "s3cr3t"})
+	require.NoError(t, err)
+	req, err := http.NewRequest(http.MethodGet, "/", nil)
+	require.NoError(t, err)
+	resp, err := client.Perform(req)
+	require.NoError(t, err)
+	resp.Body.Close()
+	assert.Equal(t, "Bearer s3cr3t", authorization)
+}
+
 // TestBeatsConfigSynced helps ensure that our elasticsearch.Config struct is
 // kept in sync with the config defined in libbeat/outputs/elasticsearch.
 func TestBeatsConfigSynced(t *testing.T) {
@@ -134,8 +171,10 @@ func TestBeatsConfigSynced(t *testing.T) {
 	libbeatStructFields := getStructFields(libbeatType)
 	localStructFields := getStructFields(localType)
 
-	// "hosts" is only expected in the local struct
+	// "hosts" is only expected in the local struct, and "service_token"
+	// is translated into an Authorization header for the libbeat output.
 	delete(localStructFields, "hosts")
+	delete(localStructFields, "service_token")
 
 	// We expect the libbeat struct to be a superset of all other
 	// fields defined in the local struct, with identical tags and
@@ -0,0 +1,88 @@
+// Licensed to Elasticsearch B.V. under one or more contributor
+// license agreements. See the NOTICE file distributed with
+// this work for additional information regarding copyright
+// ownership. Elasticsearch B.V. licenses this file to you under
+// the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package elasticsearch
+
+import (
+	"context"
+
+	"github.com/elastic/go-elasticsearch/v7/esapi"
+	"github.com/elastic/go-elasticsearch/v7/esutil"
+)
+
+// GetAliases requires view_index_metadata index privilege
+func GetAliases(ctx context.Context, client Client, index string) (map[string]IndexAliases, error) {
+	var aliases map[string]IndexAliases
+	err := doRequest(ctx, client, esapi.IndicesGetAliasRequest{Index: []string{index}}, &aliases)
+	return aliases, err
+}
+
+// UpdateAliases requires manage index privilege
+func UpdateAliases(ctx context.Context, client Client, aliasesReq UpdateAliasesRequest) error {
+	req := esapi.IndicesUpdateAliasesRequest{Body: esutil.NewJSONReader(aliasesReq)}
+	return doRequest(ctx, client, req, nil)
+}
+
+// StartReindex requires read index privilege on the source and write index privilege on the destination.
+// The reindex request is run as task, the returned response holds the task id.
+func StartReindex(ctx context.Context, client Client, reindexReq ReindexRequest) (ReindexResponse, error) {
+	var task ReindexResponse
+	waitForCompletion := false
+	req := esapi.ReindexRequest{Body: esutil.NewJSONReader(reindexReq), WaitForCompletion: &waitForCompletion}
+	err := doRequest(ctx, client, req, &task)
+	return task, err
+}
+
+type IndexAliases struct {
+	Aliases map[string]Alias `json:"aliases"`
+}
+
+type Alias struct {
+	IsWriteIndex *bool `json:"is_write_index,omitempty"`
+}
+
+type UpdateAliasesRequest struct {
+	Actions []AliasAction `json:"actions"`
+}
+
+type AliasAction struct {
+	Add *AddAlias `json:"add,omitempty"`
+}
+
+type AddAlias struct {
+	Index        string `json:"index"`
+	Alias        string `json:"alias"`
+	IsWriteIndex bool   `json:"is_write_index"`
+}
+
+type ReindexRequest struct {
+	Source ReindexIndex `json:"source"`
+	Dest   ReindexDest  `json:"dest"`
+}
+
+type ReindexIndex struct {
+	Index string `json:"index"`
+}
+
+type ReindexDest struct {
+	Index  string `json:"index"`
+	OpType string `json:"op_type,omitempty"`
+}
+
+type ReindexResponse struct {
+	Task string `json:"task"`
+}
@@ -87,12 +87,16 @@ type GetAPIKeyResponse struct {
 }
 
 type HasPrivilegesRequest struct {
+	Cluster []string          `json:"cluster,omitempty"`
+	Index   []IndexPrivileges `json:"index,omitempty"`
 	// can't reuse the `Applications` type because here the JSON attribute must be singular
-	Applications []Application `json:"application"`
+	Applications []Application `json:"application,omitempty"`
 }
 type HasPrivilegesResponse struct {
 	Username    string                             `json:"username"`
 	HasAll      bool                               `json:"has_all_requested"`
+	Cluster     map[string]bool                    `json:"cluster"`
+	Index       map[string]map[string]bool         `json:"index"`
 	Application map[AppName]PermissionsPerResource `json:"application"`
 }
 
@@ -108,7 +112,14 @@ type InvalidateAPIKeyResponse struct {
 type RoleDescriptor map[AppName]Applications
 
 type Applications struct {
-	Applications []Application `json:"applications"`
+	Cluster      []string          `json:"cluster,omitempty"`
+	Index        []IndexPrivileges `json:"index,omitempty"`
+	Applications []Application     `json:"applications,omitempty"`
+}
+
+type IndexPrivileges struct {
+	Names      []string `json:"names"`
+	Privileges []string `json:"privileges"`
 }
 
 type Application struct {
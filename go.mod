@@ -30,8 +30,9 @@ require (
 	github.com/jaegertracing/jaeger v1.16.0
 	github.com/jcmturner/gofork v1.0.0 // indirect
 	github.com/josephspurrier/goversioninfo v0.0.0-20200309025242-14b0ab84c6ca // indirect
+	github.com/json-iterator/go v1.1.8
 	github.com/jstemmer/go-junit-report v0.9.1
-	github.com/klauspost/compress v1.9.3-0.20191122130757-c099ac9f21dd // indirect
+	github.com/klauspost/compress v1.9.3-0.20191122130757-c099ac9f21dd
 	github.com/magefile/mage v1.9.0
 	github.com/mitchellh/hashstructure v1.0.0 // indirect
 	github.com/modern-go/reflect2 v1.0.1
@@ -19,17 +19,21 @@ package common
 
 const APMPrefix = "apm-%{[observer.version]}"
 
+// SourcemapEventType is the event type of uploaded sourcemaps. Sourcemaps are
+// not among the EventTypes, as they are not ingested via the intake API.
+const SourcemapEventType = "sourcemap"
+
 var (
 	EventTypes    = []string{"span", "transaction", "error", "metric", "profile"}
 	FallbackIndex = "apm-%{[observer.version]}-%{+yyyy.MM.dd}"
 )
 
 func ConditionalSourcemapIndex() map[string]interface{} {
-	return Condition("sourcemap", APMPrefix+"-sourcemap")
+	return Condition(SourcemapEventType, APMPrefix+"-"+SourcemapEventType)
 }
 
-func ConditionalOnboardingIndex() map[string]interface{} {
-	return Condition("onboarding", APMPrefix+"-onboarding-%{+yyyy.MM.dd}")
+func ConditionalTraceSummaryIndex() map[string]interface{} {
+	return Condition("trace", APMPrefix+"-trace-%{+yyyy.MM.dd}")
 }
 
 func Condition(event string, index string) map[string]interface{} {
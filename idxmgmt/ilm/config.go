@@ -33,7 +33,8 @@ import (
 )
 
 const (
-	defaultPolicyName = "apm-rollover-30-days"
+	defaultPolicyName   = "apm-rollover-30-days"
+	sourcemapPolicyName = "apm-sourcemap"
 )
 
 //Config holds information about ILM mode and whether or not the server should manage the setup
@@ -216,6 +217,10 @@ func defaultMappings() map[string]Mapping {
 		m[et] = Mapping{EventType: et, PolicyName: defaultPolicyName,
 			RolloverAlias: fmt.Sprintf("%s-%s", common.APMPrefix, et)}
 	}
+	// sourcemaps are written to their own alias, so they do not share
+	// the lifecycle of the event indices
+	m[common.SourcemapEventType] = Mapping{EventType: common.SourcemapEventType, PolicyName: sourcemapPolicyName,
+		RolloverAlias: fmt.Sprintf("%s-%s", common.APMPrefix, common.SourcemapEventType)}
 	return m
 }
 
@@ -250,13 +255,33 @@ func defaultPolicies() map[string]Policy {
 				},
 			},
 		},
+		// sourcemaps are needed for as long as the corresponding service
+		// versions are running, so they are only rolled over by size
+		sourcemapPolicyName: {
+			Name: sourcemapPolicyName,
+			Body: map[string]interface{}{
+				"policy": map[string]interface{}{
+					"phases": map[string]interface{}{
+						"hot": map[string]interface{}{
+							"actions": map[string]interface{}{
+								"rollover": map[string]interface{}{
+									"max_size": "50gb",
+								},
+								"set_priority": map[string]interface{}{
+									"priority": 100,
+								},
+							},
+						},
+					},
+				},
+			},
+		},
 	}
 }
 
 func (c *Config) conditionalIndices() []map[string]interface{} {
 	conditions := []map[string]interface{}{
-		common.ConditionalOnboardingIndex(),
-		common.ConditionalSourcemapIndex(),
+		common.ConditionalTraceSummaryIndex(),
 	}
 	for _, m := range c.Setup.Mappings {
 		conditions = append(conditions, common.Condition(m.EventType, m.RolloverAlias))
@@ -132,9 +132,12 @@ func TestConfig_Valid(t *testing.T) {
 							RolloverAlias: "apm-metric"},
 						"profile": {EventType: "profile", PolicyName: defaultPolicyName,
 							RolloverAlias: "apm-9.9.9-profile"},
+						"sourcemap": {EventType: "sourcemap", PolicyName: sourcemapPolicyName,
+							RolloverAlias: "apm-9.9.9-sourcemap"},
 					},
 					Policies: map[string]Policy{
-						defaultPolicyName: defaultPolicies()[defaultPolicyName],
+						defaultPolicyName:   defaultPolicies()[defaultPolicyName],
+						sourcemapPolicyName: defaultPolicies()[sourcemapPolicyName],
 						"spanPolicy": {Name: "spanPolicy", Body: map[string]interface{}{
 							"policy": map[string]interface{}{"phases": map[string]interface{}{
 								"foo": map[string]interface{}{}}}}},
@@ -150,6 +153,7 @@ func TestConfig_Valid(t *testing.T) {
 						defaultPolicyName: {Name: defaultPolicyName, Body: map[string]interface{}{
 							"policy": map[string]interface{}{"phases": map[string]interface{}{
 								"warm": map[string]interface{}{"min_age": "30d"}}}}},
+						sourcemapPolicyName: defaultPolicies()[sourcemapPolicyName],
 					},
 				}},
 		},
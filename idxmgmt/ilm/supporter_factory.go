@@ -27,10 +27,13 @@ import (
 const pattern = "000001"
 
 // MakeDefaultSupporter creates the ILM supporter for APM that is passed to libbeat.
+// In multi-tenant mode, events are written to aliases suffixed with "-<tenant id>",
+// so a supporter is also created for each event type's alias of each tenant.
 func MakeDefaultSupporter(
 	log *logp.Logger,
 	mode libilm.Mode,
-	ilmConfig Config) ([]libilm.Supporter, error) {
+	ilmConfig Config,
+	tenants []string) ([]libilm.Supporter, error) {
 
 	if log == nil {
 		log = logp.NewLogger(logs.Ilm)
@@ -42,9 +45,20 @@ func MakeDefaultSupporter(
 
 	for _, m := range ilmConfig.Setup.Mappings {
 		policy := ilmConfig.Setup.Policies[m.PolicyName]
-		supporter := libilm.NewStdSupport(log, mode, libilm.Alias{Name: m.RolloverAlias, Pattern: pattern},
-			libilm.Policy{Name: policy.Name, Body: policy.Body}, ilmConfig.Setup.Overwrite, true)
-		supporters = append(supporters, supporter)
+		for _, alias := range append([]string{m.RolloverAlias}, TenantAliases(m.RolloverAlias, tenants)...) {
+			supporter := libilm.NewStdSupport(log, mode, libilm.Alias{Name: alias, Pattern: pattern},
+				libilm.Policy{Name: policy.Name, Body: policy.Body}, ilmConfig.Setup.Overwrite, true)
+			supporters = append(supporters, supporter)
+		}
 	}
 	return supporters, nil
 }
+
+// TenantAliases returns the write aliases of the given tenants for alias.
+func TenantAliases(alias string, tenants []string) []string {
+	aliases := make([]string, len(tenants))
+	for i, tenant := range tenants {
+		aliases[i] = alias + "-" + tenant
+	}
+	return aliases
+}
@@ -33,17 +33,38 @@ func TestMakeDefaultSupporter(t *testing.T) {
 	cfg, err := NewConfig(info, nil)
 	require.NoError(t, err)
 
-	s, err := MakeDefaultSupporter(nil, 0, cfg)
+	s, err := MakeDefaultSupporter(nil, 0, cfg, nil)
 	require.NoError(t, err)
-	assert.Equal(t, 5, len(s))
+	assert.Equal(t, 6, len(s))
 	var aliases []string
 	for _, sup := range s {
 		aliases = append(aliases, sup.Alias().Name)
-		assert.Equal(t, defaultPolicyName, sup.Policy().Name)
+		if sup.Alias().Name == "apm-9.9.9-sourcemap" {
+			assert.Equal(t, sourcemapPolicyName, sup.Policy().Name)
+		} else {
+			assert.Equal(t, defaultPolicyName, sup.Policy().Name)
+		}
 	}
-	var defaultAliases []string
+	defaultAliases := []string{"apm-9.9.9-sourcemap"}
 	for _, et := range common.EventTypes {
 		defaultAliases = append(defaultAliases, "apm-9.9.9-"+et)
 	}
 	assert.ElementsMatch(t, defaultAliases, aliases)
 }
+
+func TestMakeDefaultSupporterTenants(t *testing.T) {
+	info := beat.Info{Beat: "mockapm", Version: "9.9.9"}
+	cfg, err := NewConfig(info, nil)
+	require.NoError(t, err)
+
+	s, err := MakeDefaultSupporter(nil, 0, cfg, []string{"team-a", "team-b"})
+	require.NoError(t, err)
+	assert.Equal(t, 18, len(s))
+	policies := make(map[string]string)
+	for _, sup := range s {
+		policies[sup.Alias().Name] = sup.Policy().Name
+	}
+	assert.Equal(t, defaultPolicyName, policies["apm-9.9.9-span-team-a"])
+	assert.Equal(t, defaultPolicyName, policies["apm-9.9.9-span-team-b"])
+	assert.Equal(t, sourcemapPolicyName, policies["apm-9.9.9-sourcemap-team-a"])
+}
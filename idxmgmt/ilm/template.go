@@ -25,12 +25,24 @@ import (
 
 //Template returns a template configuration with appropriate ILM settings
 func Template(ilmEnabled, overwrite bool, name string, policy string) libtemplate.TemplateConfig {
+	return newTemplate(ilmEnabled, overwrite, name, policy, 2)
+}
+
+// TenantTemplate returns a template configuration with appropriate ILM settings
+// for a tenant's alias. As the tenant's indices also match the pattern of the
+// event type's template, the tenant's template has a higher order, overriding
+// its rollover alias.
+func TenantTemplate(ilmEnabled, overwrite bool, name string, policy string) libtemplate.TemplateConfig {
+	return newTemplate(ilmEnabled, overwrite, name, policy, 3)
+}
+
+func newTemplate(ilmEnabled, overwrite bool, name string, policy string, order int) libtemplate.TemplateConfig {
 	template := libtemplate.TemplateConfig{
 		Enabled:   true,
 		Name:      name,
 		Pattern:   fmt.Sprintf("%s*", name),
 		Overwrite: overwrite,
-		Order:     2,
+		Order:     order,
 	}
 	if ilmEnabled {
 		template.Settings = libtemplate.TemplateSettings{
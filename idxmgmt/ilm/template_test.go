@@ -54,3 +54,10 @@ func TestTemplate_ILMDisabled(t *testing.T) {
 	}
 	assert.Equal(t, expected, tmpl)
 }
+
+func TestTenantTemplate(t *testing.T) {
+	tmpl := TenantTemplate(true, false, "mock-apm-team-a", "mock-apm-keep")
+	assert.Equal(t, 3, tmpl.Order)
+	assert.Equal(t, "mock-apm-team-a*", tmpl.Pattern)
+	assert.Equal(t, "mock-apm-team-a", tmpl.Settings.Index["lifecycle.rollover_alias"])
+}
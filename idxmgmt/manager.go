@@ -19,6 +19,7 @@ package idxmgmt
 
 import (
 	"fmt"
+	"strings"
 
 	"github.com/pkg/errors"
 
@@ -95,7 +96,14 @@ func (m *manager) Setup(loadTemplate, loadILM libidxmgmt.LoadMode) error {
 	m.supporter.templateConfig.Enabled = templateFeature.enabled
 	m.supporter.templateConfig.Overwrite = templateFeature.overwrite
 
-	//(1) load general apm template
+	//(1) load general apm template, after comparing it with the installed template if configured
+	if templateFeature.load {
+		overwrite, err := m.checkTemplate(templateFeature.overwrite)
+		if err != nil {
+			return err
+		}
+		templateFeature.overwrite = overwrite
+	}
 	if err := m.loadTemplate(templateFeature, ilmFeature); err != nil {
 		return err
 	}
@@ -128,6 +136,37 @@ func (m *manager) Setup(loadTemplate, loadILM libidxmgmt.LoadMode) error {
 	return nil
 }
 
+// SetupEventTemplate loads the templates making up the index template for the given event type:
+// the general APM template, and the event type specific template carrying the index lifecycle settings.
+// Templates are loaded regardless of the configured template and ILM overwrite settings,
+// which makes it suitable for exporting them.
+func (m *manager) SetupEventTemplate(eventType string) error {
+	mapping, ok := m.supporter.ilmConfig.Setup.Mappings[eventType]
+	if !ok {
+		return errors.Errorf("unknown event type '%s', must be one of: %s",
+			eventType, strings.Join(append(common.EventTypes[:len(common.EventTypes):len(common.EventTypes)],
+				common.SourcemapEventType), ", "))
+	}
+
+	templateFeature := m.templateFeature(libidxmgmt.LoadModeForce)
+	ilmFeature := m.ilmFeature(libidxmgmt.LoadModeOverwrite)
+	m.supporter.templateConfig.Enabled = templateFeature.enabled
+	m.supporter.templateConfig.Overwrite = templateFeature.overwrite
+	if err := m.loadTemplate(templateFeature, ilmFeature); err != nil {
+		return err
+	}
+
+	if !ilmFeature.load {
+		return nil
+	}
+	for _, ilmSupporter := range m.supporter.ilmSupporters {
+		if ilmSupporter.Alias().Name == mapping.RolloverAlias {
+			return m.loadEventTemplate(ilmFeature, ilmSupporter)
+		}
+	}
+	return nil
+}
+
 func (m *manager) templateFeature(loadMode libidxmgmt.LoadMode) feature {
 	return newFeature(m.supporter.templateConfig.Enabled, m.supporter.templateConfig.Overwrite,
 		m.supporter.templateConfig.Enabled, true, loadMode)
@@ -196,6 +235,18 @@ func (m *manager) loadTemplate(templateFeature, ilmFeature feature) error {
 	if !templateFeature.load {
 		return nil
 	}
+	m.setTemplateDefaults()
+	templateCfg := m.supporter.templateConfig
+	templateCfg.Overwrite = templateFeature.overwrite
+	if err := m.clientHandler.Load(templateCfg, m.supporter.info,
+		m.assets.Fields(m.supporter.info.Beat), m.supporter.migration); err != nil {
+		return fmt.Errorf("error loading Elasticsearch template: %+v", err)
+	}
+	m.supporter.log.Infof("Finished loading index template.")
+	return nil
+}
+
+func (m *manager) setTemplateDefaults() {
 	// if not customized, set the APM template name and pattern to the
 	// default index prefix for managed and unmanaged indices;
 	// in case the index/rollover_alias names were customized
@@ -205,16 +256,14 @@ func (m *manager) loadTemplate(templateFeature, ilmFeature feature) error {
 		m.supporter.templateConfig.Pattern = m.supporter.templateConfig.Name + "*"
 		m.supporter.log.Infof("Set setup.template.pattern to '%s'.", m.supporter.templateConfig.Pattern)
 	}
-	if err := m.clientHandler.Load(m.supporter.templateConfig, m.supporter.info,
-		m.assets.Fields(m.supporter.info.Beat), m.supporter.migration); err != nil {
-		return fmt.Errorf("error loading Elasticsearch template: %+v", err)
-	}
-	m.supporter.log.Infof("Finished loading index template.")
-	return nil
 }
 
 func (m *manager) loadEventTemplate(feature feature, ilmSupporter libilm.Supporter) error {
-	templateCfg := ilm.Template(feature.enabled, feature.overwrite,
+	template := ilm.Template
+	if m.supporter.tenantAliases[ilmSupporter.Alias().Name] {
+		template = ilm.TenantTemplate
+	}
+	templateCfg := template(feature.enabled, feature.overwrite,
 		ilmSupporter.Alias().Name,
 		ilmSupporter.Policy().Name)
 
@@ -234,11 +283,15 @@ func (m *manager) loadPolicy(ilmFeature feature, ilmSupporter libilm.Supporter,
 		m.supporter.log.Infof("ILM policy %s not loaded.", policy)
 		return policiesLoaded, nil
 	}
-	_, err := ilmSupporter.Manager(m.clientHandler).EnsurePolicy(ilmFeature.overwrite)
+	created, err := ilmSupporter.Manager(m.clientHandler).EnsurePolicy(ilmFeature.overwrite)
 	if err != nil {
 		return policiesLoaded, err
 	}
-	m.supporter.log.Infof("ILM policy %s successfully loaded.", policy)
+	if created {
+		m.supporter.log.Infof("ILM policy %s successfully loaded.", policy)
+	} else {
+		m.checkPolicy(ilmSupporter.Policy())
+	}
 	return append(policiesLoaded, policy), nil
 }
 func (m *manager) loadAlias(ilmFeature feature, ilmSupporter libilm.Supporter) error {
@@ -18,7 +18,9 @@
 package idxmgmt
 
 import (
+	"encoding/json"
 	"fmt"
+	"net/http"
 	"strings"
 	"testing"
 
@@ -30,6 +32,7 @@ import (
 	"github.com/elastic/beats/v7/libbeat/common"
 	libidxmgmt "github.com/elastic/beats/v7/libbeat/idxmgmt"
 	libilm "github.com/elastic/beats/v7/libbeat/idxmgmt/ilm"
+	"github.com/elastic/beats/v7/libbeat/logp"
 	"github.com/elastic/beats/v7/libbeat/template"
 )
 
@@ -200,6 +203,92 @@ func TestManager_SetupTemplate(t *testing.T) {
 		}
 	}
 }
+func TestManager_SetupTemplateOnChange(t *testing.T) {
+	fields := []byte(`
+- key: apm
+  title: APM
+  fields:
+    - name: service.name
+      type: keyword
+`)
+
+	for name, tc := range map[string]struct {
+		onChange  string
+		overwrite bool
+		installed func(shipped common.MapStr) common.MapStr
+
+		err                 string
+		overwrittenTemplate bool
+	}{
+		"NotInstalled": {
+			onChange:  TemplateOnChangeFail,
+			installed: func(common.MapStr) common.MapStr { return nil },
+		},
+		"Unchanged": {
+			onChange:  TemplateOnChangeFail,
+			installed: func(shipped common.MapStr) common.MapStr { return shipped },
+		},
+		"ChangedFail": {
+			onChange:  TemplateOnChangeFail,
+			overwrite: true,
+			installed: customizedTemplate,
+			err:       "installed template custom differs from shipped template: -mappings.properties.custom",
+		},
+		"ChangedOverwrite": {
+			onChange:            TemplateOnChangeOverwrite,
+			installed:           customizedTemplate,
+			overwrittenTemplate: true,
+		},
+		"ChangedSkip": {
+			onChange:  TemplateOnChangeSkip,
+			overwrite: true,
+			installed: customizedTemplate,
+		},
+		"NotConfigured": {
+			overwrite:           true,
+			installed:           customizedTemplate,
+			overwrittenTemplate: true,
+		},
+	} {
+		t.Run(name, func(t *testing.T) {
+			sup := defaultSupporter(t, common.MapStr{
+				"setup.template.on_change": tc.onChange,
+				"setup.template.overwrite": tc.overwrite,
+			})
+			client := &mockTemplateClient{version: *common.MustNewVersion("8.0.0")}
+			sup.templateClient = func() (templateClient, error) { return client, nil }
+
+			clientHandler := newMockClientHandler("8.0.0")
+			m := sup.Manager(clientHandler, libidxmgmt.BeatsAssets(fields)).(*manager)
+			tmpl, err := template.New(info.Version, info.IndexPrefix, client.version, sup.templateConfig, false)
+			require.NoError(t, err)
+			shipped, err := m.shippedTemplate(tmpl, sup.templateConfig)
+			require.NoError(t, err)
+			client.installed = tc.installed(shipped)
+
+			err = m.Setup(libidxmgmt.LoadModeEnabled, libidxmgmt.LoadModeDisabled)
+			if tc.err != "" {
+				require.Error(t, err)
+				assert.Equal(t, tc.err, err.Error())
+				assert.Equal(t, 0, clientHandler.templates, "loaded template")
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, 1, clientHandler.templates, "loaded template")
+			assert.Equal(t, tc.overwrittenTemplate, clientHandler.templateForceLoad, "overwritten template")
+			assert.Equal(t, tc.onChange != "", client.closed, "client closed")
+		})
+	}
+
+	t.Run("InvalidOnChange", func(t *testing.T) {
+		cfg, err := common.NewConfigFrom(common.MapStr{"setup.template.on_change": "ignore"})
+		require.NoError(t, err)
+		_, err = MakeDefaultSupporter(nil, info, cfg)
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "invalid value for setup.template.on_change: 'ignore'")
+	})
+}
+
 func TestManager_SetupILM(t *testing.T) {
 	fields := []byte("apm-server fields")
 
@@ -229,12 +318,12 @@ func TestManager_SetupILM(t *testing.T) {
 	var testCasesSetupEnabled = map[string]testCase{
 		"Default": {
 			loadMode:            libidxmgmt.LoadModeEnabled,
-			templatesILMEnabled: 4, policiesLoaded: 1, aliasesLoaded: 4,
+			templatesILMEnabled: 5, policiesLoaded: 2, aliasesLoaded: 5,
 		},
 		"ILM disabled": {
 			cfg:                  common.MapStr{"apm-server.ilm.enabled": false},
 			loadMode:             libidxmgmt.LoadModeEnabled,
-			templatesILMDisabled: 4,
+			templatesILMDisabled: 5,
 		},
 		"ILM setup enabled no overwrite": {
 			cfg: common.MapStr{
@@ -244,7 +333,7 @@ func TestManager_SetupILM(t *testing.T) {
 				"apm-server.ilm.setup.policies":  []common.MapStr{policyRollover1Day},
 			},
 			loadMode:            libidxmgmt.LoadModeEnabled,
-			templatesILMEnabled: 4, policiesLoaded: 1, aliasesLoaded: 4,
+			templatesILMEnabled: 5, policiesLoaded: 2, aliasesLoaded: 5,
 		},
 		"ILM overwrite": {
 			cfg: common.MapStr{
@@ -253,20 +342,20 @@ func TestManager_SetupILM(t *testing.T) {
 				"apm-server.ilm.setup.policies":  []common.MapStr{policyRollover1Day},
 			},
 			loadMode:            libidxmgmt.LoadModeEnabled,
-			templatesILMEnabled: 5, policiesLoaded: 2, aliasesLoaded: 4,
+			templatesILMEnabled: 6, policiesLoaded: 3, aliasesLoaded: 5,
 		},
 		"LoadModeOverwrite": {
 			loadMode:            libidxmgmt.LoadModeOverwrite,
-			templatesILMEnabled: 5, policiesLoaded: 1, aliasesLoaded: 4,
+			templatesILMEnabled: 6, policiesLoaded: 2, aliasesLoaded: 5,
 		},
 		"LoadModeForce ILM enabled": {
 			loadMode:            libidxmgmt.LoadModeForce,
-			templatesILMEnabled: 5, policiesLoaded: 1, aliasesLoaded: 4,
+			templatesILMEnabled: 6, policiesLoaded: 2, aliasesLoaded: 5,
 		},
 		"LoadModeForce ILM disabled": {
 			cfg:                  common.MapStr{"apm-server.ilm.enabled": false},
 			loadMode:             libidxmgmt.LoadModeForce,
-			templatesILMDisabled: 5,
+			templatesILMDisabled: 6,
 		},
 		"ILM overwrite LoadModeDisabled": {
 			cfg:                 common.MapStr{"apm-server.ilm.setup.overwrite": true},
@@ -294,12 +383,12 @@ func TestManager_SetupILM(t *testing.T) {
 		"SetupDisabled LoadModeForce ILM enabled": {
 			cfg:                 common.MapStr{"apm-server.ilm.setup.enabled": false},
 			loadMode:            libidxmgmt.LoadModeForce,
-			templatesILMEnabled: 5, policiesLoaded: 1, aliasesLoaded: 4,
+			templatesILMEnabled: 6, policiesLoaded: 2, aliasesLoaded: 5,
 		},
 		"SetupDisabled LoadModeForce ILM disabled": {
 			cfg:                  common.MapStr{"apm-server.ilm.setup.enabled": false, "apm-server.ilm.enabled": false},
 			loadMode:             libidxmgmt.LoadModeForce,
-			templatesILMDisabled: 5,
+			templatesILMDisabled: 6,
 		},
 		"LoadModeDisabled": {
 			loadMode: libidxmgmt.LoadModeDisabled,
@@ -310,19 +399,19 @@ func TestManager_SetupILM(t *testing.T) {
 		"Default ES Unsupported ILM": {
 			version:              "6.2.0",
 			loadMode:             libidxmgmt.LoadModeEnabled,
-			templatesILMDisabled: 4,
+			templatesILMDisabled: 5,
 		},
 		"SetupOverwrite Default ES Unsupported ILM": {
 			cfg:                  common.MapStr{"apm-server.ilm.setup.overwrite": "true"},
 			version:              "6.2.0",
 			loadMode:             libidxmgmt.LoadModeEnabled,
-			templatesILMDisabled: 5,
+			templatesILMDisabled: 6,
 		},
 		"ILM True ES Unsupported ILM": {
 			cfg:                  common.MapStr{"apm-server.ilm.enabled": "true"},
 			loadMode:             libidxmgmt.LoadModeEnabled,
 			version:              "6.2.0",
-			templatesILMDisabled: 4,
+			templatesILMDisabled: 5,
 		},
 		"Default ES Unsupported ILM setup disabled": {
 			cfg:      common.MapStr{"apm-server.ilm.setup.enabled": false},
@@ -344,7 +433,7 @@ func TestManager_SetupILM(t *testing.T) {
 				"setup.template.pattern":       "custom",
 				"output.elasticsearch.index":   "custom"},
 			loadMode:             libidxmgmt.LoadModeEnabled,
-			templatesILMDisabled: 4,
+			templatesILMDisabled: 5,
 		},
 		"ESIndicesConfigured": {
 			cfg: common.MapStr{
@@ -357,7 +446,7 @@ func TestManager_SetupILM(t *testing.T) {
 					"when": map[string]interface{}{
 						"contains": map[string]interface{}{"processor.event": "metric"}}}}},
 			loadMode:             libidxmgmt.LoadModeEnabled,
-			templatesILMDisabled: 4,
+			templatesILMDisabled: 5,
 		},
 		"ESIndexConfigured setup disabled": {
 			cfg: common.MapStr{
@@ -395,7 +484,7 @@ func TestManager_SetupILM(t *testing.T) {
 			// templates for all event types are loaded
 			// profile, span, and metrics share the same default policy, one policy is loaded
 			// 1 alias already exists, 4 new ones are loaded
-			templatesILMEnabled: 4, policiesLoaded: 1, aliasesLoaded: 4,
+			templatesILMEnabled: 5, policiesLoaded: 2, aliasesLoaded: 5,
 		},
 	}
 
@@ -425,6 +514,181 @@ func TestManager_SetupILM(t *testing.T) {
 	}
 }
 
+func TestManager_SetupILMPolicyChanges(t *testing.T) {
+	policy := common.MapStr{
+		"phases": common.MapStr{
+			"delete": common.MapStr{"actions": common.MapStr{"delete": common.MapStr{}}},
+		},
+	}
+	for name, tc := range map[string]struct {
+		unchecked bool
+		installed common.MapStr
+		message   string
+		changes   []interface{}
+	}{
+		"NotChecked": {
+			unchecked: true,
+			message:   "ILM policy rollover-1-day exists already.",
+		},
+		"Unchanged": {
+			installed: common.MapStr{"version": 1, "policy": policy},
+			message:   "ILM policy rollover-1-day exists already and is up to date.",
+		},
+		"Changed": {
+			installed: common.MapStr{"version": 2, "policy": common.MapStr{
+				"phases": common.MapStr{
+					"hot":    common.MapStr{"actions": common.MapStr{"rollover": common.MapStr{"max_age": "1d"}}},
+					"delete": common.MapStr{"min_age": "7d", "actions": common.MapStr{"delete": common.MapStr{}}},
+				},
+			}},
+			message: "installed ILM policy differs from shipped policy, keeping installed policy",
+			changes: []interface{}{"-policy.phases.delete.min_age", "-policy.phases.hot"},
+		},
+	} {
+		t.Run(name, func(t *testing.T) {
+			require.NoError(t, logp.DevelopmentSetup(logp.ToObserverOutput()))
+			sup := defaultSupporter(t, common.MapStr{
+				"apm-server.ilm.setup.mapping":  []common.MapStr{{"event_type": "error", "policy_name": existingILMPolicy}},
+				"apm-server.ilm.setup.policies": []common.MapStr{{"name": existingILMPolicy, "policy": policy}},
+			})
+			client := &mockTemplateClient{
+				version:  *common.MustNewVersion("8.0.0"),
+				policies: map[string]common.MapStr{existingILMPolicy: tc.installed},
+			}
+			sup.templateClient = func() (templateClient, error) { return client, nil }
+			if tc.unchecked {
+				sup.templateClient = nil
+			}
+
+			m := sup.Manager(newMockClientHandler("8.0.0"), libidxmgmt.BeatsAssets([]byte("apm-server fields")))
+			require.NoError(t, m.Setup(libidxmgmt.LoadModeDisabled, libidxmgmt.LoadModeEnabled))
+			assert.Equal(t, !tc.unchecked, client.closed, "client closed")
+
+			var found bool
+			for _, entry := range logp.ObserverLogs().All() {
+				if entry.Message != tc.message {
+					continue
+				}
+				found = true
+				if tc.changes != nil {
+					fields := entry.ContextMap()
+					assert.Equal(t, existingILMPolicy, fields["policy"])
+					assert.Equal(t, tc.changes, fields["changes"])
+				}
+			}
+			assert.True(t, found, "missing log message %q", tc.message)
+		})
+	}
+}
+
+func TestManager_SetupILMTenants(t *testing.T) {
+	clientHandler := newMockClientHandler("8.0.0")
+	m := defaultSupporter(t, common.MapStr{
+		"apm-server.tenancy.enabled": true,
+		"apm-server.tenancy.tenants": []common.MapStr{{"id": "team-a"}},
+	}).Manager(clientHandler, libidxmgmt.BeatsAssets([]byte("apm-server fields")))
+	require.NoError(t, m.Setup(libidxmgmt.LoadModeDisabled, libidxmgmt.LoadModeEnabled))
+
+	// The write aliases of the tenant are bootstrapped along with those of the event types,
+	// and their templates override the event types' templates.
+	assert.Contains(t, clientHandler.aliases, fmt.Sprintf("apm-%s-span-team-a", info.Version))
+	assert.Contains(t, clientHandler.aliases, fmt.Sprintf("apm-%s-transaction-team-a", info.Version))
+	assert.Contains(t, clientHandler.aliases, fmt.Sprintf("apm-%s-span", info.Version))
+	assert.Equal(t, 11, len(clientHandler.aliases))
+	assert.Equal(t, 5, clientHandler.templatesILMOrder)
+	assert.Equal(t, 5, clientHandler.templatesTenantOrder)
+}
+
+func TestManager_SetupEventTemplate(t *testing.T) {
+	fields := []byte("apm-server fields")
+
+	for name, tc := range map[string]struct {
+		cfg       common.MapStr
+		eventType string
+
+		templatesILMEnabled, templatesILMDisabled int
+	}{
+		"Span": {
+			eventType:           "span",
+			templatesILMEnabled: 1, templatesILMDisabled: 1,
+		},
+		"ExistingTransaction": {
+			eventType:           "transaction",
+			templatesILMEnabled: 1, templatesILMDisabled: 1,
+		},
+		"ILMDisabled": {
+			cfg:                 common.MapStr{"apm-server.ilm.enabled": false},
+			eventType:           "error",
+			templatesILMEnabled: 0, templatesILMDisabled: 2,
+		},
+		"ILMSetupDisabled": {
+			cfg:                 common.MapStr{"apm-server.ilm.setup.enabled": false},
+			eventType:           "metric",
+			templatesILMEnabled: 0, templatesILMDisabled: 1,
+		},
+		"TemplateDisabled": {
+			cfg:                 common.MapStr{"setup.template.enabled": false},
+			eventType:           "profile",
+			templatesILMEnabled: 1, templatesILMDisabled: 1,
+		},
+	} {
+		t.Run(name, func(t *testing.T) {
+			clientHandler := newMockClientHandler("8.0.0")
+			m := defaultSupporter(t, tc.cfg).Manager(clientHandler, libidxmgmt.BeatsAssets(fields))
+			require.NoError(t, m.(*manager).SetupEventTemplate(tc.eventType))
+
+			assert.Equal(t, tc.templatesILMEnabled, clientHandler.templatesILMEnabled, "ILM enabled templates")
+			assert.Equal(t, tc.templatesILMDisabled, clientHandler.templates, "ILM disabled templates")
+			assert.Empty(t, clientHandler.policies, "policies")
+			assert.Empty(t, clientHandler.aliases, "aliases")
+		})
+	}
+
+	t.Run("UnknownEventType", func(t *testing.T) {
+		m := defaultSupporter(t, nil).Manager(newMockClientHandler("8.0.0"), libidxmgmt.BeatsAssets(fields))
+		err := m.(*manager).SetupEventTemplate("trace")
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "unknown event type 'trace'")
+	})
+}
+
+// customizedTemplate returns a copy of the shipped template with a manually added field mapping.
+func customizedTemplate(shipped common.MapStr) common.MapStr {
+	installed := shipped.Clone()
+	installed.Put("mappings.properties.custom", map[string]interface{}{"type": "keyword"})
+	return installed
+}
+
+type mockTemplateClient struct {
+	version   common.Version
+	installed common.MapStr
+	policies  map[string]common.MapStr
+	closed    bool
+}
+
+func (c *mockTemplateClient) Request(method, path string, _ string, _ map[string]string, _ interface{}) (int, []byte, error) {
+	installed := c.installed
+	name := strings.TrimPrefix(path, "/_template/")
+	if strings.HasPrefix(path, "/_ilm/policy/") {
+		name = strings.TrimPrefix(path, "/_ilm/policy/")
+		installed = c.policies[name]
+	}
+	if method != "GET" || installed == nil {
+		return http.StatusNotFound, nil, errors.New("not found")
+	}
+	body, err := json.Marshal(map[string]interface{}{name: installed})
+	return http.StatusOK, body, err
+}
+
+func (c *mockTemplateClient) GetVersion() common.Version {
+	return c.version
+}
+
+func (c *mockTemplateClient) Close() error {
+	c.closed = true
+	return nil
+}
+
 type mockClientHandler struct {
 	// mockClientHandler loads templates, ilm templates, policies and aliases
 	// The handler generally treats them as non-existing in Elasticsearch.
@@ -438,6 +702,7 @@ type mockClientHandler struct {
 
 	templates, templatesILMEnabled int
 	templatesILMOrder              int
+	templatesTenantOrder           int
 	templateForceLoad              bool
 
 	esVersion *common.Version
@@ -464,10 +729,13 @@ func (h *mockClientHandler) Load(config template.TemplateConfig, _ beat.Info, fi
 	} else {
 		h.templates++
 	}
-	if config.Order == 2 {
+	switch config.Order {
+	case 1:
+	case 2:
 		h.templatesILMOrder++
-	}
-	if config.Order != 1 && config.Order != 2 {
+	case 3:
+		h.templatesTenantOrder++
+	default:
 		return errors.New("unexpected template order")
 	}
 	h.templateForceLoad = config.Overwrite
@@ -0,0 +1,92 @@
+// Licensed to Elasticsearch B.V. under one or more contributor
+// license agreements. See the NOTICE file distributed with
+// this work for additional information regarding copyright
+// ownership. Elasticsearch B.V. licenses this file to you under
+// the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package idxmgmt
+
+import (
+	"encoding/json"
+	"net/http"
+
+	libidxmgmt "github.com/elastic/beats/v7/libbeat/idxmgmt"
+	libilm "github.com/elastic/beats/v7/libbeat/idxmgmt/ilm"
+
+	"github.com/elastic/apm-server/utility"
+)
+
+// checkPolicy compares the ILM policy installed in Elasticsearch with the
+// shipped policy and logs the differences. Installed policies are only
+// overwritten if `apm-server.ilm.setup.overwrite` is enabled.
+func (m *manager) checkPolicy(policy libilm.Policy) {
+	log := m.supporter.log
+	if m.supporter.templateClient == nil {
+		log.Infof("ILM policy %s exists already.", policy.Name)
+		return
+	}
+	client, err := m.supporter.templateClient()
+	if err != nil {
+		log.Warnf("Error connecting to Elasticsearch to check ILM policy %s: %+v", policy.Name, err)
+		return
+	}
+	defer client.Close()
+
+	changes, err := policyChanges(client, policy)
+	if err != nil {
+		log.Warnf("Error checking ILM policy %s: %+v", policy.Name, err)
+		return
+	}
+	if len(changes) == 0 {
+		log.Infof("ILM policy %s exists already and is up to date.", policy.Name)
+		return
+	}
+	log.Warnw("installed ILM policy differs from shipped policy, keeping installed policy",
+		"policy", policy.Name, "changes", changes)
+}
+
+// policyChanges returns the paths at which the shipped definition of policy
+// differs from the one installed in Elasticsearch, prefixed by "+" when only
+// shipped, "-" when only installed, and "~" when changed. No changes are
+// returned if the policy is not installed.
+func policyChanges(client libidxmgmt.ESClient, policy libilm.Policy) ([]string, error) {
+	status, body, err := client.Request("GET", "/_ilm/policy/"+policy.Name, "", nil, nil)
+	if status == http.StatusNotFound {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	var policies map[string]struct {
+		Policy interface{} `json:"policy"`
+	}
+	if err := json.Unmarshal(body, &policies); err != nil {
+		return nil, err
+	}
+	installed, ok := policies[policy.Name]
+	if !ok {
+		return nil, nil
+	}
+
+	// Decode the shipped policy from JSON, for comparison with the installed policy.
+	content, err := json.Marshal(policy.Body)
+	if err != nil {
+		return nil, err
+	}
+	var shipped map[string]interface{}
+	if err := json.Unmarshal(content, &shipped); err != nil {
+		return nil, err
+	}
+	return utility.Diff("policy", installed.Policy, shipped["policy"]), nil
+}
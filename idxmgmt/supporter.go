@@ -49,10 +49,13 @@ type supporter struct {
 	log                *logp.Logger
 	info               beat.Info
 	templateConfig     template.TemplateConfig
+	templateOnChange   string
+	templateClient     func() (templateClient, error)
 	ilmConfig          ilm.Config
 	unmanagedIdxConfig *unmanaged.Config
 	migration          bool
 	ilmSupporters      []libilm.Supporter
+	tenantAliases      map[string]bool
 
 	st indexState
 }
@@ -76,6 +79,7 @@ func newSupporter(
 	templateConfig template.TemplateConfig,
 	ilmConfig ilm.Config,
 	outConfig common.ConfigNamespace,
+	tenants []string,
 ) (*supporter, error) {
 
 	var (
@@ -102,10 +106,16 @@ func newSupporter(
 		st.isSet.CAS(false, true)
 	}
 
-	ilmSupporters, err := ilm.MakeDefaultSupporter(log, mode, ilmConfig)
+	ilmSupporters, err := ilm.MakeDefaultSupporter(log, mode, ilmConfig, tenants)
 	if err != nil {
 		return nil, err
 	}
+	tenantAliases := make(map[string]bool)
+	for _, m := range ilmConfig.Setup.Mappings {
+		for _, alias := range ilm.TenantAliases(m.RolloverAlias, tenants) {
+			tenantAliases[alias] = true
+		}
+	}
 
 	return &supporter{
 		log:                log,
@@ -116,6 +126,7 @@ func newSupporter(
 		migration:          false,
 		st:                 st,
 		ilmSupporters:      ilmSupporters,
+		tenantAliases:      tenantAliases,
 	}, nil
 }
 
@@ -213,7 +224,8 @@ func (s *ilmIndexSelector) Select(evt *beat.Event) (string, error) {
 	}
 
 	if s.st.ilmEnabled.Load() {
-		return s.ilmSel.Select(evt)
+		idx, err := s.ilmSel.Select(evt)
+		return withTenant(evt, idx), err
 	}
 	return s.unmanagedSel.Select(evt)
 }
@@ -224,7 +236,24 @@ func (s unmanagedIndexSelector) Select(evt *beat.Event) (string, error) {
 	if idx := getEventCustomIndex(evt); idx != "" {
 		return idx, nil
 	}
-	return outil.Selector(s).Select(evt)
+	idx, err := outil.Selector(s).Select(evt)
+	return withTenant(evt, idx), err
+}
+
+// withTenant suffixes idx with the ID of the tenant evt was ingested for, if any,
+// such that the events of each tenant are written to separate indices. When ILM
+// is enabled, the write aliases of configured tenants are set up along with the
+// event types' aliases.
+func withTenant(evt *beat.Event, idx string) string {
+	if idx == "" {
+		return idx
+	}
+	if tenant, err := evt.Fields.GetValue("tenant.id"); err == nil {
+		if tenant, ok := tenant.(string); ok && tenant != "" {
+			return idx + "-" + tenant
+		}
+	}
+	return idx
 }
 
 // this logic is copied and aligned with handling in beats.
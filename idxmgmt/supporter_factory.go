@@ -22,6 +22,7 @@ import (
 
 	"github.com/elastic/beats/v7/libbeat/beat"
 	"github.com/elastic/beats/v7/libbeat/common"
+	"github.com/elastic/beats/v7/libbeat/esleg/eslegclient"
 	"github.com/elastic/beats/v7/libbeat/idxmgmt"
 	"github.com/elastic/beats/v7/libbeat/logp"
 	"github.com/elastic/beats/v7/libbeat/template"
@@ -37,7 +38,14 @@ func MakeDefaultSupporter(log *logp.Logger, info beat.Info, configRoot *common.C
 	cfg := struct {
 		ILM      *common.Config         `config:"apm-server.ilm"`
 		Template *common.Config         `config:"setup.template"`
+		OnChange string                 `config:"setup.template.on_change"`
 		Output   common.ConfigNamespace `config:"output"`
+		Tenancy  struct {
+			Enabled bool `config:"enabled"`
+			Tenants []struct {
+				ID string `config:"id"`
+			} `config:"tenants"`
+		} `config:"apm-server.tenancy"`
 	}{}
 	if configRoot != nil {
 		if err := configRoot.Unpack(&cfg); err != nil {
@@ -60,7 +68,28 @@ func MakeDefaultSupporter(log *logp.Logger, info beat.Info, configRoot *common.C
 	} else {
 		log = log.Named(logs.IndexManagement)
 	}
-	return newSupporter(log, info, tmplConfig, ilmConfig, cfg.Output)
+	if err := validateTemplateOnChange(cfg.OnChange); err != nil {
+		return nil, err
+	}
+
+	var tenants []string
+	if cfg.Tenancy.Enabled {
+		for _, tenant := range cfg.Tenancy.Tenants {
+			tenants = append(tenants, tenant.ID)
+		}
+	}
+	s, err := newSupporter(log, info, tmplConfig, ilmConfig, cfg.Output, tenants)
+	if err != nil {
+		return nil, err
+	}
+	s.templateOnChange = cfg.OnChange
+	if cfg.Output.Name() == esKey {
+		outputCfg := cfg.Output.Config()
+		s.templateClient = func() (templateClient, error) {
+			return eslegclient.NewConnectedClient(outputCfg)
+		}
+	}
+	return s, nil
 }
 
 func unpackTemplateConfig(cfg *common.Config) (template.TemplateConfig, error) {
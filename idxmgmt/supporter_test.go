@@ -118,10 +118,10 @@ func TestIndexSupport_BuildSelector(t *testing.T) {
 			withIlm: fmt.Sprintf("apm-7.0.0-%s", day),
 			fields:  common.MapStr{},
 		},
-		"DefaultOnboarding": {
-			noIlm:   fmt.Sprintf("apm-7.0.0-onboarding-%s", day),
-			withIlm: fmt.Sprintf("apm-7.0.0-onboarding-%s", day),
-			fields:  common.MapStr{"processor.event": "onboarding"},
+		"DefaultTraceSummary": {
+			noIlm:   fmt.Sprintf("apm-7.0.0-trace-%s", day),
+			withIlm: fmt.Sprintf("apm-7.0.0-trace-%s", day),
+			fields:  common.MapStr{"processor.event": "trace"},
 		},
 		"DefaultError": {
 			noIlm:   fmt.Sprintf("apm-7.0.0-error-%s", day),
@@ -148,6 +148,17 @@ func TestIndexSupport_BuildSelector(t *testing.T) {
 			withIlm: "apm-7.0.0-sourcemap",
 			fields:  common.MapStr{"processor.event": "sourcemap"},
 		},
+		"TenantTransaction": {
+			noIlm:   fmt.Sprintf("apm-7.0.0-transaction-%s-team-a", day),
+			withIlm: "apm-7.0.0-transaction-team-a",
+			fields:  common.MapStr{"processor.event": "transaction", "tenant.id": "team-a"},
+		},
+		"TenantMetaInformationIndex": {
+			noIlm:   fmt.Sprintf("apm-7.0.0-%s", day),
+			withIlm: fmt.Sprintf("apm-7.0.0-%s", day), //meta overwrites tenant
+			fields:  common.MapStr{"processor.event": "span", "tenant.id": "team-a"},
+			meta:    common.MapStr{"index": "apm-7.0.0"},
+		},
 		"MetaInformationAlias": {
 			noIlm:   "apm-7.0.0-meta",
 			withIlm: "apm-7.0.0-meta", //meta overwrites ilm
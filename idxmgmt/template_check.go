@@ -0,0 +1,168 @@
+// Licensed to Elasticsearch B.V. under one or more contributor
+// license agreements. See the NOTICE file distributed with
+// this work for additional information regarding copyright
+// ownership. Elasticsearch B.V. licenses this file to you under
+// the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package idxmgmt
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"net/http"
+	"strings"
+
+	"github.com/pkg/errors"
+
+	libidxmgmt "github.com/elastic/beats/v7/libbeat/idxmgmt"
+	"github.com/elastic/beats/v7/libbeat/paths"
+	"github.com/elastic/beats/v7/libbeat/template"
+
+	"github.com/elastic/apm-server/utility"
+)
+
+// Values for `setup.template.on_change`, defining how to proceed when the
+// template installed in Elasticsearch differs from the shipped template.
+const (
+	TemplateOnChangeFail      = "fail"
+	TemplateOnChangeOverwrite = "overwrite"
+	TemplateOnChangeSkip      = "skip"
+)
+
+// templateClient is the subset of the Elasticsearch client API required
+// for comparing installed and shipped templates and ILM policies.
+type templateClient interface {
+	libidxmgmt.ESClient
+	Close() error
+}
+
+func validateTemplateOnChange(onChange string) error {
+	switch onChange {
+	case "", TemplateOnChangeFail, TemplateOnChangeOverwrite, TemplateOnChangeSkip:
+		return nil
+	}
+	return errors.Errorf("invalid value for setup.template.on_change: '%s', must be one of: %s",
+		onChange, strings.Join([]string{TemplateOnChangeFail, TemplateOnChangeOverwrite, TemplateOnChangeSkip}, ", "))
+}
+
+// checkTemplate compares the template installed in Elasticsearch with the
+// shipped template, logs the differences and returns whether or not the
+// template should be overwritten according to `setup.template.on_change`.
+// If the check is not configured, the given overwrite value is returned.
+func (m *manager) checkTemplate(overwrite bool) (bool, error) {
+	if m.supporter.templateOnChange == "" || m.supporter.templateClient == nil {
+		return overwrite, nil
+	}
+	m.setTemplateDefaults()
+	client, err := m.supporter.templateClient()
+	if err != nil {
+		return false, errors.Wrap(err, "error connecting to Elasticsearch to check template")
+	}
+	defer client.Close()
+
+	name, changes, err := m.templateChanges(client)
+	if err != nil {
+		return false, errors.Wrapf(err, "error checking template %s", name)
+	}
+	if len(changes) == 0 {
+		return overwrite, nil
+	}
+	log := m.supporter.log
+	switch m.supporter.templateOnChange {
+	case TemplateOnChangeFail:
+		log.Errorw("installed template differs from shipped template",
+			"template", name, "changes", changes, "on_change", m.supporter.templateOnChange)
+		return false, errors.Errorf("installed template %s differs from shipped template: %s",
+			name, strings.Join(changes, ", "))
+	case TemplateOnChangeOverwrite:
+		log.Warnw("installed template differs from shipped template, overwriting",
+			"template", name, "changes", changes, "on_change", m.supporter.templateOnChange)
+		return true, nil
+	default:
+		log.Warnw("installed template differs from shipped template, keeping installed template",
+			"template", name, "changes", changes, "on_change", m.supporter.templateOnChange)
+		return false, nil
+	}
+}
+
+// templateChanges returns the name of the general APM template, and the paths
+// at which its shipped definition differs from the one installed in Elasticsearch,
+// prefixed by "+" when only shipped, "-" when only installed, and "~" when changed.
+// Only index patterns and mappings are compared, as Elasticsearch normalizes settings.
+// No changes are returned if the template is not installed.
+func (m *manager) templateChanges(client libidxmgmt.ESClient) (string, []string, error) {
+	cfg := m.supporter.templateConfig
+	tmpl, err := template.New(m.supporter.info.Version, m.supporter.info.IndexPrefix,
+		client.GetVersion(), cfg, m.supporter.migration)
+	if err != nil {
+		return cfg.Name, nil, err
+	}
+	name := tmpl.GetName()
+	if cfg.JSON.Enabled {
+		name = cfg.JSON.Name
+	}
+
+	status, body, err := client.Request("GET", "/_template/"+name, "", nil, nil)
+	if status == http.StatusNotFound {
+		return name, nil, nil
+	}
+	if err != nil {
+		return name, nil, err
+	}
+	var templates map[string]map[string]interface{}
+	if err := json.Unmarshal(body, &templates); err != nil {
+		return name, nil, err
+	}
+	installed, ok := templates[name]
+	if !ok {
+		return name, nil, nil
+	}
+
+	shipped, err := m.shippedTemplate(tmpl, cfg)
+	if err != nil {
+		return name, nil, err
+	}
+	var changes []string
+	for _, key := range []string{"index_patterns", "mappings"} {
+		changes = append(changes, utility.Diff(key, installed[key], shipped[key])...)
+	}
+	return name, changes, nil
+}
+
+// shippedTemplate builds the template body the same way it is loaded by libbeat,
+// decoded from JSON to allow for comparison with the installed template.
+func (m *manager) shippedTemplate(tmpl *template.Template, cfg template.TemplateConfig) (map[string]interface{}, error) {
+	var content []byte
+	var err error
+	switch {
+	case cfg.JSON.Enabled:
+		content, err = ioutil.ReadFile(paths.Resolve(paths.Config, cfg.JSON.Path))
+	case cfg.Fields != "":
+		var body map[string]interface{}
+		if body, err = tmpl.LoadFile(paths.Resolve(paths.Config, cfg.Fields)); err == nil {
+			content, err = json.Marshal(body)
+		}
+	default:
+		var body map[string]interface{}
+		if body, err = tmpl.LoadBytes(m.assets.Fields(m.supporter.info.Beat)); err == nil {
+			content, err = json.Marshal(body)
+		}
+	}
+	if err != nil {
+		return nil, err
+	}
+	var shipped map[string]interface{}
+	err = json.Unmarshal(content, &shipped)
+	return shipped, err
+}
@@ -69,8 +69,8 @@ func (cfg *Config) SelectorConfig() (*libcommon.Config, error) {
 
 func conditionalIndices() []map[string]interface{} {
 	conditions := []map[string]interface{}{
-		common.ConditionalOnboardingIndex(),
 		common.ConditionalSourcemapIndex(),
+		common.ConditionalTraceSummaryIndex(),
 	}
 	for _, k := range common.EventTypes {
 		idxStr := fmt.Sprintf("%s-%s%s", common.APMPrefix, k, "-%{+yyyy.MM.dd}")
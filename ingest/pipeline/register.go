@@ -20,43 +20,110 @@ package pipeline
 import (
 	"encoding/json"
 	"io/ioutil"
+	"net/http"
+	"strings"
 
 	logs "github.com/elastic/apm-server/log"
+	"github.com/elastic/apm-server/utility"
 
 	"github.com/elastic/beats/v7/libbeat/esleg/eslegclient"
 	"github.com/elastic/beats/v7/libbeat/logp"
 	"github.com/elastic/beats/v7/libbeat/paths"
 )
 
-func RegisterPipelines(conn *eslegclient.Connection, overwrite bool, path string) error {
+const (
+	apmPipelineID     = "apm"
+	routingPipelineID = "apm_routing"
+)
+
+// RegisterPipelines registers the pipelines defined at path. If routingField
+// is not empty, the apm pipeline additionally sets the _routing value of
+// documents to the value of the given field.
+func RegisterPipelines(conn *eslegclient.Connection, overwrite bool, path, routingField string) error {
 	logger := logp.NewLogger(logs.Pipelines)
 	pipelines, err := loadPipelinesFromJSON(path)
 	if err != nil {
 		return err
 	}
-	var exists bool
+	if routingField != "" {
+		pipelines = withRouting(pipelines, routingField)
+	}
 	for _, p := range pipelines {
-		if !overwrite {
-			exists, err = conn.PipelineExists(p.Id)
-			if err != nil {
-				return err
-			}
+		existing, err := getPipeline(conn, p.Id)
+		if err != nil {
+			return err
 		}
-		if overwrite || !exists {
-			_, _, err := conn.CreatePipeline(p.Id, nil, p.Body)
-			if err != nil {
-				logger.Errorf("Pipeline registration failed for %s.", p.Id)
-				return err
+		if existing != nil {
+			changes := utility.Diff("", existing, p.Body)
+			if len(changes) == 0 {
+				logger.Infof("Pipeline already registered and up to date: %s", p.Id)
+				continue
+			}
+			if !overwrite {
+				logger.Infof("Pipeline already registered: %s, differs from definition: %s",
+					p.Id, strings.Join(changes, ", "))
+				continue
 			}
-			logger.Infof("Pipeline successfully registered: %s", p.Id)
-		} else {
-			logger.Infof("Pipeline already registered: %s", p.Id)
+			logger.Infof("Updating pipeline %s: %s", p.Id, strings.Join(changes, ", "))
 		}
+		if _, _, err := conn.CreatePipeline(p.Id, nil, p.Body); err != nil {
+			logger.Errorf("Pipeline registration failed for %s.", p.Id)
+			return err
+		}
+		logger.Infof("Pipeline successfully registered: %s", p.Id)
 	}
 	logger.Info("Registered Ingest Pipelines successfully.")
 	return nil
 }
 
+// getPipeline returns the definition of the pipeline with the given id
+// currently registered in Elasticsearch, or nil if it does not exist.
+func getPipeline(conn *eslegclient.Connection, id string) (map[string]interface{}, error) {
+	status, body, err := conn.Request("GET", "/_ingest/pipeline/"+id, "", nil, nil)
+	if status == http.StatusNotFound {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	var pipelines map[string]map[string]interface{}
+	if err := json.Unmarshal(body, &pipelines); err != nil {
+		return nil, err
+	}
+	return pipelines[id], nil
+}
+
+// withRouting adds a pipeline setting the _routing value of documents to the
+// value of the given field, and calls it from the apm pipeline. Documents
+// without the field are routed by their _id as usual.
+func withRouting(pipelines []pipeline, field string) []pipeline {
+	for _, p := range pipelines {
+		if p.Id != apmPipelineID {
+			continue
+		}
+		processors, _ := p.Body["processors"].([]interface{})
+		p.Body["processors"] = append(processors, map[string]interface{}{
+			"pipeline": map[string]interface{}{"name": routingPipelineID},
+		})
+		return append(pipelines, pipeline{
+			Id: routingPipelineID,
+			Body: map[string]interface{}{
+				"description": "Set the routing for APM events",
+				"processors": []interface{}{
+					map[string]interface{}{
+						"set": map[string]interface{}{
+							"field": "_routing",
+							"value": "{{" + field + "}}",
+							"if":    "ctx." + strings.Join(strings.Split(field, "."), "?.") + " != null",
+						},
+					},
+				},
+			},
+		})
+	}
+	return pipelines
+}
+
 type pipeline struct {
 	Id   string                 `json:"id"`
 	Body map[string]interface{} `json:"body"`
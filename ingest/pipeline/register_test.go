@@ -18,8 +18,13 @@
 package pipeline
 
 import (
+	"encoding/json"
 	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
 	"strings"
+	"sync"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
@@ -38,24 +43,108 @@ func TestRegisterPipelines(t *testing.T) {
 	require.NoError(t, err)
 
 	// pipeline loading goes wrong
-	err = RegisterPipelines(esClient, true, "non-existing")
+	err = RegisterPipelines(esClient, true, "non-existing", "")
 	assert.Error(t, err)
 	assertContainsErrMsg(t, err.Error(), []string{"cannot find the file", "no such file or directory"})
 
 	// pipeline definition empty
 	emptyPath, err := loader.FindFile("..", "testdata", "ingest", "pipeline", "empty.json")
 	require.NoError(t, err)
-	err = RegisterPipelines(esClient, true, emptyPath)
+	err = RegisterPipelines(esClient, true, emptyPath, "")
 	assert.NoError(t, err)
 
 	// invalid esClient
 	invalidClients, err := eslegclient.NewClients(getFakeESConfig(1234))
 	require.NoError(t, err)
-	err = RegisterPipelines(&invalidClients[0], true, path)
+	err = RegisterPipelines(&invalidClients[0], true, path, "")
 	assert.Error(t, err)
 	assertContainsErrMsg(t, err.Error(), []string{"connect: cannot assign requested address", "connection refused"})
 }
 
+func TestRegisterPipelinesIdempotent(t *testing.T) {
+	path, err := loader.FindFile("..", "ingest", "pipeline", "definition.json")
+	require.NoError(t, err)
+	definitions, err := loadPipelinesFromJSON(path)
+	require.NoError(t, err)
+
+	var mu sync.Mutex
+	stored := make(map[string]json.RawMessage)
+	var puts []string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		defer mu.Unlock()
+		id := strings.TrimPrefix(r.URL.Path, "/_ingest/pipeline/")
+		switch r.Method {
+		case http.MethodGet:
+			body, ok := stored[id]
+			if !ok {
+				w.WriteHeader(http.StatusNotFound)
+				w.Write([]byte(`{}`))
+				return
+			}
+			json.NewEncoder(w).Encode(map[string]json.RawMessage{id: body})
+		case http.MethodPut:
+			body, _ := ioutil.ReadAll(r.Body)
+			stored[id] = body
+			puts = append(puts, id)
+			w.Write([]byte(`{"acknowledged":true}`))
+		}
+	}))
+	defer srv.Close()
+
+	cfg, err := common.NewConfigFrom(map[string]interface{}{"hosts": []string{srv.URL}})
+	require.NoError(t, err)
+	esClients, err := eslegclient.NewClients(cfg)
+	require.NoError(t, err)
+	esClient := &esClients[0]
+
+	// all pipelines are missing and get created
+	require.NoError(t, RegisterPipelines(esClient, false, path, ""))
+	assert.Len(t, puts, len(definitions))
+
+	// pipelines are up to date, nothing is written even when overwriting
+	puts = nil
+	require.NoError(t, RegisterPipelines(esClient, true, path, ""))
+	assert.Empty(t, puts)
+
+	// a changed pipeline is only updated when overwriting
+	stored[definitions[0].Id] = json.RawMessage(`{"description":"outdated"}`)
+	require.NoError(t, RegisterPipelines(esClient, false, path, ""))
+	assert.Empty(t, puts)
+	require.NoError(t, RegisterPipelines(esClient, true, path, ""))
+	assert.Equal(t, []string{definitions[0].Id}, puts)
+}
+
+func TestWithRouting(t *testing.T) {
+	path, err := loader.FindFile("..", "ingest", "pipeline", "definition.json")
+	require.NoError(t, err)
+	definitions, err := loadPipelinesFromJSON(path)
+	require.NoError(t, err)
+
+	pipelines := withRouting(definitions, "trace.id")
+	require.Len(t, pipelines, len(definitions)+1)
+
+	var apm map[string]interface{}
+	for _, p := range pipelines {
+		if p.Id == apmPipelineID {
+			apm = p.Body
+		}
+	}
+	processors := apm["processors"].([]interface{})
+	assert.Equal(t, map[string]interface{}{"pipeline": map[string]interface{}{"name": routingPipelineID}},
+		processors[len(processors)-1])
+
+	routing := pipelines[len(pipelines)-1]
+	assert.Equal(t, routingPipelineID, routing.Id)
+	assert.Equal(t, []interface{}{map[string]interface{}{
+		"set": map[string]interface{}{
+			"field": "_routing",
+			"value": "{{trace.id}}",
+			"if":    "ctx.trace?.id != null",
+		},
+	}}, routing.Body["processors"])
+}
+
 func getFakeESConfig(port int) *common.Config {
 	cfg := map[string]interface{}{
 		"hosts": []string{fmt.Sprintf("http://localhost:%v", port)},
@@ -0,0 +1,111 @@
+// Licensed to Elasticsearch B.V. under one or more contributor
+// license agreements. See the NOTICE file distributed with
+// this work for additional information regarding copyright
+// ownership. Elasticsearch B.V. licenses this file to you under
+// the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package kibana
+
+import (
+	"math/rand"
+	"sync"
+	"time"
+)
+
+// failureThreshold is the number of consecutive failed requests
+// after which the circuit breaker opens.
+const failureThreshold = 5
+
+// circuitBreaker stops requests to Kibana after consecutive failures,
+// so that a flapping or unavailable Kibana does not delay every request
+// depending on it. Once open, the circuit breaker rejects requests for
+// an exponentially increasing, jittered, period; after that a single
+// trial request is allowed through, which closes the circuit breaker
+// again if it succeeds.
+//
+// The zero value is a closed circuit breaker, ready for use.
+type circuitBreaker struct {
+	mu        sync.Mutex
+	failures  int
+	backoff   time.Duration
+	openUntil time.Time
+	trial     bool
+
+	// now may be set in tests.
+	now func() time.Time
+}
+
+// allow reports whether a request may be sent. If allow returns true,
+// the caller must call one of success, failure, or abort with the outcome.
+func (b *circuitBreaker) allow() bool {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if b.failures < failureThreshold {
+		return true
+	}
+	if b.trial || b.timeNow().Before(b.openUntil) {
+		return false
+	}
+	// The open period has elapsed: let a single trial request through.
+	b.trial = true
+	return true
+}
+
+// success records a successful request, closing the circuit breaker.
+func (b *circuitBreaker) success() {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if b.failures >= failureThreshold {
+		circuitOpen.Set(0)
+	}
+	b.failures = 0
+	b.backoff = 0
+	b.trial = false
+}
+
+// abort records a request whose outcome is unknown, such as one
+// cancelled by the caller, allowing another trial request if needed.
+func (b *circuitBreaker) abort() {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.trial = false
+}
+
+// failure records a failed request, opening the circuit breaker once
+// failureThreshold consecutive requests have failed.
+func (b *circuitBreaker) failure() {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.failures++
+	b.trial = false
+	if b.failures < failureThreshold {
+		return
+	}
+	if b.backoff == 0 {
+		b.backoff = initBackoff
+	} else if b.backoff *= 2; b.backoff > maxBackoff {
+		b.backoff = maxBackoff
+	}
+	// Use "equal jitter", so the open period is at least half the backoff.
+	half := b.backoff / 2
+	b.openUntil = b.timeNow().Add(half + time.Duration(rand.Int63n(int64(half)+1)))
+	circuitOpen.Set(1)
+}
+
+func (b *circuitBreaker) timeNow() time.Time {
+	if b.now != nil {
+		return b.now()
+	}
+	return time.Now()
+}
@@ -0,0 +1,71 @@
+// Licensed to Elasticsearch B.V. under one or more contributor
+// license agreements. See the NOTICE file distributed with
+// this work for additional information regarding copyright
+// ownership. Elasticsearch B.V. licenses this file to you under
+// the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package kibana
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCircuitBreaker(t *testing.T) {
+	now := time.Now()
+	b := circuitBreaker{now: func() time.Time { return now }}
+
+	for i := 0; i < failureThreshold; i++ {
+		assert.True(t, b.allow())
+		b.failure()
+	}
+	assert.False(t, b.allow())
+	assert.True(t, b.openUntil.Sub(now) >= initBackoff/2)
+	assert.True(t, b.openUntil.Sub(now) <= initBackoff)
+
+	// Once the open period has elapsed, a single trial request is allowed.
+	now = b.openUntil
+	assert.True(t, b.allow())
+	assert.False(t, b.allow())
+
+	// A failed trial request opens the circuit breaker for longer.
+	b.failure()
+	assert.Equal(t, 2*initBackoff, b.backoff)
+	assert.False(t, b.allow())
+
+	// An aborted trial request allows another trial request.
+	now = b.openUntil
+	assert.True(t, b.allow())
+	b.abort()
+	assert.True(t, b.allow())
+
+	// A successful trial request closes the circuit breaker.
+	b.success()
+	assert.Equal(t, 0, b.failures)
+	assert.Equal(t, time.Duration(0), b.backoff)
+	assert.True(t, b.allow())
+	assert.True(t, b.allow())
+}
+
+func TestCircuitBreakerMaxBackoff(t *testing.T) {
+	now := time.Now()
+	b := circuitBreaker{now: func() time.Time { return now }}
+	for i := 0; i < failureThreshold+10; i++ {
+		b.failure()
+	}
+	assert.Equal(t, maxBackoff, b.backoff)
+	assert.True(t, b.openUntil.Sub(now) <= maxBackoff)
+}
@@ -33,6 +33,7 @@ import (
 	"github.com/elastic/beats/v7/libbeat/common/backoff"
 	"github.com/elastic/beats/v7/libbeat/kibana"
 	"github.com/elastic/beats/v7/libbeat/logp"
+	"github.com/elastic/beats/v7/libbeat/monitoring"
 
 	logs "github.com/elastic/apm-server/log"
 )
@@ -40,9 +41,24 @@ import (
 const (
 	initBackoff = time.Second
 	maxBackoff  = 30 * time.Second
+
+	// maxIdleConnsPerHost is the number of idle connections to Kibana kept
+	// for reuse, so concurrent requests do not each open a new connection.
+	maxIdleConnsPerHost = 16
+	idleConnTimeout     = 90 * time.Second
 )
 
-var errNotConnected = errors.New("unable to retrieve connection to Kibana")
+var (
+	errNotConnected = errors.New("unable to retrieve connection to Kibana")
+	errCircuitOpen  = errors.New("requests to Kibana are suspended after repeated failures")
+
+	monitoringRegistry = monitoring.Default.NewRegistry("apm-server.kibana")
+	requestsCounter    = monitoring.NewInt(monitoringRegistry, "requests")
+	errorsCounter      = monitoring.NewInt(monitoringRegistry, "errors")
+	rejectedCounter    = monitoring.NewInt(monitoringRegistry, "rejected")
+	connected          = monitoring.NewInt(monitoringRegistry, "connected")
+	circuitOpen        = monitoring.NewInt(monitoringRegistry, "circuit_open")
+)
 
 // Client provides an interface for Kibana Clients
 type Client interface {
@@ -56,9 +72,10 @@ type Client interface {
 
 // ConnectingClient implements Client interface
 type ConnectingClient struct {
-	m      sync.RWMutex
-	client *kibana.Client
-	cfg    *kibana.ClientConfig
+	m       sync.RWMutex
+	client  *kibana.Client
+	cfg     *kibana.ClientConfig
+	breaker circuitBreaker
 }
 
 // NewConnectingClient returns instance of ConnectingClient and starts a background routine trying to connect
@@ -84,7 +101,7 @@ func NewConnectingClient(cfg *kibana.ClientConfig) Client {
 }
 
 // Send tries to send a request to Kibana via established connection and returns unparsed response
-// If no connection is established an error is returned
+// If no connection is established, or requests are suspended after repeated failures, an error is returned
 func (c *ConnectingClient) Send(ctx context.Context, method, extraPath string, params url.Values,
 	headers http.Header, body io.Reader) (*http.Response, error) {
 	c.m.RLock()
@@ -92,7 +109,28 @@ func (c *ConnectingClient) Send(ctx context.Context, method, extraPath string, p
 	if c.client == nil {
 		return nil, errNotConnected
 	}
-	return c.client.SendWithContext(ctx, method, extraPath, params, headers, body)
+	if !c.breaker.allow() {
+		rejectedCounter.Inc()
+		return nil, errCircuitOpen
+	}
+	requestsCounter.Inc()
+	resp, err := c.client.SendWithContext(ctx, method, extraPath, params, headers, body)
+	c.recordOutcome(ctx, err == nil && resp.StatusCode < http.StatusInternalServerError)
+	return resp, err
+}
+
+// recordOutcome records the outcome of a request allowed by the circuit breaker.
+// Requests cancelled by the caller say nothing about Kibana's health.
+func (c *ConnectingClient) recordOutcome(ctx context.Context, ok bool) {
+	switch {
+	case ok:
+		c.breaker.success()
+	case ctx.Err() != nil:
+		c.breaker.abort()
+	default:
+		errorsCounter.Inc()
+		c.breaker.failure()
+	}
 }
 
 // GetVersion returns Kibana version or an error
@@ -124,15 +162,23 @@ func (c *ConnectingClient) SupportsVersion(ctx context.Context, v *common.Versio
 	if !retry || upToDate {
 		return upToDate, nil
 	}
-	client, err := kibana.NewClientWithConfig(c.cfg)
+	// Reconnecting requests Kibana's status, so it is subject
+	// to the circuit breaker like any other request.
+	if !c.breaker.allow() {
+		rejectedCounter.Inc()
+		return upToDate, errCircuitOpen
+	}
+	requestsCounter.Inc()
+	client, err := newClient(c.cfg)
+	c.recordOutcome(ctx, err == nil)
 	if err != nil {
 		log.Errorf("failed to obtain connection to Kibana: %s", err.Error())
 		return upToDate, err
 	}
-	client.HTTP = apmhttp.WrapClient(client.HTTP)
 	c.m.Lock()
 	c.client = client
 	c.m.Unlock()
+	connected.Set(1)
 	return c.SupportsVersion(ctx, v, false)
 }
 
@@ -145,11 +191,26 @@ func (c *ConnectingClient) connect() error {
 	if c.client != nil {
 		return nil
 	}
-	client, err := kibana.NewClientWithConfig(c.cfg)
+	client, err := newClient(c.cfg)
 	if err != nil {
 		return err
 	}
-	client.HTTP = apmhttp.WrapClient(client.HTTP)
 	c.client = client
+	connected.Set(1)
 	return nil
 }
+
+// newClient returns a new Kibana client, pooling connections
+// for reuse across requests.
+func newClient(cfg *kibana.ClientConfig) (*kibana.Client, error) {
+	client, err := kibana.NewClientWithConfig(cfg)
+	if err != nil {
+		return nil, err
+	}
+	if transport, ok := client.HTTP.Transport.(*http.Transport); ok {
+		transport.MaxIdleConnsPerHost = maxIdleConnsPerHost
+		transport.IdleConnTimeout = idleConnTimeout
+	}
+	client.HTTP = apmhttp.WrapClient(client.HTTP)
+	return client, nil
+}
@@ -55,6 +55,22 @@ func TestConnectingClient_Send(t *testing.T) {
 		assert.Equal(t, err, errNotConnected)
 		assert.Nil(t, r)
 	})
+
+	t.Run("SendCircuitOpen", func(t *testing.T) {
+		c := mockClient()
+		c.client.HTTP.Transport = rt{resp: &http.Response{
+			StatusCode: http.StatusServiceUnavailable,
+			Body:       mockBody,
+		}}
+		for i := 0; i < failureThreshold; i++ {
+			r, err := c.Send(context.Background(), http.MethodGet, "", nil, nil, nil)
+			require.NoError(t, err)
+			assert.Equal(t, http.StatusServiceUnavailable, r.StatusCode)
+		}
+		r, err := c.Send(context.Background(), http.MethodGet, "", nil, nil, nil)
+		assert.Equal(t, errCircuitOpen, err)
+		assert.Nil(t, r)
+	})
 }
 
 func TestConnectingClient_GetVersion(t *testing.T) {
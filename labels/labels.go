@@ -0,0 +1,222 @@
+// Licensed to Elasticsearch B.V. under one or more contributor
+// license agreements. See the NOTICE file distributed with
+// this work for additional information regarding copyright
+// ownership. Elasticsearch B.V. licenses this file to you under
+// the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package labels
+
+import (
+	"context"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/elastic/beats/v7/libbeat/common"
+	"github.com/elastic/beats/v7/libbeat/logp"
+	"github.com/elastic/beats/v7/libbeat/monitoring"
+
+	logs "github.com/elastic/apm-server/log"
+	"github.com/elastic/apm-server/model"
+	"github.com/elastic/apm-server/publish"
+)
+
+var (
+	monitoringRegistry = monitoring.Default.NewRegistry("apm-server.labels")
+	sanitizedCounter   = monitoring.NewInt(monitoringRegistry, "keys_sanitized")
+	droppedCounter     = monitoring.NewInt(monitoringRegistry, "dropped")
+)
+
+// keyReplacer replaces characters which are not permitted in label keys.
+// Dots would otherwise be interpreted as object paths when indexing,
+// conflicting with the mapping of other labels.
+var keyReplacer = strings.NewReplacer(".", "_", "*", "_", `"`, "_")
+
+// Config holds configuration for NewSanitizer.
+type Config struct {
+	// MaxKeysPerService is the maximum number of distinct label keys
+	// indexed for a service per day. Zero means unlimited.
+	MaxKeysPerService int
+}
+
+// Sanitizer sanitizes event labels before they are indexed, replacing
+// characters not permitted in label keys, and limiting the number of
+// distinct label keys per service to protect against mapping explosions.
+//
+// Distinct label keys are tracked per UTC day.
+type Sanitizer struct {
+	cfg    Config
+	logger *logp.Logger
+	now    func() time.Time
+
+	mu       sync.Mutex
+	day      time.Time
+	services map[string]*serviceKeys
+}
+
+type serviceKeys struct {
+	keys     map[string]struct{}
+	exceeded bool
+}
+
+// NewSanitizer returns a new Sanitizer with the given config.
+func NewSanitizer(cfg Config) *Sanitizer {
+	return &Sanitizer{
+		cfg:      cfg,
+		logger:   logp.NewLogger(logs.Labels),
+		now:      time.Now,
+		services: make(map[string]*serviceKeys),
+	}
+}
+
+// Wrap returns a Reporter which sanitizes the labels of events
+// before reporting them to reporter.
+func (s *Sanitizer) Wrap(reporter publish.Reporter) publish.Reporter {
+	return func(ctx context.Context, req publish.PendingReq) error {
+		s.mu.Lock()
+		s.maybeReset()
+		for _, event := range req.Transformables {
+			switch event := event.(type) {
+			case *model.Transaction:
+				s.sanitizeMetadata(&event.Metadata)
+				if event.Labels != nil {
+					labels := model.Labels(s.sanitize(event.Metadata.Service.Name, common.MapStr(*event.Labels)))
+					event.Labels = &labels
+				}
+			case *model.Span:
+				s.sanitizeMetadata(&event.Metadata)
+				event.Labels = s.sanitize(event.Metadata.Service.Name, event.Labels)
+			case *model.Error:
+				s.sanitizeMetadata(&event.Metadata)
+				if event.Labels != nil {
+					labels := model.Labels(s.sanitize(event.Metadata.Service.Name, common.MapStr(*event.Labels)))
+					event.Labels = &labels
+				}
+			case *model.Metricset:
+				s.sanitizeMetadata(&event.Metadata)
+				event.Labels = s.sanitize(event.Metadata.Service.Name, event.Labels)
+			case *model.PprofProfile:
+				s.sanitizeMetadata(&event.Metadata)
+			case *model.TraceSummary:
+				s.sanitizeMetadata(&event.Metadata)
+			}
+		}
+		s.mu.Unlock()
+		return reporter(ctx, req)
+	}
+}
+
+// SanitizeMetadata sanitizes the labels of stream metadata, before it is
+// prepared and shared by the events of the stream. The Reporter returned by
+// Wrap does not sanitize prepared metadata again.
+func (s *Sanitizer) SanitizeMetadata(metadata *model.Metadata) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.maybeReset()
+	metadata.Labels = s.sanitize(metadata.Service.Name, metadata.Labels)
+}
+
+// maybeReset resets the tracked label keys when a new day has started.
+// The caller must hold s.mu.
+func (s *Sanitizer) maybeReset() {
+	now := s.now().UTC()
+	day := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, time.UTC)
+	if !day.Equal(s.day) {
+		s.day = day
+		s.services = make(map[string]*serviceKeys)
+	}
+}
+
+// sanitizeMetadata sanitizes the metadata labels, unless the metadata has
+// been prepared, in which case its labels have been sanitized once for the
+// stream by SanitizeMetadata. The caller must hold s.mu.
+func (s *Sanitizer) sanitizeMetadata(metadata *model.Metadata) {
+	if metadata.Prepared() {
+		return
+	}
+	metadata.Labels = s.sanitize(metadata.Service.Name, metadata.Labels)
+}
+
+// sanitize returns labels with their keys sanitized, and any keys exceeding
+// the service's limit removed. Label maps may be shared between events, so
+// labels is never modified; a new map is returned if any changes are needed.
+//
+// The caller must hold s.mu.
+func (s *Sanitizer) sanitize(service string, labels common.MapStr) common.MapStr {
+	if len(labels) == 0 {
+		return labels
+	}
+	// Sort the keys so the same keys are dropped for identical labels.
+	keys := make([]string, 0, len(labels))
+	for k := range labels {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	var out common.MapStr
+	for i, k := range keys {
+		key := keyReplacer.Replace(k)
+		if key != k {
+			sanitizedCounter.Inc()
+		}
+		allowed := s.allow(service, key)
+		if out == nil {
+			if key == k && allowed {
+				continue
+			}
+			// Copy the labels processed so far, which were unchanged.
+			out = make(common.MapStr, len(labels))
+			for _, k := range keys[:i] {
+				out[k] = labels[k]
+			}
+		}
+		if allowed {
+			out[key] = labels[k]
+		}
+	}
+	if out == nil {
+		return labels
+	}
+	return out
+}
+
+// allow records key as a label key of service, and reports whether the
+// label may be indexed. The caller must hold s.mu.
+func (s *Sanitizer) allow(service, key string) bool {
+	if s.cfg.MaxKeysPerService <= 0 {
+		return true
+	}
+	sk, ok := s.services[service]
+	if !ok {
+		sk = &serviceKeys{keys: make(map[string]struct{})}
+		s.services[service] = sk
+	}
+	if _, ok := sk.keys[key]; ok {
+		return true
+	}
+	if len(sk.keys) >= s.cfg.MaxKeysPerService {
+		droppedCounter.Inc()
+		if !sk.exceeded {
+			sk.exceeded = true
+			s.logger.Warnf(
+				"service %q exceeded the maximum of %d distinct label keys, dropping new label keys until %s",
+				service, s.cfg.MaxKeysPerService, s.day.Add(24*time.Hour).Format(time.RFC3339),
+			)
+		}
+		return false
+	}
+	sk.keys[key] = struct{}{}
+	return true
+}
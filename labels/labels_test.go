@@ -0,0 +1,127 @@
+// Licensed to Elasticsearch B.V. under one or more contributor
+// license agreements. See the NOTICE file distributed with
+// this work for additional information regarding copyright
+// ownership. Elasticsearch B.V. licenses this file to you under
+// the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package labels
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/elastic/beats/v7/libbeat/common"
+	"github.com/elastic/beats/v7/libbeat/monitoring"
+
+	"github.com/elastic/apm-server/model"
+	"github.com/elastic/apm-server/publish"
+	"github.com/elastic/apm-server/transform"
+)
+
+func TestSanitizerReplacesDots(t *testing.T) {
+	sanitizer := NewSanitizer(Config{})
+	shared := common.MapStr{"global.label": "a", "plain": "b"}
+	tx := &model.Transaction{
+		Metadata: model.Metadata{Service: model.Service{Name: "svc"}, Labels: shared},
+		Labels:   &model.Labels{"a.b": 1, `c*"d`: true},
+	}
+	span := &model.Span{Labels: common.MapStr{"unchanged": "x"}}
+	before := snapshot()
+	report(t, sanitizer, tx, span)
+
+	assert.Equal(t, common.MapStr{"global_label": "a", "plain": "b"}, tx.Metadata.Labels)
+	assert.Equal(t, &model.Labels{"a_b": 1, "c__d": true}, tx.Labels)
+	assert.Equal(t, common.MapStr{"unchanged": "x"}, span.Labels)
+	// Label maps may be shared, so they must not be modified.
+	assert.Equal(t, common.MapStr{"global.label": "a", "plain": "b"}, shared)
+
+	after := snapshot()
+	assert.Equal(t, int64(3), after["keys_sanitized"]-before["keys_sanitized"])
+}
+
+func TestSanitizerPreparedMetadata(t *testing.T) {
+	sanitizer := NewSanitizer(Config{MaxKeysPerService: 1})
+	metadata := model.Metadata{
+		Service: model.Service{Name: "svc"},
+		Labels:  common.MapStr{"global.label": "a", "other": "b"},
+	}
+	// Stream metadata is sanitized once, before it is prepared,
+	// and not again for each event sharing it.
+	sanitizer.SanitizeMetadata(&metadata)
+	metadata.Prepare()
+	tx := &model.Transaction{Metadata: metadata}
+	before := snapshot()
+	report(t, sanitizer, tx)
+	assert.Equal(t, before, snapshot())
+	assert.True(t, tx.Metadata.Prepared())
+
+	events := tx.Transform(context.Background(), &transform.Context{})
+	require.Len(t, events, 1)
+	labels, err := events[0].Fields.GetValue("labels")
+	require.NoError(t, err)
+	assert.Equal(t, common.MapStr{"global_label": "a"}, labels)
+}
+
+func TestSanitizerMaxKeysPerService(t *testing.T) {
+	sanitizer := NewSanitizer(Config{MaxKeysPerService: 2})
+	metricset := func(service string, labels common.MapStr) *model.Metricset {
+		return &model.Metricset{
+			Metadata: model.Metadata{Service: model.Service{Name: service}},
+			Labels:   labels,
+		}
+	}
+	m1 := metricset("a", common.MapStr{"k1": 1, "k2": 2, "k3": 3})
+	m2 := metricset("a", common.MapStr{"k1": 1, "k4": 4})
+	m3 := metricset("b", common.MapStr{"k3": 3, "k4": 4})
+	before := snapshot()
+	report(t, sanitizer, m1, m2, m3)
+
+	assert.Equal(t, common.MapStr{"k1": 1, "k2": 2}, m1.Labels)
+	assert.Equal(t, common.MapStr{"k1": 1}, m2.Labels)
+	assert.Equal(t, common.MapStr{"k3": 3, "k4": 4}, m3.Labels)
+
+	after := snapshot()
+	assert.Equal(t, int64(2), after["dropped"]-before["dropped"])
+}
+
+func TestSanitizerResetsDaily(t *testing.T) {
+	now := time.Date(2020, 7, 1, 23, 59, 0, 0, time.UTC)
+	sanitizer := NewSanitizer(Config{MaxKeysPerService: 1})
+	sanitizer.now = func() time.Time { return now }
+
+	span1 := &model.Span{Labels: common.MapStr{"k1": 1}}
+	span2 := &model.Span{Labels: common.MapStr{"k2": 2}}
+	report(t, sanitizer, span1, span2)
+	assert.Equal(t, common.MapStr{"k1": 1}, span1.Labels)
+	assert.Equal(t, common.MapStr{}, span2.Labels)
+
+	now = now.Add(time.Minute)
+	span3 := &model.Span{Labels: common.MapStr{"k2": 2}}
+	report(t, sanitizer, span3)
+	assert.Equal(t, common.MapStr{"k2": 2}, span3.Labels)
+}
+
+func report(t *testing.T, sanitizer *Sanitizer, events ...transform.Transformable) {
+	reporter := sanitizer.Wrap(func(ctx context.Context, req publish.PendingReq) error { return nil })
+	err := reporter(context.Background(), publish.PendingReq{Transformables: events})
+	assert.NoError(t, err)
+}
+
+func snapshot() map[string]int64 {
+	return monitoring.CollectFlatSnapshot(monitoringRegistry, monitoring.Full, false).Ints
+}
@@ -0,0 +1,132 @@
+// Licensed to Elasticsearch B.V. under one or more contributor
+// license agreements. See the NOTICE file distributed with
+// this work for additional information regarding copyright
+// ownership. Elasticsearch B.V. licenses this file to you under
+// the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package logs
+
+import (
+	"fmt"
+	"sync"
+	"time"
+
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+)
+
+// maxRepeatedEntries is the maximum number of distinct messages tracked
+// for repetition. Further messages are logged without being suppressed.
+const maxRepeatedEntries = 1000
+
+// SuppressRepeated returns a zap.Option for logp.NewLogger, suppressing
+// repetitions of an error message for interval after it is logged. Once
+// interval has passed, the message is logged again with the number of
+// times it was repeated, e.g. for the same validation error logged for
+// each request of a broken agent.
+//
+// Messages are identical if their level, logger name, message, and the
+// fields passed when logging them are equal. Fields added to the logger
+// with With, such as request IDs, are not compared, and the summary carries
+// those of the last repetition.
+func SuppressRepeated(interval time.Duration) zap.Option {
+	s := &repeatState{interval: interval, entries: make(map[string]*repetition)}
+	return zap.WrapCore(func(core zapcore.Core) zapcore.Core {
+		return &repeatCore{Core: core, state: s}
+	})
+}
+
+type repeatState struct {
+	interval time.Duration
+
+	mu      sync.Mutex
+	entries map[string]*repetition
+}
+
+// repetition holds the number of times a message was repeated since it
+// was logged, along with the last repetition.
+type repetition struct {
+	count  int
+	core   zapcore.Core
+	entry  zapcore.Entry
+	fields []zapcore.Field
+}
+
+type repeatCore struct {
+	zapcore.Core
+	state *repeatState
+}
+
+func (c *repeatCore) With(fields []zapcore.Field) zapcore.Core {
+	return &repeatCore{Core: c.Core.With(fields), state: c.state}
+}
+
+func (c *repeatCore) Check(entry zapcore.Entry, ce *zapcore.CheckedEntry) *zapcore.CheckedEntry {
+	if entry.Level < zapcore.ErrorLevel {
+		return c.Core.Check(entry, ce)
+	}
+	// Defer to the wrapped core for whether the entry is logged at all,
+	// but write it through this one, so repetitions are suppressed.
+	if c.Core.Check(entry, nil) != nil {
+		return ce.AddCore(entry, c)
+	}
+	return ce
+}
+
+func (c *repeatCore) Write(entry zapcore.Entry, fields []zapcore.Field) error {
+	if entry.Level < zapcore.ErrorLevel {
+		return c.Core.Write(entry, fields)
+	}
+	key := repeatKey(entry, fields)
+	s := c.state
+	s.mu.Lock()
+	if r, ok := s.entries[key]; ok {
+		r.count++
+		r.core, r.entry, r.fields = c.Core, entry, fields
+		s.mu.Unlock()
+		return nil
+	}
+	if len(s.entries) < maxRepeatedEntries {
+		s.entries[key] = &repetition{}
+		time.AfterFunc(s.interval, func() { s.flush(key) })
+	}
+	s.mu.Unlock()
+	return c.Core.Write(entry, fields)
+}
+
+// flush stops tracking the message identified by key, logging
+// the number of times it was repeated, if at all.
+func (s *repeatState) flush(key string) {
+	s.mu.Lock()
+	r := s.entries[key]
+	delete(s.entries, key)
+	s.mu.Unlock()
+	if r == nil || r.count == 0 {
+		return
+	}
+	entry := r.entry
+	entry.Time = time.Now()
+	entry.Message = fmt.Sprintf("%s (repeated %d times in %s)", entry.Message, r.count, s.interval)
+	fields := append(r.fields[:len(r.fields):len(r.fields)], zap.Int("repeated", r.count))
+	r.core.Write(entry, fields)
+}
+
+func repeatKey(entry zapcore.Entry, fields []zapcore.Field) string {
+	enc := zapcore.NewMapObjectEncoder()
+	for _, f := range fields {
+		f.AddTo(enc)
+	}
+	// fmt prints maps sorted by key.
+	return fmt.Sprintf("%s\x00%s\x00%s\x00%v", entry.Level, entry.LoggerName, entry.Message, enc.Fields)
+}
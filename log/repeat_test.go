@@ -0,0 +1,86 @@
+// Licensed to Elasticsearch B.V. under one or more contributor
+// license agreements. See the NOTICE file distributed with
+// this work for additional information regarding copyright
+// ownership. Elasticsearch B.V. licenses this file to you under
+// the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package logs
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+	"go.uber.org/zap/zaptest/observer"
+)
+
+func TestSuppressRepeated(t *testing.T) {
+	core, observed := observer.New(zapcore.DebugLevel)
+	logger := zap.New(core, SuppressRepeated(50*time.Millisecond)).Sugar()
+
+	for i, requestID := range []string{"a", "b", "c"} {
+		reqLogger := logger.With("request_id", requestID)
+		reqLogger.Errorw("data validation error", "error", "invalid event")
+		reqLogger.Infow("handled request", "i", i)
+	}
+	logger.Errorw("data validation error", "error", "invalid metadata")
+	logger.Warn("not an error")
+	logger.Warn("not an error")
+
+	messages := func() []string {
+		var messages []string
+		for _, entry := range observed.All() {
+			messages = append(messages, entry.Message)
+		}
+		return messages
+	}
+	assert.Equal(t, []string{
+		"data validation error",
+		"handled request",
+		"handled request",
+		"handled request",
+		"data validation error",
+		"not an error",
+		"not an error",
+	}, messages())
+
+	assert.Eventually(t, func() bool { return observed.Len() == 8 }, time.Second, 10*time.Millisecond)
+	summary := observed.All()[7]
+	assert.Equal(t, "data validation error (repeated 2 times in 50ms)", summary.Message)
+	assert.Equal(t, zapcore.ErrorLevel, summary.Level)
+	assert.Equal(t, map[string]interface{}{
+		"request_id": "c",
+		"error":      "invalid event",
+		"repeated":   int64(2),
+	}, summary.ContextMap())
+
+	// Once summarized, the message is logged again when next repeated.
+	logger.Errorw("data validation error", "error", "invalid event")
+	require.Equal(t, 9, observed.Len())
+	assert.Equal(t, "data validation error", observed.All()[8].Message)
+}
+
+func TestSuppressRepeatedErrorFields(t *testing.T) {
+	core, observed := observer.New(zapcore.DebugLevel)
+	logger := zap.New(core, SuppressRepeated(time.Minute))
+
+	logger.Error("failed", zap.Error(errors.New("a")))
+	logger.Error("failed", zap.Error(errors.New("b")))
+	logger.Error("failed", zap.Error(errors.New("a")))
+	assert.Equal(t, 2, observed.Len())
+}
@@ -19,22 +19,32 @@ package logs
 
 // logging selectors
 const (
-	Beater             = "beater"
-	Config             = "config"
-	Handler            = "handler"
-	Ilm                = "ilm"
-	IndexManagement    = "index-management"
-	Jaeger             = "jaeger"
-	Kibana             = "kibana"
-	Onboarding         = "onboarding"
-	Otel               = "otel"
-	Pipelines          = "pipelines"
-	Request            = "request"
-	Response           = "response"
-	Server             = "server"
-	Sourcemap          = "sourcemap"
-	Stacktrace         = "stacktrace"
-	Tracing            = "tracing"
-	TransactionMetrics = "txmetrics"
-	Transform          = "transform"
+	Beater               = "beater"
+	Config               = "config"
+	ContainerInference   = "container-inference"
+	Handler              = "handler"
+	Ilm                  = "ilm"
+	IndexManagement      = "index-management"
+	Jaeger               = "jaeger"
+	Kibana               = "kibana"
+	Labels               = "labels"
+	MetricsetAggregation = "metricset-aggregation"
+	Otel                 = "otel"
+	OutputHealth         = "output-health"
+	Pipelines            = "pipelines"
+	PolicyFiles          = "policy-files"
+	Quota                = "quota"
+	Request              = "request"
+	Response             = "response"
+	Sampling             = "sampling"
+	Server               = "server"
+	Sourcemap            = "sourcemap"
+	SpanMetrics          = "spanmetrics"
+	Spool                = "spool"
+	Stacktrace           = "stacktrace"
+	TraceSummary         = "tracesummary"
+	Tracing              = "tracing"
+	TransactionMetrics   = "txmetrics"
+	Transform            = "transform"
+	Validation           = "validation"
 )
@@ -17,7 +17,8 @@
 
 package main
 
-//go:generate go run script/inline_schemas/inline_schemas.go
+//go:generate go run ./script/inline_schemas
+//go:generate go run script/generate_decoders/generate_decoders.go
 
 import (
 	"os"
@@ -29,7 +29,21 @@ type Batch struct {
 }
 
 // Reset resets the batch to be empty, but it retains the underlying storage.
+// Events are cleared from the storage, so they may be garbage collected
+// once they are no longer referenced elsewhere.
 func (b *Batch) Reset() {
+	for i := range b.Transactions {
+		b.Transactions[i] = nil
+	}
+	for i := range b.Spans {
+		b.Spans[i] = nil
+	}
+	for i := range b.Metricsets {
+		b.Metricsets[i] = nil
+	}
+	for i := range b.Errors {
+		b.Errors[i] = nil
+	}
 	b.Transactions = b.Transactions[:0]
 	b.Spans = b.Spans[:0]
 	b.Metricsets = b.Metricsets[:0]
@@ -0,0 +1,54 @@
+// Licensed to Elasticsearch B.V. under one or more contributor
+// license agreements. See the NOTICE file distributed with
+// this work for additional information regarding copyright
+// ownership. Elasticsearch B.V. licenses this file to you under
+// the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package model
+
+import (
+	"context"
+)
+
+// BatchProcessor can be used to process a batch of events, giving the
+// opportunity to update, add or remove events.
+type BatchProcessor interface {
+	// Process processes a batch of events, modifying it in place.
+	//
+	// Returning an error causes the batch, and the request that
+	// produced it, to fail.
+	Process(context.Context, *Batch) error
+}
+
+// ProcessBatchFunc is a function type that implements BatchProcessor.
+type ProcessBatchFunc func(context.Context, *Batch) error
+
+// Process calls f(ctx, b).
+func (f ProcessBatchFunc) Process(ctx context.Context, b *Batch) error {
+	return f(ctx, b)
+}
+
+// BatchProcessors is a BatchProcessor which calls each processor in turn,
+// stopping at the first error.
+type BatchProcessors []BatchProcessor
+
+// Process calls each processor in turn, returning the first error.
+func (processors BatchProcessors) Process(ctx context.Context, b *Batch) error {
+	for _, p := range processors {
+		if err := p.Process(ctx, b); err != nil {
+			return err
+		}
+	}
+	return nil
+}
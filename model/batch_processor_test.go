@@ -0,0 +1,46 @@
+// Licensed to Elasticsearch B.V. under one or more contributor
+// license agreements. See the NOTICE file distributed with
+// this work for additional information regarding copyright
+// ownership. Elasticsearch B.V. licenses this file to you under
+// the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package model
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestBatchProcessors(t *testing.T) {
+	var calls []string
+	processor := func(name string, err error) BatchProcessor {
+		return ProcessBatchFunc(func(ctx context.Context, b *Batch) error {
+			calls = append(calls, name)
+			return err
+		})
+	}
+
+	processors := BatchProcessors{processor("a", nil), processor("b", nil)}
+	assert.NoError(t, processors.Process(context.Background(), &Batch{}))
+	assert.Equal(t, []string{"a", "b"}, calls)
+
+	calls = nil
+	processorErr := errors.New("boom")
+	processors = BatchProcessors{processor("a", processorErr), processor("b", nil)}
+	assert.Equal(t, processorErr, processors.Process(context.Background(), &Batch{}))
+	assert.Equal(t, []string{"a"}, calls)
+}
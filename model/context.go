@@ -178,8 +178,9 @@ func (h *Http) Fields() common.MapStr {
 
 	fields := common.MapStr{}
 	utility.Set(fields, "version", h.Version)
-	utility.Set(fields, "request", h.Request.fields())
-	utility.Set(fields, "response", h.Response.fields())
+	out := (*mapStr)(&fields)
+	out.maybeSetMapStr("request", h.Request.fields())
+	out.maybeSetMapStr("response", h.Response.fields())
 	return fields
 }
 
@@ -233,8 +234,9 @@ func (req *Req) fields() common.MapStr {
 		return nil
 	}
 	fields := common.MapStr{}
-	utility.Set(fields, "headers", headerToFields(req.Headers))
-	utility.Set(fields, "socket", req.Socket.fields())
+	out := (*mapStr)(&fields)
+	out.maybeSetMapStr("headers", headerToFields(req.Headers))
+	out.maybeSetMapStr("socket", req.Socket.fields())
 	utility.Set(fields, "env", req.Env)
 	utility.DeepUpdate(fields, "body.original", req.Body)
 	utility.Set(fields, "method", req.Method)
@@ -261,7 +263,7 @@ func (m *MinimalResp) Fields() common.MapStr {
 		return nil
 	}
 	fields := common.MapStr{}
-	utility.Set(fields, "headers", headerToFields(m.Headers))
+	(*mapStr)(&fields).maybeSetMapStr("headers", headerToFields(m.Headers))
 	utility.Set(fields, "status_code", m.StatusCode)
 	utility.Set(fields, "transfer_size", m.TransferSize)
 	utility.Set(fields, "encoded_body_size", m.EncodedBodySize)
@@ -42,6 +42,8 @@ var (
 	errorStacktraceCounter = monitoring.NewInt(errorMetrics, "stacktraces")
 	errorFrameCounter      = monitoring.NewInt(errorMetrics, "frames")
 	errorProcessorEntry    = common.MapStr{"name": errorProcessorName, "event": errorDocType}
+
+	groupingKeyTemplateErrors = monitoring.NewInt(errorMetrics, "grouping_key.template_errors")
 )
 
 const (
@@ -105,49 +107,57 @@ func (e *Error) Transform(ctx context.Context, tctx *transform.Context) []beat.E
 		addStacktraceCounter(e.Log.Stacktrace)
 	}
 
-	fields := common.MapStr{
+	// Sourcemaps are looked up for the tenant the error was ingested for.
+	ctx = e.Metadata.tenantContext(ctx)
+	fields := mapStr{
 		"error":     e.fields(ctx, tctx),
 		"processor": errorProcessorEntry,
 	}
 
 	// first set the generic metadata (order is relevant)
-	e.Metadata.Set(fields)
-	utility.Set(fields, "source", fields["client"])
+	e.Metadata.Set(common.MapStr(fields))
+	if client, ok := fields["client"]; ok {
+		fields.set("source", client)
+	}
 	// then add event specific information
 	// merges with metadata labels, overrides conflicting keys
-	utility.DeepUpdate(fields, "labels", e.Labels.Fields())
-	utility.Set(fields, "http", e.HTTP.Fields())
+	fields.mergeLabels(e.Labels.Fields())
+	fields.maybeSetMapStr("http", e.HTTP.Fields())
 	urlFields := e.URL.Fields()
 	if urlFields != nil {
-		utility.Set(fields, "url", e.URL.Fields())
+		fields.maybeSetMapStr("url", urlFields)
 	}
 	if e.Page != nil {
-		utility.DeepUpdate(fields, "http.request.referrer", e.Page.Referer)
+		if e.Page.Referer != nil {
+			common.MapStr(fields).Put("http.request.referrer", *e.Page.Referer)
+		}
 		if urlFields == nil {
-			utility.Set(fields, "url", e.Page.URL.Fields())
+			fields.maybeSetMapStr("url", e.Page.URL.Fields())
 		}
 	}
-	utility.Set(fields, "experimental", e.Experimental)
+	utility.Set(common.MapStr(fields), "experimental", e.Experimental)
 
 	// sampled and type is nil if an error happens outside a transaction or an (old) agent is not sending sampled info
 	// agents must send semantically correct data
 	if e.TransactionSampled != nil || e.TransactionType != nil || e.TransactionID != "" {
-		transaction := common.MapStr{}
-		if e.TransactionID != "" {
-			transaction["id"] = e.TransactionID
+		var transaction mapStr
+		transaction.maybeSetString("id", e.TransactionID)
+		if e.TransactionType != nil {
+			transaction.set("type", *e.TransactionType)
+		}
+		if e.TransactionSampled != nil {
+			transaction.set("sampled", *e.TransactionSampled)
 		}
-		utility.Set(transaction, "type", e.TransactionType)
-		utility.Set(transaction, "sampled", e.TransactionSampled)
-		utility.Set(fields, "transaction", transaction)
+		fields.maybeSetMapStr("transaction", common.MapStr(transaction))
 	}
 
-	utility.AddID(fields, "parent", e.ParentID)
-	utility.AddID(fields, "trace", e.TraceID)
-	utility.Set(fields, "timestamp", utility.TimeAsMicros(e.Timestamp))
+	utility.AddID(common.MapStr(fields), "parent", e.ParentID)
+	utility.AddID(common.MapStr(fields), "trace", e.TraceID)
+	fields.maybeSetMapStr("timestamp", utility.TimeAsMicros(e.Timestamp))
 
 	return []beat.Event{
 		{
-			Fields:    fields,
+			Fields:    common.MapStr(fields),
 			Timestamp: e.Timestamp,
 		},
 	}
@@ -166,21 +176,36 @@ func (e *Error) fields(ctx context.Context, tctx *transform.Context) common.MapS
 	e.add("culprit", e.Culprit)
 	e.add("custom", e.Custom.Fields())
 
-	e.add("grouping_key", e.calcGroupingKey(exceptionChain))
+	groupingKey, ok := "", false
+	if tmpl := tctx.Config.GroupingKeyTemplate; tmpl != nil {
+		groupingKey, ok = e.templateGroupingKey(tmpl, exceptionChain)
+	}
+	if !ok {
+		groupingKey = e.calcGroupingKey(exceptionChain)
+	}
+	e.add("grouping_key", groupingKey)
 
 	return e.data
 }
 
 func (e *Error) updateCulprit(tctx *transform.Context) {
-	if tctx.Config.SourcemapStore == nil {
-		return
-	}
 	var fr *StacktraceFrame
-	if e.Log != nil {
-		fr = findSmappedNonLibraryFrame(e.Log.Stacktrace)
-	}
-	if fr == nil && e.Exception != nil {
-		fr = findSmappedNonLibraryFrame(e.Exception.Stacktrace)
+	switch {
+	case tctx.Config.SourcemapStore != nil:
+		if e.Log != nil {
+			fr = findSmappedNonLibraryFrame(e.Log.Stacktrace)
+		}
+		if fr == nil && e.Exception != nil {
+			fr = findSmappedNonLibraryFrame(e.Exception.Stacktrace)
+		}
+	case tctx.Config.BackendLibraryPattern != nil:
+		// Only override the agent's culprit if the server
+		// has reclassified any of the frames it may be based on.
+		if e.Log != nil && hasMarkedLibraryFrame(e.Log.Stacktrace) {
+			fr = findNonLibraryFrame(e.Log.Stacktrace)
+		} else if e.Exception != nil && hasMarkedLibraryFrame(e.Exception.Stacktrace) {
+			fr = findNonLibraryFrame(e.Exception.Stacktrace)
+		}
 	}
 	if fr == nil {
 		return
@@ -206,6 +231,24 @@ func findSmappedNonLibraryFrame(frames []*StacktraceFrame) *StacktraceFrame {
 	return nil
 }
 
+func findNonLibraryFrame(frames []*StacktraceFrame) *StacktraceFrame {
+	for _, fr := range frames {
+		if !fr.IsLibraryFrame() {
+			return fr
+		}
+	}
+	return nil
+}
+
+func hasMarkedLibraryFrame(frames []*StacktraceFrame) bool {
+	for _, fr := range frames {
+		if fr.libraryFrameMarked {
+			return true
+		}
+	}
+	return false
+}
+
 func (e *Error) addException(ctx context.Context, tctx *transform.Context, chain []Exception) {
 	var result []common.MapStr
 	for _, exception := range chain {
@@ -0,0 +1,1915 @@
+// Licensed to Elasticsearch B.V. under one or more contributor
+// license agreements. See the NOTICE file distributed with
+// this work for additional information regarding copyright
+// ownership. Elasticsearch B.V. licenses this file to you under
+// the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+// Code generated by script/inline_schemas. DO NOT EDIT.
+
+package schema
+
+import (
+	"regexp"
+
+	"github.com/santhosh-tekuri/jsonschema"
+)
+
+// CompiledModelSchema returns the compiled form of ModelSchema.
+func CompiledModelSchema() *jsonschema.Schema {
+	s := make([]jsonschema.Schema, 164)
+	s[0] = jsonschema.Schema{
+		URL:           "error",
+		Ptr:           "#",
+		Types:         []string{"object"},
+		AllOf:         []*jsonschema.Schema{&s[1], &s[3]},
+		MinProperties: -1,
+		MaxProperties: -1,
+		MinItems:      -1,
+		MaxItems:      -1,
+		MinLength:     -1,
+		MaxLength:     -1,
+	}
+	s[1] = jsonschema.Schema{
+		URL:           "docs/spec/errors/error.json",
+		Types:         []string{"object"},
+		MinProperties: -1,
+		MaxProperties: -1,
+		Properties: map[string]*jsonschema.Schema{
+			"timestamp": &s[2],
+		},
+		MinItems:  -1,
+		MaxItems:  -1,
+		MinLength: -1,
+		MaxLength: -1,
+	}
+	s[2] = jsonschema.Schema{
+		URL:           "docs/spec/errors/docs/spec/timestamp_epoch.json",
+		Types:         []string{"integer", "null"},
+		MinProperties: -1,
+		MaxProperties: -1,
+		MinItems:      -1,
+		MaxItems:      -1,
+		MinLength:     -1,
+		MaxLength:     -1,
+	}
+	s[3] = jsonschema.Schema{
+		URL:           "docs/spec/errors/error.json",
+		AllOf:         []*jsonschema.Schema{&s[4], &s[5], &s[11], &s[16]},
+		AnyOf:         []*jsonschema.Schema{&s[21], &s[23]},
+		MinProperties: -1,
+		MaxProperties: -1,
+		Properties: map[string]*jsonschema.Schema{
+			"context":        &s[25],
+			"culprit":        &s[98],
+			"exception":      &s[99],
+			"id":             &s[132],
+			"log":            &s[133],
+			"parent_id":      &s[158],
+			"trace_id":       &s[159],
+			"transaction":    &s[160],
+			"transaction_id": &s[163],
+		},
+		MinItems:  -1,
+		MaxItems:  -1,
+		MinLength: -1,
+		MaxLength: -1,
+	}
+	s[4] = jsonschema.Schema{
+		URL:           "docs/spec/errors/error.json",
+		MinProperties: -1,
+		MaxProperties: -1,
+		Required:      []string{"id"},
+		MinItems:      -1,
+		MaxItems:      -1,
+		MinLength:     -1,
+		MaxLength:     -1,
+	}
+	s[5] = jsonschema.Schema{
+		URL:           "docs/spec/errors/error.json",
+		If:            &s[6],
+		Then:          &s[8],
+		MinProperties: -1,
+		MaxProperties: -1,
+		MinItems:      -1,
+		MaxItems:      -1,
+		MinLength:     -1,
+		MaxLength:     -1,
+	}
+	s[6] = jsonschema.Schema{
+		URL:           "docs/spec/errors/error.json",
+		MinProperties: -1,
+		MaxProperties: -1,
+		Required:      []string{"transaction_id"},
+		Properties: map[string]*jsonschema.Schema{
+			"transaction_id": &s[7],
+		},
+		MinItems:  -1,
+		MaxItems:  -1,
+		MinLength: -1,
+		MaxLength: -1,
+	}
+	s[7] = jsonschema.Schema{
+		URL:           "docs/spec/errors/error.json",
+		Types:         []string{"string"},
+		MinProperties: -1,
+		MaxProperties: -1,
+		MinItems:      -1,
+		MaxItems:      -1,
+		MinLength:     -1,
+		MaxLength:     -1,
+	}
+	s[8] = jsonschema.Schema{
+		URL:           "docs/spec/errors/error.json",
+		MinProperties: -1,
+		MaxProperties: -1,
+		Required:      []string{"trace_id", "parent_id"},
+		Properties: map[string]*jsonschema.Schema{
+			"parent_id": &s[9],
+			"trace_id":  &s[10],
+		},
+		MinItems:  -1,
+		MaxItems:  -1,
+		MinLength: -1,
+		MaxLength: -1,
+	}
+	s[9] = jsonschema.Schema{
+		URL:           "docs/spec/errors/error.json",
+		Types:         []string{"string"},
+		MinProperties: -1,
+		MaxProperties: -1,
+		MinItems:      -1,
+		MaxItems:      -1,
+		MinLength:     -1,
+		MaxLength:     -1,
+	}
+	s[10] = jsonschema.Schema{
+		URL:           "docs/spec/errors/error.json",
+		Types:         []string{"string"},
+		MinProperties: -1,
+		MaxProperties: -1,
+		MinItems:      -1,
+		MaxItems:      -1,
+		MinLength:     -1,
+		MaxLength:     -1,
+	}
+	s[11] = jsonschema.Schema{
+		URL:           "docs/spec/errors/error.json",
+		If:            &s[12],
+		Then:          &s[14],
+		MinProperties: -1,
+		MaxProperties: -1,
+		MinItems:      -1,
+		MaxItems:      -1,
+		MinLength:     -1,
+		MaxLength:     -1,
+	}
+	s[12] = jsonschema.Schema{
+		URL:           "docs/spec/errors/error.json",
+		MinProperties: -1,
+		MaxProperties: -1,
+		Required:      []string{"trace_id"},
+		Properties: map[string]*jsonschema.Schema{
+			"trace_id": &s[13],
+		},
+		MinItems:  -1,
+		MaxItems:  -1,
+		MinLength: -1,
+		MaxLength: -1,
+	}
+	s[13] = jsonschema.Schema{
+		URL:           "docs/spec/errors/error.json",
+		Types:         []string{"string"},
+		MinProperties: -1,
+		MaxProperties: -1,
+		MinItems:      -1,
+		MaxItems:      -1,
+		MinLength:     -1,
+		MaxLength:     -1,
+	}
+	s[14] = jsonschema.Schema{
+		URL:           "docs/spec/errors/error.json",
+		MinProperties: -1,
+		MaxProperties: -1,
+		Required:      []string{"parent_id"},
+		Properties: map[string]*jsonschema.Schema{
+			"parent_id": &s[15],
+		},
+		MinItems:  -1,
+		MaxItems:  -1,
+		MinLength: -1,
+		MaxLength: -1,
+	}
+	s[15] = jsonschema.Schema{
+		URL:           "docs/spec/errors/error.json",
+		Types:         []string{"string"},
+		MinProperties: -1,
+		MaxProperties: -1,
+		MinItems:      -1,
+		MaxItems:      -1,
+		MinLength:     -1,
+		MaxLength:     -1,
+	}
+	s[16] = jsonschema.Schema{
+		URL:           "docs/spec/errors/error.json",
+		If:            &s[17],
+		Then:          &s[19],
+		MinProperties: -1,
+		MaxProperties: -1,
+		MinItems:      -1,
+		MaxItems:      -1,
+		MinLength:     -1,
+		MaxLength:     -1,
+	}
+	s[17] = jsonschema.Schema{
+		URL:           "docs/spec/errors/error.json",
+		MinProperties: -1,
+		MaxProperties: -1,
+		Required:      []string{"parent_id"},
+		Properties: map[string]*jsonschema.Schema{
+			"parent_id": &s[18],
+		},
+		MinItems:  -1,
+		MaxItems:  -1,
+		MinLength: -1,
+		MaxLength: -1,
+	}
+	s[18] = jsonschema.Schema{
+		URL:           "docs/spec/errors/error.json",
+		Types:         []string{"string"},
+		MinProperties: -1,
+		MaxProperties: -1,
+		MinItems:      -1,
+		MaxItems:      -1,
+		MinLength:     -1,
+		MaxLength:     -1,
+	}
+	s[19] = jsonschema.Schema{
+		URL:           "docs/spec/errors/error.json",
+		MinProperties: -1,
+		MaxProperties: -1,
+		Required:      []string{"trace_id"},
+		Properties: map[string]*jsonschema.Schema{
+			"trace_id": &s[20],
+		},
+		MinItems:  -1,
+		MaxItems:  -1,
+		MinLength: -1,
+		MaxLength: -1,
+	}
+	s[20] = jsonschema.Schema{
+		URL:           "docs/spec/errors/error.json",
+		Types:         []string{"string"},
+		MinProperties: -1,
+		MaxProperties: -1,
+		MinItems:      -1,
+		MaxItems:      -1,
+		MinLength:     -1,
+		MaxLength:     -1,
+	}
+	s[21] = jsonschema.Schema{
+		URL:           "docs/spec/errors/error.json",
+		MinProperties: -1,
+		MaxProperties: -1,
+		Required:      []string{"exception"},
+		Properties: map[string]*jsonschema.Schema{
+			"exception": &s[22],
+		},
+		MinItems:  -1,
+		MaxItems:  -1,
+		MinLength: -1,
+		MaxLength: -1,
+	}
+	s[22] = jsonschema.Schema{
+		URL:           "docs/spec/errors/error.json",
+		Types:         []string{"object"},
+		MinProperties: -1,
+		MaxProperties: -1,
+		MinItems:      -1,
+		MaxItems:      -1,
+		MinLength:     -1,
+		MaxLength:     -1,
+	}
+	s[23] = jsonschema.Schema{
+		URL:           "docs/spec/errors/error.json",
+		MinProperties: -1,
+		MaxProperties: -1,
+		Required:      []string{"log"},
+		Properties: map[string]*jsonschema.Schema{
+			"log": &s[24],
+		},
+		MinItems:  -1,
+		MaxItems:  -1,
+		MinLength: -1,
+		MaxLength: -1,
+	}
+	s[24] = jsonschema.Schema{
+		URL:           "docs/spec/errors/error.json",
+		Types:         []string{"object"},
+		MinProperties: -1,
+		MaxProperties: -1,
+		MinItems:      -1,
+		MaxItems:      -1,
+		MinLength:     -1,
+		MaxLength:     -1,
+	}
+	s[25] = jsonschema.Schema{
+		URL:           "docs/spec/errors/error.json",
+		Types:         []string{"object", "null"},
+		MinProperties: -1,
+		MaxProperties: -1,
+		Properties: map[string]*jsonschema.Schema{
+			"custom":   &s[26],
+			"message":  &s[28],
+			"page":     &s[37],
+			"request":  &s[40],
+			"response": &s[61],
+			"service":  &s[73],
+			"tags":     &s[92],
+			"user":     &s[94],
+		},
+		MinItems:  -1,
+		MaxItems:  -1,
+		MinLength: -1,
+		MaxLength: -1,
+	}
+	s[26] = jsonschema.Schema{
+		URL:           "docs/spec/errors/docs/spec/context.json",
+		Types:         []string{"object", "null"},
+		MinProperties: -1,
+		MaxProperties: -1,
+		PatternProperties: map[*regexp.Regexp]*jsonschema.Schema{
+			regexp.MustCompile("^[^.*\"]*$"): &s[27],
+		},
+		AdditionalProperties: false,
+		MinItems:             -1,
+		MaxItems:             -1,
+		MinLength:            -1,
+		MaxLength:            -1,
+	}
+	s[27] = jsonschema.Schema{
+		URL:           "docs/spec/errors/docs/spec/context.json",
+		MinProperties: -1,
+		MaxProperties: -1,
+		MinItems:      -1,
+		MaxItems:      -1,
+		MinLength:     -1,
+		MaxLength:     -1,
+	}
+	s[28] = jsonschema.Schema{
+		URL:           "docs/spec/errors/docs/spec/context.json",
+		Types:         []string{"object", "null"},
+		MinProperties: -1,
+		MaxProperties: -1,
+		Properties: map[string]*jsonschema.Schema{
+			"age":     &s[29],
+			"body":    &s[31],
+			"headers": &s[32],
+			"queue":   &s[35],
+		},
+		MinItems:  -1,
+		MaxItems:  -1,
+		MinLength: -1,
+		MaxLength: -1,
+	}
+	s[29] = jsonschema.Schema{
+		URL:           "docs/spec/errors/docs/spec/docs/spec/message.json",
+		Types:         []string{"object", "null"},
+		MinProperties: -1,
+		MaxProperties: -1,
+		Properties: map[string]*jsonschema.Schema{
+			"ms": &s[30],
+		},
+		MinItems:  -1,
+		MaxItems:  -1,
+		MinLength: -1,
+		MaxLength: -1,
+	}
+	s[30] = jsonschema.Schema{
+		URL:           "docs/spec/errors/docs/spec/docs/spec/message.json",
+		Types:         []string{"integer", "null"},
+		MinProperties: -1,
+		MaxProperties: -1,
+		MinItems:      -1,
+		MaxItems:      -1,
+		MinLength:     -1,
+		MaxLength:     -1,
+	}
+	s[31] = jsonschema.Schema{
+		URL:           "docs/spec/errors/docs/spec/docs/spec/message.json",
+		Types:         []string{"string", "null"},
+		MinProperties: -1,
+		MaxProperties: -1,
+		MinItems:      -1,
+		MaxItems:      -1,
+		MinLength:     -1,
+		MaxLength:     -1,
+	}
+	s[32] = jsonschema.Schema{
+		URL:           "docs/spec/errors/docs/spec/docs/spec/message.json",
+		Types:         []string{"object", "null"},
+		MinProperties: -1,
+		MaxProperties: -1,
+		PatternProperties: map[*regexp.Regexp]*jsonschema.Schema{
+			regexp.MustCompile("[.*]*$"): &s[33],
+		},
+		MinItems:  -1,
+		MaxItems:  -1,
+		MinLength: -1,
+		MaxLength: -1,
+	}
+	s[33] = jsonschema.Schema{
+		URL:           "docs/spec/errors/docs/spec/docs/spec/message.json",
+		Types:         []string{"string", "array", "null"},
+		MinProperties: -1,
+		MaxProperties: -1,
+		MinItems:      -1,
+		MaxItems:      -1,
+		Items:         &s[34],
+		MinLength:     -1,
+		MaxLength:     -1,
+	}
+	s[34] = jsonschema.Schema{
+		URL:           "docs/spec/errors/docs/spec/docs/spec/message.json",
+		Types:         []string{"string"},
+		MinProperties: -1,
+		MaxProperties: -1,
+		MinItems:      -1,
+		MaxItems:      -1,
+		MinLength:     -1,
+		MaxLength:     -1,
+	}
+	s[35] = jsonschema.Schema{
+		URL:           "docs/spec/errors/docs/spec/docs/spec/message.json",
+		Types:         []string{"object", "null"},
+		MinProperties: -1,
+		MaxProperties: -1,
+		Properties: map[string]*jsonschema.Schema{
+			"name": &s[36],
+		},
+		MinItems:  -1,
+		MaxItems:  -1,
+		MinLength: -1,
+		MaxLength: -1,
+	}
+	s[36] = jsonschema.Schema{
+		URL:           "docs/spec/errors/docs/spec/docs/spec/message.json",
+		Types:         []string{"string", "null"},
+		MinProperties: -1,
+		MaxProperties: -1,
+		MinItems:      -1,
+		MaxItems:      -1,
+		MinLength:     -1,
+		MaxLength:     1024,
+	}
+	s[37] = jsonschema.Schema{
+		URL:           "docs/spec/errors/docs/spec/context.json",
+		Types:         []string{"object", "null"},
+		MinProperties: -1,
+		MaxProperties: -1,
+		Properties: map[string]*jsonschema.Schema{
+			"referer": &s[38],
+			"url":     &s[39],
+		},
+		MinItems:  -1,
+		MaxItems:  -1,
+		MinLength: -1,
+		MaxLength: -1,
+	}
+	s[38] = jsonschema.Schema{
+		URL:           "docs/spec/errors/docs/spec/context.json",
+		Types:         []string{"string", "null"},
+		MinProperties: -1,
+		MaxProperties: -1,
+		MinItems:      -1,
+		MaxItems:      -1,
+		MinLength:     -1,
+		MaxLength:     -1,
+	}
+	s[39] = jsonschema.Schema{
+		URL:           "docs/spec/errors/docs/spec/context.json",
+		Types:         []string{"string", "null"},
+		MinProperties: -1,
+		MaxProperties: -1,
+		MinItems:      -1,
+		MaxItems:      -1,
+		MinLength:     -1,
+		MaxLength:     -1,
+	}
+	s[40] = jsonschema.Schema{
+		URL:           "docs/spec/errors/docs/spec/context.json",
+		Types:         []string{"object", "null"},
+		MinProperties: -1,
+		MaxProperties: -1,
+		Required:      []string{"url", "method"},
+		Properties: map[string]*jsonschema.Schema{
+			"body":         &s[41],
+			"cookies":      &s[42],
+			"env":          &s[43],
+			"headers":      &s[44],
+			"http_version": &s[47],
+			"method":       &s[48],
+			"socket":       &s[49],
+			"url":          &s[52],
+		},
+		MinItems:  -1,
+		MaxItems:  -1,
+		MinLength: -1,
+		MaxLength: -1,
+	}
+	s[41] = jsonschema.Schema{
+		URL:           "docs/spec/errors/docs/spec/docs/spec/request.json",
+		Types:         []string{"object", "string", "null"},
+		MinProperties: -1,
+		MaxProperties: -1,
+		MinItems:      -1,
+		MaxItems:      -1,
+		MinLength:     -1,
+		MaxLength:     -1,
+	}
+	s[42] = jsonschema.Schema{
+		URL:           "docs/spec/errors/docs/spec/docs/spec/request.json",
+		Types:         []string{"object", "null"},
+		MinProperties: -1,
+		MaxProperties: -1,
+		MinItems:      -1,
+		MaxItems:      -1,
+		MinLength:     -1,
+		MaxLength:     -1,
+	}
+	s[43] = jsonschema.Schema{
+		URL:           "docs/spec/errors/docs/spec/docs/spec/request.json",
+		Types:         []string{"object", "null"},
+		MinProperties: -1,
+		MaxProperties: -1,
+		Properties:    map[string]*jsonschema.Schema{},
+		MinItems:      -1,
+		MaxItems:      -1,
+		MinLength:     -1,
+		MaxLength:     -1,
+	}
+	s[44] = jsonschema.Schema{
+		URL:           "docs/spec/errors/docs/spec/docs/spec/request.json",
+		Types:         []string{"object", "null"},
+		MinProperties: -1,
+		MaxProperties: -1,
+		PatternProperties: map[*regexp.Regexp]*jsonschema.Schema{
+			regexp.MustCompile("[.*]*$"): &s[45],
+		},
+		MinItems:  -1,
+		MaxItems:  -1,
+		MinLength: -1,
+		MaxLength: -1,
+	}
+	s[45] = jsonschema.Schema{
+		URL:           "docs/spec/errors/docs/spec/docs/spec/request.json",
+		Types:         []string{"string", "array", "null"},
+		MinProperties: -1,
+		MaxProperties: -1,
+		MinItems:      -1,
+		MaxItems:      -1,
+		Items:         &s[46],
+		MinLength:     -1,
+		MaxLength:     -1,
+	}
+	s[46] = jsonschema.Schema{
+		URL:           "docs/spec/errors/docs/spec/docs/spec/request.json",
+		Types:         []string{"string"},
+		MinProperties: -1,
+		MaxProperties: -1,
+		MinItems:      -1,
+		MaxItems:      -1,
+		MinLength:     -1,
+		MaxLength:     -1,
+	}
+	s[47] = jsonschema.Schema{
+		URL:           "docs/spec/errors/docs/spec/docs/spec/request.json",
+		Types:         []string{"string", "null"},
+		MinProperties: -1,
+		MaxProperties: -1,
+		MinItems:      -1,
+		MaxItems:      -1,
+		MinLength:     -1,
+		MaxLength:     1024,
+	}
+	s[48] = jsonschema.Schema{
+		URL:           "docs/spec/errors/docs/spec/docs/spec/request.json",
+		Types:         []string{"string"},
+		MinProperties: -1,
+		MaxProperties: -1,
+		MinItems:      -1,
+		MaxItems:      -1,
+		MinLength:     -1,
+		MaxLength:     1024,
+	}
+	s[49] = jsonschema.Schema{
+		URL:           "docs/spec/errors/docs/spec/docs/spec/request.json",
+		Types:         []string{"object", "null"},
+		MinProperties: -1,
+		MaxProperties: -1,
+		Properties: map[string]*jsonschema.Schema{
+			"encrypted":      &s[50],
+			"remote_address": &s[51],
+		},
+		MinItems:  -1,
+		MaxItems:  -1,
+		MinLength: -1,
+		MaxLength: -1,
+	}
+	s[50] = jsonschema.Schema{
+		URL:           "docs/spec/errors/docs/spec/docs/spec/request.json",
+		Types:         []string{"boolean", "null"},
+		MinProperties: -1,
+		MaxProperties: -1,
+		MinItems:      -1,
+		MaxItems:      -1,
+		MinLength:     -1,
+		MaxLength:     -1,
+	}
+	s[51] = jsonschema.Schema{
+		URL:           "docs/spec/errors/docs/spec/docs/spec/request.json",
+		Types:         []string{"string", "null"},
+		MinProperties: -1,
+		MaxProperties: -1,
+		MinItems:      -1,
+		MaxItems:      -1,
+		MinLength:     -1,
+		MaxLength:     -1,
+	}
+	s[52] = jsonschema.Schema{
+		URL:           "docs/spec/errors/docs/spec/docs/spec/request.json",
+		Types:         []string{"object"},
+		MinProperties: -1,
+		MaxProperties: -1,
+		Properties: map[string]*jsonschema.Schema{
+			"full":     &s[53],
+			"hash":     &s[54],
+			"hostname": &s[55],
+			"pathname": &s[56],
+			"port":     &s[57],
+			"protocol": &s[58],
+			"raw":      &s[59],
+			"search":   &s[60],
+		},
+		MinItems:  -1,
+		MaxItems:  -1,
+		MinLength: -1,
+		MaxLength: -1,
+	}
+	s[53] = jsonschema.Schema{
+		URL:           "docs/spec/errors/docs/spec/docs/spec/request.json",
+		Types:         []string{"string", "null"},
+		MinProperties: -1,
+		MaxProperties: -1,
+		MinItems:      -1,
+		MaxItems:      -1,
+		MinLength:     -1,
+		MaxLength:     1024,
+	}
+	s[54] = jsonschema.Schema{
+		URL:           "docs/spec/errors/docs/spec/docs/spec/request.json",
+		Types:         []string{"string", "null"},
+		MinProperties: -1,
+		MaxProperties: -1,
+		MinItems:      -1,
+		MaxItems:      -1,
+		MinLength:     -1,
+		MaxLength:     1024,
+	}
+	s[55] = jsonschema.Schema{
+		URL:           "docs/spec/errors/docs/spec/docs/spec/request.json",
+		Types:         []string{"string", "null"},
+		MinProperties: -1,
+		MaxProperties: -1,
+		MinItems:      -1,
+		MaxItems:      -1,
+		MinLength:     -1,
+		MaxLength:     1024,
+	}
+	s[56] = jsonschema.Schema{
+		URL:           "docs/spec/errors/docs/spec/docs/spec/request.json",
+		Types:         []string{"string", "null"},
+		MinProperties: -1,
+		MaxProperties: -1,
+		MinItems:      -1,
+		MaxItems:      -1,
+		MinLength:     -1,
+		MaxLength:     1024,
+	}
+	s[57] = jsonschema.Schema{
+		URL:           "docs/spec/errors/docs/spec/docs/spec/request.json",
+		Types:         []string{"string", "integer", "null"},
+		MinProperties: -1,
+		MaxProperties: -1,
+		MinItems:      -1,
+		MaxItems:      -1,
+		MinLength:     -1,
+		MaxLength:     1024,
+	}
+	s[58] = jsonschema.Schema{
+		URL:           "docs/spec/errors/docs/spec/docs/spec/request.json",
+		Types:         []string{"string", "null"},
+		MinProperties: -1,
+		MaxProperties: -1,
+		MinItems:      -1,
+		MaxItems:      -1,
+		MinLength:     -1,
+		MaxLength:     1024,
+	}
+	s[59] = jsonschema.Schema{
+		URL:           "docs/spec/errors/docs/spec/docs/spec/request.json",
+		Types:         []string{"string", "null"},
+		MinProperties: -1,
+		MaxProperties: -1,
+		MinItems:      -1,
+		MaxItems:      -1,
+		MinLength:     -1,
+		MaxLength:     1024,
+	}
+	s[60] = jsonschema.Schema{
+		URL:           "docs/spec/errors/docs/spec/docs/spec/request.json",
+		Types:         []string{"string", "null"},
+		MinProperties: -1,
+		MaxProperties: -1,
+		MinItems:      -1,
+		MaxItems:      -1,
+		MinLength:     -1,
+		MaxLength:     1024,
+	}
+	s[61] = jsonschema.Schema{
+		URL:           "docs/spec/errors/docs/spec/context.json",
+		Types:         []string{"object", "null"},
+		AllOf:         []*jsonschema.Schema{&s[62], &s[70]},
+		MinProperties: -1,
+		MaxProperties: -1,
+		MinItems:      -1,
+		MaxItems:      -1,
+		MinLength:     -1,
+		MaxLength:     -1,
+	}
+	s[62] = jsonschema.Schema{
+		URL:           "docs/spec/errors/docs/spec/context.json",
+		Types:         []string{"object", "null"},
+		MinProperties: -1,
+		MaxProperties: -1,
+		Properties: map[string]*jsonschema.Schema{
+			"decoded_body_size": &s[63],
+			"encoded_body_size": &s[64],
+			"headers":           &s[65],
+			"status_code":       &s[68],
+			"transfer_size":     &s[69],
+		},
+		MinItems:  -1,
+		MaxItems:  -1,
+		MinLength: -1,
+		MaxLength: -1,
+	}
+	s[63] = jsonschema.Schema{
+		URL:           "docs/spec/errors/docs/spec/docs/spec/http_response.json",
+		Types:         []string{"number", "null"},
+		MinProperties: -1,
+		MaxProperties: -1,
+		MinItems:      -1,
+		MaxItems:      -1,
+		MinLength:     -1,
+		MaxLength:     -1,
+	}
+	s[64] = jsonschema.Schema{
+		URL:           "docs/spec/errors/docs/spec/docs/spec/http_response.json",
+		Types:         []string{"number", "null"},
+		MinProperties: -1,
+		MaxProperties: -1,
+		MinItems:      -1,
+		MaxItems:      -1,
+		MinLength:     -1,
+		MaxLength:     -1,
+	}
+	s[65] = jsonschema.Schema{
+		URL:           "docs/spec/errors/docs/spec/docs/spec/http_response.json",
+		Types:         []string{"object", "null"},
+		MinProperties: -1,
+		MaxProperties: -1,
+		PatternProperties: map[*regexp.Regexp]*jsonschema.Schema{
+			regexp.MustCompile("[.*]*$"): &s[66],
+		},
+		MinItems:  -1,
+		MaxItems:  -1,
+		MinLength: -1,
+		MaxLength: -1,
+	}
+	s[66] = jsonschema.Schema{
+		URL:           "docs/spec/errors/docs/spec/docs/spec/http_response.json",
+		Types:         []string{"string", "array", "null"},
+		MinProperties: -1,
+		MaxProperties: -1,
+		MinItems:      -1,
+		MaxItems:      -1,
+		Items:         &s[67],
+		MinLength:     -1,
+		MaxLength:     -1,
+	}
+	s[67] = jsonschema.Schema{
+		URL:           "docs/spec/errors/docs/spec/docs/spec/http_response.json",
+		Types:         []string{"string"},
+		MinProperties: -1,
+		MaxProperties: -1,
+		MinItems:      -1,
+		MaxItems:      -1,
+		MinLength:     -1,
+		MaxLength:     -1,
+	}
+	s[68] = jsonschema.Schema{
+		URL:           "docs/spec/errors/docs/spec/docs/spec/http_response.json",
+		Types:         []string{"integer", "null"},
+		MinProperties: -1,
+		MaxProperties: -1,
+		MinItems:      -1,
+		MaxItems:      -1,
+		MinLength:     -1,
+		MaxLength:     -1,
+	}
+	s[69] = jsonschema.Schema{
+		URL:           "docs/spec/errors/docs/spec/docs/spec/http_response.json",
+		Types:         []string{"number", "null"},
+		MinProperties: -1,
+		MaxProperties: -1,
+		MinItems:      -1,
+		MaxItems:      -1,
+		MinLength:     -1,
+		MaxLength:     -1,
+	}
+	s[70] = jsonschema.Schema{
+		URL:           "docs/spec/errors/docs/spec/context.json",
+		MinProperties: -1,
+		MaxProperties: -1,
+		Properties: map[string]*jsonschema.Schema{
+			"finished":     &s[71],
+			"headers_sent": &s[72],
+		},
+		MinItems:  -1,
+		MaxItems:  -1,
+		MinLength: -1,
+		MaxLength: -1,
+	}
+	s[71] = jsonschema.Schema{
+		URL:           "docs/spec/errors/docs/spec/context.json",
+		Types:         []string{"boolean", "null"},
+		MinProperties: -1,
+		MaxProperties: -1,
+		MinItems:      -1,
+		MaxItems:      -1,
+		MinLength:     -1,
+		MaxLength:     -1,
+	}
+	s[72] = jsonschema.Schema{
+		URL:           "docs/spec/errors/docs/spec/context.json",
+		Types:         []string{"boolean", "null"},
+		MinProperties: -1,
+		MaxProperties: -1,
+		MinItems:      -1,
+		MaxItems:      -1,
+		MinLength:     -1,
+		MaxLength:     -1,
+	}
+	s[73] = jsonschema.Schema{
+		URL:           "docs/spec/errors/docs/spec/context.json",
+		Types:         []string{"object", "null"},
+		MinProperties: -1,
+		MaxProperties: -1,
+		Properties: map[string]*jsonschema.Schema{
+			"agent":       &s[74],
+			"environment": &s[78],
+			"framework":   &s[79],
+			"language":    &s[82],
+			"name":        &s[85],
+			"node":        &s[86],
+			"runtime":     &s[88],
+			"version":     &s[91],
+		},
+		MinItems:  -1,
+		MaxItems:  -1,
+		MinLength: -1,
+		MaxLength: -1,
+	}
+	s[74] = jsonschema.Schema{
+		URL:           "docs/spec/errors/docs/spec/docs/spec/service.json",
+		Types:         []string{"object", "null"},
+		MinProperties: -1,
+		MaxProperties: -1,
+		Properties: map[string]*jsonschema.Schema{
+			"ephemeral_id": &s[75],
+			"name":         &s[76],
+			"version":      &s[77],
+		},
+		MinItems:  -1,
+		MaxItems:  -1,
+		MinLength: -1,
+		MaxLength: -1,
+	}
+	s[75] = jsonschema.Schema{
+		URL:           "docs/spec/errors/docs/spec/docs/spec/service.json",
+		Types:         []string{"string", "null"},
+		MinProperties: -1,
+		MaxProperties: -1,
+		MinItems:      -1,
+		MaxItems:      -1,
+		MinLength:     -1,
+		MaxLength:     1024,
+	}
+	s[76] = jsonschema.Schema{
+		URL:           "docs/spec/errors/docs/spec/docs/spec/service.json",
+		Types:         []string{"string", "null"},
+		MinProperties: -1,
+		MaxProperties: -1,
+		MinItems:      -1,
+		MaxItems:      -1,
+		MinLength:     -1,
+		MaxLength:     1024,
+	}
+	s[77] = jsonschema.Schema{
+		URL:           "docs/spec/errors/docs/spec/docs/spec/service.json",
+		Types:         []string{"string", "null"},
+		MinProperties: -1,
+		MaxProperties: -1,
+		MinItems:      -1,
+		MaxItems:      -1,
+		MinLength:     -1,
+		MaxLength:     1024,
+	}
+	s[78] = jsonschema.Schema{
+		URL:           "docs/spec/errors/docs/spec/docs/spec/service.json",
+		Types:         []string{"string", "null"},
+		MinProperties: -1,
+		MaxProperties: -1,
+		MinItems:      -1,
+		MaxItems:      -1,
+		MinLength:     -1,
+		MaxLength:     1024,
+	}
+	s[79] = jsonschema.Schema{
+		URL:           "docs/spec/errors/docs/spec/docs/spec/service.json",
+		Types:         []string{"object", "null"},
+		MinProperties: -1,
+		MaxProperties: -1,
+		Properties: map[string]*jsonschema.Schema{
+			"name":    &s[80],
+			"version": &s[81],
+		},
+		MinItems:  -1,
+		MaxItems:  -1,
+		MinLength: -1,
+		MaxLength: -1,
+	}
+	s[80] = jsonschema.Schema{
+		URL:           "docs/spec/errors/docs/spec/docs/spec/service.json",
+		Types:         []string{"string", "null"},
+		MinProperties: -1,
+		MaxProperties: -1,
+		MinItems:      -1,
+		MaxItems:      -1,
+		MinLength:     -1,
+		MaxLength:     1024,
+	}
+	s[81] = jsonschema.Schema{
+		URL:           "docs/spec/errors/docs/spec/docs/spec/service.json",
+		Types:         []string{"string", "null"},
+		MinProperties: -1,
+		MaxProperties: -1,
+		MinItems:      -1,
+		MaxItems:      -1,
+		MinLength:     -1,
+		MaxLength:     1024,
+	}
+	s[82] = jsonschema.Schema{
+		URL:           "docs/spec/errors/docs/spec/docs/spec/service.json",
+		Types:         []string{"object", "null"},
+		MinProperties: -1,
+		MaxProperties: -1,
+		Properties: map[string]*jsonschema.Schema{
+			"name":    &s[83],
+			"version": &s[84],
+		},
+		MinItems:  -1,
+		MaxItems:  -1,
+		MinLength: -1,
+		MaxLength: -1,
+	}
+	s[83] = jsonschema.Schema{
+		URL:           "docs/spec/errors/docs/spec/docs/spec/service.json",
+		Types:         []string{"string", "null"},
+		MinProperties: -1,
+		MaxProperties: -1,
+		MinItems:      -1,
+		MaxItems:      -1,
+		MinLength:     -1,
+		MaxLength:     1024,
+	}
+	s[84] = jsonschema.Schema{
+		URL:           "docs/spec/errors/docs/spec/docs/spec/service.json",
+		Types:         []string{"string", "null"},
+		MinProperties: -1,
+		MaxProperties: -1,
+		MinItems:      -1,
+		MaxItems:      -1,
+		MinLength:     -1,
+		MaxLength:     1024,
+	}
+	s[85] = jsonschema.Schema{
+		URL:           "docs/spec/errors/docs/spec/docs/spec/service.json",
+		Types:         []string{"string", "null"},
+		MinProperties: -1,
+		MaxProperties: -1,
+		MinItems:      -1,
+		MaxItems:      -1,
+		MinLength:     -1,
+		MaxLength:     1024,
+		Pattern:       regexp.MustCompile("^[a-zA-Z0-9 _-]+$"),
+	}
+	s[86] = jsonschema.Schema{
+		URL:           "docs/spec/errors/docs/spec/docs/spec/service.json",
+		Types:         []string{"object", "null"},
+		MinProperties: -1,
+		MaxProperties: -1,
+		Properties: map[string]*jsonschema.Schema{
+			"configured_name": &s[87],
+		},
+		MinItems:  -1,
+		MaxItems:  -1,
+		MinLength: -1,
+		MaxLength: -1,
+	}
+	s[87] = jsonschema.Schema{
+		URL:           "docs/spec/errors/docs/spec/docs/spec/service.json",
+		Types:         []string{"string", "null"},
+		MinProperties: -1,
+		MaxProperties: -1,
+		MinItems:      -1,
+		MaxItems:      -1,
+		MinLength:     -1,
+		MaxLength:     1024,
+	}
+	s[88] = jsonschema.Schema{
+		URL:           "docs/spec/errors/docs/spec/docs/spec/service.json",
+		Types:         []string{"object", "null"},
+		MinProperties: -1,
+		MaxProperties: -1,
+		Properties: map[string]*jsonschema.Schema{
+			"name":    &s[89],
+			"version": &s[90],
+		},
+		MinItems:  -1,
+		MaxItems:  -1,
+		MinLength: -1,
+		MaxLength: -1,
+	}
+	s[89] = jsonschema.Schema{
+		URL:           "docs/spec/errors/docs/spec/docs/spec/service.json",
+		Types:         []string{"string", "null"},
+		MinProperties: -1,
+		MaxProperties: -1,
+		MinItems:      -1,
+		MaxItems:      -1,
+		MinLength:     -1,
+		MaxLength:     1024,
+	}
+	s[90] = jsonschema.Schema{
+		URL:           "docs/spec/errors/docs/spec/docs/spec/service.json",
+		Types:         []string{"string", "null"},
+		MinProperties: -1,
+		MaxProperties: -1,
+		MinItems:      -1,
+		MaxItems:      -1,
+		MinLength:     -1,
+		MaxLength:     1024,
+	}
+	s[91] = jsonschema.Schema{
+		URL:           "docs/spec/errors/docs/spec/docs/spec/service.json",
+		Types:         []string{"string", "null"},
+		MinProperties: -1,
+		MaxProperties: -1,
+		MinItems:      -1,
+		MaxItems:      -1,
+		MinLength:     -1,
+		MaxLength:     1024,
+	}
+	s[92] = jsonschema.Schema{
+		URL:           "docs/spec/errors/docs/spec/context.json",
+		Types:         []string{"object", "null"},
+		MinProperties: -1,
+		MaxProperties: -1,
+		PatternProperties: map[*regexp.Regexp]*jsonschema.Schema{
+			regexp.MustCompile("^[^.*\"]*$"): &s[93],
+		},
+		AdditionalProperties: false,
+		MinItems:             -1,
+		MaxItems:             -1,
+		MinLength:            -1,
+		MaxLength:            -1,
+	}
+	s[93] = jsonschema.Schema{
+		URL:           "docs/spec/errors/docs/spec/docs/spec/tags.json",
+		Types:         []string{"string", "boolean", "number", "null"},
+		MinProperties: -1,
+		MaxProperties: -1,
+		MinItems:      -1,
+		MaxItems:      -1,
+		MinLength:     -1,
+		MaxLength:     1024,
+	}
+	s[94] = jsonschema.Schema{
+		URL:           "docs/spec/errors/docs/spec/context.json",
+		Types:         []string{"object", "null"},
+		MinProperties: -1,
+		MaxProperties: -1,
+		Properties: map[string]*jsonschema.Schema{
+			"email":    &s[95],
+			"id":       &s[96],
+			"username": &s[97],
+		},
+		MinItems:  -1,
+		MaxItems:  -1,
+		MinLength: -1,
+		MaxLength: -1,
+	}
+	s[95] = jsonschema.Schema{
+		URL:           "docs/spec/errors/docs/spec/docs/spec/user.json",
+		Types:         []string{"string", "null"},
+		MinProperties: -1,
+		MaxProperties: -1,
+		MinItems:      -1,
+		MaxItems:      -1,
+		MinLength:     -1,
+		MaxLength:     1024,
+	}
+	s[96] = jsonschema.Schema{
+		URL:           "docs/spec/errors/docs/spec/docs/spec/user.json",
+		Types:         []string{"string", "integer", "null"},
+		MinProperties: -1,
+		MaxProperties: -1,
+		MinItems:      -1,
+		MaxItems:      -1,
+		MinLength:     -1,
+		MaxLength:     1024,
+	}
+	s[97] = jsonschema.Schema{
+		URL:           "docs/spec/errors/docs/spec/docs/spec/user.json",
+		Types:         []string{"string", "null"},
+		MinProperties: -1,
+		MaxProperties: -1,
+		MinItems:      -1,
+		MaxItems:      -1,
+		MinLength:     -1,
+		MaxLength:     1024,
+	}
+	s[98] = jsonschema.Schema{
+		URL:           "docs/spec/errors/error.json",
+		Types:         []string{"string", "null"},
+		MinProperties: -1,
+		MaxProperties: -1,
+		MinItems:      -1,
+		MaxItems:      -1,
+		MinLength:     -1,
+		MaxLength:     1024,
+	}
+	s[99] = jsonschema.Schema{
+		URL:           "docs/spec/errors/error.json",
+		Types:         []string{"object", "null"},
+		AnyOf:         []*jsonschema.Schema{&s[100], &s[102]},
+		MinProperties: -1,
+		MaxProperties: -1,
+		Properties: map[string]*jsonschema.Schema{
+			"attributes": &s[104],
+			"cause":      &s[105],
+			"code":       &s[107],
+			"handled":    &s[108],
+			"message":    &s[109],
+			"module":     &s[110],
+			"stacktrace": &s[111],
+			"type":       &s[131],
+		},
+		MinItems:  -1,
+		MaxItems:  -1,
+		MinLength: -1,
+		MaxLength: -1,
+	}
+	s[100] = jsonschema.Schema{
+		URL:           "docs/spec/errors/error.json",
+		MinProperties: -1,
+		MaxProperties: -1,
+		Required:      []string{"message"},
+		Properties: map[string]*jsonschema.Schema{
+			"message": &s[101],
+		},
+		MinItems:  -1,
+		MaxItems:  -1,
+		MinLength: -1,
+		MaxLength: -1,
+	}
+	s[101] = jsonschema.Schema{
+		URL:           "docs/spec/errors/error.json",
+		Types:         []string{"string"},
+		MinProperties: -1,
+		MaxProperties: -1,
+		MinItems:      -1,
+		MaxItems:      -1,
+		MinLength:     -1,
+		MaxLength:     -1,
+	}
+	s[102] = jsonschema.Schema{
+		URL:           "docs/spec/errors/error.json",
+		MinProperties: -1,
+		MaxProperties: -1,
+		Required:      []string{"type"},
+		Properties: map[string]*jsonschema.Schema{
+			"type": &s[103],
+		},
+		MinItems:  -1,
+		MaxItems:  -1,
+		MinLength: -1,
+		MaxLength: -1,
+	}
+	s[103] = jsonschema.Schema{
+		URL:           "docs/spec/errors/error.json",
+		Types:         []string{"string"},
+		MinProperties: -1,
+		MaxProperties: -1,
+		MinItems:      -1,
+		MaxItems:      -1,
+		MinLength:     -1,
+		MaxLength:     -1,
+	}
+	s[104] = jsonschema.Schema{
+		URL:           "docs/spec/errors/error.json",
+		Types:         []string{"object", "null"},
+		MinProperties: -1,
+		MaxProperties: -1,
+		MinItems:      -1,
+		MaxItems:      -1,
+		MinLength:     -1,
+		MaxLength:     -1,
+	}
+	s[105] = jsonschema.Schema{
+		URL:           "docs/spec/errors/error.json",
+		Types:         []string{"array", "null"},
+		MinProperties: -1,
+		MaxProperties: -1,
+		MinItems:      0,
+		MaxItems:      -1,
+		Items:         &s[106],
+		MinLength:     -1,
+		MaxLength:     -1,
+	}
+	s[106] = jsonschema.Schema{
+		URL:           "docs/spec/errors/error.json",
+		Types:         []string{"object", "null"},
+		MinProperties: -1,
+		MaxProperties: -1,
+		MinItems:      -1,
+		MaxItems:      -1,
+		MinLength:     -1,
+		MaxLength:     -1,
+	}
+	s[107] = jsonschema.Schema{
+		URL:           "docs/spec/errors/error.json",
+		Types:         []string{"string", "integer", "null"},
+		MinProperties: -1,
+		MaxProperties: -1,
+		MinItems:      -1,
+		MaxItems:      -1,
+		MinLength:     -1,
+		MaxLength:     1024,
+	}
+	s[108] = jsonschema.Schema{
+		URL:           "docs/spec/errors/error.json",
+		Types:         []string{"boolean", "null"},
+		MinProperties: -1,
+		MaxProperties: -1,
+		MinItems:      -1,
+		MaxItems:      -1,
+		MinLength:     -1,
+		MaxLength:     -1,
+	}
+	s[109] = jsonschema.Schema{
+		URL:           "docs/spec/errors/error.json",
+		Types:         []string{"string", "null"},
+		MinProperties: -1,
+		MaxProperties: -1,
+		MinItems:      -1,
+		MaxItems:      -1,
+		MinLength:     -1,
+		MaxLength:     -1,
+	}
+	s[110] = jsonschema.Schema{
+		URL:           "docs/spec/errors/error.json",
+		Types:         []string{"string", "null"},
+		MinProperties: -1,
+		MaxProperties: -1,
+		MinItems:      -1,
+		MaxItems:      -1,
+		MinLength:     -1,
+		MaxLength:     1024,
+	}
+	s[111] = jsonschema.Schema{
+		URL:           "docs/spec/errors/error.json",
+		Types:         []string{"array", "null"},
+		MinProperties: -1,
+		MaxProperties: -1,
+		MinItems:      0,
+		MaxItems:      -1,
+		Items:         &s[112],
+		MinLength:     -1,
+		MaxLength:     -1,
+	}
+	s[112] = jsonschema.Schema{
+		URL:           "docs/spec/errors/error.json",
+		Types:         []string{"object"},
+		AnyOf:         []*jsonschema.Schema{&s[113], &s[115]},
+		MinProperties: -1,
+		MaxProperties: -1,
+		Properties: map[string]*jsonschema.Schema{
+			"abs_path":      &s[117],
+			"classname":     &s[118],
+			"colno":         &s[119],
+			"context_line":  &s[120],
+			"filename":      &s[121],
+			"function":      &s[122],
+			"library_frame": &s[123],
+			"lineno":        &s[124],
+			"module":        &s[125],
+			"post_context":  &s[126],
+			"pre_context":   &s[128],
+			"vars":          &s[130],
+		},
+		MinItems:  -1,
+		MaxItems:  -1,
+		MinLength: -1,
+		MaxLength: -1,
+	}
+	s[113] = jsonschema.Schema{
+		URL:           "docs/spec/errors/docs/spec/stacktrace_frame.json",
+		MinProperties: -1,
+		MaxProperties: -1,
+		Required:      []string{"filename"},
+		Properties: map[string]*jsonschema.Schema{
+			"filename": &s[114],
+		},
+		MinItems:  -1,
+		MaxItems:  -1,
+		MinLength: -1,
+		MaxLength: -1,
+	}
+	s[114] = jsonschema.Schema{
+		URL:           "docs/spec/errors/docs/spec/stacktrace_frame.json",
+		Types:         []string{"string"},
+		MinProperties: -1,
+		MaxProperties: -1,
+		MinItems:      -1,
+		MaxItems:      -1,
+		MinLength:     -1,
+		MaxLength:     -1,
+	}
+	s[115] = jsonschema.Schema{
+		URL:           "docs/spec/errors/docs/spec/stacktrace_frame.json",
+		MinProperties: -1,
+		MaxProperties: -1,
+		Required:      []string{"classname"},
+		Properties: map[string]*jsonschema.Schema{
+			"classname": &s[116],
+		},
+		MinItems:  -1,
+		MaxItems:  -1,
+		MinLength: -1,
+		MaxLength: -1,
+	}
+	s[116] = jsonschema.Schema{
+		URL:           "docs/spec/errors/docs/spec/stacktrace_frame.json",
+		Types:         []string{"string"},
+		MinProperties: -1,
+		MaxProperties: -1,
+		MinItems:      -1,
+		MaxItems:      -1,
+		MinLength:     -1,
+		MaxLength:     -1,
+	}
+	s[117] = jsonschema.Schema{
+		URL:           "docs/spec/errors/docs/spec/stacktrace_frame.json",
+		Types:         []string{"string", "null"},
+		MinProperties: -1,
+		MaxProperties: -1,
+		MinItems:      -1,
+		MaxItems:      -1,
+		MinLength:     -1,
+		MaxLength:     -1,
+	}
+	s[118] = jsonschema.Schema{
+		URL:           "docs/spec/errors/docs/spec/stacktrace_frame.json",
+		Types:         []string{"string", "null"},
+		MinProperties: -1,
+		MaxProperties: -1,
+		MinItems:      -1,
+		MaxItems:      -1,
+		MinLength:     -1,
+		MaxLength:     -1,
+	}
+	s[119] = jsonschema.Schema{
+		URL:           "docs/spec/errors/docs/spec/stacktrace_frame.json",
+		Types:         []string{"integer", "null"},
+		MinProperties: -1,
+		MaxProperties: -1,
+		MinItems:      -1,
+		MaxItems:      -1,
+		MinLength:     -1,
+		MaxLength:     -1,
+	}
+	s[120] = jsonschema.Schema{
+		URL:           "docs/spec/errors/docs/spec/stacktrace_frame.json",
+		Types:         []string{"string", "null"},
+		MinProperties: -1,
+		MaxProperties: -1,
+		MinItems:      -1,
+		MaxItems:      -1,
+		MinLength:     -1,
+		MaxLength:     -1,
+	}
+	s[121] = jsonschema.Schema{
+		URL:           "docs/spec/errors/docs/spec/stacktrace_frame.json",
+		Types:         []string{"string", "null"},
+		MinProperties: -1,
+		MaxProperties: -1,
+		MinItems:      -1,
+		MaxItems:      -1,
+		MinLength:     -1,
+		MaxLength:     -1,
+	}
+	s[122] = jsonschema.Schema{
+		URL:           "docs/spec/errors/docs/spec/stacktrace_frame.json",
+		Types:         []string{"string", "null"},
+		MinProperties: -1,
+		MaxProperties: -1,
+		MinItems:      -1,
+		MaxItems:      -1,
+		MinLength:     -1,
+		MaxLength:     -1,
+	}
+	s[123] = jsonschema.Schema{
+		URL:           "docs/spec/errors/docs/spec/stacktrace_frame.json",
+		Types:         []string{"boolean", "null"},
+		MinProperties: -1,
+		MaxProperties: -1,
+		MinItems:      -1,
+		MaxItems:      -1,
+		MinLength:     -1,
+		MaxLength:     -1,
+	}
+	s[124] = jsonschema.Schema{
+		URL:           "docs/spec/errors/docs/spec/stacktrace_frame.json",
+		Types:         []string{"integer", "null"},
+		MinProperties: -1,
+		MaxProperties: -1,
+		MinItems:      -1,
+		MaxItems:      -1,
+		MinLength:     -1,
+		MaxLength:     -1,
+	}
+	s[125] = jsonschema.Schema{
+		URL:           "docs/spec/errors/docs/spec/stacktrace_frame.json",
+		Types:         []string{"string", "null"},
+		MinProperties: -1,
+		MaxProperties: -1,
+		MinItems:      -1,
+		MaxItems:      -1,
+		MinLength:     -1,
+		MaxLength:     -1,
+	}
+	s[126] = jsonschema.Schema{
+		URL:           "docs/spec/errors/docs/spec/stacktrace_frame.json",
+		Types:         []string{"array", "null"},
+		MinProperties: -1,
+		MaxProperties: -1,
+		MinItems:      0,
+		MaxItems:      -1,
+		Items:         &s[127],
+		MinLength:     -1,
+		MaxLength:     -1,
+	}
+	s[127] = jsonschema.Schema{
+		URL:           "docs/spec/errors/docs/spec/stacktrace_frame.json",
+		Types:         []string{"string"},
+		MinProperties: -1,
+		MaxProperties: -1,
+		MinItems:      -1,
+		MaxItems:      -1,
+		MinLength:     -1,
+		MaxLength:     -1,
+	}
+	s[128] = jsonschema.Schema{
+		URL:           "docs/spec/errors/docs/spec/stacktrace_frame.json",
+		Types:         []string{"array", "null"},
+		MinProperties: -1,
+		MaxProperties: -1,
+		MinItems:      0,
+		MaxItems:      -1,
+		Items:         &s[129],
+		MinLength:     -1,
+		MaxLength:     -1,
+	}
+	s[129] = jsonschema.Schema{
+		URL:           "docs/spec/errors/docs/spec/stacktrace_frame.json",
+		Types:         []string{"string"},
+		MinProperties: -1,
+		MaxProperties: -1,
+		MinItems:      -1,
+		MaxItems:      -1,
+		MinLength:     -1,
+		MaxLength:     -1,
+	}
+	s[130] = jsonschema.Schema{
+		URL:           "docs/spec/errors/docs/spec/stacktrace_frame.json",
+		Types:         []string{"object", "null"},
+		MinProperties: -1,
+		MaxProperties: -1,
+		Properties:    map[string]*jsonschema.Schema{},
+		MinItems:      -1,
+		MaxItems:      -1,
+		MinLength:     -1,
+		MaxLength:     -1,
+	}
+	s[131] = jsonschema.Schema{
+		URL:           "docs/spec/errors/error.json",
+		Types:         []string{"string", "null"},
+		MinProperties: -1,
+		MaxProperties: -1,
+		MinItems:      -1,
+		MaxItems:      -1,
+		MinLength:     -1,
+		MaxLength:     1024,
+	}
+	s[132] = jsonschema.Schema{
+		URL:           "docs/spec/errors/error.json",
+		Types:         []string{"string"},
+		MinProperties: -1,
+		MaxProperties: -1,
+		MinItems:      -1,
+		MaxItems:      -1,
+		MinLength:     -1,
+		MaxLength:     1024,
+	}
+	s[133] = jsonschema.Schema{
+		URL:           "docs/spec/errors/error.json",
+		Types:         []string{"object", "null"},
+		MinProperties: -1,
+		MaxProperties: -1,
+		Required:      []string{"message"},
+		Properties: map[string]*jsonschema.Schema{
+			"level":         &s[134],
+			"logger_name":   &s[135],
+			"message":       &s[136],
+			"param_message": &s[137],
+			"stacktrace":    &s[138],
+		},
+		MinItems:  -1,
+		MaxItems:  -1,
+		MinLength: -1,
+		MaxLength: -1,
+	}
+	s[134] = jsonschema.Schema{
+		URL:           "docs/spec/errors/error.json",
+		Types:         []string{"string", "null"},
+		MinProperties: -1,
+		MaxProperties: -1,
+		MinItems:      -1,
+		MaxItems:      -1,
+		MinLength:     -1,
+		MaxLength:     1024,
+	}
+	s[135] = jsonschema.Schema{
+		URL:           "docs/spec/errors/error.json",
+		Types:         []string{"string", "null"},
+		MinProperties: -1,
+		MaxProperties: -1,
+		MinItems:      -1,
+		MaxItems:      -1,
+		MinLength:     -1,
+		MaxLength:     1024,
+	}
+	s[136] = jsonschema.Schema{
+		URL:           "docs/spec/errors/error.json",
+		Types:         []string{"string"},
+		MinProperties: -1,
+		MaxProperties: -1,
+		MinItems:      -1,
+		MaxItems:      -1,
+		MinLength:     -1,
+		MaxLength:     -1,
+	}
+	s[137] = jsonschema.Schema{
+		URL:           "docs/spec/errors/error.json",
+		Types:         []string{"string", "null"},
+		MinProperties: -1,
+		MaxProperties: -1,
+		MinItems:      -1,
+		MaxItems:      -1,
+		MinLength:     -1,
+		MaxLength:     1024,
+	}
+	s[138] = jsonschema.Schema{
+		URL:           "docs/spec/errors/error.json",
+		Types:         []string{"array", "null"},
+		MinProperties: -1,
+		MaxProperties: -1,
+		MinItems:      0,
+		MaxItems:      -1,
+		Items:         &s[139],
+		MinLength:     -1,
+		MaxLength:     -1,
+	}
+	s[139] = jsonschema.Schema{
+		URL:           "docs/spec/errors/error.json",
+		Types:         []string{"object"},
+		AnyOf:         []*jsonschema.Schema{&s[140], &s[142]},
+		MinProperties: -1,
+		MaxProperties: -1,
+		Properties: map[string]*jsonschema.Schema{
+			"abs_path":      &s[144],
+			"classname":     &s[145],
+			"colno":         &s[146],
+			"context_line":  &s[147],
+			"filename":      &s[148],
+			"function":      &s[149],
+			"library_frame": &s[150],
+			"lineno":        &s[151],
+			"module":        &s[152],
+			"post_context":  &s[153],
+			"pre_context":   &s[155],
+			"vars":          &s[157],
+		},
+		MinItems:  -1,
+		MaxItems:  -1,
+		MinLength: -1,
+		MaxLength: -1,
+	}
+	s[140] = jsonschema.Schema{
+		URL:           "docs/spec/errors/docs/spec/stacktrace_frame.json",
+		MinProperties: -1,
+		MaxProperties: -1,
+		Required:      []string{"filename"},
+		Properties: map[string]*jsonschema.Schema{
+			"filename": &s[141],
+		},
+		MinItems:  -1,
+		MaxItems:  -1,
+		MinLength: -1,
+		MaxLength: -1,
+	}
+	s[141] = jsonschema.Schema{
+		URL:           "docs/spec/errors/docs/spec/stacktrace_frame.json",
+		Types:         []string{"string"},
+		MinProperties: -1,
+		MaxProperties: -1,
+		MinItems:      -1,
+		MaxItems:      -1,
+		MinLength:     -1,
+		MaxLength:     -1,
+	}
+	s[142] = jsonschema.Schema{
+		URL:           "docs/spec/errors/docs/spec/stacktrace_frame.json",
+		MinProperties: -1,
+		MaxProperties: -1,
+		Required:      []string{"classname"},
+		Properties: map[string]*jsonschema.Schema{
+			"classname": &s[143],
+		},
+		MinItems:  -1,
+		MaxItems:  -1,
+		MinLength: -1,
+		MaxLength: -1,
+	}
+	s[143] = jsonschema.Schema{
+		URL:           "docs/spec/errors/docs/spec/stacktrace_frame.json",
+		Types:         []string{"string"},
+		MinProperties: -1,
+		MaxProperties: -1,
+		MinItems:      -1,
+		MaxItems:      -1,
+		MinLength:     -1,
+		MaxLength:     -1,
+	}
+	s[144] = jsonschema.Schema{
+		URL:           "docs/spec/errors/docs/spec/stacktrace_frame.json",
+		Types:         []string{"string", "null"},
+		MinProperties: -1,
+		MaxProperties: -1,
+		MinItems:      -1,
+		MaxItems:      -1,
+		MinLength:     -1,
+		MaxLength:     -1,
+	}
+	s[145] = jsonschema.Schema{
+		URL:           "docs/spec/errors/docs/spec/stacktrace_frame.json",
+		Types:         []string{"string", "null"},
+		MinProperties: -1,
+		MaxProperties: -1,
+		MinItems:      -1,
+		MaxItems:      -1,
+		MinLength:     -1,
+		MaxLength:     -1,
+	}
+	s[146] = jsonschema.Schema{
+		URL:           "docs/spec/errors/docs/spec/stacktrace_frame.json",
+		Types:         []string{"integer", "null"},
+		MinProperties: -1,
+		MaxProperties: -1,
+		MinItems:      -1,
+		MaxItems:      -1,
+		MinLength:     -1,
+		MaxLength:     -1,
+	}
+	s[147] = jsonschema.Schema{
+		URL:           "docs/spec/errors/docs/spec/stacktrace_frame.json",
+		Types:         []string{"string", "null"},
+		MinProperties: -1,
+		MaxProperties: -1,
+		MinItems:      -1,
+		MaxItems:      -1,
+		MinLength:     -1,
+		MaxLength:     -1,
+	}
+	s[148] = jsonschema.Schema{
+		URL:           "docs/spec/errors/docs/spec/stacktrace_frame.json",
+		Types:         []string{"string", "null"},
+		MinProperties: -1,
+		MaxProperties: -1,
+		MinItems:      -1,
+		MaxItems:      -1,
+		MinLength:     -1,
+		MaxLength:     -1,
+	}
+	s[149] = jsonschema.Schema{
+		URL:           "docs/spec/errors/docs/spec/stacktrace_frame.json",
+		Types:         []string{"string", "null"},
+		MinProperties: -1,
+		MaxProperties: -1,
+		MinItems:      -1,
+		MaxItems:      -1,
+		MinLength:     -1,
+		MaxLength:     -1,
+	}
+	s[150] = jsonschema.Schema{
+		URL:           "docs/spec/errors/docs/spec/stacktrace_frame.json",
+		Types:         []string{"boolean", "null"},
+		MinProperties: -1,
+		MaxProperties: -1,
+		MinItems:      -1,
+		MaxItems:      -1,
+		MinLength:     -1,
+		MaxLength:     -1,
+	}
+	s[151] = jsonschema.Schema{
+		URL:           "docs/spec/errors/docs/spec/stacktrace_frame.json",
+		Types:         []string{"integer", "null"},
+		MinProperties: -1,
+		MaxProperties: -1,
+		MinItems:      -1,
+		MaxItems:      -1,
+		MinLength:     -1,
+		MaxLength:     -1,
+	}
+	s[152] = jsonschema.Schema{
+		URL:           "docs/spec/errors/docs/spec/stacktrace_frame.json",
+		Types:         []string{"string", "null"},
+		MinProperties: -1,
+		MaxProperties: -1,
+		MinItems:      -1,
+		MaxItems:      -1,
+		MinLength:     -1,
+		MaxLength:     -1,
+	}
+	s[153] = jsonschema.Schema{
+		URL:           "docs/spec/errors/docs/spec/stacktrace_frame.json",
+		Types:         []string{"array", "null"},
+		MinProperties: -1,
+		MaxProperties: -1,
+		MinItems:      0,
+		MaxItems:      -1,
+		Items:         &s[154],
+		MinLength:     -1,
+		MaxLength:     -1,
+	}
+	s[154] = jsonschema.Schema{
+		URL:           "docs/spec/errors/docs/spec/stacktrace_frame.json",
+		Types:         []string{"string"},
+		MinProperties: -1,
+		MaxProperties: -1,
+		MinItems:      -1,
+		MaxItems:      -1,
+		MinLength:     -1,
+		MaxLength:     -1,
+	}
+	s[155] = jsonschema.Schema{
+		URL:           "docs/spec/errors/docs/spec/stacktrace_frame.json",
+		Types:         []string{"array", "null"},
+		MinProperties: -1,
+		MaxProperties: -1,
+		MinItems:      0,
+		MaxItems:      -1,
+		Items:         &s[156],
+		MinLength:     -1,
+		MaxLength:     -1,
+	}
+	s[156] = jsonschema.Schema{
+		URL:           "docs/spec/errors/docs/spec/stacktrace_frame.json",
+		Types:         []string{"string"},
+		MinProperties: -1,
+		MaxProperties: -1,
+		MinItems:      -1,
+		MaxItems:      -1,
+		MinLength:     -1,
+		MaxLength:     -1,
+	}
+	s[157] = jsonschema.Schema{
+		URL:           "docs/spec/errors/docs/spec/stacktrace_frame.json",
+		Types:         []string{"object", "null"},
+		MinProperties: -1,
+		MaxProperties: -1,
+		Properties:    map[string]*jsonschema.Schema{},
+		MinItems:      -1,
+		MaxItems:      -1,
+		MinLength:     -1,
+		MaxLength:     -1,
+	}
+	s[158] = jsonschema.Schema{
+		URL:           "docs/spec/errors/error.json",
+		Types:         []string{"string", "null"},
+		MinProperties: -1,
+		MaxProperties: -1,
+		MinItems:      -1,
+		MaxItems:      -1,
+		MinLength:     -1,
+		MaxLength:     1024,
+	}
+	s[159] = jsonschema.Schema{
+		URL:           "docs/spec/errors/error.json",
+		Types:         []string{"string", "null"},
+		MinProperties: -1,
+		MaxProperties: -1,
+		MinItems:      -1,
+		MaxItems:      -1,
+		MinLength:     -1,
+		MaxLength:     1024,
+	}
+	s[160] = jsonschema.Schema{
+		URL:           "docs/spec/errors/error.json",
+		Types:         []string{"object", "null"},
+		MinProperties: -1,
+		MaxProperties: -1,
+		Properties: map[string]*jsonschema.Schema{
+			"sampled": &s[161],
+			"type":    &s[162],
+		},
+		MinItems:  -1,
+		MaxItems:  -1,
+		MinLength: -1,
+		MaxLength: -1,
+	}
+	s[161] = jsonschema.Schema{
+		URL:           "docs/spec/errors/error.json",
+		Types:         []string{"boolean", "null"},
+		MinProperties: -1,
+		MaxProperties: -1,
+		MinItems:      -1,
+		MaxItems:      -1,
+		MinLength:     -1,
+		MaxLength:     -1,
+	}
+	s[162] = jsonschema.Schema{
+		URL:           "docs/spec/errors/error.json",
+		Types:         []string{"string", "null"},
+		MinProperties: -1,
+		MaxProperties: -1,
+		MinItems:      -1,
+		MaxItems:      -1,
+		MinLength:     -1,
+		MaxLength:     1024,
+	}
+	s[163] = jsonschema.Schema{
+		URL:           "docs/spec/errors/error.json",
+		Types:         []string{"string", "null"},
+		MinProperties: -1,
+		MaxProperties: -1,
+		MinItems:      -1,
+		MaxItems:      -1,
+		MinLength:     -1,
+		MaxLength:     1024,
+	}
+	return &s[0]
+}
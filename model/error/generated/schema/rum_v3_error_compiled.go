@@ -0,0 +1,1442 @@
+// Licensed to Elasticsearch B.V. under one or more contributor
+// license agreements. See the NOTICE file distributed with
+// this work for additional information regarding copyright
+// ownership. Elasticsearch B.V. licenses this file to you under
+// the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+// Code generated by script/inline_schemas. DO NOT EDIT.
+
+package schema
+
+import (
+	"regexp"
+
+	"github.com/santhosh-tekuri/jsonschema"
+)
+
+// CompiledRUMV3Schema returns the compiled form of RUMV3Schema.
+func CompiledRUMV3Schema() *jsonschema.Schema {
+	s := make([]jsonschema.Schema, 123)
+	s[0] = jsonschema.Schema{
+		URL:           "error",
+		Ptr:           "#",
+		Types:         []string{"object"},
+		AllOf:         []*jsonschema.Schema{&s[1], &s[3]},
+		MinProperties: -1,
+		MaxProperties: -1,
+		MinItems:      -1,
+		MaxItems:      -1,
+		MinLength:     -1,
+		MaxLength:     -1,
+	}
+	s[1] = jsonschema.Schema{
+		URL:           "docs/spec/errors/rum_v3_error.json",
+		Types:         []string{"object"},
+		MinProperties: -1,
+		MaxProperties: -1,
+		Properties: map[string]*jsonschema.Schema{
+			"timestamp": &s[2],
+		},
+		MinItems:  -1,
+		MaxItems:  -1,
+		MinLength: -1,
+		MaxLength: -1,
+	}
+	s[2] = jsonschema.Schema{
+		URL:           "docs/spec/errors/docs/spec/timestamp_epoch.json",
+		Types:         []string{"integer", "null"},
+		MinProperties: -1,
+		MaxProperties: -1,
+		MinItems:      -1,
+		MaxItems:      -1,
+		MinLength:     -1,
+		MaxLength:     -1,
+	}
+	s[3] = jsonschema.Schema{
+		URL:           "docs/spec/errors/rum_v3_error.json",
+		AllOf:         []*jsonschema.Schema{&s[4], &s[5], &s[11], &s[16]},
+		AnyOf:         []*jsonschema.Schema{&s[21], &s[23]},
+		MinProperties: -1,
+		MaxProperties: -1,
+		Properties: map[string]*jsonschema.Schema{
+			"c":   &s[25],
+			"cu":  &s[69],
+			"ex":  &s[70],
+			"id":  &s[97],
+			"log": &s[98],
+			"pid": &s[117],
+			"tid": &s[118],
+			"x":   &s[119],
+			"xid": &s[122],
+		},
+		MinItems:  -1,
+		MaxItems:  -1,
+		MinLength: -1,
+		MaxLength: -1,
+	}
+	s[4] = jsonschema.Schema{
+		URL:           "docs/spec/errors/rum_v3_error.json",
+		MinProperties: -1,
+		MaxProperties: -1,
+		Required:      []string{"id"},
+		MinItems:      -1,
+		MaxItems:      -1,
+		MinLength:     -1,
+		MaxLength:     -1,
+	}
+	s[5] = jsonschema.Schema{
+		URL:           "docs/spec/errors/rum_v3_error.json",
+		If:            &s[6],
+		Then:          &s[8],
+		MinProperties: -1,
+		MaxProperties: -1,
+		MinItems:      -1,
+		MaxItems:      -1,
+		MinLength:     -1,
+		MaxLength:     -1,
+	}
+	s[6] = jsonschema.Schema{
+		URL:           "docs/spec/errors/rum_v3_error.json",
+		MinProperties: -1,
+		MaxProperties: -1,
+		Required:      []string{"xid"},
+		Properties: map[string]*jsonschema.Schema{
+			"xid": &s[7],
+		},
+		MinItems:  -1,
+		MaxItems:  -1,
+		MinLength: -1,
+		MaxLength: -1,
+	}
+	s[7] = jsonschema.Schema{
+		URL:           "docs/spec/errors/rum_v3_error.json",
+		Types:         []string{"string"},
+		MinProperties: -1,
+		MaxProperties: -1,
+		MinItems:      -1,
+		MaxItems:      -1,
+		MinLength:     -1,
+		MaxLength:     -1,
+	}
+	s[8] = jsonschema.Schema{
+		URL:           "docs/spec/errors/rum_v3_error.json",
+		MinProperties: -1,
+		MaxProperties: -1,
+		Required:      []string{"tid", "pid"},
+		Properties: map[string]*jsonschema.Schema{
+			"pid": &s[9],
+			"tid": &s[10],
+		},
+		MinItems:  -1,
+		MaxItems:  -1,
+		MinLength: -1,
+		MaxLength: -1,
+	}
+	s[9] = jsonschema.Schema{
+		URL:           "docs/spec/errors/rum_v3_error.json",
+		Types:         []string{"string"},
+		MinProperties: -1,
+		MaxProperties: -1,
+		MinItems:      -1,
+		MaxItems:      -1,
+		MinLength:     -1,
+		MaxLength:     -1,
+	}
+	s[10] = jsonschema.Schema{
+		URL:           "docs/spec/errors/rum_v3_error.json",
+		Types:         []string{"string"},
+		MinProperties: -1,
+		MaxProperties: -1,
+		MinItems:      -1,
+		MaxItems:      -1,
+		MinLength:     -1,
+		MaxLength:     -1,
+	}
+	s[11] = jsonschema.Schema{
+		URL:           "docs/spec/errors/rum_v3_error.json",
+		If:            &s[12],
+		Then:          &s[14],
+		MinProperties: -1,
+		MaxProperties: -1,
+		MinItems:      -1,
+		MaxItems:      -1,
+		MinLength:     -1,
+		MaxLength:     -1,
+	}
+	s[12] = jsonschema.Schema{
+		URL:           "docs/spec/errors/rum_v3_error.json",
+		MinProperties: -1,
+		MaxProperties: -1,
+		Required:      []string{"tid"},
+		Properties: map[string]*jsonschema.Schema{
+			"tid": &s[13],
+		},
+		MinItems:  -1,
+		MaxItems:  -1,
+		MinLength: -1,
+		MaxLength: -1,
+	}
+	s[13] = jsonschema.Schema{
+		URL:           "docs/spec/errors/rum_v3_error.json",
+		Types:         []string{"string"},
+		MinProperties: -1,
+		MaxProperties: -1,
+		MinItems:      -1,
+		MaxItems:      -1,
+		MinLength:     -1,
+		MaxLength:     -1,
+	}
+	s[14] = jsonschema.Schema{
+		URL:           "docs/spec/errors/rum_v3_error.json",
+		MinProperties: -1,
+		MaxProperties: -1,
+		Required:      []string{"pid"},
+		Properties: map[string]*jsonschema.Schema{
+			"pid": &s[15],
+		},
+		MinItems:  -1,
+		MaxItems:  -1,
+		MinLength: -1,
+		MaxLength: -1,
+	}
+	s[15] = jsonschema.Schema{
+		URL:           "docs/spec/errors/rum_v3_error.json",
+		Types:         []string{"string"},
+		MinProperties: -1,
+		MaxProperties: -1,
+		MinItems:      -1,
+		MaxItems:      -1,
+		MinLength:     -1,
+		MaxLength:     -1,
+	}
+	s[16] = jsonschema.Schema{
+		URL:           "docs/spec/errors/rum_v3_error.json",
+		If:            &s[17],
+		Then:          &s[19],
+		MinProperties: -1,
+		MaxProperties: -1,
+		MinItems:      -1,
+		MaxItems:      -1,
+		MinLength:     -1,
+		MaxLength:     -1,
+	}
+	s[17] = jsonschema.Schema{
+		URL:           "docs/spec/errors/rum_v3_error.json",
+		MinProperties: -1,
+		MaxProperties: -1,
+		Required:      []string{"pid"},
+		Properties: map[string]*jsonschema.Schema{
+			"pid": &s[18],
+		},
+		MinItems:  -1,
+		MaxItems:  -1,
+		MinLength: -1,
+		MaxLength: -1,
+	}
+	s[18] = jsonschema.Schema{
+		URL:           "docs/spec/errors/rum_v3_error.json",
+		Types:         []string{"string"},
+		MinProperties: -1,
+		MaxProperties: -1,
+		MinItems:      -1,
+		MaxItems:      -1,
+		MinLength:     -1,
+		MaxLength:     -1,
+	}
+	s[19] = jsonschema.Schema{
+		URL:           "docs/spec/errors/rum_v3_error.json",
+		MinProperties: -1,
+		MaxProperties: -1,
+		Required:      []string{"tid"},
+		Properties: map[string]*jsonschema.Schema{
+			"tid": &s[20],
+		},
+		MinItems:  -1,
+		MaxItems:  -1,
+		MinLength: -1,
+		MaxLength: -1,
+	}
+	s[20] = jsonschema.Schema{
+		URL:           "docs/spec/errors/rum_v3_error.json",
+		Types:         []string{"string"},
+		MinProperties: -1,
+		MaxProperties: -1,
+		MinItems:      -1,
+		MaxItems:      -1,
+		MinLength:     -1,
+		MaxLength:     -1,
+	}
+	s[21] = jsonschema.Schema{
+		URL:           "docs/spec/errors/rum_v3_error.json",
+		MinProperties: -1,
+		MaxProperties: -1,
+		Required:      []string{"ex"},
+		Properties: map[string]*jsonschema.Schema{
+			"ex": &s[22],
+		},
+		MinItems:  -1,
+		MaxItems:  -1,
+		MinLength: -1,
+		MaxLength: -1,
+	}
+	s[22] = jsonschema.Schema{
+		URL:           "docs/spec/errors/rum_v3_error.json",
+		Types:         []string{"object"},
+		MinProperties: -1,
+		MaxProperties: -1,
+		MinItems:      -1,
+		MaxItems:      -1,
+		MinLength:     -1,
+		MaxLength:     -1,
+	}
+	s[23] = jsonschema.Schema{
+		URL:           "docs/spec/errors/rum_v3_error.json",
+		MinProperties: -1,
+		MaxProperties: -1,
+		Required:      []string{"log"},
+		Properties: map[string]*jsonschema.Schema{
+			"log": &s[24],
+		},
+		MinItems:  -1,
+		MaxItems:  -1,
+		MinLength: -1,
+		MaxLength: -1,
+	}
+	s[24] = jsonschema.Schema{
+		URL:           "docs/spec/errors/rum_v3_error.json",
+		Types:         []string{"object"},
+		MinProperties: -1,
+		MaxProperties: -1,
+		MinItems:      -1,
+		MaxItems:      -1,
+		MinLength:     -1,
+		MaxLength:     -1,
+	}
+	s[25] = jsonschema.Schema{
+		URL:           "docs/spec/errors/rum_v3_error.json",
+		Types:         []string{"object", "null"},
+		MinProperties: -1,
+		MaxProperties: -1,
+		Properties: map[string]*jsonschema.Schema{
+			"cu": &s[26],
+			"g":  &s[28],
+			"p":  &s[30],
+			"q":  &s[33],
+			"r":  &s[40],
+			"se": &s[49],
+			"u":  &s[65],
+		},
+		MinItems:  -1,
+		MaxItems:  -1,
+		MinLength: -1,
+		MaxLength: -1,
+	}
+	s[26] = jsonschema.Schema{
+		URL:           "docs/spec/errors/docs/spec/rum_v3_context.json",
+		Types:         []string{"object", "null"},
+		MinProperties: -1,
+		MaxProperties: -1,
+		PatternProperties: map[*regexp.Regexp]*jsonschema.Schema{
+			regexp.MustCompile("^[^.*\"]*$"): &s[27],
+		},
+		AdditionalProperties: false,
+		MinItems:             -1,
+		MaxItems:             -1,
+		MinLength:            -1,
+		MaxLength:            -1,
+	}
+	s[27] = jsonschema.Schema{
+		URL:           "docs/spec/errors/docs/spec/rum_v3_context.json",
+		MinProperties: -1,
+		MaxProperties: -1,
+		MinItems:      -1,
+		MaxItems:      -1,
+		MinLength:     -1,
+		MaxLength:     -1,
+	}
+	s[28] = jsonschema.Schema{
+		URL:           "docs/spec/errors/docs/spec/rum_v3_context.json",
+		Types:         []string{"object", "null"},
+		MinProperties: -1,
+		MaxProperties: -1,
+		PatternProperties: map[*regexp.Regexp]*jsonschema.Schema{
+			regexp.MustCompile("^[^.*\"]*$"): &s[29],
+		},
+		AdditionalProperties: false,
+		MinItems:             -1,
+		MaxItems:             -1,
+		MinLength:            -1,
+		MaxLength:            -1,
+	}
+	s[29] = jsonschema.Schema{
+		URL:           "docs/spec/errors/docs/spec/docs/spec/tags.json",
+		Types:         []string{"string", "boolean", "number", "null"},
+		MinProperties: -1,
+		MaxProperties: -1,
+		MinItems:      -1,
+		MaxItems:      -1,
+		MinLength:     -1,
+		MaxLength:     1024,
+	}
+	s[30] = jsonschema.Schema{
+		URL:           "docs/spec/errors/docs/spec/rum_v3_context.json",
+		Types:         []string{"object", "null"},
+		MinProperties: -1,
+		MaxProperties: -1,
+		Properties: map[string]*jsonschema.Schema{
+			"rf":  &s[31],
+			"url": &s[32],
+		},
+		MinItems:  -1,
+		MaxItems:  -1,
+		MinLength: -1,
+		MaxLength: -1,
+	}
+	s[31] = jsonschema.Schema{
+		URL:           "docs/spec/errors/docs/spec/rum_v3_context.json",
+		Types:         []string{"string", "null"},
+		MinProperties: -1,
+		MaxProperties: -1,
+		MinItems:      -1,
+		MaxItems:      -1,
+		MinLength:     -1,
+		MaxLength:     -1,
+	}
+	s[32] = jsonschema.Schema{
+		URL:           "docs/spec/errors/docs/spec/rum_v3_context.json",
+		Types:         []string{"string", "null"},
+		MinProperties: -1,
+		MaxProperties: -1,
+		MinItems:      -1,
+		MaxItems:      -1,
+		MinLength:     -1,
+		MaxLength:     -1,
+	}
+	s[33] = jsonschema.Schema{
+		URL:           "docs/spec/errors/docs/spec/rum_v3_context.json",
+		MinProperties: -1,
+		MaxProperties: -1,
+		Required:      []string{"mt"},
+		Properties: map[string]*jsonschema.Schema{
+			"en":  &s[34],
+			"he":  &s[35],
+			"hve": &s[38],
+			"mt":  &s[39],
+		},
+		MinItems:  -1,
+		MaxItems:  -1,
+		MinLength: -1,
+		MaxLength: -1,
+	}
+	s[34] = jsonschema.Schema{
+		URL:           "docs/spec/errors/docs/spec/rum_v3_context.json",
+		Types:         []string{"object", "null"},
+		MinProperties: -1,
+		MaxProperties: -1,
+		Properties:    map[string]*jsonschema.Schema{},
+		MinItems:      -1,
+		MaxItems:      -1,
+		MinLength:     -1,
+		MaxLength:     -1,
+	}
+	s[35] = jsonschema.Schema{
+		URL:           "docs/spec/errors/docs/spec/rum_v3_context.json",
+		Types:         []string{"object", "null"},
+		MinProperties: -1,
+		MaxProperties: -1,
+		PatternProperties: map[*regexp.Regexp]*jsonschema.Schema{
+			regexp.MustCompile("[.*]*$"): &s[36],
+		},
+		MinItems:  -1,
+		MaxItems:  -1,
+		MinLength: -1,
+		MaxLength: -1,
+	}
+	s[36] = jsonschema.Schema{
+		URL:           "docs/spec/errors/docs/spec/rum_v3_context.json",
+		Types:         []string{"string", "array", "null"},
+		MinProperties: -1,
+		MaxProperties: -1,
+		MinItems:      -1,
+		MaxItems:      -1,
+		Items:         &s[37],
+		MinLength:     -1,
+		MaxLength:     -1,
+	}
+	s[37] = jsonschema.Schema{
+		URL:           "docs/spec/errors/docs/spec/rum_v3_context.json",
+		Types:         []string{"string"},
+		MinProperties: -1,
+		MaxProperties: -1,
+		MinItems:      -1,
+		MaxItems:      -1,
+		MinLength:     -1,
+		MaxLength:     -1,
+	}
+	s[38] = jsonschema.Schema{
+		URL:           "docs/spec/errors/docs/spec/rum_v3_context.json",
+		Types:         []string{"string", "null"},
+		MinProperties: -1,
+		MaxProperties: -1,
+		MinItems:      -1,
+		MaxItems:      -1,
+		MinLength:     -1,
+		MaxLength:     1024,
+	}
+	s[39] = jsonschema.Schema{
+		URL:           "docs/spec/errors/docs/spec/rum_v3_context.json",
+		Types:         []string{"string"},
+		MinProperties: -1,
+		MaxProperties: -1,
+		MinItems:      -1,
+		MaxItems:      -1,
+		MinLength:     -1,
+		MaxLength:     1024,
+	}
+	s[40] = jsonschema.Schema{
+		URL:           "docs/spec/errors/docs/spec/rum_v3_context.json",
+		Types:         []string{"object", "null"},
+		AllOf:         []*jsonschema.Schema{&s[41]},
+		MinProperties: -1,
+		MaxProperties: -1,
+		MinItems:      -1,
+		MaxItems:      -1,
+		MinLength:     -1,
+		MaxLength:     -1,
+	}
+	s[41] = jsonschema.Schema{
+		URL:           "docs/spec/errors/docs/spec/rum_v3_context.json",
+		MinProperties: -1,
+		MaxProperties: -1,
+		Properties: map[string]*jsonschema.Schema{
+			"dbs": &s[42],
+			"ebs": &s[43],
+			"he":  &s[44],
+			"sc":  &s[47],
+			"ts":  &s[48],
+		},
+		MinItems:  -1,
+		MaxItems:  -1,
+		MinLength: -1,
+		MaxLength: -1,
+	}
+	s[42] = jsonschema.Schema{
+		URL:           "docs/spec/errors/docs/spec/rum_v3_context.json",
+		Types:         []string{"number", "null"},
+		MinProperties: -1,
+		MaxProperties: -1,
+		MinItems:      -1,
+		MaxItems:      -1,
+		MinLength:     -1,
+		MaxLength:     -1,
+	}
+	s[43] = jsonschema.Schema{
+		URL:           "docs/spec/errors/docs/spec/rum_v3_context.json",
+		Types:         []string{"number", "null"},
+		MinProperties: -1,
+		MaxProperties: -1,
+		MinItems:      -1,
+		MaxItems:      -1,
+		MinLength:     -1,
+		MaxLength:     -1,
+	}
+	s[44] = jsonschema.Schema{
+		URL:           "docs/spec/errors/docs/spec/rum_v3_context.json",
+		Types:         []string{"object", "null"},
+		MinProperties: -1,
+		MaxProperties: -1,
+		PatternProperties: map[*regexp.Regexp]*jsonschema.Schema{
+			regexp.MustCompile("[.*]*$"): &s[45],
+		},
+		MinItems:  -1,
+		MaxItems:  -1,
+		MinLength: -1,
+		MaxLength: -1,
+	}
+	s[45] = jsonschema.Schema{
+		URL:           "docs/spec/errors/docs/spec/rum_v3_context.json",
+		Types:         []string{"string", "array", "null"},
+		MinProperties: -1,
+		MaxProperties: -1,
+		MinItems:      -1,
+		MaxItems:      -1,
+		Items:         &s[46],
+		MinLength:     -1,
+		MaxLength:     -1,
+	}
+	s[46] = jsonschema.Schema{
+		URL:           "docs/spec/errors/docs/spec/rum_v3_context.json",
+		Types:         []string{"string"},
+		MinProperties: -1,
+		MaxProperties: -1,
+		MinItems:      -1,
+		MaxItems:      -1,
+		MinLength:     -1,
+		MaxLength:     -1,
+	}
+	s[47] = jsonschema.Schema{
+		URL:           "docs/spec/errors/docs/spec/rum_v3_context.json",
+		Types:         []string{"integer", "null"},
+		MinProperties: -1,
+		MaxProperties: -1,
+		MinItems:      -1,
+		MaxItems:      -1,
+		MinLength:     -1,
+		MaxLength:     -1,
+	}
+	s[48] = jsonschema.Schema{
+		URL:           "docs/spec/errors/docs/spec/rum_v3_context.json",
+		Types:         []string{"number", "null"},
+		MinProperties: -1,
+		MaxProperties: -1,
+		MinItems:      -1,
+		MaxItems:      -1,
+		MinLength:     -1,
+		MaxLength:     -1,
+	}
+	s[49] = jsonschema.Schema{
+		URL:           "docs/spec/errors/docs/spec/rum_v3_context.json",
+		Types:         []string{"object", "null"},
+		MinProperties: -1,
+		MaxProperties: -1,
+		Properties: map[string]*jsonschema.Schema{
+			"a":  &s[50],
+			"en": &s[53],
+			"fw": &s[54],
+			"la": &s[57],
+			"n":  &s[60],
+			"ru": &s[61],
+			"ve": &s[64],
+		},
+		MinItems:  -1,
+		MaxItems:  -1,
+		MinLength: -1,
+		MaxLength: -1,
+	}
+	s[50] = jsonschema.Schema{
+		URL:           "docs/spec/errors/docs/spec/docs/spec/rum_v3_service.json",
+		Types:         []string{"object", "null"},
+		MinProperties: -1,
+		MaxProperties: -1,
+		Properties: map[string]*jsonschema.Schema{
+			"n":  &s[51],
+			"ve": &s[52],
+		},
+		MinItems:  -1,
+		MaxItems:  -1,
+		MinLength: -1,
+		MaxLength: -1,
+	}
+	s[51] = jsonschema.Schema{
+		URL:           "docs/spec/errors/docs/spec/docs/spec/rum_v3_service.json",
+		Types:         []string{"string", "null"},
+		MinProperties: -1,
+		MaxProperties: -1,
+		MinItems:      -1,
+		MaxItems:      -1,
+		MinLength:     -1,
+		MaxLength:     1024,
+	}
+	s[52] = jsonschema.Schema{
+		URL:           "docs/spec/errors/docs/spec/docs/spec/rum_v3_service.json",
+		Types:         []string{"string", "null"},
+		MinProperties: -1,
+		MaxProperties: -1,
+		MinItems:      -1,
+		MaxItems:      -1,
+		MinLength:     -1,
+		MaxLength:     1024,
+	}
+	s[53] = jsonschema.Schema{
+		URL:           "docs/spec/errors/docs/spec/docs/spec/rum_v3_service.json",
+		Types:         []string{"string", "null"},
+		MinProperties: -1,
+		MaxProperties: -1,
+		MinItems:      -1,
+		MaxItems:      -1,
+		MinLength:     -1,
+		MaxLength:     1024,
+	}
+	s[54] = jsonschema.Schema{
+		URL:           "docs/spec/errors/docs/spec/docs/spec/rum_v3_service.json",
+		Types:         []string{"object", "null"},
+		MinProperties: -1,
+		MaxProperties: -1,
+		Properties: map[string]*jsonschema.Schema{
+			"n":  &s[55],
+			"ve": &s[56],
+		},
+		MinItems:  -1,
+		MaxItems:  -1,
+		MinLength: -1,
+		MaxLength: -1,
+	}
+	s[55] = jsonschema.Schema{
+		URL:           "docs/spec/errors/docs/spec/docs/spec/rum_v3_service.json",
+		Types:         []string{"string", "null"},
+		MinProperties: -1,
+		MaxProperties: -1,
+		MinItems:      -1,
+		MaxItems:      -1,
+		MinLength:     -1,
+		MaxLength:     1024,
+	}
+	s[56] = jsonschema.Schema{
+		URL:           "docs/spec/errors/docs/spec/docs/spec/rum_v3_service.json",
+		Types:         []string{"string", "null"},
+		MinProperties: -1,
+		MaxProperties: -1,
+		MinItems:      -1,
+		MaxItems:      -1,
+		MinLength:     -1,
+		MaxLength:     1024,
+	}
+	s[57] = jsonschema.Schema{
+		URL:           "docs/spec/errors/docs/spec/docs/spec/rum_v3_service.json",
+		Types:         []string{"object", "null"},
+		MinProperties: -1,
+		MaxProperties: -1,
+		Properties: map[string]*jsonschema.Schema{
+			"n":  &s[58],
+			"ve": &s[59],
+		},
+		MinItems:  -1,
+		MaxItems:  -1,
+		MinLength: -1,
+		MaxLength: -1,
+	}
+	s[58] = jsonschema.Schema{
+		URL:           "docs/spec/errors/docs/spec/docs/spec/rum_v3_service.json",
+		Types:         []string{"string", "null"},
+		MinProperties: -1,
+		MaxProperties: -1,
+		MinItems:      -1,
+		MaxItems:      -1,
+		MinLength:     -1,
+		MaxLength:     1024,
+	}
+	s[59] = jsonschema.Schema{
+		URL:           "docs/spec/errors/docs/spec/docs/spec/rum_v3_service.json",
+		Types:         []string{"string", "null"},
+		MinProperties: -1,
+		MaxProperties: -1,
+		MinItems:      -1,
+		MaxItems:      -1,
+		MinLength:     -1,
+		MaxLength:     1024,
+	}
+	s[60] = jsonschema.Schema{
+		URL:           "docs/spec/errors/docs/spec/docs/spec/rum_v3_service.json",
+		Types:         []string{"string", "null"},
+		MinProperties: -1,
+		MaxProperties: -1,
+		MinItems:      -1,
+		MaxItems:      -1,
+		MinLength:     -1,
+		MaxLength:     1024,
+		Pattern:       regexp.MustCompile("^[a-zA-Z0-9 _-]+$"),
+	}
+	s[61] = jsonschema.Schema{
+		URL:           "docs/spec/errors/docs/spec/docs/spec/rum_v3_service.json",
+		Types:         []string{"object", "null"},
+		MinProperties: -1,
+		MaxProperties: -1,
+		Properties: map[string]*jsonschema.Schema{
+			"n":  &s[62],
+			"ve": &s[63],
+		},
+		MinItems:  -1,
+		MaxItems:  -1,
+		MinLength: -1,
+		MaxLength: -1,
+	}
+	s[62] = jsonschema.Schema{
+		URL:           "docs/spec/errors/docs/spec/docs/spec/rum_v3_service.json",
+		Types:         []string{"string", "null"},
+		MinProperties: -1,
+		MaxProperties: -1,
+		MinItems:      -1,
+		MaxItems:      -1,
+		MinLength:     -1,
+		MaxLength:     1024,
+	}
+	s[63] = jsonschema.Schema{
+		URL:           "docs/spec/errors/docs/spec/docs/spec/rum_v3_service.json",
+		Types:         []string{"string", "null"},
+		MinProperties: -1,
+		MaxProperties: -1,
+		MinItems:      -1,
+		MaxItems:      -1,
+		MinLength:     -1,
+		MaxLength:     1024,
+	}
+	s[64] = jsonschema.Schema{
+		URL:           "docs/spec/errors/docs/spec/docs/spec/rum_v3_service.json",
+		Types:         []string{"string", "null"},
+		MinProperties: -1,
+		MaxProperties: -1,
+		MinItems:      -1,
+		MaxItems:      -1,
+		MinLength:     -1,
+		MaxLength:     1024,
+	}
+	s[65] = jsonschema.Schema{
+		URL:           "docs/spec/errors/docs/spec/rum_v3_context.json",
+		Types:         []string{"object", "null"},
+		MinProperties: -1,
+		MaxProperties: -1,
+		Properties: map[string]*jsonschema.Schema{
+			"em": &s[66],
+			"id": &s[67],
+			"un": &s[68],
+		},
+		MinItems:  -1,
+		MaxItems:  -1,
+		MinLength: -1,
+		MaxLength: -1,
+	}
+	s[66] = jsonschema.Schema{
+		URL:           "docs/spec/errors/docs/spec/docs/spec/rum_v3_user.json",
+		Types:         []string{"string", "null"},
+		MinProperties: -1,
+		MaxProperties: -1,
+		MinItems:      -1,
+		MaxItems:      -1,
+		MinLength:     -1,
+		MaxLength:     1024,
+	}
+	s[67] = jsonschema.Schema{
+		URL:           "docs/spec/errors/docs/spec/docs/spec/rum_v3_user.json",
+		Types:         []string{"string", "integer", "null"},
+		MinProperties: -1,
+		MaxProperties: -1,
+		MinItems:      -1,
+		MaxItems:      -1,
+		MinLength:     -1,
+		MaxLength:     1024,
+	}
+	s[68] = jsonschema.Schema{
+		URL:           "docs/spec/errors/docs/spec/docs/spec/rum_v3_user.json",
+		Types:         []string{"string", "null"},
+		MinProperties: -1,
+		MaxProperties: -1,
+		MinItems:      -1,
+		MaxItems:      -1,
+		MinLength:     -1,
+		MaxLength:     1024,
+	}
+	s[69] = jsonschema.Schema{
+		URL:           "docs/spec/errors/rum_v3_error.json",
+		Types:         []string{"string", "null"},
+		MinProperties: -1,
+		MaxProperties: -1,
+		MinItems:      -1,
+		MaxItems:      -1,
+		MinLength:     -1,
+		MaxLength:     1024,
+	}
+	s[70] = jsonschema.Schema{
+		URL:           "docs/spec/errors/rum_v3_error.json",
+		Types:         []string{"object", "null"},
+		AnyOf:         []*jsonschema.Schema{&s[71], &s[73]},
+		MinProperties: -1,
+		MaxProperties: -1,
+		Properties: map[string]*jsonschema.Schema{
+			"at": &s[75],
+			"ca": &s[76],
+			"cd": &s[78],
+			"hd": &s[79],
+			"mg": &s[80],
+			"mo": &s[81],
+			"st": &s[82],
+			"t":  &s[96],
+		},
+		MinItems:  -1,
+		MaxItems:  -1,
+		MinLength: -1,
+		MaxLength: -1,
+	}
+	s[71] = jsonschema.Schema{
+		URL:           "docs/spec/errors/rum_v3_error.json",
+		MinProperties: -1,
+		MaxProperties: -1,
+		Required:      []string{"mg"},
+		Properties: map[string]*jsonschema.Schema{
+			"mg": &s[72],
+		},
+		MinItems:  -1,
+		MaxItems:  -1,
+		MinLength: -1,
+		MaxLength: -1,
+	}
+	s[72] = jsonschema.Schema{
+		URL:           "docs/spec/errors/rum_v3_error.json",
+		Types:         []string{"string"},
+		MinProperties: -1,
+		MaxProperties: -1,
+		MinItems:      -1,
+		MaxItems:      -1,
+		MinLength:     -1,
+		MaxLength:     -1,
+	}
+	s[73] = jsonschema.Schema{
+		URL:           "docs/spec/errors/rum_v3_error.json",
+		MinProperties: -1,
+		MaxProperties: -1,
+		Required:      []string{"t"},
+		Properties: map[string]*jsonschema.Schema{
+			"t": &s[74],
+		},
+		MinItems:  -1,
+		MaxItems:  -1,
+		MinLength: -1,
+		MaxLength: -1,
+	}
+	s[74] = jsonschema.Schema{
+		URL:           "docs/spec/errors/rum_v3_error.json",
+		Types:         []string{"string"},
+		MinProperties: -1,
+		MaxProperties: -1,
+		MinItems:      -1,
+		MaxItems:      -1,
+		MinLength:     -1,
+		MaxLength:     -1,
+	}
+	s[75] = jsonschema.Schema{
+		URL:           "docs/spec/errors/rum_v3_error.json",
+		Types:         []string{"object", "null"},
+		MinProperties: -1,
+		MaxProperties: -1,
+		MinItems:      -1,
+		MaxItems:      -1,
+		MinLength:     -1,
+		MaxLength:     -1,
+	}
+	s[76] = jsonschema.Schema{
+		URL:           "docs/spec/errors/rum_v3_error.json",
+		Types:         []string{"array", "null"},
+		MinProperties: -1,
+		MaxProperties: -1,
+		MinItems:      0,
+		MaxItems:      -1,
+		Items:         &s[77],
+		MinLength:     -1,
+		MaxLength:     -1,
+	}
+	s[77] = jsonschema.Schema{
+		URL:           "docs/spec/errors/rum_v3_error.json",
+		Types:         []string{"object", "null"},
+		MinProperties: -1,
+		MaxProperties: -1,
+		MinItems:      -1,
+		MaxItems:      -1,
+		MinLength:     -1,
+		MaxLength:     -1,
+	}
+	s[78] = jsonschema.Schema{
+		URL:           "docs/spec/errors/rum_v3_error.json",
+		Types:         []string{"string", "integer", "null"},
+		MinProperties: -1,
+		MaxProperties: -1,
+		MinItems:      -1,
+		MaxItems:      -1,
+		MinLength:     -1,
+		MaxLength:     1024,
+	}
+	s[79] = jsonschema.Schema{
+		URL:           "docs/spec/errors/rum_v3_error.json",
+		Types:         []string{"boolean", "null"},
+		MinProperties: -1,
+		MaxProperties: -1,
+		MinItems:      -1,
+		MaxItems:      -1,
+		MinLength:     -1,
+		MaxLength:     -1,
+	}
+	s[80] = jsonschema.Schema{
+		URL:           "docs/spec/errors/rum_v3_error.json",
+		Types:         []string{"string", "null"},
+		MinProperties: -1,
+		MaxProperties: -1,
+		MinItems:      -1,
+		MaxItems:      -1,
+		MinLength:     -1,
+		MaxLength:     -1,
+	}
+	s[81] = jsonschema.Schema{
+		URL:           "docs/spec/errors/rum_v3_error.json",
+		Types:         []string{"string", "null"},
+		MinProperties: -1,
+		MaxProperties: -1,
+		MinItems:      -1,
+		MaxItems:      -1,
+		MinLength:     -1,
+		MaxLength:     1024,
+	}
+	s[82] = jsonschema.Schema{
+		URL:           "docs/spec/errors/rum_v3_error.json",
+		Types:         []string{"array", "null"},
+		MinProperties: -1,
+		MaxProperties: -1,
+		MinItems:      0,
+		MaxItems:      -1,
+		Items:         &s[83],
+		MinLength:     -1,
+		MaxLength:     -1,
+	}
+	s[83] = jsonschema.Schema{
+		URL:           "docs/spec/errors/rum_v3_error.json",
+		Types:         []string{"object"},
+		MinProperties: -1,
+		MaxProperties: -1,
+		Required:      []string{"f"},
+		Properties: map[string]*jsonschema.Schema{
+			"ap":  &s[84],
+			"cli": &s[85],
+			"cn":  &s[86],
+			"co":  &s[87],
+			"f":   &s[88],
+			"fn":  &s[89],
+			"li":  &s[90],
+			"mo":  &s[91],
+			"poc": &s[92],
+			"prc": &s[94],
+		},
+		MinItems:  -1,
+		MaxItems:  -1,
+		MinLength: -1,
+		MaxLength: -1,
+	}
+	s[84] = jsonschema.Schema{
+		URL:           "docs/spec/errors/docs/spec/rum_v3_stacktrace_frame.json",
+		Types:         []string{"string", "null"},
+		MinProperties: -1,
+		MaxProperties: -1,
+		MinItems:      -1,
+		MaxItems:      -1,
+		MinLength:     -1,
+		MaxLength:     -1,
+	}
+	s[85] = jsonschema.Schema{
+		URL:           "docs/spec/errors/docs/spec/rum_v3_stacktrace_frame.json",
+		Types:         []string{"string", "null"},
+		MinProperties: -1,
+		MaxProperties: -1,
+		MinItems:      -1,
+		MaxItems:      -1,
+		MinLength:     -1,
+		MaxLength:     -1,
+	}
+	s[86] = jsonschema.Schema{
+		URL:           "docs/spec/errors/docs/spec/rum_v3_stacktrace_frame.json",
+		Types:         []string{"string", "null"},
+		MinProperties: -1,
+		MaxProperties: -1,
+		MinItems:      -1,
+		MaxItems:      -1,
+		MinLength:     -1,
+		MaxLength:     -1,
+	}
+	s[87] = jsonschema.Schema{
+		URL:           "docs/spec/errors/docs/spec/rum_v3_stacktrace_frame.json",
+		Types:         []string{"integer", "null"},
+		MinProperties: -1,
+		MaxProperties: -1,
+		MinItems:      -1,
+		MaxItems:      -1,
+		MinLength:     -1,
+		MaxLength:     -1,
+	}
+	s[88] = jsonschema.Schema{
+		URL:           "docs/spec/errors/docs/spec/rum_v3_stacktrace_frame.json",
+		Types:         []string{"string", "null"},
+		MinProperties: -1,
+		MaxProperties: -1,
+		MinItems:      -1,
+		MaxItems:      -1,
+		MinLength:     -1,
+		MaxLength:     -1,
+	}
+	s[89] = jsonschema.Schema{
+		URL:           "docs/spec/errors/docs/spec/rum_v3_stacktrace_frame.json",
+		Types:         []string{"string", "null"},
+		MinProperties: -1,
+		MaxProperties: -1,
+		MinItems:      -1,
+		MaxItems:      -1,
+		MinLength:     -1,
+		MaxLength:     -1,
+	}
+	s[90] = jsonschema.Schema{
+		URL:           "docs/spec/errors/docs/spec/rum_v3_stacktrace_frame.json",
+		Types:         []string{"integer", "null"},
+		MinProperties: -1,
+		MaxProperties: -1,
+		MinItems:      -1,
+		MaxItems:      -1,
+		MinLength:     -1,
+		MaxLength:     -1,
+	}
+	s[91] = jsonschema.Schema{
+		URL:           "docs/spec/errors/docs/spec/rum_v3_stacktrace_frame.json",
+		Types:         []string{"string", "null"},
+		MinProperties: -1,
+		MaxProperties: -1,
+		MinItems:      -1,
+		MaxItems:      -1,
+		MinLength:     -1,
+		MaxLength:     -1,
+	}
+	s[92] = jsonschema.Schema{
+		URL:           "docs/spec/errors/docs/spec/rum_v3_stacktrace_frame.json",
+		Types:         []string{"array", "null"},
+		MinProperties: -1,
+		MaxProperties: -1,
+		MinItems:      0,
+		MaxItems:      -1,
+		Items:         &s[93],
+		MinLength:     -1,
+		MaxLength:     -1,
+	}
+	s[93] = jsonschema.Schema{
+		URL:           "docs/spec/errors/docs/spec/rum_v3_stacktrace_frame.json",
+		Types:         []string{"string"},
+		MinProperties: -1,
+		MaxProperties: -1,
+		MinItems:      -1,
+		MaxItems:      -1,
+		MinLength:     -1,
+		MaxLength:     -1,
+	}
+	s[94] = jsonschema.Schema{
+		URL:           "docs/spec/errors/docs/spec/rum_v3_stacktrace_frame.json",
+		Types:         []string{"array", "null"},
+		MinProperties: -1,
+		MaxProperties: -1,
+		MinItems:      0,
+		MaxItems:      -1,
+		Items:         &s[95],
+		MinLength:     -1,
+		MaxLength:     -1,
+	}
+	s[95] = jsonschema.Schema{
+		URL:           "docs/spec/errors/docs/spec/rum_v3_stacktrace_frame.json",
+		Types:         []string{"string"},
+		MinProperties: -1,
+		MaxProperties: -1,
+		MinItems:      -1,
+		MaxItems:      -1,
+		MinLength:     -1,
+		MaxLength:     -1,
+	}
+	s[96] = jsonschema.Schema{
+		URL:           "docs/spec/errors/rum_v3_error.json",
+		Types:         []string{"string", "null"},
+		MinProperties: -1,
+		MaxProperties: -1,
+		MinItems:      -1,
+		MaxItems:      -1,
+		MinLength:     -1,
+		MaxLength:     1024,
+	}
+	s[97] = jsonschema.Schema{
+		URL:           "docs/spec/errors/rum_v3_error.json",
+		Types:         []string{"string"},
+		MinProperties: -1,
+		MaxProperties: -1,
+		MinItems:      -1,
+		MaxItems:      -1,
+		MinLength:     -1,
+		MaxLength:     1024,
+	}
+	s[98] = jsonschema.Schema{
+		URL:           "docs/spec/errors/rum_v3_error.json",
+		Types:         []string{"object", "null"},
+		MinProperties: -1,
+		MaxProperties: -1,
+		Required:      []string{"mg"},
+		Properties: map[string]*jsonschema.Schema{
+			"ln":  &s[99],
+			"lv":  &s[100],
+			"mg":  &s[101],
+			"pmg": &s[102],
+			"st":  &s[103],
+		},
+		MinItems:  -1,
+		MaxItems:  -1,
+		MinLength: -1,
+		MaxLength: -1,
+	}
+	s[99] = jsonschema.Schema{
+		URL:           "docs/spec/errors/rum_v3_error.json",
+		Types:         []string{"string", "null"},
+		MinProperties: -1,
+		MaxProperties: -1,
+		MinItems:      -1,
+		MaxItems:      -1,
+		MinLength:     -1,
+		MaxLength:     1024,
+	}
+	s[100] = jsonschema.Schema{
+		URL:           "docs/spec/errors/rum_v3_error.json",
+		Types:         []string{"string", "null"},
+		MinProperties: -1,
+		MaxProperties: -1,
+		MinItems:      -1,
+		MaxItems:      -1,
+		MinLength:     -1,
+		MaxLength:     1024,
+	}
+	s[101] = jsonschema.Schema{
+		URL:           "docs/spec/errors/rum_v3_error.json",
+		Types:         []string{"string"},
+		MinProperties: -1,
+		MaxProperties: -1,
+		MinItems:      -1,
+		MaxItems:      -1,
+		MinLength:     -1,
+		MaxLength:     -1,
+	}
+	s[102] = jsonschema.Schema{
+		URL:           "docs/spec/errors/rum_v3_error.json",
+		Types:         []string{"string", "null"},
+		MinProperties: -1,
+		MaxProperties: -1,
+		MinItems:      -1,
+		MaxItems:      -1,
+		MinLength:     -1,
+		MaxLength:     1024,
+	}
+	s[103] = jsonschema.Schema{
+		URL:           "docs/spec/errors/rum_v3_error.json",
+		Types:         []string{"array", "null"},
+		MinProperties: -1,
+		MaxProperties: -1,
+		MinItems:      0,
+		MaxItems:      -1,
+		Items:         &s[104],
+		MinLength:     -1,
+		MaxLength:     -1,
+	}
+	s[104] = jsonschema.Schema{
+		URL:           "docs/spec/errors/rum_v3_error.json",
+		Types:         []string{"object"},
+		MinProperties: -1,
+		MaxProperties: -1,
+		Required:      []string{"f"},
+		Properties: map[string]*jsonschema.Schema{
+			"ap":  &s[105],
+			"cli": &s[106],
+			"cn":  &s[107],
+			"co":  &s[108],
+			"f":   &s[109],
+			"fn":  &s[110],
+			"li":  &s[111],
+			"mo":  &s[112],
+			"poc": &s[113],
+			"prc": &s[115],
+		},
+		MinItems:  -1,
+		MaxItems:  -1,
+		MinLength: -1,
+		MaxLength: -1,
+	}
+	s[105] = jsonschema.Schema{
+		URL:           "docs/spec/errors/docs/spec/rum_v3_stacktrace_frame.json",
+		Types:         []string{"string", "null"},
+		MinProperties: -1,
+		MaxProperties: -1,
+		MinItems:      -1,
+		MaxItems:      -1,
+		MinLength:     -1,
+		MaxLength:     -1,
+	}
+	s[106] = jsonschema.Schema{
+		URL:           "docs/spec/errors/docs/spec/rum_v3_stacktrace_frame.json",
+		Types:         []string{"string", "null"},
+		MinProperties: -1,
+		MaxProperties: -1,
+		MinItems:      -1,
+		MaxItems:      -1,
+		MinLength:     -1,
+		MaxLength:     -1,
+	}
+	s[107] = jsonschema.Schema{
+		URL:           "docs/spec/errors/docs/spec/rum_v3_stacktrace_frame.json",
+		Types:         []string{"string", "null"},
+		MinProperties: -1,
+		MaxProperties: -1,
+		MinItems:      -1,
+		MaxItems:      -1,
+		MinLength:     -1,
+		MaxLength:     -1,
+	}
+	s[108] = jsonschema.Schema{
+		URL:           "docs/spec/errors/docs/spec/rum_v3_stacktrace_frame.json",
+		Types:         []string{"integer", "null"},
+		MinProperties: -1,
+		MaxProperties: -1,
+		MinItems:      -1,
+		MaxItems:      -1,
+		MinLength:     -1,
+		MaxLength:     -1,
+	}
+	s[109] = jsonschema.Schema{
+		URL:           "docs/spec/errors/docs/spec/rum_v3_stacktrace_frame.json",
+		Types:         []string{"string", "null"},
+		MinProperties: -1,
+		MaxProperties: -1,
+		MinItems:      -1,
+		MaxItems:      -1,
+		MinLength:     -1,
+		MaxLength:     -1,
+	}
+	s[110] = jsonschema.Schema{
+		URL:           "docs/spec/errors/docs/spec/rum_v3_stacktrace_frame.json",
+		Types:         []string{"string", "null"},
+		MinProperties: -1,
+		MaxProperties: -1,
+		MinItems:      -1,
+		MaxItems:      -1,
+		MinLength:     -1,
+		MaxLength:     -1,
+	}
+	s[111] = jsonschema.Schema{
+		URL:           "docs/spec/errors/docs/spec/rum_v3_stacktrace_frame.json",
+		Types:         []string{"integer", "null"},
+		MinProperties: -1,
+		MaxProperties: -1,
+		MinItems:      -1,
+		MaxItems:      -1,
+		MinLength:     -1,
+		MaxLength:     -1,
+	}
+	s[112] = jsonschema.Schema{
+		URL:           "docs/spec/errors/docs/spec/rum_v3_stacktrace_frame.json",
+		Types:         []string{"string", "null"},
+		MinProperties: -1,
+		MaxProperties: -1,
+		MinItems:      -1,
+		MaxItems:      -1,
+		MinLength:     -1,
+		MaxLength:     -1,
+	}
+	s[113] = jsonschema.Schema{
+		URL:           "docs/spec/errors/docs/spec/rum_v3_stacktrace_frame.json",
+		Types:         []string{"array", "null"},
+		MinProperties: -1,
+		MaxProperties: -1,
+		MinItems:      0,
+		MaxItems:      -1,
+		Items:         &s[114],
+		MinLength:     -1,
+		MaxLength:     -1,
+	}
+	s[114] = jsonschema.Schema{
+		URL:           "docs/spec/errors/docs/spec/rum_v3_stacktrace_frame.json",
+		Types:         []string{"string"},
+		MinProperties: -1,
+		MaxProperties: -1,
+		MinItems:      -1,
+		MaxItems:      -1,
+		MinLength:     -1,
+		MaxLength:     -1,
+	}
+	s[115] = jsonschema.Schema{
+		URL:           "docs/spec/errors/docs/spec/rum_v3_stacktrace_frame.json",
+		Types:         []string{"array", "null"},
+		MinProperties: -1,
+		MaxProperties: -1,
+		MinItems:      0,
+		MaxItems:      -1,
+		Items:         &s[116],
+		MinLength:     -1,
+		MaxLength:     -1,
+	}
+	s[116] = jsonschema.Schema{
+		URL:           "docs/spec/errors/docs/spec/rum_v3_stacktrace_frame.json",
+		Types:         []string{"string"},
+		MinProperties: -1,
+		MaxProperties: -1,
+		MinItems:      -1,
+		MaxItems:      -1,
+		MinLength:     -1,
+		MaxLength:     -1,
+	}
+	s[117] = jsonschema.Schema{
+		URL:           "docs/spec/errors/rum_v3_error.json",
+		Types:         []string{"string", "null"},
+		MinProperties: -1,
+		MaxProperties: -1,
+		MinItems:      -1,
+		MaxItems:      -1,
+		MinLength:     -1,
+		MaxLength:     1024,
+	}
+	s[118] = jsonschema.Schema{
+		URL:           "docs/spec/errors/rum_v3_error.json",
+		Types:         []string{"string", "null"},
+		MinProperties: -1,
+		MaxProperties: -1,
+		MinItems:      -1,
+		MaxItems:      -1,
+		MinLength:     -1,
+		MaxLength:     1024,
+	}
+	s[119] = jsonschema.Schema{
+		URL:           "docs/spec/errors/rum_v3_error.json",
+		Types:         []string{"object", "null"},
+		MinProperties: -1,
+		MaxProperties: -1,
+		Properties: map[string]*jsonschema.Schema{
+			"sm": &s[120],
+			"t":  &s[121],
+		},
+		MinItems:  -1,
+		MaxItems:  -1,
+		MinLength: -1,
+		MaxLength: -1,
+	}
+	s[120] = jsonschema.Schema{
+		URL:           "docs/spec/errors/rum_v3_error.json",
+		Types:         []string{"boolean", "null"},
+		MinProperties: -1,
+		MaxProperties: -1,
+		MinItems:      -1,
+		MaxItems:      -1,
+		MinLength:     -1,
+		MaxLength:     -1,
+	}
+	s[121] = jsonschema.Schema{
+		URL:           "docs/spec/errors/rum_v3_error.json",
+		Types:         []string{"string", "null"},
+		MinProperties: -1,
+		MaxProperties: -1,
+		MinItems:      -1,
+		MaxItems:      -1,
+		MinLength:     -1,
+		MaxLength:     1024,
+	}
+	s[122] = jsonschema.Schema{
+		URL:           "docs/spec/errors/rum_v3_error.json",
+		Types:         []string{"string", "null"},
+		MinProperties: -1,
+		MaxProperties: -1,
+		MinItems:      -1,
+		MaxItems:      -1,
+		MinLength:     -1,
+		MaxLength:     1024,
+	}
+	return &s[0]
+}
@@ -24,6 +24,7 @@ import (
 	"fmt"
 	"io"
 	"net"
+	"regexp"
 	"testing"
 	"time"
 
@@ -430,6 +431,7 @@ func TestCulprit(t *testing.T) {
 		&StacktraceFrame{Filename: tests.StringPtr("bar"), Function: &fct, SourcemapUpdated: &truthy},
 	}
 	store := &sourcemap.Store{}
+	backendLibraryPattern := regexp.MustCompile("vendor/")
 	tests := []struct {
 		event   Error
 		config  transform.Config
@@ -524,6 +526,29 @@ func TestCulprit(t *testing.T) {
 			culprit: "a in fct",
 			msg:     "Log Stacktrace is prioritized over Exception StacktraceFrame",
 		},
+		{
+			event: Error{
+				Culprit:   &c,
+				Exception: &Exception{Stacktrace: st},
+			},
+			config:  transform.Config{BackendLibraryPattern: backendLibraryPattern},
+			culprit: "foo",
+			msg:     "Backend library pattern, no frames marked",
+		},
+		{
+			event: Error{
+				Culprit: &c,
+				Exception: &Exception{
+					Stacktrace: Stacktrace{
+						&StacktraceFrame{Filename: tests.StringPtr("vendor/lib.go"), LibraryFrame: &truthy, libraryFrameMarked: true},
+						&StacktraceFrame{Classname: tests.StringPtr("app.Handler"), Function: &fct},
+					},
+				},
+			},
+			config:  transform.Config{BackendLibraryPattern: backendLibraryPattern},
+			culprit: "app.Handler in fct",
+			msg:     "Backend library pattern, culprit adapted to first non-library frame",
+		},
 	}
 	for idx, test := range tests {
 		t.Run(fmt.Sprint(idx), func(t *testing.T) {
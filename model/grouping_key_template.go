@@ -0,0 +1,149 @@
+// Licensed to Elasticsearch B.V. under one or more contributor
+// license agreements. See the NOTICE file distributed with
+// this work for additional information regarding copyright
+// ownership. Elasticsearch B.V. licenses this file to you under
+// the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package model
+
+import (
+	"io/ioutil"
+	"strings"
+	"text/template"
+)
+
+// GroupingKeyData holds the fields of an error available to
+// grouping key templates.
+type GroupingKeyData struct {
+	// ServiceName holds the name of the service reporting the error.
+	ServiceName string
+
+	// ServiceEnvironment holds the environment of the service
+	// reporting the error.
+	ServiceEnvironment string
+
+	// ExceptionType holds the type of the outermost exception.
+	ExceptionType string
+
+	// ExceptionTypes holds the types of all exceptions in the chain,
+	// starting with the outermost exception.
+	ExceptionTypes []string
+
+	// ExceptionMessage holds the message of the outermost exception.
+	ExceptionMessage string
+
+	// LogMessage holds the parameterized log message if available,
+	// and otherwise the formatted log message.
+	LogMessage string
+
+	// TransactionType holds the type of the transaction during which
+	// the error occurred, if any.
+	TransactionType string
+
+	// TopFrame holds the first stack frame which is neither a library
+	// frame nor excluded from grouping.
+	TopFrame GroupingKeyFrame
+}
+
+// GroupingKeyFrame holds the fields of a stack frame available to
+// grouping key templates.
+type GroupingKeyFrame struct {
+	Module    string
+	Filename  string
+	Classname string
+	Function  string
+}
+
+// ParseGroupingKeyTemplate parses text as a text/template for computing
+// error grouping keys from GroupingKeyData. The template is executed
+// against an empty GroupingKeyData, to report references to undefined
+// fields when parsing rather than for each error; templates must
+// therefore handle missing fields, e.g. by guarding with "if".
+func ParseGroupingKeyTemplate(text string) (*template.Template, error) {
+	tmpl, err := template.New("grouping_key").Parse(text)
+	if err != nil {
+		return nil, err
+	}
+	if err := tmpl.Execute(ioutil.Discard, &GroupingKeyData{}); err != nil {
+		return nil, err
+	}
+	return tmpl, nil
+}
+
+// groupingKeyData returns the GroupingKeyData for e, given its flattened
+// exception chain.
+func (e *Error) groupingKeyData(chain []Exception) *GroupingKeyData {
+	data := &GroupingKeyData{
+		ServiceName:        e.Metadata.Service.Name,
+		ServiceEnvironment: e.Metadata.Service.Environment,
+		TransactionType:    stringValue(e.TransactionType),
+	}
+	var stacktrace Stacktrace
+	for i, ex := range chain {
+		if i == 0 {
+			data.ExceptionType = stringValue(ex.Type)
+			data.ExceptionMessage = stringValue(ex.Message)
+		}
+		if ex.Type != nil {
+			data.ExceptionTypes = append(data.ExceptionTypes, *ex.Type)
+		}
+		stacktrace = append(stacktrace, ex.Stacktrace...)
+	}
+	if e.Log != nil {
+		data.LogMessage = e.Log.Message
+		if e.Log.ParamMessage != nil {
+			data.LogMessage = *e.Log.ParamMessage
+		}
+		if len(stacktrace) == 0 {
+			stacktrace = e.Log.Stacktrace
+		}
+	}
+	for _, fr := range stacktrace {
+		if fr.ExcludeFromGrouping || fr.IsLibraryFrame() {
+			continue
+		}
+		data.TopFrame = GroupingKeyFrame{
+			Module:    stringValue(fr.Module),
+			Filename:  stringValue(fr.Filename),
+			Classname: stringValue(fr.Classname),
+			Function:  stringValue(fr.Function),
+		}
+		break
+	}
+	return data
+}
+
+// templateGroupingKey returns the grouping key computed by executing
+// tmpl for e, or false if the template fails or produces only whitespace.
+func (e *Error) templateGroupingKey(tmpl *template.Template, chain []Exception) (string, bool) {
+	var sb strings.Builder
+	if err := tmpl.Execute(&sb, e.groupingKeyData(chain)); err != nil {
+		groupingKeyTemplateErrors.Inc()
+		return "", false
+	}
+	s := sb.String()
+	if strings.TrimSpace(s) == "" {
+		return "", false
+	}
+	k := newGroupingKey()
+	k.add(&s)
+	return k.String(), true
+}
+
+func stringValue(s *string) string {
+	if s == nil {
+		return ""
+	}
+	return *s
+}
@@ -0,0 +1,127 @@
+// Licensed to Elasticsearch B.V. under one or more contributor
+// license agreements. See the NOTICE file distributed with
+// this work for additional information regarding copyright
+// ownership. Elasticsearch B.V. licenses this file to you under
+// the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package model
+
+import (
+	"context"
+	"crypto/md5"
+	"encoding/hex"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/elastic/apm-server/tests"
+	"github.com/elastic/apm-server/transform"
+)
+
+func TestParseGroupingKeyTemplate(t *testing.T) {
+	_, err := ParseGroupingKeyTemplate("{{.ServiceName}}/{{.TopFrame.Function}}")
+	assert.NoError(t, err)
+
+	_, err = ParseGroupingKeyTemplate("{{.ServiceName")
+	assert.Error(t, err)
+
+	_, err = ParseGroupingKeyTemplate("{{.Unknown}}")
+	assert.Error(t, err)
+}
+
+func TestGroupingKeyData(t *testing.T) {
+	truthy := true
+	txType := "request"
+	e := Error{
+		Metadata:        Metadata{Service: Service{Name: "svc", Environment: "prod"}},
+		TransactionType: &txType,
+		Exception: &Exception{
+			Type:    tests.StringPtr("OuterError"),
+			Message: tests.StringPtr("outer"),
+			Stacktrace: Stacktrace{
+				&StacktraceFrame{Function: tests.StringPtr("excluded"), ExcludeFromGrouping: true},
+				&StacktraceFrame{Function: tests.StringPtr("lib"), LibraryFrame: &truthy},
+				&StacktraceFrame{Module: tests.StringPtr("app"), Classname: tests.StringPtr("Handler"), Function: tests.StringPtr("handle")},
+			},
+			Cause: []Exception{{Type: tests.StringPtr("InnerError")}},
+		},
+		Log: &Log{Message: "formatted", ParamMessage: tests.StringPtr("param %s")},
+	}
+	assert.Equal(t, &GroupingKeyData{
+		ServiceName:        "svc",
+		ServiceEnvironment: "prod",
+		ExceptionType:      "OuterError",
+		ExceptionTypes:     []string{"OuterError", "InnerError"},
+		ExceptionMessage:   "outer",
+		LogMessage:         "param %s",
+		TransactionType:    "request",
+		TopFrame:           GroupingKeyFrame{Module: "app", Classname: "Handler", Function: "handle"},
+	}, e.groupingKeyData(flattenExceptionTree(e.Exception)))
+}
+
+func TestGroupingKeyTemplate(t *testing.T) {
+	tmpl, err := ParseGroupingKeyTemplate("{{.ServiceName}}|{{.ExceptionType}}|{{.TopFrame.Function}}")
+	require.NoError(t, err)
+	tctx := &transform.Context{Config: transform.Config{GroupingKeyTemplate: tmpl}}
+
+	newError := func(function string) *Error {
+		return &Error{
+			Metadata: Metadata{Service: Service{Name: "svc"}},
+			Exception: &Exception{
+				Type:    tests.StringPtr("Error"),
+				Message: tests.StringPtr("message varies " + function),
+				Stacktrace: Stacktrace{
+					&StacktraceFrame{Function: tests.StringPtr(function)},
+				},
+			},
+		}
+	}
+	groupingKey := func(e *Error) string {
+		events := e.Transform(context.Background(), tctx)
+		require.Len(t, events, 1)
+		key, err := events[0].Fields.GetValue("error.grouping_key")
+		require.NoError(t, err)
+		return key.(string)
+	}
+
+	expected := md5.Sum([]byte("svc|Error|handle"))
+	assert.Equal(t, hex.EncodeToString(expected[:]), groupingKey(newError("handle")))
+	assert.NotEqual(t, groupingKey(newError("handle")), groupingKey(newError("other")))
+}
+
+func TestGroupingKeyTemplateFallback(t *testing.T) {
+	e := &Error{
+		Metadata: Metadata{Service: Service{Name: "svc"}},
+		Log:      &Log{Message: "message"},
+	}
+	defaultKey := e.calcGroupingKey(nil)
+
+	for _, text := range []string{
+		"  {{.ExceptionType}} ",
+		"{{if .ServiceName}}{{index .ExceptionTypes 1}}{{end}}",
+	} {
+		tmpl, err := ParseGroupingKeyTemplate(text)
+		require.NoError(t, err)
+		key, ok := e.templateGroupingKey(tmpl, nil)
+		assert.False(t, ok)
+		assert.Empty(t, key)
+
+		tctx := &transform.Context{Config: transform.Config{GroupingKeyTemplate: tmpl}}
+		events := e.Transform(context.Background(), tctx)
+		actual, err := events[0].Fields.GetValue("error.grouping_key")
+		require.NoError(t, err)
+		assert.Equal(t, defaultKey, actual)
+	}
+}
@@ -17,7 +17,11 @@
 
 package model
 
-import "github.com/elastic/beats/v7/libbeat/common"
+import (
+	"github.com/elastic/beats/v7/libbeat/common"
+
+	"github.com/elastic/apm-server/utility"
+)
 
 type mapStr common.MapStr
 
@@ -43,3 +47,37 @@ func (m *mapStr) maybeSetMapStr(k string, v common.MapStr) bool {
 	}
 	return false
 }
+
+// maybeDeepUpdateMapStr merges v into the map at key k, overriding
+// conflicting keys. v is not copied, so it must be event-specific.
+func (m *mapStr) maybeDeepUpdateMapStr(k string, v common.MapStr) bool {
+	if len(v) == 0 {
+		return false
+	}
+	if existing, ok := (*m)[k].(common.MapStr); ok {
+		existing.DeepUpdate(v)
+	} else {
+		m.set(k, v)
+	}
+	return true
+}
+
+// mergeLabels merges labels into the "labels" map, overriding
+// conflicting keys. Label values are normalised as they are set,
+// e.g. json.Number is converted to a float64 if possible.
+//
+// Any existing "labels" map is updated in place; Metadata.Set
+// always sets an event-specific copy of the metadata labels.
+func (m *mapStr) mergeLabels(labels common.MapStr) {
+	if len(labels) == 0 {
+		return
+	}
+	out, _ := (*m)["labels"].(common.MapStr)
+	if out == nil {
+		out = make(common.MapStr, len(labels))
+	}
+	for k, v := range labels {
+		utility.Update(out, k, v)
+	}
+	m.maybeSetMapStr("labels", out)
+}
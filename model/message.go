@@ -40,10 +40,10 @@ func (m *Message) Fields() common.MapStr {
 	}
 	fields := common.MapStr{}
 	if m.QueueName != nil {
-		utility.Set(fields, "queue", common.MapStr{"name": m.QueueName})
+		fields["queue"] = common.MapStr{"name": *m.QueueName}
 	}
 	if m.AgeMillis != nil {
-		utility.Set(fields, "age", common.MapStr{"ms": m.AgeMillis})
+		fields["age"] = common.MapStr{"ms": *m.AgeMillis}
 	}
 	utility.Set(fields, "body", m.Body)
 	utility.Set(fields, "headers", m.Headers)
@@ -18,6 +18,8 @@
 package model
 
 import (
+	"context"
+
 	"github.com/elastic/beats/v7/libbeat/common"
 
 	"github.com/elastic/apm-server/utility"
@@ -32,9 +34,63 @@ type Metadata struct {
 	Client    Client
 	Cloud     Cloud
 	Labels    common.MapStr
+
+	// TenantID holds the ID of the tenant the events were
+	// ingested for, in multi-tenant mode.
+	TenantID string
+
+	// prepared holds the fields computed by Prepare, shared by
+	// all copies of the metadata.
+	prepared *common.MapStr
+}
+
+// Prepare computes the fields for m once, so they may be shared by all events
+// which copy m, rather than being recomputed for each event. The metadata must
+// not be modified after calling Prepare.
+func (m *Metadata) Prepare() {
+	fields := m.set(common.MapStr{})
+	m.prepared = &fields
+}
+
+// Unprepare discards the fields computed by Prepare. Unprepare must be called
+// before modifying a copy of prepared metadata, such as when merging in
+// event-specific metadata.
+func (m *Metadata) Unprepare() {
+	m.prepared = nil
+}
+
+// Prepared reports whether Prepare has been called, and the computed
+// fields have not since been discarded by Unprepare.
+func (m *Metadata) Prepared() bool {
+	return m.prepared != nil
 }
 
+// tenantContext returns ctx carrying the metadata's tenant ID, if any,
+// so that lookups made while transforming events are scoped by tenant.
+func (m *Metadata) tenantContext(ctx context.Context) context.Context {
+	if m.TenantID == "" {
+		return ctx
+	}
+	return utility.ContextWithTenant(ctx, m.TenantID)
+}
+
+// Set sets the metadata fields in out. If Prepare has been called, the
+// prepared fields are cloned into out, as events merge their own fields
+// into them, and processors may modify them before they are published.
 func (m *Metadata) Set(out common.MapStr) common.MapStr {
+	if m.prepared == nil {
+		return m.set(out)
+	}
+	for k, v := range *m.prepared {
+		if fields, ok := v.(common.MapStr); ok {
+			v = fields.Clone()
+		}
+		out[k] = v
+	}
+	return out
+}
+
+func (m *Metadata) set(out common.MapStr) common.MapStr {
 	fields := (*mapStr)(&out)
 	fields.maybeSetMapStr("service", m.Service.Fields(m.System.Container.ID, m.System.name()))
 	fields.maybeSetMapStr("agent", m.Service.AgentFields())
@@ -46,6 +102,9 @@ func (m *Metadata) Set(out common.MapStr) common.MapStr {
 	fields.maybeSetMapStr("container", m.System.containerFields())
 	fields.maybeSetMapStr("kubernetes", m.System.kubernetesFields())
 	fields.maybeSetMapStr("cloud", m.Cloud.fields())
+	if m.TenantID != "" {
+		fields.set("tenant", common.MapStr{"id": m.TenantID})
+	}
 	if len(m.Labels) > 0 {
 		// These labels are merged with event-specific labels,
 		// hence we clone the map to avoid updating the shared
@@ -184,6 +184,11 @@ const ModelSchema = `{
             "type": ["string", "null"],
             "maxLength": 1024
         },
+        "ip": {
+            "description": "IP address of the host the monitored service is running on.",
+            "type": ["string", "null"],
+            "maxLength": 1024
+        },
         "platform": {
             "description": "Name of the system platform the agent is running on.",
             "type": ["string", "null"],
@@ -342,6 +347,11 @@ const ModelSchema = `{
         }
     },
     "additionalProperties": false
+        },
+        "schema_version": {
+            "description": "Version of the intake JSON schemas to validate the events in the stream against. If not set, the version is selected based on the agent name and version.",
+            "type": ["string", "null"],
+            "maxLength": 1024
         }
     },
     "required": ["service"]
@@ -0,0 +1,775 @@
+// Licensed to Elasticsearch B.V. under one or more contributor
+// license agreements. See the NOTICE file distributed with
+// this work for additional information regarding copyright
+// ownership. Elasticsearch B.V. licenses this file to you under
+// the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+// Code generated by script/inline_schemas. DO NOT EDIT.
+
+package schema
+
+import (
+	"regexp"
+
+	"github.com/santhosh-tekuri/jsonschema"
+)
+
+// CompiledModelSchema returns the compiled form of ModelSchema.
+func CompiledModelSchema() *jsonschema.Schema {
+	s := make([]jsonschema.Schema, 64)
+	s[0] = jsonschema.Schema{
+		URL:           "metadata",
+		Ptr:           "#",
+		Types:         []string{"object"},
+		MinProperties: -1,
+		MaxProperties: -1,
+		Required:      []string{"service"},
+		Properties: map[string]*jsonschema.Schema{
+			"cloud":          &s[1],
+			"labels":         &s[16],
+			"process":        &s[18],
+			"schema_version": &s[24],
+			"service":        &s[25],
+			"system":         &s[44],
+			"user":           &s[60],
+		},
+		MinItems:  -1,
+		MaxItems:  -1,
+		MinLength: -1,
+		MaxLength: -1,
+	}
+	s[1] = jsonschema.Schema{
+		URL:           "docs/spec/metadata.json",
+		Types:         []string{"object", "null"},
+		MinProperties: -1,
+		MaxProperties: -1,
+		Required:      []string{"provider"},
+		Properties: map[string]*jsonschema.Schema{
+			"account":           &s[2],
+			"availability_zone": &s[5],
+			"instance":          &s[6],
+			"machine":           &s[9],
+			"project":           &s[11],
+			"provider":          &s[14],
+			"region":            &s[15],
+		},
+		MinItems:  -1,
+		MaxItems:  -1,
+		MinLength: -1,
+		MaxLength: -1,
+	}
+	s[2] = jsonschema.Schema{
+		URL:           "docs/spec/docs/spec/cloud.json",
+		MinProperties: -1,
+		MaxProperties: -1,
+		Properties: map[string]*jsonschema.Schema{
+			"id":   &s[3],
+			"name": &s[4],
+		},
+		MinItems:  -1,
+		MaxItems:  -1,
+		MinLength: -1,
+		MaxLength: -1,
+	}
+	s[3] = jsonschema.Schema{
+		URL:           "docs/spec/docs/spec/cloud.json",
+		Types:         []string{"string"},
+		MinProperties: -1,
+		MaxProperties: -1,
+		MinItems:      -1,
+		MaxItems:      -1,
+		MinLength:     -1,
+		MaxLength:     1024,
+	}
+	s[4] = jsonschema.Schema{
+		URL:           "docs/spec/docs/spec/cloud.json",
+		Types:         []string{"string"},
+		MinProperties: -1,
+		MaxProperties: -1,
+		MinItems:      -1,
+		MaxItems:      -1,
+		MinLength:     -1,
+		MaxLength:     1024,
+	}
+	s[5] = jsonschema.Schema{
+		URL:           "docs/spec/docs/spec/cloud.json",
+		Types:         []string{"string", "null"},
+		MinProperties: -1,
+		MaxProperties: -1,
+		MinItems:      -1,
+		MaxItems:      -1,
+		MinLength:     -1,
+		MaxLength:     1024,
+	}
+	s[6] = jsonschema.Schema{
+		URL:           "docs/spec/docs/spec/cloud.json",
+		MinProperties: -1,
+		MaxProperties: -1,
+		Properties: map[string]*jsonschema.Schema{
+			"id":   &s[7],
+			"name": &s[8],
+		},
+		MinItems:  -1,
+		MaxItems:  -1,
+		MinLength: -1,
+		MaxLength: -1,
+	}
+	s[7] = jsonschema.Schema{
+		URL:           "docs/spec/docs/spec/cloud.json",
+		Types:         []string{"string"},
+		MinProperties: -1,
+		MaxProperties: -1,
+		MinItems:      -1,
+		MaxItems:      -1,
+		MinLength:     -1,
+		MaxLength:     1024,
+	}
+	s[8] = jsonschema.Schema{
+		URL:           "docs/spec/docs/spec/cloud.json",
+		Types:         []string{"string"},
+		MinProperties: -1,
+		MaxProperties: -1,
+		MinItems:      -1,
+		MaxItems:      -1,
+		MinLength:     -1,
+		MaxLength:     1024,
+	}
+	s[9] = jsonschema.Schema{
+		URL:           "docs/spec/docs/spec/cloud.json",
+		MinProperties: -1,
+		MaxProperties: -1,
+		Properties: map[string]*jsonschema.Schema{
+			"type": &s[10],
+		},
+		MinItems:  -1,
+		MaxItems:  -1,
+		MinLength: -1,
+		MaxLength: -1,
+	}
+	s[10] = jsonschema.Schema{
+		URL:           "docs/spec/docs/spec/cloud.json",
+		Types:         []string{"string"},
+		MinProperties: -1,
+		MaxProperties: -1,
+		MinItems:      -1,
+		MaxItems:      -1,
+		MinLength:     -1,
+		MaxLength:     1024,
+	}
+	s[11] = jsonschema.Schema{
+		URL:           "docs/spec/docs/spec/cloud.json",
+		MinProperties: -1,
+		MaxProperties: -1,
+		Properties: map[string]*jsonschema.Schema{
+			"id":   &s[12],
+			"name": &s[13],
+		},
+		MinItems:  -1,
+		MaxItems:  -1,
+		MinLength: -1,
+		MaxLength: -1,
+	}
+	s[12] = jsonschema.Schema{
+		URL:           "docs/spec/docs/spec/cloud.json",
+		Types:         []string{"string"},
+		MinProperties: -1,
+		MaxProperties: -1,
+		MinItems:      -1,
+		MaxItems:      -1,
+		MinLength:     -1,
+		MaxLength:     1024,
+	}
+	s[13] = jsonschema.Schema{
+		URL:           "docs/spec/docs/spec/cloud.json",
+		Types:         []string{"string"},
+		MinProperties: -1,
+		MaxProperties: -1,
+		MinItems:      -1,
+		MaxItems:      -1,
+		MinLength:     -1,
+		MaxLength:     1024,
+	}
+	s[14] = jsonschema.Schema{
+		URL:           "docs/spec/docs/spec/cloud.json",
+		Types:         []string{"string", "null"},
+		MinProperties: -1,
+		MaxProperties: -1,
+		MinItems:      -1,
+		MaxItems:      -1,
+		MinLength:     -1,
+		MaxLength:     1024,
+	}
+	s[15] = jsonschema.Schema{
+		URL:           "docs/spec/docs/spec/cloud.json",
+		Types:         []string{"string", "null"},
+		MinProperties: -1,
+		MaxProperties: -1,
+		MinItems:      -1,
+		MaxItems:      -1,
+		MinLength:     -1,
+		MaxLength:     1024,
+	}
+	s[16] = jsonschema.Schema{
+		URL:           "docs/spec/metadata.json",
+		Types:         []string{"object", "null"},
+		MinProperties: -1,
+		MaxProperties: -1,
+		PatternProperties: map[*regexp.Regexp]*jsonschema.Schema{
+			regexp.MustCompile("^[^.*\"]*$"): &s[17],
+		},
+		AdditionalProperties: false,
+		MinItems:             -1,
+		MaxItems:             -1,
+		MinLength:            -1,
+		MaxLength:            -1,
+	}
+	s[17] = jsonschema.Schema{
+		URL:           "docs/spec/docs/spec/tags.json",
+		Types:         []string{"string", "boolean", "number", "null"},
+		MinProperties: -1,
+		MaxProperties: -1,
+		MinItems:      -1,
+		MaxItems:      -1,
+		MinLength:     -1,
+		MaxLength:     1024,
+	}
+	s[18] = jsonschema.Schema{
+		URL:           "docs/spec/metadata.json",
+		Types:         []string{"object", "null"},
+		MinProperties: -1,
+		MaxProperties: -1,
+		Required:      []string{"pid"},
+		Properties: map[string]*jsonschema.Schema{
+			"argv":  &s[19],
+			"pid":   &s[21],
+			"ppid":  &s[22],
+			"title": &s[23],
+		},
+		MinItems:  -1,
+		MaxItems:  -1,
+		MinLength: -1,
+		MaxLength: -1,
+	}
+	s[19] = jsonschema.Schema{
+		URL:           "docs/spec/docs/spec/process.json",
+		Types:         []string{"array", "null"},
+		MinProperties: -1,
+		MaxProperties: -1,
+		MinItems:      0,
+		MaxItems:      -1,
+		Items:         &s[20],
+		MinLength:     -1,
+		MaxLength:     -1,
+	}
+	s[20] = jsonschema.Schema{
+		URL:           "docs/spec/docs/spec/process.json",
+		Types:         []string{"string"},
+		MinProperties: -1,
+		MaxProperties: -1,
+		MinItems:      -1,
+		MaxItems:      -1,
+		MinLength:     -1,
+		MaxLength:     -1,
+	}
+	s[21] = jsonschema.Schema{
+		URL:           "docs/spec/docs/spec/process.json",
+		Types:         []string{"integer"},
+		MinProperties: -1,
+		MaxProperties: -1,
+		MinItems:      -1,
+		MaxItems:      -1,
+		MinLength:     -1,
+		MaxLength:     -1,
+	}
+	s[22] = jsonschema.Schema{
+		URL:           "docs/spec/docs/spec/process.json",
+		Types:         []string{"integer", "null"},
+		MinProperties: -1,
+		MaxProperties: -1,
+		MinItems:      -1,
+		MaxItems:      -1,
+		MinLength:     -1,
+		MaxLength:     -1,
+	}
+	s[23] = jsonschema.Schema{
+		URL:           "docs/spec/docs/spec/process.json",
+		Types:         []string{"string", "null"},
+		MinProperties: -1,
+		MaxProperties: -1,
+		MinItems:      -1,
+		MaxItems:      -1,
+		MinLength:     -1,
+		MaxLength:     1024,
+	}
+	s[24] = jsonschema.Schema{
+		URL:           "docs/spec/metadata.json",
+		Types:         []string{"string", "null"},
+		MinProperties: -1,
+		MaxProperties: -1,
+		MinItems:      -1,
+		MaxItems:      -1,
+		MinLength:     -1,
+		MaxLength:     1024,
+	}
+	s[25] = jsonschema.Schema{
+		URL:           "docs/spec/metadata.json",
+		Types:         []string{"object"},
+		MinProperties: -1,
+		MaxProperties: -1,
+		Required:      []string{"name", "agent"},
+		Properties: map[string]*jsonschema.Schema{
+			"agent":       &s[26],
+			"environment": &s[30],
+			"framework":   &s[31],
+			"language":    &s[34],
+			"name":        &s[37],
+			"node":        &s[38],
+			"runtime":     &s[40],
+			"version":     &s[43],
+		},
+		MinItems:  -1,
+		MaxItems:  -1,
+		MinLength: -1,
+		MaxLength: -1,
+	}
+	s[26] = jsonschema.Schema{
+		URL:           "docs/spec/docs/spec/service.json",
+		Types:         []string{"object", "null"},
+		MinProperties: -1,
+		MaxProperties: -1,
+		Properties: map[string]*jsonschema.Schema{
+			"ephemeral_id": &s[27],
+			"name":         &s[28],
+			"version":      &s[29],
+		},
+		MinItems:  -1,
+		MaxItems:  -1,
+		MinLength: -1,
+		MaxLength: -1,
+	}
+	s[27] = jsonschema.Schema{
+		URL:           "docs/spec/docs/spec/service.json",
+		Types:         []string{"string", "null"},
+		MinProperties: -1,
+		MaxProperties: -1,
+		MinItems:      -1,
+		MaxItems:      -1,
+		MinLength:     -1,
+		MaxLength:     1024,
+	}
+	s[28] = jsonschema.Schema{
+		URL:           "docs/spec/docs/spec/service.json",
+		Types:         []string{"string", "null"},
+		MinProperties: -1,
+		MaxProperties: -1,
+		MinItems:      -1,
+		MaxItems:      -1,
+		MinLength:     -1,
+		MaxLength:     1024,
+	}
+	s[29] = jsonschema.Schema{
+		URL:           "docs/spec/docs/spec/service.json",
+		Types:         []string{"string", "null"},
+		MinProperties: -1,
+		MaxProperties: -1,
+		MinItems:      -1,
+		MaxItems:      -1,
+		MinLength:     -1,
+		MaxLength:     1024,
+	}
+	s[30] = jsonschema.Schema{
+		URL:           "docs/spec/docs/spec/service.json",
+		Types:         []string{"string", "null"},
+		MinProperties: -1,
+		MaxProperties: -1,
+		MinItems:      -1,
+		MaxItems:      -1,
+		MinLength:     -1,
+		MaxLength:     1024,
+	}
+	s[31] = jsonschema.Schema{
+		URL:           "docs/spec/docs/spec/service.json",
+		Types:         []string{"object", "null"},
+		MinProperties: -1,
+		MaxProperties: -1,
+		Properties: map[string]*jsonschema.Schema{
+			"name":    &s[32],
+			"version": &s[33],
+		},
+		MinItems:  -1,
+		MaxItems:  -1,
+		MinLength: -1,
+		MaxLength: -1,
+	}
+	s[32] = jsonschema.Schema{
+		URL:           "docs/spec/docs/spec/service.json",
+		Types:         []string{"string", "null"},
+		MinProperties: -1,
+		MaxProperties: -1,
+		MinItems:      -1,
+		MaxItems:      -1,
+		MinLength:     -1,
+		MaxLength:     1024,
+	}
+	s[33] = jsonschema.Schema{
+		URL:           "docs/spec/docs/spec/service.json",
+		Types:         []string{"string", "null"},
+		MinProperties: -1,
+		MaxProperties: -1,
+		MinItems:      -1,
+		MaxItems:      -1,
+		MinLength:     -1,
+		MaxLength:     1024,
+	}
+	s[34] = jsonschema.Schema{
+		URL:           "docs/spec/docs/spec/service.json",
+		Types:         []string{"object", "null"},
+		MinProperties: -1,
+		MaxProperties: -1,
+		Properties: map[string]*jsonschema.Schema{
+			"name":    &s[35],
+			"version": &s[36],
+		},
+		MinItems:  -1,
+		MaxItems:  -1,
+		MinLength: -1,
+		MaxLength: -1,
+	}
+	s[35] = jsonschema.Schema{
+		URL:           "docs/spec/docs/spec/service.json",
+		Types:         []string{"string", "null"},
+		MinProperties: -1,
+		MaxProperties: -1,
+		MinItems:      -1,
+		MaxItems:      -1,
+		MinLength:     -1,
+		MaxLength:     1024,
+	}
+	s[36] = jsonschema.Schema{
+		URL:           "docs/spec/docs/spec/service.json",
+		Types:         []string{"string", "null"},
+		MinProperties: -1,
+		MaxProperties: -1,
+		MinItems:      -1,
+		MaxItems:      -1,
+		MinLength:     -1,
+		MaxLength:     1024,
+	}
+	s[37] = jsonschema.Schema{
+		URL:           "docs/spec/docs/spec/service.json",
+		Types:         []string{"string", "null"},
+		MinProperties: -1,
+		MaxProperties: -1,
+		MinItems:      -1,
+		MaxItems:      -1,
+		MinLength:     -1,
+		MaxLength:     1024,
+		Pattern:       regexp.MustCompile("^[a-zA-Z0-9 _-]+$"),
+	}
+	s[38] = jsonschema.Schema{
+		URL:           "docs/spec/docs/spec/service.json",
+		Types:         []string{"object", "null"},
+		MinProperties: -1,
+		MaxProperties: -1,
+		Properties: map[string]*jsonschema.Schema{
+			"configured_name": &s[39],
+		},
+		MinItems:  -1,
+		MaxItems:  -1,
+		MinLength: -1,
+		MaxLength: -1,
+	}
+	s[39] = jsonschema.Schema{
+		URL:           "docs/spec/docs/spec/service.json",
+		Types:         []string{"string", "null"},
+		MinProperties: -1,
+		MaxProperties: -1,
+		MinItems:      -1,
+		MaxItems:      -1,
+		MinLength:     -1,
+		MaxLength:     1024,
+	}
+	s[40] = jsonschema.Schema{
+		URL:           "docs/spec/docs/spec/service.json",
+		Types:         []string{"object", "null"},
+		MinProperties: -1,
+		MaxProperties: -1,
+		Properties: map[string]*jsonschema.Schema{
+			"name":    &s[41],
+			"version": &s[42],
+		},
+		MinItems:  -1,
+		MaxItems:  -1,
+		MinLength: -1,
+		MaxLength: -1,
+	}
+	s[41] = jsonschema.Schema{
+		URL:           "docs/spec/docs/spec/service.json",
+		Types:         []string{"string", "null"},
+		MinProperties: -1,
+		MaxProperties: -1,
+		MinItems:      -1,
+		MaxItems:      -1,
+		MinLength:     -1,
+		MaxLength:     1024,
+	}
+	s[42] = jsonschema.Schema{
+		URL:           "docs/spec/docs/spec/service.json",
+		Types:         []string{"string", "null"},
+		MinProperties: -1,
+		MaxProperties: -1,
+		MinItems:      -1,
+		MaxItems:      -1,
+		MinLength:     -1,
+		MaxLength:     1024,
+	}
+	s[43] = jsonschema.Schema{
+		URL:           "docs/spec/docs/spec/service.json",
+		Types:         []string{"string", "null"},
+		MinProperties: -1,
+		MaxProperties: -1,
+		MinItems:      -1,
+		MaxItems:      -1,
+		MinLength:     -1,
+		MaxLength:     1024,
+	}
+	s[44] = jsonschema.Schema{
+		URL:           "docs/spec/metadata.json",
+		Types:         []string{"object", "null"},
+		MinProperties: -1,
+		MaxProperties: -1,
+		Properties: map[string]*jsonschema.Schema{
+			"architecture":        &s[45],
+			"configured_hostname": &s[46],
+			"container":           &s[47],
+			"detected_hostname":   &s[49],
+			"hostname":            &s[50],
+			"ip":                  &s[51],
+			"kubernetes":          &s[52],
+			"platform":            &s[59],
+		},
+		MinItems:  -1,
+		MaxItems:  -1,
+		MinLength: -1,
+		MaxLength: -1,
+	}
+	s[45] = jsonschema.Schema{
+		URL:           "docs/spec/docs/spec/system.json",
+		Types:         []string{"string", "null"},
+		MinProperties: -1,
+		MaxProperties: -1,
+		MinItems:      -1,
+		MaxItems:      -1,
+		MinLength:     -1,
+		MaxLength:     1024,
+	}
+	s[46] = jsonschema.Schema{
+		URL:           "docs/spec/docs/spec/system.json",
+		Types:         []string{"string", "null"},
+		MinProperties: -1,
+		MaxProperties: -1,
+		MinItems:      -1,
+		MaxItems:      -1,
+		MinLength:     -1,
+		MaxLength:     1024,
+	}
+	s[47] = jsonschema.Schema{
+		URL:           "docs/spec/docs/spec/system.json",
+		MinProperties: -1,
+		MaxProperties: -1,
+		Required:      []string{"id"},
+		Properties: map[string]*jsonschema.Schema{
+			"id": &s[48],
+		},
+		MinItems:  -1,
+		MaxItems:  -1,
+		MinLength: -1,
+		MaxLength: -1,
+	}
+	s[48] = jsonschema.Schema{
+		URL:           "docs/spec/docs/spec/system.json",
+		Types:         []string{"string"},
+		MinProperties: -1,
+		MaxProperties: -1,
+		MinItems:      -1,
+		MaxItems:      -1,
+		MinLength:     -1,
+		MaxLength:     1024,
+	}
+	s[49] = jsonschema.Schema{
+		URL:           "docs/spec/docs/spec/system.json",
+		Types:         []string{"string", "null"},
+		MinProperties: -1,
+		MaxProperties: -1,
+		MinItems:      -1,
+		MaxItems:      -1,
+		MinLength:     -1,
+		MaxLength:     1024,
+	}
+	s[50] = jsonschema.Schema{
+		URL:           "docs/spec/docs/spec/system.json",
+		Types:         []string{"string", "null"},
+		MinProperties: -1,
+		MaxProperties: -1,
+		MinItems:      -1,
+		MaxItems:      -1,
+		MinLength:     -1,
+		MaxLength:     1024,
+	}
+	s[51] = jsonschema.Schema{
+		URL:           "docs/spec/docs/spec/system.json",
+		Types:         []string{"string", "null"},
+		MinProperties: -1,
+		MaxProperties: -1,
+		MinItems:      -1,
+		MaxItems:      -1,
+		MinLength:     -1,
+		MaxLength:     1024,
+	}
+	s[52] = jsonschema.Schema{
+		URL:           "docs/spec/docs/spec/system.json",
+		MinProperties: -1,
+		MaxProperties: -1,
+		Properties: map[string]*jsonschema.Schema{
+			"namespace": &s[53],
+			"node":      &s[54],
+			"pod":       &s[56],
+		},
+		MinItems:  -1,
+		MaxItems:  -1,
+		MinLength: -1,
+		MaxLength: -1,
+	}
+	s[53] = jsonschema.Schema{
+		URL:           "docs/spec/docs/spec/system.json",
+		Types:         []string{"string", "null"},
+		MinProperties: -1,
+		MaxProperties: -1,
+		MinItems:      -1,
+		MaxItems:      -1,
+		MinLength:     -1,
+		MaxLength:     1024,
+	}
+	s[54] = jsonschema.Schema{
+		URL:           "docs/spec/docs/spec/system.json",
+		MinProperties: -1,
+		MaxProperties: -1,
+		Properties: map[string]*jsonschema.Schema{
+			"name": &s[55],
+		},
+		MinItems:  -1,
+		MaxItems:  -1,
+		MinLength: -1,
+		MaxLength: -1,
+	}
+	s[55] = jsonschema.Schema{
+		URL:           "docs/spec/docs/spec/system.json",
+		Types:         []string{"string", "null"},
+		MinProperties: -1,
+		MaxProperties: -1,
+		MinItems:      -1,
+		MaxItems:      -1,
+		MinLength:     -1,
+		MaxLength:     1024,
+	}
+	s[56] = jsonschema.Schema{
+		URL:           "docs/spec/docs/spec/system.json",
+		MinProperties: -1,
+		MaxProperties: -1,
+		Properties: map[string]*jsonschema.Schema{
+			"name": &s[57],
+			"uid":  &s[58],
+		},
+		MinItems:  -1,
+		MaxItems:  -1,
+		MinLength: -1,
+		MaxLength: -1,
+	}
+	s[57] = jsonschema.Schema{
+		URL:           "docs/spec/docs/spec/system.json",
+		Types:         []string{"string", "null"},
+		MinProperties: -1,
+		MaxProperties: -1,
+		MinItems:      -1,
+		MaxItems:      -1,
+		MinLength:     -1,
+		MaxLength:     1024,
+	}
+	s[58] = jsonschema.Schema{
+		URL:           "docs/spec/docs/spec/system.json",
+		Types:         []string{"string", "null"},
+		MinProperties: -1,
+		MaxProperties: -1,
+		MinItems:      -1,
+		MaxItems:      -1,
+		MinLength:     -1,
+		MaxLength:     1024,
+	}
+	s[59] = jsonschema.Schema{
+		URL:           "docs/spec/docs/spec/system.json",
+		Types:         []string{"string", "null"},
+		MinProperties: -1,
+		MaxProperties: -1,
+		MinItems:      -1,
+		MaxItems:      -1,
+		MinLength:     -1,
+		MaxLength:     1024,
+	}
+	s[60] = jsonschema.Schema{
+		URL:           "docs/spec/metadata.json",
+		Types:         []string{"object", "null"},
+		MinProperties: -1,
+		MaxProperties: -1,
+		Properties: map[string]*jsonschema.Schema{
+			"email":    &s[61],
+			"id":       &s[62],
+			"username": &s[63],
+		},
+		MinItems:  -1,
+		MaxItems:  -1,
+		MinLength: -1,
+		MaxLength: -1,
+	}
+	s[61] = jsonschema.Schema{
+		URL:           "docs/spec/docs/spec/user.json",
+		Types:         []string{"string", "null"},
+		MinProperties: -1,
+		MaxProperties: -1,
+		MinItems:      -1,
+		MaxItems:      -1,
+		MinLength:     -1,
+		MaxLength:     1024,
+	}
+	s[62] = jsonschema.Schema{
+		URL:           "docs/spec/docs/spec/user.json",
+		Types:         []string{"string", "integer", "null"},
+		MinProperties: -1,
+		MaxProperties: -1,
+		MinItems:      -1,
+		MaxItems:      -1,
+		MinLength:     -1,
+		MaxLength:     1024,
+	}
+	s[63] = jsonschema.Schema{
+		URL:           "docs/spec/docs/spec/user.json",
+		Types:         []string{"string", "null"},
+		MinProperties: -1,
+		MaxProperties: -1,
+		MinItems:      -1,
+		MaxItems:      -1,
+		MinLength:     -1,
+		MaxLength:     1024,
+	}
+	return &s[0]
+}
@@ -26,6 +26,7 @@ import (
 	"github.com/elastic/beats/v7/libbeat/common"
 
 	"github.com/elastic/apm-server/tests"
+	"github.com/elastic/apm-server/utility"
 )
 
 func TestMetadata_Set(t *testing.T) {
@@ -96,13 +97,61 @@ func TestMetadata_Set(t *testing.T) {
 				"host":    common.MapStr{"hostname": host, "name": host},
 				"service": common.MapStr{"node": common.MapStr{"name": host}}},
 		},
+		{
+			input:  Metadata{TenantID: "team-a"},
+			fields: common.MapStr{},
+			output: common.MapStr{"tenant": common.MapStr{"id": "team-a"}},
+		},
 	} {
 		assert.Equal(t, test.output, test.input.Set(test.fields))
 	}
 }
 
+func TestMetadata_SetPrepared(t *testing.T) {
+	input := Metadata{
+		Service: Service{
+			Name:  "myservice",
+			Agent: Agent{Name: "elastic-node", Version: "1.0.0"},
+		},
+		System:  System{DetectedHostname: "host"},
+		Process: Process{Pid: 1234},
+		Labels:  common.MapStr{"a": "b"},
+	}
+	expected := input.Set(common.MapStr{})
+
+	prepared := input
+	prepared.Prepare()
+	out := prepared.Set(common.MapStr{"foo": "bar"})
+	assert.Equal(t, "bar", out["foo"])
+	delete(out, "foo")
+	assert.Equal(t, expected, out)
+
+	// Fields must not be shared between events, as events merge their
+	// own fields into them, and processors may modify them.
+	utility.DeepUpdate(out, "labels", common.MapStr{"c": "d"})
+	utility.DeepUpdate(out, "service", common.MapStr{"name": "other"})
+	utility.DeepUpdate(out, "agent", common.MapStr{"name": "other"})
+	out.Delete("host.hostname")
+	out.Put("process.pid", 5678)
+	assert.Equal(t, expected, prepared.Set(common.MapStr{}))
+}
+
 func BenchmarkMetadataSet(b *testing.B) {
 	test := func(b *testing.B, name string, input Metadata) {
+		prepared := input
+		prepared.Prepare()
+		b.Run(name+"/prepared", func(b *testing.B) {
+			b.ReportAllocs()
+			b.ResetTimer()
+
+			out := make(common.MapStr)
+			for i := 0; i < b.N; i++ {
+				prepared.Set(out)
+				for k := range out {
+					delete(out, k)
+				}
+			}
+		})
 		b.Run(name, func(b *testing.B) {
 			b.ReportAllocs()
 			b.ResetTimer()
@@ -28,7 +28,6 @@ import (
 
 	logs "github.com/elastic/apm-server/log"
 	"github.com/elastic/apm-server/transform"
-	"github.com/elastic/apm-server/utility"
 )
 
 const (
@@ -36,6 +35,7 @@ const (
 	metricsetDocType        = "metric"
 	metricsetTransactionKey = "transaction"
 	metricsetSpanKey        = "span"
+	metricsetEventKey       = "event"
 )
 
 var (
@@ -61,6 +61,10 @@ type Metricset struct {
 	// metrics are associated.
 	Span MetricsetSpan
 
+	// Event holds information about the outcome of the events with
+	// which the metrics are associated.
+	Event MetricsetEventCategorization
+
 	// Labels holds arbitrary labels to apply to the metrics.
 	//
 	// These labels override any with the same names in Metadata.Labels.
@@ -122,6 +126,23 @@ type MetricsetSpan struct {
 
 	// Subtype holds the span subtype: "http", "sql", etc.
 	Subtype string
+
+	// DestinationService holds information about the destination
+	// service of the spans with which the metrics are associated.
+	DestinationService MetricsetSpanDestinationService
+}
+
+// MetricsetSpanDestinationService identifies the destination service of exit spans.
+type MetricsetSpanDestinationService struct {
+	// Resource holds the destination service resource: "elasticsearch", "mysql", etc.
+	Resource string
+}
+
+// MetricsetEventCategorization holds ECS event categorization fields
+// for the events with which a metricset is associated.
+type MetricsetEventCategorization struct {
+	// Outcome holds the event outcome: "success", "failure", or "unknown".
+	Outcome string
 }
 
 func (me *Metricset) Transform(ctx context.Context, tctx *transform.Context) []beat.Event {
@@ -139,16 +160,7 @@ func (me *Metricset) Transform(ctx context.Context, tctx *transform.Context) []b
 	}
 
 	fields["processor"] = metricsetProcessorEntry
-	me.Metadata.Set(fields)
-	if transactionFields := me.Transaction.fields(); transactionFields != nil {
-		utility.DeepUpdate(fields, metricsetTransactionKey, transactionFields)
-	}
-	if spanFields := me.Span.fields(); spanFields != nil {
-		utility.DeepUpdate(fields, metricsetSpanKey, spanFields)
-	}
-
-	// merges with metadata labels, overrides conflicting keys
-	utility.DeepUpdate(fields, "labels", me.Labels)
+	me.setDimensions(fields)
 
 	return []beat.Event{{
 		Fields:    fields,
@@ -156,6 +168,26 @@ func (me *Metricset) Transform(ctx context.Context, tctx *transform.Context) []b
 	}}
 }
 
+// Dimensions returns the fields identifying the entities with which the
+// metrics are associated: all fields of the metricset's documents, other
+// than its samples and timestamp.
+func (me *Metricset) Dimensions() common.MapStr {
+	fields := common.MapStr{}
+	me.setDimensions(fields)
+	return fields
+}
+
+func (me *Metricset) setDimensions(fields common.MapStr) {
+	me.Metadata.Set(fields)
+	out := (*mapStr)(&fields)
+	out.maybeDeepUpdateMapStr(metricsetTransactionKey, me.Transaction.fields())
+	out.maybeDeepUpdateMapStr(metricsetSpanKey, me.Span.fields())
+	out.maybeDeepUpdateMapStr(metricsetEventKey, me.Event.fields())
+
+	// merges with metadata labels, overrides conflicting keys
+	out.mergeLabels(me.Labels)
+}
+
 func (t *MetricsetTransaction) fields() common.MapStr {
 	var fields mapStr
 	fields.maybeSetString("type", t.Type)
@@ -171,6 +203,17 @@ func (s *MetricsetSpan) fields() common.MapStr {
 	var fields mapStr
 	fields.maybeSetString("type", s.Type)
 	fields.maybeSetString("subtype", s.Subtype)
+	if s.DestinationService.Resource != "" {
+		fields.set("destination", common.MapStr{
+			"service": common.MapStr{"resource": s.DestinationService.Resource},
+		})
+	}
+	return common.MapStr(fields)
+}
+
+func (e *MetricsetEventCategorization) fields() common.MapStr {
+	var fields mapStr
+	fields.maybeSetString("outcome", e.Outcome)
 	return common.MapStr(fields)
 }
 
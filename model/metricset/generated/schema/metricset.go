@@ -32,17 +32,16 @@ const ModelSchema = `{
             "type": ["integer", "null"]
         }
     }},
-        {     "$id": "docs/spec/span_type.json",
-    "title": "Span Type",
-    "type": ["object"],
-    "properties": {
-        "type": {
-            "type": "string",
-            "description": "Keyword of specific relevance in the service's domain (eg: 'db.postgresql.query', 'template.erb', etc)",
-            "maxLength": 1024
-        }
-    } },
-        {     "$id": "docs/spec/span_subtype.json",
+        {
+            "properties": {
+                "span": {
+                    "type": ["object", "null"],
+                    "description": "Span type and subtype of the spans the metrics are associated with.",
+                    "anyOf": [
+                        { "type": "null" },
+                        {
+                            "allOf": [
+                                {     "$id": "docs/spec/span_subtype.json",
     "title": "Span Subtype",
     "type": ["object"],
     "properties": {
@@ -52,7 +51,27 @@ const ModelSchema = `{
             "maxLength": 1024
         }
     } },
-        {     "$id": "docs/spec/transaction_name.json",
+                                {
+                                    "properties": {
+                                        "type": {
+                                            "type": ["string", "null"],
+                                            "description": "Keyword of specific relevance in the service's domain (eg: 'db.postgresql.query', 'template.erb', etc). Unlike for spans, the type is optional and may be null.",
+                                            "maxLength": 1024
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    ]
+                },
+                "transaction": {
+                    "type": ["object", "null"],
+                    "description": "Transaction name and type of the transactions the metrics are associated with.",
+                    "anyOf": [
+                        { "type": "null" },
+                        {
+                            "allOf": [
+                                {     "$id": "docs/spec/transaction_name.json",
     "title": "Transaction Name",
     "type": ["object"],
     "properties": {
@@ -62,18 +81,19 @@ const ModelSchema = `{
             "maxLength": 1024
         }
     } },
-        {     "$id": "docs/spec/transaction_type.json",
-    "title": "Transaction Type",
-    "type": ["object"],
-    "properties": {
-        "type": {
-            "type": "string",
-            "description": "Keyword of specific relevance in the service's domain (eg: 'request', 'backgroundjob', etc)",
-            "maxLength": 1024
-        }
-    } },
-        {
-            "properties": {
+                                {
+                                    "properties": {
+                                        "type": {
+                                            "type": ["string", "null"],
+                                            "description": "Keyword of specific relevance in the service's domain (eg: 'request', 'backgroundjob', etc). Unlike for transactions, the type is optional and may be null.",
+                                            "maxLength": 1024
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    ]
+                },
                 "samples": {
                     "type": [
                         "object"
@@ -147,6 +147,39 @@ func TestTransform(t *testing.T) {
 			},
 			Msg: "Payload with valid metric.",
 		},
+		{
+			Metricset: &Metricset{
+				Metadata:  metadata,
+				Timestamp: timestamp,
+				Span: MetricsetSpan{
+					DestinationService: MetricsetSpanDestinationService{Resource: "mysql"},
+				},
+				Event: MetricsetEventCategorization{Outcome: "success"},
+				Samples: []Sample{
+					{Name: "span.destination.service.response_time.count", Value: 2},
+					{Name: "span.destination.service.response_time.sum.us", Value: 300},
+				},
+			},
+			Output: []common.MapStr{
+				{
+					"processor": common.MapStr{"event": "metric", "name": "metric"},
+					"service":   common.MapStr{"name": "myservice"},
+					"event":     common.MapStr{"outcome": "success"},
+					"span": common.MapStr{
+						"destination": common.MapStr{
+							"service": common.MapStr{
+								"resource": "mysql",
+								"response_time": common.MapStr{
+									"count": float64(2),
+									"sum":   common.MapStr{"us": float64(300)},
+								},
+							},
+						},
+					},
+				},
+			},
+			Msg: "Payload with service destination metrics.",
+		},
 	}
 
 	tctx := &transform.Context{}
@@ -159,3 +192,18 @@ func TestTransform(t *testing.T) {
 		}
 	}
 }
+
+func TestMetricsetDimensions(t *testing.T) {
+	metricset := Metricset{
+		Timestamp:   time.Now(),
+		Metadata:    Metadata{Service: Service{Name: "myservice"}, Labels: common.MapStr{"a": "b"}},
+		Transaction: MetricsetTransaction{Type: "request"},
+		Labels:      common.MapStr{"c": "d"},
+		Samples:     []Sample{{Name: "a.counter", Value: 612}},
+	}
+	assert.Equal(t, common.MapStr{
+		"service":     common.MapStr{"name": "myservice"},
+		"transaction": common.MapStr{"type": "request"},
+		"labels":      common.MapStr{"a": "b", "c": "d"},
+	}, metricset.Dimensions())
+}
@@ -45,7 +45,7 @@ func decodeContext(input map[string]interface{}, cfg Config, meta *model.Metadat
 	}
 	http, err := decodeHTTP(input, cfg.HasShortFieldNames, decoder.Err)
 	url, err := decodeURL(input, err)
-	custom, err := decodeCustom(input, cfg.HasShortFieldNames, err)
+	custom, err := decodeCustom(input, cfg, err)
 	page, err := decodePage(input, cfg.HasShortFieldNames, err)
 	message, err := decodeMessage(input, err)
 	if err != nil {
@@ -63,24 +63,30 @@ func decodeContext(input map[string]interface{}, cfg Config, meta *model.Metadat
 
 	if tagsInp := getObject(input, fieldName("tags")); tagsInp != nil {
 		var labels model.Labels
-		decodeLabels(tagsInp, (*common.MapStr)(&labels))
+		decodeLabels(cfg.ContextLimits.apply(tagsInp), (*common.MapStr)(&labels))
 		ctx.Labels = &labels
 	}
 
 	if userInp := getObject(input, fieldName("user")); userInp != nil {
 		// Per-event user metadata replaces stream user metadata.
+		meta.Unprepare()
 		meta.User = model.User{}
 		decodeUser(userInp, cfg.HasShortFieldNames, &meta.User, &meta.Client)
 	}
 	if ua := http.UserAgent(); ua != "" {
+		meta.Unprepare()
 		meta.UserAgent.Original = ua
 	}
 	if meta.Client.IP == nil {
-		meta.Client.IP = getHTTPClientIP(http)
+		if ip := getHTTPClientIP(http); ip != nil {
+			meta.Unprepare()
+			meta.Client.IP = ip
+		}
 	}
 
 	if serviceInp := getObject(input, fieldName("service")); serviceInp != nil {
 		// Per-event service metadata is merged with stream service metadata.
+		meta.Unprepare()
 		decodeService(serviceInp, cfg.HasShortFieldNames, &meta.Service)
 	}
 
@@ -226,14 +232,14 @@ func decodePage(raw common.MapStr, hasShortFieldNames bool, err error) (*model.P
 	return page, decoder.Err
 }
 
-func decodeCustom(raw common.MapStr, hasShortFieldNames bool, err error) (*model.Custom, error) {
+func decodeCustom(raw common.MapStr, cfg Config, err error) (*model.Custom, error) {
 	if err != nil {
 		return nil, err
 	}
 	decoder := utility.ManualDecoder{}
-	fieldName := field.Mapper(hasShortFieldNames)
+	fieldName := field.Mapper(cfg.HasShortFieldNames)
 	if c := decoder.MapStr(raw, fieldName("custom")); decoder.Err == nil && c != nil {
-		custom := model.Custom(c)
+		custom := model.Custom(cfg.ContextLimits.apply(c))
 		return &custom, nil
 	}
 	return nil, decoder.Err
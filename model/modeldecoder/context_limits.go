@@ -0,0 +1,130 @@
+// Licensed to Elasticsearch B.V. under one or more contributor
+// license agreements. See the NOTICE file distributed with
+// this work for additional information regarding copyright
+// ownership. Elasticsearch B.V. licenses this file to you under
+// the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package modeldecoder
+
+import (
+	"sort"
+	"unicode/utf8"
+)
+
+// truncatedKey is set to true in objects from which keys were removed,
+// or in which string values were truncated, due to ContextLimits.
+const truncatedKey = "_truncated"
+
+// ContextLimits holds limits applied to free-form context sent by agents,
+// i.e. context.custom and context.tags. A value of zero means unlimited.
+type ContextLimits struct {
+	// MaxDepth is the maximum nesting depth of objects, where the
+	// top-level object has a depth of 1.
+	MaxDepth int
+
+	// MaxKeys is the maximum total number of keys, across all
+	// nesting levels.
+	MaxKeys int
+
+	// MaxValueLength is the maximum length of string values,
+	// in characters.
+	MaxValueLength int
+}
+
+// apply returns a copy of m with the limits applied. Objects from which
+// anything was removed or truncated have truncatedKey set to true. If no
+// limits are defined, m is returned unmodified.
+func (l ContextLimits) apply(m map[string]interface{}) map[string]interface{} {
+	if l == (ContextLimits{}) || m == nil {
+		return m
+	}
+	var numKeys int
+	return l.limitObject(m, 1, &numKeys)
+}
+
+func (l ContextLimits) limitObject(in map[string]interface{}, depth int, numKeys *int) map[string]interface{} {
+	// Sort the keys so the same keys are kept for identical input.
+	keys := make([]string, 0, len(in))
+	for k := range in {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	var truncated bool
+	out := make(map[string]interface{}, len(in))
+	for _, k := range keys {
+		if l.MaxKeys > 0 && *numKeys >= l.MaxKeys {
+			truncated = true
+			break
+		}
+		// Count the key before its value, so keys of nested
+		// objects do not take precedence over their parents.
+		*numKeys++
+		v, ok, valueTruncated := l.limitValue(in[k], depth, numKeys)
+		if valueTruncated {
+			truncated = true
+		}
+		if !ok {
+			*numKeys--
+			continue
+		}
+		out[k] = v
+	}
+	if truncated {
+		out[truncatedKey] = true
+	}
+	return out
+}
+
+// limitValue applies the limits to v, which is held in an object at the
+// given depth. If v must be removed, ok will be false. If v was removed or
+// truncated, such that the containing object must be marked as truncated,
+// truncated will be true.
+func (l ContextLimits) limitValue(v interface{}, depth int, numKeys *int) (_ interface{}, ok, truncated bool) {
+	switch v := v.(type) {
+	case map[string]interface{}:
+		if l.MaxDepth > 0 && depth >= l.MaxDepth {
+			return nil, false, true
+		}
+		return l.limitObject(v, depth+1, numKeys), true, false
+	case []interface{}:
+		out := make([]interface{}, 0, len(v))
+		for _, elem := range v {
+			elem, ok, elemTruncated := l.limitValue(elem, depth, numKeys)
+			if elemTruncated {
+				truncated = true
+			}
+			if ok {
+				out = append(out, elem)
+			}
+		}
+		return out, true, truncated
+	case string:
+		if l.MaxValueLength > 0 && utf8.RuneCountInString(v) > l.MaxValueLength {
+			return truncateString(v, l.MaxValueLength), true, true
+		}
+	}
+	return v, true, false
+}
+
+func truncateString(s string, n int) string {
+	var i int
+	for j := range s {
+		if i == n {
+			return s[:j]
+		}
+		i++
+	}
+	return s
+}
@@ -0,0 +1,94 @@
+// Licensed to Elasticsearch B.V. under one or more contributor
+// license agreements. See the NOTICE file distributed with
+// this work for additional information regarding copyright
+// ownership. Elasticsearch B.V. licenses this file to you under
+// the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package modeldecoder
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestContextLimits(t *testing.T) {
+	input := map[string]interface{}{
+		"a": "short",
+		"b": "a very long value",
+		"c": map[string]interface{}{
+			"d": map[string]interface{}{"e": "deep"},
+			"f": 1.5,
+		},
+		"g": []interface{}{"ok", "too long value", map[string]interface{}{"h": true}},
+	}
+
+	for name, test := range map[string]struct {
+		limits ContextLimits
+		expect map[string]interface{}
+	}{
+		"unlimited": {
+			limits: ContextLimits{},
+			expect: input,
+		},
+		"max_depth": {
+			limits: ContextLimits{MaxDepth: 2},
+			expect: map[string]interface{}{
+				"a": "short",
+				"b": "a very long value",
+				"c": map[string]interface{}{
+					"f":          1.5,
+					truncatedKey: true,
+				},
+				"g": []interface{}{"ok", "too long value", map[string]interface{}{"h": true}},
+			},
+		},
+		"max_keys": {
+			limits: ContextLimits{MaxKeys: 3},
+			expect: map[string]interface{}{
+				"a": "short",
+				"b": "a very long value",
+				"c": map[string]interface{}{
+					truncatedKey: true,
+				},
+				truncatedKey: true,
+			},
+		},
+		"max_value_length": {
+			limits: ContextLimits{MaxValueLength: 6},
+			expect: map[string]interface{}{
+				"a": "short",
+				"b": "a very",
+				"c": map[string]interface{}{
+					"d": map[string]interface{}{"e": "deep"},
+					"f": 1.5,
+				},
+				"g":          []interface{}{"ok", "too lo", map[string]interface{}{"h": true}},
+				truncatedKey: true,
+			},
+		},
+	} {
+		t.Run(name, func(t *testing.T) {
+			assert.Equal(t, test.expect, test.limits.apply(input))
+		})
+	}
+}
+
+func TestContextLimitsMultibyte(t *testing.T) {
+	limits := ContextLimits{MaxValueLength: 2}
+	assert.Equal(t,
+		map[string]interface{}{"a": "日本", truncatedKey: true},
+		limits.apply(map[string]interface{}{"a": "日本語"}),
+	)
+}
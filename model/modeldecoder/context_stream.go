@@ -0,0 +1,312 @@
+// Licensed to Elasticsearch B.V. under one or more contributor
+// license agreements. See the NOTICE file distributed with
+// this work for additional information regarding copyright
+// ownership. Elasticsearch B.V. licenses this file to you under
+// the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package modeldecoder
+
+import (
+	"encoding/json"
+	"net"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/elastic/beats/v7/libbeat/common"
+
+	"github.com/elastic/apm-server/model"
+	"github.com/elastic/apm-server/model/modeldecoder/generated/decoder"
+	"github.com/elastic/apm-server/model/modeldecoder/nullable"
+)
+
+// The functions in this file map the input decoded by the generated
+// decoders to the model, in the same way as the corresponding functions
+// decoding maps. The input must have been validated.
+
+// mapToContextModel is the equivalent of decodeContext.
+func mapToContextModel(in *decoder.Context, cfg Config, meta *model.Metadata) (*model.Context, error) {
+	if !in.IsSet {
+		return &model.Context{}, nil
+	}
+	var ctx model.Context
+	if in.Request.IsSet {
+		req := &in.Request
+		ctx.Http = &model.Http{
+			Version: stringPtr(req.HTTPVersion),
+			Request: &model.Req{
+				Method: strings.ToLower(req.Method.Val),
+				Env:    mapInterface(req.Env),
+				Socket: &model.Socket{
+					RemoteAddress: stringPtr(req.Socket.RemoteAddress),
+					Encrypted:     boolPtr(req.Socket.Encrypted),
+				},
+				Body:    req.Body.Val,
+				Cookies: mapInterface(req.Cookies),
+				Headers: mapToHeaders(req.Headers),
+			},
+		}
+		url, err := mapToURLModel(&req.URL)
+		if err != nil {
+			return nil, err
+		}
+		ctx.URL = url
+	}
+	if resp := &in.Response; resp.IsSet {
+		if ctx.Http == nil {
+			ctx.Http = &model.Http{}
+		}
+		ctx.Http.Response = &model.Resp{
+			Finished:    boolPtr(resp.Finished),
+			HeadersSent: boolPtr(resp.HeadersSent),
+			MinimalResp: mapToMinimalResp(resp.StatusCode, resp.Headers, resp.DecodedBodySize, resp.EncodedBodySize, resp.TransferSize),
+		}
+	}
+	if in.Custom != nil {
+		custom := model.Custom(cfg.ContextLimits.apply(in.Custom))
+		ctx.Custom = &custom
+	}
+	if in.Page.IsSet {
+		ctx.Page = &model.Page{Referer: stringPtr(in.Page.Referer)}
+		if in.Page.URL.IsSet {
+			ctx.Page.URL = model.ParseURL(in.Page.URL.Val, "")
+		}
+	}
+	ctx.Message = mapToMessageModel(&in.Message)
+
+	if in.Tags != nil {
+		var labels model.Labels
+		decodeLabels(cfg.ContextLimits.apply(in.Tags), (*common.MapStr)(&labels))
+		ctx.Labels = &labels
+	}
+
+	if user := &in.User; user.IsSet {
+		// Per-event user metadata replaces stream user metadata.
+		meta.Unprepare()
+		meta.User = model.User{Name: user.Username.Val, Email: user.Email.Val}
+		if user.IP.IsSet {
+			meta.Client.IP = net.ParseIP(user.IP.Val)
+		}
+		switch id := user.ID.Val.(type) {
+		case json.Number:
+			meta.User.ID = id.String()
+		case string:
+			meta.User.ID = id
+		}
+	}
+	if ua := ctx.Http.UserAgent(); ua != "" {
+		meta.Unprepare()
+		meta.UserAgent.Original = ua
+	}
+	if meta.Client.IP == nil {
+		if ip := getHTTPClientIP(ctx.Http); ip != nil {
+			meta.Unprepare()
+			meta.Client.IP = ip
+		}
+	}
+
+	if in.Service.IsSet {
+		// Per-event service metadata is merged with stream service metadata.
+		meta.Unprepare()
+		mapToServiceModel(&in.Service, &meta.Service)
+	}
+	return &ctx, nil
+}
+
+// mapToURLModel is the equivalent of decodeURL.
+func mapToURLModel(in *decoder.RequestURL) (*model.URL, error) {
+	url := model.URL{
+		Original: stringPtr(in.Raw),
+		Full:     stringPtr(in.Full),
+		Domain:   stringPtr(in.Hostname),
+		Path:     stringPtr(in.Pathname),
+		Query:    stringPtr(in.Search),
+		Fragment: stringPtr(in.Hash),
+	}
+	if in.Protocol.IsSet {
+		scheme := strings.TrimSuffix(in.Protocol.Val, ":")
+		url.Scheme = &scheme
+	}
+	switch port := in.Port.Val.(type) {
+	case json.Number:
+		p, err := port.Int64()
+		if err != nil {
+			return nil, err
+		}
+		i := int(p)
+		url.Port = &i
+	case string:
+		p, err := strconv.Atoi(port)
+		if err != nil {
+			return nil, err
+		}
+		url.Port = &p
+	}
+	return &url, nil
+}
+
+// mapToMinimalResp is the equivalent of decodeMinimalHTTPResponse.
+func mapToMinimalResp(
+	statusCode nullable.Int,
+	headers map[string]interface{},
+	decodedBodySize, encodedBodySize, transferSize nullable.Float64,
+) model.MinimalResp {
+	return model.MinimalResp{
+		StatusCode:      intPtr(statusCode),
+		Headers:         mapToHeaders(headers),
+		DecodedBodySize: float64Ptr(decodedBodySize),
+		EncodedBodySize: float64Ptr(encodedBodySize),
+		TransferSize:    float64Ptr(transferSize),
+	}
+}
+
+// mapToMessageModel is the equivalent of decodeMessage.
+func mapToMessageModel(in *decoder.Message) *model.Message {
+	if !in.IsSet {
+		return nil
+	}
+	return &model.Message{
+		QueueName: stringPtr(in.Queue.Name),
+		Body:      stringPtr(in.Body),
+		Headers:   mapToHeaders(in.Headers),
+		AgeMillis: intPtr(in.Age.Ms),
+	}
+}
+
+// mapToServiceModel is the equivalent of decodeService.
+func mapToServiceModel(in *decoder.Service, out *model.Service) {
+	setString(in.Name, &out.Name)
+	setString(in.Version, &out.Version)
+	setString(in.Environment, &out.Environment)
+	setString(in.Node.ConfiguredName, &out.Node.Name)
+	setString(in.Agent.Name, &out.Agent.Name)
+	setString(in.Agent.Version, &out.Agent.Version)
+	setString(in.Agent.EphemeralID, &out.Agent.EphemeralID)
+	setString(in.Framework.Name, &out.Framework.Name)
+	setString(in.Framework.Version, &out.Framework.Version)
+	setString(in.Language.Name, &out.Language.Name)
+	setString(in.Language.Version, &out.Language.Version)
+	setString(in.Runtime.Name, &out.Runtime.Name)
+	setString(in.Runtime.Version, &out.Runtime.Version)
+}
+
+// mapToStacktraceModel is the equivalent of decodeStacktrace.
+func mapToStacktraceModel(in []decoder.StacktraceFrame) model.Stacktrace {
+	if in == nil {
+		return nil
+	}
+	st := make(model.Stacktrace, len(in))
+	for i := range in {
+		frame := &in[i]
+		st[i] = &model.StacktraceFrame{
+			AbsPath:      stringPtr(frame.AbsPath),
+			Filename:     stringPtr(frame.Filename),
+			Classname:    stringPtr(frame.Classname),
+			Lineno:       intPtr(frame.Lineno),
+			Colno:        intPtr(frame.Colno),
+			ContextLine:  stringPtr(frame.ContextLine),
+			Module:       stringPtr(frame.Module),
+			Function:     stringPtr(frame.Function),
+			LibraryFrame: boolPtr(frame.LibraryFrame),
+			Vars:         frame.Vars,
+			PreContext:   stringSlice(frame.PreContext),
+			PostContext:  stringSlice(frame.PostContext),
+		}
+	}
+	return st
+}
+
+// mapToHeaders is the equivalent of utility.ManualDecoder.Headers.
+func mapToHeaders(in map[string]interface{}) http.Header {
+	if len(in) == 0 {
+		return nil
+	}
+	h := http.Header{}
+	for k, v := range in {
+		switch v := v.(type) {
+		case string:
+			h.Add(k, v)
+		case []interface{}:
+			for _, v := range v {
+				h.Add(k, v.(string))
+			}
+		}
+	}
+	return h
+}
+
+// mapInterface returns m as an interface value, which is nil if m is nil.
+func mapInterface(m map[string]interface{}) interface{} {
+	if m == nil {
+		return nil
+	}
+	return m
+}
+
+func setString(in nullable.String, out *string) {
+	if in.IsSet {
+		*out = in.Val
+	}
+}
+
+func stringPtr(in nullable.String) *string {
+	if !in.IsSet {
+		return nil
+	}
+	return &in.Val
+}
+
+func intPtr(in nullable.Int) *int {
+	if !in.IsSet {
+		return nil
+	}
+	return &in.Val
+}
+
+func float64Ptr(in nullable.Float64) *float64 {
+	if !in.IsSet {
+		return nil
+	}
+	return &in.Val
+}
+
+func boolPtr(in nullable.Bool) *bool {
+	if !in.IsSet {
+		return nil
+	}
+	return &in.Val
+}
+
+func stringSlice(in []nullable.String) []string {
+	if in == nil {
+		return nil
+	}
+	out := make([]string, len(in))
+	for i, s := range in {
+		out[i] = s.Val
+	}
+	return out
+}
+
+// timeEpochMicro is the equivalent of utility.ManualDecoder.TimeEpochMicro.
+func timeEpochMicro(in nullable.Int) time.Time {
+	if !in.IsSet {
+		return time.Time{}
+	}
+	t := int64(in.Val)
+	sec := t / 1000000
+	microsec := t - (sec * 1000000)
+	return time.Unix(sec, microsec*1000).UTC()
+}
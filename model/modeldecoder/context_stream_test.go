@@ -0,0 +1,101 @@
+// Licensed to Elasticsearch B.V. under one or more contributor
+// license agreements. See the NOTICE file distributed with
+// this work for additional information regarding copyright
+// ownership. Elasticsearch B.V. licenses this file to you under
+// the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package modeldecoder
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"io/ioutil"
+	"math/rand"
+	"net"
+	"path/filepath"
+	"testing"
+	"time"
+
+	jsoniter "github.com/json-iterator/go"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/elastic/apm-server/model"
+	"github.com/elastic/apm-server/tests/generator"
+)
+
+type (
+	decodeEventFunc     func(Input, *model.Batch) error
+	decodeEventJSONFunc func(*jsoniter.Iterator, Input, *model.Batch) error
+)
+
+// testDecodeJSONMatches checks that decodeJSON decodes the events of the
+// given type found in the intake testdata, and random events generated from
+// the event's JSON schema, into the same model as decode, and that it rejects
+// all events rejected by decode.
+func testDecodeJSONMatches(t *testing.T, eventType string, decode decodeEventFunc, decodeJSON decodeEventJSONFunc) {
+	var events [][]byte
+	files, err := filepath.Glob(filepath.Join("..", "..", "testdata", "intake-v2", "*.ndjson"))
+	require.NoError(t, err)
+	for _, file := range files {
+		data, err := ioutil.ReadFile(file)
+		require.NoError(t, err)
+		scanner := bufio.NewScanner(bytes.NewReader(data))
+		scanner.Buffer(nil, len(data))
+		for scanner.Scan() {
+			var event map[string]json.RawMessage
+			if json.Unmarshal(scanner.Bytes(), &event) != nil {
+				continue
+			}
+			if raw, ok := event[eventType]; ok {
+				events = append(events, raw)
+			}
+		}
+		require.NoError(t, scanner.Err())
+	}
+	require.NotEmpty(t, events)
+
+	g, err := generator.NewIntake(eventType, rand.New(rand.NewSource(1)))
+	require.NoError(t, err)
+	for i := 0; i < 500; i++ {
+		raw, err := json.Marshal(g.Generate())
+		require.NoError(t, err)
+		events = append(events, raw)
+	}
+
+	requestTime := time.Now()
+	metadata := model.Metadata{
+		Service: model.Service{Name: "myservice", Agent: model.Agent{Name: "go", Version: "1.0"}},
+		User:    model.User{ID: "123", Name: "stream user"},
+		Client:  model.Client{IP: net.ParseIP("10.0.0.1")},
+	}
+	for _, raw := range events {
+		var expected, actual model.Batch
+		input := Input{Raw: decodeJSONString(t, string(raw)), RequestTime: requestTime, Metadata: metadata}
+		expectedErr := decode(input, &expected)
+
+		iter := jsoniter.ConfigCompatibleWithStandardLibrary.BorrowIterator(raw)
+		actualErr := decodeJSON(iter, input, &actual)
+		jsoniter.ConfigCompatibleWithStandardLibrary.ReturnIterator(iter)
+
+		if expectedErr != nil {
+			assert.Error(t, actualErr, "%s", raw)
+			continue
+		}
+		if assert.NoError(t, actualErr, "%s", raw) {
+			assert.Equal(t, expected, actual, "%s", raw)
+		}
+	}
+}
@@ -273,3 +273,34 @@ func TestDecodeContextMetadata(t *testing.T) {
 	require.NoError(t, err)
 	assert.Equal(t, mergedMetadata, inputMetadata)
 }
+
+func TestDecodeContextPreparedMetadata(t *testing.T) {
+	var meta model.Metadata
+	meta.Service.Name = "stream-service"
+	meta.Prepare()
+
+	// Events without context metadata share the prepared stream metadata.
+	unchanged := meta
+	_, err := decodeContext(map[string]interface{}{}, Config{}, &unchanged)
+	require.NoError(t, err)
+	assert.Equal(t, meta, unchanged)
+
+	overridden := meta
+	_, err = decodeContext(map[string]interface{}{
+		"service": map[string]interface{}{"name": "event-service"},
+	}, Config{}, &overridden)
+	require.NoError(t, err)
+	fields := overridden.Set(common.MapStr{})
+	assert.Equal(t, common.MapStr{"name": "event-service"}, fields["service"])
+}
+
+func TestDecodeContextLimits(t *testing.T) {
+	var meta model.Metadata
+	out, err := decodeContext(map[string]interface{}{
+		"custom": map[string]interface{}{"a": map[string]interface{}{"b": "c"}},
+		"tags":   map[string]interface{}{"x": "long value", "y": "z"},
+	}, Config{ContextLimits: ContextLimits{MaxDepth: 1, MaxValueLength: 4}}, &meta)
+	require.NoError(t, err)
+	assert.Equal(t, &model.Custom{"_truncated": true}, out.Custom)
+	assert.Equal(t, &model.Labels{"x": "long", "y": "z", "_truncated": true}, out.Labels)
+}
@@ -29,8 +29,8 @@ import (
 )
 
 var (
-	errorSchema      = validation.CreateSchema(schema.ModelSchema, "error")
-	rumV3ErrorSchema = validation.CreateSchema(schema.RUMV3Schema, "error")
+	errorSchema      = newVersionedSchema(schema.CompiledModelSchema())
+	rumV3ErrorSchema = schema.CompiledRUMV3Schema()
 )
 
 // DecodeRUMV3Error decodes a v3 RUM error.
@@ -45,7 +45,7 @@ func DecodeRUMV3Error(input Input, batch *m.Batch) error {
 
 // DecodeError decodes a v2 error.
 func DecodeError(input Input, batch *m.Batch) error {
-	apmError, err := decodeError(input, errorSchema)
+	apmError, err := decodeError(input, errorSchema.get(input.SchemaVersion))
 	if err != nil {
 		return err
 	}
@@ -0,0 +1,136 @@
+// Licensed to Elasticsearch B.V. under one or more contributor
+// license agreements. See the NOTICE file distributed with
+// this work for additional information regarding copyright
+// ownership. Elasticsearch B.V. licenses this file to you under
+// the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package modeldecoder
+
+import (
+	jsoniter "github.com/json-iterator/go"
+	"github.com/pkg/errors"
+
+	"github.com/elastic/apm-server/model"
+	"github.com/elastic/apm-server/model/modeldecoder/generated/decoder"
+	"github.com/elastic/apm-server/utility"
+)
+
+var (
+	errInvalidError = errors.New("failed to decode error: unexpected value type")
+	errNullError    = errors.New("failed to validate error: error must be an object")
+)
+
+// DecodeErrorJSON decodes and validates a v2 error directly from iter, using
+// the decoder generated from the error JSON schema. Unlike DecodeError, the
+// event is never decoded into an intermediate map; input.Raw is ignored.
+func DecodeErrorJSON(iter *jsoniter.Iterator, input Input, batch *model.Batch) error {
+	var in decoder.Error
+	ok := in.DecodeJSON(iter)
+	if iter.Error != nil {
+		return errors.Wrap(iter.Error, "failed to decode error")
+	}
+	if !ok {
+		return errInvalidError
+	}
+	if !in.IsSet {
+		return errNullError
+	}
+	if err := in.Validate(); err != nil {
+		return errors.Wrap(err, "failed to validate error")
+	}
+	event, err := mapToErrorModel(&in, input)
+	if err != nil {
+		return err
+	}
+	batch.Errors = append(batch.Errors, event)
+	return nil
+}
+
+// mapToErrorModel is the equivalent of decodeError.
+func mapToErrorModel(in *decoder.Error, input Input) (*model.Error, error) {
+	ctx, err := mapToContextModel(&in.Context, input.Config, &input.Metadata)
+	if err != nil {
+		return nil, err
+	}
+	out := model.Error{
+		Metadata:           input.Metadata,
+		ID:                 stringPtr(in.ID),
+		Culprit:            stringPtr(in.Culprit),
+		Labels:             ctx.Labels,
+		Page:               ctx.Page,
+		HTTP:               ctx.Http,
+		URL:                ctx.URL,
+		Custom:             ctx.Custom,
+		Timestamp:          timeEpochMicro(in.Timestamp),
+		TransactionSampled: boolPtr(in.Transaction.Sampled),
+		TransactionType:    stringPtr(in.Transaction.Type),
+		ParentID:           in.ParentID.Val,
+		TraceID:            in.TraceID.Val,
+		TransactionID:      in.TransactionID.Val,
+	}
+
+	if ex := &in.Exception; ex.Message.IsSet || ex.Type.IsSet {
+		if out.Exception, err = mapToExceptionModel(ex, input.Config); err != nil {
+			return nil, err
+		}
+	}
+	if log := &in.Log; log.Message.IsSet {
+		out.Log = &model.Log{
+			Message:      log.Message.Val,
+			ParamMessage: stringPtr(log.ParamMessage),
+			Level:        stringPtr(log.Level),
+			LoggerName:   stringPtr(log.LoggerName),
+			Stacktrace:   model.Stacktrace{},
+		}
+		if log.Stacktrace != nil {
+			out.Log.Stacktrace = mapToStacktraceModel(log.Stacktrace)
+		}
+	}
+	if out.Timestamp.IsZero() {
+		out.Timestamp = input.RequestTime
+	}
+	return &out, nil
+}
+
+// mapToExceptionModel is the equivalent of decodeException. Causes are
+// not typed by the schema, and are decoded by decodeException.
+func mapToExceptionModel(in *decoder.ErrorException, cfg Config) (*model.Exception, error) {
+	ex := model.Exception{
+		Message:    stringPtr(in.Message),
+		Type:       stringPtr(in.Type),
+		Code:       in.Code.Val,
+		Module:     stringPtr(in.Module),
+		Attributes: mapInterface(in.Attributes),
+		Handled:    boolPtr(in.Handled),
+		Stacktrace: model.Stacktrace{},
+	}
+	if in.Stacktrace != nil {
+		ex.Stacktrace = mapToStacktraceModel(in.Stacktrace)
+	}
+	causeDecoder := utility.ManualDecoder{}
+	decodeCause := decodeException(&causeDecoder, cfg.HasShortFieldNames)
+	for _, cause := range in.Cause {
+		if cause == nil {
+			return nil, errors.New("cause must be an exception")
+		}
+		nested := decodeCause(cause)
+		if causeDecoder.Err != nil {
+			return nil, causeDecoder.Err
+		}
+		if nested != nil {
+			ex.Cause = append(ex.Cause, *nested)
+		}
+	}
+	return &ex, nil
+}
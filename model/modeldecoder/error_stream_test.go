@@ -0,0 +1,58 @@
+// Licensed to Elasticsearch B.V. under one or more contributor
+// license agreements. See the NOTICE file distributed with
+// this work for additional information regarding copyright
+// ownership. Elasticsearch B.V. licenses this file to you under
+// the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package modeldecoder
+
+import (
+	"testing"
+
+	jsoniter "github.com/json-iterator/go"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/elastic/apm-server/model"
+)
+
+func TestDecodeErrorJSONMatchesDecodeError(t *testing.T) {
+	testDecodeJSONMatches(t, "error", DecodeError, DecodeErrorJSON)
+}
+
+func TestDecodeErrorJSONInvalid(t *testing.T) {
+	for name, input := range map[string]string{
+		"null":                      `null`,
+		"missing id":                `{"log": {"message": "a"}}`,
+		"missing exception and log": `{"id": "a"}`,
+		"exception without message": `{"id": "a", "exception": {"module": "b"}}`,
+		"transaction without trace": `{"id": "a", "log": {"message": "b"}, "transaction_id": "c", "parent_id": "d"}`,
+		"trace without parent":      `{"id": "a", "log": {"message": "b"}, "trace_id": "c"}`,
+		"parent without trace":      `{"id": "a", "log": {"message": "b"}, "parent_id": "c"}`,
+		"fractional code":           `{"id": "a", "exception": {"message": "b", "code": 1.5}}`,
+		"null cause":                `{"id": "a", "exception": {"message": "b", "cause": [null]}}`,
+		"invalid cause":             `{"id": "a", "exception": {"message": "b", "cause": [{"message": 1}]}}`,
+		"fractional user id":        `{"id": "a", "log": {"message": "b"}, "context": {"user": {"id": 1.5}}}`,
+	} {
+		t.Run(name, func(t *testing.T) {
+			iter := jsoniter.ConfigCompatibleWithStandardLibrary.BorrowIterator([]byte(input))
+			defer jsoniter.ConfigCompatibleWithStandardLibrary.ReturnIterator(iter)
+			var batch model.Batch
+			assert.Error(t, DecodeErrorJSON(iter, Input{}, &batch))
+			assert.Empty(t, batch.Errors)
+
+			// The generated decoder must agree with the JSON schema and model decoder.
+			assert.Error(t, DecodeError(Input{Raw: decodeJSONString(t, input)}, &batch))
+		})
+	}
+}
@@ -0,0 +1,3020 @@
+// Licensed to Elasticsearch B.V. under one or more contributor
+// license agreements. See the NOTICE file distributed with
+// this work for additional information regarding copyright
+// ownership. Elasticsearch B.V. licenses this file to you under
+// the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+// Code generated by script/generate_decoders. DO NOT EDIT.
+
+package decoder
+
+import (
+	"encoding/json"
+	"github.com/elastic/apm-server/model/modeldecoder/nullable"
+	jsoniter "github.com/json-iterator/go"
+	"github.com/pkg/errors"
+	"regexp"
+	"unicode/utf8"
+)
+
+var (
+	pattern0 = regexp.MustCompile("^[^.*\"]*$")
+	pattern1 = regexp.MustCompile("^[a-zA-Z0-9 _-]+$")
+)
+
+// MessageAge holds the decoded input for the "MessageAge" schema.
+type MessageAge struct {
+	Ms nullable.Int `json:"ms"`
+
+	// IsSet reports whether a JSON object was decoded.
+	IsSet bool `json:"-"`
+}
+
+// MessageQueue holds the decoded input for the "MessageQueue" schema.
+type MessageQueue struct {
+	Name nullable.String `json:"name"`
+
+	// IsSet reports whether a JSON object was decoded.
+	IsSet bool `json:"-"`
+}
+
+// Message holds the decoded input for the "Message" schema.
+type Message struct {
+	Age     MessageAge             `json:"age"`
+	Body    nullable.String        `json:"body"`
+	Headers map[string]interface{} `json:"headers"`
+	Queue   MessageQueue           `json:"queue"`
+
+	// IsSet reports whether a JSON object was decoded.
+	IsSet bool `json:"-"`
+}
+
+// ContextPage holds the decoded input for the "ContextPage" schema.
+type ContextPage struct {
+	Referer nullable.String `json:"referer"`
+	URL     nullable.String `json:"url"`
+
+	// IsSet reports whether a JSON object was decoded.
+	IsSet bool `json:"-"`
+}
+
+// RequestSocket holds the decoded input for the "RequestSocket" schema.
+type RequestSocket struct {
+	Encrypted     nullable.Bool   `json:"encrypted"`
+	RemoteAddress nullable.String `json:"remote_address"`
+
+	// IsSet reports whether a JSON object was decoded.
+	IsSet bool `json:"-"`
+}
+
+// RequestURL holds the decoded input for the "RequestURL" schema.
+type RequestURL struct {
+	Full     nullable.String    `json:"full"`
+	Hash     nullable.String    `json:"hash"`
+	Hostname nullable.String    `json:"hostname"`
+	Pathname nullable.String    `json:"pathname"`
+	Port     nullable.Interface `json:"port"`
+	Protocol nullable.String    `json:"protocol"`
+	Raw      nullable.String    `json:"raw"`
+	Search   nullable.String    `json:"search"`
+
+	// IsSet reports whether a JSON object was decoded.
+	IsSet bool `json:"-"`
+}
+
+// Request holds the decoded input for the "Request" schema.
+type Request struct {
+	Body        nullable.Interface     `json:"body"`
+	Cookies     map[string]interface{} `json:"cookies"`
+	Env         map[string]interface{} `json:"env"`
+	Headers     map[string]interface{} `json:"headers"`
+	HTTPVersion nullable.String        `json:"http_version"`
+	Method      nullable.String        `json:"method"`
+	Socket      RequestSocket          `json:"socket"`
+	URL         RequestURL             `json:"url"`
+
+	// IsSet reports whether a JSON object was decoded.
+	IsSet bool `json:"-"`
+}
+
+// ContextResponse holds the decoded input for the "ContextResponse" schema.
+type ContextResponse struct {
+	DecodedBodySize nullable.Float64       `json:"decoded_body_size"`
+	EncodedBodySize nullable.Float64       `json:"encoded_body_size"`
+	Finished        nullable.Bool          `json:"finished"`
+	Headers         map[string]interface{} `json:"headers"`
+	HeadersSent     nullable.Bool          `json:"headers_sent"`
+	StatusCode      nullable.Int           `json:"status_code"`
+	TransferSize    nullable.Float64       `json:"transfer_size"`
+
+	// IsSet reports whether a JSON object was decoded.
+	IsSet bool `json:"-"`
+}
+
+// ServiceAgent holds the decoded input for the "ServiceAgent" schema.
+type ServiceAgent struct {
+	EphemeralID nullable.String `json:"ephemeral_id"`
+	Name        nullable.String `json:"name"`
+	Version     nullable.String `json:"version"`
+
+	// IsSet reports whether a JSON object was decoded.
+	IsSet bool `json:"-"`
+}
+
+// ServiceFramework holds the decoded input for the "ServiceFramework" schema.
+type ServiceFramework struct {
+	Name    nullable.String `json:"name"`
+	Version nullable.String `json:"version"`
+
+	// IsSet reports whether a JSON object was decoded.
+	IsSet bool `json:"-"`
+}
+
+// ServiceLanguage holds the decoded input for the "ServiceLanguage" schema.
+type ServiceLanguage struct {
+	Name    nullable.String `json:"name"`
+	Version nullable.String `json:"version"`
+
+	// IsSet reports whether a JSON object was decoded.
+	IsSet bool `json:"-"`
+}
+
+// ServiceNode holds the decoded input for the "ServiceNode" schema.
+type ServiceNode struct {
+	ConfiguredName nullable.String `json:"configured_name"`
+
+	// IsSet reports whether a JSON object was decoded.
+	IsSet bool `json:"-"`
+}
+
+// ServiceRuntime holds the decoded input for the "ServiceRuntime" schema.
+type ServiceRuntime struct {
+	Name    nullable.String `json:"name"`
+	Version nullable.String `json:"version"`
+
+	// IsSet reports whether a JSON object was decoded.
+	IsSet bool `json:"-"`
+}
+
+// Service holds the decoded input for the "Service" schema.
+type Service struct {
+	Agent       ServiceAgent     `json:"agent"`
+	Environment nullable.String  `json:"environment"`
+	Framework   ServiceFramework `json:"framework"`
+	Language    ServiceLanguage  `json:"language"`
+	Name        nullable.String  `json:"name"`
+	Node        ServiceNode      `json:"node"`
+	Runtime     ServiceRuntime   `json:"runtime"`
+	Version     nullable.String  `json:"version"`
+
+	// IsSet reports whether a JSON object was decoded.
+	IsSet bool `json:"-"`
+}
+
+// User holds the decoded input for the "User" schema.
+type User struct {
+	Email    nullable.String    `json:"email"`
+	ID       nullable.Interface `json:"id"`
+	IP       nullable.String    `json:"ip"`
+	Username nullable.String    `json:"username"`
+
+	// IsSet reports whether a JSON object was decoded.
+	IsSet bool `json:"-"`
+}
+
+// Context holds the decoded input for the "Context" schema.
+type Context struct {
+	Custom   map[string]interface{} `json:"custom"`
+	Message  Message                `json:"message"`
+	Page     ContextPage            `json:"page"`
+	Request  Request                `json:"request"`
+	Response ContextResponse        `json:"response"`
+	Service  Service                `json:"service"`
+	Tags     map[string]interface{} `json:"tags"`
+	User     User                   `json:"user"`
+
+	// IsSet reports whether a JSON object was decoded.
+	IsSet bool `json:"-"`
+}
+
+// StacktraceFrame holds the decoded input for the "StacktraceFrame" schema.
+type StacktraceFrame struct {
+	AbsPath      nullable.String        `json:"abs_path"`
+	Classname    nullable.String        `json:"classname"`
+	Colno        nullable.Int           `json:"colno"`
+	ContextLine  nullable.String        `json:"context_line"`
+	Filename     nullable.String        `json:"filename"`
+	Function     nullable.String        `json:"function"`
+	LibraryFrame nullable.Bool          `json:"library_frame"`
+	Lineno       nullable.Int           `json:"lineno"`
+	Module       nullable.String        `json:"module"`
+	PostContext  []nullable.String      `json:"post_context"`
+	PreContext   []nullable.String      `json:"pre_context"`
+	Vars         map[string]interface{} `json:"vars"`
+
+	// IsSet reports whether a JSON object was decoded.
+	IsSet bool `json:"-"`
+}
+
+// ErrorException holds the decoded input for the "ErrorException" schema.
+type ErrorException struct {
+	Attributes map[string]interface{}   `json:"attributes"`
+	Cause      []map[string]interface{} `json:"cause"`
+	Code       nullable.Interface       `json:"code"`
+	Handled    nullable.Bool            `json:"handled"`
+	Message    nullable.String          `json:"message"`
+	Module     nullable.String          `json:"module"`
+	Stacktrace []StacktraceFrame        `json:"stacktrace"`
+	Type       nullable.String          `json:"type"`
+
+	// IsSet reports whether a JSON object was decoded.
+	IsSet bool `json:"-"`
+}
+
+// ErrorLog holds the decoded input for the "ErrorLog" schema.
+type ErrorLog struct {
+	Level        nullable.String   `json:"level"`
+	LoggerName   nullable.String   `json:"logger_name"`
+	Message      nullable.String   `json:"message"`
+	ParamMessage nullable.String   `json:"param_message"`
+	Stacktrace   []StacktraceFrame `json:"stacktrace"`
+
+	// IsSet reports whether a JSON object was decoded.
+	IsSet bool `json:"-"`
+}
+
+// ErrorTransaction holds the decoded input for the "ErrorTransaction" schema.
+type ErrorTransaction struct {
+	Sampled nullable.Bool   `json:"sampled"`
+	Type    nullable.String `json:"type"`
+
+	// IsSet reports whether a JSON object was decoded.
+	IsSet bool `json:"-"`
+}
+
+// Error holds the decoded input for the "Error" schema.
+type Error struct {
+	Context       Context          `json:"context"`
+	Culprit       nullable.String  `json:"culprit"`
+	Exception     ErrorException   `json:"exception"`
+	ID            nullable.String  `json:"id"`
+	Log           ErrorLog         `json:"log"`
+	ParentID      nullable.String  `json:"parent_id"`
+	Timestamp     nullable.Int     `json:"timestamp"`
+	TraceID       nullable.String  `json:"trace_id"`
+	Transaction   ErrorTransaction `json:"transaction"`
+	TransactionID nullable.String  `json:"transaction_id"`
+
+	// IsSet reports whether a JSON object was decoded.
+	IsSet bool `json:"-"`
+}
+
+// SpanContextDB holds the decoded input for the "SpanContextDB" schema.
+type SpanContextDB struct {
+	Instance     nullable.String `json:"instance"`
+	Link         nullable.String `json:"link"`
+	RowsAffected nullable.Int    `json:"rows_affected"`
+	Statement    nullable.String `json:"statement"`
+	Type         nullable.String `json:"type"`
+	User         nullable.String `json:"user"`
+
+	// IsSet reports whether a JSON object was decoded.
+	IsSet bool `json:"-"`
+}
+
+// SpanContextDestinationService holds the decoded input for the "SpanContextDestinationService" schema.
+type SpanContextDestinationService struct {
+	Name     nullable.String `json:"name"`
+	Resource nullable.String `json:"resource"`
+	Type     nullable.String `json:"type"`
+
+	// IsSet reports whether a JSON object was decoded.
+	IsSet bool `json:"-"`
+}
+
+// SpanContextDestination holds the decoded input for the "SpanContextDestination" schema.
+type SpanContextDestination struct {
+	Address nullable.String               `json:"address"`
+	Port    nullable.Int                  `json:"port"`
+	Service SpanContextDestinationService `json:"service"`
+
+	// IsSet reports whether a JSON object was decoded.
+	IsSet bool `json:"-"`
+}
+
+// HTTPResponse holds the decoded input for the "HTTPResponse" schema.
+type HTTPResponse struct {
+	DecodedBodySize nullable.Float64       `json:"decoded_body_size"`
+	EncodedBodySize nullable.Float64       `json:"encoded_body_size"`
+	Headers         map[string]interface{} `json:"headers"`
+	StatusCode      nullable.Int           `json:"status_code"`
+	TransferSize    nullable.Float64       `json:"transfer_size"`
+
+	// IsSet reports whether a JSON object was decoded.
+	IsSet bool `json:"-"`
+}
+
+// SpanContextHTTP holds the decoded input for the "SpanContextHTTP" schema.
+type SpanContextHTTP struct {
+	Method     nullable.String `json:"method"`
+	Response   HTTPResponse    `json:"response"`
+	StatusCode nullable.Int    `json:"status_code"`
+	URL        nullable.String `json:"url"`
+
+	// IsSet reports whether a JSON object was decoded.
+	IsSet bool `json:"-"`
+}
+
+// SpanContext holds the decoded input for the "SpanContext" schema.
+type SpanContext struct {
+	DB          SpanContextDB          `json:"db"`
+	Destination SpanContextDestination `json:"destination"`
+	HTTP        SpanContextHTTP        `json:"http"`
+	Message     Message                `json:"message"`
+	Service     Service                `json:"service"`
+	Tags        map[string]interface{} `json:"tags"`
+
+	// IsSet reports whether a JSON object was decoded.
+	IsSet bool `json:"-"`
+}
+
+// Span holds the decoded input for the "Span" schema.
+type Span struct {
+	Action        nullable.String   `json:"action"`
+	ChildIds      []nullable.String `json:"child_ids"`
+	Context       SpanContext       `json:"context"`
+	Duration      nullable.Float64  `json:"duration"`
+	ID            nullable.String   `json:"id"`
+	Name          nullable.String   `json:"name"`
+	ParentID      nullable.String   `json:"parent_id"`
+	Stacktrace    []StacktraceFrame `json:"stacktrace"`
+	Start         nullable.Float64  `json:"start"`
+	Subtype       nullable.String   `json:"subtype"`
+	Sync          nullable.Bool     `json:"sync"`
+	Timestamp     nullable.Int      `json:"timestamp"`
+	TraceID       nullable.String   `json:"trace_id"`
+	TransactionID nullable.String   `json:"transaction_id"`
+	Type          nullable.String   `json:"type"`
+
+	// IsSet reports whether a JSON object was decoded.
+	IsSet bool `json:"-"`
+}
+
+// TransactionDroppedSpansStatsItemDurationSum holds the decoded input for the "TransactionDroppedSpansStatsItemDurationSum" schema.
+type TransactionDroppedSpansStatsItemDurationSum struct {
+	Us nullable.Int `json:"us"`
+
+	// IsSet reports whether a JSON object was decoded.
+	IsSet bool `json:"-"`
+}
+
+// TransactionDroppedSpansStatsItemDuration holds the decoded input for the "TransactionDroppedSpansStatsItemDuration" schema.
+type TransactionDroppedSpansStatsItemDuration struct {
+	Count nullable.Int                                `json:"count"`
+	Sum   TransactionDroppedSpansStatsItemDurationSum `json:"sum"`
+
+	// IsSet reports whether a JSON object was decoded.
+	IsSet bool `json:"-"`
+}
+
+// TransactionDroppedSpansStatsItem holds the decoded input for the "TransactionDroppedSpansStatsItem" schema.
+type TransactionDroppedSpansStatsItem struct {
+	DestinationServiceResource nullable.String                          `json:"destination_service_resource"`
+	Duration                   TransactionDroppedSpansStatsItemDuration `json:"duration"`
+	Outcome                    nullable.String                          `json:"outcome"`
+
+	// IsSet reports whether a JSON object was decoded.
+	IsSet bool `json:"-"`
+}
+
+// TransactionSpanCount holds the decoded input for the "TransactionSpanCount" schema.
+type TransactionSpanCount struct {
+	Dropped nullable.Int `json:"dropped"`
+	Started nullable.Int `json:"started"`
+
+	// IsSet reports whether a JSON object was decoded.
+	IsSet bool `json:"-"`
+}
+
+// Transaction holds the decoded input for the "Transaction" schema.
+type Transaction struct {
+	Context           Context                            `json:"context"`
+	DroppedSpansStats []TransactionDroppedSpansStatsItem `json:"dropped_spans_stats"`
+	Duration          nullable.Float64                   `json:"duration"`
+	ID                nullable.String                    `json:"id"`
+	Marks             map[string]map[string]interface{}  `json:"marks"`
+	Name              nullable.String                    `json:"name"`
+	ParentID          nullable.String                    `json:"parent_id"`
+	Result            nullable.String                    `json:"result"`
+	Sampled           nullable.Bool                      `json:"sampled"`
+	SpanCount         TransactionSpanCount               `json:"span_count"`
+	Timestamp         nullable.Int                       `json:"timestamp"`
+	TraceID           nullable.String                    `json:"trace_id"`
+	Type              nullable.String                    `json:"type"`
+
+	// IsSet reports whether a JSON object was decoded.
+	IsSet bool `json:"-"`
+}
+
+// DecodeJSON decodes the next JSON object from iter into v, reporting
+// whether the input matched the types defined in the schema.
+func (v *MessageAge) DecodeJSON(iter *jsoniter.Iterator) bool {
+	switch iter.WhatIsNext() {
+	case jsoniter.NilValue:
+		iter.ReadNil()
+		*v = MessageAge{}
+		return true
+	case jsoniter.ObjectValue:
+	default:
+		iter.Skip()
+		return false
+	}
+	*v = MessageAge{IsSet: true}
+	ok := true
+	iter.ReadMapCB(func(iter *jsoniter.Iterator, field string) bool {
+		switch field {
+		case "ms":
+			ok = v.Ms.DecodeJSON(iter)
+		default:
+			iter.Skip()
+		}
+		return ok
+	})
+	return ok
+}
+
+// Validate checks v against the constraints defined in the schema
+// which are not already enforced by DecodeJSON.
+func (v *MessageAge) Validate() error {
+	if !v.IsSet {
+		return nil
+	}
+	return nil
+}
+
+// DecodeJSON decodes the next JSON object from iter into v, reporting
+// whether the input matched the types defined in the schema.
+func (v *MessageQueue) DecodeJSON(iter *jsoniter.Iterator) bool {
+	switch iter.WhatIsNext() {
+	case jsoniter.NilValue:
+		iter.ReadNil()
+		*v = MessageQueue{}
+		return true
+	case jsoniter.ObjectValue:
+	default:
+		iter.Skip()
+		return false
+	}
+	*v = MessageQueue{IsSet: true}
+	ok := true
+	iter.ReadMapCB(func(iter *jsoniter.Iterator, field string) bool {
+		switch field {
+		case "name":
+			ok = v.Name.DecodeJSON(iter)
+		default:
+			iter.Skip()
+		}
+		return ok
+	})
+	return ok
+}
+
+// Validate checks v against the constraints defined in the schema
+// which are not already enforced by DecodeJSON.
+func (v *MessageQueue) Validate() error {
+	if !v.IsSet {
+		return nil
+	}
+	if v.Name.IsSet {
+		if utf8.RuneCountInString(v.Name.Val) > 1024 {
+			return errors.New("name: length must be <= 1024")
+		}
+	}
+	return nil
+}
+
+// DecodeJSON decodes the next JSON object from iter into v, reporting
+// whether the input matched the types defined in the schema.
+func (v *Message) DecodeJSON(iter *jsoniter.Iterator) bool {
+	switch iter.WhatIsNext() {
+	case jsoniter.NilValue:
+		iter.ReadNil()
+		*v = Message{}
+		return true
+	case jsoniter.ObjectValue:
+	default:
+		iter.Skip()
+		return false
+	}
+	*v = Message{IsSet: true}
+	ok := true
+	iter.ReadMapCB(func(iter *jsoniter.Iterator, field string) bool {
+		switch field {
+		case "age":
+			ok = v.Age.DecodeJSON(iter)
+		case "body":
+			ok = v.Body.DecodeJSON(iter)
+		case "headers":
+			switch iter.WhatIsNext() {
+			case jsoniter.NilValue:
+				iter.ReadNil()
+				v.Headers = nil
+			case jsoniter.ObjectValue:
+				v.Headers = make(map[string]interface{})
+				iter.ReadMapCB(func(iter *jsoniter.Iterator, key string) bool {
+					elem := nullable.ReadInterface(iter)
+					v.Headers[key] = elem
+					return ok
+				})
+			default:
+				iter.Skip()
+				ok = false
+			}
+		case "queue":
+			ok = v.Queue.DecodeJSON(iter)
+		default:
+			iter.Skip()
+		}
+		return ok
+	})
+	return ok
+}
+
+// Validate checks v against the constraints defined in the schema
+// which are not already enforced by DecodeJSON.
+func (v *Message) Validate() error {
+	if !v.IsSet {
+		return nil
+	}
+	if err := v.Age.Validate(); err != nil {
+		return errors.Wrap(err, "age")
+	}
+	for k, elem := range v.Headers {
+		_ = k
+		switch val := elem.(type) {
+		case string:
+		case []interface{}:
+			for _, elem := range val {
+				switch val := elem.(type) {
+				case string:
+				default:
+					return errors.Errorf("headers: unexpected type %T", val)
+				}
+			}
+		case nil:
+		default:
+			return errors.Errorf("headers: unexpected type %T", val)
+		}
+	}
+	if err := v.Queue.Validate(); err != nil {
+		return errors.Wrap(err, "queue")
+	}
+	return nil
+}
+
+// DecodeJSON decodes the next JSON object from iter into v, reporting
+// whether the input matched the types defined in the schema.
+func (v *ContextPage) DecodeJSON(iter *jsoniter.Iterator) bool {
+	switch iter.WhatIsNext() {
+	case jsoniter.NilValue:
+		iter.ReadNil()
+		*v = ContextPage{}
+		return true
+	case jsoniter.ObjectValue:
+	default:
+		iter.Skip()
+		return false
+	}
+	*v = ContextPage{IsSet: true}
+	ok := true
+	iter.ReadMapCB(func(iter *jsoniter.Iterator, field string) bool {
+		switch field {
+		case "referer":
+			ok = v.Referer.DecodeJSON(iter)
+		case "url":
+			ok = v.URL.DecodeJSON(iter)
+		default:
+			iter.Skip()
+		}
+		return ok
+	})
+	return ok
+}
+
+// Validate checks v against the constraints defined in the schema
+// which are not already enforced by DecodeJSON.
+func (v *ContextPage) Validate() error {
+	if !v.IsSet {
+		return nil
+	}
+	return nil
+}
+
+// DecodeJSON decodes the next JSON object from iter into v, reporting
+// whether the input matched the types defined in the schema.
+func (v *RequestSocket) DecodeJSON(iter *jsoniter.Iterator) bool {
+	switch iter.WhatIsNext() {
+	case jsoniter.NilValue:
+		iter.ReadNil()
+		*v = RequestSocket{}
+		return true
+	case jsoniter.ObjectValue:
+	default:
+		iter.Skip()
+		return false
+	}
+	*v = RequestSocket{IsSet: true}
+	ok := true
+	iter.ReadMapCB(func(iter *jsoniter.Iterator, field string) bool {
+		switch field {
+		case "encrypted":
+			ok = v.Encrypted.DecodeJSON(iter)
+		case "remote_address":
+			ok = v.RemoteAddress.DecodeJSON(iter)
+		default:
+			iter.Skip()
+		}
+		return ok
+	})
+	return ok
+}
+
+// Validate checks v against the constraints defined in the schema
+// which are not already enforced by DecodeJSON.
+func (v *RequestSocket) Validate() error {
+	if !v.IsSet {
+		return nil
+	}
+	return nil
+}
+
+// DecodeJSON decodes the next JSON object from iter into v, reporting
+// whether the input matched the types defined in the schema.
+func (v *RequestURL) DecodeJSON(iter *jsoniter.Iterator) bool {
+	switch iter.WhatIsNext() {
+	case jsoniter.NilValue:
+		iter.ReadNil()
+		*v = RequestURL{}
+		return true
+	case jsoniter.ObjectValue:
+	default:
+		iter.Skip()
+		return false
+	}
+	*v = RequestURL{IsSet: true}
+	ok := true
+	iter.ReadMapCB(func(iter *jsoniter.Iterator, field string) bool {
+		switch field {
+		case "full":
+			ok = v.Full.DecodeJSON(iter)
+		case "hash":
+			ok = v.Hash.DecodeJSON(iter)
+		case "hostname":
+			ok = v.Hostname.DecodeJSON(iter)
+		case "pathname":
+			ok = v.Pathname.DecodeJSON(iter)
+		case "port":
+			ok = v.Port.DecodeJSON(iter)
+		case "protocol":
+			ok = v.Protocol.DecodeJSON(iter)
+		case "raw":
+			ok = v.Raw.DecodeJSON(iter)
+		case "search":
+			ok = v.Search.DecodeJSON(iter)
+		default:
+			iter.Skip()
+		}
+		return ok
+	})
+	return ok
+}
+
+// Validate checks v against the constraints defined in the schema
+// which are not already enforced by DecodeJSON.
+func (v *RequestURL) Validate() error {
+	if !v.IsSet {
+		return nil
+	}
+	if v.Full.IsSet {
+		if utf8.RuneCountInString(v.Full.Val) > 1024 {
+			return errors.New("full: length must be <= 1024")
+		}
+	}
+	if v.Hash.IsSet {
+		if utf8.RuneCountInString(v.Hash.Val) > 1024 {
+			return errors.New("hash: length must be <= 1024")
+		}
+	}
+	if v.Hostname.IsSet {
+		if utf8.RuneCountInString(v.Hostname.Val) > 1024 {
+			return errors.New("hostname: length must be <= 1024")
+		}
+	}
+	if v.Pathname.IsSet {
+		if utf8.RuneCountInString(v.Pathname.Val) > 1024 {
+			return errors.New("pathname: length must be <= 1024")
+		}
+	}
+	if v.Port.IsSet {
+		switch val := v.Port.Val.(type) {
+		case string:
+			if utf8.RuneCountInString(val) > 1024 {
+				return errors.New("port: length must be <= 1024")
+			}
+		case json.Number:
+			if _, err := val.Int64(); err != nil {
+				return errors.New("port: value must be an integer")
+			}
+		case nil:
+		default:
+			return errors.Errorf("port: unexpected type %T", val)
+		}
+	}
+	if v.Protocol.IsSet {
+		if utf8.RuneCountInString(v.Protocol.Val) > 1024 {
+			return errors.New("protocol: length must be <= 1024")
+		}
+	}
+	if v.Raw.IsSet {
+		if utf8.RuneCountInString(v.Raw.Val) > 1024 {
+			return errors.New("raw: length must be <= 1024")
+		}
+	}
+	if v.Search.IsSet {
+		if utf8.RuneCountInString(v.Search.Val) > 1024 {
+			return errors.New("search: length must be <= 1024")
+		}
+	}
+	return nil
+}
+
+// DecodeJSON decodes the next JSON object from iter into v, reporting
+// whether the input matched the types defined in the schema.
+func (v *Request) DecodeJSON(iter *jsoniter.Iterator) bool {
+	switch iter.WhatIsNext() {
+	case jsoniter.NilValue:
+		iter.ReadNil()
+		*v = Request{}
+		return true
+	case jsoniter.ObjectValue:
+	default:
+		iter.Skip()
+		return false
+	}
+	*v = Request{IsSet: true}
+	ok := true
+	iter.ReadMapCB(func(iter *jsoniter.Iterator, field string) bool {
+		switch field {
+		case "body":
+			ok = v.Body.DecodeJSON(iter)
+		case "cookies":
+			switch iter.WhatIsNext() {
+			case jsoniter.NilValue:
+				iter.ReadNil()
+				v.Cookies = nil
+			case jsoniter.ObjectValue:
+				v.Cookies = make(map[string]interface{})
+				iter.ReadMapCB(func(iter *jsoniter.Iterator, key string) bool {
+					elem := nullable.ReadInterface(iter)
+					v.Cookies[key] = elem
+					return ok
+				})
+			default:
+				iter.Skip()
+				ok = false
+			}
+		case "env":
+			switch iter.WhatIsNext() {
+			case jsoniter.NilValue:
+				iter.ReadNil()
+				v.Env = nil
+			case jsoniter.ObjectValue:
+				v.Env = make(map[string]interface{})
+				iter.ReadMapCB(func(iter *jsoniter.Iterator, key string) bool {
+					elem := nullable.ReadInterface(iter)
+					v.Env[key] = elem
+					return ok
+				})
+			default:
+				iter.Skip()
+				ok = false
+			}
+		case "headers":
+			switch iter.WhatIsNext() {
+			case jsoniter.NilValue:
+				iter.ReadNil()
+				v.Headers = nil
+			case jsoniter.ObjectValue:
+				v.Headers = make(map[string]interface{})
+				iter.ReadMapCB(func(iter *jsoniter.Iterator, key string) bool {
+					elem := nullable.ReadInterface(iter)
+					v.Headers[key] = elem
+					return ok
+				})
+			default:
+				iter.Skip()
+				ok = false
+			}
+		case "http_version":
+			ok = v.HTTPVersion.DecodeJSON(iter)
+		case "method":
+			if iter.WhatIsNext() == jsoniter.NilValue {
+				iter.Skip()
+				ok = false
+				break
+			}
+			ok = v.Method.DecodeJSON(iter)
+		case "socket":
+			ok = v.Socket.DecodeJSON(iter)
+		case "url":
+			if iter.WhatIsNext() == jsoniter.NilValue {
+				iter.Skip()
+				ok = false
+				break
+			}
+			ok = v.URL.DecodeJSON(iter)
+		default:
+			iter.Skip()
+		}
+		return ok
+	})
+	return ok
+}
+
+// Validate checks v against the constraints defined in the schema
+// which are not already enforced by DecodeJSON.
+func (v *Request) Validate() error {
+	if !v.IsSet {
+		return nil
+	}
+	if !v.URL.IsSet {
+		return errors.New("url: missing required property")
+	}
+	if !v.Method.IsSet {
+		return errors.New("method: missing required property")
+	}
+	if v.Body.IsSet {
+		switch val := v.Body.Val.(type) {
+		case string:
+		case map[string]interface{}, nil:
+		default:
+			return errors.Errorf("body: unexpected type %T", val)
+		}
+	}
+	for k, elem := range v.Headers {
+		_ = k
+		switch val := elem.(type) {
+		case string:
+		case []interface{}:
+			for _, elem := range val {
+				switch val := elem.(type) {
+				case string:
+				default:
+					return errors.Errorf("headers: unexpected type %T", val)
+				}
+			}
+		case nil:
+		default:
+			return errors.Errorf("headers: unexpected type %T", val)
+		}
+	}
+	if v.HTTPVersion.IsSet {
+		if utf8.RuneCountInString(v.HTTPVersion.Val) > 1024 {
+			return errors.New("http_version: length must be <= 1024")
+		}
+	}
+	if v.Method.IsSet {
+		if utf8.RuneCountInString(v.Method.Val) > 1024 {
+			return errors.New("method: length must be <= 1024")
+		}
+	}
+	if err := v.Socket.Validate(); err != nil {
+		return errors.Wrap(err, "socket")
+	}
+	if err := v.URL.Validate(); err != nil {
+		return errors.Wrap(err, "url")
+	}
+	return nil
+}
+
+// DecodeJSON decodes the next JSON object from iter into v, reporting
+// whether the input matched the types defined in the schema.
+func (v *ContextResponse) DecodeJSON(iter *jsoniter.Iterator) bool {
+	switch iter.WhatIsNext() {
+	case jsoniter.NilValue:
+		iter.ReadNil()
+		*v = ContextResponse{}
+		return true
+	case jsoniter.ObjectValue:
+	default:
+		iter.Skip()
+		return false
+	}
+	*v = ContextResponse{IsSet: true}
+	ok := true
+	iter.ReadMapCB(func(iter *jsoniter.Iterator, field string) bool {
+		switch field {
+		case "decoded_body_size":
+			ok = v.DecodedBodySize.DecodeJSON(iter)
+		case "encoded_body_size":
+			ok = v.EncodedBodySize.DecodeJSON(iter)
+		case "finished":
+			ok = v.Finished.DecodeJSON(iter)
+		case "headers":
+			switch iter.WhatIsNext() {
+			case jsoniter.NilValue:
+				iter.ReadNil()
+				v.Headers = nil
+			case jsoniter.ObjectValue:
+				v.Headers = make(map[string]interface{})
+				iter.ReadMapCB(func(iter *jsoniter.Iterator, key string) bool {
+					elem := nullable.ReadInterface(iter)
+					v.Headers[key] = elem
+					return ok
+				})
+			default:
+				iter.Skip()
+				ok = false
+			}
+		case "headers_sent":
+			ok = v.HeadersSent.DecodeJSON(iter)
+		case "status_code":
+			ok = v.StatusCode.DecodeJSON(iter)
+		case "transfer_size":
+			ok = v.TransferSize.DecodeJSON(iter)
+		default:
+			iter.Skip()
+		}
+		return ok
+	})
+	return ok
+}
+
+// Validate checks v against the constraints defined in the schema
+// which are not already enforced by DecodeJSON.
+func (v *ContextResponse) Validate() error {
+	if !v.IsSet {
+		return nil
+	}
+	for k, elem := range v.Headers {
+		_ = k
+		switch val := elem.(type) {
+		case string:
+		case []interface{}:
+			for _, elem := range val {
+				switch val := elem.(type) {
+				case string:
+				default:
+					return errors.Errorf("headers: unexpected type %T", val)
+				}
+			}
+		case nil:
+		default:
+			return errors.Errorf("headers: unexpected type %T", val)
+		}
+	}
+	return nil
+}
+
+// DecodeJSON decodes the next JSON object from iter into v, reporting
+// whether the input matched the types defined in the schema.
+func (v *ServiceAgent) DecodeJSON(iter *jsoniter.Iterator) bool {
+	switch iter.WhatIsNext() {
+	case jsoniter.NilValue:
+		iter.ReadNil()
+		*v = ServiceAgent{}
+		return true
+	case jsoniter.ObjectValue:
+	default:
+		iter.Skip()
+		return false
+	}
+	*v = ServiceAgent{IsSet: true}
+	ok := true
+	iter.ReadMapCB(func(iter *jsoniter.Iterator, field string) bool {
+		switch field {
+		case "ephemeral_id":
+			ok = v.EphemeralID.DecodeJSON(iter)
+		case "name":
+			ok = v.Name.DecodeJSON(iter)
+		case "version":
+			ok = v.Version.DecodeJSON(iter)
+		default:
+			iter.Skip()
+		}
+		return ok
+	})
+	return ok
+}
+
+// Validate checks v against the constraints defined in the schema
+// which are not already enforced by DecodeJSON.
+func (v *ServiceAgent) Validate() error {
+	if !v.IsSet {
+		return nil
+	}
+	if v.EphemeralID.IsSet {
+		if utf8.RuneCountInString(v.EphemeralID.Val) > 1024 {
+			return errors.New("ephemeral_id: length must be <= 1024")
+		}
+	}
+	if v.Name.IsSet {
+		if utf8.RuneCountInString(v.Name.Val) > 1024 {
+			return errors.New("name: length must be <= 1024")
+		}
+	}
+	if v.Version.IsSet {
+		if utf8.RuneCountInString(v.Version.Val) > 1024 {
+			return errors.New("version: length must be <= 1024")
+		}
+	}
+	return nil
+}
+
+// DecodeJSON decodes the next JSON object from iter into v, reporting
+// whether the input matched the types defined in the schema.
+func (v *ServiceFramework) DecodeJSON(iter *jsoniter.Iterator) bool {
+	switch iter.WhatIsNext() {
+	case jsoniter.NilValue:
+		iter.ReadNil()
+		*v = ServiceFramework{}
+		return true
+	case jsoniter.ObjectValue:
+	default:
+		iter.Skip()
+		return false
+	}
+	*v = ServiceFramework{IsSet: true}
+	ok := true
+	iter.ReadMapCB(func(iter *jsoniter.Iterator, field string) bool {
+		switch field {
+		case "name":
+			ok = v.Name.DecodeJSON(iter)
+		case "version":
+			ok = v.Version.DecodeJSON(iter)
+		default:
+			iter.Skip()
+		}
+		return ok
+	})
+	return ok
+}
+
+// Validate checks v against the constraints defined in the schema
+// which are not already enforced by DecodeJSON.
+func (v *ServiceFramework) Validate() error {
+	if !v.IsSet {
+		return nil
+	}
+	if v.Name.IsSet {
+		if utf8.RuneCountInString(v.Name.Val) > 1024 {
+			return errors.New("name: length must be <= 1024")
+		}
+	}
+	if v.Version.IsSet {
+		if utf8.RuneCountInString(v.Version.Val) > 1024 {
+			return errors.New("version: length must be <= 1024")
+		}
+	}
+	return nil
+}
+
+// DecodeJSON decodes the next JSON object from iter into v, reporting
+// whether the input matched the types defined in the schema.
+func (v *ServiceLanguage) DecodeJSON(iter *jsoniter.Iterator) bool {
+	switch iter.WhatIsNext() {
+	case jsoniter.NilValue:
+		iter.ReadNil()
+		*v = ServiceLanguage{}
+		return true
+	case jsoniter.ObjectValue:
+	default:
+		iter.Skip()
+		return false
+	}
+	*v = ServiceLanguage{IsSet: true}
+	ok := true
+	iter.ReadMapCB(func(iter *jsoniter.Iterator, field string) bool {
+		switch field {
+		case "name":
+			ok = v.Name.DecodeJSON(iter)
+		case "version":
+			ok = v.Version.DecodeJSON(iter)
+		default:
+			iter.Skip()
+		}
+		return ok
+	})
+	return ok
+}
+
+// Validate checks v against the constraints defined in the schema
+// which are not already enforced by DecodeJSON.
+func (v *ServiceLanguage) Validate() error {
+	if !v.IsSet {
+		return nil
+	}
+	if v.Name.IsSet {
+		if utf8.RuneCountInString(v.Name.Val) > 1024 {
+			return errors.New("name: length must be <= 1024")
+		}
+	}
+	if v.Version.IsSet {
+		if utf8.RuneCountInString(v.Version.Val) > 1024 {
+			return errors.New("version: length must be <= 1024")
+		}
+	}
+	return nil
+}
+
+// DecodeJSON decodes the next JSON object from iter into v, reporting
+// whether the input matched the types defined in the schema.
+func (v *ServiceNode) DecodeJSON(iter *jsoniter.Iterator) bool {
+	switch iter.WhatIsNext() {
+	case jsoniter.NilValue:
+		iter.ReadNil()
+		*v = ServiceNode{}
+		return true
+	case jsoniter.ObjectValue:
+	default:
+		iter.Skip()
+		return false
+	}
+	*v = ServiceNode{IsSet: true}
+	ok := true
+	iter.ReadMapCB(func(iter *jsoniter.Iterator, field string) bool {
+		switch field {
+		case "configured_name":
+			ok = v.ConfiguredName.DecodeJSON(iter)
+		default:
+			iter.Skip()
+		}
+		return ok
+	})
+	return ok
+}
+
+// Validate checks v against the constraints defined in the schema
+// which are not already enforced by DecodeJSON.
+func (v *ServiceNode) Validate() error {
+	if !v.IsSet {
+		return nil
+	}
+	if v.ConfiguredName.IsSet {
+		if utf8.RuneCountInString(v.ConfiguredName.Val) > 1024 {
+			return errors.New("configured_name: length must be <= 1024")
+		}
+	}
+	return nil
+}
+
+// DecodeJSON decodes the next JSON object from iter into v, reporting
+// whether the input matched the types defined in the schema.
+func (v *ServiceRuntime) DecodeJSON(iter *jsoniter.Iterator) bool {
+	switch iter.WhatIsNext() {
+	case jsoniter.NilValue:
+		iter.ReadNil()
+		*v = ServiceRuntime{}
+		return true
+	case jsoniter.ObjectValue:
+	default:
+		iter.Skip()
+		return false
+	}
+	*v = ServiceRuntime{IsSet: true}
+	ok := true
+	iter.ReadMapCB(func(iter *jsoniter.Iterator, field string) bool {
+		switch field {
+		case "name":
+			ok = v.Name.DecodeJSON(iter)
+		case "version":
+			ok = v.Version.DecodeJSON(iter)
+		default:
+			iter.Skip()
+		}
+		return ok
+	})
+	return ok
+}
+
+// Validate checks v against the constraints defined in the schema
+// which are not already enforced by DecodeJSON.
+func (v *ServiceRuntime) Validate() error {
+	if !v.IsSet {
+		return nil
+	}
+	if v.Name.IsSet {
+		if utf8.RuneCountInString(v.Name.Val) > 1024 {
+			return errors.New("name: length must be <= 1024")
+		}
+	}
+	if v.Version.IsSet {
+		if utf8.RuneCountInString(v.Version.Val) > 1024 {
+			return errors.New("version: length must be <= 1024")
+		}
+	}
+	return nil
+}
+
+// DecodeJSON decodes the next JSON object from iter into v, reporting
+// whether the input matched the types defined in the schema.
+func (v *Service) DecodeJSON(iter *jsoniter.Iterator) bool {
+	switch iter.WhatIsNext() {
+	case jsoniter.NilValue:
+		iter.ReadNil()
+		*v = Service{}
+		return true
+	case jsoniter.ObjectValue:
+	default:
+		iter.Skip()
+		return false
+	}
+	*v = Service{IsSet: true}
+	ok := true
+	iter.ReadMapCB(func(iter *jsoniter.Iterator, field string) bool {
+		switch field {
+		case "agent":
+			ok = v.Agent.DecodeJSON(iter)
+		case "environment":
+			ok = v.Environment.DecodeJSON(iter)
+		case "framework":
+			ok = v.Framework.DecodeJSON(iter)
+		case "language":
+			ok = v.Language.DecodeJSON(iter)
+		case "name":
+			ok = v.Name.DecodeJSON(iter)
+		case "node":
+			ok = v.Node.DecodeJSON(iter)
+		case "runtime":
+			ok = v.Runtime.DecodeJSON(iter)
+		case "version":
+			ok = v.Version.DecodeJSON(iter)
+		default:
+			iter.Skip()
+		}
+		return ok
+	})
+	return ok
+}
+
+// Validate checks v against the constraints defined in the schema
+// which are not already enforced by DecodeJSON.
+func (v *Service) Validate() error {
+	if !v.IsSet {
+		return nil
+	}
+	if err := v.Agent.Validate(); err != nil {
+		return errors.Wrap(err, "agent")
+	}
+	if v.Environment.IsSet {
+		if utf8.RuneCountInString(v.Environment.Val) > 1024 {
+			return errors.New("environment: length must be <= 1024")
+		}
+	}
+	if err := v.Framework.Validate(); err != nil {
+		return errors.Wrap(err, "framework")
+	}
+	if err := v.Language.Validate(); err != nil {
+		return errors.Wrap(err, "language")
+	}
+	if v.Name.IsSet {
+		if utf8.RuneCountInString(v.Name.Val) > 1024 {
+			return errors.New("name: length must be <= 1024")
+		}
+		if !pattern1.MatchString(v.Name.Val) {
+			return errors.Errorf("name: value does not match pattern %q", pattern1.String())
+		}
+	}
+	if err := v.Node.Validate(); err != nil {
+		return errors.Wrap(err, "node")
+	}
+	if err := v.Runtime.Validate(); err != nil {
+		return errors.Wrap(err, "runtime")
+	}
+	if v.Version.IsSet {
+		if utf8.RuneCountInString(v.Version.Val) > 1024 {
+			return errors.New("version: length must be <= 1024")
+		}
+	}
+	return nil
+}
+
+// DecodeJSON decodes the next JSON object from iter into v, reporting
+// whether the input matched the types defined in the schema.
+func (v *User) DecodeJSON(iter *jsoniter.Iterator) bool {
+	switch iter.WhatIsNext() {
+	case jsoniter.NilValue:
+		iter.ReadNil()
+		*v = User{}
+		return true
+	case jsoniter.ObjectValue:
+	default:
+		iter.Skip()
+		return false
+	}
+	*v = User{IsSet: true}
+	ok := true
+	iter.ReadMapCB(func(iter *jsoniter.Iterator, field string) bool {
+		switch field {
+		case "email":
+			ok = v.Email.DecodeJSON(iter)
+		case "id":
+			ok = v.ID.DecodeJSON(iter)
+		case "ip":
+			ok = v.IP.DecodeJSON(iter)
+		case "username":
+			ok = v.Username.DecodeJSON(iter)
+		default:
+			iter.Skip()
+		}
+		return ok
+	})
+	return ok
+}
+
+// Validate checks v against the constraints defined in the schema
+// which are not already enforced by DecodeJSON.
+func (v *User) Validate() error {
+	if !v.IsSet {
+		return nil
+	}
+	if v.Email.IsSet {
+		if utf8.RuneCountInString(v.Email.Val) > 1024 {
+			return errors.New("email: length must be <= 1024")
+		}
+	}
+	if v.ID.IsSet {
+		switch val := v.ID.Val.(type) {
+		case string:
+			if utf8.RuneCountInString(val) > 1024 {
+				return errors.New("id: length must be <= 1024")
+			}
+		case json.Number:
+			if _, err := val.Int64(); err != nil {
+				return errors.New("id: value must be an integer")
+			}
+		case nil:
+		default:
+			return errors.Errorf("id: unexpected type %T", val)
+		}
+	}
+	if v.Username.IsSet {
+		if utf8.RuneCountInString(v.Username.Val) > 1024 {
+			return errors.New("username: length must be <= 1024")
+		}
+	}
+	return nil
+}
+
+// DecodeJSON decodes the next JSON object from iter into v, reporting
+// whether the input matched the types defined in the schema.
+func (v *Context) DecodeJSON(iter *jsoniter.Iterator) bool {
+	switch iter.WhatIsNext() {
+	case jsoniter.NilValue:
+		iter.ReadNil()
+		*v = Context{}
+		return true
+	case jsoniter.ObjectValue:
+	default:
+		iter.Skip()
+		return false
+	}
+	*v = Context{IsSet: true}
+	ok := true
+	iter.ReadMapCB(func(iter *jsoniter.Iterator, field string) bool {
+		switch field {
+		case "custom":
+			switch iter.WhatIsNext() {
+			case jsoniter.NilValue:
+				iter.ReadNil()
+				v.Custom = nil
+			case jsoniter.ObjectValue:
+				v.Custom = make(map[string]interface{})
+				iter.ReadMapCB(func(iter *jsoniter.Iterator, key string) bool {
+					elem := nullable.ReadInterface(iter)
+					v.Custom[key] = elem
+					return ok
+				})
+			default:
+				iter.Skip()
+				ok = false
+			}
+		case "message":
+			ok = v.Message.DecodeJSON(iter)
+		case "page":
+			ok = v.Page.DecodeJSON(iter)
+		case "request":
+			ok = v.Request.DecodeJSON(iter)
+		case "response":
+			ok = v.Response.DecodeJSON(iter)
+		case "service":
+			ok = v.Service.DecodeJSON(iter)
+		case "tags":
+			switch iter.WhatIsNext() {
+			case jsoniter.NilValue:
+				iter.ReadNil()
+				v.Tags = nil
+			case jsoniter.ObjectValue:
+				v.Tags = make(map[string]interface{})
+				iter.ReadMapCB(func(iter *jsoniter.Iterator, key string) bool {
+					elem := nullable.ReadInterface(iter)
+					v.Tags[key] = elem
+					return ok
+				})
+			default:
+				iter.Skip()
+				ok = false
+			}
+		case "user":
+			ok = v.User.DecodeJSON(iter)
+		default:
+			iter.Skip()
+		}
+		return ok
+	})
+	return ok
+}
+
+// Validate checks v against the constraints defined in the schema
+// which are not already enforced by DecodeJSON.
+func (v *Context) Validate() error {
+	if !v.IsSet {
+		return nil
+	}
+	for k := range v.Custom {
+		if !pattern0.MatchString(k) {
+			return errors.Errorf("custom: property %q does not match pattern %q", k, pattern0.String())
+		}
+	}
+	if err := v.Message.Validate(); err != nil {
+		return errors.Wrap(err, "message")
+	}
+	if err := v.Page.Validate(); err != nil {
+		return errors.Wrap(err, "page")
+	}
+	if err := v.Request.Validate(); err != nil {
+		return errors.Wrap(err, "request")
+	}
+	if err := v.Response.Validate(); err != nil {
+		return errors.Wrap(err, "response")
+	}
+	if err := v.Service.Validate(); err != nil {
+		return errors.Wrap(err, "service")
+	}
+	for k, elem := range v.Tags {
+		if !pattern0.MatchString(k) {
+			return errors.Errorf("tags: property %q does not match pattern %q", k, pattern0.String())
+		}
+		switch val := elem.(type) {
+		case string:
+			if utf8.RuneCountInString(val) > 1024 {
+				return errors.New("tags: length must be <= 1024")
+			}
+		case bool, json.Number, nil:
+		default:
+			return errors.Errorf("tags: unexpected type %T", val)
+		}
+	}
+	if err := v.User.Validate(); err != nil {
+		return errors.Wrap(err, "user")
+	}
+	return nil
+}
+
+// DecodeJSON decodes the next JSON object from iter into v, reporting
+// whether the input matched the types defined in the schema.
+func (v *StacktraceFrame) DecodeJSON(iter *jsoniter.Iterator) bool {
+	switch iter.WhatIsNext() {
+	case jsoniter.NilValue:
+		iter.ReadNil()
+		*v = StacktraceFrame{}
+		return true
+	case jsoniter.ObjectValue:
+	default:
+		iter.Skip()
+		return false
+	}
+	*v = StacktraceFrame{IsSet: true}
+	ok := true
+	iter.ReadMapCB(func(iter *jsoniter.Iterator, field string) bool {
+		switch field {
+		case "abs_path":
+			ok = v.AbsPath.DecodeJSON(iter)
+		case "classname":
+			ok = v.Classname.DecodeJSON(iter)
+		case "colno":
+			ok = v.Colno.DecodeJSON(iter)
+		case "context_line":
+			ok = v.ContextLine.DecodeJSON(iter)
+		case "filename":
+			ok = v.Filename.DecodeJSON(iter)
+		case "function":
+			ok = v.Function.DecodeJSON(iter)
+		case "library_frame":
+			ok = v.LibraryFrame.DecodeJSON(iter)
+		case "lineno":
+			ok = v.Lineno.DecodeJSON(iter)
+		case "module":
+			ok = v.Module.DecodeJSON(iter)
+		case "post_context":
+			switch iter.WhatIsNext() {
+			case jsoniter.NilValue:
+				iter.ReadNil()
+				v.PostContext = nil
+			case jsoniter.ArrayValue:
+				v.PostContext = []nullable.String{}
+				iter.ReadArrayCB(func(iter *jsoniter.Iterator) bool {
+					var item nullable.String
+					if iter.WhatIsNext() == jsoniter.NilValue {
+						iter.Skip()
+						ok = false
+						return false
+					}
+					ok = item.DecodeJSON(iter)
+					v.PostContext = append(v.PostContext, item)
+					return ok
+				})
+			default:
+				iter.Skip()
+				ok = false
+			}
+		case "pre_context":
+			switch iter.WhatIsNext() {
+			case jsoniter.NilValue:
+				iter.ReadNil()
+				v.PreContext = nil
+			case jsoniter.ArrayValue:
+				v.PreContext = []nullable.String{}
+				iter.ReadArrayCB(func(iter *jsoniter.Iterator) bool {
+					var item nullable.String
+					if iter.WhatIsNext() == jsoniter.NilValue {
+						iter.Skip()
+						ok = false
+						return false
+					}
+					ok = item.DecodeJSON(iter)
+					v.PreContext = append(v.PreContext, item)
+					return ok
+				})
+			default:
+				iter.Skip()
+				ok = false
+			}
+		case "vars":
+			switch iter.WhatIsNext() {
+			case jsoniter.NilValue:
+				iter.ReadNil()
+				v.Vars = nil
+			case jsoniter.ObjectValue:
+				v.Vars = make(map[string]interface{})
+				iter.ReadMapCB(func(iter *jsoniter.Iterator, key string) bool {
+					elem := nullable.ReadInterface(iter)
+					v.Vars[key] = elem
+					return ok
+				})
+			default:
+				iter.Skip()
+				ok = false
+			}
+		default:
+			iter.Skip()
+		}
+		return ok
+	})
+	return ok
+}
+
+// Validate checks v against the constraints defined in the schema
+// which are not already enforced by DecodeJSON.
+func (v *StacktraceFrame) Validate() error {
+	if !v.IsSet {
+		return nil
+	}
+	if !v.Filename.IsSet && !v.Classname.IsSet {
+		return errors.New("missing required properties, one of \"filename\" or \"classname\"")
+	}
+	return nil
+}
+
+// DecodeJSON decodes the next JSON object from iter into v, reporting
+// whether the input matched the types defined in the schema.
+func (v *ErrorException) DecodeJSON(iter *jsoniter.Iterator) bool {
+	switch iter.WhatIsNext() {
+	case jsoniter.NilValue:
+		iter.ReadNil()
+		*v = ErrorException{}
+		return true
+	case jsoniter.ObjectValue:
+	default:
+		iter.Skip()
+		return false
+	}
+	*v = ErrorException{IsSet: true}
+	ok := true
+	iter.ReadMapCB(func(iter *jsoniter.Iterator, field string) bool {
+		switch field {
+		case "attributes":
+			switch iter.WhatIsNext() {
+			case jsoniter.NilValue:
+				iter.ReadNil()
+				v.Attributes = nil
+			case jsoniter.ObjectValue:
+				v.Attributes = make(map[string]interface{})
+				iter.ReadMapCB(func(iter *jsoniter.Iterator, key string) bool {
+					elem := nullable.ReadInterface(iter)
+					v.Attributes[key] = elem
+					return ok
+				})
+			default:
+				iter.Skip()
+				ok = false
+			}
+		case "cause":
+			switch iter.WhatIsNext() {
+			case jsoniter.NilValue:
+				iter.ReadNil()
+				v.Cause = nil
+			case jsoniter.ArrayValue:
+				v.Cause = []map[string]interface{}{}
+				iter.ReadArrayCB(func(iter *jsoniter.Iterator) bool {
+					var item map[string]interface{}
+					switch iter.WhatIsNext() {
+					case jsoniter.NilValue:
+						iter.ReadNil()
+						item = nil
+					case jsoniter.ObjectValue:
+						item = make(map[string]interface{})
+						iter.ReadMapCB(func(iter *jsoniter.Iterator, key string) bool {
+							elem := nullable.ReadInterface(iter)
+							item[key] = elem
+							return ok
+						})
+					default:
+						iter.Skip()
+						ok = false
+					}
+					v.Cause = append(v.Cause, item)
+					return ok
+				})
+			default:
+				iter.Skip()
+				ok = false
+			}
+		case "code":
+			ok = v.Code.DecodeJSON(iter)
+		case "handled":
+			ok = v.Handled.DecodeJSON(iter)
+		case "message":
+			ok = v.Message.DecodeJSON(iter)
+		case "module":
+			ok = v.Module.DecodeJSON(iter)
+		case "stacktrace":
+			switch iter.WhatIsNext() {
+			case jsoniter.NilValue:
+				iter.ReadNil()
+				v.Stacktrace = nil
+			case jsoniter.ArrayValue:
+				v.Stacktrace = []StacktraceFrame{}
+				iter.ReadArrayCB(func(iter *jsoniter.Iterator) bool {
+					var item StacktraceFrame
+					if iter.WhatIsNext() == jsoniter.NilValue {
+						iter.Skip()
+						ok = false
+						return false
+					}
+					ok = item.DecodeJSON(iter)
+					v.Stacktrace = append(v.Stacktrace, item)
+					return ok
+				})
+			default:
+				iter.Skip()
+				ok = false
+			}
+		case "type":
+			ok = v.Type.DecodeJSON(iter)
+		default:
+			iter.Skip()
+		}
+		return ok
+	})
+	return ok
+}
+
+// Validate checks v against the constraints defined in the schema
+// which are not already enforced by DecodeJSON.
+func (v *ErrorException) Validate() error {
+	if !v.IsSet {
+		return nil
+	}
+	if !v.Message.IsSet && !v.Type.IsSet {
+		return errors.New("missing required properties, one of \"message\" or \"type\"")
+	}
+	if v.Code.IsSet {
+		switch val := v.Code.Val.(type) {
+		case string:
+			if utf8.RuneCountInString(val) > 1024 {
+				return errors.New("code: length must be <= 1024")
+			}
+		case json.Number:
+			if _, err := val.Int64(); err != nil {
+				return errors.New("code: value must be an integer")
+			}
+		case nil:
+		default:
+			return errors.Errorf("code: unexpected type %T", val)
+		}
+	}
+	if v.Module.IsSet {
+		if utf8.RuneCountInString(v.Module.Val) > 1024 {
+			return errors.New("module: length must be <= 1024")
+		}
+	}
+	for _, elem := range v.Stacktrace {
+		if err := elem.Validate(); err != nil {
+			return errors.Wrap(err, "stacktrace")
+		}
+	}
+	if v.Type.IsSet {
+		if utf8.RuneCountInString(v.Type.Val) > 1024 {
+			return errors.New("type: length must be <= 1024")
+		}
+	}
+	return nil
+}
+
+// DecodeJSON decodes the next JSON object from iter into v, reporting
+// whether the input matched the types defined in the schema.
+func (v *ErrorLog) DecodeJSON(iter *jsoniter.Iterator) bool {
+	switch iter.WhatIsNext() {
+	case jsoniter.NilValue:
+		iter.ReadNil()
+		*v = ErrorLog{}
+		return true
+	case jsoniter.ObjectValue:
+	default:
+		iter.Skip()
+		return false
+	}
+	*v = ErrorLog{IsSet: true}
+	ok := true
+	iter.ReadMapCB(func(iter *jsoniter.Iterator, field string) bool {
+		switch field {
+		case "level":
+			ok = v.Level.DecodeJSON(iter)
+		case "logger_name":
+			ok = v.LoggerName.DecodeJSON(iter)
+		case "message":
+			if iter.WhatIsNext() == jsoniter.NilValue {
+				iter.Skip()
+				ok = false
+				break
+			}
+			ok = v.Message.DecodeJSON(iter)
+		case "param_message":
+			ok = v.ParamMessage.DecodeJSON(iter)
+		case "stacktrace":
+			switch iter.WhatIsNext() {
+			case jsoniter.NilValue:
+				iter.ReadNil()
+				v.Stacktrace = nil
+			case jsoniter.ArrayValue:
+				v.Stacktrace = []StacktraceFrame{}
+				iter.ReadArrayCB(func(iter *jsoniter.Iterator) bool {
+					var item StacktraceFrame
+					if iter.WhatIsNext() == jsoniter.NilValue {
+						iter.Skip()
+						ok = false
+						return false
+					}
+					ok = item.DecodeJSON(iter)
+					v.Stacktrace = append(v.Stacktrace, item)
+					return ok
+				})
+			default:
+				iter.Skip()
+				ok = false
+			}
+		default:
+			iter.Skip()
+		}
+		return ok
+	})
+	return ok
+}
+
+// Validate checks v against the constraints defined in the schema
+// which are not already enforced by DecodeJSON.
+func (v *ErrorLog) Validate() error {
+	if !v.IsSet {
+		return nil
+	}
+	if !v.Message.IsSet {
+		return errors.New("message: missing required property")
+	}
+	if v.Level.IsSet {
+		if utf8.RuneCountInString(v.Level.Val) > 1024 {
+			return errors.New("level: length must be <= 1024")
+		}
+	}
+	if v.LoggerName.IsSet {
+		if utf8.RuneCountInString(v.LoggerName.Val) > 1024 {
+			return errors.New("logger_name: length must be <= 1024")
+		}
+	}
+	if v.ParamMessage.IsSet {
+		if utf8.RuneCountInString(v.ParamMessage.Val) > 1024 {
+			return errors.New("param_message: length must be <= 1024")
+		}
+	}
+	for _, elem := range v.Stacktrace {
+		if err := elem.Validate(); err != nil {
+			return errors.Wrap(err, "stacktrace")
+		}
+	}
+	return nil
+}
+
+// DecodeJSON decodes the next JSON object from iter into v, reporting
+// whether the input matched the types defined in the schema.
+func (v *ErrorTransaction) DecodeJSON(iter *jsoniter.Iterator) bool {
+	switch iter.WhatIsNext() {
+	case jsoniter.NilValue:
+		iter.ReadNil()
+		*v = ErrorTransaction{}
+		return true
+	case jsoniter.ObjectValue:
+	default:
+		iter.Skip()
+		return false
+	}
+	*v = ErrorTransaction{IsSet: true}
+	ok := true
+	iter.ReadMapCB(func(iter *jsoniter.Iterator, field string) bool {
+		switch field {
+		case "sampled":
+			ok = v.Sampled.DecodeJSON(iter)
+		case "type":
+			ok = v.Type.DecodeJSON(iter)
+		default:
+			iter.Skip()
+		}
+		return ok
+	})
+	return ok
+}
+
+// Validate checks v against the constraints defined in the schema
+// which are not already enforced by DecodeJSON.
+func (v *ErrorTransaction) Validate() error {
+	if !v.IsSet {
+		return nil
+	}
+	if v.Type.IsSet {
+		if utf8.RuneCountInString(v.Type.Val) > 1024 {
+			return errors.New("type: length must be <= 1024")
+		}
+	}
+	return nil
+}
+
+// DecodeJSON decodes the next JSON object from iter into v, reporting
+// whether the input matched the types defined in the schema.
+func (v *Error) DecodeJSON(iter *jsoniter.Iterator) bool {
+	switch iter.WhatIsNext() {
+	case jsoniter.NilValue:
+		iter.ReadNil()
+		*v = Error{}
+		return true
+	case jsoniter.ObjectValue:
+	default:
+		iter.Skip()
+		return false
+	}
+	*v = Error{IsSet: true}
+	ok := true
+	iter.ReadMapCB(func(iter *jsoniter.Iterator, field string) bool {
+		switch field {
+		case "context":
+			ok = v.Context.DecodeJSON(iter)
+		case "culprit":
+			ok = v.Culprit.DecodeJSON(iter)
+		case "exception":
+			ok = v.Exception.DecodeJSON(iter)
+		case "id":
+			if iter.WhatIsNext() == jsoniter.NilValue {
+				iter.Skip()
+				ok = false
+				break
+			}
+			ok = v.ID.DecodeJSON(iter)
+		case "log":
+			ok = v.Log.DecodeJSON(iter)
+		case "parent_id":
+			ok = v.ParentID.DecodeJSON(iter)
+		case "timestamp":
+			ok = v.Timestamp.DecodeJSON(iter)
+		case "trace_id":
+			ok = v.TraceID.DecodeJSON(iter)
+		case "transaction":
+			ok = v.Transaction.DecodeJSON(iter)
+		case "transaction_id":
+			ok = v.TransactionID.DecodeJSON(iter)
+		default:
+			iter.Skip()
+		}
+		return ok
+	})
+	return ok
+}
+
+// Validate checks v against the constraints defined in the schema
+// which are not already enforced by DecodeJSON.
+func (v *Error) Validate() error {
+	if !v.IsSet {
+		return nil
+	}
+	if !v.ID.IsSet {
+		return errors.New("id: missing required property")
+	}
+	if !v.Exception.IsSet && !v.Log.IsSet {
+		return errors.New("missing required properties, one of \"exception\" or \"log\"")
+	}
+	if v.TransactionID.IsSet && !(v.TraceID.IsSet && v.ParentID.IsSet) {
+		return errors.New("\"trace_id\", \"parent_id\": required if \"transaction_id\" is set")
+	}
+	if v.TraceID.IsSet && !v.ParentID.IsSet {
+		return errors.New("\"parent_id\": required if \"trace_id\" is set")
+	}
+	if v.ParentID.IsSet && !v.TraceID.IsSet {
+		return errors.New("\"trace_id\": required if \"parent_id\" is set")
+	}
+	if err := v.Context.Validate(); err != nil {
+		return errors.Wrap(err, "context")
+	}
+	if v.Culprit.IsSet {
+		if utf8.RuneCountInString(v.Culprit.Val) > 1024 {
+			return errors.New("culprit: length must be <= 1024")
+		}
+	}
+	if err := v.Exception.Validate(); err != nil {
+		return errors.Wrap(err, "exception")
+	}
+	if v.ID.IsSet {
+		if utf8.RuneCountInString(v.ID.Val) > 1024 {
+			return errors.New("id: length must be <= 1024")
+		}
+	}
+	if err := v.Log.Validate(); err != nil {
+		return errors.Wrap(err, "log")
+	}
+	if v.ParentID.IsSet {
+		if utf8.RuneCountInString(v.ParentID.Val) > 1024 {
+			return errors.New("parent_id: length must be <= 1024")
+		}
+	}
+	if v.TraceID.IsSet {
+		if utf8.RuneCountInString(v.TraceID.Val) > 1024 {
+			return errors.New("trace_id: length must be <= 1024")
+		}
+	}
+	if err := v.Transaction.Validate(); err != nil {
+		return errors.Wrap(err, "transaction")
+	}
+	if v.TransactionID.IsSet {
+		if utf8.RuneCountInString(v.TransactionID.Val) > 1024 {
+			return errors.New("transaction_id: length must be <= 1024")
+		}
+	}
+	return nil
+}
+
+// DecodeJSON decodes the next JSON object from iter into v, reporting
+// whether the input matched the types defined in the schema.
+func (v *SpanContextDB) DecodeJSON(iter *jsoniter.Iterator) bool {
+	switch iter.WhatIsNext() {
+	case jsoniter.NilValue:
+		iter.ReadNil()
+		*v = SpanContextDB{}
+		return true
+	case jsoniter.ObjectValue:
+	default:
+		iter.Skip()
+		return false
+	}
+	*v = SpanContextDB{IsSet: true}
+	ok := true
+	iter.ReadMapCB(func(iter *jsoniter.Iterator, field string) bool {
+		switch field {
+		case "instance":
+			ok = v.Instance.DecodeJSON(iter)
+		case "link":
+			ok = v.Link.DecodeJSON(iter)
+		case "rows_affected":
+			ok = v.RowsAffected.DecodeJSON(iter)
+		case "statement":
+			ok = v.Statement.DecodeJSON(iter)
+		case "type":
+			ok = v.Type.DecodeJSON(iter)
+		case "user":
+			ok = v.User.DecodeJSON(iter)
+		default:
+			iter.Skip()
+		}
+		return ok
+	})
+	return ok
+}
+
+// Validate checks v against the constraints defined in the schema
+// which are not already enforced by DecodeJSON.
+func (v *SpanContextDB) Validate() error {
+	if !v.IsSet {
+		return nil
+	}
+	if v.Link.IsSet {
+		if utf8.RuneCountInString(v.Link.Val) > 1024 {
+			return errors.New("link: length must be <= 1024")
+		}
+	}
+	return nil
+}
+
+// DecodeJSON decodes the next JSON object from iter into v, reporting
+// whether the input matched the types defined in the schema.
+func (v *SpanContextDestinationService) DecodeJSON(iter *jsoniter.Iterator) bool {
+	switch iter.WhatIsNext() {
+	case jsoniter.NilValue:
+		iter.ReadNil()
+		*v = SpanContextDestinationService{}
+		return true
+	case jsoniter.ObjectValue:
+	default:
+		iter.Skip()
+		return false
+	}
+	*v = SpanContextDestinationService{IsSet: true}
+	ok := true
+	iter.ReadMapCB(func(iter *jsoniter.Iterator, field string) bool {
+		switch field {
+		case "name":
+			ok = v.Name.DecodeJSON(iter)
+		case "resource":
+			ok = v.Resource.DecodeJSON(iter)
+		case "type":
+			ok = v.Type.DecodeJSON(iter)
+		default:
+			iter.Skip()
+		}
+		return ok
+	})
+	return ok
+}
+
+// Validate checks v against the constraints defined in the schema
+// which are not already enforced by DecodeJSON.
+func (v *SpanContextDestinationService) Validate() error {
+	if !v.IsSet {
+		return nil
+	}
+	if !v.Type.IsSet {
+		return errors.New("type: missing required property")
+	}
+	if !v.Name.IsSet {
+		return errors.New("name: missing required property")
+	}
+	if !v.Resource.IsSet {
+		return errors.New("resource: missing required property")
+	}
+	if v.Name.IsSet {
+		if utf8.RuneCountInString(v.Name.Val) > 1024 {
+			return errors.New("name: length must be <= 1024")
+		}
+	}
+	if v.Resource.IsSet {
+		if utf8.RuneCountInString(v.Resource.Val) > 1024 {
+			return errors.New("resource: length must be <= 1024")
+		}
+	}
+	if v.Type.IsSet {
+		if utf8.RuneCountInString(v.Type.Val) > 1024 {
+			return errors.New("type: length must be <= 1024")
+		}
+	}
+	return nil
+}
+
+// DecodeJSON decodes the next JSON object from iter into v, reporting
+// whether the input matched the types defined in the schema.
+func (v *SpanContextDestination) DecodeJSON(iter *jsoniter.Iterator) bool {
+	switch iter.WhatIsNext() {
+	case jsoniter.NilValue:
+		iter.ReadNil()
+		*v = SpanContextDestination{}
+		return true
+	case jsoniter.ObjectValue:
+	default:
+		iter.Skip()
+		return false
+	}
+	*v = SpanContextDestination{IsSet: true}
+	ok := true
+	iter.ReadMapCB(func(iter *jsoniter.Iterator, field string) bool {
+		switch field {
+		case "address":
+			ok = v.Address.DecodeJSON(iter)
+		case "port":
+			ok = v.Port.DecodeJSON(iter)
+		case "service":
+			ok = v.Service.DecodeJSON(iter)
+		default:
+			iter.Skip()
+		}
+		return ok
+	})
+	return ok
+}
+
+// Validate checks v against the constraints defined in the schema
+// which are not already enforced by DecodeJSON.
+func (v *SpanContextDestination) Validate() error {
+	if !v.IsSet {
+		return nil
+	}
+	if v.Address.IsSet {
+		if utf8.RuneCountInString(v.Address.Val) > 1024 {
+			return errors.New("address: length must be <= 1024")
+		}
+	}
+	if err := v.Service.Validate(); err != nil {
+		return errors.Wrap(err, "service")
+	}
+	return nil
+}
+
+// DecodeJSON decodes the next JSON object from iter into v, reporting
+// whether the input matched the types defined in the schema.
+func (v *HTTPResponse) DecodeJSON(iter *jsoniter.Iterator) bool {
+	switch iter.WhatIsNext() {
+	case jsoniter.NilValue:
+		iter.ReadNil()
+		*v = HTTPResponse{}
+		return true
+	case jsoniter.ObjectValue:
+	default:
+		iter.Skip()
+		return false
+	}
+	*v = HTTPResponse{IsSet: true}
+	ok := true
+	iter.ReadMapCB(func(iter *jsoniter.Iterator, field string) bool {
+		switch field {
+		case "decoded_body_size":
+			ok = v.DecodedBodySize.DecodeJSON(iter)
+		case "encoded_body_size":
+			ok = v.EncodedBodySize.DecodeJSON(iter)
+		case "headers":
+			switch iter.WhatIsNext() {
+			case jsoniter.NilValue:
+				iter.ReadNil()
+				v.Headers = nil
+			case jsoniter.ObjectValue:
+				v.Headers = make(map[string]interface{})
+				iter.ReadMapCB(func(iter *jsoniter.Iterator, key string) bool {
+					elem := nullable.ReadInterface(iter)
+					v.Headers[key] = elem
+					return ok
+				})
+			default:
+				iter.Skip()
+				ok = false
+			}
+		case "status_code":
+			ok = v.StatusCode.DecodeJSON(iter)
+		case "transfer_size":
+			ok = v.TransferSize.DecodeJSON(iter)
+		default:
+			iter.Skip()
+		}
+		return ok
+	})
+	return ok
+}
+
+// Validate checks v against the constraints defined in the schema
+// which are not already enforced by DecodeJSON.
+func (v *HTTPResponse) Validate() error {
+	if !v.IsSet {
+		return nil
+	}
+	for k, elem := range v.Headers {
+		_ = k
+		switch val := elem.(type) {
+		case string:
+		case []interface{}:
+			for _, elem := range val {
+				switch val := elem.(type) {
+				case string:
+				default:
+					return errors.Errorf("headers: unexpected type %T", val)
+				}
+			}
+		case nil:
+		default:
+			return errors.Errorf("headers: unexpected type %T", val)
+		}
+	}
+	return nil
+}
+
+// DecodeJSON decodes the next JSON object from iter into v, reporting
+// whether the input matched the types defined in the schema.
+func (v *SpanContextHTTP) DecodeJSON(iter *jsoniter.Iterator) bool {
+	switch iter.WhatIsNext() {
+	case jsoniter.NilValue:
+		iter.ReadNil()
+		*v = SpanContextHTTP{}
+		return true
+	case jsoniter.ObjectValue:
+	default:
+		iter.Skip()
+		return false
+	}
+	*v = SpanContextHTTP{IsSet: true}
+	ok := true
+	iter.ReadMapCB(func(iter *jsoniter.Iterator, field string) bool {
+		switch field {
+		case "method":
+			ok = v.Method.DecodeJSON(iter)
+		case "response":
+			ok = v.Response.DecodeJSON(iter)
+		case "status_code":
+			ok = v.StatusCode.DecodeJSON(iter)
+		case "url":
+			ok = v.URL.DecodeJSON(iter)
+		default:
+			iter.Skip()
+		}
+		return ok
+	})
+	return ok
+}
+
+// Validate checks v against the constraints defined in the schema
+// which are not already enforced by DecodeJSON.
+func (v *SpanContextHTTP) Validate() error {
+	if !v.IsSet {
+		return nil
+	}
+	if v.Method.IsSet {
+		if utf8.RuneCountInString(v.Method.Val) > 1024 {
+			return errors.New("method: length must be <= 1024")
+		}
+	}
+	if err := v.Response.Validate(); err != nil {
+		return errors.Wrap(err, "response")
+	}
+	return nil
+}
+
+// DecodeJSON decodes the next JSON object from iter into v, reporting
+// whether the input matched the types defined in the schema.
+func (v *SpanContext) DecodeJSON(iter *jsoniter.Iterator) bool {
+	switch iter.WhatIsNext() {
+	case jsoniter.NilValue:
+		iter.ReadNil()
+		*v = SpanContext{}
+		return true
+	case jsoniter.ObjectValue:
+	default:
+		iter.Skip()
+		return false
+	}
+	*v = SpanContext{IsSet: true}
+	ok := true
+	iter.ReadMapCB(func(iter *jsoniter.Iterator, field string) bool {
+		switch field {
+		case "db":
+			ok = v.DB.DecodeJSON(iter)
+		case "destination":
+			ok = v.Destination.DecodeJSON(iter)
+		case "http":
+			ok = v.HTTP.DecodeJSON(iter)
+		case "message":
+			ok = v.Message.DecodeJSON(iter)
+		case "service":
+			ok = v.Service.DecodeJSON(iter)
+		case "tags":
+			switch iter.WhatIsNext() {
+			case jsoniter.NilValue:
+				iter.ReadNil()
+				v.Tags = nil
+			case jsoniter.ObjectValue:
+				v.Tags = make(map[string]interface{})
+				iter.ReadMapCB(func(iter *jsoniter.Iterator, key string) bool {
+					elem := nullable.ReadInterface(iter)
+					v.Tags[key] = elem
+					return ok
+				})
+			default:
+				iter.Skip()
+				ok = false
+			}
+		default:
+			iter.Skip()
+		}
+		return ok
+	})
+	return ok
+}
+
+// Validate checks v against the constraints defined in the schema
+// which are not already enforced by DecodeJSON.
+func (v *SpanContext) Validate() error {
+	if !v.IsSet {
+		return nil
+	}
+	if err := v.DB.Validate(); err != nil {
+		return errors.Wrap(err, "db")
+	}
+	if err := v.Destination.Validate(); err != nil {
+		return errors.Wrap(err, "destination")
+	}
+	if err := v.HTTP.Validate(); err != nil {
+		return errors.Wrap(err, "http")
+	}
+	if err := v.Message.Validate(); err != nil {
+		return errors.Wrap(err, "message")
+	}
+	if err := v.Service.Validate(); err != nil {
+		return errors.Wrap(err, "service")
+	}
+	for k, elem := range v.Tags {
+		if !pattern0.MatchString(k) {
+			return errors.Errorf("tags: property %q does not match pattern %q", k, pattern0.String())
+		}
+		switch val := elem.(type) {
+		case string:
+			if utf8.RuneCountInString(val) > 1024 {
+				return errors.New("tags: length must be <= 1024")
+			}
+		case bool, json.Number, nil:
+		default:
+			return errors.Errorf("tags: unexpected type %T", val)
+		}
+	}
+	return nil
+}
+
+// DecodeJSON decodes the next JSON object from iter into v, reporting
+// whether the input matched the types defined in the schema.
+func (v *Span) DecodeJSON(iter *jsoniter.Iterator) bool {
+	switch iter.WhatIsNext() {
+	case jsoniter.NilValue:
+		iter.ReadNil()
+		*v = Span{}
+		return true
+	case jsoniter.ObjectValue:
+	default:
+		iter.Skip()
+		return false
+	}
+	*v = Span{IsSet: true}
+	ok := true
+	iter.ReadMapCB(func(iter *jsoniter.Iterator, field string) bool {
+		switch field {
+		case "action":
+			ok = v.Action.DecodeJSON(iter)
+		case "child_ids":
+			switch iter.WhatIsNext() {
+			case jsoniter.NilValue:
+				iter.ReadNil()
+				v.ChildIds = nil
+			case jsoniter.ArrayValue:
+				v.ChildIds = []nullable.String{}
+				iter.ReadArrayCB(func(iter *jsoniter.Iterator) bool {
+					var item nullable.String
+					if iter.WhatIsNext() == jsoniter.NilValue {
+						iter.Skip()
+						ok = false
+						return false
+					}
+					ok = item.DecodeJSON(iter)
+					v.ChildIds = append(v.ChildIds, item)
+					return ok
+				})
+			default:
+				iter.Skip()
+				ok = false
+			}
+		case "context":
+			ok = v.Context.DecodeJSON(iter)
+		case "duration":
+			if iter.WhatIsNext() == jsoniter.NilValue {
+				iter.Skip()
+				ok = false
+				break
+			}
+			ok = v.Duration.DecodeJSON(iter)
+		case "id":
+			if iter.WhatIsNext() == jsoniter.NilValue {
+				iter.Skip()
+				ok = false
+				break
+			}
+			ok = v.ID.DecodeJSON(iter)
+		case "name":
+			if iter.WhatIsNext() == jsoniter.NilValue {
+				iter.Skip()
+				ok = false
+				break
+			}
+			ok = v.Name.DecodeJSON(iter)
+		case "parent_id":
+			if iter.WhatIsNext() == jsoniter.NilValue {
+				iter.Skip()
+				ok = false
+				break
+			}
+			ok = v.ParentID.DecodeJSON(iter)
+		case "stacktrace":
+			switch iter.WhatIsNext() {
+			case jsoniter.NilValue:
+				iter.ReadNil()
+				v.Stacktrace = nil
+			case jsoniter.ArrayValue:
+				v.Stacktrace = []StacktraceFrame{}
+				iter.ReadArrayCB(func(iter *jsoniter.Iterator) bool {
+					var item StacktraceFrame
+					if iter.WhatIsNext() == jsoniter.NilValue {
+						iter.Skip()
+						ok = false
+						return false
+					}
+					ok = item.DecodeJSON(iter)
+					v.Stacktrace = append(v.Stacktrace, item)
+					return ok
+				})
+			default:
+				iter.Skip()
+				ok = false
+			}
+		case "start":
+			ok = v.Start.DecodeJSON(iter)
+		case "subtype":
+			ok = v.Subtype.DecodeJSON(iter)
+		case "sync":
+			ok = v.Sync.DecodeJSON(iter)
+		case "timestamp":
+			ok = v.Timestamp.DecodeJSON(iter)
+		case "trace_id":
+			if iter.WhatIsNext() == jsoniter.NilValue {
+				iter.Skip()
+				ok = false
+				break
+			}
+			ok = v.TraceID.DecodeJSON(iter)
+		case "transaction_id":
+			ok = v.TransactionID.DecodeJSON(iter)
+		case "type":
+			if iter.WhatIsNext() == jsoniter.NilValue {
+				iter.Skip()
+				ok = false
+				break
+			}
+			ok = v.Type.DecodeJSON(iter)
+		default:
+			iter.Skip()
+		}
+		return ok
+	})
+	return ok
+}
+
+// Validate checks v against the constraints defined in the schema
+// which are not already enforced by DecodeJSON.
+func (v *Span) Validate() error {
+	if !v.IsSet {
+		return nil
+	}
+	if !v.Duration.IsSet {
+		return errors.New("duration: missing required property")
+	}
+	if !v.Name.IsSet {
+		return errors.New("name: missing required property")
+	}
+	if !v.Type.IsSet {
+		return errors.New("type: missing required property")
+	}
+	if !v.ID.IsSet {
+		return errors.New("id: missing required property")
+	}
+	if !v.TraceID.IsSet {
+		return errors.New("trace_id: missing required property")
+	}
+	if !v.ParentID.IsSet {
+		return errors.New("parent_id: missing required property")
+	}
+	if !v.Timestamp.IsSet && !v.Start.IsSet {
+		return errors.New("missing required properties, one of \"timestamp\" or \"start\"")
+	}
+	if v.Action.IsSet {
+		if utf8.RuneCountInString(v.Action.Val) > 1024 {
+			return errors.New("action: length must be <= 1024")
+		}
+	}
+	for _, elem := range v.ChildIds {
+		if elem.IsSet {
+			if utf8.RuneCountInString(elem.Val) > 1024 {
+				return errors.New("child_ids: length must be <= 1024")
+			}
+		}
+	}
+	if err := v.Context.Validate(); err != nil {
+		return errors.Wrap(err, "context")
+	}
+	if v.Duration.IsSet && v.Duration.Val < 0 {
+		return errors.New("duration: value must be >= 0")
+	}
+	if v.ID.IsSet {
+		if utf8.RuneCountInString(v.ID.Val) > 1024 {
+			return errors.New("id: length must be <= 1024")
+		}
+	}
+	if v.Name.IsSet {
+		if utf8.RuneCountInString(v.Name.Val) > 1024 {
+			return errors.New("name: length must be <= 1024")
+		}
+	}
+	if v.ParentID.IsSet {
+		if utf8.RuneCountInString(v.ParentID.Val) > 1024 {
+			return errors.New("parent_id: length must be <= 1024")
+		}
+	}
+	for _, elem := range v.Stacktrace {
+		if err := elem.Validate(); err != nil {
+			return errors.Wrap(err, "stacktrace")
+		}
+	}
+	if v.Subtype.IsSet {
+		if utf8.RuneCountInString(v.Subtype.Val) > 1024 {
+			return errors.New("subtype: length must be <= 1024")
+		}
+	}
+	if v.TraceID.IsSet {
+		if utf8.RuneCountInString(v.TraceID.Val) > 1024 {
+			return errors.New("trace_id: length must be <= 1024")
+		}
+	}
+	if v.TransactionID.IsSet {
+		if utf8.RuneCountInString(v.TransactionID.Val) > 1024 {
+			return errors.New("transaction_id: length must be <= 1024")
+		}
+	}
+	if v.Type.IsSet {
+		if utf8.RuneCountInString(v.Type.Val) > 1024 {
+			return errors.New("type: length must be <= 1024")
+		}
+	}
+	return nil
+}
+
+// DecodeJSON decodes the next JSON object from iter into v, reporting
+// whether the input matched the types defined in the schema.
+func (v *TransactionDroppedSpansStatsItemDurationSum) DecodeJSON(iter *jsoniter.Iterator) bool {
+	switch iter.WhatIsNext() {
+	case jsoniter.NilValue:
+		iter.ReadNil()
+		*v = TransactionDroppedSpansStatsItemDurationSum{}
+		return true
+	case jsoniter.ObjectValue:
+	default:
+		iter.Skip()
+		return false
+	}
+	*v = TransactionDroppedSpansStatsItemDurationSum{IsSet: true}
+	ok := true
+	iter.ReadMapCB(func(iter *jsoniter.Iterator, field string) bool {
+		switch field {
+		case "us":
+			if iter.WhatIsNext() == jsoniter.NilValue {
+				iter.Skip()
+				ok = false
+				break
+			}
+			ok = v.Us.DecodeJSON(iter)
+		default:
+			iter.Skip()
+		}
+		return ok
+	})
+	return ok
+}
+
+// Validate checks v against the constraints defined in the schema
+// which are not already enforced by DecodeJSON.
+func (v *TransactionDroppedSpansStatsItemDurationSum) Validate() error {
+	if !v.IsSet {
+		return nil
+	}
+	if !v.Us.IsSet {
+		return errors.New("us: missing required property")
+	}
+	if v.Us.IsSet && v.Us.Val < 0 {
+		return errors.New("us: value must be >= 0")
+	}
+	return nil
+}
+
+// DecodeJSON decodes the next JSON object from iter into v, reporting
+// whether the input matched the types defined in the schema.
+func (v *TransactionDroppedSpansStatsItemDuration) DecodeJSON(iter *jsoniter.Iterator) bool {
+	switch iter.WhatIsNext() {
+	case jsoniter.NilValue:
+		iter.ReadNil()
+		*v = TransactionDroppedSpansStatsItemDuration{}
+		return true
+	case jsoniter.ObjectValue:
+	default:
+		iter.Skip()
+		return false
+	}
+	*v = TransactionDroppedSpansStatsItemDuration{IsSet: true}
+	ok := true
+	iter.ReadMapCB(func(iter *jsoniter.Iterator, field string) bool {
+		switch field {
+		case "count":
+			if iter.WhatIsNext() == jsoniter.NilValue {
+				iter.Skip()
+				ok = false
+				break
+			}
+			ok = v.Count.DecodeJSON(iter)
+		case "sum":
+			if iter.WhatIsNext() == jsoniter.NilValue {
+				iter.Skip()
+				ok = false
+				break
+			}
+			ok = v.Sum.DecodeJSON(iter)
+		default:
+			iter.Skip()
+		}
+		return ok
+	})
+	return ok
+}
+
+// Validate checks v against the constraints defined in the schema
+// which are not already enforced by DecodeJSON.
+func (v *TransactionDroppedSpansStatsItemDuration) Validate() error {
+	if !v.IsSet {
+		return nil
+	}
+	if !v.Count.IsSet {
+		return errors.New("count: missing required property")
+	}
+	if !v.Sum.IsSet {
+		return errors.New("sum: missing required property")
+	}
+	if v.Count.IsSet && v.Count.Val < 0 {
+		return errors.New("count: value must be >= 0")
+	}
+	if err := v.Sum.Validate(); err != nil {
+		return errors.Wrap(err, "sum")
+	}
+	return nil
+}
+
+// DecodeJSON decodes the next JSON object from iter into v, reporting
+// whether the input matched the types defined in the schema.
+func (v *TransactionDroppedSpansStatsItem) DecodeJSON(iter *jsoniter.Iterator) bool {
+	switch iter.WhatIsNext() {
+	case jsoniter.NilValue:
+		iter.ReadNil()
+		*v = TransactionDroppedSpansStatsItem{}
+		return true
+	case jsoniter.ObjectValue:
+	default:
+		iter.Skip()
+		return false
+	}
+	*v = TransactionDroppedSpansStatsItem{IsSet: true}
+	ok := true
+	iter.ReadMapCB(func(iter *jsoniter.Iterator, field string) bool {
+		switch field {
+		case "destination_service_resource":
+			if iter.WhatIsNext() == jsoniter.NilValue {
+				iter.Skip()
+				ok = false
+				break
+			}
+			ok = v.DestinationServiceResource.DecodeJSON(iter)
+		case "duration":
+			if iter.WhatIsNext() == jsoniter.NilValue {
+				iter.Skip()
+				ok = false
+				break
+			}
+			ok = v.Duration.DecodeJSON(iter)
+		case "outcome":
+			ok = v.Outcome.DecodeJSON(iter)
+		default:
+			iter.Skip()
+		}
+		return ok
+	})
+	return ok
+}
+
+// Validate checks v against the constraints defined in the schema
+// which are not already enforced by DecodeJSON.
+func (v *TransactionDroppedSpansStatsItem) Validate() error {
+	if !v.IsSet {
+		return nil
+	}
+	if !v.DestinationServiceResource.IsSet {
+		return errors.New("destination_service_resource: missing required property")
+	}
+	if !v.Duration.IsSet {
+		return errors.New("duration: missing required property")
+	}
+	if v.DestinationServiceResource.IsSet {
+		if utf8.RuneCountInString(v.DestinationServiceResource.Val) > 1024 {
+			return errors.New("destination_service_resource: length must be <= 1024")
+		}
+	}
+	if err := v.Duration.Validate(); err != nil {
+		return errors.Wrap(err, "duration")
+	}
+	if v.Outcome.IsSet {
+		if utf8.RuneCountInString(v.Outcome.Val) > 1024 {
+			return errors.New("outcome: length must be <= 1024")
+		}
+	}
+	return nil
+}
+
+// DecodeJSON decodes the next JSON object from iter into v, reporting
+// whether the input matched the types defined in the schema.
+func (v *TransactionSpanCount) DecodeJSON(iter *jsoniter.Iterator) bool {
+	switch iter.WhatIsNext() {
+	case jsoniter.NilValue:
+		iter.ReadNil()
+		*v = TransactionSpanCount{}
+		return true
+	case jsoniter.ObjectValue:
+	default:
+		iter.Skip()
+		return false
+	}
+	*v = TransactionSpanCount{IsSet: true}
+	ok := true
+	iter.ReadMapCB(func(iter *jsoniter.Iterator, field string) bool {
+		switch field {
+		case "dropped":
+			ok = v.Dropped.DecodeJSON(iter)
+		case "started":
+			if iter.WhatIsNext() == jsoniter.NilValue {
+				iter.Skip()
+				ok = false
+				break
+			}
+			ok = v.Started.DecodeJSON(iter)
+		default:
+			iter.Skip()
+		}
+		return ok
+	})
+	return ok
+}
+
+// Validate checks v against the constraints defined in the schema
+// which are not already enforced by DecodeJSON.
+func (v *TransactionSpanCount) Validate() error {
+	if !v.IsSet {
+		return nil
+	}
+	if !v.Started.IsSet {
+		return errors.New("started: missing required property")
+	}
+	return nil
+}
+
+// DecodeJSON decodes the next JSON object from iter into v, reporting
+// whether the input matched the types defined in the schema.
+func (v *Transaction) DecodeJSON(iter *jsoniter.Iterator) bool {
+	switch iter.WhatIsNext() {
+	case jsoniter.NilValue:
+		iter.ReadNil()
+		*v = Transaction{}
+		return true
+	case jsoniter.ObjectValue:
+	default:
+		iter.Skip()
+		return false
+	}
+	*v = Transaction{IsSet: true}
+	ok := true
+	iter.ReadMapCB(func(iter *jsoniter.Iterator, field string) bool {
+		switch field {
+		case "context":
+			ok = v.Context.DecodeJSON(iter)
+		case "dropped_spans_stats":
+			switch iter.WhatIsNext() {
+			case jsoniter.NilValue:
+				iter.ReadNil()
+				v.DroppedSpansStats = nil
+			case jsoniter.ArrayValue:
+				v.DroppedSpansStats = []TransactionDroppedSpansStatsItem{}
+				iter.ReadArrayCB(func(iter *jsoniter.Iterator) bool {
+					var item TransactionDroppedSpansStatsItem
+					if iter.WhatIsNext() == jsoniter.NilValue {
+						iter.Skip()
+						ok = false
+						return false
+					}
+					ok = item.DecodeJSON(iter)
+					v.DroppedSpansStats = append(v.DroppedSpansStats, item)
+					return ok
+				})
+			default:
+				iter.Skip()
+				ok = false
+			}
+		case "duration":
+			if iter.WhatIsNext() == jsoniter.NilValue {
+				iter.Skip()
+				ok = false
+				break
+			}
+			ok = v.Duration.DecodeJSON(iter)
+		case "id":
+			if iter.WhatIsNext() == jsoniter.NilValue {
+				iter.Skip()
+				ok = false
+				break
+			}
+			ok = v.ID.DecodeJSON(iter)
+		case "marks":
+			switch iter.WhatIsNext() {
+			case jsoniter.NilValue:
+				iter.ReadNil()
+				v.Marks = nil
+			case jsoniter.ObjectValue:
+				v.Marks = make(map[string]map[string]interface{})
+				iter.ReadMapCB(func(iter *jsoniter.Iterator, key string) bool {
+					var elem map[string]interface{}
+					switch iter.WhatIsNext() {
+					case jsoniter.NilValue:
+						iter.ReadNil()
+						elem = nil
+					case jsoniter.ObjectValue:
+						elem = make(map[string]interface{})
+						iter.ReadMapCB(func(iter *jsoniter.Iterator, key string) bool {
+							value := nullable.ReadInterface(iter)
+							elem[key] = value
+							return ok
+						})
+					default:
+						iter.Skip()
+						ok = false
+					}
+					v.Marks[key] = elem
+					return ok
+				})
+			default:
+				iter.Skip()
+				ok = false
+			}
+		case "name":
+			ok = v.Name.DecodeJSON(iter)
+		case "parent_id":
+			ok = v.ParentID.DecodeJSON(iter)
+		case "result":
+			ok = v.Result.DecodeJSON(iter)
+		case "sampled":
+			ok = v.Sampled.DecodeJSON(iter)
+		case "span_count":
+			if iter.WhatIsNext() == jsoniter.NilValue {
+				iter.Skip()
+				ok = false
+				break
+			}
+			ok = v.SpanCount.DecodeJSON(iter)
+		case "timestamp":
+			ok = v.Timestamp.DecodeJSON(iter)
+		case "trace_id":
+			if iter.WhatIsNext() == jsoniter.NilValue {
+				iter.Skip()
+				ok = false
+				break
+			}
+			ok = v.TraceID.DecodeJSON(iter)
+		case "type":
+			if iter.WhatIsNext() == jsoniter.NilValue {
+				iter.Skip()
+				ok = false
+				break
+			}
+			ok = v.Type.DecodeJSON(iter)
+		default:
+			iter.Skip()
+		}
+		return ok
+	})
+	return ok
+}
+
+// Validate checks v against the constraints defined in the schema
+// which are not already enforced by DecodeJSON.
+func (v *Transaction) Validate() error {
+	if !v.IsSet {
+		return nil
+	}
+	if !v.ID.IsSet {
+		return errors.New("id: missing required property")
+	}
+	if !v.TraceID.IsSet {
+		return errors.New("trace_id: missing required property")
+	}
+	if !v.SpanCount.IsSet {
+		return errors.New("span_count: missing required property")
+	}
+	if !v.Duration.IsSet {
+		return errors.New("duration: missing required property")
+	}
+	if !v.Type.IsSet {
+		return errors.New("type: missing required property")
+	}
+	if err := v.Context.Validate(); err != nil {
+		return errors.Wrap(err, "context")
+	}
+	for _, elem := range v.DroppedSpansStats {
+		if err := elem.Validate(); err != nil {
+			return errors.Wrap(err, "dropped_spans_stats")
+		}
+	}
+	if v.Duration.IsSet && v.Duration.Val < 0 {
+		return errors.New("duration: value must be >= 0")
+	}
+	if v.ID.IsSet {
+		if utf8.RuneCountInString(v.ID.Val) > 1024 {
+			return errors.New("id: length must be <= 1024")
+		}
+	}
+	for k, elem := range v.Marks {
+		if !pattern0.MatchString(k) {
+			return errors.Errorf("marks: property %q does not match pattern %q", k, pattern0.String())
+		}
+		for k, elem := range elem {
+			if !pattern0.MatchString(k) {
+				return errors.Errorf("marks: property %q does not match pattern %q", k, pattern0.String())
+			}
+			switch val := elem.(type) {
+			case json.Number, nil:
+			default:
+				return errors.Errorf("marks: unexpected type %T", val)
+			}
+		}
+	}
+	if v.Name.IsSet {
+		if utf8.RuneCountInString(v.Name.Val) > 1024 {
+			return errors.New("name: length must be <= 1024")
+		}
+	}
+	if v.ParentID.IsSet {
+		if utf8.RuneCountInString(v.ParentID.Val) > 1024 {
+			return errors.New("parent_id: length must be <= 1024")
+		}
+	}
+	if v.Result.IsSet {
+		if utf8.RuneCountInString(v.Result.Val) > 1024 {
+			return errors.New("result: length must be <= 1024")
+		}
+	}
+	if err := v.SpanCount.Validate(); err != nil {
+		return errors.Wrap(err, "span_count")
+	}
+	if v.TraceID.IsSet {
+		if utf8.RuneCountInString(v.TraceID.Val) > 1024 {
+			return errors.New("trace_id: length must be <= 1024")
+		}
+	}
+	if v.Type.IsSet {
+		if utf8.RuneCountInString(v.Type.Val) > 1024 {
+			return errors.New("type: length must be <= 1024")
+		}
+	}
+	return nil
+}
@@ -36,6 +36,10 @@ type Input struct {
 	// Metadata holds metadata that may be added to the event.
 	Metadata model.Metadata
 
+	// SchemaVersion holds the version of the JSON schema to validate
+	// the input against. If empty, the latest version is used.
+	SchemaVersion string
+
 	// Config holds configuration for decoding.
 	//
 	// TODO(axw) define a Decoder type which encapsulates
@@ -49,4 +53,6 @@ type Config struct {
 	Experimental bool
 	// RUM v3 support
 	HasShortFieldNames bool
+	// ContextLimits holds limits applied to context.custom and context.tags.
+	ContextLimits ContextLimits
 }
@@ -28,8 +28,8 @@ import (
 )
 
 var (
-	metadataSchema      = validation.CreateSchema(schema.ModelSchema, "metadata")
-	rumV3MetadataSchema = validation.CreateSchema(schema.RUMV3Schema, "metadata")
+	metadataSchema      = schema.CompiledModelSchema()
+	rumV3MetadataSchema = schema.CompiledRUMV3Schema()
 )
 
 // DecodeRUMV3Metadata decodes v3 RUM metadata.
@@ -35,13 +35,25 @@ const (
 )
 
 var (
-	metricsetSchema = validation.CreateSchema(schema.ModelSchema, "metricset")
-	rumV3Schema     = validation.CreateSchema(schema.RUMV3Schema, "metricset")
+	metricsetSchema = newVersionedSchema(schema.CompiledModelSchema())
+	rumV3Schema     = schema.CompiledRUMV3Schema()
 )
 
+// SchemaVersion78 identifies the intake JSON schemas released with 7.8,
+// which did not validate the span and transaction metricsets are associated
+// with. Agents may select it explicitly while they are being fixed to send
+// valid span and transaction fields.
+const SchemaVersion78 = "7.8"
+
+func init() {
+	registerSchemaVersion(SchemaVersion78, map[*versionedSchema]*jsonschema.Schema{
+		&metricsetSchema: schema.CompiledModelSchemaV7_8(),
+	})
+}
+
 // DecodeMetricset decodes a v2 metricset.
 func DecodeMetricset(input Input, batch *model.Batch) error {
-	metricset, err := decodeMetricset(input, metricsetSchema)
+	metricset, err := decodeMetricset(input, metricsetSchema.get(input.SchemaVersion))
 	if err != nil {
 		return err
 	}
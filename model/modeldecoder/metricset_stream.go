@@ -0,0 +1,84 @@
+// Licensed to Elasticsearch B.V. under one or more contributor
+// license agreements. See the NOTICE file distributed with
+// this work for additional information regarding copyright
+// ownership. Elasticsearch B.V. licenses this file to you under
+// the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package modeldecoder
+
+import (
+	"time"
+
+	jsoniter "github.com/json-iterator/go"
+	"github.com/pkg/errors"
+
+	"github.com/elastic/beats/v7/libbeat/common"
+
+	"github.com/elastic/apm-server/model"
+	"github.com/elastic/apm-server/model/metricset/generated/decoder"
+	"github.com/elastic/apm-server/utility"
+)
+
+var (
+	errInvalidMetricset = errors.New("failed to decode metricset: unexpected value type")
+	errNullMetricset    = errors.New("failed to validate metricset: metricset must be an object")
+)
+
+// DecodeMetricsetJSON decodes and validates a v2 metricset directly from
+// iter, using the decoder generated from the metricset JSON schema. Unlike
+// DecodeMetricset, the event is never decoded into an intermediate map;
+// input.Raw is ignored.
+func DecodeMetricsetJSON(iter *jsoniter.Iterator, input Input, batch *model.Batch) error {
+	var in decoder.Metricset
+	ok := in.DecodeJSON(iter)
+	if iter.Error != nil {
+		return errors.Wrap(iter.Error, "failed to decode metricset")
+	}
+	if !ok {
+		return errInvalidMetricset
+	}
+	if !in.IsSet {
+		return errNullMetricset
+	}
+	if err := in.Validate(); err != nil {
+		return errors.Wrap(err, "failed to validate metricset")
+	}
+	batch.Metricsets = append(batch.Metricsets, mapToMetricsetModel(&in, input))
+	return nil
+}
+
+func mapToMetricsetModel(in *decoder.Metricset, input Input) *model.Metricset {
+	out := model.Metricset{
+		Metadata:  input.Metadata,
+		Timestamp: input.RequestTime,
+		Transaction: model.MetricsetTransaction{
+			Type: in.Transaction.Type.Val,
+			Name: in.Transaction.Name.Val,
+		},
+		Span: model.MetricsetSpan{
+			Type:    in.Span.Type.Val,
+			Subtype: in.Span.Subtype.Val,
+		},
+	}
+	if in.Timestamp.IsSet {
+		out.Timestamp = time.Unix(0, int64(in.Timestamp.Val)*int64(time.Microsecond)).UTC()
+	}
+	for name, sample := range in.Samples {
+		out.Samples = append(out.Samples, model.Sample{Name: name, Value: sample.Value.Val})
+	}
+	if tags := utility.Prune(common.MapStr(in.Tags)); len(tags) > 0 {
+		out.Labels = tags
+	}
+	return &out
+}
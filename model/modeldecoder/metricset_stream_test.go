@@ -0,0 +1,135 @@
+// Licensed to Elasticsearch B.V. under one or more contributor
+// license agreements. See the NOTICE file distributed with
+// this work for additional information regarding copyright
+// ownership. Elasticsearch B.V. licenses this file to you under
+// the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package modeldecoder
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"io/ioutil"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	jsoniter "github.com/json-iterator/go"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/elastic/apm-server/model"
+)
+
+func TestDecodeMetricsetJSONMatchesDecodeMetricset(t *testing.T) {
+	data, err := ioutil.ReadFile(filepath.Join("..", "..", "testdata", "intake-v2", "metricsets.ndjson"))
+	require.NoError(t, err)
+
+	requestTime := time.Now()
+	metadata := model.Metadata{Service: model.Service{Name: "myservice"}}
+
+	var n int
+	scanner := bufio.NewScanner(bytes.NewReader(data))
+	for scanner.Scan() {
+		var event map[string]json.RawMessage
+		require.NoError(t, json.Unmarshal(scanner.Bytes(), &event))
+		raw, ok := event["metricset"]
+		if !ok {
+			continue
+		}
+		n++
+
+		var m map[string]interface{}
+		dec := json.NewDecoder(bytes.NewReader(raw))
+		dec.UseNumber()
+		require.NoError(t, dec.Decode(&m))
+
+		var expected, actual model.Batch
+		input := Input{Raw: m, RequestTime: requestTime, Metadata: metadata}
+		require.NoError(t, DecodeMetricset(input, &expected))
+
+		iter := jsoniter.ConfigCompatibleWithStandardLibrary.BorrowIterator(raw)
+		require.NoError(t, DecodeMetricsetJSON(iter, input, &actual))
+		jsoniter.ConfigCompatibleWithStandardLibrary.ReturnIterator(iter)
+
+		require.Len(t, actual.Metricsets, 1)
+		assertMetricsetsMatch(t, expected.Metricsets[0], actual.Metricsets[0])
+	}
+	require.NoError(t, scanner.Err())
+	assert.NotZero(t, n)
+}
+
+func TestDecodeMetricsetJSONValid(t *testing.T) {
+	for name, input := range map[string]string{
+		"null sample":      `{"samples": {"a": null}}`,
+		"null span":        `{"samples": {}, "span": null}`,
+		"tag types":        `{"samples": {}, "tags": {"a": "b", "c": 1.5, "d": true, "e": null}}`,
+		"unknown property": `{"samples": {}, "foo": {"bar": [1, 2]}}`,
+	} {
+		t.Run(name, func(t *testing.T) {
+			iter := jsoniter.ConfigCompatibleWithStandardLibrary.BorrowIterator([]byte(input))
+			defer jsoniter.ConfigCompatibleWithStandardLibrary.ReturnIterator(iter)
+			var batch model.Batch
+			assert.NoError(t, DecodeMetricsetJSON(iter, Input{}, &batch))
+			assert.Len(t, batch.Metricsets, 1)
+
+			// The generated decoder must agree with the JSON schema.
+			assert.NoError(t, DecodeMetricset(Input{Raw: decodeJSONString(t, input)}, &batch))
+		})
+	}
+}
+
+func TestDecodeMetricsetJSONInvalid(t *testing.T) {
+	for name, input := range map[string]string{
+		"samples not an object": `{"samples": "foo"}`,
+		"sample value string":   `{"samples": {"a": {"value": "foo"}}}`,
+		"timestamp float":       `{"samples": {}, "timestamp": 1.5}`,
+		"timestamp integral":    `{"samples": {}, "timestamp": 1.0}`,
+		"span not an object":    `{"samples": {}, "span": []}`,
+		"truncated":             `{"samples": {`,
+		"null":                  `null`,
+		"null samples":          `{"samples": null}`,
+		"missing samples":       `{}`,
+		"missing sample value":  `{"samples": {"a": {}}}`,
+		"null sample value":     `{"samples": {"a": {"value": null}}}`,
+		"invalid sample name":   `{"samples": {"a*": {"value": 1}}}`,
+		"invalid tag name":      `{"samples": {}, "tags": {"a.b": "c"}}`,
+		"invalid tag type":      `{"samples": {}, "tags": {"a": {}}}`,
+		"tag too long":          `{"samples": {}, "tags": {"a": "` + strings.Repeat("x", 1025) + `"}}`,
+		"span type too long":    `{"samples": {}, "span": {"type": "` + strings.Repeat("x", 1025) + `"}}`,
+	} {
+		t.Run(name, func(t *testing.T) {
+			iter := jsoniter.ConfigCompatibleWithStandardLibrary.BorrowIterator([]byte(input))
+			defer jsoniter.ConfigCompatibleWithStandardLibrary.ReturnIterator(iter)
+			var batch model.Batch
+			assert.Error(t, DecodeMetricsetJSON(iter, Input{}, &batch))
+			assert.Empty(t, batch.Metricsets)
+
+			// The generated decoder must agree with the JSON schema.
+			if json.Valid([]byte(input)) {
+				assert.Error(t, DecodeMetricset(Input{Raw: decodeJSONString(t, input)}, &batch))
+			}
+		})
+	}
+}
+
+func decodeJSONString(t *testing.T, s string) interface{} {
+	var v interface{}
+	dec := json.NewDecoder(strings.NewReader(s))
+	dec.UseNumber()
+	require.NoError(t, dec.Decode(&v))
+	return v
+}
@@ -0,0 +1,157 @@
+// Licensed to Elasticsearch B.V. under one or more contributor
+// license agreements. See the NOTICE file distributed with
+// this work for additional information regarding copyright
+// ownership. Elasticsearch B.V. licenses this file to you under
+// the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+// Package nullable provides types for holding optional JSON values,
+// decoded by the generated streaming decoders without allocating.
+package nullable
+
+import (
+	jsoniter "github.com/json-iterator/go"
+)
+
+// String holds an optional string value.
+type String struct {
+	Val   string
+	IsSet bool
+}
+
+// DecodeJSON decodes the next value from iter into v, reporting
+// whether the value was a JSON string or null.
+func (v *String) DecodeJSON(iter *jsoniter.Iterator) bool {
+	switch iter.WhatIsNext() {
+	case jsoniter.NilValue:
+		iter.ReadNil()
+		*v = String{}
+	case jsoniter.StringValue:
+		*v = String{Val: iter.ReadString(), IsSet: true}
+	default:
+		iter.Skip()
+		return false
+	}
+	return true
+}
+
+// Int holds an optional integer value.
+type Int struct {
+	Val   int
+	IsSet bool
+}
+
+// DecodeJSON decodes the next value from iter into v, reporting
+// whether the value was a JSON integer or null.
+func (v *Int) DecodeJSON(iter *jsoniter.Iterator) bool {
+	switch iter.WhatIsNext() {
+	case jsoniter.NilValue:
+		iter.ReadNil()
+		*v = Int{}
+	case jsoniter.NumberValue:
+		// Consistent with the JSON schema validator, numbers with
+		// a fractional part or exponent are never integers, even 1.0.
+		i, err := iter.ReadNumber().Int64()
+		if err != nil {
+			return false
+		}
+		*v = Int{Val: int(i), IsSet: true}
+	default:
+		iter.Skip()
+		return false
+	}
+	return true
+}
+
+// Float64 holds an optional floating point value.
+type Float64 struct {
+	Val   float64
+	IsSet bool
+}
+
+// DecodeJSON decodes the next value from iter into v, reporting
+// whether the value was a JSON number or null.
+func (v *Float64) DecodeJSON(iter *jsoniter.Iterator) bool {
+	switch iter.WhatIsNext() {
+	case jsoniter.NilValue:
+		iter.ReadNil()
+		*v = Float64{}
+	case jsoniter.NumberValue:
+		*v = Float64{Val: iter.ReadFloat64(), IsSet: true}
+	default:
+		iter.Skip()
+		return false
+	}
+	return true
+}
+
+// Bool holds an optional boolean value.
+type Bool struct {
+	Val   bool
+	IsSet bool
+}
+
+// DecodeJSON decodes the next value from iter into v, reporting
+// whether the value was a JSON boolean or null.
+func (v *Bool) DecodeJSON(iter *jsoniter.Iterator) bool {
+	switch iter.WhatIsNext() {
+	case jsoniter.NilValue:
+		iter.ReadNil()
+		*v = Bool{}
+	case jsoniter.BoolValue:
+		*v = Bool{Val: iter.ReadBool(), IsSet: true}
+	default:
+		iter.Skip()
+		return false
+	}
+	return true
+}
+
+// Interface holds an optional value of any JSON type. Numbers are
+// decoded as json.Number, consistent with decoder.NewJSONDecoder.
+type Interface struct {
+	Val   interface{}
+	IsSet bool
+}
+
+// DecodeJSON decodes the next value from iter into v.
+func (v *Interface) DecodeJSON(iter *jsoniter.Iterator) bool {
+	val := ReadInterface(iter)
+	*v = Interface{Val: val, IsSet: val != nil}
+	return true
+}
+
+// ReadInterface reads the next value from iter as a generic value,
+// decoding numbers as json.Number.
+func ReadInterface(iter *jsoniter.Iterator) interface{} {
+	switch iter.WhatIsNext() {
+	case jsoniter.NumberValue:
+		return iter.ReadNumber()
+	case jsoniter.ArrayValue:
+		arr := []interface{}{}
+		iter.ReadArrayCB(func(iter *jsoniter.Iterator) bool {
+			arr = append(arr, ReadInterface(iter))
+			return true
+		})
+		return arr
+	case jsoniter.ObjectValue:
+		obj := map[string]interface{}{}
+		iter.ReadMapCB(func(iter *jsoniter.Iterator, field string) bool {
+			obj[field] = ReadInterface(iter)
+			return true
+		})
+		return obj
+	default:
+		return iter.Read()
+	}
+}
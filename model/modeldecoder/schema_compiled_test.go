@@ -0,0 +1,110 @@
+// Licensed to Elasticsearch B.V. under one or more contributor
+// license agreements. See the NOTICE file distributed with
+// this work for additional information regarding copyright
+// ownership. Elasticsearch B.V. licenses this file to you under
+// the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package modeldecoder
+
+import (
+	"reflect"
+	"regexp"
+	"testing"
+
+	"github.com/santhosh-tekuri/jsonschema"
+	"github.com/stretchr/testify/assert"
+
+	errorschema "github.com/elastic/apm-server/model/error/generated/schema"
+	metadataschema "github.com/elastic/apm-server/model/metadata/generated/schema"
+	metricsetschema "github.com/elastic/apm-server/model/metricset/generated/schema"
+	sourcemapschema "github.com/elastic/apm-server/model/sourcemap/generated/schema"
+	spanschema "github.com/elastic/apm-server/model/span/generated/schema"
+	transactionschema "github.com/elastic/apm-server/model/transaction/generated/schema"
+	"github.com/elastic/apm-server/validation"
+)
+
+func TestCompiledSchemasUpToDate(t *testing.T) {
+	for name, test := range map[string]struct {
+		schema   string
+		url      string
+		compiled func() *jsonschema.Schema
+	}{
+		"error":              {errorschema.ModelSchema, "error", errorschema.CompiledModelSchema},
+		"rum_v3_error":       {errorschema.RUMV3Schema, "error", errorschema.CompiledRUMV3Schema},
+		"metadata":           {metadataschema.ModelSchema, "metadata", metadataschema.CompiledModelSchema},
+		"rum_v3_metadata":    {metadataschema.RUMV3Schema, "metadata", metadataschema.CompiledRUMV3Schema},
+		"metricset":          {metricsetschema.ModelSchema, "metricset", metricsetschema.CompiledModelSchema},
+		"rum_v3_metricset":   {metricsetschema.RUMV3Schema, "metricset", metricsetschema.CompiledRUMV3Schema},
+		"sourcemap":          {sourcemapschema.PayloadSchema, "sourcemap", sourcemapschema.CompiledPayloadSchema},
+		"span":               {spanschema.ModelSchema, "span", spanschema.CompiledModelSchema},
+		"rum_v3_span":        {spanschema.RUMV3Schema, "span", spanschema.CompiledRUMV3Schema},
+		"transaction":        {transactionschema.ModelSchema, "transaction", transactionschema.CompiledModelSchema},
+		"rum_v3_transaction": {transactionschema.RUMV3Schema, "transaction", transactionschema.CompiledRUMV3Schema},
+	} {
+		t.Run(name, func(t *testing.T) {
+			expected := validation.CreateSchema(test.schema, test.url)
+			actual := test.compiled()
+			patterns := make(map[string]*regexp.Regexp)
+			normalizeSchema(expected, patterns, make(map[*jsonschema.Schema]bool))
+			normalizeSchema(actual, patterns, make(map[*jsonschema.Schema]bool))
+			assert.True(t, reflect.DeepEqual(expected, actual), "compiled schema is out of date, run `make update`")
+		})
+	}
+}
+
+// normalizeSchema prepares s for comparison with reflect.DeepEqual.
+// Formats are functions, which are never deeply equal, so they are
+// compared by name only. Pattern properties are keyed by pointer, so
+// the keys are replaced by a shared regexp for each pattern.
+func normalizeSchema(s *jsonschema.Schema, patterns map[string]*regexp.Regexp, seen map[*jsonschema.Schema]bool) {
+	if s == nil || seen[s] {
+		return
+	}
+	seen[s] = true
+	s.Format = nil
+	if s.PatternProperties != nil {
+		patternProperties := make(map[*regexp.Regexp]*jsonschema.Schema, len(s.PatternProperties))
+		for re, child := range s.PatternProperties {
+			if _, ok := patterns[re.String()]; !ok {
+				patterns[re.String()] = re
+			}
+			patternProperties[patterns[re.String()]] = child
+		}
+		s.PatternProperties = patternProperties
+	}
+	for _, child := range []*jsonschema.Schema{s.Ref, s.Not, s.If, s.Then, s.Else, s.PropertyNames, s.Contains} {
+		normalizeSchema(child, patterns, seen)
+	}
+	for _, children := range [][]*jsonschema.Schema{s.AllOf, s.AnyOf, s.OneOf} {
+		for _, child := range children {
+			normalizeSchema(child, patterns, seen)
+		}
+	}
+	for _, child := range s.Properties {
+		normalizeSchema(child, patterns, seen)
+	}
+	for _, child := range s.PatternProperties {
+		normalizeSchema(child, patterns, seen)
+	}
+	for _, v := range []interface{}{s.AdditionalProperties, s.Items, s.AdditionalItems} {
+		switch v := v.(type) {
+		case *jsonschema.Schema:
+			normalizeSchema(v, patterns, seen)
+		case []*jsonschema.Schema:
+			for _, child := range v {
+				normalizeSchema(child, patterns, seen)
+			}
+		}
+	}
+}
@@ -0,0 +1,68 @@
+// Licensed to Elasticsearch B.V. under one or more contributor
+// license agreements. See the NOTICE file distributed with
+// this work for additional information regarding copyright
+// ownership. Elasticsearch B.V. licenses this file to you under
+// the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package modeldecoder
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+
+	errorschema "github.com/elastic/apm-server/model/error/generated/schema"
+	metadataschema "github.com/elastic/apm-server/model/metadata/generated/schema"
+	metricsetschema "github.com/elastic/apm-server/model/metricset/generated/schema"
+	sourcemapschema "github.com/elastic/apm-server/model/sourcemap/generated/schema"
+	spanschema "github.com/elastic/apm-server/model/span/generated/schema"
+	transactionschema "github.com/elastic/apm-server/model/transaction/generated/schema"
+)
+
+// SchemaDigests returns digests of the latest JSON schemas, keyed by the
+// API they validate: "intake.v2", "intake.rum.v3", and "sourcemap".
+//
+// Older schema versions are snapshots which never change once registered,
+// so they are identified by their version alone. The latest schemas change
+// between releases, so builds validate events identically only if their
+// digests are equal.
+func SchemaDigests() map[string]string {
+	return map[string]string{
+		"intake.v2": schemaDigest(
+			metadataschema.ModelSchema,
+			errorschema.ModelSchema,
+			metricsetschema.ModelSchema,
+			spanschema.ModelSchema,
+			transactionschema.ModelSchema,
+		),
+		"intake.rum.v3": schemaDigest(
+			metadataschema.RUMV3Schema,
+			errorschema.RUMV3Schema,
+			metricsetschema.RUMV3Schema,
+			spanschema.RUMV3Schema,
+			transactionschema.RUMV3Schema,
+		),
+		"sourcemap": schemaDigest(sourcemapschema.PayloadSchema),
+	}
+}
+
+func schemaDigest(schemas ...string) string {
+	h := sha256.New()
+	for _, schema := range schemas {
+		h.Write([]byte(schema))
+		// Separate schemas, so moving content
+		// between them changes the digest.
+		h.Write([]byte{0})
+	}
+	return "sha256:" + hex.EncodeToString(h.Sum(nil))
+}
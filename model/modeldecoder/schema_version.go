@@ -0,0 +1,123 @@
+// Licensed to Elasticsearch B.V. under one or more contributor
+// license agreements. See the NOTICE file distributed with
+// this work for additional information regarding copyright
+// ownership. Elasticsearch B.V. licenses this file to you under
+// the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package modeldecoder
+
+import (
+	"fmt"
+	"sort"
+
+	"github.com/santhosh-tekuri/jsonschema"
+
+	"github.com/elastic/beats/v7/libbeat/common"
+
+	"github.com/elastic/apm-server/model"
+)
+
+// LatestSchemaVersion identifies the most recent version of the intake
+// JSON schemas. Events are validated against this version, unless an
+// older version is explicitly requested or selected for the agent.
+const LatestSchemaVersion = "latest"
+
+// knownSchemaVersions holds all schema versions which may be selected.
+var knownSchemaVersions = map[string]struct{}{LatestSchemaVersion: {}}
+
+// schemaVersionRule selects schemaVersion for events sent by agents with
+// the name agentName, and a version lower than beforeAgentVersion.
+type schemaVersionRule struct {
+	agentName          string
+	beforeAgentVersion *common.Version
+	schemaVersion      string
+}
+
+// schemaVersionRules holds the rules for selecting older schema versions,
+// in order of precedence.
+//
+// When tightening a schema in a way that would reject events sent by agents
+// which are already released, register the previous schema under a new
+// version with registerSchemaVersion, and add a rule for the affected agents.
+var schemaVersionRules []schemaVersionRule
+
+// SchemaVersions returns the schema versions which may be selected, sorted.
+func SchemaVersions() []string {
+	versions := make([]string, 0, len(knownSchemaVersions))
+	for version := range knownSchemaVersions {
+		versions = append(versions, version)
+	}
+	sort.Strings(versions)
+	return versions
+}
+
+// versionedSchema holds the JSON schemas for a single event type, keyed by
+// schema version.
+type versionedSchema map[string]*jsonschema.Schema
+
+func newVersionedSchema(latest *jsonschema.Schema) versionedSchema {
+	return versionedSchema{LatestSchemaVersion: latest}
+}
+
+// get returns the schema for the given version. Versions are only required
+// to register schemas for event types which changed, so get falls back to
+// the latest schema if there is none registered for the version.
+func (s versionedSchema) get(version string) *jsonschema.Schema {
+	if schema, ok := s[version]; ok {
+		return schema
+	}
+	return s[LatestSchemaVersion]
+}
+
+// registerSchemaVersion registers schemas for an older schema version.
+func registerSchemaVersion(version string, schemas map[*versionedSchema]*jsonschema.Schema) {
+	knownSchemaVersions[version] = struct{}{}
+	for vs, schema := range schemas {
+		(*vs)[version] = schema
+	}
+}
+
+// SelectSchemaVersion returns the version of the JSON schemas to validate
+// events against, given the stream metadata and an optional explicitly
+// requested version.
+//
+// Metadata is always validated against the latest schema version, as it is
+// required for selecting the schema version of the events that follow it.
+func SelectSchemaVersion(explicit string, metadata *model.Metadata) (string, error) {
+	if explicit != "" {
+		if _, ok := knownSchemaVersions[explicit]; !ok {
+			return "", fmt.Errorf("unsupported schema version %q", explicit)
+		}
+		return explicit, nil
+	}
+	if metadata == nil || len(schemaVersionRules) == 0 {
+		return LatestSchemaVersion, nil
+	}
+	agent := metadata.Service.Agent
+	agentVersion, err := common.NewVersion(agent.Version)
+	if err != nil {
+		// Agent versions are not required to be semantic versions,
+		// in which case we cannot apply any of the version rules.
+		return LatestSchemaVersion, nil
+	}
+	for _, rule := range schemaVersionRules {
+		if rule.agentName != agent.Name {
+			continue
+		}
+		if rule.beforeAgentVersion == nil || agentVersion.LessThan(rule.beforeAgentVersion) {
+			return rule.schemaVersion, nil
+		}
+	}
+	return LatestSchemaVersion, nil
+}
@@ -0,0 +1,160 @@
+// Licensed to Elasticsearch B.V. under one or more contributor
+// license agreements. See the NOTICE file distributed with
+// this work for additional information regarding copyright
+// ownership. Elasticsearch B.V. licenses this file to you under
+// the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package modeldecoder
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/santhosh-tekuri/jsonschema"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/elastic/beats/v7/libbeat/common"
+
+	"github.com/elastic/apm-server/model"
+	"github.com/elastic/apm-server/validation"
+)
+
+const lenientTransactionSchema = `{
+  "$id": "transaction",
+  "type": "object",
+  "properties": {
+    "id": {"type": "string"}
+  }
+}`
+
+// withSchemaVersion registers a lenient transaction schema under the given
+// version, returning a function which restores the original schemas.
+func withSchemaVersion(version string, rules ...schemaVersionRule) func() {
+	origTransactionSchema := transactionSchema
+	origRules := schemaVersionRules
+	transactionSchema = newVersionedSchema(origTransactionSchema.get(LatestSchemaVersion))
+	registerSchemaVersion(version, map[*versionedSchema]*jsonschema.Schema{
+		&transactionSchema: validation.CreateSchema(lenientTransactionSchema, "transaction"),
+	})
+	schemaVersionRules = rules
+	return func() {
+		transactionSchema = origTransactionSchema
+		schemaVersionRules = origRules
+		delete(knownSchemaVersions, version)
+	}
+}
+
+func TestSelectSchemaVersion(t *testing.T) {
+	defer withSchemaVersion("1.x", schemaVersionRule{
+		agentName:          "java",
+		beforeAgentVersion: common.MustNewVersion("1.5.0"),
+		schemaVersion:      "1.x",
+	})()
+
+	metadata := func(name, version string) *model.Metadata {
+		var m model.Metadata
+		m.Service.Agent.Name = name
+		m.Service.Agent.Version = version
+		return &m
+	}
+
+	for name, test := range map[string]struct {
+		explicit string
+		metadata *model.Metadata
+		expected string
+	}{
+		"no metadata":           {expected: LatestSchemaVersion},
+		"explicit":              {explicit: "1.x", metadata: metadata("java", "1.8.0"), expected: "1.x"},
+		"explicit latest":       {explicit: LatestSchemaVersion, metadata: metadata("java", "1.0.0"), expected: LatestSchemaVersion},
+		"old agent":             {metadata: metadata("java", "1.4.2"), expected: "1.x"},
+		"new agent":             {metadata: metadata("java", "1.5.0"), expected: LatestSchemaVersion},
+		"other agent":           {metadata: metadata("python", "1.0.0"), expected: LatestSchemaVersion},
+		"invalid agent version": {metadata: metadata("java", "unknown"), expected: LatestSchemaVersion},
+	} {
+		t.Run(name, func(t *testing.T) {
+			version, err := SelectSchemaVersion(test.explicit, test.metadata)
+			require.NoError(t, err)
+			assert.Equal(t, test.expected, version)
+		})
+	}
+}
+
+func TestSelectSchemaVersionUnsupported(t *testing.T) {
+	_, err := SelectSchemaVersion("0.1", nil)
+	assert.EqualError(t, err, `unsupported schema version "0.1"`)
+}
+
+func TestSchemaVersions(t *testing.T) {
+	assert.Equal(t, []string{"7.8", LatestSchemaVersion}, SchemaVersions())
+	defer withSchemaVersion("1.x")()
+	assert.Equal(t, []string{"1.x", "7.8", LatestSchemaVersion}, SchemaVersions())
+}
+
+func TestSchemaDigests(t *testing.T) {
+	digests := SchemaDigests()
+	assert.Len(t, digests, 3)
+	seen := make(map[string]bool)
+	for api, digest := range digests {
+		assert.Regexp(t, "^sha256:[0-9a-f]{64}$", digest, api)
+		assert.False(t, seen[digest], api)
+		seen[digest] = true
+	}
+	assert.Equal(t, digests, SchemaDigests())
+}
+
+func TestDecodeTransactionSchemaVersion(t *testing.T) {
+	defer withSchemaVersion("1.x")()
+
+	// The transaction is missing required fields, so it is only
+	// accepted by the lenient schema registered for version 1.x.
+	raw := map[string]interface{}{"id": "abc"}
+
+	var batch model.Batch
+	err := DecodeTransaction(Input{Raw: raw}, &batch)
+	require.Error(t, err)
+
+	err = DecodeTransaction(Input{Raw: raw, SchemaVersion: "1.x"}, &batch)
+	require.NoError(t, err)
+	require.Len(t, batch.Transactions, 1)
+	assert.Equal(t, "abc", batch.Transactions[0].ID)
+
+	// Event types without a schema for the version fall back to the latest.
+	err = DecodeSpan(Input{Raw: map[string]interface{}{}, SchemaVersion: "1.x"}, &batch)
+	require.Error(t, err)
+}
+
+func TestDecodeMetricsetSchemaVersion78(t *testing.T) {
+	version, err := SelectSchemaVersion(SchemaVersion78, nil)
+	require.NoError(t, err)
+	assert.Equal(t, SchemaVersion78, version)
+
+	// The span type exceeds the maximum length, which was
+	// not validated by the schemas released with 7.8.
+	raw := map[string]interface{}{
+		"timestamp": json.Number("1496170422281000"),
+		"samples":   map[string]interface{}{"a.b": map[string]interface{}{"value": json.Number("1")}},
+		"span":      map[string]interface{}{"type": strings.Repeat("x", 1025)},
+	}
+
+	var batch model.Batch
+	err = DecodeMetricset(Input{Raw: raw}, &batch)
+	require.Error(t, err)
+
+	err = DecodeMetricset(Input{Raw: raw, SchemaVersion: version}, &batch)
+	require.NoError(t, err)
+	require.Len(t, batch.Metricsets, 1)
+	assert.Equal(t, strings.Repeat("x", 1025), batch.Metricsets[0].Span.Type)
+}
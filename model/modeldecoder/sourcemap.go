@@ -22,14 +22,13 @@ import (
 	"github.com/elastic/apm-server/model/sourcemap/generated/schema"
 	"github.com/elastic/apm-server/transform"
 	"github.com/elastic/apm-server/utility"
-	"github.com/elastic/apm-server/validation"
 )
 
 // SourcemapSchema is the compiled JSON Schema for validating sourcemaps.
 //
 // TODO(axw) make DecodeSourcemap validate against SourcemapSchema, and unexpose this.
 // This will require changes to processor/asset/sourcemap.
-var SourcemapSchema = validation.CreateSchema(schema.PayloadSchema, "sourcemap")
+var SourcemapSchema = schema.CompiledPayloadSchema()
 
 // DecodeSourcemap decodes a sourcemap.
 func DecodeSourcemap(raw map[string]interface{}) (transform.Transformable, error) {
@@ -33,8 +33,8 @@ import (
 )
 
 var (
-	spanSchema      = validation.CreateSchema(schema.ModelSchema, "span")
-	rumV3SpanSchema = validation.CreateSchema(schema.RUMV3Schema, "span")
+	spanSchema      = newVersionedSchema(schema.CompiledModelSchema())
+	rumV3SpanSchema = schema.CompiledRUMV3Schema()
 )
 
 // decodeRUMV3Span decodes a v3 RUM span, and optional parent index.
@@ -45,7 +45,7 @@ func decodeRUMV3Span(input Input) (_ *model.Span, parentIndex int, _ error) {
 
 // DecodeSpan decodes a span.
 func DecodeSpan(input Input, batch *model.Batch) error {
-	span, _, err := decodeSpan(input, spanSchema)
+	span, _, err := decodeSpan(input, spanSchema.get(input.SchemaVersion))
 	if err != nil {
 		return err
 	}
@@ -81,7 +81,7 @@ func decodeSpan(input Input, schema *jsonschema.Schema) (_ *model.Span, parentIn
 	ctx := decoder.MapStr(raw, fieldName("context"))
 	if ctx != nil {
 		if labels, ok := ctx[fieldName("tags")].(map[string]interface{}); ok {
-			event.Labels = labels
+			event.Labels = input.Config.ContextLimits.apply(labels)
 		}
 
 		db, err := decodeDB(ctx, decoder.Err)
@@ -0,0 +1,150 @@
+// Licensed to Elasticsearch B.V. under one or more contributor
+// license agreements. See the NOTICE file distributed with
+// this work for additional information regarding copyright
+// ownership. Elasticsearch B.V. licenses this file to you under
+// the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package modeldecoder
+
+import (
+	"strings"
+	"time"
+
+	jsoniter "github.com/json-iterator/go"
+	"github.com/pkg/errors"
+
+	"github.com/elastic/apm-server/model"
+	"github.com/elastic/apm-server/model/modeldecoder/generated/decoder"
+)
+
+var (
+	errInvalidSpan = errors.New("failed to decode span: unexpected value type")
+	errNullSpan    = errors.New("failed to validate span: span must be an object")
+)
+
+// DecodeSpanJSON decodes and validates a v2 span directly from iter, using
+// the decoder generated from the span JSON schema. Unlike DecodeSpan, the
+// event is never decoded into an intermediate map; input.Raw is ignored.
+func DecodeSpanJSON(iter *jsoniter.Iterator, input Input, batch *model.Batch) error {
+	var in decoder.Span
+	ok := in.DecodeJSON(iter)
+	if iter.Error != nil {
+		return errors.Wrap(iter.Error, "failed to decode span")
+	}
+	if !ok {
+		return errInvalidSpan
+	}
+	if !in.IsSet {
+		return errNullSpan
+	}
+	if err := in.Validate(); err != nil {
+		return errors.Wrap(err, "failed to validate span")
+	}
+	batch.Spans = append(batch.Spans, mapToSpanModel(&in, input))
+	return nil
+}
+
+// mapToSpanModel is the equivalent of decodeSpan.
+func mapToSpanModel(in *decoder.Span, input Input) *model.Span {
+	out := model.Span{
+		Metadata:      input.Metadata,
+		Name:          in.Name.Val,
+		Start:         float64Ptr(in.Start),
+		Duration:      in.Duration.Val,
+		Sync:          boolPtr(in.Sync),
+		Timestamp:     timeEpochMicro(in.Timestamp),
+		ID:            in.ID.Val,
+		ChildIDs:      stringSlice(in.ChildIds),
+		Type:          in.Type.Val,
+		Subtype:       stringPtr(in.Subtype),
+		Action:        stringPtr(in.Action),
+		ParentID:      in.ParentID.Val,
+		TraceID:       in.TraceID.Val,
+		TransactionID: in.TransactionID.Val,
+		Stacktrace:    mapToStacktraceModel(in.Stacktrace),
+	}
+
+	if ctx := &in.Context; ctx.IsSet {
+		if ctx.Tags != nil {
+			out.Labels = input.Config.ContextLimits.apply(ctx.Tags)
+		}
+		if db := &ctx.DB; db.IsSet {
+			out.DB = &model.DB{
+				Instance:     stringPtr(db.Instance),
+				Statement:    stringPtr(db.Statement),
+				Type:         stringPtr(db.Type),
+				UserName:     stringPtr(db.User),
+				Link:         stringPtr(db.Link),
+				RowsAffected: intPtr(db.RowsAffected),
+			}
+		}
+		if http := &ctx.HTTP; http.IsSet {
+			method := stringPtr(http.Method)
+			if method != nil {
+				*method = strings.ToLower(*method)
+			}
+			out.HTTP = &model.HTTP{
+				URL:        stringPtr(http.URL),
+				StatusCode: intPtr(http.StatusCode),
+				Method:     method,
+			}
+			if resp := &http.Response; resp.IsSet {
+				minimalResp := mapToMinimalResp(resp.StatusCode, resp.Headers, resp.DecodedBodySize, resp.EncodedBodySize, resp.TransferSize)
+				out.HTTP.Response = &minimalResp
+			}
+		}
+		if dest := &ctx.Destination; dest.IsSet {
+			if service := &dest.Service; service.IsSet {
+				out.DestinationService = &model.DestinationService{
+					Type:     stringPtr(service.Type),
+					Name:     stringPtr(service.Name),
+					Resource: stringPtr(service.Resource),
+				}
+			}
+			out.Destination = &model.Destination{
+				Address: stringPtr(dest.Address),
+				Port:    intPtr(dest.Port),
+			}
+		}
+		if ctx.Service.IsSet {
+			var service model.Service
+			mapToServiceModel(&ctx.Service, &service)
+			out.Service = &service
+		}
+		out.Message = mapToMessageModel(&ctx.Message)
+	}
+
+	if out.Subtype == nil && out.Action == nil {
+		sep := "."
+		t := strings.Split(out.Type, sep)
+		out.Type = t[0]
+		if len(t) > 1 {
+			out.Subtype = &t[1]
+		}
+		if len(t) > 2 {
+			action := strings.Join(t[2:], sep)
+			out.Action = &action
+		}
+	}
+
+	if out.Timestamp.IsZero() {
+		timestamp := input.RequestTime
+		if out.Start != nil {
+			// adjust timestamp to be reqTime + start
+			timestamp = timestamp.Add(time.Duration(float64(time.Millisecond) * *out.Start))
+		}
+		out.Timestamp = timestamp
+	}
+	return &out
+}
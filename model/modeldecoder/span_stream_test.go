@@ -0,0 +1,56 @@
+// Licensed to Elasticsearch B.V. under one or more contributor
+// license agreements. See the NOTICE file distributed with
+// this work for additional information regarding copyright
+// ownership. Elasticsearch B.V. licenses this file to you under
+// the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package modeldecoder
+
+import (
+	"strings"
+	"testing"
+
+	jsoniter "github.com/json-iterator/go"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/elastic/apm-server/model"
+)
+
+func TestDecodeSpanJSONMatchesDecodeSpan(t *testing.T) {
+	testDecodeJSONMatches(t, "span", DecodeSpan, DecodeSpanJSON)
+}
+
+func TestDecodeSpanJSONInvalid(t *testing.T) {
+	const valid = `"id": "a", "trace_id": "b", "parent_id": "c", "name": "d", "type": "e", "duration": 1, "start": 1`
+	for name, input := range map[string]string{
+		"null":                       `null`,
+		"missing timestamp or start": `{"id": "a", "trace_id": "b", "parent_id": "c", "name": "d", "type": "e", "duration": 1}`,
+		"null start":                 `{"id": "a", "trace_id": "b", "parent_id": "c", "name": "d", "type": "e", "duration": 1, "start": null}`,
+		"negative duration":          `{"id": "a", "trace_id": "b", "parent_id": "c", "name": "d", "type": "e", "duration": -1, "start": 1}`,
+		"child id too long":          `{` + valid + `, "child_ids": ["` + strings.Repeat("x", 1025) + `"]}`,
+		"null child id":              `{` + valid + `, "child_ids": [null]}`,
+		"null stacktrace frame":      `{` + valid + `, "stacktrace": [null]}`,
+		"frame without filename":     `{` + valid + `, "stacktrace": [{"lineno": 1}]}`,
+		"invalid service version":    `{` + valid + `, "context": {"service": {"version": 1}}}`,
+		"service not an object":      `{` + valid + `, "context": {"service": "foo"}}`,
+	} {
+		t.Run(name, func(t *testing.T) {
+			iter := jsoniter.ConfigCompatibleWithStandardLibrary.BorrowIterator([]byte(input))
+			defer jsoniter.ConfigCompatibleWithStandardLibrary.ReturnIterator(iter)
+			var batch model.Batch
+			assert.Error(t, DecodeSpanJSON(iter, Input{}, &batch))
+			assert.Empty(t, batch.Spans)
+		})
+	}
+}
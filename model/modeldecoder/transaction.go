@@ -19,6 +19,7 @@ package modeldecoder
 
 import (
 	"encoding/json"
+	"time"
 
 	"github.com/pkg/errors"
 	"github.com/santhosh-tekuri/jsonschema"
@@ -31,8 +32,8 @@ import (
 )
 
 var (
-	transactionSchema      = validation.CreateSchema(schema.ModelSchema, "transaction")
-	rumV3TransactionSchema = validation.CreateSchema(schema.RUMV3Schema, "transaction")
+	transactionSchema      = newVersionedSchema(schema.CompiledModelSchema())
+	rumV3TransactionSchema = schema.CompiledRUMV3Schema()
 )
 
 // DecodeRUMV3Transaction decodes a v3 RUM transaction.
@@ -112,7 +113,7 @@ func decodeRUMV3Spans(raw map[string]interface{}, input Input, tr *model.Transac
 
 // DecodeTransaction decodes a v2 transaction.
 func DecodeTransaction(input Input, batch *model.Batch) error {
-	transaction, err := decodeTransaction(input, transactionSchema)
+	transaction, err := decodeTransaction(input, transactionSchema.get(input.SchemaVersion))
 	if err != nil {
 		return err
 	}
@@ -158,12 +159,37 @@ func decodeTransaction(input Input, schema *jsonschema.Schema) (*model.Transacti
 	decodeString(raw, fieldName("name"), &e.Name)
 	decodeString(raw, fieldName("result"), &e.Result)
 	decodeFloat64(raw, fieldName("duration"), &e.Duration)
+	e.DroppedSpansStats = decodeDroppedSpansStats(raw["dropped_spans_stats"])
 	if e.Timestamp.IsZero() {
 		e.Timestamp = input.RequestTime
 	}
 	return &e, nil
 }
 
+func decodeDroppedSpansStats(raw interface{}) []model.DroppedSpanStats {
+	rawStats, _ := raw.([]interface{})
+	if len(rawStats) == 0 {
+		return nil
+	}
+	stats := make([]model.DroppedSpanStats, 0, len(rawStats))
+	for _, rawStat := range rawStats {
+		obj, ok := rawStat.(map[string]interface{})
+		if !ok {
+			continue
+		}
+		var stat model.DroppedSpanStats
+		var sumMicros float64
+		decodeString(obj, "destination_service_resource", &stat.DestinationServiceResource)
+		decodeString(obj, "outcome", &stat.Outcome)
+		duration := getObject(obj, "duration")
+		decodeInt(duration, "count", &stat.Duration.Count)
+		decodeFloat64(getObject(duration, "sum"), "us", &sumMicros)
+		stat.Duration.Sum = time.Duration(sumMicros) * time.Microsecond
+		stats = append(stats, stat)
+	}
+	return stats
+}
+
 func decodeV2Marks(raw map[string]interface{}) model.TransactionMarks {
 	if len(raw) == 0 {
 		return nil
@@ -0,0 +1,142 @@
+// Licensed to Elasticsearch B.V. under one or more contributor
+// license agreements. See the NOTICE file distributed with
+// this work for additional information regarding copyright
+// ownership. Elasticsearch B.V. licenses this file to you under
+// the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package modeldecoder
+
+import (
+	"encoding/json"
+	"time"
+
+	jsoniter "github.com/json-iterator/go"
+	"github.com/pkg/errors"
+
+	"github.com/elastic/apm-server/model"
+	"github.com/elastic/apm-server/model/modeldecoder/generated/decoder"
+)
+
+var (
+	errInvalidTransaction = errors.New("failed to decode transaction: unexpected value type")
+	errNullTransaction    = errors.New("failed to validate transaction: transaction must be an object")
+)
+
+// DecodeTransactionJSON decodes and validates a v2 transaction directly from
+// iter, using the decoder generated from the transaction JSON schema. Unlike
+// DecodeTransaction, the event is never decoded into an intermediate map;
+// input.Raw is ignored.
+func DecodeTransactionJSON(iter *jsoniter.Iterator, input Input, batch *model.Batch) error {
+	var in decoder.Transaction
+	ok := in.DecodeJSON(iter)
+	if iter.Error != nil {
+		return errors.Wrap(iter.Error, "failed to decode transaction")
+	}
+	if !ok {
+		return errInvalidTransaction
+	}
+	if !in.IsSet {
+		return errNullTransaction
+	}
+	if err := in.Validate(); err != nil {
+		return errors.Wrap(err, "failed to validate transaction")
+	}
+	event, err := mapToTransactionModel(&in, input)
+	if err != nil {
+		return err
+	}
+	batch.Transactions = append(batch.Transactions, event)
+	return nil
+}
+
+// mapToTransactionModel is the equivalent of decodeTransaction.
+func mapToTransactionModel(in *decoder.Transaction, input Input) (*model.Transaction, error) {
+	ctx, err := mapToContextModel(&in.Context, input.Config, &input.Metadata)
+	if err != nil {
+		return nil, err
+	}
+	out := model.Transaction{
+		Metadata:  input.Metadata,
+		Labels:    ctx.Labels,
+		Page:      ctx.Page,
+		HTTP:      ctx.Http,
+		URL:       ctx.URL,
+		Custom:    ctx.Custom,
+		Message:   ctx.Message,
+		Sampled:   boolPtr(in.Sampled),
+		Marks:     mapToMarksModel(in.Marks),
+		Timestamp: timeEpochMicro(in.Timestamp),
+		SpanCount: model.SpanCount{
+			Dropped: intPtr(in.SpanCount.Dropped),
+			Started: intPtr(in.SpanCount.Started),
+		},
+		ID:                in.ID.Val,
+		TraceID:           in.TraceID.Val,
+		ParentID:          in.ParentID.Val,
+		Type:              in.Type.Val,
+		Name:              in.Name.Val,
+		Result:            in.Result.Val,
+		Duration:          in.Duration.Val,
+		DroppedSpansStats: mapToDroppedSpansStats(in.DroppedSpansStats),
+	}
+	if out.Timestamp.IsZero() {
+		out.Timestamp = input.RequestTime
+	}
+	return &out, nil
+}
+
+// mapToDroppedSpansStats is the equivalent of decodeDroppedSpansStats.
+func mapToDroppedSpansStats(in []decoder.TransactionDroppedSpansStatsItem) []model.DroppedSpanStats {
+	if len(in) == 0 {
+		return nil
+	}
+	stats := make([]model.DroppedSpanStats, 0, len(in))
+	for _, s := range in {
+		// Numbers are converted as in decodeDroppedSpansStats,
+		// which decodes them as floats.
+		sumMicros := float64(s.Duration.Sum.Us.Val)
+		stats = append(stats, model.DroppedSpanStats{
+			DestinationServiceResource: s.DestinationServiceResource.Val,
+			Outcome:                    s.Outcome.Val,
+			Duration: model.AggregatedDuration{
+				Count: int(float64(s.Duration.Count.Val)),
+				Sum:   time.Duration(sumMicros) * time.Microsecond,
+			},
+		})
+	}
+	return stats
+}
+
+// mapToMarksModel is the equivalent of decodeV2Marks.
+func mapToMarksModel(in map[string]map[string]interface{}) model.TransactionMarks {
+	if len(in) == 0 {
+		return nil
+	}
+	marks := make(model.TransactionMarks, len(in))
+	for group, groupIn := range in {
+		if groupIn == nil {
+			continue
+		}
+		groupMarks := make(model.TransactionMark, len(groupIn))
+		for k, v := range groupIn {
+			if v, ok := v.(json.Number); ok {
+				if f, err := v.Float64(); err == nil {
+					groupMarks[k] = f
+				}
+			}
+		}
+		marks[group] = groupMarks
+	}
+	return marks
+}
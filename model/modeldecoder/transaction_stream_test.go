@@ -0,0 +1,64 @@
+// Licensed to Elasticsearch B.V. under one or more contributor
+// license agreements. See the NOTICE file distributed with
+// this work for additional information regarding copyright
+// ownership. Elasticsearch B.V. licenses this file to you under
+// the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package modeldecoder
+
+import (
+	"strings"
+	"testing"
+
+	jsoniter "github.com/json-iterator/go"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/elastic/apm-server/model"
+)
+
+func TestDecodeTransactionJSONMatchesDecodeTransaction(t *testing.T) {
+	testDecodeJSONMatches(t, "transaction", DecodeTransaction, DecodeTransactionJSON)
+}
+
+func TestDecodeTransactionJSONInvalid(t *testing.T) {
+	const valid = `"id": "a", "trace_id": "b", "type": "c", "duration": 1, "span_count": {"started": 1}`
+	for name, input := range map[string]string{
+		"null":                 `null`,
+		"missing span_count":   `{"id": "a", "trace_id": "b", "type": "c", "duration": 1}`,
+		"null id":              `{"id": null, "trace_id": "b", "type": "c", "duration": 1, "span_count": {"started": 1}}`,
+		"id too long":          `{"id": "` + strings.Repeat("x", 1025) + `", "trace_id": "b", "type": "c", "duration": 1, "span_count": {"started": 1}}`,
+		"negative duration":    `{"id": "a", "trace_id": "b", "type": "c", "duration": -1, "span_count": {"started": 1}}`,
+		"dotted custom key":    `{` + valid + `, "context": {"custom": {"a.b": 1}}}`,
+		"invalid tag type":     `{` + valid + `, "context": {"tags": {"a": {}}}}`,
+		"invalid header value": `{` + valid + `, "context": {"request": {"method": "GET", "url": {}, "headers": {"a": [1]}}}}`,
+		"missing method":       `{` + valid + `, "context": {"request": {"url": {}}}}`,
+		"invalid port":         `{` + valid + `, "context": {"request": {"method": "GET", "url": {"port": "http"}}}}`,
+		"fractional port":      `{` + valid + `, "context": {"request": {"method": "GET", "url": {"port": 80.5}}}}`,
+		"invalid service name": `{` + valid + `, "context": {"service": {"name": "a/b"}}}`,
+		"invalid mark":         `{` + valid + `, "marks": {"a": {"b": "c"}}}`,
+		"negative span count":  `{` + valid + `, "dropped_spans_stats": [{"duration": {"count": -1, "sum": {"us": 1}}}]}`,
+		"null dropped stat":    `{` + valid + `, "dropped_spans_stats": [null]}`,
+	} {
+		t.Run(name, func(t *testing.T) {
+			iter := jsoniter.ConfigCompatibleWithStandardLibrary.BorrowIterator([]byte(input))
+			defer jsoniter.ConfigCompatibleWithStandardLibrary.ReturnIterator(iter)
+			var batch model.Batch
+			assert.Error(t, DecodeTransactionJSON(iter, Input{}, &batch))
+			assert.Empty(t, batch.Transactions)
+
+			// The generated decoder must agree with the JSON schema.
+			assert.Error(t, DecodeTransaction(Input{Raw: decodeJSONString(t, input)}, &batch))
+		})
+	}
+}
@@ -295,6 +295,46 @@ func TestTransactionEventDecode(t *testing.T) {
 				},
 			},
 		},
+		"dropped spans stats": {
+			input: map[string]interface{}{
+				"timestamp": timestampEpoch,
+				"dropped_spans_stats": []interface{}{
+					map[string]interface{}{
+						"destination_service_resource": "mysql",
+						"outcome":                      "success",
+						"duration": map[string]interface{}{
+							"count": 10.0,
+							"sum":   map[string]interface{}{"us": 1500.0},
+						},
+					},
+					map[string]interface{}{
+						"destination_service_resource": "elasticsearch",
+						"duration": map[string]interface{}{
+							"count": json.Number("2"),
+							"sum":   map[string]interface{}{"us": json.Number("30")},
+						},
+					},
+				},
+			},
+			e: &model.Transaction{
+				Metadata:  inputMetadata,
+				ID:        id,
+				Type:      trType,
+				Name:      name,
+				TraceID:   traceID,
+				Duration:  duration,
+				Timestamp: timestampParsed,
+				SpanCount: model.SpanCount{Dropped: &dropped, Started: &started},
+				DroppedSpansStats: []model.DroppedSpanStats{{
+					DestinationServiceResource: "mysql",
+					Outcome:                    "success",
+					Duration:                   model.AggregatedDuration{Count: 10, Sum: 1500 * time.Microsecond},
+				}, {
+					DestinationServiceResource: "elasticsearch",
+					Duration:                   model.AggregatedDuration{Count: 2, Sum: 30 * time.Microsecond},
+				}},
+			},
+		},
 		"valid event": {
 			input: map[string]interface{}{
 				"timestamp": timestampEpoch,
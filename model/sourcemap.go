@@ -51,6 +51,11 @@ type Sourcemap struct {
 	ServiceVersion string
 	Sourcemap      string
 	BundleFilepath string
+
+	// TenantID holds the ID of the tenant the sourcemap was
+	// uploaded for, in multi-tenant mode. The sourcemap is
+	// only applied to the tenant's events.
+	TenantID string
 }
 
 func (pa *Sourcemap) Transform(ctx context.Context, tctx *transform.Context) []beat.Event {
@@ -62,6 +67,9 @@ func (pa *Sourcemap) Transform(ctx context.Context, tctx *transform.Context) []b
 	if tctx.Config.SourcemapStore == nil {
 		logp.NewLogger(logs.Sourcemap).Error("Sourcemap Accessor is nil, cache cannot be invalidated.")
 	} else {
+		if pa.TenantID != "" {
+			ctx = utility.ContextWithTenant(ctx, pa.TenantID)
+		}
 		tctx.Config.SourcemapStore.Added(ctx, pa.ServiceName, pa.ServiceVersion, pa.BundleFilepath)
 	}
 
@@ -76,5 +84,8 @@ func (pa *Sourcemap) Transform(ctx context.Context, tctx *transform.Context) []b
 		},
 		Timestamp: time.Now(),
 	}
+	if pa.TenantID != "" {
+		ev.Fields["tenant"] = common.MapStr{"id": pa.TenantID}
+	}
 	return []beat.Event{ev}
 }
@@ -0,0 +1,80 @@
+// Licensed to Elasticsearch B.V. under one or more contributor
+// license agreements. See the NOTICE file distributed with
+// this work for additional information regarding copyright
+// ownership. Elasticsearch B.V. licenses this file to you under
+// the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+// Code generated by script/inline_schemas. DO NOT EDIT.
+
+package schema
+
+import (
+	"regexp"
+
+	"github.com/santhosh-tekuri/jsonschema"
+)
+
+// CompiledPayloadSchema returns the compiled form of PayloadSchema.
+func CompiledPayloadSchema() *jsonschema.Schema {
+	s := make([]jsonschema.Schema, 4)
+	s[0] = jsonschema.Schema{
+		URL:           "sourcemap",
+		Ptr:           "#",
+		Types:         []string{"object"},
+		MinProperties: -1,
+		MaxProperties: -1,
+		Required:      []string{"bundle_filepath", "service_name", "service_version"},
+		Properties: map[string]*jsonschema.Schema{
+			"bundle_filepath": &s[1],
+			"service_name":    &s[2],
+			"service_version": &s[3],
+		},
+		MinItems:  -1,
+		MaxItems:  -1,
+		MinLength: -1,
+		MaxLength: -1,
+	}
+	s[1] = jsonschema.Schema{
+		URL:           "docs/spec/sourcemaps/sourcemap-metadata.json",
+		Types:         []string{"string"},
+		MinProperties: -1,
+		MaxProperties: -1,
+		MinItems:      -1,
+		MaxItems:      -1,
+		MinLength:     1,
+		MaxLength:     1024,
+	}
+	s[2] = jsonschema.Schema{
+		URL:           "docs/spec/sourcemaps/sourcemap-metadata.json",
+		Types:         []string{"string"},
+		MinProperties: -1,
+		MaxProperties: -1,
+		MinItems:      -1,
+		MaxItems:      -1,
+		MinLength:     1,
+		MaxLength:     1024,
+		Pattern:       regexp.MustCompile("^[a-zA-Z0-9 _-]+$"),
+	}
+	s[3] = jsonschema.Schema{
+		URL:           "docs/spec/sourcemaps/sourcemap-metadata.json",
+		Types:         []string{"string"},
+		MinProperties: -1,
+		MaxProperties: -1,
+		MinItems:      -1,
+		MaxItems:      -1,
+		MinLength:     1,
+		MaxLength:     1024,
+	}
+	return &s[0]
+}
@@ -66,6 +66,11 @@ func TestTransform(t *testing.T) {
 	assert.Equal(t, "myService", getStr(output, "service.name"))
 	assert.Equal(t, "1.0", getStr(output, "service.version"))
 	assert.Equal(t, "mysmap", getStr(output, "sourcemap"))
+	assert.NotContains(t, event.Fields, "tenant")
+
+	p.TenantID = "team-a"
+	events = p.Transform(context.Background(), tctx)
+	assert.Equal(t, "team-a", getStr(events[0].Fields, "tenant.id"))
 }
 
 func TestParseSourcemaps(t *testing.T) {
@@ -111,9 +116,9 @@ func TestInvalidateCache(t *testing.T) {
 			assert.Equal(t, logs.Sourcemap, entry.LoggerName)
 			assert.Equal(t, zapcore.DebugLevel, entry.Level)
 			if i == 0 {
-				assert.Contains(t, entry.Message, "Added id service_1_js/bundle.js. Cache now has 1 entries.")
+				assert.Contains(t, entry.Message, "Added id /service_1_js/bundle.js. Cache now has 1 entries.")
 			} else {
-				assert.Contains(t, entry.Message, "Removed id service_1_js/bundle.js. Cache now has 0 entries.")
+				assert.Contains(t, entry.Message, "Removed id /service_1_js/bundle.js. Cache now has 0 entries.")
 			}
 		}
 
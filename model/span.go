@@ -116,7 +116,7 @@ func (db *DB) fields() common.MapStr {
 	utility.Set(fields, "type", db.Type)
 	utility.Set(fields, "rows_affected", db.RowsAffected)
 	if db.UserName != nil {
-		utility.Set(fields, "user", common.MapStr{"name": db.UserName})
+		fields["user"] = common.MapStr{"name": *db.UserName}
 	}
 	utility.Set(fields, "link", db.Link)
 	return fields
@@ -128,7 +128,7 @@ func (http *HTTP) fields() common.MapStr {
 	}
 	var fields = common.MapStr{}
 	if http.URL != nil {
-		utility.Set(fields, "url", common.MapStr{"original": http.URL})
+		fields["url"] = common.MapStr{"original": *http.URL}
 	}
 	response := http.Response.Fields()
 	if http.StatusCode != nil {
@@ -138,7 +138,9 @@ func (http *HTTP) fields() common.MapStr {
 			response["status_code"] = *http.StatusCode
 		}
 	}
-	utility.Set(fields, "response", response)
+	if len(response) > 0 {
+		fields["response"] = response
+	}
 	utility.Set(fields, "method", http.Method)
 	return fields
 }
@@ -177,32 +179,34 @@ func (e *Span) Transform(ctx context.Context, tctx *transform.Context) []beat.Ev
 		spanFrameCounter.Add(int64(frames))
 	}
 
-	fields := common.MapStr{
+	// Sourcemaps are looked up for the tenant the span was ingested for.
+	ctx = e.Metadata.tenantContext(ctx)
+	fields := mapStr{
 		"processor": spanProcessorEntry,
 		spanDocType: e.fields(ctx, tctx),
 	}
 
 	// first set the generic metadata
-	e.Metadata.Set(fields)
+	e.Metadata.Set(common.MapStr(fields))
 
 	// then add event specific information
-	utility.DeepUpdate(fields, "service", e.Service.Fields("", ""))
-	utility.DeepUpdate(fields, "agent", e.Service.AgentFields())
+	fields.maybeDeepUpdateMapStr("service", e.Service.Fields("", ""))
+	fields.maybeDeepUpdateMapStr("agent", e.Service.AgentFields())
 	// merges with metadata labels, overrides conflicting keys
-	utility.DeepUpdate(fields, "labels", e.Labels)
-	utility.AddID(fields, "parent", e.ParentID)
+	fields.mergeLabels(e.Labels)
+	utility.AddID(common.MapStr(fields), "parent", e.ParentID)
 	if e.ChildIDs != nil {
-		utility.Set(fields, "child", common.MapStr{"id": e.ChildIDs})
+		utility.Set(common.MapStr(fields), "child", common.MapStr{"id": e.ChildIDs})
 	}
-	utility.AddID(fields, "trace", e.TraceID)
-	utility.AddID(fields, "transaction", e.TransactionID)
-	utility.Set(fields, "experimental", e.Experimental)
-	utility.Set(fields, "destination", e.Destination.fields())
-	utility.Set(fields, "timestamp", utility.TimeAsMicros(e.Timestamp))
+	utility.AddID(common.MapStr(fields), "trace", e.TraceID)
+	utility.AddID(common.MapStr(fields), "transaction", e.TransactionID)
+	utility.Set(common.MapStr(fields), "experimental", e.Experimental)
+	fields.maybeSetMapStr("destination", e.Destination.fields())
+	fields.maybeSetMapStr("timestamp", utility.TimeAsMicros(e.Timestamp))
 
 	return []beat.Event{
 		{
-			Fields:    fields,
+			Fields:    common.MapStr(fields),
 			Timestamp: e.Timestamp,
 		},
 	}
@@ -230,11 +234,13 @@ func (e *Span) fields(ctx context.Context, tctx *transform.Context) common.MapSt
 
 	utility.Set(fields, "duration", utility.MillisAsMicros(e.Duration))
 
-	utility.Set(fields, "db", e.DB.fields())
-	utility.Set(fields, "http", e.HTTP.fields())
-	utility.DeepUpdate(fields, "destination.service", e.DestinationService.fields())
-
-	utility.Set(fields, "message", e.Message.Fields())
+	out := (*mapStr)(&fields)
+	out.maybeSetMapStr("db", e.DB.fields())
+	out.maybeSetMapStr("http", e.HTTP.fields())
+	if destinationService := e.DestinationService.fields(); len(destinationService) > 0 {
+		out.set("destination", common.MapStr{"service": destinationService})
+	}
+	out.maybeSetMapStr("message", e.Message.Fields())
 
 	// TODO(axw) we should be using a merged service object, combining
 	// the stream metadata and event-specific service info.
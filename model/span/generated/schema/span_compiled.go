@@ -0,0 +1,1044 @@
+// Licensed to Elasticsearch B.V. under one or more contributor
+// license agreements. See the NOTICE file distributed with
+// this work for additional information regarding copyright
+// ownership. Elasticsearch B.V. licenses this file to you under
+// the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+// Code generated by script/inline_schemas. DO NOT EDIT.
+
+package schema
+
+import (
+	"math/big"
+	"regexp"
+
+	"github.com/santhosh-tekuri/jsonschema"
+)
+
+// CompiledModelSchema returns the compiled form of ModelSchema.
+func CompiledModelSchema() *jsonschema.Schema {
+	s := make([]jsonschema.Schema, 88)
+	s[0] = jsonschema.Schema{
+		URL:           "span",
+		Ptr:           "#",
+		Types:         []string{"object"},
+		AllOf:         []*jsonschema.Schema{&s[1], &s[3], &s[5], &s[7], &s[83]},
+		MinProperties: -1,
+		MaxProperties: -1,
+		MinItems:      -1,
+		MaxItems:      -1,
+		MinLength:     -1,
+		MaxLength:     -1,
+	}
+	s[1] = jsonschema.Schema{
+		URL:           "docs/spec/spans/span.json",
+		Types:         []string{"object"},
+		MinProperties: -1,
+		MaxProperties: -1,
+		Properties: map[string]*jsonschema.Schema{
+			"timestamp": &s[2],
+		},
+		MinItems:  -1,
+		MaxItems:  -1,
+		MinLength: -1,
+		MaxLength: -1,
+	}
+	s[2] = jsonschema.Schema{
+		URL:           "docs/spec/spans/docs/spec/timestamp_epoch.json",
+		Types:         []string{"integer", "null"},
+		MinProperties: -1,
+		MaxProperties: -1,
+		MinItems:      -1,
+		MaxItems:      -1,
+		MinLength:     -1,
+		MaxLength:     -1,
+	}
+	s[3] = jsonschema.Schema{
+		URL:           "docs/spec/spans/span.json",
+		Types:         []string{"object"},
+		MinProperties: -1,
+		MaxProperties: -1,
+		Properties: map[string]*jsonschema.Schema{
+			"type": &s[4],
+		},
+		MinItems:  -1,
+		MaxItems:  -1,
+		MinLength: -1,
+		MaxLength: -1,
+	}
+	s[4] = jsonschema.Schema{
+		URL:           "docs/spec/spans/docs/spec/span_type.json",
+		Types:         []string{"string"},
+		MinProperties: -1,
+		MaxProperties: -1,
+		MinItems:      -1,
+		MaxItems:      -1,
+		MinLength:     -1,
+		MaxLength:     1024,
+	}
+	s[5] = jsonschema.Schema{
+		URL:           "docs/spec/spans/span.json",
+		Types:         []string{"object"},
+		MinProperties: -1,
+		MaxProperties: -1,
+		Properties: map[string]*jsonschema.Schema{
+			"subtype": &s[6],
+		},
+		MinItems:  -1,
+		MaxItems:  -1,
+		MinLength: -1,
+		MaxLength: -1,
+	}
+	s[6] = jsonschema.Schema{
+		URL:           "docs/spec/spans/docs/spec/span_subtype.json",
+		Types:         []string{"string", "null"},
+		MinProperties: -1,
+		MaxProperties: -1,
+		MinItems:      -1,
+		MaxItems:      -1,
+		MinLength:     -1,
+		MaxLength:     1024,
+	}
+	s[7] = jsonschema.Schema{
+		URL:           "docs/spec/spans/span.json",
+		MinProperties: -1,
+		MaxProperties: -1,
+		Required:      []string{"duration", "name", "type", "id", "trace_id", "parent_id"},
+		Properties: map[string]*jsonschema.Schema{
+			"action":         &s[8],
+			"child_ids":      &s[9],
+			"context":        &s[11],
+			"duration":       &s[55],
+			"id":             &s[56],
+			"name":           &s[57],
+			"parent_id":      &s[58],
+			"stacktrace":     &s[59],
+			"start":          &s[79],
+			"sync":           &s[80],
+			"trace_id":       &s[81],
+			"transaction_id": &s[82],
+		},
+		MinItems:  -1,
+		MaxItems:  -1,
+		MinLength: -1,
+		MaxLength: -1,
+	}
+	s[8] = jsonschema.Schema{
+		URL:           "docs/spec/spans/span.json",
+		Types:         []string{"string", "null"},
+		MinProperties: -1,
+		MaxProperties: -1,
+		MinItems:      -1,
+		MaxItems:      -1,
+		MinLength:     -1,
+		MaxLength:     1024,
+	}
+	s[9] = jsonschema.Schema{
+		URL:           "docs/spec/spans/span.json",
+		Types:         []string{"array", "null"},
+		MinProperties: -1,
+		MaxProperties: -1,
+		MinItems:      0,
+		MaxItems:      -1,
+		Items:         &s[10],
+		MinLength:     -1,
+		MaxLength:     1024,
+	}
+	s[10] = jsonschema.Schema{
+		URL:           "docs/spec/spans/span.json",
+		Types:         []string{"string"},
+		MinProperties: -1,
+		MaxProperties: -1,
+		MinItems:      -1,
+		MaxItems:      -1,
+		MinLength:     -1,
+		MaxLength:     1024,
+	}
+	s[11] = jsonschema.Schema{
+		URL:           "docs/spec/spans/span.json",
+		Types:         []string{"object", "null"},
+		MinProperties: -1,
+		MaxProperties: -1,
+		Properties: map[string]*jsonschema.Schema{
+			"db":          &s[12],
+			"destination": &s[19],
+			"http":        &s[26],
+			"message":     &s[38],
+			"service":     &s[47],
+			"tags":        &s[53],
+		},
+		MinItems:  -1,
+		MaxItems:  -1,
+		MinLength: -1,
+		MaxLength: -1,
+	}
+	s[12] = jsonschema.Schema{
+		URL:           "docs/spec/spans/span.json",
+		Types:         []string{"object", "null"},
+		MinProperties: -1,
+		MaxProperties: -1,
+		Properties: map[string]*jsonschema.Schema{
+			"instance":      &s[13],
+			"link":          &s[14],
+			"rows_affected": &s[15],
+			"statement":     &s[16],
+			"type":          &s[17],
+			"user":          &s[18],
+		},
+		MinItems:  -1,
+		MaxItems:  -1,
+		MinLength: -1,
+		MaxLength: -1,
+	}
+	s[13] = jsonschema.Schema{
+		URL:           "docs/spec/spans/span.json",
+		Types:         []string{"string", "null"},
+		MinProperties: -1,
+		MaxProperties: -1,
+		MinItems:      -1,
+		MaxItems:      -1,
+		MinLength:     -1,
+		MaxLength:     -1,
+	}
+	s[14] = jsonschema.Schema{
+		URL:           "docs/spec/spans/span.json",
+		Types:         []string{"string", "null"},
+		MinProperties: -1,
+		MaxProperties: -1,
+		MinItems:      -1,
+		MaxItems:      -1,
+		MinLength:     -1,
+		MaxLength:     1024,
+	}
+	s[15] = jsonschema.Schema{
+		URL:           "docs/spec/spans/span.json",
+		Types:         []string{"integer", "null"},
+		MinProperties: -1,
+		MaxProperties: -1,
+		MinItems:      -1,
+		MaxItems:      -1,
+		MinLength:     -1,
+		MaxLength:     -1,
+	}
+	s[16] = jsonschema.Schema{
+		URL:           "docs/spec/spans/span.json",
+		Types:         []string{"string", "null"},
+		MinProperties: -1,
+		MaxProperties: -1,
+		MinItems:      -1,
+		MaxItems:      -1,
+		MinLength:     -1,
+		MaxLength:     -1,
+	}
+	s[17] = jsonschema.Schema{
+		URL:           "docs/spec/spans/span.json",
+		Types:         []string{"string", "null"},
+		MinProperties: -1,
+		MaxProperties: -1,
+		MinItems:      -1,
+		MaxItems:      -1,
+		MinLength:     -1,
+		MaxLength:     -1,
+	}
+	s[18] = jsonschema.Schema{
+		URL:           "docs/spec/spans/span.json",
+		Types:         []string{"string", "null"},
+		MinProperties: -1,
+		MaxProperties: -1,
+		MinItems:      -1,
+		MaxItems:      -1,
+		MinLength:     -1,
+		MaxLength:     -1,
+	}
+	s[19] = jsonschema.Schema{
+		URL:           "docs/spec/spans/span.json",
+		Types:         []string{"object", "null"},
+		MinProperties: -1,
+		MaxProperties: -1,
+		Properties: map[string]*jsonschema.Schema{
+			"address": &s[20],
+			"port":    &s[21],
+			"service": &s[22],
+		},
+		MinItems:  -1,
+		MaxItems:  -1,
+		MinLength: -1,
+		MaxLength: -1,
+	}
+	s[20] = jsonschema.Schema{
+		URL:           "docs/spec/spans/span.json",
+		Types:         []string{"string", "null"},
+		MinProperties: -1,
+		MaxProperties: -1,
+		MinItems:      -1,
+		MaxItems:      -1,
+		MinLength:     -1,
+		MaxLength:     1024,
+	}
+	s[21] = jsonschema.Schema{
+		URL:           "docs/spec/spans/span.json",
+		Types:         []string{"integer", "null"},
+		MinProperties: -1,
+		MaxProperties: -1,
+		MinItems:      -1,
+		MaxItems:      -1,
+		MinLength:     -1,
+		MaxLength:     -1,
+	}
+	s[22] = jsonschema.Schema{
+		URL:           "docs/spec/spans/span.json",
+		Types:         []string{"object", "null"},
+		MinProperties: -1,
+		MaxProperties: -1,
+		Required:      []string{"type", "name", "resource"},
+		Properties: map[string]*jsonschema.Schema{
+			"name":     &s[23],
+			"resource": &s[24],
+			"type":     &s[25],
+		},
+		MinItems:  -1,
+		MaxItems:  -1,
+		MinLength: -1,
+		MaxLength: -1,
+	}
+	s[23] = jsonschema.Schema{
+		URL:           "docs/spec/spans/span.json",
+		Types:         []string{"string", "null"},
+		MinProperties: -1,
+		MaxProperties: -1,
+		MinItems:      -1,
+		MaxItems:      -1,
+		MinLength:     -1,
+		MaxLength:     1024,
+	}
+	s[24] = jsonschema.Schema{
+		URL:           "docs/spec/spans/span.json",
+		Types:         []string{"string", "null"},
+		MinProperties: -1,
+		MaxProperties: -1,
+		MinItems:      -1,
+		MaxItems:      -1,
+		MinLength:     -1,
+		MaxLength:     1024,
+	}
+	s[25] = jsonschema.Schema{
+		URL:           "docs/spec/spans/span.json",
+		Types:         []string{"string", "null"},
+		MinProperties: -1,
+		MaxProperties: -1,
+		MinItems:      -1,
+		MaxItems:      -1,
+		MinLength:     -1,
+		MaxLength:     1024,
+	}
+	s[26] = jsonschema.Schema{
+		URL:           "docs/spec/spans/span.json",
+		Types:         []string{"object", "null"},
+		MinProperties: -1,
+		MaxProperties: -1,
+		Properties: map[string]*jsonschema.Schema{
+			"method":      &s[27],
+			"response":    &s[28],
+			"status_code": &s[36],
+			"url":         &s[37],
+		},
+		MinItems:  -1,
+		MaxItems:  -1,
+		MinLength: -1,
+		MaxLength: -1,
+	}
+	s[27] = jsonschema.Schema{
+		URL:           "docs/spec/spans/span.json",
+		Types:         []string{"string", "null"},
+		MinProperties: -1,
+		MaxProperties: -1,
+		MinItems:      -1,
+		MaxItems:      -1,
+		MinLength:     -1,
+		MaxLength:     1024,
+	}
+	s[28] = jsonschema.Schema{
+		URL:           "docs/spec/spans/span.json",
+		Types:         []string{"object", "null"},
+		MinProperties: -1,
+		MaxProperties: -1,
+		Properties: map[string]*jsonschema.Schema{
+			"decoded_body_size": &s[29],
+			"encoded_body_size": &s[30],
+			"headers":           &s[31],
+			"status_code":       &s[34],
+			"transfer_size":     &s[35],
+		},
+		MinItems:  -1,
+		MaxItems:  -1,
+		MinLength: -1,
+		MaxLength: -1,
+	}
+	s[29] = jsonschema.Schema{
+		URL:           "docs/spec/spans/docs/spec/http_response.json",
+		Types:         []string{"number", "null"},
+		MinProperties: -1,
+		MaxProperties: -1,
+		MinItems:      -1,
+		MaxItems:      -1,
+		MinLength:     -1,
+		MaxLength:     -1,
+	}
+	s[30] = jsonschema.Schema{
+		URL:           "docs/spec/spans/docs/spec/http_response.json",
+		Types:         []string{"number", "null"},
+		MinProperties: -1,
+		MaxProperties: -1,
+		MinItems:      -1,
+		MaxItems:      -1,
+		MinLength:     -1,
+		MaxLength:     -1,
+	}
+	s[31] = jsonschema.Schema{
+		URL:           "docs/spec/spans/docs/spec/http_response.json",
+		Types:         []string{"object", "null"},
+		MinProperties: -1,
+		MaxProperties: -1,
+		PatternProperties: map[*regexp.Regexp]*jsonschema.Schema{
+			regexp.MustCompile("[.*]*$"): &s[32],
+		},
+		MinItems:  -1,
+		MaxItems:  -1,
+		MinLength: -1,
+		MaxLength: -1,
+	}
+	s[32] = jsonschema.Schema{
+		URL:           "docs/spec/spans/docs/spec/http_response.json",
+		Types:         []string{"string", "array", "null"},
+		MinProperties: -1,
+		MaxProperties: -1,
+		MinItems:      -1,
+		MaxItems:      -1,
+		Items:         &s[33],
+		MinLength:     -1,
+		MaxLength:     -1,
+	}
+	s[33] = jsonschema.Schema{
+		URL:           "docs/spec/spans/docs/spec/http_response.json",
+		Types:         []string{"string"},
+		MinProperties: -1,
+		MaxProperties: -1,
+		MinItems:      -1,
+		MaxItems:      -1,
+		MinLength:     -1,
+		MaxLength:     -1,
+	}
+	s[34] = jsonschema.Schema{
+		URL:           "docs/spec/spans/docs/spec/http_response.json",
+		Types:         []string{"integer", "null"},
+		MinProperties: -1,
+		MaxProperties: -1,
+		MinItems:      -1,
+		MaxItems:      -1,
+		MinLength:     -1,
+		MaxLength:     -1,
+	}
+	s[35] = jsonschema.Schema{
+		URL:           "docs/spec/spans/docs/spec/http_response.json",
+		Types:         []string{"number", "null"},
+		MinProperties: -1,
+		MaxProperties: -1,
+		MinItems:      -1,
+		MaxItems:      -1,
+		MinLength:     -1,
+		MaxLength:     -1,
+	}
+	s[36] = jsonschema.Schema{
+		URL:           "docs/spec/spans/span.json",
+		Types:         []string{"integer", "null"},
+		MinProperties: -1,
+		MaxProperties: -1,
+		MinItems:      -1,
+		MaxItems:      -1,
+		MinLength:     -1,
+		MaxLength:     -1,
+	}
+	s[37] = jsonschema.Schema{
+		URL:           "docs/spec/spans/span.json",
+		Types:         []string{"string", "null"},
+		MinProperties: -1,
+		MaxProperties: -1,
+		MinItems:      -1,
+		MaxItems:      -1,
+		MinLength:     -1,
+		MaxLength:     -1,
+	}
+	s[38] = jsonschema.Schema{
+		URL:           "docs/spec/spans/span.json",
+		Types:         []string{"object", "null"},
+		MinProperties: -1,
+		MaxProperties: -1,
+		Properties: map[string]*jsonschema.Schema{
+			"age":     &s[39],
+			"body":    &s[41],
+			"headers": &s[42],
+			"queue":   &s[45],
+		},
+		MinItems:  -1,
+		MaxItems:  -1,
+		MinLength: -1,
+		MaxLength: -1,
+	}
+	s[39] = jsonschema.Schema{
+		URL:           "docs/spec/spans/docs/spec/message.json",
+		Types:         []string{"object", "null"},
+		MinProperties: -1,
+		MaxProperties: -1,
+		Properties: map[string]*jsonschema.Schema{
+			"ms": &s[40],
+		},
+		MinItems:  -1,
+		MaxItems:  -1,
+		MinLength: -1,
+		MaxLength: -1,
+	}
+	s[40] = jsonschema.Schema{
+		URL:           "docs/spec/spans/docs/spec/message.json",
+		Types:         []string{"integer", "null"},
+		MinProperties: -1,
+		MaxProperties: -1,
+		MinItems:      -1,
+		MaxItems:      -1,
+		MinLength:     -1,
+		MaxLength:     -1,
+	}
+	s[41] = jsonschema.Schema{
+		URL:           "docs/spec/spans/docs/spec/message.json",
+		Types:         []string{"string", "null"},
+		MinProperties: -1,
+		MaxProperties: -1,
+		MinItems:      -1,
+		MaxItems:      -1,
+		MinLength:     -1,
+		MaxLength:     -1,
+	}
+	s[42] = jsonschema.Schema{
+		URL:           "docs/spec/spans/docs/spec/message.json",
+		Types:         []string{"object", "null"},
+		MinProperties: -1,
+		MaxProperties: -1,
+		PatternProperties: map[*regexp.Regexp]*jsonschema.Schema{
+			regexp.MustCompile("[.*]*$"): &s[43],
+		},
+		MinItems:  -1,
+		MaxItems:  -1,
+		MinLength: -1,
+		MaxLength: -1,
+	}
+	s[43] = jsonschema.Schema{
+		URL:           "docs/spec/spans/docs/spec/message.json",
+		Types:         []string{"string", "array", "null"},
+		MinProperties: -1,
+		MaxProperties: -1,
+		MinItems:      -1,
+		MaxItems:      -1,
+		Items:         &s[44],
+		MinLength:     -1,
+		MaxLength:     -1,
+	}
+	s[44] = jsonschema.Schema{
+		URL:           "docs/spec/spans/docs/spec/message.json",
+		Types:         []string{"string"},
+		MinProperties: -1,
+		MaxProperties: -1,
+		MinItems:      -1,
+		MaxItems:      -1,
+		MinLength:     -1,
+		MaxLength:     -1,
+	}
+	s[45] = jsonschema.Schema{
+		URL:           "docs/spec/spans/docs/spec/message.json",
+		Types:         []string{"object", "null"},
+		MinProperties: -1,
+		MaxProperties: -1,
+		Properties: map[string]*jsonschema.Schema{
+			"name": &s[46],
+		},
+		MinItems:  -1,
+		MaxItems:  -1,
+		MinLength: -1,
+		MaxLength: -1,
+	}
+	s[46] = jsonschema.Schema{
+		URL:           "docs/spec/spans/docs/spec/message.json",
+		Types:         []string{"string", "null"},
+		MinProperties: -1,
+		MaxProperties: -1,
+		MinItems:      -1,
+		MaxItems:      -1,
+		MinLength:     -1,
+		MaxLength:     1024,
+	}
+	s[47] = jsonschema.Schema{
+		URL:           "docs/spec/spans/span.json",
+		MinProperties: -1,
+		MaxProperties: -1,
+		Properties: map[string]*jsonschema.Schema{
+			"agent": &s[48],
+			"name":  &s[52],
+		},
+		MinItems:  -1,
+		MaxItems:  -1,
+		MinLength: -1,
+		MaxLength: -1,
+	}
+	s[48] = jsonschema.Schema{
+		URL:           "docs/spec/spans/span.json",
+		Types:         []string{"object", "null"},
+		MinProperties: -1,
+		MaxProperties: -1,
+		Properties: map[string]*jsonschema.Schema{
+			"ephemeral_id": &s[49],
+			"name":         &s[50],
+			"version":      &s[51],
+		},
+		MinItems:  -1,
+		MaxItems:  -1,
+		MinLength: -1,
+		MaxLength: -1,
+	}
+	s[49] = jsonschema.Schema{
+		URL:           "docs/spec/spans/span.json",
+		Types:         []string{"string", "null"},
+		MinProperties: -1,
+		MaxProperties: -1,
+		MinItems:      -1,
+		MaxItems:      -1,
+		MinLength:     -1,
+		MaxLength:     1024,
+	}
+	s[50] = jsonschema.Schema{
+		URL:           "docs/spec/spans/span.json",
+		Types:         []string{"string", "null"},
+		MinProperties: -1,
+		MaxProperties: -1,
+		MinItems:      -1,
+		MaxItems:      -1,
+		MinLength:     -1,
+		MaxLength:     1024,
+	}
+	s[51] = jsonschema.Schema{
+		URL:           "docs/spec/spans/span.json",
+		Types:         []string{"string", "null"},
+		MinProperties: -1,
+		MaxProperties: -1,
+		MinItems:      -1,
+		MaxItems:      -1,
+		MinLength:     -1,
+		MaxLength:     1024,
+	}
+	s[52] = jsonschema.Schema{
+		URL:           "docs/spec/spans/span.json",
+		Types:         []string{"string", "null"},
+		MinProperties: -1,
+		MaxProperties: -1,
+		MinItems:      -1,
+		MaxItems:      -1,
+		MinLength:     -1,
+		MaxLength:     1024,
+		Pattern:       regexp.MustCompile("^[a-zA-Z0-9 _-]+$"),
+	}
+	s[53] = jsonschema.Schema{
+		URL:           "docs/spec/spans/span.json",
+		Types:         []string{"object", "null"},
+		MinProperties: -1,
+		MaxProperties: -1,
+		PatternProperties: map[*regexp.Regexp]*jsonschema.Schema{
+			regexp.MustCompile("^[^.*\"]*$"): &s[54],
+		},
+		AdditionalProperties: false,
+		MinItems:             -1,
+		MaxItems:             -1,
+		MinLength:            -1,
+		MaxLength:            -1,
+	}
+	s[54] = jsonschema.Schema{
+		URL:           "docs/spec/spans/docs/spec/tags.json",
+		Types:         []string{"string", "boolean", "number", "null"},
+		MinProperties: -1,
+		MaxProperties: -1,
+		MinItems:      -1,
+		MaxItems:      -1,
+		MinLength:     -1,
+		MaxLength:     1024,
+	}
+	s[55] = jsonschema.Schema{
+		URL:           "docs/spec/spans/span.json",
+		Types:         []string{"number"},
+		MinProperties: -1,
+		MaxProperties: -1,
+		MinItems:      -1,
+		MaxItems:      -1,
+		MinLength:     -1,
+		MaxLength:     -1,
+		Minimum:       func() *big.Float { f, _ := new(big.Float).SetString("0"); return f }(),
+	}
+	s[56] = jsonschema.Schema{
+		URL:           "docs/spec/spans/span.json",
+		Types:         []string{"string"},
+		MinProperties: -1,
+		MaxProperties: -1,
+		MinItems:      -1,
+		MaxItems:      -1,
+		MinLength:     -1,
+		MaxLength:     1024,
+	}
+	s[57] = jsonschema.Schema{
+		URL:           "docs/spec/spans/span.json",
+		Types:         []string{"string"},
+		MinProperties: -1,
+		MaxProperties: -1,
+		MinItems:      -1,
+		MaxItems:      -1,
+		MinLength:     -1,
+		MaxLength:     1024,
+	}
+	s[58] = jsonschema.Schema{
+		URL:           "docs/spec/spans/span.json",
+		Types:         []string{"string"},
+		MinProperties: -1,
+		MaxProperties: -1,
+		MinItems:      -1,
+		MaxItems:      -1,
+		MinLength:     -1,
+		MaxLength:     1024,
+	}
+	s[59] = jsonschema.Schema{
+		URL:           "docs/spec/spans/span.json",
+		Types:         []string{"array", "null"},
+		MinProperties: -1,
+		MaxProperties: -1,
+		MinItems:      0,
+		MaxItems:      -1,
+		Items:         &s[60],
+		MinLength:     -1,
+		MaxLength:     -1,
+	}
+	s[60] = jsonschema.Schema{
+		URL:           "docs/spec/spans/span.json",
+		Types:         []string{"object"},
+		AnyOf:         []*jsonschema.Schema{&s[61], &s[63]},
+		MinProperties: -1,
+		MaxProperties: -1,
+		Properties: map[string]*jsonschema.Schema{
+			"abs_path":      &s[65],
+			"classname":     &s[66],
+			"colno":         &s[67],
+			"context_line":  &s[68],
+			"filename":      &s[69],
+			"function":      &s[70],
+			"library_frame": &s[71],
+			"lineno":        &s[72],
+			"module":        &s[73],
+			"post_context":  &s[74],
+			"pre_context":   &s[76],
+			"vars":          &s[78],
+		},
+		MinItems:  -1,
+		MaxItems:  -1,
+		MinLength: -1,
+		MaxLength: -1,
+	}
+	s[61] = jsonschema.Schema{
+		URL:           "docs/spec/spans/docs/spec/stacktrace_frame.json",
+		MinProperties: -1,
+		MaxProperties: -1,
+		Required:      []string{"filename"},
+		Properties: map[string]*jsonschema.Schema{
+			"filename": &s[62],
+		},
+		MinItems:  -1,
+		MaxItems:  -1,
+		MinLength: -1,
+		MaxLength: -1,
+	}
+	s[62] = jsonschema.Schema{
+		URL:           "docs/spec/spans/docs/spec/stacktrace_frame.json",
+		Types:         []string{"string"},
+		MinProperties: -1,
+		MaxProperties: -1,
+		MinItems:      -1,
+		MaxItems:      -1,
+		MinLength:     -1,
+		MaxLength:     -1,
+	}
+	s[63] = jsonschema.Schema{
+		URL:           "docs/spec/spans/docs/spec/stacktrace_frame.json",
+		MinProperties: -1,
+		MaxProperties: -1,
+		Required:      []string{"classname"},
+		Properties: map[string]*jsonschema.Schema{
+			"classname": &s[64],
+		},
+		MinItems:  -1,
+		MaxItems:  -1,
+		MinLength: -1,
+		MaxLength: -1,
+	}
+	s[64] = jsonschema.Schema{
+		URL:           "docs/spec/spans/docs/spec/stacktrace_frame.json",
+		Types:         []string{"string"},
+		MinProperties: -1,
+		MaxProperties: -1,
+		MinItems:      -1,
+		MaxItems:      -1,
+		MinLength:     -1,
+		MaxLength:     -1,
+	}
+	s[65] = jsonschema.Schema{
+		URL:           "docs/spec/spans/docs/spec/stacktrace_frame.json",
+		Types:         []string{"string", "null"},
+		MinProperties: -1,
+		MaxProperties: -1,
+		MinItems:      -1,
+		MaxItems:      -1,
+		MinLength:     -1,
+		MaxLength:     -1,
+	}
+	s[66] = jsonschema.Schema{
+		URL:           "docs/spec/spans/docs/spec/stacktrace_frame.json",
+		Types:         []string{"string", "null"},
+		MinProperties: -1,
+		MaxProperties: -1,
+		MinItems:      -1,
+		MaxItems:      -1,
+		MinLength:     -1,
+		MaxLength:     -1,
+	}
+	s[67] = jsonschema.Schema{
+		URL:           "docs/spec/spans/docs/spec/stacktrace_frame.json",
+		Types:         []string{"integer", "null"},
+		MinProperties: -1,
+		MaxProperties: -1,
+		MinItems:      -1,
+		MaxItems:      -1,
+		MinLength:     -1,
+		MaxLength:     -1,
+	}
+	s[68] = jsonschema.Schema{
+		URL:           "docs/spec/spans/docs/spec/stacktrace_frame.json",
+		Types:         []string{"string", "null"},
+		MinProperties: -1,
+		MaxProperties: -1,
+		MinItems:      -1,
+		MaxItems:      -1,
+		MinLength:     -1,
+		MaxLength:     -1,
+	}
+	s[69] = jsonschema.Schema{
+		URL:           "docs/spec/spans/docs/spec/stacktrace_frame.json",
+		Types:         []string{"string", "null"},
+		MinProperties: -1,
+		MaxProperties: -1,
+		MinItems:      -1,
+		MaxItems:      -1,
+		MinLength:     -1,
+		MaxLength:     -1,
+	}
+	s[70] = jsonschema.Schema{
+		URL:           "docs/spec/spans/docs/spec/stacktrace_frame.json",
+		Types:         []string{"string", "null"},
+		MinProperties: -1,
+		MaxProperties: -1,
+		MinItems:      -1,
+		MaxItems:      -1,
+		MinLength:     -1,
+		MaxLength:     -1,
+	}
+	s[71] = jsonschema.Schema{
+		URL:           "docs/spec/spans/docs/spec/stacktrace_frame.json",
+		Types:         []string{"boolean", "null"},
+		MinProperties: -1,
+		MaxProperties: -1,
+		MinItems:      -1,
+		MaxItems:      -1,
+		MinLength:     -1,
+		MaxLength:     -1,
+	}
+	s[72] = jsonschema.Schema{
+		URL:           "docs/spec/spans/docs/spec/stacktrace_frame.json",
+		Types:         []string{"integer", "null"},
+		MinProperties: -1,
+		MaxProperties: -1,
+		MinItems:      -1,
+		MaxItems:      -1,
+		MinLength:     -1,
+		MaxLength:     -1,
+	}
+	s[73] = jsonschema.Schema{
+		URL:           "docs/spec/spans/docs/spec/stacktrace_frame.json",
+		Types:         []string{"string", "null"},
+		MinProperties: -1,
+		MaxProperties: -1,
+		MinItems:      -1,
+		MaxItems:      -1,
+		MinLength:     -1,
+		MaxLength:     -1,
+	}
+	s[74] = jsonschema.Schema{
+		URL:           "docs/spec/spans/docs/spec/stacktrace_frame.json",
+		Types:         []string{"array", "null"},
+		MinProperties: -1,
+		MaxProperties: -1,
+		MinItems:      0,
+		MaxItems:      -1,
+		Items:         &s[75],
+		MinLength:     -1,
+		MaxLength:     -1,
+	}
+	s[75] = jsonschema.Schema{
+		URL:           "docs/spec/spans/docs/spec/stacktrace_frame.json",
+		Types:         []string{"string"},
+		MinProperties: -1,
+		MaxProperties: -1,
+		MinItems:      -1,
+		MaxItems:      -1,
+		MinLength:     -1,
+		MaxLength:     -1,
+	}
+	s[76] = jsonschema.Schema{
+		URL:           "docs/spec/spans/docs/spec/stacktrace_frame.json",
+		Types:         []string{"array", "null"},
+		MinProperties: -1,
+		MaxProperties: -1,
+		MinItems:      0,
+		MaxItems:      -1,
+		Items:         &s[77],
+		MinLength:     -1,
+		MaxLength:     -1,
+	}
+	s[77] = jsonschema.Schema{
+		URL:           "docs/spec/spans/docs/spec/stacktrace_frame.json",
+		Types:         []string{"string"},
+		MinProperties: -1,
+		MaxProperties: -1,
+		MinItems:      -1,
+		MaxItems:      -1,
+		MinLength:     -1,
+		MaxLength:     -1,
+	}
+	s[78] = jsonschema.Schema{
+		URL:           "docs/spec/spans/docs/spec/stacktrace_frame.json",
+		Types:         []string{"object", "null"},
+		MinProperties: -1,
+		MaxProperties: -1,
+		Properties:    map[string]*jsonschema.Schema{},
+		MinItems:      -1,
+		MaxItems:      -1,
+		MinLength:     -1,
+		MaxLength:     -1,
+	}
+	s[79] = jsonschema.Schema{
+		URL:           "docs/spec/spans/span.json",
+		Types:         []string{"number", "null"},
+		MinProperties: -1,
+		MaxProperties: -1,
+		MinItems:      -1,
+		MaxItems:      -1,
+		MinLength:     -1,
+		MaxLength:     -1,
+	}
+	s[80] = jsonschema.Schema{
+		URL:           "docs/spec/spans/span.json",
+		Types:         []string{"boolean", "null"},
+		MinProperties: -1,
+		MaxProperties: -1,
+		MinItems:      -1,
+		MaxItems:      -1,
+		MinLength:     -1,
+		MaxLength:     -1,
+	}
+	s[81] = jsonschema.Schema{
+		URL:           "docs/spec/spans/span.json",
+		Types:         []string{"string"},
+		MinProperties: -1,
+		MaxProperties: -1,
+		MinItems:      -1,
+		MaxItems:      -1,
+		MinLength:     -1,
+		MaxLength:     1024,
+	}
+	s[82] = jsonschema.Schema{
+		URL:           "docs/spec/spans/span.json",
+		Types:         []string{"string", "null"},
+		MinProperties: -1,
+		MaxProperties: -1,
+		MinItems:      -1,
+		MaxItems:      -1,
+		MinLength:     -1,
+		MaxLength:     1024,
+	}
+	s[83] = jsonschema.Schema{
+		URL:           "docs/spec/spans/span.json",
+		AnyOf:         []*jsonschema.Schema{&s[84], &s[86]},
+		MinProperties: -1,
+		MaxProperties: -1,
+		MinItems:      -1,
+		MaxItems:      -1,
+		MinLength:     -1,
+		MaxLength:     -1,
+	}
+	s[84] = jsonschema.Schema{
+		URL:           "docs/spec/spans/span.json",
+		MinProperties: -1,
+		MaxProperties: -1,
+		Required:      []string{"timestamp"},
+		Properties: map[string]*jsonschema.Schema{
+			"timestamp": &s[85],
+		},
+		MinItems:  -1,
+		MaxItems:  -1,
+		MinLength: -1,
+		MaxLength: -1,
+	}
+	s[85] = jsonschema.Schema{
+		URL:           "docs/spec/spans/span.json",
+		Types:         []string{"integer"},
+		MinProperties: -1,
+		MaxProperties: -1,
+		MinItems:      -1,
+		MaxItems:      -1,
+		MinLength:     -1,
+		MaxLength:     -1,
+	}
+	s[86] = jsonschema.Schema{
+		URL:           "docs/spec/spans/span.json",
+		MinProperties: -1,
+		MaxProperties: -1,
+		Required:      []string{"start"},
+		Properties: map[string]*jsonschema.Schema{
+			"start": &s[87],
+		},
+		MinItems:  -1,
+		MaxItems:  -1,
+		MinLength: -1,
+		MaxLength: -1,
+	}
+	s[87] = jsonschema.Schema{
+		URL:           "docs/spec/spans/span.json",
+		Types:         []string{"number"},
+		MinProperties: -1,
+		MaxProperties: -1,
+		MinItems:      -1,
+		MaxItems:      -1,
+		MinLength:     -1,
+		MaxLength:     -1,
+	}
+	return &s[0]
+}
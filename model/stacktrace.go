@@ -68,6 +68,10 @@ func (st *Stacktrace) Transform(ctx context.Context, tctx *transform.Context, se
 	logger := logp.NewLogger(logs.Stacktrace)
 	fct := "<anonymous>"
 	return st.transform(tctx, func(frame *StacktraceFrame) {
+		if ctx.Err() != nil {
+			// the event is being dropped, don't fetch sourcemaps for it
+			return
+		}
 		fct, errMsg = frame.applySourcemap(ctx, tctx.Config.SourcemapStore, service, fct)
 		if errMsg != "" {
 			if _, ok := sourcemapErrorSet[errMsg]; !ok {
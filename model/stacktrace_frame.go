@@ -54,6 +54,10 @@ type StacktraceFrame struct {
 	SourcemapUpdated *bool
 	SourcemapError   *string
 	Original         Original
+
+	// libraryFrameMarked records whether the frame was marked as a
+	// library frame by the server, rather than by the agent.
+	libraryFrameMarked bool
 }
 
 type Original struct {
@@ -78,6 +82,8 @@ func (s *StacktraceFrame) Transform(tctx *transform.Context) common.MapStr {
 	utility.Set(m, "vars", s.Vars)
 	if tctx.Config.LibraryPattern != nil {
 		s.setLibraryFrame(tctx.Config.LibraryPattern)
+	} else if tctx.Config.BackendLibraryPattern != nil {
+		s.markLibraryFrame(tctx.Config.BackendLibraryPattern)
 	}
 	utility.Set(m, "library_frame", s.LibraryFrame)
 
@@ -136,6 +142,25 @@ func (s *StacktraceFrame) setLibraryFrame(pattern *regexp.Regexp) {
 	s.LibraryFrame = &libraryFrame
 }
 
+// markLibraryFrame marks the frame as a library frame if its module,
+// classname, filename or abs_path matches pattern. Unlike setLibraryFrame,
+// frames already marked as library frames by the agent are left as-is,
+// and non-matching frames are never unmarked.
+func (s *StacktraceFrame) markLibraryFrame(pattern *regexp.Regexp) {
+	if s.IsLibraryFrame() {
+		return
+	}
+	for _, v := range []*string{s.Module, s.Classname, s.Filename, s.AbsPath} {
+		if v != nil && pattern.MatchString(*v) {
+			s.Original.LibraryFrame = s.LibraryFrame
+			libraryFrame := true
+			s.LibraryFrame = &libraryFrame
+			s.libraryFrameMarked = true
+			return
+		}
+	}
+}
+
 func (s *StacktraceFrame) applySourcemap(ctx context.Context, store *sourcemap.Store, service *Service, prevFunction string) (function string, errMsg string) {
 	function = prevFunction
 
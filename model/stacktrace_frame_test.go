@@ -403,6 +403,26 @@ func TestLibraryFrame(t *testing.T) {
 			libraryFrame:     &truthy,
 			origLibraryFrame: nil,
 			msg:              "AbsPath and Filename matching"},
+		{fr: StacktraceFrame{Classname: tests.StringPtr("jdk.internal.reflect.Method")},
+			conf:             transform.Config{BackendLibraryPattern: regexp.MustCompile(`^jdk\.`)},
+			libraryFrame:     &truthy,
+			origLibraryFrame: nil,
+			msg:              "Backend pattern, Classname matching"},
+		{fr: StacktraceFrame{Module: tests.StringPtr("github.com/x/vendor/y"), LibraryFrame: &falsy},
+			conf:             transform.Config{BackendLibraryPattern: regexp.MustCompile("/vendor/")},
+			libraryFrame:     &truthy,
+			origLibraryFrame: &falsy,
+			msg:              "Backend pattern, Module matching"},
+		{fr: StacktraceFrame{AbsPath: &path, LibraryFrame: &falsy},
+			conf:             transform.Config{BackendLibraryPattern: regexp.MustCompile("site-packages/")},
+			libraryFrame:     &falsy,
+			origLibraryFrame: nil,
+			msg:              "Backend pattern, no Match"},
+		{fr: StacktraceFrame{AbsPath: &path, LibraryFrame: &truthy},
+			conf:             transform.Config{BackendLibraryPattern: regexp.MustCompile("site-packages/")},
+			libraryFrame:     &truthy,
+			origLibraryFrame: nil,
+			msg:              "Backend pattern, agent library frame kept"},
 	}
 
 	for _, test := range tests {
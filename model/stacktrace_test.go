@@ -263,3 +263,21 @@ func TestStacktraceTransformWithSourcemapping(t *testing.T) {
 		})
 	}
 }
+
+func TestStacktraceTransformSourcemappingCancelled(t *testing.T) {
+	service := Service{Name: "service1", Version: "2.4.1"}
+	absPath, lineno, colno := "/../a/c", 1, 67
+	st := Stacktrace{&StacktraceFrame{AbsPath: &absPath, Lineno: &lineno, Colno: &colno}}
+	tctx := &transform.Context{
+		Config: transform.Config{SourcemapStore: testSourcemapStore(t, test.ESClientWithValidSourcemap(t))},
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	output := st.Transform(ctx, tctx, &service)
+	assert.Equal(t, []common.MapStr{{
+		"abs_path":              "/../a/c",
+		"line":                  common.MapStr{"column": 67, "number": 1},
+		"exclude_from_grouping": false,
+	}}, output)
+}
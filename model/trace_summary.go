@@ -0,0 +1,127 @@
+// Licensed to Elasticsearch B.V. under one or more contributor
+// license agreements. See the NOTICE file distributed with
+// this work for additional information regarding copyright
+// ownership. Elasticsearch B.V. licenses this file to you under
+// the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package model
+
+import (
+	"context"
+	"time"
+
+	"github.com/elastic/beats/v7/libbeat/beat"
+	"github.com/elastic/beats/v7/libbeat/common"
+	"github.com/elastic/beats/v7/libbeat/monitoring"
+
+	"github.com/elastic/apm-server/transform"
+)
+
+const (
+	traceSummaryProcessorName = "trace"
+	traceSummaryDocType       = "trace"
+)
+
+var (
+	traceSummaryMetrics         = monitoring.Default.NewRegistry("apm-server.processor.trace")
+	traceSummaryTransformations = monitoring.NewInt(traceSummaryMetrics, "transformations")
+	traceSummaryProcessorEntry  = common.MapStr{"name": traceSummaryProcessorName, "event": traceSummaryDocType}
+)
+
+// TraceSummary summarises a completed trace: its root transaction,
+// total duration, and the number of spans and errors per service.
+type TraceSummary struct {
+	// Metadata holds the metadata of the root transaction.
+	Metadata Metadata
+
+	// Timestamp holds the start time of the trace.
+	Timestamp time.Time
+
+	// TraceID holds the ID of the summarised trace.
+	TraceID string
+
+	// Root holds information about the trace's root transaction.
+	Root TraceSummaryRoot
+
+	// Duration holds the total duration of the trace, from the start
+	// of its earliest event to the end of its latest event.
+	Duration time.Duration
+
+	// Services holds per-service event counts, ordered by service name.
+	Services []TraceSummaryService
+}
+
+// TraceSummaryRoot identifies the root transaction of a trace.
+type TraceSummaryRoot struct {
+	ID     string
+	Name   string
+	Type   string
+	Result string
+}
+
+// TraceSummaryService holds the event counts of a single service
+// participating in a trace.
+type TraceSummaryService struct {
+	Name             string
+	TransactionCount int
+	SpanCount        int
+	ErrorCount       int
+}
+
+// Transform transforms a TraceSummary into a single beat.Event.
+func (s *TraceSummary) Transform(ctx context.Context, tctx *transform.Context) []beat.Event {
+	traceSummaryTransformations.Inc()
+	if s == nil {
+		return nil
+	}
+
+	var transactionCount, spanCount, errorCount int
+	services := make([]common.MapStr, len(s.Services))
+	for i, service := range s.Services {
+		transactionCount += service.TransactionCount
+		spanCount += service.SpanCount
+		errorCount += service.ErrorCount
+		services[i] = common.MapStr{
+			"name":              service.Name,
+			"transaction_count": service.TransactionCount,
+			"span_count":        service.SpanCount,
+			"error_count":       service.ErrorCount,
+		}
+	}
+
+	fields := common.MapStr{
+		"processor": traceSummaryProcessorEntry,
+		"trace": common.MapStr{
+			"id":                s.TraceID,
+			"duration":          common.MapStr{"us": int64(s.Duration / time.Microsecond)},
+			"transaction_count": transactionCount,
+			"span_count":        spanCount,
+			"error_count":       errorCount,
+			"services":          services,
+		},
+	}
+	s.Metadata.Set(fields)
+
+	var root mapStr
+	root.maybeSetString("id", s.Root.ID)
+	root.maybeSetString("name", s.Root.Name)
+	root.maybeSetString("type", s.Root.Type)
+	root.maybeSetString("result", s.Root.Result)
+	(*mapStr)(&fields).maybeSetMapStr("transaction", common.MapStr(root))
+
+	return []beat.Event{{
+		Fields:    fields,
+		Timestamp: s.Timestamp,
+	}}
+}
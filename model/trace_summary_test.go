@@ -0,0 +1,81 @@
+// Licensed to Elasticsearch B.V. under one or more contributor
+// license agreements. See the NOTICE file distributed with
+// this work for additional information regarding copyright
+// ownership. Elasticsearch B.V. licenses this file to you under
+// the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package model
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/elastic/beats/v7/libbeat/common"
+
+	"github.com/elastic/apm-server/transform"
+)
+
+func TestTraceSummaryTransform(t *testing.T) {
+	timestamp := time.Date(2020, 6, 1, 12, 0, 0, 0, time.UTC)
+	summary := TraceSummary{
+		Metadata:  Metadata{Service: Service{Name: "frontend"}},
+		Timestamp: timestamp,
+		TraceID:   "trace-id",
+		Root: TraceSummaryRoot{
+			ID:   "tx-id",
+			Name: "GET /",
+			Type: "request",
+		},
+		Duration: 1500 * time.Microsecond,
+		Services: []TraceSummaryService{
+			{Name: "backend", TransactionCount: 2, SpanCount: 5, ErrorCount: 1},
+			{Name: "frontend", TransactionCount: 1, SpanCount: 3},
+		},
+	}
+
+	events := summary.Transform(context.Background(), &transform.Context{})
+	require.Len(t, events, 1)
+	assert.Equal(t, timestamp, events[0].Timestamp)
+	assert.Equal(t, common.MapStr{
+		"processor": common.MapStr{"name": "trace", "event": "trace"},
+		"service":   common.MapStr{"name": "frontend"},
+		"transaction": common.MapStr{
+			"id":   "tx-id",
+			"name": "GET /",
+			"type": "request",
+		},
+		"trace": common.MapStr{
+			"id":                "trace-id",
+			"duration":          common.MapStr{"us": int64(1500)},
+			"transaction_count": 3,
+			"span_count":        8,
+			"error_count":       1,
+			"services": []common.MapStr{{
+				"name":              "backend",
+				"transaction_count": 2,
+				"span_count":        5,
+				"error_count":       1,
+			}, {
+				"name":              "frontend",
+				"transaction_count": 1,
+				"span_count":        3,
+				"error_count":       0,
+			}},
+		},
+	}, events[0].Fields)
+}
@@ -67,6 +67,10 @@ type Transaction struct {
 	Labels    *Labels
 	Custom    *Custom
 
+	// DroppedSpansStats holds statistics of the spans dropped by the
+	// agent, grouped by destination service resource and outcome.
+	DroppedSpansStats []DroppedSpanStats
+
 	Experimental interface{}
 }
 
@@ -75,6 +79,31 @@ type SpanCount struct {
 	Started *int
 }
 
+// DroppedSpanStats holds statistics of the spans dropped by an agent
+// for a destination service resource and outcome.
+type DroppedSpanStats struct {
+	DestinationServiceResource string
+	Outcome                    string
+	Duration                   AggregatedDuration
+}
+
+// AggregatedDuration holds the number and total duration of a set of events.
+type AggregatedDuration struct {
+	Count int
+	Sum   time.Duration
+}
+
+func (s *DroppedSpanStats) fields() common.MapStr {
+	var fields mapStr
+	fields.set("destination_service_resource", s.DestinationServiceResource)
+	fields.maybeSetString("outcome", s.Outcome)
+	fields.set("duration", common.MapStr{
+		"count": s.Duration.Count,
+		"sum":   common.MapStr{"us": int64(s.Duration.Sum / time.Microsecond)},
+	})
+	return common.MapStr(fields)
+}
+
 // fields creates the fields to populate in the top-level "transaction" object field.
 func (e *Transaction) fields() common.MapStr {
 	var fields mapStr
@@ -97,6 +126,13 @@ func (e *Transaction) fields() common.MapStr {
 		}
 		fields.set("span_count", spanCount)
 	}
+	if len(e.DroppedSpansStats) > 0 {
+		stats := make([]common.MapStr, len(e.DroppedSpansStats))
+		for i, s := range e.DroppedSpansStats {
+			stats[i] = s.fields()
+		}
+		fields.set("dropped_spans_stats", stats)
+	}
 	// TODO(axw) change Sampled to be non-pointer, and set its final value when
 	// instantiating the model type.
 	fields.set("sampled", e.Sampled == nil || *e.Sampled)
@@ -106,35 +142,39 @@ func (e *Transaction) fields() common.MapStr {
 func (e *Transaction) Transform(_ context.Context, _ *transform.Context) []beat.Event {
 	transactionTransformations.Inc()
 
-	fields := common.MapStr{
+	fields := mapStr{
 		"processor":        transactionProcessorEntry,
 		transactionDocType: e.fields(),
 	}
 
 	// first set generic metadata (order is relevant)
-	e.Metadata.Set(fields)
-	utility.Set(fields, "source", fields["client"])
+	e.Metadata.Set(common.MapStr(fields))
+	if client, ok := fields["client"]; ok {
+		fields.set("source", client)
+	}
 
 	// then merge event specific information
-	utility.AddID(fields, "parent", e.ParentID)
-	utility.AddID(fields, "trace", e.TraceID)
-	utility.Set(fields, "timestamp", utility.TimeAsMicros(e.Timestamp))
+	utility.AddID(common.MapStr(fields), "parent", e.ParentID)
+	utility.AddID(common.MapStr(fields), "trace", e.TraceID)
+	fields.maybeSetMapStr("timestamp", utility.TimeAsMicros(e.Timestamp))
 	// merges with metadata labels, overrides conflicting keys
-	utility.DeepUpdate(fields, "labels", e.Labels.Fields())
-	utility.Set(fields, "http", e.HTTP.Fields())
+	fields.mergeLabels(e.Labels.Fields())
+	fields.maybeSetMapStr("http", e.HTTP.Fields())
 	urlFields := e.URL.Fields()
 	if urlFields != nil {
-		utility.Set(fields, "url", e.URL.Fields())
+		fields.maybeSetMapStr("url", urlFields)
 	}
 	if e.Page != nil {
-		utility.DeepUpdate(fields, "http.request.referrer", e.Page.Referer)
+		if e.Page.Referer != nil {
+			common.MapStr(fields).Put("http.request.referrer", *e.Page.Referer)
+		}
 		if urlFields == nil {
-			utility.Set(fields, "url", e.Page.URL.Fields())
+			fields.maybeSetMapStr("url", e.Page.URL.Fields())
 		}
 	}
-	utility.Set(fields, "experimental", e.Experimental)
+	utility.Set(common.MapStr(fields), "experimental", e.Experimental)
 
-	return []beat.Event{{Fields: fields, Timestamp: e.Timestamp}}
+	return []beat.Event{{Fields: common.MapStr(fields), Timestamp: e.Timestamp}}
 }
 
 type TransactionMarks map[string]TransactionMark
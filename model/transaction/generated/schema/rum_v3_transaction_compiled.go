@@ -0,0 +1,1718 @@
+// Licensed to Elasticsearch B.V. under one or more contributor
+// license agreements. See the NOTICE file distributed with
+// this work for additional information regarding copyright
+// ownership. Elasticsearch B.V. licenses this file to you under
+// the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+// Code generated by script/inline_schemas. DO NOT EDIT.
+
+package schema
+
+import (
+	"math/big"
+	"regexp"
+
+	"github.com/santhosh-tekuri/jsonschema"
+)
+
+// CompiledRUMV3Schema returns the compiled form of RUMV3Schema.
+func CompiledRUMV3Schema() *jsonschema.Schema {
+	s := make([]jsonschema.Schema, 145)
+	s[0] = jsonschema.Schema{
+		URL:           "transaction",
+		Ptr:           "#",
+		Types:         []string{"object"},
+		AllOf:         []*jsonschema.Schema{&s[1]},
+		MinProperties: -1,
+		MaxProperties: -1,
+		MinItems:      -1,
+		MaxItems:      -1,
+		MinLength:     -1,
+		MaxLength:     -1,
+	}
+	s[1] = jsonschema.Schema{
+		URL:           "docs/spec/transactions/rum_v3_transaction.json",
+		MinProperties: -1,
+		MaxProperties: -1,
+		Required:      []string{"id", "tid", "yc", "d", "t"},
+		Properties: map[string]*jsonschema.Schema{
+			"c":   &s[2],
+			"d":   &s[46],
+			"id":  &s[47],
+			"k":   &s[48],
+			"me":  &s[73],
+			"n":   &s[90],
+			"pid": &s[91],
+			"rt":  &s[92],
+			"sm":  &s[93],
+			"t":   &s[94],
+			"tid": &s[95],
+			"y":   &s[96],
+			"yc":  &s[142],
+		},
+		MinItems:  -1,
+		MaxItems:  -1,
+		MinLength: -1,
+		MaxLength: -1,
+	}
+	s[2] = jsonschema.Schema{
+		URL:           "docs/spec/transactions/rum_v3_transaction.json",
+		Types:         []string{"object", "null"},
+		MinProperties: -1,
+		MaxProperties: -1,
+		Properties: map[string]*jsonschema.Schema{
+			"cu": &s[3],
+			"g":  &s[5],
+			"p":  &s[7],
+			"q":  &s[10],
+			"r":  &s[17],
+			"se": &s[26],
+			"u":  &s[42],
+		},
+		MinItems:  -1,
+		MaxItems:  -1,
+		MinLength: -1,
+		MaxLength: -1,
+	}
+	s[3] = jsonschema.Schema{
+		URL:           "docs/spec/transactions/docs/spec/rum_v3_context.json",
+		Types:         []string{"object", "null"},
+		MinProperties: -1,
+		MaxProperties: -1,
+		PatternProperties: map[*regexp.Regexp]*jsonschema.Schema{
+			regexp.MustCompile("^[^.*\"]*$"): &s[4],
+		},
+		AdditionalProperties: false,
+		MinItems:             -1,
+		MaxItems:             -1,
+		MinLength:            -1,
+		MaxLength:            -1,
+	}
+	s[4] = jsonschema.Schema{
+		URL:           "docs/spec/transactions/docs/spec/rum_v3_context.json",
+		MinProperties: -1,
+		MaxProperties: -1,
+		MinItems:      -1,
+		MaxItems:      -1,
+		MinLength:     -1,
+		MaxLength:     -1,
+	}
+	s[5] = jsonschema.Schema{
+		URL:           "docs/spec/transactions/docs/spec/rum_v3_context.json",
+		Types:         []string{"object", "null"},
+		MinProperties: -1,
+		MaxProperties: -1,
+		PatternProperties: map[*regexp.Regexp]*jsonschema.Schema{
+			regexp.MustCompile("^[^.*\"]*$"): &s[6],
+		},
+		AdditionalProperties: false,
+		MinItems:             -1,
+		MaxItems:             -1,
+		MinLength:            -1,
+		MaxLength:            -1,
+	}
+	s[6] = jsonschema.Schema{
+		URL:           "docs/spec/transactions/docs/spec/docs/spec/tags.json",
+		Types:         []string{"string", "boolean", "number", "null"},
+		MinProperties: -1,
+		MaxProperties: -1,
+		MinItems:      -1,
+		MaxItems:      -1,
+		MinLength:     -1,
+		MaxLength:     1024,
+	}
+	s[7] = jsonschema.Schema{
+		URL:           "docs/spec/transactions/docs/spec/rum_v3_context.json",
+		Types:         []string{"object", "null"},
+		MinProperties: -1,
+		MaxProperties: -1,
+		Properties: map[string]*jsonschema.Schema{
+			"rf":  &s[8],
+			"url": &s[9],
+		},
+		MinItems:  -1,
+		MaxItems:  -1,
+		MinLength: -1,
+		MaxLength: -1,
+	}
+	s[8] = jsonschema.Schema{
+		URL:           "docs/spec/transactions/docs/spec/rum_v3_context.json",
+		Types:         []string{"string", "null"},
+		MinProperties: -1,
+		MaxProperties: -1,
+		MinItems:      -1,
+		MaxItems:      -1,
+		MinLength:     -1,
+		MaxLength:     -1,
+	}
+	s[9] = jsonschema.Schema{
+		URL:           "docs/spec/transactions/docs/spec/rum_v3_context.json",
+		Types:         []string{"string", "null"},
+		MinProperties: -1,
+		MaxProperties: -1,
+		MinItems:      -1,
+		MaxItems:      -1,
+		MinLength:     -1,
+		MaxLength:     -1,
+	}
+	s[10] = jsonschema.Schema{
+		URL:           "docs/spec/transactions/docs/spec/rum_v3_context.json",
+		MinProperties: -1,
+		MaxProperties: -1,
+		Required:      []string{"mt"},
+		Properties: map[string]*jsonschema.Schema{
+			"en":  &s[11],
+			"he":  &s[12],
+			"hve": &s[15],
+			"mt":  &s[16],
+		},
+		MinItems:  -1,
+		MaxItems:  -1,
+		MinLength: -1,
+		MaxLength: -1,
+	}
+	s[11] = jsonschema.Schema{
+		URL:           "docs/spec/transactions/docs/spec/rum_v3_context.json",
+		Types:         []string{"object", "null"},
+		MinProperties: -1,
+		MaxProperties: -1,
+		Properties:    map[string]*jsonschema.Schema{},
+		MinItems:      -1,
+		MaxItems:      -1,
+		MinLength:     -1,
+		MaxLength:     -1,
+	}
+	s[12] = jsonschema.Schema{
+		URL:           "docs/spec/transactions/docs/spec/rum_v3_context.json",
+		Types:         []string{"object", "null"},
+		MinProperties: -1,
+		MaxProperties: -1,
+		PatternProperties: map[*regexp.Regexp]*jsonschema.Schema{
+			regexp.MustCompile("[.*]*$"): &s[13],
+		},
+		MinItems:  -1,
+		MaxItems:  -1,
+		MinLength: -1,
+		MaxLength: -1,
+	}
+	s[13] = jsonschema.Schema{
+		URL:           "docs/spec/transactions/docs/spec/rum_v3_context.json",
+		Types:         []string{"string", "array", "null"},
+		MinProperties: -1,
+		MaxProperties: -1,
+		MinItems:      -1,
+		MaxItems:      -1,
+		Items:         &s[14],
+		MinLength:     -1,
+		MaxLength:     -1,
+	}
+	s[14] = jsonschema.Schema{
+		URL:           "docs/spec/transactions/docs/spec/rum_v3_context.json",
+		Types:         []string{"string"},
+		MinProperties: -1,
+		MaxProperties: -1,
+		MinItems:      -1,
+		MaxItems:      -1,
+		MinLength:     -1,
+		MaxLength:     -1,
+	}
+	s[15] = jsonschema.Schema{
+		URL:           "docs/spec/transactions/docs/spec/rum_v3_context.json",
+		Types:         []string{"string", "null"},
+		MinProperties: -1,
+		MaxProperties: -1,
+		MinItems:      -1,
+		MaxItems:      -1,
+		MinLength:     -1,
+		MaxLength:     1024,
+	}
+	s[16] = jsonschema.Schema{
+		URL:           "docs/spec/transactions/docs/spec/rum_v3_context.json",
+		Types:         []string{"string"},
+		MinProperties: -1,
+		MaxProperties: -1,
+		MinItems:      -1,
+		MaxItems:      -1,
+		MinLength:     -1,
+		MaxLength:     1024,
+	}
+	s[17] = jsonschema.Schema{
+		URL:           "docs/spec/transactions/docs/spec/rum_v3_context.json",
+		Types:         []string{"object", "null"},
+		AllOf:         []*jsonschema.Schema{&s[18]},
+		MinProperties: -1,
+		MaxProperties: -1,
+		MinItems:      -1,
+		MaxItems:      -1,
+		MinLength:     -1,
+		MaxLength:     -1,
+	}
+	s[18] = jsonschema.Schema{
+		URL:           "docs/spec/transactions/docs/spec/rum_v3_context.json",
+		MinProperties: -1,
+		MaxProperties: -1,
+		Properties: map[string]*jsonschema.Schema{
+			"dbs": &s[19],
+			"ebs": &s[20],
+			"he":  &s[21],
+			"sc":  &s[24],
+			"ts":  &s[25],
+		},
+		MinItems:  -1,
+		MaxItems:  -1,
+		MinLength: -1,
+		MaxLength: -1,
+	}
+	s[19] = jsonschema.Schema{
+		URL:           "docs/spec/transactions/docs/spec/rum_v3_context.json",
+		Types:         []string{"number", "null"},
+		MinProperties: -1,
+		MaxProperties: -1,
+		MinItems:      -1,
+		MaxItems:      -1,
+		MinLength:     -1,
+		MaxLength:     -1,
+	}
+	s[20] = jsonschema.Schema{
+		URL:           "docs/spec/transactions/docs/spec/rum_v3_context.json",
+		Types:         []string{"number", "null"},
+		MinProperties: -1,
+		MaxProperties: -1,
+		MinItems:      -1,
+		MaxItems:      -1,
+		MinLength:     -1,
+		MaxLength:     -1,
+	}
+	s[21] = jsonschema.Schema{
+		URL:           "docs/spec/transactions/docs/spec/rum_v3_context.json",
+		Types:         []string{"object", "null"},
+		MinProperties: -1,
+		MaxProperties: -1,
+		PatternProperties: map[*regexp.Regexp]*jsonschema.Schema{
+			regexp.MustCompile("[.*]*$"): &s[22],
+		},
+		MinItems:  -1,
+		MaxItems:  -1,
+		MinLength: -1,
+		MaxLength: -1,
+	}
+	s[22] = jsonschema.Schema{
+		URL:           "docs/spec/transactions/docs/spec/rum_v3_context.json",
+		Types:         []string{"string", "array", "null"},
+		MinProperties: -1,
+		MaxProperties: -1,
+		MinItems:      -1,
+		MaxItems:      -1,
+		Items:         &s[23],
+		MinLength:     -1,
+		MaxLength:     -1,
+	}
+	s[23] = jsonschema.Schema{
+		URL:           "docs/spec/transactions/docs/spec/rum_v3_context.json",
+		Types:         []string{"string"},
+		MinProperties: -1,
+		MaxProperties: -1,
+		MinItems:      -1,
+		MaxItems:      -1,
+		MinLength:     -1,
+		MaxLength:     -1,
+	}
+	s[24] = jsonschema.Schema{
+		URL:           "docs/spec/transactions/docs/spec/rum_v3_context.json",
+		Types:         []string{"integer", "null"},
+		MinProperties: -1,
+		MaxProperties: -1,
+		MinItems:      -1,
+		MaxItems:      -1,
+		MinLength:     -1,
+		MaxLength:     -1,
+	}
+	s[25] = jsonschema.Schema{
+		URL:           "docs/spec/transactions/docs/spec/rum_v3_context.json",
+		Types:         []string{"number", "null"},
+		MinProperties: -1,
+		MaxProperties: -1,
+		MinItems:      -1,
+		MaxItems:      -1,
+		MinLength:     -1,
+		MaxLength:     -1,
+	}
+	s[26] = jsonschema.Schema{
+		URL:           "docs/spec/transactions/docs/spec/rum_v3_context.json",
+		Types:         []string{"object", "null"},
+		MinProperties: -1,
+		MaxProperties: -1,
+		Properties: map[string]*jsonschema.Schema{
+			"a":  &s[27],
+			"en": &s[30],
+			"fw": &s[31],
+			"la": &s[34],
+			"n":  &s[37],
+			"ru": &s[38],
+			"ve": &s[41],
+		},
+		MinItems:  -1,
+		MaxItems:  -1,
+		MinLength: -1,
+		MaxLength: -1,
+	}
+	s[27] = jsonschema.Schema{
+		URL:           "docs/spec/transactions/docs/spec/docs/spec/rum_v3_service.json",
+		Types:         []string{"object", "null"},
+		MinProperties: -1,
+		MaxProperties: -1,
+		Properties: map[string]*jsonschema.Schema{
+			"n":  &s[28],
+			"ve": &s[29],
+		},
+		MinItems:  -1,
+		MaxItems:  -1,
+		MinLength: -1,
+		MaxLength: -1,
+	}
+	s[28] = jsonschema.Schema{
+		URL:           "docs/spec/transactions/docs/spec/docs/spec/rum_v3_service.json",
+		Types:         []string{"string", "null"},
+		MinProperties: -1,
+		MaxProperties: -1,
+		MinItems:      -1,
+		MaxItems:      -1,
+		MinLength:     -1,
+		MaxLength:     1024,
+	}
+	s[29] = jsonschema.Schema{
+		URL:           "docs/spec/transactions/docs/spec/docs/spec/rum_v3_service.json",
+		Types:         []string{"string", "null"},
+		MinProperties: -1,
+		MaxProperties: -1,
+		MinItems:      -1,
+		MaxItems:      -1,
+		MinLength:     -1,
+		MaxLength:     1024,
+	}
+	s[30] = jsonschema.Schema{
+		URL:           "docs/spec/transactions/docs/spec/docs/spec/rum_v3_service.json",
+		Types:         []string{"string", "null"},
+		MinProperties: -1,
+		MaxProperties: -1,
+		MinItems:      -1,
+		MaxItems:      -1,
+		MinLength:     -1,
+		MaxLength:     1024,
+	}
+	s[31] = jsonschema.Schema{
+		URL:           "docs/spec/transactions/docs/spec/docs/spec/rum_v3_service.json",
+		Types:         []string{"object", "null"},
+		MinProperties: -1,
+		MaxProperties: -1,
+		Properties: map[string]*jsonschema.Schema{
+			"n":  &s[32],
+			"ve": &s[33],
+		},
+		MinItems:  -1,
+		MaxItems:  -1,
+		MinLength: -1,
+		MaxLength: -1,
+	}
+	s[32] = jsonschema.Schema{
+		URL:           "docs/spec/transactions/docs/spec/docs/spec/rum_v3_service.json",
+		Types:         []string{"string", "null"},
+		MinProperties: -1,
+		MaxProperties: -1,
+		MinItems:      -1,
+		MaxItems:      -1,
+		MinLength:     -1,
+		MaxLength:     1024,
+	}
+	s[33] = jsonschema.Schema{
+		URL:           "docs/spec/transactions/docs/spec/docs/spec/rum_v3_service.json",
+		Types:         []string{"string", "null"},
+		MinProperties: -1,
+		MaxProperties: -1,
+		MinItems:      -1,
+		MaxItems:      -1,
+		MinLength:     -1,
+		MaxLength:     1024,
+	}
+	s[34] = jsonschema.Schema{
+		URL:           "docs/spec/transactions/docs/spec/docs/spec/rum_v3_service.json",
+		Types:         []string{"object", "null"},
+		MinProperties: -1,
+		MaxProperties: -1,
+		Properties: map[string]*jsonschema.Schema{
+			"n":  &s[35],
+			"ve": &s[36],
+		},
+		MinItems:  -1,
+		MaxItems:  -1,
+		MinLength: -1,
+		MaxLength: -1,
+	}
+	s[35] = jsonschema.Schema{
+		URL:           "docs/spec/transactions/docs/spec/docs/spec/rum_v3_service.json",
+		Types:         []string{"string", "null"},
+		MinProperties: -1,
+		MaxProperties: -1,
+		MinItems:      -1,
+		MaxItems:      -1,
+		MinLength:     -1,
+		MaxLength:     1024,
+	}
+	s[36] = jsonschema.Schema{
+		URL:           "docs/spec/transactions/docs/spec/docs/spec/rum_v3_service.json",
+		Types:         []string{"string", "null"},
+		MinProperties: -1,
+		MaxProperties: -1,
+		MinItems:      -1,
+		MaxItems:      -1,
+		MinLength:     -1,
+		MaxLength:     1024,
+	}
+	s[37] = jsonschema.Schema{
+		URL:           "docs/spec/transactions/docs/spec/docs/spec/rum_v3_service.json",
+		Types:         []string{"string", "null"},
+		MinProperties: -1,
+		MaxProperties: -1,
+		MinItems:      -1,
+		MaxItems:      -1,
+		MinLength:     -1,
+		MaxLength:     1024,
+		Pattern:       regexp.MustCompile("^[a-zA-Z0-9 _-]+$"),
+	}
+	s[38] = jsonschema.Schema{
+		URL:           "docs/spec/transactions/docs/spec/docs/spec/rum_v3_service.json",
+		Types:         []string{"object", "null"},
+		MinProperties: -1,
+		MaxProperties: -1,
+		Properties: map[string]*jsonschema.Schema{
+			"n":  &s[39],
+			"ve": &s[40],
+		},
+		MinItems:  -1,
+		MaxItems:  -1,
+		MinLength: -1,
+		MaxLength: -1,
+	}
+	s[39] = jsonschema.Schema{
+		URL:           "docs/spec/transactions/docs/spec/docs/spec/rum_v3_service.json",
+		Types:         []string{"string", "null"},
+		MinProperties: -1,
+		MaxProperties: -1,
+		MinItems:      -1,
+		MaxItems:      -1,
+		MinLength:     -1,
+		MaxLength:     1024,
+	}
+	s[40] = jsonschema.Schema{
+		URL:           "docs/spec/transactions/docs/spec/docs/spec/rum_v3_service.json",
+		Types:         []string{"string", "null"},
+		MinProperties: -1,
+		MaxProperties: -1,
+		MinItems:      -1,
+		MaxItems:      -1,
+		MinLength:     -1,
+		MaxLength:     1024,
+	}
+	s[41] = jsonschema.Schema{
+		URL:           "docs/spec/transactions/docs/spec/docs/spec/rum_v3_service.json",
+		Types:         []string{"string", "null"},
+		MinProperties: -1,
+		MaxProperties: -1,
+		MinItems:      -1,
+		MaxItems:      -1,
+		MinLength:     -1,
+		MaxLength:     1024,
+	}
+	s[42] = jsonschema.Schema{
+		URL:           "docs/spec/transactions/docs/spec/rum_v3_context.json",
+		Types:         []string{"object", "null"},
+		MinProperties: -1,
+		MaxProperties: -1,
+		Properties: map[string]*jsonschema.Schema{
+			"em": &s[43],
+			"id": &s[44],
+			"un": &s[45],
+		},
+		MinItems:  -1,
+		MaxItems:  -1,
+		MinLength: -1,
+		MaxLength: -1,
+	}
+	s[43] = jsonschema.Schema{
+		URL:           "docs/spec/transactions/docs/spec/docs/spec/rum_v3_user.json",
+		Types:         []string{"string", "null"},
+		MinProperties: -1,
+		MaxProperties: -1,
+		MinItems:      -1,
+		MaxItems:      -1,
+		MinLength:     -1,
+		MaxLength:     1024,
+	}
+	s[44] = jsonschema.Schema{
+		URL:           "docs/spec/transactions/docs/spec/docs/spec/rum_v3_user.json",
+		Types:         []string{"string", "integer", "null"},
+		MinProperties: -1,
+		MaxProperties: -1,
+		MinItems:      -1,
+		MaxItems:      -1,
+		MinLength:     -1,
+		MaxLength:     1024,
+	}
+	s[45] = jsonschema.Schema{
+		URL:           "docs/spec/transactions/docs/spec/docs/spec/rum_v3_user.json",
+		Types:         []string{"string", "null"},
+		MinProperties: -1,
+		MaxProperties: -1,
+		MinItems:      -1,
+		MaxItems:      -1,
+		MinLength:     -1,
+		MaxLength:     1024,
+	}
+	s[46] = jsonschema.Schema{
+		URL:           "docs/spec/transactions/rum_v3_transaction.json",
+		Types:         []string{"number"},
+		MinProperties: -1,
+		MaxProperties: -1,
+		MinItems:      -1,
+		MaxItems:      -1,
+		MinLength:     -1,
+		MaxLength:     -1,
+		Minimum:       func() *big.Float { f, _ := new(big.Float).SetString("0"); return f }(),
+	}
+	s[47] = jsonschema.Schema{
+		URL:           "docs/spec/transactions/rum_v3_transaction.json",
+		Types:         []string{"string"},
+		MinProperties: -1,
+		MaxProperties: -1,
+		MinItems:      -1,
+		MaxItems:      -1,
+		MinLength:     -1,
+		MaxLength:     1024,
+	}
+	s[48] = jsonschema.Schema{
+		URL:           "docs/spec/transactions/rum_v3_transaction.json",
+		Types:         []string{"object", "null"},
+		MinProperties: -1,
+		MaxProperties: -1,
+		Properties: map[string]*jsonschema.Schema{
+			"a":  &s[49],
+			"nt": &s[57],
+		},
+		MinItems:  -1,
+		MaxItems:  -1,
+		MinLength: -1,
+		MaxLength: -1,
+	}
+	s[49] = jsonschema.Schema{
+		URL:           "docs/spec/transactions/docs/spec/transactions/rum_v3_mark.json",
+		Types:         []string{"object", "null"},
+		MinProperties: -1,
+		MaxProperties: -1,
+		Properties: map[string]*jsonschema.Schema{
+			"dc": &s[50],
+			"de": &s[51],
+			"di": &s[52],
+			"ds": &s[53],
+			"fb": &s[54],
+			"fp": &s[55],
+			"lp": &s[56],
+		},
+		MinItems:  -1,
+		MaxItems:  -1,
+		MinLength: -1,
+		MaxLength: -1,
+	}
+	s[50] = jsonschema.Schema{
+		URL:           "docs/spec/transactions/docs/spec/transactions/rum_v3_mark.json",
+		Types:         []string{"number", "null"},
+		MinProperties: -1,
+		MaxProperties: -1,
+		MinItems:      -1,
+		MaxItems:      -1,
+		MinLength:     -1,
+		MaxLength:     -1,
+	}
+	s[51] = jsonschema.Schema{
+		URL:           "docs/spec/transactions/docs/spec/transactions/rum_v3_mark.json",
+		Types:         []string{"number", "null"},
+		MinProperties: -1,
+		MaxProperties: -1,
+		MinItems:      -1,
+		MaxItems:      -1,
+		MinLength:     -1,
+		MaxLength:     -1,
+	}
+	s[52] = jsonschema.Schema{
+		URL:           "docs/spec/transactions/docs/spec/transactions/rum_v3_mark.json",
+		Types:         []string{"number", "null"},
+		MinProperties: -1,
+		MaxProperties: -1,
+		MinItems:      -1,
+		MaxItems:      -1,
+		MinLength:     -1,
+		MaxLength:     -1,
+	}
+	s[53] = jsonschema.Schema{
+		URL:           "docs/spec/transactions/docs/spec/transactions/rum_v3_mark.json",
+		Types:         []string{"number", "null"},
+		MinProperties: -1,
+		MaxProperties: -1,
+		MinItems:      -1,
+		MaxItems:      -1,
+		MinLength:     -1,
+		MaxLength:     -1,
+	}
+	s[54] = jsonschema.Schema{
+		URL:           "docs/spec/transactions/docs/spec/transactions/rum_v3_mark.json",
+		Types:         []string{"number", "null"},
+		MinProperties: -1,
+		MaxProperties: -1,
+		MinItems:      -1,
+		MaxItems:      -1,
+		MinLength:     -1,
+		MaxLength:     -1,
+	}
+	s[55] = jsonschema.Schema{
+		URL:           "docs/spec/transactions/docs/spec/transactions/rum_v3_mark.json",
+		Types:         []string{"number", "null"},
+		MinProperties: -1,
+		MaxProperties: -1,
+		MinItems:      -1,
+		MaxItems:      -1,
+		MinLength:     -1,
+		MaxLength:     -1,
+	}
+	s[56] = jsonschema.Schema{
+		URL:           "docs/spec/transactions/docs/spec/transactions/rum_v3_mark.json",
+		Types:         []string{"number", "null"},
+		MinProperties: -1,
+		MaxProperties: -1,
+		MinItems:      -1,
+		MaxItems:      -1,
+		MinLength:     -1,
+		MaxLength:     -1,
+	}
+	s[57] = jsonschema.Schema{
+		URL:           "docs/spec/transactions/docs/spec/transactions/rum_v3_mark.json",
+		Types:         []string{"object", "null"},
+		MinProperties: -1,
+		MaxProperties: -1,
+		Properties: map[string]*jsonschema.Schema{
+			"ce": &s[58],
+			"cs": &s[59],
+			"dc": &s[60],
+			"de": &s[61],
+			"di": &s[62],
+			"dl": &s[63],
+			"ds": &s[64],
+			"ee": &s[65],
+			"es": &s[66],
+			"fs": &s[67],
+			"le": &s[68],
+			"ls": &s[69],
+			"qs": &s[70],
+			"re": &s[71],
+			"rs": &s[72],
+		},
+		MinItems:  -1,
+		MaxItems:  -1,
+		MinLength: -1,
+		MaxLength: -1,
+	}
+	s[58] = jsonschema.Schema{
+		URL:           "docs/spec/transactions/docs/spec/transactions/rum_v3_mark.json",
+		Types:         []string{"number", "null"},
+		MinProperties: -1,
+		MaxProperties: -1,
+		MinItems:      -1,
+		MaxItems:      -1,
+		MinLength:     -1,
+		MaxLength:     -1,
+	}
+	s[59] = jsonschema.Schema{
+		URL:           "docs/spec/transactions/docs/spec/transactions/rum_v3_mark.json",
+		Types:         []string{"number", "null"},
+		MinProperties: -1,
+		MaxProperties: -1,
+		MinItems:      -1,
+		MaxItems:      -1,
+		MinLength:     -1,
+		MaxLength:     -1,
+	}
+	s[60] = jsonschema.Schema{
+		URL:           "docs/spec/transactions/docs/spec/transactions/rum_v3_mark.json",
+		Types:         []string{"number", "null"},
+		MinProperties: -1,
+		MaxProperties: -1,
+		MinItems:      -1,
+		MaxItems:      -1,
+		MinLength:     -1,
+		MaxLength:     -1,
+	}
+	s[61] = jsonschema.Schema{
+		URL:           "docs/spec/transactions/docs/spec/transactions/rum_v3_mark.json",
+		Types:         []string{"number", "null"},
+		MinProperties: -1,
+		MaxProperties: -1,
+		MinItems:      -1,
+		MaxItems:      -1,
+		MinLength:     -1,
+		MaxLength:     -1,
+	}
+	s[62] = jsonschema.Schema{
+		URL:           "docs/spec/transactions/docs/spec/transactions/rum_v3_mark.json",
+		Types:         []string{"number", "null"},
+		MinProperties: -1,
+		MaxProperties: -1,
+		MinItems:      -1,
+		MaxItems:      -1,
+		MinLength:     -1,
+		MaxLength:     -1,
+	}
+	s[63] = jsonschema.Schema{
+		URL:           "docs/spec/transactions/docs/spec/transactions/rum_v3_mark.json",
+		Types:         []string{"number", "null"},
+		MinProperties: -1,
+		MaxProperties: -1,
+		MinItems:      -1,
+		MaxItems:      -1,
+		MinLength:     -1,
+		MaxLength:     -1,
+	}
+	s[64] = jsonschema.Schema{
+		URL:           "docs/spec/transactions/docs/spec/transactions/rum_v3_mark.json",
+		Types:         []string{"number", "null"},
+		MinProperties: -1,
+		MaxProperties: -1,
+		MinItems:      -1,
+		MaxItems:      -1,
+		MinLength:     -1,
+		MaxLength:     -1,
+	}
+	s[65] = jsonschema.Schema{
+		URL:           "docs/spec/transactions/docs/spec/transactions/rum_v3_mark.json",
+		Types:         []string{"number", "null"},
+		MinProperties: -1,
+		MaxProperties: -1,
+		MinItems:      -1,
+		MaxItems:      -1,
+		MinLength:     -1,
+		MaxLength:     -1,
+	}
+	s[66] = jsonschema.Schema{
+		URL:           "docs/spec/transactions/docs/spec/transactions/rum_v3_mark.json",
+		Types:         []string{"number", "null"},
+		MinProperties: -1,
+		MaxProperties: -1,
+		MinItems:      -1,
+		MaxItems:      -1,
+		MinLength:     -1,
+		MaxLength:     -1,
+	}
+	s[67] = jsonschema.Schema{
+		URL:           "docs/spec/transactions/docs/spec/transactions/rum_v3_mark.json",
+		Types:         []string{"number", "null"},
+		MinProperties: -1,
+		MaxProperties: -1,
+		MinItems:      -1,
+		MaxItems:      -1,
+		MinLength:     -1,
+		MaxLength:     -1,
+	}
+	s[68] = jsonschema.Schema{
+		URL:           "docs/spec/transactions/docs/spec/transactions/rum_v3_mark.json",
+		Types:         []string{"number", "null"},
+		MinProperties: -1,
+		MaxProperties: -1,
+		MinItems:      -1,
+		MaxItems:      -1,
+		MinLength:     -1,
+		MaxLength:     -1,
+	}
+	s[69] = jsonschema.Schema{
+		URL:           "docs/spec/transactions/docs/spec/transactions/rum_v3_mark.json",
+		Types:         []string{"number", "null"},
+		MinProperties: -1,
+		MaxProperties: -1,
+		MinItems:      -1,
+		MaxItems:      -1,
+		MinLength:     -1,
+		MaxLength:     -1,
+	}
+	s[70] = jsonschema.Schema{
+		URL:           "docs/spec/transactions/docs/spec/transactions/rum_v3_mark.json",
+		Types:         []string{"number", "null"},
+		MinProperties: -1,
+		MaxProperties: -1,
+		MinItems:      -1,
+		MaxItems:      -1,
+		MinLength:     -1,
+		MaxLength:     -1,
+	}
+	s[71] = jsonschema.Schema{
+		URL:           "docs/spec/transactions/docs/spec/transactions/rum_v3_mark.json",
+		Types:         []string{"number", "null"},
+		MinProperties: -1,
+		MaxProperties: -1,
+		MinItems:      -1,
+		MaxItems:      -1,
+		MinLength:     -1,
+		MaxLength:     -1,
+	}
+	s[72] = jsonschema.Schema{
+		URL:           "docs/spec/transactions/docs/spec/transactions/rum_v3_mark.json",
+		Types:         []string{"number", "null"},
+		MinProperties: -1,
+		MaxProperties: -1,
+		MinItems:      -1,
+		MaxItems:      -1,
+		MinLength:     -1,
+		MaxLength:     -1,
+	}
+	s[73] = jsonschema.Schema{
+		URL:           "docs/spec/transactions/rum_v3_transaction.json",
+		Types:         []string{"array", "null"},
+		MinProperties: -1,
+		MaxProperties: -1,
+		Required:      []string{"sa"},
+		Properties: map[string]*jsonschema.Schema{
+			"g":  &s[74],
+			"sa": &s[76],
+			"y":  &s[87],
+		},
+		MinItems:  -1,
+		MaxItems:  -1,
+		MinLength: -1,
+		MaxLength: -1,
+	}
+	s[74] = jsonschema.Schema{
+		URL:           "docs/spec/transactions/docs/spec/metricsets/rum_v3_metricset.json",
+		Types:         []string{"object", "null"},
+		MinProperties: -1,
+		MaxProperties: -1,
+		PatternProperties: map[*regexp.Regexp]*jsonschema.Schema{
+			regexp.MustCompile("^[^.*\"]*$"): &s[75],
+		},
+		AdditionalProperties: false,
+		MinItems:             -1,
+		MaxItems:             -1,
+		MinLength:            -1,
+		MaxLength:            -1,
+	}
+	s[75] = jsonschema.Schema{
+		URL:           "docs/spec/transactions/docs/spec/metricsets/docs/spec/tags.json",
+		Types:         []string{"string", "boolean", "number", "null"},
+		MinProperties: -1,
+		MaxProperties: -1,
+		MinItems:      -1,
+		MaxItems:      -1,
+		MinLength:     -1,
+		MaxLength:     1024,
+	}
+	s[76] = jsonschema.Schema{
+		URL:           "docs/spec/transactions/docs/spec/metricsets/rum_v3_metricset.json",
+		Types:         []string{"object"},
+		MinProperties: -1,
+		MaxProperties: -1,
+		Properties: map[string]*jsonschema.Schema{
+			"xbc": &s[77],
+			"xdc": &s[79],
+			"xds": &s[81],
+			"ysc": &s[83],
+			"yss": &s[85],
+		},
+		MinItems:  -1,
+		MaxItems:  -1,
+		MinLength: -1,
+		MaxLength: -1,
+	}
+	s[77] = jsonschema.Schema{
+		URL:           "docs/spec/transactions/docs/spec/metricsets/rum_v3_metricset.json",
+		Types:         []string{"object", "null"},
+		MinProperties: -1,
+		MaxProperties: -1,
+		Required:      []string{"v"},
+		Properties: map[string]*jsonschema.Schema{
+			"v": &s[78],
+		},
+		MinItems:  -1,
+		MaxItems:  -1,
+		MinLength: -1,
+		MaxLength: -1,
+	}
+	s[78] = jsonschema.Schema{
+		URL:           "docs/spec/transactions/docs/spec/metricsets/docs/spec/metricsets/rum_v3_sample.json",
+		Types:         []string{"number"},
+		MinProperties: -1,
+		MaxProperties: -1,
+		MinItems:      -1,
+		MaxItems:      -1,
+		MinLength:     -1,
+		MaxLength:     -1,
+	}
+	s[79] = jsonschema.Schema{
+		URL:           "docs/spec/transactions/docs/spec/metricsets/rum_v3_metricset.json",
+		Types:         []string{"object", "null"},
+		MinProperties: -1,
+		MaxProperties: -1,
+		Required:      []string{"v"},
+		Properties: map[string]*jsonschema.Schema{
+			"v": &s[80],
+		},
+		MinItems:  -1,
+		MaxItems:  -1,
+		MinLength: -1,
+		MaxLength: -1,
+	}
+	s[80] = jsonschema.Schema{
+		URL:           "docs/spec/transactions/docs/spec/metricsets/docs/spec/metricsets/rum_v3_sample.json",
+		Types:         []string{"number"},
+		MinProperties: -1,
+		MaxProperties: -1,
+		MinItems:      -1,
+		MaxItems:      -1,
+		MinLength:     -1,
+		MaxLength:     -1,
+	}
+	s[81] = jsonschema.Schema{
+		URL:           "docs/spec/transactions/docs/spec/metricsets/rum_v3_metricset.json",
+		Types:         []string{"object", "null"},
+		MinProperties: -1,
+		MaxProperties: -1,
+		Required:      []string{"v"},
+		Properties: map[string]*jsonschema.Schema{
+			"v": &s[82],
+		},
+		MinItems:  -1,
+		MaxItems:  -1,
+		MinLength: -1,
+		MaxLength: -1,
+	}
+	s[82] = jsonschema.Schema{
+		URL:           "docs/spec/transactions/docs/spec/metricsets/docs/spec/metricsets/rum_v3_sample.json",
+		Types:         []string{"number"},
+		MinProperties: -1,
+		MaxProperties: -1,
+		MinItems:      -1,
+		MaxItems:      -1,
+		MinLength:     -1,
+		MaxLength:     -1,
+	}
+	s[83] = jsonschema.Schema{
+		URL:           "docs/spec/transactions/docs/spec/metricsets/rum_v3_metricset.json",
+		Types:         []string{"object", "null"},
+		MinProperties: -1,
+		MaxProperties: -1,
+		Required:      []string{"v"},
+		Properties: map[string]*jsonschema.Schema{
+			"v": &s[84],
+		},
+		MinItems:  -1,
+		MaxItems:  -1,
+		MinLength: -1,
+		MaxLength: -1,
+	}
+	s[84] = jsonschema.Schema{
+		URL:           "docs/spec/transactions/docs/spec/metricsets/docs/spec/metricsets/rum_v3_sample.json",
+		Types:         []string{"number"},
+		MinProperties: -1,
+		MaxProperties: -1,
+		MinItems:      -1,
+		MaxItems:      -1,
+		MinLength:     -1,
+		MaxLength:     -1,
+	}
+	s[85] = jsonschema.Schema{
+		URL:           "docs/spec/transactions/docs/spec/metricsets/rum_v3_metricset.json",
+		Types:         []string{"object", "null"},
+		MinProperties: -1,
+		MaxProperties: -1,
+		Required:      []string{"v"},
+		Properties: map[string]*jsonschema.Schema{
+			"v": &s[86],
+		},
+		MinItems:  -1,
+		MaxItems:  -1,
+		MinLength: -1,
+		MaxLength: -1,
+	}
+	s[86] = jsonschema.Schema{
+		URL:           "docs/spec/transactions/docs/spec/metricsets/docs/spec/metricsets/rum_v3_sample.json",
+		Types:         []string{"number"},
+		MinProperties: -1,
+		MaxProperties: -1,
+		MinItems:      -1,
+		MaxItems:      -1,
+		MinLength:     -1,
+		MaxLength:     -1,
+	}
+	s[87] = jsonschema.Schema{
+		URL:           "docs/spec/transactions/docs/spec/metricsets/rum_v3_metricset.json",
+		Types:         []string{"object", "null"},
+		MinProperties: -1,
+		MaxProperties: -1,
+		Properties: map[string]*jsonschema.Schema{
+			"su": &s[88],
+			"t":  &s[89],
+		},
+		MinItems:  -1,
+		MaxItems:  -1,
+		MinLength: -1,
+		MaxLength: -1,
+	}
+	s[88] = jsonschema.Schema{
+		URL:           "docs/spec/transactions/docs/spec/metricsets/rum_v3_metricset.json",
+		Types:         []string{"string", "null"},
+		MinProperties: -1,
+		MaxProperties: -1,
+		MinItems:      -1,
+		MaxItems:      -1,
+		MinLength:     -1,
+		MaxLength:     1024,
+	}
+	s[89] = jsonschema.Schema{
+		URL:           "docs/spec/transactions/docs/spec/metricsets/rum_v3_metricset.json",
+		Types:         []string{"string"},
+		MinProperties: -1,
+		MaxProperties: -1,
+		MinItems:      -1,
+		MaxItems:      -1,
+		MinLength:     -1,
+		MaxLength:     1024,
+	}
+	s[90] = jsonschema.Schema{
+		URL:           "docs/spec/transactions/rum_v3_transaction.json",
+		Types:         []string{"string", "null"},
+		MinProperties: -1,
+		MaxProperties: -1,
+		MinItems:      -1,
+		MaxItems:      -1,
+		MinLength:     -1,
+		MaxLength:     1024,
+	}
+	s[91] = jsonschema.Schema{
+		URL:           "docs/spec/transactions/rum_v3_transaction.json",
+		Types:         []string{"string", "null"},
+		MinProperties: -1,
+		MaxProperties: -1,
+		MinItems:      -1,
+		MaxItems:      -1,
+		MinLength:     -1,
+		MaxLength:     1024,
+	}
+	s[92] = jsonschema.Schema{
+		URL:           "docs/spec/transactions/rum_v3_transaction.json",
+		Types:         []string{"string", "null"},
+		MinProperties: -1,
+		MaxProperties: -1,
+		MinItems:      -1,
+		MaxItems:      -1,
+		MinLength:     -1,
+		MaxLength:     1024,
+	}
+	s[93] = jsonschema.Schema{
+		URL:           "docs/spec/transactions/rum_v3_transaction.json",
+		Types:         []string{"boolean", "null"},
+		MinProperties: -1,
+		MaxProperties: -1,
+		MinItems:      -1,
+		MaxItems:      -1,
+		MinLength:     -1,
+		MaxLength:     -1,
+	}
+	s[94] = jsonschema.Schema{
+		URL:           "docs/spec/transactions/rum_v3_transaction.json",
+		Types:         []string{"string"},
+		MinProperties: -1,
+		MaxProperties: -1,
+		MinItems:      -1,
+		MaxItems:      -1,
+		MinLength:     -1,
+		MaxLength:     1024,
+	}
+	s[95] = jsonschema.Schema{
+		URL:           "docs/spec/transactions/rum_v3_transaction.json",
+		Types:         []string{"string"},
+		MinProperties: -1,
+		MaxProperties: -1,
+		MinItems:      -1,
+		MaxItems:      -1,
+		MinLength:     -1,
+		MaxLength:     1024,
+	}
+	s[96] = jsonschema.Schema{
+		URL:           "docs/spec/transactions/rum_v3_transaction.json",
+		Types:         []string{"array", "null"},
+		AllOf:         []*jsonschema.Schema{&s[97], &s[140]},
+		MinProperties: -1,
+		MaxProperties: -1,
+		MinItems:      -1,
+		MaxItems:      -1,
+		MinLength:     -1,
+		MaxLength:     -1,
+	}
+	s[97] = jsonschema.Schema{
+		URL:           "docs/spec/transactions/docs/spec/spans/rum_v3_span.json",
+		MinProperties: -1,
+		MaxProperties: -1,
+		Required:      []string{"d", "n", "t", "id"},
+		Properties: map[string]*jsonschema.Schema{
+			"ac": &s[98],
+			"c":  &s[99],
+			"d":  &s[118],
+			"id": &s[119],
+			"n":  &s[120],
+			"pi": &s[121],
+			"s":  &s[122],
+			"st": &s[123],
+			"su": &s[137],
+			"sy": &s[138],
+			"t":  &s[139],
+		},
+		MinItems:  -1,
+		MaxItems:  -1,
+		MinLength: -1,
+		MaxLength: -1,
+	}
+	s[98] = jsonschema.Schema{
+		URL:           "docs/spec/transactions/docs/spec/spans/rum_v3_span.json",
+		Types:         []string{"string", "null"},
+		MinProperties: -1,
+		MaxProperties: -1,
+		MinItems:      -1,
+		MaxItems:      -1,
+		MinLength:     -1,
+		MaxLength:     1024,
+	}
+	s[99] = jsonschema.Schema{
+		URL:           "docs/spec/transactions/docs/spec/spans/rum_v3_span.json",
+		Types:         []string{"object", "null"},
+		MinProperties: -1,
+		MaxProperties: -1,
+		Properties: map[string]*jsonschema.Schema{
+			"dt": &s[100],
+			"g":  &s[107],
+			"h":  &s[109],
+			"se": &s[113],
+		},
+		MinItems:  -1,
+		MaxItems:  -1,
+		MinLength: -1,
+		MaxLength: -1,
+	}
+	s[100] = jsonschema.Schema{
+		URL:           "docs/spec/transactions/docs/spec/spans/rum_v3_span.json",
+		Types:         []string{"object", "null"},
+		MinProperties: -1,
+		MaxProperties: -1,
+		Properties: map[string]*jsonschema.Schema{
+			"ad": &s[101],
+			"po": &s[102],
+			"se": &s[103],
+		},
+		MinItems:  -1,
+		MaxItems:  -1,
+		MinLength: -1,
+		MaxLength: -1,
+	}
+	s[101] = jsonschema.Schema{
+		URL:           "docs/spec/transactions/docs/spec/spans/rum_v3_span.json",
+		Types:         []string{"string", "null"},
+		MinProperties: -1,
+		MaxProperties: -1,
+		MinItems:      -1,
+		MaxItems:      -1,
+		MinLength:     -1,
+		MaxLength:     1024,
+	}
+	s[102] = jsonschema.Schema{
+		URL:           "docs/spec/transactions/docs/spec/spans/rum_v3_span.json",
+		Types:         []string{"integer", "null"},
+		MinProperties: -1,
+		MaxProperties: -1,
+		MinItems:      -1,
+		MaxItems:      -1,
+		MinLength:     -1,
+		MaxLength:     -1,
+	}
+	s[103] = jsonschema.Schema{
+		URL:           "docs/spec/transactions/docs/spec/spans/rum_v3_span.json",
+		Types:         []string{"object", "null"},
+		MinProperties: -1,
+		MaxProperties: -1,
+		Required:      []string{"t", "n", "rc"},
+		Properties: map[string]*jsonschema.Schema{
+			"n":  &s[104],
+			"rc": &s[105],
+			"t":  &s[106],
+		},
+		MinItems:  -1,
+		MaxItems:  -1,
+		MinLength: -1,
+		MaxLength: -1,
+	}
+	s[104] = jsonschema.Schema{
+		URL:           "docs/spec/transactions/docs/spec/spans/rum_v3_span.json",
+		Types:         []string{"string", "null"},
+		MinProperties: -1,
+		MaxProperties: -1,
+		MinItems:      -1,
+		MaxItems:      -1,
+		MinLength:     -1,
+		MaxLength:     1024,
+	}
+	s[105] = jsonschema.Schema{
+		URL:           "docs/spec/transactions/docs/spec/spans/rum_v3_span.json",
+		Types:         []string{"string", "null"},
+		MinProperties: -1,
+		MaxProperties: -1,
+		MinItems:      -1,
+		MaxItems:      -1,
+		MinLength:     -1,
+		MaxLength:     1024,
+	}
+	s[106] = jsonschema.Schema{
+		URL:           "docs/spec/transactions/docs/spec/spans/rum_v3_span.json",
+		Types:         []string{"string", "null"},
+		MinProperties: -1,
+		MaxProperties: -1,
+		MinItems:      -1,
+		MaxItems:      -1,
+		MinLength:     -1,
+		MaxLength:     1024,
+	}
+	s[107] = jsonschema.Schema{
+		URL:           "docs/spec/transactions/docs/spec/spans/rum_v3_span.json",
+		Types:         []string{"object", "null"},
+		MinProperties: -1,
+		MaxProperties: -1,
+		PatternProperties: map[*regexp.Regexp]*jsonschema.Schema{
+			regexp.MustCompile("^[^.*\"]*$"): &s[108],
+		},
+		AdditionalProperties: false,
+		MinItems:             -1,
+		MaxItems:             -1,
+		MinLength:            -1,
+		MaxLength:            -1,
+	}
+	s[108] = jsonschema.Schema{
+		URL:           "docs/spec/transactions/docs/spec/spans/docs/spec/tags.json",
+		Types:         []string{"string", "boolean", "number", "null"},
+		MinProperties: -1,
+		MaxProperties: -1,
+		MinItems:      -1,
+		MaxItems:      -1,
+		MinLength:     -1,
+		MaxLength:     1024,
+	}
+	s[109] = jsonschema.Schema{
+		URL:           "docs/spec/transactions/docs/spec/spans/rum_v3_span.json",
+		Types:         []string{"object", "null"},
+		MinProperties: -1,
+		MaxProperties: -1,
+		Properties: map[string]*jsonschema.Schema{
+			"mt":  &s[110],
+			"sc":  &s[111],
+			"url": &s[112],
+		},
+		MinItems:  -1,
+		MaxItems:  -1,
+		MinLength: -1,
+		MaxLength: -1,
+	}
+	s[110] = jsonschema.Schema{
+		URL:           "docs/spec/transactions/docs/spec/spans/rum_v3_span.json",
+		Types:         []string{"string", "null"},
+		MinProperties: -1,
+		MaxProperties: -1,
+		MinItems:      -1,
+		MaxItems:      -1,
+		MinLength:     -1,
+		MaxLength:     1024,
+	}
+	s[111] = jsonschema.Schema{
+		URL:           "docs/spec/transactions/docs/spec/spans/rum_v3_span.json",
+		Types:         []string{"integer", "null"},
+		MinProperties: -1,
+		MaxProperties: -1,
+		MinItems:      -1,
+		MaxItems:      -1,
+		MinLength:     -1,
+		MaxLength:     -1,
+	}
+	s[112] = jsonschema.Schema{
+		URL:           "docs/spec/transactions/docs/spec/spans/rum_v3_span.json",
+		Types:         []string{"string", "null"},
+		MinProperties: -1,
+		MaxProperties: -1,
+		MinItems:      -1,
+		MaxItems:      -1,
+		MinLength:     -1,
+		MaxLength:     -1,
+	}
+	s[113] = jsonschema.Schema{
+		URL:           "docs/spec/transactions/docs/spec/spans/rum_v3_span.json",
+		MinProperties: -1,
+		MaxProperties: -1,
+		Properties: map[string]*jsonschema.Schema{
+			"a": &s[114],
+			"n": &s[117],
+		},
+		MinItems:  -1,
+		MaxItems:  -1,
+		MinLength: -1,
+		MaxLength: -1,
+	}
+	s[114] = jsonschema.Schema{
+		URL:           "docs/spec/transactions/docs/spec/spans/rum_v3_span.json",
+		Types:         []string{"object", "null"},
+		MinProperties: -1,
+		MaxProperties: -1,
+		Properties: map[string]*jsonschema.Schema{
+			"n":  &s[115],
+			"ve": &s[116],
+		},
+		MinItems:  -1,
+		MaxItems:  -1,
+		MinLength: -1,
+		MaxLength: -1,
+	}
+	s[115] = jsonschema.Schema{
+		URL:           "docs/spec/transactions/docs/spec/spans/rum_v3_span.json",
+		Types:         []string{"string", "null"},
+		MinProperties: -1,
+		MaxProperties: -1,
+		MinItems:      -1,
+		MaxItems:      -1,
+		MinLength:     -1,
+		MaxLength:     1024,
+	}
+	s[116] = jsonschema.Schema{
+		URL:           "docs/spec/transactions/docs/spec/spans/rum_v3_span.json",
+		Types:         []string{"string", "null"},
+		MinProperties: -1,
+		MaxProperties: -1,
+		MinItems:      -1,
+		MaxItems:      -1,
+		MinLength:     -1,
+		MaxLength:     1024,
+	}
+	s[117] = jsonschema.Schema{
+		URL:           "docs/spec/transactions/docs/spec/spans/rum_v3_span.json",
+		Types:         []string{"string", "null"},
+		MinProperties: -1,
+		MaxProperties: -1,
+		MinItems:      -1,
+		MaxItems:      -1,
+		MinLength:     -1,
+		MaxLength:     1024,
+		Pattern:       regexp.MustCompile("^[a-zA-Z0-9 _-]+$"),
+	}
+	s[118] = jsonschema.Schema{
+		URL:           "docs/spec/transactions/docs/spec/spans/rum_v3_span.json",
+		Types:         []string{"number"},
+		MinProperties: -1,
+		MaxProperties: -1,
+		MinItems:      -1,
+		MaxItems:      -1,
+		MinLength:     -1,
+		MaxLength:     -1,
+		Minimum:       func() *big.Float { f, _ := new(big.Float).SetString("0"); return f }(),
+	}
+	s[119] = jsonschema.Schema{
+		URL:           "docs/spec/transactions/docs/spec/spans/rum_v3_span.json",
+		Types:         []string{"string"},
+		MinProperties: -1,
+		MaxProperties: -1,
+		MinItems:      -1,
+		MaxItems:      -1,
+		MinLength:     -1,
+		MaxLength:     1024,
+	}
+	s[120] = jsonschema.Schema{
+		URL:           "docs/spec/transactions/docs/spec/spans/rum_v3_span.json",
+		Types:         []string{"string"},
+		MinProperties: -1,
+		MaxProperties: -1,
+		MinItems:      -1,
+		MaxItems:      -1,
+		MinLength:     -1,
+		MaxLength:     1024,
+	}
+	s[121] = jsonschema.Schema{
+		URL:           "docs/spec/transactions/docs/spec/spans/rum_v3_span.json",
+		Types:         []string{"integer", "null"},
+		MinProperties: -1,
+		MaxProperties: -1,
+		MinItems:      -1,
+		MaxItems:      -1,
+		MinLength:     -1,
+		MaxLength:     1024,
+	}
+	s[122] = jsonschema.Schema{
+		URL:           "docs/spec/transactions/docs/spec/spans/rum_v3_span.json",
+		Types:         []string{"number", "null"},
+		MinProperties: -1,
+		MaxProperties: -1,
+		MinItems:      -1,
+		MaxItems:      -1,
+		MinLength:     -1,
+		MaxLength:     -1,
+	}
+	s[123] = jsonschema.Schema{
+		URL:           "docs/spec/transactions/docs/spec/spans/rum_v3_span.json",
+		Types:         []string{"array", "null"},
+		MinProperties: -1,
+		MaxProperties: -1,
+		MinItems:      0,
+		MaxItems:      -1,
+		Items:         &s[124],
+		MinLength:     -1,
+		MaxLength:     -1,
+	}
+	s[124] = jsonschema.Schema{
+		URL:           "docs/spec/transactions/docs/spec/spans/rum_v3_span.json",
+		Types:         []string{"object"},
+		MinProperties: -1,
+		MaxProperties: -1,
+		Required:      []string{"f"},
+		Properties: map[string]*jsonschema.Schema{
+			"ap":  &s[125],
+			"cli": &s[126],
+			"cn":  &s[127],
+			"co":  &s[128],
+			"f":   &s[129],
+			"fn":  &s[130],
+			"li":  &s[131],
+			"mo":  &s[132],
+			"poc": &s[133],
+			"prc": &s[135],
+		},
+		MinItems:  -1,
+		MaxItems:  -1,
+		MinLength: -1,
+		MaxLength: -1,
+	}
+	s[125] = jsonschema.Schema{
+		URL:           "docs/spec/transactions/docs/spec/spans/docs/spec/rum_v3_stacktrace_frame.json",
+		Types:         []string{"string", "null"},
+		MinProperties: -1,
+		MaxProperties: -1,
+		MinItems:      -1,
+		MaxItems:      -1,
+		MinLength:     -1,
+		MaxLength:     -1,
+	}
+	s[126] = jsonschema.Schema{
+		URL:           "docs/spec/transactions/docs/spec/spans/docs/spec/rum_v3_stacktrace_frame.json",
+		Types:         []string{"string", "null"},
+		MinProperties: -1,
+		MaxProperties: -1,
+		MinItems:      -1,
+		MaxItems:      -1,
+		MinLength:     -1,
+		MaxLength:     -1,
+	}
+	s[127] = jsonschema.Schema{
+		URL:           "docs/spec/transactions/docs/spec/spans/docs/spec/rum_v3_stacktrace_frame.json",
+		Types:         []string{"string", "null"},
+		MinProperties: -1,
+		MaxProperties: -1,
+		MinItems:      -1,
+		MaxItems:      -1,
+		MinLength:     -1,
+		MaxLength:     -1,
+	}
+	s[128] = jsonschema.Schema{
+		URL:           "docs/spec/transactions/docs/spec/spans/docs/spec/rum_v3_stacktrace_frame.json",
+		Types:         []string{"integer", "null"},
+		MinProperties: -1,
+		MaxProperties: -1,
+		MinItems:      -1,
+		MaxItems:      -1,
+		MinLength:     -1,
+		MaxLength:     -1,
+	}
+	s[129] = jsonschema.Schema{
+		URL:           "docs/spec/transactions/docs/spec/spans/docs/spec/rum_v3_stacktrace_frame.json",
+		Types:         []string{"string", "null"},
+		MinProperties: -1,
+		MaxProperties: -1,
+		MinItems:      -1,
+		MaxItems:      -1,
+		MinLength:     -1,
+		MaxLength:     -1,
+	}
+	s[130] = jsonschema.Schema{
+		URL:           "docs/spec/transactions/docs/spec/spans/docs/spec/rum_v3_stacktrace_frame.json",
+		Types:         []string{"string", "null"},
+		MinProperties: -1,
+		MaxProperties: -1,
+		MinItems:      -1,
+		MaxItems:      -1,
+		MinLength:     -1,
+		MaxLength:     -1,
+	}
+	s[131] = jsonschema.Schema{
+		URL:           "docs/spec/transactions/docs/spec/spans/docs/spec/rum_v3_stacktrace_frame.json",
+		Types:         []string{"integer", "null"},
+		MinProperties: -1,
+		MaxProperties: -1,
+		MinItems:      -1,
+		MaxItems:      -1,
+		MinLength:     -1,
+		MaxLength:     -1,
+	}
+	s[132] = jsonschema.Schema{
+		URL:           "docs/spec/transactions/docs/spec/spans/docs/spec/rum_v3_stacktrace_frame.json",
+		Types:         []string{"string", "null"},
+		MinProperties: -1,
+		MaxProperties: -1,
+		MinItems:      -1,
+		MaxItems:      -1,
+		MinLength:     -1,
+		MaxLength:     -1,
+	}
+	s[133] = jsonschema.Schema{
+		URL:           "docs/spec/transactions/docs/spec/spans/docs/spec/rum_v3_stacktrace_frame.json",
+		Types:         []string{"array", "null"},
+		MinProperties: -1,
+		MaxProperties: -1,
+		MinItems:      0,
+		MaxItems:      -1,
+		Items:         &s[134],
+		MinLength:     -1,
+		MaxLength:     -1,
+	}
+	s[134] = jsonschema.Schema{
+		URL:           "docs/spec/transactions/docs/spec/spans/docs/spec/rum_v3_stacktrace_frame.json",
+		Types:         []string{"string"},
+		MinProperties: -1,
+		MaxProperties: -1,
+		MinItems:      -1,
+		MaxItems:      -1,
+		MinLength:     -1,
+		MaxLength:     -1,
+	}
+	s[135] = jsonschema.Schema{
+		URL:           "docs/spec/transactions/docs/spec/spans/docs/spec/rum_v3_stacktrace_frame.json",
+		Types:         []string{"array", "null"},
+		MinProperties: -1,
+		MaxProperties: -1,
+		MinItems:      0,
+		MaxItems:      -1,
+		Items:         &s[136],
+		MinLength:     -1,
+		MaxLength:     -1,
+	}
+	s[136] = jsonschema.Schema{
+		URL:           "docs/spec/transactions/docs/spec/spans/docs/spec/rum_v3_stacktrace_frame.json",
+		Types:         []string{"string"},
+		MinProperties: -1,
+		MaxProperties: -1,
+		MinItems:      -1,
+		MaxItems:      -1,
+		MinLength:     -1,
+		MaxLength:     -1,
+	}
+	s[137] = jsonschema.Schema{
+		URL:           "docs/spec/transactions/docs/spec/spans/rum_v3_span.json",
+		Types:         []string{"string", "null"},
+		MinProperties: -1,
+		MaxProperties: -1,
+		MinItems:      -1,
+		MaxItems:      -1,
+		MinLength:     -1,
+		MaxLength:     1024,
+	}
+	s[138] = jsonschema.Schema{
+		URL:           "docs/spec/transactions/docs/spec/spans/rum_v3_span.json",
+		Types:         []string{"boolean", "null"},
+		MinProperties: -1,
+		MaxProperties: -1,
+		MinItems:      -1,
+		MaxItems:      -1,
+		MinLength:     -1,
+		MaxLength:     -1,
+	}
+	s[139] = jsonschema.Schema{
+		URL:           "docs/spec/transactions/docs/spec/spans/rum_v3_span.json",
+		Types:         []string{"string"},
+		MinProperties: -1,
+		MaxProperties: -1,
+		MinItems:      -1,
+		MaxItems:      -1,
+		MinLength:     -1,
+		MaxLength:     1024,
+	}
+	s[140] = jsonschema.Schema{
+		URL:           "docs/spec/transactions/docs/spec/spans/rum_v3_span.json",
+		MinProperties: -1,
+		MaxProperties: -1,
+		Required:      []string{"s"},
+		Properties: map[string]*jsonschema.Schema{
+			"s": &s[141],
+		},
+		MinItems:  -1,
+		MaxItems:  -1,
+		MinLength: -1,
+		MaxLength: -1,
+	}
+	s[141] = jsonschema.Schema{
+		URL:           "docs/spec/transactions/docs/spec/spans/rum_v3_span.json",
+		Types:         []string{"number"},
+		MinProperties: -1,
+		MaxProperties: -1,
+		MinItems:      -1,
+		MaxItems:      -1,
+		MinLength:     -1,
+		MaxLength:     -1,
+	}
+	s[142] = jsonschema.Schema{
+		URL:           "docs/spec/transactions/rum_v3_transaction.json",
+		Types:         []string{"object"},
+		MinProperties: -1,
+		MaxProperties: -1,
+		Required:      []string{"sd"},
+		Properties: map[string]*jsonschema.Schema{
+			"dd": &s[143],
+			"sd": &s[144],
+		},
+		MinItems:  -1,
+		MaxItems:  -1,
+		MinLength: -1,
+		MaxLength: -1,
+	}
+	s[143] = jsonschema.Schema{
+		URL:           "docs/spec/transactions/rum_v3_transaction.json",
+		Types:         []string{"integer", "null"},
+		MinProperties: -1,
+		MaxProperties: -1,
+		MinItems:      -1,
+		MaxItems:      -1,
+		MinLength:     -1,
+		MaxLength:     -1,
+	}
+	s[144] = jsonschema.Schema{
+		URL:           "docs/spec/transactions/rum_v3_transaction.json",
+		Types:         []string{"integer"},
+		MinProperties: -1,
+		MaxProperties: -1,
+		MinItems:      -1,
+		MaxItems:      -1,
+		MinLength:     -1,
+		MaxLength:     -1,
+	}
+	return &s[0]
+}
@@ -85,6 +85,48 @@ const ModelSchema = `{
                     },
                     "required": ["started"]
                 },
+                "dropped_spans_stats": {
+                    "type": ["array", "null"],
+                    "description": "Statistics of spans dropped by the agent due to limits, grouped by destination service resource and outcome. Used to keep aggregated latencies accurate when spans are dropped.",
+                    "items": {
+                        "type": "object",
+                        "properties": {
+                            "destination_service_resource": {
+                                "type": "string",
+                                "description": "Identifier of the destination service resource of the dropped spans (e.g. 'mysql', 'elasticsearch').",
+                                "maxLength": 1024
+                            },
+                            "outcome": {
+                                "type": ["string", "null"],
+                                "description": "Outcome of the dropped spans: 'success', 'failure', or 'unknown'.",
+                                "maxLength": 1024
+                            },
+                            "duration": {
+                                "type": "object",
+                                "properties": {
+                                    "count": {
+                                        "type": "integer",
+                                        "description": "Number of dropped spans.",
+                                        "minimum": 0
+                                    },
+                                    "sum": {
+                                        "type": "object",
+                                        "properties": {
+                                            "us": {
+                                                "type": "integer",
+                                                "description": "Sum of the durations of the dropped spans, in microseconds.",
+                                                "minimum": 0
+                                            }
+                                        },
+                                        "required": ["us"]
+                                    }
+                                },
+                                "required": ["count", "sum"]
+                            }
+                        },
+                        "required": ["destination_service_resource", "duration"]
+                    }
+                },
                 "context": {
                         "$id": "docs/spec/context.json",
     "title": "Context",
@@ -0,0 +1,1216 @@
+// Licensed to Elasticsearch B.V. under one or more contributor
+// license agreements. See the NOTICE file distributed with
+// this work for additional information regarding copyright
+// ownership. Elasticsearch B.V. licenses this file to you under
+// the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+// Code generated by script/inline_schemas. DO NOT EDIT.
+
+package schema
+
+import (
+	"math/big"
+	"regexp"
+
+	"github.com/santhosh-tekuri/jsonschema"
+)
+
+// CompiledModelSchema returns the compiled form of ModelSchema.
+func CompiledModelSchema() *jsonschema.Schema {
+	s := make([]jsonschema.Schema, 101)
+	s[0] = jsonschema.Schema{
+		URL:           "transaction",
+		Ptr:           "#",
+		Types:         []string{"object"},
+		AllOf:         []*jsonschema.Schema{&s[1], &s[3], &s[5], &s[7]},
+		MinProperties: -1,
+		MaxProperties: -1,
+		MinItems:      -1,
+		MaxItems:      -1,
+		MinLength:     -1,
+		MaxLength:     -1,
+	}
+	s[1] = jsonschema.Schema{
+		URL:           "docs/spec/transactions/transaction.json",
+		Types:         []string{"object"},
+		MinProperties: -1,
+		MaxProperties: -1,
+		Properties: map[string]*jsonschema.Schema{
+			"timestamp": &s[2],
+		},
+		MinItems:  -1,
+		MaxItems:  -1,
+		MinLength: -1,
+		MaxLength: -1,
+	}
+	s[2] = jsonschema.Schema{
+		URL:           "docs/spec/transactions/docs/spec/timestamp_epoch.json",
+		Types:         []string{"integer", "null"},
+		MinProperties: -1,
+		MaxProperties: -1,
+		MinItems:      -1,
+		MaxItems:      -1,
+		MinLength:     -1,
+		MaxLength:     -1,
+	}
+	s[3] = jsonschema.Schema{
+		URL:           "docs/spec/transactions/transaction.json",
+		Types:         []string{"object"},
+		MinProperties: -1,
+		MaxProperties: -1,
+		Properties: map[string]*jsonschema.Schema{
+			"name": &s[4],
+		},
+		MinItems:  -1,
+		MaxItems:  -1,
+		MinLength: -1,
+		MaxLength: -1,
+	}
+	s[4] = jsonschema.Schema{
+		URL:           "docs/spec/transactions/docs/spec/transaction_name.json",
+		Types:         []string{"string", "null"},
+		MinProperties: -1,
+		MaxProperties: -1,
+		MinItems:      -1,
+		MaxItems:      -1,
+		MinLength:     -1,
+		MaxLength:     1024,
+	}
+	s[5] = jsonschema.Schema{
+		URL:           "docs/spec/transactions/transaction.json",
+		Types:         []string{"object"},
+		MinProperties: -1,
+		MaxProperties: -1,
+		Properties: map[string]*jsonschema.Schema{
+			"type": &s[6],
+		},
+		MinItems:  -1,
+		MaxItems:  -1,
+		MinLength: -1,
+		MaxLength: -1,
+	}
+	s[6] = jsonschema.Schema{
+		URL:           "docs/spec/transactions/docs/spec/transaction_type.json",
+		Types:         []string{"string"},
+		MinProperties: -1,
+		MaxProperties: -1,
+		MinItems:      -1,
+		MaxItems:      -1,
+		MinLength:     -1,
+		MaxLength:     1024,
+	}
+	s[7] = jsonschema.Schema{
+		URL:           "docs/spec/transactions/transaction.json",
+		MinProperties: -1,
+		MaxProperties: -1,
+		Required:      []string{"id", "trace_id", "span_count", "duration", "type"},
+		Properties: map[string]*jsonschema.Schema{
+			"context":             &s[8],
+			"dropped_spans_stats": &s[81],
+			"duration":            &s[89],
+			"id":                  &s[90],
+			"marks":               &s[91],
+			"parent_id":           &s[94],
+			"result":              &s[95],
+			"sampled":             &s[96],
+			"span_count":          &s[97],
+			"trace_id":            &s[100],
+		},
+		MinItems:  -1,
+		MaxItems:  -1,
+		MinLength: -1,
+		MaxLength: -1,
+	}
+	s[8] = jsonschema.Schema{
+		URL:           "docs/spec/transactions/transaction.json",
+		Types:         []string{"object", "null"},
+		MinProperties: -1,
+		MaxProperties: -1,
+		Properties: map[string]*jsonschema.Schema{
+			"custom":   &s[9],
+			"message":  &s[11],
+			"page":     &s[20],
+			"request":  &s[23],
+			"response": &s[44],
+			"service":  &s[56],
+			"tags":     &s[75],
+			"user":     &s[77],
+		},
+		MinItems:  -1,
+		MaxItems:  -1,
+		MinLength: -1,
+		MaxLength: -1,
+	}
+	s[9] = jsonschema.Schema{
+		URL:           "docs/spec/transactions/docs/spec/context.json",
+		Types:         []string{"object", "null"},
+		MinProperties: -1,
+		MaxProperties: -1,
+		PatternProperties: map[*regexp.Regexp]*jsonschema.Schema{
+			regexp.MustCompile("^[^.*\"]*$"): &s[10],
+		},
+		AdditionalProperties: false,
+		MinItems:             -1,
+		MaxItems:             -1,
+		MinLength:            -1,
+		MaxLength:            -1,
+	}
+	s[10] = jsonschema.Schema{
+		URL:           "docs/spec/transactions/docs/spec/context.json",
+		MinProperties: -1,
+		MaxProperties: -1,
+		MinItems:      -1,
+		MaxItems:      -1,
+		MinLength:     -1,
+		MaxLength:     -1,
+	}
+	s[11] = jsonschema.Schema{
+		URL:           "docs/spec/transactions/docs/spec/context.json",
+		Types:         []string{"object", "null"},
+		MinProperties: -1,
+		MaxProperties: -1,
+		Properties: map[string]*jsonschema.Schema{
+			"age":     &s[12],
+			"body":    &s[14],
+			"headers": &s[15],
+			"queue":   &s[18],
+		},
+		MinItems:  -1,
+		MaxItems:  -1,
+		MinLength: -1,
+		MaxLength: -1,
+	}
+	s[12] = jsonschema.Schema{
+		URL:           "docs/spec/transactions/docs/spec/docs/spec/message.json",
+		Types:         []string{"object", "null"},
+		MinProperties: -1,
+		MaxProperties: -1,
+		Properties: map[string]*jsonschema.Schema{
+			"ms": &s[13],
+		},
+		MinItems:  -1,
+		MaxItems:  -1,
+		MinLength: -1,
+		MaxLength: -1,
+	}
+	s[13] = jsonschema.Schema{
+		URL:           "docs/spec/transactions/docs/spec/docs/spec/message.json",
+		Types:         []string{"integer", "null"},
+		MinProperties: -1,
+		MaxProperties: -1,
+		MinItems:      -1,
+		MaxItems:      -1,
+		MinLength:     -1,
+		MaxLength:     -1,
+	}
+	s[14] = jsonschema.Schema{
+		URL:           "docs/spec/transactions/docs/spec/docs/spec/message.json",
+		Types:         []string{"string", "null"},
+		MinProperties: -1,
+		MaxProperties: -1,
+		MinItems:      -1,
+		MaxItems:      -1,
+		MinLength:     -1,
+		MaxLength:     -1,
+	}
+	s[15] = jsonschema.Schema{
+		URL:           "docs/spec/transactions/docs/spec/docs/spec/message.json",
+		Types:         []string{"object", "null"},
+		MinProperties: -1,
+		MaxProperties: -1,
+		PatternProperties: map[*regexp.Regexp]*jsonschema.Schema{
+			regexp.MustCompile("[.*]*$"): &s[16],
+		},
+		MinItems:  -1,
+		MaxItems:  -1,
+		MinLength: -1,
+		MaxLength: -1,
+	}
+	s[16] = jsonschema.Schema{
+		URL:           "docs/spec/transactions/docs/spec/docs/spec/message.json",
+		Types:         []string{"string", "array", "null"},
+		MinProperties: -1,
+		MaxProperties: -1,
+		MinItems:      -1,
+		MaxItems:      -1,
+		Items:         &s[17],
+		MinLength:     -1,
+		MaxLength:     -1,
+	}
+	s[17] = jsonschema.Schema{
+		URL:           "docs/spec/transactions/docs/spec/docs/spec/message.json",
+		Types:         []string{"string"},
+		MinProperties: -1,
+		MaxProperties: -1,
+		MinItems:      -1,
+		MaxItems:      -1,
+		MinLength:     -1,
+		MaxLength:     -1,
+	}
+	s[18] = jsonschema.Schema{
+		URL:           "docs/spec/transactions/docs/spec/docs/spec/message.json",
+		Types:         []string{"object", "null"},
+		MinProperties: -1,
+		MaxProperties: -1,
+		Properties: map[string]*jsonschema.Schema{
+			"name": &s[19],
+		},
+		MinItems:  -1,
+		MaxItems:  -1,
+		MinLength: -1,
+		MaxLength: -1,
+	}
+	s[19] = jsonschema.Schema{
+		URL:           "docs/spec/transactions/docs/spec/docs/spec/message.json",
+		Types:         []string{"string", "null"},
+		MinProperties: -1,
+		MaxProperties: -1,
+		MinItems:      -1,
+		MaxItems:      -1,
+		MinLength:     -1,
+		MaxLength:     1024,
+	}
+	s[20] = jsonschema.Schema{
+		URL:           "docs/spec/transactions/docs/spec/context.json",
+		Types:         []string{"object", "null"},
+		MinProperties: -1,
+		MaxProperties: -1,
+		Properties: map[string]*jsonschema.Schema{
+			"referer": &s[21],
+			"url":     &s[22],
+		},
+		MinItems:  -1,
+		MaxItems:  -1,
+		MinLength: -1,
+		MaxLength: -1,
+	}
+	s[21] = jsonschema.Schema{
+		URL:           "docs/spec/transactions/docs/spec/context.json",
+		Types:         []string{"string", "null"},
+		MinProperties: -1,
+		MaxProperties: -1,
+		MinItems:      -1,
+		MaxItems:      -1,
+		MinLength:     -1,
+		MaxLength:     -1,
+	}
+	s[22] = jsonschema.Schema{
+		URL:           "docs/spec/transactions/docs/spec/context.json",
+		Types:         []string{"string", "null"},
+		MinProperties: -1,
+		MaxProperties: -1,
+		MinItems:      -1,
+		MaxItems:      -1,
+		MinLength:     -1,
+		MaxLength:     -1,
+	}
+	s[23] = jsonschema.Schema{
+		URL:           "docs/spec/transactions/docs/spec/context.json",
+		Types:         []string{"object", "null"},
+		MinProperties: -1,
+		MaxProperties: -1,
+		Required:      []string{"url", "method"},
+		Properties: map[string]*jsonschema.Schema{
+			"body":         &s[24],
+			"cookies":      &s[25],
+			"env":          &s[26],
+			"headers":      &s[27],
+			"http_version": &s[30],
+			"method":       &s[31],
+			"socket":       &s[32],
+			"url":          &s[35],
+		},
+		MinItems:  -1,
+		MaxItems:  -1,
+		MinLength: -1,
+		MaxLength: -1,
+	}
+	s[24] = jsonschema.Schema{
+		URL:           "docs/spec/transactions/docs/spec/docs/spec/request.json",
+		Types:         []string{"object", "string", "null"},
+		MinProperties: -1,
+		MaxProperties: -1,
+		MinItems:      -1,
+		MaxItems:      -1,
+		MinLength:     -1,
+		MaxLength:     -1,
+	}
+	s[25] = jsonschema.Schema{
+		URL:           "docs/spec/transactions/docs/spec/docs/spec/request.json",
+		Types:         []string{"object", "null"},
+		MinProperties: -1,
+		MaxProperties: -1,
+		MinItems:      -1,
+		MaxItems:      -1,
+		MinLength:     -1,
+		MaxLength:     -1,
+	}
+	s[26] = jsonschema.Schema{
+		URL:           "docs/spec/transactions/docs/spec/docs/spec/request.json",
+		Types:         []string{"object", "null"},
+		MinProperties: -1,
+		MaxProperties: -1,
+		Properties:    map[string]*jsonschema.Schema{},
+		MinItems:      -1,
+		MaxItems:      -1,
+		MinLength:     -1,
+		MaxLength:     -1,
+	}
+	s[27] = jsonschema.Schema{
+		URL:           "docs/spec/transactions/docs/spec/docs/spec/request.json",
+		Types:         []string{"object", "null"},
+		MinProperties: -1,
+		MaxProperties: -1,
+		PatternProperties: map[*regexp.Regexp]*jsonschema.Schema{
+			regexp.MustCompile("[.*]*$"): &s[28],
+		},
+		MinItems:  -1,
+		MaxItems:  -1,
+		MinLength: -1,
+		MaxLength: -1,
+	}
+	s[28] = jsonschema.Schema{
+		URL:           "docs/spec/transactions/docs/spec/docs/spec/request.json",
+		Types:         []string{"string", "array", "null"},
+		MinProperties: -1,
+		MaxProperties: -1,
+		MinItems:      -1,
+		MaxItems:      -1,
+		Items:         &s[29],
+		MinLength:     -1,
+		MaxLength:     -1,
+	}
+	s[29] = jsonschema.Schema{
+		URL:           "docs/spec/transactions/docs/spec/docs/spec/request.json",
+		Types:         []string{"string"},
+		MinProperties: -1,
+		MaxProperties: -1,
+		MinItems:      -1,
+		MaxItems:      -1,
+		MinLength:     -1,
+		MaxLength:     -1,
+	}
+	s[30] = jsonschema.Schema{
+		URL:           "docs/spec/transactions/docs/spec/docs/spec/request.json",
+		Types:         []string{"string", "null"},
+		MinProperties: -1,
+		MaxProperties: -1,
+		MinItems:      -1,
+		MaxItems:      -1,
+		MinLength:     -1,
+		MaxLength:     1024,
+	}
+	s[31] = jsonschema.Schema{
+		URL:           "docs/spec/transactions/docs/spec/docs/spec/request.json",
+		Types:         []string{"string"},
+		MinProperties: -1,
+		MaxProperties: -1,
+		MinItems:      -1,
+		MaxItems:      -1,
+		MinLength:     -1,
+		MaxLength:     1024,
+	}
+	s[32] = jsonschema.Schema{
+		URL:           "docs/spec/transactions/docs/spec/docs/spec/request.json",
+		Types:         []string{"object", "null"},
+		MinProperties: -1,
+		MaxProperties: -1,
+		Properties: map[string]*jsonschema.Schema{
+			"encrypted":      &s[33],
+			"remote_address": &s[34],
+		},
+		MinItems:  -1,
+		MaxItems:  -1,
+		MinLength: -1,
+		MaxLength: -1,
+	}
+	s[33] = jsonschema.Schema{
+		URL:           "docs/spec/transactions/docs/spec/docs/spec/request.json",
+		Types:         []string{"boolean", "null"},
+		MinProperties: -1,
+		MaxProperties: -1,
+		MinItems:      -1,
+		MaxItems:      -1,
+		MinLength:     -1,
+		MaxLength:     -1,
+	}
+	s[34] = jsonschema.Schema{
+		URL:           "docs/spec/transactions/docs/spec/docs/spec/request.json",
+		Types:         []string{"string", "null"},
+		MinProperties: -1,
+		MaxProperties: -1,
+		MinItems:      -1,
+		MaxItems:      -1,
+		MinLength:     -1,
+		MaxLength:     -1,
+	}
+	s[35] = jsonschema.Schema{
+		URL:           "docs/spec/transactions/docs/spec/docs/spec/request.json",
+		Types:         []string{"object"},
+		MinProperties: -1,
+		MaxProperties: -1,
+		Properties: map[string]*jsonschema.Schema{
+			"full":     &s[36],
+			"hash":     &s[37],
+			"hostname": &s[38],
+			"pathname": &s[39],
+			"port":     &s[40],
+			"protocol": &s[41],
+			"raw":      &s[42],
+			"search":   &s[43],
+		},
+		MinItems:  -1,
+		MaxItems:  -1,
+		MinLength: -1,
+		MaxLength: -1,
+	}
+	s[36] = jsonschema.Schema{
+		URL:           "docs/spec/transactions/docs/spec/docs/spec/request.json",
+		Types:         []string{"string", "null"},
+		MinProperties: -1,
+		MaxProperties: -1,
+		MinItems:      -1,
+		MaxItems:      -1,
+		MinLength:     -1,
+		MaxLength:     1024,
+	}
+	s[37] = jsonschema.Schema{
+		URL:           "docs/spec/transactions/docs/spec/docs/spec/request.json",
+		Types:         []string{"string", "null"},
+		MinProperties: -1,
+		MaxProperties: -1,
+		MinItems:      -1,
+		MaxItems:      -1,
+		MinLength:     -1,
+		MaxLength:     1024,
+	}
+	s[38] = jsonschema.Schema{
+		URL:           "docs/spec/transactions/docs/spec/docs/spec/request.json",
+		Types:         []string{"string", "null"},
+		MinProperties: -1,
+		MaxProperties: -1,
+		MinItems:      -1,
+		MaxItems:      -1,
+		MinLength:     -1,
+		MaxLength:     1024,
+	}
+	s[39] = jsonschema.Schema{
+		URL:           "docs/spec/transactions/docs/spec/docs/spec/request.json",
+		Types:         []string{"string", "null"},
+		MinProperties: -1,
+		MaxProperties: -1,
+		MinItems:      -1,
+		MaxItems:      -1,
+		MinLength:     -1,
+		MaxLength:     1024,
+	}
+	s[40] = jsonschema.Schema{
+		URL:           "docs/spec/transactions/docs/spec/docs/spec/request.json",
+		Types:         []string{"string", "integer", "null"},
+		MinProperties: -1,
+		MaxProperties: -1,
+		MinItems:      -1,
+		MaxItems:      -1,
+		MinLength:     -1,
+		MaxLength:     1024,
+	}
+	s[41] = jsonschema.Schema{
+		URL:           "docs/spec/transactions/docs/spec/docs/spec/request.json",
+		Types:         []string{"string", "null"},
+		MinProperties: -1,
+		MaxProperties: -1,
+		MinItems:      -1,
+		MaxItems:      -1,
+		MinLength:     -1,
+		MaxLength:     1024,
+	}
+	s[42] = jsonschema.Schema{
+		URL:           "docs/spec/transactions/docs/spec/docs/spec/request.json",
+		Types:         []string{"string", "null"},
+		MinProperties: -1,
+		MaxProperties: -1,
+		MinItems:      -1,
+		MaxItems:      -1,
+		MinLength:     -1,
+		MaxLength:     1024,
+	}
+	s[43] = jsonschema.Schema{
+		URL:           "docs/spec/transactions/docs/spec/docs/spec/request.json",
+		Types:         []string{"string", "null"},
+		MinProperties: -1,
+		MaxProperties: -1,
+		MinItems:      -1,
+		MaxItems:      -1,
+		MinLength:     -1,
+		MaxLength:     1024,
+	}
+	s[44] = jsonschema.Schema{
+		URL:           "docs/spec/transactions/docs/spec/context.json",
+		Types:         []string{"object", "null"},
+		AllOf:         []*jsonschema.Schema{&s[45], &s[53]},
+		MinProperties: -1,
+		MaxProperties: -1,
+		MinItems:      -1,
+		MaxItems:      -1,
+		MinLength:     -1,
+		MaxLength:     -1,
+	}
+	s[45] = jsonschema.Schema{
+		URL:           "docs/spec/transactions/docs/spec/context.json",
+		Types:         []string{"object", "null"},
+		MinProperties: -1,
+		MaxProperties: -1,
+		Properties: map[string]*jsonschema.Schema{
+			"decoded_body_size": &s[46],
+			"encoded_body_size": &s[47],
+			"headers":           &s[48],
+			"status_code":       &s[51],
+			"transfer_size":     &s[52],
+		},
+		MinItems:  -1,
+		MaxItems:  -1,
+		MinLength: -1,
+		MaxLength: -1,
+	}
+	s[46] = jsonschema.Schema{
+		URL:           "docs/spec/transactions/docs/spec/docs/spec/http_response.json",
+		Types:         []string{"number", "null"},
+		MinProperties: -1,
+		MaxProperties: -1,
+		MinItems:      -1,
+		MaxItems:      -1,
+		MinLength:     -1,
+		MaxLength:     -1,
+	}
+	s[47] = jsonschema.Schema{
+		URL:           "docs/spec/transactions/docs/spec/docs/spec/http_response.json",
+		Types:         []string{"number", "null"},
+		MinProperties: -1,
+		MaxProperties: -1,
+		MinItems:      -1,
+		MaxItems:      -1,
+		MinLength:     -1,
+		MaxLength:     -1,
+	}
+	s[48] = jsonschema.Schema{
+		URL:           "docs/spec/transactions/docs/spec/docs/spec/http_response.json",
+		Types:         []string{"object", "null"},
+		MinProperties: -1,
+		MaxProperties: -1,
+		PatternProperties: map[*regexp.Regexp]*jsonschema.Schema{
+			regexp.MustCompile("[.*]*$"): &s[49],
+		},
+		MinItems:  -1,
+		MaxItems:  -1,
+		MinLength: -1,
+		MaxLength: -1,
+	}
+	s[49] = jsonschema.Schema{
+		URL:           "docs/spec/transactions/docs/spec/docs/spec/http_response.json",
+		Types:         []string{"string", "array", "null"},
+		MinProperties: -1,
+		MaxProperties: -1,
+		MinItems:      -1,
+		MaxItems:      -1,
+		Items:         &s[50],
+		MinLength:     -1,
+		MaxLength:     -1,
+	}
+	s[50] = jsonschema.Schema{
+		URL:           "docs/spec/transactions/docs/spec/docs/spec/http_response.json",
+		Types:         []string{"string"},
+		MinProperties: -1,
+		MaxProperties: -1,
+		MinItems:      -1,
+		MaxItems:      -1,
+		MinLength:     -1,
+		MaxLength:     -1,
+	}
+	s[51] = jsonschema.Schema{
+		URL:           "docs/spec/transactions/docs/spec/docs/spec/http_response.json",
+		Types:         []string{"integer", "null"},
+		MinProperties: -1,
+		MaxProperties: -1,
+		MinItems:      -1,
+		MaxItems:      -1,
+		MinLength:     -1,
+		MaxLength:     -1,
+	}
+	s[52] = jsonschema.Schema{
+		URL:           "docs/spec/transactions/docs/spec/docs/spec/http_response.json",
+		Types:         []string{"number", "null"},
+		MinProperties: -1,
+		MaxProperties: -1,
+		MinItems:      -1,
+		MaxItems:      -1,
+		MinLength:     -1,
+		MaxLength:     -1,
+	}
+	s[53] = jsonschema.Schema{
+		URL:           "docs/spec/transactions/docs/spec/context.json",
+		MinProperties: -1,
+		MaxProperties: -1,
+		Properties: map[string]*jsonschema.Schema{
+			"finished":     &s[54],
+			"headers_sent": &s[55],
+		},
+		MinItems:  -1,
+		MaxItems:  -1,
+		MinLength: -1,
+		MaxLength: -1,
+	}
+	s[54] = jsonschema.Schema{
+		URL:           "docs/spec/transactions/docs/spec/context.json",
+		Types:         []string{"boolean", "null"},
+		MinProperties: -1,
+		MaxProperties: -1,
+		MinItems:      -1,
+		MaxItems:      -1,
+		MinLength:     -1,
+		MaxLength:     -1,
+	}
+	s[55] = jsonschema.Schema{
+		URL:           "docs/spec/transactions/docs/spec/context.json",
+		Types:         []string{"boolean", "null"},
+		MinProperties: -1,
+		MaxProperties: -1,
+		MinItems:      -1,
+		MaxItems:      -1,
+		MinLength:     -1,
+		MaxLength:     -1,
+	}
+	s[56] = jsonschema.Schema{
+		URL:           "docs/spec/transactions/docs/spec/context.json",
+		Types:         []string{"object", "null"},
+		MinProperties: -1,
+		MaxProperties: -1,
+		Properties: map[string]*jsonschema.Schema{
+			"agent":       &s[57],
+			"environment": &s[61],
+			"framework":   &s[62],
+			"language":    &s[65],
+			"name":        &s[68],
+			"node":        &s[69],
+			"runtime":     &s[71],
+			"version":     &s[74],
+		},
+		MinItems:  -1,
+		MaxItems:  -1,
+		MinLength: -1,
+		MaxLength: -1,
+	}
+	s[57] = jsonschema.Schema{
+		URL:           "docs/spec/transactions/docs/spec/docs/spec/service.json",
+		Types:         []string{"object", "null"},
+		MinProperties: -1,
+		MaxProperties: -1,
+		Properties: map[string]*jsonschema.Schema{
+			"ephemeral_id": &s[58],
+			"name":         &s[59],
+			"version":      &s[60],
+		},
+		MinItems:  -1,
+		MaxItems:  -1,
+		MinLength: -1,
+		MaxLength: -1,
+	}
+	s[58] = jsonschema.Schema{
+		URL:           "docs/spec/transactions/docs/spec/docs/spec/service.json",
+		Types:         []string{"string", "null"},
+		MinProperties: -1,
+		MaxProperties: -1,
+		MinItems:      -1,
+		MaxItems:      -1,
+		MinLength:     -1,
+		MaxLength:     1024,
+	}
+	s[59] = jsonschema.Schema{
+		URL:           "docs/spec/transactions/docs/spec/docs/spec/service.json",
+		Types:         []string{"string", "null"},
+		MinProperties: -1,
+		MaxProperties: -1,
+		MinItems:      -1,
+		MaxItems:      -1,
+		MinLength:     -1,
+		MaxLength:     1024,
+	}
+	s[60] = jsonschema.Schema{
+		URL:           "docs/spec/transactions/docs/spec/docs/spec/service.json",
+		Types:         []string{"string", "null"},
+		MinProperties: -1,
+		MaxProperties: -1,
+		MinItems:      -1,
+		MaxItems:      -1,
+		MinLength:     -1,
+		MaxLength:     1024,
+	}
+	s[61] = jsonschema.Schema{
+		URL:           "docs/spec/transactions/docs/spec/docs/spec/service.json",
+		Types:         []string{"string", "null"},
+		MinProperties: -1,
+		MaxProperties: -1,
+		MinItems:      -1,
+		MaxItems:      -1,
+		MinLength:     -1,
+		MaxLength:     1024,
+	}
+	s[62] = jsonschema.Schema{
+		URL:           "docs/spec/transactions/docs/spec/docs/spec/service.json",
+		Types:         []string{"object", "null"},
+		MinProperties: -1,
+		MaxProperties: -1,
+		Properties: map[string]*jsonschema.Schema{
+			"name":    &s[63],
+			"version": &s[64],
+		},
+		MinItems:  -1,
+		MaxItems:  -1,
+		MinLength: -1,
+		MaxLength: -1,
+	}
+	s[63] = jsonschema.Schema{
+		URL:           "docs/spec/transactions/docs/spec/docs/spec/service.json",
+		Types:         []string{"string", "null"},
+		MinProperties: -1,
+		MaxProperties: -1,
+		MinItems:      -1,
+		MaxItems:      -1,
+		MinLength:     -1,
+		MaxLength:     1024,
+	}
+	s[64] = jsonschema.Schema{
+		URL:           "docs/spec/transactions/docs/spec/docs/spec/service.json",
+		Types:         []string{"string", "null"},
+		MinProperties: -1,
+		MaxProperties: -1,
+		MinItems:      -1,
+		MaxItems:      -1,
+		MinLength:     -1,
+		MaxLength:     1024,
+	}
+	s[65] = jsonschema.Schema{
+		URL:           "docs/spec/transactions/docs/spec/docs/spec/service.json",
+		Types:         []string{"object", "null"},
+		MinProperties: -1,
+		MaxProperties: -1,
+		Properties: map[string]*jsonschema.Schema{
+			"name":    &s[66],
+			"version": &s[67],
+		},
+		MinItems:  -1,
+		MaxItems:  -1,
+		MinLength: -1,
+		MaxLength: -1,
+	}
+	s[66] = jsonschema.Schema{
+		URL:           "docs/spec/transactions/docs/spec/docs/spec/service.json",
+		Types:         []string{"string", "null"},
+		MinProperties: -1,
+		MaxProperties: -1,
+		MinItems:      -1,
+		MaxItems:      -1,
+		MinLength:     -1,
+		MaxLength:     1024,
+	}
+	s[67] = jsonschema.Schema{
+		URL:           "docs/spec/transactions/docs/spec/docs/spec/service.json",
+		Types:         []string{"string", "null"},
+		MinProperties: -1,
+		MaxProperties: -1,
+		MinItems:      -1,
+		MaxItems:      -1,
+		MinLength:     -1,
+		MaxLength:     1024,
+	}
+	s[68] = jsonschema.Schema{
+		URL:           "docs/spec/transactions/docs/spec/docs/spec/service.json",
+		Types:         []string{"string", "null"},
+		MinProperties: -1,
+		MaxProperties: -1,
+		MinItems:      -1,
+		MaxItems:      -1,
+		MinLength:     -1,
+		MaxLength:     1024,
+		Pattern:       regexp.MustCompile("^[a-zA-Z0-9 _-]+$"),
+	}
+	s[69] = jsonschema.Schema{
+		URL:           "docs/spec/transactions/docs/spec/docs/spec/service.json",
+		Types:         []string{"object", "null"},
+		MinProperties: -1,
+		MaxProperties: -1,
+		Properties: map[string]*jsonschema.Schema{
+			"configured_name": &s[70],
+		},
+		MinItems:  -1,
+		MaxItems:  -1,
+		MinLength: -1,
+		MaxLength: -1,
+	}
+	s[70] = jsonschema.Schema{
+		URL:           "docs/spec/transactions/docs/spec/docs/spec/service.json",
+		Types:         []string{"string", "null"},
+		MinProperties: -1,
+		MaxProperties: -1,
+		MinItems:      -1,
+		MaxItems:      -1,
+		MinLength:     -1,
+		MaxLength:     1024,
+	}
+	s[71] = jsonschema.Schema{
+		URL:           "docs/spec/transactions/docs/spec/docs/spec/service.json",
+		Types:         []string{"object", "null"},
+		MinProperties: -1,
+		MaxProperties: -1,
+		Properties: map[string]*jsonschema.Schema{
+			"name":    &s[72],
+			"version": &s[73],
+		},
+		MinItems:  -1,
+		MaxItems:  -1,
+		MinLength: -1,
+		MaxLength: -1,
+	}
+	s[72] = jsonschema.Schema{
+		URL:           "docs/spec/transactions/docs/spec/docs/spec/service.json",
+		Types:         []string{"string", "null"},
+		MinProperties: -1,
+		MaxProperties: -1,
+		MinItems:      -1,
+		MaxItems:      -1,
+		MinLength:     -1,
+		MaxLength:     1024,
+	}
+	s[73] = jsonschema.Schema{
+		URL:           "docs/spec/transactions/docs/spec/docs/spec/service.json",
+		Types:         []string{"string", "null"},
+		MinProperties: -1,
+		MaxProperties: -1,
+		MinItems:      -1,
+		MaxItems:      -1,
+		MinLength:     -1,
+		MaxLength:     1024,
+	}
+	s[74] = jsonschema.Schema{
+		URL:           "docs/spec/transactions/docs/spec/docs/spec/service.json",
+		Types:         []string{"string", "null"},
+		MinProperties: -1,
+		MaxProperties: -1,
+		MinItems:      -1,
+		MaxItems:      -1,
+		MinLength:     -1,
+		MaxLength:     1024,
+	}
+	s[75] = jsonschema.Schema{
+		URL:           "docs/spec/transactions/docs/spec/context.json",
+		Types:         []string{"object", "null"},
+		MinProperties: -1,
+		MaxProperties: -1,
+		PatternProperties: map[*regexp.Regexp]*jsonschema.Schema{
+			regexp.MustCompile("^[^.*\"]*$"): &s[76],
+		},
+		AdditionalProperties: false,
+		MinItems:             -1,
+		MaxItems:             -1,
+		MinLength:            -1,
+		MaxLength:            -1,
+	}
+	s[76] = jsonschema.Schema{
+		URL:           "docs/spec/transactions/docs/spec/docs/spec/tags.json",
+		Types:         []string{"string", "boolean", "number", "null"},
+		MinProperties: -1,
+		MaxProperties: -1,
+		MinItems:      -1,
+		MaxItems:      -1,
+		MinLength:     -1,
+		MaxLength:     1024,
+	}
+	s[77] = jsonschema.Schema{
+		URL:           "docs/spec/transactions/docs/spec/context.json",
+		Types:         []string{"object", "null"},
+		MinProperties: -1,
+		MaxProperties: -1,
+		Properties: map[string]*jsonschema.Schema{
+			"email":    &s[78],
+			"id":       &s[79],
+			"username": &s[80],
+		},
+		MinItems:  -1,
+		MaxItems:  -1,
+		MinLength: -1,
+		MaxLength: -1,
+	}
+	s[78] = jsonschema.Schema{
+		URL:           "docs/spec/transactions/docs/spec/docs/spec/user.json",
+		Types:         []string{"string", "null"},
+		MinProperties: -1,
+		MaxProperties: -1,
+		MinItems:      -1,
+		MaxItems:      -1,
+		MinLength:     -1,
+		MaxLength:     1024,
+	}
+	s[79] = jsonschema.Schema{
+		URL:           "docs/spec/transactions/docs/spec/docs/spec/user.json",
+		Types:         []string{"string", "integer", "null"},
+		MinProperties: -1,
+		MaxProperties: -1,
+		MinItems:      -1,
+		MaxItems:      -1,
+		MinLength:     -1,
+		MaxLength:     1024,
+	}
+	s[80] = jsonschema.Schema{
+		URL:           "docs/spec/transactions/docs/spec/docs/spec/user.json",
+		Types:         []string{"string", "null"},
+		MinProperties: -1,
+		MaxProperties: -1,
+		MinItems:      -1,
+		MaxItems:      -1,
+		MinLength:     -1,
+		MaxLength:     1024,
+	}
+	s[81] = jsonschema.Schema{
+		URL:           "docs/spec/transactions/transaction.json",
+		Types:         []string{"array", "null"},
+		MinProperties: -1,
+		MaxProperties: -1,
+		MinItems:      -1,
+		MaxItems:      -1,
+		Items:         &s[82],
+		MinLength:     -1,
+		MaxLength:     -1,
+	}
+	s[82] = jsonschema.Schema{
+		URL:           "docs/spec/transactions/transaction.json",
+		Types:         []string{"object"},
+		MinProperties: -1,
+		MaxProperties: -1,
+		Required:      []string{"destination_service_resource", "duration"},
+		Properties: map[string]*jsonschema.Schema{
+			"destination_service_resource": &s[83],
+			"duration":                     &s[84],
+			"outcome":                      &s[88],
+		},
+		MinItems:  -1,
+		MaxItems:  -1,
+		MinLength: -1,
+		MaxLength: -1,
+	}
+	s[83] = jsonschema.Schema{
+		URL:           "docs/spec/transactions/transaction.json",
+		Types:         []string{"string"},
+		MinProperties: -1,
+		MaxProperties: -1,
+		MinItems:      -1,
+		MaxItems:      -1,
+		MinLength:     -1,
+		MaxLength:     1024,
+	}
+	s[84] = jsonschema.Schema{
+		URL:           "docs/spec/transactions/transaction.json",
+		Types:         []string{"object"},
+		MinProperties: -1,
+		MaxProperties: -1,
+		Required:      []string{"count", "sum"},
+		Properties: map[string]*jsonschema.Schema{
+			"count": &s[85],
+			"sum":   &s[86],
+		},
+		MinItems:  -1,
+		MaxItems:  -1,
+		MinLength: -1,
+		MaxLength: -1,
+	}
+	s[85] = jsonschema.Schema{
+		URL:           "docs/spec/transactions/transaction.json",
+		Types:         []string{"integer"},
+		MinProperties: -1,
+		MaxProperties: -1,
+		MinItems:      -1,
+		MaxItems:      -1,
+		MinLength:     -1,
+		MaxLength:     -1,
+		Minimum:       func() *big.Float { f, _ := new(big.Float).SetString("0"); return f }(),
+	}
+	s[86] = jsonschema.Schema{
+		URL:           "docs/spec/transactions/transaction.json",
+		Types:         []string{"object"},
+		MinProperties: -1,
+		MaxProperties: -1,
+		Required:      []string{"us"},
+		Properties: map[string]*jsonschema.Schema{
+			"us": &s[87],
+		},
+		MinItems:  -1,
+		MaxItems:  -1,
+		MinLength: -1,
+		MaxLength: -1,
+	}
+	s[87] = jsonschema.Schema{
+		URL:           "docs/spec/transactions/transaction.json",
+		Types:         []string{"integer"},
+		MinProperties: -1,
+		MaxProperties: -1,
+		MinItems:      -1,
+		MaxItems:      -1,
+		MinLength:     -1,
+		MaxLength:     -1,
+		Minimum:       func() *big.Float { f, _ := new(big.Float).SetString("0"); return f }(),
+	}
+	s[88] = jsonschema.Schema{
+		URL:           "docs/spec/transactions/transaction.json",
+		Types:         []string{"string", "null"},
+		MinProperties: -1,
+		MaxProperties: -1,
+		MinItems:      -1,
+		MaxItems:      -1,
+		MinLength:     -1,
+		MaxLength:     1024,
+	}
+	s[89] = jsonschema.Schema{
+		URL:           "docs/spec/transactions/transaction.json",
+		Types:         []string{"number"},
+		MinProperties: -1,
+		MaxProperties: -1,
+		MinItems:      -1,
+		MaxItems:      -1,
+		MinLength:     -1,
+		MaxLength:     -1,
+		Minimum:       func() *big.Float { f, _ := new(big.Float).SetString("0"); return f }(),
+	}
+	s[90] = jsonschema.Schema{
+		URL:           "docs/spec/transactions/transaction.json",
+		Types:         []string{"string"},
+		MinProperties: -1,
+		MaxProperties: -1,
+		MinItems:      -1,
+		MaxItems:      -1,
+		MinLength:     -1,
+		MaxLength:     1024,
+	}
+	s[91] = jsonschema.Schema{
+		URL:           "docs/spec/transactions/transaction.json",
+		Types:         []string{"object", "null"},
+		MinProperties: -1,
+		MaxProperties: -1,
+		PatternProperties: map[*regexp.Regexp]*jsonschema.Schema{
+			regexp.MustCompile("^[^.*\"]*$"): &s[92],
+		},
+		AdditionalProperties: false,
+		MinItems:             -1,
+		MaxItems:             -1,
+		MinLength:            -1,
+		MaxLength:            -1,
+	}
+	s[92] = jsonschema.Schema{
+		URL:           "docs/spec/transactions/transaction.json",
+		Types:         []string{"object", "null"},
+		MinProperties: -1,
+		MaxProperties: -1,
+		PatternProperties: map[*regexp.Regexp]*jsonschema.Schema{
+			regexp.MustCompile("^[^.*\"]*$"): &s[93],
+		},
+		AdditionalProperties: false,
+		MinItems:             -1,
+		MaxItems:             -1,
+		MinLength:            -1,
+		MaxLength:            -1,
+	}
+	s[93] = jsonschema.Schema{
+		URL:           "docs/spec/transactions/docs/spec/transactions/mark.json",
+		Types:         []string{"number", "null"},
+		MinProperties: -1,
+		MaxProperties: -1,
+		MinItems:      -1,
+		MaxItems:      -1,
+		MinLength:     -1,
+		MaxLength:     -1,
+	}
+	s[94] = jsonschema.Schema{
+		URL:           "docs/spec/transactions/transaction.json",
+		Types:         []string{"string", "null"},
+		MinProperties: -1,
+		MaxProperties: -1,
+		MinItems:      -1,
+		MaxItems:      -1,
+		MinLength:     -1,
+		MaxLength:     1024,
+	}
+	s[95] = jsonschema.Schema{
+		URL:           "docs/spec/transactions/transaction.json",
+		Types:         []string{"string", "null"},
+		MinProperties: -1,
+		MaxProperties: -1,
+		MinItems:      -1,
+		MaxItems:      -1,
+		MinLength:     -1,
+		MaxLength:     1024,
+	}
+	s[96] = jsonschema.Schema{
+		URL:           "docs/spec/transactions/transaction.json",
+		Types:         []string{"boolean", "null"},
+		MinProperties: -1,
+		MaxProperties: -1,
+		MinItems:      -1,
+		MaxItems:      -1,
+		MinLength:     -1,
+		MaxLength:     -1,
+	}
+	s[97] = jsonschema.Schema{
+		URL:           "docs/spec/transactions/transaction.json",
+		Types:         []string{"object"},
+		MinProperties: -1,
+		MaxProperties: -1,
+		Required:      []string{"started"},
+		Properties: map[string]*jsonschema.Schema{
+			"dropped": &s[98],
+			"started": &s[99],
+		},
+		MinItems:  -1,
+		MaxItems:  -1,
+		MinLength: -1,
+		MaxLength: -1,
+	}
+	s[98] = jsonschema.Schema{
+		URL:           "docs/spec/transactions/transaction.json",
+		Types:         []string{"integer", "null"},
+		MinProperties: -1,
+		MaxProperties: -1,
+		MinItems:      -1,
+		MaxItems:      -1,
+		MinLength:     -1,
+		MaxLength:     -1,
+	}
+	s[99] = jsonschema.Schema{
+		URL:           "docs/spec/transactions/transaction.json",
+		Types:         []string{"integer"},
+		MinProperties: -1,
+		MaxProperties: -1,
+		MinItems:      -1,
+		MaxItems:      -1,
+		MinLength:     -1,
+		MaxLength:     -1,
+	}
+	s[100] = jsonschema.Schema{
+		URL:           "docs/spec/transactions/transaction.json",
+		Types:         []string{"string"},
+		MinProperties: -1,
+		MaxProperties: -1,
+		MinItems:      -1,
+		MaxItems:      -1,
+		MinLength:     -1,
+		MaxLength:     1024,
+	}
+	return &s[0]
+}
@@ -19,6 +19,7 @@ package model
 
 import (
 	"context"
+	"encoding/json"
 	"fmt"
 	"net"
 	"net/http"
@@ -124,6 +125,38 @@ func TestTransactionTransform(t *testing.T) {
 			},
 			Msg: "Full Event",
 		},
+		{
+			Transaction: Transaction{
+				ID:        id,
+				Type:      "tx",
+				Duration:  65.98,
+				SpanCount: SpanCount{Dropped: &dropped},
+				DroppedSpansStats: []DroppedSpanStats{{
+					DestinationServiceResource: "mysql",
+					Outcome:                    "success",
+					Duration:                   AggregatedDuration{Count: 3, Sum: 1500 * time.Microsecond},
+				}, {
+					DestinationServiceResource: "elasticsearch",
+					Duration:                   AggregatedDuration{Count: 2, Sum: 20 * time.Microsecond},
+				}},
+			},
+			Output: common.MapStr{
+				"id":         id,
+				"type":       "tx",
+				"duration":   common.MapStr{"us": 65980},
+				"span_count": common.MapStr{"dropped": 5},
+				"dropped_spans_stats": []common.MapStr{{
+					"destination_service_resource": "mysql",
+					"outcome":                      "success",
+					"duration":                     common.MapStr{"count": 3, "sum": common.MapStr{"us": int64(1500)}},
+				}, {
+					"destination_service_resource": "elasticsearch",
+					"duration":                     common.MapStr{"count": 2, "sum": common.MapStr{"us": int64(20)}},
+				}},
+				"sampled": true,
+			},
+			Msg: "Dropped spans stats",
+		},
 	}
 
 	tctx := &transform.Context{}
@@ -276,3 +309,55 @@ func TestTransactionTransformPage(t *testing.T) {
 		assert.Equal(t, test.Output, output[0].Fields["url"], fmt.Sprintf("Failed at idx %v; %s", idx, test.Msg))
 	}
 }
+
+func TestTransactionTransformMergesLabels(t *testing.T) {
+	metadata := Metadata{
+		Service: Service{Name: "myservice"},
+		Labels:  common.MapStr{"a": true, "b": "metadata"},
+	}
+	metadata.Prepare()
+	tx := Transaction{
+		Metadata: metadata,
+		Labels:   &Labels{"b": "event", "c": json.Number("1.5"), "d": nil},
+	}
+
+	// Labels from the shared metadata must not be modified by
+	// merging in event-specific labels.
+	for i := 0; i < 2; i++ {
+		events := tx.Transform(context.Background(), &transform.Context{})
+		require.Len(t, events, 1)
+		assert.Equal(t, common.MapStr{"a": true, "b": "event", "c": common.Float(1.5)}, events[0].Fields["labels"])
+	}
+	assert.Equal(t, common.MapStr{"a": true, "b": "metadata"}, metadata.Labels)
+}
+
+func BenchmarkTransactionTransform(b *testing.B) {
+	url, referer := "https://localhost", "http://localhost"
+	metadata := Metadata{
+		Service: Service{Name: "myservice", Version: "2.1.3", Agent: Agent{Name: "go", Version: "1.0"}},
+		System:  System{DetectedHostname: "a.b.c", Architecture: "x64", Platform: "linux"},
+		Labels:  common.MapStr{"a": true},
+	}
+	metadata.Prepare()
+	tx := Transaction{
+		Metadata:  metadata,
+		ID:        "123",
+		Type:      "request",
+		TraceID:   "abc",
+		Timestamp: time.Now(),
+		Labels:    &Labels{"b": "c"},
+		Page:      &Page{URL: &URL{Original: &url}, Referer: &referer},
+		HTTP: &Http{
+			Request:  &Req{Method: "post", Socket: &Socket{}, Headers: http.Header{"Accept": []string{"*/*"}}},
+			Response: &Resp{MinimalResp: MinimalResp{Headers: http.Header{"Content-Type": []string{"text/html"}}}},
+		},
+		URL: &URL{Original: &url},
+	}
+	tctx := &transform.Context{}
+
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		tx.Transform(context.Background(), tctx)
+	}
+}
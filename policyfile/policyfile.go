@@ -0,0 +1,146 @@
+// Licensed to Elasticsearch B.V. under one or more contributor
+// license agreements. See the NOTICE file distributed with
+// this work for additional information regarding copyright
+// ownership. Elasticsearch B.V. licenses this file to you under
+// the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+// Package policyfile provides access to policies loaded from YAML files,
+// which are reloaded when the files change.
+package policyfile
+
+import (
+	"context"
+	"os"
+	"sync"
+	"time"
+
+	"github.com/pkg/errors"
+
+	"github.com/elastic/beats/v7/libbeat/common"
+	"github.com/elastic/beats/v7/libbeat/logp"
+
+	logs "github.com/elastic/apm-server/log"
+)
+
+// UnpackFunc unpacks the contents of a policy file into a value.
+type UnpackFunc func(*common.Config) (interface{}, error)
+
+// File holds the value most recently unpacked from a YAML file.
+//
+// The file is checked for changes to its modification time or size at
+// most once per reload period. Changed files are unpacked in full before
+// the value is replaced, so readers observe either the previous value or
+// the new one. If the file cannot be read or unpacked, the error is logged
+// and the previous value retained.
+type File struct {
+	path   string
+	period time.Duration
+	unpack UnpackFunc
+	logger *logp.Logger
+
+	mu       sync.Mutex
+	checked  time.Time
+	modTime  time.Time
+	size     int64
+	value    interface{}
+	changed  bool
+	hasValue bool
+}
+
+// New returns a new File for the YAML file at path, reloaded at most
+// once per period. The file is first read by Load.
+func New(path string, period time.Duration, unpack UnpackFunc) *File {
+	return &File{
+		path:   path,
+		period: period,
+		unpack: unpack,
+		logger: logp.NewLogger(logs.PolicyFiles),
+	}
+}
+
+// Unpack reads and unpacks the file at path, for validating
+// policy files when loading the server configuration.
+func Unpack(path string, unpack UnpackFunc) (interface{}, error) {
+	cfg, err := common.LoadFile(path)
+	if err != nil {
+		return nil, errors.Wrapf(err, "loading policy file %q", path)
+	}
+	v, err := unpack(cfg)
+	if err != nil {
+		return nil, errors.Wrapf(err, "unpacking policy file %q", path)
+	}
+	return v, nil
+}
+
+// Load returns the value unpacked from the file, reloading the file if
+// it has changed and was last checked at least one reload period ago.
+// Load returns nil if the file has never been unpacked successfully.
+//
+// The changed result reports whether the value has been replaced since
+// the previous call to Load.
+func (f *File) Load() (value interface{}, changed bool) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if now := time.Now(); f.checked.IsZero() || now.Sub(f.checked) >= f.period {
+		f.checked = now
+		f.reloadLocked()
+	}
+	changed, f.changed = f.changed, false
+	return f.value, changed
+}
+
+// Watch checks the file for changes once per reload period until ctx is
+// cancelled, calling changed with each value unpacked from the file after
+// it changes. Values are only observed by one of Watch and Load.
+func (f *File) Watch(ctx context.Context, changed func(value interface{})) error {
+	ticker := time.NewTicker(f.period)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case now := <-ticker.C:
+			f.mu.Lock()
+			f.checked = now
+			f.reloadLocked()
+			value, ok := f.value, f.changed
+			f.changed = false
+			f.mu.Unlock()
+			if ok {
+				changed(value)
+			}
+		}
+	}
+}
+
+func (f *File) reloadLocked() {
+	info, err := os.Stat(f.path)
+	if err != nil {
+		f.logger.Errorf("failed to stat policy file %q: %s", f.path, err)
+		return
+	}
+	if f.hasValue && info.ModTime().Equal(f.modTime) && info.Size() == f.size {
+		return
+	}
+	v, err := Unpack(f.path, f.unpack)
+	if err != nil {
+		f.logger.Errorf("failed to reload policy file, keeping previous policies: %s", err)
+		return
+	}
+	if f.hasValue {
+		f.logger.Infof("reloaded policy file %q", f.path)
+	}
+	f.modTime, f.size = info.ModTime(), info.Size()
+	f.value, f.changed, f.hasValue = v, true, true
+}
@@ -0,0 +1,137 @@
+// Licensed to Elasticsearch B.V. under one or more contributor
+// license agreements. See the NOTICE file distributed with
+// this work for additional information regarding copyright
+// ownership. Elasticsearch B.V. licenses this file to you under
+// the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package policyfile
+
+import (
+	"context"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/elastic/beats/v7/libbeat/common"
+)
+
+func unpackRates(cfg *common.Config) (interface{}, error) {
+	var v struct {
+		Rates []float64 `config:"rates" validate:"required"`
+	}
+	if err := cfg.Unpack(&v); err != nil {
+		return nil, err
+	}
+	return v.Rates, nil
+}
+
+func TestFileLoad(t *testing.T) {
+	dir, err := ioutil.TempDir("", "policyfile")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+	path := filepath.Join(dir, "policies.yml")
+
+	write := func(content string, modTime time.Time) {
+		require.NoError(t, ioutil.WriteFile(path, []byte(content), 0644))
+		require.NoError(t, os.Chtimes(path, modTime, modTime))
+	}
+	now := time.Now()
+	write("rates: [0.1, 1]", now)
+
+	f := New(path, 0, unpackRates)
+	v, changed := f.Load()
+	assert.True(t, changed)
+	assert.Equal(t, []float64{0.1, 1}, v)
+
+	v, changed = f.Load()
+	assert.False(t, changed)
+	assert.Equal(t, []float64{0.1, 1}, v)
+
+	// Invalid policies are not loaded, and the previous value is retained.
+	write("rates: [", now.Add(time.Second))
+	v, changed = f.Load()
+	assert.False(t, changed)
+	assert.Equal(t, []float64{0.1, 1}, v)
+
+	write("rates: [0.5]", now.Add(2*time.Second))
+	v, changed = f.Load()
+	assert.True(t, changed)
+	assert.Equal(t, []float64{0.5}, v)
+}
+
+func TestFileLoadPeriod(t *testing.T) {
+	dir, err := ioutil.TempDir("", "policyfile")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+	path := filepath.Join(dir, "policies.yml")
+	require.NoError(t, ioutil.WriteFile(path, []byte("rates: [1]"), 0644))
+
+	f := New(path, time.Hour, unpackRates)
+	v, _ := f.Load()
+	assert.Equal(t, []float64{1}, v)
+
+	// The file is not checked again until the period has passed.
+	require.NoError(t, ioutil.WriteFile(path, []byte("rates: [0.1, 0.2]"), 0644))
+	v, changed := f.Load()
+	assert.False(t, changed)
+	assert.Equal(t, []float64{1}, v)
+}
+
+func TestFileLoadMissing(t *testing.T) {
+	f := New("/does/not/exist.yml", 0, unpackRates)
+	v, changed := f.Load()
+	assert.False(t, changed)
+	assert.Nil(t, v)
+
+	_, err := Unpack("/does/not/exist.yml", unpackRates)
+	assert.Error(t, err)
+}
+
+func TestFileWatch(t *testing.T) {
+	dir, err := ioutil.TempDir("", "policyfile")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+	path := filepath.Join(dir, "policies.yml")
+	require.NoError(t, ioutil.WriteFile(path, []byte("rates: [1]"), 0644))
+
+	f := New(path, time.Millisecond, unpackRates)
+	v, _ := f.Load()
+	assert.Equal(t, []float64{1}, v)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	values := make(chan interface{}, 1)
+	done := make(chan error)
+	go func() {
+		done <- f.Watch(ctx, func(v interface{}) { values <- v })
+	}()
+
+	later := time.Now().Add(time.Second)
+	require.NoError(t, ioutil.WriteFile(path, []byte("rates: [0.5]"), 0644))
+	require.NoError(t, os.Chtimes(path, later, later))
+	select {
+	case v := <-values:
+		assert.Equal(t, []float64{0.5}, v)
+	case <-time.After(10 * time.Second):
+		t.Fatal("timed out waiting for the file to be reloaded")
+	}
+
+	cancel()
+	assert.NoError(t, <-done)
+}
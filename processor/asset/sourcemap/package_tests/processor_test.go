@@ -90,9 +90,8 @@ func TestPayloadAttrsMatchJsonSchema(t *testing.T) {
 }
 
 func TestAttributesPresenceRequirementInSourcemap(t *testing.T) {
-	procSetup.AttrsPresence(t,
-		tests.NewSet("service_name", "service_version",
-			"bundle_filepath", "sourcemap"), nil)
+	// the sourcemap is sent as separate form field, and not part of the schema
+	procSetup.AttrsPresence(t, tests.NewSet("sourcemap"), nil)
 }
 
 func TestKeywordLimitationOnSourcemapAttributes(t *testing.T) {
@@ -53,6 +53,10 @@ const (
 type Consumer struct {
 	TransformConfig transform.Config
 	Reporter        publish.Reporter
+
+	// AttributeMappings holds mappings of resource and span attributes
+	// to Elastic APM fields, overriding the default translation.
+	AttributeMappings []AttributeMapping
 }
 
 // ConsumeTraceData consumes OpenTelemetry trace data,
@@ -69,8 +73,9 @@ func (c *Consumer) ConsumeTraceData(ctx context.Context, td consumerdata.TraceDa
 }
 
 func (c *Consumer) convert(td consumerdata.TraceData) *model.Batch {
+	mappings := makeAttributeMappings(c.AttributeMappings)
 	md := model.Metadata{}
-	parseMetadata(td, &md)
+	parseMetadata(td, mappings, &md)
 	hostname := md.System.DetectedHostname
 
 	logger := logp.NewLogger(logs.Otel)
@@ -115,7 +120,7 @@ func (c *Consumer) convert(td consumerdata.TraceData) *model.Batch {
 				Duration:  duration,
 				Name:      name,
 			}
-			parseTransaction(otelSpan, hostname, &transaction)
+			parseTransaction(otelSpan, hostname, mappings, &transaction)
 			batch.Transactions = append(batch.Transactions, &transaction)
 			for _, err := range parseErrors(logger, td.SourceFormat, otelSpan) {
 				addTransactionCtxToErr(transaction, err)
@@ -132,7 +137,7 @@ func (c *Consumer) convert(td consumerdata.TraceData) *model.Batch {
 				Duration:  duration,
 				Name:      name,
 			}
-			parseSpan(otelSpan, &span)
+			parseSpan(otelSpan, mappings, &span)
 			batch.Spans = append(batch.Spans, &span)
 			for _, err := range parseErrors(logger, td.SourceFormat, otelSpan) {
 				addSpanCtxToErr(span, hostname, err)
@@ -143,7 +148,7 @@ func (c *Consumer) convert(td consumerdata.TraceData) *model.Batch {
 	return &batch
 }
 
-func parseMetadata(td consumerdata.TraceData, md *model.Metadata) {
+func parseMetadata(td consumerdata.TraceData, mappings attributeMappings, md *model.Metadata) {
 	md.Service.Name = truncate(td.Node.GetServiceInfo().GetName())
 	if md.Service.Name == "" {
 		md.Service.Name = "unknown"
@@ -199,17 +204,21 @@ func parseMetadata(td consumerdata.TraceData, md *model.Metadata) {
 
 	md.Labels = make(common.MapStr)
 	for key, val := range td.Node.GetAttributes() {
-		md.Labels[key] = truncate(val)
+		if !mappings.apply(key, val, md, md.Labels) {
+			md.Labels[key] = truncate(val)
+		}
 	}
 	if t := td.Resource.GetType(); t != "" {
 		md.Labels["resource"] = truncate(t)
 	}
 	for key, val := range td.Resource.GetLabels() {
-		md.Labels[key] = truncate(val)
+		if !mappings.apply(key, val, md, md.Labels) {
+			md.Labels[key] = truncate(val)
+		}
 	}
 }
 
-func parseTransaction(span *tracepb.Span, hostname string, event *model.Transaction) {
+func parseTransaction(span *tracepb.Span, hostname string, mappings attributeMappings, event *model.Transaction) {
 	labels := make(common.MapStr)
 	var http model.Http
 	var message model.Message
@@ -218,6 +227,9 @@ func parseTransaction(span *tracepb.Span, hostname string, event *model.Transact
 	var hasFailed bool
 	var isHTTP, isMessaging bool
 	for kDots, v := range span.Attributes.GetAttributeMap() {
+		if mappings.apply(kDots, attributeValue(v), &event.Metadata, labels) {
+			continue
+		}
 		k := replaceDots(kDots)
 		switch v := v.Value.(type) {
 		case *tracepb.AttributeValue_BoolValue:
@@ -314,7 +326,7 @@ func parseTransaction(span *tracepb.Span, hostname string, event *model.Transact
 	event.Labels = &l
 }
 
-func parseSpan(span *tracepb.Span, event *model.Span) {
+func parseSpan(span *tracepb.Span, mappings attributeMappings, event *model.Span) {
 	labels := make(common.MapStr)
 
 	var http model.HTTP
@@ -325,6 +337,9 @@ func parseSpan(span *tracepb.Span, event *model.Span) {
 	var isDBSpan, isHTTPSpan, isMessagingSpan bool
 	var component string
 	for kDots, v := range span.Attributes.GetAttributeMap() {
+		if mappings.apply(kDots, attributeValue(v), &event.Metadata, labels) {
+			continue
+		}
 		k := replaceDots(kDots)
 		switch v := v.Value.(type) {
 		case *tracepb.AttributeValue_BoolValue:
@@ -434,6 +449,10 @@ func parseSpan(span *tracepb.Span, event *model.Span) {
 func parseErrors(logger *logp.Logger, source string, otelSpan *tracepb.Span) []*model.Error {
 	var errors []*model.Error
 	for _, log := range otelSpan.GetTimeEvents().GetTimeEvent() {
+		if isExceptionEvent(log.GetAnnotation()) {
+			errors = append(errors, parseExceptionEvent(log))
+			continue
+		}
 		var isError, hasMinimalInfo bool
 		var err model.Error
 		var logMessage, exMessage, exType string
@@ -32,7 +32,9 @@ import (
 	"github.com/stretchr/testify/require"
 
 	"github.com/elastic/beats/v7/libbeat/beat"
+	"github.com/elastic/beats/v7/libbeat/common"
 
+	"github.com/elastic/apm-server/model"
 	"github.com/elastic/apm-server/publish"
 	"github.com/elastic/apm-server/tests/approvals"
 )
@@ -402,6 +404,130 @@ func testTimeEvents() *tracepb.Span_TimeEvents {
 				}}}}}}}
 }
 
+func TestConsumer_AttributeMappings(t *testing.T) {
+	consumer := Consumer{AttributeMappings: []AttributeMapping{
+		{Source: "deployment.environment", Target: "service.environment"},
+		{Source: "k8s.pod.name", Target: "kubernetes.pod.name"},
+		{Source: "team", Target: "labels.owner"},
+		{Source: "service.version", Target: "service.version"},
+		{Source: "retries", Target: "labels.retry.count"},
+	}}
+	batch := consumer.convert(consumerdata.TraceData{
+		SourceFormat: "jaeger",
+		Node: &commonpb.Node{
+			ServiceInfo: &commonpb.ServiceInfo{Name: "foo"},
+			Attributes:  map[string]string{"deployment.environment": "production", "other": "value"},
+		},
+		Resource: &resourcepb.Resource{
+			Labels: map[string]string{"k8s.pod.name": "foo-pod", "team": "apm"},
+		},
+		Spans: []*tracepb.Span{{
+			Kind:      tracepb.Span_SERVER,
+			StartTime: testStartTime(),
+			Attributes: &tracepb.Span_Attributes{AttributeMap: map[string]*tracepb.AttributeValue{
+				"service.version": testAttributeStringValue("1.2.3"),
+				"retries":         testAttributeIntValue(2),
+				"component":       testAttributeStringValue("http"),
+			}},
+		}, {
+			ParentSpanId: []byte{0, 0, 0, 0, 70, 70, 48, 88},
+			StartTime:    testStartTime(),
+		}},
+	})
+
+	require.Len(t, batch.Transactions, 1)
+	tx := batch.Transactions[0]
+	assert.Equal(t, "production", tx.Metadata.Service.Environment)
+	assert.Equal(t, "foo-pod", tx.Metadata.System.Kubernetes.PodName)
+	assert.Equal(t, "1.2.3", tx.Metadata.Service.Version)
+	assert.Equal(t, common.MapStr{"owner": "apm", "other": "value"}, tx.Metadata.Labels)
+	require.NotNil(t, tx.Labels)
+	assert.Equal(t, model.Labels{
+		"retry_count": int64(2),
+		"component":   "http",
+	}, *tx.Labels)
+
+	// Span attribute mappings apply only to the event they are recorded on.
+	require.Len(t, batch.Spans, 1)
+	assert.Equal(t, "production", batch.Spans[0].Metadata.Service.Environment)
+	assert.Equal(t, "", batch.Spans[0].Metadata.Service.Version)
+}
+
+func TestConsumer_ExceptionEvents(t *testing.T) {
+	javaStacktrace := "java.lang.IllegalStateException: boom\n" +
+		"\tat com.example.Service.handle(Service.java:42)\n" +
+		"\tat com.example.Main.main(Main.java)\n" +
+		"\t... 2 more"
+	exceptionEvent := func(attrs map[string]*tracepb.AttributeValue) *tracepb.Span_TimeEvent {
+		return &tracepb.Span_TimeEvent{
+			Time: testStartTime(),
+			Value: &tracepb.Span_TimeEvent_Annotation_{Annotation: &tracepb.Span_TimeEvent_Annotation{
+				Description: testTruncatableString("exception"),
+				Attributes:  &tracepb.Span_Attributes{AttributeMap: attrs},
+			}},
+		}
+	}
+
+	batch := (&Consumer{}).convert(consumerdata.TraceData{
+		SourceFormat: "otlp",
+		Spans: []*tracepb.Span{{
+			TraceId:   []byte{0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 70, 70, 120, 48},
+			SpanId:    []byte{0, 0, 0, 0, 65, 65, 70, 70},
+			Kind:      tracepb.Span_SERVER,
+			StartTime: testStartTime(),
+			TimeEvents: &tracepb.Span_TimeEvents{TimeEvent: []*tracepb.Span_TimeEvent{
+				exceptionEvent(map[string]*tracepb.AttributeValue{
+					"exception.type":       testAttributeStringValue("java.lang.IllegalStateException"),
+					"exception.message":    testAttributeStringValue("boom"),
+					"exception.stacktrace": testAttributeStringValue(javaStacktrace),
+					"exception.escaped":    testAttributeBoolValue(true),
+				}),
+				// Not an exception: neither type nor message are recorded.
+				exceptionEvent(map[string]*tracepb.AttributeValue{
+					"exception.stacktrace": testAttributeStringValue(javaStacktrace),
+				}),
+			}},
+		}, {
+			TraceId:      []byte{0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 70, 70, 120, 48},
+			SpanId:       []byte{0, 0, 0, 0, 65, 65, 70, 71},
+			ParentSpanId: []byte{0, 0, 0, 0, 65, 65, 70, 70},
+			StartTime:    testStartTime(),
+			TimeEvents: &tracepb.Span_TimeEvents{TimeEvent: []*tracepb.Span_TimeEvent{
+				exceptionEvent(map[string]*tracepb.AttributeValue{
+					"exception.type":       testAttributeStringValue("ZeroDivisionError"),
+					"exception.stacktrace": testAttributeStringValue("Traceback (most recent call last):\n..."),
+				}),
+			}},
+		}},
+	})
+
+	require.Len(t, batch.Errors, 2)
+	txErr, spanErr := batch.Errors[0], batch.Errors[1]
+
+	assert.Equal(t, "00000000000000000000000046467830", txErr.TraceID)
+	assert.Equal(t, "0000000041414646", txErr.TransactionID)
+	assert.Equal(t, "0000000041414646", txErr.ParentID)
+	require.NotNil(t, txErr.Exception)
+	assert.Equal(t, "java.lang.IllegalStateException", *txErr.Exception.Type)
+	assert.Equal(t, "boom", *txErr.Exception.Message)
+	assert.Equal(t, false, *txErr.Exception.Handled)
+	require.Len(t, txErr.Exception.Stacktrace, 2)
+	assert.Equal(t, "com.example.Service", *txErr.Exception.Stacktrace[0].Classname)
+	assert.Equal(t, "handle", *txErr.Exception.Stacktrace[0].Function)
+	assert.Equal(t, "Service.java", *txErr.Exception.Stacktrace[0].Filename)
+	assert.Equal(t, 42, *txErr.Exception.Stacktrace[0].Lineno)
+	assert.Nil(t, txErr.Exception.Stacktrace[1].Lineno)
+
+	assert.Equal(t, "0000000041414647", spanErr.ParentID)
+	require.NotNil(t, spanErr.Exception)
+	assert.Equal(t, "ZeroDivisionError", *spanErr.Exception.Type)
+	assert.Nil(t, spanErr.Exception.Message)
+	assert.Empty(t, spanErr.Exception.Stacktrace)
+	assert.Equal(t, map[string]interface{}{
+		"stacktrace": "Traceback (most recent call last):\n...",
+	}, spanErr.Exception.Attributes)
+}
+
 func file(f string) string {
 	return filepath.Join("test_approved", f)
 }
@@ -0,0 +1,122 @@
+// Licensed to Elasticsearch B.V. under one or more contributor
+// license agreements. See the NOTICE file distributed with
+// this work for additional information regarding copyright
+// ownership. Elasticsearch B.V. licenses this file to you under
+// the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package otel
+
+import (
+	"regexp"
+	"strconv"
+	"strings"
+
+	tracepb "github.com/census-instrumentation/opencensus-proto/gen-go/trace/v1"
+
+	"github.com/elastic/apm-server/model"
+)
+
+const (
+	exceptionEventName           = "exception"
+	exceptionTypeAttribute       = "exception.type"
+	exceptionMessageAttribute    = "exception.message"
+	exceptionStacktraceAttribute = "exception.stacktrace"
+	exceptionEscapedAttribute    = "exception.escaped"
+)
+
+// javaStacktraceFrameRegexp matches Java stack trace frames of the form
+// "at com.example.Class.method(Class.java:123)".
+var javaStacktraceFrameRegexp = regexp.MustCompile(`^\s*at\s+(?:(.+)\.)?([^.(]+)\(([^:)]*)(?::(\d+))?\)\s*$`)
+
+// isExceptionEvent reports whether a span event follows the OpenTelemetry
+// semantic conventions for exceptions: it is named "exception", and has at
+// least one of the "exception.type" or "exception.message" attributes.
+//
+// Jaeger records the event name in the "event" attribute of span logs.
+func isExceptionEvent(annotation *tracepb.Span_TimeEvent_Annotation) bool {
+	attrs := annotation.GetAttributes().GetAttributeMap()
+	name := annotation.GetDescription().GetValue()
+	if name == "" {
+		name = attrs["event"].GetStringValue().GetValue()
+	}
+	if name != exceptionEventName {
+		return false
+	}
+	_, hasType := attrs[exceptionTypeAttribute]
+	_, hasMessage := attrs[exceptionMessageAttribute]
+	return hasType || hasMessage
+}
+
+// parseExceptionEvent translates an OpenTelemetry exception span event into
+// an Elastic APM error, preserving the exception type, message, and stack trace.
+func parseExceptionEvent(event *tracepb.Span_TimeEvent) *model.Error {
+	var exception model.Exception
+	for k, v := range event.GetAnnotation().GetAttributes().GetAttributeMap() {
+		switch k {
+		case exceptionTypeAttribute:
+			if s := truncate(v.GetStringValue().GetValue()); s != "" {
+				exception.Type = &s
+			}
+		case exceptionMessageAttribute:
+			if s := v.GetStringValue().GetValue(); s != "" {
+				exception.Message = &s
+			}
+		case exceptionStacktraceAttribute:
+			stacktrace := v.GetStringValue().GetValue()
+			if frames := parseJavaStacktrace(stacktrace); len(frames) > 0 {
+				exception.Stacktrace = frames
+			} else if stacktrace != "" {
+				// Keep stack traces in unrecognised formats verbatim.
+				exception.Attributes = map[string]interface{}{"stacktrace": stacktrace}
+			}
+		case exceptionEscapedAttribute:
+			if escaped, ok := v.GetValue().(*tracepb.AttributeValue_BoolValue); ok {
+				handled := !escaped.BoolValue
+				exception.Handled = &handled
+			}
+		}
+	}
+	return &model.Error{
+		Timestamp: parseTimestamp(event.GetTime()),
+		Exception: &exception,
+	}
+}
+
+// parseJavaStacktrace parses a Java stack trace as recorded by the
+// OpenTelemetry Java SDK, returning nil if s is in any other format.
+func parseJavaStacktrace(s string) model.Stacktrace {
+	var frames model.Stacktrace
+	for _, line := range strings.Split(s, "\n") {
+		match := javaStacktraceFrameRegexp.FindStringSubmatch(line)
+		if match == nil {
+			continue
+		}
+		frame := model.StacktraceFrame{}
+		if match[1] != "" {
+			classname := match[1]
+			frame.Classname = &classname
+		}
+		function := match[2]
+		frame.Function = &function
+		if match[3] != "" {
+			filename := match[3]
+			frame.Filename = &filename
+		}
+		if lineno, err := strconv.Atoi(match[4]); err == nil {
+			frame.Lineno = &lineno
+		}
+		frames = append(frames, &frame)
+	}
+	return frames
+}
@@ -0,0 +1,135 @@
+// Licensed to Elasticsearch B.V. under one or more contributor
+// license agreements. See the NOTICE file distributed with
+// this work for additional information regarding copyright
+// ownership. Elasticsearch B.V. licenses this file to you under
+// the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package otel
+
+import (
+	"strconv"
+	"strings"
+
+	tracepb "github.com/census-instrumentation/opencensus-proto/gen-go/trace/v1"
+
+	"github.com/elastic/beats/v7/libbeat/common"
+
+	"github.com/elastic/apm-server/model"
+	"github.com/elastic/apm-server/utility"
+)
+
+const labelsTargetPrefix = "labels."
+
+// AttributeMapping maps an OpenTelemetry resource or span attribute to
+// an Elastic APM field, overriding the default translation.
+type AttributeMapping struct {
+	// Source holds the name of the attribute, e.g. "deployment.environment".
+	Source string
+
+	// Target holds the name of the field to which the attribute is mapped,
+	// e.g. "service.environment". Targets prefixed with "labels." map the
+	// attribute to a label with the given name.
+	Target string
+}
+
+// metadataTargets holds the setters for the metadata fields which
+// attributes may be mapped to.
+var metadataTargets = map[string]func(*model.Metadata, string){
+	"service.name":         func(md *model.Metadata, v string) { md.Service.Name = v },
+	"service.version":      func(md *model.Metadata, v string) { md.Service.Version = v },
+	"service.environment":  func(md *model.Metadata, v string) { md.Service.Environment = v },
+	"service.node.name":    func(md *model.Metadata, v string) { md.Service.Node.Name = v },
+	"host.hostname":        func(md *model.Metadata, v string) { md.System.DetectedHostname = v },
+	"host.name":            func(md *model.Metadata, v string) { md.System.ConfiguredHostname = v },
+	"container.id":         func(md *model.Metadata, v string) { md.System.Container.ID = v },
+	"kubernetes.namespace": func(md *model.Metadata, v string) { md.System.Kubernetes.Namespace = v },
+	"kubernetes.pod.name":  func(md *model.Metadata, v string) { md.System.Kubernetes.PodName = v },
+	"kubernetes.pod.uid":   func(md *model.Metadata, v string) { md.System.Kubernetes.PodUID = v },
+}
+
+// AttributeMappingTargets returns the names of the non-label fields
+// which attributes may be mapped to.
+func AttributeMappingTargets() []string {
+	targets := make([]string, 0, len(metadataTargets))
+	for target := range metadataTargets {
+		targets = append(targets, target)
+	}
+	return targets
+}
+
+// attributeMappings holds the configured attribute mappings, keyed by source.
+type attributeMappings map[string]string
+
+func makeAttributeMappings(in []AttributeMapping) attributeMappings {
+	if len(in) == 0 {
+		return nil
+	}
+	out := make(attributeMappings, len(in))
+	for _, m := range in {
+		out[m.Source] = m.Target
+	}
+	return out
+}
+
+// apply applies the mapping configured for the attribute k, if any, setting
+// either a metadata field in md or a label in labels. apply reports whether
+// a mapping was applied; if not, the attribute should be translated as usual.
+func (m attributeMappings) apply(k string, v interface{}, md *model.Metadata, labels common.MapStr) bool {
+	target, ok := m[k]
+	if !ok {
+		return false
+	}
+	if strings.HasPrefix(target, labelsTargetPrefix) {
+		if s, ok := v.(string); ok {
+			v = truncate(s)
+		}
+		utility.DeepUpdate(labels, replaceDots(strings.TrimPrefix(target, labelsTargetPrefix)), v)
+		return true
+	}
+	set, ok := metadataTargets[target]
+	if !ok {
+		return false
+	}
+	set(md, truncate(attributeString(v)))
+	return true
+}
+
+// attributeValue returns the Go value of an OpenCensus attribute value.
+func attributeValue(v *tracepb.AttributeValue) interface{} {
+	switch v := v.GetValue().(type) {
+	case *tracepb.AttributeValue_BoolValue:
+		return v.BoolValue
+	case *tracepb.AttributeValue_DoubleValue:
+		return v.DoubleValue
+	case *tracepb.AttributeValue_IntValue:
+		return v.IntValue
+	case *tracepb.AttributeValue_StringValue:
+		return v.StringValue.GetValue()
+	}
+	return nil
+}
+
+func attributeString(v interface{}) string {
+	switch v := v.(type) {
+	case string:
+		return v
+	case bool:
+		return strconv.FormatBool(v)
+	case int64:
+		return strconv.FormatInt(v, 10)
+	case float64:
+		return strconv.FormatFloat(v, 'f', -1, 64)
+	}
+	return ""
+}
@@ -0,0 +1,56 @@
+// Licensed to Elasticsearch B.V. under one or more contributor
+// license agreements. See the NOTICE file distributed with
+// this work for additional information regarding copyright
+// ownership. Elasticsearch B.V. licenses this file to you under
+// the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package stream
+
+import (
+	"context"
+	"io/ioutil"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/elastic/apm-server/beater/config"
+	"github.com/elastic/apm-server/processor/stream/generated/examples"
+	"github.com/elastic/apm-server/publish"
+	"github.com/elastic/apm-server/transform"
+)
+
+func TestExamples(t *testing.T) {
+	cfg := config.DefaultConfig("8.0.0")
+	for _, test := range []struct {
+		file      string
+		example   string
+		processor *Processor
+	}{
+		{"../../testdata/intake-v2/events.ndjson", examples.Events, BackendProcessor(cfg)},
+		{"../../testdata/intake-v3/rum_events.ndjson", examples.RUMV3Events, RUMV3Processor(cfg, &transform.Config{})},
+	} {
+		t.Run(test.file, func(t *testing.T) {
+			data, err := ioutil.ReadFile(test.file)
+			require.NoError(t, err)
+			assert.Equal(t, string(data), test.example, "examples out of date, run `go generate`")
+
+			report := func(context.Context, publish.PendingReq) error { return nil }
+			result := test.processor.HandleStream(context.Background(), nil, map[string]interface{}{}, strings.NewReader(test.example), report)
+			assert.Empty(t, result.Errors)
+			assert.NotZero(t, result.Accepted)
+		})
+	}
+}
@@ -0,0 +1,166 @@
+// Licensed to Elasticsearch B.V. under one or more contributor
+// license agreements. See the NOTICE file distributed with
+// this work for additional information regarding copyright
+// ownership. Elasticsearch B.V. licenses this file to you under
+// the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package package_tests
+
+import (
+	"context"
+	"io"
+	"testing"
+	"time"
+
+	"github.com/santhosh-tekuri/jsonschema"
+	"github.com/stretchr/testify/require"
+
+	"github.com/elastic/apm-server/decoder"
+	"github.com/elastic/apm-server/model"
+	errorschema "github.com/elastic/apm-server/model/error/generated/schema"
+	metricsetschema "github.com/elastic/apm-server/model/metricset/generated/schema"
+	"github.com/elastic/apm-server/model/modeldecoder"
+	spanschema "github.com/elastic/apm-server/model/span/generated/schema"
+	transactionschema "github.com/elastic/apm-server/model/transaction/generated/schema"
+	"github.com/elastic/apm-server/tests"
+	"github.com/elastic/apm-server/tests/loader"
+	"github.com/elastic/apm-server/transform"
+	"github.com/elastic/apm-server/validation"
+)
+
+// benchmarkPayload holds hundreds of realistic events of each event type.
+const benchmarkPayload = "../testdata/intake-v2/heavy.ndjson"
+
+var benchmarkEventTypes = []struct {
+	name   string
+	schema *jsonschema.Schema
+	decode func(modeldecoder.Input, *model.Batch) error
+}{
+	{"error", errorschema.CompiledModelSchema(), modeldecoder.DecodeError},
+	{"metricset", metricsetschema.CompiledModelSchema(), modeldecoder.DecodeMetricset},
+	{"span", spanschema.CompiledModelSchema(), modeldecoder.DecodeSpan},
+	{"transaction", transactionschema.CompiledModelSchema(), modeldecoder.DecodeTransaction},
+}
+
+// Each benchmark operation validates, decodes or transforms all events of the
+// event type held by benchmarkPayload.
+func BenchmarkValidate(b *testing.B) {
+	for _, et := range benchmarkEventTypes {
+		b.Run(et.name, benchmarkValidate(et.name, et.schema))
+	}
+}
+
+func BenchmarkDecode(b *testing.B) {
+	for _, et := range benchmarkEventTypes {
+		b.Run(et.name, benchmarkDecode(et.name, et.decode))
+	}
+}
+
+func BenchmarkTransform(b *testing.B) {
+	for _, et := range benchmarkEventTypes {
+		b.Run(et.name, benchmarkTransform(et.name, et.decode))
+	}
+}
+
+// TestBenchmarkBaseline fails on regressions of the benchmarks against the stored baseline,
+// run with `make bench-check`.
+func TestBenchmarkBaseline(t *testing.T) {
+	benchmarks := make(map[string]func(*testing.B))
+	for _, et := range benchmarkEventTypes {
+		benchmarks["Validate/"+et.name] = benchmarkValidate(et.name, et.schema)
+		benchmarks["Decode/"+et.name] = benchmarkDecode(et.name, et.decode)
+		benchmarks["Transform/"+et.name] = benchmarkTransform(et.name, et.decode)
+	}
+	tests.CheckBenchmarkBaseline(t, "benchmark_baseline.json", benchmarks)
+}
+
+func benchmarkValidate(eventType string, schema *jsonschema.Schema) func(*testing.B) {
+	return func(b *testing.B) {
+		_, events := loadBenchmarkEvents(b, eventType)
+		b.ReportAllocs()
+		b.ResetTimer()
+		for i := 0; i < b.N; i++ {
+			for _, raw := range events {
+				if err := validation.Validate(raw, schema); err != nil {
+					b.Fatal(err)
+				}
+			}
+		}
+	}
+}
+
+func benchmarkDecode(eventType string, decode func(modeldecoder.Input, *model.Batch) error) func(*testing.B) {
+	return func(b *testing.B) {
+		metadata, events := loadBenchmarkEvents(b, eventType)
+		requestTime := time.Now()
+		var batch model.Batch
+		b.ReportAllocs()
+		b.ResetTimer()
+		for i := 0; i < b.N; i++ {
+			batch.Reset()
+			for _, raw := range events {
+				if err := decode(modeldecoder.Input{Raw: raw, RequestTime: requestTime, Metadata: *metadata}, &batch); err != nil {
+					b.Fatal(err)
+				}
+			}
+		}
+	}
+}
+
+func benchmarkTransform(eventType string, decode func(modeldecoder.Input, *model.Batch) error) func(*testing.B) {
+	return func(b *testing.B) {
+		metadata, events := loadBenchmarkEvents(b, eventType)
+		var batch model.Batch
+		for _, raw := range events {
+			require.NoError(b, decode(modeldecoder.Input{Raw: raw, RequestTime: time.Now(), Metadata: *metadata}, &batch))
+		}
+		transformables := batch.Transformables()
+		ctx, tctx := context.Background(), &transform.Context{}
+		b.ReportAllocs()
+		b.ResetTimer()
+		for i := 0; i < b.N; i++ {
+			for _, t := range transformables {
+				t.Transform(ctx, tctx)
+			}
+		}
+	}
+}
+
+// loadBenchmarkEvents returns the decoded metadata and the raw events of the
+// given type held by benchmarkPayload.
+func loadBenchmarkEvents(b *testing.B, eventType string) (*model.Metadata, []map[string]interface{}) {
+	r, err := loader.LoadDataAsStream(benchmarkPayload)
+	require.NoError(b, err)
+	defer r.Close()
+	reader := decoder.NewNDJSONStreamReader(r, lrSize)
+
+	rawMetadata, err := reader.Read()
+	require.NoError(b, err)
+	metadata, err := modeldecoder.DecodeMetadata(rawMetadata["metadata"], false)
+	require.NoError(b, err)
+
+	var events []map[string]interface{}
+	for err != io.EOF {
+		var e map[string]interface{}
+		e, err = reader.Read()
+		if err != io.EOF {
+			require.NoError(b, err)
+		}
+		if raw, ok := e[eventType].(map[string]interface{}); ok {
+			events = append(events, raw)
+		}
+	}
+	require.NotEmpty(b, events)
+	return metadata, events
+}
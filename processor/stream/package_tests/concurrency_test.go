@@ -0,0 +1,42 @@
+// Licensed to Elasticsearch B.V. under one or more contributor
+// license agreements. See the NOTICE file distributed with
+// this work for additional information regarding copyright
+// ownership. Elasticsearch B.V. licenses this file to you under
+// the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package package_tests
+
+import (
+	"testing"
+
+	"github.com/elastic/apm-server/tests"
+)
+
+// concurrentGoroutines is the number of goroutines validating and decoding
+// each mutated payload at the same time; run with -race to detect data races.
+const concurrentGoroutines = 8
+
+func TestConcurrentPayloadMutations(t *testing.T) {
+	for _, ps := range []*tests.ProcessorSetup{
+		errorProcSetup(),
+		metricsetProcSetup(),
+		spanProcSetup(),
+		transactionProcSetup(),
+	} {
+		t.Run(ps.SchemaPrefix, func(t *testing.T) {
+			ps.Goroutines = concurrentGoroutines
+			ps.AttrsPresence(t, nil, nil)
+		})
+	}
+}
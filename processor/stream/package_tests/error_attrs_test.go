@@ -60,6 +60,11 @@ func errorFieldsNotInPayloadAttrs() *tests.Set {
 		"view errors", "error id icon",
 		"host.ip", "transaction.name", "source.ip",
 		tests.Group("observer"),
+		tests.Group("tenant"),
+		tests.Group("event"),
+		tests.Group("trace.duration"),
+		"trace.transaction_count", "trace.span_count", "trace.error_count",
+		tests.Group("trace.services"),
 		tests.Group("user"),
 		tests.Group("client"),
 		tests.Group("destination"),
@@ -89,46 +94,9 @@ func errorPayloadAttrsNotInJsonSchema() *tests.Set {
 	)
 }
 
-func errorRequiredKeys() *tests.Set {
-	return tests.NewSet(
-		"error",
-		"error.id",
-		"error.log",
-		"error.exception",
-		"error.exception.type",
-		"error.exception.message",
-		"error.log.message",
-		"error.exception.stacktrace.filename",
-		"error.log.stacktrace.filename",
-		"error.exception.stacktrace.classname",
-		"error.log.stacktrace.classname",
-		"error.context.request.method",
-		"error.context.request.url",
-
-		"error.trace_id",
-		"error.parent_id",
-	)
-}
-
 type val = []interface{}
 type obj = map[string]interface{}
 
-func errorCondRequiredKeys() map[string]tests.Condition {
-	return map[string]tests.Condition{
-		"error.exception":                      {Absence: []string{"error.log"}},
-		"error.exception.message":              {Absence: []string{"error.exception.type"}},
-		"error.exception.type":                 {Absence: []string{"error.exception.message"}},
-		"error.exception.stacktrace.filename":  {Absence: []string{"error.exception.stacktrace.classname"}},
-		"error.exception.stacktrace.classname": {Absence: []string{"error.exception.stacktrace.filename"}},
-		"error.log":                            {Absence: []string{"error.exception"}},
-		"error.log.stacktrace.filename":        {Absence: []string{"error.log.stacktrace.classname"}},
-		"error.log.stacktrace.classname":       {Absence: []string{"error.log.stacktrace.filename"}},
-
-		"error.trace_id":  {Existence: obj{"error.parent_id": "abc123"}},
-		"error.parent_id": {Existence: obj{"error.trace_id": "abc123"}},
-	}
-}
-
 func errorKeywordExceptionKeys() *tests.Set {
 	return tests.NewSet(
 		"processor.event", "processor.name", "error.grouping_key",
@@ -143,6 +111,11 @@ func errorKeywordExceptionKeys() *tests.Set {
 		tests.Group("host"),
 		tests.Group("kubernetes"),
 		tests.Group("observer"),
+		tests.Group("tenant"),
+		tests.Group("event"),
+		tests.Group("trace.duration"),
+		"trace.transaction_count", "trace.span_count", "trace.error_count",
+		tests.Group("trace.services"),
 		tests.Group("process"),
 		tests.Group("service"),
 		tests.Group("user"),
@@ -172,22 +145,46 @@ func TestErrorPayloadAttrsMatchJsonSchema(t *testing.T) {
 }
 
 func TestErrorAttrsPresenceInError(t *testing.T) {
-	errorProcSetup().AttrsPresence(t, errorRequiredKeys(), errorCondRequiredKeys())
+	errorProcSetup().AttrsPresence(t, nil, nil)
+}
+
+func errorTemplateToSchema() []tests.FieldTemplateMapping {
+	return []tests.FieldTemplateMapping{
+		{Template: "timestamp.us", Mapping: "timestamp"},
+		{Template: "error."},
+		{Template: "transaction.id", Mapping: "transaction_id"},
+		{Template: "parent.id", Mapping: "parent_id"},
+		{Template: "trace.id", Mapping: "trace_id"},
+	}
 }
 
 func TestErrorKeywordLimitationOnErrorAttributes(t *testing.T) {
 	errorProcSetup().KeywordLimitation(
 		t,
 		errorKeywordExceptionKeys(),
-		[]tests.FieldTemplateMapping{
-			{Template: "error."},
-			{Template: "transaction.id", Mapping: "transaction_id"},
-			{Template: "parent.id", Mapping: "parent_id"},
-			{Template: "trace.id", Mapping: "trace_id"},
-		},
+		errorTemplateToSchema(),
 	)
 }
 
+func TestErrorTypeCompatibility(t *testing.T) {
+	errorProcSetup().TypeCompatibility(
+		t,
+		// numeric codes are converted to strings on decoding
+		tests.NewSet("error.exception.code"),
+		errorTemplateToSchema(),
+	)
+}
+
+func TestErrorDecodeRoundTrip(t *testing.T) {
+	errorProcSetup().DecodeRoundTrip(t, roundTripIterations, tests.NewSet(
+		// message context is not indexed for errors
+		tests.Group("error.context.message"),
+		// normalized or converted on decoding
+		"error.context.request.method",
+		"error.context.request.url.port",
+	))
+}
+
 func TestPayloadDataForError(t *testing.T) {
 	//// add test data for testing
 	//// * specific edge cases
@@ -22,12 +22,11 @@ import (
 	"context"
 	"errors"
 	"io"
-	"time"
+	"strings"
 
 	"github.com/santhosh-tekuri/jsonschema"
 
 	"github.com/elastic/apm-server/decoder"
-	"github.com/elastic/apm-server/model"
 	"github.com/elastic/apm-server/processor/stream"
 	"github.com/elastic/apm-server/publish"
 	"github.com/elastic/apm-server/tests"
@@ -45,7 +44,12 @@ type intakeTestProcessor struct {
 	stream.Processor
 }
 
-const lrSize = 100 * 1024
+const (
+	lrSize = 100 * 1024
+
+	// roundTripIterations is the number of random payloads decoded per event type.
+	roundTripIterations = 200
+)
 
 func (v *intakeTestProcessor) getReader(path string) (*decoder.NDJSONStreamReader, error) {
 	reader, err := loader.LoadDataAsStream(path)
@@ -74,32 +78,44 @@ func (v *intakeTestProcessor) readEvents(reader *decoder.NDJSONStreamReader) ([]
 	return events, nil
 }
 
+// LoadPayload loads the NDJSON stream, whose events are mutated by the tests.
 func (p *intakeTestProcessor) LoadPayload(path string) (interface{}, error) {
-	ndjson, err := p.getReader(path)
-	if err != nil {
-		return nil, err
-	}
-
-	// read and discard metadata
-	ndjson.Read()
-
-	return p.readEvents(ndjson)
+	return tests.LoadStreamPayload(path)
 }
 
+// Decode sends the stream to the processor, and transforms the decoded events.
 func (p *intakeTestProcessor) Decode(data interface{}) error {
-	events := data.([]interface{})
-	for _, e := range events {
-		err := p.Processor.HandleRawModel(e.(map[string]interface{}), &model.Batch{}, time.Now(), model.Metadata{})
-		if err != nil {
-			return err
-		}
-	}
-
-	return nil
+	return p.handleStream(data, true)
 }
 
+// Validate sends the stream to the processor.
 func (p *intakeTestProcessor) Validate(data interface{}) error {
-	return p.Decode(data)
+	return p.handleStream(data, false)
+}
+
+func (p *intakeTestProcessor) handleStream(data interface{}, transformEvents bool) error {
+	buf, err := data.(*tests.StreamPayload).NDJSON()
+	if err != nil {
+		return err
+	}
+	report := func(ctx context.Context, req publish.PendingReq) error {
+		if transformEvents {
+			for _, transformable := range req.Transformables {
+				transformable.Transform(ctx, req.Tcontext)
+			}
+		}
+		return nil
+	}
+	result := p.HandleStream(context.Background(), nil, map[string]interface{}{}, bytes.NewReader(buf), report)
+	if len(result.Errors) == 0 {
+		return nil
+	}
+	// leave out the offending documents, so errors are only matched against the messages
+	messages := make([]string, len(result.Errors))
+	for i, e := range result.Errors {
+		messages[i] = e.Message
+	}
+	return errors.New(strings.Join(messages, ", "))
 }
 
 func (p *intakeTestProcessor) Process(buf []byte) ([]beat.Event, error) {
@@ -0,0 +1,41 @@
+// Licensed to Elasticsearch B.V. under one or more contributor
+// license agreements. See the NOTICE file distributed with
+// this work for additional information regarding copyright
+// ownership. Elasticsearch B.V. licenses this file to you under
+// the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package package_tests
+
+import (
+	"os"
+	"testing"
+
+	"github.com/elastic/apm-server/tests"
+)
+
+// TestMain checks the share of schema constraints exercised by the tests, when run with
+// -check-constraint-coverage. Raise the minimums when adding tests for more constraints.
+func TestMain(m *testing.M) {
+	code := m.Run()
+	if code == 0 && !tests.CheckConstraintCoverage(os.Stdout, map[string]tests.ConstraintCoverageCheck{
+		"error":       {Setup: errorProcSetup(), Minimum: 0.13},
+		"metadata":    {Setup: metadataProcSetup(), Minimum: 0.02},
+		"metricset":   {Setup: metricsetProcSetup(), Minimum: 0.33},
+		"span":        {Setup: spanProcSetup(), Minimum: 0.32},
+		"transaction": {Setup: transactionProcSetup(), Minimum: 0.23},
+	}) {
+		code = 1
+	}
+	os.Exit(code)
+}
@@ -124,7 +124,30 @@ func TestMetadataPayloadMatchJsonSchema(t *testing.T) {
 	metadataProcSetup().AttrsMatchJsonSchema(t,
 		getMetadataEventAttrs(t, ""),
 		tests.NewSet(tests.Group("labels")),
-		nil,
+		tests.NewSet("schema_version"),
+	)
+}
+
+func metadataTemplateToSchema() []tests.FieldTemplateMapping {
+	return []tests.FieldTemplateMapping{
+		{Template: "agent.", Mapping: "service.agent."},
+		{Template: "container.", Mapping: "system.container."},
+		{Template: "kubernetes.", Mapping: "system.kubernetes."},
+		{Template: "host.os.platform", Mapping: "system.platform"},
+		{Template: "host.name", Mapping: "system.configured_hostname"},
+		{Template: "host.", Mapping: "system."},
+		{Template: "user.name", Mapping: "user.username"},
+		{Template: "service.node.name", Mapping: "service.node.configured_name"},
+		//{Template: "url.", Mapping:"context.request.url."},
+	}
+}
+
+func TestTypeCompatibilityOnMetadataAttrs(t *testing.T) {
+	metadataProcSetup().TypeCompatibility(
+		t,
+		// numeric IDs are converted to strings on decoding
+		tests.NewSet("user.id"),
+		metadataTemplateToSchema(),
 	)
 }
 
@@ -134,6 +157,8 @@ func TestKeywordLimitationOnMetadataAttrs(t *testing.T) {
 		tests.NewSet("processor.event", "processor.name",
 			"process.args",
 			tests.Group("observer"),
+			tests.Group("tenant"),
+			tests.Group("event"),
 			tests.Group("http"),
 			tests.Group("url"),
 			tests.Group("context.tags"),
@@ -144,17 +169,7 @@ func TestKeywordLimitationOnMetadataAttrs(t *testing.T) {
 			tests.Group("user_agent"),
 			tests.Group("destination"),
 		),
-		[]tests.FieldTemplateMapping{
-			{Template: "agent.", Mapping: "service.agent."},
-			{Template: "container.", Mapping: "system.container."},
-			{Template: "kubernetes.", Mapping: "system.kubernetes."},
-			{Template: "host.os.platform", Mapping: "system.platform"},
-			{Template: "host.name", Mapping: "system.configured_hostname"},
-			{Template: "host.", Mapping: "system."},
-			{Template: "user.name", Mapping: "user.username"},
-			{Template: "service.node.name", Mapping: "service.node.configured_name"},
-			//{Template: "url.", Mapping:"context.request.url."},
-		},
+		metadataTemplateToSchema(),
 	)
 }
 
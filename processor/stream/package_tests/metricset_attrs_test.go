@@ -37,18 +37,13 @@ func metricsetProcSetup() *tests.ProcessorSetup {
 			"../../../model/metricset/_meta/fields.yml",
 			"../../../_meta/fields.common.yml",
 		},
-		Schema: schema.ModelSchema,
+		Schema:       schema.ModelSchema,
+		SchemaPrefix: "metricset",
 	}
 }
 
 func TestAttributesPresenceInMetric(t *testing.T) {
-	requiredKeys := tests.NewSet(
-		"service",
-		"metricset",
-		"metricset.samples",
-		"metricset.samples.+.value",
-	)
-	metricsetProcSetup().AttrsPresence(t, requiredKeys, nil)
+	metricsetProcSetup().AttrsPresence(t, nil, nil)
 }
 
 func TestInvalidPayloads(t *testing.T) {
@@ -93,3 +88,11 @@ func TestInvalidPayloads(t *testing.T) {
 	}
 	metricsetProcSetup().DataValidation(t, payloadData)
 }
+
+func TestMetricsetTypeCompatibility(t *testing.T) {
+	metricsetProcSetup().TypeCompatibility(t, nil, nil)
+}
+
+func TestMetricsetDecodeRoundTrip(t *testing.T) {
+	metricsetProcSetup().DecodeRoundTrip(t, roundTripIterations, nil)
+}
@@ -64,6 +64,11 @@ func spanFieldsNotInPayloadAttrs() *tests.Set {
 			tests.Group("host"),
 			tests.Group("kubernetes"),
 			tests.Group("observer"),
+			tests.Group("tenant"),
+			tests.Group("event"),
+			tests.Group("trace.duration"),
+			"trace.transaction_count", "trace.span_count", "trace.error_count",
+			tests.Group("trace.services"),
 			tests.Group("process"),
 			tests.Group("service"),
 			tests.Group("user"),
@@ -72,6 +77,7 @@ func spanFieldsNotInPayloadAttrs() *tests.Set {
 			tests.Group("http"),
 			tests.Group("url"),
 			tests.Group("span.self_time"),
+			tests.Group("span.destination.service.response_time"),
 			tests.Group("transaction.self_time"),
 			tests.Group("transaction.breakdown"),
 			tests.Group("transaction.duration"),
@@ -102,41 +108,6 @@ func spanJsonSchemaNotInPayloadAttrs() *tests.Set {
 	)
 }
 
-func spanRequiredKeys() *tests.Set {
-	return tests.NewSet(
-		"span",
-		"span.name",
-		"span.trace_id",
-		"span.parent_id",
-		"span.id",
-		"span.duration",
-		"span.type",
-		"span.start",
-		"span.timestamp",
-		"span.stacktrace.filename",
-	)
-}
-
-func spanCondRequiredKeys() map[string]tests.Condition {
-	return map[string]tests.Condition{
-		"span.start":     {Absence: []string{"span.timestamp"}},
-		"span.timestamp": {Absence: []string{"span.start"}},
-
-		"span.context.destination.service.type": {Existence: map[string]interface{}{
-			"span.context.destination.service.name":     "postgresql",
-			"span.context.destination.service.resource": "postgresql",
-		}},
-		"span.context.destination.service.name": {Existence: map[string]interface{}{
-			"span.context.destination.service.type":     "db",
-			"span.context.destination.service.resource": "postgresql",
-		}},
-		"span.context.destination.service.resource": {Existence: map[string]interface{}{
-			"span.context.destination.service.type": "db",
-			"span.context.destination.service.name": "postgresql",
-		}},
-	}
-}
-
 func transactionContext() *tests.Set {
 	return tests.NewSet(
 		tests.Group("context.user"),
@@ -150,6 +121,11 @@ func spanKeywordExceptionKeys() *tests.Set {
 		"processor.event", "processor.name",
 		"context.tags", "transaction.type", "transaction.name",
 		tests.Group("observer"),
+		tests.Group("tenant"),
+		tests.Group("event"),
+		tests.Group("trace.duration"),
+		"trace.transaction_count", "trace.span_count", "trace.error_count",
+		tests.Group("trace.services"),
 
 		// metadata fields
 		tests.Group("agent"),
@@ -181,30 +157,46 @@ func TestSpanPayloadMatchJsonSchema(t *testing.T) {
 }
 
 func TestAttrsPresenceInSpan(t *testing.T) {
-	spanProcSetup().AttrsPresence(t, spanRequiredKeys(), spanCondRequiredKeys())
+	spanProcSetup().AttrsPresence(t, nil, nil)
+}
+
+func spanTemplateToSchema() []tests.FieldTemplateMapping {
+	return []tests.FieldTemplateMapping{
+		{Template: "timestamp.us", Mapping: "timestamp"},
+		{Template: "transaction.id", Mapping: "transaction_id"},
+		{Template: "child.id", Mapping: "child_ids"},
+		{Template: "parent.id", Mapping: "parent_id"},
+		{Template: "trace.id", Mapping: "trace_id"},
+		{Template: "span.id", Mapping: "id"},
+		{Template: "span.db.link", Mapping: "context.db.link"},
+		{Template: "span.destination.service", Mapping: "context.destination.service"},
+		{Template: "span.message.", Mapping: "context.message."},
+		{Template: "span.", Mapping: ""},
+		{Template: "destination.address", Mapping: "context.destination.address"},
+		{Template: "destination.port", Mapping: "context.destination.port"},
+		{Template: "span.message.queue.name", Mapping: "context.message.queue.name"},
+	}
 }
 
 func TestKeywordLimitationOnSpanAttrs(t *testing.T) {
 	spanProcSetup().KeywordLimitation(
 		t,
 		spanKeywordExceptionKeys(),
-		[]tests.FieldTemplateMapping{
-			{Template: "transaction.id", Mapping: "transaction_id"},
-			{Template: "child.id", Mapping: "child_ids"},
-			{Template: "parent.id", Mapping: "parent_id"},
-			{Template: "trace.id", Mapping: "trace_id"},
-			{Template: "span.id", Mapping: "id"},
-			{Template: "span.db.link", Mapping: "context.db.link"},
-			{Template: "span.destination.service", Mapping: "context.destination.service"},
-			{Template: "span.message.", Mapping: "context.message."},
-			{Template: "span.", Mapping: ""},
-			{Template: "destination.address", Mapping: "context.destination.address"},
-			{Template: "destination.port", Mapping: "context.destination.port"},
-			{Template: "span.message.queue.name", Mapping: "context.message.queue.name"},
-		},
+		spanTemplateToSchema(),
 	)
 }
 
+func TestSpanTypeCompatibility(t *testing.T) {
+	spanProcSetup().TypeCompatibility(t, nil, spanTemplateToSchema())
+}
+
+func TestSpanDecodeRoundTrip(t *testing.T) {
+	spanProcSetup().DecodeRoundTrip(t, roundTripIterations, tests.NewSet(
+		// normalized on decoding
+		"span.context.http.method",
+	))
+}
+
 func TestPayloadDataForSpans(t *testing.T) {
 	// add test data for testing
 	// * specific edge cases
@@ -63,6 +63,11 @@ func transactionFieldsNotInPayloadAttrs() *tests.Set {
 		"transaction.marks.*.*",
 		"source.ip",
 		tests.Group("observer"),
+		tests.Group("tenant"),
+		tests.Group("event"),
+		tests.Group("trace.duration"),
+		"trace.transaction_count", "trace.span_count", "trace.error_count",
+		tests.Group("trace.services"),
 		tests.Group("user"),
 		tests.Group("client"),
 		tests.Group("destination"),
@@ -72,6 +77,7 @@ func transactionFieldsNotInPayloadAttrs() *tests.Set {
 		tests.Group("transaction.self_time"),
 		tests.Group("transaction.breakdown"),
 		tests.Group("transaction.duration.sum"),
+		tests.Group("transaction.dropped_spans_stats"),
 		"experimental",
 	)
 }
@@ -91,26 +97,17 @@ func transactionPayloadAttrsNotInJsonSchema() *tests.Set {
 	)
 }
 
-func transactionRequiredKeys() *tests.Set {
-	return tests.NewSet(
-		"transaction",
-		"transaction.span_count",
-		"transaction.span_count.started",
-		"transaction.trace_id",
-		"transaction.id",
-		"transaction.duration",
-		"transaction.type",
-		"transaction.context.request.method",
-		"transaction.context.request.url",
-	)
-}
-
 func transactionKeywordExceptionKeys() *tests.Set {
 	return tests.NewSet(
 		"processor.event", "processor.name",
 		"transaction.marks",
 		"context.tags",
 		tests.Group("observer"),
+		tests.Group("tenant"),
+		tests.Group("event"),
+		tests.Group("trace.duration"),
+		"trace.transaction_count", "trace.span_count", "trace.error_count",
+		tests.Group("trace.services"),
 		tests.Group("url"),
 		tests.Group("http"),
 		tests.Group("destination"),
@@ -137,26 +134,47 @@ func TestTransactionPayloadMatchFields(t *testing.T) {
 func TestTransactionPayloadMatchJsonSchema(t *testing.T) {
 	transactionProcSetup().PayloadAttrsMatchJsonSchema(t,
 		transactionPayloadAttrsNotInJsonSchema(),
-		tests.NewSet("transaction.context.user.email", "transaction.context.experimental"))
+		tests.NewSet(
+			"transaction.context.user.email",
+			"transaction.context.experimental",
+			tests.Group("transaction.dropped_spans_stats"),
+		))
 }
 
 func TestAttrsPresenceInTransaction(t *testing.T) {
-	transactionProcSetup().AttrsPresence(t, transactionRequiredKeys(), nil)
+	transactionProcSetup().AttrsPresence(t, nil, nil)
+}
+
+func transactionTemplateToSchema() []tests.FieldTemplateMapping {
+	return []tests.FieldTemplateMapping{
+		{Template: "timestamp.us", Mapping: "timestamp"},
+		{Template: "parent.id", Mapping: "parent_id"},
+		{Template: "trace.id", Mapping: "trace_id"},
+		{Template: "transaction.message.", Mapping: "context.message."},
+		{Template: "transaction."},
+	}
 }
 
 func TestKeywordLimitationOnTransactionAttrs(t *testing.T) {
 	transactionProcSetup().KeywordLimitation(
 		t,
 		transactionKeywordExceptionKeys(),
-		[]tests.FieldTemplateMapping{
-			{Template: "parent.id", Mapping: "parent_id"},
-			{Template: "trace.id", Mapping: "trace_id"},
-			{Template: "transaction.message.", Mapping: "context.message."},
-			{Template: "transaction."},
-		},
+		transactionTemplateToSchema(),
 	)
 }
 
+func TestTransactionTypeCompatibility(t *testing.T) {
+	transactionProcSetup().TypeCompatibility(t, nil, transactionTemplateToSchema())
+}
+
+func TestTransactionDecodeRoundTrip(t *testing.T) {
+	transactionProcSetup().DecodeRoundTrip(t, roundTripIterations, tests.NewSet(
+		// normalized or converted on decoding
+		"transaction.context.request.method",
+		"transaction.context.request.url.port",
+	))
+}
+
 func TestPayloadDataForTransaction(t *testing.T) {
 	// add test data for testing
 	// * specific edge cases
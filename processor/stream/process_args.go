@@ -0,0 +1,87 @@
+// Licensed to Elasticsearch B.V. under one or more contributor
+// license agreements. See the NOTICE file distributed with
+// this work for additional information regarding copyright
+// ownership. Elasticsearch B.V. licenses this file to you under
+// the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package stream
+
+import (
+	"regexp"
+	"strings"
+
+	"github.com/elastic/beats/v7/libbeat/common"
+
+	"github.com/elastic/apm-server/beater/config"
+	"github.com/elastic/apm-server/policyfile"
+)
+
+const redactedProcessArg = "[REDACTED]"
+
+// sensitiveFlagPattern returns a function returning the compiled pattern
+// matching sensitive command line flags, or nil if process args should not
+// be scrubbed. If a rules file is configured, the pattern is reloaded when
+// the file changes.
+func sensitiveFlagPattern(cfg *config.Config) func() *regexp.Regexp {
+	pattern := compileSensitiveFlagPattern(cfg.ProcessArgs)
+	rulesFile := cfg.ProcessArgs.RulesFile
+	if rulesFile.Path == "" {
+		return func() *regexp.Regexp { return pattern }
+	}
+	f := policyfile.New(rulesFile.Path, rulesFile.ReloadPeriod, func(c *common.Config) (interface{}, error) {
+		rules, err := cfg.ProcessArgs.UnpackRules(c)
+		if err != nil {
+			return nil, err
+		}
+		return compileSensitiveFlagPattern(rules.(config.ProcessArgsConfig)), nil
+	})
+	return func() *regexp.Regexp {
+		if v, _ := f.Load(); v != nil {
+			return v.(*regexp.Regexp)
+		}
+		// The rules file could not be loaded.
+		return pattern
+	}
+}
+
+func compileSensitiveFlagPattern(cfg config.ProcessArgsConfig) *regexp.Regexp {
+	if !cfg.Scrub {
+		return nil
+	}
+	// The pattern has been validated when loading the config.
+	return regexp.MustCompile(cfg.SensitiveFlagPattern)
+}
+
+// scrubProcessArgs redacts, in place, the values of command line flags
+// whose names match sensitive. Both "--flag=value" and "--flag value"
+// forms are handled.
+func scrubProcessArgs(argv []string, sensitive *regexp.Regexp) {
+	for i := 0; i < len(argv); i++ {
+		arg := argv[i]
+		if !strings.HasPrefix(arg, "-") {
+			continue
+		}
+		name := strings.TrimLeft(arg, "-")
+		if j := strings.IndexByte(name, '='); j >= 0 {
+			if sensitive.MatchString(name[:j]) {
+				argv[i] = arg[:len(arg)-len(name)+j+1] + redactedProcessArg
+			}
+			continue
+		}
+		if sensitive.MatchString(name) && i+1 < len(argv) && !strings.HasPrefix(argv[i+1], "-") {
+			argv[i+1] = redactedProcessArg
+			i++
+		}
+	}
+}
@@ -0,0 +1,66 @@
+// Licensed to Elasticsearch B.V. under one or more contributor
+// license agreements. See the NOTICE file distributed with
+// this work for additional information regarding copyright
+// ownership. Elasticsearch B.V. licenses this file to you under
+// the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package stream
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"regexp"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/elastic/apm-server/beater/config"
+)
+
+func TestScrubProcessArgs(t *testing.T) {
+	sensitive := regexp.MustCompile("(?i)password|token")
+	argv := []string{
+		"java", "-jar", "app.jar",
+		"--password=hunter2", "-Token", "abc", "--user", "bob",
+		"--api-token", "--verbose", "positional",
+	}
+	scrubProcessArgs(argv, sensitive)
+	assert.Equal(t, []string{
+		"java", "-jar", "app.jar",
+		"--password=[REDACTED]", "-Token", "[REDACTED]", "--user", "bob",
+		"--api-token", "--verbose", "positional",
+	}, argv)
+}
+
+func TestSensitiveFlagPatternRulesFile(t *testing.T) {
+	dir, err := ioutil.TempDir("", "process_args")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+	path := filepath.Join(dir, "rules.yml")
+	require.NoError(t, ioutil.WriteFile(path, []byte(`sensitive_flag_pattern: "ssn"`), 0644))
+
+	cfg := config.DefaultConfig("7.0.0")
+	cfg.ProcessArgs.RulesFile = config.PolicyFileConfig{Path: path, ReloadPeriod: time.Nanosecond}
+	pattern := sensitiveFlagPattern(cfg)
+	assert.Equal(t, "ssn", pattern().String())
+
+	// Changes to the rules file are picked up.
+	modTime := time.Now().Add(time.Minute)
+	require.NoError(t, ioutil.WriteFile(path, []byte("scrub: false"), 0644))
+	require.NoError(t, os.Chtimes(path, modTime, modTime))
+	assert.Nil(t, pattern())
+}
@@ -21,19 +21,24 @@ import (
 	"context"
 	"errors"
 	"io"
+	"regexp"
 	"sync"
 	"time"
+	"unicode/utf8"
 
-	"golang.org/x/time/rate"
+	jsoniter "github.com/json-iterator/go"
 
 	"go.elastic.co/apm"
 
+	"github.com/elastic/apm-server/agentstats"
 	"github.com/elastic/apm-server/beater/config"
+	"github.com/elastic/apm-server/containerinfer"
 	"github.com/elastic/apm-server/decoder"
 	"github.com/elastic/apm-server/model"
 	"github.com/elastic/apm-server/model/modeldecoder"
 	"github.com/elastic/apm-server/model/modeldecoder/field"
 	"github.com/elastic/apm-server/publish"
+	"github.com/elastic/apm-server/quota"
 	"github.com/elastic/apm-server/transform"
 	"github.com/elastic/apm-server/utility"
 	"github.com/elastic/apm-server/validation"
@@ -45,6 +50,10 @@ var (
 
 const (
 	batchSize = 10
+
+	// decodedSizeFactor approximates the ratio of the memory held by
+	// decoded events, to the size of their ND-JSON encoding.
+	decodedSizeFactor = 3
 )
 
 type decodeMetadataFunc func(interface{}, bool) (*model.Metadata, error)
@@ -52,51 +61,160 @@ type decodeMetadataFunc func(interface{}, bool) (*model.Metadata, error)
 // functions with the decodeEventFunc signature decode their input argument into their batch argument (output)
 type decodeEventFunc func(modeldecoder.Input, *model.Batch) error
 
+// functions with the decodeEventJSONFunc signature decode and validate the
+// next JSON value read from iter into their batch argument (output), without
+// first decoding it into a map
+type decodeEventJSONFunc func(*jsoniter.Iterator, modeldecoder.Input, *model.Batch) error
+
 type Processor struct {
-	Tconfig          transform.Config
-	Mconfig          modeldecoder.Config
-	MaxEventSize     int
+	Tconfig      transform.Config
+	Mconfig      modeldecoder.Config
+	MaxEventSize int
+	// MaxDecompressedSize holds the maximum size of a request body
+	// after decompression, in bytes; 0 means unlimited.
+	MaxDecompressedSize int64
+	// DecodeConcurrency holds the maximum number of events
+	// in a stream to decode concurrently.
+	DecodeConcurrency int
+	// FastJSON controls whether events are decoded with jsoniter
+	// rather than encoding/json.
+	FastJSON         bool
 	streamReaderPool sync.Pool
+	batchPool        sync.Pool
 	decodeMetadata   decodeMetadataFunc
 	models           map[string]decodeEventFunc
+
+	// sensitiveFlags, if non-nil, returns the pattern matching the
+	// names of command line flags whose values are redacted from the
+	// process metadata, or nil if they are not redacted.
+	sensitiveFlags func() *regexp.Regexp
+
+	// allowedServiceNames, if non-nil, returns the service names
+	// which may be claimed by agents sending events from an origin,
+	// and whether service names are restricted for the origin.
+	allowedServiceNames func(origin string) ([]string, bool)
+
+	// allowedEventTypes, if non-nil, returns the event types which
+	// may be sent by agents claiming a service name from an origin,
+	// and whether event types are restricted for them.
+	allowedEventTypes func(origin, serviceName string) ([]string, bool)
+
+	// jsonModels holds streaming decoders for event types, which are
+	// used in place of models for events using the latest schema version.
+	jsonModels map[string]decodeEventJSONFunc
+
+	// ValidationSummary, if non-nil, aggregates the events rejected
+	// for being invalid or too large, to be logged periodically.
+	ValidationSummary *ValidationSummary
+
+	// SamplingRate, if non-nil, returns the head-based sampling rate
+	// desired for a service, to be reported to the agent. It defaults
+	// to the rates of the sampling rules in the config.
+	SamplingRate func(serviceName, environment string) (float64, bool)
+
+	// SanitizeMetadata, if non-nil, sanitizes the labels of the metadata
+	// of each stream, once for all events in the stream.
+	SanitizeMetadata func(*model.Metadata)
+}
+
+func decoderConfig(cfg *config.Config, hasShortFieldNames bool) modeldecoder.Config {
+	return modeldecoder.Config{
+		Experimental:       cfg.Mode == config.ModeExperimental,
+		HasShortFieldNames: hasShortFieldNames,
+		ContextLimits: modeldecoder.ContextLimits{
+			MaxDepth:       cfg.ContextLimits.MaxDepth,
+			MaxKeys:        cfg.ContextLimits.MaxKeys,
+			MaxValueLength: cfg.ContextLimits.MaxValueLength,
+		},
+	}
+}
+
+// samplingRateFunc returns a function returning the sampling rate desired
+// for a service, or nil if no sampling rules are configured.
+func samplingRateFunc(cfg *config.Config) func(serviceName, environment string) (float64, bool) {
+	if len(cfg.Sampling.Rules) == 0 {
+		return nil
+	}
+	return cfg.Sampling.Rules.Rate
+}
+
+// backendTransformConfig returns the transform.Config for events sent by
+// backend agents.
+func backendTransformConfig(cfg *config.Config) transform.Config {
+	var tcfg transform.Config
+	if pattern := cfg.LibraryFrames.Pattern(); pattern != "" {
+		// The patterns have been validated when loading the config.
+		tcfg.BackendLibraryPattern = regexp.MustCompile(pattern)
+	}
+	tcfg.GroupingKeyTemplate = cfg.ErrorGrouping.Template()
+	return tcfg
 }
 
 func BackendProcessor(cfg *config.Config) *Processor {
 	return &Processor{
-		Tconfig:        transform.Config{},
-		Mconfig:        modeldecoder.Config{Experimental: cfg.Mode == config.ModeExperimental},
-		MaxEventSize:   cfg.MaxEventSize,
-		decodeMetadata: modeldecoder.DecodeMetadata,
+		Tconfig:             backendTransformConfig(cfg),
+		Mconfig:             decoderConfig(cfg, false),
+		MaxEventSize:        cfg.MaxEventSize,
+		MaxDecompressedSize: cfg.MaxDecompressedBodySize,
+		DecodeConcurrency:   cfg.DecodeConcurrency,
+		FastJSON:            cfg.FastJSON,
+		decodeMetadata:      modeldecoder.DecodeMetadata,
+		sensitiveFlags:      sensitiveFlagPattern(cfg),
+		SamplingRate:        samplingRateFunc(cfg),
 		models: map[string]decodeEventFunc{
 			"transaction": modeldecoder.DecodeTransaction,
 			"span":        modeldecoder.DecodeSpan,
 			"metricset":   modeldecoder.DecodeMetricset,
 			"error":       modeldecoder.DecodeError,
 		},
+		jsonModels: map[string]decodeEventJSONFunc{
+			"transaction": modeldecoder.DecodeTransactionJSON,
+			"span":        modeldecoder.DecodeSpanJSON,
+			"metricset":   modeldecoder.DecodeMetricsetJSON,
+			"error":       modeldecoder.DecodeErrorJSON,
+		},
 	}
 }
 
 func RUMProcessor(cfg *config.Config, tcfg *transform.Config) *Processor {
 	return &Processor{
-		Tconfig:        *tcfg,
-		Mconfig:        modeldecoder.Config{Experimental: cfg.Mode == config.ModeExperimental},
-		MaxEventSize:   cfg.MaxEventSize,
-		decodeMetadata: modeldecoder.DecodeMetadata,
+		Tconfig:             *tcfg,
+		Mconfig:             decoderConfig(cfg, false),
+		MaxEventSize:        cfg.MaxEventSize,
+		MaxDecompressedSize: cfg.MaxDecompressedBodySize,
+		DecodeConcurrency:   cfg.DecodeConcurrency,
+		FastJSON:            cfg.FastJSON,
+		decodeMetadata:      modeldecoder.DecodeMetadata,
+		allowedServiceNames: serviceNameAllowlist(cfg),
+		allowedEventTypes:   eventTypeAllowlist(cfg),
+		SamplingRate:        samplingRateFunc(cfg),
 		models: map[string]decodeEventFunc{
 			"transaction": modeldecoder.DecodeTransaction,
 			"span":        modeldecoder.DecodeSpan,
 			"metricset":   modeldecoder.DecodeMetricset,
 			"error":       modeldecoder.DecodeError,
 		},
+		jsonModels: map[string]decodeEventJSONFunc{
+			"transaction": modeldecoder.DecodeTransactionJSON,
+			"span":        modeldecoder.DecodeSpanJSON,
+			"metricset":   modeldecoder.DecodeMetricsetJSON,
+			"error":       modeldecoder.DecodeErrorJSON,
+		},
 	}
 }
 
 func RUMV3Processor(cfg *config.Config, tcfg *transform.Config) *Processor {
 	return &Processor{
-		Tconfig:        *tcfg,
-		Mconfig:        modeldecoder.Config{Experimental: cfg.Mode == config.ModeExperimental, HasShortFieldNames: true},
-		MaxEventSize:   cfg.MaxEventSize,
-		decodeMetadata: modeldecoder.DecodeRUMV3Metadata,
+		Tconfig:             *tcfg,
+		Mconfig:             decoderConfig(cfg, true),
+		MaxEventSize:        cfg.MaxEventSize,
+		MaxDecompressedSize: cfg.MaxDecompressedBodySize,
+		DecodeConcurrency:   cfg.DecodeConcurrency,
+		FastJSON:            cfg.FastJSON,
+		decodeMetadata:      modeldecoder.DecodeRUMV3Metadata,
+		allowedServiceNames: serviceNameAllowlist(cfg),
+		allowedEventTypes:   eventTypeAllowlist(cfg),
+		SamplingRate:        samplingRateFunc(cfg),
 		models: map[string]decodeEventFunc{
 			"x":  modeldecoder.DecodeRUMV3Transaction,
 			"e":  modeldecoder.DecodeRUMV3Error,
@@ -105,23 +223,26 @@ func RUMV3Processor(cfg *config.Config, tcfg *transform.Config) *Processor {
 	}
 }
 
-func (p *Processor) readMetadata(reqMeta map[string]interface{}, reader *streamReader) (*model.Metadata, error) {
+// readMetadata reads and decodes the metadata object at the start of the
+// stream, returning it along with the schema version to validate events
+// in the stream against.
+func (p *Processor) readMetadata(reqMeta map[string]interface{}, reader *streamReader) (*model.Metadata, string, error) {
 	rawModel, err := reader.Read()
 	if err != nil {
 		if err == io.EOF {
-			return nil, &Error{
+			return nil, "", &Error{
 				Type:     InvalidInputErrType,
 				Message:  "EOF while reading metadata",
 				Document: string(reader.LatestLine()),
 			}
 		}
-		return nil, err
+		return nil, "", err
 	}
 
 	fieldName := field.Mapper(p.Mconfig.HasShortFieldNames)
 	rawMetadata, ok := rawModel[fieldName("metadata")].(map[string]interface{})
 	if !ok {
-		return nil, &Error{
+		return nil, "", &Error{
 			Type:     InvalidInputErrType,
 			Message:  ErrUnrecognizedObject.Error(),
 			Document: string(reader.LatestLine()),
@@ -135,29 +256,55 @@ func (p *Processor) readMetadata(reqMeta map[string]interface{}, reader *streamR
 	if err != nil {
 		var ve *validation.Error
 		if errors.As(err, &ve) {
-			return nil, &Error{
+			return nil, "", &Error{
 				Type:     InvalidInputErrType,
 				Message:  err.Error(),
 				Document: string(reader.LatestLine()),
 			}
 		}
-		return nil, err
+		return nil, "", err
+	}
+
+	if p.sensitiveFlags != nil {
+		if sensitive := p.sensitiveFlags(); sensitive != nil {
+			scrubProcessArgs(metadata.Process.Argv, sensitive)
+		}
 	}
-	return metadata, nil
+
+	explicitSchemaVersion, _ := rawMetadata["schema_version"].(string)
+	schemaVersion, err := modeldecoder.SelectSchemaVersion(explicitSchemaVersion, metadata)
+	if err != nil {
+		return nil, "", &Error{
+			Type:     InvalidInputErrType,
+			Message:  err.Error(),
+			Document: string(reader.LatestLine()),
+		}
+	}
+	return metadata, schemaVersion, nil
 }
 
-// HandleRawModel validates and decodes a single json object into its struct form
+// HandleRawModel validates and decodes a single json object into its struct form,
+// using the schema version selected for the agent described by streamMetadata.
 func (p *Processor) HandleRawModel(rawModel map[string]interface{}, batch *model.Batch, requestTime time.Time, streamMetadata model.Metadata) error {
+	schemaVersion, err := modeldecoder.SelectSchemaVersion("", &streamMetadata)
+	if err != nil {
+		return err
+	}
+	return p.handleRawModel(rawModel, batch, requestTime, streamMetadata, schemaVersion)
+}
+
+func (p *Processor) handleRawModel(rawModel map[string]interface{}, batch *model.Batch, requestTime time.Time, streamMetadata model.Metadata, schemaVersion string) error {
 	for key, decodeEvent := range p.models {
 		entry, ok := rawModel[key]
 		if !ok {
 			continue
 		}
 		err := decodeEvent(modeldecoder.Input{
-			Raw:         entry,
-			RequestTime: requestTime,
-			Metadata:    streamMetadata,
-			Config:      p.Mconfig,
+			Raw:           entry,
+			RequestTime:   requestTime,
+			Metadata:      streamMetadata,
+			SchemaVersion: schemaVersion,
+			Config:        p.Mconfig,
 		}, batch)
 		if err != nil {
 			return err
@@ -167,14 +314,83 @@ func (p *Processor) HandleRawModel(rawModel map[string]interface{}, batch *model
 	return ErrUnrecognizedObject
 }
 
+// handleLine decodes a single ND-JSON line into batch. Events with a
+// streaming decoder are decoded and validated directly from line; all
+// other events are first decoded into a map, and then handled by
+// handleRawModel.
+//
+// Events rejected by a streaming decoder are decoded again into a map, so
+// that the errors reported for invalid events do not depend on the decoder.
+func (p *Processor) handleLine(
+	line []byte,
+	batch *model.Batch,
+	requestTime time.Time,
+	streamMetadata model.Metadata,
+	schemaVersion string,
+) error {
+	if p.decodeLineJSON(line, batch, requestTime, streamMetadata, schemaVersion) {
+		return nil
+	}
+	decodeJSON := decoder.DecodeJSONBytes
+	if p.FastJSON {
+		decodeJSON = decoder.DecodeJSONBytesFast
+	}
+	rawModel, err := decodeJSON(line)
+	if err != nil {
+		return &Error{
+			Type:      InvalidInputErrType,
+			Message:   "data read error: " + err.Error(),
+			Document:  string(line),
+			rejection: rejection{reason: "invalid_json"},
+		}
+	}
+	return p.handleRawModel(rawModel, batch, requestTime, streamMetadata, schemaVersion)
+}
+
+// decodeLineJSON decodes line into batch with a streaming decoder, reporting
+// whether the line held a valid event of a type with a streaming decoder.
+//
+// Streaming decoders are generated from the latest schemas only, and do not
+// decode experimental fields. Malformed lines are left to the map decoder for
+// error reporting, as are lines with invalid UTF-8, which jsoniter decodes
+// differently to encoding/json.
+func (p *Processor) decodeLineJSON(
+	line []byte,
+	batch *model.Batch,
+	requestTime time.Time,
+	streamMetadata model.Metadata,
+	schemaVersion string,
+) bool {
+	if len(p.jsonModels) == 0 || p.Mconfig.Experimental || schemaVersion != modeldecoder.LatestSchemaVersion {
+		return false
+	}
+	if !jsoniter.Valid(line) || !utf8.Valid(line) {
+		return false
+	}
+	iter := jsoniter.ConfigCompatibleWithStandardLibrary.BorrowIterator(line)
+	defer jsoniter.ConfigCompatibleWithStandardLibrary.ReturnIterator(iter)
+	decodeEvent, ok := p.jsonModels[iter.ReadObject()]
+	if !ok {
+		return false
+	}
+	err := decodeEvent(iter, modeldecoder.Input{
+		RequestTime:   requestTime,
+		Metadata:      streamMetadata,
+		SchemaVersion: schemaVersion,
+		Config:        p.Mconfig,
+	}, batch)
+	return err == nil
+}
+
 // readBatch will read up to `batchSize` objects from the ndjson stream,
 // returning a slice of Transformables and a boolean indicating that there
 // might be more to read.
 func (p *Processor) readBatch(
 	ctx context.Context,
-	ipRateLimiter *rate.Limiter,
+	ipRateLimiter RateLimiter,
 	requestTime time.Time,
 	streamMetadata *model.Metadata,
+	schemaVersion string,
 	batchSize int,
 	batch *model.Batch,
 	reader *streamReader,
@@ -195,9 +411,13 @@ func (p *Processor) readBatch(
 		}
 	}
 
+	if p.DecodeConcurrency > 1 {
+		return p.readBatchConcurrently(requestTime, streamMetadata, schemaVersion, batchSize, batch, reader, response)
+	}
+
 	// input events are decoded and appended to the batch
 	for i := 0; i < batchSize && !reader.IsEOF(); i++ {
-		rawModel, err := reader.Read()
+		line, err := reader.ReadLine()
 		if err != nil && err != io.EOF {
 			if e, ok := err.(*Error); ok && (e.Type == InvalidInputErrType || e.Type == InputTooLargeErrType) {
 				response.LimitedAdd(e)
@@ -207,36 +427,176 @@ func (p *Processor) readBatch(
 			response.Add(err)
 			return true
 		}
-		if len(rawModel) > 0 {
-
-			err := p.HandleRawModel(rawModel, batch, requestTime, *streamMetadata)
-			if err != nil {
-				response.LimitedAdd(&Error{
-					Type:     InvalidInputErrType,
-					Message:  err.Error(),
-					Document: string(reader.LatestLine()),
-				})
+		if len(line) > 0 {
+			if err := p.handleLine(line, batch, requestTime, *streamMetadata, schemaVersion); err != nil {
+				response.LimitedAdd(lineError(err, line))
+			}
+		}
+	}
+	return reader.IsEOF()
+}
+
+// readBatchConcurrently is like readBatch, but decodes the lines read with up
+// to DecodeConcurrency goroutines. Events are appended to the batch, and errors
+// added to the response, in the order in which the lines were read.
+func (p *Processor) readBatchConcurrently(
+	requestTime time.Time,
+	streamMetadata *model.Metadata,
+	schemaVersion string,
+	batchSize int,
+	batch *model.Batch,
+	reader *streamReader,
+	response *Result,
+) bool {
+	type pendingLine struct {
+		line  []byte
+		batch model.Batch
+		err   error
+	}
+
+	// Lines are copied, as the reader's buffer is reused for each line.
+	pending := make([]pendingLine, 0, batchSize)
+	var readErr error
+	for i := 0; i < batchSize && !reader.IsEOF(); i++ {
+		line, err := reader.ReadLine()
+		if err != nil && err != io.EOF {
+			if e, ok := err.(*Error); ok && (e.Type == InvalidInputErrType || e.Type == InputTooLargeErrType) {
+				pending = append(pending, pendingLine{err: e})
 				continue
 			}
+			// stop reading, we assume we can only recover from a input error types
+			readErr = err
+			break
+		}
+		if len(line) > 0 {
+			pending = append(pending, pendingLine{line: append([]byte(nil), line...)})
+		}
+	}
+
+	var wg sync.WaitGroup
+	indices := make(chan int, len(pending))
+	for i := range pending {
+		if pending[i].err == nil {
+			indices <- i
 		}
 	}
+	close(indices)
+	workers := p.DecodeConcurrency
+	if workers > len(indices) {
+		workers = len(indices)
+	}
+	for i := 0; i < workers; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := range indices {
+				pl := &pending[i]
+				if err := p.handleLine(pl.line, &pl.batch, requestTime, *streamMetadata, schemaVersion); err != nil {
+					pl.err = lineError(err, pl.line)
+				}
+			}
+		}()
+	}
+	wg.Wait()
+
+	for _, pl := range pending {
+		if pl.err != nil {
+			response.LimitedAdd(pl.err)
+			continue
+		}
+		batch.Transactions = append(batch.Transactions, pl.batch.Transactions...)
+		batch.Spans = append(batch.Spans, pl.batch.Spans...)
+		batch.Metricsets = append(batch.Metricsets, pl.batch.Metricsets...)
+		batch.Errors = append(batch.Errors, pl.batch.Errors...)
+	}
+	if readErr != nil {
+		response.Add(readErr)
+		return true
+	}
 	return reader.IsEOF()
 }
 
-// HandleStream processes a stream of events
-func (p *Processor) HandleStream(ctx context.Context, ipRateLimiter *rate.Limiter, meta map[string]interface{}, reader io.Reader, report publish.Reporter) *Result {
+// lineError converts an error decoding line to a stream error.
+func lineError(err error, line []byte) error {
+	if e, ok := err.(*Error); ok {
+		return e
+	}
+	return &Error{
+		Type:      InvalidInputErrType,
+		Message:   err.Error(),
+		Document:  string(line),
+		rejection: classifyLineError(err, line),
+	}
+}
+
+// RateLimiter throttles reading events from a stream.
+// It is implemented by *rate.Limiter.
+type RateLimiter interface {
+	// WaitN blocks until n events may be read, returning an
+	// error if they may not be read before ctx is done.
+	WaitN(ctx context.Context, n int) error
+}
+
+// HandleStream processes a stream of events. If ipRateLimiter is
+// non-nil, reading events is throttled by it.
+func (p *Processor) HandleStream(ctx context.Context, ipRateLimiter RateLimiter, meta map[string]interface{}, reader io.Reader, report publish.Reporter) *Result {
 	res := &Result{}
+	mActive.Inc()
+	defer mActive.Dec()
 
 	sr := p.getStreamReader(reader)
 	defer sr.release()
 
 	// first item is the metadata object
-	metadata, err := p.readMetadata(meta, sr)
+	metadata, schemaVersion, err := p.readMetadata(meta, sr)
 	if err != nil {
 		// no point in continuing if we couldn't read the metadata
 		res.Add(err)
 		return res
 	}
+	// Record the events accepted and rejected for the agent, so outdated
+	// agent versions producing validation failures can be identified.
+	defer func() {
+		agent := metadata.Service.Agent
+		agentstats.Default.Add(agent.Name, agent.Version, res.Accepted, res.rejected)
+		if p.ValidationSummary != nil && len(res.rejections) > 0 {
+			p.ValidationSummary.add(agent.Name, agent.Version, res.rejections)
+			res.summarized = true
+		}
+	}()
+	serviceNames := p.serviceNames(ctx)
+	if serviceNames != nil {
+		if err := serviceNames.check(metadata); err != nil {
+			res.Add(err)
+			return res
+		}
+	}
+	eventTypes := p.eventTypes(ctx)
+	if p.SamplingRate != nil {
+		res.samplingRate, res.hasSamplingRate = p.SamplingRate(
+			metadata.Service.Name, metadata.Service.Environment,
+		)
+	}
+	// The tenant is determined by the request's credentials,
+	// and cannot be set by agents.
+	metadata.TenantID = utility.Tenant(ctx)
+	if metadata.System.Container.ID == "" {
+		// The agent did not send container metadata; the server
+		// may be able to infer it from the request's connection.
+		if container, ok := containerinfer.FromContext(ctx); ok {
+			metadata.System.Container.ID = container.ID
+			if metadata.System.Kubernetes.PodUID == "" {
+				metadata.System.Kubernetes.PodUID = container.PodUID
+			}
+		}
+	}
+	// The metadata is shared by all events in the stream,
+	// so its labels need only be sanitized, and its fields
+	// computed, once.
+	if p.SanitizeMetadata != nil {
+		p.SanitizeMetadata(metadata)
+	}
+	metadata.Prepare()
 
 	requestTime := utility.RequestTime(ctx)
 	tctx := &transform.Context{Config: p.Tconfig}
@@ -244,33 +604,79 @@ func (p *Processor) HandleStream(ctx context.Context, ipRateLimiter *rate.Limite
 	sp, ctx := apm.StartSpan(ctx, "Stream", "Reporter")
 	defer sp.End()
 
-	var batch model.Batch
+	// Events are published asynchronously, after the request's context is
+	// done. Queued events are dropped only if the client went away or the
+	// deadline passed before the whole stream was processed.
+	publishCtx, abandon := context.WithCancel(context.Background())
+	defer func() {
+		if ctx.Err() != nil {
+			abandon()
+		}
+	}()
+
+	batch := p.getBatch()
+	defer p.releaseBatch(batch)
 	var done bool
 	for !done {
-		done = p.readBatch(ctx, ipRateLimiter, requestTime, metadata, batchSize, &batch, sr, res)
+		if err := ctx.Err(); err != nil {
+			// The client has gone away or the request deadline has
+			// passed; stop processing rather than decoding and
+			// publishing events nobody will be told about.
+			res.Add(contextError(err))
+			return res
+		}
+		done = p.readBatch(ctx, ipRateLimiter, requestTime, metadata, schemaVersion, batchSize, batch, sr, res)
+		if serviceNames != nil {
+			serviceNames.filter(batch, res)
+		}
+		if eventTypes != nil {
+			eventTypes.filter(batch, res)
+		}
 		if batch.Len() == 0 {
+			sr.resetBytesRead()
 			continue
 		}
 		// NOTE(axw) `report` takes ownership of transformables, which
 		// means we cannot reuse the slice memory. We should investigate
 		// alternative interfaces between the processor and publisher
 		// which would enable better memory reuse.
-		if err := report(ctx, publish.PendingReq{
+		decoded := int64(batch.Len())
+		mDecoding.Add(decoded)
+		err := report(ctx, publish.PendingReq{
 			Transformables: batch.Transformables(),
 			Tcontext:       tctx,
 			Trace:          !sp.Dropped(),
-		}); err != nil {
+			Size:           sr.resetBytesRead() * decodedSizeFactor,
+			Context:        publishCtx,
+		})
+		mDecoding.Add(-decoded)
+		if errors.Is(err, quota.ErrExceeded) {
+			// Events of the batch within quota were reported.
+			var exceeded *quota.ExceededError
+			if errors.As(err, &exceeded) {
+				res.AddAccepted(exceeded.Accepted)
+				res.retryAfter = time.Until(exceeded.Reset)
+			}
+			res.Add(&Error{
+				Type:    QuotaExceededErrType,
+				Message: err.Error(),
+			})
+			return res
+		}
+		if err != nil {
 			switch err {
 			case publish.ErrChannelClosed:
 				res.Add(&Error{
 					Type:    ShuttingDownErrType,
 					Message: "server is shutting down",
 				})
-			case publish.ErrFull:
+			case publish.ErrFull, publish.ErrMemoryFull:
 				res.Add(&Error{
 					Type:    QueueFullErrType,
 					Message: err.Error(),
 				})
+			case context.Canceled, context.DeadlineExceeded:
+				res.Add(contextError(err))
 			default:
 				res.Add(err)
 			}
@@ -282,6 +688,22 @@ func (p *Processor) HandleStream(ctx context.Context, ipRateLimiter *rate.Limite
 	return res
 }
 
+// getBatch returns an empty model.Batch, reusing the storage of
+// batches from previous streams where possible.
+func (p *Processor) getBatch() *model.Batch {
+	if batch, ok := p.batchPool.Get().(*model.Batch); ok {
+		return batch
+	}
+	return &model.Batch{}
+}
+
+// releaseBatch resets batch and adds it to the Processor's sync.Pool.
+// The batch must not be used after releaseBatch returns.
+func (p *Processor) releaseBatch(batch *model.Batch) {
+	batch.Reset()
+	p.batchPool.Put(batch)
+}
+
 // getStreamReader returns a streamReader that reads ND-JSON lines from r.
 func (p *Processor) getStreamReader(r io.Reader) *streamReader {
 	if sr, ok := p.streamReaderPool.Get().(*streamReader); ok {
@@ -298,12 +720,17 @@ func (p *Processor) getStreamReader(r io.Reader) *streamReader {
 type streamReader struct {
 	processor *Processor
 	*decoder.NDJSONStreamReader
+
+	// bytesRead holds the number of bytes read since
+	// the last call to resetBytesRead.
+	bytesRead int64
 }
 
 // release releases the streamReader, adding it to its Processor's sync.Pool.
 // The streamReader must not be used after release returns.
 func (sr *streamReader) release() {
 	sr.Reset(nil)
+	sr.bytesRead = 0
 	sr.processor.streamReaderPool.Put(sr)
 }
 
@@ -313,21 +740,55 @@ func (sr *streamReader) Read() (map[string]interface{}, error) {
 	// any contents of rawModel that they wish to retain after
 	// the call, in order to safely reuse the map.
 	v, err := sr.NDJSONStreamReader.Read()
+	return v, sr.wrapError(err)
+}
+
+func (sr *streamReader) ReadLine() ([]byte, error) {
+	line, err := sr.NDJSONStreamReader.ReadLine()
+	sr.bytesRead += int64(len(line))
+	return line, sr.wrapError(err)
+}
+
+// resetBytesRead returns the number of bytes read since
+// the last call to resetBytesRead, and resets it to zero.
+func (sr *streamReader) resetBytesRead() int64 {
+	n := sr.bytesRead
+	sr.bytesRead = 0
+	return n
+}
+
+// wrapError converts reading and decoding errors to stream errors.
+func (sr *streamReader) wrapError(err error) error {
 	if err != nil {
 		if _, ok := err.(decoder.JSONDecodeError); ok {
-			return nil, &Error{
+			return &Error{
 				Type:     InvalidInputErrType,
 				Message:  err.Error(),
 				Document: string(sr.LatestLine()),
 			}
 		}
+		if err == decoder.ErrDecompressedTooLarge {
+			return &Error{
+				Type:    InputTooLargeErrType,
+				Message: "request body exceeded the permitted size after decompression.",
+			}
+		}
 		if err == decoder.ErrLineTooLong {
-			return nil, &Error{
+			return &Error{
 				Type:     InputTooLargeErrType,
 				Message:  "event exceeded the permitted size.",
 				Document: string(sr.LatestLine()),
 			}
 		}
 	}
-	return v, err
+	return err
+}
+
+// contextError returns an Error describing why processing of a request
+// was stopped, given the error of its cancelled context.
+func contextError(err error) *Error {
+	return &Error{
+		Type:    TimeoutErrType,
+		Message: "stopped processing request: " + err.Error(),
+	}
 }
@@ -35,7 +35,11 @@ import (
 	"golang.org/x/time/rate"
 
 	"github.com/elastic/beats/v7/libbeat/beat"
+	"github.com/elastic/beats/v7/libbeat/common"
 
+	"github.com/elastic/apm-server/agentstats"
+	"github.com/elastic/apm-server/model"
+	"github.com/elastic/apm-server/model/modeldecoder"
 	"github.com/elastic/apm-server/publish"
 	"github.com/elastic/apm-server/tests"
 	"github.com/elastic/apm-server/tests/approvals"
@@ -79,6 +83,16 @@ func TestHandlerReportingStreamError(t *testing.T) {
 			report: func(ctx context.Context, p publish.PendingReq) error {
 				return publish.ErrFull
 			},
+		}, {
+			name: "MemoryFull",
+			report: func(ctx context.Context, p publish.PendingReq) error {
+				return publish.ErrMemoryFull
+			},
+		}, {
+			name: "Timeout",
+			report: func(ctx context.Context, p publish.PendingReq) error {
+				return context.DeadlineExceeded
+			},
 		},
 	} {
 
@@ -92,6 +106,54 @@ func TestHandlerReportingStreamError(t *testing.T) {
 	}
 }
 
+func TestHandlerContextCancelled(t *testing.T) {
+	var pendingReqs []publish.PendingReq
+	report := tests.TestReporter(&pendingReqs)
+
+	b, err := loader.LoadDataAsBytes("../testdata/intake-v2/transactions.ndjson")
+	require.NoError(t, err)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	sp := BackendProcessor(&config.Config{MaxEventSize: 100 * 1024})
+	actualResult := sp.HandleStream(ctx, nil, map[string]interface{}{}, bytes.NewReader(b), report)
+	assert.Empty(t, pendingReqs)
+	assertApproveResult(t, actualResult, "Cancelled")
+}
+
+func TestHandlerPublishContext(t *testing.T) {
+	b, err := loader.LoadDataAsBytes("../testdata/intake-v2/transactions.ndjson")
+	require.NoError(t, err)
+	sp := BackendProcessor(&config.Config{MaxEventSize: 100 * 1024})
+
+	t.Run("Processed", func(t *testing.T) {
+		var pendingReqs []publish.PendingReq
+		ctx, cancel := context.WithCancel(context.Background())
+		sp.HandleStream(ctx, nil, map[string]interface{}{}, bytes.NewReader(b), tests.TestReporter(&pendingReqs))
+		cancel()
+		require.NotEmpty(t, pendingReqs)
+		for _, req := range pendingReqs {
+			assert.NoError(t, req.Context.Err())
+		}
+	})
+
+	t.Run("Abandoned", func(t *testing.T) {
+		var pendingReqs []publish.PendingReq
+		ctx, cancel := context.WithCancel(context.Background())
+		report := func(ctx context.Context, req publish.PendingReq) error {
+			// the client goes away while the stream is being processed
+			cancel()
+			pendingReqs = append(pendingReqs, req)
+			return nil
+		}
+		sp.HandleStream(ctx, nil, map[string]interface{}{}, bytes.NewReader(b), report)
+		require.NotEmpty(t, pendingReqs)
+		for _, req := range pendingReqs {
+			assert.Equal(t, context.Canceled, req.Context.Err())
+		}
+	})
+}
+
 func TestIntegrationESOutput(t *testing.T) {
 	report := func(ctx context.Context, p publish.PendingReq) error {
 		var events []beat.Event
@@ -122,29 +184,37 @@ func TestIntegrationESOutput(t *testing.T) {
 		{path: "invalid-json-metadata.ndjson", name: "InvalidJSONMetadata"},
 		{path: "invalid-metadata.ndjson", name: "InvalidMetadata"},
 		{path: "invalid-metadata-2.ndjson", name: "InvalidMetadata2"},
+		{path: "invalid-metricset.ndjson", name: "InvalidMetricset"},
+		{path: "invalid-schema-version.ndjson", name: "InvalidSchemaVersion"},
 		{path: "unrecognized-event.ndjson", name: "UnrecognizedEvent"},
 		{path: "optional-timestamps.ndjson", name: "OptionalTimestamps"},
 	} {
-		t.Run(test.name, func(t *testing.T) {
-			b, err := loader.LoadDataAsBytes(filepath.Join("../testdata/intake-v2/", test.path))
-			require.NoError(t, err)
-			bodyReader := bytes.NewBuffer(b)
-
-			name := fmt.Sprintf("test_approved_es_documents/testIntakeIntegration%s", test.name)
-			ctx := context.WithValue(context.Background(), "name", name)
-			reqTimestamp := time.Date(2018, 8, 1, 10, 0, 0, 0, time.UTC)
-			ctx = utility.ContextWithRequestTime(ctx, reqTimestamp)
-
-			reqDecoderMeta := map[string]interface{}{
-				"system": map[string]interface{}{
-					"ip": "192.0.0.1",
-				},
+		// Concurrent decoding must produce the same results as sequential
+		// decoding, and jsoniter the same results as encoding/json.
+		for _, concurrency := range []int{1, 4} {
+			for _, fastJSON := range []bool{false, true} {
+				t.Run(fmt.Sprintf("%s/concurrency=%d/fast_json=%t", test.name, concurrency, fastJSON), func(t *testing.T) {
+					b, err := loader.LoadDataAsBytes(filepath.Join("../testdata/intake-v2/", test.path))
+					require.NoError(t, err)
+					bodyReader := bytes.NewBuffer(b)
+
+					name := fmt.Sprintf("test_approved_es_documents/testIntakeIntegration%s", test.name)
+					ctx := context.WithValue(context.Background(), "name", name)
+					reqTimestamp := time.Date(2018, 8, 1, 10, 0, 0, 0, time.UTC)
+					ctx = utility.ContextWithRequestTime(ctx, reqTimestamp)
+
+					reqDecoderMeta := map[string]interface{}{
+						"system": map[string]interface{}{
+							"ip": "192.0.0.1",
+						},
+					}
+
+					p := BackendProcessor(&config.Config{MaxEventSize: 100 * 1024, DecodeConcurrency: concurrency, FastJSON: fastJSON})
+					actualResult := p.HandleStream(ctx, nil, reqDecoderMeta, bodyReader, report)
+					assertApproveResult(t, actualResult, test.name)
+				})
 			}
-
-			p := BackendProcessor(&config.Config{MaxEventSize: 100 * 1024})
-			actualResult := p.HandleStream(ctx, nil, reqDecoderMeta, bodyReader, report)
-			assertApproveResult(t, actualResult, test.name)
-		})
+		}
 	}
 }
 
@@ -260,12 +330,203 @@ func TestRateLimiting(t *testing.T) {
 		{name: "LimiterPartiallyUsedLimitDeny", lim: rate.NewLimiter(rate.Limit(7), 7*2), hit: 10},
 		{name: "LimiterDeny", lim: rate.NewLimiter(rate.Limit(6), 6*2)},
 	} {
+		var lim RateLimiter
+		if test.lim != nil {
+			lim = test.lim
+		}
 		if test.hit > 0 {
 			assert.True(t, test.lim.AllowN(time.Now(), test.hit))
 		}
 
 		actualResult := BackendProcessor(&config.Config{MaxEventSize: 100 * 1024}).HandleStream(
-			context.Background(), test.lim, map[string]interface{}{}, bytes.NewReader(b), report)
+			context.Background(), lim, map[string]interface{}{}, bytes.NewReader(b), report)
 		assertApproveResult(t, actualResult, test.name)
 	}
 }
+
+func TestHandleStreamReportsSize(t *testing.T) {
+	b, err := loader.LoadDataAsBytes("../testdata/intake-v2/transactions.ndjson")
+	require.NoError(t, err)
+	lines := bytes.Split(bytes.TrimSpace(b), []byte("\n"))
+	var eventBytes int64
+	for _, line := range lines[1:] {
+		eventBytes += int64(len(line))
+	}
+
+	var size int64
+	report := func(ctx context.Context, p publish.PendingReq) error {
+		size += p.Size
+		return nil
+	}
+	sp := BackendProcessor(&config.Config{MaxEventSize: 100 * 1024})
+	result := sp.HandleStream(context.Background(), nil, map[string]interface{}{}, bytes.NewReader(b), report)
+	require.Empty(t, result.Errors)
+	assert.Equal(t, eventBytes*decodedSizeFactor, size)
+}
+
+func TestHandleStreamGauges(t *testing.T) {
+	b, err := loader.LoadDataAsBytes("../testdata/intake-v2/transactions.ndjson")
+	require.NoError(t, err)
+
+	var active, decoding int64
+	report := func(ctx context.Context, p publish.PendingReq) error {
+		active, decoding = mActive.Get(), mDecoding.Get()
+		return nil
+	}
+	sp := BackendProcessor(&config.Config{MaxEventSize: 100 * 1024})
+	result := sp.HandleStream(context.Background(), nil, map[string]interface{}{}, bytes.NewReader(b), report)
+	require.Empty(t, result.Errors)
+	assert.Equal(t, int64(1), active)
+	assert.Equal(t, int64(result.Accepted), decoding)
+	assert.Equal(t, int64(0), mActive.Get())
+	assert.Equal(t, int64(0), mDecoding.Get())
+}
+
+func TestHandleStreamAgentStats(t *testing.T) {
+	b, err := loader.LoadDataAsBytes("../testdata/intake-v2/invalid-event.ndjson")
+	require.NoError(t, err)
+
+	agentStats := func() agentstats.Stats {
+		stats, _ := agentstats.Default.Stats()
+		for _, s := range stats {
+			if s.Name == "elastic-node" && s.Version == "3.14.0" {
+				return s
+			}
+		}
+		return agentstats.Stats{}
+	}
+	before := agentStats()
+
+	sp := BackendProcessor(&config.Config{MaxEventSize: 100 * 1024})
+	result := sp.HandleStream(context.Background(), nil, map[string]interface{}{}, bytes.NewReader(b), func(context.Context, publish.PendingReq) error { return nil })
+	require.Len(t, result.Errors, 1)
+
+	after := agentStats()
+	assert.Equal(t, before.Accepted+int64(result.Accepted), after.Accepted)
+	assert.Equal(t, before.Rejected+1, after.Rejected)
+}
+
+func TestHandleStreamTenant(t *testing.T) {
+	b, err := loader.LoadDataAsBytes("../testdata/intake-v2/transactions.ndjson")
+	require.NoError(t, err)
+
+	var events []beat.Event
+	report := func(ctx context.Context, p publish.PendingReq) error {
+		for _, tr := range p.Transformables {
+			events = append(events, tr.Transform(ctx, p.Tcontext)...)
+		}
+		return nil
+	}
+	ctx := utility.ContextWithTenant(context.Background(), "team-a")
+	sp := BackendProcessor(&config.Config{MaxEventSize: 100 * 1024})
+	result := sp.HandleStream(ctx, nil, map[string]interface{}{}, bytes.NewReader(b), report)
+	require.Empty(t, result.Errors)
+	require.NotEmpty(t, events)
+	for _, event := range events {
+		tenant, err := event.Fields.GetValue("tenant.id")
+		require.NoError(t, err)
+		assert.Equal(t, "team-a", tenant)
+	}
+}
+
+func TestHandleStreamSamplingRate(t *testing.T) {
+	b, err := loader.LoadDataAsBytes("../testdata/intake-v2/transactions.ndjson")
+	require.NoError(t, err)
+
+	report := func(context.Context, publish.PendingReq) error { return nil }
+	cfg := &config.Config{MaxEventSize: 100 * 1024}
+	sp := BackendProcessor(cfg)
+	result := sp.HandleStream(context.Background(), nil, map[string]interface{}{}, bytes.NewReader(b), report)
+	_, ok := result.SamplingRate()
+	assert.False(t, ok)
+
+	cfg.Sampling.Rules = []config.SamplingRuleConfig{{Environment: "staging", Rate: 0.5}}
+	sp = BackendProcessor(cfg)
+	result = sp.HandleStream(context.Background(), nil, map[string]interface{}{}, bytes.NewReader(b), report)
+	require.Empty(t, result.Errors)
+	rate, ok := result.SamplingRate()
+	assert.True(t, ok)
+	assert.Equal(t, 0.5, rate)
+}
+
+func TestHandleStreamSanitizeMetadata(t *testing.T) {
+	b, err := loader.LoadDataAsBytes("../testdata/intake-v2/transactions.ndjson")
+	require.NoError(t, err)
+
+	var events []transform.Transformable
+	report := func(ctx context.Context, req publish.PendingReq) error {
+		events = append(events, req.Transformables...)
+		return nil
+	}
+	var calls int
+	sp := BackendProcessor(&config.Config{MaxEventSize: 100 * 1024})
+	sp.SanitizeMetadata = func(metadata *model.Metadata) {
+		assert.False(t, metadata.Prepared())
+		metadata.Labels = common.MapStr{"sanitized": true}
+		calls++
+	}
+	result := sp.HandleStream(context.Background(), nil, map[string]interface{}{}, bytes.NewReader(b), report)
+	require.Empty(t, result.Errors)
+	require.NotEmpty(t, events)
+
+	// The metadata is sanitized once for all events in the stream.
+	assert.Equal(t, 1, calls)
+	for _, event := range events {
+		tx := event.(*model.Transaction)
+		assert.Equal(t, common.MapStr{"sanitized": true}, tx.Metadata.Labels)
+	}
+}
+
+func TestNegativePayloads(t *testing.T) {
+	for _, test := range []struct {
+		dir       string
+		processor func() *Processor
+	}{
+		{dir: "intake-v2", processor: func() *Processor {
+			return BackendProcessor(&config.Config{MaxEventSize: 100 * 1024})
+		}},
+		{dir: "intake-v3", processor: func() *Processor {
+			return RUMV3Processor(&config.Config{MaxEventSize: 100 * 1024}, &transform.Config{})
+		}},
+	} {
+		payloads, err := loader.LoadNegativePayloads("..", "testdata", test.dir, "invalid")
+		require.NoError(t, err)
+		require.NotEmpty(t, payloads)
+		for _, payload := range payloads {
+			t.Run(filepath.Join(test.dir, payload.Name), func(t *testing.T) {
+				report := func(ctx context.Context, p publish.PendingReq) error { return nil }
+				result := test.processor().HandleStream(context.Background(), nil, map[string]interface{}{},
+					bytes.NewReader(payload.Data), report)
+				require.NotEmpty(t, result.Errors, "payload was accepted")
+				for _, expected := range payload.ExpectedErrors {
+					assert.Contains(t, result.Error(), expected)
+				}
+			})
+		}
+	}
+}
+
+func TestHandleLineStreamingDecoders(t *testing.T) {
+	b, err := loader.LoadDataAsBytes("../testdata/intake-v2/events.ndjson")
+	require.NoError(t, err)
+	lines := bytes.Split(bytes.TrimSpace(b), []byte("\n"))
+	require.True(t, len(lines) > 1)
+
+	p := BackendProcessor(&config.Config{MaxEventSize: 100 * 1024})
+	requestTime := time.Now()
+	for _, line := range lines[1:] {
+		var batch model.Batch
+		assert.True(t, p.decodeLineJSON(line, &batch, requestTime, model.Metadata{}, modeldecoder.LatestSchemaVersion), string(line))
+		assert.Equal(t, 1, batch.Len(), string(line))
+	}
+
+	// Invalid events are decoded again into a map, and rejected
+	// with the errors reported by the JSON schema validation.
+	invalid := []byte(`{"transaction":{"id":"abc","trace_id":"def","type":"request","duration":1}}`)
+	var batch model.Batch
+	assert.False(t, p.decodeLineJSON(invalid, &batch, requestTime, model.Metadata{}, modeldecoder.LatestSchemaVersion))
+	err = p.handleLine(invalid, &batch, requestTime, model.Metadata{}, modeldecoder.LatestSchemaVersion)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), `missing properties: "span_count"`)
+	assert.Equal(t, 0, batch.Len())
+}
@@ -20,6 +20,7 @@ package stream
 import (
 	"fmt"
 	"strings"
+	"time"
 
 	"github.com/elastic/beats/v7/libbeat/monitoring"
 )
@@ -28,6 +29,10 @@ type Error struct {
 	Type     StreamError `json:"-"`
 	Message  string      `json:"message"`
 	Document string      `json:"document,omitempty"`
+
+	// rejection identifies the field and reason for which
+	// an event was rejected, if known.
+	rejection rejection
 }
 
 func (s *Error) Error() string {
@@ -47,6 +52,10 @@ const (
 	ServerErrType
 	MethodForbiddenErrType
 	RateLimitErrType
+	TimeoutErrType
+	QuotaExceededErrType
+	ServiceNotAllowedErrType
+	EventTypeNotAllowedErrType
 )
 
 const (
@@ -57,17 +66,53 @@ var (
 	m             = monitoring.Default.NewRegistry("apm-server.processor.stream")
 	mAccepted     = monitoring.NewInt(m, "accepted")
 	monitoringMap = map[StreamError]*monitoring.Int{
-		QueueFullErrType:     monitoring.NewInt(m, "errors.queue"),
-		InvalidInputErrType:  monitoring.NewInt(m, "errors.invalid"),
-		InputTooLargeErrType: monitoring.NewInt(m, "errors.toolarge"),
-		ShuttingDownErrType:  monitoring.NewInt(m, "errors.server"),
-		ServerErrType:        monitoring.NewInt(m, "errors.closed"),
+		QueueFullErrType:         monitoring.NewInt(m, "errors.queue"),
+		InvalidInputErrType:      monitoring.NewInt(m, "errors.invalid"),
+		InputTooLargeErrType:     monitoring.NewInt(m, "errors.toolarge"),
+		ShuttingDownErrType:      monitoring.NewInt(m, "errors.server"),
+		ServerErrType:            monitoring.NewInt(m, "errors.closed"),
+		TimeoutErrType:           monitoring.NewInt(m, "errors.timeout"),
+		QuotaExceededErrType:     monitoring.NewInt(m, "errors.quota"),
+		ServiceNotAllowedErrType: monitoring.NewInt(m, "errors.service_not_allowed"),
+
+		EventTypeNotAllowedErrType: monitoring.NewInt(m, "errors.event_type_not_allowed"),
 	}
+
+	// mActive records the number of streams being processed, and
+	// mDecoding the number of events decoded but not yet accepted
+	// by the publisher.
+	mActive   = monitoring.NewInt(m, "active")
+	mDecoding = monitoring.NewInt(m, "events.decoding")
 )
 
 type Result struct {
 	Accepted int      `json:"accepted"`
 	Errors   []*Error `json:"errors,omitempty"`
+
+	// RequestID holds the ID of the request, for correlating
+	// error responses with server logs.
+	RequestID string `json:"request_id,omitempty"`
+
+	// rejected holds the number of events rejected
+	// for being invalid or too large.
+	rejected int
+
+	// rejections holds the number of events rejected,
+	// by field and reason.
+	rejections map[rejection]int
+
+	// summarized records whether the rejected events
+	// were added to a validation summary.
+	summarized bool
+
+	// samplingRate holds the head-based sampling rate desired
+	// for the service, if hasSamplingRate is true.
+	samplingRate    float64
+	hasSamplingRate bool
+
+	// retryAfter holds the duration after which the client
+	// may retry sending rejected events, if positive.
+	retryAfter time.Duration
 }
 
 func (r *Result) LimitedAdd(err error) {
@@ -83,6 +128,25 @@ func (r *Result) AddAccepted(ct int) {
 	mAccepted.Add(int64(ct))
 }
 
+// Summarized reports whether the events rejected for being invalid
+// or too large were added to a validation summary, to be logged in
+// aggregate.
+func (r *Result) Summarized() bool {
+	return r.summarized
+}
+
+// SamplingRate returns the head-based sampling rate desired for the
+// service sending the events, and whether a sampling rule matched it.
+func (r *Result) SamplingRate() (float64, bool) {
+	return r.samplingRate, r.hasSamplingRate
+}
+
+// RetryAfter returns the duration after which the client may retry
+// sending the events rejected, if positive.
+func (r *Result) RetryAfter() time.Duration {
+	return r.retryAfter
+}
+
 func (r *Result) Error() string {
 	var errorList []string
 	for _, e := range r.Errors {
@@ -99,6 +163,20 @@ func (r *Result) add(err error, add bool) {
 	if add {
 		r.Errors = append(r.Errors, e)
 	}
+	if e.Type == InvalidInputErrType || e.Type == InputTooLargeErrType {
+		r.rejected++
+		rej := e.rejection
+		if rej.reason == "" {
+			rej.reason = "invalid"
+			if e.Type == InputTooLargeErrType {
+				rej.reason = "too_large"
+			}
+		}
+		if r.rejections == nil {
+			r.rejections = make(map[rejection]int)
+		}
+		r.rejections[rej]++
+	}
 	countErr(e.Type)
 }
 
@@ -0,0 +1,204 @@
+// Licensed to Elasticsearch B.V. under one or more contributor
+// license agreements. See the NOTICE file distributed with
+// this work for additional information regarding copyright
+// ownership. Elasticsearch B.V. licenses this file to you under
+// the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package stream
+
+import (
+	"context"
+
+	"github.com/elastic/apm-server/beater/config"
+	"github.com/elastic/apm-server/model"
+	"github.com/elastic/apm-server/utility"
+)
+
+// serviceNameAllowlist returns a function returning the service names
+// RUM agents may claim for a given request origin, or nil if RUM service
+// names are not restricted.
+func serviceNameAllowlist(cfg *config.Config) func(origin string) ([]string, bool) {
+	rum := cfg.RumConfig
+	if rum == nil || (len(rum.AllowServiceNames) == 0 && len(rum.OriginServiceNames) == 0) {
+		return nil
+	}
+	return rum.AllowedServiceNames
+}
+
+// serviceNameSet holds the service names allowed in a stream.
+type serviceNameSet map[string]bool
+
+// serviceNames returns the service names allowed for the origin of the
+// request carried by ctx, or nil if any service name is allowed.
+func (p *Processor) serviceNames(ctx context.Context) serviceNameSet {
+	if p.allowedServiceNames == nil {
+		return nil
+	}
+	allowed, restricted := p.allowedServiceNames(utility.Origin(ctx))
+	if !restricted {
+		return nil
+	}
+	set := make(serviceNameSet, len(allowed))
+	for _, name := range allowed {
+		set[name] = true
+	}
+	return set
+}
+
+// check returns an error if the service named in metadata is not in s.
+func (s serviceNameSet) check(metadata *model.Metadata) *Error {
+	if s[metadata.Service.Name] {
+		return nil
+	}
+	return &Error{
+		Type:    ServiceNotAllowedErrType,
+		Message: "service name '" + metadata.Service.Name + "' is not allowed",
+	}
+}
+
+// filter removes the events whose service is not in s from batch, adding
+// an error to res for each event removed. Events may override the stream's
+// service name with their own context, so each event is checked.
+func (s serviceNameSet) filter(batch *model.Batch, res *Result) {
+	filterBatch(batch, res, func(eventType string, metadata *model.Metadata) *Error {
+		return s.check(metadata)
+	})
+}
+
+// filterBatch removes the events of batch for which check returns an error,
+// adding the error to res.
+func filterBatch(batch *model.Batch, res *Result, check func(eventType string, metadata *model.Metadata) *Error) {
+	keep := func(eventType string, metadata *model.Metadata) bool {
+		if err := check(eventType, metadata); err != nil {
+			res.LimitedAdd(err)
+			return false
+		}
+		return true
+	}
+	transactions := batch.Transactions[:0]
+	for _, event := range batch.Transactions {
+		if keep("transaction", &event.Metadata) {
+			transactions = append(transactions, event)
+		}
+	}
+	for i := len(transactions); i < len(batch.Transactions); i++ {
+		batch.Transactions[i] = nil
+	}
+	batch.Transactions = transactions
+
+	spans := batch.Spans[:0]
+	for _, event := range batch.Spans {
+		if keep("span", &event.Metadata) {
+			spans = append(spans, event)
+		}
+	}
+	for i := len(spans); i < len(batch.Spans); i++ {
+		batch.Spans[i] = nil
+	}
+	batch.Spans = spans
+
+	metricsets := batch.Metricsets[:0]
+	for _, event := range batch.Metricsets {
+		if keep("metricset", &event.Metadata) {
+			metricsets = append(metricsets, event)
+		}
+	}
+	for i := len(metricsets); i < len(batch.Metricsets); i++ {
+		batch.Metricsets[i] = nil
+	}
+	batch.Metricsets = metricsets
+
+	errs := batch.Errors[:0]
+	for _, event := range batch.Errors {
+		if keep("error", &event.Metadata) {
+			errs = append(errs, event)
+		}
+	}
+	for i := len(errs); i < len(batch.Errors); i++ {
+		batch.Errors[i] = nil
+	}
+	batch.Errors = errs
+}
+
+// eventTypeAllowlist returns a function returning the event types RUM
+// agents may send for a given request origin and service name, or nil if
+// RUM event types are not restricted.
+func eventTypeAllowlist(cfg *config.Config) func(origin, serviceName string) ([]string, bool) {
+	rum := cfg.RumConfig
+	if rum == nil || (len(rum.AllowEventTypes) == 0 && len(rum.OriginEventTypes) == 0 && len(rum.ServiceEventTypes) == 0) {
+		return nil
+	}
+	return rum.AllowedEventTypes
+}
+
+// eventTypeSet holds the event types allowed for a service.
+type eventTypeSet map[string]bool
+
+// eventTypeFilter resolves the event types allowed for the services of
+// the events in a stream, caching them by service name.
+type eventTypeFilter struct {
+	origin  string
+	allowed func(origin, serviceName string) ([]string, bool)
+
+	// services holds the event types allowed by service name,
+	// with a nil set if any event type is allowed.
+	services map[string]eventTypeSet
+}
+
+// eventTypes returns an eventTypeFilter for the origin of the request
+// carried by ctx, or nil if any event type is allowed.
+func (p *Processor) eventTypes(ctx context.Context) *eventTypeFilter {
+	if p.allowedEventTypes == nil {
+		return nil
+	}
+	return &eventTypeFilter{
+		origin:   utility.Origin(ctx),
+		allowed:  p.allowedEventTypes,
+		services: make(map[string]eventTypeSet),
+	}
+}
+
+// serviceEventTypes returns the event types allowed for the named service,
+// or nil if any event type is allowed.
+func (f *eventTypeFilter) serviceEventTypes(serviceName string) eventTypeSet {
+	if set, ok := f.services[serviceName]; ok {
+		return set
+	}
+	var set eventTypeSet
+	if allowed, restricted := f.allowed(f.origin, serviceName); restricted {
+		set = make(eventTypeSet, len(allowed))
+		for _, t := range allowed {
+			set[t] = true
+		}
+	}
+	f.services[serviceName] = set
+	return set
+}
+
+// filter removes the events of types not allowed for their service from
+// batch, adding an error to res for each event removed. Events may override
+// the stream's service name with their own context, so the allowed event
+// types are resolved for each event's service.
+func (f *eventTypeFilter) filter(batch *model.Batch, res *Result) {
+	filterBatch(batch, res, func(eventType string, metadata *model.Metadata) *Error {
+		set := f.serviceEventTypes(metadata.Service.Name)
+		if set == nil || set[eventType] {
+			return nil
+		}
+		return &Error{
+			Type:    EventTypeNotAllowedErrType,
+			Message: "event type '" + eventType + "' is not allowed",
+		}
+	})
+}
@@ -0,0 +1,222 @@
+// Licensed to Elasticsearch B.V. under one or more contributor
+// license agreements. See the NOTICE file distributed with
+// this work for additional information regarding copyright
+// ownership. Elasticsearch B.V. licenses this file to you under
+// the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package stream
+
+import (
+	"bytes"
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/elastic/apm-server/beater/config"
+	"github.com/elastic/apm-server/model"
+	"github.com/elastic/apm-server/publish"
+	"github.com/elastic/apm-server/tests/loader"
+	"github.com/elastic/apm-server/transform"
+	"github.com/elastic/apm-server/utility"
+)
+
+func TestRUMServiceNameAllowlist(t *testing.T) {
+	b, err := loader.LoadDataAsBytes("../testdata/intake-v2/transactions_spans_rum.ndjson")
+	require.NoError(t, err)
+
+	rumConfig := &config.RumConfig{
+		AllowServiceNames: []string{"apm-agent-js"},
+		OriginServiceNames: []config.OriginServiceNames{
+			{Origin: "https://*.example.com", ServiceNames: []string{"frontend"}},
+			{Origin: "https://open.example.org", ServiceNames: []string{}},
+			{Origin: "https://closed.example.org", ServiceNames: []string{"apm-agent-js"}},
+		},
+	}
+	for name, test := range map[string]struct {
+		origin   string
+		accepted bool
+	}{
+		"no origin":               {origin: "", accepted: false},
+		"unmatched origin":        {origin: "https://other.example.org", accepted: false},
+		"origin with empty list":  {origin: "https://open.example.org", accepted: false},
+		"origin with other names": {origin: "https://shop.example.com", accepted: false},
+		"origin with service":     {origin: "https://closed.example.org", accepted: true},
+	} {
+		t.Run(name, func(t *testing.T) {
+			var reported int
+			report := func(ctx context.Context, p publish.PendingReq) error {
+				reported += len(p.Transformables)
+				return nil
+			}
+			ctx := context.Background()
+			if test.origin != "" {
+				ctx = utility.ContextWithOrigin(ctx, test.origin)
+			}
+			p := RUMProcessor(&config.Config{MaxEventSize: 100 * 1024, RumConfig: rumConfig}, &transform.Config{})
+			result := p.HandleStream(ctx, nil, map[string]interface{}{}, bytes.NewReader(b), report)
+			if test.accepted {
+				require.Empty(t, result.Errors)
+				assert.NotZero(t, reported)
+				return
+			}
+			require.Len(t, result.Errors, 1)
+			assert.Equal(t, ServiceNotAllowedErrType, result.Errors[0].Type)
+			assert.Equal(t, "service name 'apm-agent-js' is not allowed", result.Errors[0].Message)
+			assert.Zero(t, reported)
+		})
+	}
+}
+
+func TestRUMServiceNameAllowlistDisallowed(t *testing.T) {
+	b, err := loader.LoadDataAsBytes("../testdata/intake-v2/transactions_spans_rum.ndjson")
+	require.NoError(t, err)
+
+	report := func(ctx context.Context, p publish.PendingReq) error {
+		t.Fatal("unexpected report")
+		return nil
+	}
+	cfg := &config.Config{
+		MaxEventSize: 100 * 1024,
+		RumConfig:    &config.RumConfig{AllowServiceNames: []string{"frontend"}},
+	}
+	result := RUMProcessor(cfg, &transform.Config{}).HandleStream(
+		context.Background(), nil, map[string]interface{}{}, bytes.NewReader(b), report,
+	)
+	require.Len(t, result.Errors, 1)
+	assert.Equal(t, ServiceNotAllowedErrType, result.Errors[0].Type)
+
+	// The backend processor never restricts service names.
+	result = BackendProcessor(cfg).HandleStream(
+		context.Background(), nil, map[string]interface{}{}, bytes.NewReader(b), func(context.Context, publish.PendingReq) error { return nil },
+	)
+	assert.Empty(t, result.Errors)
+}
+
+func TestRUMServiceNameAllowlistEventOverride(t *testing.T) {
+	// The stream metadata names an allowed service, but events may
+	// override the service name with their own context.
+	payload := `{"metadata":{"service":{"name":"frontend","agent":{"name":"rum-js","version":"5.0.0"}}}}
+{"transaction":{"id":"611f4fa950f04631","trace_id":"611f4fa950f04631aaaaaaaaaaaaaaaa","type":"page-load","duration":643,"span_count":{"started":0}}}
+{"transaction":{"id":"611f4fa950f04632","trace_id":"611f4fa950f04631aaaaaaaaaaaaaaaa","type":"page-load","duration":643,"span_count":{"started":0},"context":{"service":{"name":"spoofed"}}}}
+{"error":{"id":"611f4fa950f04633","exception":{"message":"boom"},"context":{"service":{"name":"spoofed"}}}}
+`
+	var reported []string
+	report := func(ctx context.Context, p publish.PendingReq) error {
+		for _, tr := range p.Transformables {
+			reported = append(reported, tr.(*model.Transaction).Metadata.Service.Name)
+		}
+		return nil
+	}
+	cfg := &config.Config{
+		MaxEventSize: 100 * 1024,
+		RumConfig:    &config.RumConfig{AllowServiceNames: []string{"frontend"}},
+	}
+	result := RUMProcessor(cfg, &transform.Config{}).HandleStream(
+		context.Background(), nil, map[string]interface{}{}, strings.NewReader(payload), report,
+	)
+	require.Len(t, result.Errors, 2)
+	for _, err := range result.Errors {
+		assert.Equal(t, ServiceNotAllowedErrType, err.Type)
+		assert.Equal(t, "service name 'spoofed' is not allowed", err.Message)
+	}
+	assert.Equal(t, []string{"frontend"}, reported)
+	assert.Equal(t, 1, result.Accepted)
+}
+
+func TestRUMEventTypeAllowlist(t *testing.T) {
+	b, err := loader.LoadDataAsBytes("../testdata/intake-v2/transactions_spans_rum.ndjson")
+	require.NoError(t, err)
+
+	rumConfig := &config.RumConfig{
+		OriginEventTypes: []config.OriginEventTypes{
+			{Origin: "https://*.example.com", EventTypes: []string{"transaction", "error"}},
+		},
+		ServiceEventTypes: []config.ServiceEventTypes{
+			{ServiceName: "apm-agent-js", EventTypes: []string{"error"}},
+		},
+	}
+	for name, test := range map[string]struct {
+		origin   string
+		rumV3    bool
+		rumCfg   *config.RumConfig
+		rejected []string
+	}{
+		"unrestricted":       {rumCfg: &config.RumConfig{}},
+		"restricted origin":  {origin: "https://shop.example.com", rumCfg: &config.RumConfig{OriginEventTypes: rumConfig.OriginEventTypes}, rejected: []string{"span"}},
+		"other origin":       {origin: "https://example.org", rumCfg: &config.RumConfig{OriginEventTypes: rumConfig.OriginEventTypes}},
+		"restricted service": {origin: "https://shop.example.com", rumCfg: rumConfig, rejected: []string{"transaction", "span"}},
+		"empty list": {rumCfg: &config.RumConfig{ServiceEventTypes: []config.ServiceEventTypes{
+			{ServiceName: "apm-agent-js", EventTypes: []string{}},
+		}}, rejected: []string{"transaction", "span"}},
+	} {
+		t.Run(name, func(t *testing.T) {
+			var reported int
+			report := func(ctx context.Context, p publish.PendingReq) error {
+				reported += len(p.Transformables)
+				return nil
+			}
+			ctx := context.Background()
+			if test.origin != "" {
+				ctx = utility.ContextWithOrigin(ctx, test.origin)
+			}
+			p := RUMProcessor(&config.Config{MaxEventSize: 100 * 1024, RumConfig: test.rumCfg}, &transform.Config{})
+			result := p.HandleStream(ctx, nil, map[string]interface{}{}, bytes.NewReader(b), report)
+
+			var rejected []string
+			for _, err := range result.Errors {
+				assert.Equal(t, EventTypeNotAllowedErrType, err.Type)
+				rejected = append(rejected, err.Message)
+			}
+			var expected []string
+			for _, eventType := range test.rejected {
+				expected = append(expected, "event type '"+eventType+"' is not allowed")
+			}
+			assert.Equal(t, expected, rejected)
+			assert.Equal(t, 2-len(test.rejected), reported)
+			assert.Equal(t, reported, result.Accepted)
+		})
+	}
+}
+
+func TestRUMEventTypeAllowlistEventOverride(t *testing.T) {
+	// Event types are restricted for the service of each event,
+	// which may differ from the service named in the stream metadata.
+	payload := `{"metadata":{"service":{"name":"frontend","agent":{"name":"rum-js","version":"5.0.0"}}}}
+{"transaction":{"id":"611f4fa950f04631","trace_id":"611f4fa950f04631aaaaaaaaaaaaaaaa","type":"page-load","duration":643,"span_count":{"started":0}}}
+{"transaction":{"id":"611f4fa950f04632","trace_id":"611f4fa950f04631aaaaaaaaaaaaaaaa","type":"page-load","duration":643,"span_count":{"started":0},"context":{"service":{"name":"restricted"}}}}
+{"error":{"id":"611f4fa950f04633","exception":{"message":"boom"},"context":{"service":{"name":"restricted"}}}}
+`
+	var reported int
+	report := func(ctx context.Context, p publish.PendingReq) error {
+		reported += len(p.Transformables)
+		return nil
+	}
+	cfg := &config.Config{
+		MaxEventSize: 100 * 1024,
+		RumConfig: &config.RumConfig{ServiceEventTypes: []config.ServiceEventTypes{
+			{ServiceName: "restricted", EventTypes: []string{"error"}},
+		}},
+	}
+	result := RUMProcessor(cfg, &transform.Config{}).HandleStream(
+		context.Background(), nil, map[string]interface{}{}, strings.NewReader(payload), report,
+	)
+	require.Len(t, result.Errors, 1)
+	assert.Equal(t, EventTypeNotAllowedErrType, result.Errors[0].Type)
+	assert.Equal(t, "event type 'transaction' is not allowed", result.Errors[0].Message)
+	assert.Equal(t, 2, reported)
+	assert.Equal(t, 2, result.Accepted)
+}
@@ -0,0 +1,219 @@
+// Licensed to Elasticsearch B.V. under one or more contributor
+// license agreements. See the NOTICE file distributed with
+// this work for additional information regarding copyright
+// ownership. Elasticsearch B.V. licenses this file to you under
+// the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package stream
+
+import (
+	"errors"
+	"regexp"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	jsoniter "github.com/json-iterator/go"
+	"github.com/santhosh-tekuri/jsonschema"
+
+	"github.com/elastic/beats/v7/libbeat/logp"
+
+	logs "github.com/elastic/apm-server/log"
+)
+
+// maxValidationSummaryEntries is the number of distinct agents, fields and
+// reasons aggregated in each validation summary. Agent names and versions,
+// and the fields of invalid events, are sent by clients, so the number of
+// entries must be bounded.
+const maxValidationSummaryEntries = 1000
+
+var (
+	arrayIndexPattern = regexp.MustCompile(`\.[0-9]+(\.|$)`)
+
+	// generatedReasons maps the messages of errors returned by the
+	// generated decoders' Validate methods to validation reasons,
+	// matching the JSON Schema keywords used by jsonschema errors.
+	generatedReasons = []struct {
+		message string
+		reason  string
+	}{
+		{"missing required property", "required"},
+		{"length must be", "maxLength"},
+		{"does not match pattern", "pattern"},
+		{"unexpected type", "type"},
+	}
+)
+
+// rejection identifies the field, and the reason, for which an event was rejected.
+type rejection struct {
+	field  string
+	reason string
+}
+
+// ValidationSummary aggregates events rejected for being invalid or too large,
+// by agent name and version, field, and reason, and periodically logs a summary
+// of them. This avoids flooding the logs when an agent version sending invalid
+// events is rolled out.
+type ValidationSummary struct {
+	interval time.Duration
+	logger   *logp.Logger
+
+	mu        sync.Mutex
+	counts    map[validationSummaryKey]int
+	overflow  int
+	scheduled bool
+}
+
+type validationSummaryKey struct {
+	agentName    string
+	agentVersion string
+	rejection
+}
+
+// NewValidationSummary returns a new ValidationSummary, logging a summary
+// of rejected events at most once per interval.
+func NewValidationSummary(interval time.Duration) *ValidationSummary {
+	return &ValidationSummary{
+		interval: interval,
+		logger:   logp.NewLogger(logs.Validation),
+		counts:   make(map[validationSummaryKey]int),
+	}
+}
+
+// add adds the events rejected in a request from the given agent to the
+// summary, scheduling the summary to be logged if it is not already.
+func (s *ValidationSummary) add(agentName, agentVersion string, rejections map[rejection]int) {
+	if len(rejections) == 0 {
+		return
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for r, n := range rejections {
+		key := validationSummaryKey{agentName: agentName, agentVersion: agentVersion, rejection: r}
+		if _, ok := s.counts[key]; !ok && len(s.counts) >= maxValidationSummaryEntries {
+			s.overflow += n
+			continue
+		}
+		s.counts[key] += n
+	}
+	if !s.scheduled {
+		s.scheduled = true
+		time.AfterFunc(s.interval, s.log)
+	}
+}
+
+// log logs the summary of events rejected since it was last logged,
+// most frequently rejected first, and resets the summary.
+func (s *ValidationSummary) log() {
+	s.mu.Lock()
+	counts, overflow := s.counts, s.overflow
+	s.counts = make(map[validationSummaryKey]int)
+	s.overflow = 0
+	s.scheduled = false
+	s.mu.Unlock()
+
+	type entry struct {
+		AgentName    string `json:"agent.name"`
+		AgentVersion string `json:"agent.version"`
+		Field        string `json:"field,omitempty"`
+		Reason       string `json:"reason"`
+		Count        int    `json:"count"`
+	}
+	var total int
+	entries := make([]entry, 0, len(counts))
+	for key, count := range counts {
+		entries = append(entries, entry{
+			AgentName:    key.agentName,
+			AgentVersion: key.agentVersion,
+			Field:        key.field,
+			Reason:       key.reason,
+			Count:        count,
+		})
+		total += count
+	}
+	sort.Slice(entries, func(i, j int) bool {
+		if entries[i].Count != entries[j].Count {
+			return entries[i].Count > entries[j].Count
+		}
+		a, b := entries[i], entries[j]
+		return a.AgentName+a.AgentVersion+a.Field+a.Reason < b.AgentName+b.AgentVersion+b.Field+b.Reason
+	})
+	s.logger.Warnw("events rejected by validation",
+		"interval", s.interval.String(),
+		"rejected", total+overflow,
+		"not_summarized", overflow,
+		"summary", entries,
+	)
+}
+
+// classifyLineError returns the field of the event in line, and the reason,
+// for which decoding the event failed with err. The field is empty if the
+// event could not be decoded at all.
+func classifyLineError(err error, line []byte) rejection {
+	if errors.Is(err, ErrUnrecognizedObject) {
+		return rejection{reason: "unrecognized_event"}
+	}
+	eventType := lineEventType(line)
+
+	var verr *jsonschema.ValidationError
+	if errors.As(err, &verr) {
+		for len(verr.Causes) > 0 {
+			verr = verr.Causes[0]
+		}
+		field := strings.Replace(strings.TrimPrefix(verr.InstancePtr, "#"), "/", ".", -1)
+		return rejection{
+			field:  normalizeField(eventType + field),
+			reason: verr.SchemaPtr[strings.LastIndex(verr.SchemaPtr, "/")+1:],
+		}
+	}
+
+	// Errors returned by the generated decoders are wrapped with the path
+	// of the invalid field, e.g. "failed to validate metricset: span: subtype:
+	// length must be <= 1024".
+	msg := err.Error()
+	for _, r := range generatedReasons {
+		i := strings.Index(msg, r.message)
+		if i < 0 {
+			continue
+		}
+		field := eventType
+		if j := strings.LastIndex(msg[:i], ": "); j >= 0 {
+			if path := strings.Split(msg[:j], ": "); len(path) > 1 {
+				field += "." + strings.Join(path[1:], ".")
+			}
+		}
+		return rejection{field: normalizeField(field), reason: r.reason}
+	}
+	return rejection{field: eventType, reason: "invalid"}
+}
+
+// lineEventType returns the event type of an ND-JSON line,
+// or the empty string if the line is not a JSON object.
+func lineEventType(line []byte) string {
+	iter := jsoniter.ConfigFastest.BorrowIterator(line)
+	defer jsoniter.ConfigFastest.ReturnIterator(iter)
+	key := iter.ReadObject()
+	if iter.Error != nil {
+		return ""
+	}
+	return key
+}
+
+// normalizeField replaces array indices in field with "*",
+// so that the same field of different array elements is
+// summarized together.
+func normalizeField(field string) string {
+	return arrayIndexPattern.ReplaceAllString(field, ".*$1")
+}
@@ -0,0 +1,119 @@
+// Licensed to Elasticsearch B.V. under one or more contributor
+// license agreements. See the NOTICE file distributed with
+// this work for additional information regarding copyright
+// ownership. Elasticsearch B.V. licenses this file to you under
+// the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package stream
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/elastic/beats/v7/libbeat/logp"
+
+	"github.com/elastic/apm-server/beater/config"
+	logs "github.com/elastic/apm-server/log"
+	"github.com/elastic/apm-server/publish"
+	"github.com/elastic/apm-server/tests/loader"
+)
+
+func TestValidationSummary(t *testing.T) {
+	err := logp.DevelopmentSetup(logp.ToObserverOutput())
+	require.NoError(t, err)
+
+	summary := NewValidationSummary(time.Hour)
+	sp := BackendProcessor(&config.Config{MaxEventSize: 100 * 1024})
+	sp.ValidationSummary = summary
+	for _, name := range []string{
+		"invalid-event.ndjson",
+		"invalid-json-event.ndjson",
+		"unrecognized-event.ndjson",
+		"invalid-metricset.ndjson",
+	} {
+		b, err := loader.LoadDataAsBytes("../testdata/intake-v2/" + name)
+		require.NoError(t, err)
+		result := sp.HandleStream(context.Background(), nil, map[string]interface{}{}, bytes.NewReader(b), func(context.Context, publish.PendingReq) error { return nil })
+		require.NotEmpty(t, result.Errors, name)
+		assert.True(t, result.Summarized(), name)
+	}
+	agent := func(r rejection) validationSummaryKey {
+		return validationSummaryKey{agentName: "elastic-node", agentVersion: "3.14.0", rejection: r}
+	}
+	assert.Equal(t, map[validationSummaryKey]int{
+		agent(rejection{reason: "invalid_json"}):                                        1,
+		agent(rejection{reason: "unrecognized_event"}):                                  1,
+		agent(rejection{field: "transaction.id", reason: "type"}):                       1,
+		agent(rejection{field: "metricset.tags", reason: "additionalProperties"}):       1,
+		agent(rejection{field: "metricset.samples.byte_counter.value", reason: "type"}): 1,
+	}, summary.counts)
+
+	summary.log()
+	assert.Empty(t, summary.counts)
+	entries := logp.ObserverLogs().TakeAll()
+	require.Len(t, entries, 1)
+	assert.Equal(t, logs.Validation, entries[0].LoggerName)
+	fields := entries[0].ContextMap()
+	assert.Equal(t, int64(5), fields["rejected"])
+	assert.Equal(t, int64(0), fields["not_summarized"])
+}
+
+func TestValidationSummaryOverflow(t *testing.T) {
+	summary := NewValidationSummary(time.Hour)
+	for i := 0; i < maxValidationSummaryEntries+10; i++ {
+		summary.add("go", strconv.Itoa(i), map[rejection]int{{reason: "invalid"}: 2})
+	}
+	assert.Len(t, summary.counts, maxValidationSummaryEntries)
+	assert.Equal(t, 20, summary.overflow)
+}
+
+func TestClassifyLineError(t *testing.T) {
+	for name, tc := range map[string]struct {
+		err    error
+		line   string
+		expect rejection
+	}{
+		"unrecognized": {
+			err:    ErrUnrecognizedObject,
+			line:   `{"foo":{}}`,
+			expect: rejection{reason: "unrecognized_event"},
+		},
+		"generated": {
+			err:    errors.New("failed to validate span: context: tags: length must be <= 1024"),
+			line:   `{"span":{}}`,
+			expect: rejection{field: "span.context.tags", reason: "maxLength"},
+		},
+		"generated_array": {
+			err:    errors.New("failed to validate span: stacktrace: 3: filename: missing required property"),
+			line:   `{"span":{}}`,
+			expect: rejection{field: "span.stacktrace.*.filename", reason: "required"},
+		},
+		"other": {
+			err:    errors.New("boom"),
+			line:   `{"error":{}}`,
+			expect: rejection{field: "error", reason: "invalid"},
+		},
+	} {
+		t.Run(name, func(t *testing.T) {
+			assert.Equal(t, tc.expect, classifyLineError(tc.err, []byte(tc.line)))
+		})
+	}
+}
@@ -0,0 +1,165 @@
+// Licensed to Elasticsearch B.V. under one or more contributor
+// license agreements. See the NOTICE file distributed with
+// this work for additional information regarding copyright
+// ownership. Elasticsearch B.V. licenses this file to you under
+// the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package publish
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/elastic/beats/v7/libbeat/logp"
+	"github.com/elastic/beats/v7/libbeat/monitoring"
+
+	logs "github.com/elastic/apm-server/log"
+)
+
+var (
+	healthRegistry      = monitoring.Default.NewRegistry("apm-server.publish.health")
+	unhealthyGauge      = monitoring.NewInt(healthRegistry, "throttled")
+	healthRejected      = monitoring.NewInt(healthRegistry, "rejected")
+	healthCheckFailures = monitoring.NewInt(healthRegistry, "check_failures")
+)
+
+// IntakeBackpressureConfig holds configuration for NewIntakeBackpressure.
+type IntakeBackpressureConfig struct {
+	// Interval defines how often Check is called.
+	Interval time.Duration
+
+	// MaxWait defines how long to wait for an unhealthy
+	// output to recover, before returning ErrFull.
+	MaxWait time.Duration
+
+	// Check checks the health of the output, returning a non-empty
+	// reason if the output is unhealthy. If Check returns an error,
+	// the health of the output is left unchanged.
+	Check func(context.Context) (reason string, err error)
+}
+
+// IntakeBackpressure holds back intake while the output is unhealthy.
+//
+// While the output is unhealthy, reporters wait for it to recover, so
+// that intake requests are slowed down and, after waiting for longer than
+// MaxWait, rejected with ErrFull; agents then buffer events and retry.
+//
+// IntakeBackpressure does not throttle the output itself: events which
+// have already been reported continue to be published from the queue.
+type IntakeBackpressure struct {
+	cfg    IntakeBackpressureConfig
+	logger *logp.Logger
+
+	mu        sync.Mutex
+	reason    string
+	recovered chan struct{}
+}
+
+// NewIntakeBackpressure returns a new IntakeBackpressure with the given config.
+//
+// The output is considered healthy until Run has checked otherwise.
+func NewIntakeBackpressure(cfg IntakeBackpressureConfig) *IntakeBackpressure {
+	unhealthyGauge.Set(0)
+	return &IntakeBackpressure{
+		cfg:       cfg,
+		logger:    logp.NewLogger(logs.OutputHealth),
+		recovered: make(chan struct{}),
+	}
+}
+
+// Run periodically checks the health of the output until ctx is
+// cancelled, at which point reports are no longer held back.
+func (t *IntakeBackpressure) Run(ctx context.Context) error {
+	defer t.update("")
+	ticker := time.NewTicker(t.cfg.Interval)
+	defer ticker.Stop()
+	for {
+		reason, err := t.cfg.Check(ctx)
+		if err != nil {
+			if ctx.Err() != nil {
+				return nil
+			}
+			healthCheckFailures.Inc()
+			t.logger.Warnf("failed to check output health: %s", err)
+		} else {
+			t.update(reason)
+		}
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-ticker.C:
+		}
+	}
+}
+
+// Unhealthy returns the reason the output is considered
+// unhealthy, or the empty string if it is healthy.
+func (t *IntakeBackpressure) Unhealthy() string {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	return t.reason
+}
+
+// Wrap returns a Reporter which reports to reporter once the output is healthy.
+func (t *IntakeBackpressure) Wrap(reporter Reporter) Reporter {
+	return func(ctx context.Context, req PendingReq) error {
+		if err := t.wait(ctx); err != nil {
+			return err
+		}
+		return reporter(ctx, req)
+	}
+}
+
+func (t *IntakeBackpressure) wait(ctx context.Context) error {
+	t.mu.Lock()
+	if t.reason == "" {
+		t.mu.Unlock()
+		return nil
+	}
+	recovered := t.recovered
+	t.mu.Unlock()
+
+	timer := time.NewTimer(t.cfg.MaxWait)
+	defer timer.Stop()
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-timer.C:
+		healthRejected.Inc()
+		return ErrFull
+	case <-recovered:
+		return nil
+	}
+}
+
+func (t *IntakeBackpressure) update(reason string) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	if reason == t.reason {
+		return
+	}
+	switch {
+	case reason == "":
+		t.logger.Info("output recovered, no longer holding back intake")
+		unhealthyGauge.Set(0)
+		// Wake up all waiting reporters.
+		close(t.recovered)
+		t.recovered = make(chan struct{})
+	case t.reason == "":
+		t.logger.Warnf("holding back intake, output is unhealthy: %s", reason)
+		unhealthyGauge.Set(1)
+	}
+	t.reason = reason
+}
@@ -0,0 +1,78 @@
+// Licensed to Elasticsearch B.V. under one or more contributor
+// license agreements. See the NOTICE file distributed with
+// this work for additional information regarding copyright
+// ownership. Elasticsearch B.V. licenses this file to you under
+// the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package publish
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestIntakeBackpressure(t *testing.T) {
+	checks := make(chan string)
+	backpressure := NewIntakeBackpressure(IntakeBackpressureConfig{
+		Interval: time.Millisecond,
+		MaxWait:  50 * time.Millisecond,
+		Check: func(ctx context.Context) (string, error) {
+			select {
+			case <-ctx.Done():
+				return "", ctx.Err()
+			case reason := <-checks:
+				if reason == "error" {
+					return "", errors.New("connection refused")
+				}
+				return reason, nil
+			}
+		},
+	})
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan error)
+	go func() { done <- backpressure.Run(ctx) }()
+
+	var reported int
+	reporter := backpressure.Wrap(func(context.Context, PendingReq) error {
+		reported++
+		return nil
+	})
+	require.NoError(t, reporter(context.Background(), PendingReq{}))
+	assert.Equal(t, 1, reported)
+
+	checks <- "cluster health is red"
+	checks <- "error" // failed checks leave the health unchanged
+	assert.Equal(t, "cluster health is red", backpressure.Unhealthy())
+	assert.Equal(t, ErrFull, reporter(context.Background(), PendingReq{}))
+	assert.Equal(t, 1, reported)
+
+	// Reports waiting for the output are released once it recovers.
+	errs := make(chan error)
+	backpressure.cfg.MaxWait = time.Minute
+	go func() { errs <- reporter(context.Background(), PendingReq{}) }()
+	checks <- ""
+	require.NoError(t, <-errs)
+	assert.Equal(t, 2, reported)
+
+	// Reports are no longer held back once Run returns.
+	checks <- "cluster health is red"
+	cancel()
+	require.NoError(t, <-done)
+	assert.Equal(t, "", backpressure.Unhealthy())
+}
@@ -0,0 +1,165 @@
+// Licensed to Elasticsearch B.V. under one or more contributor
+// license agreements. See the NOTICE file distributed with
+// this work for additional information regarding copyright
+// ownership. Elasticsearch B.V. licenses this file to you under
+// the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package publish
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/elastic/beats/v7/libbeat/monitoring"
+)
+
+var (
+	limiterRegistry      = monitoring.Default.NewRegistry("apm-server.publish.limiter")
+	limiterLimitGauge    = monitoring.NewInt(limiterRegistry, "limit")
+	limiterInflightGauge = monitoring.NewInt(limiterRegistry, "inflight")
+	limiterRejected      = monitoring.NewInt(limiterRegistry, "rejected")
+)
+
+// AdaptiveLimiterConfig holds configuration for NewAdaptiveLimiter.
+type AdaptiveLimiterConfig struct {
+	// Min, Max and Initial bound the concurrency limit,
+	// and define its value on creation.
+	Min, Max, Initial int
+
+	// LatencyThreshold defines the reporting latency above which
+	// the limit is decreased multiplicatively by BackoffRatio.
+	//
+	// The limit is decreased at most once per latency window: reports
+	// which started before the last decrease do not decrease it again,
+	// as they were admitted under the previous limit.
+	LatencyThreshold time.Duration
+	BackoffRatio     float64
+
+	// MaxWait defines how long to wait for the number of
+	// concurrent reports to drop below the limit, before
+	// returning ErrFull.
+	MaxWait time.Duration
+}
+
+// AdaptiveLimiter limits the number of concurrent calls to a Reporter,
+// adapting the limit using additive-increase/multiplicative-decrease (AIMD).
+//
+// Reporters block when the publisher's queue cannot keep up with the output,
+// so the time taken to report events indicates the health of the output. While
+// reporting is fast, the limit is increased by roughly one for each limit's
+// worth of reports; when reporting is slow or the queue is full, the limit is
+// multiplied by the backoff ratio, once for all reports in flight at the time.
+// This smooths throughput, rather than having all requests proceed until the
+// queue is full, and then all fail.
+type AdaptiveLimiter struct {
+	cfg AdaptiveLimiterConfig
+
+	mu          sync.Mutex
+	limit       float64
+	inflight    int
+	released    chan struct{}
+	lastBackoff time.Time
+}
+
+// NewAdaptiveLimiter returns a new AdaptiveLimiter with the given config.
+func NewAdaptiveLimiter(cfg AdaptiveLimiterConfig) *AdaptiveLimiter {
+	limiterLimitGauge.Set(int64(cfg.Initial))
+	return &AdaptiveLimiter{
+		cfg:      cfg,
+		limit:    float64(cfg.Initial),
+		released: make(chan struct{}),
+	}
+}
+
+// Limit returns the current concurrency limit.
+func (l *AdaptiveLimiter) Limit() int {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	return int(l.limit)
+}
+
+// Wrap returns a Reporter which reports to reporter, subject to the limiter.
+func (l *AdaptiveLimiter) Wrap(reporter Reporter) Reporter {
+	return func(ctx context.Context, req PendingReq) error {
+		if err := l.acquire(ctx); err != nil {
+			return err
+		}
+		start := time.Now()
+		err := reporter(ctx, req)
+		l.release(start, err)
+		return err
+	}
+}
+
+func (l *AdaptiveLimiter) acquire(ctx context.Context) error {
+	var timeout <-chan time.Time
+	for {
+		l.mu.Lock()
+		if l.inflight < int(l.limit) {
+			l.inflight++
+			limiterInflightGauge.Set(int64(l.inflight))
+			l.mu.Unlock()
+			return nil
+		}
+		released := l.released
+		l.mu.Unlock()
+
+		if timeout == nil {
+			timer := time.NewTimer(l.cfg.MaxWait)
+			defer timer.Stop()
+			timeout = timer.C
+		}
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-timeout:
+			limiterRejected.Inc()
+			return ErrFull
+		case <-released:
+		}
+	}
+}
+
+func (l *AdaptiveLimiter) release(start time.Time, err error) {
+	now := time.Now()
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.inflight--
+	limiterInflightGauge.Set(int64(l.inflight))
+
+	if err == ErrFull || now.Sub(start) > l.cfg.LatencyThreshold {
+		// Reports which started before the last backoff were
+		// admitted under the previous limit, and have already
+		// been accounted for.
+		if start.After(l.lastBackoff) {
+			l.lastBackoff = now
+			l.limit *= l.cfg.BackoffRatio
+			if min := float64(l.cfg.Min); l.limit < min {
+				l.limit = min
+			}
+		}
+	} else if err == nil {
+		l.limit += 1 / l.limit
+		if max := float64(l.cfg.Max); l.limit > max {
+			l.limit = max
+		}
+	}
+	limiterLimitGauge.Set(int64(l.limit))
+
+	// Wake up all waiting reporters; they will
+	// compete for the newly available capacity.
+	close(l.released)
+	l.released = make(chan struct{})
+}
@@ -0,0 +1,141 @@
+// Licensed to Elasticsearch B.V. under one or more contributor
+// license agreements. See the NOTICE file distributed with
+// this work for additional information regarding copyright
+// ownership. Elasticsearch B.V. licenses this file to you under
+// the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package publish
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newTestLimiter() *AdaptiveLimiter {
+	return NewAdaptiveLimiter(AdaptiveLimiterConfig{
+		Min:              2,
+		Max:              5,
+		Initial:          3,
+		LatencyThreshold: time.Second,
+		BackoffRatio:     0.5,
+		MaxWait:          50 * time.Millisecond,
+	})
+}
+
+func TestAdaptiveLimiterAdditiveIncrease(t *testing.T) {
+	l := newTestLimiter()
+	reporter := l.Wrap(func(context.Context, PendingReq) error { return nil })
+	// The limit is increased by 1/limit for each report:
+	// 3 + 1/3 + 1/3.33 + 1/3.63 + 1/3.91 ≈ 4.16
+	for i := 0; i < 4; i++ {
+		require.NoError(t, reporter(context.Background(), PendingReq{}))
+	}
+	assert.Equal(t, 4, l.Limit())
+	for i := 0; i < 100; i++ {
+		require.NoError(t, reporter(context.Background(), PendingReq{}))
+	}
+	assert.Equal(t, 5, l.Limit())
+}
+
+func TestAdaptiveLimiterMultiplicativeDecrease(t *testing.T) {
+	l := newTestLimiter()
+	l.cfg.LatencyThreshold = time.Millisecond
+
+	slow := l.Wrap(func(context.Context, PendingReq) error {
+		time.Sleep(2 * time.Millisecond)
+		return nil
+	})
+	require.NoError(t, slow(context.Background(), PendingReq{}))
+	assert.Equal(t, 2, l.Limit()) // 3 * 0.5 = 1.5, bounded by min
+
+	l = newTestLimiter()
+	full := l.Wrap(func(context.Context, PendingReq) error { return ErrFull })
+	assert.Equal(t, ErrFull, full(context.Background(), PendingReq{}))
+	assert.Equal(t, 2, l.Limit())
+}
+
+func TestAdaptiveLimiterBackoffOncePerWindow(t *testing.T) {
+	l := newTestLimiter()
+	l.cfg.Min = 1
+	l.cfg.Max = 100
+	l.limit = 40
+	l.cfg.LatencyThreshold = time.Millisecond
+
+	unblock := make(chan struct{})
+	slow := l.Wrap(func(context.Context, PendingReq) error {
+		<-unblock
+		time.Sleep(2 * time.Millisecond)
+		return nil
+	})
+	errs := make(chan error)
+	for i := 0; i < 3; i++ {
+		go func() { errs <- slow(context.Background(), PendingReq{}) }()
+	}
+	assert.Eventually(t, func() bool {
+		l.mu.Lock()
+		defer l.mu.Unlock()
+		return l.inflight == 3
+	}, time.Second, time.Millisecond)
+
+	// All three reports were in flight when the first one backed
+	// off, so the limit is only decreased once.
+	close(unblock)
+	for i := 0; i < 3; i++ {
+		require.NoError(t, <-errs)
+	}
+	assert.Equal(t, 20, l.Limit())
+
+	// Reports started after the backoff may decrease it again.
+	require.NoError(t, slow(context.Background(), PendingReq{}))
+	assert.Equal(t, 10, l.Limit())
+}
+
+func TestAdaptiveLimiterWait(t *testing.T) {
+	l := newTestLimiter()
+	unblock := make(chan struct{})
+	blocking := l.Wrap(func(context.Context, PendingReq) error {
+		<-unblock
+		return nil
+	})
+
+	errs := make(chan error)
+	for i := 0; i < 3; i++ {
+		go func() { errs <- blocking(context.Background(), PendingReq{}) }()
+	}
+	assert.Eventually(t, func() bool {
+		l.mu.Lock()
+		defer l.mu.Unlock()
+		return l.inflight == 3
+	}, time.Second, time.Millisecond)
+
+	// The limit has been reached, so further reports wait for
+	// up to MaxWait before being rejected.
+	assert.Equal(t, ErrFull, blocking(context.Background(), PendingReq{}))
+
+	// Reports proceed once the number of inflight reports drops.
+	go func() { errs <- blocking(context.Background(), PendingReq{}) }()
+	for i := 0; i < 4; i++ {
+		unblock <- struct{}{}
+		assert.NoError(t, <-errs)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	l.limit = 0
+	assert.Equal(t, context.Canceled, blocking(ctx, PendingReq{}))
+}
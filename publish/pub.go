@@ -21,6 +21,7 @@ import (
 	"context"
 	"runtime"
 	"sync"
+	"sync/atomic"
 	"time"
 
 	"github.com/pkg/errors"
@@ -46,12 +47,26 @@ type Publisher struct {
 	client          beat.Client
 	m               sync.RWMutex
 	stopped         bool
+
+	// inflightBytes holds the approximate memory held by requests
+	// which have been sent, but not yet published to libbeat.
+	inflightBytes    int64
+	maxInflightBytes int64
 }
 
 type PendingReq struct {
 	Transformables []transform.Transformable
 	Tcontext       *transform.Context
 	Trace          bool
+
+	// Size holds the approximate memory held by Transformables,
+	// in bytes, for limiting the memory held by unpublished events.
+	Size int64
+
+	// Context, if non-nil, is done if the request the events were received
+	// in was abandoned before being processed, in which case events that have
+	// not yet been published are dropped. Context carries no values.
+	Context context.Context
 }
 
 // PublisherConfig is a struct holding configuration information for the publisher,
@@ -60,10 +75,16 @@ type PublisherConfig struct {
 	Info            beat.Info
 	ShutdownTimeout time.Duration
 	Pipeline        string
+
+	// MaxInflightBytes holds the maximum approximate memory held by
+	// requests which have not yet been published, in bytes. If zero,
+	// the memory is not limited.
+	MaxInflightBytes int64
 }
 
 var (
 	ErrFull          = errors.New("queue is full")
+	ErrMemoryFull    = errors.New("memory limit for unpublished events exceeded")
 	ErrChannelClosed = errors.New("can't send batch, publisher is being stopped")
 )
 
@@ -97,8 +118,9 @@ func NewPublisher(pipeline beat.Pipeline, tracer *apm.Tracer, cfg *PublisherConf
 	}
 
 	p := &Publisher{
-		tracer: tracer,
-		client: client,
+		tracer:           tracer,
+		client:           client,
+		maxInflightBytes: cfg.MaxInflightBytes,
 
 		// One request will be actively processed by the
 		// worker, while the other concurrent requests will be buffered in the queue.
@@ -129,6 +151,7 @@ func (p *Publisher) Stop() {
 }
 
 // Send tries to forward pendingReq to the publishers worker. If the queue is full,
+// or the memory held by unpublished requests would exceed the configured limit,
 // an error is returned.
 // Calling send after Stop will return an error.
 func (p *Publisher) Send(ctx context.Context, req PendingReq) error {
@@ -141,25 +164,62 @@ func (p *Publisher) Send(ctx context.Context, req PendingReq) error {
 	if p.stopped {
 		return ErrChannelClosed
 	}
+	if err := ctx.Err(); err != nil {
+		return err
+	}
 
+	if !p.reserveMemory(req.Size) {
+		return ErrMemoryFull
+	}
 	select {
 	case <-ctx.Done():
+		p.releaseMemory(req.Size)
 		return ctx.Err()
 	case p.pendingRequests <- req:
 		return nil
 	case <-time.After(time.Second * 1): // this forces the go scheduler to try something else for a while
+		p.releaseMemory(req.Size)
 		return ErrFull
 	}
 }
 
+// reserveMemory reserves size bytes for an unpublished request, reporting
+// whether the reservation was within the limit. A request is always admitted
+// if there are no other unpublished requests, so that requests larger than
+// the limit are not rejected indefinitely.
+func (p *Publisher) reserveMemory(size int64) bool {
+	if p.maxInflightBytes <= 0 || size <= 0 {
+		return true
+	}
+	inflight := atomic.AddInt64(&p.inflightBytes, size)
+	if inflight > p.maxInflightBytes && inflight != size {
+		atomic.AddInt64(&p.inflightBytes, -size)
+		return false
+	}
+	return true
+}
+
+func (p *Publisher) releaseMemory(size int64) {
+	if p.maxInflightBytes > 0 && size > 0 {
+		atomic.AddInt64(&p.inflightBytes, -size)
+	}
+}
+
 func (p *Publisher) run() {
-	ctx := context.Background()
 	for req := range p.pendingRequests {
+		ctx := req.Context
+		if ctx == nil {
+			ctx = context.Background()
+		}
 		p.processPendingReq(ctx, req)
 	}
 }
 
 func (p *Publisher) processPendingReq(ctx context.Context, req PendingReq) {
+	defer p.releaseMemory(req.Size)
+	if ctx.Err() != nil {
+		return
+	}
 	var tx *apm.Transaction
 	if req.Trace {
 		tx = p.tracer.StartTransaction("ProcessPending", "Publisher")
@@ -167,12 +227,18 @@ func (p *Publisher) processPendingReq(ctx context.Context, req PendingReq) {
 		ctx = apm.ContextWithTransaction(ctx, tx)
 	}
 
+	// Events are published to the pipeline in a single batch per request,
+	// as publishing incurs channel and locking overhead per call.
+	events := make([]beat.Event, 0, len(req.Transformables))
 	for _, transformable := range req.Transformables {
-		events := transformTransformable(ctx, transformable, req.Tcontext)
-		span := tx.StartSpan("PublishAll", "Publisher", nil)
-		p.client.PublishAll(events)
-		span.End()
+		if ctx.Err() != nil {
+			return
+		}
+		events = append(events, transformTransformable(ctx, transformable, req.Tcontext)...)
 	}
+	span := tx.StartSpan("PublishAll", "Publisher", nil)
+	p.client.PublishAll(events)
+	span.End()
 }
 
 func transformTransformable(ctx context.Context, t transform.Transformable, tctx *transform.Context) []beat.Event {
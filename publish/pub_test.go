@@ -0,0 +1,136 @@
+// Licensed to Elasticsearch B.V. under one or more contributor
+// license agreements. See the NOTICE file distributed with
+// this work for additional information regarding copyright
+// ownership. Elasticsearch B.V. licenses this file to you under
+// the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package publish
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/elastic/beats/v7/libbeat/beat"
+	"github.com/elastic/beats/v7/libbeat/common"
+
+	"github.com/elastic/apm-server/transform"
+)
+
+func TestPublisherSendMemoryLimit(t *testing.T) {
+	p := &Publisher{
+		pendingRequests:  make(chan PendingReq, 10),
+		maxInflightBytes: 100,
+	}
+	req := func(size int64) PendingReq {
+		return PendingReq{Transformables: make([]transform.Transformable, 1), Size: size}
+	}
+
+	// A single request larger than the limit is admitted,
+	// so long as there are no other unpublished requests.
+	assert.NoError(t, p.Send(context.Background(), req(150)))
+	assert.Equal(t, ErrMemoryFull, p.Send(context.Background(), req(1)))
+	p.releaseMemory((<-p.pendingRequests).Size)
+
+	assert.NoError(t, p.Send(context.Background(), req(60)))
+	assert.NoError(t, p.Send(context.Background(), req(40)))
+	assert.Equal(t, ErrMemoryFull, p.Send(context.Background(), req(1)))
+	p.releaseMemory((<-p.pendingRequests).Size)
+	assert.NoError(t, p.Send(context.Background(), req(50)))
+
+	// Requests without a size are not limited.
+	assert.NoError(t, p.Send(context.Background(), req(0)))
+	assert.Equal(t, int64(90), p.inflightBytes)
+
+	// Memory reserved for requests which could not be sent is released.
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	p.pendingRequests = make(chan PendingReq)
+	assert.Equal(t, context.Canceled, p.Send(ctx, req(10)))
+	assert.Equal(t, int64(90), p.inflightBytes)
+}
+
+func TestPublisherSendContextDone(t *testing.T) {
+	p := &Publisher{pendingRequests: make(chan PendingReq, 1)}
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	// A request whose context is already done is not queued,
+	// even if there is room for it.
+	err := p.Send(ctx, PendingReq{Transformables: make([]transform.Transformable, 1), Size: 10})
+	assert.Equal(t, context.Canceled, err)
+	assert.Len(t, p.pendingRequests, 0)
+	assert.Equal(t, int64(0), p.inflightBytes)
+}
+
+func TestPublisherPublishesRequestInOneBatch(t *testing.T) {
+	var client batchRecordingClient
+	p := &Publisher{client: &client}
+	p.processPendingReq(context.Background(), PendingReq{
+		Transformables: []transform.Transformable{
+			transformableEvents{{Fields: common.MapStr{"a": 1}}},
+			transformableEvents{},
+			transformableEvents{{Fields: common.MapStr{"b": 2}}, {Fields: common.MapStr{"c": 3}}},
+		},
+		Tcontext: &transform.Context{},
+	})
+	assert.Equal(t, [][]beat.Event{{
+		{Fields: common.MapStr{"a": 1}},
+		{Fields: common.MapStr{"b": 2}},
+		{Fields: common.MapStr{"c": 3}},
+	}}, client.batches)
+}
+
+type transformableEvents []beat.Event
+
+func (events transformableEvents) Transform(context.Context, *transform.Context) []beat.Event {
+	return events
+}
+
+func TestPublisherDropsAbandonedRequest(t *testing.T) {
+	var client batchRecordingClient
+	p := &Publisher{client: &client, pendingRequests: make(chan PendingReq, 2)}
+	abandoned, abandon := context.WithCancel(context.Background())
+	abandon()
+	p.pendingRequests <- PendingReq{
+		Transformables: []transform.Transformable{transformableEvents{{Fields: common.MapStr{"a": 1}}}},
+		Tcontext:       &transform.Context{},
+		Context:        abandoned,
+	}
+	p.pendingRequests <- PendingReq{
+		Transformables: []transform.Transformable{transformableEvents{{Fields: common.MapStr{"b": 2}}}},
+		Tcontext:       &transform.Context{},
+		Context:        context.Background(),
+	}
+	close(p.pendingRequests)
+	p.run()
+	assert.Equal(t, [][]beat.Event{{{Fields: common.MapStr{"b": 2}}}}, client.batches)
+}
+
+type batchRecordingClient struct {
+	batches [][]beat.Event
+}
+
+func (c *batchRecordingClient) Publish(event beat.Event) {
+	c.PublishAll([]beat.Event{event})
+}
+
+func (c *batchRecordingClient) PublishAll(events []beat.Event) {
+	c.batches = append(c.batches, events)
+}
+
+func (c *batchRecordingClient) Close() error {
+	return nil
+}
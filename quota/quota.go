@@ -0,0 +1,265 @@
+// Licensed to Elasticsearch B.V. under one or more contributor
+// license agreements. See the NOTICE file distributed with
+// this work for additional information regarding copyright
+// ownership. Elasticsearch B.V. licenses this file to you under
+// the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package quota
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"hash/fnv"
+	"math/rand"
+	"sync"
+	"time"
+
+	"github.com/elastic/beats/v7/libbeat/logp"
+	"github.com/elastic/beats/v7/libbeat/monitoring"
+
+	logs "github.com/elastic/apm-server/log"
+	"github.com/elastic/apm-server/model"
+	"github.com/elastic/apm-server/publish"
+	"github.com/elastic/apm-server/transform"
+)
+
+var (
+	monitoringRegistry = monitoring.Default.NewRegistry("apm-server.quota")
+	exceededCounter    = monitoring.NewInt(monitoringRegistry, "exceeded")
+	rejectedCounter    = monitoring.NewInt(monitoringRegistry, "events_rejected")
+	sampledOutCounter  = monitoring.NewInt(monitoringRegistry, "events_sampled_out")
+)
+
+// ErrExceeded is matched by the errors returned by reporters when events
+// were rejected because their service exceeded its ingestion quota.
+var ErrExceeded = errors.New("service ingestion quota exceeded")
+
+// ExceededError is returned by reporters when events were rejected because
+// their service exceeded its ingestion quota. It matches ErrExceeded.
+type ExceededError struct {
+	// Accepted holds the number of events within quota, which were reported.
+	Accepted int
+
+	// Reset holds the time at which quota usage is reset.
+	Reset time.Time
+}
+
+func (e *ExceededError) Error() string {
+	return fmt.Sprintf("%s until %s", ErrExceeded, e.Reset.Format(time.RFC3339))
+}
+
+// Is reports whether target is ErrExceeded.
+func (e *ExceededError) Is(target error) bool {
+	return target == ErrExceeded
+}
+
+// Action defines what is done with events of a service exceeding its quota.
+type Action string
+
+const (
+	// ActionReject rejects events of services exceeding their quota.
+	ActionReject Action = "reject"
+
+	// ActionSample keeps a fraction of the events of services exceeding
+	// their quota, keeping or dropping whole traces.
+	ActionSample Action = "sample"
+)
+
+// Limits holds the daily ingestion limits for a service.
+// Zero values mean unlimited.
+type Limits struct {
+	EventsPerDay int64
+	BytesPerDay  int64
+}
+
+// Config holds configuration for NewEnforcer.
+type Config struct {
+	// Default holds the limits for services not listed in Services.
+	Default Limits
+
+	// Services holds limits by service name.
+	Services map[string]Limits
+
+	// Action defines how events over quota are handled.
+	Action Action
+
+	// SampleRate is the fraction of traces kept for services
+	// over their quota, when Action is ActionSample.
+	SampleRate float64
+}
+
+// Enforcer tracks the daily ingestion of services, enforcing their quotas.
+// Usage is tracked separately for services of the same name of different
+// tenants, so tenants cannot exhaust each other's quotas.
+//
+// Usage is tracked per UTC day. Bytes are accounted as the approximate
+// size of the reported events, as given by publish.PendingReq.Size.
+type Enforcer struct {
+	cfg    Config
+	logger *logp.Logger
+	now    func() time.Time
+
+	mu    sync.Mutex
+	day   time.Time
+	usage map[string]*usage // "<tenant>/<service>" -> usage
+}
+
+type usage struct {
+	events   int64
+	bytes    int64
+	exceeded bool
+}
+
+// NewEnforcer returns a new Enforcer with the given config.
+func NewEnforcer(cfg Config) *Enforcer {
+	return &Enforcer{
+		cfg:    cfg,
+		logger: logp.NewLogger(logs.Quota),
+		now:    time.Now,
+		usage:  make(map[string]*usage),
+	}
+}
+
+// Wrap returns a Reporter which reports to reporter the events of services
+// within their quota. Events of services over their quota are sampled or
+// rejected, depending on the configured action; an *ExceededError is returned
+// if any events were rejected, after reporting the remaining events.
+//
+// The returned publish.Reporter does not guarantee order preservation of
+// reported events.
+func (e *Enforcer) Wrap(reporter publish.Reporter) publish.Reporter {
+	return func(ctx context.Context, req publish.PendingReq) error {
+		events := req.Transformables
+		if len(events) == 0 {
+			return reporter(ctx, req)
+		}
+		eventSize := req.Size / int64(len(events))
+
+		var rejected, sampledOut int64
+		e.mu.Lock()
+		e.maybeReset()
+		reset := e.day.Add(24 * time.Hour)
+		for i := 0; i < len(events); {
+			if e.allow(events[i], eventSize) {
+				i++
+				continue
+			}
+			if e.cfg.Action == ActionReject {
+				rejected++
+			} else {
+				sampledOut++
+			}
+			n := len(events)
+			events[i], events[n-1] = events[n-1], events[i]
+			events = events[:n-1]
+		}
+		e.mu.Unlock()
+
+		rejectedCounter.Add(rejected)
+		sampledOutCounter.Add(sampledOut)
+		if len(events) > 0 {
+			req.Transformables = events
+			req.Size = eventSize * int64(len(events))
+			if err := reporter(ctx, req); err != nil {
+				return err
+			}
+		}
+		if rejected > 0 {
+			return &ExceededError{Accepted: len(events), Reset: reset}
+		}
+		return nil
+	}
+}
+
+// maybeReset resets usage when a new day has started.
+// The caller must hold e.mu.
+func (e *Enforcer) maybeReset() {
+	now := e.now().UTC()
+	day := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, time.UTC)
+	if !day.Equal(e.day) {
+		e.day = day
+		e.usage = make(map[string]*usage)
+	}
+}
+
+// allow accounts for event in the usage of its service, and reports whether
+// the event may be reported. The caller must hold e.mu.
+func (e *Enforcer) allow(event transform.Transformable, size int64) bool {
+	tenant, service, traceID := eventService(event)
+	limits, ok := e.cfg.Services[service]
+	if !ok {
+		limits = e.cfg.Default
+	}
+	key := tenant + "/" + service
+	u, ok := e.usage[key]
+	if !ok {
+		u = &usage{}
+		e.usage[key] = u
+	}
+	if !u.exceeded {
+		u.exceeded = (limits.EventsPerDay > 0 && u.events >= limits.EventsPerDay) ||
+			(limits.BytesPerDay > 0 && u.bytes >= limits.BytesPerDay)
+		if u.exceeded {
+			exceededCounter.Inc()
+			logger := e.logger
+			if tenant != "" {
+				logger = logger.With("tenant.id", tenant)
+			}
+			logger.Warnf("service %q exceeded its daily ingestion quota, %s events until %s",
+				service, e.actionVerb(), e.day.Add(24*time.Hour).Format(time.RFC3339))
+		}
+	}
+	if u.exceeded && (e.cfg.Action == ActionReject || !e.sampled(traceID)) {
+		return false
+	}
+	u.events++
+	u.bytes += size
+	return true
+}
+
+func (e *Enforcer) actionVerb() string {
+	if e.cfg.Action == ActionReject {
+		return "rejecting"
+	}
+	return "sampling"
+}
+
+// sampled reports whether the trace with the given ID is kept when sampling.
+// Events without a trace ID are sampled randomly.
+func (e *Enforcer) sampled(traceID string) bool {
+	if traceID == "" {
+		return rand.Float64() < e.cfg.SampleRate
+	}
+	h := fnv.New32a()
+	h.Write([]byte(traceID))
+	return float64(h.Sum32())/(1<<32) < e.cfg.SampleRate
+}
+
+// eventService returns the tenant ID, service name and trace ID of event.
+func eventService(event transform.Transformable) (tenant, service, traceID string) {
+	switch event := event.(type) {
+	case *model.Transaction:
+		return event.Metadata.TenantID, event.Metadata.Service.Name, event.TraceID
+	case *model.Span:
+		return event.Metadata.TenantID, event.Metadata.Service.Name, event.TraceID
+	case *model.Error:
+		return event.Metadata.TenantID, event.Metadata.Service.Name, event.TraceID
+	case *model.Metricset:
+		return event.Metadata.TenantID, event.Metadata.Service.Name, ""
+	case *model.PprofProfile:
+		return event.Metadata.TenantID, event.Metadata.Service.Name, ""
+	}
+	return "", "", ""
+}
@@ -0,0 +1,175 @@
+// Licensed to Elasticsearch B.V. under one or more contributor
+// license agreements. See the NOTICE file distributed with
+// this work for additional information regarding copyright
+// ownership. Elasticsearch B.V. licenses this file to you under
+// the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package quota
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/elastic/beats/v7/libbeat/monitoring"
+
+	"github.com/elastic/apm-server/model"
+	"github.com/elastic/apm-server/publish"
+	"github.com/elastic/apm-server/transform"
+)
+
+func TestEnforcerReject(t *testing.T) {
+	enforcer := NewEnforcer(Config{
+		Default:  Limits{EventsPerDay: 3},
+		Services: map[string]Limits{"unlimited": {}},
+		Action:   ActionReject,
+	})
+	var reported []transform.Transformable
+	reporter := enforcer.Wrap(func(ctx context.Context, req publish.PendingReq) error {
+		reported = append(reported, req.Transformables...)
+		return nil
+	})
+	before := snapshot()
+
+	report := func(events ...transform.Transformable) error {
+		return reporter(context.Background(), publish.PendingReq{Transformables: events})
+	}
+	assert.NoError(t, report(transaction("a", "1"), transaction("a", "2")))
+	assertExceeded(t, 2, report(transaction("a", "3"), transaction("a", "4"), transaction("b", "5")))
+	assertExceeded(t, 0, report(transaction("a", "6")))
+	for i := 0; i < 10; i++ {
+		assert.NoError(t, report(transaction("unlimited", "7")))
+	}
+	assert.Len(t, reported, 14)
+
+	after := snapshot()
+	assert.Equal(t, int64(1), after["exceeded"]-before["exceeded"])
+	assert.Equal(t, int64(2), after["events_rejected"]-before["events_rejected"])
+}
+
+func TestEnforcerTenants(t *testing.T) {
+	enforcer := NewEnforcer(Config{Default: Limits{EventsPerDay: 2}, Action: ActionReject})
+	reporter := enforcer.Wrap(func(ctx context.Context, req publish.PendingReq) error { return nil })
+	report := func(events ...transform.Transformable) error {
+		return reporter(context.Background(), publish.PendingReq{Transformables: events})
+	}
+	tenantTransaction := func(tenant, service, traceID string) *model.Transaction {
+		tx := transaction(service, traceID)
+		tx.Metadata.TenantID = tenant
+		return tx
+	}
+
+	// team-a exhausts the quota of its "frontend" service...
+	assert.NoError(t, report(tenantTransaction("team-a", "frontend", "1"), tenantTransaction("team-a", "frontend", "2")))
+	assertExceeded(t, 0, report(tenantTransaction("team-a", "frontend", "3")))
+
+	// ...which does not affect team-b's, or untenanted, "frontend" services.
+	assert.NoError(t, report(tenantTransaction("team-b", "frontend", "4"), tenantTransaction("team-b", "frontend", "5")))
+	assert.NoError(t, report(transaction("frontend", "6"), transaction("frontend", "7")))
+	assertExceeded(t, 0, report(tenantTransaction("team-b", "frontend", "8")))
+}
+
+func TestEnforcerBytes(t *testing.T) {
+	enforcer := NewEnforcer(Config{Default: Limits{BytesPerDay: 100}, Action: ActionReject})
+	var reported int
+	reporter := enforcer.Wrap(func(ctx context.Context, req publish.PendingReq) error {
+		reported += len(req.Transformables)
+		return nil
+	})
+	events := []transform.Transformable{transaction("a", "1"), transaction("a", "2")}
+	assert.NoError(t, reporter(context.Background(), publish.PendingReq{Transformables: events, Size: 120}))
+	assertExceeded(t, 0, reporter(context.Background(), publish.PendingReq{
+		Transformables: []transform.Transformable{transaction("a", "3")}, Size: 10,
+	}))
+	assert.Equal(t, 2, reported)
+}
+
+func TestEnforcerSample(t *testing.T) {
+	enforcer := NewEnforcer(Config{Default: Limits{EventsPerDay: 1}, Action: ActionSample, SampleRate: 0.5})
+	kept := make(map[string]int)
+	reporter := enforcer.Wrap(func(ctx context.Context, req publish.PendingReq) error {
+		for _, event := range req.Transformables {
+			kept[event.(*model.Transaction).TraceID]++
+		}
+		return nil
+	})
+
+	traceIDs := []string{"a", "b", "c", "d", "e", "f", "g", "h", "i", "j", "k", "l"}
+	for _, traceID := range traceIDs {
+		// all events of a trace are either kept or dropped
+		require.NoError(t, reporter(context.Background(), publish.PendingReq{
+			Transformables: []transform.Transformable{transaction("a", traceID), transaction("a", traceID)},
+		}))
+	}
+	assert.NotEmpty(t, kept)
+	assert.True(t, len(kept) < len(traceIDs))
+	for traceID, n := range kept {
+		if traceID != traceIDs[0] {
+			assert.Equal(t, 2, n, traceID)
+		}
+	}
+}
+
+func TestEnforcerResetsDaily(t *testing.T) {
+	now := time.Date(2020, 7, 1, 23, 59, 0, 0, time.UTC)
+	enforcer := NewEnforcer(Config{Default: Limits{EventsPerDay: 1}, Action: ActionReject})
+	enforcer.now = func() time.Time { return now }
+	reporter := enforcer.Wrap(func(ctx context.Context, req publish.PendingReq) error { return nil })
+	report := func() error {
+		return reporter(context.Background(), publish.PendingReq{
+			Transformables: []transform.Transformable{transaction("a", "1")},
+		})
+	}
+
+	assert.NoError(t, report())
+	err := report()
+	assertExceeded(t, 0, err)
+	assert.Equal(t, time.Date(2020, 7, 2, 0, 0, 0, 0, time.UTC), err.(*ExceededError).Reset)
+	assert.EqualError(t, err, "service ingestion quota exceeded until 2020-07-02T00:00:00Z")
+	now = now.Add(time.Minute)
+	assert.NoError(t, report())
+}
+
+func assertExceeded(t *testing.T, accepted int, err error) {
+	t.Helper()
+	assert.True(t, errors.Is(err, ErrExceeded))
+	var exceeded *ExceededError
+	if assert.True(t, errors.As(err, &exceeded)) {
+		assert.Equal(t, accepted, exceeded.Accepted)
+	}
+}
+
+func TestEnforcerReporterError(t *testing.T) {
+	enforcer := NewEnforcer(Config{Default: Limits{EventsPerDay: 1}, Action: ActionReject})
+	reporter := enforcer.Wrap(func(ctx context.Context, req publish.PendingReq) error { return publish.ErrFull })
+	err := reporter(context.Background(), publish.PendingReq{
+		Transformables: []transform.Transformable{transaction("a", "1"), transaction("a", "2")},
+	})
+	assert.Equal(t, publish.ErrFull, err)
+}
+
+func transaction(service, traceID string) *model.Transaction {
+	return &model.Transaction{
+		Metadata: model.Metadata{Service: model.Service{Name: service}},
+		TraceID:  traceID,
+	}
+}
+
+func snapshot() map[string]int64 {
+	return monitoring.CollectFlatSnapshot(monitoringRegistry, monitoring.Full, false).Ints
+}
@@ -0,0 +1,156 @@
+// Licensed to Elasticsearch B.V. under one or more contributor
+// license agreements. See the NOTICE file distributed with
+// this work for additional information regarding copyright
+// ownership. Elasticsearch B.V. licenses this file to you under
+// the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package resources
+
+import (
+	"bufio"
+	"io/ioutil"
+	"math"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strconv"
+	"strings"
+)
+
+// unlimitedMemory is the threshold from which cgroup v1 memory
+// limits are treated as unlimited; the kernel reports an unlimited
+// limit as the largest page aligned int64 value.
+const unlimitedMemory = 1 << 62
+
+// Limits holds the CPU and memory resources available to the process,
+// taking into account cgroup limits when running in a container.
+type Limits struct {
+	// CPUs holds the number of CPUs available to the process,
+	// rounded up when the cgroup CPU quota is fractional.
+	CPUs int
+
+	// Memory holds the memory limit of the process in bytes,
+	// or zero if the memory is not limited.
+	Memory int64
+}
+
+// Detect returns the resource limits of the current process.
+func Detect() Limits {
+	return detect("/", runtime.NumCPU())
+}
+
+// detect returns the resource limits of the current process, reading
+// cgroup v1 and v2 control files from the filesystem mounted at root.
+// The number of CPUs is never greater than numCPU.
+func detect(root string, numCPU int) Limits {
+	limits := Limits{CPUs: numCPU}
+	paths := cgroupPaths(root)
+	if cpus, ok := cpuLimit(root, paths); ok && cpus < numCPU {
+		limits.CPUs = cpus
+	}
+	if memory, ok := memoryLimit(root, paths); ok {
+		limits.Memory = memory
+	}
+	return limits
+}
+
+// cgroupPaths returns the cgroup paths of the current process by controller,
+// with the cgroup v2 unified hierarchy path stored under the empty key.
+func cgroupPaths(root string) map[string]string {
+	paths := make(map[string]string)
+	f, err := os.Open(filepath.Join(root, "proc", "self", "cgroup"))
+	if err != nil {
+		return paths
+	}
+	defer f.Close()
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		// Lines are formatted as hierarchy-ID:controller-list:cgroup-path.
+		fields := strings.SplitN(scanner.Text(), ":", 3)
+		if len(fields) != 3 {
+			continue
+		}
+		for _, controller := range strings.Split(fields[1], ",") {
+			paths[controller] = fields[2]
+		}
+	}
+	return paths
+}
+
+// cpuLimit returns the number of CPUs allowed by the cgroup CPU quota,
+// and false if the CPU usage is not limited.
+func cpuLimit(root string, paths map[string]string) (int, bool) {
+	var quota, period float64
+	if content, ok := readControlFile(root, "", paths, "cpu.max"); ok {
+		// cgroup v2: "$MAX $PERIOD", where $MAX may be "max".
+		fields := strings.Fields(content)
+		if len(fields) != 2 || fields[0] == "max" {
+			return 0, false
+		}
+		quota, _ = strconv.ParseFloat(fields[0], 64)
+		period, _ = strconv.ParseFloat(fields[1], 64)
+	} else {
+		content, ok := readControlFile(root, "cpu", paths, "cpu.cfs_quota_us")
+		if !ok {
+			return 0, false
+		}
+		quota, _ = strconv.ParseFloat(content, 64)
+		if content, ok = readControlFile(root, "cpu", paths, "cpu.cfs_period_us"); !ok {
+			return 0, false
+		}
+		period, _ = strconv.ParseFloat(content, 64)
+	}
+	if quota <= 0 || period <= 0 {
+		return 0, false
+	}
+	return int(math.Ceil(quota / period)), true
+}
+
+// memoryLimit returns the cgroup memory limit in bytes,
+// and false if the memory is not limited.
+func memoryLimit(root string, paths map[string]string) (int64, bool) {
+	content, ok := readControlFile(root, "", paths, "memory.max")
+	if !ok {
+		if content, ok = readControlFile(root, "memory", paths, "memory.limit_in_bytes"); !ok {
+			return 0, false
+		}
+	}
+	if content == "max" {
+		return 0, false
+	}
+	limit, err := strconv.ParseInt(content, 10, 64)
+	if err != nil || limit <= 0 || limit >= unlimitedMemory {
+		return 0, false
+	}
+	return limit, true
+}
+
+// readControlFile returns the trimmed content of the named control file of
+// the given cgroup controller, with the empty controller denoting cgroup v2.
+// The file is looked up in the process' cgroup, falling back to the root of
+// the hierarchy, which is the process' cgroup when running in a cgroup namespace.
+func readControlFile(root, controller string, paths map[string]string, name string) (string, bool) {
+	path, ok := paths[controller]
+	if !ok {
+		return "", false
+	}
+	dir := filepath.Join(root, "sys", "fs", "cgroup", controller)
+	for _, dir := range []string{filepath.Join(dir, path), dir} {
+		content, err := ioutil.ReadFile(filepath.Join(dir, name))
+		if err == nil {
+			return strings.TrimSpace(string(content)), true
+		}
+	}
+	return "", false
+}
@@ -0,0 +1,105 @@
+// Licensed to Elasticsearch B.V. under one or more contributor
+// license agreements. See the NOTICE file distributed with
+// this work for additional information regarding copyright
+// ownership. Elasticsearch B.V. licenses this file to you under
+// the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package resources
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func writeFiles(t *testing.T, files map[string]string) string {
+	root, err := ioutil.TempDir("", "resources")
+	require.NoError(t, err)
+	for name, content := range files {
+		path := filepath.Join(root, name)
+		require.NoError(t, os.MkdirAll(filepath.Dir(path), 0755))
+		require.NoError(t, ioutil.WriteFile(path, []byte(content), 0644))
+	}
+	return root
+}
+
+func TestDetect(t *testing.T) {
+	for name, tc := range map[string]struct {
+		files  map[string]string
+		limits Limits
+	}{
+		"NoCgroups": {
+			limits: Limits{CPUs: 8},
+		},
+		"CgroupV2": {
+			files: map[string]string{
+				"proc/self/cgroup":                 "0::/apm\n",
+				"sys/fs/cgroup/apm/cpu.max":        "150000 100000\n",
+				"sys/fs/cgroup/apm/memory.max":     "536870912\n",
+				"sys/fs/cgroup/other/memory.max":   "1024\n",
+				"sys/fs/cgroup/cpu.max":            "max 100000\n",
+				"sys/fs/cgroup/memory.max":         "max\n",
+				"sys/fs/cgroup/cgroup.controllers": "cpu memory\n",
+			},
+			limits: Limits{CPUs: 2, Memory: 512 << 20},
+		},
+		"CgroupV2Namespace": {
+			files: map[string]string{
+				"proc/self/cgroup":         "0::/\n",
+				"sys/fs/cgroup/cpu.max":    "400000 100000\n",
+				"sys/fs/cgroup/memory.max": "max\n",
+			},
+			limits: Limits{CPUs: 4},
+		},
+		"CgroupV1": {
+			files: map[string]string{
+				"proc/self/cgroup": "" +
+					"9:memory:/docker/abc\n" +
+					"4:cpu,cpuacct:/docker/abc\n",
+				"sys/fs/cgroup/cpu/docker/abc/cpu.cfs_quota_us":         "50000\n",
+				"sys/fs/cgroup/cpu/docker/abc/cpu.cfs_period_us":        "100000\n",
+				"sys/fs/cgroup/memory/docker/abc/memory.limit_in_bytes": "1073741824\n",
+			},
+			limits: Limits{CPUs: 1, Memory: 1 << 30},
+		},
+		"CgroupV1Unlimited": {
+			files: map[string]string{
+				"proc/self/cgroup": "" +
+					"9:memory:/\n" +
+					"4:cpu,cpuacct:/\n",
+				"sys/fs/cgroup/cpu/cpu.cfs_quota_us":         "-1\n",
+				"sys/fs/cgroup/cpu/cpu.cfs_period_us":        "100000\n",
+				"sys/fs/cgroup/memory/memory.limit_in_bytes": "9223372036854771712\n",
+			},
+			limits: Limits{CPUs: 8},
+		},
+		"QuotaAboveNumCPU": {
+			files: map[string]string{
+				"proc/self/cgroup":      "0::/\n",
+				"sys/fs/cgroup/cpu.max": "1600000 100000\n",
+			},
+			limits: Limits{CPUs: 8},
+		},
+	} {
+		t.Run(name, func(t *testing.T) {
+			root := writeFiles(t, tc.files)
+			defer os.RemoveAll(root)
+			assert.Equal(t, tc.limits, detect(root, 8))
+		})
+	}
+}
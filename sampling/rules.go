@@ -0,0 +1,192 @@
+// Licensed to Elasticsearch B.V. under one or more contributor
+// license agreements. See the NOTICE file distributed with
+// this work for additional information regarding copyright
+// ownership. Elasticsearch B.V. licenses this file to you under
+// the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package sampling
+
+import (
+	"context"
+	"hash/fnv"
+	"math"
+	"sync"
+	"time"
+
+	"github.com/hashicorp/golang-lru/simplelru"
+
+	"github.com/elastic/apm-server/model"
+	"github.com/elastic/apm-server/publish"
+	"github.com/elastic/apm-server/transform"
+	"github.com/elastic/beats/v7/libbeat/monitoring"
+)
+
+var (
+	transactionsUnsampledCounter = monitoring.NewInt(monitoringRegistry, "transactions_unsampled")
+	spansUnsampledCounter        = monitoring.NewInt(monitoringRegistry, "spans_unsampled")
+)
+
+const (
+	// unsampledTransactionsTTL is how long the IDs of transactions
+	// unsampled by the server are remembered, for dropping their spans
+	// received in later requests.
+	unsampledTransactionsTTL = time.Minute
+
+	// maxUnsampledTransactions bounds the number of transaction IDs
+	// remembered; the least recently unsampled are forgotten first.
+	maxUnsampledTransactions = 10000
+)
+
+// TransactionRateFunc returns the sampling rate the server should apply to
+// a transaction, and false if the agent's sampling decision should stand.
+type TransactionRateFunc func(*model.Transaction) (float64, bool)
+
+// NewTransactionSamplingReporter returns a publish.Reporter which marks
+// sampled transactions as unsampled according to the rates returned by
+// rate, and drops the spans of those transactions, before deferring to
+// reporter.
+//
+// Sampling decisions are made by hashing the trace ID, so all transactions
+// of a trace sampled at the same rate share the same decision.
+//
+// Unsampled transactions are kept, as agents send their unsampled
+// transactions, so they may still be counted in aggregations; whether they
+// are indexed is decided by sampling.keep_unsampled, independently of the
+// rules. Their spans are dropped, as agents do not send the spans of
+// unsampled transactions. The IDs of unsampled transactions are remembered
+// for a minute, so their spans received in the same request or in later
+// ones are dropped. Spans received in earlier requests, which agents send
+// when the spans end before their transaction, have already been reported.
+func NewTransactionSamplingReporter(rate TransactionRateFunc, reporter publish.Reporter) publish.Reporter {
+	unsampledIDs := newUnsampledTransactions(maxUnsampledTransactions, unsampledTransactionsTTL)
+	return func(ctx context.Context, req publish.PendingReq) error {
+		now := time.Now()
+		var unsampled int64
+		for _, event := range req.Transformables {
+			tx, ok := event.(*model.Transaction)
+			if !ok || (tx.Sampled != nil && !*tx.Sampled) {
+				continue
+			}
+			r, ok := rate(tx)
+			if !ok || sampleTrace(tx.TraceID, r) {
+				continue
+			}
+			sampled := false
+			tx.Sampled = &sampled
+			unsampled++
+			if tx.ID != "" {
+				unsampledIDs.add(tx.ID, now)
+			}
+		}
+		if unsampled > 0 {
+			transactionsUnsampledCounter.Add(unsampled)
+		}
+		if events, dropped := unsampledIDs.dropSpans(req.Transformables, now); dropped > 0 {
+			spansUnsampledCounter.Add(dropped)
+			req.Transformables = events
+		}
+		return reporter(ctx, req)
+	}
+}
+
+// unsampledTransactions holds the IDs of transactions unsampled by the
+// server, until they expire or are evicted by newer ones.
+type unsampledTransactions struct {
+	ttl time.Duration
+
+	mu  sync.Mutex
+	lru *simplelru.LRU // transaction ID -> expiry time.Time
+}
+
+func newUnsampledTransactions(size int, ttl time.Duration) *unsampledTransactions {
+	lru, err := simplelru.NewLRU(size, nil)
+	if err != nil {
+		panic(err)
+	}
+	return &unsampledTransactions{ttl: ttl, lru: lru}
+}
+
+func (u *unsampledTransactions) add(id string, now time.Time) {
+	u.mu.Lock()
+	defer u.mu.Unlock()
+	u.lru.Add(id, now.Add(u.ttl))
+}
+
+// dropSpans returns events without the spans of unsampled transactions,
+// and the number of spans dropped. If no spans are dropped, events is
+// returned as is.
+func (u *unsampledTransactions) dropSpans(events []transform.Transformable, now time.Time) ([]transform.Transformable, int64) {
+	u.mu.Lock()
+	defer u.mu.Unlock()
+	u.removeExpiredLocked(now)
+	if u.lru.Len() == 0 {
+		return events, 0
+	}
+	var kept []transform.Transformable
+	var dropped int64
+	for i, event := range events {
+		if span, ok := event.(*model.Span); ok && u.lru.Contains(span.TransactionID) {
+			if kept == nil {
+				kept = make([]transform.Transformable, i, len(events)-1)
+				copy(kept, events[:i])
+			}
+			dropped++
+			continue
+		}
+		if kept != nil {
+			kept = append(kept, event)
+		}
+	}
+	if dropped == 0 {
+		return events, 0
+	}
+	return kept, dropped
+}
+
+// removeExpiredLocked forgets the expired transaction IDs. IDs are added
+// with the same TTL, so the oldest expire first.
+func (u *unsampledTransactions) removeExpiredLocked(now time.Time) {
+	for {
+		_, expiry, ok := u.lru.GetOldest()
+		if !ok || now.Before(expiry.(time.Time)) {
+			return
+		}
+		u.lru.RemoveOldest()
+	}
+}
+
+// sampleTrace reports whether the trace with the given ID should
+// be sampled at the given rate.
+func sampleTrace(traceID string, rate float64) bool {
+	if rate >= 1 {
+		return true
+	}
+	if rate <= 0 {
+		return false
+	}
+	h := fnv.New64a()
+	h.Write([]byte(traceID))
+	return float64(mix64(h.Sum64())) < rate*math.MaxUint64
+}
+
+// mix64 is the MurmurHash3 finalizer, which spreads the small differences
+// FNV produces for trace IDs differing only in their last characters.
+func mix64(h uint64) uint64 {
+	h ^= h >> 33
+	h *= 0xff51afd7ed558ccd
+	h ^= h >> 33
+	h *= 0xc4ceb9fe1a85ec53
+	h ^= h >> 33
+	return h
+}
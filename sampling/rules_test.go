@@ -0,0 +1,136 @@
+// Licensed to Elasticsearch B.V. under one or more contributor
+// license agreements. See the NOTICE file distributed with
+// this work for additional information regarding copyright
+// ownership. Elasticsearch B.V. licenses this file to you under
+// the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package sampling_test
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/elastic/apm-server/model"
+	"github.com/elastic/apm-server/publish"
+	"github.com/elastic/apm-server/sampling"
+	"github.com/elastic/apm-server/transform"
+)
+
+func TestNewTransactionSamplingReporter(t *testing.T) {
+	var reported []transform.Transformable
+	rate := func(tx *model.Transaction) (float64, bool) {
+		switch tx.Type {
+		case "request":
+			return 1, true
+		case "messaging":
+			return 0, true
+		case "scheduled":
+			return 0.5, true
+		}
+		return 0, false
+	}
+	reporter := sampling.NewTransactionSamplingReporter(rate,
+		func(ctx context.Context, req publish.PendingReq) error {
+			reported = req.Transformables
+			return nil
+		},
+	)
+
+	request := &model.Transaction{Type: "request", TraceID: "a"}
+	messaging := &model.Transaction{Type: "messaging", TraceID: "b", Sampled: newBool(true)}
+	other := &model.Transaction{Type: "other", TraceID: "c"}
+	span := &model.Span{}
+	reporter(context.Background(), publish.PendingReq{
+		Transformables: []transform.Transformable{request, messaging, other, span},
+	})
+	require.Len(t, reported, 4)
+	assert.Nil(t, request.Sampled)
+	assert.Equal(t, newBool(false), messaging.Sampled)
+	assert.Nil(t, other.Sampled)
+
+	// Transactions sharing a trace ID share the sampling decision,
+	// and roughly the configured fraction of traces is sampled.
+	var sampled int
+	for i := 0; i < 1000; i++ {
+		traceID := fmt.Sprintf("%032x", i)
+		tx1 := &model.Transaction{Type: "scheduled", TraceID: traceID}
+		tx2 := &model.Transaction{Type: "scheduled", TraceID: traceID}
+		reporter(context.Background(), publish.PendingReq{
+			Transformables: []transform.Transformable{tx1, tx2},
+		})
+		assert.Equal(t, tx1.Sampled, tx2.Sampled)
+		if tx1.Sampled == nil {
+			sampled++
+		}
+	}
+	assert.InDelta(t, 500, sampled, 100)
+}
+
+func TestNewTransactionSamplingReporterDropsSpans(t *testing.T) {
+	var reported []transform.Transformable
+	rate := func(tx *model.Transaction) (float64, bool) {
+		return 0, tx.Type == "messaging"
+	}
+	reporter := sampling.NewTransactionSamplingReporter(rate,
+		func(ctx context.Context, req publish.PendingReq) error {
+			reported = req.Transformables
+			return nil
+		},
+	)
+
+	// The spans of transactions unsampled by the server are dropped,
+	// while the transactions themselves are kept.
+	unsampled := &model.Transaction{Type: "messaging", TraceID: "a", ID: "tx1"}
+	sampled := &model.Transaction{Type: "request", TraceID: "b", ID: "tx2"}
+	unsampledSpan := &model.Span{TraceID: "a", TransactionID: "tx1"}
+	sampledSpan := &model.Span{TraceID: "b", TransactionID: "tx2"}
+	reporter(context.Background(), publish.PendingReq{
+		Transformables: []transform.Transformable{unsampledSpan, sampledSpan, unsampled, sampled},
+	})
+	assert.Equal(t, []transform.Transformable{sampledSpan, unsampled, sampled}, reported)
+	assert.Equal(t, newBool(false), unsampled.Sampled)
+}
+
+func TestNewTransactionSamplingReporterDropsLaterSpans(t *testing.T) {
+	var reported []transform.Transformable
+	rate := func(tx *model.Transaction) (float64, bool) {
+		return 0, tx.Type == "messaging"
+	}
+	reporter := sampling.NewTransactionSamplingReporter(rate,
+		func(ctx context.Context, req publish.PendingReq) error {
+			reported = req.Transformables
+			return nil
+		},
+	)
+
+	unsampled := &model.Transaction{Type: "messaging", TraceID: "a", ID: "tx1"}
+	reporter(context.Background(), publish.PendingReq{
+		Transformables: []transform.Transformable{unsampled},
+	})
+	assert.Equal(t, []transform.Transformable{unsampled}, reported)
+
+	// Spans of the unsampled transaction received in later
+	// requests are dropped too, such as spans ending after
+	// their transaction.
+	unsampledSpan := &model.Span{TraceID: "a", TransactionID: "tx1"}
+	otherSpan := &model.Span{TraceID: "b", TransactionID: "tx2"}
+	reporter(context.Background(), publish.PendingReq{
+		Transformables: []transform.Transformable{otherSpan, unsampledSpan},
+	})
+	assert.Equal(t, []transform.Transformable{otherSpan}, reported)
+}
@@ -56,15 +56,12 @@ func TestNewDiscardUnsampledReporter(t *testing.T) {
 	assert.Equal(t, span, reported[1])
 	assert.Equal(t, t3, reported[2])
 
-	expectedMonitoring := monitoring.MakeFlatSnapshot()
-	expectedMonitoring.Ints["transactions_dropped"] = 1
-
 	snapshot := monitoring.CollectFlatSnapshot(
 		monitoring.GetRegistry("apm-server.sampling"),
 		monitoring.Full,
 		false, // expvar
 	)
-	assert.Equal(t, expectedMonitoring, snapshot)
+	assert.Equal(t, int64(1), snapshot.Ints["transactions_dropped"])
 }
 
 func newBool(v bool) *bool {
@@ -0,0 +1,535 @@
+// Licensed to Elasticsearch B.V. under one or more contributor
+// license agreements. See the NOTICE file distributed with
+// this work for additional information regarding copyright
+// ownership. Elasticsearch B.V. licenses this file to you under
+// the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package sampling
+
+import (
+	"context"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/hashicorp/golang-lru/simplelru"
+	"github.com/ryanuber/go-glob"
+
+	"github.com/elastic/beats/v7/libbeat/logp"
+	"github.com/elastic/beats/v7/libbeat/monitoring"
+
+	logs "github.com/elastic/apm-server/log"
+	"github.com/elastic/apm-server/model"
+	"github.com/elastic/apm-server/publish"
+	"github.com/elastic/apm-server/transform"
+)
+
+const (
+	// decisionTTL is how long sampling decisions are remembered, for
+	// events of a trace received after its root transaction.
+	decisionTTL = time.Minute
+
+	// policyLabel is the label recording the policy which kept a
+	// trace, set on its root transaction when annotating traces.
+	policyLabel = "tail_sampling_policy"
+)
+
+var (
+	// monitoredTailSampler holds the running TailSampler, whose
+	// metrics are reported under apm-server.sampling.tail.
+	monitoredTailSamplerMu sync.Mutex
+	monitoredTailSampler   *TailSampler
+)
+
+func init() {
+	monitoring.NewFunc(monitoringRegistry, "tail", collectTailSamplerMonitoring, monitoring.Report)
+}
+
+// TailSamplerConfig holds configuration for a TailSampler.
+type TailSamplerConfig struct {
+	// Policies holds the policies deciding which traces to keep.
+	// The first policy matching a trace applies; traces matching
+	// no policy are kept.
+	Policies []TailSamplingPolicy
+
+	// DecisionWait is how long the events of a trace are held
+	// waiting for its root transaction, after which the trace is
+	// decided without it.
+	DecisionWait time.Duration
+
+	// MaxTraces is the maximum number of traces held waiting for
+	// their root transaction. Events of further traces are kept
+	// without being sampled.
+	//
+	// MaxTraces also bounds the number of sampling decisions
+	// remembered: the least recently decided traces are forgotten
+	// before their decision expires once it is reached.
+	MaxTraces int
+
+	// Annotate controls whether the root transactions of traces
+	// kept by a policy are labeled with the policy's name.
+	Annotate bool
+}
+
+// TailSamplingPolicy keeps a fraction of the traces matching its criteria.
+// Empty criteria match any trace.
+type TailSamplingPolicy struct {
+	// Name identifies the policy in metrics and annotations.
+	// If empty, the policy's index is used.
+	Name string
+
+	// ServiceName, ServiceEnvironment, TransactionType, and
+	// TransactionName hold glob patterns matched against the
+	// trace's root transaction.
+	ServiceName        string
+	ServiceEnvironment string
+	TransactionType    string
+	TransactionName    string
+
+	// MinDuration, if positive, matches traces whose root
+	// transaction took at least as long.
+	MinDuration time.Duration
+
+	// HasError, if true, matches traces containing an error.
+	HasError bool
+
+	// SampleRate is the fraction of matching traces to keep.
+	SampleRate float64
+}
+
+// TailSampler holds the events of traces until their root transaction is
+// received, and then decides whether to keep the whole trace according to
+// its policies.
+type TailSampler struct {
+	config   TailSamplerConfig
+	reporter publish.Reporter
+	logger   *logp.Logger
+
+	mu      sync.Mutex
+	traces  map[string]*pendingTrace
+	decided *simplelru.LRU // trace ID -> decision
+	stats   tailSamplerStats
+}
+
+// tailSamplerStats holds counters of the sampler's decisions.
+type tailSamplerStats struct {
+	// policies holds the counters for each policy, by index.
+	policies []policyStats
+
+	// unmatched holds the number of traces matching no
+	// policy, which are kept.
+	unmatched int64
+
+	// overflowed holds the number of events kept without
+	// being sampled, as MaxTraces traces were pending.
+	overflowed int64
+
+	// flushFailed holds the number of events of kept traces which
+	// could not be reported once their trace was decided, e.g. as
+	// the publisher's queue was full. These events are lost.
+	flushFailed int64
+
+	spanCount spanCountStats
+}
+
+// spanCountStats holds the results of checking the span_count.started of
+// transactions against the spans received for them while their trace was
+// pending, helping to detect agents losing spans.
+type spanCountStats struct {
+	// checked holds the number of transactions checked.
+	checked int64
+
+	// inconsistent holds the number of transactions for which
+	// fewer spans were received than reported as started.
+	inconsistent int64
+
+	// missing holds the total number of spans reported as
+	// started by transactions, but not received.
+	missing int64
+}
+
+// policyStats holds the number of traces matching a policy, and
+// how many of them were kept or dropped.
+type policyStats struct {
+	matched, kept, dropped int64
+}
+
+type pendingTrace struct {
+	received     time.Time
+	events       []pendingEvent
+	root         *model.Transaction
+	hasError     bool
+	transactions []*model.Transaction
+
+	// spans holds the number of spans received, by transaction ID.
+	spans map[string]int
+}
+
+type pendingEvent struct {
+	tcontext *transform.Context
+	event    transform.Transformable
+}
+
+type decision struct {
+	keep    bool
+	expires time.Time
+}
+
+// NewTailSampler returns a new TailSampler with the given config.
+func NewTailSampler(config TailSamplerConfig) *TailSampler {
+	decided, err := simplelru.NewLRU(config.MaxTraces, nil)
+	if err != nil {
+		// config.MaxTraces is not positive
+		panic(err)
+	}
+	s := &TailSampler{
+		config:  config,
+		logger:  logp.NewLogger(logs.Sampling),
+		traces:  make(map[string]*pendingTrace),
+		decided: decided,
+	}
+	s.setPoliciesLocked(config.Policies)
+	return s
+}
+
+// SetPolicies replaces the sampler's policies, resetting the policy
+// metrics. Pending traces are decided by the new policies.
+func (s *TailSampler) SetPolicies(policies []TailSamplingPolicy) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.setPoliciesLocked(policies)
+}
+
+func (s *TailSampler) setPoliciesLocked(policies []TailSamplingPolicy) {
+	policies = append([]TailSamplingPolicy(nil), policies...)
+	for i := range policies {
+		if policies[i].Name == "" {
+			policies[i].Name = strconv.Itoa(i)
+		}
+	}
+	s.config.Policies = policies
+	s.stats.policies = make([]policyStats, len(policies))
+}
+
+// Wrap wraps reporter such that events of traces are held until the
+// traces are decided, and reported only if they are kept. Events not
+// belonging to a trace are reported immediately.
+//
+// Wrap must be called once, before Run.
+func (s *TailSampler) Wrap(reporter publish.Reporter) publish.Reporter {
+	s.reporter = reporter
+	return func(ctx context.Context, req publish.PendingReq) error {
+		var kept []transform.Transformable
+		var flush []pendingEvent
+		now := time.Now()
+
+		// All events of the request are added to their traces before any
+		// trace is decided, as requests are not ordered by trace: batches
+		// hold transactions before spans and errors, so deciding a trace on
+		// receiving its root would miss the rest of the request's events.
+		var pending []string
+		added := make(map[string]bool)
+		s.mu.Lock()
+		for _, event := range req.Transformables {
+			traceID := eventTraceID(event)
+			if traceID == "" {
+				kept = append(kept, event)
+				continue
+			}
+			if d, ok := s.decided.Peek(traceID); ok {
+				if d.(decision).keep {
+					kept = append(kept, event)
+				}
+				continue
+			}
+			t, ok := s.traces[traceID]
+			if !ok {
+				if len(s.traces) >= s.config.MaxTraces {
+					s.stats.overflowed++
+					kept = append(kept, event)
+					continue
+				}
+				t = &pendingTrace{received: now}
+				s.traces[traceID] = t
+			}
+			if !added[traceID] {
+				added[traceID] = true
+				pending = append(pending, traceID)
+			}
+			t.add(req.Tcontext, event)
+		}
+		for _, traceID := range pending {
+			t := s.traces[traceID]
+			if t.root != nil && s.decideLocked(traceID, t, now) {
+				flush = append(flush, t.events...)
+			}
+		}
+		s.mu.Unlock()
+
+		s.flush(ctx, flush)
+		req.Transformables = kept
+		return reporter(ctx, req)
+	}
+}
+
+// Run periodically decides the traces whose root transaction has not been
+// received within the configured decision wait, until ctx is cancelled.
+// Traces still pending when ctx is cancelled are then decided.
+//
+// While running, the sampler's metrics are reported under
+// apm-server.sampling.tail.
+func (s *TailSampler) Run(ctx context.Context) error {
+	monitoredTailSamplerMu.Lock()
+	monitoredTailSampler = s
+	monitoredTailSamplerMu.Unlock()
+	defer func() {
+		monitoredTailSamplerMu.Lock()
+		if monitoredTailSampler == s {
+			monitoredTailSampler = nil
+		}
+		monitoredTailSamplerMu.Unlock()
+	}()
+
+	ticker := time.NewTicker(s.config.DecisionWait)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			// Use a fresh context, so the remaining
+			// events are reported during shutdown.
+			s.flush(context.Background(), s.decideExpired(time.Now(), true))
+			return nil
+		case now := <-ticker.C:
+			s.flush(ctx, s.decideExpired(now, false))
+		}
+	}
+}
+
+// decideExpired decides the traces held for longer than the decision
+// wait, or all pending traces if all is true, returning the events of
+// traces that are kept. Expired decisions are forgotten.
+func (s *TailSampler) decideExpired(now time.Time, all bool) []pendingEvent {
+	var flush []pendingEvent
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for traceID, t := range s.traces {
+		if all || now.Sub(t.received) >= s.config.DecisionWait {
+			if s.decideLocked(traceID, t, now) {
+				flush = append(flush, t.events...)
+			}
+		}
+	}
+	// Decisions are never updated, so the least recently
+	// decided traces are the first to expire.
+	for {
+		_, d, ok := s.decided.GetOldest()
+		if !ok || !now.After(d.(decision).expires) {
+			break
+		}
+		s.decided.RemoveOldest()
+	}
+	return flush
+}
+
+// decideLocked decides whether to keep the trace, recording the decision
+// for events received later. s.mu must be held.
+func (s *TailSampler) decideLocked(traceID string, t *pendingTrace, now time.Time) bool {
+	keep, matched := true, false
+	for i, p := range s.config.Policies {
+		if !p.matches(t) {
+			continue
+		}
+		matched = true
+		keep = sampleTrace(traceID, p.SampleRate)
+		stats := &s.stats.policies[i]
+		stats.matched++
+		if !keep {
+			stats.dropped++
+			break
+		}
+		stats.kept++
+		if s.config.Annotate && t.root != nil {
+			if t.root.Labels == nil {
+				t.root.Labels = &model.Labels{}
+			}
+			(*t.root.Labels)[policyLabel] = p.Name
+		}
+		break
+	}
+	if !matched {
+		s.stats.unmatched++
+	}
+	s.checkSpanCountsLocked(t)
+	delete(s.traces, traceID)
+	s.decided.Add(traceID, decision{keep: keep, expires: now.Add(decisionTTL)})
+	return keep
+}
+
+// checkSpanCountsLocked checks the span_count.started of the trace's
+// transactions against the spans received for them. Spans received after
+// the trace has been decided are not taken into account. s.mu must be held.
+func (s *TailSampler) checkSpanCountsLocked(t *pendingTrace) {
+	for _, tx := range t.transactions {
+		if tx.SpanCount.Started == nil {
+			continue
+		}
+		s.stats.spanCount.checked++
+		if missing := *tx.SpanCount.Started - t.spans[tx.ID]; missing > 0 {
+			s.stats.spanCount.inconsistent++
+			s.stats.spanCount.missing += int64(missing)
+		}
+	}
+}
+
+// flush reports events of kept traces, grouped by the transform.Context
+// of the request they were received in.
+//
+// The requests the events were received in may have completed, so events
+// which cannot be reported are not retried, nor spooled; they are counted
+// and logged instead.
+func (s *TailSampler) flush(ctx context.Context, events []pendingEvent) {
+	for len(events) > 0 {
+		tcontext := events[0].tcontext
+		var transformables []transform.Transformable
+		remaining := events[:0]
+		for _, e := range events {
+			if e.tcontext == tcontext {
+				transformables = append(transformables, e.event)
+			} else {
+				remaining = append(remaining, e)
+			}
+		}
+		events = remaining
+		req := publish.PendingReq{Transformables: transformables, Tcontext: tcontext}
+		if err := s.reporter(ctx, req); err != nil {
+			s.mu.Lock()
+			s.stats.flushFailed += int64(len(transformables))
+			s.mu.Unlock()
+			s.logger.Errorw("failed to report events of kept traces",
+				"events", len(transformables), "error", err)
+		}
+	}
+}
+
+// CollectMonitoring may be called to collect monitoring metrics from the
+// sampler. It is intended to be used with libbeat/monitoring.NewFunc.
+//
+// The policy metrics are nested by policy name, e.g.
+// policies.<name>.kept.
+func (s *TailSampler) CollectMonitoring(_ monitoring.Mode, V monitoring.Visitor) {
+	V.OnRegistryStart()
+	defer V.OnRegistryFinished()
+
+	s.mu.Lock()
+	policies := s.config.Policies
+	stats := s.stats
+	stats.policies = append([]policyStats(nil), s.stats.policies...)
+	pending := len(s.traces)
+	s.mu.Unlock()
+
+	monitoring.ReportInt(V, "pending", int64(pending))
+	monitoring.ReportInt(V, "unmatched", stats.unmatched)
+	monitoring.ReportInt(V, "overflowed", stats.overflowed)
+	monitoring.ReportInt(V, "flush_failed", stats.flushFailed)
+	monitoring.ReportNamespace(V, "span_count", func() {
+		monitoring.ReportInt(V, "checked", stats.spanCount.checked)
+		monitoring.ReportInt(V, "inconsistent", stats.spanCount.inconsistent)
+		monitoring.ReportInt(V, "missing", stats.spanCount.missing)
+	})
+	monitoring.ReportNamespace(V, "policies", func() {
+		for i, p := range policies {
+			policy := stats.policies[i]
+			monitoring.ReportNamespace(V, p.Name, func() {
+				monitoring.ReportInt(V, "matched", policy.matched)
+				monitoring.ReportInt(V, "kept", policy.kept)
+				monitoring.ReportInt(V, "dropped", policy.dropped)
+			})
+		}
+	})
+}
+
+func collectTailSamplerMonitoring(mode monitoring.Mode, V monitoring.Visitor) {
+	monitoredTailSamplerMu.Lock()
+	s := monitoredTailSampler
+	monitoredTailSamplerMu.Unlock()
+	if s == nil {
+		V.OnRegistryStart()
+		V.OnRegistryFinished()
+		return
+	}
+	s.CollectMonitoring(mode, V)
+}
+
+func (t *pendingTrace) add(tcontext *transform.Context, event transform.Transformable) {
+	t.events = append(t.events, pendingEvent{tcontext: tcontext, event: event})
+	switch event := event.(type) {
+	case *model.Transaction:
+		if event.ParentID == "" {
+			t.root = event
+		}
+		t.transactions = append(t.transactions, event)
+	case *model.Span:
+		if t.spans == nil {
+			t.spans = make(map[string]int)
+		}
+		t.spans[event.TransactionID]++
+	case *model.Error:
+		t.hasError = true
+	}
+}
+
+func (p *TailSamplingPolicy) matches(t *pendingTrace) bool {
+	if p.HasError && !t.hasError {
+		return false
+	}
+	root := t.root
+	if root == nil {
+		// Criteria on the root transaction cannot be
+		// evaluated for traces decided without it.
+		return p.ServiceName == "" && p.ServiceEnvironment == "" &&
+			p.TransactionType == "" && p.TransactionName == "" &&
+			p.MinDuration <= 0
+	}
+	service := root.Metadata.Service
+	if !matchGlob(p.ServiceName, service.Name) ||
+		!matchGlob(p.ServiceEnvironment, service.Environment) ||
+		!matchGlob(p.TransactionType, root.Type) ||
+		!matchGlob(p.TransactionName, root.Name) {
+		return false
+	}
+	if p.MinDuration > 0 {
+		// Transaction durations are recorded in milliseconds.
+		duration := time.Duration(root.Duration * float64(time.Millisecond))
+		if duration < p.MinDuration {
+			return false
+		}
+	}
+	return true
+}
+
+func matchGlob(pattern, s string) bool {
+	return pattern == "" || glob.Glob(pattern, s)
+}
+
+func eventTraceID(event transform.Transformable) string {
+	switch event := event.(type) {
+	case *model.Transaction:
+		return event.TraceID
+	case *model.Span:
+		return event.TraceID
+	case *model.Error:
+		return event.TraceID
+	}
+	return ""
+}
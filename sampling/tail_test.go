@@ -0,0 +1,363 @@
+// Licensed to Elasticsearch B.V. under one or more contributor
+// license agreements. See the NOTICE file distributed with
+// this work for additional information regarding copyright
+// ownership. Elasticsearch B.V. licenses this file to you under
+// the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package sampling_test
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/elastic/beats/v7/libbeat/monitoring"
+
+	"github.com/elastic/apm-server/model"
+	"github.com/elastic/apm-server/publish"
+	"github.com/elastic/apm-server/sampling"
+	"github.com/elastic/apm-server/transform"
+)
+
+func TestTailSamplerPolicies(t *testing.T) {
+	var reported []transform.Transformable
+	sampler := sampling.NewTailSampler(sampling.TailSamplerConfig{
+		Policies: []sampling.TailSamplingPolicy{
+			{MinDuration: 2 * time.Second, SampleRate: 1},
+			{HasError: true, SampleRate: 1},
+			{SampleRate: 0},
+		},
+		DecisionWait: time.Minute,
+		MaxTraces:    100,
+	})
+	reporter := sampler.Wrap(func(ctx context.Context, req publish.PendingReq) error {
+		reported = append(reported, req.Transformables...)
+		return nil
+	})
+	report := func(events ...transform.Transformable) {
+		err := reporter(context.Background(), publish.PendingReq{
+			Transformables: events,
+			Tcontext:       &transform.Context{},
+		})
+		require.NoError(t, err)
+	}
+
+	slowSpan := &model.Span{TraceID: "slow", ParentID: "slow-root"}
+	slowRoot := &model.Transaction{TraceID: "slow", ID: "slow-root", Duration: 2500}
+	errorSpan := &model.Span{TraceID: "error", ParentID: "error-root"}
+	errorEvent := &model.Error{TraceID: "error", ParentID: "error-root"}
+	errorRoot := &model.Transaction{TraceID: "error", ID: "error-root", Duration: 10}
+	fastSpan := &model.Span{TraceID: "fast", ParentID: "fast-root"}
+	fastRoot := &model.Transaction{TraceID: "fast", ID: "fast-root", Duration: 10}
+	metricset := &model.Metricset{}
+
+	// Events are held until the root transaction is received.
+	report(slowSpan, errorSpan, errorEvent, fastSpan, metricset)
+	assert.Equal(t, []transform.Transformable{metricset}, reported)
+
+	reported = nil
+	report(slowRoot, errorRoot, fastRoot)
+	assert.ElementsMatch(t, []transform.Transformable{
+		slowSpan, slowRoot, errorSpan, errorEvent, errorRoot,
+	}, reported)
+
+	// Events received after the decision follow it.
+	reported = nil
+	lateSlowSpan := &model.Span{TraceID: "slow"}
+	report(lateSlowSpan, &model.Span{TraceID: "fast"})
+	assert.Equal(t, []transform.Transformable{lateSlowSpan}, reported)
+}
+
+func TestTailSamplerPoliciesSameRequest(t *testing.T) {
+	var reported []transform.Transformable
+	sampler := sampling.NewTailSampler(sampling.TailSamplerConfig{
+		Policies: []sampling.TailSamplingPolicy{
+			{HasError: true, SampleRate: 1},
+			{SampleRate: 0},
+		},
+		DecisionWait: time.Minute,
+		MaxTraces:    100,
+	})
+	reporter := sampler.Wrap(func(ctx context.Context, req publish.PendingReq) error {
+		reported = append(reported, req.Transformables...)
+		return nil
+	})
+
+	// Batches order transactions before spans and errors, so the root is
+	// seen before the error that makes the trace match the first policy.
+	root := &model.Transaction{TraceID: "1", ID: "root"}
+	span := &model.Span{TraceID: "1", ParentID: "root"}
+	errorEvent := &model.Error{TraceID: "1", ParentID: "root"}
+	batch := model.Batch{
+		Transactions: []*model.Transaction{root},
+		Spans:        []*model.Span{span},
+		Errors:       []*model.Error{errorEvent},
+	}
+	err := reporter(context.Background(), publish.PendingReq{
+		Transformables: batch.Transformables(),
+		Tcontext:       &transform.Context{},
+	})
+	require.NoError(t, err)
+	assert.ElementsMatch(t, []transform.Transformable{root, span, errorEvent}, reported)
+}
+
+func TestTailSamplerProportional(t *testing.T) {
+	var kept int
+	sampler := sampling.NewTailSampler(sampling.TailSamplerConfig{
+		Policies:     []sampling.TailSamplingPolicy{{SampleRate: 0.25}},
+		DecisionWait: time.Minute,
+		MaxTraces:    100,
+	})
+	reporter := sampler.Wrap(func(ctx context.Context, req publish.PendingReq) error {
+		kept += len(req.Transformables)
+		return nil
+	})
+	for i := 0; i < 1000; i++ {
+		reporter(context.Background(), publish.PendingReq{
+			Transformables: []transform.Transformable{
+				&model.Transaction{TraceID: fmt.Sprintf("%032x", i)},
+			},
+		})
+	}
+	assert.InDelta(t, 250, kept, 75)
+}
+
+func TestTailSamplerDecisionWait(t *testing.T) {
+	reported := make(chan []transform.Transformable, 10)
+	sampler := sampling.NewTailSampler(sampling.TailSamplerConfig{
+		Policies: []sampling.TailSamplingPolicy{
+			{TransactionType: "request", SampleRate: 0},
+		},
+		DecisionWait: 10 * time.Millisecond,
+		MaxTraces:    1,
+	})
+	reporter := sampler.Wrap(func(ctx context.Context, req publish.PendingReq) error {
+		if len(req.Transformables) > 0 {
+			reported <- req.Transformables
+		}
+		return nil
+	})
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	go sampler.Run(ctx)
+
+	// The first trace is held, the second exceeds MaxTraces.
+	span1 := &model.Span{TraceID: "1"}
+	span2 := &model.Span{TraceID: "2"}
+	reporter(context.Background(), publish.PendingReq{
+		Transformables: []transform.Transformable{span1, span2},
+	})
+	assert.Equal(t, []transform.Transformable{span2}, <-reported)
+
+	// The root transaction of trace 1 is not received, so the policy on
+	// transaction type does not match and the trace is kept.
+	select {
+	case events := <-reported:
+		assert.Equal(t, []transform.Transformable{span1}, events)
+	case <-time.After(10 * time.Second):
+		t.Fatal("timed out waiting for trace to be decided")
+	}
+}
+
+func TestTailSamplerMaxDecisions(t *testing.T) {
+	var reported []transform.Transformable
+	sampler := sampling.NewTailSampler(sampling.TailSamplerConfig{
+		Policies:     []sampling.TailSamplingPolicy{{SampleRate: 0}},
+		DecisionWait: time.Minute,
+		MaxTraces:    1,
+	})
+	reporter := sampler.Wrap(func(ctx context.Context, req publish.PendingReq) error {
+		reported = append(reported, req.Transformables...)
+		return nil
+	})
+	report := func(events ...transform.Transformable) {
+		reporter(context.Background(), publish.PendingReq{Transformables: events})
+	}
+	pending := func() int64 {
+		registry := monitoring.NewRegistry()
+		monitoring.NewFunc(registry, "tail", sampler.CollectMonitoring, monitoring.Report)
+		return monitoring.CollectFlatSnapshot(registry, monitoring.Full, false).Ints["tail.pending"]
+	}
+
+	// Spans received after their trace was dropped are dropped too.
+	report(&model.Transaction{TraceID: "1"})
+	report(&model.Span{TraceID: "1"})
+	assert.Equal(t, int64(0), pending())
+
+	// Deciding a second trace evicts the decision for the first,
+	// so its late spans are held waiting for a root transaction.
+	report(&model.Transaction{TraceID: "2"})
+	report(&model.Span{TraceID: "1"})
+	assert.Equal(t, int64(1), pending())
+	assert.Empty(t, reported)
+}
+
+func TestTailSamplerFlushFailed(t *testing.T) {
+	sampler := sampling.NewTailSampler(sampling.TailSamplerConfig{
+		Policies:     []sampling.TailSamplingPolicy{{SampleRate: 1}},
+		DecisionWait: time.Minute,
+		MaxTraces:    100,
+	})
+	reporter := sampler.Wrap(func(ctx context.Context, req publish.PendingReq) error {
+		if len(req.Transformables) > 0 {
+			return publish.ErrFull
+		}
+		return nil
+	})
+
+	// The span is held, and reported with its root transaction once the
+	// trace is kept. The error is not returned to the request reporting
+	// the root transaction, whose own events were all held.
+	err := reporter(context.Background(), publish.PendingReq{
+		Transformables: []transform.Transformable{&model.Span{TraceID: "1"}},
+	})
+	require.NoError(t, err)
+	err = reporter(context.Background(), publish.PendingReq{
+		Transformables: []transform.Transformable{&model.Transaction{TraceID: "1"}},
+	})
+	require.NoError(t, err)
+
+	registry := monitoring.NewRegistry()
+	monitoring.NewFunc(registry, "tail", sampler.CollectMonitoring, monitoring.Report)
+	snapshot := monitoring.CollectFlatSnapshot(registry, monitoring.Full, false)
+	assert.Equal(t, int64(2), snapshot.Ints["tail.flush_failed"])
+}
+
+func TestTailSamplerMonitoring(t *testing.T) {
+	sampler := sampling.NewTailSampler(sampling.TailSamplerConfig{
+		Policies: []sampling.TailSamplingPolicy{
+			{Name: "slow", MinDuration: time.Second, SampleRate: 1},
+			{TransactionType: "request", SampleRate: 0},
+		},
+		DecisionWait: time.Minute,
+		MaxTraces:    100,
+		Annotate:     true,
+	})
+	reporter := sampler.Wrap(func(ctx context.Context, req publish.PendingReq) error { return nil })
+
+	slow := &model.Transaction{TraceID: "1", Type: "request", Duration: 1500}
+	fast := &model.Transaction{TraceID: "2", Type: "request", Duration: 10}
+	other := &model.Transaction{TraceID: "3", Type: "scheduled", Duration: 10}
+	reporter(context.Background(), publish.PendingReq{
+		Transformables: []transform.Transformable{slow, fast, other, &model.Span{TraceID: "4"}},
+	})
+
+	// Only the root transactions of traces kept by a policy are annotated.
+	require.NotNil(t, slow.Labels)
+	assert.Equal(t, model.Labels{"tail_sampling_policy": "slow"}, *slow.Labels)
+	assert.Nil(t, fast.Labels)
+	assert.Nil(t, other.Labels)
+
+	registry := monitoring.NewRegistry()
+	monitoring.NewFunc(registry, "tail", sampler.CollectMonitoring, monitoring.Report)
+	snapshot := monitoring.CollectFlatSnapshot(registry, monitoring.Full, false)
+	assert.Equal(t, map[string]int64{
+		"tail.pending":                 1,
+		"tail.unmatched":               1,
+		"tail.overflowed":              0,
+		"tail.flush_failed":            0,
+		"tail.span_count.checked":      0,
+		"tail.span_count.inconsistent": 0,
+		"tail.span_count.missing":      0,
+		"tail.policies.slow.matched":   1,
+		"tail.policies.slow.kept":      1,
+		"tail.policies.slow.dropped":   0,
+		"tail.policies.1.matched":      1,
+		"tail.policies.1.kept":         0,
+		"tail.policies.1.dropped":      1,
+	}, snapshot.Ints)
+}
+
+func TestTailSamplerSpanCount(t *testing.T) {
+	sampler := sampling.NewTailSampler(sampling.TailSamplerConfig{
+		Policies:     []sampling.TailSamplingPolicy{{SampleRate: 1}},
+		DecisionWait: time.Minute,
+		MaxTraces:    100,
+	})
+	reporter := sampler.Wrap(func(ctx context.Context, req publish.PendingReq) error { return nil })
+
+	intPtr := func(i int) *int { return &i }
+	reporter(context.Background(), publish.PendingReq{
+		Transformables: []transform.Transformable{
+			&model.Span{TraceID: "1", TransactionID: "b"},
+			&model.Span{TraceID: "1", TransactionID: "b"},
+			&model.Span{TraceID: "1", TransactionID: "a"},
+			// b received all of its spans, a is missing 2 of its spans
+			&model.Transaction{TraceID: "1", ID: "b", ParentID: "x", SpanCount: model.SpanCount{Started: intPtr(2)}},
+			&model.Transaction{TraceID: "1", ID: "a", SpanCount: model.SpanCount{Started: intPtr(3), Dropped: intPtr(5)}},
+			// span counts are only checked when reported
+			&model.Transaction{TraceID: "2", ID: "c"},
+		},
+	})
+
+	registry := monitoring.NewRegistry()
+	monitoring.NewFunc(registry, "tail", sampler.CollectMonitoring, monitoring.Report)
+	snapshot := monitoring.CollectFlatSnapshot(registry, monitoring.Full, false)
+	assert.Equal(t, int64(2), snapshot.Ints["tail.span_count.checked"])
+	assert.Equal(t, int64(1), snapshot.Ints["tail.span_count.inconsistent"])
+	assert.Equal(t, int64(2), snapshot.Ints["tail.span_count.missing"])
+}
+
+func TestTailSamplerSpanCountSameRequest(t *testing.T) {
+	sampler := sampling.NewTailSampler(sampling.TailSamplerConfig{
+		Policies:     []sampling.TailSamplingPolicy{{SampleRate: 1}},
+		DecisionWait: time.Minute,
+		MaxTraces:    100,
+	})
+	reporter := sampler.Wrap(func(ctx context.Context, req publish.PendingReq) error { return nil })
+
+	// The root transaction precedes its spans in the batch; the spans
+	// must still be counted when checking span_count.started.
+	intPtr := func(i int) *int { return &i }
+	batch := model.Batch{
+		Transactions: []*model.Transaction{
+			{TraceID: "1", ID: "a", SpanCount: model.SpanCount{Started: intPtr(2)}},
+		},
+		Spans: []*model.Span{
+			{TraceID: "1", TransactionID: "a"},
+			{TraceID: "1", TransactionID: "a"},
+		},
+	}
+	reporter(context.Background(), publish.PendingReq{Transformables: batch.Transformables()})
+
+	registry := monitoring.NewRegistry()
+	monitoring.NewFunc(registry, "tail", sampler.CollectMonitoring, monitoring.Report)
+	snapshot := monitoring.CollectFlatSnapshot(registry, monitoring.Full, false)
+	assert.Equal(t, int64(1), snapshot.Ints["tail.span_count.checked"])
+	assert.Equal(t, int64(0), snapshot.Ints["tail.span_count.inconsistent"])
+	assert.Equal(t, int64(0), snapshot.Ints["tail.span_count.missing"])
+}
+
+func TestTailSamplerSetPolicies(t *testing.T) {
+	var reported []transform.Transformable
+	sampler := sampling.NewTailSampler(sampling.TailSamplerConfig{
+		Policies:     []sampling.TailSamplingPolicy{{SampleRate: 1}},
+		DecisionWait: time.Millisecond,
+		MaxTraces:    100,
+	})
+	reporter := sampler.Wrap(func(ctx context.Context, req publish.PendingReq) error {
+		reported = append(reported, req.Transformables...)
+		return nil
+	})
+	sampler.SetPolicies([]sampling.TailSamplingPolicy{{SampleRate: 0}})
+
+	reporter(context.Background(), publish.PendingReq{
+		Transformables: []transform.Transformable{&model.Transaction{TraceID: "1"}},
+	})
+	assert.Empty(t, reported)
+}
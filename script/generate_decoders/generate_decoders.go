@@ -0,0 +1,900 @@
+// Licensed to Elasticsearch B.V. under one or more contributor
+// license agreements. See the NOTICE file distributed with
+// this work for additional information regarding copyright
+// ownership. Elasticsearch B.V. licenses this file to you under
+// the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+// generate_decoders generates typed input structs and streaming JSON
+// decoders from the intake JSON schemas in docs/spec, avoiding the need
+// to decode events into intermediate maps. The "$ref"s of the schemas are
+// resolved relative to the referencing schema's file.
+//
+// Decoders written to the same file share the types generated for schemas
+// they both reference, such as context.json, which are named after the
+// referenced file. Schema constructs which cannot be checked by the generated
+// code cause the generator to panic, rather than accepting invalid input.
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"go/format"
+	"io/ioutil"
+	"os"
+	"path"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+const basePath = "docs/spec"
+
+const header = `// Licensed to Elasticsearch B.V. under one or more contributor
+// license agreements. See the NOTICE file distributed with
+// this work for additional information regarding copyright
+// ownership. Elasticsearch B.V. licenses this file to you under
+// the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+// Code generated by script/generate_decoders. DO NOT EDIT.
+
+`
+
+// patches extend the schemas of some properties with fields which are
+// read by the model decoders, but not defined in the schemas. Events holding
+// such fields are valid, so the generated decoders must handle them.
+var (
+	// The service of a span's context is decoded like the metadata service.
+	// service.json defines all of its fields, and is at least as strict.
+	spanServicePatch = `{"$ref": "service.json"}`
+	// The user's IP is decoded as the client IP.
+	userIPPatch = `{"properties": {"ip": {"type": ["string", "null"]}}}`
+)
+
+type decoderSpec struct {
+	schema, typeName string
+	// patches maps dotted property paths to schemas, which are merged
+	// into the property's schema. Schemas consisting of a "$ref" replace
+	// the property's schema instead, and must be at least as strict.
+	patches map[string]string
+}
+
+func main() {
+	outputs := []struct {
+		out      string
+		decoders []decoderSpec
+	}{{
+		out:      "model/metricset/generated/decoder/metricset.go",
+		decoders: []decoderSpec{{schema: "metricsets/metricset.json", typeName: "Metricset"}},
+	}, {
+		out: "model/modeldecoder/generated/decoder/events.go",
+		decoders: []decoderSpec{{
+			schema: "errors/error.json", typeName: "Error",
+			patches: map[string]string{"context.user": userIPPatch},
+		}, {
+			schema: "spans/span.json", typeName: "Span",
+			patches: map[string]string{"context.service": spanServicePatch},
+		}, {
+			schema: "transactions/transaction.json", typeName: "Transaction",
+			patches: map[string]string{"context.user": userIPPatch},
+		}},
+	}}
+	for _, o := range outputs {
+		g := generator{}
+		for _, d := range o.decoders {
+			root, err := loadSchema(path.Join(basePath, d.schema))
+			if err != nil {
+				panic(err)
+			}
+			root = root.flatten()
+			for property, patch := range d.patches {
+				if err := root.patch(property, patch); err != nil {
+					panic(fmt.Sprintf("%s: %s", d.schema, err))
+				}
+			}
+			g.generateStruct(d.typeName, root)
+		}
+		if err := g.write(o.out); err != nil {
+			panic(err)
+		}
+	}
+}
+
+// schemaNode holds the subset of JSON Schema keywords which
+// are relevant for generating decoders.
+type schemaNode struct {
+	Ref                  string                 `json:"$ref"`
+	Type                 interface{}            `json:"type"`
+	Properties           map[string]*schemaNode `json:"properties"`
+	PatternProperties    map[string]*schemaNode `json:"patternProperties"`
+	AdditionalProperties interface{}            `json:"additionalProperties"`
+	Items                *schemaNode            `json:"items"`
+	AllOf                []*schemaNode          `json:"allOf"`
+	AnyOf                []*schemaNode          `json:"anyOf"`
+	OneOf                []*schemaNode          `json:"oneOf"`
+	If                   *schemaNode            `json:"if"`
+	Then                 *schemaNode            `json:"then"`
+	Required             []string               `json:"required"`
+	MaxLength            *int                   `json:"maxLength"`
+	MinItems             *int                   `json:"minItems"`
+	Minimum              *float64               `json:"minimum"`
+	Pattern              *string                `json:"pattern"`
+	Enum                 []interface{}          `json:"enum"`
+
+	// origin holds the file of the schema, relative to basePath,
+	// if the node is the root of a schema file.
+	origin string
+	// conditions holds the properties required by if/then subschemas,
+	// and alternatives the properties required by anyOf and oneOf
+	// subschemas, once the node has been flattened.
+	conditions   []condition
+	alternatives [][][]string
+}
+
+// condition holds the properties required by a "then" subschema,
+// if the properties required by the "if" subschema are set.
+type condition struct {
+	ifSet, thenSet []string
+}
+
+// loadSchema reads the schema stored in file,
+// resolving its "$ref"s and those of referenced schemas.
+func loadSchema(file string) (*schemaNode, error) {
+	data, err := ioutil.ReadFile(file)
+	if err != nil {
+		return nil, err
+	}
+	var node schemaNode
+	if err := json.Unmarshal(data, &node); err != nil {
+		return nil, fmt.Errorf("%s: %s", file, err)
+	}
+	if err := node.resolveRefs(path.Dir(file)); err != nil {
+		return nil, fmt.Errorf("%s: %s", file, err)
+	}
+	node.origin = strings.TrimPrefix(file, basePath+"/")
+	return &node, nil
+}
+
+// patch merges the schema given as JSON into the flattened node's
+// property at the dotted path, or replaces the property's schema
+// if the given schema is a "$ref".
+func (n *schemaNode) patch(property, schema string) error {
+	var patch schemaNode
+	if err := json.Unmarshal([]byte(schema), &patch); err != nil {
+		return err
+	}
+	if err := patch.resolveRefs(basePath); err != nil {
+		return err
+	}
+	keys := strings.Split(property, ".")
+	for i, k := range keys {
+		prop, ok := n.Properties[k]
+		if !ok {
+			return fmt.Errorf("unknown property %q", strings.Join(keys[:i+1], "."))
+		}
+		if i == len(keys)-1 && patch.origin != "" {
+			n.Properties[k] = &patch
+			break
+		}
+		if i == len(keys)-1 {
+			patched := *prop
+			patched.AllOf = append(append([]*schemaNode(nil), prop.AllOf...), &patch)
+			n.Properties[k] = &patched
+			break
+		}
+		n.Properties[k] = prop.flatten()
+		n = n.Properties[k]
+	}
+	return nil
+}
+
+// resolveRefs replaces nodes holding a "$ref" with the referenced schema,
+// relative to dir. As in JSON Schema, other keywords alongside "$ref" are
+// ignored. References to fragments of schemas are not supported.
+func (n *schemaNode) resolveRefs(dir string) error {
+	if n.Ref != "" {
+		if strings.Contains(n.Ref, "#") {
+			return fmt.Errorf("unsupported $ref %q", n.Ref)
+		}
+		ref, err := loadSchema(path.Join(dir, n.Ref))
+		if err != nil {
+			return err
+		}
+		*n = *ref
+		return nil
+	}
+	for _, nodes := range []map[string]*schemaNode{n.Properties, n.PatternProperties} {
+		for _, node := range nodes {
+			if err := node.resolveRefs(dir); err != nil {
+				return err
+			}
+		}
+	}
+	children := append(append(append([]*schemaNode{n.Items, n.If, n.Then}, n.AllOf...), n.AnyOf...), n.OneOf...)
+	for _, node := range children {
+		if node == nil {
+			continue
+		}
+		if err := node.resolveRefs(dir); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// flatten merges the properties of all allOf, anyOf and oneOf
+// subschemas into a single node. The properties required by allOf
+// subschemas are merged into Required, those required by anyOf and oneOf
+// subschemas into alternatives, and those of if/then into conditions.
+func (n *schemaNode) flatten() *schemaNode {
+	out := *n
+	out.AllOf, out.AnyOf, out.OneOf, out.If, out.Then = nil, nil, nil, nil, nil
+	out.Properties = make(map[string]*schemaNode)
+	for k, v := range n.Properties {
+		out.Properties[k] = v
+	}
+	out.Required = append([]string(nil), n.Required...)
+	out.conditions = append([]condition(nil), n.conditions...)
+	out.alternatives = append([][][]string(nil), n.alternatives...)
+	if n.If != nil || n.Then != nil {
+		out.conditions = append(out.conditions, newCondition(n.If, n.Then))
+	}
+	for _, sub := range n.AllOf {
+		sub = sub.flatten()
+		out.merge(sub)
+		out.Required = append(out.Required, sub.Required...)
+		out.conditions = append(out.conditions, sub.conditions...)
+		out.alternatives = append(out.alternatives, sub.alternatives...)
+	}
+	for _, subs := range [][]*schemaNode{n.AnyOf, n.OneOf} {
+		var alternatives [][]string
+		for _, sub := range subs {
+			sub = sub.flatten()
+			if len(sub.conditions) > 0 || len(sub.alternatives) > 0 {
+				panic("nested conditions in anyOf and oneOf subschemas are not supported")
+			}
+			out.merge(sub)
+			alternatives = append(alternatives, sub.Required)
+		}
+		// Alternatives without required properties are always satisfied.
+		satisfied := false
+		for _, required := range alternatives {
+			satisfied = satisfied || len(required) == 0
+		}
+		if len(alternatives) > 0 && !satisfied {
+			out.alternatives = append(out.alternatives, alternatives)
+		}
+	}
+	return &out
+}
+
+// newCondition returns the condition defined by the if and then subschemas.
+// The properties required by "if" must not be nullable, as they would be
+// present in the input even if null.
+func newCondition(ifNode, thenNode *schemaNode) condition {
+	if ifNode == nil || thenNode == nil || len(ifNode.Required) == 0 {
+		panic("if without then, or without required properties, is not supported")
+	}
+	for _, k := range ifNode.Required {
+		if prop, ok := ifNode.Properties[k]; !ok || prop.nullable() {
+			panic(fmt.Sprintf("nullable property %q in if subschema is not supported", k))
+		}
+	}
+	return condition{ifSet: ifNode.Required, thenSet: thenNode.Required}
+}
+
+// merge merges the properties and constraints of the flattened
+// subschema sub into n, other than its required properties.
+func (n *schemaNode) merge(sub *schemaNode) {
+	for k, v := range sub.Properties {
+		if existing, ok := n.Properties[k]; ok {
+			v = mergeNodes(existing, v)
+		}
+		n.Properties[k] = v
+	}
+	if n.Type == nil {
+		n.Type = sub.Type
+	}
+	if n.MaxLength == nil {
+		n.MaxLength = sub.MaxLength
+	}
+	if n.MinItems == nil {
+		n.MinItems = sub.MinItems
+	}
+	if n.Minimum == nil {
+		n.Minimum = sub.Minimum
+	}
+	if n.Pattern == nil {
+		n.Pattern = sub.Pattern
+	}
+	if n.Enum == nil {
+		n.Enum = sub.Enum
+	}
+	if n.Items == nil {
+		n.Items = sub.Items
+	}
+	if n.AdditionalProperties == nil {
+		n.AdditionalProperties = sub.AdditionalProperties
+	}
+	if len(sub.PatternProperties) > 0 && len(n.PatternProperties) == 0 {
+		n.PatternProperties = sub.PatternProperties
+	}
+}
+
+func mergeNodes(a, b *schemaNode) *schemaNode {
+	merged := &schemaNode{AllOf: []*schemaNode{a, b}}
+	merged = merged.flatten()
+	if merged.Type == nil {
+		merged.Type = a.Type
+	}
+	return merged
+}
+
+// types returns the non-null JSON types allowed by the node.
+func (n *schemaNode) types() []string {
+	var types []string
+	switch t := n.Type.(type) {
+	case string:
+		types = []string{t}
+	case []interface{}:
+		for _, v := range t {
+			types = append(types, v.(string))
+		}
+	}
+	out := types[:0]
+	for _, t := range types {
+		if t != "null" {
+			out = append(out, t)
+		}
+	}
+	return out
+}
+
+// nullable reports whether the node allows null values. Nodes without
+// any type constraint allow all values, including null.
+func (n *schemaNode) nullable() bool {
+	switch t := n.Type.(type) {
+	case string:
+		return t == "null"
+	case []interface{}:
+		for _, v := range t {
+			if v == "null" {
+				return true
+			}
+		}
+		return false
+	}
+	return true
+}
+
+// patternProperty returns the single pattern property of the node, if any.
+// Multiple pattern properties are not supported.
+func (n *schemaNode) patternProperty() (string, *schemaNode) {
+	if len(n.PatternProperties) > 1 {
+		panic("multiple patternProperties are not supported")
+	}
+	for pattern, v := range n.PatternProperties {
+		return pattern, v.flatten()
+	}
+	return "", nil
+}
+
+type generator struct {
+	types   bytes.Buffer
+	methods bytes.Buffer
+	imports map[string]bool
+	regexps map[string]string // pattern -> variable name
+
+	// structs holds the schemas of the types generated for
+	// schema files, keyed by type name.
+	structs map[string][]byte
+}
+
+func (g *generator) write(out string) error {
+	var buf bytes.Buffer
+	buf.WriteString(header)
+	fmt.Fprintf(&buf, "package %s\n\n", path.Base(path.Dir(out)))
+	imports := []string{`jsoniter "github.com/json-iterator/go"`}
+	for imp := range g.imports {
+		imports = append(imports, imp)
+	}
+	sort.Strings(imports)
+	fmt.Fprintf(&buf, "import (\n%s\n)\n\n", strings.Join(imports, "\n"))
+	if len(g.regexps) > 0 {
+		patterns := make([]string, 0, len(g.regexps))
+		for pattern := range g.regexps {
+			patterns = append(patterns, pattern)
+		}
+		sort.Slice(patterns, func(i, j int) bool { return g.regexps[patterns[i]] < g.regexps[patterns[j]] })
+		buf.WriteString("var (\n")
+		for _, pattern := range patterns {
+			fmt.Fprintf(&buf, "\t%s = regexp.MustCompile(%q)\n", g.regexps[pattern], pattern)
+		}
+		buf.WriteString(")\n\n")
+	}
+	buf.Write(g.types.Bytes())
+	buf.Write(g.methods.Bytes())
+	src, err := format.Source(buf.Bytes())
+	if err != nil {
+		return fmt.Errorf("%s: %s", err, buf.String())
+	}
+	if err := os.MkdirAll(path.Dir(out), os.ModePerm); err != nil {
+		return err
+	}
+	return ioutil.WriteFile(out, src, 0644)
+}
+
+func (g *generator) addImport(imp string) {
+	if g.imports == nil {
+		g.imports = make(map[string]bool)
+	}
+	g.imports[imp] = true
+}
+
+// generateStruct generates a struct type for an object node, along with
+// DecodeJSON and Validate methods.
+func (g *generator) generateStruct(name string, node *schemaNode) {
+	keys := make([]string, 0, len(node.Properties))
+	for k := range node.Properties {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	var fields, cases, checks bytes.Buffer
+	isSet, notSet := make(map[string]string), make(map[string]string)
+	for _, k := range keys {
+		fieldName := goName(k)
+		propNode := node.Properties[k].flatten()
+		fieldType, decode := g.fieldType(name+fieldName, propNode, "v."+fieldName)
+		if !propNode.nullable() {
+			decode = fmt.Sprintf("\t\tif iter.WhatIsNext() == jsoniter.NilValue {\n\t\t\titer.Skip()\n\t\t\tok = false\n\t\t\tbreak\n\t\t}\n%s", decode)
+		}
+		fmt.Fprintf(&fields, "\t%s %s `json:%q`\n", fieldName, fieldType, k)
+		fmt.Fprintf(&cases, "\tcase %q:\n%s", k, decode)
+		isSet[k], notSet[k] = fmt.Sprintf("v.%s.IsSet", fieldName), fmt.Sprintf("!v.%s.IsSet", fieldName)
+		if strings.HasPrefix(fieldType, "map[") || strings.HasPrefix(fieldType, "[]") {
+			isSet[k], notSet[k] = fmt.Sprintf("v.%s != nil", fieldName), fmt.Sprintf("v.%s == nil", fieldName)
+		}
+		checks.WriteString(g.validation(k, propNode, fieldType, "v."+fieldName))
+	}
+	allSet := func(keys []string) string {
+		conds := make([]string, len(keys))
+		for i, k := range keys {
+			cond, ok := isSet[k]
+			if !ok {
+				panic(fmt.Sprintf("%s: required property %q is not defined", name, k))
+			}
+			conds[i] = cond
+		}
+		return strings.Join(conds, " && ")
+	}
+	notAllSet := func(keys []string) string {
+		if len(keys) == 1 {
+			allSet(keys)
+			return notSet[keys[0]]
+		}
+		return "!(" + allSet(keys) + ")"
+	}
+	quoted := func(keys []string) string {
+		out := make([]string, len(keys))
+		for i, k := range keys {
+			out[i] = strconv.Quote(k)
+		}
+		return strings.Join(out, ", ")
+	}
+
+	var required bytes.Buffer
+	for _, k := range node.Required {
+		fmt.Fprintf(&required, "\tif %s {\n\t\treturn errors.New(%q)\n\t}\n", notAllSet([]string{k}), k+": missing required property")
+	}
+	for _, alternatives := range node.alternatives {
+		conds := make([]string, len(alternatives))
+		descs := make([]string, len(alternatives))
+		for i, keys := range alternatives {
+			conds[i] = notAllSet(keys)
+			descs[i] = quoted(keys)
+		}
+		fmt.Fprintf(&required, "\tif %s {\n\t\treturn errors.New(%q)\n\t}\n",
+			strings.Join(conds, " && "), "missing required properties, one of "+strings.Join(descs, " or "))
+	}
+	for _, c := range node.conditions {
+		fmt.Fprintf(&required, "\tif %s && %s {\n\t\treturn errors.New(%q)\n\t}\n",
+			allSet(c.ifSet), notAllSet(c.thenSet), quoted(c.thenSet)+": required if "+quoted(c.ifSet)+" is set")
+	}
+
+	// Unknown properties are skipped, unless disallowed.
+	unknown := "\t\t\titer.Skip()\n"
+	if node.AdditionalProperties == false {
+		unknown = "\t\t\titer.Skip()\n\t\t\tok = false\n"
+	}
+	fmt.Fprintf(&g.types, "// %s holds the decoded input for the %q schema.\ntype %s struct {\n%s\n\t// IsSet reports whether a JSON object was decoded.\n\tIsSet bool `json:\"-\"`\n}\n\n",
+		name, name, name, fields.String())
+
+	g.addImport(`"github.com/pkg/errors"`)
+	fmt.Fprintf(&g.methods, `// DecodeJSON decodes the next JSON object from iter into v, reporting
+// whether the input matched the types defined in the schema.
+func (v *%[1]s) DecodeJSON(iter *jsoniter.Iterator) bool {
+	switch iter.WhatIsNext() {
+	case jsoniter.NilValue:
+		iter.ReadNil()
+		*v = %[1]s{}
+		return true
+	case jsoniter.ObjectValue:
+	default:
+		iter.Skip()
+		return false
+	}
+	*v = %[1]s{IsSet: true}
+	ok := true
+	iter.ReadMapCB(func(iter *jsoniter.Iterator, field string) bool {
+		switch field {
+%[2]s		default:
+%[4]s		}
+		return ok
+	})
+	return ok
+}
+
+// Validate checks v against the constraints defined in the schema
+// which are not already enforced by DecodeJSON.
+func (v *%[1]s) Validate() error {
+	if !v.IsSet {
+		return nil
+	}
+%[3]s	return nil
+}
+
+`, name, cases.String(), required.String()+checks.String(), unknown)
+}
+
+// validation returns code for checking the constraints of node,
+// for the value of expr with the given Go type.
+func (g *generator) validation(key string, node *schemaNode, goType, expr string) string {
+	var buf bytes.Buffer
+	if node.Minimum != nil && goType != "nullable.Int" && goType != "nullable.Float64" {
+		panic(fmt.Sprintf("%s: minimum is only supported for numbers", key))
+	}
+	switch {
+	case goType == "nullable.String":
+		if checks := g.stringChecks(key, node, expr+".Val"); checks != "" {
+			fmt.Fprintf(&buf, "\tif %s.IsSet {\n%s\t}\n", expr, checks)
+		}
+	case goType == "nullable.Int" || goType == "nullable.Float64":
+		if node.Minimum != nil {
+			min := strconv.FormatFloat(*node.Minimum, 'f', -1, 64)
+			fmt.Fprintf(&buf, "\tif %[1]s.IsSet && %[1]s.Val < %[2]s {\n\t\treturn errors.New(\"%[3]s: value must be >= %[2]s\")\n\t}\n", expr, min, key)
+		}
+	case goType == "nullable.Interface":
+		fmt.Fprintf(&buf, "\tif %s.IsSet {\n", expr)
+		buf.WriteString(g.interfaceChecks(key, node, expr+".Val"))
+		buf.WriteString("\t}\n")
+	case strings.HasPrefix(goType, "map["):
+		pattern, valueNode := node.patternProperty()
+		if pattern == "" && valueNode == nil {
+			break
+		}
+		checkKey := pattern != "" && node.AdditionalProperties == false
+		elemType := strings.TrimPrefix(goType, "map[string]")
+		var elemChecks string
+		switch {
+		case elemType == "interface{}":
+			if valueNode != nil && valueNode.Type != nil {
+				elemChecks = g.interfaceChecks(key, valueNode, "elem")
+			}
+		case strings.HasPrefix(elemType, "map["):
+			elemChecks = g.validation(key, valueNode, elemType, "elem")
+		default:
+			elemChecks = fmt.Sprintf("\t\tif err := elem.Validate(); err != nil {\n\t\t\treturn errors.Wrapf(err, \"%s.%%s\", k)\n\t\t}\n", key)
+		}
+		checkElem := elemChecks != ""
+		switch {
+		case checkKey && checkElem:
+			fmt.Fprintf(&buf, "\tfor k, elem := range %s {\n", expr)
+		case checkKey:
+			fmt.Fprintf(&buf, "\tfor k := range %s {\n", expr)
+		case checkElem:
+			fmt.Fprintf(&buf, "\tfor k, elem := range %s {\n\t\t_ = k\n", expr)
+		default:
+			return ""
+		}
+		if checkKey {
+			re := g.regexpVar(pattern)
+			fmt.Fprintf(&buf, "\t\tif !%s.MatchString(k) {\n\t\t\treturn errors.Errorf(\"%s: property %%q does not match pattern %%q\", k, %s.String())\n\t\t}\n",
+				re, key, re)
+		}
+		buf.WriteString(elemChecks)
+		buf.WriteString("\t}\n")
+	case strings.HasPrefix(goType, "[]"):
+		if node.MinItems != nil && *node.MinItems > 0 {
+			fmt.Fprintf(&buf, "\tif %[1]s != nil && len(%[1]s) < %[2]d {\n\t\treturn errors.New(\"%[3]s: must have at least %[2]d items\")\n\t}\n", expr, *node.MinItems, key)
+		}
+		elemChecks := g.validation(key, node.Items.flatten(), strings.TrimPrefix(goType, "[]"), "elem")
+		if elemChecks != "" {
+			fmt.Fprintf(&buf, "\tfor _, elem := range %s {\n%s\t}\n", expr, elemChecks)
+		}
+	case !strings.HasPrefix(goType, "nullable."):
+		fmt.Fprintf(&buf, "\tif err := %s.Validate(); err != nil {\n\t\treturn errors.Wrap(err, %q)\n\t}\n", expr, key)
+	}
+	return buf.String()
+}
+
+// stringChecks returns code for checking the string constraints of node.
+func (g *generator) stringChecks(key string, node *schemaNode, expr string) string {
+	var buf bytes.Buffer
+	if node.MaxLength != nil {
+		g.addImport(`"unicode/utf8"`)
+		fmt.Fprintf(&buf, "\t\tif utf8.RuneCountInString(%s) > %d {\n\t\t\treturn errors.New(\"%s: length must be <= %d\")\n\t\t}\n",
+			expr, *node.MaxLength, key, *node.MaxLength)
+	}
+	if node.Pattern != nil {
+		re := g.regexpVar(*node.Pattern)
+		fmt.Fprintf(&buf, "\t\tif !%s.MatchString(%s) {\n\t\t\treturn errors.Errorf(\"%s: value does not match pattern %%q\", %s.String())\n\t\t}\n",
+			re, expr, key, re)
+	}
+	if len(node.Enum) > 0 {
+		var values []string
+		for _, v := range node.Enum {
+			if s, ok := v.(string); ok {
+				values = append(values, strconv.Quote(s))
+			}
+		}
+		fmt.Fprintf(&buf, "\t\tswitch %s {\n\t\tcase %s:\n\t\tdefault:\n\t\t\treturn errors.Errorf(\"%s: value %%q is not one of the allowed values\", %s)\n\t\t}\n",
+			expr, strings.Join(values, ", "), key, expr)
+	}
+	return buf.String()
+}
+
+// interfaceChecks returns code for checking the dynamic type of expr
+// against the types allowed by node, along with any string constraints.
+func (g *generator) interfaceChecks(key string, node *schemaNode, expr string) string {
+	goTypes := map[string]string{
+		"string":  "string",
+		"boolean": "bool",
+		"number":  "json.Number",
+		"integer": "json.Number",
+		"array":   "[]interface{}",
+		"object":  "map[string]interface{}",
+	}
+	var allowed []string
+	seen := make(map[string]bool)
+	types := node.types()
+	for _, t := range types {
+		if goType := goTypes[t]; !seen[goType] {
+			seen[goType] = true
+			if goType != "string" {
+				allowed = append(allowed, goType)
+			}
+		}
+	}
+	// Numbers must be checked to be integers, unless any number is allowed.
+	integer := seen["json.Number"]
+	for _, t := range types {
+		integer = integer && t != "number"
+	}
+	var arrayChecks string
+	if seen["[]interface{}"] && node.Items != nil {
+		arrayChecks = g.interfaceChecks(key, node.Items.flatten(), "elem")
+	}
+	if node.Minimum != nil {
+		panic(fmt.Sprintf("%s: minimum is only supported for numbers", key))
+	}
+	if node.nullable() {
+		allowed = append(allowed, "nil")
+	}
+	if seen["json.Number"] {
+		g.addImport(`"encoding/json"`)
+	}
+	var buf bytes.Buffer
+	fmt.Fprintf(&buf, "\t\tswitch val := %s.(type) {\n", expr)
+	if seen["string"] {
+		buf.WriteString("\t\tcase string:\n")
+		buf.WriteString(g.stringChecks(key, node, "val"))
+	}
+	if integer {
+		buf.WriteString("\t\tcase json.Number:\n")
+		fmt.Fprintf(&buf, "\t\t\tif _, err := val.Int64(); err != nil {\n\t\t\t\treturn errors.New(\"%s: value must be an integer\")\n\t\t\t}\n", key)
+		allowed = removeString(allowed, "json.Number")
+	}
+	if arrayChecks != "" {
+		fmt.Fprintf(&buf, "\t\tcase []interface{}:\n\t\t\tfor _, elem := range val {\n%s\t\t\t}\n", arrayChecks)
+		allowed = removeString(allowed, "[]interface{}")
+	}
+	if len(allowed) > 0 {
+		fmt.Fprintf(&buf, "\t\tcase %s:\n", strings.Join(allowed, ", "))
+	}
+	fmt.Fprintf(&buf, "\t\tdefault:\n\t\t\treturn errors.Errorf(\"%s: unexpected type %%T\", val)\n\t\t}\n", key)
+	return buf.String()
+}
+
+func removeString(values []string, s string) []string {
+	out := values[:0]
+	for _, v := range values {
+		if v != s {
+			out = append(out, v)
+		}
+	}
+	return out
+}
+
+// regexpVar returns the name of a package variable holding the
+// compiled pattern, declaring it if necessary.
+func (g *generator) regexpVar(pattern string) string {
+	if name, ok := g.regexps[pattern]; ok {
+		return name
+	}
+	if g.regexps == nil {
+		g.regexps = make(map[string]string)
+	}
+	g.addImport(`"regexp"`)
+	name := fmt.Sprintf("pattern%d", len(g.regexps))
+	g.regexps[pattern] = name
+	return name
+}
+
+// fieldType returns the Go type for node, and code for decoding into
+// the given expression. Nested object types are generated as needed.
+func (g *generator) fieldType(name string, node *schemaNode, expr string) (string, string) {
+	types := node.types()
+	if len(types) == 0 && len(node.Properties) > 0 {
+		// Properties without type constraints are decoded as objects,
+		// rejecting other values rather than ignoring their properties.
+		types = []string{"object"}
+	}
+	decodeNullable := fmt.Sprintf("\t\tok = %s.DecodeJSON(iter)\n", expr)
+	if len(types) != 1 {
+		g.addImport(`"github.com/elastic/apm-server/model/modeldecoder/nullable"`)
+		return "nullable.Interface", decodeNullable
+	}
+	switch types[0] {
+	case "string":
+		g.addImport(`"github.com/elastic/apm-server/model/modeldecoder/nullable"`)
+		return "nullable.String", decodeNullable
+	case "integer":
+		g.addImport(`"github.com/elastic/apm-server/model/modeldecoder/nullable"`)
+		return "nullable.Int", decodeNullable
+	case "number":
+		g.addImport(`"github.com/elastic/apm-server/model/modeldecoder/nullable"`)
+		return "nullable.Float64", decodeNullable
+	case "boolean":
+		g.addImport(`"github.com/elastic/apm-server/model/modeldecoder/nullable"`)
+		return "nullable.Bool", decodeNullable
+	case "array":
+		if node.Items == nil {
+			g.addImport(`"github.com/elastic/apm-server/model/modeldecoder/nullable"`)
+			return "nullable.Interface", decodeNullable
+		}
+		itemNode := node.Items.flatten()
+		elemType, elemDecode := g.fieldType(name+"Item", itemNode, "item")
+		if !itemNode.nullable() {
+			elemDecode = "if iter.WhatIsNext() == jsoniter.NilValue {\niter.Skip()\nok = false\nreturn false\n}\n" + elemDecode
+		}
+		// Empty arrays are decoded as empty, rather than nil, slices.
+		return "[]" + elemType, fmt.Sprintf(`		switch iter.WhatIsNext() {
+		case jsoniter.NilValue:
+			iter.ReadNil()
+			%[1]s = nil
+		case jsoniter.ArrayValue:
+			%[1]s = []%[2]s{}
+			iter.ReadArrayCB(func(iter *jsoniter.Iterator) bool {
+				var item %[2]s
+				%[3]s
+				%[1]s = append(%[1]s, item)
+				return ok
+			})
+		default:
+			iter.Skip()
+			ok = false
+		}
+`, expr, elemType, strings.TrimSpace(elemDecode))
+	case "object":
+		if len(node.Properties) > 0 {
+			if node.origin != "" {
+				return g.fileStruct(node), decodeNullable
+			}
+			g.generateStruct(name, node)
+			return name, decodeNullable
+		}
+		if node.AdditionalProperties == false && len(node.PatternProperties) == 0 {
+			panic(fmt.Sprintf("%s: additionalProperties without properties is not supported", name))
+		}
+		_, valueNode := node.patternProperty()
+		// Values of nested maps are decoded into a variable
+		// which does not shadow the enclosing map's value.
+		elem := "elem"
+		if expr == elem {
+			elem = "value"
+		}
+		if valueNode == nil || valueNode.Type == nil {
+			g.addImport(`"github.com/elastic/apm-server/model/modeldecoder/nullable"`)
+			return "map[string]interface{}", decodeMap(expr, elem, "interface{}", elem+" := nullable.ReadInterface(iter)")
+		}
+		elemType, elemDecode := g.fieldType(name, valueNode, elem)
+		if strings.HasPrefix(elemType, "nullable.") {
+			g.addImport(`"github.com/elastic/apm-server/model/modeldecoder/nullable"`)
+			return "map[string]interface{}", decodeMap(expr, elem, "interface{}", elem+" := nullable.ReadInterface(iter)")
+		}
+		return "map[string]" + elemType, decodeMap(expr, elem, elemType,
+			fmt.Sprintf("var %s %s\n%s", elem, elemType, strings.TrimSpace(elemDecode)))
+	}
+	panic(fmt.Sprintf("unhandled type %q for %s", types[0], name))
+}
+
+// fileStruct returns the name of the struct type for the root of a schema
+// file, generating it if necessary. The type is named after the file, so
+// that it is shared by all schemas referencing the file.
+func (g *generator) fileStruct(node *schemaNode) string {
+	name := goName(strings.TrimSuffix(path.Base(node.origin), ".json"))
+	schema, err := json.Marshal(node)
+	if err != nil {
+		panic(err)
+	}
+	if existing, ok := g.structs[name]; ok {
+		if !bytes.Equal(existing, schema) {
+			panic(fmt.Sprintf("conflicting schemas for %s", name))
+		}
+		return name
+	}
+	if g.structs == nil {
+		g.structs = make(map[string][]byte)
+	}
+	g.structs[name] = schema
+	g.generateStruct(name, node)
+	return name
+}
+
+func decodeMap(expr, elem, elemType, elemDecode string) string {
+	return fmt.Sprintf(`		switch iter.WhatIsNext() {
+		case jsoniter.NilValue:
+			iter.ReadNil()
+			%[1]s = nil
+		case jsoniter.ObjectValue:
+			%[1]s = make(map[string]%[2]s)
+			iter.ReadMapCB(func(iter *jsoniter.Iterator, key string) bool {
+				%[3]s
+				%[1]s[key] = %[4]s
+				return ok
+			})
+		default:
+			iter.Skip()
+			ok = false
+		}
+`, expr, elemType, elemDecode, elem)
+}
+
+// goName converts a JSON property name to an exported Go identifier.
+func goName(s string) string {
+	initialisms := map[string]string{"id": "ID", "ip": "IP", "url": "URL", "http": "HTTP", "db": "DB"}
+	var out strings.Builder
+	for _, part := range strings.FieldsFunc(s, func(r rune) bool {
+		return r == '_' || r == '-' || r == '.'
+	}) {
+		if v, ok := initialisms[part]; ok {
+			out.WriteString(v)
+			continue
+		}
+		out.WriteString(strings.ToUpper(part[:1]) + part[1:])
+	}
+	return out.String()
+}
@@ -0,0 +1,393 @@
+// Licensed to Elasticsearch B.V. under one or more contributor
+// license agreements. See the NOTICE file distributed with
+// this work for additional information regarding copyright
+// ownership. Elasticsearch B.V. licenses this file to you under
+// the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"go/format"
+	"math/big"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/santhosh-tekuri/jsonschema"
+)
+
+// generateCompiledSchema returns Go source code for a function named funcName,
+// which returns schema without compiling it at runtime. Schemas are laid out
+// in a single slice, so that references between them, including cycles, are
+// expressed as pointers into the slice.
+func generateCompiledSchema(pkg, funcName, varName string, schema *jsonschema.Schema) ([]byte, error) {
+	g := schemaGenerator{index: make(map[*jsonschema.Schema]int)}
+	g.collect(schema)
+	for _, s := range g.schemas {
+		// The message reported for enum failures is computed at compile
+		// time and stored in an unexported field, which cannot be set here.
+		if s.Enum != nil {
+			return nil, fmt.Errorf("%s%s: enum is not supported in compiled schemas", s.URL, s.Ptr)
+		}
+	}
+
+	var body bytes.Buffer
+	for i, s := range g.schemas {
+		fmt.Fprintf(&body, "\ts[%d] = jsonschema.Schema{\n%s\t}\n", i, g.fields(s))
+	}
+
+	var buf bytes.Buffer
+	buf.WriteString(licenseHeader)
+	buf.WriteString("// Code generated by script/inline_schemas. DO NOT EDIT.\n\n")
+	fmt.Fprintf(&buf, "package %s\n\n", pkg)
+	std, thirdParty := g.imports()
+	buf.WriteString("import (\n")
+	for _, imp := range std {
+		fmt.Fprintf(&buf, "\t%q\n", imp)
+	}
+	if len(std) > 0 {
+		buf.WriteString("\n")
+	}
+	for _, imp := range thirdParty {
+		fmt.Fprintf(&buf, "\t%q\n", imp)
+	}
+	buf.WriteString(")\n\n")
+	fmt.Fprintf(&buf, "// %s returns the compiled form of %s.\n", funcName, varName)
+	fmt.Fprintf(&buf, "func %s() *jsonschema.Schema {\n", funcName)
+	fmt.Fprintf(&buf, "\ts := make([]jsonschema.Schema, %d)\n", len(g.schemas))
+	buf.Write(body.Bytes())
+	buf.WriteString("\treturn &s[0]\n}\n")
+	return format.Source(buf.Bytes())
+}
+
+type schemaGenerator struct {
+	schemas []*jsonschema.Schema
+	index   map[*jsonschema.Schema]int
+
+	usesRegexp     bool
+	usesBigFloat   bool
+	usesFormats    bool
+	usesJSONNumber bool
+	usesDecoders   bool
+	usesMediaTypes bool
+}
+
+func (g *schemaGenerator) imports() (std, thirdParty []string) {
+	if g.usesJSONNumber {
+		std = append(std, "encoding/json")
+	}
+	if g.usesBigFloat {
+		std = append(std, "math/big")
+	}
+	if g.usesRegexp {
+		std = append(std, "regexp")
+	}
+	thirdParty = append(thirdParty, "github.com/santhosh-tekuri/jsonschema")
+	if g.usesDecoders {
+		thirdParty = append(thirdParty, "github.com/santhosh-tekuri/jsonschema/decoders")
+	}
+	if g.usesFormats {
+		thirdParty = append(thirdParty, "github.com/santhosh-tekuri/jsonschema/formats")
+	}
+	if g.usesMediaTypes {
+		thirdParty = append(thirdParty, "github.com/santhosh-tekuri/jsonschema/mediatypes")
+	}
+	return std, thirdParty
+}
+
+// collect assigns an index to s and all schemas reachable from it.
+func (g *schemaGenerator) collect(s *jsonschema.Schema) {
+	if s == nil {
+		return
+	}
+	if _, ok := g.index[s]; ok {
+		return
+	}
+	g.index[s] = len(g.schemas)
+	g.schemas = append(g.schemas, s)
+
+	for _, child := range []*jsonschema.Schema{s.Ref, s.Not, s.If, s.Then, s.Else, s.PropertyNames, s.Contains} {
+		g.collect(child)
+	}
+	for _, subs := range [][]*jsonschema.Schema{s.AllOf, s.AnyOf, s.OneOf} {
+		for _, child := range subs {
+			g.collect(child)
+		}
+	}
+	for _, k := range sortedKeys(s.Properties) {
+		g.collect(s.Properties[k])
+	}
+	for _, re := range sortedPatterns(s.PatternProperties) {
+		g.collect(s.PatternProperties[re])
+	}
+	for _, v := range []interface{}{s.AdditionalProperties, s.Items, s.AdditionalItems} {
+		switch v := v.(type) {
+		case *jsonschema.Schema:
+			g.collect(v)
+		case []*jsonschema.Schema:
+			for _, child := range v {
+				g.collect(child)
+			}
+		}
+	}
+	for _, k := range sortedDependencies(s.Dependencies) {
+		if child, ok := s.Dependencies[k].(*jsonschema.Schema); ok {
+			g.collect(child)
+		}
+	}
+}
+
+func (g *schemaGenerator) ref(s *jsonschema.Schema) string {
+	return fmt.Sprintf("&s[%d]", g.index[s])
+}
+
+// fields returns the keyed fields of a composite literal for s.
+func (g *schemaGenerator) fields(s *jsonschema.Schema) string {
+	var buf bytes.Buffer
+	field := func(name, value string) {
+		fmt.Fprintf(&buf, "\t\t%s: %s,\n", name, value)
+	}
+	schemaField := func(name string, v *jsonschema.Schema) {
+		if v != nil {
+			field(name, g.ref(v))
+		}
+	}
+	schemasField := func(name string, v []*jsonschema.Schema) {
+		if v != nil {
+			field(name, g.refs(v))
+		}
+	}
+	stringField := func(name, v string) {
+		if v != "" {
+			field(name, strconv.Quote(v))
+		}
+	}
+	intField := func(name string, v int) {
+		field(name, strconv.Itoa(v))
+	}
+	boolField := func(name string, v bool) {
+		if v {
+			field(name, "true")
+		}
+	}
+	floatField := func(name string, v *big.Float) {
+		if v != nil {
+			g.usesBigFloat = true
+			field(name, fmt.Sprintf("func() *big.Float { f, _ := new(big.Float).SetString(%q); return f }()", v.Text('g', -1)))
+		}
+	}
+	valuesField := func(name string, v []interface{}) {
+		if v != nil {
+			field(name, g.value(v))
+		}
+	}
+
+	stringField("URL", s.URL)
+	stringField("Ptr", s.Ptr)
+	if s.Always != nil {
+		field("Always", fmt.Sprintf("func(b bool) *bool { return &b }(%t)", *s.Always))
+	}
+	schemaField("Ref", s.Ref)
+	if s.Types != nil {
+		field("Types", stringSlice(s.Types))
+	}
+	valuesField("Constant", s.Constant)
+	schemaField("Not", s.Not)
+	schemasField("AllOf", s.AllOf)
+	schemasField("AnyOf", s.AnyOf)
+	schemasField("OneOf", s.OneOf)
+	schemaField("If", s.If)
+	schemaField("Then", s.Then)
+	schemaField("Else", s.Else)
+
+	intField("MinProperties", s.MinProperties)
+	intField("MaxProperties", s.MaxProperties)
+	if s.Required != nil {
+		field("Required", stringSlice(s.Required))
+	}
+	if s.Properties != nil {
+		var props bytes.Buffer
+		props.WriteString("map[string]*jsonschema.Schema{\n")
+		for _, k := range sortedKeys(s.Properties) {
+			fmt.Fprintf(&props, "%q: %s,\n", k, g.ref(s.Properties[k]))
+		}
+		props.WriteString("}")
+		field("Properties", props.String())
+	}
+	schemaField("PropertyNames", s.PropertyNames)
+	boolField("RegexProperties", s.RegexProperties)
+	if s.PatternProperties != nil {
+		g.usesRegexp = true
+		var props bytes.Buffer
+		props.WriteString("map[*regexp.Regexp]*jsonschema.Schema{\n")
+		for _, re := range sortedPatterns(s.PatternProperties) {
+			fmt.Fprintf(&props, "regexp.MustCompile(%q): %s,\n", re.String(), g.ref(s.PatternProperties[re]))
+		}
+		props.WriteString("}")
+		field("PatternProperties", props.String())
+	}
+	if s.AdditionalProperties != nil {
+		field("AdditionalProperties", g.schemaOrValue(s.AdditionalProperties))
+	}
+	if s.Dependencies != nil {
+		var deps bytes.Buffer
+		deps.WriteString("map[string]interface{}{\n")
+		for _, k := range sortedDependencies(s.Dependencies) {
+			fmt.Fprintf(&deps, "%q: %s,\n", k, g.schemaOrValue(s.Dependencies[k]))
+		}
+		deps.WriteString("}")
+		field("Dependencies", deps.String())
+	}
+
+	intField("MinItems", s.MinItems)
+	intField("MaxItems", s.MaxItems)
+	boolField("UniqueItems", s.UniqueItems)
+	if s.Items != nil {
+		field("Items", g.schemaOrValue(s.Items))
+	}
+	if s.AdditionalItems != nil {
+		field("AdditionalItems", g.schemaOrValue(s.AdditionalItems))
+	}
+	schemaField("Contains", s.Contains)
+
+	intField("MinLength", s.MinLength)
+	intField("MaxLength", s.MaxLength)
+	if s.Pattern != nil {
+		g.usesRegexp = true
+		field("Pattern", fmt.Sprintf("regexp.MustCompile(%q)", s.Pattern.String()))
+	}
+	if s.Format != nil {
+		g.usesFormats = true
+		field("Format", fmt.Sprintf("func() formats.Format { f, _ := formats.Get(%q); return f }()", s.FormatName))
+	}
+	stringField("FormatName", s.FormatName)
+	stringField("ContentEncoding", s.ContentEncoding)
+	if s.Decoder != nil {
+		g.usesDecoders = true
+		field("Decoder", fmt.Sprintf("func() decoders.Decoder { d, _ := decoders.Get(%q); return d }()", s.ContentEncoding))
+	}
+	stringField("ContentMediaType", s.ContentMediaType)
+	if s.MediaType != nil {
+		g.usesMediaTypes = true
+		field("MediaType", fmt.Sprintf("func() mediatypes.MediaType { m, _ := mediatypes.Get(%q); return m }()", s.ContentMediaType))
+	}
+
+	floatField("Minimum", s.Minimum)
+	floatField("ExclusiveMinimum", s.ExclusiveMinimum)
+	floatField("Maximum", s.Maximum)
+	floatField("ExclusiveMaximum", s.ExclusiveMaximum)
+	floatField("MultipleOf", s.MultipleOf)
+
+	stringField("Title", s.Title)
+	stringField("Description", s.Description)
+	if s.Default != nil {
+		field("Default", g.value(s.Default))
+	}
+	boolField("ReadOnly", s.ReadOnly)
+	boolField("WriteOnly", s.WriteOnly)
+	valuesField("Examples", s.Examples)
+	return buf.String()
+}
+
+func (g *schemaGenerator) refs(schemas []*jsonschema.Schema) string {
+	refs := make([]string, len(schemas))
+	for i, s := range schemas {
+		refs[i] = g.ref(s)
+	}
+	return fmt.Sprintf("[]*jsonschema.Schema{%s}", strings.Join(refs, ", "))
+}
+
+func (g *schemaGenerator) schemaOrValue(v interface{}) string {
+	switch v := v.(type) {
+	case *jsonschema.Schema:
+		return g.ref(v)
+	case []*jsonschema.Schema:
+		return g.refs(v)
+	case []string:
+		return stringSlice(v)
+	}
+	return g.value(v)
+}
+
+// value returns a Go expression for a decoded JSON value.
+func (g *schemaGenerator) value(v interface{}) string {
+	switch v := v.(type) {
+	case nil:
+		return "nil"
+	case bool:
+		return strconv.FormatBool(v)
+	case string:
+		return strconv.Quote(v)
+	case json.Number:
+		g.usesJSONNumber = true
+		return fmt.Sprintf("json.Number(%q)", string(v))
+	case []interface{}:
+		values := make([]string, len(v))
+		for i, elem := range v {
+			values[i] = g.value(elem)
+		}
+		return fmt.Sprintf("[]interface{}{%s}", strings.Join(values, ", "))
+	case map[string]interface{}:
+		keys := make([]string, 0, len(v))
+		for k := range v {
+			keys = append(keys, k)
+		}
+		sort.Strings(keys)
+		values := make([]string, len(keys))
+		for i, k := range keys {
+			values[i] = fmt.Sprintf("%q: %s", k, g.value(v[k]))
+		}
+		return fmt.Sprintf("map[string]interface{}{%s}", strings.Join(values, ", "))
+	}
+	panic(fmt.Sprintf("unhandled value type %T", v))
+}
+
+func stringSlice(s []string) string {
+	values := make([]string, len(s))
+	for i, v := range s {
+		values[i] = strconv.Quote(v)
+	}
+	return fmt.Sprintf("[]string{%s}", strings.Join(values, ", "))
+}
+
+func sortedKeys(m map[string]*jsonschema.Schema) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+func sortedDependencies(m map[string]interface{}) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+func sortedPatterns(m map[*regexp.Regexp]*jsonschema.Schema) []*regexp.Regexp {
+	patterns := make([]*regexp.Regexp, 0, len(m))
+	for re := range m {
+		patterns = append(patterns, re)
+	}
+	sort.Slice(patterns, func(i, j int) bool { return patterns[i].String() < patterns[j].String() })
+	return patterns
+}
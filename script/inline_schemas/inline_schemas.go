@@ -25,25 +25,48 @@ import (
 	"path/filepath"
 	"regexp"
 	"strings"
+
+	"github.com/elastic/apm-server/validation"
 )
 
 const basePath = "./docs/spec/"
 
+const licenseHeader = `// Licensed to Elasticsearch B.V. under one or more contributor
+// license agreements. See the NOTICE file distributed with
+// this work for additional information regarding copyright
+// ownership. Elasticsearch B.V. licenses this file to you under
+// the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+`
+
 func main() {
 	schemaPaths := []struct {
-		path, schemaOut, varName string
+		path, schemaOut, varName, url string
 	}{
-		{"sourcemaps/payload.json", "model/sourcemap/generated/schema/payload.go", "PayloadSchema"},
-		{"metadata.json", "model/metadata/generated/schema/metadata.go", "ModelSchema"},
-		{"rum_v3_metadata.json", "model/metadata/generated/schema/rum_v3_metadata.go", "RUMV3Schema"},
-		{"errors/error.json", "model/error/generated/schema/error.go", "ModelSchema"},
-		{"transactions/transaction.json", "model/transaction/generated/schema/transaction.go", "ModelSchema"},
-		{"spans/span.json", "model/span/generated/schema/span.go", "ModelSchema"},
-		{"metricsets/metricset.json", "model/metricset/generated/schema/metricset.go", "ModelSchema"},
-		{"errors/rum_v3_error.json", "model/error/generated/schema/rum_v3_error.go", "RUMV3Schema"},
-		{"transactions/rum_v3_transaction.json", "model/transaction/generated/schema/rum_v3_transaction.go", "RUMV3Schema"},
-		{"spans/rum_v3_span.json", "model/span/generated/schema/rum_v3_span.go", "RUMV3Schema"},
-		{"metricsets/rum_v3_metricset.json", "model/metricset/generated/schema/rum_v3_metricset.go", "RUMV3Schema"},
+		{"sourcemaps/payload.json", "model/sourcemap/generated/schema/payload.go", "PayloadSchema", "sourcemap"},
+		{"metadata.json", "model/metadata/generated/schema/metadata.go", "ModelSchema", "metadata"},
+		{"rum_v3_metadata.json", "model/metadata/generated/schema/rum_v3_metadata.go", "RUMV3Schema", "metadata"},
+		{"errors/error.json", "model/error/generated/schema/error.go", "ModelSchema", "error"},
+		{"transactions/transaction.json", "model/transaction/generated/schema/transaction.go", "ModelSchema", "transaction"},
+		{"spans/span.json", "model/span/generated/schema/span.go", "ModelSchema", "span"},
+		{"metricsets/metricset.json", "model/metricset/generated/schema/metricset.go", "ModelSchema", "metricset"},
+		{"errors/rum_v3_error.json", "model/error/generated/schema/rum_v3_error.go", "RUMV3Schema", "error"},
+		{"transactions/rum_v3_transaction.json", "model/transaction/generated/schema/rum_v3_transaction.go", "RUMV3Schema", "transaction"},
+		{"spans/rum_v3_span.json", "model/span/generated/schema/rum_v3_span.go", "RUMV3Schema", "span"},
+		{"metricsets/rum_v3_metricset.json", "model/metricset/generated/schema/rum_v3_metricset.go", "RUMV3Schema", "metricset"},
+		// Snapshots of schemas of older versions, see model/modeldecoder/schema_version.go.
+		{"v7.8/metricsets/metricset.json", "model/metricset/generated/schema/v7_8_metricset.go", "ModelSchemaV7_8", "metricset"},
 	}
 	for _, schemaInfo := range schemaPaths {
 		file := filepath.Join(filepath.Dir(basePath), schemaInfo.path)
@@ -57,7 +80,7 @@ func main() {
 			panic(err)
 		}
 
-		goScript := fmt.Sprintf("package schema\n\nconst %s = `%s`\n", schemaInfo.varName, schema)
+		goScript := fmt.Sprintf("%spackage schema\n\nconst %s = `%s`\n", licenseHeader, schemaInfo.varName, schema)
 		err = os.MkdirAll(path.Dir(schemaInfo.schemaOut), os.ModePerm)
 		if err != nil {
 			panic(err)
@@ -66,6 +89,51 @@ func main() {
 		if err != nil {
 			panic(err)
 		}
+
+		// Compile the schema now, so invalid schemas are reported at
+		// generation time and the server doesn't compile them on startup.
+		compiled, err := validation.CompileSchema(schema, schemaInfo.url)
+		if err != nil {
+			panic(fmt.Errorf("%s: %v", file, err))
+		}
+		funcName := "Compiled" + schemaInfo.varName
+		compiledScript, err := generateCompiledSchema("schema", funcName, schemaInfo.varName, compiled)
+		if err != nil {
+			panic(err)
+		}
+		compiledOut := strings.TrimSuffix(schemaInfo.schemaOut, ".go") + "_compiled.go"
+		err = ioutil.WriteFile(compiledOut, compiledScript, 0644)
+		if err != nil {
+			panic(err)
+		}
+	}
+	generateExamples()
+}
+
+// generateExamples inlines the canonical example payloads,
+// so they can be exported by the server binary.
+func generateExamples() {
+	examplePaths := []struct {
+		path, out, varName string
+	}{
+		{"testdata/intake-v2/events.ndjson", "processor/stream/generated/examples/events.go", "Events"},
+		{"testdata/intake-v3/rum_events.ndjson", "processor/stream/generated/examples/rum_v3_events.go", "RUMV3Events"},
+	}
+	for _, exampleInfo := range examplePaths {
+		example, err := ioutil.ReadFile(exampleInfo.path)
+		if err != nil {
+			panic(err)
+		}
+		if strings.Contains(string(example), "`") {
+			panic(fmt.Errorf("%s: backticks cannot be inlined", exampleInfo.path))
+		}
+		goScript := fmt.Sprintf("%spackage examples\n\nconst %s = `%s`\n", licenseHeader, exampleInfo.varName, example)
+		if err := os.MkdirAll(path.Dir(exampleInfo.out), os.ModePerm); err != nil {
+			panic(err)
+		}
+		if err := ioutil.WriteFile(exampleInfo.out, []byte(goScript), 0644); err != nil {
+			panic(err)
+		}
 	}
 }
 
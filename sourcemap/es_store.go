@@ -25,7 +25,9 @@ import (
 	"io"
 	"io/ioutil"
 	"net/http"
+	"time"
 
+	"github.com/elastic/go-elasticsearch/v7/esapi"
 	"github.com/pkg/errors"
 
 	"github.com/elastic/beats/v7/libbeat/logp"
@@ -37,6 +39,9 @@ import (
 const (
 	emptyResult          = ""
 	errMsgParseSourcemap = "Could not parse Sourcemap."
+
+	// maxListSize is the maximum number of sourcemaps listed at once.
+	maxListSize = 1000
 )
 
 var (
@@ -65,8 +70,35 @@ type esSourcemapResponse struct {
 	} `json:"hits"`
 }
 
-func (s *esStore) fetch(ctx context.Context, name, version, path string) (string, error) {
-	statusCode, body, err := s.runSearchQuery(ctx, name, version, path)
+type esSourcemapListResponse struct {
+	Hits struct {
+		Hits []struct {
+			ID     string `json:"_id"`
+			Source struct {
+				Timestamp time.Time `json:"@timestamp"`
+				Sourcemap struct {
+					BundleFilepath string `json:"bundle_filepath"`
+					Service        struct {
+						Name    string
+						Version string
+					}
+				}
+			} `json:"_source"`
+		}
+	} `json:"hits"`
+}
+
+// Metadata describes an uploaded sourcemap.
+type Metadata struct {
+	ID             string    `json:"id"`
+	ServiceName    string    `json:"service_name"`
+	ServiceVersion string    `json:"service_version"`
+	BundleFilepath string    `json:"bundle_filepath"`
+	Uploaded       time.Time `json:"uploaded"`
+}
+
+func (s *esStore) fetch(ctx context.Context, tenant, name, version, path string) (string, error) {
+	statusCode, body, err := s.runSearchQuery(ctx, tenant, name, version, path)
 	if err != nil {
 		return "", errors.Wrap(err, errMsgESFailure)
 	}
@@ -87,10 +119,91 @@ func (s *esStore) fetch(ctx context.Context, name, version, path string) (string
 	return parse(body, name, version, path, s.logger)
 }
 
-func (s *esStore) runSearchQuery(ctx context.Context, name, version, path string) (int, io.ReadCloser, error) {
+// list returns metadata of the sourcemaps uploaded for the given service
+// name and version, most recently uploaded first. Empty name or version
+// match any service name or version, and an empty tenant matches any tenant.
+func (s *esStore) list(ctx context.Context, tenant, name, version string) ([]Metadata, error) {
+	var buf bytes.Buffer
+	if err := json.NewEncoder(&buf).Encode(listQuery(tenant, name, version)); err != nil {
+		return nil, err
+	}
+	statusCode, body, err := s.client.SearchQuery(ctx, s.index, &buf)
+	if err != nil {
+		return nil, errors.Wrap(err, errMsgESFailure)
+	}
+	defer body.Close()
+	if statusCode >= http.StatusMultipleChoices {
+		if statusCode == http.StatusNotFound {
+			return nil, nil
+		}
+		b, err := ioutil.ReadAll(body)
+		if err != nil {
+			return nil, errors.Wrap(err, errMsgESFailure)
+		}
+		return nil, errors.Errorf("%s: %s", errMsgESFailure, b)
+	}
+
+	var resp esSourcemapListResponse
+	if err := json.NewDecoder(body).Decode(&resp); err != nil {
+		return nil, err
+	}
+	result := make([]Metadata, len(resp.Hits.Hits))
+	for i, hit := range resp.Hits.Hits {
+		result[i] = Metadata{
+			ID:             hit.ID,
+			ServiceName:    hit.Source.Sourcemap.Service.Name,
+			ServiceVersion: hit.Source.Sourcemap.Service.Version,
+			BundleFilepath: hit.Source.Sourcemap.BundleFilepath,
+			Uploaded:       hit.Source.Timestamp,
+		}
+	}
+	return result, nil
+}
+
+// delete deletes the sourcemaps uploaded for the given service name and
+// version, and bundle filepath and tenant if non-empty, returning the number
+// of sourcemaps deleted.
+func (s *esStore) delete(ctx context.Context, tenant, name, version, path string) (int, error) {
+	var buf bytes.Buffer
+	if err := json.NewEncoder(&buf).Encode(deleteQuery(tenant, name, version, path)); err != nil {
+		return 0, err
+	}
+	refresh := true
+	req := esapi.DeleteByQueryRequest{
+		Index:     []string{s.index},
+		Body:      &buf,
+		Conflicts: "proceed",
+		Refresh:   &refresh,
+	}
+	resp, err := req.Do(ctx, s.client)
+	if err != nil {
+		return 0, errors.Wrap(err, errMsgESFailure)
+	}
+	defer resp.Body.Close()
+	if resp.IsError() {
+		if resp.StatusCode == http.StatusNotFound {
+			return 0, nil
+		}
+		b, err := ioutil.ReadAll(resp.Body)
+		if err != nil {
+			return 0, errors.Wrap(err, errMsgESFailure)
+		}
+		return 0, errors.Errorf("%s: %s", errMsgESFailure, b)
+	}
+
+	var result struct {
+		Deleted int `json:"deleted"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&result); err != nil {
+		return 0, err
+	}
+	return result.Deleted, nil
+}
+
+func (s *esStore) runSearchQuery(ctx context.Context, tenant, name, version, path string) (int, io.ReadCloser, error) {
 	// build and encode the query
 	var buf bytes.Buffer
-	if err := json.NewEncoder(&buf).Encode(query(name, version, path)); err != nil {
+	if err := json.NewEncoder(&buf).Encode(query(tenant, name, version, path)); err != nil {
 		return 0, nil, err
 	}
 	// Perform the runSearchQuery request.
@@ -120,10 +233,10 @@ func parse(body io.ReadCloser, name, version, path string, logger *logp.Logger)
 	return esSourcemap, nil
 }
 
-func query(name, version, path string) map[string]interface{} {
+func query(tenant, name, version, path string) map[string]interface{} {
 	return searchFirst(
 		boolean(
-			must(
+			must(withTenant(tenant,
 				term("processor.name", "sourcemap"),
 				term("sourcemap.service.name", name),
 				term("sourcemap.service.version", version),
@@ -135,7 +248,7 @@ func query(name, version, path string) map[string]interface{} {
 						term("sourcemap.bundle_filepath", utility.UrlPath(path)),
 					),
 				),
-			),
+			)...),
 		),
 		"sourcemap.sourcemap",
 		desc("_score"),
@@ -143,6 +256,43 @@ func query(name, version, path string) map[string]interface{} {
 	)
 }
 
+func listQuery(tenant, name, version string) map[string]interface{} {
+	filters := withTenant(tenant, term("processor.name", "sourcemap"))
+	if name != "" {
+		filters = append(filters, term("sourcemap.service.name", name))
+	}
+	if version != "" {
+		filters = append(filters, term("sourcemap.service.version", version))
+	}
+	return map[string]interface{}{
+		"query":   boolean(must(filters...)),
+		"size":    maxListSize,
+		"sort":    []map[string]interface{}{desc("@timestamp")},
+		"_source": []string{"@timestamp", "sourcemap.service", "sourcemap.bundle_filepath"},
+	}
+}
+
+func deleteQuery(tenant, name, version, path string) map[string]interface{} {
+	filters := withTenant(tenant,
+		term("processor.name", "sourcemap"),
+		term("sourcemap.service.name", name),
+		term("sourcemap.service.version", version),
+	)
+	if path != "" {
+		filters = append(filters, term("sourcemap.bundle_filepath", utility.UrlPath(path)))
+	}
+	return map[string]interface{}{"query": boolean(must(filters...))}
+}
+
+// withTenant returns filters, restricted to the sourcemaps
+// uploaded for tenant if it is non-empty.
+func withTenant(tenant string, filters ...map[string]interface{}) []map[string]interface{} {
+	if tenant != "" {
+		filters = append(filters, term("tenant.id", tenant))
+	}
+	return filters
+}
+
 func wrap(k string, v map[string]interface{}) map[string]interface{} {
 	return map[string]interface{}{k: v}
 }
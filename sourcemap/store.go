@@ -21,6 +21,7 @@ import (
 	"context"
 	"math"
 	"strings"
+	"sync"
 	"time"
 
 	"github.com/elastic/apm-server/elasticsearch"
@@ -30,8 +31,10 @@ import (
 	"github.com/pkg/errors"
 
 	"github.com/elastic/beats/v7/libbeat/logp"
+	"github.com/elastic/beats/v7/libbeat/monitoring"
 
 	logs "github.com/elastic/apm-server/log"
+	"github.com/elastic/apm-server/utility"
 )
 
 const (
@@ -39,14 +42,40 @@ const (
 )
 
 var (
-	errInit = errors.New("Cache cannot be initialized. Expiration and CleanupInterval need to be >= 0")
+	errInit         = errors.New("Cache cannot be initialized. Expiration and CleanupInterval need to be >= 0")
+	errFetchTimeout = errors.New("timed out waiting for sourcemap to be fetched")
+
+	cacheRegistry = monitoring.Default.NewRegistry("apm-server.sourcemap_cache")
+	cacheHits     = monitoring.NewInt(cacheRegistry, "hits")
+	cacheMisses   = monitoring.NewInt(cacheRegistry, "misses")
 )
 
 // Store holds information necessary to fetch a sourcemap, either from an Elasticsearch instance or an internal cache.
 type Store struct {
+	// WaitTimeout holds the maximum amount of time to wait for a
+	// sourcemap to be fetched from Elasticsearch. If the fetch does
+	// not complete in time, it continues in the background and the
+	// result is cached for subsequent events. Waits for the same
+	// sourcemap share a deadline, so a slow fetch delays a stream at
+	// most once.
+	//
+	// If WaitTimeout is zero, Fetch waits for the fetch to complete.
+	WaitTimeout time.Duration
+
 	cache   *gocache.Cache
 	esStore *esStore
 	logger  *logp.Logger
+
+	mu       sync.Mutex
+	inflight map[string]*inflightFetch
+}
+
+// inflightFetch holds the state of a background sourcemap fetch.
+type inflightFetch struct {
+	deadline time.Time
+	done     chan struct{}
+	consumer *sourcemap.Consumer
+	err      error
 }
 
 // NewStore creates a new instance for fetching sourcemaps. The client and index parameters are needed to be able to
@@ -57,52 +86,116 @@ func NewStore(client elasticsearch.Client, index string, expiration time.Duratio
 	}
 	logger := logp.NewLogger(logs.Sourcemap)
 	return &Store{
-		cache:   gocache.New(expiration, cleanupInterval(expiration)),
-		esStore: &esStore{client: client, index: index, logger: logger},
-		logger:  logger,
+		cache:    gocache.New(expiration, cleanupInterval(expiration)),
+		esStore:  &esStore{client: client, index: index, logger: logger},
+		logger:   logger,
+		inflight: make(map[string]*inflightFetch),
 	}, nil
 }
 
 // Fetch a sourcemap from the store.
+//
+// In multi-tenant mode, sourcemaps are scoped by the tenant carried by ctx:
+// only sourcemaps uploaded for the tenant are applied to its events.
 func (s *Store) Fetch(ctx context.Context, name string, version string, path string) (*sourcemap.Consumer, error) {
-	key := key([]string{name, version, path})
+	tenant := utility.Tenant(ctx)
+	key := cacheKey(tenant, name, version, path)
 
 	// fetch from cache
-	if val, found := s.cache.Get(key); found {
-		consumer, _ := val.(*sourcemap.Consumer)
+	if consumer, found := s.cached(key); found {
+		cacheHits.Inc()
 		return consumer, nil
 	}
+	cacheMisses.Inc()
+	if s.WaitTimeout <= 0 {
+		consumer, cache, err := s.fetch(ctx, tenant, name, version, path)
+		if cache {
+			s.add(key, consumer)
+		}
+		return consumer, err
+	}
 
-	// fetch from Elasticsearch and ensure caching for all non-temporary results
-	sourcemapStr, err := s.esStore.fetch(ctx, name, version, path)
+	s.mu.Lock()
+	f, ok := s.inflight[key]
+	if !ok {
+		f = &inflightFetch{deadline: time.Now().Add(s.WaitTimeout), done: make(chan struct{})}
+		s.inflight[key] = f
+		go s.fetchInflight(key, tenant, name, version, path, f)
+	}
+	s.mu.Unlock()
+
+	timer := time.NewTimer(time.Until(f.deadline))
+	defer timer.Stop()
+	select {
+	case <-f.done:
+		return f.consumer, f.err
+	case <-timer.C:
+		return nil, errFetchTimeout
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	}
+}
+
+// fetchInflight fetches a sourcemap in the background, caching the result
+// unless the sourcemap was uploaded while it was being fetched.
+func (s *Store) fetchInflight(key, tenant, name, version, path string, f *inflightFetch) {
+	defer close(f.done)
+	// The fetch outlives the event being transformed,
+	// so it must not be cancelled along with its context.
+	consumer, cache, err := s.fetch(context.Background(), tenant, name, version, path)
+	f.consumer, f.err = consumer, err
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.inflight[key] != f {
+		return
+	}
+	delete(s.inflight, key)
+	if cache {
+		s.add(key, consumer)
+	}
+}
+
+// fetch fetches a sourcemap from Elasticsearch, reporting whether the
+// result should be cached; temporary failures are not cached.
+func (s *Store) fetch(ctx context.Context, tenant, name, version, path string) (*sourcemap.Consumer, bool, error) {
+	sourcemapStr, err := s.esStore.fetch(ctx, tenant, name, version, path)
 	if err != nil {
-		if !strings.Contains(err.Error(), errMsgESFailure) {
-			s.add(key, nil)
-		}
-		return nil, err
+		return nil, !strings.Contains(err.Error(), errMsgESFailure), err
 	}
 
 	if sourcemapStr == emptyResult {
-		s.add(key, nil)
-		return nil, nil
+		return nil, true, nil
 	}
 
 	consumer, err := sourcemap.Parse("", []byte(sourcemapStr))
 	if err != nil {
-		s.add(key, nil)
-		return nil, errors.Wrap(err, errMsgParseSourcemap)
+		return nil, true, errors.Wrap(err, errMsgParseSourcemap)
 	}
-	s.add(key, consumer)
-	return consumer, nil
+	return consumer, true, nil
 }
 
 // Added ensures the internal cache is cleared for the given parameters. This should be called when a sourcemap is uploaded.
 func (s *Store) Added(ctx context.Context, name string, version string, path string) {
-	if sourcemap, err := s.Fetch(ctx, name, version, path); err == nil && sourcemap != nil {
+	tenant := utility.Tenant(ctx)
+	key := cacheKey(tenant, name, version, path)
+	consumer, found := s.cached(key)
+	if !found {
+		var cache bool
+		if consumer, cache, _ = s.fetch(ctx, tenant, name, version, path); cache {
+			s.add(key, consumer)
+		}
+	}
+	if consumer != nil {
 		s.logger.Warnf("Overriding sourcemap for service %s version %s and file %s",
 			name, version, path)
 	}
-	key := key([]string{name, version, path})
+
+	// Prevent any background fetch from caching the replaced sourcemap.
+	s.mu.Lock()
+	delete(s.inflight, key)
+	s.mu.Unlock()
+
 	s.cache.Delete(key)
 	if !s.logger.IsDebug() {
 		return
@@ -110,6 +203,58 @@ func (s *Store) Added(ctx context.Context, name string, version string, path str
 	s.logger.Debugf("Removed id %v. Cache now has %v entries.", key, s.cache.ItemCount())
 }
 
+// List returns the sourcemaps uploaded for the given service name and version,
+// most recently uploaded first. An empty name or version matches any. Like
+// Fetch, List is scoped by the tenant carried by ctx.
+func (s *Store) List(ctx context.Context, name, version string) ([]Metadata, error) {
+	return s.esStore.list(ctx, utility.Tenant(ctx), name, version)
+}
+
+// Delete deletes the sourcemaps uploaded for the given service name and version,
+// limited to the given bundle filepath if it is non-empty, and clears them from
+// the internal cache. Delete returns the number of sourcemaps deleted. Like
+// Fetch, Delete is scoped by the tenant carried by ctx.
+func (s *Store) Delete(ctx context.Context, name, version, path string) (int, error) {
+	tenant := utility.Tenant(ctx)
+	deleted, err := s.esStore.delete(ctx, tenant, name, version, path)
+	if err != nil {
+		return 0, err
+	}
+
+	// Cached sourcemaps are keyed by the bundle filepath of events,
+	// which may differ from the uploaded bundle filepath, so all
+	// sourcemaps cached for the service version are cleared.
+	// Without a tenant, the sourcemaps of all tenants are deleted.
+	prefix := key([]string{name, version, ""})
+	matches := func(k string) bool {
+		i := strings.IndexByte(k, '/')
+		return (tenant == "" || k[:i] == tenant) && strings.HasPrefix(k[i+1:], prefix)
+	}
+	s.mu.Lock()
+	for k := range s.inflight {
+		if matches(k) {
+			delete(s.inflight, k)
+		}
+	}
+	s.mu.Unlock()
+	for k := range s.cache.Items() {
+		if matches(k) {
+			s.cache.Delete(k)
+		}
+	}
+	s.logger.Infof("Deleted %d sourcemaps for service %s version %s", deleted, name, version)
+	return deleted, nil
+}
+
+func (s *Store) cached(key string) (*sourcemap.Consumer, bool) {
+	val, found := s.cache.Get(key)
+	if !found {
+		return nil, false
+	}
+	consumer, _ := val.(*sourcemap.Consumer)
+	return consumer, true
+}
+
 func (s *Store) add(key string, consumer *sourcemap.Consumer) {
 	s.cache.SetDefault(key, consumer)
 	if !s.logger.IsDebug() {
@@ -122,6 +267,12 @@ func key(s []string) string {
 	return strings.Join(s, "_")
 }
 
+// cacheKey returns the key of a cached sourcemap. Tenant IDs
+// cannot contain "/", so the key is prefixed with the tenant.
+func cacheKey(tenant, name, version, path string) string {
+	return tenant + "/" + key([]string{name, version, path})
+}
+
 func cleanupInterval(ttl time.Duration) time.Duration {
 	return time.Duration(math.Max(ttl.Seconds(), minCleanupIntervalSeconds)) * time.Second
 }
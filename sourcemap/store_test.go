@@ -19,7 +19,9 @@ package sourcemap
 
 import (
 	"context"
+	"encoding/json"
 	"fmt"
+	"net/http"
 	"testing"
 	"time"
 
@@ -29,8 +31,10 @@ import (
 	"github.com/stretchr/testify/require"
 
 	"github.com/elastic/apm-server/elasticsearch"
+	"github.com/elastic/apm-server/elasticsearch/estest"
 
 	"github.com/elastic/apm-server/sourcemap/test"
+	"github.com/elastic/apm-server/utility"
 )
 
 func Test_NewStore(t *testing.T) {
@@ -44,7 +48,7 @@ func Test_NewStore(t *testing.T) {
 
 func TestStore_Fetch(t *testing.T) {
 	serviceName, serviceVersion, path := "foo", "1.0.1", "/tmp"
-	key := "foo_1.0.1_/tmp"
+	key := "/foo_1.0.1_/tmp"
 
 	t.Run("cache", func(t *testing.T) {
 		t.Run("nil", func(t *testing.T) {
@@ -81,6 +85,17 @@ func TestStore_Fetch(t *testing.T) {
 		assert.Equal(t, mapper, cached)
 	})
 
+	t.Run("cacheMetrics", func(t *testing.T) {
+		hits, misses := cacheHits.Get(), cacheMisses.Get()
+		store := testStore(t, test.ESClientWithValidSourcemap(t))
+		for i := 0; i < 3; i++ {
+			_, err := store.Fetch(context.Background(), serviceName, serviceVersion, path)
+			require.NoError(t, err)
+		}
+		assert.Equal(t, hits+2, cacheHits.Get())
+		assert.Equal(t, misses+1, cacheMisses.Get())
+	})
+
 	t.Run("notFoundInES", func(t *testing.T) {
 
 		store := testStore(t, test.ESClientWithSourcemapNotFound(t))
@@ -144,7 +159,7 @@ func TestStore_Fetch(t *testing.T) {
 
 func TestStore_Added(t *testing.T) {
 	name, version, path := "foo", "1.0.1", "/tmp"
-	key := "foo_1.0.1_/tmp"
+	key := "/foo_1.0.1_/tmp"
 
 	// setup
 	// remove empty sourcemap from cache, and valid one with File() == "bundle.js" from Elasticsearch
@@ -167,7 +182,7 @@ func TestStore_Added(t *testing.T) {
 func TestExpiration(t *testing.T) {
 	store := testStore(t, test.ESClientUnavailable(t)) //if ES was queried it would return an error
 	store.cache = gocache.New(25*time.Millisecond, 100)
-	store.add("foo_1.0.1_/tmp", &sourcemap.Consumer{})
+	store.add("/foo_1.0.1_/tmp", &sourcemap.Consumer{})
 	name, version, path := "foo", "1.0.1", "/tmp"
 
 	// sourcemap is cached
@@ -182,6 +197,193 @@ func TestExpiration(t *testing.T) {
 	assert.Nil(t, mapper)
 }
 
+func TestStore_FetchWaitTimeout(t *testing.T) {
+	name, version, path := "foo", "1.0.1", "/tmp"
+	unblock := make(chan struct{})
+	var requests int
+	transport := roundTripperFunc(func(req *http.Request) (*http.Response, error) {
+		requests++
+		<-unblock
+		return estest.NewTransport(t, http.StatusOK, nil).RoundTrip(req)
+	})
+	client, err := elasticsearch.NewVersionedClient("", "", "", []string{}, transport)
+	require.NoError(t, err)
+	store := testStore(t, client)
+	store.WaitTimeout = 50 * time.Millisecond
+
+	// The first fetch waits for up to WaitTimeout.
+	mapper, err := store.Fetch(context.Background(), name, version, path)
+	assert.Equal(t, errFetchTimeout, err)
+	assert.Nil(t, mapper)
+
+	// Subsequent fetches share the deadline of the in-flight
+	// fetch, and so fail immediately.
+	before := time.Now()
+	mapper, err = store.Fetch(context.Background(), name, version, path)
+	assert.Equal(t, errFetchTimeout, err)
+	assert.Nil(t, mapper)
+	assert.True(t, time.Since(before) < store.WaitTimeout)
+
+	// Once the background fetch completes, its result is cached.
+	close(unblock)
+	for {
+		if _, found := store.cached(cacheKey("", name, version, path)); found {
+			break
+		}
+		time.Sleep(time.Millisecond)
+	}
+	mapper, err = store.Fetch(context.Background(), name, version, path)
+	assert.NoError(t, err)
+	assert.Nil(t, mapper)
+	assert.Equal(t, 1, requests)
+}
+
+func TestStore_AddedDuringFetch(t *testing.T) {
+	name, version, path := "foo", "1.0.1", "/tmp"
+	unblock := make(chan struct{})
+	transport := roundTripperFunc(func(req *http.Request) (*http.Response, error) {
+		<-unblock
+		return estest.NewTransport(t, http.StatusOK, nil).RoundTrip(req)
+	})
+	client, err := elasticsearch.NewVersionedClient("", "", "", []string{}, transport)
+	require.NoError(t, err)
+	store := testStore(t, client)
+	store.WaitTimeout = time.Millisecond
+
+	_, err = store.Fetch(context.Background(), name, version, path)
+	assert.Equal(t, errFetchTimeout, err)
+	store.mu.Lock()
+	f := store.inflight[cacheKey("", name, version, path)]
+	store.mu.Unlock()
+	require.NotNil(t, f)
+
+	// A sourcemap uploaded while a fetch is in flight must
+	// not be hidden by the result of the earlier fetch.
+	close(unblock)
+	store.Added(context.Background(), name, version, path)
+	<-f.done
+	_, found := store.cached(cacheKey("", name, version, path))
+	assert.False(t, found)
+}
+
+func TestStore_List(t *testing.T) {
+	var query map[string]interface{}
+	transport := roundTripperFunc(func(req *http.Request) (*http.Response, error) {
+		require.NoError(t, json.NewDecoder(req.Body).Decode(&query))
+		return estest.NewTransport(t, http.StatusOK, map[string]interface{}{
+			"hits": map[string]interface{}{
+				"hits": []map[string]interface{}{{
+					"_id": "abc",
+					"_source": map[string]interface{}{
+						"@timestamp": "2020-06-01T10:00:00Z",
+						"sourcemap": map[string]interface{}{
+							"bundle_filepath": "/js/bundle.js",
+							"service":         map[string]interface{}{"name": "foo", "version": "1.0.1"},
+						},
+					},
+				}},
+			},
+		}).RoundTrip(req)
+	})
+	client, err := elasticsearch.NewVersionedClient("", "", "", []string{}, transport)
+	require.NoError(t, err)
+
+	sourcemaps, err := testStore(t, client).List(context.Background(), "foo", "")
+	require.NoError(t, err)
+	assert.Equal(t, []Metadata{{
+		ID:             "abc",
+		ServiceName:    "foo",
+		ServiceVersion: "1.0.1",
+		BundleFilepath: "/js/bundle.js",
+		Uploaded:       time.Date(2020, 6, 1, 10, 0, 0, 0, time.UTC),
+	}}, sourcemaps)
+	assert.Equal(t, map[string]interface{}{
+		"bool": map[string]interface{}{
+			"must": []interface{}{
+				map[string]interface{}{"term": map[string]interface{}{"processor.name": "sourcemap"}},
+				map[string]interface{}{"term": map[string]interface{}{"sourcemap.service.name": "foo"}},
+			},
+		},
+	}, query["query"])
+}
+
+func TestStore_Delete(t *testing.T) {
+	name, version, path := "foo", "1.0.1", "/tmp"
+	var requestPath string
+	transport := roundTripperFunc(func(req *http.Request) (*http.Response, error) {
+		requestPath = req.URL.Path
+		return estest.NewTransport(t, http.StatusOK, map[string]interface{}{"deleted": 2}).RoundTrip(req)
+	})
+	client, err := elasticsearch.NewVersionedClient("", "", "", []string{}, transport)
+	require.NoError(t, err)
+	store := testStore(t, client)
+	store.add(cacheKey("", name, version, path), nil)
+	store.add(cacheKey("", name, "2.0.0", path), nil)
+
+	deleted, err := store.Delete(context.Background(), name, version, "")
+	require.NoError(t, err)
+	assert.Equal(t, 2, deleted)
+	assert.Equal(t, "/apm-*sourcemap*/_delete_by_query", requestPath)
+
+	_, found := store.cached(cacheKey("", name, version, path))
+	assert.False(t, found)
+	_, found = store.cached(cacheKey("", name, "2.0.0", path))
+	assert.True(t, found)
+}
+
+func TestStore_Tenant(t *testing.T) {
+	var query map[string]interface{}
+	transport := roundTripperFunc(func(req *http.Request) (*http.Response, error) {
+		require.NoError(t, json.NewDecoder(req.Body).Decode(&query))
+		return estest.NewTransport(t, http.StatusOK, map[string]interface{}{"deleted": 1}).RoundTrip(req)
+	})
+	client, err := elasticsearch.NewVersionedClient("", "", "", []string{}, transport)
+	require.NoError(t, err)
+	store := testStore(t, client)
+	name, version, path := "foo", "1.0.1", "/tmp"
+	store.add(cacheKey("", name, version, path), &sourcemap.Consumer{})
+	store.add(cacheKey("team-b", name, version, path), &sourcemap.Consumer{})
+
+	// Sourcemaps cached for other tenants are not used.
+	ctx := utility.ContextWithTenant(context.Background(), "team-a")
+	mapper, err := store.Fetch(ctx, name, version, path)
+	require.NoError(t, err)
+	assert.Nil(t, mapper)
+	tenantTerm := map[string]interface{}{"term": map[string]interface{}{"tenant.id": "team-a"}}
+	assert.Contains(t, query["query"].(map[string]interface{})["bool"].(map[string]interface{})["must"], tenantTerm)
+
+	store.add(cacheKey("team-a", name, version, path), &sourcemap.Consumer{})
+	_, err = store.Delete(ctx, name, version, "")
+	require.NoError(t, err)
+	assert.Contains(t, query["query"].(map[string]interface{})["bool"].(map[string]interface{})["must"], tenantTerm)
+	_, found := store.cached(cacheKey("team-a", name, version, path))
+	assert.False(t, found)
+	_, found = store.cached(cacheKey("team-b", name, version, path))
+	assert.True(t, found)
+
+	// Without a tenant, the sourcemaps of all tenants are deleted.
+	_, err = store.Delete(context.Background(), name, version, "")
+	require.NoError(t, err)
+	_, found = store.cached(cacheKey("team-b", name, version, path))
+	assert.False(t, found)
+	_, found = store.cached(cacheKey("", name, version, path))
+	assert.False(t, found)
+}
+
+func TestStore_DeleteError(t *testing.T) {
+	client, err := estest.NewElasticsearchClient(estest.NewTransport(t, http.StatusInternalServerError, nil))
+	require.NoError(t, err)
+	_, err = testStore(t, client).Delete(context.Background(), "foo", "1.0.1", "")
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), errMsgESFailure)
+}
+
+type roundTripperFunc func(*http.Request) (*http.Response, error)
+
+func (f roundTripperFunc) RoundTrip(req *http.Request) (*http.Response, error) {
+	return f(req)
+}
+
 func TestCleanupInterval(t *testing.T) {
 	tests := []struct {
 		ttl      time.Duration
@@ -0,0 +1,90 @@
+// Licensed to Elasticsearch B.V. under one or more contributor
+// license agreements. See the NOTICE file distributed with
+// this work for additional information regarding copyright
+// ownership. Elasticsearch B.V. licenses this file to you under
+// the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package stacktrace
+
+import (
+	"context"
+	"time"
+
+	"github.com/elastic/beats/v7/libbeat/monitoring"
+
+	"github.com/elastic/apm-server/model"
+	"github.com/elastic/apm-server/publish"
+)
+
+var (
+	monitoringRegistry = monitoring.Default.NewRegistry("apm-server.stacktrace")
+	droppedCounter     = monitoring.NewInt(monitoringRegistry, "span.dropped")
+	truncatedCounter   = monitoring.NewInt(monitoringRegistry, "span.truncated")
+	framesCounter      = monitoring.NewInt(monitoringRegistry, "span.frames_dropped")
+)
+
+// Config holds configuration for NewSpanReporter.
+type Config struct {
+	// MinSpanDuration is the minimum duration of spans whose stacktraces
+	// are indexed. Stacktraces of shorter spans are dropped. Zero means
+	// stacktraces are kept regardless of span duration.
+	MinSpanDuration time.Duration
+
+	// MaxFrames is the maximum number of frames indexed for a span's
+	// stacktrace. Frames beyond this are dropped, keeping the innermost
+	// frames. Zero means unlimited.
+	MaxFrames int
+}
+
+// NewSpanReporter returns a publish.Reporter which drops or truncates span
+// stacktraces according to cfg, before deferring to reporter.
+//
+// Agents do not consistently honour their own minimum span duration for
+// capturing stacktraces, and stacktraces may dominate the size of span
+// documents, so the server applies its own limits.
+func NewSpanReporter(cfg Config, reporter publish.Reporter) publish.Reporter {
+	minDuration := float64(cfg.MinSpanDuration) / float64(time.Millisecond)
+	return func(ctx context.Context, req publish.PendingReq) error {
+		var dropped, truncated, frames int64
+		for _, event := range req.Transformables {
+			span, ok := event.(*model.Span)
+			if !ok || len(span.Stacktrace) == 0 {
+				continue
+			}
+			if span.Duration < minDuration {
+				dropped++
+				frames += int64(len(span.Stacktrace))
+				span.Stacktrace = nil
+				continue
+			}
+			if cfg.MaxFrames > 0 && len(span.Stacktrace) > cfg.MaxFrames {
+				truncated++
+				frames += int64(len(span.Stacktrace) - cfg.MaxFrames)
+				// Stacktraces may be shared, so reslice rather than
+				// modifying the underlying array.
+				span.Stacktrace = span.Stacktrace[:cfg.MaxFrames:cfg.MaxFrames]
+			}
+		}
+		if dropped > 0 {
+			droppedCounter.Add(dropped)
+		}
+		if truncated > 0 {
+			truncatedCounter.Add(truncated)
+		}
+		if frames > 0 {
+			framesCounter.Add(frames)
+		}
+		return reporter(ctx, req)
+	}
+}
@@ -0,0 +1,91 @@
+// Licensed to Elasticsearch B.V. under one or more contributor
+// license agreements. See the NOTICE file distributed with
+// this work for additional information regarding copyright
+// ownership. Elasticsearch B.V. licenses this file to you under
+// the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package stacktrace
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/elastic/beats/v7/libbeat/monitoring"
+
+	"github.com/elastic/apm-server/model"
+	"github.com/elastic/apm-server/publish"
+	"github.com/elastic/apm-server/transform"
+)
+
+func TestSpanReporterMinSpanDuration(t *testing.T) {
+	short := &model.Span{Duration: 4.5, Stacktrace: frames(3)}
+	long := &model.Span{Duration: 5, Stacktrace: frames(3)}
+	errorEvent := &model.Error{Log: &model.Log{Stacktrace: frames(3)}}
+	before := snapshot()
+	report(t, Config{MinSpanDuration: 5 * time.Millisecond}, short, long, errorEvent)
+
+	assert.Nil(t, short.Stacktrace)
+	assert.Len(t, long.Stacktrace, 3)
+	assert.Len(t, errorEvent.Log.Stacktrace, 3)
+
+	after := snapshot()
+	assert.Equal(t, int64(1), after["span.dropped"]-before["span.dropped"])
+	assert.Equal(t, int64(0), after["span.truncated"]-before["span.truncated"])
+	assert.Equal(t, int64(3), after["span.frames_dropped"]-before["span.frames_dropped"])
+}
+
+func TestSpanReporterMaxFrames(t *testing.T) {
+	shared := frames(5)
+	span := &model.Span{Duration: 10, Stacktrace: shared}
+	small := &model.Span{Duration: 10, Stacktrace: frames(2)}
+	before := snapshot()
+	report(t, Config{MaxFrames: 3}, span, small)
+
+	require.Len(t, span.Stacktrace, 3)
+	assert.Equal(t, shared[:3], span.Stacktrace)
+	assert.Len(t, small.Stacktrace, 2)
+	assert.Len(t, shared, 5)
+
+	after := snapshot()
+	assert.Equal(t, int64(0), after["span.dropped"]-before["span.dropped"])
+	assert.Equal(t, int64(1), after["span.truncated"]-before["span.truncated"])
+	assert.Equal(t, int64(2), after["span.frames_dropped"]-before["span.frames_dropped"])
+}
+
+func frames(n int) model.Stacktrace {
+	st := make(model.Stacktrace, n)
+	for i := range st {
+		st[i] = &model.StacktraceFrame{}
+	}
+	return st
+}
+
+func report(t *testing.T, cfg Config, events ...transform.Transformable) {
+	var reported []transform.Transformable
+	reporter := NewSpanReporter(cfg, func(ctx context.Context, req publish.PendingReq) error {
+		reported = req.Transformables
+		return nil
+	})
+	err := reporter(context.Background(), publish.PendingReq{Transformables: events})
+	require.NoError(t, err)
+	assert.Equal(t, events, reported)
+}
+
+func snapshot() map[string]int64 {
+	return monitoring.CollectFlatSnapshot(monitoringRegistry, monitoring.Full, false).Ints
+}
@@ -0,0 +1,180 @@
+// Licensed to Elasticsearch B.V. under one or more contributor
+// license agreements. See the NOTICE file distributed with
+// this work for additional information regarding copyright
+// ownership. Elasticsearch B.V. licenses this file to you under
+// the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+// +build systemtest
+
+package systemtest
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io/ioutil"
+	"net"
+	"net/http"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+const serverStartupTimeout = 30 * time.Second
+
+// serverBinary is the path of the apm-server binary built by BuildServer.
+var serverBinary = filepath.Join(repoRoot(), "build", "systemtest", "apm-server")
+
+// BuildServer builds the apm-server binary used by the tests.
+func BuildServer() error {
+	cmd := exec.Command("go", "build", "-o", serverBinary, "./x-pack/apm-server")
+	cmd.Dir = repoRoot()
+	cmd.Stdout = os.Stderr
+	cmd.Stderr = os.Stderr
+	if err := cmd.Run(); err != nil {
+		return fmt.Errorf("building apm-server failed: %w", err)
+	}
+	return nil
+}
+
+// Server is an apm-server process, sending events to Elasticsearch at ElasticsearchURL.
+type Server struct {
+	// URL is the base URL of the server.
+	URL string
+
+	cmd     *exec.Cmd
+	dataDir string
+	done    chan struct{}
+
+	mu  sync.Mutex
+	log bytes.Buffer
+}
+
+// StartServer starts apm-server with the given additional settings, given as
+// "key=value" pairs, and waits until it is ready to accept requests.
+// The server must be closed after use.
+func StartServer(t testing.TB, settings ...string) *Server {
+	addr := freeAddr(t)
+	dataDir, err := ioutil.TempDir("", "apm-server-systemtest")
+	require.NoError(t, err)
+
+	args := []string{
+		"-e",
+		"-c", filepath.Join(repoRoot(), "apm-server.yml"),
+		"--path.home", repoRoot(),
+		"--path.data", dataDir,
+		"-E", "apm-server.host=" + addr,
+		"-E", fmt.Sprintf("output.elasticsearch.hosts=[%q]", ElasticsearchURL),
+		"-E", "output.elasticsearch.username=" + ElasticsearchUser,
+		"-E", "output.elasticsearch.password=" + ElasticsearchPass,
+		"-E", "queue.mem.flush.min_events=0",
+	}
+	for _, s := range settings {
+		args = append(args, "-E", s)
+	}
+
+	s := &Server{URL: "http://" + addr, dataDir: dataDir, done: make(chan struct{})}
+	s.cmd = exec.Command(serverBinary, args...)
+	s.cmd.Stdout = s
+	s.cmd.Stderr = s
+	require.NoError(t, s.cmd.Start())
+	go func() {
+		s.cmd.Wait()
+		close(s.done)
+	}()
+
+	deadline := time.Now().Add(serverStartupTimeout)
+	for {
+		resp, err := http.Get(s.URL)
+		if err == nil {
+			resp.Body.Close()
+			if resp.StatusCode == http.StatusOK {
+				return s
+			}
+		}
+		select {
+		case <-s.done:
+			s.Close()
+			require.FailNow(t, "apm-server exited", s.Log())
+		case <-time.After(100 * time.Millisecond):
+		}
+		if time.Now().After(deadline) {
+			s.Close()
+			require.FailNow(t, "apm-server not ready", "not ready after %s:\n%s", serverStartupTimeout, s.Log())
+		}
+	}
+}
+
+// Write collects the output of the server process.
+func (s *Server) Write(p []byte) (int, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.log.Write(p)
+}
+
+// Log returns the output of the server process so far.
+func (s *Server) Log() string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.log.String()
+}
+
+// Close stops the server, and removes its data directory.
+func (s *Server) Close() error {
+	defer os.RemoveAll(s.dataDir)
+	select {
+	case <-s.done:
+		return nil
+	default:
+	}
+	if err := s.cmd.Process.Signal(os.Interrupt); err != nil {
+		return err
+	}
+	select {
+	case <-s.done:
+	case <-time.After(10 * time.Second):
+		s.cmd.Process.Kill()
+		<-s.done
+	}
+	return nil
+}
+
+// PostEvents sends the events stored in the fixture file to the intake endpoint at path,
+// and checks they are accepted.
+func (s *Server) PostEvents(t testing.TB, path, fixture string) {
+	data, err := ioutil.ReadFile(filepath.Join(repoRoot(), fixture))
+	require.NoError(t, err)
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, s.URL+path, bytes.NewReader(data))
+	require.NoError(t, err)
+	req.Header.Set("Content-Type", "application/x-ndjson")
+	resp, err := http.DefaultClient.Do(req)
+	require.NoError(t, err)
+	defer resp.Body.Close()
+	body, _ := ioutil.ReadAll(resp.Body)
+	require.Equal(t, http.StatusAccepted, resp.StatusCode, string(body))
+}
+
+func freeAddr(t testing.TB) string {
+	l, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	defer l.Close()
+	return l.Addr().String()
+}
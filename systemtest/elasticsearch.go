@@ -0,0 +1,167 @@
+// Licensed to Elasticsearch B.V. under one or more contributor
+// license agreements. See the NOTICE file distributed with
+// this work for additional information regarding copyright
+// ownership. Elasticsearch B.V. licenses this file to you under
+// the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+// +build systemtest
+
+// Package systemtest holds end-to-end tests, running apm-server against
+// Elasticsearch and asserting on the documents it indexes.
+//
+// Elasticsearch is started with docker-compose, unless it is already reachable
+// at ES_URL, and apm-server is built from source. Run the tests with:
+//
+//     go test -tags=systemtest -v ./systemtest
+package systemtest
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/elastic/go-elasticsearch/v7/esapi"
+
+	"github.com/elastic/apm-server/elasticsearch"
+)
+
+var (
+	// ElasticsearchURL is the URL of the Elasticsearch cluster used by the tests.
+	ElasticsearchURL = getenvDefault("ES_URL", "http://localhost:9200")
+	// ElasticsearchUser and ElasticsearchPass are the credentials used by apm-server and the tests.
+	ElasticsearchUser = getenvDefault("ES_SUPERUSER_USER", "admin")
+	ElasticsearchPass = getenvDefault("ES_SUPERUSER_PASS", "changeme")
+
+	// Elasticsearch is a client for the cluster at ElasticsearchURL.
+	Elasticsearch elasticsearch.Client
+)
+
+const stackStartupTimeout = 5 * time.Minute
+
+// StartStack starts Elasticsearch with docker-compose, unless it is already reachable,
+// and waits for the cluster to be healthy.
+func StartStack() error {
+	client, err := elasticsearch.NewVersionedClient("", ElasticsearchUser, ElasticsearchPass,
+		[]string{ElasticsearchURL}, http.DefaultTransport)
+	if err != nil {
+		return err
+	}
+	Elasticsearch = client
+	if clusterHealthy(context.Background()) {
+		return nil
+	}
+
+	cmd := exec.Command("docker-compose", "up", "-d", "elasticsearch")
+	cmd.Dir = repoRoot()
+	cmd.Stdout = os.Stderr
+	cmd.Stderr = os.Stderr
+	if err := cmd.Run(); err != nil {
+		return fmt.Errorf("starting Elasticsearch failed: %w", err)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), stackStartupTimeout)
+	defer cancel()
+	for !clusterHealthy(ctx) {
+		select {
+		case <-ctx.Done():
+			return fmt.Errorf("Elasticsearch at %s not healthy after %s", ElasticsearchURL, stackStartupTimeout)
+		case <-time.After(time.Second):
+		}
+	}
+	return nil
+}
+
+func clusterHealthy(ctx context.Context) bool {
+	req := esapi.ClusterHealthRequest{WaitForStatus: "yellow", Timeout: time.Second}
+	resp, err := req.Do(ctx, Elasticsearch)
+	if err != nil {
+		return false
+	}
+	defer resp.Body.Close()
+	return !resp.IsError()
+}
+
+// CleanupElasticsearch deletes all apm-server indices, so tests don't observe
+// documents indexed by earlier tests.
+func CleanupElasticsearch(t testing.TB) {
+	req := esapi.IndicesDeleteRequest{Index: []string{"apm*"}}
+	resp, err := req.Do(context.Background(), Elasticsearch)
+	require.NoError(t, err)
+	defer resp.Body.Close()
+	require.False(t, resp.IsError(), resp.String())
+}
+
+// SearchResult holds the documents found by a search.
+type SearchResult struct {
+	Hits struct {
+		Total struct {
+			Value int `json:"value"`
+		} `json:"total"`
+		Hits []struct {
+			Index  string                 `json:"_index"`
+			Source map[string]interface{} `json:"_source"`
+		} `json:"hits"`
+	} `json:"hits"`
+}
+
+// Search waits until at least min documents matching query are found in the given indices,
+// and returns them. The test fails if not enough documents are found within timeout.
+func Search(t testing.TB, index string, query map[string]interface{}, min int, timeout time.Duration) SearchResult {
+	body := map[string]interface{}{"size": 1000}
+	if query != nil {
+		body["query"] = query
+	}
+	encoded, err := json.Marshal(body)
+	require.NoError(t, err)
+
+	var result SearchResult
+	deadline := time.Now().Add(timeout)
+	for {
+		status, resp, err := Elasticsearch.SearchQuery(context.Background(), index, strings.NewReader(string(encoded)))
+		require.NoError(t, err)
+		result = SearchResult{}
+		err = json.NewDecoder(resp).Decode(&result)
+		resp.Close()
+		if err == nil && status == http.StatusOK && result.Hits.Total.Value >= min {
+			return result
+		}
+		if time.Now().After(deadline) {
+			require.FailNow(t, "documents not found",
+				"expected at least %d documents in %s within %s, found %d", min, index, timeout, result.Hits.Total.Value)
+		}
+		time.Sleep(100 * time.Millisecond)
+	}
+}
+
+func repoRoot() string {
+	_, current, _, _ := runtime.Caller(0)
+	return filepath.Dir(filepath.Dir(current))
+}
+
+func getenvDefault(key, defaultValue string) string {
+	if v := os.Getenv(key); v != "" {
+		return v
+	}
+	return defaultValue
+}
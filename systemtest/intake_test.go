@@ -0,0 +1,69 @@
+// Licensed to Elasticsearch B.V. under one or more contributor
+// license agreements. See the NOTICE file distributed with
+// this work for additional information regarding copyright
+// ownership. Elasticsearch B.V. licenses this file to you under
+// the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+// +build systemtest
+
+package systemtest
+
+import (
+	"bytes"
+	"io/ioutil"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestIntakeEvents(t *testing.T) {
+	for _, test := range []struct {
+		fixture   string
+		index     string
+		processor string
+	}{
+		{fixture: "errors.ndjson", index: "apm-*-error*", processor: "error"},
+		{fixture: "transactions.ndjson", index: "apm-*-transaction*", processor: "transaction"},
+		{fixture: "spans.ndjson", index: "apm-*-span*", processor: "transaction"},
+		{fixture: "metricsets.ndjson", index: "apm-*-metric*", processor: "metric"},
+	} {
+		t.Run(test.fixture, func(t *testing.T) {
+			CleanupElasticsearch(t)
+			server := StartServer(t)
+			defer server.Close()
+
+			fixture := filepath.Join("testdata", "intake-v2", test.fixture)
+			server.PostEvents(t, "/intake/v2/events", fixture)
+
+			events := countEvents(t, fixture)
+			result := Search(t, test.index, nil, events, time.Minute)
+			for _, hit := range result.Hits.Hits {
+				processor, _ := hit.Source["processor"].(map[string]interface{})
+				assert.Equal(t, test.processor, processor["name"], hit.Index)
+				assert.Contains(t, hit.Source, "@timestamp")
+			}
+		})
+	}
+}
+
+// countEvents returns the number of events in an intake fixture, not counting metadata.
+func countEvents(t testing.TB, fixture string) int {
+	data, err := ioutil.ReadFile(filepath.Join(repoRoot(), fixture))
+	require.NoError(t, err)
+	lines := bytes.Split(bytes.TrimSpace(data), []byte("\n"))
+	return len(lines) - 1
+}
@@ -20,6 +20,7 @@ package approvals
 import (
 	"encoding/json"
 	"errors"
+	"flag"
 	"fmt"
 	"os"
 	"path/filepath"
@@ -39,6 +40,9 @@ const ApprovedSuffix = ".approved.json"
 // ReceivedSuffix signals a file has changed and not yet been approved
 const ReceivedSuffix = ".received.json"
 
+var update = flag.Bool("update", false,
+	"update approved files with received data, rather than failing on differences")
+
 // AssertApproveResult tests that given result equals an already approved result, and fails otherwise.
 func AssertApproveResult(t *testing.T, name string, actualResult []byte) {
 	var resultmap map[string]interface{}
@@ -78,21 +82,38 @@ func ApproveJSON(received map[string]interface{}, name string, ignored ...string
 	data, _ := json.Marshal(received)
 	json.Unmarshal(data, &received)
 
-	if diff := CompareObjects(received, approved, ignored...); diff != "" {
-		f, err := os.OpenFile(path+ReceivedSuffix, os.O_WRONLY|os.O_CREATE|os.O_TRUNC, 0644)
-		if err != nil {
+	diff := CompareObjects(received, approved, ignored...)
+	if diff == "" {
+		return nil
+	}
+	if *update {
+		if err := writeJSON(path+ApprovedSuffix, received); err != nil {
 			return err
 		}
-		defer f.Close()
-
-		enc := json.NewEncoder(f)
-		enc.SetIndent("", "    ")
-		if err := enc.Encode(received); err != nil {
+		if err := os.Remove(path + ReceivedSuffix); err != nil && !os.IsNotExist(err) {
 			return err
 		}
-		return errors.New("received data differs from approved data. Run 'make update check-approvals' to verify the diff")
+		return nil
+	}
+	if err := writeJSON(path+ReceivedSuffix, received); err != nil {
+		return err
 	}
-	return nil
+	return errors.New("received data differs from approved data (-approved +received):\n" + diff +
+		"\nRun 'make check-approvals' to verify the diff, or 'make update-approvals' to accept all changes")
+}
+
+// writeJSON writes the given data to path, indented and with sorted keys,
+// so the output is deterministic.
+func writeJSON(path string, data interface{}) error {
+	f, err := os.OpenFile(path, os.O_WRONLY|os.O_CREATE|os.O_TRUNC, 0644)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	enc := json.NewEncoder(f)
+	enc.SetIndent("", "    ")
+	return enc.Encode(data)
 }
 
 // Compare compares given data to approved data and returns diff if not equal.
@@ -0,0 +1,66 @@
+// Licensed to Elasticsearch B.V. under one or more contributor
+// license agreements. See the NOTICE file distributed with
+// this work for additional information regarding copyright
+// ownership. Elasticsearch B.V. licenses this file to you under
+// the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package approvals
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestApproveJSON(t *testing.T) {
+	dir, err := ioutil.TempDir("", "approvals")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+	cwd, err := os.Getwd()
+	require.NoError(t, err)
+	require.NoError(t, os.Chdir(dir))
+	defer os.Chdir(cwd)
+
+	received := map[string]interface{}{"b": "received", "a": 1}
+
+	// without approved data, the received data are written and the diff is reported
+	err = ApproveJSON(received, "test")
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), `"received"`)
+	assert.FileExists(t, filepath.Join(dir, "test"+ReceivedSuffix))
+	assert.NoFileExists(t, filepath.Join(dir, "test"+ApprovedSuffix))
+
+	// in update mode, the received data are approved
+	*update = true
+	defer func() { *update = false }()
+	require.NoError(t, ApproveJSON(received, "test"))
+	assert.NoFileExists(t, filepath.Join(dir, "test"+ReceivedSuffix))
+	approved, err := ioutil.ReadFile(filepath.Join(dir, "test"+ApprovedSuffix))
+	require.NoError(t, err)
+	assert.Equal(t, "{\n    \"a\": 1,\n    \"b\": \"received\"\n}\n", string(approved))
+
+	*update = false
+	assert.NoError(t, ApproveJSON(received, "test"))
+}
+
+func TestCompareObjectsIgnored(t *testing.T) {
+	received := map[string]interface{}{"a": 1, "ts": "now"}
+	approved := map[string]interface{}{"a": 1, "ts": "before"}
+	assert.NotEmpty(t, CompareObjects(received, approved))
+	assert.Empty(t, CompareObjects(received, approved, "ts"))
+}
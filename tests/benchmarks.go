@@ -0,0 +1,120 @@
+// Licensed to Elasticsearch B.V. under one or more contributor
+// license agreements. See the NOTICE file distributed with
+// this work for additional information regarding copyright
+// ownership. Elasticsearch B.V. licenses this file to you under
+// the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package tests
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"sort"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+var (
+	checkBenchmarks = flag.Bool("check-benchmarks", false,
+		"run benchmarks and compare their results against the stored baseline")
+	updateBenchmarks = flag.Bool("update-benchmarks", false,
+		"run benchmarks and store their results as new baseline")
+	benchmarkTolerance = flag.Float64("benchmark-tolerance", 0.25,
+		"relative increase of ns/op over the baseline tolerated before failing")
+)
+
+// memoryTolerance is the relative increase of allocations and bytes allocated
+// per operation tolerated, which unlike timings hardly depend on the machine.
+const memoryTolerance = 0.1
+
+// BenchmarkMeasure holds the per operation results of a benchmark.
+type BenchmarkMeasure struct {
+	NsPerOp     int64 `json:"ns_per_op"`
+	AllocsPerOp int64 `json:"allocs_per_op"`
+	BytesPerOp  int64 `json:"bytes_per_op"`
+}
+
+// BenchmarkBaseline holds benchmark measures, keyed by benchmark name.
+type BenchmarkBaseline map[string]BenchmarkMeasure
+
+// CheckBenchmarkBaseline runs the given benchmarks and compares their results against
+// the baseline stored at path, failing for each regression.
+// Timings are only comparable when recorded on the same machine; store a new baseline
+// with -update-benchmarks when changing machines or accepting a change in performance.
+//
+// Benchmarks only run if enabled with -check-benchmarks or -update-benchmarks,
+// as they take too long for regular test runs.
+func CheckBenchmarkBaseline(t *testing.T, path string, benchmarks map[string]func(*testing.B)) {
+	if !*checkBenchmarks && !*updateBenchmarks {
+		t.Skip("enable with -check-benchmarks or -update-benchmarks")
+	}
+
+	results := make(BenchmarkBaseline, len(benchmarks))
+	for name, fn := range benchmarks {
+		r := testing.Benchmark(fn)
+		require.NotZero(t, r.N, "benchmark %s failed", name)
+		results[name] = BenchmarkMeasure{NsPerOp: r.NsPerOp(), AllocsPerOp: r.AllocsPerOp(), BytesPerOp: r.AllocedBytesPerOp()}
+		t.Logf("%s: %s %s", name, r.String(), r.MemString())
+	}
+
+	if *updateBenchmarks {
+		out, err := json.MarshalIndent(results, "", "  ")
+		require.NoError(t, err)
+		require.NoError(t, ioutil.WriteFile(path, append(out, '\n'), 0644))
+		return
+	}
+
+	data, err := ioutil.ReadFile(path)
+	if os.IsNotExist(err) {
+		t.Fatalf("no benchmark baseline stored at %s, create it with -update-benchmarks", path)
+	}
+	require.NoError(t, err)
+	var baseline BenchmarkBaseline
+	require.NoError(t, json.Unmarshal(data, &baseline))
+	for _, regression := range CompareBenchmarks(baseline, results, *benchmarkTolerance) {
+		t.Error("REGRESSION: " + regression)
+	}
+}
+
+// CompareBenchmarks returns a description of all results regressing from the baseline,
+// given the tolerated relative increase of timings. Results without baseline are ignored.
+func CompareBenchmarks(baseline, results BenchmarkBaseline, timeTolerance float64) []string {
+	var regressions []string
+	check := func(name, unit string, base, result int64, tolerance float64) {
+		if base > 0 && float64(result) > float64(base)*(1+tolerance) {
+			regressions = append(regressions, fmt.Sprintf("%s: %d %s exceeds baseline of %d %s by %.1f%% (tolerance %.0f%%)",
+				name, result, unit, base, unit, 100*(float64(result)/float64(base)-1), 100*tolerance))
+		}
+	}
+	names := make([]string, 0, len(results))
+	for name := range results {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		base, ok := baseline[name]
+		if !ok {
+			continue
+		}
+		result := results[name]
+		check(name, "ns/op", base.NsPerOp, result.NsPerOp, timeTolerance)
+		check(name, "allocs/op", base.AllocsPerOp, result.AllocsPerOp, memoryTolerance)
+		check(name, "B/op", base.BytesPerOp, result.BytesPerOp, memoryTolerance)
+	}
+	return regressions
+}
@@ -0,0 +1,41 @@
+// Licensed to Elasticsearch B.V. under one or more contributor
+// license agreements. See the NOTICE file distributed with
+// this work for additional information regarding copyright
+// ownership. Elasticsearch B.V. licenses this file to you under
+// the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package tests
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCompareBenchmarks(t *testing.T) {
+	baseline := BenchmarkBaseline{
+		"Decode/error": {NsPerOp: 1000, AllocsPerOp: 100, BytesPerOp: 4096},
+		"Decode/span":  {NsPerOp: 1000, AllocsPerOp: 100, BytesPerOp: 4096},
+	}
+	results := BenchmarkBaseline{
+		"Decode/error":       {NsPerOp: 1200, AllocsPerOp: 105, BytesPerOp: 4000},
+		"Decode/span":        {NsPerOp: 1500, AllocsPerOp: 120, BytesPerOp: 4096},
+		"Decode/transaction": {NsPerOp: 9999, AllocsPerOp: 999, BytesPerOp: 9999},
+	}
+	assert.Equal(t, []string{
+		"Decode/span: 1500 ns/op exceeds baseline of 1000 ns/op by 50.0% (tolerance 25%)",
+		"Decode/span: 120 allocs/op exceeds baseline of 100 allocs/op by 20.0% (tolerance 10%)",
+	}, CompareBenchmarks(baseline, results, 0.25))
+	assert.Empty(t, CompareBenchmarks(baseline, baseline, 0))
+}
@@ -56,11 +56,9 @@ func differenceWithGroup(s1 *Set, s2 *Set) *Set {
 		if e2Grp, ok := e2.(group); !ok {
 			continue
 		} else {
-			for _, e1 := range s1.Array() {
-				if e1Str, ok := e1.(string); ok {
-					if strings.HasPrefix(e1Str, e2Grp.str) {
-						s.Remove(e1)
-					}
+			for _, e1 := range s1.Strings() {
+				if strings.HasPrefix(e1, e2Grp.str) {
+					s.Remove(e1)
 				}
 			}
 
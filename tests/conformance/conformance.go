@@ -0,0 +1,293 @@
+// Licensed to Elasticsearch B.V. under one or more contributor
+// license agreements. See the NOTICE file distributed with
+// this work for additional information regarding copyright
+// ownership. Elasticsearch B.V. licenses this file to you under
+// the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+// Package conformance checks events sent by APM agents against the intake API,
+// running canonical event fixtures of each agent through decoding and transformation.
+//
+// Fixtures are laid out as <agent>/<version>.ndjson, holding an intake payload as
+// sent by the given agent version. A snapshot of fixtures is vendored in
+// testdata/agents; fixtures published by agent repositories can be checked by
+// pointing AGENT_FIXTURES_DIR at a directory with the same layout, or by listing
+// their URLs, separated by commas, in AGENT_FIXTURES_URLS.
+package conformance
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/url"
+	"os"
+	"path"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"github.com/pkg/errors"
+
+	"github.com/elastic/apm-server/beater/config"
+	"github.com/elastic/apm-server/processor/stream"
+	"github.com/elastic/apm-server/publish"
+	"github.com/elastic/apm-server/tests/loader"
+	"github.com/elastic/apm-server/transform"
+	"github.com/elastic/apm-server/utility"
+)
+
+const fixtureExt = ".ndjson"
+
+// rumAgentName is the name of the agent sending events to the RUM endpoints.
+const rumAgentName = "rum-js"
+
+// Fixture identifies an intake payload sent by an agent version.
+type Fixture struct {
+	Agent   string
+	Version string
+	Path    string
+}
+
+// Result holds the outcome of checking a fixture.
+//
+// Events counts the events sent, while Accepted counts the events decoded,
+// which may be more as the RUM v3 intake nests spans and metricsets in transactions.
+type Result struct {
+	Fixture
+	Events   int
+	Accepted int
+	Errors   []string
+}
+
+// OK returns whether all events of the fixture were accepted without errors.
+func (r Result) OK() bool {
+	return len(r.Errors) == 0
+}
+
+// Fixtures returns the fixtures found in dir, sorted by agent and version.
+func Fixtures(dir string) ([]Fixture, error) {
+	paths, err := filepath.Glob(filepath.Join(dir, "*", "*"+fixtureExt))
+	if err != nil {
+		return nil, err
+	}
+	fixtures := make([]Fixture, len(paths))
+	for i, path := range paths {
+		fixtures[i] = Fixture{
+			Agent:   filepath.Base(filepath.Dir(path)),
+			Version: strings.TrimSuffix(filepath.Base(path), fixtureExt),
+			Path:    path,
+		}
+	}
+	sort.Slice(fixtures, func(i, j int) bool {
+		if fixtures[i].Agent != fixtures[j].Agent {
+			return fixtures[i].Agent < fixtures[j].Agent
+		}
+		return fixtures[i].Version < fixtures[j].Version
+	})
+	return fixtures, nil
+}
+
+// URLFixtures returns the fixtures published at the given URLs, each of which
+// must end in <agent>/<version>.ndjson.
+func URLFixtures(urls []string) ([]Fixture, error) {
+	fixtures := make([]Fixture, len(urls))
+	for i, u := range urls {
+		parsed, err := url.Parse(u)
+		if err != nil {
+			return nil, err
+		}
+		agent, file := path.Split(parsed.Path)
+		if !strings.HasSuffix(file, fixtureExt) || path.Base(agent) == "/" {
+			return nil, fmt.Errorf("fixture URL %s does not end in <agent>/<version>%s", u, fixtureExt)
+		}
+		fixtures[i] = Fixture{
+			Agent:   path.Base(agent),
+			Version: strings.TrimSuffix(file, fixtureExt),
+			Path:    u,
+		}
+	}
+	return fixtures, nil
+}
+
+// Check runs the fixture through the processor of the intake endpoint the agent sends to,
+// decoding and transforming all events. An error is returned if the fixture cannot be read.
+func Check(f Fixture) (Result, error) {
+	result := Result{Fixture: f}
+	data, err := readFixture(f.Path)
+	if err != nil {
+		return result, err
+	}
+	metadataKey, events, err := scan(data)
+	if err != nil {
+		return result, errors.Wrapf(err, "invalid fixture %s", f.Path)
+	}
+	result.Events = events
+
+	if err := checkAgent(f, metadataKey, data); err != nil {
+		result.Errors = append(result.Errors, err.Error())
+	}
+
+	var transformed int
+	report := func(ctx context.Context, req publish.PendingReq) error {
+		for _, t := range req.Transformables {
+			t.Transform(ctx, req.Tcontext)
+		}
+		transformed += len(req.Transformables)
+		return nil
+	}
+	ctx := utility.ContextWithRequestTime(context.Background(), time.Now())
+	sr := processor(f.Agent, metadataKey).HandleStream(ctx, nil, map[string]interface{}{}, bytes.NewReader(data), report)
+	for _, err := range sr.Errors {
+		result.Errors = append(result.Errors, err.Error())
+	}
+	result.Accepted = sr.Accepted
+	if transformed != sr.Accepted {
+		result.Errors = append(result.Errors, fmt.Sprintf("%d events accepted, but %d transformed", sr.Accepted, transformed))
+	}
+	return result, nil
+}
+
+// Report writes a table of the compatibility of all agent versions checked.
+func Report(w io.Writer, results []Result) error {
+	tw := tabwriter.NewWriter(w, 0, 8, 2, ' ', 0)
+	fmt.Fprintln(tw, "AGENT\tVERSION\tEVENTS\tACCEPTED\tSTATUS")
+	for _, r := range results {
+		status := "ok"
+		if !r.OK() {
+			status = "incompatible: " + strings.Join(r.Errors, "; ")
+		}
+		fmt.Fprintf(tw, "%s\t%s\t%d\t%d\t%s\n", r.Agent, r.Version, r.Events, r.Accepted, status)
+	}
+	return tw.Flush()
+}
+
+var (
+	cfg  = config.DefaultConfig("7.x")
+	tcfg = &transform.Config{
+		LibraryPattern:      regexp.MustCompile(cfg.RumConfig.LibraryPattern),
+		ExcludeFromGrouping: regexp.MustCompile(cfg.RumConfig.ExcludeFromGrouping),
+	}
+)
+
+func processor(agent, metadataKey string) *stream.Processor {
+	switch {
+	case metadataKey == "m":
+		return stream.RUMV3Processor(cfg, tcfg)
+	case agent == rumAgentName:
+		return stream.RUMProcessor(cfg, tcfg)
+	}
+	return stream.BackendProcessor(cfg)
+}
+
+// scan returns the key of the metadata object, identifying the intake version,
+// and the number of events following it.
+func scan(data []byte) (string, int, error) {
+	var metadataKey string
+	var events int
+	scanner := bufio.NewScanner(bytes.NewReader(data))
+	scanner.Buffer(nil, cfg.MaxEventSize)
+	for scanner.Scan() {
+		line := bytes.TrimSpace(scanner.Bytes())
+		if len(line) == 0 {
+			continue
+		}
+		if metadataKey != "" {
+			events++
+			continue
+		}
+		var first map[string]json.RawMessage
+		if err := json.Unmarshal(line, &first); err != nil {
+			return "", 0, err
+		}
+		for k := range first {
+			metadataKey = k
+		}
+		if metadataKey != "metadata" && metadataKey != "m" {
+			return "", 0, errors.New("first line must hold the metadata")
+		}
+	}
+	return metadataKey, events, scanner.Err()
+}
+
+// checkAgent verifies the fixture is filed under the agent name and version it reports.
+func checkAgent(f Fixture, metadataKey string, data []byte) error {
+	var name, version string
+	line := bytes.SplitN(data, []byte("\n"), 2)[0]
+	if metadataKey == "m" {
+		var m struct {
+			M struct {
+				Service struct {
+					Agent struct {
+						Name    string `json:"n"`
+						Version string `json:"ve"`
+					} `json:"a"`
+				} `json:"se"`
+			} `json:"m"`
+		}
+		if err := json.Unmarshal(line, &m); err != nil {
+			return err
+		}
+		name, version = m.M.Service.Agent.Name, m.M.Service.Agent.Version
+	} else {
+		var m struct {
+			Metadata struct {
+				Service struct {
+					Agent struct{ Name, Version string } `json:"agent"`
+				} `json:"service"`
+			} `json:"metadata"`
+		}
+		if err := json.Unmarshal(line, &m); err != nil {
+			return err
+		}
+		name, version = m.Metadata.Service.Agent.Name, m.Metadata.Service.Agent.Version
+	}
+	if name != f.Agent || version != f.Version {
+		return fmt.Errorf("metadata reports agent %s %s", name, version)
+	}
+	return nil
+}
+
+func readFixture(p string) ([]byte, error) {
+	if strings.HasPrefix(p, "http://") || strings.HasPrefix(p, "https://") {
+		return loader.LoadDataFromURL(p)
+	}
+	return ioutil.ReadFile(p)
+}
+
+// Dirs returns the directories to load fixtures from: the vendored snapshot
+// and the directory given by AGENT_FIXTURES_DIR, if set.
+func Dirs(vendored string) []string {
+	dirs := []string{vendored}
+	if dir := os.Getenv("AGENT_FIXTURES_DIR"); dir != "" {
+		dirs = append(dirs, dir)
+	}
+	return dirs
+}
+
+// URLs returns the fixture URLs given by AGENT_FIXTURES_URLS, if set.
+func URLs() []string {
+	var urls []string
+	for _, u := range strings.Split(os.Getenv("AGENT_FIXTURES_URLS"), ",") {
+		if u = strings.TrimSpace(u); u != "" {
+			urls = append(urls, u)
+		}
+	}
+	return urls
+}
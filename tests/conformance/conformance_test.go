@@ -0,0 +1,115 @@
+// Licensed to Elasticsearch B.V. under one or more contributor
+// license agreements. See the NOTICE file distributed with
+// this work for additional information regarding copyright
+// ownership. Elasticsearch B.V. licenses this file to you under
+// the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package conformance
+
+import (
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/elastic/apm-server/tests/loader"
+)
+
+func TestAgentFixtures(t *testing.T) {
+	var fixtures []Fixture
+	for _, dir := range Dirs(filepath.Join("..", "..", "testdata", "agents")) {
+		dirFixtures, err := Fixtures(dir)
+		require.NoError(t, err)
+		require.NotEmpty(t, dirFixtures, "no fixtures found in %s", dir)
+		fixtures = append(fixtures, dirFixtures...)
+	}
+	urlFixtures, err := URLFixtures(URLs())
+	require.NoError(t, err)
+	fixtures = append(fixtures, urlFixtures...)
+
+	var results []Result
+	for _, f := range fixtures {
+		t.Run(f.Agent+"/"+f.Version, func(t *testing.T) {
+			result, err := Check(f)
+			require.NoError(t, err)
+			assert.NotZero(t, result.Events)
+			assert.True(t, result.OK(), "%s %s: %v", f.Agent, f.Version, result.Errors)
+			results = append(results, result)
+		})
+	}
+
+	var report strings.Builder
+	require.NoError(t, Report(&report, results))
+	t.Log("\n" + report.String())
+}
+
+func TestCheckAgentMismatch(t *testing.T) {
+	fixtures, err := Fixtures(filepath.Join("..", "..", "testdata", "agents"))
+	require.NoError(t, err)
+	require.NotEmpty(t, fixtures)
+
+	f := fixtures[0]
+	f.Version = "0.0.1"
+	result, err := Check(f)
+	require.NoError(t, err)
+	assert.False(t, result.OK())
+	assert.Contains(t, result.Errors, "metadata reports agent "+fixtures[0].Agent+" "+fixtures[0].Version)
+}
+
+func TestReport(t *testing.T) {
+	var report strings.Builder
+	require.NoError(t, Report(&report, []Result{
+		{Fixture: Fixture{Agent: "go", Version: "1.8.0"}, Events: 3, Accepted: 3},
+		{Fixture: Fixture{Agent: "java", Version: "1.0.0"}, Events: 2, Accepted: 1, Errors: []string{"invalid span"}},
+	}))
+	assert.Equal(t, `AGENT  VERSION  EVENTS  ACCEPTED  STATUS
+go     1.8.0    3       3         ok
+java   1.0.0    2       1         incompatible: invalid span
+`, report.String())
+}
+
+func TestURLFixtures(t *testing.T) {
+	fixtures, err := URLFixtures([]string{"https://example.com/fixtures/java/1.17.0.ndjson"})
+	require.NoError(t, err)
+	assert.Equal(t, []Fixture{{Agent: "java", Version: "1.17.0", Path: "https://example.com/fixtures/java/1.17.0.ndjson"}}, fixtures)
+
+	for _, u := range []string{"https://example.com/1.17.0.ndjson", "https://example.com/java/1.17.0.json"} {
+		_, err := URLFixtures([]string{u})
+		assert.Error(t, err, u)
+	}
+}
+
+func TestCheckURLFixture(t *testing.T) {
+	cacheDir, err := ioutil.TempDir("", "fixtures")
+	require.NoError(t, err)
+	defer os.RemoveAll(cacheDir)
+	defer func(d string) { loader.FixtureCacheDir = d }(loader.FixtureCacheDir)
+	loader.FixtureCacheDir = cacheDir
+
+	srv := httptest.NewServer(http.FileServer(http.Dir(filepath.Join("..", "..", "testdata", "agents"))))
+	defer srv.Close()
+
+	fixtures, err := URLFixtures([]string{srv.URL + "/go/1.8.0.ndjson"})
+	require.NoError(t, err)
+	result, err := Check(fixtures[0])
+	require.NoError(t, err)
+	assert.NotZero(t, result.Events)
+	assert.True(t, result.OK(), "%v", result.Errors)
+}
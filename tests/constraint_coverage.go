@@ -0,0 +1,184 @@
+// Licensed to Elasticsearch B.V. under one or more contributor
+// license agreements. See the NOTICE file distributed with
+// this work for additional information regarding copyright
+// ownership. Elasticsearch B.V. licenses this file to you under
+// the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package tests
+
+import (
+	"flag"
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+	"sync"
+)
+
+var checkConstraintCoverage = flag.Bool("check-constraint-coverage", false,
+	"fail if the share of schema constraints exercised by payload mutation tests drops below the minimum")
+
+// trackedConstraints are the schema constraints whose coverage is tracked.
+var trackedConstraints = []string{"enum", "maxLength", "pattern", "required"}
+
+// constraintErrors maps substrings of validation errors to the constraint they report as violated,
+// covering both the JSON schema validator and the streaming decoders.
+var constraintErrors = []struct {
+	substr     string
+	constraint string
+}{
+	{"maxlength", "maxLength"},
+	{"length must be <=", "maxLength"},
+	{"does not match pattern", "pattern"},
+	{"/pattern", "pattern"},
+	{"/enum", "enum"},
+	{"must be one of", "enum"},
+	{"missing properties", "required"},
+	{"missing required property", "required"},
+}
+
+// constraintHits records the constraints exercised by payload mutation tests, per schema,
+// constraint and key. It is shared by all ProcessorSetups in a test binary, as the tests
+// exercising a schema are spread across test functions.
+var constraintHits = struct {
+	sync.Mutex
+	hits map[string]map[string]*Set
+}{hits: make(map[string]map[string]*Set)}
+
+// recordConstraintHit records the constraint reported as violated by err, when mutating the payload at key.
+// Payload keys start with the name of the event object, e.g. transaction.name, which is not part of the schema.
+func (ps *ProcessorSetup) recordConstraintHit(key string, err error) {
+	msg := strings.ToLower(err.Error())
+	_, field := splitFirstKey(key)
+	constraintHits.Lock()
+	defer constraintHits.Unlock()
+	hits, ok := constraintHits.hits[ps.Schema]
+	if !ok {
+		hits = make(map[string]*Set)
+		constraintHits.hits[ps.Schema] = hits
+	}
+	for _, e := range constraintErrors {
+		if !strings.Contains(msg, e.substr) {
+			continue
+		}
+		if hits[e.constraint] == nil {
+			hits[e.constraint] = NewSet()
+		}
+		hits[e.constraint].Add(field)
+	}
+}
+
+// ConstraintCoverage holds the schema constraints exercised by payload mutation tests.
+type ConstraintCoverage struct {
+	Covered int
+	Total   int
+	// Uncovered lists the constraints not exercised, as "<field>: <constraint>".
+	Uncovered []string
+}
+
+// Ratio returns the share of constraints exercised.
+func (c ConstraintCoverage) Ratio() float64 {
+	if c.Total == 0 {
+		return 1
+	}
+	return float64(c.Covered) / float64(c.Total)
+}
+
+// ConstraintCoverage returns the coverage of the schema's maxLength, pattern, required and enum constraints
+// by the payload mutation tests run so far. A constraint counts as exercised if a payload was rejected for
+// violating it when mutating its field or one of the field's parents.
+func (ps *ProcessorSetup) ConstraintCoverage() (ConstraintCoverage, error) {
+	fields, err := flattenSchemaConstraints(ps.Schema)
+	if err != nil {
+		return ConstraintCoverage{}, err
+	}
+	constraintHits.Lock()
+	hits := constraintHits.hits[ps.Schema]
+	constraintHits.Unlock()
+
+	var coverage ConstraintCoverage
+	for field, constraints := range fields {
+		for _, constraint := range trackedConstraints {
+			if _, ok := constraints[constraint]; !ok {
+				continue
+			}
+			coverage.Total++
+			if constraintHit(hits[constraint], field) {
+				coverage.Covered++
+			} else {
+				coverage.Uncovered = append(coverage.Uncovered, fmt.Sprintf("%s: %s", field, constraint))
+			}
+		}
+	}
+	sort.Strings(coverage.Uncovered)
+	return coverage, nil
+}
+
+func splitFirstKey(key string) (string, string) {
+	if i := strings.Index(key, "."); i >= 0 {
+		return key[:i], key[i+1:]
+	}
+	return key, ""
+}
+
+func constraintHit(keys *Set, field string) bool {
+	for _, key := range keys.Strings() {
+		if key == field || strings.HasPrefix(field, key+".") {
+			return true
+		}
+	}
+	return false
+}
+
+// ConstraintCoverageCheck is the minimum share of schema constraints of a ProcessorSetup
+// to be exercised by payload mutation tests.
+type ConstraintCoverageCheck struct {
+	Setup   *ProcessorSetup
+	Minimum float64
+}
+
+// CheckConstraintCoverage reports the constraint coverage of the given setups to w, and returns false
+// if run with -check-constraint-coverage and the coverage of any of them dropped below its minimum.
+// It is meant to be called from TestMain after all tests ran, as the coverage is collected across tests.
+func CheckConstraintCoverage(w io.Writer, checks map[string]ConstraintCoverageCheck) bool {
+	if !*checkConstraintCoverage {
+		return true
+	}
+	names := make([]string, 0, len(checks))
+	for name := range checks {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	ok := true
+	for _, name := range names {
+		check := checks[name]
+		coverage, err := check.Setup.ConstraintCoverage()
+		if err != nil {
+			fmt.Fprintf(w, "%s: %s\n", name, err)
+			ok = false
+			continue
+		}
+		fmt.Fprintf(w, "%s: %d/%d schema constraints exercised (%.0f%%)\n",
+			name, coverage.Covered, coverage.Total, 100*coverage.Ratio())
+		for _, u := range coverage.Uncovered {
+			fmt.Fprintf(w, "  not exercised: %s\n", u)
+		}
+		if coverage.Ratio() < check.Minimum {
+			fmt.Fprintf(w, "FAIL: constraint coverage of %s dropped below %.0f%%\n", name, 100*check.Minimum)
+			ok = false
+		}
+	}
+	return ok
+}
@@ -0,0 +1,55 @@
+// Licensed to Elasticsearch B.V. under one or more contributor
+// license agreements. See the NOTICE file distributed with
+// this work for additional information regarding copyright
+// ownership. Elasticsearch B.V. licenses this file to you under
+// the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package tests
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestConstraintCoverage(t *testing.T) {
+	ps := &ProcessorSetup{Schema: `{
+		"properties": {
+			"id": {"type": "string", "maxLength": 16},
+			"kind": {"enum": ["a", "b"]},
+			"context": {"properties": {"tags": {"patternProperties": {"^[a-z]*$": {"maxLength": 1024}}}}}
+		},
+		"required": ["id"]
+	}`}
+
+	coverage, err := ps.ConstraintCoverage()
+	require.NoError(t, err)
+	assert.Equal(t, ConstraintCoverage{Total: 4, Uncovered: []string{
+		"context.tags.^[a-z]*$: maxLength",
+		"id: maxLength",
+		"id: required",
+		"kind: enum",
+	}}, coverage)
+
+	ps.recordConstraintHit("event.id", errors.New(`I[#/id] S[#/properties/id/maxLength] length must be <= 16, but got 17`))
+	ps.recordConstraintHit("event.context.tags", errors.New(`tags: length must be <= 1024`))
+	ps.recordConstraintHit("event.kind", errors.New(`expected string, but got number`))
+
+	coverage, err = ps.ConstraintCoverage()
+	require.NoError(t, err)
+	assert.Equal(t, ConstraintCoverage{Covered: 2, Total: 4, Uncovered: []string{"id: required", "kind: enum"}}, coverage)
+	assert.Equal(t, 0.5, coverage.Ratio())
+}
@@ -0,0 +1,190 @@
+// Licensed to Elasticsearch B.V. under one or more contributor
+// license agreements. See the NOTICE file distributed with
+// this work for additional information regarding copyright
+// ownership. Elasticsearch B.V. licenses this file to you under
+// the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package tests
+
+import (
+	"bufio"
+	"compress/gzip"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Fault describes how the fake Elasticsearch misbehaves when handling a bulk request.
+// The zero value handles the request successfully.
+type Fault struct {
+	// Latency delays the response.
+	Latency time.Duration
+	// Status responds to the whole request with the given HTTP status, e.g. 429.
+	Status int
+	// ItemStatus returns the status for the i-th item of a bulk request, e.g.
+	// 429 for items to be retried or 400 for items to be dropped.
+	// All items are created if nil.
+	ItemStatus func(i int) int
+	// Reset closes the connection without responding.
+	Reset bool
+}
+
+// EveryNthItem returns an ItemStatus function failing every n-th item with the given status.
+func EveryNthItem(n, status int) func(int) int {
+	return func(i int) int {
+		if (i+1)%n == 0 {
+			return status
+		}
+		return http.StatusCreated
+	}
+}
+
+// ChaosES is a fake Elasticsearch server, injecting faults into bulk requests on a schedule:
+// the n-th bulk request is handled according to the n-th fault, and all requests
+// following the schedule are handled successfully.
+type ChaosES struct {
+	*httptest.Server
+
+	mu       sync.Mutex
+	schedule []Fault
+	requests int
+	indexed  []json.RawMessage
+}
+
+// NewChaosES starts a fake Elasticsearch server with the given fault schedule.
+// The server must be closed after use.
+func NewChaosES(schedule ...Fault) *ChaosES {
+	es := &ChaosES{schedule: schedule}
+	es.Server = httptest.NewServer(http.HandlerFunc(es.handle))
+	return es
+}
+
+// BulkRequests returns the number of bulk requests received so far.
+func (es *ChaosES) BulkRequests() int {
+	es.mu.Lock()
+	defer es.mu.Unlock()
+	return es.requests
+}
+
+// Indexed returns the documents successfully indexed so far.
+func (es *ChaosES) Indexed() []json.RawMessage {
+	es.mu.Lock()
+	defer es.mu.Unlock()
+	return append([]json.RawMessage(nil), es.indexed...)
+}
+
+func (es *ChaosES) handle(w http.ResponseWriter, r *http.Request) {
+	if !strings.HasSuffix(r.URL.Path, "/_bulk") {
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`{"version":{"number":"7.8.0"},"tagline":"You Know, for Search"}`))
+		return
+	}
+
+	es.mu.Lock()
+	var fault Fault
+	if es.requests < len(es.schedule) {
+		fault = es.schedule[es.requests]
+	}
+	es.requests++
+	es.mu.Unlock()
+
+	if fault.Latency > 0 {
+		select {
+		case <-time.After(fault.Latency):
+		case <-r.Context().Done():
+			return
+		}
+	}
+	if fault.Reset {
+		if hj, ok := w.(http.Hijacker); ok {
+			if conn, _, err := hj.Hijack(); err == nil {
+				conn.Close()
+				return
+			}
+		}
+		panic(http.ErrAbortHandler)
+	}
+	if fault.Status != 0 {
+		w.WriteHeader(fault.Status)
+		return
+	}
+
+	actions, docs, err := readBulk(r)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	type itemResult struct {
+		Status int `json:"status"`
+	}
+	result := struct {
+		Took   int                     `json:"took"`
+		Errors bool                    `json:"errors"`
+		Items  []map[string]itemResult `json:"items"`
+	}{Items: make([]map[string]itemResult, len(docs))}
+	var indexed []json.RawMessage
+	for i, doc := range docs {
+		status := http.StatusCreated
+		if fault.ItemStatus != nil {
+			status = fault.ItemStatus(i)
+		}
+		if status < 300 {
+			indexed = append(indexed, doc)
+		} else {
+			result.Errors = true
+		}
+		result.Items[i] = map[string]itemResult{actions[i]: {Status: status}}
+	}
+
+	es.mu.Lock()
+	es.indexed = append(es.indexed, indexed...)
+	es.mu.Unlock()
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(result)
+}
+
+// readBulk reads the action names and documents of a bulk request.
+func readBulk(r *http.Request) ([]string, []json.RawMessage, error) {
+	var body io.Reader = r.Body
+	if r.Header.Get("Content-Encoding") == "gzip" {
+		gz, err := gzip.NewReader(r.Body)
+		if err != nil {
+			return nil, nil, err
+		}
+		defer gz.Close()
+		body = gz
+	}
+	var actions []string
+	var docs []json.RawMessage
+	scanner := bufio.NewScanner(body)
+	scanner.Buffer(nil, 10*1024*1024)
+	for scanner.Scan() {
+		var meta map[string]json.RawMessage
+		if err := json.Unmarshal(scanner.Bytes(), &meta); err != nil {
+			return nil, nil, err
+		}
+		for action := range meta {
+			actions = append(actions, action)
+		}
+		if !scanner.Scan() {
+			break
+		}
+		docs = append(docs, append(json.RawMessage(nil), scanner.Bytes()...))
+	}
+	return actions, docs, scanner.Err()
+}
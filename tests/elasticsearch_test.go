@@ -0,0 +1,173 @@
+// Licensed to Elasticsearch B.V. under one or more contributor
+// license agreements. See the NOTICE file distributed with
+// this work for additional information regarding copyright
+// ownership. Elasticsearch B.V. licenses this file to you under
+// the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package tests
+
+import (
+	"context"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/elastic/beats/v7/libbeat/beat"
+	"github.com/elastic/beats/v7/libbeat/common"
+	"github.com/elastic/beats/v7/libbeat/esleg/eslegclient"
+	"github.com/elastic/beats/v7/libbeat/monitoring"
+	"github.com/elastic/beats/v7/libbeat/outputs"
+	"github.com/elastic/beats/v7/libbeat/outputs/elasticsearch"
+	"github.com/elastic/beats/v7/libbeat/outputs/outest"
+	"github.com/elastic/beats/v7/libbeat/publisher"
+)
+
+type staticIndex string
+
+func (i staticIndex) Select(*beat.Event) (string, error) { return string(i), nil }
+
+// TestChaosES covers how the Elasticsearch output classifies bulk item failures.
+// All retryable items share the output's max_retries budget: per-class retry
+// budgets would require changes to libbeat's Elasticsearch client, which consumes
+// the bulk item statuses, and are out of scope.
+func TestChaosES(t *testing.T) {
+	for name, test := range map[string]struct {
+		schedule []Fault
+		events   int
+
+		attempts int
+		counters map[string]int64
+	}{
+		"healthy": {
+			events:   4,
+			attempts: 1,
+			counters: map[string]int64{"events.acked": 4},
+		},
+		"latency": {
+			schedule: []Fault{{Latency: 400 * time.Millisecond}},
+			events:   4,
+			attempts: 2,
+			counters: map[string]int64{"events.acked": 4},
+		},
+		"too_many_requests": {
+			// rejected bulk requests are reported as errors, retrying
+			// all events without counting them as failed
+			schedule: []Fault{{Status: http.StatusTooManyRequests}, {Status: http.StatusTooManyRequests}},
+			events:   4,
+			attempts: 3,
+			counters: map[string]int64{"events.acked": 4},
+		},
+		"partial_bulk_retried": {
+			schedule: []Fault{{ItemStatus: EveryNthItem(3, http.StatusTooManyRequests)}},
+			events:   6,
+			attempts: 2,
+			counters: map[string]int64{"events.acked": 6, "events.failed": 2, "events.toomany": 2},
+		},
+		"partial_bulk_unavailable_retried": {
+			// item failures with 5xx statuses are retryable, like 429
+			schedule: []Fault{{ItemStatus: EveryNthItem(2, http.StatusServiceUnavailable)}},
+			events:   4,
+			attempts: 2,
+			counters: map[string]int64{"events.acked": 4, "events.failed": 2},
+		},
+		"partial_bulk_conflict": {
+			// conflicts are reported for documents which already exist,
+			// and are neither retried nor counted as dropped
+			schedule: []Fault{{ItemStatus: EveryNthItem(2, http.StatusConflict)}},
+			events:   4,
+			attempts: 1,
+			counters: map[string]int64{"events.acked": 2, "events.duplicates": 2},
+		},
+		"partial_bulk_dropped": {
+			schedule: []Fault{{ItemStatus: EveryNthItem(2, http.StatusBadRequest)}},
+			events:   4,
+			attempts: 1,
+			counters: map[string]int64{"events.acked": 2, "events.dropped": 2},
+		},
+		"connection_reset": {
+			schedule: []Fault{{Reset: true}},
+			events:   4,
+			attempts: 2,
+			counters: map[string]int64{"events.acked": 4},
+		},
+	} {
+		t.Run(name, func(t *testing.T) {
+			es := NewChaosES(test.schedule...)
+			defer es.Close()
+			reg := monitoring.NewRegistry()
+			client, err := elasticsearch.NewClient(elasticsearch.ClientSettings{
+				ConnectionSettings: eslegclient.ConnectionSettings{URL: es.URL, Timeout: 200 * time.Millisecond},
+				Index:              staticIndex("apm"),
+				Observer:           outputs.NewStats(reg),
+			}, nil)
+			require.NoError(t, err)
+			require.NoError(t, client.Connect())
+			defer client.Close()
+
+			attempts := publishWithRetries(t, client, test.events, 5)
+			assert.Equal(t, test.attempts, attempts)
+			assert.Equal(t, test.attempts, es.BulkRequests())
+			assert.Len(t, es.Indexed(), int(test.counters["events.acked"]))
+
+			snapshot := monitoring.CollectFlatSnapshot(reg, monitoring.Full, false)
+			for _, counter := range []string{"events.acked", "events.failed", "events.dropped", "events.duplicates", "events.toomany"} {
+				assert.Equal(t, test.counters[counter], snapshot.Ints[counter], counter)
+			}
+		})
+	}
+}
+
+// publishWithRetries publishes events, retrying events signalled for retry the way
+// the publisher pipeline does, and returns the number of publish attempts.
+func publishWithRetries(t *testing.T, client outputs.Client, n, maxAttempts int) int {
+	events := make([]beat.Event, n)
+	for i := range events {
+		events[i] = beat.Event{Timestamp: time.Now(), Fields: common.MapStr{"i": i}}
+	}
+	batch := outest.NewBatch(events...)
+	for attempt := 1; attempt <= maxAttempts; attempt++ {
+		client.Publish(context.Background(), batch)
+		require.Len(t, batch.Signals, 1)
+		switch sig := batch.Signals[0]; sig.Tag {
+		case outest.BatchACK:
+			return attempt
+		case outest.BatchRetryEvents:
+			batch = retryBatch(sig.Events)
+		default:
+			t.Fatalf("unexpected batch signal %v", sig.Tag)
+		}
+	}
+	t.Fatalf("events not acknowledged after %d attempts", maxAttempts)
+	return 0
+}
+
+func retryBatch(events []publisher.Event) *outest.Batch {
+	contents := make([]beat.Event, len(events))
+	for i, e := range events {
+		contents[i] = e.Content
+	}
+	return outest.NewBatch(contents...)
+}
+
+func TestEveryNthItem(t *testing.T) {
+	status := EveryNthItem(3, http.StatusTooManyRequests)
+	var statuses []int
+	for i := 0; i < 6; i++ {
+		statuses = append(statuses, status(i))
+	}
+	assert.Equal(t, []int{201, 201, 429, 201, 201, 429}, statuses)
+}
@@ -0,0 +1,196 @@
+// Licensed to Elasticsearch B.V. under one or more contributor
+// license agreements. See the NOTICE file distributed with
+// this work for additional information regarding copyright
+// ownership. Elasticsearch B.V. licenses this file to you under
+// the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package tests
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/elastic/beats/v7/libbeat/mapping"
+)
+
+// schemaTypesForTemplateType holds the JSON schema types compatible with ES template field types.
+var schemaTypesForTemplateType = map[string][]string{
+	"keyword":      {"string"},
+	"text":         {"string"},
+	"ip":           {"string"},
+	"boolean":      {"boolean"},
+	"long":         {"integer"},
+	"integer":      {"integer"},
+	"short":        {"integer"},
+	"byte":         {"integer"},
+	"float":        {"number", "integer"},
+	"double":       {"number", "integer"},
+	"half_float":   {"number", "integer"},
+	"scaled_float": {"number", "integer"},
+	// dates are sent as formatted strings or as epoch numbers
+	"date":   {"string", "integer", "number"},
+	"object": {"object"},
+}
+
+// Test that fields defined in the ES template have compatible types in the json schema:
+// numeric fields must be numbers, keyword fields strings, and date fields formatted as
+// date-time if sent as strings.
+// Fields not found in the schema are ignored, their presence is covered by other tests.
+//
+// typeExceptionKeys: attributes whose values are converted on decoding, e.g. numeric
+//   strings indexed as numbers,
+// templateToSchema: mapping for fields that are nested or named different on
+//   ES level than on intake API
+func (ps *ProcessorSetup) TypeCompatibility(t *testing.T, typeExceptionKeys *Set,
+	templateToSchema []FieldTemplateMapping) {
+
+	templateTypes := make(map[string]string)
+	for _, path := range ps.TemplatePaths {
+		fields, err := loadFields(path)
+		require.NoError(t, err)
+		flattenFieldTypes(fields, "", templateTypes)
+	}
+
+	schema, err := ParseSchema(ps.Schema)
+	require.NoError(t, err)
+	schemaTypes := make(map[string]*schemaFieldTypes)
+	flattenSchemaTypes(schema, "", schemaTypes)
+
+	templateKeys := NewSet()
+	for k := range templateTypes {
+		templateKeys.Add(k)
+	}
+	templateKeys = differenceWithGroup(templateKeys, typeExceptionKeys)
+
+	for _, templateKey := range templateKeys.Strings() {
+		templateType := templateTypes[templateKey]
+		allowed, ok := schemaTypesForTemplateType[templateType]
+		if !ok {
+			continue
+		}
+		key := templateKeyToSchema(templateKey, templateToSchema)
+		st, ok := schemaTypes[key]
+		if !ok {
+			continue
+		}
+		var incompatible []string
+		for _, typ := range st.sorted() {
+			if !contains(allowed, typ) {
+				incompatible = append(incompatible, typ)
+			}
+		}
+		assert.Empty(t, incompatible, "Expected <%s> (original: <%s>) to only allow types %v in the json schema because it gets indexed as '%s'",
+			key, templateKey, allowed, templateType)
+		if templateType == "date" && st.types["string"] {
+			assert.Equal(t, "date-time", st.format, "Expected <%s> (original: <%s>) to have the date-time format because it gets indexed as 'date'",
+				key, templateKey)
+		}
+	}
+}
+
+// schemaFieldTypes holds the types allowed for a json schema field, ignoring null,
+// and its format. Array types are represented by the types of their items.
+type schemaFieldTypes struct {
+	types  map[string]bool
+	format string
+}
+
+func (s *schemaFieldTypes) sorted() []string {
+	types := make([]string, 0, len(s.types))
+	for t := range s.types {
+		types = append(types, t)
+	}
+	sort.Strings(types)
+	return types
+}
+
+func flattenFieldTypes(fields []mapping.Field, prefix string, flattened map[string]string) {
+	for _, f := range fields {
+		key := strConcat(prefix, f.Name, ".")
+		if f.Name != "" && f.Type != "" && f.Type != "group" {
+			flattened[key] = f.Type
+		}
+		flattenFieldTypes(f.Fields, key, flattened)
+	}
+}
+
+func flattenSchemaTypes(s *Schema, prefix string, flattened map[string]*schemaFieldTypes) {
+	for k, v := range s.Properties {
+		key := strConcat(prefix, k, ".")
+		st, ok := flattened[key]
+		if !ok {
+			st = &schemaFieldTypes{types: make(map[string]bool)}
+			flattened[key] = st
+		}
+		addSchemaTypes(v, st)
+		flattenSchemaTypes(v, key, flattened)
+	}
+	if s.Items != nil {
+		flattenSchemaTypes(s.Items, prefix, flattened)
+	}
+	for _, schemas := range [][]*Schema{s.AllOf, s.OneOf, s.AnyOf} {
+		for _, e := range schemas {
+			flattenSchemaTypes(e, prefix, flattened)
+		}
+	}
+}
+
+func addSchemaTypes(s *Schema, st *schemaFieldTypes) {
+	var types []string
+	switch t := s.Type.(type) {
+	case string:
+		types = []string{t}
+	case []interface{}:
+		for _, v := range t {
+			types = append(types, fmt.Sprint(v))
+		}
+	}
+	for _, t := range types {
+		switch {
+		case t == "null":
+		case t == "array" && s.Items != nil:
+			addSchemaTypes(s.Items, st)
+		default:
+			st.types[t] = true
+		}
+	}
+	if s.Format != "" {
+		st.format = s.Format
+	}
+}
+
+// templateKeyToSchema maps an ES template field name to the corresponding json schema
+// key, applying the first matching mapping.
+func templateKeyToSchema(key string, templateToSchema []FieldTemplateMapping) string {
+	for _, ts := range templateToSchema {
+		if strings.HasPrefix(key, ts.Template) {
+			return strings.Replace(key, ts.Template, ts.Mapping, 1)
+		}
+	}
+	return key
+}
+
+func contains(values []string, s string) bool {
+	for _, v := range values {
+		if v == s {
+			return true
+		}
+	}
+	return false
+}
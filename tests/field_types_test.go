@@ -0,0 +1,71 @@
+// Licensed to Elasticsearch B.V. under one or more contributor
+// license agreements. See the NOTICE file distributed with
+// this work for additional information regarding copyright
+// ownership. Elasticsearch B.V. licenses this file to you under
+// the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package tests
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestFlattenFieldTypes(t *testing.T) {
+	fields, err := loadFields("./_meta/fields.yml")
+	require.NoError(t, err)
+
+	types := make(map[string]string)
+	flattenFieldTypes(fields, "", types)
+	assert.Equal(t, map[string]string{
+		"transaction.id":       "keyword",
+		"transaction.context":  "object",
+		"exception.http.url":   "keyword",
+		"exception.http.meta":  "object",
+		"exception.stacktrace": "object",
+	}, types)
+}
+
+func TestFlattenSchemaTypes(t *testing.T) {
+	schema, err := ParseSchema(`{
+		"allOf": [
+			{"properties": {"timestamp": {"type": ["string", "null"], "format": "date-time"}}},
+			{"properties": {
+				"tags": {"type": ["array", "null"], "items": {"type": "string"}},
+				"code": {"type": ["string", "integer"]}
+			}}
+		]
+	}`)
+	require.NoError(t, err)
+
+	types := make(map[string]*schemaFieldTypes)
+	flattenSchemaTypes(schema, "", types)
+	require.Len(t, types, 3)
+	assert.Equal(t, []string{"string"}, types["timestamp"].sorted())
+	assert.Equal(t, "date-time", types["timestamp"].format)
+	assert.Equal(t, []string{"string"}, types["tags"].sorted())
+	assert.Equal(t, []string{"integer", "string"}, types["code"].sorted())
+}
+
+func TestTemplateKeyToSchema(t *testing.T) {
+	mappings := []FieldTemplateMapping{
+		{Template: "trace.id", Mapping: "trace_id"},
+		{Template: "error."},
+	}
+	assert.Equal(t, "trace_id", templateKeyToSchema("trace.id", mappings))
+	assert.Equal(t, "exception.code", templateKeyToSchema("error.exception.code", mappings))
+	assert.Equal(t, "service.name", templateKeyToSchema("service.name", mappings))
+}
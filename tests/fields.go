@@ -20,7 +20,6 @@ package tests
 import (
 	"fmt"
 	"io/ioutil"
-	"sort"
 	"strings"
 	"testing"
 
@@ -83,8 +82,7 @@ func (ps *ProcessorSetup) EventFieldsMappedToTemplateFields(t *testing.T, eventF
 	require.NoError(t, err)
 
 	var eventFieldsMapped = NewSet()
-	for _, val := range eventFields.Array() {
-		var f = val.(string)
+	for _, f := range eventFields.Strings() {
 		for _, m := range mappings {
 			template := m.Template
 			starMatch := strings.HasSuffix(m.Template, ".*")
@@ -130,12 +128,7 @@ func fetchFields(t *testing.T, p TestProcessor, path string, blacklisted *Set) *
 			FlattenMapStr(event.Fields[k], k, blacklisted, keys)
 		}
 	}
-	sortedKeys := make([]string, keys.Len())
-	for i, v := range keys.Array() {
-		sortedKeys[i] = v.(string)
-	}
-	sort.Strings(sortedKeys)
-	t.Logf("Keys in events: %v", sortedKeys)
+	t.Logf("Keys in events: %v", keys.Strings())
 	return keys
 }
 
@@ -201,6 +194,35 @@ func fetchFlattenedFieldNames(paths []string, filters ...filter) (*Set, error) {
 	return fields, nil
 }
 
+// defaultIgnoreAbove is the `ignore_above` setting libbeat applies to keyword fields not defining one.
+const defaultIgnoreAbove = 1024
+
+// fetchIgnoreAbove returns the effective `ignore_above` setting of all keyword fields.
+func fetchIgnoreAbove(paths []string) (map[string]int, error) {
+	ignoreAbove := make(map[string]int)
+	for _, path := range paths {
+		f, err := loadFields(path)
+		if err != nil {
+			return nil, err
+		}
+		flattenIgnoreAbove(f, "", ignoreAbove)
+	}
+	return ignoreAbove, nil
+}
+
+func flattenIgnoreAbove(fields []mapping.Field, prefix string, flattened map[string]int) {
+	for _, f := range fields {
+		key := strConcat(prefix, f.Name, ".")
+		if f.Type == "keyword" {
+			flattened[key] = f.IgnoreAbove
+			if f.IgnoreAbove == 0 {
+				flattened[key] = defaultIgnoreAbove
+			}
+		}
+		flattenIgnoreAbove(f.Fields, key, flattened)
+	}
+}
+
 func flattenFieldNames(fields []mapping.Field, prefix string, flattened *Set, filters ...filter) {
 	for _, f := range fields {
 		key := strConcat(prefix, f.Name, ".")
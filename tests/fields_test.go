@@ -101,3 +101,12 @@ func TestFlattenFieldNames(t *testing.T) {
 	flattenFieldNames(fields, "", disabledFields, hasName, isDisabled)
 	assert.Equal(t, expectDisabled, disabledFields)
 }
+
+func TestFetchIgnoreAbove(t *testing.T) {
+	ignoreAbove, err := fetchIgnoreAbove([]string{"./_meta/fields.yml"})
+	require.NoError(t, err)
+	assert.Equal(t, map[string]int{
+		"transaction.id":     defaultIgnoreAbove,
+		"exception.http.url": 2048,
+	}, ignoreAbove)
+}
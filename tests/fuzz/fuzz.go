@@ -0,0 +1,107 @@
+// Licensed to Elasticsearch B.V. under one or more contributor
+// license agreements. See the NOTICE file distributed with
+// this work for additional information regarding copyright
+// ownership. Elasticsearch B.V. licenses this file to you under
+// the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+// Package fuzz holds fuzzing targets for the intake payload decoding,
+// compatible with go-fuzz and libFuzzer; run them with `make fuzz`.
+//
+// The targets panic whenever processing a payload results in anything else
+// than validation errors or well-formed events.
+package fuzz
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"time"
+
+	"github.com/elastic/apm-server/beater/config"
+	"github.com/elastic/apm-server/processor/stream"
+	"github.com/elastic/apm-server/publish"
+	"github.com/elastic/apm-server/transform"
+	"github.com/elastic/apm-server/utility"
+)
+
+var (
+	cfg = func() *config.Config {
+		cfg := config.DefaultConfig("7.x")
+		cfg.MaxEventSize = 100 * 1024
+		return cfg
+	}()
+	tcfg = &transform.Config{
+		LibraryPattern:      regexp.MustCompile(cfg.RumConfig.LibraryPattern),
+		ExcludeFromGrouping: regexp.MustCompile(cfg.RumConfig.ExcludeFromGrouping),
+	}
+
+	backendProcessor = stream.BackendProcessor(cfg)
+	rumProcessor     = stream.RUMProcessor(cfg, tcfg)
+	rumV3Processor   = stream.RUMV3Processor(cfg, tcfg)
+)
+
+// FuzzIntake processes data as payload sent to the backend intake v2 endpoint.
+func FuzzIntake(data []byte) int {
+	return process(backendProcessor, data)
+}
+
+// FuzzRUMIntake processes data as payload sent to the RUM intake v2 endpoint.
+func FuzzRUMIntake(data []byte) int {
+	return process(rumProcessor, data)
+}
+
+// FuzzRUMV3Intake processes data as payload sent to the RUM intake v3 endpoint.
+func FuzzRUMV3Intake(data []byte) int {
+	return process(rumV3Processor, data)
+}
+
+// process handles data with the given processor, returning 1 if events
+// were accepted, so that the fuzzer prioritizes the input, and 0 otherwise.
+func process(p *stream.Processor, data []byte) int {
+	var events int
+	report := func(ctx context.Context, req publish.PendingReq) error {
+		for _, transformable := range req.Transformables {
+			for _, event := range transformable.Transform(ctx, req.Tcontext) {
+				if event.Timestamp.IsZero() {
+					panic(fmt.Sprintf("event without timestamp: %v", event.Fields))
+				}
+				if ok, _ := event.Fields.HasKey("processor.event"); !ok {
+					panic(fmt.Sprintf("event without processor.event: %v", event.Fields))
+				}
+				if _, err := json.Marshal(event.Fields); err != nil {
+					panic(fmt.Sprintf("event cannot be encoded: %s", err))
+				}
+			}
+		}
+		events += len(req.Transformables)
+		return nil
+	}
+
+	ctx := utility.ContextWithRequestTime(context.Background(), time.Now())
+	result := p.HandleStream(ctx, nil, map[string]interface{}{}, bytes.NewReader(data), report)
+	for _, err := range result.Errors {
+		if err.Type != stream.InvalidInputErrType && err.Type != stream.InputTooLargeErrType {
+			panic(fmt.Sprintf("unexpected error: %s", err))
+		}
+	}
+	if result.Accepted != events {
+		panic(fmt.Sprintf("%d events accepted, but %d reported", result.Accepted, events))
+	}
+	if events > 0 {
+		return 1
+	}
+	return 0
+}
@@ -0,0 +1,87 @@
+// Licensed to Elasticsearch B.V. under one or more contributor
+// license agreements. See the NOTICE file distributed with
+// this work for additional information regarding copyright
+// ownership. Elasticsearch B.V. licenses this file to you under
+// the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package fuzz
+
+import (
+	"math/rand"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/elastic/apm-server/tests/loader"
+)
+
+// mutationsPerPayload defines how many mutations of each fixture are processed
+// when running the fuzzing targets as part of the regular tests.
+const mutationsPerPayload = 20
+
+func TestFuzzIntake(t *testing.T) {
+	testFuzz(t, FuzzIntake, "intake-v2", "*.ndjson")
+}
+
+func TestFuzzRUMIntake(t *testing.T) {
+	testFuzz(t, FuzzRUMIntake, "intake-v2", "*rum*.ndjson")
+}
+
+func TestFuzzRUMV3Intake(t *testing.T) {
+	testFuzz(t, FuzzRUMV3Intake, "intake-v3", "*.ndjson")
+}
+
+func testFuzz(t *testing.T, fuzz func([]byte) int, dir, pattern string) {
+	path, err := loader.FindFile("..", "testdata", dir)
+	require.NoError(t, err)
+	files, err := filepath.Glob(filepath.Join(path, pattern))
+	require.NoError(t, err)
+	require.NotEmpty(t, files)
+
+	rng := rand.New(rand.NewSource(1))
+	for _, file := range files {
+		data, err := loader.LoadDataAsBytes(filepath.Join("..", "testdata", dir, filepath.Base(file)))
+		require.NoError(t, err)
+		t.Run(filepath.Base(file), func(t *testing.T) {
+			assert.NotPanics(t, func() { fuzz(data) })
+			for i := 0; i < mutationsPerPayload; i++ {
+				mutated := mutate(rng, data)
+				assert.NotPanics(t, func() { fuzz(mutated) }, "%q", mutated)
+			}
+		})
+	}
+}
+
+// mutate returns a copy of data with a few random bytes flipped,
+// removed, duplicated or replaced by JSON syntax characters.
+func mutate(rng *rand.Rand, data []byte) []byte {
+	const special = `{}[]":,\0 -e.`
+	mutated := append([]byte(nil), data...)
+	for n := rng.Intn(4) + 1; n > 0 && len(mutated) > 0; n-- {
+		i := rng.Intn(len(mutated))
+		switch rng.Intn(4) {
+		case 0:
+			mutated[i] ^= 1 << uint(rng.Intn(8))
+		case 1:
+			mutated = append(mutated[:i], mutated[i+1:]...)
+		case 2:
+			mutated = append(mutated[:i], append([]byte{mutated[i]}, mutated[i:]...)...)
+		default:
+			mutated[i] = special[rng.Intn(len(special))]
+		}
+	}
+	return mutated
+}
@@ -0,0 +1,460 @@
+// Licensed to Elasticsearch B.V. under one or more contributor
+// license agreements. See the NOTICE file distributed with
+// this work for additional information regarding copyright
+// ownership. Elasticsearch B.V. licenses this file to you under
+// the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+// Package generator produces random payloads which are structurally valid
+// according to a JSON schema, respecting types, required properties,
+// length limits, patterns and enums.
+package generator
+
+import (
+	"encoding/json"
+	"math/rand"
+	"regexp/syntax"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/pkg/errors"
+)
+
+const (
+	// maxStringLength limits the length of generated strings without maxLength.
+	maxStringLength = 32
+	// maxRepeat limits the number of repetitions when generating strings matching patterns.
+	maxRepeat = 16
+	// maxExtraItems limits the number of array items added on top of minItems.
+	maxExtraItems = 3
+	// maxPatternProperties limits the number of properties generated for patternProperties.
+	maxPatternProperties = 3
+)
+
+// Generator generates random values for a JSON schema.
+type Generator struct {
+	rng    *rand.Rand
+	schema *schema
+
+	// OptionalProbability is the probability of generating a property which is not required.
+	OptionalProbability float64
+	// NullProbability is the probability of generating null for a value which may be null.
+	NullProbability float64
+	// EdgeProbability is the probability of generating a boundary value, such as a string
+	// of exactly maxLength multi-byte characters, a number equal to the minimum, or an
+	// array of exactly minItems items.
+	EdgeProbability float64
+	// Override, if set, is called with the dot-separated path of each property and the
+	// generator's source of randomness before
+	// generating its value. It allows constraining values beyond what the schema
+	// expresses; if it returns false, the value is generated from the schema.
+	Override func(path string, rng *rand.Rand) (interface{}, bool)
+}
+
+type schema struct {
+	Type                 interface{}        `json:"type"`
+	Properties           map[string]*schema `json:"properties"`
+	PatternProperties    map[string]*schema `json:"patternProperties"`
+	AdditionalProperties interface{}        `json:"additionalProperties"`
+	Required             []string           `json:"required"`
+	Items                *schema            `json:"items"`
+	MinItems             int                `json:"minItems"`
+	AllOf                []*schema          `json:"allOf"`
+	AnyOf                []*schema          `json:"anyOf"`
+	If                   *schema            `json:"if"`
+	Then                 *schema            `json:"then"`
+	Enum                 []interface{}      `json:"enum"`
+	MinLength            int                `json:"minLength"`
+	MaxLength            int                `json:"maxLength"`
+	Pattern              string             `json:"pattern"`
+	Format               string             `json:"format"`
+	Minimum              *float64           `json:"minimum"`
+}
+
+// New returns a Generator for the given JSON schema, drawing random values from rng.
+func New(jsonSchema string, rng *rand.Rand) (*Generator, error) {
+	var s schema
+	if err := json.Unmarshal([]byte(jsonSchema), &s); err != nil {
+		return nil, errors.Wrap(err, "invalid schema")
+	}
+	return &Generator{rng: rng, schema: &s, OptionalProbability: 0.5, NullProbability: 0.1, EdgeProbability: 0.1}, nil
+}
+
+// Generate returns a random value valid according to the schema.
+func (g *Generator) Generate() interface{} {
+	return g.value([]*schema{g.schema}, "", false)
+}
+
+// GenerateObject returns a random object valid according to the schema,
+// which must describe an object.
+func (g *Generator) GenerateObject() map[string]interface{} {
+	return g.object(flatten([]*schema{g.schema}), "")
+}
+
+// value generates a value satisfying all given schemas.
+func (g *Generator) value(schemas []*schema, path string, optional bool) interface{} {
+	if g.Override != nil && path != "" {
+		if v, ok := g.Override(path, g.rng); ok {
+			return v
+		}
+	}
+	schemas = flatten(schemas)
+	types := allowedTypes(schemas)
+	var enum []interface{}
+	for _, s := range schemas {
+		if s.Enum != nil {
+			enum = s.Enum
+		}
+	}
+	if enum != nil {
+		return enum[g.rng.Intn(len(enum))]
+	}
+	if types["null"] && (len(types) == 1 || optional && g.rng.Float64() < g.NullProbability) {
+		return nil
+	}
+	delete(types, "null")
+	candidates := make([]string, 0, len(types))
+	for t := range types {
+		candidates = append(candidates, t)
+	}
+	sort.Strings(candidates)
+	if len(candidates) == 0 {
+		// schemas without type restrictions accept anything,
+		// generate objects if properties are described
+		for _, s := range schemas {
+			if s.Properties != nil || s.PatternProperties != nil {
+				return g.object(schemas, path)
+			}
+		}
+		return g.str(schemas)
+	}
+	switch candidates[g.rng.Intn(len(candidates))] {
+	case "object":
+		return g.object(schemas, path)
+	case "array":
+		return g.array(schemas, path)
+	case "string":
+		return g.str(schemas)
+	case "integer":
+		return int64(g.number(schemas))
+	case "number":
+		n := g.number(schemas)
+		if !g.edge() {
+			n += float64(g.rng.Intn(1000)) / 1000
+		}
+		return n
+	case "boolean":
+		return g.rng.Intn(2) == 1
+	}
+	return nil
+}
+
+func (g *Generator) object(schemas []*schema, path string) map[string]interface{} {
+	// anyOf alternatives only add constraints, pick one of them for each anyOf clause
+	for _, s := range schemas {
+		if len(s.AnyOf) > 0 {
+			schemas = append(schemas, flatten([]*schema{s.AnyOf[g.rng.Intn(len(s.AnyOf))]})...)
+		}
+	}
+
+	properties := make(map[string][]*schema)
+	required := make(map[string]bool)
+	var names []string
+	for _, s := range schemas {
+		for name, p := range s.Properties {
+			if _, ok := properties[name]; !ok {
+				names = append(names, name)
+			}
+			properties[name] = append(properties[name], p)
+		}
+		for _, name := range s.Required {
+			required[name] = true
+		}
+	}
+	sort.Strings(names)
+
+	present := make(map[string]bool)
+	for _, name := range names {
+		if required[name] || g.rng.Float64() < g.OptionalProbability {
+			present[name] = true
+		}
+	}
+	// properties required by `then` clauses become required when
+	// all properties required by the corresponding `if` clause are present
+	for changed := true; changed; {
+		changed = false
+		for _, s := range schemas {
+			if s.If == nil || s.Then == nil || !allPresent(s.If.Required, present) {
+				continue
+			}
+			for _, name := range s.Then.Required {
+				if !required[name] {
+					required[name], present[name], changed = true, true, true
+				}
+			}
+			for name, p := range s.Then.Properties {
+				properties[name] = append(properties[name], p)
+			}
+			for name, p := range s.If.Properties {
+				// ensure the `if` clause applies, as assumed above
+				properties[name] = append(properties[name], p)
+			}
+		}
+	}
+
+	out := make(map[string]interface{}, len(present))
+	for _, name := range names {
+		if present[name] {
+			out[name] = g.value(properties[name], join(path, name), !required[name])
+		}
+	}
+	for _, s := range schemas {
+		for pattern, p := range s.PatternProperties {
+			for i := g.rng.Intn(maxPatternProperties + 1); i > 0; i-- {
+				key := g.matching(pattern, 1, maxStringLength)
+				if !strings.HasPrefix(pattern, "^") {
+					// patterns not anchored at the start match any prefix,
+					// avoid generating keys made up of wildcard matches only
+					key = g.fromAlphabet(alphanumeric, 1, 8) + key
+				}
+				if _, ok := out[key]; !ok {
+					out[key] = g.value([]*schema{p}, join(path, key), true)
+				}
+			}
+		}
+	}
+	return out
+}
+
+func (g *Generator) array(schemas []*schema, path string) []interface{} {
+	var items []*schema
+	var minItems int
+	for _, s := range schemas {
+		if s.Items != nil {
+			items = append(items, s.Items)
+		}
+		if s.MinItems > minItems {
+			minItems = s.MinItems
+		}
+	}
+	n := minItems
+	if !g.edge() {
+		n += g.rng.Intn(maxExtraItems + 1)
+	}
+	out := make([]interface{}, n)
+	for i := range out {
+		out[i] = g.value(items, path, false)
+	}
+	return out
+}
+
+func (g *Generator) str(schemas []*schema) string {
+	minLength, maxLength := 0, maxStringLength
+	var pattern, format string
+	for _, s := range schemas {
+		if s.MinLength > minLength {
+			minLength = s.MinLength
+		}
+		if s.MaxLength > 0 && s.MaxLength < maxLength {
+			maxLength = s.MaxLength
+		}
+		if s.Pattern != "" {
+			pattern = s.Pattern
+		}
+		if s.Format != "" {
+			format = s.Format
+		}
+	}
+	if format == "date-time" {
+		t := time.Unix(g.rng.Int63n(1<<31), g.rng.Int63n(int64(time.Second))).UTC()
+		return t.Format(time.RFC3339Nano)
+	}
+	if pattern != "" {
+		return g.matching(pattern, minLength, maxLength)
+	}
+	if g.edge() {
+		n := minLength
+		if g.rng.Intn(2) == 1 {
+			n = maxLength
+		}
+		return g.fromAlphabet(edgeAlphabet, n, n)
+	}
+	return g.fromAlphabet(alphanumeric, minLength, maxLength)
+}
+
+func (g *Generator) number(schemas []*schema) float64 {
+	var min float64
+	for _, s := range schemas {
+		if s.Minimum != nil && *s.Minimum > min {
+			min = *s.Minimum
+		}
+	}
+	if g.edge() {
+		return min
+	}
+	return min + float64(g.rng.Intn(1e6))
+}
+
+func (g *Generator) edge() bool {
+	return g.rng.Float64() < g.EdgeProbability
+}
+
+const (
+	alphanumeric = "abcdefghijklmnopqrstuvwxyzABCDEFGHIJKLMNOPQRSTUVWXYZ0123456789"
+	// edgeAlphabet includes multi-byte characters, as lengths are limited in characters.
+	edgeAlphabet = alphanumeric + "⌘ü-_ "
+)
+
+// fromAlphabet returns a string of minLength to maxLength characters from alphabet.
+func (g *Generator) fromAlphabet(alphabet string, minLength, maxLength int) string {
+	runes := []rune(alphabet)
+	n := minLength
+	if maxLength > minLength {
+		n += g.rng.Intn(maxLength - minLength + 1)
+	}
+	var b strings.Builder
+	for i := 0; i < n; i++ {
+		b.WriteRune(runes[g.rng.Intn(len(runes))])
+	}
+	return b.String()
+}
+
+// matching returns a random string matching the regular expression pattern, with a length
+// between minLength and maxLength, falling back to alphanumeric strings if not possible.
+func (g *Generator) matching(pattern string, minLength, maxLength int) string {
+	re, err := syntax.Parse(pattern, syntax.Perl)
+	if err == nil {
+		re = re.Simplify()
+		for attempt := 0; attempt < 10; attempt++ {
+			var b strings.Builder
+			g.regexp(re, &b)
+			if s := b.String(); len(s) >= minLength && len(s) <= maxLength {
+				return s
+			}
+		}
+	}
+	return g.fromAlphabet(alphanumeric, minLength, maxLength)
+}
+
+func (g *Generator) regexp(re *syntax.Regexp, b *strings.Builder) {
+	switch re.Op {
+	case syntax.OpLiteral:
+		b.WriteString(string(re.Rune))
+	case syntax.OpCharClass:
+		b.WriteRune(g.fromCharClass(re.Rune))
+	case syntax.OpAnyCharNotNL, syntax.OpAnyChar:
+		b.WriteByte(alphanumeric[g.rng.Intn(len(alphanumeric))])
+	case syntax.OpCapture:
+		g.regexp(re.Sub[0], b)
+	case syntax.OpConcat:
+		for _, sub := range re.Sub {
+			g.regexp(sub, b)
+		}
+	case syntax.OpAlternate:
+		g.regexp(re.Sub[g.rng.Intn(len(re.Sub))], b)
+	case syntax.OpStar, syntax.OpPlus, syntax.OpQuest, syntax.OpRepeat:
+		min, max := re.Min, re.Max
+		switch re.Op {
+		case syntax.OpStar:
+			min, max = 0, -1
+		case syntax.OpPlus:
+			min, max = 1, -1
+		case syntax.OpQuest:
+			min, max = 0, 1
+		}
+		if max < 0 || max > min+maxRepeat {
+			max = min + maxRepeat
+		}
+		for i := min + g.rng.Intn(max-min+1); i > 0; i-- {
+			g.regexp(re.Sub[0], b)
+		}
+	}
+}
+
+// fromCharClass returns a random printable ASCII character from the
+// character class given as pairs of rune ranges, if possible.
+func (g *Generator) fromCharClass(ranges []rune) rune {
+	var candidates []rune
+	for i := 0; i+1 < len(ranges); i += 2 {
+		for r := ranges[i]; r <= ranges[i+1] && r < 0x7f; r++ {
+			if r >= 0x20 {
+				candidates = append(candidates, r)
+			}
+		}
+	}
+	if len(candidates) == 0 {
+		return ranges[0]
+	}
+	return candidates[g.rng.Intn(len(candidates))]
+}
+
+// flatten resolves allOf clauses, which must all be satisfied.
+func flatten(schemas []*schema) []*schema {
+	var out []*schema
+	for _, s := range schemas {
+		out = append(out, s)
+		out = append(out, flatten(s.AllOf)...)
+	}
+	return out
+}
+
+// allowedTypes returns the types satisfying all given schemas.
+func allowedTypes(schemas []*schema) map[string]bool {
+	var types map[string]bool
+	for _, s := range schemas {
+		var st []string
+		switch t := s.Type.(type) {
+		case string:
+			st = []string{t}
+		case []interface{}:
+			for _, v := range t {
+				if v, ok := v.(string); ok {
+					st = append(st, v)
+				}
+			}
+		default:
+			continue
+		}
+		allowed := make(map[string]bool)
+		for _, t := range st {
+			if types == nil || types[t] || t == "integer" && types["number"] {
+				allowed[t] = true
+			}
+			if t == "number" && types["integer"] {
+				allowed["integer"] = true
+			}
+		}
+		types = allowed
+	}
+	if types == nil {
+		types = make(map[string]bool)
+	}
+	return types
+}
+
+func join(path, name string) string {
+	if path == "" {
+		return name
+	}
+	return path + "." + name
+}
+
+func allPresent(names []string, present map[string]bool) bool {
+	for _, name := range names {
+		if !present[name] {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,146 @@
+// Licensed to Elasticsearch B.V. under one or more contributor
+// license agreements. See the NOTICE file distributed with
+// this work for additional information regarding copyright
+// ownership. Elasticsearch B.V. licenses this file to you under
+// the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package generator
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"math/rand"
+	"regexp"
+	"testing"
+	"time"
+
+	"github.com/santhosh-tekuri/jsonschema"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/elastic/apm-server/beater/config"
+	errorschema "github.com/elastic/apm-server/model/error/generated/schema"
+	metadataschema "github.com/elastic/apm-server/model/metadata/generated/schema"
+	metricsetschema "github.com/elastic/apm-server/model/metricset/generated/schema"
+	spanschema "github.com/elastic/apm-server/model/span/generated/schema"
+	transactionschema "github.com/elastic/apm-server/model/transaction/generated/schema"
+	"github.com/elastic/apm-server/processor/stream"
+	"github.com/elastic/apm-server/publish"
+	"github.com/elastic/apm-server/utility"
+)
+
+const iterations = 200
+
+type eventSchema struct {
+	name     string
+	schema   string
+	compiled *jsonschema.Schema
+}
+
+var intakeSchemas = []eventSchema{
+	{"error", errorschema.ModelSchema, errorschema.CompiledModelSchema()},
+	{"metricset", metricsetschema.ModelSchema, metricsetschema.CompiledModelSchema()},
+	{"span", spanschema.ModelSchema, spanschema.CompiledModelSchema()},
+	{"transaction", transactionschema.ModelSchema, transactionschema.CompiledModelSchema()},
+}
+
+func TestGenerateValidatesAgainstSchema(t *testing.T) {
+	schemas := append(intakeSchemas,
+		eventSchema{"metadata", metadataschema.ModelSchema, metadataschema.CompiledModelSchema()})
+	for _, s := range schemas {
+		t.Run(s.name, func(t *testing.T) {
+			g, err := New(s.schema, rand.New(rand.NewSource(1)))
+			require.NoError(t, err)
+			for i := 0; i < iterations; i++ {
+				doc := roundtrip(t, g.GenerateObject())
+				require.NoError(t, s.compiled.ValidateInterface(doc), "%v", doc)
+			}
+		})
+	}
+}
+
+func TestGenerateDecodes(t *testing.T) {
+	cfg := config.DefaultConfig("7.x")
+	p := stream.BackendProcessor(cfg)
+	metadata, err := NewIntake("metadata", rand.New(rand.NewSource(1)))
+	require.NoError(t, err)
+	report := func(ctx context.Context, req publish.PendingReq) error {
+		for _, transformable := range req.Transformables {
+			transformable.Transform(ctx, req.Tcontext)
+		}
+		return nil
+	}
+
+	for _, s := range intakeSchemas {
+		t.Run(s.name, func(t *testing.T) {
+			g, err := NewIntake(s.name, rand.New(rand.NewSource(1)))
+			require.NoError(t, err)
+			for i := 0; i < iterations; i++ {
+				var buf bytes.Buffer
+				enc := json.NewEncoder(&buf)
+				require.NoError(t, enc.Encode(map[string]interface{}{"metadata": metadata.GenerateObject()}))
+				require.NoError(t, enc.Encode(map[string]interface{}{s.name: g.GenerateObject()}))
+
+				ctx := utility.ContextWithRequestTime(context.Background(), time.Now())
+				result := p.HandleStream(ctx, nil, map[string]interface{}{}, &buf, report)
+				require.Empty(t, result.Errors, buf.String())
+				assert.Equal(t, 1, result.Accepted)
+			}
+		})
+	}
+}
+
+func TestGenerateDeterministic(t *testing.T) {
+	generate := func() []byte {
+		g, err := New(transactionschema.ModelSchema, rand.New(rand.NewSource(42)))
+		require.NoError(t, err)
+		out, err := json.Marshal(g.GenerateObject())
+		require.NoError(t, err)
+		return out
+	}
+	assert.Equal(t, generate(), generate())
+}
+
+func TestGenerateStrings(t *testing.T) {
+	g, err := New(`{
+		"type": "object",
+		"properties": {
+			"enum": {"type": "string", "enum": ["a", "b"]},
+			"limited": {"type": "string", "maxLength": 4, "minLength": 2},
+			"pattern": {"type": "string", "pattern": "^[a-zA-Z0-9 _-]+$", "maxLength": 8},
+			"date": {"type": "string", "format": "date-time", "pattern": "Z$"}
+		},
+		"required": ["enum", "limited", "pattern", "date"]
+	}`, rand.New(rand.NewSource(1)))
+	require.NoError(t, err)
+	pattern := regexp.MustCompile("^[a-zA-Z0-9 _-]+$")
+	for i := 0; i < iterations; i++ {
+		out := g.GenerateObject()
+		assert.Contains(t, []interface{}{"a", "b"}, out["enum"])
+		assert.True(t, len(out["limited"].(string)) >= 2 && len(out["limited"].(string)) <= 4, out["limited"])
+		assert.Regexp(t, pattern, out["pattern"])
+		assert.True(t, len(out["pattern"].(string)) <= 8, out["pattern"])
+		_, err := time.Parse(time.RFC3339Nano, out["date"].(string))
+		assert.NoError(t, err)
+	}
+}
+
+func roundtrip(t *testing.T, in interface{}) interface{} {
+	data, err := json.Marshal(in)
+	require.NoError(t, err)
+	var out interface{}
+	require.NoError(t, json.Unmarshal(data, &out))
+	return out
+}
@@ -0,0 +1,70 @@
+// Licensed to Elasticsearch B.V. under one or more contributor
+// license agreements. See the NOTICE file distributed with
+// this work for additional information regarding copyright
+// ownership. Elasticsearch B.V. licenses this file to you under
+// the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package generator
+
+import (
+	"math/rand"
+	"strconv"
+	"strings"
+
+	"github.com/pkg/errors"
+
+	errorschema "github.com/elastic/apm-server/model/error/generated/schema"
+	metadataschema "github.com/elastic/apm-server/model/metadata/generated/schema"
+	metricsetschema "github.com/elastic/apm-server/model/metricset/generated/schema"
+	spanschema "github.com/elastic/apm-server/model/span/generated/schema"
+	transactionschema "github.com/elastic/apm-server/model/transaction/generated/schema"
+)
+
+// IntakeSchemas holds the JSON schemas of the intake v2 event types, keyed by event type.
+var IntakeSchemas = map[string]string{
+	"metadata":    metadataschema.ModelSchema,
+	"error":       errorschema.ModelSchema,
+	"metricset":   metricsetschema.ModelSchema,
+	"span":        spanschema.ModelSchema,
+	"transaction": transactionschema.ModelSchema,
+}
+
+// NewIntake returns a Generator for the given intake v2 event type,
+// with values constrained by IntakeOverride.
+func NewIntake(eventType string, rng *rand.Rand) (*Generator, error) {
+	schema, ok := IntakeSchemas[eventType]
+	if !ok {
+		return nil, errors.Errorf("unknown event type %q", eventType)
+	}
+	g, err := New(schema, rng)
+	if err != nil {
+		return nil, err
+	}
+	g.Override = IntakeOverride
+	return g, nil
+}
+
+// IntakeOverride constrains values of intake payloads which are restricted
+// further by the decoders than by the JSON schemas.
+func IntakeOverride(path string, rng *rand.Rand) (interface{}, bool) {
+	switch {
+	case path == "schema_version":
+		// only known schema versions are accepted, let the server select one
+		return nil, true
+	case strings.HasSuffix(path, "url.port"):
+		// ports may be given as strings, but must be numeric
+		return strconv.Itoa(1 + rng.Intn(65535)), true
+	}
+	return nil, false
+}
@@ -23,6 +23,7 @@ import (
 	"fmt"
 	"regexp"
 	"strings"
+	"sync"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
@@ -49,6 +50,11 @@ type ProcessorSetup struct {
 	Schema string
 	// prefix schema fields with this
 	SchemaPrefix string
+	// Goroutines, if greater than 1, makes payload mutation tests validate and
+	// decode each mutated payload concurrently in as many goroutines, to catch
+	// data races in shared schema validators, caches and transformations when
+	// run with -race.
+	Goroutines int
 }
 
 type SchemaTestData struct {
@@ -96,7 +102,7 @@ func (ps *ProcessorSetup) PayloadAttrsMatchJsonSchema(t *testing.T, payloadAttrs
 	payload, err := ps.Proc.LoadPayload(ps.FullPayloadPath)
 	require.NoError(t, err, fmt.Sprintf("File %s not loaded", ps.FullPayloadPath))
 	payloadAttrs := NewSet()
-	flattenJsonKeys(payload, "", payloadAttrs)
+	flattenJsonKeys(payloadEvents(payload), "", payloadAttrs)
 
 	ps.AttrsMatchJsonSchema(t, payloadAttrs, payloadAttrsNotInSchema, schemaAttrsNotInPayload)
 }
@@ -122,7 +128,25 @@ func (ps *ProcessorSetup) AttrsMatchJsonSchema(t *testing.T, payloadAttrs, paylo
 // - `required`: ensure required keys must not be missing or nil
 // - `conditionally required`: prepare payload according to conditions, then
 //   ensure required keys must not be missing
+//
+// Required and conditionally required keys are derived from the schema's `required`,
+// `anyOf` and `if`/`then` clauses. requiredKeys and condRequiredKeys hold additional keys
+// required by the processor but not expressed in the schema; keys already derived from
+// the schema are flagged, to prevent the lists from drifting apart from the schema.
 func (ps *ProcessorSetup) AttrsPresence(t *testing.T, requiredKeys *Set, condRequiredKeys map[string]Condition) {
+	schema, err := ParseSchema(ps.Schema)
+	require.NoError(t, err)
+	schemaRequiredKeys, schemaCondRequiredKeys := SchemaRequiredKeys(schema, ps.SchemaPrefix)
+
+	derived := Intersection(requiredKeys, schemaRequiredKeys)
+	assertEmptySet(t, derived, fmt.Sprintf("Required keys derived from the schema, remove them from the list: %v", derived))
+	requiredKeys = Union(requiredKeys, schemaRequiredKeys)
+	for k, cond := range condRequiredKeys {
+		_, ok := schemaCondRequiredKeys[k]
+		assert.False(t, ok, fmt.Sprintf("Conditionally required key %s derived from the schema, remove it from the list", k))
+		schemaCondRequiredKeys[k] = cond
+	}
+	condRequiredKeys = schemaCondRequiredKeys
 
 	required := Union(requiredKeys, NewSet(
 		"service",
@@ -140,25 +164,26 @@ func (ps *ProcessorSetup) AttrsPresence(t *testing.T, requiredKeys *Set, condReq
 	require.NoError(t, err)
 
 	payloadKeys := NewSet()
-	flattenJsonKeys(payload, "", payloadKeys)
+	flattenJsonKeys(payloadEvents(payload), "", payloadKeys)
 
-	for _, k := range payloadKeys.Array() {
-		key := k.(string)
+	for _, key := range payloadKeys.Strings() {
 		_, keyLast := splitKey(key)
 
-		//test sending nil value for key
-		ps.changePayload(t, key, nil, Condition{}, upsertFn,
+		//test sending nil value for key, given the conditions the key is required under
+		cond := condRequiredKeys[key]
+		ps.changePayload(t, key, nil, cond, upsertFn,
 			func(k string) (bool, []string) {
-				return !required.ContainsStrPattern(k), []string{keyLast}
+				// streaming decoders reject nil values for required objects as unexpected type
+				return !required.ContainsStrPattern(k), []string{keyLast, "unexpected value type"}
 			},
 		)
 
 		//test removing key from payload
-		cond := condRequiredKeys[key]
 		ps.changePayload(t, key, nil, cond, deleteFn,
 			func(k string) (bool, []string) {
 				errMsgs := []string{
 					fmt.Sprintf("missing properties: \"%s\"", keyLast),
+					fmt.Sprintf("%s: missing required property", keyLast),
 					"did not recognize object type",
 				}
 
@@ -175,7 +200,9 @@ func (ps *ProcessorSetup) AttrsPresence(t *testing.T, requiredKeys *Set, condReq
 
 // Test that field names indexed as `keywords` in Elasticsearch, have the same
 // length limitation on the Intake API.
-// APM Server has set all keyword restrictions to length 1024.
+// The limitation is read from the `ignore_above` setting of each field in the
+// ES template, defaulting to 1024 as libbeat does; fields using the ES default
+// by setting it to -1 are not length restricted.
 //
 // keywordExceptionKeys: attributes defined as keywords in the ES template, but
 //   do not require a length restriction in the json schema, e.g. due to regex
@@ -185,35 +212,30 @@ func (ps *ProcessorSetup) AttrsPresence(t *testing.T, requiredKeys *Set, condReq
 func (ps *ProcessorSetup) KeywordLimitation(t *testing.T, keywordExceptionKeys *Set,
 	templateToSchema []FieldTemplateMapping) {
 
-	// fetch keyword restricted field names from ES template
+	// fetch keyword restricted field names and their limits from ES template
 	keywordFields, err := fetchFlattenedFieldNames(ps.TemplatePaths, hasName,
-		func(f mapping.Field) bool { return f.Type == "keyword" })
+		func(f mapping.Field) bool { return f.Type == "keyword" && f.IgnoreAbove != -1 })
+	require.NoError(t, err)
+	ignoreAbove, err := fetchIgnoreAbove(ps.TemplatePaths)
 	require.NoError(t, err)
 
 	// fetch length restricted field names from json schema
-	maxLengthFilter := func(s *Schema) bool {
-		return s.MaxLength > 0
-	}
-	schemaKeys := NewSet()
 	schema, err := ParseSchema(ps.Schema)
 	require.NoError(t, err)
-	FlattenSchemaNames(schema, "", maxLengthFilter, schemaKeys)
+	maxLengths := make(map[string]int)
+	flattenSchemaMaxLengths(schema, "", maxLengths)
 
-	t.Log("Schema keys:", schemaKeys.Array())
+	t.Log("Schema keys:", maxLengths)
 
 	keywordFields = differenceWithGroup(keywordFields, keywordExceptionKeys)
 
-	for _, k := range keywordFields.Array() {
-		key := k.(string)
-
-		for _, ts := range templateToSchema {
-			if strings.HasPrefix(key, ts.Template) {
-				key = strings.Replace(key, ts.Template, ts.Mapping, 1)
-				break
-			}
+	for _, k := range keywordFields.Strings() {
+		key := templateKeyToSchema(k, templateToSchema)
+		maxLength, ok := maxLengths[key]
+		if assert.True(t, ok, "Expected <%s> (original: <%s>) to have the MaxLength limit set because it gets indexed as 'keyword'", key, k) {
+			assert.Equal(t, ignoreAbove[k], maxLength,
+				"Expected MaxLength of <%s> (original: <%s>) to match the 'ignore_above' setting of the ES template", key, k)
 		}
-
-		assert.True(t, schemaKeys.Contains(key), "Expected <%s> (original: <%s>) to have the MaxLength limit set because it gets indexed as 'keyword'", key, k.(string))
 	}
 }
 
@@ -267,19 +289,24 @@ func (ps *ProcessorSetup) changePayload(
 	// - ensure specified keys being present
 	for k, val := range condition.Existence {
 		fnKey, keyToChange := splitKey(k)
-
-		payload = iterateMap(payload, "", fnKey, keyToChange, val, upsertFn)
+		payload = mutatePayload(payload, func(p interface{}) interface{} {
+			return iterateMap(p, "", fnKey, keyToChange, val, upsertFn)
+		})
 	}
 
 	// - ensure specified keys being absent
 	for _, k := range condition.Absence {
 		fnKey, keyToChange := splitKey(k)
-		payload = iterateMap(payload, "", fnKey, keyToChange, nil, deleteFn)
+		payload = mutatePayload(payload, func(p interface{}) interface{} {
+			return iterateMap(p, "", fnKey, keyToChange, nil, deleteFn)
+		})
 	}
 
 	// change payload for key to test
 	fnKey, keyToChange := splitKey(key)
-	payload = iterateMap(payload, "", fnKey, keyToChange, val, changeFn)
+	payload = mutatePayload(payload, func(p interface{}) interface{} {
+		return iterateMap(p, "", fnKey, keyToChange, val, changeFn)
+	})
 
 	wantLog := false
 	defer func() {
@@ -289,15 +316,16 @@ func (ps *ProcessorSetup) changePayload(
 	}()
 
 	// run actual validation
-	err = ps.Proc.Validate(payload)
+	err = ps.concurrently(t, payload, ps.Proc.Validate)
 	if shouldValidate, errMsgs := validateFn(key); shouldValidate {
 		wantLog = !assert.NoError(t, err, fmt.Sprintf("Expected <%v> for key <%s> to be valid", val, key))
-		err = ps.Proc.Decode(payload)
+		err = ps.concurrently(t, payload, ps.Proc.Decode)
 		assert.NoError(t, err)
 	} else {
 		if assert.Error(t, err, fmt.Sprintf(`Expected error for key <%v>, but received no error.`, key)) {
 			for _, errMsg := range errMsgs {
 				if strings.Contains(strings.ToLower(err.Error()), errMsg) {
+					ps.recordConstraintHit(key, err)
 					return
 				}
 			}
@@ -309,6 +337,57 @@ func (ps *ProcessorSetup) changePayload(
 	}
 }
 
+// concurrently runs fn for payload in ps.Goroutines goroutines, if configured, and returns
+// its result after checking that all goroutines agree on it.
+// Each goroutine gets its own copy of the payload, as decoding may modify its input,
+// which is never shared in production.
+func (ps *ProcessorSetup) concurrently(t *testing.T, payload interface{}, fn func(interface{}) error) error {
+	if ps.Goroutines <= 1 {
+		return fn(payload)
+	}
+	payloads := make([]interface{}, ps.Goroutines)
+	for i := range payloads {
+		payloads[i] = deepCopy(payload)
+	}
+	errs := make([]error, ps.Goroutines)
+	var wg sync.WaitGroup
+	for i := range errs {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			errs[i] = fn(payloads[i])
+		}(i)
+	}
+	wg.Wait()
+	for _, err := range errs[1:] {
+		assert.Equal(t, fmt.Sprint(errs[0]), fmt.Sprint(err), "concurrent runs returned different results")
+	}
+	return errs[0]
+}
+
+// deepCopy copies the maps and slices of a decoded JSON value.
+func deepCopy(v interface{}) interface{} {
+	switch v := v.(type) {
+	case *StreamPayload:
+		metadata, _ := deepCopy(v.Metadata).(map[string]interface{})
+		events, _ := deepCopy(v.Events).([]interface{})
+		return &StreamPayload{Metadata: metadata, Events: events}
+	case map[string]interface{}:
+		out := make(map[string]interface{}, len(v))
+		for k, e := range v {
+			out[k] = deepCopy(e)
+		}
+		return out
+	case []interface{}:
+		out := make([]interface{}, len(v))
+		for i, e := range v {
+			out[i] = deepCopy(e)
+		}
+		return out
+	}
+	return v
+}
+
 func createStr(n int, start string) string {
 	buf := bytes.NewBufferString(start)
 	for buf.Len() < n {
@@ -380,12 +459,17 @@ type Schema struct {
 	Title                string
 	Properties           map[string]*Schema
 	AdditionalProperties interface{} // bool or object
-	PatternProperties    obj
+	PatternProperties    map[string]*Schema
 	Items                *Schema
 	AllOf                []*Schema
 	OneOf                []*Schema
 	AnyOf                []*Schema
 	MaxLength            int
+	Format               string
+	Type                 interface{} // string or array of strings
+	Required             []string
+	If                   *Schema
+	Then                 *Schema
 }
 
 func ParseSchema(s string) (*Schema, error) {
@@ -417,6 +501,152 @@ func FlattenSchemaNames(s *Schema, prefix string, filter func(*Schema) bool, fla
 	}
 }
 
+// flattenSchemaMaxLengths collects the maxLength of all length restricted schema fields.
+func flattenSchemaMaxLengths(s *Schema, prefix string, flattened map[string]int) {
+	for k, v := range s.Properties {
+		key := strConcat(prefix, k, ".")
+		if v.MaxLength > 0 {
+			flattened[key] = v.MaxLength
+		}
+		flattenSchemaMaxLengths(v, key, flattened)
+	}
+	if s.Items != nil {
+		flattenSchemaMaxLengths(s.Items, prefix, flattened)
+	}
+	for _, schemas := range [][]*Schema{s.AllOf, s.OneOf, s.AnyOf} {
+		for _, e := range schemas {
+			flattenSchemaMaxLengths(e, prefix, flattened)
+		}
+	}
+}
+
+// SchemaRequiredKeys derives the keys required by the given schema from its `required` clauses,
+// and the conditionally required keys from its `anyOf` and `if`/`then` clauses, along with the
+// conditions under which they are required.
+//
+// Required keys must neither be removed nor set to nil. Required keys allowing nil values are
+// returned as conditionally required keys without conditions, as they only must not be removed.
+func SchemaRequiredKeys(s *Schema, prefix string) (*Set, map[string]Condition) {
+	c := requiredKeysCollector{
+		required:     NewSet(),
+		notNullable:  NewSet(),
+		condRequired: make(map[string]Condition),
+		thenRequired: make(map[string]int),
+	}
+	c.collect(s, prefix)
+
+	required := NewSet()
+	if prefix != "" {
+		required.Add(prefix)
+	}
+	for _, k := range c.required.Strings() {
+		if c.notNullable.Contains(k) {
+			required.Add(k)
+		} else {
+			c.addCondition(k, Condition{})
+		}
+	}
+	for k, cond := range c.condRequired {
+		if cond.Absence != nil || cond.Existence != nil {
+			required.Add(k)
+		}
+	}
+	return required, c.condRequired
+}
+
+type requiredKeysCollector struct {
+	required     *Set
+	notNullable  *Set
+	condRequired map[string]Condition
+	// thenRequired holds the number of keys required by the `then` clause
+	// a conditionally required key was derived from
+	thenRequired map[string]int
+}
+
+func (c *requiredKeysCollector) collect(s *Schema, prefix string) {
+	for _, k := range s.Required {
+		c.required.Add(strConcat(prefix, k, "."))
+	}
+	c.collectProperties(s, prefix)
+	if s.Items != nil {
+		c.collect(s.Items, prefix)
+	}
+	for _, e := range s.AllOf {
+		c.collect(e, prefix)
+	}
+
+	// one of the alternatives is required, so a key is required in absence of the others
+	for i, e := range s.AnyOf {
+		var others []string
+		for j, other := range s.AnyOf {
+			if i != j {
+				for _, k := range other.Required {
+					others = append(others, strConcat(prefix, k, "."))
+				}
+			}
+		}
+		for _, k := range e.Required {
+			c.addCondition(strConcat(prefix, k, "."), Condition{Absence: others})
+		}
+		c.collectProperties(e, prefix)
+	}
+
+	// keys required by `then` are required in presence of the keys required by `if`
+	if s.If != nil && s.Then != nil {
+		existence := make(map[string]interface{})
+		for _, k := range s.If.Required {
+			if v, ok := s.If.Properties[k]; ok && v.allowsType("string") {
+				existence[strConcat(prefix, k, ".")] = "abc123"
+			}
+		}
+		for _, k := range s.Then.Required {
+			// prefer the most specific rule, so that validation reports only the key itself missing
+			key := strConcat(prefix, k, ".")
+			if n, ok := c.thenRequired[key]; ok && n <= len(s.Then.Required) {
+				continue
+			}
+			c.thenRequired[key] = len(s.Then.Required)
+			c.condRequired[key] = Condition{Existence: existence}
+		}
+		c.collectProperties(s.Then, prefix)
+	}
+}
+
+func (c *requiredKeysCollector) collectProperties(s *Schema, prefix string) {
+	for k, v := range s.Properties {
+		key := strConcat(prefix, k, ".")
+		if v.Type != nil && !v.allowsType("null") {
+			c.notNullable.Add(key)
+		}
+		c.collect(v, key)
+	}
+	for _, v := range s.PatternProperties {
+		// match any key, following the notation of the key sets
+		c.collect(v, strConcat(prefix, "+", "."))
+	}
+}
+
+// addCondition adds the condition for the given key, unless there is one already.
+func (c *requiredKeysCollector) addCondition(key string, cond Condition) {
+	if _, ok := c.condRequired[key]; !ok {
+		c.condRequired[key] = cond
+	}
+}
+
+func (s *Schema) allowsType(t string) bool {
+	switch typ := s.Type.(type) {
+	case string:
+		return typ == t
+	case []interface{}:
+		for _, v := range typ {
+			if v == t {
+				return true
+			}
+		}
+	}
+	return false
+}
+
 func flattenJsonKeys(data interface{}, prefix string, flattened *Set) {
 	if d, ok := data.(obj); ok {
 		for k, v := range d {
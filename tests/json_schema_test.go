@@ -21,6 +21,7 @@ import (
 	"testing"
 
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 )
 
 func TestIterateMap(t *testing.T) {
@@ -98,3 +99,52 @@ func TestIterateMap(t *testing.T) {
 		assert.Equal(t, d.result, out)
 	}
 }
+
+func TestSchemaRequiredKeys(t *testing.T) {
+	schema, err := ParseSchema(`{
+		"properties": {
+			"id": {"type": "string"},
+			"name": {"type": ["string", "null"]},
+			"trace_id": {"type": ["string", "null"]},
+			"parent_id": {"type": ["string", "null"]},
+			"frames": {
+				"type": "array",
+				"items": {
+					"anyOf": [
+						{"required": ["filename"], "properties": {"filename": {"type": "string"}}},
+						{"required": ["classname"], "properties": {"classname": {"type": "string"}}}
+					]
+				}
+			},
+			"samples": {"patternProperties": {"^.*$": {"required": ["value"], "properties": {"value": {"type": "number"}}}}}
+		},
+		"allOf": [
+			{"required": ["id", "name"]},
+			{"if": {"required": ["trace_id"], "properties": {"trace_id": {"type": "string"}}},
+			 "then": {"required": ["parent_id"], "properties": {"parent_id": {"type": "string"}}}}
+		]
+	}`)
+	require.NoError(t, err)
+
+	required, condRequired := SchemaRequiredKeys(schema, "event")
+	assert.ElementsMatch(t, []interface{}{
+		"event", "event.id", "event.samples.+.value",
+		"event.frames.filename", "event.frames.classname", "event.parent_id",
+	}, required.Array())
+	assert.Equal(t, map[string]Condition{
+		"event.name":             {},
+		"event.frames.filename":  {Absence: []string{"event.frames.classname"}},
+		"event.frames.classname": {Absence: []string{"event.frames.filename"}},
+		"event.parent_id":        {Existence: map[string]interface{}{"event.trace_id": "abc123"}},
+	}, condRequired)
+}
+
+func TestDeepCopy(t *testing.T) {
+	in := map[string]interface{}{"a": []interface{}{map[string]interface{}{"b": 1}}, "c": "d"}
+	out := deepCopy(in).(map[string]interface{})
+	assert.Equal(t, in, out)
+
+	out["a"].([]interface{})[0].(map[string]interface{})["b"] = 2
+	delete(out, "c")
+	assert.Equal(t, map[string]interface{}{"a": []interface{}{map[string]interface{}{"b": 1}}, "c": "d"}, in)
+}
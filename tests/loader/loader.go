@@ -18,15 +18,32 @@
 package loader
 
 import (
+	"bufio"
+	"bytes"
+	"fmt"
 	"io"
 	"io/ioutil"
 	"os"
 	"path/filepath"
 	"runtime"
+	"sort"
+	"strings"
 
 	"github.com/elastic/apm-server/decoder"
 )
 
+// ExpectationSuffix is the suffix of files holding the errors expected for a
+// negative payload stored next to it, with the same name.
+const ExpectationSuffix = ".expected"
+
+// NegativePayload is a known-bad payload, along with substrings expected in
+// the errors reported when processing it.
+type NegativePayload struct {
+	Name           string
+	Data           []byte
+	ExpectedErrors []string
+}
+
 func LoadData(file string) (map[string]interface{}, error) {
 	return unmarshalData(FindFile(file))
 }
@@ -73,3 +90,73 @@ func unmarshalData(filePath string, err error) (map[string]interface{}, error) {
 	defer r.Close()
 	return decoder.DecodeJSONData(r)
 }
+
+// LoadNegativePayloads loads all payloads stored in the given directory, each of which
+// must come with an expectation file, named after the payload with ExpectationSuffix
+// appended. Expectation files list one expected error substring per line; empty lines
+// and lines starting with # are ignored.
+//
+// This allows adding regression fixtures for validation bugs without writing code.
+func LoadNegativePayloads(dir ...string) ([]NegativePayload, error) {
+	path, err := FindFile(dir...)
+	if err != nil {
+		return nil, err
+	}
+	entries, err := ioutil.ReadDir(path)
+	if err != nil {
+		return nil, err
+	}
+	names := make(map[string]bool)
+	for _, e := range entries {
+		if !e.IsDir() {
+			names[e.Name()] = true
+		}
+	}
+
+	var payloads []NegativePayload
+	for name := range names {
+		if strings.HasSuffix(name, ExpectationSuffix) {
+			if !names[strings.TrimSuffix(name, ExpectationSuffix)] {
+				return nil, fmt.Errorf("expectation file %s without payload", name)
+			}
+			continue
+		}
+		if !names[name+ExpectationSuffix] {
+			return nil, fmt.Errorf("payload %s without expectation file %s", name, name+ExpectationSuffix)
+		}
+		data, err := ioutil.ReadFile(filepath.Join(path, name))
+		if err != nil {
+			return nil, err
+		}
+		expected, err := readExpectations(filepath.Join(path, name+ExpectationSuffix))
+		if err != nil {
+			return nil, err
+		}
+		payloads = append(payloads, NegativePayload{Name: name, Data: data, ExpectedErrors: expected})
+	}
+	sort.Slice(payloads, func(i, j int) bool { return payloads[i].Name < payloads[j].Name })
+	return payloads, nil
+}
+
+func readExpectations(path string) ([]string, error) {
+	data, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	var expected []string
+	scanner := bufio.NewScanner(bytes.NewReader(data))
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		expected = append(expected, line)
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+	if len(expected) == 0 {
+		return nil, fmt.Errorf("no expected errors listed in %s", path)
+	}
+	return expected, nil
+}
@@ -0,0 +1,128 @@
+// Licensed to Elasticsearch B.V. under one or more contributor
+// license agreements. See the NOTICE file distributed with
+// this work for additional information regarding copyright
+// ownership. Elasticsearch B.V. licenses this file to you under
+// the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package loader
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"os"
+	"path/filepath"
+	"time"
+)
+
+// FixtureCacheDir is the directory in which fixtures fetched by LoadDataFromURL are cached.
+// It defaults to APM_FIXTURES_CACHE_DIR; if empty, a directory within the user's cache directory is used.
+var FixtureCacheDir = os.Getenv("APM_FIXTURES_CACHE_DIR")
+
+var fixtureClient = &http.Client{Timeout: 30 * time.Second}
+
+// LoadDataFromFS reads the fixture with the given name from fs. Any http.FileSystem is supported,
+// e.g. a directory (http.Dir) or fixtures compiled into the test binary by an asset generator.
+func LoadDataFromFS(fs http.FileSystem, name string) ([]byte, error) {
+	r, err := LoadDataAsStreamFromFS(fs, name)
+	if err != nil {
+		return nil, err
+	}
+	defer r.Close()
+	return ioutil.ReadAll(r)
+}
+
+// LoadDataAsStreamFromFS opens the fixture with the given name from fs.
+func LoadDataAsStreamFromFS(fs http.FileSystem, name string) (io.ReadCloser, error) {
+	f, err := fs.Open(name)
+	if err != nil {
+		return nil, err
+	}
+	if info, err := f.Stat(); err == nil && info.IsDir() {
+		f.Close()
+		return nil, fmt.Errorf("fixture %s is a directory", name)
+	}
+	return f, nil
+}
+
+// LoadDataFromURL fetches the fixture published at url, e.g. by an agent repository.
+// Fetched fixtures are cached in FixtureCacheDir, so they are only downloaded once.
+func LoadDataFromURL(url string) ([]byte, error) {
+	dir, err := fixtureCacheDir()
+	if err != nil {
+		return nil, err
+	}
+	sum := sha256.Sum256([]byte(url))
+	cached := filepath.Join(dir, hex.EncodeToString(sum[:]))
+	if data, err := ioutil.ReadFile(cached); err == nil {
+		return data, nil
+	}
+
+	data, err := fetch(url)
+	if err != nil {
+		return nil, err
+	}
+	if err := writeCacheFile(dir, cached, data); err != nil {
+		return nil, err
+	}
+	return data, nil
+}
+
+func fetch(url string) ([]byte, error) {
+	resp, err := fixtureClient.Get(url)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("fetching fixture %s failed: %s", url, resp.Status)
+	}
+	return ioutil.ReadAll(resp.Body)
+}
+
+func fixtureCacheDir() (string, error) {
+	if FixtureCacheDir != "" {
+		return FixtureCacheDir, nil
+	}
+	dir, err := os.UserCacheDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(dir, "apm-server", "fixtures"), nil
+}
+
+// writeCacheFile writes data to a temporary file first, so concurrent tests
+// never read partially written fixtures.
+func writeCacheFile(dir, path string, data []byte) error {
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return err
+	}
+	tmp, err := ioutil.TempFile(dir, ".fixture")
+	if err != nil {
+		return err
+	}
+	if _, err := tmp.Write(data); err != nil {
+		tmp.Close()
+		os.Remove(tmp.Name())
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		os.Remove(tmp.Name())
+		return err
+	}
+	return os.Rename(tmp.Name(), path)
+}
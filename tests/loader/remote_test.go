@@ -0,0 +1,75 @@
+// Licensed to Elasticsearch B.V. under one or more contributor
+// license agreements. See the NOTICE file distributed with
+// this work for additional information regarding copyright
+// ownership. Elasticsearch B.V. licenses this file to you under
+// the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package loader
+
+import (
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLoadDataFromFS(t *testing.T) {
+	path, err := FindFile("..", "testdata", "intake-v2")
+	require.NoError(t, err)
+	fs := http.Dir(path)
+
+	data, err := LoadDataFromFS(fs, "transactions.ndjson")
+	require.NoError(t, err)
+	expected, err := LoadDataAsBytes("../testdata/intake-v2/transactions.ndjson")
+	require.NoError(t, err)
+	assert.Equal(t, expected, data)
+
+	_, err = LoadDataFromFS(fs, "missing.ndjson")
+	assert.True(t, os.IsNotExist(err))
+	_, err = LoadDataFromFS(fs, "invalid")
+	assert.Error(t, err)
+}
+
+func TestLoadDataFromURL(t *testing.T) {
+	dir, err := ioutil.TempDir("", "fixtures")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+	defer func(d string) { FixtureCacheDir = d }(FixtureCacheDir)
+	FixtureCacheDir = dir
+
+	var requests int
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		if r.URL.Path != "/fixture.ndjson" {
+			http.NotFound(w, r)
+			return
+		}
+		w.Write([]byte(`{"metadata":{}}`))
+	}))
+	defer srv.Close()
+
+	for i := 0; i < 2; i++ {
+		data, err := LoadDataFromURL(srv.URL + "/fixture.ndjson")
+		require.NoError(t, err)
+		assert.Equal(t, `{"metadata":{}}`, string(data))
+	}
+	assert.Equal(t, 1, requests, "fixture should be served from cache")
+
+	_, err = LoadDataFromURL(srv.URL + "/missing.ndjson")
+	assert.EqualError(t, err, "fetching fixture "+srv.URL+"/missing.ndjson failed: 404 Not Found")
+}
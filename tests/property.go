@@ -0,0 +1,103 @@
+// Licensed to Elasticsearch B.V. under one or more contributor
+// license agreements. See the NOTICE file distributed with
+// this work for additional information regarding copyright
+// ownership. Elasticsearch B.V. licenses this file to you under
+// the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package tests
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"math/rand"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/elastic/apm-server/tests/generator"
+)
+
+// Property-based test on payloads randomly generated from the JSON schema,
+// covering edge values such as maximum length strings and minimum numbers.
+// It checks that
+//   - any schema valid event is decoded and transformed without error
+//   - all non-empty string values sent with the event are preserved in the
+//     transformed event, except for notPreservedKeys which are not indexed.
+func (ps *ProcessorSetup) DecodeRoundTrip(t *testing.T, iterations int, notPreservedKeys *Set) {
+	require.True(t, len(ps.Schema) > 0, "Schema must be set")
+	require.True(t, len(ps.SchemaPrefix) > 0, "SchemaPrefix must be set to the event type")
+
+	rng := rand.New(rand.NewSource(1))
+	metadata, err := generator.NewIntake("metadata", rng)
+	require.NoError(t, err)
+	event, err := generator.New(ps.Schema, rng)
+	require.NoError(t, err)
+	event.Override = generator.IntakeOverride
+
+	missing := NewSet()
+	for i := 0; i < iterations; i++ {
+		raw := event.GenerateObject()
+		var buf bytes.Buffer
+		enc := json.NewEncoder(&buf)
+		require.NoError(t, enc.Encode(obj{"metadata": metadata.GenerateObject()}))
+		require.NoError(t, enc.Encode(obj{ps.SchemaPrefix: raw}))
+
+		events, err := ps.Proc.Process(buf.Bytes())
+		if !assert.NoError(t, err) || !assert.Len(t, events, 1) {
+			logPayload(t, raw)
+			return
+		}
+
+		indexed := NewSet()
+		fields, err := json.Marshal(events[0].Fields)
+		require.NoError(t, err)
+		var decoded interface{}
+		require.NoError(t, json.Unmarshal(fields, &decoded))
+		collectStrings(decoded, "", func(_, v string) { indexed.Add(v) })
+
+		collectStrings(roundtripJSON(t, raw), ps.SchemaPrefix, func(k, v string) {
+			if v != "" && !indexed.Contains(v) {
+				missing.Add(k)
+			}
+		})
+	}
+	missing = differenceWithGroup(missing, notPreservedKeys)
+	assertEmptySet(t, missing, fmt.Sprintf("Values not preserved in transformed events %v", missing))
+}
+
+// collectStrings calls fn with the flattened key and value of all strings in data.
+func collectStrings(data interface{}, prefix string, fn func(k, v string)) {
+	switch d := data.(type) {
+	case map[string]interface{}:
+		for k, v := range d {
+			collectStrings(v, strConcat(prefix, k, "."), fn)
+		}
+	case []interface{}:
+		for _, v := range d {
+			collectStrings(v, prefix, fn)
+		}
+	case string:
+		fn(prefix, d)
+	}
+}
+
+func roundtripJSON(t *testing.T, in interface{}) interface{} {
+	data, err := json.Marshal(in)
+	require.NoError(t, err)
+	var out interface{}
+	require.NoError(t, json.Unmarshal(data, &out))
+	return out
+}
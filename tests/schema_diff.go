@@ -0,0 +1,334 @@
+// Licensed to Elasticsearch B.V. under one or more contributor
+// license agreements. See the NOTICE file distributed with
+// this work for additional information regarding copyright
+// ownership. Elasticsearch B.V. licenses this file to you under
+// the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package tests
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// SchemaChangeKind describes how a field or constraint changed between schema versions.
+type SchemaChangeKind string
+
+const (
+	SchemaAdded   SchemaChangeKind = "added"
+	SchemaRemoved SchemaChangeKind = "removed"
+	SchemaChanged SchemaChangeKind = "changed"
+)
+
+// SchemaChange is a change of a field, or of one of its constraints if Constraint is set.
+type SchemaChange struct {
+	Field      string
+	Constraint string
+	Kind       SchemaChangeKind
+	Old, New   string
+}
+
+// Breaking reports whether the change might lead to previously valid payloads being rejected.
+func (c SchemaChange) Breaking() bool {
+	if c.Constraint == "" {
+		return c.Kind == SchemaRemoved
+	}
+	switch c.Kind {
+	case SchemaRemoved:
+		return false
+	case SchemaAdded:
+		return c.Constraint != "additionalProperties" || c.New == "false"
+	}
+	switch c.Constraint {
+	case "maxLength", "maximum", "maxItems":
+		return parseFloat(c.New) < parseFloat(c.Old)
+	case "minLength", "minimum", "minItems":
+		return parseFloat(c.New) > parseFloat(c.Old)
+	case "type", "enum":
+		return !isSubset(decodeStrings(c.Old), decodeStrings(c.New))
+	case "additionalProperties":
+		return c.New == "false"
+	}
+	return true
+}
+
+func (c SchemaChange) String() string {
+	var what string
+	if c.Constraint != "" {
+		what = " " + c.Constraint
+	}
+	switch c.Kind {
+	case SchemaAdded:
+		if c.Constraint == "" {
+			return string(c.Kind)
+		}
+		return fmt.Sprintf("%s%s: %s", c.Kind, what, c.New)
+	case SchemaRemoved:
+		if c.Constraint == "" {
+			return string(c.Kind)
+		}
+		return fmt.Sprintf("%s%s: %s", c.Kind, what, c.Old)
+	}
+	return fmt.Sprintf("%s%s: %s -> %s", c.Kind, what, c.Old, c.New)
+}
+
+// SchemaDiff holds the changes between two schema versions, sorted by field and constraint.
+type SchemaDiff []SchemaChange
+
+// Breaking returns all changes which might lead to previously valid payloads being rejected.
+func (d SchemaDiff) Breaking() SchemaDiff {
+	var breaking SchemaDiff
+	for _, c := range d {
+		if c.Breaking() {
+			breaking = append(breaking, c)
+		}
+	}
+	return breaking
+}
+
+// String returns a report of the changes per field, e.g. to be used in changelogs.
+func (d SchemaDiff) String() string {
+	var b strings.Builder
+	for i, c := range d {
+		if i == 0 || d[i-1].Field != c.Field {
+			fmt.Fprintln(&b, c.Field)
+		}
+		marker := ""
+		if c.Breaking() {
+			marker = " (breaking)"
+		}
+		fmt.Fprintf(&b, "  %s%s\n", c, marker)
+	}
+	return b.String()
+}
+
+// DiffSchemas structurally compares two versions of a JSON schema, reporting added and removed fields,
+// and added, removed and changed constraints per field. Constraints defined in allOf, anyOf and oneOf
+// clauses are attributed to the field they are defined for.
+func DiffSchemas(oldSchema, newSchema string) (SchemaDiff, error) {
+	oldFields, err := flattenSchemaConstraints(oldSchema)
+	if err != nil {
+		return nil, err
+	}
+	newFields, err := flattenSchemaConstraints(newSchema)
+	if err != nil {
+		return nil, err
+	}
+
+	var diff SchemaDiff
+	for field, oldConstraints := range oldFields {
+		newConstraints, ok := newFields[field]
+		if !ok {
+			diff = append(diff, SchemaChange{Field: field, Kind: SchemaRemoved})
+			continue
+		}
+		for name, old := range oldConstraints {
+			if updated, ok := newConstraints[name]; !ok {
+				diff = append(diff, SchemaChange{Field: field, Constraint: name, Kind: SchemaRemoved, Old: old})
+			} else if updated != old {
+				diff = append(diff, SchemaChange{Field: field, Constraint: name, Kind: SchemaChanged, Old: old, New: updated})
+			}
+		}
+		for name, updated := range newConstraints {
+			if _, ok := oldConstraints[name]; !ok {
+				diff = append(diff, SchemaChange{Field: field, Constraint: name, Kind: SchemaAdded, New: updated})
+			}
+		}
+	}
+	for field, newConstraints := range newFields {
+		if _, ok := oldFields[field]; ok {
+			continue
+		}
+		diff = append(diff, SchemaChange{Field: field, Kind: SchemaAdded})
+		for name, updated := range newConstraints {
+			diff = append(diff, SchemaChange{Field: field, Constraint: name, Kind: SchemaAdded, New: updated})
+		}
+	}
+	sort.Slice(diff, func(i, j int) bool {
+		if diff[i].Field != diff[j].Field {
+			return diff[i].Field < diff[j].Field
+		}
+		return diff[i].Constraint < diff[j].Constraint
+	})
+	return diff, nil
+}
+
+// AssertNoBreakingSchemaChanges fails the test if newSchema contains changes which might
+// lead to payloads valid according to oldSchema being rejected.
+func AssertNoBreakingSchemaChanges(t *testing.T, oldSchema, newSchema string) bool {
+	diff, err := DiffSchemas(oldSchema, newSchema)
+	if !assert.NoError(t, err) {
+		return false
+	}
+	breaking := diff.Breaking()
+	return assert.Empty(t, breaking, "breaking schema changes:\n%s", breaking)
+}
+
+// diffedConstraints are the schema keywords compared by DiffSchemas.
+var diffedConstraints = []string{
+	"type", "format", "pattern", "enum",
+	"maxLength", "minLength", "minimum", "maximum", "minItems", "maxItems",
+	"additionalProperties",
+}
+
+// flattenSchemaConstraints returns the constraints of all fields of the schema,
+// keyed by the dotted field name; pattern properties are named after their pattern.
+func flattenSchemaConstraints(schema string) (map[string]map[string]string, error) {
+	decoder := json.NewDecoder(bytes.NewBufferString(schema))
+	decoder.UseNumber()
+	var root map[string]interface{}
+	if err := decoder.Decode(&root); err != nil {
+		return nil, err
+	}
+	fields := make(map[string]map[string]string)
+	collectSchemaConstraints(root, "", fields)
+	collectSchemaRequired(root, "", fields)
+	return fields, nil
+}
+
+func collectSchemaConstraints(s map[string]interface{}, field string, fields map[string]map[string]string) {
+	if field != "" {
+		addSchemaConstraints(s, fields[field])
+	}
+	for _, properties := range []string{"properties", "patternProperties"} {
+		props, _ := s[properties].(map[string]interface{})
+		for name, p := range props {
+			prop, ok := p.(map[string]interface{})
+			if !ok {
+				continue
+			}
+			key := strConcat(field, name, ".")
+			if _, ok := fields[key]; !ok {
+				fields[key] = make(map[string]string)
+			}
+			collectSchemaConstraints(prop, key, fields)
+		}
+	}
+	if items, ok := s["items"].(map[string]interface{}); ok {
+		collectSchemaConstraints(items, field, fields)
+	}
+	for _, combinator := range []string{"allOf", "anyOf", "oneOf"} {
+		schemas, _ := s[combinator].([]interface{})
+		for _, e := range schemas {
+			if sub, ok := e.(map[string]interface{}); ok {
+				collectSchemaConstraints(sub, field, fields)
+			}
+		}
+	}
+}
+
+// collectSchemaRequired marks fields as required if listed in a `required` clause
+// applying unconditionally, i.e. not within anyOf or oneOf clauses.
+func collectSchemaRequired(s map[string]interface{}, field string, fields map[string]map[string]string) {
+	required, _ := s["required"].([]interface{})
+	for _, r := range required {
+		name, ok := r.(string)
+		if !ok {
+			continue
+		}
+		key := strConcat(field, name, ".")
+		if _, ok := fields[key]; !ok {
+			fields[key] = make(map[string]string)
+		}
+		fields[key]["required"] = "true"
+	}
+	props, _ := s["properties"].(map[string]interface{})
+	for name, p := range props {
+		if prop, ok := p.(map[string]interface{}); ok {
+			collectSchemaRequired(prop, strConcat(field, name, "."), fields)
+		}
+	}
+	if items, ok := s["items"].(map[string]interface{}); ok {
+		collectSchemaRequired(items, field, fields)
+	}
+	allOf, _ := s["allOf"].([]interface{})
+	for _, e := range allOf {
+		if sub, ok := e.(map[string]interface{}); ok {
+			collectSchemaRequired(sub, field, fields)
+		}
+	}
+}
+
+// addSchemaConstraints adds the constraints defined in s to the field's constraints.
+// Types and enum values defined in several clauses are merged.
+func addSchemaConstraints(s map[string]interface{}, constraints map[string]string) {
+	for _, name := range diffedConstraints {
+		v, ok := s[name]
+		if !ok {
+			continue
+		}
+		switch name {
+		case "type", "enum":
+			values := decodeStrings(constraints[name])
+			switch v := v.(type) {
+			case []interface{}:
+				for _, e := range v {
+					values = append(values, constraintValue(e))
+				}
+			default:
+				values = append(values, constraintValue(v))
+			}
+			constraints[name] = encodeStrings(values)
+		case "additionalProperties":
+			if _, isBool := v.(bool); isBool {
+				constraints[name] = constraintValue(v)
+			}
+		default:
+			constraints[name] = constraintValue(v)
+		}
+	}
+}
+
+func constraintValue(v interface{}) string {
+	if s, ok := v.(string); ok {
+		return s
+	}
+	encoded, _ := json.Marshal(v)
+	return string(encoded)
+}
+
+// encodeStrings encodes the sorted, unique values as JSON array.
+func encodeStrings(values []string) string {
+	set := NewStringSet(values...)
+	encoded, _ := json.Marshal(set.Strings())
+	return string(encoded)
+}
+
+func decodeStrings(s string) []string {
+	var values []string
+	json.Unmarshal([]byte(s), &values)
+	return values
+}
+
+func isSubset(subset, superset []string) bool {
+	s := NewStringSet(superset...)
+	for _, v := range subset {
+		if !s.Contains(v) {
+			return false
+		}
+	}
+	return true
+}
+
+func parseFloat(s string) float64 {
+	f, _ := strconv.ParseFloat(s, 64)
+	return f
+}
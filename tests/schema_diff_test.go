@@ -0,0 +1,104 @@
+// Licensed to Elasticsearch B.V. under one or more contributor
+// license agreements. See the NOTICE file distributed with
+// this work for additional information regarding copyright
+// ownership. Elasticsearch B.V. licenses this file to you under
+// the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package tests
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/elastic/apm-server/model/transaction/generated/schema"
+)
+
+func TestDiffSchemas(t *testing.T) {
+	oldSchema := `{
+		"properties": {
+			"id": {"type": "string", "maxLength": 1024},
+			"name": {"type": ["string", "null"], "maxLength": 1024},
+			"kind": {"enum": ["a", "b"]},
+			"legacy": {"type": "string"},
+			"context": {"properties": {"tags": {"patternProperties": {"^[^.*\"]*$": {"type": ["string", "null"]}}}}}
+		},
+		"required": ["id"]
+	}`
+	newSchema := `{
+		"properties": {
+			"id": {"type": "string", "maxLength": 512},
+			"name": {"type": "string", "maxLength": 2048},
+			"kind": {"enum": ["a", "b", "c"]},
+			"duration": {"type": "number", "minimum": 0},
+			"context": {"properties": {"tags": {"patternProperties": {"^[^.*\"]*$": {"type": ["string", "null", "number"]}}}}}
+		},
+		"allOf": [{"required": ["id", "duration"]}]
+	}`
+
+	diff, err := DiffSchemas(oldSchema, newSchema)
+	require.NoError(t, err)
+	assert.Equal(t, SchemaDiff{
+		{Field: `context.tags.^[^.*"]*$`, Constraint: "type", Kind: SchemaChanged, Old: `["null","string"]`, New: `["null","number","string"]`},
+		{Field: "duration", Kind: SchemaAdded},
+		{Field: "duration", Constraint: "minimum", Kind: SchemaAdded, New: "0"},
+		{Field: "duration", Constraint: "required", Kind: SchemaAdded, New: "true"},
+		{Field: "duration", Constraint: "type", Kind: SchemaAdded, New: `["number"]`},
+		{Field: "id", Constraint: "maxLength", Kind: SchemaChanged, Old: "1024", New: "512"},
+		{Field: "kind", Constraint: "enum", Kind: SchemaChanged, Old: `["a","b"]`, New: `["a","b","c"]`},
+		{Field: "legacy", Kind: SchemaRemoved},
+		{Field: "name", Constraint: "maxLength", Kind: SchemaChanged, Old: "1024", New: "2048"},
+		{Field: "name", Constraint: "type", Kind: SchemaChanged, Old: `["null","string"]`, New: `["string"]`},
+	}, diff)
+
+	assert.Equal(t, SchemaDiff{
+		{Field: "duration", Constraint: "minimum", Kind: SchemaAdded, New: "0"},
+		{Field: "duration", Constraint: "required", Kind: SchemaAdded, New: "true"},
+		{Field: "duration", Constraint: "type", Kind: SchemaAdded, New: `["number"]`},
+		{Field: "id", Constraint: "maxLength", Kind: SchemaChanged, Old: "1024", New: "512"},
+		{Field: "legacy", Kind: SchemaRemoved},
+		{Field: "name", Constraint: "type", Kind: SchemaChanged, Old: `["null","string"]`, New: `["string"]`},
+	}, diff.Breaking())
+
+	assert.Equal(t, `id
+  changed maxLength: 1024 -> 512 (breaking)
+kind
+  changed enum: ["a","b"] -> ["a","b","c"]
+`, diff[5:7].String())
+}
+
+func TestSchemaChangeBreaking(t *testing.T) {
+	for _, c := range []struct {
+		change   SchemaChange
+		breaking bool
+	}{
+		{SchemaChange{Field: "a", Kind: SchemaAdded}, false},
+		{SchemaChange{Field: "a", Kind: SchemaRemoved}, true},
+		{SchemaChange{Field: "a", Constraint: "maxLength", Kind: SchemaRemoved, Old: "1"}, false},
+		{SchemaChange{Field: "a", Constraint: "pattern", Kind: SchemaAdded, New: "^a$"}, true},
+		{SchemaChange{Field: "a", Constraint: "pattern", Kind: SchemaChanged, Old: "^a$", New: "^b$"}, true},
+		{SchemaChange{Field: "a", Constraint: "additionalProperties", Kind: SchemaAdded, New: "true"}, false},
+		{SchemaChange{Field: "a", Constraint: "additionalProperties", Kind: SchemaChanged, Old: "true", New: "false"}, true},
+		{SchemaChange{Field: "a", Constraint: "minimum", Kind: SchemaChanged, Old: "1", New: "0"}, false},
+		{SchemaChange{Field: "a", Constraint: "minimum", Kind: SchemaChanged, Old: "0", New: "1"}, true},
+		{SchemaChange{Field: "a", Constraint: "maxItems", Kind: SchemaChanged, Old: "10", New: "5"}, true},
+	} {
+		assert.Equal(t, c.breaking, c.change.Breaking(), c.change.String())
+	}
+}
+
+func TestNoBreakingSchemaChanges(t *testing.T) {
+	AssertNoBreakingSchemaChanges(t, schema.ModelSchema, schema.ModelSchema)
+}
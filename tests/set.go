@@ -20,6 +20,7 @@ package tests
 import (
 	"fmt"
 	"regexp"
+	"sort"
 )
 
 type Set struct {
@@ -34,6 +35,15 @@ func NewSet(entries ...interface{}) *Set {
 	return &s
 }
 
+// NewStringSet creates a set containing the given strings.
+func NewStringSet(entries ...string) *Set {
+	s := NewSet()
+	for _, v := range entries {
+		s.Add(v)
+	}
+	return s
+}
+
 func (s *Set) Add(input interface{}) {
 	if s == nil {
 		return
@@ -62,12 +72,10 @@ func (s *Set) ContainsStrPattern(str string) bool {
 	if s.Contains(str) {
 		return true
 	}
-	for _, entry := range s.Array() {
-		if entryStr, ok := entry.(string); ok {
-			re, err := regexp.Compile(fmt.Sprintf("^%s$", entryStr))
-			if err == nil && re.MatchString(str) {
-				return true
-			}
+	for _, entry := range s.Strings() {
+		re, err := regexp.Compile(fmt.Sprintf("^%s$", entry))
+		if err == nil && re.MatchString(str) {
+			return true
 		}
 	}
 	return false
@@ -118,6 +126,19 @@ func Difference(s1, s2 *Set) *Set {
 	return s
 }
 
+func Intersection(s1, s2 *Set) *Set {
+	s := NewSet()
+	if s1 == nil {
+		return s
+	}
+	for k := range s1.entries {
+		if s2.Contains(k) {
+			s.Add(k)
+		}
+	}
+	return s
+}
+
 func SymmDifference(s1, s2 *Set) *Set {
 	return Union(Difference(s1, s2), Difference(s2, s1))
 }
@@ -132,3 +153,19 @@ func (s *Set) Array() []interface{} {
 	}
 	return a
 }
+
+// Strings returns the string entries of the set in sorted order.
+// Entries of any other type, such as groups, are skipped.
+func (s *Set) Strings() []string {
+	if s == nil {
+		return []string{}
+	}
+	a := make([]string, 0, len(s.entries))
+	for k := range s.entries {
+		if str, ok := k.(string); ok {
+			a = append(a, str)
+		}
+	}
+	sort.Strings(a)
+	return a
+}
@@ -174,6 +174,23 @@ func TestSetDifference(t *testing.T) {
 	}
 }
 
+func TestSetIntersection(t *testing.T) {
+	for _, d := range []struct {
+		s1  *Set
+		s2  *Set
+		out []interface{}
+	}{
+		{nil, nil, []interface{}{}},
+		{nil, NewSet("a"), []interface{}{}},
+		{NewSet(34.5), nil, []interface{}{}},
+		{NewSet(), NewSet(), []interface{}{}},
+		{NewSet(34.5, "a"), NewSet("a"), []interface{}{"a"}},
+		{NewSet(1, 2, 3), NewSet(1, "a", 3), []interface{}{1, 3}},
+	} {
+		assert.ElementsMatch(t, d.out, Intersection(d.s1, d.s2).Array())
+	}
+}
+
 func TestSetSymmDifference(t *testing.T) {
 	for _, d := range []struct {
 		s1  *Set
@@ -206,3 +223,22 @@ func TestSetArray(t *testing.T) {
 		assert.ElementsMatch(t, d.out, d.s.Array())
 	}
 }
+
+func TestNewStringSet(t *testing.T) {
+	assert.ElementsMatch(t, []interface{}{"a", "b"}, NewStringSet("b", "a", "b").Array())
+	assert.Equal(t, 0, NewStringSet().Len())
+}
+
+func TestSetStrings(t *testing.T) {
+	for _, d := range []struct {
+		s   *Set
+		out []string
+	}{
+		{nil, []string{}},
+		{NewSet(), []string{}},
+		{NewSet("b", "a", "c"), []string{"a", "b", "c"}},
+		{NewSet(1, "b", Group("x"), "a"), []string{"a", "b"}},
+	} {
+		assert.Equal(t, d.out, d.s.Strings())
+	}
+}
@@ -0,0 +1,102 @@
+// Licensed to Elasticsearch B.V. under one or more contributor
+// license agreements. See the NOTICE file distributed with
+// this work for additional information regarding copyright
+// ownership. Elasticsearch B.V. licenses this file to you under
+// the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package tests
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"errors"
+
+	"github.com/elastic/apm-server/tests/loader"
+)
+
+// StreamPayload is a v2 intake NDJSON stream, made up of a metadata line followed by event lines.
+//
+// When a TestProcessor loads a StreamPayload, ProcessorSetup applies payload mutations to the
+// individual events, and sends the metadata unchanged, so the tests cover the streaming intake path.
+type StreamPayload struct {
+	Metadata map[string]interface{}
+	Events   []interface{}
+}
+
+// LoadStreamPayload loads the NDJSON stream stored at path.
+func LoadStreamPayload(path string) (*StreamPayload, error) {
+	data, err := loader.LoadDataAsBytes(path)
+	if err != nil {
+		return nil, err
+	}
+	var payload StreamPayload
+	scanner := bufio.NewScanner(bytes.NewReader(data))
+	scanner.Buffer(nil, len(data)+1)
+	for scanner.Scan() {
+		line := bytes.TrimSpace(scanner.Bytes())
+		if len(line) == 0 {
+			continue
+		}
+		var v map[string]interface{}
+		if err := json.Unmarshal(line, &v); err != nil {
+			return nil, err
+		}
+		if payload.Metadata == nil {
+			payload.Metadata = v
+			continue
+		}
+		payload.Events = append(payload.Events, v)
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+	if payload.Metadata == nil {
+		return nil, errors.New("stream payload without metadata")
+	}
+	return &payload, nil
+}
+
+// NDJSON encodes the payload as NDJSON stream.
+func (p *StreamPayload) NDJSON() ([]byte, error) {
+	var buf bytes.Buffer
+	enc := json.NewEncoder(&buf)
+	if err := enc.Encode(p.Metadata); err != nil {
+		return nil, err
+	}
+	for _, e := range p.Events {
+		if err := enc.Encode(e); err != nil {
+			return nil, err
+		}
+	}
+	return buf.Bytes(), nil
+}
+
+// payloadEvents returns the part of a payload subject to mutations: the events of a
+// StreamPayload, or the whole payload otherwise.
+func payloadEvents(payload interface{}) interface{} {
+	if p, ok := payload.(*StreamPayload); ok {
+		return p.Events
+	}
+	return payload
+}
+
+// mutatePayload applies fn to the events of a StreamPayload, or to the whole payload otherwise.
+func mutatePayload(payload interface{}, fn func(interface{}) interface{}) interface{} {
+	if p, ok := payload.(*StreamPayload); ok {
+		events, _ := fn(p.Events).([]interface{})
+		return &StreamPayload{Metadata: p.Metadata, Events: events}
+	}
+	return fn(payload)
+}
@@ -0,0 +1,61 @@
+// Licensed to Elasticsearch B.V. under one or more contributor
+// license agreements. See the NOTICE file distributed with
+// this work for additional information regarding copyright
+// ownership. Elasticsearch B.V. licenses this file to you under
+// the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package tests
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/elastic/apm-server/tests/loader"
+)
+
+func TestStreamPayload(t *testing.T) {
+	payload, err := LoadStreamPayload("../testdata/intake-v2/transactions.ndjson")
+	require.NoError(t, err)
+	assert.Contains(t, payload.Metadata, "metadata")
+	require.Len(t, payload.Events, 4)
+
+	data, err := loader.LoadDataAsBytes("../testdata/intake-v2/transactions.ndjson")
+	require.NoError(t, err)
+	encoded, err := payload.NDJSON()
+	require.NoError(t, err)
+	assert.Len(t, bytes.Split(bytes.TrimSpace(encoded), []byte("\n")), len(bytes.Split(bytes.TrimSpace(data), []byte("\n"))))
+
+	mutated := mutatePayload(payload, func(p interface{}) interface{} {
+		return iterateMap(p, "", "transaction", "name", "changed", upsertFn)
+	}).(*StreamPayload)
+	assert.Equal(t, payload.Metadata, mutated.Metadata)
+	for _, e := range mutated.Events {
+		assert.Equal(t, "changed", e.(map[string]interface{})["transaction"].(map[string]interface{})["name"])
+	}
+
+	keys := NewSet()
+	flattenJsonKeys(payloadEvents(payload), "", keys)
+	assert.True(t, keys.Contains("transaction.name"))
+	assert.False(t, keys.Contains("metadata"))
+}
+
+func TestLoadStreamPayloadOnlyMetadata(t *testing.T) {
+	payload, err := LoadStreamPayload("../testdata/intake-v2/only-metadata.ndjson")
+	require.NoError(t, err)
+	assert.NotNil(t, payload.Metadata)
+	assert.Empty(t, payload.Events)
+}
@@ -156,6 +156,7 @@ class ServerBaseTest(BaseTest):
 
     host = "http://localhost:8200"
     root_url = "{}/".format(host)
+    status_url = "{}/{}".format(host, "v1/status")
     agent_config_url = "{}/{}".format(host, "config/v1/agents")
     rum_agent_config_url = "{}/{}".format(host, "config/v1/rum/agents")
     intake_url = "{}/{}".format(host, 'intake/v2/events')
@@ -1,20 +1,31 @@
 import time
 
+import requests
+
 from apmserver import integration_test
 from apmserver import ClientSideElasticTest, ElasticTest, ExpvarBaseTest, ProcStartupFailureTest
 from helper import wait_until
-from es_helper import index_smap, index_metric, index_transaction, index_error, index_span, index_onboarding, index_name
+from es_helper import index_smap, index_metric, index_transaction, index_error, index_span, index_name
 
 
 @integration_test
 class Test(ElasticTest):
 
-    def test_onboarding_doc(self):
+    def test_status(self):
         """
-        This test starts the beat and checks that the onboarding doc has been published to ES
+        This test starts the beat and checks that the status API reports the services events were received from
         """
-        wait_until(lambda: self.es.indices.exists(index_onboarding), name="onboarding index created")
-        wait_until(lambda: (self.es.count(index=index_onboarding)['count'] == 1))
+        r = requests.get(self.status_url)
+        assert r.status_code == 200, r.status_code
+        assert r.json() == {"first_event_received": None, "services": []}, r.json()
+
+        self.load_docs_with_template(self.get_error_payload_path(), self.intake_url, 'error', 4)
+        r = requests.get(self.status_url)
+        assert r.status_code == 200, r.status_code
+        status = r.json()
+        assert status["first_event_received"] is not None
+        services = [(s["name"], [a["name"] for a in s["agents"]]) for s in status["services"]]
+        assert services == [("1234_service-12a3", ["elastic-node"]), ("abc", ["python"])], services
 
         # Makes sure no error or warnings were logged
         self.assert_no_logged_warnings()
@@ -23,7 +34,7 @@ class Test(ElasticTest):
         """
         This test starts the beat and checks that the template has been loaded to ES
         """
-        wait_until(lambda: self.es.indices.exists(index_onboarding))
+        wait_until(lambda: self.es.indices.exists_template(index_name), name="template loaded")
         templates = self.es.indices.get_template(index_name)
         assert len(templates) == 1
         t = templates[index_name]
@@ -238,8 +249,8 @@ class OverrideIndicesIntegrationTest(OverrideIndicesTest):
                                      2,
                                      query_index=index_name)
 
-        # check that every document is indexed once in the expected index (incl.1 onboarding doc)
-        assert 4+2+1 == self.es.count(index=index_name)['count']
+        # check that every document is indexed once in the expected index
+        assert 4+2 == self.es.count(index=index_name)['count']
 
 
 @integration_test
@@ -7,7 +7,7 @@ from apmserver import integration_test
 from apmserver import ClientSideElasticTest
 from test_auth import APIKeyHelper
 from helper import wait_until
-from es_helper import index_smap, index_metric, index_transaction, index_error, index_span, index_onboarding, index_name
+from es_helper import index_smap, index_metric, index_transaction, index_error, index_span, index_name
 
 
 class BaseSourcemapTest(ClientSideElasticTest):
@@ -130,7 +130,7 @@ class CorsTest(CorsBaseTest):
                                       'Access-Control-Request-Headers': 'Content-Type, Content-Encoding'})
         assert r.status_code == 200, r.status_code
         assert r.headers['Access-Control-Allow-Origin'] == 'http://www.elastic.co', r.headers
-        assert r.headers['Access-Control-Allow-Headers'] == 'Content-Type, Content-Encoding, Accept', r.headers
+        assert r.headers['Access-Control-Allow-Headers'] == 'Content-Type, Content-Encoding, Accept, X-Request-Id', r.headers
         assert r.headers['Access-Control-Allow-Methods'] == 'POST, OPTIONS', r.headers
         assert r.headers['Vary'] == 'Origin', r.headers
         assert r.headers['Content-Length'] == '0', r.headers
@@ -143,7 +143,7 @@ class CorsTest(CorsBaseTest):
                                  headers=h)
             assert r.status_code == 200, r.status_code
             assert 'Access-Control-Allow-Origin' not in r.headers.keys(), r.headers
-            assert r.headers['Access-Control-Allow-Headers'] == 'Content-Type, Content-Encoding, Accept', r.headers
+            assert r.headers['Access-Control-Allow-Headers'] == 'Content-Type, Content-Encoding, Accept, X-Request-Id', r.headers
             assert r.headers['Access-Control-Allow-Methods'] == 'POST, OPTIONS', r.headers
 
 
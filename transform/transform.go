@@ -20,6 +20,7 @@ package transform
 import (
 	"context"
 	"regexp"
+	"text/template"
 
 	"github.com/elastic/beats/v7/libbeat/beat"
 
@@ -44,4 +45,14 @@ type Config struct {
 	LibraryPattern      *regexp.Regexp
 	ExcludeFromGrouping *regexp.Regexp
 	SourcemapStore      *sourcemap.Store
+
+	// BackendLibraryPattern, if non-nil, is matched against the module,
+	// classname, filename and abs_path of backend stack frames. Matching
+	// frames are marked as library frames; other frames are unchanged.
+	BackendLibraryPattern *regexp.Regexp
+
+	// GroupingKeyTemplate, if non-nil, is executed with the error's
+	// model.GroupingKeyData to compute its grouping key. The default
+	// grouping key is used if the template fails or outputs nothing.
+	GroupingKeyTemplate *template.Template
 }
@@ -0,0 +1,76 @@
+// Licensed to Elasticsearch B.V. under one or more contributor
+// license agreements. See the NOTICE file distributed with
+// this work for additional information regarding copyright
+// ownership. Elasticsearch B.V. licenses this file to you under
+// the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package utility
+
+import (
+	"reflect"
+	"sort"
+	"strconv"
+)
+
+// Diff returns the paths at which the decoded JSON document b differs from a,
+// prefixed by "+" when only defined in b, "-" when only defined in a,
+// and "~" when defined in both with different values.
+func Diff(path string, a, b interface{}) []string {
+	join := func(key string) string {
+		if path == "" {
+			return key
+		}
+		return path + "." + key
+	}
+	switch a := a.(type) {
+	case map[string]interface{}:
+		if b, ok := b.(map[string]interface{}); ok {
+			var changes []string
+			for k, va := range a {
+				if vb, ok := b[k]; ok {
+					changes = append(changes, Diff(join(k), va, vb)...)
+				} else {
+					changes = append(changes, "-"+join(k))
+				}
+			}
+			for k := range b {
+				if _, ok := a[k]; !ok {
+					changes = append(changes, "+"+join(k))
+				}
+			}
+			sort.Strings(changes)
+			return changes
+		}
+	case []interface{}:
+		if b, ok := b.([]interface{}); ok {
+			var changes []string
+			for i := 0; i < len(a) || i < len(b); i++ {
+				key := join(strconv.Itoa(i))
+				switch {
+				case i >= len(b):
+					changes = append(changes, "-"+key)
+				case i >= len(a):
+					changes = append(changes, "+"+key)
+				default:
+					changes = append(changes, Diff(key, a[i], b[i])...)
+				}
+			}
+			return changes
+		}
+	}
+	if !reflect.DeepEqual(a, b) {
+		return []string{"~" + path}
+	}
+	return nil
+}
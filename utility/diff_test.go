@@ -0,0 +1,39 @@
+// Licensed to Elasticsearch B.V. under one or more contributor
+// license agreements. See the NOTICE file distributed with
+// this work for additional information regarding copyright
+// ownership. Elasticsearch B.V. licenses this file to you under
+// the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package utility
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDiff(t *testing.T) {
+	a := map[string]interface{}{
+		"description": "a",
+		"processors":  []interface{}{map[string]interface{}{"set": "x"}, "y"},
+		"removed":     true,
+	}
+	b := map[string]interface{}{
+		"description": "b",
+		"processors":  []interface{}{map[string]interface{}{"set": "x"}, "y", "z"},
+		"added":       1.0,
+	}
+	assert.Equal(t, []string{"+added", "+processors.2", "-removed", "~description"}, Diff("", a, b))
+	assert.Empty(t, Diff("", a, a))
+}
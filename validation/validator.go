@@ -37,19 +37,25 @@ func (e *Error) Unwrap() error {
 	return e.Err
 }
 
+// CreateSchema compiles schemaData, panicking if it is not a valid schema.
 func CreateSchema(schemaData string, url string) *jsonschema.Schema {
-	compiler := jsonschema.NewCompiler()
-	if err := compiler.AddResource(url, strings.NewReader(schemaData)); err != nil {
-		panic(err)
-	}
-	compiler.Draft = jsonschema.Draft7
-	schema, err := compiler.Compile(url)
+	schema, err := CompileSchema(schemaData, url)
 	if err != nil {
 		panic(err)
 	}
 	return schema
 }
 
+// CompileSchema compiles schemaData as a draft-07 JSON schema.
+func CompileSchema(schemaData string, url string) (*jsonschema.Schema, error) {
+	compiler := jsonschema.NewCompiler()
+	if err := compiler.AddResource(url, strings.NewReader(schemaData)); err != nil {
+		return nil, err
+	}
+	compiler.Draft = jsonschema.Draft7
+	return compiler.Compile(url)
+}
+
 // ValidateObject checks that raw is a non-nil, decoded JSON object
 // (i.e. has type map[string]interface{}), and validates against the
 // provided schema.
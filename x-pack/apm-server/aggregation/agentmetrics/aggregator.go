@@ -0,0 +1,302 @@
+// Copyright Elasticsearch B.V. and/or licensed to Elasticsearch B.V. under one
+// or more contributor license agreements. Licensed under the Elastic License;
+// you may not use this file except in compliance with the Elastic License.
+
+// Package agentmetrics provides an Aggregator for aggregating the metricsets
+// reported by agents over a longer interval, reducing the number of metrics
+// documents indexed.
+package agentmetrics
+
+import (
+	"context"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/pkg/errors"
+	"github.com/ryanuber/go-glob"
+
+	"github.com/elastic/beats/v7/libbeat/logp"
+	"github.com/elastic/beats/v7/libbeat/monitoring"
+
+	logs "github.com/elastic/apm-server/log"
+	"github.com/elastic/apm-server/model"
+	"github.com/elastic/apm-server/publish"
+	"github.com/elastic/apm-server/transform"
+)
+
+var (
+	monitoringRegistry = monitoring.Default.NewRegistry("apm-server.aggregation.metricsets")
+	aggregatedCounter  = monitoring.NewInt(monitoringRegistry, "samples_aggregated")
+	overflowCounter    = monitoring.NewInt(monitoringRegistry, "overflowed")
+)
+
+// MetricType identifies how samples of a metric are aggregated.
+type MetricType int
+
+const (
+	// Counter metrics are aggregated by summing their values. The values
+	// reported by agents must be deltas over each reporting interval.
+	Counter MetricType = iota + 1
+
+	// Gauge metrics are aggregated by keeping the most recent value.
+	Gauge
+)
+
+// Rule identifies metrics to aggregate, and how to aggregate them.
+type Rule struct {
+	// Pattern is a glob pattern matching metric names.
+	Pattern string
+
+	// Type defines how the matching metrics are aggregated.
+	Type MetricType
+}
+
+// Aggregator aggregates the samples of metricsets with identical
+// dimensions, periodically publishing metricsets.
+type Aggregator struct {
+	config AggregatorConfig
+
+	mu               sync.Mutex
+	active, inactive map[string]*metricsetGroup
+}
+
+// AggregatorConfig holds configuration for creating an Aggregator.
+type AggregatorConfig struct {
+	// Report is a publish.Reporter for reporting metrics documents.
+	Report publish.Reporter
+
+	// Logger is the logger for logging metrics aggregation/publishing.
+	//
+	// If Logger is nil, a new logger will be constructed.
+	Logger *logp.Logger
+
+	// MaxGroups is the maximum number of distinct metricset dimensions
+	// to store within an aggregation period. Once this number of groups
+	// has been reached, metricsets with new dimensions are published
+	// without being aggregated.
+	MaxGroups int
+
+	// Interval is the interval between publishing of aggregated metrics.
+	Interval time.Duration
+
+	// Rules identifies the metrics to aggregate. The first rule matching
+	// a metric's name applies. Metrics not matching any rule, and
+	// histogram metrics, are published without being aggregated.
+	Rules []Rule
+}
+
+// Validate validates the aggregator config.
+func (config AggregatorConfig) Validate() error {
+	if config.Report == nil {
+		return errors.New("Report unspecified")
+	}
+	if config.MaxGroups <= 0 {
+		return errors.New("MaxGroups unspecified or negative")
+	}
+	if config.Interval <= 0 {
+		return errors.New("Interval unspecified or negative")
+	}
+	if len(config.Rules) == 0 {
+		return errors.New("Rules unspecified")
+	}
+	for _, rule := range config.Rules {
+		if rule.Type != Counter && rule.Type != Gauge {
+			return errors.Errorf("invalid type for rule %q", rule.Pattern)
+		}
+	}
+	return nil
+}
+
+// NewAggregator returns a new Aggregator with the given config.
+func NewAggregator(config AggregatorConfig) (*Aggregator, error) {
+	if err := config.Validate(); err != nil {
+		return nil, errors.Wrap(err, "invalid aggregator config")
+	}
+	if config.Logger == nil {
+		config.Logger = logp.NewLogger(logs.MetricsetAggregation)
+	}
+	return &Aggregator{
+		config:   config,
+		active:   make(map[string]*metricsetGroup),
+		inactive: make(map[string]*metricsetGroup),
+	}, nil
+}
+
+// Run runs the Aggregator, periodically publishing and clearing
+// aggregated metrics. Run returns when either a fatal error occurs,
+// or the context is cancelled.
+func (a *Aggregator) Run(ctx context.Context) error {
+	ticker := time.NewTicker(a.config.Interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-ticker.C:
+		}
+		if err := a.publish(ctx); err != nil {
+			a.config.Logger.With(logp.Error(err)).Warnf(
+				"publishing aggregated metricsets failed: %s", err,
+			)
+		}
+	}
+}
+
+func (a *Aggregator) publish(ctx context.Context) error {
+	// We hold a.mu only long enough to swap the maps. After
+	// the lock is released nothing will be accessing a.inactive.
+	a.mu.Lock()
+	a.active, a.inactive = a.inactive, a.active
+	a.mu.Unlock()
+
+	if len(a.inactive) == 0 {
+		a.config.Logger.Debugf("no metrics to publish")
+		return nil
+	}
+
+	metricsets := make([]transform.Transformable, 0, len(a.inactive))
+	for key, group := range a.inactive {
+		metricsets = append(metricsets, group.metricset())
+		delete(a.inactive, key)
+	}
+
+	a.config.Logger.Debugf("publishing %d metricsets", len(metricsets))
+	return a.config.Report(ctx, publish.PendingReq{
+		Transformables: metricsets,
+		Trace:          true,
+	})
+}
+
+// AggregateTransformables aggregates the samples of all metricsets in
+// "in" matching the configured rules, returning the input with fully
+// aggregated metricsets removed. Metricsets with samples which are not
+// aggregated are replaced with copies holding only those samples.
+func (a *Aggregator) AggregateTransformables(in []transform.Transformable) []transform.Transformable {
+	out := in[:0]
+	for _, tf := range in {
+		if metricset, ok := tf.(*model.Metricset); ok {
+			metricset = a.AggregateMetricset(metricset)
+			if metricset == nil {
+				continue
+			}
+			tf = metricset
+		}
+		out = append(out, tf)
+	}
+	return out
+}
+
+// AggregateMetricset aggregates the samples of metricset matching the
+// configured rules. AggregateMetricset returns nil if all samples were
+// aggregated, and otherwise a metricset holding the remaining samples,
+// which should be published immediately.
+func (a *Aggregator) AggregateMetricset(metricset *model.Metricset) *model.Metricset {
+	var aggregate, remaining []model.Sample
+	var types []MetricType
+	for _, sample := range metricset.Samples {
+		if len(sample.Counts) == 0 {
+			if metricType := a.metricType(sample.Name); metricType != 0 {
+				aggregate = append(aggregate, sample)
+				types = append(types, metricType)
+				continue
+			}
+		}
+		remaining = append(remaining, sample)
+	}
+	if len(aggregate) == 0 {
+		return metricset
+	}
+
+	key := metricset.Dimensions().String()
+	a.mu.Lock()
+	group, ok := a.active[key]
+	if !ok {
+		if len(a.active) >= a.config.MaxGroups {
+			a.mu.Unlock()
+			overflowCounter.Inc()
+			return metricset
+		}
+		group = newMetricsetGroup(metricset)
+		a.active[key] = group
+	}
+	for i, sample := range aggregate {
+		group.update(sample, types[i], metricset.Timestamp)
+	}
+	a.mu.Unlock()
+	aggregatedCounter.Add(int64(len(aggregate)))
+
+	if len(remaining) == 0 {
+		return nil
+	}
+	// Copy the metricset, as it may be referenced by the group.
+	out := *metricset
+	out.Samples = remaining
+	return &out
+}
+
+func (a *Aggregator) metricType(name string) MetricType {
+	for _, rule := range a.config.Rules {
+		if glob.Glob(rule.Pattern, name) {
+			return rule.Type
+		}
+	}
+	return 0
+}
+
+// metricsetGroup holds the aggregated samples of metricsets with
+// identical dimensions.
+type metricsetGroup struct {
+	// dimensions holds a metricset with the group's dimensions,
+	// and the latest timestamp of the aggregated metricsets.
+	dimensions model.Metricset
+	samples    map[string]*aggregatedSample
+}
+
+type aggregatedSample struct {
+	value     float64
+	timestamp time.Time
+}
+
+func newMetricsetGroup(metricset *model.Metricset) *metricsetGroup {
+	dimensions := *metricset
+	dimensions.Samples = nil
+	return &metricsetGroup{
+		dimensions: dimensions,
+		samples:    make(map[string]*aggregatedSample),
+	}
+}
+
+func (g *metricsetGroup) update(sample model.Sample, metricType MetricType, timestamp time.Time) {
+	if timestamp.After(g.dimensions.Timestamp) {
+		g.dimensions.Timestamp = timestamp
+	}
+	existing, ok := g.samples[sample.Name]
+	if !ok {
+		g.samples[sample.Name] = &aggregatedSample{value: sample.Value, timestamp: timestamp}
+		return
+	}
+	switch metricType {
+	case Counter:
+		existing.value += sample.Value
+	case Gauge:
+		if !timestamp.Before(existing.timestamp) {
+			existing.value = sample.Value
+			existing.timestamp = timestamp
+		}
+	}
+}
+
+// metricset returns a metricset holding the group's aggregated samples,
+// ordered by name.
+func (g *metricsetGroup) metricset() *model.Metricset {
+	metricset := g.dimensions
+	metricset.Samples = make([]model.Sample, 0, len(g.samples))
+	for name, sample := range g.samples {
+		metricset.Samples = append(metricset.Samples, model.Sample{Name: name, Value: sample.value})
+	}
+	sort.Slice(metricset.Samples, func(i, j int) bool {
+		return metricset.Samples[i].Name < metricset.Samples[j].Name
+	})
+	return &metricset
+}
@@ -0,0 +1,215 @@
+// Copyright Elasticsearch B.V. and/or licensed to Elasticsearch B.V. under one
+// or more contributor license agreements. Licensed under the Elastic License;
+// you may not use this file except in compliance with the Elastic License.
+
+package agentmetrics_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/elastic/apm-server/model"
+	"github.com/elastic/apm-server/publish"
+	"github.com/elastic/apm-server/transform"
+	"github.com/elastic/apm-server/x-pack/apm-server/aggregation/agentmetrics"
+)
+
+var testRules = []agentmetrics.Rule{
+	{Pattern: "jvm.gc.*", Type: agentmetrics.Counter},
+	{Pattern: "jvm.memory.*", Type: agentmetrics.Gauge},
+}
+
+func TestNewAggregatorConfigInvalid(t *testing.T) {
+	report := makeErrReporter(nil)
+
+	type test struct {
+		config agentmetrics.AggregatorConfig
+		err    string
+	}
+
+	for _, test := range []test{{
+		config: agentmetrics.AggregatorConfig{},
+		err:    "Report unspecified",
+	}, {
+		config: agentmetrics.AggregatorConfig{
+			Report: report,
+		},
+		err: "MaxGroups unspecified or negative",
+	}, {
+		config: agentmetrics.AggregatorConfig{
+			Report:    report,
+			MaxGroups: 1,
+		},
+		err: "Interval unspecified or negative",
+	}, {
+		config: agentmetrics.AggregatorConfig{
+			Report:    report,
+			MaxGroups: 1,
+			Interval:  time.Second,
+		},
+		err: "Rules unspecified",
+	}, {
+		config: agentmetrics.AggregatorConfig{
+			Report:    report,
+			MaxGroups: 1,
+			Interval:  time.Second,
+			Rules:     []agentmetrics.Rule{{Pattern: "*"}},
+		},
+		err: `invalid type for rule "*"`,
+	}} {
+		agg, err := agentmetrics.NewAggregator(test.config)
+		require.Error(t, err)
+		require.Nil(t, agg)
+		assert.EqualError(t, err, "invalid aggregator config: "+test.err)
+	}
+}
+
+func TestAggregatorRun(t *testing.T) {
+	reqs := make(chan publish.PendingReq, 1)
+	agg, err := agentmetrics.NewAggregator(agentmetrics.AggregatorConfig{
+		Report:    makeChanReporter(reqs),
+		MaxGroups: 10,
+		Interval:  10 * time.Millisecond,
+		Rules:     testRules,
+	})
+	require.NoError(t, err)
+
+	t0 := time.Unix(0, 0).UTC()
+	for i := 0; i < 3; i++ {
+		metricset := agg.AggregateMetricset(makeMetricset("service-A", t0.Add(time.Duration(i)*time.Second),
+			model.Sample{Name: "jvm.gc.count", Value: 2},
+			model.Sample{Name: "jvm.memory.heap.used", Value: float64(100 * (i + 1))},
+		))
+		require.Nil(t, metricset)
+	}
+	metricset := agg.AggregateMetricset(makeMetricset("service-B", t0,
+		model.Sample{Name: "jvm.gc.count", Value: 1},
+	))
+	require.Nil(t, metricset)
+
+	stopAggregator := runAggregator(agg)
+	defer stopAggregator()
+
+	req := expectPublish(t, reqs)
+	require.Len(t, req.Transformables, 2)
+	metricsets := make(map[string]*model.Metricset)
+	for _, tf := range req.Transformables {
+		metricset := tf.(*model.Metricset)
+		metricsets[metricset.Metadata.Service.Name] = metricset
+	}
+	assert.Equal(t, makeMetricset("service-A", t0.Add(2*time.Second),
+		model.Sample{Name: "jvm.gc.count", Value: 6},
+		model.Sample{Name: "jvm.memory.heap.used", Value: 300},
+	), metricsets["service-A"])
+	assert.Equal(t, makeMetricset("service-B", t0,
+		model.Sample{Name: "jvm.gc.count", Value: 1},
+	), metricsets["service-B"])
+
+	select {
+	case <-reqs:
+		t.Fatal("unexpected publish")
+	case <-time.After(100 * time.Millisecond):
+	}
+}
+
+func TestAggregateMetricsetPartial(t *testing.T) {
+	agg, err := agentmetrics.NewAggregator(agentmetrics.AggregatorConfig{
+		Report:    makeErrReporter(nil),
+		MaxGroups: 10,
+		Interval:  time.Minute,
+		Rules:     testRules,
+	})
+	require.NoError(t, err)
+
+	t0 := time.Unix(0, 0).UTC()
+	histogram := model.Sample{Name: "jvm.gc.time", Values: []float64{1, 2}, Counts: []int64{3, 4}}
+	in := makeMetricset("service-A", t0,
+		model.Sample{Name: "jvm.gc.count", Value: 2},
+		model.Sample{Name: "system.cpu.total.norm.pct", Value: 0.5},
+		histogram,
+	)
+	out := agg.AggregateMetricset(in)
+	assert.Equal(t, makeMetricset("service-A", t0,
+		model.Sample{Name: "system.cpu.total.norm.pct", Value: 0.5},
+		histogram,
+	), out)
+
+	// Metricsets with no matching samples are returned unmodified.
+	in = makeMetricset("service-A", t0, model.Sample{Name: "system.cpu.total.norm.pct", Value: 0.5})
+	assert.Same(t, in, agg.AggregateMetricset(in))
+}
+
+func TestAggregateTransformablesOverflow(t *testing.T) {
+	agg, err := agentmetrics.NewAggregator(agentmetrics.AggregatorConfig{
+		Report:    makeErrReporter(nil),
+		MaxGroups: 2,
+		Interval:  time.Microsecond,
+		Rules:     testRules,
+	})
+	require.NoError(t, err)
+
+	t0 := time.Unix(0, 0).UTC()
+	var input []transform.Transformable
+	for _, serviceName := range []string{"service-A", "service-B", "service-C"} {
+		input = append(input, makeMetricset(serviceName, t0, model.Sample{Name: "jvm.gc.count", Value: 1}))
+	}
+	input = append(input, &model.Transaction{})
+
+	output := agg.AggregateTransformables(input)
+	require.Len(t, output, 2)
+	assert.Equal(t, makeMetricset("service-C", t0, model.Sample{Name: "jvm.gc.count", Value: 1}), output[0])
+	assert.Equal(t, &model.Transaction{}, output[1])
+}
+
+func makeMetricset(serviceName string, timestamp time.Time, samples ...model.Sample) *model.Metricset {
+	return &model.Metricset{
+		Metadata: model.Metadata{Service: model.Service{
+			Name:  serviceName,
+			Agent: model.Agent{Name: "java"},
+		}},
+		Timestamp: timestamp,
+		Samples:   samples,
+	}
+}
+
+func runAggregator(agg *agentmetrics.Aggregator) func() error {
+	done := make(chan error)
+	ctx, cancel := context.WithCancel(context.Background())
+	go func() {
+		defer close(done)
+		done <- agg.Run(ctx)
+	}()
+	return func() error {
+		cancel()
+		return <-done
+	}
+}
+
+func makeErrReporter(err error) publish.Reporter {
+	return func(context.Context, publish.PendingReq) error { return err }
+}
+
+func makeChanReporter(ch chan<- publish.PendingReq) publish.Reporter {
+	return func(ctx context.Context, req publish.PendingReq) error {
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case ch <- req:
+			return nil
+		}
+	}
+}
+
+func expectPublish(t *testing.T, ch <-chan publish.PendingReq) publish.PendingReq {
+	select {
+	case req := <-ch:
+		return req
+	case <-time.After(time.Second):
+		t.Fatal("expected publish")
+	}
+	panic("unreachable")
+}
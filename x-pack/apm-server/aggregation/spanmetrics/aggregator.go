@@ -0,0 +1,301 @@
+// Copyright Elasticsearch B.V. and/or licensed to Elasticsearch B.V. under one
+// or more contributor license agreements. Licensed under the Elastic License;
+// you may not use this file except in compliance with the Elastic License.
+
+// Package spanmetrics provides an Aggregator for aggregating exit spans
+// into service destination metrics, suitable for rendering service maps.
+package spanmetrics
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/pkg/errors"
+
+	"github.com/elastic/beats/v7/libbeat/logp"
+
+	logs "github.com/elastic/apm-server/log"
+	"github.com/elastic/apm-server/model"
+	"github.com/elastic/apm-server/publish"
+	"github.com/elastic/apm-server/transform"
+)
+
+const (
+	outcomeSuccess = "success"
+	outcomeFailure = "failure"
+	outcomeUnknown = "unknown"
+)
+
+// Aggregator aggregates exit span durations by source service and
+// destination resource, periodically publishing metrics documents.
+type Aggregator struct {
+	config AggregatorConfig
+
+	mu               sync.Mutex
+	active, inactive map[aggregationKey]spanMetrics
+}
+
+// AggregatorConfig holds configuration for creating an Aggregator.
+type AggregatorConfig struct {
+	// Report is a publish.Reporter for reporting metrics documents.
+	Report publish.Reporter
+
+	// Logger is the logger for logging metrics aggregation/publishing.
+	//
+	// If Logger is nil, a new logger will be constructed.
+	Logger *logp.Logger
+
+	// MaxGroups is the maximum number of distinct service destination
+	// groups to store within an aggregation period. Once this number of
+	// groups has been reached, any new aggregation keys will cause
+	// individual metrics documents to be immediately published.
+	MaxGroups int
+
+	// Interval is the interval between publishing of aggregated metrics.
+	// There may be additional metrics reported at arbitrary times if the
+	// aggregation groups fill up.
+	Interval time.Duration
+}
+
+// Validate validates the aggregator config.
+func (config AggregatorConfig) Validate() error {
+	if config.Report == nil {
+		return errors.New("Report unspecified")
+	}
+	if config.MaxGroups <= 0 {
+		return errors.New("MaxGroups unspecified or negative")
+	}
+	if config.Interval <= 0 {
+		return errors.New("Interval unspecified or negative")
+	}
+	return nil
+}
+
+// NewAggregator returns a new Aggregator with the given config.
+func NewAggregator(config AggregatorConfig) (*Aggregator, error) {
+	if err := config.Validate(); err != nil {
+		return nil, errors.Wrap(err, "invalid aggregator config")
+	}
+	if config.Logger == nil {
+		config.Logger = logp.NewLogger(logs.SpanMetrics)
+	}
+	return &Aggregator{
+		config:   config,
+		active:   make(map[aggregationKey]spanMetrics),
+		inactive: make(map[aggregationKey]spanMetrics),
+	}, nil
+}
+
+// Run runs the Aggregator, periodically publishing and clearing
+// aggregated metrics. Run returns when either a fatal error occurs,
+// or the context is cancelled.
+func (a *Aggregator) Run(ctx context.Context) error {
+	ticker := time.NewTicker(a.config.Interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-ticker.C:
+		}
+		if err := a.publish(ctx); err != nil {
+			a.config.Logger.With(logp.Error(err)).Warnf(
+				"publishing service destination metrics failed: %s", err,
+			)
+		}
+	}
+}
+
+func (a *Aggregator) publish(ctx context.Context) error {
+	// We hold a.mu only long enough to swap the maps. After
+	// the lock is released nothing will be accessing a.inactive.
+	a.mu.Lock()
+	a.active, a.inactive = a.inactive, a.active
+	a.mu.Unlock()
+
+	if len(a.inactive) == 0 {
+		a.config.Logger.Debugf("no metrics to publish")
+		return nil
+	}
+
+	now := time.Now()
+	metricsets := make([]transform.Transformable, 0, len(a.inactive))
+	for key, metrics := range a.inactive {
+		metricset := makeMetricset(key, metrics, now)
+		metricsets = append(metricsets, &metricset)
+		delete(a.inactive, key)
+	}
+
+	a.config.Logger.Debugf("publishing %d metricsets", len(metricsets))
+	return a.config.Report(ctx, publish.PendingReq{
+		Transformables: metricsets,
+		Trace:          true,
+	})
+}
+
+// AggregateTransformables aggregates all exit spans contained in
+// "in", returning the input with any metricsets requiring immediate
+// publication appended.
+//
+// This method is expected to be used immediately prior to publishing
+// the events, so that the metricsets requiring immediate publication
+// can be included in the same batch.
+func (a *Aggregator) AggregateTransformables(in []transform.Transformable) []transform.Transformable {
+	out := in
+	for _, tf := range in {
+		switch event := tf.(type) {
+		case *model.Span:
+			if metricset := a.AggregateSpan(event); metricset != nil {
+				out = append(out, metricset)
+			}
+		case *model.Transaction:
+			for _, metricset := range a.AggregateDroppedSpansStats(event) {
+				out = append(out, metricset)
+			}
+		}
+	}
+	return out
+}
+
+// AggregateSpan aggregates exit span metrics. Spans without a
+// destination service resource are ignored.
+//
+// If the span cannot be aggregated due to the maximum number of
+// groups being exceeded, then a *model.Metricset will be returned
+// which should be published immediately, along with the span.
+// Otherwise, the returned metricset will be nil.
+func (a *Aggregator) AggregateSpan(span *model.Span) *model.Metricset {
+	if span.DestinationService == nil || span.DestinationService.Resource == nil {
+		return nil
+	}
+	if *span.DestinationService.Resource == "" {
+		return nil
+	}
+	key := makeAggregationKey(span.Metadata, *span.DestinationService.Resource, spanOutcome(span))
+	duration := time.Duration(span.Duration * float64(time.Millisecond))
+	return a.aggregate(key, spanMetrics{count: 1, sum: durationMicros(duration)})
+}
+
+// AggregateDroppedSpansStats aggregates the statistics of exit spans
+// dropped by the agent which recorded the transaction, so aggregated
+// latencies remain accurate when agents drop spans.
+//
+// Any metricsets which could not be aggregated due to the maximum
+// number of groups being exceeded are returned, and should be
+// published immediately along with the transaction.
+func (a *Aggregator) AggregateDroppedSpansStats(tx *model.Transaction) []*model.Metricset {
+	var metricsets []*model.Metricset
+	for _, stats := range tx.DroppedSpansStats {
+		if stats.DestinationServiceResource == "" {
+			continue
+		}
+		outcome := stats.Outcome
+		if outcome == "" {
+			outcome = outcomeUnknown
+		}
+		key := makeAggregationKey(tx.Metadata, stats.DestinationServiceResource, outcome)
+		metrics := spanMetrics{
+			count: int64(stats.Duration.Count),
+			sum:   durationMicros(stats.Duration.Sum),
+		}
+		if metricset := a.aggregate(key, metrics); metricset != nil {
+			metricsets = append(metricsets, metricset)
+		}
+	}
+	return metricsets
+}
+
+// aggregate adds metrics to the group identified by key. If the group
+// cannot be created due to the maximum number of groups being exceeded,
+// a metricset is returned for immediate publication.
+func (a *Aggregator) aggregate(key aggregationKey, metrics spanMetrics) *model.Metricset {
+	if a.updateMetrics(key, metrics) {
+		return nil
+	}
+	// Too many aggregation keys: could not update metrics, so immediately
+	// publish a single-value metric document.
+	metricset := makeMetricset(key, metrics, time.Now())
+	return &metricset
+}
+
+func (a *Aggregator) updateMetrics(key aggregationKey, value spanMetrics) bool {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	existing, ok := a.active[key]
+	if !ok && len(a.active) >= a.config.MaxGroups {
+		return false
+	}
+	existing.count += value.count
+	existing.sum += value.sum
+	a.active[key] = existing
+	return true
+}
+
+func makeAggregationKey(metadata model.Metadata, resource, outcome string) aggregationKey {
+	return aggregationKey{
+		serviceEnvironment: metadata.Service.Environment,
+		serviceName:        metadata.Service.Name,
+		agentName:          metadata.Service.Agent.Name,
+		resource:           resource,
+		outcome:            outcome,
+	}
+}
+
+// spanOutcome returns the outcome of an exit span, inferred from the
+// HTTP response status code if there is one.
+func spanOutcome(span *model.Span) string {
+	if span.HTTP == nil || span.HTTP.StatusCode == nil {
+		return outcomeUnknown
+	}
+	if *span.HTTP.StatusCode >= 400 {
+		return outcomeFailure
+	}
+	return outcomeSuccess
+}
+
+// makeMetricset makes a Metricset from key and metrics, with timestamp ts.
+func makeMetricset(key aggregationKey, metrics spanMetrics, ts time.Time) model.Metricset {
+	return model.Metricset{
+		Timestamp: ts,
+		Metadata: model.Metadata{
+			Service: model.Service{
+				Name:        key.serviceName,
+				Environment: key.serviceEnvironment,
+				Agent:       model.Agent{Name: key.agentName},
+			},
+		},
+		Event: model.MetricsetEventCategorization{
+			Outcome: key.outcome,
+		},
+		Span: model.MetricsetSpan{
+			DestinationService: model.MetricsetSpanDestinationService{
+				Resource: key.resource,
+			},
+		},
+		Samples: []model.Sample{{
+			Name:  "span.destination.service.response_time.count",
+			Value: float64(metrics.count),
+		}, {
+			Name:  "span.destination.service.response_time.sum.us",
+			Value: float64(metrics.sum),
+		}},
+	}
+}
+
+type aggregationKey struct {
+	serviceEnvironment string
+	serviceName        string
+	agentName          string
+	resource           string
+	outcome            string
+}
+
+type spanMetrics struct {
+	count int64
+	sum   int64
+}
+
+func durationMicros(d time.Duration) int64 {
+	return int64(d / time.Microsecond)
+}
@@ -0,0 +1,234 @@
+// Copyright Elasticsearch B.V. and/or licensed to Elasticsearch B.V. under one
+// or more contributor license agreements. Licensed under the Elastic License;
+// you may not use this file except in compliance with the Elastic License.
+
+package spanmetrics_test
+
+import (
+	"context"
+	"sort"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/elastic/apm-server/model"
+	"github.com/elastic/apm-server/publish"
+	"github.com/elastic/apm-server/transform"
+	"github.com/elastic/apm-server/x-pack/apm-server/aggregation/spanmetrics"
+)
+
+func TestNewAggregatorConfigInvalid(t *testing.T) {
+	report := makeErrReporter(nil)
+
+	type test struct {
+		config spanmetrics.AggregatorConfig
+		err    string
+	}
+
+	for _, test := range []test{{
+		config: spanmetrics.AggregatorConfig{},
+		err:    "Report unspecified",
+	}, {
+		config: spanmetrics.AggregatorConfig{
+			Report: report,
+		},
+		err: "MaxGroups unspecified or negative",
+	}, {
+		config: spanmetrics.AggregatorConfig{
+			Report:    report,
+			MaxGroups: 1,
+		},
+		err: "Interval unspecified or negative",
+	}} {
+		agg, err := spanmetrics.NewAggregator(test.config)
+		require.Error(t, err)
+		require.Nil(t, agg)
+		assert.EqualError(t, err, "invalid aggregator config: "+test.err)
+	}
+}
+
+func TestAggregatorRun(t *testing.T) {
+	reqs := make(chan publish.PendingReq, 1)
+	agg, err := spanmetrics.NewAggregator(spanmetrics.AggregatorConfig{
+		Report:    makeChanReporter(reqs),
+		MaxGroups: 10,
+		Interval:  10 * time.Millisecond,
+	})
+	require.NoError(t, err)
+
+	for i := 0; i < 100; i++ {
+		metricset := agg.AggregateSpan(makeSpan("service-A", "mysql", 100*time.Millisecond, nil))
+		require.Nil(t, metricset)
+	}
+	for i := 0; i < 10; i++ {
+		metricset := agg.AggregateSpan(makeSpan("service-A", "elasticsearch", time.Millisecond, newInt(200)))
+		require.Nil(t, metricset)
+	}
+	for i := 0; i < 5; i++ {
+		metricset := agg.AggregateSpan(makeSpan("service-A", "elasticsearch", time.Millisecond, newInt(503)))
+		require.Nil(t, metricset)
+	}
+	// Spans without a destination service resource are not aggregated.
+	metricset := agg.AggregateSpan(&model.Span{Duration: 1})
+	require.Nil(t, metricset)
+	// Statistics for spans dropped by the agent are aggregated too.
+	metricsets := agg.AggregateDroppedSpansStats(&model.Transaction{
+		Metadata: model.Metadata{Service: model.Service{
+			Name:  "service-A",
+			Agent: model.Agent{Name: "java"},
+		}},
+		DroppedSpansStats: []model.DroppedSpanStats{{
+			DestinationServiceResource: "mysql",
+			Duration:                   model.AggregatedDuration{Count: 10, Sum: time.Second},
+		}, {
+			DestinationServiceResource: "elasticsearch",
+			Outcome:                    "success",
+			Duration:                   model.AggregatedDuration{Count: 2, Sum: 5 * time.Millisecond},
+		}},
+	})
+	require.Empty(t, metricsets)
+
+	stopAggregator := runAggregator(agg)
+	defer stopAggregator()
+
+	req := expectPublish(t, reqs)
+	require.Len(t, req.Transformables, 3)
+	metricsets = make([]*model.Metricset, len(req.Transformables))
+	for i, tf := range req.Transformables {
+		metricsets[i] = tf.(*model.Metricset)
+		require.False(t, metricsets[i].Timestamp.IsZero())
+		metricsets[i].Timestamp = time.Time{}
+	}
+	sort.Slice(metricsets, func(i, j int) bool {
+		mi, mj := metricsets[i], metricsets[j]
+		if mi.Span.DestinationService.Resource != mj.Span.DestinationService.Resource {
+			return mi.Span.DestinationService.Resource < mj.Span.DestinationService.Resource
+		}
+		return mi.Event.Outcome < mj.Event.Outcome
+	})
+
+	assert.Equal(t, []*model.Metricset{
+		makeMetricset("service-A", "elasticsearch", "failure", 5, 5000),
+		makeMetricset("service-A", "elasticsearch", "success", 12, 15000),
+		makeMetricset("service-A", "mysql", "unknown", 110, 11000000),
+	}, metricsets)
+
+	select {
+	case <-reqs:
+		t.Fatal("unexpected publish")
+	case <-time.After(100 * time.Millisecond):
+	}
+}
+
+func TestAggregateTransformablesOverflow(t *testing.T) {
+	agg, err := spanmetrics.NewAggregator(spanmetrics.AggregatorConfig{
+		Report:    makeErrReporter(nil),
+		MaxGroups: 2,
+		Interval:  time.Minute,
+	})
+	require.NoError(t, err)
+
+	// The first two groups will not require immediate publication,
+	// as we have configured the aggregator with a maximum of two groups.
+	var input []transform.Transformable
+	for i := 0; i < 10; i++ {
+		input = append(input, makeSpan("service", "foo", time.Millisecond, nil))
+		input = append(input, makeSpan("service", "bar", time.Millisecond, nil))
+		input = append(input, &model.Transaction{Name: "baz"})
+	}
+	output := agg.AggregateTransformables(input)
+	assert.Equal(t, input, output)
+
+	// The third group will return a metricset for immediate publication.
+	input = append(input, makeSpan("service", "baz", time.Millisecond, nil))
+	output = agg.AggregateTransformables(input)
+	require.Len(t, output, len(input)+1)
+	assert.Equal(t, input, output[:len(input)])
+
+	m, ok := output[len(input)].(*model.Metricset)
+	require.True(t, ok)
+	require.False(t, m.Timestamp.IsZero())
+	m.Timestamp = time.Time{}
+	assert.Equal(t, makeMetricset("service", "baz", "unknown", 1, 1000), m)
+}
+
+func makeSpan(serviceName, resource string, duration time.Duration, statusCode *int) *model.Span {
+	span := &model.Span{
+		Metadata: model.Metadata{Service: model.Service{
+			Name:  serviceName,
+			Agent: model.Agent{Name: "java"},
+		}},
+		Duration:           float64(duration) / float64(time.Millisecond),
+		DestinationService: &model.DestinationService{Resource: &resource},
+	}
+	if statusCode != nil {
+		span.HTTP = &model.HTTP{StatusCode: statusCode}
+	}
+	return span
+}
+
+func makeMetricset(serviceName, resource, outcome string, count, sum int64) *model.Metricset {
+	return &model.Metricset{
+		Metadata: model.Metadata{Service: model.Service{
+			Name:  serviceName,
+			Agent: model.Agent{Name: "java"},
+		}},
+		Event: model.MetricsetEventCategorization{
+			Outcome: outcome,
+		},
+		Span: model.MetricsetSpan{
+			DestinationService: model.MetricsetSpanDestinationService{Resource: resource},
+		},
+		Samples: []model.Sample{{
+			Name:  "span.destination.service.response_time.count",
+			Value: float64(count),
+		}, {
+			Name:  "span.destination.service.response_time.sum.us",
+			Value: float64(sum),
+		}},
+	}
+}
+
+func newInt(v int) *int {
+	return &v
+}
+
+func runAggregator(agg *spanmetrics.Aggregator) func() error {
+	done := make(chan error)
+	ctx, cancel := context.WithCancel(context.Background())
+	go func() {
+		defer close(done)
+		done <- agg.Run(ctx)
+	}()
+	return func() error {
+		cancel()
+		return <-done
+	}
+}
+
+func makeErrReporter(err error) publish.Reporter {
+	return func(context.Context, publish.PendingReq) error { return err }
+}
+
+func makeChanReporter(ch chan<- publish.PendingReq) publish.Reporter {
+	return func(ctx context.Context, req publish.PendingReq) error {
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case ch <- req:
+			return nil
+		}
+	}
+}
+
+func expectPublish(t *testing.T, ch <-chan publish.PendingReq) publish.PendingReq {
+	select {
+	case req := <-ch:
+		return req
+	case <-time.After(time.Second):
+		t.Fatal("expected publish")
+	}
+	panic("unreachable")
+}
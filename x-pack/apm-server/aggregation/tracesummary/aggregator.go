@@ -0,0 +1,255 @@
+// Copyright Elasticsearch B.V. and/or licensed to Elasticsearch B.V. under one
+// or more contributor license agreements. Licensed under the Elastic License;
+// you may not use this file except in compliance with the Elastic License.
+
+// Package tracesummary provides an Aggregator for summarising completed
+// traces, enabling trace-level search and analytics without querying
+// the individual events of each trace.
+package tracesummary
+
+import (
+	"context"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/pkg/errors"
+
+	"github.com/elastic/beats/v7/libbeat/logp"
+
+	logs "github.com/elastic/apm-server/log"
+	"github.com/elastic/apm-server/model"
+	"github.com/elastic/apm-server/publish"
+	"github.com/elastic/apm-server/transform"
+)
+
+// Aggregator tracks the events of in-progress traces, periodically
+// publishing a summary document for each trace that has completed.
+//
+// A trace is considered complete once its root transaction has been
+// observed, and no further events have been observed for the trace
+// within the configured idle timeout. Traces for which the root
+// transaction is never observed are discarded after the idle timeout.
+type Aggregator struct {
+	config AggregatorConfig
+
+	mu     sync.Mutex
+	traces map[string]*trace
+}
+
+// AggregatorConfig holds configuration for creating an Aggregator.
+type AggregatorConfig struct {
+	// Report is a publish.Reporter for reporting trace summary documents.
+	Report publish.Reporter
+
+	// Logger is the logger for logging trace summary aggregation/publishing.
+	//
+	// If Logger is nil, a new logger will be constructed.
+	Logger *logp.Logger
+
+	// MaxTraces is the maximum number of in-progress traces to track.
+	// Once this number of traces has been reached, events for new traces
+	// will be ignored until tracked traces complete.
+	MaxTraces int
+
+	// IdleTimeout is the amount of time after the last observed event
+	// of a trace after which the trace is considered complete.
+	IdleTimeout time.Duration
+}
+
+// Validate validates the aggregator config.
+func (config AggregatorConfig) Validate() error {
+	if config.Report == nil {
+		return errors.New("Report unspecified")
+	}
+	if config.MaxTraces <= 0 {
+		return errors.New("MaxTraces unspecified or negative")
+	}
+	if config.IdleTimeout <= 0 {
+		return errors.New("IdleTimeout unspecified or negative")
+	}
+	return nil
+}
+
+// NewAggregator returns a new Aggregator with the given config.
+func NewAggregator(config AggregatorConfig) (*Aggregator, error) {
+	if err := config.Validate(); err != nil {
+		return nil, errors.Wrap(err, "invalid aggregator config")
+	}
+	if config.Logger == nil {
+		config.Logger = logp.NewLogger(logs.TraceSummary)
+	}
+	return &Aggregator{
+		config: config,
+		traces: make(map[string]*trace),
+	}, nil
+}
+
+// Run runs the Aggregator, periodically publishing summaries of
+// completed traces. Run returns when either a fatal error occurs,
+// or the context is cancelled.
+func (a *Aggregator) Run(ctx context.Context) error {
+	ticker := time.NewTicker(a.config.IdleTimeout)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case now := <-ticker.C:
+			if err := a.publish(ctx, now); err != nil {
+				a.config.Logger.With(logp.Error(err)).Warnf(
+					"publishing trace summaries failed: %s", err,
+				)
+			}
+		}
+	}
+}
+
+func (a *Aggregator) publish(ctx context.Context, now time.Time) error {
+	var summaries []transform.Transformable
+	var discarded int
+	a.mu.Lock()
+	for traceID, trace := range a.traces {
+		if now.Sub(trace.lastSeen) < a.config.IdleTimeout {
+			continue
+		}
+		delete(a.traces, traceID)
+		if trace.root == nil {
+			discarded++
+			continue
+		}
+		summaries = append(summaries, trace.summary(traceID))
+	}
+	a.mu.Unlock()
+
+	if discarded > 0 {
+		a.config.Logger.Debugf("discarded %d traces without a root transaction", discarded)
+	}
+	if len(summaries) == 0 {
+		a.config.Logger.Debugf("no trace summaries to publish")
+		return nil
+	}
+	a.config.Logger.Debugf("publishing %d trace summaries", len(summaries))
+	return a.config.Report(ctx, publish.PendingReq{
+		Transformables: summaries,
+		Trace:          true,
+	})
+}
+
+// AggregateTransformables records all transactions, spans, and errors
+// contained in "in" against their traces, returning "in" unmodified.
+func (a *Aggregator) AggregateTransformables(in []transform.Transformable) []transform.Transformable {
+	now := time.Now()
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	for _, tf := range in {
+		switch event := tf.(type) {
+		case *model.Transaction:
+			a.recordTransaction(event, now)
+		case *model.Span:
+			if t := a.getTrace(event.TraceID, now); t != nil {
+				t.recordEvent(event.Timestamp, event.Duration)
+				t.service(event.Metadata.Service.Name).SpanCount++
+			}
+		case *model.Error:
+			if t := a.getTrace(event.TraceID, now); t != nil {
+				t.service(event.Metadata.Service.Name).ErrorCount++
+			}
+		}
+	}
+	return in
+}
+
+func (a *Aggregator) recordTransaction(tx *model.Transaction, now time.Time) {
+	t := a.getTrace(tx.TraceID, now)
+	if t == nil {
+		return
+	}
+	t.recordEvent(tx.Timestamp, tx.Duration)
+	t.service(tx.Metadata.Service.Name).TransactionCount++
+	if tx.ParentID == "" {
+		t.root = tx
+	}
+}
+
+// getTrace returns the trace with the given ID, marking it as seen at
+// time now. If the trace is not already being tracked and there is no
+// capacity to track more traces, getTrace returns nil.
+func (a *Aggregator) getTrace(traceID string, now time.Time) *trace {
+	if traceID == "" {
+		return nil
+	}
+	t, ok := a.traces[traceID]
+	if !ok {
+		if len(a.traces) >= a.config.MaxTraces {
+			return nil
+		}
+		t = &trace{services: make(map[string]*model.TraceSummaryService)}
+		a.traces[traceID] = t
+	}
+	t.lastSeen = now
+	return t
+}
+
+type trace struct {
+	lastSeen   time.Time
+	root       *model.Transaction
+	start, end time.Time
+	services   map[string]*model.TraceSummaryService
+}
+
+// recordEvent extends the trace's time range to include an event
+// starting at timestamp, with the given duration in milliseconds.
+func (t *trace) recordEvent(timestamp time.Time, durationMillis float64) {
+	if timestamp.IsZero() {
+		return
+	}
+	end := timestamp.Add(time.Duration(durationMillis * float64(time.Millisecond)))
+	if t.start.IsZero() || timestamp.Before(t.start) {
+		t.start = timestamp
+	}
+	if end.After(t.end) {
+		t.end = end
+	}
+}
+
+func (t *trace) service(name string) *model.TraceSummaryService {
+	s, ok := t.services[name]
+	if !ok {
+		s = &model.TraceSummaryService{Name: name}
+		t.services[name] = s
+	}
+	return s
+}
+
+func (t *trace) summary(traceID string) *model.TraceSummary {
+	services := make([]model.TraceSummaryService, 0, len(t.services))
+	for _, s := range t.services {
+		services = append(services, *s)
+	}
+	sort.Slice(services, func(i, j int) bool {
+		return services[i].Name < services[j].Name
+	})
+
+	root := t.root
+	start, end := t.start, t.end
+	if start.IsZero() {
+		start = root.Timestamp
+	}
+	if rootEnd := root.Timestamp.Add(time.Duration(root.Duration * float64(time.Millisecond))); end.Before(rootEnd) {
+		end = rootEnd
+	}
+	return &model.TraceSummary{
+		Metadata:  root.Metadata,
+		Timestamp: start,
+		TraceID:   traceID,
+		Root: model.TraceSummaryRoot{
+			ID:     root.ID,
+			Name:   root.Name,
+			Type:   root.Type,
+			Result: root.Result,
+		},
+		Duration: end.Sub(start),
+		Services: services,
+	}
+}
@@ -0,0 +1,179 @@
+// Copyright Elasticsearch B.V. and/or licensed to Elasticsearch B.V. under one
+// or more contributor license agreements. Licensed under the Elastic License;
+// you may not use this file except in compliance with the Elastic License.
+
+package tracesummary_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/elastic/apm-server/model"
+	"github.com/elastic/apm-server/publish"
+	"github.com/elastic/apm-server/transform"
+	"github.com/elastic/apm-server/x-pack/apm-server/aggregation/tracesummary"
+)
+
+func TestNewAggregatorConfigInvalid(t *testing.T) {
+	report := makeErrReporter(nil)
+
+	type test struct {
+		config tracesummary.AggregatorConfig
+		err    string
+	}
+
+	for _, test := range []test{{
+		config: tracesummary.AggregatorConfig{},
+		err:    "Report unspecified",
+	}, {
+		config: tracesummary.AggregatorConfig{
+			Report: report,
+		},
+		err: "MaxTraces unspecified or negative",
+	}, {
+		config: tracesummary.AggregatorConfig{
+			Report:    report,
+			MaxTraces: 1,
+		},
+		err: "IdleTimeout unspecified or negative",
+	}} {
+		agg, err := tracesummary.NewAggregator(test.config)
+		require.Error(t, err)
+		require.Nil(t, agg)
+		assert.EqualError(t, err, "invalid aggregator config: "+test.err)
+	}
+}
+
+func TestAggregatorRun(t *testing.T) {
+	reqs := make(chan publish.PendingReq, 1)
+	agg, err := tracesummary.NewAggregator(tracesummary.AggregatorConfig{
+		Report:      makeChanReporter(reqs),
+		MaxTraces:   10,
+		IdleTimeout: 10 * time.Millisecond,
+	})
+	require.NoError(t, err)
+
+	start := time.Date(2020, 6, 1, 12, 0, 0, 0, time.UTC)
+	frontend := model.Metadata{Service: model.Service{Name: "frontend"}}
+	backend := model.Metadata{Service: model.Service{Name: "backend"}}
+	input := []transform.Transformable{
+		&model.Span{Metadata: backend, TraceID: "trace-1", Timestamp: start.Add(20 * time.Millisecond), Duration: 100},
+		&model.Error{Metadata: backend, TraceID: "trace-1"},
+		&model.Transaction{
+			Metadata:  backend,
+			TraceID:   "trace-1",
+			ID:        "tx-2",
+			ParentID:  "span-1",
+			Timestamp: start.Add(10 * time.Millisecond),
+			Duration:  200,
+		},
+		&model.Span{Metadata: frontend, TraceID: "trace-1", Timestamp: start.Add(5 * time.Millisecond), Duration: 10},
+		&model.Transaction{
+			Metadata:  frontend,
+			TraceID:   "trace-1",
+			ID:        "tx-1",
+			Name:      "GET /",
+			Type:      "request",
+			Result:    "HTTP 2xx",
+			Timestamp: start,
+			Duration:  150,
+		},
+
+		// trace-2 has no root transaction, and will be discarded.
+		&model.Span{Metadata: backend, TraceID: "trace-2", Timestamp: start, Duration: 1},
+	}
+	output := agg.AggregateTransformables(input)
+	assert.Equal(t, input, output)
+
+	stopAggregator := runAggregator(agg)
+	defer stopAggregator()
+
+	req := expectPublish(t, reqs)
+	require.Len(t, req.Transformables, 1)
+	assert.Equal(t, &model.TraceSummary{
+		Metadata:  frontend,
+		Timestamp: start,
+		TraceID:   "trace-1",
+		Root: model.TraceSummaryRoot{
+			ID:     "tx-1",
+			Name:   "GET /",
+			Type:   "request",
+			Result: "HTTP 2xx",
+		},
+		Duration: 210 * time.Millisecond,
+		Services: []model.TraceSummaryService{
+			{Name: "backend", TransactionCount: 1, SpanCount: 1, ErrorCount: 1},
+			{Name: "frontend", TransactionCount: 1, SpanCount: 1},
+		},
+	}, req.Transformables[0])
+
+	select {
+	case <-reqs:
+		t.Fatal("unexpected publish")
+	case <-time.After(100 * time.Millisecond):
+	}
+}
+
+func TestAggregatorMaxTraces(t *testing.T) {
+	reqs := make(chan publish.PendingReq, 1)
+	agg, err := tracesummary.NewAggregator(tracesummary.AggregatorConfig{
+		Report:      makeChanReporter(reqs),
+		MaxTraces:   1,
+		IdleTimeout: 10 * time.Millisecond,
+	})
+	require.NoError(t, err)
+
+	agg.AggregateTransformables([]transform.Transformable{
+		&model.Transaction{TraceID: "trace-1", ID: "tx-1"},
+		&model.Transaction{TraceID: "trace-2", ID: "tx-2"},
+	})
+
+	stopAggregator := runAggregator(agg)
+	defer stopAggregator()
+
+	req := expectPublish(t, reqs)
+	require.Len(t, req.Transformables, 1)
+	assert.Equal(t, "trace-1", req.Transformables[0].(*model.TraceSummary).TraceID)
+}
+
+func runAggregator(agg *tracesummary.Aggregator) func() error {
+	done := make(chan error)
+	ctx, cancel := context.WithCancel(context.Background())
+	go func() {
+		defer close(done)
+		done <- agg.Run(ctx)
+	}()
+	return func() error {
+		cancel()
+		return <-done
+	}
+}
+
+func makeErrReporter(err error) publish.Reporter {
+	return func(context.Context, publish.PendingReq) error { return err }
+}
+
+func makeChanReporter(ch chan<- publish.PendingReq) publish.Reporter {
+	return func(ctx context.Context, req publish.PendingReq) error {
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case ch <- req:
+			return nil
+		}
+	}
+}
+
+func expectPublish(t *testing.T, ch <-chan publish.PendingReq) publish.PendingReq {
+	select {
+	case req := <-ch:
+		return req
+	case <-time.After(time.Second):
+		t.Fatal("expected publish")
+	}
+	panic("unreachable")
+}
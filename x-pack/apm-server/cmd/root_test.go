@@ -13,9 +13,12 @@ import (
 func TestSubCommands(t *testing.T) {
 	validCommands := map[string]struct{}{
 		"apikey":     {},
+		"bench":      {},
 		"completion": {},
 		"export":     {},
 		"keystore":   {},
+		"migrate":    {},
+		"replay":     {},
 		"run":        {},
 		"setup":      {},
 		"test":       {},
@@ -14,50 +14,118 @@ import (
 	"github.com/elastic/beats/v7/libbeat/logp"
 
 	"github.com/elastic/apm-server/beater"
+	"github.com/elastic/apm-server/beater/config"
 	"github.com/elastic/apm-server/publish"
+	"github.com/elastic/apm-server/transform"
+	"github.com/elastic/apm-server/x-pack/apm-server/aggregation/agentmetrics"
+	"github.com/elastic/apm-server/x-pack/apm-server/aggregation/spanmetrics"
+	"github.com/elastic/apm-server/x-pack/apm-server/aggregation/tracesummary"
 	"github.com/elastic/apm-server/x-pack/apm-server/aggregation/txmetrics"
 	"github.com/elastic/apm-server/x-pack/apm-server/cmd"
 )
 
+// aggregator is the interface implemented by the aggregators
+// run alongside the server.
+type aggregator interface {
+	AggregateTransformables([]transform.Transformable) []transform.Transformable
+	Run(context.Context) error
+}
+
 // runServerWithAggregator runs the APM Server. If aggregation
-// is enabled, then a txmetrics.Aggregator will also be run,
-// and the publish.Reporter will be wrapped such that all
-// transactions pass through the aggregator before being
-// published to libbeat.
+// is enabled, then a txmetrics.Aggregator will also be run;
+// if service destination aggregation is enabled, then a
+// spanmetrics.Aggregator will also be run; if trace summaries
+// are enabled, then a tracesummary.Aggregator will also be run; and if
+// metricset aggregation is enabled, an agentmetrics.Aggregator will
+// also be run.
+// The publish.Reporter
+// will be wrapped such that all events pass through the
+// aggregators before being published to libbeat.
 func runServerWithAggregator(ctx context.Context, runServer beater.RunServerFunc, args beater.ServerParams) error {
-	if !args.Config.Aggregation.Enabled {
-		return runServer(ctx, args)
+	var aggregators []aggregator
+	if args.Config.Aggregation.Enabled {
+		agg, err := txmetrics.NewAggregator(txmetrics.AggregatorConfig{
+			Report:                         args.Reporter,
+			MaxTransactionGroups:           args.Config.Aggregation.MaxTransactionGroups,
+			MetricsInterval:                args.Config.Aggregation.Interval,
+			HDRHistogramSignificantFigures: args.Config.Aggregation.HDRHistogramSignificantFigures,
+			RUMUserAgentLRUSize:            args.Config.Aggregation.RUMUserAgentLRUSize,
+		})
+		if err != nil {
+			return errors.Wrap(err, "error creating aggregator")
+		}
+		aggregators = append(aggregators, agg)
 	}
-
-	agg, err := txmetrics.NewAggregator(txmetrics.AggregatorConfig{
-		Report:                         args.Reporter,
-		MaxTransactionGroups:           args.Config.Aggregation.MaxTransactionGroups,
-		MetricsInterval:                args.Config.Aggregation.Interval,
-		HDRHistogramSignificantFigures: args.Config.Aggregation.HDRHistogramSignificantFigures,
-		RUMUserAgentLRUSize:            args.Config.Aggregation.RUMUserAgentLRUSize,
-	})
-	if err != nil {
-		return errors.Wrap(err, "error creating aggregator")
+	if args.Config.Aggregation.ServiceDestinations.Enabled {
+		agg, err := spanmetrics.NewAggregator(spanmetrics.AggregatorConfig{
+			Report:    args.Reporter,
+			MaxGroups: args.Config.Aggregation.ServiceDestinations.MaxGroups,
+			Interval:  args.Config.Aggregation.Interval,
+		})
+		if err != nil {
+			return errors.Wrap(err, "error creating service destination aggregator")
+		}
+		aggregators = append(aggregators, agg)
+	}
+	if args.Config.Aggregation.TraceSummaries.Enabled && !args.Config.FeatureFlags.TraceSummaries {
+		args.Logger.Warn("trace summaries are experimental, and require the `apm-server.features.trace_summaries` flag to be enabled")
+	} else if args.Config.Aggregation.TraceSummaries.Enabled {
+		agg, err := tracesummary.NewAggregator(tracesummary.AggregatorConfig{
+			Report:      args.Reporter,
+			MaxTraces:   args.Config.Aggregation.TraceSummaries.MaxTraces,
+			IdleTimeout: args.Config.Aggregation.TraceSummaries.IdleTimeout,
+		})
+		if err != nil {
+			return errors.Wrap(err, "error creating trace summary aggregator")
+		}
+		aggregators = append(aggregators, agg)
+	}
+	if cfg := args.Config.Aggregation.Metricsets; cfg.Enabled {
+		rules := make([]agentmetrics.Rule, len(cfg.Rules))
+		for i, rule := range cfg.Rules {
+			rules[i] = agentmetrics.Rule{Pattern: rule.Name, Type: agentmetrics.Counter}
+			if rule.Type == config.MetricTypeGauge {
+				rules[i].Type = agentmetrics.Gauge
+			}
+		}
+		agg, err := agentmetrics.NewAggregator(agentmetrics.AggregatorConfig{
+			Report:    args.Reporter,
+			MaxGroups: cfg.MaxGroups,
+			Interval:  cfg.Interval,
+			Rules:     rules,
+		})
+		if err != nil {
+			return errors.Wrap(err, "error creating metricset aggregator")
+		}
+		aggregators = append(aggregators, agg)
+	}
+	if len(aggregators) == 0 {
+		return runServer(ctx, args)
 	}
 
 	origReport := args.Reporter
 	args.Reporter = func(ctx context.Context, req publish.PendingReq) error {
-		req.Transformables = agg.AggregateTransformables(req.Transformables)
+		for _, agg := range aggregators {
+			req.Transformables = agg.AggregateTransformables(req.Transformables)
+		}
 		return origReport(ctx, req)
 	}
 
 	g, ctx := errgroup.WithContext(ctx)
-	g.Go(func() error {
-		args.Logger.Infof("aggregator started with config: %+v", args.Config.Aggregation)
-		switch err := agg.Run(ctx); err {
-		case nil, context.Canceled:
-			args.Logger.Infof("aggregator stopped")
-			return nil
-		default:
-			args.Logger.Errorf("aggregator aborted", logp.Error(err))
-			return err
-		}
-	})
+	for _, agg := range aggregators {
+		agg := agg
+		g.Go(func() error {
+			args.Logger.Infof("aggregator started with config: %+v", args.Config.Aggregation)
+			switch err := agg.Run(ctx); err {
+			case nil, context.Canceled:
+				args.Logger.Infof("aggregator stopped")
+				return nil
+			default:
+				args.Logger.Errorf("aggregator aborted", logp.Error(err))
+				return err
+			}
+		})
+	}
 	g.Go(func() error {
 		return runServer(ctx, args)
 	})